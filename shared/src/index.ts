@@ -62,6 +62,9 @@ export type {
     QuizAdBreakEndEvent,
 } from './ws-events';
 
+// Контракт протокола, сгенерированный из catalog.json сервера (cmd/ws-contract)
+export * as WSContract from './ws-contract.gen';
+
 // Runtime type guards
 export {
     isQuestionOption,
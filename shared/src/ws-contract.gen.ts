@@ -0,0 +1,630 @@
+// Code generated by cmd/ws-contract from internal/websocket/contract/catalog.json. DO NOT EDIT.
+// Контракт протокола WebSocket: события сервера, сообщения клиента и их данные.
+
+/** Текущая версия протокола сервера */
+export const WS_PROTOCOL_VERSION = 2;
+
+/** Причина выбывания игрока */
+export type EliminationReason = 'incorrect_answer' | 'time_exceeded' | 'no_answer_timeout' | 'already_eliminated' | 'sudden_death';
+
+/** Состояние таймера текущего вопроса после команды администратора */
+export interface LiveQuestionTimer {
+    question_id: number;
+    remaining_ms: number;
+    paused: boolean;
+    /** Unix ms */
+    server_timestamp: number;
+    /** Unix ms окончания вопроса; на паузе не задан */
+    deadline?: number;
+}
+
+/** Подписанные ссылки на медиа вопроса */
+export interface QuestionMedia {
+    image_url?: string;
+    audio_url?: string;
+    /** Unix ms, до которого действуют ссылки */
+    expires_at: number;
+}
+
+/** Вариант ответа; id — индекс варианта, совпадает с correct_option */
+export interface QuestionOption {
+    id: number;
+    text: string;
+}
+
+/** Перевод вопроса на дополнительный язык */
+export interface QuestionTranslation {
+    text: string;
+    options: QuestionOption[];
+}
+
+/** Объявление администратора во время викторины */
+export interface QuizAnnouncementAdmin {
+    quiz_id: number;
+    message: string;
+    /** Unix ms */
+    server_timestamp: number;
+}
+
+/** Анонс запланированной викторины */
+export interface QuizAnnouncementScheduled {
+    quiz_id: number;
+    title: string;
+    description: string;
+    scheduled_time: string;
+    question_count: number;
+    minutes_to_start: number;
+}
+
+/** Текущий вопрос в ответе на user:resync */
+export interface QuizStateQuestion {
+    question_id: number;
+    number: number;
+    total_questions: number;
+    text: string;
+    options: QuestionOption[];
+    time_limit: number;
+    /** Unix ms окончания вопроса с учётом продлений */
+    deadline: number;
+}
+
+/** Способ вернуться в игру: просмотр рекламы или списание очков */
+export type SecondChanceMethod = 'ad' | 'points';
+
+/** TOKEN_EXPIRED — Access-токен истёк или токены пользователя отозваны (reason: invalidated), нужен повторный вход; соединение закрывается */
+export interface TokenExpiredData {
+    message: string;
+    reason?: 'invalidated';
+}
+
+/** TOKEN_EXPIRE_SOON — Access-токен скоро истечёт */
+export interface TokenExpireSoonData {
+    expires_in: number;
+    unit: 'seconds';
+}
+
+/** adaptive:question_stats — Статистика адаптивной сложности по итогам вопроса (для мониторинга) */
+export interface AdaptiveQuestionStatsData {
+    quiz_id: number;
+    question_number: number;
+    difficulty_used: number;
+    target_pass_rate: number;
+    actual_pass_rate: number;
+    total_answers: number;
+    passed_count: number;
+    remaining_players: number;
+    timestamp: string;
+}
+
+/** auth:refreshed — Новый тикет из auth:refresh принят, соединение продолжает работу */
+export interface AuthRefreshedData {
+    user_id: number;
+    /** Unix ms */
+    server_timestamp: number;
+}
+
+/** friend:joined_waiting_room — Игрок, на которого подписан пользователь, вошёл в зал ожидания */
+export interface FriendJoinedWaitingRoomData {
+    quiz_id: number;
+    user_id: number;
+    username: string;
+    profile_picture: string;
+}
+
+/** notification:new — Новое уведомление в центре уведомлений */
+export interface NotificationNewData {
+    id: number;
+    user_id: number;
+    type: string;
+    title: string;
+    body: string;
+    data: Record<string, unknown>;
+    read_at?: string;
+    created_at: string;
+}
+
+/** quiz:ad_break — Рекламная пауза между вопросами */
+export interface QuizAdBreakData {
+    quiz_id: number;
+    media_type: 'image' | 'video';
+    media_url: string;
+    duration_sec: number;
+    duration_ms: number;
+    /** Только для видео */
+    mime_type?: string;
+    /** Только для видео */
+    thumbnail_url?: string;
+}
+
+/** quiz:ad_break_end — Рекламная пауза закончилась */
+export interface QuizAdBreakEndData {
+    quiz_id: number;
+}
+
+/** quiz:admission_queue — Позиция игрока в очереди, если викторина заполнена */
+export interface QuizAdmissionQueueData {
+    quiz_id: number;
+    position: number;
+    queue_length: number;
+    max_players: number;
+}
+
+/** quiz:admitted — Игрок из очереди допущен к викторине */
+export interface QuizAdmittedData {
+    quiz_id: number;
+    status: 'scheduled' | 'in_progress';
+}
+
+/** quiz:announcement — Анонс викторины планировщиком или объявление администратора */
+export type QuizAnnouncementData = QuizAnnouncementScheduled | QuizAnnouncementAdmin;
+
+/** quiz:answer_result — Результат ответа игрока (только ему) */
+export interface QuizAnswerResultData {
+    question_id: number;
+    correct_option: number;
+    your_answer: number;
+    is_correct: boolean;
+    points_earned: number;
+    time_taken_ms: number;
+    is_eliminated: boolean;
+    /** Пустая строка, если игрок остаётся в игре */
+    elimination_reason: '' | 'incorrect_answer' | 'time_exceeded' | 'sudden_death';
+    time_limit_exceeded: boolean;
+    /** Только в режиме lives */
+    lives_left?: number;
+    /** Ответ в финале на выбывание: итог раунда придёт в quiz:sudden_death_result */
+    sudden_death?: true;
+}
+
+/** quiz:answer_reveal — Правильный ответ после окончания времени */
+export interface QuizAnswerRevealData {
+    question_id: number;
+    correct_option: number;
+}
+
+/** quiz:cancelled — Викторина отменена администратором или не стартовала из-за другой активной викторины */
+export interface QuizCancelledData {
+    quiz_id: number;
+    reason: 'cancelled_by_admin' | 'another_quiz_active';
+    message?: string;
+    details?: string;
+}
+
+/** quiz:countdown — Обратный отсчёт до старта, раз в секунду */
+export interface QuizCountdownData {
+    quiz_id: number;
+    seconds_left: number;
+}
+
+/** quiz:elimination — Игрок выбыл и дальше только наблюдает (только ему) */
+export interface QuizEliminationData {
+    quiz_id: number;
+    user_id: number;
+    reason: EliminationReason;
+    message: string;
+}
+
+/** quiz:error — Зарезервировано для ошибок викторины; сейчас ошибки приходят в server:error */
+export interface QuizErrorData {
+    code?: string;
+    message: string;
+}
+
+/** quiz:finish — Викторина завершена, идёт подсчёт результатов */
+export interface QuizFinishData {
+    quiz_id: number;
+    title: string;
+    message: string;
+    status: 'completed';
+    ended_at: string;
+}
+
+/** quiz:life_lost — Пропуск вопроса стоил игроку жизни (режим lives) */
+export interface QuizLifeLostData {
+    quiz_id: number;
+    question_id: number;
+    lives_left: number;
+}
+
+/** quiz:media_preload — Ссылки на медиа вопросов для загрузки во время обратного отсчёта */
+export interface QuizMediaPreloadData {
+    quiz_id: number;
+    items: {
+        question_id: number;
+        image_url: string;
+        audio_url: string;
+    }[];
+    /** Unix ms, до которого действуют ссылки */
+    expires_at: number;
+}
+
+/** quiz:player_count — Изменилось число подключённых игроков */
+export interface QuizPlayerCountData {
+    quiz_id: number;
+    player_count: number;
+}
+
+/** quiz:question — Новый вопрос; ответы принимаются до deadline */
+export interface QuizQuestionData {
+    question_id: number;
+    quiz_id: number;
+    number: number;
+    text: string;
+    /** Текст на казахском, может быть пустым */
+    text_kk: string;
+    options: QuestionOption[];
+    options_kk: QuestionOption[];
+    /** Секунды на ответ */
+    time_limit: number;
+    total_questions: number;
+    /** Unix ms отправки вопроса */
+    start_time: number;
+    /** Unix ms окончания приёма ответов */
+    deadline: number;
+    /** Unix ms */
+    server_timestamp: number;
+    /** Переводы по коду языка */
+    translations?: Record<string, QuestionTranslation>;
+    media?: QuestionMedia;
+    /** Номер раунда финала на выбывание */
+    sudden_death_round?: number;
+}
+
+/** quiz:question_deadline — Персональный дедлайн вопроса в ответ на user:question_received: общий deadline плюс отсрочка на задержку доставки */
+export interface QuizQuestionDeadlineData {
+    question_id: number;
+    /** Unix ms окончания приёма ответов этого игрока */
+    deadline: number;
+    /** Отсрочка относительно общего deadline */
+    allowance_ms: number;
+}
+
+/** quiz:question_voided — Вопрос снят с эфира; ответы на него не засчитываются */
+export interface QuizQuestionVoidedData {
+    quiz_id: number;
+    question_id: number;
+    number: number;
+    message: string;
+}
+
+/** quiz:results_available — Результаты викторины подсчитаны */
+export interface QuizResultsAvailableData {
+    quiz_id: number;
+}
+
+/** quiz:revived — Игрок вернулся в игру по второму шансу */
+export interface QuizRevivedData {
+    quiz_id: number;
+    question_number: number;
+    method: SecondChanceMethod;
+}
+
+/** quiz:second_chance_offer — Выбывшему игроку предложен второй шанс до начала следующего вопроса */
+export interface QuizSecondChanceOfferData {
+    quiz_id: number;
+    question_number: number;
+    method: SecondChanceMethod;
+    /** Стоимость в очках (способ points) */
+    cost?: number;
+}
+
+/** quiz:start — Викторина началась */
+export interface QuizStartData {
+    quiz_id: number;
+    title: string;
+    question_count: number;
+}
+
+/** quiz:state — Состояние викторины для игрока в ответ на user:resync */
+export interface QuizStateData {
+    quiz_id: number;
+    status: string;
+    current_question?: QuizStateQuestion;
+    time_remaining: number;
+    is_eliminated: boolean;
+    elimination_reason?: string;
+    score: number;
+    correct_count: number;
+    player_count: number;
+}
+
+/** quiz:sudden_death — Начался финал на выбывание */
+export interface QuizSuddenDeathData {
+    quiz_id: number;
+    players: number;
+    max_rounds: number;
+}
+
+/** quiz:sudden_death_result — Итог раунда финала; voided — никто не ответил верно и раунд не засчитан */
+export interface QuizSuddenDeathResultData {
+    quiz_id: number;
+    number: number;
+    eliminated: number;
+    remaining: number;
+    voided: boolean;
+}
+
+/** quiz:time_extended — Администратор продлил текущий вопрос */
+export interface QuizTimeExtendedData {
+    question_id: number;
+    remaining_ms: number;
+    paused: boolean;
+    /** Unix ms */
+    server_timestamp: number;
+    /** Unix ms окончания вопроса; на паузе не задан */
+    deadline?: number;
+    added_seconds: number;
+}
+
+/** quiz:timer — Остаток времени на вопрос, раз в секунду */
+export interface QuizTimerData {
+    question_id: number;
+    remaining_seconds: number;
+    paused: boolean;
+    /** Unix ms */
+    server_timestamp: number;
+}
+
+/** quiz:timer_paused — Администратор поставил таймер вопроса на паузу */
+export type QuizTimerPausedData = LiveQuestionTimer;
+
+/** quiz:timer_resumed — Таймер вопроса снят с паузы */
+export type QuizTimerResumedData = LiveQuestionTimer;
+
+/** quiz:user_ready — Игрок вошёл в викторину; player_count — подключённые игроки */
+export interface QuizUserReadyData {
+    user_id: number;
+    quiz_id: number;
+    status: 'ready';
+    player_count: number;
+}
+
+/** quiz:waiting_room — Открыт зал ожидания викторины */
+export interface QuizWaitingRoomData {
+    quiz_id: number;
+    title: string;
+    description: string;
+    scheduled_time: string;
+    question_count: number;
+    starts_in_seconds: number;
+}
+
+/** server:buffer_warning — Часть сообщений пропущена из-за переполнения очереди отправки */
+export interface ServerBufferWarningData {
+    dropped_messages: number;
+    message: string;
+}
+
+/** server:error — Ошибка обработки сообщения клиента; соединение не закрывается */
+export interface ServerErrorData {
+    code: string;
+    message: string;
+}
+
+/** server:heartbeat — Ответ на user:heartbeat */
+export interface ServerHeartbeatData {
+    /** Unix ms */
+    timestamp: number;
+}
+
+/** server:hello — Ответ на client:hello: согласованная версия, события и возможности */
+export interface ServerHelloData {
+    protocol_version: number;
+    min_version: number;
+    max_version: number;
+    events: string[];
+    messages: string[];
+    capabilities: string[];
+}
+
+/** server:time_sync — Ответ на user:time_sync: моменты приёма и отправки в unix ms с дробной частью */
+export interface ServerTimeSyncData {
+    id?: string;
+    client_time: number;
+    server_receive_time: number;
+    server_send_time: number;
+}
+
+/** system:maintenance — Включён или выключен режим обслуживания */
+export interface SystemMaintenanceData {
+    enabled: boolean;
+    message?: string;
+    /** Секунды до повторной попытки */
+    retry_after: number;
+    since?: string;
+    /** Режим включён в конфигурации и через API не выключается */
+    forced?: boolean;
+}
+
+/** token_expiring — Авторизация соединения отозвана (отзыв токенов или ротация ключей); до refresh_by нужно прислать новый тикет в auth:refresh, иначе придёт TOKEN_EXPIRED и соединение закроется */
+export interface TokenExpiringData {
+    reason: 'invalidated' | 'key_rotated';
+    /** Unix ms */
+    refresh_by: number;
+    grace_sec: number;
+}
+
+/** auth:refresh — Обновление авторизации соединения без переподключения: тикет из POST /api/auth/ws-ticket */
+export interface AuthRefreshData {
+    ticket: string;
+}
+
+/** client:hello — Согласование версии протокола и возможностей */
+export interface ClientHelloData {
+    version: number;
+    capabilities?: string[];
+}
+
+/** user:answer — Ответ на вопрос */
+export interface UserAnswerData {
+    question_id: number;
+    selected_option: number;
+    /** Unix ms на клиенте */
+    timestamp: number;
+}
+
+/** user:heartbeat — Проверка соединения */
+export type UserHeartbeatData = Record<string, unknown>;
+
+/** user:question_received — Подтверждение получения quiz:question; время на ответ отсчитывается от него (отсрочка ограничена) */
+export interface UserQuestionReceivedData {
+    question_id: number;
+}
+
+/** user:ready — Вход в викторину; last_seq — номер последнего полученного события при переподключении */
+export interface UserReadyData {
+    quiz_id: number;
+    last_seq?: number;
+}
+
+/** user:resync — Запрос текущего состояния викторины после переподключения */
+export interface UserResyncData {
+    quiz_id: number;
+}
+
+/** user:time_sync — Синхронизация часов: client_time — unix ms на клиенте */
+export interface UserTimeSyncData {
+    id?: string;
+    client_time: number;
+}
+
+/** Пользователь вышел на всех устройствах (сообщение без конверта {type, data}) */
+export interface LogoutAllDevicesNotice {
+    event: 'logout_all_devices';
+    user_id: number;
+    timestamp: string;
+    reason: 'user_logout_all';
+}
+
+/** Сессия пользователя отозвана (сообщение без конверта {type, data}) */
+export interface SessionRevokedNotice {
+    event: 'session_revoked';
+    session_id: number;
+    timestamp: string;
+    reason: string;
+    user_id: number;
+}
+
+/** Данные событий сервера по типу */
+export interface WSServerEventDataMap {
+    'TOKEN_EXPIRED': TokenExpiredData;
+    'TOKEN_EXPIRE_SOON': TokenExpireSoonData;
+    'adaptive:question_stats': AdaptiveQuestionStatsData;
+    'auth:refreshed': AuthRefreshedData;
+    'friend:joined_waiting_room': FriendJoinedWaitingRoomData;
+    'notification:new': NotificationNewData;
+    'quiz:ad_break': QuizAdBreakData;
+    'quiz:ad_break_end': QuizAdBreakEndData;
+    'quiz:admission_queue': QuizAdmissionQueueData;
+    'quiz:admitted': QuizAdmittedData;
+    'quiz:announcement': QuizAnnouncementData;
+    'quiz:answer_result': QuizAnswerResultData;
+    'quiz:answer_reveal': QuizAnswerRevealData;
+    'quiz:cancelled': QuizCancelledData;
+    'quiz:countdown': QuizCountdownData;
+    'quiz:elimination': QuizEliminationData;
+    'quiz:error': QuizErrorData;
+    'quiz:finish': QuizFinishData;
+    'quiz:life_lost': QuizLifeLostData;
+    'quiz:media_preload': QuizMediaPreloadData;
+    'quiz:player_count': QuizPlayerCountData;
+    'quiz:question': QuizQuestionData;
+    'quiz:question_deadline': QuizQuestionDeadlineData;
+    'quiz:question_voided': QuizQuestionVoidedData;
+    'quiz:results_available': QuizResultsAvailableData;
+    'quiz:revived': QuizRevivedData;
+    'quiz:second_chance_offer': QuizSecondChanceOfferData;
+    'quiz:start': QuizStartData;
+    'quiz:state': QuizStateData;
+    'quiz:sudden_death': QuizSuddenDeathData;
+    'quiz:sudden_death_result': QuizSuddenDeathResultData;
+    'quiz:time_extended': QuizTimeExtendedData;
+    'quiz:timer': QuizTimerData;
+    'quiz:timer_paused': QuizTimerPausedData;
+    'quiz:timer_resumed': QuizTimerResumedData;
+    'quiz:user_ready': QuizUserReadyData;
+    'quiz:waiting_room': QuizWaitingRoomData;
+    'server:buffer_warning': ServerBufferWarningData;
+    'server:error': ServerErrorData;
+    'server:heartbeat': ServerHeartbeatData;
+    'server:hello': ServerHelloData;
+    'server:time_sync': ServerTimeSyncData;
+    'system:maintenance': SystemMaintenanceData;
+    'token_expiring': TokenExpiringData;
+}
+
+export type WSServerEventName = keyof WSServerEventDataMap;
+
+/** Сообщение сервера; seq — номер события в журнале викторины для досылки после переподключения */
+export type WSServerEvent = {
+    [K in WSServerEventName]: { type: K; data: WSServerEventDataMap[K]; seq?: number };
+}[WSServerEventName];
+
+/** Версия протокола, с которой клиент получает событие */
+export const WS_SERVER_EVENT_SINCE: Record<WSServerEventName, number> = {
+    'TOKEN_EXPIRED': 1,
+    'TOKEN_EXPIRE_SOON': 1,
+    'adaptive:question_stats': 1,
+    'auth:refreshed': 2,
+    'friend:joined_waiting_room': 1,
+    'notification:new': 1,
+    'quiz:ad_break': 1,
+    'quiz:ad_break_end': 1,
+    'quiz:admission_queue': 1,
+    'quiz:admitted': 1,
+    'quiz:announcement': 1,
+    'quiz:answer_result': 1,
+    'quiz:answer_reveal': 1,
+    'quiz:cancelled': 1,
+    'quiz:countdown': 1,
+    'quiz:elimination': 1,
+    'quiz:error': 1,
+    'quiz:finish': 1,
+    'quiz:life_lost': 1,
+    'quiz:media_preload': 1,
+    'quiz:player_count': 1,
+    'quiz:question': 1,
+    'quiz:question_deadline': 2,
+    'quiz:question_voided': 1,
+    'quiz:results_available': 1,
+    'quiz:revived': 1,
+    'quiz:second_chance_offer': 1,
+    'quiz:start': 1,
+    'quiz:state': 1,
+    'quiz:sudden_death': 1,
+    'quiz:sudden_death_result': 1,
+    'quiz:time_extended': 1,
+    'quiz:timer': 1,
+    'quiz:timer_paused': 1,
+    'quiz:timer_resumed': 1,
+    'quiz:user_ready': 1,
+    'quiz:waiting_room': 1,
+    'server:buffer_warning': 1,
+    'server:error': 1,
+    'server:heartbeat': 1,
+    'server:hello': 2,
+    'server:time_sync': 1,
+    'system:maintenance': 1,
+    'token_expiring': 2,
+};
+
+/** Данные сообщений клиента по типу */
+export interface WSClientMessageDataMap {
+    'auth:refresh': AuthRefreshData;
+    'client:hello': ClientHelloData;
+    'user:answer': UserAnswerData;
+    'user:heartbeat': UserHeartbeatData;
+    'user:question_received': UserQuestionReceivedData;
+    'user:ready': UserReadyData;
+    'user:resync': UserResyncData;
+    'user:time_sync': UserTimeSyncData;
+}
+
+export type WSClientMessageName = keyof WSClientMessageDataMap;
+
+export type WSClientMessage = {
+    [K in WSClientMessageName]: { type: K; data: WSClientMessageDataMap[K] };
+}[WSClientMessageName];
+
+/** Служебные сообщения сервера без конверта {type, data} */
+export type WSNotice = LogoutAllDevicesNotice | SessionRevokedNotice;
@@ -11,10 +11,14 @@ export const WS_CLIENT_EVENTS = {
     READY: 'user:ready',
     /** Ответ на вопрос */
     ANSWER: 'user:answer',
+    /** Вопрос получен: от этого момента отсчитывается время на ответ */
+    QUESTION_RECEIVED: 'user:question_received',
     /** Heartbeat для поддержания соединения */
     HEARTBEAT: 'user:heartbeat',
     /** Запрос ресинхронизации состояния */
     RESYNC: 'user:resync',
+    /** Новый ws-тикет после token_expiring (обновление авторизации без переподключения) */
+    AUTH_REFRESH: 'auth:refresh',
 } as const;
 
 /** Тип клиентского WS-события */
@@ -32,6 +36,8 @@ export const WS_QUIZ_EVENTS = {
     QUESTION: 'quiz:question',
     /** Таймер обратного отсчета ответа */
     TIMER: 'quiz:timer',
+    /** Персональный дедлайн вопроса (ответ на user:question_received, протокол v2) */
+    QUESTION_DEADLINE: 'quiz:question_deadline',
     /** Результат ответа */
     ANSWER_RESULT: 'quiz:answer_result',
     /** Раскрытие правильного ответа */
@@ -71,6 +77,10 @@ export const WS_SYSTEM_EVENTS = {
     TOKEN_EXPIRE_SOON: 'TOKEN_EXPIRE_SOON',
     /** Токен истек */
     TOKEN_EXPIRED: 'TOKEN_EXPIRED',
+    /** Авторизация соединения отозвана, нужно прислать новый тикет в auth:refresh */
+    TOKEN_EXPIRING: 'token_expiring',
+    /** Новый тикет принят, соединение продолжает работу */
+    AUTH_REFRESHED: 'auth:refreshed',
     /** Сессия отозвана */
     SESSION_REVOKED: 'session_revoked',
     /** Выход со всех устройств */
@@ -7,17 +7,34 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
-	"strings"
+	"strconv"
 	"syscall"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/yourusername/trivia-api/internal/admingraph"
 	"github.com/yourusername/trivia-api/internal/config"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	"github.com/yourusername/trivia-api/internal/grpcapi"
 	"github.com/yourusername/trivia-api/internal/handler"
+	"github.com/yourusername/trivia-api/internal/handler/response"
 	"github.com/yourusername/trivia-api/internal/middleware"
+	"github.com/yourusername/trivia-api/internal/pkg/envelope"
+	"github.com/yourusername/trivia-api/internal/pkg/geo"
+	"github.com/yourusername/trivia-api/internal/pkg/httpcache"
+	"github.com/yourusername/trivia-api/internal/pkg/i18n"
+	"github.com/yourusername/trivia-api/internal/pkg/iprisk"
+	"github.com/yourusername/trivia-api/internal/pkg/moderation"
+	"github.com/yourusername/trivia-api/internal/pkg/rating"
+	"github.com/yourusername/trivia-api/internal/pkg/storage"
+	"github.com/yourusername/trivia-api/internal/pkg/tracing"
+	"github.com/yourusername/trivia-api/internal/pkg/warehouse"
+	clickhouseRepo "github.com/yourusername/trivia-api/internal/repository/clickhouse"
 	pgRepo "github.com/yourusername/trivia-api/internal/repository/postgres"
 	redisRepo "github.com/yourusername/trivia-api/internal/repository/redis"
+	"github.com/yourusername/trivia-api/internal/seed"
 	"github.com/yourusername/trivia-api/internal/service"
 	"github.com/yourusername/trivia-api/internal/service/quizmanager"
 	ws "github.com/yourusername/trivia-api/internal/websocket"
@@ -41,7 +58,7 @@ func main() {
 	}
 
 	// РРЅРёС†РёР°Р»РёР·РёСЂСѓРµРј РїРѕРґРєР»СЋС‡РµРЅРёРµ Рє PostgreSQL
-	db, err := database.NewPostgresDB(cfg.Database.PostgresConnectionString())
+	db, err := database.NewPostgresDB(cfg.Database)
 	if err != nil {
 		log.Printf("Failed to connect to database: %v", err)
 		os.Exit(1)
@@ -53,6 +70,14 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Demo data for local development: config validation allows DEV_SEED only with GIN_MODE=debug
+	if cfg.DevSeed {
+		if err := seed.Run(db, seed.DefaultOptions()); err != nil {
+			log.Printf("Failed to seed demo data: %v", err)
+			os.Exit(1)
+		}
+	}
+
 	// РРЅРёС†РёР°Р»РёР·РёСЂСѓРµРј РїРѕРґРєР»СЋС‡РµРЅРёРµ Рє Redis СЃ РёСЃРїРѕР»СЊР·РѕРІР°РЅРёРµРј СѓРЅРёС„РёС†РёСЂРѕРІР°РЅРЅРѕР№ РєРѕРЅС„РёРіСѓСЂР°С†РёРё
 	redisClient, err := database.NewUniversalRedisClient(cfg.Redis)
 	if err != nil {
@@ -61,6 +86,28 @@ func main() {
 	}
 	log.Println("Successfully connected to Redis")
 
+	// Initialize OpenTelemetry: trace context propagation is always on, spans are exported only when enabled
+	shutdownTracing, err := tracing.Init(context.Background(), tracing.Config{
+		Enabled:     cfg.Tracing.Enabled,
+		Endpoint:    cfg.Tracing.Endpoint,
+		Insecure:    cfg.Tracing.Insecure,
+		ServiceName: cfg.Tracing.ServiceName,
+		Environment: gin.Mode(),
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		log.Printf("Failed to initialize tracing: %v", err)
+		os.Exit(1)
+	}
+	if cfg.Tracing.Enabled {
+		if err := db.Use(tracing.GormPlugin{}); err != nil {
+			log.Printf("Failed to register GORM tracing plugin: %v", err)
+			os.Exit(1)
+		}
+		redisClient.AddHook(tracing.RedisHook{})
+		log.Printf("Tracing enabled, exporting spans to %s", cfg.Tracing.Endpoint)
+	}
+
 	// РРЅРёС†РёР°Р»РёР·РёСЂСѓРµРј СЂРµРїРѕР·РёС‚РѕСЂРёРё
 	userRepo := pgRepo.NewUserRepo(db)
 	quizRepo := pgRepo.NewQuizRepo(db)
@@ -88,11 +135,18 @@ func main() {
 	}
 
 	// РРЅРёС†РёР°Р»РёР·РёСЂСѓРµРј СЂРµРїРѕР·РёС‚РѕСЂРёР№ РґР»СЏ JWT РєР»СЋС‡РµР№
-	jwtKeyRepo, err := pgRepo.NewPostgresJWTKeyRepository(db, cfg.JWT.DBJWTKeyEncryptionKey)
+	jwtKeyring, err := envelope.NewKeyring(cfg.JWT.DBJWTKeyEncryptionKeyVersion, cfg.JWT.DBKeyEncryptionKeys())
+	if err != nil {
+		log.Printf("Failed to initialize JWT key encryption keyring: %v", err)
+		os.Exit(1)
+	}
+	jwtKeyRepo, err := pgRepo.NewPostgresJWTKeyRepository(db, jwtKeyring)
 	if err != nil {
 		log.Printf("Failed to initialize JWTKeyRepository: %v", err)
 		os.Exit(1)
 	}
+	// Rotated keys keep validating tokens they signed for the retention period
+	jwtKeyRepo.SetValidationWindow(cfg.JWT.Rotation.Retention)
 
 	// --- РРЅРёС†РёР°Р»РёР·Р°С†РёСЏ РєРѕРЅС„РёРіСѓСЂР°С†РёРё РґР»СЏ QuizManager ---
 	quizConfig := quizmanager.DefaultConfig()
@@ -120,6 +174,30 @@ func main() {
 	tokenManager.SetRefreshTokenExpiry(time.Duration(cfg.Auth.RefreshTokenLifetime) * time.Hour)
 	tokenManager.SetMaxRefreshTokensPerUser(cfg.Auth.SessionLimit)
 
+	// Session idle/absolute lifetime limits per client type, with per-role overrides
+	sessionPolicy := func(p config.SessionPolicyConfig) manager.SessionPolicy {
+		return manager.SessionPolicy{IdleTimeout: p.IdleTimeout, AbsoluteLifetime: p.AbsoluteLifetime}
+	}
+	sessionPolicies := manager.SessionPolicies{
+		ClientSessionPolicies: manager.ClientSessionPolicies{
+			Web:    sessionPolicy(cfg.Auth.Sessions.Web),
+			Mobile: sessionPolicy(cfg.Auth.Sessions.Mobile),
+		},
+		Roles: make(map[string]manager.ClientSessionPolicies, len(cfg.Auth.Sessions.Roles)),
+	}
+	for role, override := range cfg.Auth.Sessions.Roles {
+		sessionPolicies.Roles[role] = manager.ClientSessionPolicies{
+			Web:    sessionPolicy(override.Web),
+			Mobile: sessionPolicy(override.Mobile),
+		}
+	}
+	tokenManager.SetSessionPolicies(sessionPolicies)
+	keyRotationPolicy := manager.KeyRotationPolicy{Retention: cfg.JWT.Rotation.Retention}
+	if cfg.JWT.Rotation.Enabled {
+		keyRotationPolicy.MaxAge = cfg.JWT.Rotation.MaxKeyAge
+	}
+	tokenManager.SetKeyRotationPolicy(keyRotationPolicy)
+
 	isProduction := gin.Mode() == gin.ReleaseMode
 	tokenManager.SetProductionMode(isProduction) // РЈСЃС‚Р°РЅР°РІР»РёРІР°РµРј СЂРµР¶РёРј РґР»СЏ Secure РєСѓРє
 
@@ -148,7 +226,21 @@ func main() {
 	var pubSubProvider ws.PubSubProvider = &ws.NoOpPubSub{} // РџСЂРѕРІР°Р№РґРµСЂ РїРѕ СѓРјРѕР»С‡Р°РЅРёСЋ
 
 	// РЎРѕР·РґР°РµРј PubSubProvider С‚РѕР»СЊРєРѕ РµСЃР»Рё РєР»Р°СЃС‚РµСЂРёР·Р°С†РёСЏ РІРєР»СЋС‡РµРЅР°
-	if cfg.WebSocket.Cluster.Enabled {
+	if cfg.WebSocket.Cluster.Enabled && cfg.WebSocket.Cluster.Provider == "nats" {
+		log.Println("Initializing NATS PubSub for WebSocket clustering...")
+		natsCfg := cfg.WebSocket.Cluster.NATS
+		if natsCfg.Name == "" && cfg.WebSocket.Cluster.InstanceID != "" {
+			natsCfg.Name = "trivia-api-" + cfg.WebSocket.Cluster.InstanceID
+		}
+		natsProvider, errProv := ws.NewNATSPubSub(natsCfg)
+		if errProv != nil {
+			log.Printf("Failed to create NATS PubSub provider: %v. WebSocket clustering will be inactive.", errProv)
+			pubSubProvider = &ws.NoOpPubSub{}
+		} else {
+			log.Println("NATS PubSub provider initialized")
+			pubSubProvider = natsProvider
+		}
+	} else if cfg.WebSocket.Cluster.Enabled {
 		log.Println("РРЅРёС†РёР°Р»РёР·Р°С†РёСЏ Redis PubSub РґР»СЏ РєР»Р°СЃС‚РµСЂРёР·Р°С†РёРё WebSocket...")
 		redisPubSubClient, errPubSub := database.NewUniversalRedisClient(cfg.Redis)
 		if errPubSub != nil {
@@ -202,24 +294,104 @@ func main() {
 	}
 	authService.SetFeatureFlags(cfg.Features.EmailVerificationEnabled, cfg.Features.GoogleOAuthEnabled)
 	authService.SetLegalVersions(cfg.Legal.TOSVersion, cfg.Legal.PrivacyVersion)
+	// Re-acceptance of the terms and privacy policy after legal.tosVersion/privacyVersion bumps
+	legalService := service.NewLegalService(legalRepo, service.LegalVersions{
+		TOSVersion:     cfg.Legal.TOSVersion,
+		PrivacyVersion: cfg.Legal.PrivacyVersion,
+	})
 	authService.SetEmailVerificationRepository(emailVerificationRepo)
 	authService.SetIdentityRepository(userIdentityRepo)
+	// Content locales (localization section) also bound the values accepted for users.language
+	locales := i18n.NewLocales(cfg.Locale.DefaultLocale, cfg.Locale.SupportedLocales)
+	authService.SetLocales(locales)
 
-	if cfg.Features.EmailVerificationEnabled {
-		var emailSvc service.EmailService
-		switch strings.ToLower(strings.TrimSpace(cfg.Email.Provider)) {
-		case "resend":
-			resendSvc, emailErr := service.NewResendEmailService(cfg.Email.ResendAPIKey, cfg.Email.From)
-			if emailErr != nil {
-				log.Printf("Failed to initialize ResendEmailService: %v", emailErr)
+	// Runtime feature flags: rows in feature_flags override the static cfg.Features defaults
+	featureFlagService := service.NewFeatureFlagService(pgRepo.NewFeatureFlagRepo(db), cacheRepo, map[string]bool{
+		entity.FeatureEmailVerification: cfg.Features.EmailVerificationEnabled,
+	})
+	authService.SetFeatureEvaluator(featureFlagService)
+
+	// Domain events are written to the outbox in the same transaction as the change
+	// and dispatched to subscribers after commit (at-least-once)
+	eventBus := service.NewEventBus(pgRepo.NewOutboxRepo(db), service.EventBusConfig{
+		BatchSize:   cfg.EventBus.BatchSize,
+		MaxAttempts: cfg.EventBus.MaxAttempts,
+		Retention:   time.Duration(cfg.EventBus.RetentionDays) * 24 * time.Hour,
+	})
+	authService.SetEventBus(eventBus)
+
+	// Moderation of usernames and profile fields: blocked words are rejected,
+	// borderline ones are accepted and queued for admin review
+	var moderationService *service.ModerationService
+	if cfg.Moderation.Enabled {
+		wordlists, err := moderation.LoadWordlists(cfg.Moderation.WordlistsDir, cfg.Moderation.Locales)
+		if err != nil {
+			log.Printf("Failed to load moderation wordlists: %v", err)
+			os.Exit(1)
+		}
+		moderationService = service.NewModerationService(moderation.NewFilter(wordlists), pgRepo.NewModerationRepo(db), userRepo)
+		authService.SetModerationService(moderationService)
+	}
+
+	// IP geolocation: the client country is recorded at login and quiz join; prizes of quizzes
+	// with allowed_countries go only to players who joined from those countries
+	var geoService *service.GeoService
+	if cfg.Geo.Enabled {
+		var resolver geo.Resolver
+		switch cfg.Geo.Provider {
+		case "maxmind_web":
+			webService, err := geo.NewMaxMindWebService(cfg.Geo.MaxMind.BaseURL, cfg.Geo.MaxMind.AccountID, cfg.Geo.MaxMind.LicenseKey,
+				time.Duration(cfg.Geo.TimeoutMs)*time.Millisecond)
+			if err != nil {
+				log.Printf("Failed to initialize MaxMind web service: %v", err)
 				os.Exit(1)
 			}
-			emailSvc = resendSvc
+			resolver = webService
+			if cfg.Geo.CacheTTLMinutes > 0 {
+				resolver = geo.NewCachedResolver(webService, time.Duration(cfg.Geo.CacheTTLMinutes)*time.Minute)
+			}
 		default:
-			log.Printf("Unsupported email provider for verification: %s", cfg.Email.Provider)
-			os.Exit(1)
+			mmdb, err := geo.OpenMaxMindDB(cfg.Geo.DatabasePath)
+			if err != nil {
+				log.Printf("Failed to open MaxMind database: %v", err)
+				os.Exit(1)
+			}
+			resolver = mmdb
 		}
+		geoService = service.NewGeoService(resolver, pgRepo.NewGeoRepo(db), quizRepo, cfg.Geo.AllowUnknown,
+			time.Duration(cfg.Geo.TimeoutMs)*time.Millisecond)
+		tokenManager.SetLoginHook(func(userID uint, ipAddress string) {
+			go geoService.RecordLogin(userID, ipAddress)
+		})
+		log.Printf("IP geolocation enabled (%s)", resolver.Name())
+	}
 
+	// Email templates: files shipped with the server (or email.templatesDir) overridden by
+	// versions saved by admins, resolved along the user's locale chain
+	emailTemplateService, err := service.NewEmailTemplateService(pgRepo.NewEmailTemplateRepo(db), locales, cfg.Email.TemplatesDir)
+	if err != nil {
+		log.Printf("Failed to load email templates: %v", err)
+		os.Exit(1)
+	}
+
+	// Transactional email goes through the configured provider chain (primary, then fallbacks)
+	// with per-provider retries; failed sends land in email_dead_letters
+	var emailSvc service.EmailService
+	var emailDispatcher *service.EmailDispatcher
+	if cfg.Features.EmailVerificationEnabled || cfg.Features.MagicLinkEnabled {
+		emailRegistry, emailChain, emailErr := service.NewEmailProviderRegistryFromConfig(cfg.Email)
+		if emailErr != nil {
+			log.Printf("Failed to initialize email providers: %v", emailErr)
+			os.Exit(1)
+		}
+		emailDispatcher, emailErr = service.NewEmailDispatcher(emailRegistry, emailTemplateService, pgRepo.NewEmailRepo(db), emailChain)
+		if emailErr != nil {
+			log.Printf("Failed to initialize EmailDispatcher: %v", emailErr)
+			os.Exit(1)
+		}
+		emailSvc = emailDispatcher
+	}
+	if cfg.Features.EmailVerificationEnabled {
 		emailVerificationService, emailErr := service.NewEmailVerificationService(
 			userRepo,
 			emailVerificationRepo,
@@ -236,15 +408,97 @@ func main() {
 		authService.SetEmailVerificationService(emailVerificationService)
 	}
 
+	// Passwordless login: single-use links emailed to the user and bound to the requesting device
+	if cfg.Features.MagicLinkEnabled {
+		magicLinkService, magicErr := service.NewMagicLinkService(userRepo, pgRepo.NewMagicLinkRepo(db), emailSvc, cfg.MagicLink)
+		if magicErr != nil {
+			log.Printf("Failed to initialize MagicLinkService: %v", magicErr)
+			os.Exit(1)
+		}
+		authService.SetMagicLinkService(magicLinkService)
+	}
+
+	// Passkeys (WebAuthn): ceremony state lives in Redis, credentials in webauthn_credentials
+	if cfg.Features.WebAuthnEnabled {
+		webAuthnService, webAuthnErr := service.NewWebAuthnService(userRepo, pgRepo.NewWebAuthnCredentialRepo(db), cacheRepo, cfg.WebAuthn)
+		if webAuthnErr != nil {
+			log.Printf("Failed to initialize WebAuthnService: %v", webAuthnErr)
+			os.Exit(1)
+		}
+		authService.SetWebAuthnService(webAuthnService)
+	}
+
 	if cfg.Features.GoogleOAuthEnabled {
 		googleOAuthService, googleErr := service.NewGoogleOAuthService(userRepo, userIdentityRepo, tokenManager, cfg.Google)
 		if googleErr != nil {
 			log.Printf("Failed to initialize GoogleOAuthService: %v", googleErr)
 			os.Exit(1)
 		}
+		googleOAuthService.SetEventBus(eventBus)
+		googleOAuthService.SetLoginPolicy(authService.CheckLoginMethod)
 		authService.SetGoogleOAuthService(googleOAuthService)
 	}
 
+	// Referral program (optional, behind feature flag)
+	var referralService *service.ReferralService
+	if cfg.Features.ReferralsEnabled {
+		referralRepo := pgRepo.NewReferralRepo(db)
+		referralService, err = service.NewReferralService(referralRepo, userRepo, refreshTokenRepo, cfg.Referral.BonusPoints, cfg.Referral.MaxPerIP)
+		if err != nil {
+			log.Printf("Failed to initialize ReferralService: %v", err)
+			os.Exit(1)
+		}
+		authService.SetReferralService(referralService)
+	}
+
+	// Per-device account caps and shadow bans
+	abuseService, err := service.NewAbuseService(pgRepo.NewAbuseRepo(db), cfg.AntiAbuse.MaxAccountsPerDevice, cfg.AntiAbuse.OverLimitAction)
+	if err != nil {
+		log.Printf("Failed to initialize AbuseService: %v", err)
+		os.Exit(1)
+	}
+	authService.SetAbuseService(abuseService)
+
+	notificationPrefRepo := pgRepo.NewNotificationPreferenceRepo(db)
+
+	// Push notifications (optional, behind feature flag).
+	// Providers without credentials fall back to a noop sender so device registration still works.
+	var pushService *service.PushNotificationService
+	if cfg.Features.PushNotificationsEnabled {
+		var fcmSender service.PushSender = service.NewNoopPushSender(entity.PushProviderFCM)
+		if cfg.Push.FCMServiceAccountFile != "" {
+			sender, fcmErr := service.NewFCMPushSender(cfg.Push.FCMServiceAccountFile)
+			if fcmErr != nil {
+				log.Printf("Failed to initialize FCM sender: %v", fcmErr)
+				os.Exit(1)
+			}
+			fcmSender = sender
+		}
+		var apnsSender service.PushSender = service.NewNoopPushSender(entity.PushProviderAPNs)
+		if cfg.Push.APNsKeyFile != "" {
+			sender, apnsErr := service.NewAPNsPushSender(cfg.Push.APNsKeyFile, cfg.Push.APNsKeyID, cfg.Push.APNsTeamID, cfg.Push.APNsBundleID, cfg.Push.APNsProduction)
+			if apnsErr != nil {
+				log.Printf("Failed to initialize APNs sender: %v", apnsErr)
+				os.Exit(1)
+			}
+			apnsSender = sender
+		}
+
+		pushService, err = service.NewPushNotificationService(
+			pgRepo.NewPushDeviceRepo(db),
+			notificationPrefRepo,
+			[]service.PushSender{fcmSender, apnsSender},
+			cfg.Push.Workers,
+			cfg.Push.QueueSize,
+		)
+		if err != nil {
+			log.Printf("Failed to initialize PushNotificationService: %v", err)
+			os.Exit(1)
+		}
+		pushService.Start(ctx)
+		authService.SetPushNotificationService(pushService)
+	}
+
 	// Р—Р°РїСѓСЃРєР°РµРј С„РѕРЅРѕРІСѓСЋ Р·Р°РґР°С‡Сѓ РґР»СЏ РѕС‡РёСЃС‚РєРё РёСЃС‚РµРєС€РёС… CSRF С‚РѕРєРµРЅРѕРІ Рё РґСЂСѓРіРёС… СЂРµСЃСѓСЂСЃРѕРІ
 	go func() {
 		ticker := time.NewTicker(1 * time.Hour)
@@ -274,6 +528,8 @@ func main() {
 	// Sharding.Enabled РєРѕРЅС‚СЂРѕР»РёСЂСѓРµС‚ С‚РѕР»СЊРєРѕ Р·Р°РїСѓСЃРє ClusterHub РґР»СЏ РјРµР¶СЃРµСЂРІРµСЂРЅРѕРіРѕ
 	// РІР·Р°РёРјРѕРґРµР№СЃС‚РІРёСЏ, РЅРѕ Р»РѕРєР°Р»СЊРЅС‹Р№ Hub РЅСѓР¶РµРЅ РІСЃРµРіРґР°.
 	log.Println("WebSocket: РёРЅРёС†РёР°Р»РёР·Р°С†РёСЏ ShardedHub")
+	// Per-client fan-out logs are for debugging only: at tens of thousands of subscribers they cost more than the broadcast itself
+	ws.SetDebugLogging(cfg.WebSocket.Fanout.DebugLogging)
 	shardedHub := ws.NewShardedHub(cfg.WebSocket, pubSubProvider, cacheRepo)
 	go shardedHub.Run() // Р—Р°РїСѓСЃРєР°РµРј РѕР±СЂР°Р±РѕС‚С‡РёРє С€Р°СЂРґРѕРІ
 	wsHub = shardedHub
@@ -286,31 +542,697 @@ func main() {
 
 	wsManager := ws.NewManager(wsHub)
 
+	// Revoking a user's tokens on any node (auth:invalidate) closes their sockets on every node
+	jwtService.SetInvalidationHandler(func(userID uint, invalidatedAt time.Time) {
+		wsManager.DisconnectUser(userID, invalidatedAt)
+	})
+
+	// Sockets whose ticket key was rotated out, or whose revocation event was missed, get
+	// token_expiring and may send a fresh ticket via auth:refresh instead of being disconnected
+	if authCfg := cfg.WebSocket.AuthRefresh; authCfg.Enabled && authCfg.CheckIntervalSec > 0 {
+		authRefresher := ws.NewAuthRefresher(wsManager, jwtService, time.Duration(authCfg.GraceSec)*time.Second)
+		authRefresher.Start(ctx, time.Duration(authCfg.CheckIntervalSec)*time.Second)
+	}
+
+	// In-app notification center; email copies are sent only when an email provider is configured
+	notificationService, err := service.NewNotificationService(pgRepo.NewNotificationRepo(db), notificationPrefRepo, wsManager)
+	if err != nil {
+		log.Printf("Failed to initialize NotificationService: %v", err)
+		os.Exit(1)
+	}
+	if emailSvc != nil {
+		notificationService.SetEmailService(emailSvc, userRepo)
+	}
+	authService.SetNotificationService(notificationService)
+
 	// РРЅРёС†РёР°Р»РёР·РёСЂСѓРµРј СЃРµСЂРІРёСЃС‹
 	quizService := service.NewQuizService(quizRepo, questionRepo, cacheRepo, quizConfig, db)
-	resultService := service.NewResultService(resultRepo, userRepo, quizRepo, questionRepo, cacheRepo, db, wsManager, quizConfig)
+
+	// Quiz statistics and personal analytics aggregate answers either in PostgreSQL or,
+	// to keep them off the OLTP database, in ClickHouse fed by the warehouse event stream
+	analyticsRepo := pgRepo.NewAnalyticsRepo(db)
+	var statsBackend repository.StatisticsBackend = analyticsRepo
+	if cfg.Statistics.Backend == "clickhouse" {
+		chStats, err := clickhouseRepo.NewStatisticsRepo(clickhouseRepo.Config{
+			URL:      cfg.Statistics.ClickHouse.URL,
+			Database: cfg.Statistics.ClickHouse.Database,
+			User:     cfg.Statistics.ClickHouse.User,
+			Password: cfg.Statistics.ClickHouse.Password,
+			Timeout:  time.Duration(cfg.Statistics.ClickHouse.TimeoutSec) * time.Second,
+		})
+		if err != nil {
+			log.Printf("Failed to create ClickHouse statistics backend: %v", err)
+			os.Exit(1)
+		}
+		if err := chStats.Ping(ctx); err != nil {
+			log.Printf("WARNING: ClickHouse statistics backend is not reachable yet: %v", err)
+		}
+		statsBackend = chStats
+		log.Println("Statistics backend: ClickHouse")
+	}
+	resultService := service.NewResultService(resultRepo, userRepo, quizRepo, questionRepo, statsBackend, cacheRepo, db, wsManager, quizConfig)
 	resultService.SetEmailVerificationGate(cfg.Features.EmailVerificationSoftGateEnabled)
-	userService := service.NewUserService(userRepo)
+	if geoService != nil {
+		resultService.SetGeoRestrictions(cfg.Geo.AllowUnknown)
+	}
+	if referralService != nil {
+		resultService.SetReferralService(referralService)
+	}
+	if pushService != nil {
+		resultService.SetPushNotificationService(pushService)
+	}
+	resultService.SetNotificationService(notificationService)
+
+	// Wallet and payouts (optional, behind feature flag)
+	var walletService *service.WalletService
+	if cfg.Features.WalletEnabled {
+		walletService, err = service.NewWalletService(pgRepo.NewWalletRepo(db), userRepo, cfg.Wallet.MinPayout)
+		if err != nil {
+			log.Printf("Failed to initialize WalletService: %v", err)
+			os.Exit(1)
+		}
+		resultService.SetWalletService(walletService)
+	}
+
+	// Prize claims: winners submit payout details with a claim token, admins verify identity;
+	// unclaimed prizes are redistributed or forfeited after the claim window
+	var prizeClaimService *service.PrizeClaimService
+	if cfg.PrizeClaims.Enabled {
+		prizeClaimService, err = service.NewPrizeClaimService(pgRepo.NewPrizeClaimRepo(db), userRepo,
+			time.Duration(cfg.PrizeClaims.ClaimWindowHours)*time.Hour, cfg.PrizeClaims.UnclaimedPolicy)
+		if err != nil {
+			log.Printf("Failed to initialize PrizeClaimService: %v", err)
+			os.Exit(1)
+		}
+		prizeClaimService.SetNotifier(notificationService)
+		if walletService != nil {
+			prizeClaimService.SetWalletService(walletService)
+		}
+		resultService.SetPrizeClaimService(prizeClaimService)
+		prizeClaimService.Start(ctx, time.Duration(cfg.PrizeClaims.CheckIntervalMin)*time.Minute)
+	}
+
+	// VPN/proxy risk of quiz join addresses: winners whose sessions score at or above the threshold
+	// are queued for admin review, with the payout held until approval when ipRisk.action is exclude
+	var ipRiskService *service.IPRiskService
+	if cfg.IPRisk.Enabled {
+		lists, err := iprisk.LoadRangeLists(cfg.IPRisk.ListsDir)
+		if err != nil {
+			log.Printf("Failed to load IP risk lists: %v", err)
+			os.Exit(1)
+		}
+		timeout := time.Duration(cfg.IPRisk.TimeoutMs) * time.Millisecond
+		var provider iprisk.Provider
+		if cfg.IPRisk.Provider == "proxycheck" {
+			provider = iprisk.NewProxyCheck(cfg.IPRisk.ProxyCheck.BaseURL, cfg.IPRisk.ProxyCheck.APIKey, timeout)
+		}
+		scorer := iprisk.NewScorer(lists, provider, time.Duration(cfg.IPRisk.CacheTTLMinutes)*time.Minute)
+		ipRiskService = service.NewIPRiskService(scorer, pgRepo.NewIPRiskRepo(db), quizRepo, cfg.IPRisk.Threshold, timeout)
+		ipRiskService.SetWalletService(walletService)
+		ipRiskService.SetPrizeClaimService(prizeClaimService)
+		ipRiskService.StartListReload(ctx, cfg.IPRisk.ListsDir, time.Duration(cfg.IPRisk.ReloadIntervalMinutes)*time.Minute)
+		resultService.SetIPRiskPolicy(cfg.IPRisk.Threshold, cfg.IPRisk.Action)
+		log.Printf("IP risk scoring enabled (%d networks in lists, provider %q, action %s)", lists.Len(), cfg.IPRisk.Provider, cfg.IPRisk.Action)
+	}
+	userService := service.NewUserService(userRepo, resultRepo)
+	userService.SetStreakRepository(pgRepo.NewUserStreakRepo(db))
+	// Skill rating, recalculated from quiz.completed events; shown in profiles and the rating leaderboard
+	var ratingService *service.RatingService
+	if cfg.Rating.Enabled {
+		ratingService, err = service.NewRatingService(pgRepo.NewRatingRepo(db), resultRepo, service.RatingConfig{
+			Algorithm: cfg.Rating.Algorithm,
+			Params: rating.Config{
+				Initial:          cfg.Rating.Initial,
+				KFactor:          cfg.Rating.KFactor,
+				ProvisionalK:     cfg.Rating.ProvisionalKFactor,
+				ProvisionalGames: cfg.Rating.ProvisionalGames,
+			},
+			MinPlayers: cfg.Rating.MinPlayers,
+		})
+		if err != nil {
+			log.Printf("Failed to initialize RatingService: %v", err)
+			os.Exit(1)
+		}
+		ratingService.SubscribeTo(eventBus)
+		userService.SetRatingService(ratingService)
+	}
+	// Daily challenge: one pool question per UTC day, answered over REST
+	var dailyChallengeService *service.DailyChallengeService
+	if cfg.DailyChallenge.Enabled {
+		dailyChallengeService = service.NewDailyChallengeService(pgRepo.NewDailyChallengeRepo(db), questionRepo, cacheRepo, service.DailyChallengeConfig{
+			Grace: time.Duration(cfg.DailyChallenge.GraceMs) * time.Millisecond,
+		})
+	}
 	quizManagerService := service.NewQuizManager(quizRepo, questionRepo, resultRepo, resultService, cacheRepo, wsManager, db, quizAdSlotRepo)
+	// RSVP: in-app reminders for users who pre-registered, plus the expected audience
+	// used to pre-scale WebSocket shards when the waiting room opens
+	rsvpService := service.NewRSVPService(pgRepo.NewQuizRSVPRepo(db), quizRepo)
+	rsvpService.SetNotifier(notificationService)
+	quizNotifiers := quizmanager.QuizNotifiers{rsvpService}
+	if pushService != nil {
+		quizNotifiers = append(quizNotifiers, pushService)
+	}
+	quizManagerService.SetNotifier(quizNotifiers)
+	quizManagerService.SetParticipantForecast(rsvpService)
+	// Host announcements: scheduled by admins (or in config for every quiz) relative to quiz
+	// milestones and sent once as quiz:announcement, with participant and prize placeholders
+	var announcementService *service.AnnouncementService
+	if cfg.Announcer.Enabled {
+		automatic := make([]service.AnnouncementInput, 0, len(cfg.Announcer.Automatic))
+		for _, a := range cfg.Announcer.Automatic {
+			automatic = append(automatic, service.AnnouncementInput{
+				Trigger:        a.Trigger,
+				OffsetSec:      a.OffsetSec,
+				QuestionNumber: a.QuestionNumber,
+				Message:        a.Message,
+			})
+		}
+		announcementService, err = service.NewAnnouncementService(pgRepo.NewQuizAnnouncementRepo(db), quizRepo, cacheRepo, service.AnnouncementConfig{
+			Automatic: automatic,
+		})
+		if err != nil {
+			log.Printf("Failed to initialize AnnouncementService: %v", err)
+			os.Exit(1)
+		}
+		quizManagerService.SetAnnouncer(announcementService)
+	}
+	// Social graph: followers are notified over WebSocket when a player joins a waiting room
+	followService := service.NewFollowService(pgRepo.NewUserFollowRepo(db), userRepo)
+	followService.SetEvents(wsManager)
+	quizManagerService.SetFriendNotifier(followService)
+	adImpressionRepo := pgRepo.NewAdImpressionRepo(db)
+	quizManagerService.SetAdImpressionRepo(adImpressionRepo)
+	// Sponsor branding: shown in quiz detail and in quiz:start / quiz:results_available,
+	// with impressions recorded next to ad breaks
+	sponsorService := service.NewSponsorService(quizRepo, adAssetRepo, adImpressionRepo)
+	sponsorService.SetQuizCache(quizService)
+	quizManagerService.SetSponsorship(sponsorService)
+	resultService.SetSponsorService(sponsorService)
+	questionTranslationRepo := pgRepo.NewQuestionTranslationRepo(db)
+	translationService := service.NewTranslationService(questionTranslationRepo, questionRepo, userRepo, locales)
+	quizManagerService.SetLocalizer(translationService)
+	translationService.SetQuizCache(quizService)
+	// Quiz timings come from config.yaml (quiz section) and can be changed by a config reload
+	applyQuizTiming := func(q config.QuizConfig) {
+		quizManagerService.SetTiming(quizmanager.Timing{
+			AnnouncementMinutes:  q.AnnouncementMinutes,
+			ReminderMinutes:      q.ReminderMinutes,
+			WaitingRoomMinutes:   q.WaitingRoomMinutes,
+			CountdownSeconds:     q.CountdownSeconds,
+			QuestionDelayMs:      q.QuestionDelayMs,
+			AnswerRevealDelayMs:  q.AnswerRevealDelayMs,
+			InterQuestionDelayMs: q.InterQuestionDelayMs,
+			AdVideoBufferMs:      q.AdVideoBufferMs,
+		})
+	}
+	applyQuizTiming(cfg.Quiz)
+
+	// Answers are written to Postgres in batches; batches that fail to land are parked
+	// in Redis and replayed, and the buffer is flushed before results are calculated
+	var answerBuffer *service.AnswerBuffer
+	if cfg.AnswerBuffer.Enabled {
+		answerBuffer = service.NewAnswerBuffer(resultRepo, cacheRepo, service.AnswerBufferConfig{
+			BatchSize:     cfg.AnswerBuffer.BatchSize,
+			FlushInterval: time.Duration(cfg.AnswerBuffer.FlushIntervalMs) * time.Millisecond,
+		})
+		resultService.SetAnswerBuffer(answerBuffer)
+		quizManagerService.SetAnswerWriter(answerBuffer)
+	}
+
+	// Game events (answers, eliminations, completed quizzes, connections) are streamed
+	// to the analytics warehouse; gaps are filled by cmd/warehouse-backfill
+	var warehouseExporter *service.WarehouseExporter
+	if cfg.Warehouse.Enabled {
+		warehouseSink, err := warehouse.NewSink(warehouse.SinkConfig{
+			Type:      cfg.Warehouse.Sink,
+			URL:       cfg.Warehouse.URL,
+			Topic:     cfg.Warehouse.Topic,
+			AuthToken: cfg.Warehouse.AuthToken,
+			Dir:       cfg.Warehouse.Dir,
+			Timeout:   time.Duration(cfg.Warehouse.TimeoutSec) * time.Second,
+		})
+		if err != nil {
+			log.Printf("Failed to create warehouse sink: %v", err)
+			os.Exit(1)
+		}
+		warehouseExporter = service.NewWarehouseExporter(warehouseSink, service.WarehouseConfig{
+			BatchSize:     cfg.Warehouse.BatchSize,
+			FlushInterval: time.Duration(cfg.Warehouse.FlushIntervalMs) * time.Millisecond,
+			BufferSize:    cfg.Warehouse.BufferSize,
+			InstanceID:    shardedHub.GetInstanceID(),
+		})
+		warehouseExporter.SubscribeTo(eventBus)
+		quizManagerService.SetAnswerObserver(warehouseExporter)
+		wsManager.SetConnectionHandler(warehouseExporter.HandleConnection)
+	}
+
+	// Public listing/leaderboard responses are cached in Redis and revalidated via ETag;
+	// services bump the cache version whenever the underlying data changes
+	var httpCache *httpcache.Store
+	if cfg.HTTPCache.Enabled {
+		httpCache = httpcache.New(cacheRepo, time.Duration(cfg.HTTPCache.TTLSeconds)*time.Second, response.EnvelopeHeader)
+		quizService.SetHTTPCache(httpCache)
+		resultService.SetHTTPCache(httpCache)
+		quizManagerService.SetHTTPCache(httpCache)
+		userService.SetHTTPCache(httpCache)
+		if ratingService != nil {
+			ratingService.SetHTTPCache(httpCache)
+		}
+	}
+	cachedResponse := func(namespace string) gin.HandlerFunc {
+		if httpCache == nil {
+			return func(c *gin.Context) { c.Next() }
+		}
+		return httpCache.Middleware(namespace)
+	}
+
+	// Admin analytics: aggregate rollups plus per-instance WS connection sampling
+	analyticsService, err := service.NewAnalyticsService(analyticsRepo, cacheRepo)
+	if err != nil {
+		log.Printf("Failed to initialize AnalyticsService: %v", err)
+		os.Exit(1)
+	}
+	analyticsService.StartConnectionSampler(ctx, shardedHub, shardedHub.GetInstanceID(), time.Minute)
+
+	// Landing page counters: online count comes from the connection samples of all instances
+	publicStatsService := service.NewPublicStatsService(pgRepo.NewPublicStatsRepo(db), cacheRepo, shardedHub)
+
+	// Personal analytics: per-user rollups over answers from the statistics backend, cached in Redis
+	userAnalyticsService, err := service.NewUserAnalyticsService(statsBackend, cacheRepo)
+	if err != nil {
+		log.Printf("Failed to initialize UserAnalyticsService: %v", err)
+		os.Exit(1)
+	}
+
+	// Read-only maintenance mode: shared through Redis, rejects writes and holds quiz starts
+	maintenanceService := service.NewMaintenanceService(cacheRepo, wsManager, service.MaintenanceState{
+		Enabled:       cfg.Maintenance.Enabled,
+		Message:       cfg.Maintenance.Message,
+		RetryAfterSec: cfg.Maintenance.RetryAfterSec,
+	})
+	maintenanceService.Start(ctx, 5*time.Second)
+	quizManagerService.SetSchedulingPause(maintenanceService)
+
+	// Partner webhooks for quiz lifecycle events, delivered by a background worker with retries
+	var webhookService *service.WebhookService
+	if cfg.Webhooks.Enabled {
+		webhookService = service.NewWebhookService(pgRepo.NewWebhookRepo(db), service.WebhookConfig{
+			MaxAttempts: cfg.Webhooks.MaxAttempts,
+			Timeout:     time.Duration(cfg.Webhooks.TimeoutSec) * time.Second,
+			BatchSize:   cfg.Webhooks.BatchSize,
+			AllowHTTP:   cfg.Webhooks.AllowHTTP,
+			Retention:   time.Duration(cfg.Webhooks.RetentionDays) * 24 * time.Hour,
+		})
+		webhookService.Start(ctx, time.Duration(cfg.Webhooks.PollIntervalSec)*time.Second)
+		quizManagerService.SetWebhookService(webhookService)
+		webhookService.SubscribeTo(eventBus)
+	}
+
+	// API keys for partner server-to-server access; usage counters are flushed to the DB periodically
+	var apiKeyService *service.APIKeyService
+	if cfg.APIKeys.Enabled {
+		apiKeyService = service.NewAPIKeyService(pgRepo.NewAPIKeyRepo(db))
+		apiKeyService.Start(ctx, time.Duration(cfg.APIKeys.FlushIntervalSec)*time.Second)
+	}
+	// In-app purchases (App Store, Google Play) and the premium entitlements they grant
+	var purchaseService *service.PurchaseService
+	if cfg.Purchases.Enabled {
+		purchaseService, err = service.NewPurchaseServiceFromConfig(pgRepo.NewPurchaseRepo(db), cfg.Purchases)
+		if err != nil {
+			log.Printf("Failed to initialize PurchaseService: %v", err)
+			os.Exit(1)
+		}
+	}
+	// Mobile API request counters by API version and app version (User-Agent)
+	mobileClientService := service.NewMobileClientService(pgRepo.NewMobileClientUsageRepo(db))
+	mobileClientService.Start(ctx, time.Duration(cfg.MobileAPI.StatsFlushIntervalSec)*time.Second)
+	resultService.SetEventBus(eventBus)
+	eventBus.Start(ctx, time.Duration(cfg.EventBus.PollIntervalMs)*time.Millisecond)
+
+	// Old user_answers/results partitions are detached into the archive schema
+	if cfg.Partitioning.RetentionDays > 0 {
+		partitionRetention := service.NewPartitionRetentionService(pgRepo.NewPartitionRepo(db), time.Duration(cfg.Partitioning.RetentionDays)*24*time.Hour)
+		partitionRetention.Start(ctx, time.Duration(cfg.Partitioning.CheckIntervalHours)*time.Hour)
+	}
 
 	// РРЅРёС†РёР°Р»РёР·РёСЂСѓРµРј СЃРµСЂРІРёСЃС‹ СЂРµРєР»Р°РјС‹
-	adService := service.NewAdService(adAssetRepo, "./uploads/ads")
+	// Upload storage: local disk for a single VM, S3-compatible bucket when scaled out
+	var uploadStorage storage.Storage
+	switch cfg.Storage.Backend {
+	case "s3":
+		uploadStorage, err = storage.NewS3Storage(storage.S3Config{
+			Endpoint:  cfg.Storage.S3.Endpoint,
+			Region:    cfg.Storage.S3.Region,
+			Bucket:    cfg.Storage.S3.Bucket,
+			AccessKey: cfg.Storage.S3.AccessKey,
+			SecretKey: cfg.Storage.S3.SecretKey,
+			PathStyle: cfg.Storage.S3.PathStyle,
+			PublicURL: cfg.Storage.S3.PublicURL,
+		})
+	case "", "local":
+		if cfg.Storage.LocalDir == "" {
+			cfg.Storage.LocalDir = "./uploads"
+		}
+		if cfg.Storage.LocalURL == "" {
+			cfg.Storage.LocalURL = "/uploads"
+		}
+		uploadStorage, err = storage.NewLocalStorage(cfg.Storage.LocalDir, cfg.Storage.LocalURL)
+	default:
+		log.Printf("Unknown storage backend %q", cfg.Storage.Backend)
+		os.Exit(1)
+	}
+	if err != nil {
+		log.Printf("Failed to initialize upload storage: %v", err)
+		os.Exit(1)
+	}
+
+	adService := service.NewAdService(adAssetRepo, uploadStorage)
+	shareCardService, err := service.NewShareCardService(resultRepo, quizRepo, uploadStorage, cfg.ShareCards)
+	if err != nil {
+		log.Printf("Failed to initialize ShareCardService: %v", err)
+		os.Exit(1)
+	}
+	avatarService, err := service.NewAvatarService(userRepo, uploadStorage)
+	if err != nil {
+		log.Printf("Failed to initialize AvatarService: %v", err)
+		os.Exit(1)
+	}
+	adService.SetUploadLimits(service.AdUploadLimits{
+		MaxImageSizeBytes:   cfg.Ads.MaxImageSizeMB * 1024 * 1024,
+		MaxVideoSizeBytes:   cfg.Ads.MaxVideoSizeMB * 1024 * 1024,
+		MaxVideoDurationSec: cfg.Ads.MaxVideoDurationSec,
+	})
+	if mediaProcessor, err := service.NewFFmpegMediaProcessor(cfg.Ads.FFmpegPath, cfg.Ads.FFprobePath); err != nil {
+		log.Printf("Video ad processing disabled: %v", err)
+	} else {
+		adService.SetMediaProcessor(mediaProcessor)
+	}
 	quizAdSlotService := service.NewQuizAdSlotService(quizAdSlotRepo, adAssetRepo, quizRepo)
 
+	// Background exports of quiz results/answers into upload storage with expiring download links
+	exportService := service.NewExportService(pgRepo.NewExportJobRepo(db), quizRepo, uploadStorage, service.ExportConfig{
+		BatchSize:      cfg.Exports.BatchSize,
+		DownloadURLTTL: time.Duration(cfg.Exports.DownloadURLTTLMinutes) * time.Minute,
+		Retention:      time.Duration(cfg.Exports.RetentionHours) * time.Hour,
+	})
+	exportService.Start(ctx, time.Duration(cfg.Exports.PollIntervalSec)*time.Second)
+
+	// Question images/audio live in the same storage; quiz events carry signed URLs
+	questionMediaService := service.NewQuestionMediaService(questionRepo, uploadStorage)
+	questionMediaService.SetUploadLimits(service.QuestionMediaLimits{
+		MaxImageSizeBytes: cfg.QuestionMedia.MaxImageSizeMB * 1024 * 1024,
+		MaxAudioSizeBytes: cfg.QuestionMedia.MaxAudioSizeMB * 1024 * 1024,
+	})
+	questionMediaService.SetURLTTL(time.Duration(cfg.QuestionMedia.URLTTLMinutes) * time.Minute)
+	quizManagerService.SetQuestionMedia(questionMediaService)
+
+	// Question review: only approved questions reach the adaptive selector
+	questionReviewService := service.NewQuestionReviewService(questionRepo, pgRepo.NewQuestionReviewRepo(db), userRepo)
+	questionReviewService.SetQuizCache(quizService)
+
+	// Categories and tags: themed quiz discovery, category-first pool selection, per-category leaderboards
+	taxonomyService := service.NewTaxonomyService(pgRepo.NewTaxonomyRepo(db))
+	taxonomyService.SetQuizCache(quizService)
+	if httpCache != nil {
+		taxonomyService.SetHTTPCache(httpCache)
+	}
+
+	// Question bank import from external trivia sources; imported questions wait in the review queue
+	questionImportService := service.NewQuestionImportService(questionRepo, pgRepo.NewQuestionImportRepo(db), taxonomyService)
+	openTDBCategories := make(map[string]string, len(cfg.QuestionImport.OpenTDB.Categories))
+	for _, mapping := range cfg.QuestionImport.OpenTDB.Categories {
+		openTDBCategories[mapping.External] = mapping.Category
+	}
+	questionImportService.RegisterSource(
+		service.NewOpenTDBSource(cfg.QuestionImport.OpenTDB.BaseURL, time.Duration(cfg.QuestionImport.TimeoutSec)*time.Second),
+		service.QuestionSourceMapping{Categories: openTDBCategories, Difficulties: cfg.QuestionImport.OpenTDB.Difficulties},
+	)
+	if cfg.QuestionImport.Enabled {
+		scheduledImports := make([]service.ScheduledQuestionImport, 0, len(cfg.QuestionImport.Scheduled))
+		for _, entry := range cfg.QuestionImport.Scheduled {
+			scheduledImports = append(scheduledImports, service.ScheduledQuestionImport{
+				Source: entry.Source,
+				Query: service.QuestionImportQuery{
+					Amount:           entry.Amount,
+					ExternalCategory: entry.ExternalCategory,
+					Difficulty:       entry.Difficulty,
+				},
+			})
+		}
+		questionImportService.Start(ctx, time.Duration(cfg.QuestionImport.IntervalHours)*time.Hour, scheduledImports)
+	}
+
+	// AI-assisted question generation; generated questions never skip the review queue
+	var questionGenerationService *service.QuestionGenerationService
+	if cfg.AIQuestions.Enabled {
+		llmProvider, err := service.NewLLMProviderFromConfig(cfg.AIQuestions)
+		if err != nil {
+			log.Printf("Failed to initialize LLM provider: %v", err)
+			os.Exit(1)
+		}
+		questionGenerationService, err = service.NewQuestionGenerationService(questionRepo, pgRepo.NewQuestionGenerationRepo(db), taxonomyService, llmProvider, cfg.AIQuestions)
+		if err != nil {
+			log.Printf("Failed to initialize QuestionGenerationService: %v", err)
+			os.Exit(1)
+		}
+	}
+
+	// Machine translation assistance: missing kk translations are pre-filled and wait for admin review
+	var translationAssistService *service.TranslationAssistService
+	if cfg.TranslationAssist.Enabled {
+		translator, err := service.NewMachineTranslatorFromConfig(cfg.TranslationAssist, cfg.AIQuestions)
+		if err != nil {
+			log.Printf("Failed to initialize machine translator: %v", err)
+			os.Exit(1)
+		}
+		translationAssistService = service.NewTranslationAssistService(questionTranslationRepo, translator, locales.Default(),
+			cfg.TranslationAssist.BatchSize, time.Duration(cfg.TranslationAssist.TimeoutSec)*time.Second)
+		if cfg.TranslationAssist.IntervalMinutes > 0 {
+			translationAssistService.Start(ctx, time.Duration(cfg.TranslationAssist.IntervalMinutes)*time.Minute)
+		}
+	}
+
+	// Second chance: eliminated players can come back once per quiz (ad or wallet points)
+	secondChanceService := service.NewSecondChanceService(quizRepo, adAssetRepo, cacheRepo, quizManagerService, resultService, wsManager)
+	secondChanceService.SetQuizCache(quizService)
+	if walletService != nil {
+		secondChanceService.SetWalletService(walletService)
+	}
+
+	// Append-only audit log of admin and security-sensitive actions
+	auditService, err := service.NewAuditService(pgRepo.NewAuditLogRepo(db))
+	if err != nil {
+		log.Printf("Failed to initialize AuditService: %v", err)
+		os.Exit(1)
+	}
+
+	// Questions voided because the quiz:question broadcast reached too few players
+	quizManagerService.SetDeliveryIncidentHook(func(incident quizmanager.DeliveryIncident) {
+		auditService.Record(ctx, service.AuditEntry{
+			Action:     entity.AuditActionQuizDeliveryFailure,
+			TargetType: entity.AuditTargetQuiz,
+			TargetID:   strconv.FormatUint(uint64(incident.QuizID), 10),
+			Metadata: map[string]interface{}{
+				"question_id":     incident.QuestionID,
+				"question_number": incident.QuestionNumber,
+				"subscribers":     incident.Subscribers,
+				"delivered":       incident.Delivered,
+				"ratio":           incident.Ratio,
+				"threshold":       incident.Threshold,
+				"voided":          incident.Voided,
+				"instance_id":     shardedHub.GetInstanceID(),
+			},
+		})
+	})
+
+	// Scheduled JWT signing key rotation; manual rotation is POST /api/auth/admin/rotate-keys
+	if cfg.JWT.Rotation.Enabled {
+		tokenManager.SetKeyRotationHook(func(rotation manager.KeyRotation) {
+			auditService.Record(ctx, service.AuditEntry{
+				Action:     entity.AuditActionJWTKeyRotate,
+				TargetType: entity.AuditTargetJWTKey,
+				TargetID:   rotation.NewKeyID,
+				Metadata:   map[string]interface{}{"previous_key_id": rotation.PreviousKeyID, "trigger": rotation.Trigger},
+			})
+		})
+		tokenManager.StartKeyRotation(ctx, cfg.JWT.Rotation.CheckInterval)
+	}
+
 	// РРЅРёС†РёР°Р»РёР·РёСЂСѓРµРј РѕР±СЂР°Р±РѕС‚С‡РёРєРё
 	authHandler := handler.NewAuthHandler(authService, tokenManager, wsHub)
 	mobileAuthHandler := handler.NewMobileAuthHandler(authService, tokenManager, wsHub)
 	quizHandler := handler.NewQuizHandler(quizService, resultService, quizManagerService)
 	wsHandler := handler.NewWSHandler(wsHub, wsManager, quizManagerService, jwtService, cfg.WebSocket, cfg.CORS.AllowedOrigins)
+	wsHandler.SetGeoService(geoService)
+	wsHandler.SetIPRiskService(ipRiskService)
 	userHandler := handler.NewUserHandler(userService, resultService)
+	authHandler.SetUserService(userService)
+	userHandler.SetRatingService(ratingService)
 	adHandler := handler.NewAdHandler(adService, quizAdSlotService)
+	referralHandler := handler.NewReferralHandler(referralService)
+	pushHandler := handler.NewPushNotificationHandler(pushService)
+	notificationHandler := handler.NewNotificationHandler(notificationService)
+	walletHandler := handler.NewWalletHandler(walletService)
+	purchaseHandler := handler.NewPurchaseHandler(purchaseService)
+	analyticsHandler := handler.NewAnalyticsHandler(analyticsService)
+	analyticsHandler.SetMobileClientService(mobileClientService)
+	userAnalyticsHandler := handler.NewUserAnalyticsHandler(userAnalyticsService)
+	exportHandler := handler.NewExportHandler(exportService)
+
+	// Connection pool utilization for admins (per instance)
+	sqlDB, err := database.GetSQLDB(db)
+	if err != nil {
+		log.Printf("Failed to get sql.DB for stats: %v", err)
+		os.Exit(1)
+	}
+	var slowQueryCounter handler.SlowQueryCounter
+	if plugin, ok := database.SlowQueryPlugin(db); ok {
+		slowQueryCounter = plugin
+	}
+	dbStatsHandler := handler.NewDBStatsHandler(sqlDB, slowQueryCounter)
+	adminGraph, err := admingraph.NewGraph(quizService, resultService, userRepo, quizRepo, quizAdSlotRepo)
+	if err != nil {
+		log.Fatalf("Failed to build admin GraphQL schema: %v", err)
+	}
+	adminGraphQLHandler := handler.NewAdminGraphQLHandler(adminGraph)
+	avatarHandler := handler.NewAvatarHandler(avatarService)
+	shareCardHandler := handler.NewShareCardHandler(shareCardService)
+	auditHandler := handler.NewAuditHandler(auditService)
+	maintenanceHandler := handler.NewMaintenanceHandler(maintenanceService)
+	legalHandler := handler.NewLegalHandler(legalService)
+	maintenanceHandler.SetAuditService(auditService)
+	featureFlagHandler := handler.NewFeatureFlagHandler(featureFlagService)
+	featureFlagHandler.SetAuditService(auditService)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	webhookHandler.SetAuditService(auditService)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
+	apiKeyHandler.SetAuditService(auditService)
+	translationHandler := handler.NewTranslationHandler(translationService)
+	translationHandler.SetAuditService(auditService)
+	translationHandler.SetAssistService(translationAssistService)
+	questionMediaHandler := handler.NewQuestionMediaHandler(questionMediaService)
+	questionMediaHandler.SetAuditService(auditService)
+	questionReviewHandler := handler.NewQuestionReviewHandler(questionReviewService)
+	questionImportHandler := handler.NewQuestionImportHandler(questionImportService)
+	questionImportHandler.SetAuditService(auditService)
+	questionGenerationHandler := handler.NewQuestionGenerationHandler(questionGenerationService)
+	questionGenerationHandler.SetAuditService(auditService)
+	moderationHandler := handler.NewModerationHandler(moderationService)
+	moderationHandler.SetAuditService(auditService)
+	geoHandler := handler.NewGeoHandler(geoService)
+	ipRiskHandler := handler.NewIPRiskHandler(ipRiskService)
+	ipRiskHandler.SetAuditService(auditService)
+	taxonomyHandler := handler.NewTaxonomyHandler(taxonomyService)
+	quizHandler.SetTaxonomyService(taxonomyService)
+	quizHandler.SetRSVPService(rsvpService)
+	rsvpHandler := handler.NewRSVPHandler(rsvpService)
+	followHandler := handler.NewFollowHandler(followService)
+	dailyChallengeHandler := handler.NewDailyChallengeHandler(dailyChallengeService)
+	announcementHandler := handler.NewAnnouncementHandler(announcementService)
+	announcementHandler.SetAuditService(auditService)
+	questionReviewHandler.SetAuditService(auditService)
+	secondChanceHandler := handler.NewSecondChanceHandler(secondChanceService)
+	sponsorHandler := handler.NewSponsorHandler(sponsorService)
+	sponsorHandler.SetAuditService(auditService)
+	quizHandler.SetSponsorService(sponsorService)
+	timeHandler := handler.NewTimeHandler()
+	publicStatsHandler := handler.NewPublicStatsHandler(publicStatsService)
+	secondChanceHandler.SetAuditService(auditService)
+	prizeClaimHandler := handler.NewPrizeClaimHandler(prizeClaimService)
+	prizeClaimHandler.SetAuditService(auditService)
+	abuseHandler := handler.NewAbuseHandler(abuseService)
+	abuseHandler.SetAuditService(auditService)
+	emailHandler := handler.NewEmailHandler(emailDispatcher)
+	emailHandler.SetAuditService(auditService)
+	emailTemplateHandler := handler.NewEmailTemplateHandler(emailTemplateService)
+	emailTemplateHandler.SetAuditService(auditService)
+	quizHandler.SetTranslationService(translationService)
+	authHandler.SetAuditService(auditService)
+	mobileAuthHandler.SetAuditService(auditService)
+	quizHandler.SetAuditService(auditService)
+	taxonomyHandler.SetAuditService(auditService)
+	adHandler.SetAuditService(auditService)
+	walletHandler.SetAuditService(auditService)
 
 	// РРЅРёС†РёР°Р»РёР·РёСЂСѓРµРј middleware
 	authMiddleware := middleware.NewAuthMiddlewareWithManager(jwtService, tokenManager)
+	// Synchronizer-token CSRF for admin routes: server-stored nonces per admin browser session
+	var csrfNonceService *service.CSRFNonceService
+	if cfg.Auth.CSRF.AdminMode == config.CSRFModeSynchronizer {
+		csrfNonceService = service.NewCSRFNonceService(cacheRepo, cfg.Auth.CSRF.NonceTTL, cfg.Auth.CSRF.RotationGrace)
+		authMiddleware.SetAdminCSRFNonces(csrfNonceService)
+		authHandler.SetCSRFNonceService(csrfNonceService)
+	}
+	// Until the current legal versions are accepted, authenticated routes answer 428
+	// legal_acceptance_required; accepting, reading the profile, logout and account deletion stay open
+	if cfg.Legal.RequireReacceptance {
+		legalExempt := []string{
+			"/api/auth/accept-legal", "/api/auth/legal", "/api/auth/csrf",
+			"/api/auth/logout", "/api/auth/logout-all", "/api/users/me",
+		}
+		for _, prefix := range []string{"/api/mobile", "/api/mobile/v2"} {
+			legalExempt = append(legalExempt,
+				prefix+"/auth/accept-legal", prefix+"/auth/legal", prefix+"/auth/logout-all",
+				prefix+"/auth/me", prefix+"/users/me",
+			)
+		}
+		authMiddleware.SetLegalAcceptance(legalService, cfg.Legal.TOSVersion, cfg.Legal.PrivacyVersion, legalExempt...)
+	}
 	rateLimiter := middleware.NewRateLimiter(redisClient)
+	// Per-group limits from config.yaml (rateLimits) override these built-in presets
+	// on every request, so a config reload takes effect without a restart
+	rateLimiter.SetRules(cfg.RateLimits)
+	authRateLimitCfg := middleware.DefaultAuthRateLimitConfig()
+	strictRateLimitCfg := middleware.StrictAuthRateLimitConfig()
+	mobileRateLimitCfg := middleware.RateLimitConfig{
+		MaxRequests: 20, MaxUserRequests: 120, Window: time.Minute, KeyPrefix: "rl:mobile", Group: "mobile",
+	}
+	usersRateLimitCfg := middleware.RateLimitConfig{
+		MaxUserRequests: 120, Window: time.Minute, KeyPrefix: "rl:users", Group: "users",
+	}
+	uploadsRateLimitCfg := middleware.RateLimitConfig{
+		MaxUserRequests: 10, Window: 10 * time.Minute, KeyPrefix: "rl:uploads", Group: "uploads",
+	}
+	followsRateLimitCfg := middleware.RateLimitConfig{
+		MaxUserRequests: 30, Window: time.Minute, KeyPrefix: "rl:follows", Group: "follows",
+	}
+	apiKeysRateLimitCfg := middleware.RateLimitConfig{
+		MaxRequests: 60, Window: time.Minute, KeyPrefix: "rl:api_keys", Group: "api_keys",
+	}
+	// Anonymous tier for embeddable landing widgets: no API key, strict per-IP budget
+	publicRateLimitCfg := middleware.RateLimitConfig{
+		MaxRequests: 10, Window: time.Minute, KeyPrefix: "rl:public", Group: "public",
+	}
+	strictRateLimit := rateLimiter.Limit(strictRateLimitCfg)
+
+	// Hot reload of non-structural settings (rate limits, quiz timings) on SIGHUP or config file change
+	configWatcher := config.NewWatcher(configPath, cfg)
+	configWatcher.OnReload(func(c *config.Config) {
+		rateLimiter.SetRules(c.RateLimits)
+		applyQuizTiming(c.Quiz)
+	})
+	configWatcher.Start(ctx, cfg.Reload.WatchFile)
+	mobileUserRateLimit := rateLimiter.LimitByUser(mobileRateLimitCfg)
+	uploadsRateLimit := rateLimiter.LimitByUser(uploadsRateLimitCfg)
+	followsRateLimit := rateLimiter.LimitByUser(followsRateLimitCfg)
 
 	// РРЅРёС†РёР°Р»РёР·РёСЂСѓРµРј СЂРѕСѓС‚РµСЂ Gin
 	router := gin.Default()
+	router.Use(middleware.Tracing())
+	// Security headers (CSP, HSTS, ...) per route group; /admin and uploads override the defaults below
+	router.Use(middleware.SecurityHeaders(middleware.SecurityHeadersPolicyFor(cfg.SecurityHeaders, config.SecurityHeadersGroupDefault, middleware.DefaultSecurityHeadersPolicy())))
+	// API error messages follow the user's language (?lang=, users.language, Accept-Language)
+	router.Use(middleware.Locale(translationService))
+	router.Use(middleware.FeatureFlags(featureFlagService))
+	// Errors passed via c.Error and handler panics are rendered in the common error format
+	router.Use(response.ErrorHandler())
+	// In maintenance mode writes get 503; sign-in and token refresh keep sessions alive for reads
+	router.Use(middleware.Maintenance(maintenanceService,
+		"/api/admin/maintenance",
+		"/api/auth/login", "/api/auth/refresh", "/api/auth/check-refresh", "/api/auth/token-info", "/api/auth/ws-ticket",
+		"/api/mobile/auth/login", "/api/mobile/auth/refresh", "/api/mobile/auth/device-key", "/api/mobile/auth/ws-ticket",
+		"/api/mobile/v2/auth/login", "/api/mobile/v2/auth/refresh", "/api/mobile/v2/auth/device-key", "/api/mobile/v2/auth/ws-ticket",
+	))
 
 	// РќР°СЃС‚СЂРѕР№РєР° РґРѕРІРµСЂРµРЅРЅС‹С… РїСЂРѕРєСЃРё РґР»СЏ РєРѕСЂСЂРµРєС‚РЅРѕР№ СЂР°Р±РѕС‚С‹ c.ClientIP()
 	// Р’ production (GIN_MODE=release): РЅРµ РґРѕРІРµСЂСЏРµРј РїСЂРѕРєСЃРё (Р·Р°С‰РёС‚Р° РѕС‚ IP spoofing)
@@ -337,31 +1259,49 @@ func main() {
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     cfg.CORS.AllowedOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-CSRF-Token"},
-		ExposeHeaders:    []string{"Content-Length", "X-Quiz-Schedule-Warning", "Retry-After", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-CSRF-Token", "X-Request-ID", "traceparent", "tracestate", "If-None-Match", response.EnvelopeHeader},
+		ExposeHeaders:    []string{"Content-Length", "X-Quiz-Schedule-Warning", "Retry-After", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "X-Request-ID", "X-Trace-ID", "ETag", "X-CSRF-Token", middleware.CSRFFamilyHeader},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
 
 	// РЎС‚Р°С‚РёС‡РµСЃРєРёРµ С„Р°Р№Р»С‹ РґР»СЏ Р°РґРјРёРЅ-РїР°РЅРµР»Рё
-	router.StaticFS("/admin", http.Dir("./static/admin"))
+	adminPanel := router.Group("/admin", middleware.SecurityHeaders(middleware.SecurityHeadersPolicyFor(cfg.SecurityHeaders, config.SecurityHeadersGroupAdmin, middleware.AdminSecurityHeadersPolicy())))
+	if csrfNonceService != nil {
+		// Admin pages get the CSRF nonce injected when opened by a signed-in admin
+		adminPanelHandler := handler.NewAdminPanelHandler("./static/admin", "/admin", jwtService, tokenManager, csrfNonceService)
+		adminPanel.GET("/*filepath", adminPanelHandler.Serve)
+		adminPanel.HEAD("/*filepath", adminPanelHandler.Serve)
+	} else {
+		adminPanel.StaticFS("/", http.Dir("./static/admin"))
+	}
 
 	// РЎС‚Р°С‚РёС‡РµСЃРєРёРµ С„Р°Р№Р»С‹ РґР»СЏ Р·Р°РіСЂСѓР¶РµРЅРЅС‹С… СЂРµРєР»Р°Рј
-	router.Static("/uploads/ads", "./uploads/ads")
+	if _, ok := uploadStorage.(*storage.LocalStorage); ok {
+		uploads := router.Group(cfg.Storage.LocalURL, middleware.SecurityHeaders(middleware.SecurityHeadersPolicyFor(cfg.SecurityHeaders, config.SecurityHeadersGroupUploads, middleware.UploadsSecurityHeadersPolicy())))
+		uploads.Static("/", cfg.Storage.LocalDir)
+	}
+
+	// Public result card pages with OpenGraph metadata for social media previews
+	router.GET("/share/:token", shareCardHandler.GetSharePage)
 
 	// РќР°СЃС‚СЂР°РёРІР°РµРј РјР°СЂС€СЂСѓС‚С‹ API
 	api := router.Group("/api")
 	{
 		// РђСѓС‚РµРЅС‚РёС„РёРєР°С†РёСЏ
 		authGroup := api.Group("/auth")
-		authDefaultRateLimit := rateLimiter.Limit(middleware.DefaultAuthRateLimitConfig())
+		authDefaultRateLimit := rateLimiter.Limit(authRateLimitCfg)
 		{
-			authGroup.POST("/register", rateLimiter.Limit(middleware.StrictAuthRateLimitConfig()), authHandler.Register)
-			authGroup.POST("/login", rateLimiter.Limit(middleware.StrictAuthRateLimitConfig()), authHandler.Login)
+			authGroup.POST("/register", strictRateLimit, authHandler.Register)
+			authGroup.POST("/login", strictRateLimit, authHandler.Login)
 			authGroup.POST("/refresh", authDefaultRateLimit, authHandler.RefreshToken)
 			authGroup.POST("/check-refresh", authDefaultRateLimit, authHandler.CheckRefreshToken)
 			authGroup.POST("/token-info", authDefaultRateLimit, authHandler.GetTokenInfo)
 			authGroup.POST("/google/exchange", authDefaultRateLimit, authHandler.GoogleExchange)
+			authGroup.POST("/magic-link", strictRateLimit, authHandler.RequestMagicLink)
+			authGroup.GET("/magic-link/verify", authDefaultRateLimit, authHandler.VerifyMagicLink)
+			authGroup.POST("/webauthn/login/begin", authDefaultRateLimit, authHandler.BeginPasskeyLogin)
+			authGroup.POST("/webauthn/login/finish", strictRateLimit, authHandler.FinishPasskeyLogin)
 
 			// РњР°СЂС€СЂСѓС‚С‹, С‚СЂРµР±СѓСЋС‰РёРµ Р°СѓС‚РµРЅС‚РёС„РёРєР°С†РёРё
 			authedAuth := authGroup.Group("/")
@@ -370,6 +1310,8 @@ func main() {
 				// Р­РЅРґРїРѕРёРЅС‚ РґР»СЏ РїРѕР»СѓС‡РµРЅРёСЏ CSRF С‚РѕРєРµРЅР° (С…РµС€Р°)
 				authedAuth.GET("/csrf", authHandler.GetCSRFToken)
 				authedAuth.GET("/verify-email/status", authHandler.GetEmailVerificationStatus)
+				authedAuth.GET("/webauthn/credentials", authHandler.ListPasskeys)
+				authedAuth.GET("/legal", legalHandler.GetLegalStatus)
 
 				// РњР°СЂС€СЂСѓС‚С‹, С‚СЂРµР±СѓСЋС‰РёРµ Рё Р°СѓС‚РµРЅС‚РёС„РёРєР°С†РёРё, Рё CSRF С‚РѕРєРµРЅР°
 				csrfProtected := authedAuth.Group("/")
@@ -384,6 +1326,10 @@ func main() {
 					csrfProtected.POST("/verify-email/send", authHandler.SendEmailVerificationCode)
 					csrfProtected.POST("/verify-email/confirm", authHandler.ConfirmEmailVerificationCode)
 					csrfProtected.POST("/google/link", authHandler.GoogleLink)
+					csrfProtected.POST("/webauthn/register/begin", authHandler.BeginPasskeyRegistration)
+					csrfProtected.POST("/webauthn/register/finish", authHandler.FinishPasskeyRegistration)
+					csrfProtected.DELETE("/webauthn/credentials/:id", authHandler.DeletePasskey)
+					csrfProtected.POST("/accept-legal", legalHandler.AcceptLegal)
 				}
 			}
 
@@ -395,29 +1341,107 @@ func main() {
 				adminAuth.POST("/reset-auth", authHandler.ResetAuth)
 				adminAuth.POST("/debug-token", authHandler.DebugToken)
 				adminAuth.POST("/reset-password", authHandler.AdminResetPassword)
+				adminAuth.POST("/rotate-keys", authHandler.RotateKeys)
+				adminAuth.GET("/keys", authHandler.ListKeys)
 			}
 		}
 
 		// РџРѕР»СЊР·РѕРІР°С‚РµР»Рё
 		users := api.Group("/users")
-		users.Use(authMiddleware.RequireAuth())
+		users.Use(rateLimiter.LimitByIP(usersRateLimitCfg), authMiddleware.RequireAuth(), rateLimiter.LimitByUser(usersRateLimitCfg))
 		{
 			users.GET("/me", authHandler.GetMe)
+			users.GET("/me/features", featureFlagHandler.GetMyFeatures)
 			users.GET("/me/results", userHandler.GetMyResults) // РСЃС‚РѕСЂРёСЏ РёРіСЂ
 			users.PUT("/me", authMiddleware.RequireCSRF(), authHandler.UpdateProfile)
 			users.PUT("/me/language", authMiddleware.RequireCSRF(), authHandler.UpdateLanguage)
+			users.POST("/me/avatar", authMiddleware.RequireCSRF(), uploadsRateLimit, avatarHandler.UploadAvatar)
+			users.DELETE("/me/avatar", authMiddleware.RequireCSRF(), avatarHandler.DeleteAvatar)
 			users.DELETE("/me", authMiddleware.RequireCSRF(), authHandler.DeleteMe)
+
+			// Public player profiles and their privacy settings
+			users.GET("/:id/profile", userHandler.GetPublicProfile)
+			users.PUT("/me/privacy", authMiddleware.RequireCSRF(), userHandler.UpdatePrivacy)
+			users.GET("/me/rating/history", userHandler.GetMyRatingHistory)
+
+			// Follows and the friends-only leaderboard
+			users.GET("/:id/follow", followHandler.GetFollowStatus)
+			users.POST("/:id/follow", authMiddleware.RequireCSRF(), followsRateLimit, followHandler.Follow)
+			users.DELETE("/:id/follow", authMiddleware.RequireCSRF(), followsRateLimit, followHandler.Unfollow)
+			users.GET("/me/following", followHandler.ListFollowing)
+			users.GET("/me/followers", followHandler.ListFollowers)
+			users.GET("/me/friends/leaderboard", followHandler.GetFriendsLeaderboard)
+			users.GET("/me/analytics", userAnalyticsHandler.GetMyAnalytics)
+			if referralService != nil {
+				users.GET("/me/referral", referralHandler.GetMyReferral)
+			}
+
+			// Центр уведомлений
+			users.GET("/me/notifications", notificationHandler.ListNotifications)
+			users.GET("/me/notifications/unread-count", notificationHandler.GetUnreadCount)
+			users.POST("/me/notifications/read", authMiddleware.RequireCSRF(), notificationHandler.MarkRead)
+			users.POST("/me/notifications/read-all", authMiddleware.RequireCSRF(), notificationHandler.MarkAllRead)
+			users.GET("/me/notifications/preferences", notificationHandler.GetPreferences)
+			users.PUT("/me/notifications/preferences", authMiddleware.RequireCSRF(), notificationHandler.UpdatePreferences)
+
+			// Кошелёк и выплаты
+			if walletService != nil {
+				users.GET("/me/wallet", walletHandler.GetMyWallet)
+				users.GET("/me/wallet/transactions", walletHandler.GetMyTransactions)
+				users.GET("/me/wallet/payouts", walletHandler.GetMyPayouts)
+				users.POST("/me/wallet/payouts", authMiddleware.RequireCSRF(), walletHandler.RequestPayout)
+				users.POST("/me/wallet/payouts/:id/cancel", authMiddleware.RequireCSRF(), walletHandler.CancelPayout)
+			}
+
+			// Premium entitlements from in-app purchases
+			if purchaseService != nil {
+				users.GET("/me/entitlements", purchaseHandler.GetMyEntitlements)
+			}
+		}
+
+		// Server time for client clock sync (public, never cached)
+		api.GET("/time", timeHandler.GetTime)
+
+		// Landing page counters (public, cached in Redis, strict per-IP limit)
+		api.GET("/public/stats", rateLimiter.LimitByIP(publicRateLimitCfg), publicStatsHandler.GetStats)
+
+		// Delivery status callbacks from email providers (authenticated by provider signature/token)
+		if emailDispatcher != nil {
+			api.POST("/webhooks/email/:provider", emailHandler.HandleCallback)
+		}
+
+		// App Store Server Notifications and Google Play RTDN (Pub/Sub push); purchase state is re-read from the store API
+		if purchaseService != nil {
+			api.POST("/webhooks/purchases/:store", purchaseHandler.HandleStoreNotification)
 		}
 
 		// Р›РёРґРµСЂР±РѕСЂРґ (РїСѓР±Р»РёС‡РЅС‹Р№ РјР°СЂС€СЂСѓС‚)
-		api.GET("/leaderboard", userHandler.GetLeaderboard)
+		api.GET("/leaderboard", cachedResponse(httpcache.NamespaceLeaderboard), userHandler.GetLeaderboard)
+
+		// Daily challenge; the daily leaderboard changes with every answer and is not cached
+		if dailyChallengeService != nil {
+			daily := api.Group("/daily-challenge")
+			{
+				daily.GET("/leaderboard", dailyChallengeHandler.GetLeaderboard)
+				daily.GET("", authMiddleware.RequireAuth(), dailyChallengeHandler.GetToday)
+				daily.POST("/answer", authMiddleware.RequireAuth(), authMiddleware.RequireCSRF(), dailyChallengeHandler.Answer)
+			}
+		}
+
+		// Quiz categories and tags; ?category=&tag= on /quizzes filters by slug
+		api.GET("/categories", taxonomyHandler.ListCategories)
+		api.GET("/categories/:slug/leaderboard", cachedResponse(httpcache.NamespaceLeaderboard), taxonomyHandler.GetCategoryLeaderboard)
+		api.GET("/tags", taxonomyHandler.ListTags)
 
 		// Р’РёРєС‚РѕСЂРёРЅС‹
 		quizzes := api.Group("/quizzes")
 		{
-			quizzes.GET("", quizHandler.ListQuizzes)
+			quizzes.GET("", cachedResponse(httpcache.NamespaceQuizzes), quizHandler.ListQuizzes)
 			quizzes.GET("/active", quizHandler.GetActiveQuiz)
+			// Not cached: starts_in_seconds and registered_count change between requests
 			quizzes.GET("/scheduled", quizHandler.GetScheduledQuizzes)
+			// iCalendar feed of the schedule for calendar subscriptions and embeds
+			quizzes.GET("/calendar.ics", cachedResponse(httpcache.NamespaceQuizzes), quizHandler.GetScheduleCalendar)
 
 			// Р“СЂСѓРїРїР° РјР°СЂС€СЂСѓС‚РѕРІ, С‚СЂРµР±СѓСЋС‰РёС… quizID
 			quizWithID := quizzes.Group("/:id")
@@ -426,12 +1450,33 @@ func main() {
 				quizWithID.GET("", quizHandler.GetQuiz)
 				quizWithID.GET("/with-questions", quizHandler.GetQuizWithQuestions)
 				quizWithID.GET("/results", quizHandler.GetQuizResults)
+				// Per-question replay of a completed quiz, computed once and cached
+				quizWithID.GET("/timeline", quizHandler.GetQuizTimeline)
+
+				// SSE fallback for networks that block WebSocket upgrades; same ticket auth as /ws.
+				// The ticket is redacted from access logs after the stream ends.
+				quizWithID.GET("/events", func(c *gin.Context) {
+					wsHandler.HandleQuizEvents(c)
+					if c.Request.URL.RawQuery != "" {
+						c.Request.URL.RawQuery = "ticket=[REDACTED]"
+					}
+				})
 
 				// РњР°СЂС€СЂСѓС‚С‹ РґР»СЏ Р°СѓС‚РµРЅС‚РёС„РёС†РёСЂРѕРІР°РЅРЅС‹С… РїРѕР»СЊР·РѕРІР°С‚РµР»РµР№
 				authedQuizzes := quizWithID.Group("") // РќР°СЃР»РµРґСѓРµС‚ middleware
 				authedQuizzes.Use(authMiddleware.RequireAuth())
 				{
 					authedQuizzes.GET("/my-result", quizHandler.GetUserQuizResult)
+					authedQuizzes.GET("/my-result/share", shareCardHandler.GetMyResultShare)
+					authedQuizzes.GET("/rsvp", rsvpHandler.GetRSVP)
+					authedQuizzes.POST("/rsvp", authMiddleware.RequireCSRF(), rsvpHandler.RSVP)
+					authedQuizzes.DELETE("/rsvp", authMiddleware.RequireCSRF(), rsvpHandler.CancelRSVP)
+					authedQuizzes.POST("/second-chance/start", authMiddleware.RequireCSRF(), secondChanceHandler.Start)
+					authedQuizzes.POST("/second-chance", authMiddleware.RequireCSRF(), secondChanceHandler.Revive)
+					if prizeClaimService != nil {
+						authedQuizzes.GET("/claim", prizeClaimHandler.GetClaim)
+						authedQuizzes.POST("/claim", authMiddleware.RequireCSRF(), prizeClaimHandler.Submit)
+					}
 				}
 
 				// РњР°СЂС€СЂСѓС‚С‹ РґР»СЏ Р°РґРјРёРЅРёСЃС‚СЂР°С‚РѕСЂРѕРІ
@@ -441,12 +1486,55 @@ func main() {
 				{
 					adminQuizzes.POST("/questions", quizHandler.AddQuestions)
 					adminQuizzes.PUT("/schedule", quizHandler.ScheduleQuiz)
+					adminQuizzes.POST("/schedule", quizHandler.ScheduleQuiz) // ?dry_run=true for a validation report
 					adminQuizzes.PUT("/cancel", quizHandler.CancelQuiz)
 					adminQuizzes.POST("/duplicate", quizHandler.DuplicateQuiz)
+					adminQuizzes.PUT("/taxonomy", taxonomyHandler.SetQuizTaxonomy)
+					adminQuizzes.GET("/results/full", quizHandler.GetQuizResults)      // results without players' privacy settings applied
 					adminQuizzes.GET("/results/export", quizHandler.ExportQuizResults) // CSV/Excel СЌРєСЃРїРѕСЂС‚
 					adminQuizzes.GET("/statistics", quizHandler.GetQuizStatistics)     // Р Р°СЃС€РёСЂРµРЅРЅР°СЏ СЃС‚Р°С‚РёСЃС‚РёРєР°
 					adminQuizzes.GET("/winners", quizHandler.GetQuizWinners)           // РЎРїРёСЃРѕРє РїРѕР±РµРґРёС‚РµР»РµР№
 					adminQuizzes.GET("/asked-questions", quizHandler.GetQuizAskedQuestions)
+					adminQuizzes.GET("/preview", quizHandler.GetQuizPreview) // likely questions and timeline, read-only
+					// Background exports (results or answers; csv, xlsx, json) for large quizzes
+					adminQuizzes.POST("/exports", exportHandler.CreateExport)
+					adminQuizzes.GET("/exports", exportHandler.ListExports)
+					adminQuizzes.GET("/exports/:jobId", exportHandler.GetExport)
+					// Sandbox replay of a completed quiz for scoring debugging
+					adminQuizzes.POST("/simulations", quizHandler.StartQuizSimulation)
+					adminQuizzes.GET("/simulations/:runId", quizHandler.GetQuizSimulation)
+
+					// Live-ops console: intervene in the running quiz (audited)
+					adminQuizzes.POST("/live/pause", quizHandler.PauseQuestion)
+					adminQuizzes.POST("/live/resume", quizHandler.ResumeQuestion)
+					adminQuizzes.POST("/live/extend", quizHandler.ExtendQuestion)
+					adminQuizzes.POST("/live/skip", quizHandler.SkipQuestion)
+					adminQuizzes.POST("/live/announce", quizHandler.Announce)
+					// Question delivery skew behind per-player deadlines (this node's acks)
+					adminQuizzes.GET("/live/delivery", quizHandler.GetDeliveryStats)
+					if announcementService != nil {
+						// Host announcements scheduled relative to milestones (T-N seconds, after question N, before the finale)
+						adminQuizzes.GET("/announcements", announcementHandler.ListAnnouncements)
+						adminQuizzes.POST("/announcements", announcementHandler.CreateAnnouncement)
+						adminAnnouncement := adminQuizzes.Group("/announcements/:announcementId", middleware.ExtractUintParam("announcementId", "announcementID"))
+						adminAnnouncement.PUT("", announcementHandler.UpdateAnnouncement)
+						adminAnnouncement.DELETE("", announcementHandler.DeleteAnnouncement)
+					}
+					adminQuizzes.PUT("/second-chance", secondChanceHandler.Configure)
+					adminQuizzes.PUT("/sponsor", sponsorHandler.Configure)
+
+					// Waiting room capacity: players beyond max_players wait in an admission queue
+					adminQuizzes.GET("/admission", quizHandler.GetAdmission)
+					adminQuizzes.PUT("/capacity", quizHandler.SetQuizCapacity)
+					adminQuizzes.POST("/admission/admit", quizHandler.AdmitPlayer)
+
+					// Game mode: elimination, points-only or lives, with optional sudden-death finals
+					adminQuizzes.PUT("/game-mode", quizHandler.SetGameMode)
+					adminQuizzes.PUT("/tie-break", quizHandler.SetTieBreak)
+					adminQuizzes.PUT("/prize-ladder", quizHandler.SetPrizeLadder)
+					if geoService != nil {
+						adminQuizzes.PUT("/allowed-regions", quizHandler.SetAllowedRegions)
+					}
 
 					// Р РµРєР»Р°РјРЅС‹Рµ СЃР»РѕС‚С‹ РІРёРєС‚РѕСЂРёРЅС‹
 					adminQuizzes.POST("/ad-slots", adHandler.CreateAdSlot)
@@ -475,6 +1563,295 @@ func main() {
 			adminAds.DELETE("/:id", adHandler.DeleteAdAsset)
 		}
 
+		// Referral program reporting (admin)
+		if referralService != nil {
+			adminReferrals := api.Group("/admin/referrals")
+			adminReferrals.Use(authMiddleware.RequireAuth(), authMiddleware.AdminOnly())
+			{
+				adminReferrals.GET("", referralHandler.ListReferrals)
+				adminReferrals.GET("/stats", referralHandler.GetStats)
+				adminReferrals.GET("/top", referralHandler.GetTopReferrers)
+			}
+		}
+
+		// Кошельки и выплаты (для администраторов)
+		if walletService != nil {
+			adminWallet := api.Group("/admin/wallet")
+			adminWallet.Use(authMiddleware.RequireAuth(), authMiddleware.AdminOnly())
+			{
+				adminWallet.GET("/payouts", walletHandler.ListPayouts)
+				adminWallet.GET("/reconciliation", walletHandler.GetReconciliation)
+				adminWallet.POST("/payouts/:id/approve", authMiddleware.RequireCSRF(), walletHandler.ApprovePayout)
+				adminWallet.POST("/payouts/:id/reject", authMiddleware.RequireCSRF(), walletHandler.RejectPayout)
+				adminWallet.POST("/payouts/:id/paid", authMiddleware.RequireCSRF(), walletHandler.MarkPayoutPaid)
+			}
+		}
+
+		// Prize claims review (admin)
+		if prizeClaimService != nil {
+			adminPrizeClaims := api.Group("/admin/prize-claims")
+			adminPrizeClaims.Use(authMiddleware.RequireAuth(), authMiddleware.AdminOnly())
+			{
+				adminPrizeClaims.GET("", prizeClaimHandler.ListClaims)
+
+				adminPrizeClaim := adminPrizeClaims.Group("/:id", middleware.ExtractUintParam("id", "claimID"))
+				adminPrizeClaim.POST("/verify", authMiddleware.RequireCSRF(), prizeClaimHandler.Verify)
+				adminPrizeClaim.POST("/reject", authMiddleware.RequireCSRF(), prizeClaimHandler.Reject)
+			}
+		}
+
+		// Multi-account review and shadow bans (admin)
+		adminAbuse := api.Group("/admin/abuse")
+		adminAbuse.Use(authMiddleware.RequireAuth(), authMiddleware.AdminOnly())
+		{
+			adminAbuse.GET("/devices", abuseHandler.ListSharedDevices)
+			adminAbuse.GET("/devices/:hash/users", abuseHandler.ListDeviceAccounts)
+			adminAbuse.GET("/shadow-banned", abuseHandler.ListShadowBanned)
+
+			adminAbuseUser := adminAbuse.Group("/users/:id", middleware.ExtractUintParam("id", "targetUserID"))
+			adminAbuseUser.POST("/shadow-ban", authMiddleware.RequireCSRF(), abuseHandler.ShadowBan)
+			adminAbuseUser.DELETE("/shadow-ban", authMiddleware.RequireCSRF(), abuseHandler.LiftShadowBan)
+
+			// VPN/proxy risk of quiz sessions and the review queue of high-risk winners
+			if ipRiskService != nil {
+				adminAbuse.GET("/ip-risk/sessions", ipRiskHandler.ListSessions)
+				adminAbuse.GET("/prize-reviews", ipRiskHandler.ListReviews)
+				adminPrizeReview := adminAbuse.Group("/prize-reviews/:id", middleware.ExtractUintParam("id", "reviewID"))
+				adminPrizeReview.POST("/approve", authMiddleware.RequireCSRF(), ipRiskHandler.ApproveReview)
+				adminPrizeReview.POST("/reject", authMiddleware.RequireCSRF(), ipRiskHandler.RejectReview)
+			}
+		}
+
+		// Журнал аудита (для администраторов)
+		adminAudit := api.Group("/admin/audit")
+		adminAudit.Use(authMiddleware.RequireAuth(), authMiddleware.AdminOnly())
+		{
+			adminAudit.GET("", auditHandler.ListAuditLog)
+		}
+
+		// Агрегированная аналитика для админ-панели
+		adminAnalytics := api.Group("/admin/analytics")
+		adminAnalytics.Use(authMiddleware.RequireAuth(), authMiddleware.AdminOnly())
+		{
+			adminAnalytics.GET("", analyticsHandler.GetDashboard)
+			adminAnalytics.GET("/mobile-clients", analyticsHandler.GetMobileClients)
+		}
+
+		// Флаги функций
+		adminFeatureFlags := api.Group("/admin/feature-flags")
+		adminFeatureFlags.Use(authMiddleware.RequireAuth(), authMiddleware.AdminOnly())
+		{
+			adminFeatureFlags.GET("", featureFlagHandler.ListFeatureFlags)
+			adminFeatureFlags.POST("", authMiddleware.RequireCSRF(), featureFlagHandler.CreateFeatureFlag)
+			adminFeatureFlags.PUT("/:key", authMiddleware.RequireCSRF(), featureFlagHandler.UpdateFeatureFlag)
+			adminFeatureFlags.DELETE("/:key", authMiddleware.RequireCSRF(), featureFlagHandler.DeleteFeatureFlag)
+		}
+
+		// Шаблоны писем
+		adminEmailTemplates := api.Group("/admin/email/templates")
+		adminEmailTemplates.Use(authMiddleware.RequireAuth(), authMiddleware.AdminOnly())
+		{
+			adminEmailTemplates.GET("", emailTemplateHandler.ListTemplates)
+			adminEmailTemplates.POST("/:key/preview", authMiddleware.RequireCSRF(), emailTemplateHandler.Preview)
+			adminEmailTemplates.GET("/:key/:locale/versions", emailTemplateHandler.ListVersions)
+			adminEmailTemplates.POST("/:key/:locale", authMiddleware.RequireCSRF(), emailTemplateHandler.SaveVersion)
+			adminEmailTemplates.DELETE("/:key/:locale", authMiddleware.RequireCSRF(), emailTemplateHandler.ResetToFile)
+			adminEmailTemplates.POST("/:key/:locale/versions/:version/activate", authMiddleware.RequireCSRF(), emailTemplateHandler.ActivateVersion)
+		}
+
+		// Журнал писем и недоставленные письма
+		if emailDispatcher != nil {
+			adminEmail := api.Group("/admin/email")
+			adminEmail.Use(authMiddleware.RequireAuth(), authMiddleware.AdminOnly())
+			{
+				adminEmail.GET("/messages", emailHandler.ListMessages)
+				adminEmail.GET("/messages/:id/events", middleware.ExtractUintParam("id", "emailMessageID"), emailHandler.ListMessageEvents)
+				adminEmail.GET("/dead-letters", emailHandler.ListDeadLetters)
+				adminEmail.POST("/dead-letters/:id/retry", middleware.ExtractUintParam("id", "deadLetterID"), authMiddleware.RequireCSRF(), emailHandler.RetryDeadLetter)
+			}
+		}
+
+		// Вебхуки партнёров
+		if webhookService != nil {
+			adminWebhooks := api.Group("/admin/webhooks")
+			adminWebhooks.Use(authMiddleware.RequireAuth(), authMiddleware.AdminOnly())
+			{
+				adminWebhooks.GET("", webhookHandler.ListEndpoints)
+				adminWebhooks.POST("", authMiddleware.RequireCSRF(), webhookHandler.CreateEndpoint)
+
+				adminWebhook := adminWebhooks.Group("/:id", middleware.ExtractUintParam("id", "endpointID"))
+				adminWebhook.PUT("", authMiddleware.RequireCSRF(), webhookHandler.UpdateEndpoint)
+				adminWebhook.DELETE("", authMiddleware.RequireCSRF(), webhookHandler.DeleteEndpoint)
+				adminWebhook.GET("/deliveries", webhookHandler.ListDeliveries)
+			}
+		}
+
+		// API keys for partners (admin) and the partner API itself: X-API-Key instead of cookies/CSRF
+		if apiKeyService != nil {
+			adminAPIKeys := api.Group("/admin/api-keys")
+			adminAPIKeys.Use(authMiddleware.RequireAuth(), authMiddleware.AdminOnly())
+			{
+				adminAPIKeys.GET("", apiKeyHandler.ListKeys)
+				adminAPIKeys.POST("", authMiddleware.RequireCSRF(), apiKeyHandler.CreateKey)
+
+				adminAPIKey := adminAPIKeys.Group("/:id", middleware.ExtractUintParam("id", "apiKeyID"))
+				adminAPIKey.DELETE("", authMiddleware.RequireCSRF(), apiKeyHandler.RevokeKey)
+				adminAPIKey.GET("/usage", apiKeyHandler.GetUsage)
+			}
+
+			partner := api.Group("/partner")
+			partner.Use(middleware.RequireAPIKey(apiKeyService), rateLimiter.LimitByAPIKey(apiKeysRateLimitCfg))
+			{
+				partnerQuizzes := partner.Group("/quizzes")
+				partnerQuizzes.GET("", middleware.RequireAPIKeyScope(entity.APIKeyScopeQuizzesRead), quizHandler.ListQuizzes)
+
+				partnerQuiz := partnerQuizzes.Group("/:id", middleware.ExtractUintParam("id", "quizID"))
+				partnerQuiz.GET("", middleware.RequireAPIKeyScope(entity.APIKeyScopeQuizzesRead), quizHandler.GetQuiz)
+				partnerQuiz.GET("/results", middleware.RequireAPIKeyScope(entity.APIKeyScopeResultsRead), quizHandler.GetQuizResults)
+			}
+		}
+
+		// Режим обслуживания «только чтение»
+		adminLegal := api.Group("/admin/legal")
+		adminLegal.Use(authMiddleware.RequireAuth(), authMiddleware.AdminOnly())
+		{
+			adminLegal.GET("/coverage", legalHandler.GetCoverage)
+		}
+
+		adminMaintenance := api.Group("/admin/maintenance")
+		adminMaintenance.Use(authMiddleware.RequireAuth(), authMiddleware.AdminOnly())
+		{
+			adminMaintenance.GET("", maintenanceHandler.GetMaintenance)
+			adminMaintenance.PUT("", authMiddleware.RequireCSRF(), maintenanceHandler.SetMaintenance)
+		}
+
+		// Состояние пула соединений с БД
+		adminDB := api.Group("/admin/db")
+		adminDB.Use(authMiddleware.RequireAuth(), authMiddleware.AdminOnly())
+		{
+			adminDB.GET("/stats", dbStatsHandler.GetStats)
+		}
+
+		// Переводы вопросов (для администраторов)
+		adminTranslations := api.Group("/admin/questions/:id/translations")
+		adminTranslations.Use(authMiddleware.RequireAuth(), authMiddleware.AdminOnly(), middleware.ExtractUintParam("id", "questionID"))
+		{
+			adminTranslations.GET("", translationHandler.ListTranslations)
+			adminTranslations.PUT("/:locale", authMiddleware.RequireCSRF(), translationHandler.UpsertTranslation)
+			adminTranslations.DELETE("/:locale", authMiddleware.RequireCSRF(), translationHandler.DeleteTranslation)
+			adminTranslations.POST("/:locale/accept", authMiddleware.RequireCSRF(), translationHandler.AcceptTranslation)
+		}
+
+		// Review queue of machine translations; manual runs only when translationAssist is enabled
+		adminMachineTranslations := api.Group("/admin/translations/machine")
+		adminMachineTranslations.Use(authMiddleware.RequireAuth(), authMiddleware.AdminOnly())
+		{
+			adminMachineTranslations.GET("", translationHandler.ListMachineTranslations)
+			if translationAssistService != nil {
+				adminMachineTranslations.POST("/run", authMiddleware.RequireCSRF(), translationHandler.RunMachineTranslation)
+			}
+		}
+
+		// Question media: image and audio clip (admin)
+		adminQuestionMedia := api.Group("/admin/questions/:id/media")
+		adminQuestionMedia.Use(authMiddleware.RequireAuth(), authMiddleware.AdminOnly(), middleware.ExtractUintParam("id", "questionID"))
+		adminQuestionMedia.Use(authMiddleware.RequireCSRF())
+		{
+			adminQuestionMedia.POST("", questionMediaHandler.UploadMedia)
+			adminQuestionMedia.DELETE("/:kind", questionMediaHandler.DeleteMedia)
+		}
+
+		// Question review workflow: draft -> pending_review -> approved/rejected (admin)
+		adminQuestionReview := api.Group("/admin/questions/:id/review")
+		adminQuestionReview.Use(authMiddleware.RequireAuth(), authMiddleware.AdminOnly(), middleware.ExtractUintParam("id", "questionID"))
+		{
+			adminQuestionReview.GET("", questionReviewHandler.GetReview)
+			adminQuestionReview.POST("/submit", authMiddleware.RequireCSRF(), questionReviewHandler.Submit)
+			adminQuestionReview.POST("/assign", authMiddleware.RequireCSRF(), questionReviewHandler.AssignReviewer)
+			adminQuestionReview.POST("/approve", authMiddleware.RequireCSRF(), questionReviewHandler.Approve)
+			adminQuestionReview.POST("/reject", authMiddleware.RequireCSRF(), questionReviewHandler.Reject)
+			adminQuestionReview.POST("/comments", authMiddleware.RequireCSRF(), questionReviewHandler.AddComment)
+		}
+		adminQuestionReviews := api.Group("/admin/question-reviews")
+		adminQuestionReviews.Use(authMiddleware.RequireAuth(), authMiddleware.AdminOnly())
+		{
+			adminQuestionReviews.GET("", questionReviewHandler.ListQueue)
+		}
+
+		// Question bank import from external sources (Open Trivia DB) into the review queue
+		adminQuestionImports := api.Group("/admin/question-imports")
+		adminQuestionImports.Use(authMiddleware.RequireAuth(), authMiddleware.AdminOnly())
+		{
+			adminQuestionImports.GET("", questionImportHandler.ListImports)
+			adminQuestionImports.GET("/:id", middleware.ExtractUintParam("id", "importID"), questionImportHandler.GetImport)
+			adminQuestionImports.POST("", authMiddleware.RequireCSRF(), questionImportHandler.StartImport)
+		}
+
+		// AI question generation into the review queue, with per-admin daily quotas
+		if questionGenerationService != nil {
+			adminQuestionGenerations := api.Group("/admin/question-generations")
+			adminQuestionGenerations.Use(authMiddleware.RequireAuth(), authMiddleware.AdminOnly())
+			{
+				adminQuestionGenerations.GET("", questionGenerationHandler.ListGenerations)
+				adminQuestionGenerations.GET("/:id", middleware.ExtractUintParam("id", "generationID"), questionGenerationHandler.GetGeneration)
+				adminQuestionGenerations.POST("", authMiddleware.RequireCSRF(), questionGenerationHandler.Generate)
+			}
+		}
+
+		// Moderation queue of borderline usernames and profile fields
+		if moderationService != nil {
+			adminModeration := api.Group("/admin/moderation/cases")
+			adminModeration.Use(authMiddleware.RequireAuth(), authMiddleware.AdminOnly())
+			{
+				adminModeration.GET("", moderationHandler.ListCases)
+				adminModeration.POST("/:id/approve", authMiddleware.RequireCSRF(), middleware.ExtractUintParam("id", "caseID"), moderationHandler.ApproveCase)
+				adminModeration.POST("/:id/reject", authMiddleware.RequireCSRF(), middleware.ExtractUintParam("id", "caseID"), moderationHandler.RejectCase)
+			}
+		}
+
+		// Regional prize restrictions: joins from countries outside a quiz's allowed list
+		if geoService != nil {
+			adminGeo := api.Group("/admin/geo")
+			adminGeo.Use(authMiddleware.RequireAuth(), authMiddleware.AdminOnly())
+			{
+				adminGeo.GET("/blocked", geoHandler.ListBlocked)
+				adminGeo.GET("/quizzes/:id", middleware.ExtractUintParam("id", "quizID"), geoHandler.GetQuizReport)
+			}
+		}
+
+		// Question category and tags (admin); pool questions of a quiz's category are asked first
+		adminQuestionTaxonomy := api.Group("/admin/questions/:id/taxonomy")
+		adminQuestionTaxonomy.Use(authMiddleware.RequireAuth(), authMiddleware.AdminOnly(), middleware.ExtractUintParam("id", "questionID"))
+		adminQuestionTaxonomy.Use(authMiddleware.RequireCSRF())
+		{
+			adminQuestionTaxonomy.PUT("", taxonomyHandler.SetQuestionTaxonomy)
+		}
+
+		// Taxonomy management (admin)
+		adminCategories := api.Group("/admin/categories")
+		adminCategories.Use(authMiddleware.RequireAuth(), authMiddleware.AdminOnly())
+		adminCategories.Use(authMiddleware.RequireCSRF())
+		{
+			adminCategories.POST("", taxonomyHandler.CreateCategory)
+			adminCategories.PUT("/:id", middleware.ExtractUintParam("id", "categoryID"), taxonomyHandler.UpdateCategory)
+			adminCategories.DELETE("/:id", middleware.ExtractUintParam("id", "categoryID"), taxonomyHandler.DeleteCategory)
+		}
+		adminTags := api.Group("/admin/tags")
+		adminTags.Use(authMiddleware.RequireAuth(), authMiddleware.AdminOnly())
+		adminTags.Use(authMiddleware.RequireCSRF())
+		{
+			adminTags.POST("", taxonomyHandler.CreateTag)
+			adminTags.PUT("/:id", middleware.ExtractUintParam("id", "tagID"), taxonomyHandler.UpdateTag)
+			adminTags.DELETE("/:id", middleware.ExtractUintParam("id", "tagID"), taxonomyHandler.DeleteTag)
+		}
+
+		// GraphQL-граф данных админ-панели (только чтение)
+		adminGraphQL := api.Group("/admin/graphql")
+		adminGraphQL.Use(authMiddleware.RequireAuth(), authMiddleware.AdminOnly())
+		{
+			adminGraphQL.POST("", adminGraphQLHandler.Query)
+		}
+
 		// РџСѓР» РІРѕРїСЂРѕСЃРѕРІ РґР»СЏ Р°РґР°РїС‚РёРІРЅРѕР№ СЃРёСЃС‚РµРјС‹ (admin)
 		adminQuestionPool := api.Group("/admin/question-pool")
 		adminQuestionPool.Use(authMiddleware.RequireAuth(), authMiddleware.AdminOnly())
@@ -489,40 +1866,109 @@ func main() {
 	// ============================================================================
 	// Mobile Auth Endpoints (Bearer + JSON, Р±РµР· cookies/CSRF)
 	// ============================================================================
-	mobileAuth := api.Group("/mobile/auth")
-	mobileDefaultRateLimit := rateLimiter.Limit(middleware.DefaultAuthRateLimitConfig())
-	{
-		// РџСѓР±Р»РёС‡РЅС‹Рµ СЌРЅРґРїРѕРёРЅС‚С‹ (РЅРµ С‚СЂРµР±СѓСЋС‚ Р°СѓС‚РµРЅС‚РёС„РёРєР°С†РёРё)
-		mobileAuth.POST("/login", rateLimiter.Limit(middleware.StrictAuthRateLimitConfig()), mobileAuthHandler.MobileLogin)
-		mobileAuth.POST("/register", rateLimiter.Limit(middleware.StrictAuthRateLimitConfig()), mobileAuthHandler.MobileRegister)
-		mobileAuth.POST("/refresh", mobileDefaultRateLimit, mobileAuthHandler.MobileRefresh)
-		mobileAuth.POST("/google/exchange", mobileDefaultRateLimit, mobileAuthHandler.MobileGoogleExchange)
-
-		// Logout РЅРµ С‚СЂРµР±СѓРµС‚ RequireAuth вЂ” СЂР°Р±РѕС‚Р°РµС‚ РїРѕ refresh_token РёР· body.
-		// Р­С‚Рѕ РїРѕР·РІРѕР»СЏРµС‚ РІС‹Р№С‚Рё РґР°Р¶Рµ СЃ РїСЂРѕС‚СѓС…С€РёРј access token.
-		mobileAuth.POST("/logout", mobileDefaultRateLimit, mobileAuthHandler.MobileLogout)
-
-		// РўСЂРµР±СѓСЋС‚ Bearer auth, РЅРѕ РќР• CSRF
-		mobileAuthed := mobileAuth.Group("/")
-		mobileAuthed.Use(mobileDefaultRateLimit, authMiddleware.RequireAuth())
+	// The same handlers serve /api/mobile (v1, deprecated) and /api/mobile/v2; handlers shape
+	// version-specific responses via middleware.APIVersionFromContext
+	mobileDefaultRateLimit := rateLimiter.Limit(mobileRateLimitCfg)
+	registerMobileRoutes := func(mobile *gin.RouterGroup) {
+		mobileAuth := mobile.Group("/auth")
 		{
-			mobileAuthed.POST("/ws-ticket", mobileAuthHandler.MobileWsTicket)
-			mobileAuthed.PUT("/profile", mobileAuthHandler.MobileUpdateProfile)
-			mobileAuthed.GET("/sessions", mobileAuthHandler.MobileGetActiveSessions)
-			mobileAuthed.POST("/revoke-session", mobileAuthHandler.MobileRevokeSession)
-			mobileAuthed.POST("/logout-all", mobileAuthHandler.MobileLogoutAllDevices)
-			mobileAuthed.POST("/verify-email/send", mobileAuthHandler.MobileSendEmailVerificationCode)
-			mobileAuthed.POST("/verify-email/confirm", mobileAuthHandler.MobileConfirmEmailVerificationCode)
-			mobileAuthed.GET("/verify-email/status", mobileAuthHandler.MobileGetEmailVerificationStatus)
-			mobileAuthed.POST("/google/link", mobileAuthHandler.MobileGoogleLink)
-			mobileAuthed.DELETE("/me", mobileAuthHandler.MobileDeleteMe)
-		}
-	}
-	mobileUsers := api.Group("/mobile/users")
-	mobileUsers.Use(mobileDefaultRateLimit, authMiddleware.RequireAuth())
-	{
-		mobileUsers.DELETE("/me", mobileAuthHandler.MobileDeleteMe)
+			// РџСѓР±Р»РёС‡РЅС‹Рµ СЌРЅРґРїРѕРёРЅС‚С‹ (РЅРµ С‚СЂРµР±СѓСЋС‚ Р°СѓС‚РµРЅС‚РёС„РёРєР°С†РёРё)
+			mobileAuth.POST("/login", strictRateLimit, mobileAuthHandler.MobileLogin)
+			mobileAuth.POST("/register", strictRateLimit, mobileAuthHandler.MobileRegister)
+			mobileAuth.POST("/refresh", mobileDefaultRateLimit, mobileAuthHandler.MobileRefresh)
+			// Device key rotation works like refresh: refresh_token in body + DPoP proof of the current key
+			mobileAuth.POST("/device-key", mobileDefaultRateLimit, mobileAuthHandler.MobileRotateDeviceKey)
+			mobileAuth.POST("/google/exchange", mobileDefaultRateLimit, mobileAuthHandler.MobileGoogleExchange)
+
+			// Logout РЅРµ С‚СЂРµР±СѓРµС‚ RequireAuth вЂ” СЂР°Р±РѕС‚Р°РµС‚ РїРѕ refresh_token РёР· body.
+			// Р­С‚Рѕ РїРѕР·РІРѕР»СЏРµС‚ РІС‹Р№С‚Рё РґР°Р¶Рµ СЃ РїСЂРѕС‚СѓС…С€РёРј access token.
+			mobileAuth.POST("/logout", mobileDefaultRateLimit, mobileAuthHandler.MobileLogout)
+
+			// РўСЂРµР±СѓСЋС‚ Bearer auth, РЅРѕ РќР• CSRF
+			mobileAuthed := mobileAuth.Group("/")
+			mobileAuthed.Use(mobileDefaultRateLimit, authMiddleware.RequireAuth(), mobileUserRateLimit)
+			{
+				mobileAuthed.POST("/ws-ticket", mobileAuthHandler.MobileWsTicket)
+				mobileAuthed.PUT("/profile", mobileAuthHandler.MobileUpdateProfile)
+				mobileAuthed.GET("/sessions", mobileAuthHandler.MobileGetActiveSessions)
+				mobileAuthed.POST("/revoke-session", mobileAuthHandler.MobileRevokeSession)
+				mobileAuthed.DELETE("/device-keys/:thumbprint", mobileAuthHandler.MobileRevokeDeviceKey)
+				mobileAuthed.POST("/logout-all", mobileAuthHandler.MobileLogoutAllDevices)
+				mobileAuthed.POST("/verify-email/send", mobileAuthHandler.MobileSendEmailVerificationCode)
+				mobileAuthed.POST("/verify-email/confirm", mobileAuthHandler.MobileConfirmEmailVerificationCode)
+				mobileAuthed.GET("/verify-email/status", mobileAuthHandler.MobileGetEmailVerificationStatus)
+				mobileAuthed.POST("/google/link", mobileAuthHandler.MobileGoogleLink)
+				mobileAuthed.DELETE("/me", mobileAuthHandler.MobileDeleteMe)
+				mobileAuthed.GET("/legal", legalHandler.GetLegalStatus)
+				mobileAuthed.POST("/accept-legal", legalHandler.AcceptLegal)
+			}
+		}
+		mobileUsers := mobile.Group("/users")
+		mobileUsers.Use(mobileDefaultRateLimit, authMiddleware.RequireAuth(), mobileUserRateLimit)
+		{
+			mobileUsers.DELETE("/me", mobileAuthHandler.MobileDeleteMe)
+			mobileUsers.POST("/me/avatar", uploadsRateLimit, avatarHandler.UploadAvatar)
+			mobileUsers.DELETE("/me/avatar", avatarHandler.DeleteAvatar)
+			mobileUsers.GET("/:id/profile", userHandler.GetPublicProfile)
+			mobileUsers.PUT("/me/privacy", userHandler.UpdatePrivacy)
+			mobileUsers.GET("/me/rating/history", userHandler.GetMyRatingHistory)
+			mobileUsers.GET("/:id/follow", followHandler.GetFollowStatus)
+			mobileUsers.POST("/:id/follow", followsRateLimit, followHandler.Follow)
+			mobileUsers.DELETE("/:id/follow", followsRateLimit, followHandler.Unfollow)
+			mobileUsers.GET("/me/following", followHandler.ListFollowing)
+			mobileUsers.GET("/me/followers", followHandler.ListFollowers)
+			mobileUsers.GET("/me/friends/leaderboard", followHandler.GetFriendsLeaderboard)
+			mobileUsers.GET("/me/analytics", userAnalyticsHandler.GetMyAnalytics)
+			if referralService != nil {
+				mobileUsers.GET("/me/referral", referralHandler.GetMyReferral)
+			}
+			mobileUsers.GET("/me/notifications", notificationHandler.ListNotifications)
+			mobileUsers.GET("/me/notifications/unread-count", notificationHandler.GetUnreadCount)
+			mobileUsers.POST("/me/notifications/read", notificationHandler.MarkRead)
+			mobileUsers.POST("/me/notifications/read-all", notificationHandler.MarkAllRead)
+			mobileUsers.GET("/me/notifications/preferences", notificationHandler.GetPreferences)
+			mobileUsers.PUT("/me/notifications/preferences", notificationHandler.UpdatePreferences)
+			if walletService != nil {
+				mobileUsers.GET("/me/wallet", walletHandler.GetMyWallet)
+				mobileUsers.GET("/me/wallet/transactions", walletHandler.GetMyTransactions)
+				mobileUsers.GET("/me/wallet/payouts", walletHandler.GetMyPayouts)
+				mobileUsers.POST("/me/wallet/payouts", walletHandler.RequestPayout)
+				mobileUsers.POST("/me/wallet/payouts/:id/cancel", walletHandler.CancelPayout)
+			}
+			if purchaseService != nil {
+				mobileUsers.GET("/me/entitlements", purchaseHandler.GetMyEntitlements)
+				mobileUsers.GET("/me/purchases", purchaseHandler.ListMyPurchases)
+				mobileUsers.POST("/me/purchases", purchaseHandler.VerifyPurchase)
+			}
+		}
+		if dailyChallengeService != nil {
+			mobileDaily := mobile.Group("/daily-challenge")
+			mobileDaily.Use(mobileDefaultRateLimit, authMiddleware.RequireAuth(), mobileUserRateLimit)
+			{
+				mobileDaily.GET("", dailyChallengeHandler.GetToday)
+				mobileDaily.POST("/answer", dailyChallengeHandler.Answer)
+			}
+		}
+		if pushService != nil {
+			mobileNotifications := mobile.Group("/notifications")
+			mobileNotifications.Use(mobileDefaultRateLimit, authMiddleware.RequireAuth(), mobileUserRateLimit)
+			{
+				mobileNotifications.POST("/devices", pushHandler.RegisterDevice)
+				mobileNotifications.GET("/devices", pushHandler.ListDevices)
+				mobileNotifications.DELETE("/devices", pushHandler.UnregisterDevice)
+				mobileNotifications.GET("/preferences", notificationHandler.GetPreferences)
+				mobileNotifications.PUT("/preferences", notificationHandler.UpdatePreferences)
+			}
+		}
 	}
+	v1DeprecatedAt, v1SunsetAt, _ := cfg.MobileAPI.V1Dates() // validated at startup
+	registerMobileRoutes(api.Group("/mobile", middleware.APIVersion(middleware.APIVersionV1, &middleware.APIDeprecation{
+		Since:           v1DeprecatedAt,
+		Sunset:          v1SunsetAt,
+		Prefix:          "/api/mobile/",
+		SuccessorPrefix: "/api/mobile/v2/",
+	}, mobileClientService)))
+	registerMobileRoutes(api.Group("/mobile/v2", middleware.APIVersion(middleware.APIVersionV2, nil, mobileClientService)))
 
 	// WebSocket РјР°СЂС€СЂСѓС‚
 	// Р РµРґР°РєС†РёСЏ ticket РёР· access-Р»РѕРіРѕРІ Gin: ticket вЂ” СЃРµРєСЂРµС‚РЅС‹Рµ РґР°РЅРЅС‹Рµ.
@@ -559,7 +2005,7 @@ func main() {
 		}
 
 		for _, quiz := range scheduledQuizzes {
-			if err := quizManagerService.ScheduleQuiz(quiz.ID, quiz.ScheduledTime); err != nil {
+			if err := quizManagerService.RestoreSchedule(quiz.ID, quiz.ScheduledTime); err != nil {
 				log.Printf("Failed to reschedule quiz %d: %v", quiz.ID, err)
 			}
 		}
@@ -583,6 +2029,20 @@ func main() {
 
 	log.Printf("Server started on port %s", cfg.Server.Port)
 
+	// Internal gRPC API for service-to-service calls, on its own port
+	var grpcServer *grpcapi.Server
+	if cfg.GRPC.Enabled {
+		grpcServer, err = grpcapi.NewServer(cfg.GRPC, jwtService, quizService, quizManagerService, userService)
+		if err != nil {
+			log.Fatalf("Failed to create gRPC server: %v", err)
+		}
+		go func() {
+			if err := grpcServer.Start(); err != nil {
+				log.Printf("gRPC server stopped with error: %v", err)
+			}
+		}()
+	}
+
 	// Р’ РѕР±СЂР°Р±РѕС‚С‡РёРєРµ СЃРёРіРЅР°Р»РѕРІ РѕСЃС‚Р°РЅРѕРІРєРё
 	// РџРѕСЃР»Рµ РїРѕР»СѓС‡РµРЅРёСЏ СЃРёРіРЅР°Р»Р° SIGINT РёР»Рё SIGTERM РІС‹Р·С‹РІР°РµРј cancel() РґР»СЏ Р·Р°РІРµСЂС€РµРЅРёСЏ РіРѕСЂСѓС‚РёРЅ
 	quit := make(chan os.Signal, 1)
@@ -604,10 +2064,27 @@ func main() {
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer shutdownCancel()
 
+	if grpcServer != nil {
+		grpcServer.Stop(shutdownCtx)
+	}
+
+	// Answers arriving after Close are written directly, so the buffer can be drained first
+	if answerBuffer != nil {
+		answerBuffer.Close()
+	}
+
+	if warehouseExporter != nil {
+		warehouseExporter.Close()
+	}
+
 	if err := srv.Shutdown(shutdownCtx); err != nil {
 		log.Printf("Server forced to shutdown: %v", err)
 		os.Exit(1)
 	}
 
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		log.Printf("Error flushing traces: %v", err)
+	}
+
 	log.Println("Server exited properly")
 }
@@ -0,0 +1,412 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// api — HTTP-клиент к мобильным эндпоинтам авторизации
+type api struct {
+	baseURL string
+	http    *http.Client
+}
+
+type apiError struct {
+	status int
+	body   string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("HTTP %d: %s", e.status, e.body)
+}
+
+func (a *api) do(ctx context.Context, method, path, token string, body, dest interface{}) error {
+	var reader *bytes.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, a.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := a.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		return &apiError{status: resp.StatusCode, body: buf.String()}
+	}
+	if dest == nil {
+		return nil
+	}
+	return json.Unmarshal(buf.Bytes(), dest)
+}
+
+// login входит по email и паролю и возвращает access-токен. С register неизвестный
+// пользователь регистрируется через /api/mobile/auth/register.
+func (a *api) login(ctx context.Context, email, password, deviceID string, register bool, index int) (string, error) {
+	var tokens struct {
+		AccessToken string `json:"accessToken"`
+	}
+	err := a.do(ctx, http.MethodPost, "/api/mobile/auth/login", "", map[string]string{
+		"email": email, "password": password, "device_id": deviceID,
+	}, &tokens)
+
+	var apiErr *apiError
+	if err != nil && register && errors.As(err, &apiErr) && apiErr.status == http.StatusUnauthorized {
+		err = a.do(ctx, http.MethodPost, "/api/mobile/auth/register", "", map[string]interface{}{
+			"username":         fmt.Sprintf("loadtest%d", index),
+			"email":            email,
+			"password":         password,
+			"device_id":        deviceID,
+			"first_name":       "Load",
+			"last_name":        fmt.Sprintf("Test %d", index),
+			"birth_date":       "1990-01-01",
+			"gender":           "prefer_not_to_say",
+			"tos_accepted":     true,
+			"privacy_accepted": true,
+		}, &tokens)
+	}
+	if err != nil {
+		return "", err
+	}
+	if tokens.AccessToken == "" {
+		return "", errors.New("empty access token in login response")
+	}
+	return tokens.AccessToken, nil
+}
+
+// wsTicket получает одноразовый тикет для подключения к WebSocket
+func (a *api) wsTicket(ctx context.Context, token string) (string, error) {
+	var resp struct {
+		Data struct {
+			Ticket string `json:"ticket"`
+		} `json:"data"`
+	}
+	if err := a.do(ctx, http.MethodPost, "/api/mobile/auth/ws-ticket", token, nil, &resp); err != nil {
+		return "", err
+	}
+	return resp.Data.Ticket, nil
+}
+
+// answerOracle узнаёт правильные ответы из истории заданных вопросов
+// (GET /api/quizzes/:id/asked-questions, нужен токен администратора). Вопрос попадает
+// в историю до рассылки, поэтому к приходу quiz:question ответ уже известен.
+type answerOracle struct {
+	api    *api
+	token  string
+	quizID uint
+
+	mu      sync.Mutex
+	correct map[uint]int
+	fetches map[uint]chan struct{} // загрузка истории, ожидаемая клиентами
+}
+
+func newAnswerOracle(a *api, token string, quizID uint) *answerOracle {
+	return &answerOracle{api: a, token: token, quizID: quizID, correct: make(map[uint]int), fetches: make(map[uint]chan struct{})}
+}
+
+// correctOption возвращает правильный вариант вопроса. Историю загружает один клиент,
+// остальные ждут её загрузки.
+func (o *answerOracle) correctOption(ctx context.Context, questionID uint) (int, bool) {
+	if o == nil {
+		return 0, false
+	}
+	o.mu.Lock()
+	if option, ok := o.correct[questionID]; ok {
+		o.mu.Unlock()
+		return option, true
+	}
+	done, loading := o.fetches[questionID]
+	if !loading {
+		done = make(chan struct{})
+		o.fetches[questionID] = done
+	}
+	o.mu.Unlock()
+
+	if loading {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return 0, false
+		}
+	} else {
+		o.fetch(ctx)
+		close(done)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	option, ok := o.correct[questionID]
+	return option, ok
+}
+
+func (o *answerOracle) fetch(ctx context.Context) {
+	var asked []struct {
+		Question struct {
+			ID            uint `json:"id"`
+			CorrectOption int  `json:"correct_option"`
+		} `json:"question"`
+	}
+	path := fmt.Sprintf("/api/quizzes/%d/asked-questions", o.quizID)
+	if err := o.api.do(ctx, http.MethodGet, path, o.token, nil, &asked); err != nil {
+		return
+	}
+	o.mu.Lock()
+	for _, item := range asked {
+		o.correct[item.Question.ID] = item.Question.CorrectOption
+	}
+	o.mu.Unlock()
+}
+
+// behavior — распределения поведения синтетических игроков
+type behavior struct {
+	answerRate    float64       // доля вопросов, на которые игрок отвечает
+	accuracy      float64       // вероятность выбрать правильный вариант (если он известен)
+	latencyMean   time.Duration // среднее время ответа
+	latencyStdDev time.Duration // разброс времени ответа (нормальное распределение)
+}
+
+// answerDelay возвращает время «раздумья» игрока, не больше лимита вопроса
+func (b behavior) answerDelay(rng *rand.Rand, limit time.Duration) time.Duration {
+	delay := time.Duration(rng.NormFloat64()*float64(b.latencyStdDev)) + b.latencyMean
+	delay = time.Duration(math.Max(float64(delay), float64(50*time.Millisecond)))
+	if limit > 0 && delay > limit {
+		delay = limit
+	}
+	return delay
+}
+
+// chooseOption выбирает вариант ответа с учётом accuracy
+func (b behavior) chooseOption(rng *rand.Rand, options int, correct int, known bool) int {
+	if options <= 0 {
+		return 0
+	}
+	if !known {
+		return rng.Intn(options)
+	}
+	if rng.Float64() < b.accuracy || options == 1 {
+		return correct
+	}
+	wrong := rng.Intn(options - 1)
+	if wrong >= correct {
+		wrong++
+	}
+	return wrong
+}
+
+// serverEvent — сообщение сервера: {"type": ..., "data": ..., "seq": ...}
+type serverEvent struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+	Seq  int64           `json:"seq"`
+}
+
+// syntheticClient — один игрок: вход, тикет, WebSocket, готовность и ответы на вопросы
+type syntheticClient struct {
+	index    int
+	quizID   uint
+	wsURL    string
+	api      *api
+	oracle   *answerOracle
+	behavior behavior
+	stats    *stats
+	rng      *rand.Rand
+
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+	lastSeq int64
+
+	answersMu sync.Mutex
+	pending   map[uint]time.Time // question_id → время отправки ответа
+}
+
+// connect проходит реальный поток авторизации и подключается к WebSocket
+func (c *syntheticClient) connect(ctx context.Context, email, password string, register bool) error {
+	started := time.Now()
+	deviceID := fmt.Sprintf("loadtest-%d", c.index)
+
+	token, err := c.api.login(ctx, email, password, deviceID, register, c.index)
+	if err != nil {
+		return fmt.Errorf("login %s: %w", email, err)
+	}
+	ticket, err := c.api.wsTicket(ctx, token)
+	if err != nil {
+		return fmt.Errorf("ws ticket %s: %w", email, err)
+	}
+
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, c.wsURL+"?ticket="+url.QueryEscape(ticket), nil)
+	if err != nil {
+		return fmt.Errorf("dial %s: %w", email, err)
+	}
+	c.conn = conn
+	c.stats.connect.add(time.Since(started))
+	return c.send("user:ready", map[string]interface{}{"quiz_id": c.quizID})
+}
+
+func (c *syntheticClient) send(eventType string, data interface{}) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_ = c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	return c.conn.WriteJSON(map[string]interface{}{"type": eventType, "data": data})
+}
+
+// run читает события до quiz:finish, закрытия соединения или отмены ctx.
+// Возвращает true, если викторина дошла до конца.
+func (c *syntheticClient) run(ctx context.Context) bool {
+	go func() {
+		<-ctx.Done()
+		c.writeMu.Lock()
+		_ = c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+		c.writeMu.Unlock()
+		_ = c.conn.Close()
+	}()
+
+	for {
+		_, message, err := c.conn.ReadMessage()
+		if err != nil {
+			return false
+		}
+		received := time.Now()
+		// Сервер может отправить несколько событий одним кадром, разделяя их переводом строки
+		for _, frame := range bytes.Split(message, []byte{'\n'}) {
+			if len(bytes.TrimSpace(frame)) == 0 {
+				continue
+			}
+			if finished := c.handle(ctx, frame, received); finished {
+				return true
+			}
+		}
+	}
+}
+
+func (c *syntheticClient) handle(ctx context.Context, frame []byte, received time.Time) bool {
+	var event serverEvent
+	if err := json.Unmarshal(frame, &event); err != nil {
+		c.stats.countMessage("invalid_json", len(frame))
+		return false
+	}
+	c.stats.countMessage(event.Type, len(frame))
+
+	if event.Seq > 0 {
+		if c.lastSeq > 0 && event.Seq > c.lastSeq+1 {
+			c.stats.seqGaps.Add(event.Seq - c.lastSeq - 1)
+		}
+		if event.Seq > c.lastSeq {
+			c.lastSeq = event.Seq
+		}
+	}
+
+	var payload struct {
+		ServerTimestamp int64 `json:"server_timestamp"`
+	}
+	if json.Unmarshal(event.Data, &payload) == nil && payload.ServerTimestamp > 0 {
+		c.stats.delivery.add(received.Sub(time.UnixMilli(payload.ServerTimestamp)))
+	}
+
+	switch event.Type {
+	case "quiz:question":
+		c.onQuestion(ctx, event.Data)
+	case "quiz:answer_result":
+		var result struct {
+			QuestionID   uint `json:"question_id"`
+			IsEliminated bool `json:"is_eliminated"`
+		}
+		if json.Unmarshal(event.Data, &result) == nil {
+			c.stats.answerResults.Add(1)
+			c.answersMu.Lock()
+			if sentAt, ok := c.pending[result.QuestionID]; ok {
+				c.stats.answer.add(received.Sub(sentAt))
+				delete(c.pending, result.QuestionID)
+			}
+			c.answersMu.Unlock()
+		}
+	case "quiz:elimination":
+		c.stats.eliminated.Add(1)
+	case "server:buffer_warning":
+		var warning struct {
+			Dropped int64 `json:"dropped_messages"`
+		}
+		if json.Unmarshal(event.Data, &warning) == nil {
+			c.stats.droppedWarning.Add(warning.Dropped)
+		}
+	case "server:error":
+		c.stats.serverErrors.Add(1)
+	case "quiz:finish":
+		return true
+	}
+	return false
+}
+
+// onQuestion планирует ответ на вопрос по распределениям поведения
+func (c *syntheticClient) onQuestion(ctx context.Context, data json.RawMessage) {
+	var question struct {
+		QuestionID uint `json:"question_id"`
+		TimeLimit  int  `json:"time_limit"`
+		Options    []struct {
+			ID int `json:"id"`
+		} `json:"options"`
+	}
+	if err := json.Unmarshal(data, &question); err != nil {
+		return
+	}
+	if c.rng.Float64() >= c.behavior.answerRate {
+		return
+	}
+	delay := c.behavior.answerDelay(c.rng, time.Duration(question.TimeLimit)*time.Second)
+	optionsCount := len(question.Options)
+	rng := rand.New(rand.NewSource(c.rng.Int63()))
+
+	go func() {
+		correct, known := c.oracle.correctOption(ctx, question.QuestionID)
+		option := c.behavior.chooseOption(rng, optionsCount, correct, known)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		sentAt := time.Now()
+		c.answersMu.Lock()
+		c.pending[question.QuestionID] = sentAt
+		c.answersMu.Unlock()
+		err := c.send("user:answer", map[string]interface{}{
+			"question_id":     question.QuestionID,
+			"selected_option": option,
+			"timestamp":       sentAt.UnixMilli(),
+		})
+		if err == nil {
+			c.stats.answersSent.Add(1)
+		}
+	}()
+}
@@ -0,0 +1,146 @@
+// Команда loadtest — нагрузочный тест WebSocket-хаба перед крупными викторинами.
+//
+// Поднимает N синтетических игроков: каждый входит через /api/mobile/auth/login, получает
+// тикет /api/mobile/auth/ws-ticket, подключается к /ws, отправляет user:ready и отвечает
+// на вопросы с заданным распределением задержки и точности. По окончании викторины
+// (quiz:finish), по -duration или по Ctrl+C печатает пропускную способность хаба,
+// потерянные сообщения и перцентили задержки доставки.
+//
+// Пример:
+//
+//	go run ./cmd/loadtest -base-url https://staging.example.com -quiz-id 42 -clients 2000 \
+//	    -email-pattern 'loadtest+%d@example.com' -password secret -register -ramp 60s
+//
+// Задержка доставки считается по server_timestamp событий, поэтому часы машины
+// с тестом должны быть синхронизированы с сервером (NTP).
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+func main() {
+	var (
+		baseURL      = flag.String("base-url", "http://localhost:8080", "адрес API")
+		wsURL        = flag.String("ws-url", "", "адрес WebSocket (по умолчанию base-url + /ws)")
+		quizID       = flag.Uint("quiz-id", 0, "ID запланированной викторины")
+		clients      = flag.Int("clients", 100, "число синтетических игроков")
+		ramp         = flag.Duration("ramp", 10*time.Second, "за сколько подключить всех игроков")
+		duration     = flag.Duration("duration", time.Hour, "максимальная длительность теста")
+		emailPattern = flag.String("email-pattern", "loadtest+%d@example.com", "шаблон email игроков (%d — номер)")
+		password     = flag.String("password", "", "пароль игроков")
+		startIndex   = flag.Int("start-index", 1, "номер первого игрока")
+		register     = flag.Bool("register", false, "регистрировать игроков, которые не могут войти")
+		adminToken   = flag.String("admin-token", "", "access-токен администратора для выбора правильных ответов")
+		answerRate   = flag.Float64("answer-rate", 1, "доля вопросов, на которые игрок отвечает (0..1)")
+		accuracy     = flag.Float64("accuracy", 0.9, "вероятность правильного ответа (0..1), нужен -admin-token")
+		latencyMean  = flag.Duration("latency-mean", 3*time.Second, "среднее время ответа")
+		latencyDev   = flag.Duration("latency-stddev", time.Second, "разброс времени ответа")
+		jsonOutput   = flag.Bool("json", false, "вывести отчёт в JSON")
+		seed         = flag.Int64("seed", time.Now().UnixNano(), "seed генератора случайных чисел")
+	)
+	flag.Parse()
+
+	if *quizID == 0 || *password == "" || *clients < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *answerRate < 0 || *answerRate > 1 || *accuracy < 0 || *accuracy > 1 {
+		log.Fatal("answer-rate and accuracy must be between 0 and 1")
+	}
+	if *wsURL == "" {
+		*wsURL = strings.Replace(strings.TrimRight(*baseURL, "/"), "http", "ws", 1) + "/ws"
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	ctx, cancelTimeout := context.WithTimeout(ctx, *duration)
+	defer cancelTimeout()
+
+	client := &api{
+		baseURL: strings.TrimRight(*baseURL, "/"),
+		http: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: &http.Transport{MaxIdleConnsPerHost: 100},
+		},
+	}
+	var oracle *answerOracle
+	if *adminToken != "" {
+		oracle = newAnswerOracle(client, *adminToken, uint(*quizID))
+	} else {
+		log.Printf("[loadtest] -admin-token не задан: игроки выбирают ответы случайно, -accuracy не учитывается")
+	}
+
+	b := behavior{answerRate: *answerRate, accuracy: *accuracy, latencyMean: *latencyMean, latencyStdDev: *latencyDev}
+	st := newStats()
+	rng := rand.New(rand.NewSource(*seed))
+
+	var interval time.Duration
+	if *clients > 1 {
+		interval = *ramp / time.Duration(*clients-1)
+	}
+
+	log.Printf("[loadtest] %d игроков → викторина #%d (%s), подключение за %s", *clients, *quizID, *wsURL, *ramp)
+	started := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < *clients; i++ {
+		if i > 0 && interval > 0 {
+			select {
+			case <-ctx.Done():
+			case <-time.After(interval):
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		index := *startIndex + i
+		c := &syntheticClient{
+			index:    index,
+			quizID:   uint(*quizID),
+			wsURL:    *wsURL,
+			api:      client,
+			oracle:   oracle,
+			behavior: b,
+			stats:    st,
+			rng:      rand.New(rand.NewSource(rng.Int63())),
+			pending:  make(map[uint]time.Time),
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.connect(ctx, fmt.Sprintf(*emailPattern, index), *password, *register); err != nil {
+				st.connectFailed.Add(1)
+				log.Printf("[loadtest] игрок %d: %v", index, err)
+				return
+			}
+			st.connected.Add(1)
+			if c.run(ctx) {
+				st.finished.Add(1)
+			} else if ctx.Err() == nil {
+				st.disconnected.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	r := st.report(*clients, time.Since(started))
+	if *jsonOutput {
+		if err := r.writeJSON(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	r.writeText(os.Stdout)
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// latencySamples накапливает замеры задержки для расчёта перцентилей
+type latencySamples struct {
+	mu      sync.Mutex
+	samples []time.Duration
+}
+
+func (l *latencySamples) add(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	l.mu.Lock()
+	l.samples = append(l.samples, d)
+	l.mu.Unlock()
+}
+
+// summary возвращает число замеров и перцентили p50/p95/p99/max
+func (l *latencySamples) summary() latencySummary {
+	l.mu.Lock()
+	sorted := append([]time.Duration(nil), l.samples...)
+	l.mu.Unlock()
+
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return latencySummary{
+		Count: len(sorted),
+		P50Ms: percentile(sorted, 0.50).Milliseconds(),
+		P95Ms: percentile(sorted, 0.95).Milliseconds(),
+		P99Ms: percentile(sorted, 0.99).Milliseconds(),
+		MaxMs: percentile(sorted, 1).Milliseconds(),
+	}
+}
+
+// percentile возвращает перцентиль p (0..1) отсортированной выборки методом ближайшего ранга
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(p*float64(len(sorted))+0.999999) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
+type latencySummary struct {
+	Count int   `json:"count"`
+	P50Ms int64 `json:"p50_ms"`
+	P95Ms int64 `json:"p95_ms"`
+	P99Ms int64 `json:"p99_ms"`
+	MaxMs int64 `json:"max_ms"`
+}
+
+// stats — общие счётчики всех синтетических клиентов
+type stats struct {
+	connected      atomic.Int64
+	connectFailed  atomic.Int64
+	disconnected   atomic.Int64 // соединения, закрытые до конца викторины
+	finished       atomic.Int64 // клиенты, получившие quiz:finish
+	messages       atomic.Int64
+	bytes          atomic.Int64
+	droppedWarning atomic.Int64 // dropped_messages из server:buffer_warning
+	seqGaps        atomic.Int64 // пропуски в seq событий викторины
+	answersSent    atomic.Int64
+	answerResults  atomic.Int64
+	eliminated     atomic.Int64
+	serverErrors   atomic.Int64
+
+	mu     sync.Mutex
+	byType map[string]int64
+
+	connect  latencySamples // вход + тикет + подключение WebSocket
+	delivery latencySamples // server_timestamp события → получение клиентом
+	answer   latencySamples // user:answer → quiz:answer_result
+}
+
+func newStats() *stats {
+	return &stats{byType: make(map[string]int64)}
+}
+
+func (s *stats) countMessage(eventType string, size int) {
+	s.messages.Add(1)
+	s.bytes.Add(int64(size))
+	s.mu.Lock()
+	s.byType[eventType]++
+	s.mu.Unlock()
+}
+
+// report — итог прогона нагрузочного теста
+type report struct {
+	Clients        int              `json:"clients"`
+	Connected      int64            `json:"connected"`
+	ConnectFailed  int64            `json:"connect_failed"`
+	Disconnected   int64            `json:"disconnected"`
+	Finished       int64            `json:"finished"`
+	DurationSec    float64          `json:"duration_sec"`
+	Messages       int64            `json:"messages"`
+	Bytes          int64            `json:"bytes"`
+	MessagesPerSec float64          `json:"messages_per_sec"`
+	Dropped        int64            `json:"dropped_messages"`
+	SeqGaps        int64            `json:"seq_gaps"`
+	AnswersSent    int64            `json:"answers_sent"`
+	AnswerResults  int64            `json:"answer_results"`
+	Eliminated     int64            `json:"eliminated"`
+	ServerErrors   int64            `json:"server_errors"`
+	ByType         map[string]int64 `json:"messages_by_type"`
+	Connect        latencySummary   `json:"connect_latency"`
+	Delivery       latencySummary   `json:"delivery_latency"`
+	Answer         latencySummary   `json:"answer_latency"`
+}
+
+func (s *stats) report(clients int, elapsed time.Duration) report {
+	s.mu.Lock()
+	byType := make(map[string]int64, len(s.byType))
+	for eventType, count := range s.byType {
+		byType[eventType] = count
+	}
+	s.mu.Unlock()
+
+	r := report{
+		Clients:       clients,
+		Connected:     s.connected.Load(),
+		ConnectFailed: s.connectFailed.Load(),
+		Disconnected:  s.disconnected.Load(),
+		Finished:      s.finished.Load(),
+		DurationSec:   elapsed.Seconds(),
+		Messages:      s.messages.Load(),
+		Bytes:         s.bytes.Load(),
+		Dropped:       s.droppedWarning.Load(),
+		SeqGaps:       s.seqGaps.Load(),
+		AnswersSent:   s.answersSent.Load(),
+		AnswerResults: s.answerResults.Load(),
+		Eliminated:    s.eliminated.Load(),
+		ServerErrors:  s.serverErrors.Load(),
+		ByType:        byType,
+		Connect:       s.connect.summary(),
+		Delivery:      s.delivery.summary(),
+		Answer:        s.answer.summary(),
+	}
+	if elapsed > 0 {
+		r.MessagesPerSec = float64(r.Messages) / elapsed.Seconds()
+	}
+	return r
+}
+
+func (r report) writeJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+func (r report) writeText(w io.Writer) {
+	fmt.Fprintf(w, "Клиенты:          %d (подключено %d, ошибок подключения %d, обрывов %d, дошли до конца %d)\n",
+		r.Clients, r.Connected, r.ConnectFailed, r.Disconnected, r.Finished)
+	fmt.Fprintf(w, "Длительность:     %.1f с\n", r.DurationSec)
+	fmt.Fprintf(w, "Сообщений:        %d (%.0f/с, %.1f МБ)\n", r.Messages, r.MessagesPerSec, float64(r.Bytes)/(1<<20))
+	fmt.Fprintf(w, "Потеряно:         %d по server:buffer_warning, %d пропусков seq\n", r.Dropped, r.SeqGaps)
+	fmt.Fprintf(w, "Ответы:           отправлено %d, результатов %d, выбыло %d, ошибок сервера %d\n",
+		r.AnswersSent, r.AnswerResults, r.Eliminated, r.ServerErrors)
+	writeLatency(w, "Подключение", r.Connect)
+	writeLatency(w, "Доставка", r.Delivery)
+	writeLatency(w, "Ответ", r.Answer)
+
+	types := make([]string, 0, len(r.ByType))
+	for eventType := range r.ByType {
+		types = append(types, eventType)
+	}
+	sort.Strings(types)
+	fmt.Fprintln(w, "По типам:")
+	for _, eventType := range types {
+		fmt.Fprintf(w, "  %-28s %d\n", eventType, r.ByType[eventType])
+	}
+}
+
+func writeLatency(w io.Writer, name string, l latencySummary) {
+	fmt.Fprintf(w, "%-17s n=%d p50=%dмс p95=%dмс p99=%dмс max=%dмс\n", name+":", l.Count, l.P50Ms, l.P95Ms, l.P99Ms, l.MaxMs)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatencySummary(t *testing.T) {
+	var samples latencySamples
+	for i := 100; i >= 1; i-- {
+		samples.add(time.Duration(i) * time.Millisecond)
+	}
+
+	summary := samples.summary()
+	assert.Equal(t, 100, summary.Count)
+	assert.Equal(t, int64(50), summary.P50Ms)
+	assert.Equal(t, int64(99), summary.P99Ms)
+	assert.Equal(t, int64(100), summary.MaxMs)
+
+	var empty latencySamples
+	assert.Equal(t, latencySummary{}, empty.summary())
+}
+
+func TestBehavior_ChooseOption(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	perfect := behavior{accuracy: 1}
+	wrong := behavior{accuracy: 0}
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, 2, perfect.chooseOption(rng, 4, 2, true))
+		option := wrong.chooseOption(rng, 4, 2, true)
+		assert.NotEqual(t, 2, option)
+		assert.True(t, option >= 0 && option < 4)
+	}
+}
+
+func TestBehavior_AnswerDelayWithinLimit(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	b := behavior{latencyMean: 8 * time.Second, latencyStdDev: 5 * time.Second}
+	for i := 0; i < 100; i++ {
+		delay := b.answerDelay(rng, 10*time.Second)
+		assert.True(t, delay >= 50*time.Millisecond && delay <= 10*time.Second, delay)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang-migrate/migrate/v4"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/pkg/database"
+)
+
+// runDoctor проверяет, что миграции применены и не dirty, а таблицы и колонки моделей есть в БД.
+// Возвращает ошибку, если найдена хотя бы одна проблема, чтобы команду можно было вызывать в CI и перед релизом.
+func runDoctor(dir string) error {
+	db, err := connect()
+	if err != nil {
+		return err
+	}
+	problems := 0
+
+	migrations, err := localMigrations(dir)
+	if err != nil {
+		return err
+	}
+	m, err := database.NewMigrator(db, dir)
+	if err != nil {
+		return err
+	}
+	version, dirty, err := m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		version, err = 0, nil
+	}
+	if err != nil {
+		return err
+	}
+	latest := uint(0)
+	if len(migrations) > 0 {
+		latest = migrations[len(migrations)-1].Version
+	}
+	fmt.Printf("migrations: database at %d, files up to %d\n", version, latest)
+	if dirty {
+		problems++
+		fmt.Printf("  ✗ version %d is dirty: fix the schema by hand, then run migrate force\n", version)
+	}
+	if pending := pendingMigrations(migrations, version); len(pending) > 0 {
+		problems++
+		fmt.Printf("  ✗ %d pending migrations: run migrate up\n", len(pending))
+	}
+	if version > latest {
+		problems++
+		fmt.Printf("  ✗ database is ahead of the migration files: the API build is older than the schema\n")
+	}
+	for _, mf := range migrations {
+		if !mf.HasUp || !mf.HasDown {
+			problems++
+			fmt.Printf("  ✗ %06d_%s has no up or down file\n", mf.Version, mf.Name)
+		}
+	}
+
+	models := entity.PersistentModels()
+	issues, err := database.CheckSchema(db, models)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("schema: %d tables checked against models\n", len(models))
+	for _, issue := range issues {
+		fmt.Printf("  ✗ %s\n", issue)
+	}
+	problems += len(issues)
+
+	if problems > 0 {
+		return fmt.Errorf("%d problems found", problems)
+	}
+	fmt.Println("ok")
+	return nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/yourusername/trivia-api/internal/pkg/envelope"
+	pgRepo "github.com/yourusername/trivia-api/internal/repository/postgres"
+)
+
+// runJWTKeys обслуживает ключи подписи JWT в БД.
+//
+// reencrypt перешифровывает ключи данных текущим мастер-ключом (jwt.db_jwt_key_encryption_key).
+// Смена мастер-ключа без простоя: выкатить API с новым ключом, увеличенной версией
+// (db_jwt_key_encryption_key_version) и прежним ключом в db_jwt_key_previous_encryption_key,
+// выполнить reencrypt, затем убрать прежний ключ из конфигурации.
+func runJWTKeys(args []string) error {
+	if len(args) == 0 || args[0] != "reencrypt" {
+		return errors.New("expected reencrypt")
+	}
+	fs := flag.NewFlagSet("jwt-keys reencrypt", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "только показать, сколько ключей будет перешифровано")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	cfg, db, err := connectWithConfig()
+	if err != nil {
+		return err
+	}
+	keyring, err := envelope.NewKeyring(cfg.JWT.DBJWTKeyEncryptionKeyVersion, cfg.JWT.DBKeyEncryptionKeys())
+	if err != nil {
+		return err
+	}
+	repo, err := pgRepo.NewPostgresJWTKeyRepository(db, keyring)
+	if err != nil {
+		return err
+	}
+
+	result, err := repo.ReencryptKeys(context.Background(), *dryRun)
+	if err != nil {
+		return err
+	}
+	verb := "re-encrypted"
+	if *dryRun {
+		verb = "would re-encrypt"
+	}
+	fmt.Printf("jwt keys: %d total, %s %d with master key version %d", result.Total, verb, result.Reencrypted, keyring.CurrentVersion())
+	if result.Skipped > 0 {
+		fmt.Printf(", %d changed concurrently (run again)", result.Skipped)
+	}
+	fmt.Println()
+	return nil
+}
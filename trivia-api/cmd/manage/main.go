@@ -0,0 +1,105 @@
+// Команда manage — обслуживание базы данных API: миграции, демонстрационные данные, проверка схемы
+// и перешифрование ключей подписи JWT.
+// Читает ту же конфигурацию, что и API (CONFIG_PATH, по умолчанию config/config.yaml, переменные
+// окружения и провайдер секретов), и те же миграции (-migrations, по умолчанию ./migrations).
+//
+// Команды:
+//
+//	manage migrate up               применить все новые миграции
+//	manage migrate down [N]         откатить N последних миграций (по умолчанию 1)
+//	manage migrate status           текущая версия, dirty и ожидающие миграции
+//	manage migrate force V          записать версию V и снять dirty после ручного исправления
+//	manage migrate new NAME         создать пару файлов NNNNNN_NAME.up.sql и .down.sql
+//	manage seed all                 все демо-данные ниже по порядку (флаги подкоманд тоже принимаются)
+//	manage seed users [-count N] [-password P]   демо-игроки demo_player_N и администратор demo_admin
+//	manage seed questions           пул вопросов по темам
+//	manage seed quiz [-in D] [-questions N]      запланированная викторина со своими вопросами
+//	manage seed ads                 рекламные ресурсы
+//	manage seed history [-days N]   сыгранные викторины за прошедшие дни с результатами демо-игроков
+//	manage doctor                   проверка миграций и расхождений схемы с моделями
+//	manage jwt-keys reencrypt [-dry-run]         перешифровать ключи JWT текущим мастер-ключом
+//
+// Повторный seed не дублирует данные (см. пакет internal/seed). Демо-данные создаются с известным
+// паролем, поэтому seed отказывается работать при GIN_MODE=release без флага -force.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"gorm.io/gorm"
+
+	"github.com/yourusername/trivia-api/internal/config"
+	"github.com/yourusername/trivia-api/pkg/database"
+)
+
+const usage = `usage: manage [-migrations DIR] <command> [args]
+
+commands:
+  migrate up | down [N] | status | force VERSION | new NAME
+  seed all [flags of the commands below]
+  seed users [-count N] [-password P] [-force]
+  seed questions [-force]
+  seed quiz [-in DURATION] [-questions N] [-force]
+  seed ads [-force]
+  seed history [-days N] [-force]
+  doctor
+  jwt-keys reencrypt [-dry-run]
+`
+
+func main() {
+	log.SetFlags(0)
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	migrationsDir := flag.String("migrations", "migrations", "каталог SQL-миграций")
+	flag.Parse()
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch args[0] {
+	case "migrate":
+		err = runMigrate(*migrationsDir, args[1:])
+	case "seed":
+		err = runSeed(args[1:])
+	case "doctor":
+		err = runDoctor(*migrationsDir)
+	case "jwt-keys":
+		err = runJWTKeys(args[1:])
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatalf("manage %s: %v", args[0], err)
+	}
+}
+
+// connect загружает конфигурацию API и подключается к БД
+func connect() (*gorm.DB, error) {
+	_, db, err := connectWithConfig()
+	return db, err
+}
+
+// connectWithConfig загружает конфигурацию API, подключается к БД и возвращает обе
+func connectWithConfig() (*config.Config, *gorm.DB, error) {
+	configPath := os.Getenv("CONFIG_PATH")
+	if configPath == "" {
+		configPath = "config/config.yaml"
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("load config: %w", err)
+	}
+	// SQL-лог GORM только мешает выводу команд
+	cfg.Database.LogLevel = "warn"
+	db, err := database.NewPostgresDB(cfg.Database)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, db, nil
+}
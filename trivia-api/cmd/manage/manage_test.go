@@ -0,0 +1,58 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("SELECT 1;"), 0o644))
+	}
+}
+
+func TestLocalMigrations(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir,
+		"000010_quiz_rsvp.up.sql", "000010_quiz_rsvp.down.sql",
+		"000002_users.up.sql", "000002_users.down.sql",
+		"000011_broken.up.sql",
+		"README.md",
+	)
+
+	migrations, err := localMigrations(dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, []migrationFile{
+		{Version: 2, Name: "users", HasUp: true, HasDown: true},
+		{Version: 10, Name: "quiz_rsvp", HasUp: true, HasDown: true},
+		{Version: 11, Name: "broken", HasUp: true},
+	}, migrations)
+	assert.Len(t, pendingMigrations(migrations, 2), 2)
+	assert.Empty(t, pendingMigrations(migrations, 11))
+}
+
+func TestCreateMigration(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, "000070_export_jobs.up.sql", "000070_export_jobs.down.sql")
+
+	paths, err := createMigration(dir, "user_streaks")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "000071_user_streaks.up.sql"),
+		filepath.Join(dir, "000071_user_streaks.down.sql"),
+	}, paths)
+
+	_, err = createMigration(dir, "User Streaks")
+	assert.Error(t, err, "имя только из строчных латинских букв, цифр и _")
+
+	empty := t.TempDir()
+	paths, err = createMigration(empty, "init")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(empty, "000001_init.up.sql"), paths[0])
+}
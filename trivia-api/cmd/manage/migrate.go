@@ -0,0 +1,188 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/golang-migrate/migrate/v4"
+
+	"github.com/yourusername/trivia-api/pkg/database"
+)
+
+var (
+	migrationFilePattern = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+	migrationNamePattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+)
+
+// migrationFile — миграция в каталоге: версия, имя и наличие файлов up и down
+type migrationFile struct {
+	Version uint
+	Name    string
+	HasUp   bool
+	HasDown bool
+}
+
+// localMigrations возвращает миграции каталога dir по возрастанию версии
+func localMigrations(dir string) ([]migrationFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	byVersion := make(map[uint]*migrationFile)
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", entry.Name(), err)
+		}
+		m := byVersion[uint(version)]
+		if m == nil {
+			m = &migrationFile{Version: uint(version), Name: match[2]}
+			byVersion[uint(version)] = m
+		}
+		if match[3] == "up" {
+			m.HasUp = true
+		} else {
+			m.HasDown = true
+		}
+	}
+	migrations := make([]migrationFile, 0, len(byVersion))
+	for _, m := range byVersion {
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// createMigration создаёт пустые файлы следующей по номеру миграции и возвращает их пути
+func createMigration(dir, name string) ([]string, error) {
+	if !migrationNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("migration name must match %s, got %q", migrationNamePattern, name)
+	}
+	migrations, err := localMigrations(dir)
+	if err != nil {
+		return nil, err
+	}
+	next := uint(1)
+	if len(migrations) > 0 {
+		next = migrations[len(migrations)-1].Version + 1
+	}
+	var paths []string
+	for _, direction := range []string{"up", "down"} {
+		path := filepath.Join(dir, fmt.Sprintf("%06d_%s.%s.sql", next, name, direction))
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return paths, err
+		}
+		f.Close()
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// pendingMigrations возвращает миграции новее applied
+func pendingMigrations(migrations []migrationFile, applied uint) []migrationFile {
+	var pending []migrationFile
+	for _, m := range migrations {
+		if m.Version > applied {
+			pending = append(pending, m)
+		}
+	}
+	return pending
+}
+
+func runMigrate(dir string, args []string) error {
+	if len(args) == 0 {
+		return errors.New("expected up, down, status, force or new")
+	}
+	if args[0] == "new" {
+		if len(args) != 2 {
+			return errors.New("usage: migrate new NAME")
+		}
+		paths, err := createMigration(dir, args[1])
+		for _, path := range paths {
+			fmt.Println("created", path)
+		}
+		return err
+	}
+
+	db, err := connect()
+	if err != nil {
+		return err
+	}
+	m, err := database.NewMigrator(db, dir)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		err = m.Up()
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+		}
+		err = m.Steps(-steps)
+	case "force":
+		if len(args) != 2 {
+			return errors.New("usage: migrate force VERSION")
+		}
+		version, errParse := strconv.Atoi(args[1])
+		if errParse != nil || version < -1 {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		err = m.Force(version)
+	case "status":
+		return printMigrationStatus(m, dir)
+	default:
+		return fmt.Errorf("unknown migrate command %q", args[0])
+	}
+	if errors.Is(err, migrate.ErrNoChange) {
+		fmt.Println("no change")
+		err = nil
+	}
+	if err != nil {
+		return err
+	}
+	return printMigrationStatus(m, dir)
+}
+
+func printMigrationStatus(m *migrate.Migrate, dir string) error {
+	migrations, err := localMigrations(dir)
+	if err != nil {
+		return err
+	}
+	version, dirty, err := m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		version, err = 0, nil
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("version: %d", version)
+	if dirty {
+		fmt.Print(" (dirty: fix the schema by hand, then run migrate force)")
+	}
+	fmt.Println()
+	pending := pendingMigrations(migrations, version)
+	if len(pending) == 0 {
+		fmt.Println("pending: none")
+		return nil
+	}
+	fmt.Printf("pending: %d\n", len(pending))
+	for _, p := range pending {
+		fmt.Printf("  %06d_%s\n", p.Version, p.Name)
+	}
+	return nil
+}
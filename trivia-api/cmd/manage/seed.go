@@ -0,0 +1,62 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/seed"
+)
+
+func runSeed(args []string) error {
+	if len(args) == 0 {
+		return errors.New("expected all, users, questions, quiz, ads or history")
+	}
+	defaults := seed.DefaultOptions()
+	fs := flag.NewFlagSet("seed "+args[0], flag.ExitOnError)
+	force := fs.Bool("force", false, "разрешить запуск при GIN_MODE=release")
+	count := fs.Int("count", defaults.Players, "users: число демо-игроков")
+	password := fs.String("password", defaults.Password, "users: пароль демо-аккаунтов")
+	startIn := fs.Duration("in", defaults.QuizIn, "quiz: через сколько начнётся викторина")
+	questionCount := fs.Int("questions", defaults.QuizQuestions, "quiz: число вопросов")
+	days := fs.Int("days", defaults.HistoryQuizzes, "history: число сыгранных викторин")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if os.Getenv("GIN_MODE") == "release" && !*force {
+		return errors.New("refusing to seed demo data with GIN_MODE=release; pass -force if this is intended")
+	}
+	opts := seed.Options{
+		Players:        *count,
+		Password:       *password,
+		QuizIn:         *startIn,
+		QuizQuestions:  *questionCount,
+		HistoryQuizzes: *days,
+	}
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	db, err := connect()
+	if err != nil {
+		return err
+	}
+	switch args[0] {
+	case "all":
+		return seed.Run(db, opts)
+	case "users":
+		return seed.Users(db, opts.Players, opts.Password)
+	case "questions":
+		return seed.QuestionPool(db)
+	case "quiz":
+		return seed.Quiz(db, time.Now().Add(opts.QuizIn), opts.QuizQuestions)
+	case "ads":
+		return seed.Ads(db)
+	case "history":
+		return seed.History(db, opts.HistoryQuizzes, time.Now())
+	default:
+		return fmt.Errorf("unknown seed command %q", args[0])
+	}
+}
@@ -0,0 +1,120 @@
+// Команда warehouse-backfill восстанавливает события хранилища аналитики из БД: answer_submitted,
+// elimination, quiz_completed и исправления (question_voided, elimination_revoked) завершённых викторин. Нужна после простоя приёмника, при подключении
+// нового хранилища и после повышения schema_version.
+//
+// Использует конфигурацию API (CONFIG_PATH, по умолчанию config/config.yaml): БД и приёмник
+// из секции warehouse. Флаги -sink и -dir переопределяют приёмник, например чтобы выгрузить
+// события в файлы для ручной загрузки.
+//
+// Примеры:
+//
+//	go run ./cmd/warehouse-backfill -from 2026-09-01 -to 2026-10-01
+//	go run ./cmd/warehouse-backfill -quiz-ids 41,42 -sink file -dir ./backfill
+//
+// События получают source=backfill и те же event_id, что и живые, поэтому повторный запуск
+// не создаёт дубликатов в хранилище, которое убирает их по event_id.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/config"
+	"github.com/yourusername/trivia-api/internal/pkg/warehouse"
+	pgRepo "github.com/yourusername/trivia-api/internal/repository/postgres"
+	"github.com/yourusername/trivia-api/internal/service"
+	"github.com/yourusername/trivia-api/pkg/database"
+)
+
+func main() {
+	var (
+		quizIDs   = flag.String("quiz-ids", "", "ID викторин через запятую (по умолчанию — все завершённые за период)")
+		from      = flag.String("from", "", "начало периода по времени проведения, YYYY-MM-DD")
+		to        = flag.String("to", "", "конец периода: до полуночи этой даты, YYYY-MM-DD")
+		sinkType  = flag.String("sink", "", "приёмник: kafka, http или file (по умолчанию warehouse.sink)")
+		dir       = flag.String("dir", "", "каталог для -sink file (по умолчанию warehouse.dir)")
+		batchSize = flag.Int("batch-size", 1000, "строк за запрос к БД и событий в пачке")
+	)
+	flag.Parse()
+
+	opts := service.WarehouseBackfillOptions{BatchSize: *batchSize}
+	if *quizIDs != "" {
+		for _, raw := range strings.Split(*quizIDs, ",") {
+			id, err := strconv.ParseUint(strings.TrimSpace(raw), 10, 64)
+			if err != nil || id == 0 {
+				log.Fatalf("invalid quiz id %q", raw)
+			}
+			opts.QuizIDs = append(opts.QuizIDs, uint(id))
+		}
+	}
+	opts.From = parseDate("from", *from)
+	opts.To = parseDate("to", *to)
+	if len(opts.QuizIDs) == 0 && opts.From == nil {
+		log.Fatal("-quiz-ids or -from is required")
+	}
+
+	configPath := os.Getenv("CONFIG_PATH")
+	if configPath == "" {
+		configPath = "config/config.yaml"
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	sinkCfg := warehouse.SinkConfig{
+		Type:      cfg.Warehouse.Sink,
+		URL:       cfg.Warehouse.URL,
+		Topic:     cfg.Warehouse.Topic,
+		AuthToken: cfg.Warehouse.AuthToken,
+		Dir:       cfg.Warehouse.Dir,
+		Timeout:   time.Duration(cfg.Warehouse.TimeoutSec) * time.Second,
+	}
+	if *sinkType != "" {
+		sinkCfg.Type = *sinkType
+	}
+	if *dir != "" {
+		sinkCfg.Dir = *dir
+	}
+	sink, err := warehouse.NewSink(sinkCfg)
+	if err != nil {
+		log.Fatalf("Failed to create warehouse sink: %v", err)
+	}
+	defer sink.Close()
+
+	db, err := database.NewPostgresDB(cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	started := time.Now()
+	report, err := service.BackfillWarehouse(ctx, sink, pgRepo.NewQuizRepo(db), pgRepo.NewExportJobRepo(db), opts)
+	if report != nil {
+		log.Printf("Викторин: %d, событий: %d, за %s", report.Quizzes, report.Events, time.Since(started).Round(time.Second))
+	}
+	if err != nil {
+		log.Fatalf("Backfill failed: %v", err)
+	}
+}
+
+// parseDate разбирает дату флага name; пустое значение — nil
+func parseDate(name, value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		log.Fatalf("invalid -%s %q: expected YYYY-MM-DD", name, value)
+	}
+	return &date
+}
@@ -0,0 +1,52 @@
+// Команда ws-contract генерирует TypeScript-типы протокола WebSocket из каталога
+// internal/websocket/contract/catalog.json для пакета @trivia/shared.
+//
+// Примеры (из каталога trivia-api):
+//
+//	go run ./cmd/ws-contract
+//	go run ./cmd/ws-contract -check
+//
+// С флагом -check файл не перезаписывается: команда завершается с ошибкой, если он устарел
+// (для CI после изменения каталога).
+package main
+
+import (
+	"bytes"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/yourusername/trivia-api/internal/websocket/contract"
+)
+
+func main() {
+	var (
+		out   = flag.String("out", "../shared/src/ws-contract.gen.ts", "файл TypeScript-типов")
+		check = flag.Bool("check", false, "только проверить, что файл соответствует каталогу")
+	)
+	flag.Parse()
+
+	catalog, err := contract.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	generated, err := catalog.TypeScript()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *check {
+		current, err := os.ReadFile(*out)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if !bytes.Equal(current, []byte(generated)) {
+			log.Fatalf("%s is out of date, run go run ./cmd/ws-contract", *out)
+		}
+		return
+	}
+	if err := os.WriteFile(*out, []byte(generated), 0o644); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("ws-contract: %s written", *out)
+}
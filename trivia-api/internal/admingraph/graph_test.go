@@ -0,0 +1,133 @@
+package admingraph
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+type stubQuizzes struct{}
+
+func (stubQuizzes) GetQuizByID(quizID uint) (*entity.Quiz, error) {
+	if quizID != 1 {
+		return nil, apperrors.ErrNotFound
+	}
+	return &entity.Quiz{ID: 1, Title: "Вечерняя", Status: entity.QuizStatusCompleted}, nil
+}
+
+func (stubQuizzes) ListQuizzesWithFilters(int, int, repository.QuizFilters) ([]entity.Quiz, int64, error) {
+	return []entity.Quiz{{ID: 1}, {ID: 2}, {ID: 3}}, 3, nil
+}
+
+type stubResults struct{}
+
+func (stubResults) GetQuizResults(quizID uint, page, pageSize int, fullAccess bool) ([]entity.Result, int64, error) {
+	results := make([]entity.Result, 0, 30)
+	for i := 1; i <= 30; i++ {
+		results = append(results, entity.Result{ID: uint(i), QuizID: quizID, UserID: uint(100 + i%10), Rank: i})
+	}
+	return results, 30, nil
+}
+
+func (stubResults) GetUserResults(uint, int, int) ([]entity.Result, int64, error) { return nil, 0, nil }
+func (stubResults) GetQuizWinners(uint) ([]entity.Result, error)                  { return nil, nil }
+func (stubResults) CalculateQuizStatistics(uint) (*service.QuizStatistics, error) {
+	return &service.QuizStatistics{TotalParticipants: 30}, nil
+}
+
+// countingUsers считает пакетные запросы пользователей
+type countingUsers struct {
+	repository.UserRepository
+	mu    sync.Mutex
+	calls [][]uint
+}
+
+func (u *countingUsers) GetByIDs(ids []uint) ([]entity.User, error) {
+	u.mu.Lock()
+	u.calls = append(u.calls, ids)
+	u.mu.Unlock()
+	users := make([]entity.User, 0, len(ids))
+	for _, id := range ids {
+		users = append(users, entity.User{ID: id, Username: "player"})
+	}
+	return users, nil
+}
+
+type countingAdSlots struct {
+	repository.QuizAdSlotRepository
+	calls int
+}
+
+func (a *countingAdSlots) ListByQuizIDs(quizIDs []uint) ([]entity.QuizAdSlot, error) {
+	a.calls++
+	return []entity.QuizAdSlot{{ID: 9, QuizID: 2, QuestionAfter: 5, AdAsset: &entity.AdAsset{ID: 4, Title: "Промо"}}}, nil
+}
+
+func newTestGraph(t *testing.T) (*Graph, *countingUsers, *countingAdSlots) {
+	t.Helper()
+	users, adSlots := &countingUsers{}, &countingAdSlots{}
+	graph, err := NewGraph(stubQuizzes{}, stubResults{}, users, nil, adSlots)
+	require.NoError(t, err)
+	return graph, users, adSlots
+}
+
+func TestGraph_BatchesResultUsers(t *testing.T) {
+	graph, users, _ := newTestGraph(t)
+
+	response := graph.Exec(context.Background(), `{
+		quiz(id: "1") { title results(pageSize: 30) { total items { rank user { id username } } } statistics { totalParticipants } }
+	}`, "", nil)
+	require.Empty(t, response.Errors)
+
+	var data struct {
+		Quiz struct {
+			Title   string
+			Results struct {
+				Total int
+				Items []struct {
+					Rank int
+					User struct{ ID string }
+				}
+			}
+			Statistics struct{ TotalParticipants int }
+		}
+	}
+	require.NoError(t, json.Unmarshal(response.Data, &data))
+	assert.Equal(t, "Вечерняя", data.Quiz.Title)
+	require.Len(t, data.Quiz.Results.Items, 30)
+	assert.Equal(t, "101", data.Quiz.Results.Items[0].User.ID)
+	assert.Equal(t, 30, data.Quiz.Statistics.TotalParticipants)
+
+	// 30 результатов 10 разных игроков — один пакетный запрос без повторов
+	require.Len(t, users.calls, 1)
+	assert.Len(t, users.calls[0], 10)
+}
+
+func TestGraph_AdSlotsLoadedOncePerPage(t *testing.T) {
+	graph, _, adSlots := newTestGraph(t)
+
+	response := graph.Exec(context.Background(), `{ quizzes { total items { id adSlots { questionAfter asset { title } } } } }`, "", nil)
+	require.Empty(t, response.Errors)
+	assert.Equal(t, 1, adSlots.calls)
+	assert.Contains(t, string(response.Data), `"adSlots":[{"questionAfter":5,"asset":{"title":"Промо"}}]`)
+}
+
+func TestGraph_MissingQuizIsNull(t *testing.T) {
+	graph, _, _ := newTestGraph(t)
+
+	response := graph.Exec(context.Background(), `{ quiz(id: "5") { title } }`, "", nil)
+	require.Empty(t, response.Errors)
+	assert.JSONEq(t, `{"quiz":null}`, string(response.Data))
+
+	response = graph.Exec(context.Background(), `{ quiz(id: "abc") { title } }`, "", nil)
+	assert.NotEmpty(t, response.Errors)
+}
@@ -0,0 +1,99 @@
+package admingraph
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// loaderWait — сколько загрузчик ждёт остальные ключи, прежде чем выполнить пакетный запрос.
+	// Поля элементов списка резолвятся параллельно (см. maxParallelism), поэтому за это время
+	// ключи всей страницы успевают попасть в один пакет.
+	loaderWait = 2 * time.Millisecond
+	// loaderMaxBatch — максимальный размер пакета; больше ключей делятся на несколько запросов
+	loaderMaxBatch = 100
+)
+
+// batchFunc загружает значения для набора ключей одним запросом.
+// Ключи, для которых значения нет, в результат не попадают.
+type batchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// loaderResult — результат загрузки одного ключа; done закрывается, когда значение готово
+type loaderResult[V any] struct {
+	value V
+	found bool
+	err   error
+	done  chan struct{}
+}
+
+// batchLoader объединяет загрузки по ключу, поступившие почти одновременно, в один пакетный
+// запрос и кеширует результаты на время одного GraphQL-запроса (dataloader)
+type batchLoader[K comparable, V any] struct {
+	fetch batchFunc[K, V]
+
+	mu      sync.Mutex
+	cache   map[K]*loaderResult[V]
+	pending []K
+	timer   *time.Timer
+}
+
+func newBatchLoader[K comparable, V any](fetch batchFunc[K, V]) *batchLoader[K, V] {
+	return &batchLoader[K, V]{fetch: fetch, cache: make(map[K]*loaderResult[V])}
+}
+
+// Load возвращает значение по ключу; false — значения с таким ключом нет
+func (l *batchLoader[K, V]) Load(ctx context.Context, key K) (V, bool, error) {
+	l.mu.Lock()
+	result, ok := l.cache[key]
+	if !ok {
+		result = &loaderResult[V]{done: make(chan struct{})}
+		l.cache[key] = result
+		l.pending = append(l.pending, key)
+		if len(l.pending) >= loaderMaxBatch {
+			l.dispatchLocked(ctx)
+		} else if l.timer == nil {
+			l.timer = time.AfterFunc(loaderWait, func() {
+				l.mu.Lock()
+				defer l.mu.Unlock()
+				l.dispatchLocked(ctx)
+			})
+		}
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-result.done:
+		return result.value, result.found, result.err
+	case <-ctx.Done():
+		var zero V
+		return zero, false, ctx.Err()
+	}
+}
+
+// dispatchLocked забирает накопленные ключи и запускает их загрузку. Вызывается под l.mu.
+func (l *batchLoader[K, V]) dispatchLocked(ctx context.Context) {
+	if l.timer != nil {
+		l.timer.Stop()
+		l.timer = nil
+	}
+	if len(l.pending) == 0 {
+		return
+	}
+	keys := l.pending
+	l.pending = nil
+	results := make([]*loaderResult[V], len(keys))
+	for i, key := range keys {
+		results[i] = l.cache[key]
+	}
+
+	go func() {
+		values, err := l.fetch(ctx, keys)
+		for i, key := range keys {
+			result := results[i]
+			result.value, result.found = values[key]
+			result.err = err
+			close(result.done)
+		}
+	}()
+}
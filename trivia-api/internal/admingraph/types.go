@@ -0,0 +1,281 @@
+package admingraph
+
+import (
+	"context"
+	"errors"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+// quizPageResolver резолвит QuizPage
+type quizPageResolver struct {
+	items    []*quizResolver
+	total    int64
+	page     int
+	pageSize int
+}
+
+func (p *quizPageResolver) Items() []*quizResolver { return p.items }
+func (p *quizPageResolver) Total() int32           { return toInt32(p.total) }
+func (p *quizPageResolver) Page() int32            { return int32(p.page) }
+func (p *quizPageResolver) PageSize() int32        { return int32(p.pageSize) }
+
+// quizResolver резолвит Quiz
+type quizResolver struct {
+	root *Resolver
+	quiz *entity.Quiz
+}
+
+func (q *quizResolver) ID() graphql.ID              { return toID(q.quiz.ID) }
+func (q *quizResolver) Title() string               { return q.quiz.Title }
+func (q *quizResolver) Description() string         { return q.quiz.Description }
+func (q *quizResolver) Status() string              { return q.quiz.Status }
+func (q *quizResolver) ScheduledTime() graphql.Time { return graphql.Time{Time: q.quiz.ScheduledTime} }
+func (q *quizResolver) QuestionCount() int32        { return int32(q.quiz.QuestionCount) }
+func (q *quizResolver) PrizeFund() int32            { return toInt32(int64(q.quiz.PrizeFund)) }
+func (q *quizResolver) FinishOnZeroPlayers() bool   { return q.quiz.FinishOnZeroPlayers }
+func (q *quizResolver) QuestionSourceMode() string  { return q.quiz.QuestionSourceMode }
+func (q *quizResolver) CreatedAt() graphql.Time     { return graphql.Time{Time: q.quiz.CreatedAt} }
+func (q *quizResolver) UpdatedAt() graphql.Time     { return graphql.Time{Time: q.quiz.UpdatedAt} }
+
+// Results возвращает страницу результатов викторины
+func (q *quizResolver) Results(ctx context.Context, args pageArgs) (*resultPageResolver, error) {
+	page, pageSize := args.normalize()
+	results, total, err := q.root.results.GetQuizResults(q.quiz.ID, page, pageSize, true)
+	if err != nil {
+		return nil, internalError("получения результатов викторины", err)
+	}
+	return newResultPage(q.root, results, total, page, pageSize), nil
+}
+
+// Winners возвращает победителей викторины
+func (q *quizResolver) Winners(ctx context.Context) ([]*resultResolver, error) {
+	winners, err := q.root.results.GetQuizWinners(q.quiz.ID)
+	if err != nil {
+		return nil, internalError("получения победителей викторины", err)
+	}
+	return newResults(q.root, winners), nil
+}
+
+// Statistics считает статистику викторины; запрос тяжёлый, поэтому выполняется только
+// когда поле выбрано
+func (q *quizResolver) Statistics(ctx context.Context) (*statisticsResolver, error) {
+	stats, err := q.root.results.CalculateQuizStatistics(q.quiz.ID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, internalError("расчёта статистики викторины", err)
+	}
+	return &statisticsResolver{stats: stats}, nil
+}
+
+// AdSlots возвращает рекламные слоты викторины (пакетно для всех викторин страницы)
+func (q *quizResolver) AdSlots(ctx context.Context) ([]*adSlotResolver, error) {
+	slots, _, err := loadersFrom(ctx).adSlots.Load(ctx, q.quiz.ID)
+	if err != nil {
+		return nil, internalError("получения рекламных слотов", err)
+	}
+	items := make([]*adSlotResolver, len(slots))
+	for i := range slots {
+		items[i] = &adSlotResolver{slot: &slots[i]}
+	}
+	return items, nil
+}
+
+// resultPageResolver резолвит ResultPage
+type resultPageResolver struct {
+	items    []*resultResolver
+	total    int64
+	page     int
+	pageSize int
+}
+
+func newResultPage(root *Resolver, results []entity.Result, total int64, page, pageSize int) *resultPageResolver {
+	return &resultPageResolver{items: newResults(root, results), total: total, page: page, pageSize: pageSize}
+}
+
+func newResults(root *Resolver, results []entity.Result) []*resultResolver {
+	items := make([]*resultResolver, len(results))
+	for i := range results {
+		items[i] = &resultResolver{root: root, result: &results[i]}
+	}
+	return items
+}
+
+func (p *resultPageResolver) Items() []*resultResolver { return p.items }
+func (p *resultPageResolver) Total() int32             { return toInt32(p.total) }
+func (p *resultPageResolver) Page() int32              { return int32(p.page) }
+func (p *resultPageResolver) PageSize() int32          { return int32(p.pageSize) }
+
+// resultResolver резолвит Result
+type resultResolver struct {
+	root   *Resolver
+	result *entity.Result
+}
+
+func (r *resultResolver) ID() graphql.ID             { return toID(r.result.ID) }
+func (r *resultResolver) Score() int32               { return int32(r.result.Score) }
+func (r *resultResolver) CorrectAnswers() int32      { return int32(r.result.CorrectAnswers) }
+func (r *resultResolver) TotalQuestions() int32      { return int32(r.result.TotalQuestions) }
+func (r *resultResolver) Rank() int32                { return int32(r.result.Rank) }
+func (r *resultResolver) IsWinner() bool             { return r.result.IsWinner }
+func (r *resultResolver) PrizeFund() int32           { return toInt32(int64(r.result.PrizeFund)) }
+func (r *resultResolver) IsEliminated() bool         { return r.result.IsEliminated }
+func (r *resultResolver) EliminationReason() *string { return r.result.EliminationReason }
+func (r *resultResolver) CompletedAt() graphql.Time  { return graphql.Time{Time: r.result.CompletedAt} }
+
+func (r *resultResolver) EliminatedOnQuestion() *int32 {
+	if r.result.EliminatedOnQuestion == nil {
+		return nil
+	}
+	question := int32(*r.result.EliminatedOnQuestion)
+	return &question
+}
+
+// User возвращает игрока (пакетно для всех результатов страницы)
+func (r *resultResolver) User(ctx context.Context) (*userResolver, error) {
+	user, found, err := loadersFrom(ctx).users.Load(ctx, r.result.UserID)
+	if err != nil {
+		return nil, internalError("получения пользователя результата", err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &userResolver{root: r.root, user: user}, nil
+}
+
+// Quiz возвращает викторину (пакетно для всех результатов страницы)
+func (r *resultResolver) Quiz(ctx context.Context) (*quizResolver, error) {
+	quiz, found, err := loadersFrom(ctx).quizzes.Load(ctx, r.result.QuizID)
+	if err != nil {
+		return nil, internalError("получения викторины результата", err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &quizResolver{root: r.root, quiz: quiz}, nil
+}
+
+// userResolver резолвит User
+type userResolver struct {
+	root *Resolver
+	user *entity.User
+}
+
+func (u *userResolver) ID() graphql.ID          { return toID(u.user.ID) }
+func (u *userResolver) Username() string        { return u.user.Username }
+func (u *userResolver) Email() string           { return u.user.Email }
+func (u *userResolver) FirstName() string       { return u.user.FirstName }
+func (u *userResolver) LastName() string        { return u.user.LastName }
+func (u *userResolver) ProfilePicture() string  { return u.user.ProfilePicture }
+func (u *userResolver) Language() string        { return u.user.Language }
+func (u *userResolver) GamesPlayed() int32      { return toInt32(u.user.GamesPlayed) }
+func (u *userResolver) TotalScore() int32       { return toInt32(u.user.TotalScore) }
+func (u *userResolver) HighestScore() int32     { return toInt32(u.user.HighestScore) }
+func (u *userResolver) WinsCount() int32        { return toInt32(u.user.WinsCount) }
+func (u *userResolver) TotalPrizeWon() int32    { return toInt32(u.user.TotalPrizeWon) }
+func (u *userResolver) EmailVerified() bool     { return u.user.EmailVerifiedAt != nil }
+func (u *userResolver) Deleted() bool           { return u.user.DeletedAt != nil }
+func (u *userResolver) CreatedAt() graphql.Time { return graphql.Time{Time: u.user.CreatedAt} }
+
+// Results возвращает страницу результатов игрока
+func (u *userResolver) Results(ctx context.Context, args pageArgs) (*resultPageResolver, error) {
+	page, pageSize := args.normalize()
+	results, total, err := u.root.results.GetUserResults(u.user.ID, page, pageSize)
+	if err != nil {
+		return nil, internalError("получения результатов пользователя", err)
+	}
+	return newResultPage(u.root, results, total, page, pageSize), nil
+}
+
+// adSlotResolver резолвит AdSlot
+type adSlotResolver struct {
+	slot *entity.QuizAdSlot
+}
+
+func (a *adSlotResolver) ID() graphql.ID       { return toID(a.slot.ID) }
+func (a *adSlotResolver) QuestionAfter() int32 { return int32(a.slot.QuestionAfter) }
+func (a *adSlotResolver) IsActive() bool       { return a.slot.IsActive }
+
+func (a *adSlotResolver) DurationSec() *int32 {
+	if a.slot.DurationSec == nil {
+		return nil
+	}
+	duration := int32(*a.slot.DurationSec)
+	return &duration
+}
+
+func (a *adSlotResolver) Asset() *adAssetResolver {
+	if a.slot.AdAsset == nil {
+		return nil
+	}
+	return &adAssetResolver{asset: a.slot.AdAsset}
+}
+
+// adAssetResolver резолвит AdAsset
+type adAssetResolver struct {
+	asset *entity.AdAsset
+}
+
+func (a *adAssetResolver) ID() graphql.ID       { return toID(a.asset.ID) }
+func (a *adAssetResolver) Title() string        { return a.asset.Title }
+func (a *adAssetResolver) MediaType() string    { return a.asset.MediaType }
+func (a *adAssetResolver) URL() string          { return a.asset.URL }
+func (a *adAssetResolver) ThumbnailURL() string { return a.asset.ThumbnailURL }
+func (a *adAssetResolver) DurationSec() int32   { return int32(a.asset.DurationSec) }
+
+// statisticsResolver резолвит QuizStatistics
+type statisticsResolver struct {
+	stats *service.QuizStatistics
+}
+
+func (s *statisticsResolver) TotalParticipants() int32   { return int32(s.stats.TotalParticipants) }
+func (s *statisticsResolver) TotalWinners() int32        { return int32(s.stats.TotalWinners) }
+func (s *statisticsResolver) TotalEliminated() int32     { return int32(s.stats.TotalEliminated) }
+func (s *statisticsResolver) RevivedCount() int32        { return int32(s.stats.RevivedCount) }
+func (s *statisticsResolver) AvgResponseTimeMs() float64 { return s.stats.AvgResponseTimeMs }
+func (s *statisticsResolver) AvgCorrectAnswers() float64 { return s.stats.AvgCorrectAnswers }
+func (s *statisticsResolver) AvgPassRate() float64       { return s.stats.AvgPassRate }
+func (s *statisticsResolver) PoolQuestionsUsed() int32   { return int32(s.stats.PoolQuestionsUsed) }
+
+func (s *statisticsResolver) EliminationsByQuestion() []*questionEliminationResolver {
+	items := make([]*questionEliminationResolver, len(s.stats.EliminationsByQ))
+	for i := range s.stats.EliminationsByQ {
+		items[i] = &questionEliminationResolver{q: &s.stats.EliminationsByQ[i]}
+	}
+	return items
+}
+
+func (s *statisticsResolver) EliminationReasons() *eliminationReasonsResolver {
+	return &eliminationReasonsResolver{reasons: &s.stats.EliminationReasons}
+}
+
+// questionEliminationResolver резолвит QuestionElimination
+type questionEliminationResolver struct {
+	q *service.QuestionElimination
+}
+
+func (e *questionEliminationResolver) QuestionNumber() int32  { return int32(e.q.QuestionNumber) }
+func (e *questionEliminationResolver) QuestionID() graphql.ID { return toID(e.q.QuestionID) }
+func (e *questionEliminationResolver) EliminatedCount() int32 { return int32(e.q.EliminatedCount) }
+func (e *questionEliminationResolver) ByTimeout() int32       { return int32(e.q.ByTimeout) }
+func (e *questionEliminationResolver) ByWrongAnswer() int32   { return int32(e.q.ByWrongAnswer) }
+func (e *questionEliminationResolver) AvgResponseMs() float64 { return e.q.AvgResponseMs }
+func (e *questionEliminationResolver) Difficulty() int32      { return int32(e.q.Difficulty) }
+func (e *questionEliminationResolver) PassRate() float64      { return e.q.PassRate }
+func (e *questionEliminationResolver) TotalAnswers() int32    { return int32(e.q.TotalAnswers) }
+
+// eliminationReasonsResolver резолвит EliminationReasons
+type eliminationReasonsResolver struct {
+	reasons *service.EliminationReasons
+}
+
+func (e *eliminationReasonsResolver) Timeout() int32      { return int32(e.reasons.Timeout) }
+func (e *eliminationReasonsResolver) WrongAnswer() int32  { return int32(e.reasons.WrongAnswer) }
+func (e *eliminationReasonsResolver) Disconnected() int32 { return int32(e.reasons.Disconnected) }
+func (e *eliminationReasonsResolver) Other() int32        { return int32(e.reasons.Other) }
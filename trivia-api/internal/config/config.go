@@ -1,9 +1,12 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"log"
 	"os"
+	"reflect"
 	"time"
 
 	"github.com/spf13/viper"
@@ -12,6 +15,7 @@ import (
 // Config хранит все настройки приложения
 type Config struct {
 	Server    ServerConfig
+	GRPC      GRPCConfig `mapstructure:"grpc"`
 	Database  DatabaseConfig
 	Redis     RedisConfig
 	JWT       JWTConfig
@@ -20,9 +24,67 @@ type Config struct {
 	Google    GoogleOAuthConfig `mapstructure:"google_oauth"`
 	Apple     AppleSignInConfig `mapstructure:"apple_signin"`
 	Features  FeaturesConfig
+	Referral  ReferralConfig
+	Push      PushConfig
+	Wallet    WalletConfig
+	Ads       AdsConfig
+	Storage   StorageConfig
+	Tracing   TracingConfig
+	Secrets   SecretsConfig
+	Quiz      QuizConfig
+	Reload    ReloadConfig
 	Legal     LegalConfig
 	CORS      CORSConfig
-	WebSocket WebSocketConfig
+	Locale    LocalizationConfig `mapstructure:"localization"`
+	WebSocket WebSocketConfig    `mapstructure:"websocket"`
+	HTTPCache HTTPCacheConfig    `mapstructure:"httpCache"`
+
+	AnswerBuffer AnswerBufferConfig `mapstructure:"answerBuffer"`
+	Partitioning PartitioningConfig `mapstructure:"partitioning"`
+	Maintenance  MaintenanceConfig  `mapstructure:"maintenance"`
+	Webhooks     WebhooksConfig     `mapstructure:"webhooks"`
+	APIKeys      APIKeysConfig      `mapstructure:"apiKeys"`
+	MobileAPI    MobileAPIConfig    `mapstructure:"mobileAPI"`
+	EventBus     EventBusConfig     `mapstructure:"eventBus"`
+	PrizeClaims  PrizeClaimsConfig  `mapstructure:"prizeClaims"`
+	AntiAbuse    AntiAbuseConfig    `mapstructure:"antiAbuse"`
+	MagicLink    MagicLinkConfig    `mapstructure:"magicLink"`
+	WebAuthn     WebAuthnConfig     `mapstructure:"webauthn"`
+	Purchases    PurchasesConfig    `mapstructure:"purchases"`
+
+	QuestionMedia QuestionMediaConfig `mapstructure:"questionMedia"`
+	ShareCards    ShareCardsConfig    `mapstructure:"shareCards"`
+	Exports       ExportsConfig       `mapstructure:"exports"`
+	Warehouse     WarehouseConfig     `mapstructure:"warehouse"`
+	Statistics    StatisticsConfig    `mapstructure:"statistics"`
+
+	QuestionImport    QuestionImportConfig    `mapstructure:"questionImport"`
+	AIQuestions       AIQuestionsConfig       `mapstructure:"aiQuestions"`
+	TranslationAssist TranslationAssistConfig `mapstructure:"translationAssist"`
+
+	// Moderation — проверка имён и полей профиля по спискам запрещённых слов
+	Moderation ModerationConfig `mapstructure:"moderation"`
+
+	// Geo — определение страны клиента по IP и региональные ограничения призов
+	Geo GeoConfig `mapstructure:"geo"`
+
+	// IPRisk — оценка адресов подключений к викторинам (VPN, прокси, дата-центры) и проверка победителей
+	IPRisk IPRiskConfig `mapstructure:"ipRisk"`
+	// Rating — рейтинг мастерства игроков, пересчитываемый после каждой викторины
+	Rating RatingConfig `mapstructure:"rating"`
+	// DailyChallenge — вопрос дня: один вопрос пула в день с ответом по REST
+	DailyChallenge DailyChallengeConfig `mapstructure:"dailyChallenge"`
+	// Announcer — объявления ведущего, привязанные к моментам викторины
+	Announcer AnnouncerConfig `mapstructure:"announcer"`
+
+	// DevSeed — наполнить БД демо-данными при старте (пакет internal/seed); только для GIN_MODE=debug
+	DevSeed bool `mapstructure:"devSeed"`
+
+	// RateLimits — лимиты запросов по группам маршрутов (auth, auth_strict, mobile, users, uploads, follows, api_keys, public)
+	RateLimits map[string]RateLimitRule `mapstructure:"rateLimits"`
+
+	// SecurityHeaders — заголовки безопасности ответов по группам маршрутов (default, admin, uploads)
+	SecurityHeaders map[string]SecurityHeadersRule `mapstructure:"securityHeaders"`
 }
 
 // ServerConfig содержит настройки HTTP сервера
@@ -32,6 +94,108 @@ type ServerConfig struct {
 	WriteTimeout int
 }
 
+// GRPCConfig содержит настройки внутреннего gRPC API для межсервисных вызовов
+type GRPCConfig struct {
+	Enabled   bool
+	Port      string
+	AuthToken string // Сервисный токен; клиенты передают его в метаданных authorization: Bearer <token>
+}
+
+// LocalizationConfig содержит настройки языков контента
+type LocalizationConfig struct {
+	DefaultLocale    string   // Язык основных полей вопросов (text/options) и последний запасной язык
+	SupportedLocales []string // Языки, для которых принимаются переводы и предпочтения пользователей
+}
+
+// HTTPCacheConfig содержит настройки кеширования ответов публичных эндпоинтов
+// (списки викторин, лидерборд) с ETag/If-None-Match
+type HTTPCacheConfig struct {
+	Enabled    bool `mapstructure:"enabled"`
+	TTLSeconds int  `mapstructure:"ttlSeconds"` // срок жизни ответа, если данные изменились в обход сервисов
+}
+
+// AnswerBufferConfig содержит настройки пакетной записи ответов пользователей
+type AnswerBufferConfig struct {
+	Enabled         bool `mapstructure:"enabled"`
+	BatchSize       int  `mapstructure:"batchSize"`       // ответов в пачке, при котором запись идёт не дожидаясь интервала
+	FlushIntervalMs int  `mapstructure:"flushIntervalMs"` // максимальная задержка записи ответа
+}
+
+// PartitioningConfig содержит настройки хранения секций user_answers и results
+type PartitioningConfig struct {
+	RetentionDays      int `mapstructure:"retentionDays"`      // секции викторин старше срока переносятся в схему archive; 0 — хранить всё
+	CheckIntervalHours int `mapstructure:"checkIntervalHours"` // как часто искать устаревшие секции
+}
+
+// WebhooksConfig содержит настройки доставки вебхуков о событиях викторин
+type WebhooksConfig struct {
+	Enabled         bool `mapstructure:"enabled"`
+	MaxAttempts     int  `mapstructure:"maxAttempts"`     // попыток на доставку, включая первую
+	TimeoutSec      int  `mapstructure:"timeoutSec"`      // таймаут запроса к партнёру
+	PollIntervalSec int  `mapstructure:"pollIntervalSec"` // как часто искать доставки для повтора
+	BatchSize       int  `mapstructure:"batchSize"`       // доставок за один проход
+	RetentionDays   int  `mapstructure:"retentionDays"`   // срок хранения журнала доставки, 0 — бессрочно
+	AllowHTTP       bool `mapstructure:"allowHTTP"`       // разрешить адреса http:// (только для разработки)
+}
+
+// APIKeysConfig содержит настройки доступа партнёров по API-ключам (/api/partner)
+type APIKeysConfig struct {
+	Enabled          bool `mapstructure:"enabled"`
+	FlushIntervalSec int  `mapstructure:"flushIntervalSec"` // как часто статистика запросов ключей сохраняется в БД
+}
+
+// MobileAPIConfig содержит настройки версий мобильного API (/api/mobile — v1, /api/mobile/v2)
+type MobileAPIConfig struct {
+	V1DeprecatedAt        string `mapstructure:"v1DeprecatedAt"`        // дата объявления v1 устаревшей (ГГГГ-ММ-ДД), заголовок Deprecation
+	V1SunsetAt            string `mapstructure:"v1SunsetAt"`            // дата отключения v1, заголовок Sunset; пусто — не объявлена
+	StatsFlushIntervalSec int    `mapstructure:"statsFlushIntervalSec"` // как часто статистика версий клиентов сохраняется в БД
+}
+
+// V1Dates возвращает даты объявления v1 устаревшей и её отключения (нулевая, если не объявлена)
+func (c MobileAPIConfig) V1Dates() (deprecatedAt, sunsetAt time.Time, err error) {
+	deprecatedAt, err = time.Parse("2006-01-02", c.V1DeprecatedAt)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("mobileAPI.v1DeprecatedAt must be a date (YYYY-MM-DD), got %q", c.V1DeprecatedAt)
+	}
+	if c.V1SunsetAt != "" {
+		sunsetAt, err = time.Parse("2006-01-02", c.V1SunsetAt)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("mobileAPI.v1SunsetAt must be a date (YYYY-MM-DD), got %q", c.V1SunsetAt)
+		}
+	}
+	return deprecatedAt, sunsetAt, nil
+}
+
+// EventBusConfig содержит настройки обработки доменных событий из outbox
+type EventBusConfig struct {
+	PollIntervalMs int `mapstructure:"pollIntervalMs"` // как часто искать события для обработки и повтора
+	BatchSize      int `mapstructure:"batchSize"`      // событий за один проход
+	MaxAttempts    int `mapstructure:"maxAttempts"`    // попыток на событие, затем статус dead
+	RetentionDays  int `mapstructure:"retentionDays"`  // срок хранения обработанных событий, 0 — бессрочно
+}
+
+// PrizeClaimsConfig содержит настройки получения призов победителями
+type PrizeClaimsConfig struct {
+	Enabled          bool   `mapstructure:"enabled"`
+	ClaimWindowHours int    `mapstructure:"claimWindowHours"` // срок подачи данных для выплаты после викторины
+	UnclaimedPolicy  string `mapstructure:"unclaimedPolicy"`  // redistribute — разделить между остальными победителями, forfeit — приз сгорает
+	CheckIntervalMin int    `mapstructure:"checkIntervalMin"` // как часто искать просроченные заявки
+}
+
+// AntiAbuseConfig содержит ограничения на создание аккаунтов с одного устройства
+type AntiAbuseConfig struct {
+	MaxAccountsPerDevice int    `mapstructure:"maxAccountsPerDevice"` // 0 — без ограничения
+	OverLimitAction      string `mapstructure:"overLimitAction"`      // block — отказать в регистрации, shadow_ban — зарегистрировать без права на призы
+}
+
+// MaintenanceConfig содержит настройки режима обслуживания «только чтение».
+// Режим включается и выключается через админ-API; Enabled включает его принудительно.
+type MaintenanceConfig struct {
+	Enabled       bool   `mapstructure:"enabled"`
+	Message       string `mapstructure:"message"`
+	RetryAfterSec int    `mapstructure:"retryAfterSec"` // значение Retry-After по умолчанию
+}
+
 // DatabaseConfig содержит настройки подключения к PostgreSQL
 type DatabaseConfig struct {
 	Host     string
@@ -40,6 +204,15 @@ type DatabaseConfig struct {
 	Password string
 	DBName   string
 	SSLMode  string
+
+	// Пул соединений
+	MaxOpenConns           int // максимум открытых соединений
+	MaxIdleConns           int // максимум простаивающих соединений (не больше MaxOpenConns)
+	ConnMaxLifetimeMinutes int // время жизни соединения; 0 — без ограничения
+	ConnMaxIdleTimeMinutes int // время простоя, после которого соединение закрывается; 0 — без ограничения
+
+	SlowQueryMs int    // запросы дольше порога логируются без значений параметров; 0 — отключено
+	LogLevel    string // уровень логгера GORM: silent, error, warn, info
 }
 
 // RedisConfig содержит унифицированные настройки подключения к Redis
@@ -79,23 +252,162 @@ type JWTConfig struct {
 	WSTicketExpirySec     int           `mapstructure:"wsTicketExpirySec"`         // Время жизни тикета для WebSocket в секундах
 	CleanupInterval       time.Duration `mapstructure:"cleanup_interval"`          // Интервал очистки кеша
 	DBJWTKeyEncryptionKey string        `mapstructure:"db_jwt_key_encryption_key"` // Ключ для шифрования JWT ключей в БД
+	// Версия мастер-ключа db_jwt_key_encryption_key; при смене ключа увеличивается на 1, а прежний
+	// ключ передаётся в db_jwt_key_previous_encryption_key до завершения `manage jwt-keys reencrypt`
+	DBJWTKeyEncryptionKeyVersion  int               `mapstructure:"db_jwt_key_encryption_key_version"`
+	DBJWTKeyPreviousEncryptionKey string            `mapstructure:"db_jwt_key_previous_encryption_key"`
+	Rotation                      JWTRotationConfig `mapstructure:"rotation"` // Автоматическая ротация ключей подписи
+}
+
+// DBKeyEncryptionKeys возвращает мастер-ключи шифрования JWT ключей в БД по версиям:
+// текущий и, если задан, прежний (на версию меньше)
+func (c JWTConfig) DBKeyEncryptionKeys() map[int]string {
+	keys := map[int]string{c.DBJWTKeyEncryptionKeyVersion: c.DBJWTKeyEncryptionKey}
+	if c.DBJWTKeyPreviousEncryptionKey != "" {
+		keys[c.DBJWTKeyEncryptionKeyVersion-1] = c.DBJWTKeyPreviousEncryptionKey
+	}
+	return keys
+}
+
+// JWTRotationConfig содержит настройки автоматической ротации ключей подписи JWT
+type JWTRotationConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	MaxKeyAge     time.Duration `mapstructure:"maxKeyAge"`     // возраст активного ключа, после которого он ротируется
+	Retention     time.Duration `mapstructure:"retention"`     // сколько ротированный ключ ещё принимается при проверке подписи
+	CheckInterval time.Duration `mapstructure:"checkInterval"` // как часто проверять возраст ключа
 }
 
 // AuthConfig содержит настройки аутентификации
 type AuthConfig struct {
 	SessionLimit         int
 	RefreshTokenLifetime int
+	Sessions             SessionsConfig `mapstructure:"sessions"` // ограничения времени жизни сессий
+	CSRF                 CSRFConfig     `mapstructure:"csrf"`
+}
+
+// Схемы CSRF-защиты маршрутов админ-панели
+const (
+	CSRFModeDoubleSubmit = "double_submit" // секрет в JWT и HttpOnly cookie, токен — его хеш
+	CSRFModeSynchronizer = "synchronizer"  // nonce хранятся на сервере в CSRF-сессии и ротируются
+)
+
+// CSRFConfig содержит настройки CSRF-защиты
+type CSRFConfig struct {
+	AdminMode     string        `mapstructure:"adminMode"`     // double_submit | synchronizer
+	NonceTTL      time.Duration `mapstructure:"nonceTTL"`      // срок жизни неиспользованного nonce
+	RotationGrace time.Duration `mapstructure:"rotationGrace"` // сколько заменённый nonce ещё принимается
+}
+
+// SessionPolicyConfig ограничивает сессию (цепочку refresh-токенов от входа до выхода); 0 — без ограничения
+type SessionPolicyConfig struct {
+	IdleTimeout      time.Duration `mapstructure:"idleTimeout"`      // выход, если refresh-токен не использовался дольше
+	AbsoluteLifetime time.Duration `mapstructure:"absoluteLifetime"` // выход через это время после входа
+}
+
+// ClientSessionsConfig — политики для веб- и мобильных клиентов
+type ClientSessionsConfig struct {
+	Web    SessionPolicyConfig `mapstructure:"web"`
+	Mobile SessionPolicyConfig `mapstructure:"mobile"`
+}
+
+// SessionsConfig — политики по умолчанию и переопределения для ролей
+type SessionsConfig struct {
+	Web    SessionPolicyConfig             `mapstructure:"web"`
+	Mobile SessionPolicyConfig             `mapstructure:"mobile"`
+	Roles  map[string]ClientSessionsConfig `mapstructure:"roles"` // незаданные поля наследуются от web/mobile
 }
 
 // EmailConfig contains transactional email settings.
 type EmailConfig struct {
-	Provider          string        `mapstructure:"provider"`
-	ResendAPIKey      string        `mapstructure:"resendApiKey"`
-	From              string        `mapstructure:"from"`
-	VerificationTTL   time.Duration `mapstructure:"verificationCodeTTL"`
+	Provider            string                      `mapstructure:"provider"`          // resend | smtp | ses
+	FallbackProviders   []string                    `mapstructure:"fallbackProviders"` // резервные провайдеры по порядку
+	ResendAPIKey        string                      `mapstructure:"resendApiKey"`
+	ResendWebhookSecret string                      `mapstructure:"resendWebhookSecret"` // whsec_... для событий доставки
+	SMTP                EmailSMTPConfig             `mapstructure:"smtp"`
+	SES                 EmailSESConfig              `mapstructure:"ses"`
+	Retry               map[string]EmailRetryConfig `mapstructure:"retry"`        // ключ — имя провайдера
+	TemplatesDir        string                      `mapstructure:"templatesDir"` // файлы <key>.<locale>.tmpl поверх встроенных шаблонов
+	From                string                      `mapstructure:"from"`
+	VerificationTTL     time.Duration               `mapstructure:"verificationCodeTTL"`
+	ResendCooldownSec   int                         `mapstructure:"resendCooldownSec"`
+	MaxAttempts         int                         `mapstructure:"maxAttempts"`
+	CodePepper          string                      `mapstructure:"codePepper"`
+}
+
+// MagicLinkConfig содержит настройки входа по ссылке из письма (features.magic_link_enabled)
+type MagicLinkConfig struct {
+	URL               string        `mapstructure:"url"` // страница фронтенда, к которой добавляется ?token=...
+	TTL               time.Duration `mapstructure:"ttl"`
 	ResendCooldownSec int           `mapstructure:"resendCooldownSec"`
-	MaxAttempts       int           `mapstructure:"maxAttempts"`
-	CodePepper        string        `mapstructure:"codePepper"`
+	MaxPerHour        int           `mapstructure:"maxPerHour"` // ссылок одному пользователю в час
+	Secret            string        `mapstructure:"secret"`     // ключ HMAC токенов и привязки к устройству
+}
+
+// WebAuthnConfig содержит настройки входа по ключам доступа (features.webauthn_enabled)
+type WebAuthnConfig struct {
+	RPID             string        `mapstructure:"rpID"`          // домен сайта без схемы и порта
+	RPDisplayName    string        `mapstructure:"rpDisplayName"` // название, которое показывает браузер
+	RPOrigins        []string      `mapstructure:"rpOrigins"`     // допустимые origin фронтенда
+	CeremonyTimeout  time.Duration `mapstructure:"ceremonyTimeout"`
+	RequireForAdmins bool          `mapstructure:"requireForAdmins"` // администраторам с ключом вход только по ключу
+}
+
+// PurchasesConfig содержит настройки покупок в магазинах приложений (премиум-тариф)
+type PurchasesConfig struct {
+	Enabled  bool                    `mapstructure:"enabled"`
+	Products []PurchaseProductConfig `mapstructure:"products"` // список, а не карта: идентификаторы продуктов содержат точки
+	Apple    AppStoreConfig          `mapstructure:"apple"`
+	Google   GooglePlayConfig        `mapstructure:"google"`
+}
+
+// PurchaseProductConfig описывает продукт магазина и права, которые он даёт
+type PurchaseProductConfig struct {
+	ID           string   `mapstructure:"id"`           // идентификатор продукта в App Store Connect и Play Console
+	Entitlements []string `mapstructure:"entitlements"` // premium, ad_free, extra_lifelines
+	Subscription bool     `mapstructure:"subscription"` // автовозобновляемая подписка
+}
+
+// AppStoreConfig содержит ключ App Store Server API; пустой issuerID — покупки App Store не принимаются
+type AppStoreConfig struct {
+	IssuerID       string `mapstructure:"issuerID"`
+	KeyID          string `mapstructure:"keyID"`
+	PrivateKeyFile string `mapstructure:"privateKeyFile"` // .p8 ключ In-App Purchase
+	BundleID       string `mapstructure:"bundleID"`
+	Environment    string `mapstructure:"environment"`  // production | sandbox
+	AllowSandbox   bool   `mapstructure:"allowSandbox"` // в production принимать покупки песочницы (App Review)
+}
+
+// GooglePlayConfig содержит доступ к Google Play Developer API; пустой packageName — покупки Google Play не принимаются
+type GooglePlayConfig struct {
+	PackageName        string `mapstructure:"packageName"`
+	ServiceAccountFile string `mapstructure:"serviceAccountFile"` // JSON сервисного аккаунта с доступом к Play Console
+	NotificationToken  string `mapstructure:"notificationToken"`  // секрет в адресе push-подписки Pub/Sub (?token=)
+}
+
+// EmailSMTPConfig содержит параметры SMTP-сервера
+type EmailSMTPConfig struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	TLS      string `mapstructure:"tls"` // starttls | implicit | none
+}
+
+// EmailSESConfig содержит параметры Amazon SES
+type EmailSESConfig struct {
+	Region           string `mapstructure:"region"`
+	Endpoint         string `mapstructure:"endpoint"`
+	AccessKey        string `mapstructure:"accessKey"`
+	SecretKey        string `mapstructure:"secretKey"`
+	ConfigurationSet string `mapstructure:"configurationSet"`
+	CallbackToken    string `mapstructure:"callbackToken"` // token в адресе подписки SNS
+}
+
+// EmailRetryConfig — политика повторов отправки через провайдера
+type EmailRetryConfig struct {
+	MaxAttempts      int `mapstructure:"maxAttempts"`
+	InitialBackoffMs int `mapstructure:"initialBackoffMs"`
+	MaxBackoffMs     int `mapstructure:"maxBackoffMs"`
 }
 
 // GoogleOAuthConfig stores OAuth credentials for Google sign-in.
@@ -122,12 +434,339 @@ type FeaturesConfig struct {
 	EmailVerificationEnabled         bool `mapstructure:"email_verification_enabled"`
 	EmailVerificationSoftGateEnabled bool `mapstructure:"email_verification_soft_gate_enabled"`
 	GoogleOAuthEnabled               bool `mapstructure:"google_oauth_enabled"`
+	MagicLinkEnabled                 bool `mapstructure:"magic_link_enabled"`
+	WebAuthnEnabled                  bool `mapstructure:"webauthn_enabled"`
 	AppleSignInEnabled               bool `mapstructure:"apple_signin_enabled"`
+	ReferralsEnabled                 bool `mapstructure:"referrals_enabled"`
+	PushNotificationsEnabled         bool `mapstructure:"push_notifications_enabled"`
+	WalletEnabled                    bool `mapstructure:"wallet_enabled"`
+}
+
+// ReferralConfig содержит настройки реферальной программы
+type ReferralConfig struct {
+	BonusPoints int `mapstructure:"bonusPoints"` // очки, начисляемые пригласившему
+	MaxPerIP    int `mapstructure:"maxPerIP"`    // лимит приглашений одного пользователя с одного IP
+}
+
+// PushConfig содержит настройки push-уведомлений (FCM/APNs)
+type PushConfig struct {
+	Workers               int    `mapstructure:"workers"`   // количество воркеров рассылки
+	QueueSize             int    `mapstructure:"queueSize"` // размер очереди задач рассылки
+	FCMServiceAccountFile string `mapstructure:"fcmServiceAccountFile"`
+	APNsKeyFile           string `mapstructure:"apnsKeyFile"` // .p8 ключ для token-based авторизации
+	APNsKeyID             string `mapstructure:"apnsKeyID"`
+	APNsTeamID            string `mapstructure:"apnsTeamID"`
+	APNsBundleID          string `mapstructure:"apnsBundleID"`
+	APNsProduction        bool   `mapstructure:"apnsProduction"`
+}
+
+// WalletConfig содержит настройки кошелька и выплат
+type WalletConfig struct {
+	MinPayout int64 `mapstructure:"minPayout"` // минимальная сумма заявки на выплату
+}
+
+// AdsConfig содержит ограничения на рекламные файлы и настройки обработки видео
+type AdsConfig struct {
+	MaxImageSizeMB      int64  `mapstructure:"maxImageSizeMB"`
+	MaxVideoSizeMB      int64  `mapstructure:"maxVideoSizeMB"`
+	MaxVideoDurationSec int    `mapstructure:"maxVideoDurationSec"`
+	FFmpegPath          string `mapstructure:"ffmpegPath"`  // пусто — поиск ffmpeg в PATH
+	FFprobePath         string `mapstructure:"ffprobePath"` // пусто — поиск ffprobe в PATH
+}
+
+// QuestionMediaConfig содержит ограничения на медиа вопросов и срок действия ссылок на них
+type QuestionMediaConfig struct {
+	MaxImageSizeMB int64 `mapstructure:"maxImageSizeMB"`
+	MaxAudioSizeMB int64 `mapstructure:"maxAudioSizeMB"`
+	URLTTLMinutes  int   `mapstructure:"urlTTLMinutes"` // срок действия подписанных ссылок в quiz:question
+}
+
+// ShareCardsConfig содержит настройки карточек результатов для соцсетей
+type ShareCardsConfig struct {
+	BaseURL  string `mapstructure:"baseURL"`  // публичный адрес сервера для страниц /share/{token} и абсолютных ссылок в OpenGraph
+	SiteName string `mapstructure:"siteName"` // og:site_name и подпись на карточке
+	Secret   string `mapstructure:"secret"`   // ключ HMAC адресов карточек
+}
+
+// ExportsConfig содержит настройки фоновых выгрузок результатов и ответов викторин
+type ExportsConfig struct {
+	PollIntervalSec       int `mapstructure:"pollIntervalSec"`       // как часто обработчик ищет задания
+	BatchSize             int `mapstructure:"batchSize"`             // строк за один запрос к БД
+	DownloadURLTTLMinutes int `mapstructure:"downloadURLTTLMinutes"` // срок действия ссылки на скачивание
+	RetentionHours        int `mapstructure:"retentionHours"`        // сколько хранится готовый файл
+}
+
+// WarehouseConfig содержит настройки потоковой выгрузки событий игры в хранилище аналитики
+type WarehouseConfig struct {
+	Enabled         bool   `mapstructure:"enabled"`
+	Sink            string `mapstructure:"sink"`            // kafka (через REST Proxy), http или file
+	URL             string `mapstructure:"url"`             // kafka: адрес REST Proxy; http: адрес приёма пачек NDJSON
+	Topic           string `mapstructure:"topic"`           // kafka: топик
+	AuthToken       string `mapstructure:"authToken"`       // kafka, http: Bearer-токен
+	Dir             string `mapstructure:"dir"`             // file: каталог для NDJSON-файлов
+	BatchSize       int    `mapstructure:"batchSize"`       // событий в пачке
+	FlushIntervalMs int    `mapstructure:"flushIntervalMs"` // максимальная задержка отправки события
+	BufferSize      int    `mapstructure:"bufferSize"`      // ёмкость очереди; при переполнении события отбрасываются
+	TimeoutSec      int    `mapstructure:"timeoutSec"`      // таймаут запроса к приёмнику
+}
+
+// StatisticsConfig выбирает источник админ-статистики викторин и личной аналитики игроков
+type StatisticsConfig struct {
+	Backend    string           `mapstructure:"backend"` // postgres (по умолчанию) или clickhouse
+	ClickHouse ClickHouseConfig `mapstructure:"clickhouse"`
+}
+
+// ClickHouseConfig содержит параметры HTTP-интерфейса ClickHouse с таблицей trivia_events
+type ClickHouseConfig struct {
+	URL        string `mapstructure:"url"` // например http://clickhouse:8123
+	Database   string `mapstructure:"database"`
+	User       string `mapstructure:"user"`
+	Password   string `mapstructure:"password"`
+	TimeoutSec int    `mapstructure:"timeoutSec"`
+}
+
+// QuestionImportConfig содержит настройки импорта вопросов из внешних источников
+// (POST /api/admin/question-imports и импорт по расписанию)
+type QuestionImportConfig struct {
+	Enabled       bool                   `mapstructure:"enabled"`       // импорт по расписанию; вручную импорт доступен всегда
+	IntervalHours int                    `mapstructure:"intervalHours"` // как часто выполняется scheduled
+	TimeoutSec    int                    `mapstructure:"timeoutSec"`    // таймаут запроса к источнику
+	OpenTDB       QuestionSourceConfig   `mapstructure:"opentdb"`
+	Scheduled     []ScheduledImportEntry `mapstructure:"scheduled"`
+}
+
+// QuestionSourceConfig содержит адрес источника и сопоставление его категорий и сложности с пулом
+type QuestionSourceConfig struct {
+	BaseURL      string                    `mapstructure:"baseURL"`
+	Categories   []QuestionCategoryMapping `mapstructure:"categories"`
+	Difficulties map[string]int            `mapstructure:"difficulties"` // сложность источника → 1–5; пусто — easy 2, medium 3, hard 4
+}
+
+// QuestionCategoryMapping сопоставляет категорию источника (по названию) с категорией пула
+type QuestionCategoryMapping struct {
+	External string `mapstructure:"external"` // название категории в источнике, например "Science: Computers"
+	Category string `mapstructure:"category"` // slug категории пула
+}
+
+// ScheduledImportEntry — один импорт, выполняемый по расписанию
+type ScheduledImportEntry struct {
+	Source           string `mapstructure:"source"`
+	Amount           int    `mapstructure:"amount"`
+	ExternalCategory string `mapstructure:"externalCategory"` // фильтр категории в терминах источника
+	Difficulty       string `mapstructure:"difficulty"`
+}
+
+// AIQuestionsConfig содержит настройки генерации вопросов языковой моделью
+// (POST /api/admin/question-generations)
+type AIQuestionsConfig struct {
+	Enabled                bool    `mapstructure:"enabled"`
+	Provider               string  `mapstructure:"provider"` // openai (и совместимые API) или anthropic
+	BaseURL                string  `mapstructure:"baseURL"`  // пусто — адрес API провайдера
+	APIKey                 string  `mapstructure:"apiKey"`
+	Model                  string  `mapstructure:"model"`
+	MaxTokens              int     `mapstructure:"maxTokens"`             // предел токенов ответа модели
+	TimeoutSec             int     `mapstructure:"timeoutSec"`            // генерация идёт в фоне, таймаут может быть больше writeTimeout
+	InputPricePerMillion   float64 `mapstructure:"inputPricePerMillion"`  // USD за 1M токенов запроса
+	OutputPricePerMillion  float64 `mapstructure:"outputPricePerMillion"` // USD за 1M токенов ответа
+	Language               string  `mapstructure:"language"`              // язык вопросов по умолчанию: ru, kk или en
+	MaxQuestionsPerRequest int     `mapstructure:"maxQuestionsPerRequest"`
+	DailyQuestionsPerAdmin int     `mapstructure:"dailyQuestionsPerAdmin"` // 0 — без ограничения
+	DailyCostPerAdminUSD   float64 `mapstructure:"dailyCostPerAdminUSD"`   // 0 — без ограничения
+}
+
+// TranslationAssistConfig содержит настройки машинного предзаполнения казахских переводов вопросов.
+// Переводы сохраняются с флагом machine_translated и ждут проверки администратором.
+type TranslationAssistConfig struct {
+	Enabled         bool                          `mapstructure:"enabled"`
+	Provider        string                        `mapstructure:"provider"`        // google или llm (модель из секции aiQuestions)
+	IntervalMinutes int                           `mapstructure:"intervalMinutes"` // 0 — только запуск из админ-панели
+	BatchSize       int                           `mapstructure:"batchSize"`       // вопросов за один проход
+	TimeoutSec      int                           `mapstructure:"timeoutSec"`      // таймаут перевода одного вопроса
+	Google          TranslationAssistGoogleConfig `mapstructure:"google"`
+}
+
+// TranslationAssistGoogleConfig содержит параметры Google Cloud Translation API (v2)
+type TranslationAssistGoogleConfig struct {
+	APIKey  string `mapstructure:"apiKey"`
+	BaseURL string `mapstructure:"baseURL"` // пусто — https://translation.googleapis.com
+}
+
+// ModerationConfig содержит настройки модерации пользовательского текста (пакет internal/pkg/moderation)
+type ModerationConfig struct {
+	Enabled      bool     `mapstructure:"enabled"`
+	WordlistsDir string   `mapstructure:"wordlistsDir"` // каталог с <locale>.txt, заменяющими встроенные списки; пусто — только встроенные
+	Locales      []string `mapstructure:"locales"`      // проверяемые языки: текст сверяется со списками всех языков
+}
+
+// GeoConfig содержит настройки геолокации по IP (пакет internal/pkg/geo). Страна определяется
+// при входе и подключении к викторине; призы викторины с allowed_countries получают только
+// игроки из этих стран.
+type GeoConfig struct {
+	Enabled         bool             `mapstructure:"enabled"`
+	Provider        string           `mapstructure:"provider"`     // maxmind_db (локальный файл .mmdb) или maxmind_web
+	DatabasePath    string           `mapstructure:"databasePath"` // файл GeoIP2/GeoLite2 Country или City для maxmind_db
+	MaxMind         GeoMaxMindConfig `mapstructure:"maxmind"`
+	TimeoutMs       int              `mapstructure:"timeoutMs"`       // на определение страны одного адреса
+	CacheTTLMinutes int              `mapstructure:"cacheTTLMinutes"` // кэш ответов веб-сервиса; 0 — без кэша
+	AllowUnknown    bool             `mapstructure:"allowUnknown"`    // выдавать приз, если страну определить не удалось
+}
+
+// GeoMaxMindConfig содержит учётные данные веб-сервиса MaxMind GeoIP2
+type GeoMaxMindConfig struct {
+	AccountID  string `mapstructure:"accountID"`
+	LicenseKey string `mapstructure:"licenseKey"`
+	BaseURL    string `mapstructure:"baseURL"` // пусто — https://geoip.maxmind.com
+}
+
+// IPRiskConfig содержит настройки оценки риска адресов (пакет internal/pkg/iprisk). Победители,
+// подключавшиеся к викторине с адреса с оценкой не ниже Threshold, попадают в очередь проверки.
+type IPRiskConfig struct {
+	Enabled               bool                   `mapstructure:"enabled"`
+	ListsDir              string                 `mapstructure:"listsDir"`              // <категория>.txt (datacenter, vpn, tor) вместо встроенных списков
+	ReloadIntervalMinutes int                    `mapstructure:"reloadIntervalMinutes"` // перечитывать listsDir; 0 — только при старте
+	Threshold             int                    `mapstructure:"threshold"`             // оценка 1–100, с которой подключение рискованное
+	Action                string                 `mapstructure:"action"`                // flag — приз выплачивается, exclude — выплата ждёт решения администратора
+	Provider              string                 `mapstructure:"provider"`              // пусто — только списки; proxycheck
+	ProxyCheck            IPRiskProxyCheckConfig `mapstructure:"proxycheck"`
+	TimeoutMs             int                    `mapstructure:"timeoutMs"`       // на оценку одного адреса
+	CacheTTLMinutes       int                    `mapstructure:"cacheTTLMinutes"` // кэш ответов провайдера; 0 — без кэша
+}
+
+// IPRiskProxyCheckConfig содержит параметры API proxycheck.io
+type IPRiskProxyCheckConfig struct {
+	APIKey  string `mapstructure:"apiKey"`  // пусто — бесплатный лимит без ключа
+	BaseURL string `mapstructure:"baseURL"` // пусто — https://proxycheck.io
+}
+
+// RatingConfig содержит настройки рейтинга мастерства
+type RatingConfig struct {
+	Enabled            bool    `mapstructure:"enabled"`
+	Algorithm          string  `mapstructure:"algorithm"`          // elo
+	Initial            int     `mapstructure:"initial"`            // рейтинг новичка
+	KFactor            float64 `mapstructure:"kFactor"`            // максимальное изменение за викторину
+	ProvisionalKFactor float64 `mapstructure:"provisionalKFactor"` // K для первых provisionalGames викторин
+	ProvisionalGames   int     `mapstructure:"provisionalGames"`   // пока рейтинг считается предварительным
+	MinPlayers         int     `mapstructure:"minPlayers"`         // викторины с меньшим числом участников не меняют рейтинг
+}
+
+// DailyChallengeConfig содержит настройки вопроса дня
+type DailyChallengeConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	GraceMs int  `mapstructure:"graceMs"` // запас к лимиту времени вопроса на сетевую задержку
+}
+
+// AnnouncerConfig содержит настройки объявлений ведущего
+type AnnouncerConfig struct {
+	Enabled   bool                     `mapstructure:"enabled"`
+	Automatic []AutoAnnouncementConfig `mapstructure:"automatic"` // объявления каждой викторины, вдобавок к заданным администратором
+}
+
+// AutoAnnouncementConfig описывает автоматическое объявление ведущего
+type AutoAnnouncementConfig struct {
+	Trigger        string `mapstructure:"trigger"`        // before_start | after_question | before_finale
+	OffsetSec      int    `mapstructure:"offsetSec"`      // before_start: за сколько секунд до начала
+	QuestionNumber int    `mapstructure:"questionNumber"` // after_question: после какого вопроса
+	Message        string `mapstructure:"message"`        // шаблон с подстановками ({participants}, {prize_fund}, ...)
+}
+
+// StorageConfig содержит настройки хранилища загружаемых файлов
+type StorageConfig struct {
+	Backend  string          `mapstructure:"backend"`  // "local" или "s3"
+	LocalDir string          `mapstructure:"localDir"` // каталог для backend=local
+	LocalURL string          `mapstructure:"localURL"` // URL-префикс раздачи файлов для backend=local
+	S3       StorageS3Config `mapstructure:"s3"`
+}
+
+// StorageS3Config содержит параметры S3-совместимого хранилища (AWS S3, MinIO, GCS XML API)
+type StorageS3Config struct {
+	Endpoint  string `mapstructure:"endpoint"`
+	Region    string `mapstructure:"region"`
+	Bucket    string `mapstructure:"bucket"`
+	AccessKey string `mapstructure:"accessKey"`
+	SecretKey string `mapstructure:"secretKey"`
+	PathStyle bool   `mapstructure:"pathStyle"`
+	PublicURL string `mapstructure:"publicURL"` // базовый URL CDN; пусто — URL объекта в bucket
+}
+
+// TracingConfig содержит настройки OpenTelemetry-трейсинга (экспорт по OTLP/HTTP)
+type TracingConfig struct {
+	Enabled     bool    `mapstructure:"enabled"`
+	Endpoint    string  `mapstructure:"endpoint"`    // http://otel-collector:4318 или host:port; пусто — OTEL_EXPORTER_OTLP_* env
+	Insecure    bool    `mapstructure:"insecure"`    // без TLS, если в endpoint не указана схема
+	ServiceName string  `mapstructure:"serviceName"` // service.name в трейсах
+	SampleRatio float64 `mapstructure:"sampleRatio"` // доля трейсов в выборке (0..1]
+}
+
+// SecretsConfig задаёт источник секретов (см. resolveSecrets): значения из него заменяют
+// заданные в файле и переменных окружения
+type SecretsConfig struct {
+	Provider string             `mapstructure:"provider"` // "env" (по умолчанию), "file" или "vault"
+	Dir      string             `mapstructure:"dir"`      // каталог с файлами секретов для provider=file
+	Vault    SecretsVaultConfig `mapstructure:"vault"`
+}
+
+// SecretsVaultConfig содержит параметры HashiCorp Vault (KV v2) для provider=vault
+type SecretsVaultConfig struct {
+	Addr      string        `mapstructure:"addr"`
+	Token     string        `mapstructure:"token"`     // только через VAULT_TOKEN, не в файле
+	TokenFile string        `mapstructure:"tokenFile"` // токен от Vault Agent / Kubernetes auth
+	Namespace string        `mapstructure:"namespace"`
+	Mount     string        `mapstructure:"mount"`
+	Path      string        `mapstructure:"path"`
+	Timeout   time.Duration `mapstructure:"timeout"`
+}
+
+// QuizConfig содержит тайминги проведения викторин. Перечитывается без перезапуска (hot reload).
+type QuizConfig struct {
+	AnnouncementMinutes  int `mapstructure:"announcementMinutes"`  // за сколько минут анонсировать викторину
+	ReminderMinutes      int `mapstructure:"reminderMinutes"`      // за сколько минут push-напоминание (0 — выключено)
+	WaitingRoomMinutes   int `mapstructure:"waitingRoomMinutes"`   // за сколько минут открывать зал ожидания
+	CountdownSeconds     int `mapstructure:"countdownSeconds"`     // длительность обратного отсчёта
+	QuestionDelayMs      int `mapstructure:"questionDelayMs"`      // пауза перед отправкой вопроса
+	AnswerRevealDelayMs  int `mapstructure:"answerRevealDelayMs"`  // пауза перед показом правильного ответа
+	InterQuestionDelayMs int `mapstructure:"interQuestionDelayMs"` // пауза между вопросами
+	AdVideoBufferMs      int `mapstructure:"adVideoBufferMs"`      // запас на буферизацию видео-рекламы
+}
+
+// ReloadConfig содержит настройки перечитывания конфигурации без перезапуска.
+// По SIGHUP конфигурация перечитывается всегда; WatchFile добавляет отслеживание изменений файла.
+type ReloadConfig struct {
+	WatchFile bool `mapstructure:"watchFile"`
+}
+
+// RateLimitRule описывает лимит запросов группы маршрутов в скользящем окне.
+// Незаданные поля берутся из значений по умолчанию в коде; 0 отключает соответствующий лимит.
+type RateLimitRule struct {
+	Requests     *int          `mapstructure:"requests"`     // запросов с одного IP за окно
+	UserRequests *int          `mapstructure:"userRequests"` // запросов одного пользователя за окно
+	Window       time.Duration `mapstructure:"window"`       // например, 1m
+}
+
+// Группы маршрутов с собственными заголовками безопасности
+const (
+	SecurityHeadersGroupDefault = "default" // API и прочие маршруты
+	SecurityHeadersGroupAdmin   = "admin"   // статическая админ-панель /admin
+	SecurityHeadersGroupUploads = "uploads" // загруженные файлы (storage.localURL)
+)
+
+// SecurityHeadersRule задаёт заголовки безопасности группы маршрутов.
+// Незаданные поля берутся из значений группы по умолчанию в коде; пустая строка отключает заголовок.
+type SecurityHeadersRule struct {
+	ContentSecurityPolicy     *string `mapstructure:"contentSecurityPolicy"`   // {nonce} заменяется nonce запроса
+	StrictTransportSecurity   *string `mapstructure:"strictTransportSecurity"` // отправляется только по HTTPS
+	ContentTypeOptions        *string `mapstructure:"contentTypeOptions"`
+	FrameOptions              *string `mapstructure:"frameOptions"`
+	ReferrerPolicy            *string `mapstructure:"referrerPolicy"`
+	PermissionsPolicy         *string `mapstructure:"permissionsPolicy"`
+	CrossOriginResourcePolicy *string `mapstructure:"crossOriginResourcePolicy"`
 }
 
 type LegalConfig struct {
 	TOSVersion     string `mapstructure:"tosVersion"`
 	PrivacyVersion string `mapstructure:"privacyVersion"`
+	// RequireReacceptance — отвечать 428 legal_acceptance_required, пока пользователь
+	// не примет текущие версии документов (POST /api/auth/accept-legal)
+	RequireReacceptance bool `mapstructure:"requireReacceptance"`
 }
 
 // CORSConfig содержит настройки CORS (Cross-Origin Resource Sharing)
@@ -137,12 +776,16 @@ type CORSConfig struct {
 
 // WebSocketConfig содержит настройки WebSocket-подсистемы
 type WebSocketConfig struct {
-	Sharding ShardingConfig
-	Buffers  BuffersConfig
-	Priority PriorityConfig
-	Ping     PingConfig
-	Cluster  ClusterConfig
-	Limits   LimitsConfig
+	Sharding    ShardingConfig
+	Buffers     BuffersConfig
+	Priority    PriorityConfig
+	Ping        PingConfig
+	Cluster     ClusterConfig
+	Limits      LimitsConfig
+	Fanout      FanoutConfig
+	Replay      ReplayConfig
+	SSE         SSEConfig
+	AuthRefresh AuthRefreshConfig
 }
 
 // ShardingConfig содержит настройки шардирования
@@ -182,6 +825,21 @@ type ClusterConfig struct {
 	DirectChannel    string
 	MetricsChannel   string
 	MetricsInterval  int
+	// Provider — транспорт межузловых сообщений: redis (по умолчанию) или nats
+	Provider string
+	NATS     NATSConfig
+}
+
+// NATSConfig содержит настройки подключения к NATS для кластерного режима WebSocket
+type NATSConfig struct {
+	URL            string // Список серверов через запятую, например nats://nats-1:4222,nats://nats-2:4222
+	Name           string // Имя соединения, видимое в мониторинге NATS
+	Token          string
+	Username       string
+	Password       string
+	MaxReconnects  int // -1 — переподключаться бесконечно
+	ReconnectWait  int // Пауза между попытками переподключения в секундах
+	ConnectTimeout int // Тайм-аут подключения в секундах
 }
 
 // LimitsConfig содержит настройки ограничений
@@ -193,6 +851,35 @@ type LimitsConfig struct {
 	CleanupInterval     int
 }
 
+// FanoutConfig содержит настройки рассылки событий клиентам
+type FanoutConfig struct {
+	DebugLogging  bool // подробные логи по каждому клиенту и сообщению (только для отладки)
+	WriteBudgetMs int  // сколько миллисекунд клиент пишет накопившиеся сообщения подряд
+	MaxBatch      int  // сколько сообщений клиент забирает из очереди за один проход
+}
+
+// ReplayConfig содержит настройки журнала событий викторины для переподключившихся клиентов
+type ReplayConfig struct {
+	Enabled    bool
+	MaxEvents  int // сколько последних событий викторины хранится в журнале
+	TTLSeconds int // время жизни журнала после последнего события
+}
+
+// SSEConfig содержит настройки потока событий викторины по Server-Sent Events
+// (для сетей, где WebSocket заблокирован)
+type SSEConfig struct {
+	Enabled      bool
+	BufferSize   int // сколько событий может ждать отправки одному подписчику; при переполнении поток закрывается
+	KeepAliveSec int // интервал комментариев keep-alive, чтобы прокси не закрывали простаивающее соединение
+}
+
+// AuthRefreshConfig содержит настройки обновления авторизации WebSocket-соединений без переподключения
+type AuthRefreshConfig struct {
+	Enabled          bool
+	CheckIntervalSec int // как часто проверять, не отозвана ли авторизация подключённых клиентов
+	GraceSec         int // сколько ждать новый тикет после token_expiring, прежде чем закрыть соединение
+}
+
 // PostgresConnectionString формирует строку подключения к PostgreSQL
 func (d *DatabaseConfig) PostgresConnectionString() string {
 	return fmt.Sprintf(
@@ -201,109 +888,16 @@ func (d *DatabaseConfig) PostgresConnectionString() string {
 	)
 }
 
-// Load загружает конфигурацию из файла
+// Load загружает конфигурацию из файла и переменных окружения и проверяет её (см. Validate).
+// Отсутствующий файл допустим (всё задаётся через env), а файл с синтаксической ошибкой — нет:
+// приложение не должно молча стартовать на значениях по умолчанию.
 func Load(configPath string) (*Config, error) {
-	vip := viper.New() // Используем новый экземпляр Viper, чтобы избежать глобального состояния
-
-	// 1. Устанавливаем значения по умолчанию (если они нужны)
-	// vip.SetDefault("database.host", "localhost")
-
-	// 2. Привязываем переменные окружения ЯВНО
-	// Привязка для секции Database
-	vip.BindEnv("database.host", "DATABASE_HOST")
-	vip.BindEnv("database.port", "DATABASE_PORT")
-	vip.BindEnv("database.user", "DATABASE_USER")
-	vip.BindEnv("database.password", "DATABASE_PASSWORD")
-	vip.BindEnv("database.dbname", "DATABASE_DBNAME")
-	vip.BindEnv("database.sslmode", "DATABASE_SSLMODE")
-
-	// Привязка для секции Redis
-	vip.BindEnv("redis.mode", "REDIS_MODE")
-	vip.BindEnv("redis.addrs", "REDIS_ADDRS") // Для массива строк
-	vip.BindEnv("redis.addr", "REDIS_ADDR")   // Для одиночной строки
-	vip.BindEnv("redis.password", "REDIS_PASSWORD")
-	vip.BindEnv("redis.db", "REDIS_DB")
-	vip.BindEnv("redis.master_name", "REDIS_MASTER_NAME")
-
-	// Привязка для секции JWT
-	vip.BindEnv("jwt.accessTokenTTL", "JWT_ACCESS_TOKEN_TTL")
-	vip.BindEnv("jwt.expirationHrs", "JWT_EXPIRATIONHRS")
-	vip.BindEnv("jwt.wsTicketExpirySec", "JWT_WSTICKETEXPIRYSEC")
-	vip.BindEnv("jwt.cleanup_interval", "JWT_CLEANUP_INTERVAL")
-	vip.BindEnv("jwt.db_jwt_key_encryption_key", "DB_JWT_KEY_ENCRYPTION_KEY")
-
-	// Привязка для секции Auth
-	vip.BindEnv("auth.sessionLimit", "AUTH_SESSIONLIMIT")
-	vip.BindEnv("auth.refreshTokenLifetime", "AUTH_REFRESHTOKENLIFETIME")
-
-	// Привязка для секции Email
-	vip.BindEnv("email.provider", "EMAIL_PROVIDER")
-	vip.BindEnv("email.resendApiKey", "EMAIL_RESEND_API_KEY")
-	vip.BindEnv("email.from", "EMAIL_FROM")
-	vip.BindEnv("email.verificationCodeTTL", "EMAIL_VERIFICATION_CODE_TTL")
-	vip.BindEnv("email.resendCooldownSec", "EMAIL_VERIFICATION_RESEND_COOLDOWN_SEC")
-	vip.BindEnv("email.maxAttempts", "EMAIL_VERIFICATION_MAX_ATTEMPTS")
-	vip.BindEnv("email.codePepper", "EMAIL_VERIFICATION_CODE_PEPPER")
-
-	// Привязка для секции Google OAuth
-	vip.BindEnv("google_oauth.enabled", "GOOGLE_OAUTH_ENABLED")
-	vip.BindEnv("google_oauth.webClientID", "GOOGLE_WEB_CLIENT_ID")
-	vip.BindEnv("google_oauth.webClientSecret", "GOOGLE_WEB_CLIENT_SECRET")
-	vip.BindEnv("google_oauth.androidClientID", "GOOGLE_ANDROID_CLIENT_ID")
-	vip.BindEnv("google_oauth.iosClientID", "GOOGLE_IOS_CLIENT_ID")
-	vip.BindEnv("google_oauth.redirectURIWeb", "GOOGLE_WEB_REDIRECT_URI")
-
-	// Привязка для секции Apple Sign-In (readiness)
-	vip.BindEnv("apple_signin.enabled", "APPLE_SIGNIN_ENABLED")
-	vip.BindEnv("apple_signin.teamID", "APPLE_TEAM_ID")
-	vip.BindEnv("apple_signin.keyID", "APPLE_KEY_ID")
-	vip.BindEnv("apple_signin.bundleID", "APPLE_BUNDLE_ID")
-	vip.BindEnv("apple_signin.serviceID", "APPLE_SERVICE_ID")
-	vip.BindEnv("apple_signin.audience", "APPLE_AUDIENCE")
-
-	// Feature flags
-	vip.BindEnv("features.email_verification_enabled", "FEATURE_EMAIL_VERIFICATION_ENABLED")
-	vip.BindEnv("features.email_verification_soft_gate_enabled", "FEATURE_EMAIL_VERIFICATION_SOFT_GATE_ENABLED")
-	vip.BindEnv("features.google_oauth_enabled", "FEATURE_GOOGLE_OAUTH_ENABLED")
-	vip.BindEnv("features.apple_signin_enabled", "FEATURE_APPLE_SIGNIN_ENABLED")
-
-	// Legal versions
-	vip.BindEnv("legal.tosVersion", "LEGAL_TOS_VERSION")
-	vip.BindEnv("legal.privacyVersion", "LEGAL_PRIVACY_VERSION")
-
-	// Привязка для Server
-	vip.BindEnv("server.port", "SERVER_PORT")
-
-	// Привязка для WebSocket Cluster
-	vip.BindEnv("websocket.cluster.enabled", "WEBSOCKET_CLUSTER_ENABLED")
-
-	// Заменяем '.' на '_' в именах переменных окружения для AutomaticEnv (если используется)
-	// vip.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
-	// vip.AutomaticEnv() // Можно оставить или убрать, т.к. BindEnv уже сделан
-
-	// 3. Устанавливаем путь к файлу конфигурации
-	if configPath != "" {
-		vip.SetConfigFile(configPath)
-		// 4. Пытаемся прочитать файл конфигурации (не страшно, если его нет, т.к. есть BindEnv)
-		if err := vip.ReadInConfig(); err != nil {
-			if _, ok := err.(viper.ConfigFileNotFoundError); ok {
-				log.Printf("Файл конфигурации '%s' не найден, используются переменные окружения/умолчания.", configPath)
-			} else {
-				log.Printf("Предупреждение: не удалось прочитать файл конфигурации '%s': %v", configPath, err)
-			}
-		}
+	cfg, err := read(configPath)
+	if err != nil {
+		return nil, err
 	}
 
-	// 5. Анмаршалим конфигурацию (Viper объединит значения из файла и привязанных env vars)
-	var cfg Config
-	if err := vip.Unmarshal(&cfg); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
-	}
-	if !vip.IsSet("features.email_verification_soft_gate_enabled") {
-		cfg.Features.EmailVerificationSoftGateEnabled = cfg.Features.EmailVerificationEnabled
-	}
-
-	// 6. Логирование конфигурации (только в debug режиме)
+	// Логирование конфигурации (только в debug режиме)
 	if os.Getenv("GIN_MODE") != "release" {
 		log.Printf("--- Загруженные значения конфигурации ---")
 		log.Printf("Database Host: %s", cfg.Database.Host)
@@ -319,77 +913,93 @@ func Load(configPath string) (*Config, error) {
 		log.Printf("Email Verification Enabled: %t", cfg.Features.EmailVerificationEnabled)
 		log.Printf("Email Verification Soft Gate Enabled: %t", cfg.Features.EmailVerificationSoftGateEnabled)
 		log.Printf("Google OAuth Enabled: %t", cfg.Features.GoogleOAuthEnabled)
+		log.Printf("Magic Link Enabled: %t", cfg.Features.MagicLinkEnabled)
+		log.Printf("WebAuthn Enabled: %t", cfg.Features.WebAuthnEnabled)
 		log.Printf("Apple Sign-In Enabled: %t", cfg.Features.AppleSignInEnabled)
+		log.Printf("Referrals Enabled: %t", cfg.Features.ReferralsEnabled)
+		log.Printf("Push Notifications Enabled: %t", cfg.Features.PushNotificationsEnabled)
+		log.Printf("Wallet Enabled: %t", cfg.Features.WalletEnabled)
+		log.Printf("Storage Backend: %s", cfg.Storage.Backend)
+		log.Printf("Tracing Enabled: %t (endpoint: %s)", cfg.Tracing.Enabled, cfg.Tracing.Endpoint)
+		log.Printf("Secrets Provider: %s", cfg.Secrets.Provider)
+		log.Printf("Quiz Timing: countdown=%ds, waiting room=%dm, announcement=%dm", cfg.Quiz.CountdownSeconds, cfg.Quiz.WaitingRoomMinutes, cfg.Quiz.AnnouncementMinutes)
 		log.Printf("Server Port: %s", cfg.Server.Port)
+		log.Printf("gRPC Enabled: %t (port: %s)", cfg.GRPC.Enabled, cfg.GRPC.Port)
 		log.Printf("Websocket Cluster Enabled: %t", cfg.WebSocket.Cluster.Enabled)
 		log.Printf("-----------------------------------------")
 	}
 
-	// 7. Проверка обязательных параметров
-	if cfg.JWT.DBJWTKeyEncryptionKey == "" {
-		return nil, fmt.Errorf("DB JWT key encryption key is required in config (check DB_JWT_KEY_ENCRYPTION_KEY env var)")
+	production := isProduction()
+	if err := cfg.Validate(production); err != nil {
+		return nil, fmt.Errorf("invalid configuration:\n%w", err)
 	}
-	if cfg.Database.Host == "" || cfg.Database.DBName == "" || cfg.Database.User == "" {
-		return nil, fmt.Errorf("database configuration (host, dbname, user) is incomplete in config (check DATABASE_HOST, DATABASE_DBNAME, DATABASE_USER env vars)")
-	}
-	if cfg.Features.EmailVerificationEnabled {
-		if cfg.Email.Provider == "" || cfg.Email.From == "" {
-			return nil, fmt.Errorf("email verification is enabled but email provider/from are not configured")
-		}
-		if cfg.Email.Provider == "resend" && cfg.Email.ResendAPIKey == "" {
-			return nil, fmt.Errorf("email verification is enabled with resend provider but EMAIL_RESEND_API_KEY is missing")
-		}
-		if cfg.Email.VerificationTTL <= 0 {
-			cfg.Email.VerificationTTL = 15 * time.Minute
-		}
-		if cfg.Email.ResendCooldownSec <= 0 {
-			cfg.Email.ResendCooldownSec = 60
+
+	// Секреты открытым текстом в файле: при внешнем источнике секретов это ошибка, иначе — предупреждение в production
+	if keys := plaintextSecrets(configPath); len(keys) > 0 {
+		if cfg.Secrets.Provider == "file" || cfg.Secrets.Provider == "vault" {
+			return nil, fmt.Errorf("secrets provider is %q but config file %s contains plaintext secrets: %v", cfg.Secrets.Provider, configPath, keys)
 		}
-		if cfg.Email.MaxAttempts <= 0 {
-			cfg.Email.MaxAttempts = 5
+		if production {
+			log.Printf("Warning: config file %s contains plaintext secrets %v; use secrets.provider=file or vault", configPath, keys)
 		}
 	}
-	if cfg.Features.GoogleOAuthEnabled {
-		if cfg.Google.WebClientID == "" {
-			return nil, fmt.Errorf("google oauth is enabled but GOOGLE_WEB_CLIENT_ID is missing")
+
+	if production {
+		// Пароль Redis не обязателен (локальный инстанс без пароля), но в production его отсутствие подозрительно
+		if cfg.Redis.Password == "" && cfg.Redis.Mode != "single" && len(cfg.Redis.Addrs) > 0 {
+			log.Println("Warning: Redis is configured but REDIS_PASSWORD is not set in a non-debug environment.")
 		}
-		if cfg.Google.RedirectURIWeb == "" {
-			return nil, fmt.Errorf("google oauth is enabled but GOOGLE_WEB_REDIRECT_URI is missing")
+	}
+
+	return cfg, nil
+}
+
+// read читает файл конфигурации, накладывает переменные окружения и значения по умолчанию без проверки
+func read(configPath string) (*Config, error) {
+	vip := viper.New() // Используем новый экземпляр Viper, чтобы избежать глобального состояния
+
+	// 1. Значения по умолчанию для параметров, которых может не быть в файле
+	setDefaults(vip)
+
+	// 2. Привязываем переменные окружения для каждого поля (см. bindEnvs; исторические имена — envAliases)
+	bindEnvs(vip, reflect.TypeOf(Config{}), "")
+
+	// 3. Читаем файл конфигурации: его отсутствие не ошибка, а некорректное содержимое — ошибка
+	if configPath != "" {
+		vip.SetConfigFile(configPath)
+		if err := vip.ReadInConfig(); err != nil {
+			var notFound viper.ConfigFileNotFoundError
+			if errors.As(err, &notFound) || errors.Is(err, fs.ErrNotExist) {
+				log.Printf("Файл конфигурации '%s' не найден, используются переменные окружения/умолчания.", configPath)
+			} else {
+				return nil, fmt.Errorf("failed to read config file %s: %w", configPath, err)
+			}
 		}
 	}
-	if cfg.Legal.TOSVersion == "" {
-		cfg.Legal.TOSVersion = "1.0"
+
+	// 4. Анмаршалим конфигурацию (Viper объединит значения из файла и привязанных env vars)
+	var cfg Config
+	if err := vip.Unmarshal(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
-	if cfg.Legal.PrivacyVersion == "" {
-		cfg.Legal.PrivacyVersion = "1.0"
+	if !vip.IsSet("features.email_verification_soft_gate_enabled") {
+		cfg.Features.EmailVerificationSoftGateEnabled = cfg.Features.EmailVerificationEnabled
 	}
-	// Проверяем пароли для БД и Redis, если приложение не в режиме разработки (например, в production)
-	// Для этого нам нужен способ определить режим. Сначала проверяем env var напрямую, потом viper.
-	ginMode := os.Getenv("GIN_MODE")
-	if ginMode == "" {
-		ginMode = vip.GetString("GIN_MODE")
+
+	// 5. Подставляем секреты из внешнего источника (env, файлы, Vault)
+	if err := cfg.resolveSecrets(); err != nil {
+		return nil, err
 	}
+	cfg.applyDefaults()
+
+	return &cfg, nil
+}
+
+// isProduction определяет, запущено ли приложение не в режиме разработки (release, test и т.д.)
+func isProduction() bool {
+	ginMode := os.Getenv("GIN_MODE")
 	if ginMode == "" {
 		ginMode = "debug" // fallback для локальной разработки
 	}
-	if ginMode != "debug" { // Если не debug (т.е. release, test и т.д.), считаем production-like
-		if cfg.Database.Password == "" {
-			return nil, fmt.Errorf("database password is required in production mode (check DATABASE_PASSWORD env var)")
-		}
-		if cfg.Redis.Password == "" && cfg.Redis.Mode != "single" && len(cfg.Redis.Addrs) > 0 {
-			// Пароль для Redis может быть не нужен, если Redis не настроен или это локальный инстанс без пароля.
-			// Более точная проверка может зависеть от того, как используется Redis.
-			// Здесь проверяем, что если Redis сконфигурирован (addrs не пуст и не single mode по умолчанию без адреса), то пароль должен быть.
-			// Для простоты, если REDIS_PASSWORD пуст, но REDIS_ADDRS задан, будем требовать пароль в non-debug.
-			// Если cfg.Redis.Addrs не пуст или cfg.Redis.Addr не пуст - значит Redis используется.
-			isRedisConfigured := len(cfg.Redis.Addrs) > 0 || cfg.Redis.Addr != ""
-			if isRedisConfigured && cfg.Redis.Password == "" {
-				log.Println("Warning: Redis is configured but REDIS_PASSWORD is not set in a non-debug environment.")
-				// Можно сделать это фатальной ошибкой, если требуется:
-				// return nil, fmt.Errorf("Redis password is required in non-debug mode when Redis is configured (check REDIS_PASSWORD env var)")
-			}
-		}
-	}
-
-	return &cfg, nil
+	return ginMode != "debug"
 }
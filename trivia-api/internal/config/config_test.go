@@ -0,0 +1,267 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfigYAML = `
+server:
+  port: "8080"
+database:
+  host: localhost
+  user: postgres
+  dbname: trivia_db
+redis:
+  addr: localhost:6379
+jwt:
+  accessTokenTTL: 15m
+  db_jwt_key_encryption_key: test-key
+cors:
+  allowed_origins:
+    - https://example.com
+rateLimits:
+  auth:
+    requests: 20
+    window: 1m
+quiz:
+  countdownSeconds: 60
+`
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	t.Setenv("GIN_MODE", "debug")
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestEnvName(t *testing.T) {
+	cases := map[string]string{
+		"database.host":                       "DATABASE_HOST",
+		"jwt.accessTokenTTL":                  "JWT_ACCESS_TOKEN_TTL",
+		"websocket.limits.maxMessageSize":     "WEBSOCKET_LIMITS_MAX_MESSAGE_SIZE",
+		"google_oauth.webClientID":            "GOOGLE_OAUTH_WEB_CLIENT_ID",
+		"storage.s3.accessKey":                "STORAGE_S3_ACCESS_KEY",
+		"features.email_verification_enabled": "FEATURES_EMAIL_VERIFICATION_ENABLED",
+	}
+	for key, want := range cases {
+		assert.Equal(t, want, envName(key), key)
+	}
+	assert.Equal(t, "dbName", lowerCamel("DBName"))
+	assert.Equal(t, "shardCount", lowerCamel("ShardCount"))
+}
+
+func TestLoad_EnvOverridesFile(t *testing.T) {
+	path := writeConfig(t, testConfigYAML)
+	t.Setenv("QUIZ_COUNTDOWN_SECONDS", "45")
+	t.Setenv("WEBSOCKET_LIMITS_MAX_MESSAGE_SIZE", "1024")
+	t.Setenv("DATABASE_DBNAME", "legacy_db")
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, 45, cfg.Quiz.CountdownSeconds)
+	assert.Equal(t, 1024, cfg.WebSocket.Limits.MaxMessageSize)
+	assert.Equal(t, "legacy_db", cfg.Database.DBName)
+	// Незаданные в файле тайминги берутся из значений по умолчанию
+	assert.Equal(t, 30, cfg.Quiz.AnnouncementMinutes)
+}
+
+func TestLoad_FailsOnMalformedFile(t *testing.T) {
+	path := writeConfig(t, "server: [unclosed")
+	_, err := Load(path)
+	assert.Error(t, err)
+}
+
+func TestValidate_CollectsAllErrors(t *testing.T) {
+	path := writeConfig(t, testConfigYAML)
+	cfg, err := read(path)
+	require.NoError(t, err)
+	require.NoError(t, cfg.Validate(false))
+
+	cfg.JWT.AccessTokenTTL = "15 minutes"
+	cfg.JWT.DBJWTKeyEncryptionKey = ""
+	cfg.CORS.AllowedOrigins = []string{"example.com"}
+	cfg.Tracing.SampleRatio = 2
+
+	err = cfg.Validate(false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "jwt.accessTokenTTL")
+	assert.Contains(t, err.Error(), "DB_JWT_KEY_ENCRYPTION_KEY")
+	assert.Contains(t, err.Error(), "cors.allowed_origins")
+	assert.Contains(t, err.Error(), "tracing.sampleRatio")
+
+	cfg.CORS.AllowedOrigins = nil
+	assert.ErrorContains(t, cfg.Validate(false), "must not be empty")
+}
+
+func TestValidate_ProductionRequiresDatabasePassword(t *testing.T) {
+	path := writeConfig(t, testConfigYAML)
+	cfg, err := read(path)
+	require.NoError(t, err)
+
+	assert.ErrorContains(t, cfg.Validate(true), "database password")
+	cfg.Database.Password = "secret"
+	assert.NoError(t, cfg.Validate(true))
+}
+
+func TestLoad_DevSeed(t *testing.T) {
+	path := writeConfig(t, testConfigYAML)
+	t.Setenv("DEV_SEED", "true")
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.True(t, cfg.DevSeed)
+
+	cfg.Database.Password = "secret"
+	assert.ErrorContains(t, cfg.Validate(true), "devSeed")
+}
+
+func TestValidate_ClusterProvider(t *testing.T) {
+	path := writeConfig(t, testConfigYAML)
+	cfg, err := read(path)
+	require.NoError(t, err)
+	assert.Equal(t, "redis", cfg.WebSocket.Cluster.Provider)
+
+	cfg.WebSocket.Cluster.Provider = "nats"
+	require.NoError(t, cfg.Validate(false))
+	cfg.WebSocket.Cluster.NATS.URL = ""
+	assert.ErrorContains(t, cfg.Validate(false), "websocket.cluster.nats.url")
+
+	cfg.WebSocket.Cluster.Provider = "kafka"
+	assert.ErrorContains(t, cfg.Validate(false), "websocket.cluster.provider")
+}
+
+func TestLoad_SecurityHeadersOverrides(t *testing.T) {
+	path := writeConfig(t, testConfigYAML+`
+securityHeaders:
+  uploads:
+    frameOptions: ""
+    crossOriginResourcePolicy: cross-origin
+`)
+	cfg, err := read(path)
+	require.NoError(t, err)
+	require.NoError(t, cfg.Validate(false))
+
+	rule := cfg.SecurityHeaders[SecurityHeadersGroupUploads]
+	require.NotNil(t, rule.FrameOptions, "пустая строка отключает заголовок, а не наследует значение")
+	assert.Equal(t, "", *rule.FrameOptions)
+	assert.Equal(t, "cross-origin", *rule.CrossOriginResourcePolicy)
+	assert.Nil(t, rule.ContentSecurityPolicy)
+
+	hsts := "31536000"
+	cfg.SecurityHeaders["static"] = SecurityHeadersRule{StrictTransportSecurity: &hsts}
+	err = cfg.Validate(false)
+	assert.ErrorContains(t, err, "securityHeaders.static: unknown route group")
+	assert.ErrorContains(t, err, "securityHeaders.static.strictTransportSecurity")
+}
+
+func TestLoad_PurchaseProducts(t *testing.T) {
+	path := writeConfig(t, testConfigYAML+`
+purchases:
+  enabled: true
+  products:
+    - id: com.example.trivia.premium.monthly
+      entitlements: [premium, ad_free, extra_lifelines]
+      subscription: true
+    - id: com.example.trivia.adfree
+      entitlements: [ad_free]
+  google:
+    packageName: com.example.trivia
+    serviceAccountFile: /etc/trivia/play.json
+`)
+	t.Setenv("PURCHASES_GOOGLE_NOTIFICATION_TOKEN", "pubsub-secret")
+	cfg, err := read(path)
+	require.NoError(t, err)
+	require.NoError(t, cfg.Validate(false))
+
+	require.Len(t, cfg.Purchases.Products, 2)
+	assert.Equal(t, "com.example.trivia.premium.monthly", cfg.Purchases.Products[0].ID, "точки в идентификаторе сохраняются")
+	assert.True(t, cfg.Purchases.Products[0].Subscription)
+	assert.Equal(t, []string{"ad_free"}, cfg.Purchases.Products[1].Entitlements)
+	assert.Equal(t, "pubsub-secret", cfg.Purchases.Google.NotificationToken)
+
+	cfg.Purchases.Products = append(cfg.Purchases.Products, cfg.Purchases.Products[1])
+	cfg.Purchases.Apple.IssuerID = "issuer"
+	err = cfg.Validate(false)
+	assert.ErrorContains(t, err, "listed more than once")
+	assert.ErrorContains(t, err, "purchases.apple requires")
+}
+
+func TestWatcher_ReloadAppliesOnlyReloadableSections(t *testing.T) {
+	path := writeConfig(t, testConfigYAML)
+	initial, err := Load(path)
+	require.NoError(t, err)
+
+	w := NewWatcher(path, initial)
+	var applied *Config
+	w.OnReload(func(c *Config) { applied = c })
+
+	updated := testConfigYAML + `
+  waitingRoomMinutes: 3
+`
+	updated = replaceOnce(t, updated, "requests: 20", "requests: 7")
+	updated = replaceOnce(t, updated, "host: localhost", "host: db.internal")
+	require.NoError(t, os.WriteFile(path, []byte(updated), 0o600))
+
+	require.NoError(t, w.Reload())
+	require.NotNil(t, applied)
+	assert.Equal(t, 7, *w.Current().RateLimits["auth"].Requests)
+	assert.Equal(t, 3, w.Current().Quiz.WaitingRoomMinutes)
+	// Подключение к БД меняется только перезапуском
+	assert.Equal(t, "localhost", w.Current().Database.Host)
+}
+
+func TestWatcher_InvalidReloadKeepsPreviousConfig(t *testing.T) {
+	path := writeConfig(t, testConfigYAML)
+	initial, err := Load(path)
+	require.NoError(t, err)
+	w := NewWatcher(path, initial)
+
+	invalid := replaceOnce(t, testConfigYAML, "countdownSeconds: 60", "countdownSeconds: 0")
+	require.NoError(t, os.WriteFile(path, []byte(invalid), 0o600))
+
+	assert.ErrorContains(t, w.Reload(), "quiz.countdownSeconds")
+	assert.Same(t, initial, w.Current())
+}
+
+func replaceOnce(t *testing.T, s, old, new string) string {
+	t.Helper()
+	require.Contains(t, s, old)
+	return strings.Replace(s, old, new, 1)
+}
+
+func TestLoad_SecretsFromFiles(t *testing.T) {
+	secretsDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(secretsDir, "db_jwt_key_encryption_key"), []byte("from-file\n"), 0o600))
+	withoutKey := replaceOnce(t, testConfigYAML, "  db_jwt_key_encryption_key: test-key\n", "")
+	path := writeConfig(t, withoutKey+"secrets:\n  provider: file\n  dir: "+secretsDir+"\n")
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "from-file", cfg.JWT.DBJWTKeyEncryptionKey)
+
+	// При внешнем источнике секретов хранить их в файле конфигурации нельзя
+	path = writeConfig(t, testConfigYAML+"secrets:\n  provider: file\n  dir: "+secretsDir+"\n")
+	_, err = Load(path)
+	assert.ErrorContains(t, err, "plaintext secrets")
+}
+
+func TestJWTConfig_DBKeyEncryptionKeys(t *testing.T) {
+	cfg, err := read(writeConfig(t, testConfigYAML))
+	require.NoError(t, err)
+	assert.Equal(t, 1, cfg.JWT.DBJWTKeyEncryptionKeyVersion)
+	assert.Equal(t, map[int]string{1: "test-key"}, cfg.JWT.DBKeyEncryptionKeys())
+
+	// Прежний ключ получает предыдущую версию
+	cfg.JWT.DBJWTKeyPreviousEncryptionKey = "old-key"
+	assert.ErrorContains(t, cfg.Validate(false), "db_jwt_key_encryption_key_version of 2 or more")
+	cfg.JWT.DBJWTKeyEncryptionKeyVersion = 2
+	require.NoError(t, cfg.Validate(false))
+	assert.Equal(t, map[int]string{1: "old-key", 2: "test-key"}, cfg.JWT.DBKeyEncryptionKeys())
+}
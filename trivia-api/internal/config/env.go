@@ -0,0 +1,124 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/spf13/viper"
+)
+
+// envAliases — исторические имена переменных окружения, которые отличаются от
+// автоматически сформированных (см. envName). Поддерживаются для обратной совместимости.
+var envAliases = map[string][]string{
+	"database.dbname":                               {"DATABASE_DBNAME"},
+	"database.sslmode":                              {"DATABASE_SSLMODE"},
+	"jwt.expirationHrs":                             {"JWT_EXPIRATIONHRS"},
+	"jwt.wsTicketExpirySec":                         {"JWT_WSTICKETEXPIRYSEC"},
+	"jwt.db_jwt_key_encryption_key":                 {"DB_JWT_KEY_ENCRYPTION_KEY"},
+	"jwt.db_jwt_key_encryption_key_version":         {"DB_JWT_KEY_ENCRYPTION_KEY_VERSION"},
+	"jwt.db_jwt_key_previous_encryption_key":        {"DB_JWT_KEY_PREVIOUS_ENCRYPTION_KEY"},
+	"auth.sessionLimit":                             {"AUTH_SESSIONLIMIT"},
+	"auth.refreshTokenLifetime":                     {"AUTH_REFRESHTOKENLIFETIME"},
+	"email.resendCooldownSec":                       {"EMAIL_VERIFICATION_RESEND_COOLDOWN_SEC"},
+	"email.maxAttempts":                             {"EMAIL_VERIFICATION_MAX_ATTEMPTS"},
+	"email.codePepper":                              {"EMAIL_VERIFICATION_CODE_PEPPER"},
+	"google_oauth.webClientID":                      {"GOOGLE_WEB_CLIENT_ID"},
+	"google_oauth.webClientSecret":                  {"GOOGLE_WEB_CLIENT_SECRET"},
+	"google_oauth.androidClientID":                  {"GOOGLE_ANDROID_CLIENT_ID"},
+	"google_oauth.iosClientID":                      {"GOOGLE_IOS_CLIENT_ID"},
+	"google_oauth.redirectURIWeb":                   {"GOOGLE_WEB_REDIRECT_URI"},
+	"apple_signin.teamID":                           {"APPLE_TEAM_ID"},
+	"apple_signin.keyID":                            {"APPLE_KEY_ID"},
+	"apple_signin.bundleID":                         {"APPLE_BUNDLE_ID"},
+	"apple_signin.serviceID":                        {"APPLE_SERVICE_ID"},
+	"apple_signin.audience":                         {"APPLE_AUDIENCE"},
+	"features.email_verification_enabled":           {"FEATURE_EMAIL_VERIFICATION_ENABLED"},
+	"features.email_verification_soft_gate_enabled": {"FEATURE_EMAIL_VERIFICATION_SOFT_GATE_ENABLED"},
+	"features.google_oauth_enabled":                 {"FEATURE_GOOGLE_OAUTH_ENABLED"},
+	"features.apple_signin_enabled":                 {"FEATURE_APPLE_SIGNIN_ENABLED"},
+	"features.magic_link_enabled":                   {"FEATURE_MAGIC_LINK_ENABLED"},
+	"features.webauthn_enabled":                     {"FEATURE_WEBAUTHN_ENABLED"},
+	"features.referrals_enabled":                    {"FEATURE_REFERRALS_ENABLED"},
+	"features.push_notifications_enabled":           {"FEATURE_PUSH_NOTIFICATIONS_ENABLED"},
+	"features.wallet_enabled":                       {"FEATURE_WALLET_ENABLED"},
+	"secrets.vault.addr":                            {"VAULT_ADDR"},
+	"secrets.vault.token":                           {"VAULT_TOKEN"},
+	"secrets.vault.namespace":                       {"VAULT_NAMESPACE"},
+}
+
+// bindEnvs привязывает к переменным окружения каждое поле конфигурации.
+// Имя переменной строится из пути ключа: database.host → DATABASE_HOST,
+// jwt.accessTokenTTL → JWT_ACCESS_TOKEN_TTL, websocket.limits.maxMessageSize → WEBSOCKET_LIMITS_MAX_MESSAGE_SIZE.
+// Поля-карты (rateLimits) задаются только в файле: их ключи заранее неизвестны.
+func bindEnvs(vip *viper.Viper, t reflect.Type, prefix string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Tag.Get("mapstructure")
+		if name == "" {
+			name = lowerCamel(field.Name)
+		}
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+
+		ft := field.Type
+		switch {
+		case ft.Kind() == reflect.Map:
+			continue
+		case ft.Kind() == reflect.Struct && ft != reflect.TypeOf(time.Duration(0)):
+			bindEnvs(vip, ft, key)
+			continue
+		}
+
+		names := []string{envName(key)}
+		for aliasKey, aliases := range envAliases {
+			if strings.EqualFold(aliasKey, key) {
+				names = append(names, aliases...)
+			}
+		}
+		vip.BindEnv(append([]string{key}, names...)...)
+	}
+}
+
+// lowerCamel приводит имя поля без тега к виду ключей config.yaml: ShardCount → shardCount, DBName → dbName
+func lowerCamel(name string) string {
+	runes := []rune(name)
+	for i := range runes {
+		if !unicode.IsUpper(runes[i]) {
+			break
+		}
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+// envName переводит путь ключа в имя переменной окружения (UPPER_SNAKE_CASE)
+func envName(key string) string {
+	var b strings.Builder
+	runes := []rune(key)
+	for i, r := range runes {
+		switch {
+		case r == '.' || r == '_' || r == '-':
+			b.WriteByte('_')
+			continue
+		case unicode.IsUpper(r) && i > 0:
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			// Граница слова: aB → A_B, а в аббревиатурах — перед последней заглавной (TTLValue → TTL_VALUE)
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/yourusername/trivia-api/internal/pkg/secrets"
+)
+
+// secretField связывает имя секрета у провайдера с полем конфигурации.
+// Имя секрета — имя файла для provider=file, поле записи в Vault, а в верхнем регистре — переменная окружения.
+type secretField struct {
+	name  string
+	key   string // ключ в config.yaml
+	field func(*Config) *string
+}
+
+var secretFields = []secretField{
+	{"db_jwt_key_encryption_key", "jwt.db_jwt_key_encryption_key", func(c *Config) *string { return &c.JWT.DBJWTKeyEncryptionKey }},
+	{"db_jwt_key_previous_encryption_key", "jwt.db_jwt_key_previous_encryption_key", func(c *Config) *string { return &c.JWT.DBJWTKeyPreviousEncryptionKey }},
+	{"database_password", "database.password", func(c *Config) *string { return &c.Database.Password }},
+	{"redis_password", "redis.password", func(c *Config) *string { return &c.Redis.Password }},
+	{"grpc_auth_token", "grpc.authToken", func(c *Config) *string { return &c.GRPC.AuthToken }},
+	{"websocket_cluster_nats_token", "websocket.cluster.nats.token", func(c *Config) *string { return &c.WebSocket.Cluster.NATS.Token }},
+	{"websocket_cluster_nats_password", "websocket.cluster.nats.password", func(c *Config) *string { return &c.WebSocket.Cluster.NATS.Password }},
+	{"email_resend_api_key", "email.resendApiKey", func(c *Config) *string { return &c.Email.ResendAPIKey }},
+	{"email_resend_webhook_secret", "email.resendWebhookSecret", func(c *Config) *string { return &c.Email.ResendWebhookSecret }},
+	{"email_smtp_password", "email.smtp.password", func(c *Config) *string { return &c.Email.SMTP.Password }},
+	{"email_ses_secret_key", "email.ses.secretKey", func(c *Config) *string { return &c.Email.SES.SecretKey }},
+	{"email_ses_callback_token", "email.ses.callbackToken", func(c *Config) *string { return &c.Email.SES.CallbackToken }},
+	{"email_code_pepper", "email.codePepper", func(c *Config) *string { return &c.Email.CodePepper }},
+	{"magic_link_secret", "magicLink.secret", func(c *Config) *string { return &c.MagicLink.Secret }},
+	{"share_cards_secret", "shareCards.secret", func(c *Config) *string { return &c.ShareCards.Secret }},
+	{"warehouse_auth_token", "warehouse.authToken", func(c *Config) *string { return &c.Warehouse.AuthToken }},
+	{"statistics_clickhouse_password", "statistics.clickhouse.password", func(c *Config) *string { return &c.Statistics.ClickHouse.Password }},
+	{"google_web_client_secret", "google_oauth.webClientSecret", func(c *Config) *string { return &c.Google.WebClientSecret }},
+	{"storage_s3_access_key", "storage.s3.accessKey", func(c *Config) *string { return &c.Storage.S3.AccessKey }},
+	{"storage_s3_secret_key", "storage.s3.secretKey", func(c *Config) *string { return &c.Storage.S3.SecretKey }},
+	{"ai_questions_api_key", "aiQuestions.apiKey", func(c *Config) *string { return &c.AIQuestions.APIKey }},
+	{"translation_assist_google_api_key", "translationAssist.google.apiKey", func(c *Config) *string { return &c.TranslationAssist.Google.APIKey }},
+	{"geo_maxmind_license_key", "geo.maxmind.licenseKey", func(c *Config) *string { return &c.Geo.MaxMind.LicenseKey }},
+	{"ip_risk_proxycheck_api_key", "ipRisk.proxycheck.apiKey", func(c *Config) *string { return &c.IPRisk.ProxyCheck.APIKey }},
+}
+
+// newSecretsProvider создаёт провайдер секретов по секции secrets
+func newSecretsProvider(cfg SecretsConfig) (secrets.Provider, error) {
+	switch cfg.Provider {
+	case "", "env":
+		return secrets.EnvProvider{}, nil
+	case "file":
+		return secrets.FileProvider{Dir: cfg.Dir}, nil
+	case "vault":
+		return secrets.NewVaultProvider(secrets.VaultConfig{
+			Addr:      cfg.Vault.Addr,
+			Token:     cfg.Vault.Token,
+			TokenFile: cfg.Vault.TokenFile,
+			Namespace: cfg.Vault.Namespace,
+			Mount:     cfg.Vault.Mount,
+			Path:      cfg.Vault.Path,
+			Timeout:   cfg.Vault.Timeout,
+		})
+	default:
+		return nil, fmt.Errorf("unknown secrets provider %q", cfg.Provider)
+	}
+}
+
+// resolveSecrets заменяет секреты значениями из провайдера. Секреты, которых у провайдера нет,
+// остаются такими, как заданы в файле или переменных окружения.
+func (c *Config) resolveSecrets() error {
+	provider, err := newSecretsProvider(c.Secrets)
+	if err != nil {
+		return fmt.Errorf("failed to initialize secrets provider: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	for _, sf := range secretFields {
+		value, err := provider.Get(ctx, sf.name)
+		if errors.Is(err, secrets.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load secret %s from %s provider: %w", sf.name, c.Secrets.Provider, err)
+		}
+		*sf.field(c) = value
+	}
+	return nil
+}
+
+// plaintextSecrets возвращает ключи секретов, заданные непустыми значениями прямо в файле конфигурации
+// (без учёта переменных окружения)
+func plaintextSecrets(configPath string) []string {
+	if configPath == "" {
+		return nil
+	}
+	fileVip := viper.New()
+	fileVip.SetConfigFile(configPath)
+	if err := fileVip.ReadInConfig(); err != nil {
+		return nil
+	}
+
+	var keys []string
+	for _, sf := range secretFields {
+		if fileVip.GetString(sf.key) != "" {
+			keys = append(keys, sf.key)
+		}
+	}
+	if fileVip.GetString("secrets.vault.token") != "" {
+		keys = append(keys, "secrets.vault.token")
+	}
+	return keys
+}
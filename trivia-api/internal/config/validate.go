@@ -0,0 +1,832 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/yourusername/trivia-api/internal/pkg/i18n"
+)
+
+// setDefaults задаёт значения параметров, которые могут отсутствовать в config.yaml
+func setDefaults(vip *viper.Viper) {
+	vip.SetDefault("secrets.provider", "env")
+	vip.SetDefault("secrets.dir", "/run/secrets")
+	vip.SetDefault("secrets.vault.mount", "secret")
+	vip.SetDefault("quiz.announcementMinutes", 30)
+	vip.SetDefault("quiz.reminderMinutes", 10)
+	vip.SetDefault("quiz.waitingRoomMinutes", 5)
+	vip.SetDefault("quiz.countdownSeconds", 60)
+	vip.SetDefault("quiz.questionDelayMs", 500)
+	vip.SetDefault("quiz.answerRevealDelayMs", 200)
+	vip.SetDefault("quiz.interQuestionDelayMs", 500)
+	vip.SetDefault("quiz.adVideoBufferMs", 1500)
+	vip.SetDefault("websocket.fanout.writeBudgetMs", 50)
+	vip.SetDefault("websocket.fanout.maxBatch", 64)
+	vip.SetDefault("websocket.replay.enabled", true)
+	vip.SetDefault("websocket.replay.maxEvents", 50)
+	vip.SetDefault("websocket.replay.ttlSeconds", 600)
+	vip.SetDefault("websocket.sse.enabled", true)
+	vip.SetDefault("websocket.sse.bufferSize", 64)
+	vip.SetDefault("websocket.sse.keepAliveSec", 15)
+	vip.SetDefault("websocket.authRefresh.enabled", true)
+	vip.SetDefault("websocket.authRefresh.checkIntervalSec", 30)
+	vip.SetDefault("websocket.authRefresh.graceSec", 60)
+	vip.SetDefault("websocket.cluster.provider", "redis")
+	vip.SetDefault("localization.defaultLocale", "ru")
+	vip.SetDefault("localization.supportedLocales", []string{"ru", "kk"})
+	vip.SetDefault("grpc.enabled", false)
+	vip.SetDefault("database.maxOpenConns", 25)
+	vip.SetDefault("database.maxIdleConns", 10)
+	vip.SetDefault("database.connMaxLifetimeMinutes", 60)
+	vip.SetDefault("database.connMaxIdleTimeMinutes", 10)
+	vip.SetDefault("database.slowQueryMs", 200)
+	vip.SetDefault("database.logLevel", "info")
+	vip.SetDefault("httpCache.enabled", true)
+	vip.SetDefault("httpCache.ttlSeconds", 30)
+	vip.SetDefault("answerBuffer.enabled", true)
+	vip.SetDefault("answerBuffer.batchSize", 500)
+	vip.SetDefault("answerBuffer.flushIntervalMs", 200)
+	vip.SetDefault("partitioning.retentionDays", 0)
+	vip.SetDefault("partitioning.checkIntervalHours", 24)
+	vip.SetDefault("maintenance.enabled", false)
+	vip.SetDefault("maintenance.retryAfterSec", 300)
+	vip.SetDefault("webhooks.enabled", false)
+	vip.SetDefault("webhooks.maxAttempts", 8)
+	vip.SetDefault("webhooks.timeoutSec", 10)
+	vip.SetDefault("webhooks.pollIntervalSec", 5)
+	vip.SetDefault("webhooks.batchSize", 50)
+	vip.SetDefault("webhooks.retentionDays", 30)
+	vip.SetDefault("apiKeys.enabled", false)
+	vip.SetDefault("apiKeys.flushIntervalSec", 30)
+	vip.SetDefault("mobileAPI.v1DeprecatedAt", "2026-10-16")
+	vip.SetDefault("mobileAPI.statsFlushIntervalSec", 60)
+	vip.SetDefault("eventBus.pollIntervalMs", 1000)
+	vip.SetDefault("eventBus.batchSize", 100)
+	vip.SetDefault("eventBus.maxAttempts", 10)
+	vip.SetDefault("eventBus.retentionDays", 7)
+	vip.SetDefault("prizeClaims.enabled", false)
+	vip.SetDefault("prizeClaims.claimWindowHours", 72)
+	vip.SetDefault("prizeClaims.unclaimedPolicy", "redistribute")
+	vip.SetDefault("prizeClaims.checkIntervalMin", 10)
+	vip.SetDefault("auth.sessions.web.idleTimeout", 7*24*time.Hour)
+	vip.SetDefault("auth.sessions.web.absoluteLifetime", 30*24*time.Hour)
+	vip.SetDefault("auth.sessions.mobile.idleTimeout", 30*24*time.Hour)
+	vip.SetDefault("auth.sessions.mobile.absoluteLifetime", 180*24*time.Hour)
+	vip.SetDefault("jwt.db_jwt_key_encryption_key_version", 1)
+	vip.SetDefault("jwt.rotation.enabled", true)
+	vip.SetDefault("jwt.rotation.maxKeyAge", 30*24*time.Hour)
+	vip.SetDefault("jwt.rotation.retention", 7*24*time.Hour)
+	vip.SetDefault("jwt.rotation.checkInterval", time.Hour)
+	vip.SetDefault("auth.csrf.adminMode", CSRFModeDoubleSubmit)
+	vip.SetDefault("auth.csrf.nonceTTL", 12*time.Hour)
+	vip.SetDefault("auth.csrf.rotationGrace", 30*time.Second)
+	vip.SetDefault("purchases.enabled", false)
+	vip.SetDefault("purchases.apple.environment", "production")
+	vip.SetDefault("antiAbuse.maxAccountsPerDevice", 3)
+	vip.SetDefault("antiAbuse.overLimitAction", "shadow_ban")
+	vip.SetDefault("questionMedia.maxImageSizeMB", 5)
+	vip.SetDefault("questionMedia.maxAudioSizeMB", 10)
+	vip.SetDefault("questionMedia.urlTTLMinutes", 120)
+	vip.SetDefault("exports.pollIntervalSec", 5)
+	vip.SetDefault("exports.batchSize", 1000)
+	vip.SetDefault("exports.downloadURLTTLMinutes", 60)
+	vip.SetDefault("exports.retentionHours", 168)
+	vip.SetDefault("warehouse.sink", "http")
+	vip.SetDefault("warehouse.topic", "trivia-events")
+	vip.SetDefault("warehouse.dir", "./warehouse-events")
+	vip.SetDefault("warehouse.batchSize", 500)
+	vip.SetDefault("warehouse.flushIntervalMs", 2000)
+	vip.SetDefault("warehouse.bufferSize", 20000)
+	vip.SetDefault("warehouse.timeoutSec", 10)
+	vip.SetDefault("statistics.backend", "postgres")
+	vip.SetDefault("statistics.clickhouse.database", "default")
+	vip.SetDefault("statistics.clickhouse.user", "default")
+	vip.SetDefault("statistics.clickhouse.timeoutSec", 30)
+	vip.SetDefault("questionImport.enabled", false)
+	vip.SetDefault("questionImport.intervalHours", 24)
+	vip.SetDefault("questionImport.timeoutSec", 8)
+	vip.SetDefault("questionImport.opentdb.baseURL", "https://opentdb.com")
+	vip.SetDefault("aiQuestions.enabled", false)
+	vip.SetDefault("aiQuestions.provider", "openai")
+	vip.SetDefault("aiQuestions.model", "gpt-4o-mini")
+	vip.SetDefault("aiQuestions.maxTokens", 4000)
+	vip.SetDefault("aiQuestions.timeoutSec", 120)
+	vip.SetDefault("aiQuestions.language", "ru")
+	vip.SetDefault("aiQuestions.maxQuestionsPerRequest", 20)
+	vip.SetDefault("aiQuestions.dailyQuestionsPerAdmin", 100)
+	vip.SetDefault("aiQuestions.dailyCostPerAdminUSD", 5)
+	vip.SetDefault("translationAssist.enabled", false)
+	vip.SetDefault("translationAssist.provider", "google")
+	vip.SetDefault("translationAssist.intervalMinutes", 60)
+	vip.SetDefault("translationAssist.batchSize", 50)
+	vip.SetDefault("translationAssist.timeoutSec", 30)
+	vip.SetDefault("moderation.enabled", true)
+	vip.SetDefault("moderation.wordlistsDir", "")
+	vip.SetDefault("moderation.locales", []string{"ru", "kk", "en"})
+	vip.SetDefault("geo.enabled", false)
+	vip.SetDefault("geo.provider", "maxmind_db")
+	vip.SetDefault("geo.timeoutMs", 2000)
+	vip.SetDefault("geo.cacheTTLMinutes", 60)
+	vip.SetDefault("geo.allowUnknown", false)
+	vip.SetDefault("ipRisk.enabled", false)
+	vip.SetDefault("ipRisk.reloadIntervalMinutes", 0)
+	vip.SetDefault("ipRisk.threshold", 75)
+	vip.SetDefault("ipRisk.action", "flag")
+	vip.SetDefault("ipRisk.provider", "")
+	vip.SetDefault("ipRisk.timeoutMs", 2000)
+	vip.SetDefault("ipRisk.cacheTTLMinutes", 60)
+	vip.SetDefault("rating.enabled", true)
+	vip.SetDefault("rating.algorithm", "elo")
+	vip.SetDefault("rating.initial", 1500)
+	vip.SetDefault("rating.kFactor", 32)
+	vip.SetDefault("rating.provisionalKFactor", 64)
+	vip.SetDefault("rating.provisionalGames", 10)
+	vip.SetDefault("rating.minPlayers", 2)
+	vip.SetDefault("dailyChallenge.enabled", true)
+	vip.SetDefault("dailyChallenge.graceMs", 2000)
+	vip.SetDefault("announcer.enabled", true)
+	vip.SetDefault("devSeed", false)
+	vip.SetDefault("grpc.port", "9090")
+	vip.SetDefault("websocket.cluster.nats.url", "nats://localhost:4222")
+	vip.SetDefault("websocket.cluster.nats.maxReconnects", -1)
+	vip.SetDefault("websocket.cluster.nats.reconnectWait", 2)
+	vip.SetDefault("websocket.cluster.nats.connectTimeout", 5)
+	vip.SetDefault("legal.requireReacceptance", true)
+}
+
+// applyDefaults заполняет параметры, значения по умолчанию которых зависят от других настроек
+func (c *Config) applyDefaults() {
+	if c.Features.EmailVerificationEnabled {
+		if c.Email.VerificationTTL <= 0 {
+			c.Email.VerificationTTL = 15 * time.Minute
+		}
+		if c.Email.ResendCooldownSec <= 0 {
+			c.Email.ResendCooldownSec = 60
+		}
+		if c.Email.MaxAttempts <= 0 {
+			c.Email.MaxAttempts = 5
+		}
+	}
+	if c.Features.MagicLinkEnabled {
+		if c.MagicLink.TTL <= 0 {
+			c.MagicLink.TTL = 15 * time.Minute
+		}
+		if c.MagicLink.ResendCooldownSec <= 0 {
+			c.MagicLink.ResendCooldownSec = 60
+		}
+		if c.MagicLink.MaxPerHour <= 0 {
+			c.MagicLink.MaxPerHour = 5
+		}
+	}
+	if c.Features.WebAuthnEnabled {
+		if c.WebAuthn.CeremonyTimeout <= 0 {
+			c.WebAuthn.CeremonyTimeout = 5 * time.Minute
+		}
+		if c.WebAuthn.RPDisplayName == "" {
+			c.WebAuthn.RPDisplayName = "Trivia"
+		}
+	}
+	if c.Legal.TOSVersion == "" {
+		c.Legal.TOSVersion = "1.0"
+	}
+	if c.Legal.PrivacyVersion == "" {
+		c.Legal.PrivacyVersion = "1.0"
+	}
+}
+
+// Validate проверяет конфигурацию целиком и возвращает все найденные ошибки разом,
+// чтобы их можно было исправить за один заход. production включает проверки секретов,
+// обязательных только вне режима разработки.
+func (c *Config) Validate(production bool) error {
+	var errs []error
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	// Сервер
+	if port, err := strconv.Atoi(c.Server.Port); err != nil || port < 1 || port > 65535 {
+		fail("server.port must be a TCP port number, got %q", c.Server.Port)
+	}
+	if c.Server.ReadTimeout < 0 || c.Server.WriteTimeout < 0 {
+		fail("server.readTimeout and server.writeTimeout must not be negative")
+	}
+
+	// Секреты и подключения
+	if c.JWT.DBJWTKeyEncryptionKey == "" {
+		fail("DB JWT key encryption key is required in config (check DB_JWT_KEY_ENCRYPTION_KEY env var)")
+	}
+	if c.JWT.DBJWTKeyEncryptionKeyVersion < 1 {
+		fail("jwt.db_jwt_key_encryption_key_version must be positive")
+	}
+	if c.JWT.DBJWTKeyPreviousEncryptionKey != "" && c.JWT.DBJWTKeyEncryptionKeyVersion < 2 {
+		fail("jwt.db_jwt_key_previous_encryption_key requires jwt.db_jwt_key_encryption_key_version of 2 or more")
+	}
+	if c.Database.Host == "" || c.Database.DBName == "" || c.Database.User == "" {
+		fail("database configuration (host, dbname, user) is incomplete in config (check DATABASE_HOST, DATABASE_DBNAME, DATABASE_USER env vars)")
+	}
+	if production && c.Database.Password == "" {
+		fail("database password is required in production mode (check DATABASE_PASSWORD env var)")
+	}
+	switch c.Redis.Mode {
+	case "", "single", "cluster":
+	case "sentinel":
+		if c.Redis.MasterName == "" {
+			fail("redis.master_name is required in sentinel mode")
+		}
+	default:
+		fail("redis.mode must be one of single, sentinel, cluster, got %q", c.Redis.Mode)
+	}
+	if c.Redis.Addr == "" && len(c.Redis.Addrs) == 0 {
+		fail("redis.addr or redis.addrs is required")
+	}
+
+	// Токены
+	accessTokenTTL := time.Duration(c.JWT.ExpirationHrs) * time.Hour
+	if c.JWT.AccessTokenTTL != "" {
+		ttl, err := time.ParseDuration(c.JWT.AccessTokenTTL)
+		if err != nil || ttl <= 0 {
+			fail("jwt.accessTokenTTL must be a positive duration (e.g. 15m), got %q", c.JWT.AccessTokenTTL)
+		}
+		accessTokenTTL = ttl
+	} else if c.JWT.ExpirationHrs <= 0 {
+		fail("jwt.accessTokenTTL or jwt.expirationHrs is required")
+	}
+	if rotation := c.JWT.Rotation; rotation.Enabled {
+		if rotation.MaxKeyAge <= 0 || rotation.CheckInterval <= 0 {
+			fail("jwt.rotation.maxKeyAge and jwt.rotation.checkInterval must be positive")
+		}
+		// Токены, подписанные прежним ключом, должны дожить до конца своего срока
+		if rotation.Retention < accessTokenTTL {
+			fail("jwt.rotation.retention must not be shorter than the access token lifetime (%s)", accessTokenTTL)
+		}
+	} else if rotation.Retention < 0 {
+		fail("jwt.rotation.retention must not be negative")
+	}
+	if c.JWT.CleanupInterval < 0 {
+		fail("jwt.cleanup_interval must not be negative")
+	}
+	if c.JWT.WSTicketExpirySec < 0 {
+		fail("jwt.wsTicketExpirySec must not be negative")
+	}
+	if c.Auth.SessionLimit < 0 || c.Auth.RefreshTokenLifetime < 0 {
+		fail("auth.sessionLimit and auth.refreshTokenLifetime must not be negative")
+	}
+	validateSessionPolicy := func(key string, policy SessionPolicyConfig) {
+		if policy.IdleTimeout < 0 || policy.AbsoluteLifetime < 0 {
+			fail("%s: idleTimeout and absoluteLifetime must not be negative", key)
+		}
+		if policy.IdleTimeout > 0 && policy.AbsoluteLifetime > 0 && policy.IdleTimeout > policy.AbsoluteLifetime {
+			fail("%s.idleTimeout must not exceed absoluteLifetime", key)
+		}
+	}
+	validateSessionPolicy("auth.sessions.web", c.Auth.Sessions.Web)
+	validateSessionPolicy("auth.sessions.mobile", c.Auth.Sessions.Mobile)
+	for role, override := range c.Auth.Sessions.Roles {
+		validateSessionPolicy("auth.sessions.roles."+role+".web", override.Web)
+		validateSessionPolicy("auth.sessions.roles."+role+".mobile", override.Mobile)
+	}
+	switch c.Auth.CSRF.AdminMode {
+	case CSRFModeDoubleSubmit:
+	case CSRFModeSynchronizer:
+		if c.Auth.CSRF.NonceTTL <= 0 || c.Auth.CSRF.RotationGrace <= 0 {
+			fail("auth.csrf.nonceTTL and auth.csrf.rotationGrace must be positive")
+		}
+	default:
+		fail("auth.csrf.adminMode must be %q or %q, got %q", CSRFModeDoubleSubmit, CSRFModeSynchronizer, c.Auth.CSRF.AdminMode)
+	}
+
+	// CORS: пустой список заблокирует все браузерные клиенты
+	if len(c.CORS.AllowedOrigins) == 0 {
+		fail("cors.allowed_origins must not be empty")
+	}
+	for _, origin := range c.CORS.AllowedOrigins {
+		if origin == "*" {
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") {
+			fail("cors.allowed_origins: %q is not a valid origin (scheme://host[:port])", origin)
+		}
+	}
+
+	// Версии мобильного API
+	if deprecatedAt, sunsetAt, err := c.MobileAPI.V1Dates(); err != nil {
+		fail("%v", err)
+	} else if !sunsetAt.IsZero() && sunsetAt.Before(deprecatedAt) {
+		fail("mobileAPI.v1SunsetAt must not be earlier than v1DeprecatedAt")
+	}
+	if c.MobileAPI.StatsFlushIntervalSec <= 0 {
+		fail("mobileAPI.statsFlushIntervalSec must be positive")
+	}
+
+	// Заголовки безопасности: опечатка в имени группы иначе молча оставила бы значения по умолчанию
+	for group, rule := range c.SecurityHeaders {
+		switch group {
+		case SecurityHeadersGroupDefault, SecurityHeadersGroupAdmin, SecurityHeadersGroupUploads:
+		default:
+			fail("securityHeaders.%s: unknown route group (expected %s, %s or %s)", group,
+				SecurityHeadersGroupDefault, SecurityHeadersGroupAdmin, SecurityHeadersGroupUploads)
+		}
+		if hsts := rule.StrictTransportSecurity; hsts != nil && *hsts != "" && !strings.HasPrefix(*hsts, "max-age=") {
+			fail("securityHeaders.%s.strictTransportSecurity must start with max-age=, got %q", group, *hsts)
+		}
+	}
+
+	// Внешние сервисы, включённые флагами
+	if c.Features.EmailVerificationEnabled || c.Features.MagicLinkEnabled {
+		if c.Email.Provider == "" || c.Email.From == "" {
+			fail("email verification is enabled but email provider/from are not configured")
+		}
+		seen := map[string]bool{}
+		for _, provider := range append([]string{c.Email.Provider}, c.Email.FallbackProviders...) {
+			if seen[provider] {
+				fail("email provider %q is listed more than once", provider)
+				continue
+			}
+			seen[provider] = true
+			switch provider {
+			case "resend":
+				if c.Email.ResendAPIKey == "" {
+					fail("email verification is enabled with resend provider but EMAIL_RESEND_API_KEY is missing")
+				}
+			case "smtp":
+				if c.Email.SMTP.Host == "" {
+					fail("email verification is enabled with smtp provider but EMAIL_SMTP_HOST is missing")
+				}
+				switch c.Email.SMTP.TLS {
+				case "", "starttls", "implicit", "none":
+				default:
+					fail("email.smtp.tls must be starttls, implicit or none")
+				}
+			case "ses":
+				if c.Email.SES.Region == "" || c.Email.SES.AccessKey == "" || c.Email.SES.SecretKey == "" {
+					fail("email verification is enabled with ses provider but EMAIL_SES_REGION/ACCESS_KEY/SECRET_KEY are missing")
+				}
+			default:
+				fail("unknown email provider %q (expected resend, smtp or ses)", provider)
+			}
+		}
+	}
+	if c.Features.MagicLinkEnabled {
+		if u, err := url.Parse(c.MagicLink.URL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			fail("magic link login is enabled but magicLink.url is not an absolute http(s) URL")
+		}
+		if production && c.MagicLink.Secret == "" {
+			fail("magicLink.secret is required in production mode when magic link login is enabled (check MAGIC_LINK_SECRET env var)")
+		}
+	}
+	if c.ShareCards.BaseURL != "" {
+		if u, err := url.Parse(c.ShareCards.BaseURL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			fail("shareCards.baseURL must be an absolute http(s) URL")
+		}
+	}
+	if c.Features.WebAuthnEnabled {
+		if c.WebAuthn.RPID == "" || len(c.WebAuthn.RPOrigins) == 0 {
+			fail("webauthn is enabled but webauthn.rpID/rpOrigins are not configured")
+		}
+		for _, origin := range c.WebAuthn.RPOrigins {
+			if u, err := url.Parse(origin); err != nil || u.Scheme == "" || u.Host == "" {
+				fail("webauthn.rpOrigins: %q is not a valid origin (scheme://host[:port])", origin)
+			}
+		}
+	}
+	if c.Purchases.Enabled {
+		c.validatePurchases(fail)
+	}
+	if c.Features.GoogleOAuthEnabled {
+		if c.Google.WebClientID == "" {
+			fail("google oauth is enabled but GOOGLE_WEB_CLIENT_ID is missing")
+		}
+		if c.Google.RedirectURIWeb == "" {
+			fail("google oauth is enabled but GOOGLE_WEB_REDIRECT_URI is missing")
+		}
+	}
+	switch c.Storage.Backend {
+	case "", "local":
+	case "s3":
+		if c.Storage.S3.Bucket == "" || c.Storage.S3.AccessKey == "" || c.Storage.S3.SecretKey == "" {
+			fail("storage backend s3 requires bucket, access key and secret key (check STORAGE_S3_* env vars)")
+		}
+	default:
+		fail("storage.backend must be local or s3, got %q", c.Storage.Backend)
+	}
+	switch c.Secrets.Provider {
+	case "", "env":
+	case "file":
+		if c.Secrets.Dir == "" {
+			fail("secrets.dir is required for secrets provider file")
+		}
+	case "vault":
+		if c.Secrets.Vault.Addr == "" || c.Secrets.Vault.Path == "" {
+			fail("secrets provider vault requires secrets.vault.addr and secrets.vault.path (check VAULT_ADDR)")
+		}
+		if c.Secrets.Vault.Token == "" && c.Secrets.Vault.TokenFile == "" {
+			fail("secrets provider vault requires VAULT_TOKEN or secrets.vault.tokenFile")
+		}
+	default:
+		fail("secrets.provider must be env, file or vault, got %q", c.Secrets.Provider)
+	}
+	if c.GRPC.Enabled {
+		if c.GRPC.Port == "" || c.GRPC.Port == c.Server.Port {
+			fail("grpc.port must be set and differ from server.port, got %q", c.GRPC.Port)
+		}
+		if production && c.GRPC.AuthToken == "" {
+			fail("grpc.authToken is required in production mode when grpc is enabled (check GRPC_AUTH_TOKEN env var)")
+		}
+	}
+	if !i18n.IsValidTag(c.Locale.DefaultLocale) {
+		fail("localization.defaultLocale must be a language tag like ru or pt-BR, got %q", c.Locale.DefaultLocale)
+	}
+	defaultSupported := false
+	for _, locale := range c.Locale.SupportedLocales {
+		if !i18n.IsValidTag(locale) {
+			fail("localization.supportedLocales contains invalid language tag %q", locale)
+		}
+		if i18n.Normalize(locale) == i18n.Normalize(c.Locale.DefaultLocale) {
+			defaultSupported = true
+		}
+	}
+	if !defaultSupported {
+		fail("localization.supportedLocales must include localization.defaultLocale %q", c.Locale.DefaultLocale)
+	}
+	if c.Database.MaxOpenConns < 1 {
+		fail("database.maxOpenConns must be positive, got %d", c.Database.MaxOpenConns)
+	}
+	if c.Database.MaxIdleConns < 0 || c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		fail("database.maxIdleConns must be between 0 and database.maxOpenConns, got %d", c.Database.MaxIdleConns)
+	}
+	if c.Database.ConnMaxLifetimeMinutes < 0 || c.Database.ConnMaxIdleTimeMinutes < 0 {
+		fail("database.connMaxLifetimeMinutes and database.connMaxIdleTimeMinutes must not be negative")
+	}
+	if c.Database.SlowQueryMs < 0 {
+		fail("database.slowQueryMs must not be negative, got %d", c.Database.SlowQueryMs)
+	}
+	switch c.Database.LogLevel {
+	case "silent", "error", "warn", "info":
+	default:
+		fail("database.logLevel must be one of silent, error, warn, info, got %q", c.Database.LogLevel)
+	}
+	if c.HTTPCache.Enabled && c.HTTPCache.TTLSeconds < 1 {
+		fail("httpCache.ttlSeconds must be positive, got %d", c.HTTPCache.TTLSeconds)
+	}
+	if c.AnswerBuffer.Enabled && (c.AnswerBuffer.BatchSize < 1 || c.AnswerBuffer.FlushIntervalMs < 1) {
+		fail("answerBuffer.batchSize and answerBuffer.flushIntervalMs must be positive")
+	}
+	if c.Partitioning.RetentionDays < 0 {
+		fail("partitioning.retentionDays must not be negative, got %d", c.Partitioning.RetentionDays)
+	}
+	if c.Partitioning.RetentionDays > 0 && c.Partitioning.CheckIntervalHours < 1 {
+		fail("partitioning.checkIntervalHours must be positive, got %d", c.Partitioning.CheckIntervalHours)
+	}
+	if c.Maintenance.RetryAfterSec < 1 {
+		fail("maintenance.retryAfterSec must be positive, got %d", c.Maintenance.RetryAfterSec)
+	}
+	if c.Webhooks.Enabled {
+		if c.Webhooks.MaxAttempts < 1 || c.Webhooks.TimeoutSec < 1 || c.Webhooks.PollIntervalSec < 1 || c.Webhooks.BatchSize < 1 {
+			fail("webhooks.maxAttempts, timeoutSec, pollIntervalSec and batchSize must be positive")
+		}
+		if c.Webhooks.RetentionDays < 0 {
+			fail("webhooks.retentionDays must not be negative, got %d", c.Webhooks.RetentionDays)
+		}
+	}
+	if c.APIKeys.Enabled && c.APIKeys.FlushIntervalSec < 1 {
+		fail("apiKeys.flushIntervalSec must be positive, got %d", c.APIKeys.FlushIntervalSec)
+	}
+	if c.EventBus.PollIntervalMs < 1 || c.EventBus.BatchSize < 1 || c.EventBus.MaxAttempts < 1 {
+		fail("eventBus.pollIntervalMs, batchSize and maxAttempts must be positive")
+	}
+	if c.EventBus.RetentionDays < 0 {
+		fail("eventBus.retentionDays must not be negative, got %d", c.EventBus.RetentionDays)
+	}
+	if c.PrizeClaims.Enabled {
+		if c.PrizeClaims.ClaimWindowHours < 1 || c.PrizeClaims.CheckIntervalMin < 1 {
+			fail("prizeClaims.claimWindowHours and prizeClaims.checkIntervalMin must be positive")
+		}
+		switch c.PrizeClaims.UnclaimedPolicy {
+		case "redistribute", "forfeit":
+		default:
+			fail("prizeClaims.unclaimedPolicy must be redistribute or forfeit, got %q", c.PrizeClaims.UnclaimedPolicy)
+		}
+	}
+	if c.AntiAbuse.MaxAccountsPerDevice < 0 {
+		fail("antiAbuse.maxAccountsPerDevice must not be negative, got %d", c.AntiAbuse.MaxAccountsPerDevice)
+	}
+	switch c.AntiAbuse.OverLimitAction {
+	case "block", "shadow_ban":
+	default:
+		fail("antiAbuse.overLimitAction must be block or shadow_ban, got %q", c.AntiAbuse.OverLimitAction)
+	}
+	if c.QuestionMedia.MaxImageSizeMB < 0 || c.QuestionMedia.MaxAudioSizeMB < 0 {
+		fail("questionMedia limits must not be negative")
+	}
+	if c.QuestionMedia.URLTTLMinutes < 1 {
+		fail("questionMedia.urlTTLMinutes must be positive, got %d", c.QuestionMedia.URLTTLMinutes)
+	}
+	if c.Exports.PollIntervalSec < 1 || c.Exports.BatchSize < 1 || c.Exports.DownloadURLTTLMinutes < 1 || c.Exports.RetentionHours < 1 {
+		fail("exports.pollIntervalSec, batchSize, downloadURLTTLMinutes and retentionHours must be positive")
+	}
+	if c.Warehouse.Enabled {
+		switch c.Warehouse.Sink {
+		case "kafka":
+			if c.Warehouse.URL == "" || c.Warehouse.Topic == "" {
+				fail("warehouse.url and warehouse.topic are required for sink kafka")
+			}
+		case "http":
+			if c.Warehouse.URL == "" {
+				fail("warehouse.url is required for sink http")
+			}
+		case "file":
+			if c.Warehouse.Dir == "" {
+				fail("warehouse.dir is required for sink file")
+			}
+		default:
+			fail("warehouse.sink must be kafka, http or file, got %q", c.Warehouse.Sink)
+		}
+		if c.Warehouse.BatchSize < 1 || c.Warehouse.FlushIntervalMs < 1 || c.Warehouse.BufferSize < 1 || c.Warehouse.TimeoutSec < 1 {
+			fail("warehouse.batchSize, flushIntervalMs, bufferSize and timeoutSec must be positive")
+		}
+	}
+	switch c.Statistics.Backend {
+	case "postgres":
+	case "clickhouse":
+		if c.Statistics.ClickHouse.URL == "" {
+			fail("statistics.clickhouse.url is required for backend clickhouse")
+		}
+		if !c.Warehouse.Enabled {
+			fail("statistics.backend clickhouse requires warehouse.enabled: ClickHouse is fed by the event stream")
+		}
+		if c.Statistics.ClickHouse.TimeoutSec < 1 {
+			fail("statistics.clickhouse.timeoutSec must be positive, got %d", c.Statistics.ClickHouse.TimeoutSec)
+		}
+	default:
+		fail("statistics.backend must be postgres or clickhouse, got %q", c.Statistics.Backend)
+	}
+	if c.QuestionImport.IntervalHours < 1 || c.QuestionImport.TimeoutSec < 1 {
+		fail("questionImport.intervalHours and timeoutSec must be positive")
+	}
+	for external, difficulty := range c.QuestionImport.OpenTDB.Difficulties {
+		if difficulty < 1 || difficulty > 5 {
+			fail("questionImport.opentdb.difficulties.%s must be between 1 and 5, got %d", external, difficulty)
+		}
+	}
+	for i, mapping := range c.QuestionImport.OpenTDB.Categories {
+		if mapping.External == "" || mapping.Category == "" {
+			fail("questionImport.opentdb.categories[%d] needs external and category", i)
+		}
+	}
+	if c.QuestionImport.Enabled {
+		if len(c.QuestionImport.Scheduled) == 0 {
+			fail("questionImport.scheduled must list at least one import when questionImport.enabled")
+		}
+		for i, entry := range c.QuestionImport.Scheduled {
+			if entry.Source != "opentdb" {
+				fail("questionImport.scheduled[%d].source must be opentdb, got %q", i, entry.Source)
+			}
+			if entry.Amount < 1 || entry.Amount > 50 {
+				fail("questionImport.scheduled[%d].amount must be between 1 and 50, got %d", i, entry.Amount)
+			}
+		}
+	}
+	if c.AIQuestions.Enabled {
+		ai := c.AIQuestions
+		if ai.Provider != "openai" && ai.Provider != "anthropic" {
+			fail("aiQuestions.provider must be openai or anthropic, got %q", ai.Provider)
+		}
+		if ai.APIKey == "" || ai.Model == "" {
+			fail("aiQuestions.apiKey and aiQuestions.model are required when aiQuestions.enabled (check AI_QUESTIONS_API_KEY env var)")
+		}
+		if ai.MaxTokens < 1 || ai.TimeoutSec < 1 {
+			fail("aiQuestions.maxTokens and timeoutSec must be positive")
+		}
+		if ai.InputPricePerMillion < 0 || ai.OutputPricePerMillion < 0 || ai.DailyQuestionsPerAdmin < 0 || ai.DailyCostPerAdminUSD < 0 {
+			fail("aiQuestions prices and daily quotas must not be negative")
+		}
+		if ai.MaxQuestionsPerRequest < 1 || ai.MaxQuestionsPerRequest > 50 {
+			fail("aiQuestions.maxQuestionsPerRequest must be between 1 and 50, got %d", ai.MaxQuestionsPerRequest)
+		}
+		if ai.Language != "ru" && ai.Language != "kk" && ai.Language != "en" {
+			fail("aiQuestions.language must be ru, kk or en, got %q", ai.Language)
+		}
+	}
+	if c.TranslationAssist.Enabled {
+		ta := c.TranslationAssist
+		switch ta.Provider {
+		case "google":
+			if ta.Google.APIKey == "" {
+				fail("translationAssist.google.apiKey is required when translationAssist.provider is google (check TRANSLATION_ASSIST_GOOGLE_API_KEY env var)")
+			}
+		case "llm":
+			if !c.AIQuestions.Enabled {
+				fail("translationAssist.provider llm uses the aiQuestions model and requires aiQuestions.enabled")
+			}
+		default:
+			fail("translationAssist.provider must be google or llm, got %q", ta.Provider)
+		}
+		if ta.IntervalMinutes < 0 {
+			fail("translationAssist.intervalMinutes must not be negative")
+		}
+		if ta.BatchSize < 1 || ta.BatchSize > 500 {
+			fail("translationAssist.batchSize must be between 1 and 500, got %d", ta.BatchSize)
+		}
+		if ta.TimeoutSec < 1 {
+			fail("translationAssist.timeoutSec must be positive")
+		}
+	}
+	if c.Moderation.Enabled && len(c.Moderation.Locales) == 0 {
+		fail("moderation.locales must not be empty when moderation is enabled")
+	}
+	if c.Geo.Enabled {
+		switch c.Geo.Provider {
+		case "maxmind_db":
+			if c.Geo.DatabasePath == "" {
+				fail("geo.databasePath is required when geo.provider is maxmind_db")
+			}
+		case "maxmind_web":
+			if c.Geo.MaxMind.AccountID == "" || c.Geo.MaxMind.LicenseKey == "" {
+				fail("geo.maxmind.accountID and geo.maxmind.licenseKey are required when geo.provider is maxmind_web (check GEO_MAXMIND_LICENSE_KEY env var)")
+			}
+		default:
+			fail("geo.provider must be maxmind_db or maxmind_web, got %q", c.Geo.Provider)
+		}
+		if c.Geo.TimeoutMs < 1 {
+			fail("geo.timeoutMs must be positive")
+		}
+		if c.Geo.CacheTTLMinutes < 0 {
+			fail("geo.cacheTTLMinutes must not be negative")
+		}
+	}
+	if c.IPRisk.Enabled {
+		ir := c.IPRisk
+		if ir.Threshold < 1 || ir.Threshold > 100 {
+			fail("ipRisk.threshold must be between 1 and 100, got %d", ir.Threshold)
+		}
+		if ir.Action != "flag" && ir.Action != "exclude" {
+			fail("ipRisk.action must be flag or exclude, got %q", ir.Action)
+		}
+		if ir.Provider != "" && ir.Provider != "proxycheck" {
+			fail("ipRisk.provider must be empty or proxycheck, got %q", ir.Provider)
+		}
+		if ir.TimeoutMs < 1 {
+			fail("ipRisk.timeoutMs must be positive")
+		}
+		if ir.CacheTTLMinutes < 0 || ir.ReloadIntervalMinutes < 0 {
+			fail("ipRisk.cacheTTLMinutes and ipRisk.reloadIntervalMinutes must not be negative")
+		}
+	}
+	if c.Rating.Enabled {
+		rc := c.Rating
+		if rc.Algorithm != "elo" {
+			fail("rating.algorithm must be elo, got %q", rc.Algorithm)
+		}
+		if rc.Initial < 1 {
+			fail("rating.initial must be positive")
+		}
+		if rc.KFactor <= 0 || rc.ProvisionalKFactor < 0 {
+			fail("rating.kFactor must be positive and rating.provisionalKFactor must not be negative")
+		}
+		if rc.ProvisionalGames < 0 {
+			fail("rating.provisionalGames must not be negative")
+		}
+		if rc.MinPlayers < 2 {
+			fail("rating.minPlayers must be at least 2, got %d", rc.MinPlayers)
+		}
+	}
+	if c.DailyChallenge.Enabled && (c.DailyChallenge.GraceMs < 0 || c.DailyChallenge.GraceMs > 10000) {
+		fail("dailyChallenge.graceMs must be between 0 and 10000, got %d", c.DailyChallenge.GraceMs)
+	}
+	if c.Announcer.Enabled {
+		for i, a := range c.Announcer.Automatic {
+			switch {
+			case strings.TrimSpace(a.Message) == "":
+				fail("announcer.automatic[%d]: message is required", i)
+			case a.Trigger == "before_start" && (a.OffsetSec < 1 || a.OffsetSec > 3600):
+				fail("announcer.automatic[%d]: offsetSec must be between 1 and 3600, got %d", i, a.OffsetSec)
+			case a.Trigger == "after_question" && a.QuestionNumber < 1:
+				fail("announcer.automatic[%d]: questionNumber must be at least 1, got %d", i, a.QuestionNumber)
+			case a.Trigger != "before_start" && a.Trigger != "after_question" && a.Trigger != "before_finale":
+				fail("announcer.automatic[%d]: trigger must be before_start, after_question or before_finale, got %q", i, a.Trigger)
+			}
+		}
+	}
+	if production && c.DevSeed {
+		fail("devSeed creates accounts with a known password and is allowed only with GIN_MODE=debug (check DEV_SEED env var)")
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		fail("tracing.sampleRatio must be between 0 and 1, got %v", c.Tracing.SampleRatio)
+	}
+	if c.Ads.MaxImageSizeMB < 0 || c.Ads.MaxVideoSizeMB < 0 || c.Ads.MaxVideoDurationSec < 0 {
+		fail("ads limits must not be negative")
+	}
+
+	// WebSocket
+	ws := c.WebSocket
+	if ws.Sharding.Enabled && ws.Sharding.ShardCount <= 0 {
+		fail("websocket.sharding.shardCount must be positive when sharding is enabled")
+	}
+	if ws.Ping.Interval < 0 || ws.Ping.Timeout < 0 || ws.Limits.WriteWait < 0 || ws.Limits.PongWait < 0 {
+		fail("websocket ping/limits timeouts must not be negative")
+	}
+	if ws.Ping.Interval > 0 && ws.Limits.PongWait > 0 && ws.Ping.Interval >= ws.Limits.PongWait {
+		fail("websocket.ping.interval (%ds) must be less than websocket.limits.pongWait (%ds)", ws.Ping.Interval, ws.Limits.PongWait)
+	}
+	if ws.Limits.MaxMessageSize < 0 || ws.Limits.MaxConnectionsPerIP < 0 {
+		fail("websocket limits must not be negative")
+	}
+	if ws.Fanout.WriteBudgetMs < 0 || ws.Fanout.MaxBatch < 0 {
+		fail("websocket.fanout settings must not be negative")
+	}
+	if ws.Replay.MaxEvents < 0 || ws.Replay.TTLSeconds < 0 {
+		fail("websocket.replay settings must not be negative")
+	}
+	if ws.SSE.BufferSize < 0 || ws.SSE.KeepAliveSec < 0 {
+		fail("websocket.sse settings must not be negative")
+	}
+	if ws.AuthRefresh.CheckIntervalSec < 0 || ws.AuthRefresh.GraceSec < 0 {
+		fail("websocket.authRefresh settings must not be negative")
+	}
+	switch ws.Cluster.Provider {
+	case "", "redis":
+	case "nats":
+		if ws.Cluster.NATS.URL == "" {
+			fail("websocket.cluster.nats.url is required when websocket.cluster.provider is nats")
+		}
+		if ws.Cluster.NATS.ReconnectWait < 0 || ws.Cluster.NATS.ConnectTimeout < 0 {
+			fail("websocket.cluster.nats timeouts must not be negative")
+		}
+	default:
+		fail("websocket.cluster.provider must be one of redis, nats, got %q", ws.Cluster.Provider)
+	}
+
+	// Параметры, которые перечитываются без перезапуска
+	errs = append(errs, c.validateReloadable()...)
+
+	return errors.Join(errs...)
+}
+
+// validateReloadable проверяет секции, применяемые при hot reload (rateLimits, quiz)
+func (c *Config) validateReloadable() []error {
+	var errs []error
+	for group, rule := range c.RateLimits {
+		if rule.Requests != nil && *rule.Requests < 0 {
+			errs = append(errs, fmt.Errorf("rateLimits.%s.requests must not be negative", group))
+		}
+		if rule.UserRequests != nil && *rule.UserRequests < 0 {
+			errs = append(errs, fmt.Errorf("rateLimits.%s.userRequests must not be negative", group))
+		}
+		if rule.Window < 0 {
+			errs = append(errs, fmt.Errorf("rateLimits.%s.window must not be negative", group))
+		}
+	}
+
+	q := c.Quiz
+	if q.AnnouncementMinutes < 0 || q.ReminderMinutes < 0 || q.WaitingRoomMinutes < 0 ||
+		q.QuestionDelayMs < 0 || q.AnswerRevealDelayMs < 0 || q.InterQuestionDelayMs < 0 || q.AdVideoBufferMs < 0 {
+		errs = append(errs, fmt.Errorf("quiz timings must not be negative"))
+	}
+	if q.CountdownSeconds <= 0 {
+		errs = append(errs, fmt.Errorf("quiz.countdownSeconds must be positive"))
+	}
+	if q.WaitingRoomMinutes > 0 && q.AnnouncementMinutes > 0 && q.WaitingRoomMinutes > q.AnnouncementMinutes {
+		errs = append(errs, fmt.Errorf("quiz.waitingRoomMinutes (%d) must not exceed quiz.announcementMinutes (%d)", q.WaitingRoomMinutes, q.AnnouncementMinutes))
+	}
+	return errs
+}
+
+// validatePurchases проверяет настройки покупок: продукты и хотя бы один настроенный магазин
+func (c *Config) validatePurchases(fail func(format string, args ...interface{})) {
+	p := c.Purchases
+	if len(p.Products) == 0 {
+		fail("purchases.products must not be empty when purchases are enabled")
+	}
+	seen := make(map[string]bool, len(p.Products))
+	for _, product := range p.Products {
+		if product.ID == "" || len(product.Entitlements) == 0 {
+			fail("purchases.products: every product needs id and entitlements")
+			continue
+		}
+		if seen[product.ID] {
+			fail("purchases.products: product %q is listed more than once", product.ID)
+		}
+		seen[product.ID] = true
+	}
+	if p.Apple.IssuerID == "" && p.Google.PackageName == "" {
+		fail("purchases are enabled but neither purchases.apple nor purchases.google is configured")
+	}
+	if p.Apple.IssuerID != "" {
+		if p.Apple.KeyID == "" || p.Apple.PrivateKeyFile == "" || p.Apple.BundleID == "" {
+			fail("purchases.apple requires issuerID, keyID, privateKeyFile and bundleID")
+		}
+		if p.Apple.Environment != "production" && p.Apple.Environment != "sandbox" {
+			fail("purchases.apple.environment must be production or sandbox, got %q", p.Apple.Environment)
+		}
+	}
+	if p.Google.PackageName != "" && (p.Google.ServiceAccountFile == "" || p.Google.NotificationToken == "") {
+		fail("purchases.google requires serviceAccountFile and notificationToken (check PURCHASES_GOOGLE_NOTIFICATION_TOKEN env var)")
+	}
+}
@@ -0,0 +1,173 @@
+package config
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDebounce — пауза после события файловой системы перед перечитыванием:
+// редакторы и ConfigMap в Kubernetes меняют файл несколькими операциями подряд
+const reloadDebounce = 500 * time.Millisecond
+
+// Watcher перечитывает конфигурацию без перезапуска (по SIGHUP или изменению файла).
+// Применяются только параметры, которые компоненты умеют менять на лету: rateLimits и quiz.
+// Изменения остальных секций (подключения, секреты, WebSocket) логируются и требуют перезапуска.
+type Watcher struct {
+	path string
+
+	mu        sync.RWMutex
+	current   *Config
+	callbacks []func(*Config)
+
+	reloadMu sync.Mutex
+}
+
+// NewWatcher создаёт Watcher для файла path с уже загруженной конфигурацией initial
+func NewWatcher(path string, initial *Config) *Watcher {
+	return &Watcher{path: path, current: initial}
+}
+
+// Current возвращает действующую конфигурацию
+func (w *Watcher) Current() *Config {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.current
+}
+
+// OnReload регистрирует обработчик, вызываемый после успешного перечитывания
+func (w *Watcher) OnReload(fn func(*Config)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.callbacks = append(w.callbacks, fn)
+}
+
+// Reload перечитывает файл и переменные окружения. Если новая конфигурация не проходит
+// проверку, продолжает действовать прежняя, а ошибка возвращается вызывающему.
+func (w *Watcher) Reload() error {
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+
+	loaded, err := read(w.path)
+	if err != nil {
+		return err
+	}
+	if err := loaded.Validate(isProduction()); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	prev := w.current
+	next := *prev
+	next.RateLimits = loaded.RateLimits
+	next.Quiz = loaded.Quiz
+	w.current = &next
+	callbacks := append([]func(*Config){}, w.callbacks...)
+	w.mu.Unlock()
+
+	if changed := restartRequired(prev, loaded); len(changed) > 0 {
+		log.Printf("[Config] Изменения в секциях %v требуют перезапуска и не применены", changed)
+	}
+	for _, fn := range callbacks {
+		fn(&next)
+	}
+	log.Printf("[Config] Конфигурация перечитана из %s", w.path)
+	return nil
+}
+
+// Start запускает перечитывание по SIGHUP, а при watchFile — и по изменению файла конфигурации.
+// Работает до отмены ctx.
+func (w *Watcher) Start(ctx context.Context, watchFile bool) {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+
+	var fileEvents <-chan fsnotify.Event
+	var fileErrors <-chan error
+	var fsWatcher *fsnotify.Watcher
+	if watchFile && w.path != "" {
+		var err error
+		fsWatcher, err = fsnotify.NewWatcher()
+		if err != nil {
+			log.Printf("[Config] Не удалось включить отслеживание файла конфигурации: %v", err)
+		} else if err := fsWatcher.Add(filepath.Dir(w.path)); err != nil {
+			// Следим за каталогом, а не за файлом: при атомарной замене (rename) файл меняет inode
+			log.Printf("[Config] Не удалось отслеживать каталог %s: %v", filepath.Dir(w.path), err)
+			fsWatcher.Close()
+			fsWatcher = nil
+		} else {
+			fileEvents = fsWatcher.Events
+			fileErrors = fsWatcher.Errors
+		}
+	}
+
+	go func() {
+		defer signal.Stop(sighup)
+		if fsWatcher != nil {
+			defer fsWatcher.Close()
+		}
+
+		target := filepath.Clean(w.path)
+		var debounce *time.Timer
+		var debounceC <-chan time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				if debounce != nil {
+					debounce.Stop()
+				}
+				return
+			case <-sighup:
+				log.Println("[Config] Получен SIGHUP, перечитываю конфигурацию")
+				w.reloadAndLog()
+			case ev := <-fileEvents:
+				if filepath.Clean(ev.Name) != target || !ev.Has(fsnotify.Write|fsnotify.Create|fsnotify.Rename) {
+					continue
+				}
+				if debounce == nil {
+					debounce = time.NewTimer(reloadDebounce)
+				} else {
+					debounce.Reset(reloadDebounce)
+				}
+				debounceC = debounce.C
+			case <-debounceC:
+				debounceC = nil
+				log.Printf("[Config] Файл %s изменён, перечитываю конфигурацию", w.path)
+				w.reloadAndLog()
+			case err := <-fileErrors:
+				log.Printf("[Config] Ошибка отслеживания файла конфигурации: %v", err)
+			}
+		}
+	}()
+}
+
+func (w *Watcher) reloadAndLog() {
+	if err := w.Reload(); err != nil {
+		log.Printf("[Config] Конфигурация не перечитана, продолжает действовать прежняя: %v", err)
+	}
+}
+
+// restartRequired возвращает верхнеуровневые секции, изменившиеся в next по сравнению с prev,
+// кроме тех, что применяются на лету
+func restartRequired(prev, next *Config) []string {
+	var changed []string
+	pv, nv := reflect.ValueOf(*prev), reflect.ValueOf(*next)
+	t := pv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		switch t.Field(i).Name {
+		case "RateLimits", "Quiz":
+			continue
+		}
+		if !reflect.DeepEqual(pv.Field(i).Interface(), nv.Field(i).Interface()) {
+			changed = append(changed, t.Field(i).Name)
+		}
+	}
+	return changed
+}
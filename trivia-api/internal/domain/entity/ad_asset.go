@@ -1,18 +1,27 @@
 package entity
 
-import "time"
+import (
+	"math"
+	"time"
+)
 
 // AdAsset представляет рекламный медиа-файл
 type AdAsset struct {
-	ID            uint      `gorm:"primaryKey" json:"id"`
-	Title         string    `gorm:"size:100;not null" json:"title"`
-	MediaType     string    `gorm:"size:16;not null" json:"media_type"` // "image" | "video"
-	URL           string    `gorm:"size:1024;not null" json:"url"`
-	ThumbnailURL  string    `gorm:"size:1024" json:"thumbnail_url,omitempty"`
-	DurationSec   int       `gorm:"not null;default:10" json:"duration_sec"`
-	FileSizeBytes int64     `json:"file_size_bytes,omitempty"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	Title           string    `gorm:"size:100;not null" json:"title"`
+	MediaType       string    `gorm:"size:16;not null" json:"media_type"` // "image" | "video"
+	URL             string    `gorm:"size:1024;not null" json:"url"`
+	ThumbnailURL    string    `gorm:"size:1024" json:"thumbnail_url,omitempty"`
+	StorageKey      string    `gorm:"size:512" json:"-"` // ключ файла в хранилище загрузок
+	ThumbnailKey    string    `gorm:"column:thumbnail_storage_key;size:512" json:"-"`
+	DurationSec     int       `gorm:"not null;default:10" json:"duration_sec"`
+	FileSizeBytes   int64     `json:"file_size_bytes,omitempty"`
+	MimeType        string    `gorm:"size:64" json:"mime_type,omitempty"`
+	VideoDurationMs int       `json:"video_duration_ms,omitempty"` // фактическая длительность ролика (0 — неизвестна)
+	Width           int       `json:"width,omitempty"`
+	Height          int       `json:"height,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
 }
 
 // TableName возвращает имя таблицы
@@ -29,3 +38,17 @@ func (a *AdAsset) IsVideo() bool {
 func (a *AdAsset) IsImage() bool {
 	return a.MediaType == "image"
 }
+
+// PlaybackDuration возвращает длительность показа рекламы.
+// Для видео с известной длительностью ролика используется она, иначе — DurationSec.
+func (a *AdAsset) PlaybackDuration() time.Duration {
+	if a.IsVideo() && a.VideoDurationMs > 0 {
+		return time.Duration(a.VideoDurationMs) * time.Millisecond
+	}
+	return time.Duration(a.DurationSec) * time.Second
+}
+
+// VideoDurationSec возвращает длительность ролика в секундах, округлённую вверх
+func (a *AdAsset) VideoDurationSec() int {
+	return int(math.Ceil(float64(a.VideoDurationMs) / 1000))
+}
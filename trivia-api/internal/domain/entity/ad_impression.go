@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// AdImpression фиксирует показ рекламной паузы во время викторины.
+// Viewers — количество подписчиков викторины в момент показа.
+type AdImpression struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	AdAssetID     *uint     `gorm:"index" json:"ad_asset_id,omitempty"`
+	QuizID        *uint     `json:"quiz_id,omitempty"`
+	QuestionAfter int       `gorm:"not null" json:"question_after"`
+	Viewers       int       `gorm:"not null" json:"viewers"`
+	ShownAt       time.Time `gorm:"not null;index" json:"shown_at"`
+	Placement     string    `gorm:"size:20;not null;default:'ad_break'" json:"placement"` // entity.AdPlacement*
+}
+
+// TableName возвращает имя таблицы
+func (AdImpression) TableName() string {
+	return "ad_impressions"
+}
@@ -0,0 +1,69 @@
+package entity
+
+import "time"
+
+// Права (scopes) API-ключей партнёров
+const (
+	APIKeyScopeQuizzesRead = "quizzes:read" // список и карточки викторин
+	APIKeyScopeResultsRead = "results:read" // результаты викторин
+)
+
+// APIKeyScopes — все права, которые можно выдать ключу
+var APIKeyScopes = []string{
+	APIKeyScopeQuizzesRead,
+	APIKeyScopeResultsRead,
+}
+
+// APIKey — ключ доступа партнёра к API (server-to-server). Сам ключ не хранится:
+// KeyHash — SHA-256 от ключа, Prefix — его начало для опознания в админ-панели.
+type APIKey struct {
+	ID         uint        `gorm:"primaryKey" json:"id"`
+	Name       string      `gorm:"size:100;not null" json:"name"`
+	Prefix     string      `gorm:"size:16;not null" json:"prefix"`
+	KeyHash    string      `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	Scopes     StringArray `gorm:"type:jsonb;not null" json:"scopes"`
+	RateLimit  int         `gorm:"not null;default:0" json:"rate_limit"` // запросов в окне группы api_keys; 0 — по умолчанию
+	CreatedBy  uint        `gorm:"not null;default:0" json:"created_by"`
+	ExpiresAt  *time.Time  `json:"expires_at,omitempty"`
+	RevokedAt  *time.Time  `json:"revoked_at,omitempty"`
+	LastUsedAt *time.Time  `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time   `json:"created_at"`
+	UpdatedAt  time.Time   `json:"updated_at"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// HasScope сообщает, выдано ли ключу право
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Usable сообщает, можно ли пользоваться ключом в момент now
+func (k *APIKey) Usable(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
+
+// APIKeyUsage — счётчики запросов ключа за сутки (UTC)
+type APIKeyUsage struct {
+	KeyID       uint      `gorm:"primaryKey" json:"key_id"`
+	Day         time.Time `gorm:"primaryKey;type:date" json:"day"`
+	Requests    int64     `gorm:"not null;default:0" json:"requests"`
+	RateLimited int64     `gorm:"not null;default:0" json:"rate_limited"` // отклонено с 429
+	Errors      int64     `gorm:"not null;default:0" json:"errors"`       // ответы 4xx (кроме 429) и 5xx
+}
+
+// TableName определяет имя таблицы для GORM
+func (APIKeyUsage) TableName() string {
+	return "api_key_usage"
+}
@@ -0,0 +1,177 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Действия, фиксируемые в журнале аудита
+const (
+	AuditActionLogin                  = "auth.login"
+	AuditActionLoginFailed            = "auth.login_failed"
+	AuditActionLogoutAll              = "auth.logout_all"
+	AuditActionPasskeyRegister        = "auth.passkey_register"
+	AuditActionPasskeyDelete          = "auth.passkey_delete"
+	AuditActionDeviceKeyRevoke        = "auth.device_key_revoke"
+	AuditActionTokenInvalidationReset = "auth.token_invalidation_reset"
+	AuditActionJWTKeyRotate           = "auth.jwt_key_rotate"
+	AuditActionPasswordReset          = "admin.password_reset"
+	AuditActionUserShadowBan          = "user.shadow_ban"
+	AuditActionUserShadowUnban        = "user.shadow_unban"
+	AuditActionQuizSchedule           = "quiz.schedule"
+	AuditActionQuizCancel             = "quiz.cancel"
+	AuditActionQuizLivePause          = "quiz.live_pause"
+	AuditActionQuizLiveResume         = "quiz.live_resume"
+	AuditActionQuizLiveExtend         = "quiz.live_extend"
+	AuditActionQuizLiveSkip           = "quiz.live_skip"
+	AuditActionQuizLiveAnnounce       = "quiz.live_announce"
+	AuditActionQuizDeliveryFailure    = "quiz.delivery_failure"
+	AuditActionQuizSecondChance       = "quiz.second_chance_update"
+	AuditActionQuizCapacity           = "quiz.capacity_update"
+	AuditActionQuizAdmit              = "quiz.admission_admit"
+	AuditActionQuizGameMode           = "quiz.game_mode_update"
+	AuditActionQuizTieBreak           = "quiz.tie_break_update"
+	AuditActionQuizPrizeLadder        = "quiz.prize_ladder_update"
+	AuditActionQuizAllowedRegions     = "quiz.allowed_regions_update"
+	AuditActionQuizAnnouncementCreate = "quiz.announcement_create"
+	AuditActionQuizAnnouncementUpdate = "quiz.announcement_update"
+	AuditActionQuizAnnouncementDelete = "quiz.announcement_delete"
+	AuditActionQuizSponsor            = "quiz.sponsor_update"
+	AuditActionAdUpload               = "ad.upload"
+	AuditActionAdDelete               = "ad.delete"
+	AuditActionPayoutApprove          = "wallet.payout_approve"
+	AuditActionPayoutReject           = "wallet.payout_reject"
+	AuditActionPayoutPaid             = "wallet.payout_paid"
+	AuditActionPrizeClaimVerify       = "prize_claim.verify"
+	AuditActionPrizeClaimReject       = "prize_claim.reject"
+	AuditActionTranslationUpsert      = "question.translation_upsert"
+	AuditActionTranslationDelete      = "question.translation_delete"
+	AuditActionTranslationAccept      = "question.translation_accept"
+	AuditActionTranslationAssistRun   = "question.translation_assist_run"
+	AuditActionQuestionMediaUpload    = "question.media_upload"
+	AuditActionQuestionMediaDelete    = "question.media_delete"
+	AuditActionQuestionReviewSubmit   = "question.review_submit"
+	AuditActionQuestionReviewAssign   = "question.review_assign"
+	AuditActionQuestionReviewApprove  = "question.review_approve"
+	AuditActionQuestionReviewReject   = "question.review_reject"
+	AuditActionMaintenanceToggle      = "system.maintenance_toggle"
+	AuditActionFeatureFlagCreate      = "feature_flag.create"
+	AuditActionFeatureFlagUpdate      = "feature_flag.update"
+	AuditActionFeatureFlagDelete      = "feature_flag.delete"
+	AuditActionWebhookCreate          = "webhook.create"
+	AuditActionWebhookUpdate          = "webhook.update"
+	AuditActionWebhookDelete          = "webhook.delete"
+	AuditActionAPIKeyCreate           = "api_key.create"
+	AuditActionAPIKeyRevoke           = "api_key.revoke"
+	AuditActionEmailDeadLetterRetry   = "email.dead_letter_retry"
+	AuditActionEmailTemplateSave      = "email.template_save"
+	AuditActionEmailTemplateActivate  = "email.template_activate"
+	AuditActionEmailTemplateReset     = "email.template_reset"
+	AuditActionCategoryCreate         = "taxonomy.category_create"
+	AuditActionCategoryUpdate         = "taxonomy.category_update"
+	AuditActionCategoryDelete         = "taxonomy.category_delete"
+	AuditActionTagCreate              = "taxonomy.tag_create"
+	AuditActionTagUpdate              = "taxonomy.tag_update"
+	AuditActionTagDelete              = "taxonomy.tag_delete"
+	AuditActionQuizTaxonomy           = "quiz.taxonomy_update"
+	AuditActionQuestionTaxonomy       = "question.taxonomy_update"
+	AuditActionQuestionImport         = "question.import"
+	AuditActionQuestionGenerate       = "question.generate"
+	AuditActionModerationApprove      = "moderation.approve"
+	AuditActionModerationReject       = "moderation.reject"
+	AuditActionPrizeReviewApprove     = "prize_review.approve"
+	AuditActionPrizeReviewReject      = "prize_review.reject"
+)
+
+// Типы объектов, над которыми выполняются действия
+const (
+	AuditTargetUser        = "user"
+	AuditTargetQuiz        = "quiz"
+	AuditTargetAdAsset     = "ad_asset"
+	AuditTargetPayout      = "payout"
+	AuditTargetPrizeClaim  = "prize_claim"
+	AuditTargetQuestion    = "question"
+	AuditTargetSystem      = "system"
+	AuditTargetFeatureFlag = "feature_flag"
+	AuditTargetWebhook     = "webhook"
+	AuditTargetAPIKey      = "api_key"
+	AuditTargetEmail       = "email"
+	AuditTargetCategory    = "category"
+	AuditTargetTag         = "tag"
+	AuditTargetImport      = "question_import"
+	AuditTargetGeneration  = "question_generation"
+	AuditTargetModeration  = "moderation_case"
+	AuditTargetPrizeReview = "prize_review"
+	AuditTargetJWTKey      = "jwt_key"
+)
+
+// AuditData - JSON в записи аудита: снимок объекта до/после действия или метаданные (JSONB, может отсутствовать)
+type AuditData []byte
+
+// Scan реализует интерфейс sql.Scanner
+func (s *AuditData) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*s = nil
+	case []byte:
+		*s = append(AuditData(nil), v...)
+	case string:
+		*s = AuditData(v)
+	default:
+		return errors.New("failed to scan JSONB value: unexpected type")
+	}
+	return nil
+}
+
+// Value реализует интерфейс driver.Valuer
+func (s AuditData) Value() (driver.Value, error) {
+	if len(s) == 0 {
+		return nil, nil
+	}
+	return string(s), nil
+}
+
+// MarshalJSON отдаёт значение как вложенный JSON, а не строку
+func (s AuditData) MarshalJSON() ([]byte, error) {
+	if len(s) == 0 {
+		return []byte("null"), nil
+	}
+	return s, nil
+}
+
+// UnmarshalJSON реализует json.Unmarshaler
+func (s *AuditData) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*s = nil
+		return nil
+	}
+	if !json.Valid(data) {
+		return errors.New("invalid audit data JSON")
+	}
+	*s = append(AuditData(nil), data...)
+	return nil
+}
+
+// AuditLog - запись журнала аудита. Таблица только для добавления: изменение и удаление
+// записей запрещено триггером в БД. ActorID пуст для анонимных событий (неудачный вход).
+type AuditLog struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	Action     string    `gorm:"size:64;not null;index" json:"action"`
+	ActorID    *uint     `gorm:"index" json:"actor_id,omitempty"`
+	TargetType string    `gorm:"size:32;not null;default:''" json:"target_type,omitempty"`
+	TargetID   string    `gorm:"size:64;not null;default:''" json:"target_id,omitempty"`
+	IPAddress  string    `gorm:"size:64;not null;default:''" json:"ip_address,omitempty"`
+	UserAgent  string    `gorm:"type:text;not null;default:''" json:"user_agent,omitempty"`
+	RequestID  string    `gorm:"size:128;not null;default:''" json:"request_id,omitempty"`
+	Before     AuditData `gorm:"column:before_state;type:jsonb" json:"before,omitempty"`
+	After      AuditData `gorm:"column:after_state;type:jsonb" json:"after,omitempty"`
+	Metadata   AuditData `gorm:"type:jsonb" json:"metadata,omitempty"`
+	CreatedAt  time.Time `gorm:"not null;index" json:"created_at"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (AuditLog) TableName() string {
+	return "audit_log"
+}
@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+// DailyChallenge — вопрос дня: один вопрос общего пула на календарный день (UTC), одинаковый
+// для всех игроков. Выбирается при первом запросе дня и помечается использованным в пуле.
+type DailyChallenge struct {
+	Day        time.Time `gorm:"type:date;primaryKey" json:"day"`
+	QuestionID uint      `gorm:"not null" json:"question_id"`
+	Difficulty int       `gorm:"not null" json:"difficulty"` // целевая сложность с учётом прошлого дня
+	CreatedAt  time.Time `json:"created_at"`
+	Question   *Question `gorm:"foreignKey:QuestionID" json:"-"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (DailyChallenge) TableName() string {
+	return "daily_challenges"
+}
+
+// DailyChallengeAnswer — ответ игрока на вопрос дня; один на игрока в день
+type DailyChallengeAnswer struct {
+	ID             uint      `gorm:"primaryKey" json:"-"`
+	Day            time.Time `gorm:"type:date;not null;uniqueIndex:idx_daily_answer_day_user" json:"day"`
+	UserID         uint      `gorm:"not null;uniqueIndex:idx_daily_answer_day_user" json:"-"`
+	QuestionID     uint      `gorm:"not null" json:"question_id"`
+	SelectedOption int       `gorm:"not null" json:"selected_option"`
+	IsCorrect      bool      `gorm:"not null" json:"is_correct"`
+	TimedOut       bool      `gorm:"not null;default:false" json:"timed_out"` // ответ пришёл после лимита времени
+	ResponseTimeMs int64     `gorm:"not null" json:"response_time_ms"`        // от выдачи вопроса до ответа
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (DailyChallengeAnswer) TableName() string {
+	return "daily_challenge_answers"
+}
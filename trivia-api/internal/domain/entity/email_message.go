@@ -0,0 +1,125 @@
+package entity
+
+import "time"
+
+// Провайдеры отправки email
+const (
+	EmailProviderResend = "resend"
+	EmailProviderSMTP   = "smtp"
+	EmailProviderSES    = "ses"
+)
+
+// Виды писем
+const (
+	EmailKindVerification = "verification"
+	EmailKindNotification = "notification"
+	EmailKindMagicLink    = "magic_link"
+)
+
+// Статусы отправленного письма
+const (
+	EmailStatusSent       = "sent"       // провайдер принял письмо
+	EmailStatusDelayed    = "delayed"    // доставка откладывается
+	EmailStatusDelivered  = "delivered"  // сервер получателя принял письмо
+	EmailStatusBounced    = "bounced"    // письмо не доставлено
+	EmailStatusComplained = "complained" // получатель пожаловался на спам
+)
+
+// Типы событий доставки из обратных вызовов провайдеров
+const (
+	EmailEventDelivered  = "delivered"
+	EmailEventDelayed    = "delayed"
+	EmailEventBounced    = "bounced"
+	EmailEventComplained = "complained"
+	EmailEventOpened     = "opened"
+	EmailEventClicked    = "clicked"
+)
+
+// EmailStatusAfterEvent возвращает статус письма после события и статусы, из которых
+// переход допустим: поздний delayed не отменяет delivered, а отказ и жалоба окончательны.
+// ok=false — событие только записывается в журнал, статус не меняется.
+func EmailStatusAfterEvent(eventType string) (status string, from []string, ok bool) {
+	switch eventType {
+	case EmailEventDelayed:
+		return EmailStatusDelayed, []string{EmailStatusSent}, true
+	case EmailEventDelivered:
+		return EmailStatusDelivered, []string{EmailStatusSent, EmailStatusDelayed}, true
+	case EmailEventBounced:
+		return EmailStatusBounced, []string{EmailStatusSent, EmailStatusDelayed, EmailStatusDelivered}, true
+	case EmailEventComplained:
+		return EmailStatusComplained, []string{EmailStatusSent, EmailStatusDelayed, EmailStatusDelivered}, true
+	default:
+		return "", nil, false
+	}
+}
+
+// EmailMessage — письмо, принятое провайдером. ProviderMessageID связывает его
+// с обратными вызовами о доставке.
+type EmailMessage struct {
+	ID                uint       `gorm:"primaryKey" json:"id"`
+	Provider          string     `gorm:"size:16;not null" json:"provider"`
+	ProviderMessageID string     `gorm:"size:255;not null" json:"provider_message_id"`
+	Kind              string     `gorm:"size:32;not null" json:"kind"`
+	Recipient         string     `gorm:"size:255;not null" json:"recipient"`
+	Subject           string     `gorm:"size:255;not null;default:''" json:"subject"`
+	IdempotencyKey    string     `gorm:"size:255;not null;default:''" json:"-"`
+	Status            string     `gorm:"size:16;not null;default:'sent'" json:"status"`
+	Attempts          int        `gorm:"not null;default:1" json:"attempts"`
+	LastEventAt       *time.Time `json:"last_event_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (EmailMessage) TableName() string {
+	return "email_messages"
+}
+
+// EmailDeliveryEvent — событие доставки из обратного вызова провайдера.
+// MessageID пуст, если письмо отправлено до ведения журнала или другим сервисом.
+type EmailDeliveryEvent struct {
+	ID                uint      `gorm:"primaryKey" json:"id"`
+	MessageID         *uint     `json:"message_id,omitempty"`
+	Provider          string    `gorm:"size:16;not null" json:"provider"`
+	ProviderMessageID string    `gorm:"size:255;not null" json:"provider_message_id"`
+	ExternalID        string    `gorm:"size:255;not null;default:''" json:"-"`
+	EventType         string    `gorm:"size:32;not null" json:"event_type"`
+	Detail            string    `gorm:"size:500;not null;default:''" json:"detail,omitempty"`
+	OccurredAt        time.Time `gorm:"not null" json:"occurred_at"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (EmailDeliveryEvent) TableName() string {
+	return "email_delivery_events"
+}
+
+// EmailDeadLetter — письмо, которое не принял ни один провайдер. Текст кодов подтверждения
+// и ссылок для входа не сохраняется: такие письма не переотправляются, пользователь
+// запрашивает новый код или ссылку.
+type EmailDeadLetter struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	Kind           string     `gorm:"size:32;not null" json:"kind"`
+	Recipient      string     `gorm:"size:255;not null" json:"recipient"`
+	Subject        string     `gorm:"size:255;not null;default:''" json:"subject"`
+	TextBody       string     `gorm:"type:text;not null;default:''" json:"-"`
+	HTMLBody       string     `gorm:"type:text;not null;default:''" json:"-"`
+	IdempotencyKey string     `gorm:"size:255;not null;default:''" json:"-"`
+	Provider       string     `gorm:"size:16;not null;default:''" json:"provider"`
+	Attempts       int        `gorm:"not null;default:0" json:"attempts"`
+	LastError      string     `gorm:"size:500;not null;default:''" json:"last_error"`
+	RetriedAt      *time.Time `json:"retried_at,omitempty"`
+	RetryMessageID *uint      `json:"retry_message_id,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (EmailDeadLetter) TableName() string {
+	return "email_dead_letters"
+}
+
+// Retryable сообщает, можно ли переотправить письмо
+func (d *EmailDeadLetter) Retryable() bool {
+	return d.RetriedAt == nil && (d.TextBody != "" || d.HTMLBody != "")
+}
@@ -0,0 +1,33 @@
+package entity
+
+import "time"
+
+// Шаблоны писем
+const (
+	EmailTemplateVerificationCode = "verification_code" // код подтверждения email
+	EmailTemplatePasswordReset    = "password_reset"    // пароль сброшен администратором
+	EmailTemplatePrizeWon         = "prize_won"         // объявление победителей
+	EmailTemplatePrizeClaim       = "prize_claim"       // токен заявки на приз
+	EmailTemplateSecurityAlert    = "security_alert"    // отзыв сессий и другие события безопасности
+	EmailTemplateMagicLink        = "magic_link"        // ссылка для входа без пароля
+)
+
+// EmailTemplate — версия шаблона письма, сохранённая администратором. Активная версия
+// заменяет шаблон из файлов для своего языка; версии не изменяются, правка создаёт новую.
+type EmailTemplate struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Key       string    `gorm:"size:64;not null" json:"key"`
+	Locale    string    `gorm:"size:5;not null" json:"locale"`
+	Version   int       `gorm:"not null" json:"version"`
+	Subject   string    `gorm:"size:255;not null" json:"subject"`
+	TextBody  string    `gorm:"type:text;not null" json:"text_body"`
+	HTMLBody  string    `gorm:"type:text;not null;default:''" json:"html_body"`
+	IsActive  bool      `gorm:"not null;default:false" json:"is_active"`
+	CreatedBy *uint     `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (EmailTemplate) TableName() string {
+	return "email_templates"
+}
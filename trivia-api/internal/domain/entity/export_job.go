@@ -0,0 +1,68 @@
+package entity
+
+import "time"
+
+// Что выгружается
+const (
+	ExportKindResults = "results" // результаты викторины, строка на игрока
+	ExportKindAnswers = "answers" // ответы викторины, строка на ответ
+)
+
+// Форматы файла выгрузки
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatXLSX = "xlsx"
+	ExportFormatJSON = "json"
+)
+
+// Статусы задания выгрузки
+const (
+	ExportJobPending   = "pending"   // ждёт обработчика
+	ExportJobRunning   = "running"   // файл формируется
+	ExportJobCompleted = "completed" // файл в хранилище, доступен по временной ссылке
+	ExportJobFailed    = "failed"    // попытки исчерпаны, см. Error
+	ExportJobExpired   = "expired"   // срок хранения истёк, файл удалён
+)
+
+// ExportJob — фоновая выгрузка данных викторины в файл. Обработчик забирает задание,
+// пишет файл порциями, обновляя RowsDone, и кладёт его в хранилище загрузок.
+type ExportJob struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	QuizID      uint       `gorm:"not null;index" json:"quiz_id"`
+	Kind        string     `gorm:"size:20;not null" json:"kind"`
+	Format      string     `gorm:"size:10;not null" json:"format"`
+	Status      string     `gorm:"size:20;not null;default:'pending'" json:"status"`
+	RequestedBy uint       `gorm:"not null" json:"requested_by"`
+	RowsTotal   int64      `gorm:"not null;default:0" json:"rows_total"`
+	RowsDone    int64      `gorm:"not null;default:0" json:"rows_done"`
+	StorageKey  string     `gorm:"size:255;not null;default:''" json:"-"`
+	FileName    string     `gorm:"size:255;not null;default:''" json:"file_name,omitempty"`
+	SizeBytes   int64      `gorm:"not null;default:0" json:"size_bytes"`
+	Error       string     `gorm:"size:500;not null;default:''" json:"error,omitempty"`
+	Attempts    int        `gorm:"not null;default:0" json:"attempts"`
+	LeaseUntil  *time.Time `json:"-"` // до какого времени задание занято обработчиком
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"` // когда файл будет удалён
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (ExportJob) TableName() string {
+	return "export_jobs"
+}
+
+// Progress возвращает готовность выгрузки в процентах
+func (j *ExportJob) Progress() int {
+	switch {
+	case j.Status == ExportJobCompleted:
+		return 100
+	case j.RowsTotal <= 0:
+		return 0
+	case j.RowsDone >= j.RowsTotal:
+		return 99 // строки записаны, файл ещё сохраняется
+	default:
+		return int(j.RowsDone * 100 / j.RowsTotal)
+	}
+}
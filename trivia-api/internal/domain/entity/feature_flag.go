@@ -0,0 +1,83 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Флаги функций, которые проверяет код. Флаг без записи в БД берёт значение из cfg.Features.
+const (
+	FeatureEmailVerification = "email_verification"
+)
+
+// UintArray - список идентификаторов в JSONB
+type UintArray []uint
+
+// Scan реализует интерфейс sql.Scanner для UintArray
+func (a *UintArray) Scan(value interface{}) error {
+	if value == nil {
+		*a = UintArray{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to unmarshal JSONB value: expected []byte")
+	}
+	if len(bytes) == 0 {
+		*a = UintArray{}
+		return nil
+	}
+	return json.Unmarshal(bytes, a)
+}
+
+// Value реализует интерфейс driver.Valuer для UintArray
+func (a UintArray) Value() (driver.Value, error) {
+	if len(a) == 0 {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(a)
+}
+
+// FeatureFlag — флаг функции с постепенным включением. Выключенный флаг (Enabled=false)
+// выключен для всех. Включённый действует для пользователей из UserIDs, для администраторов
+// при AdminOnly и для Percentage процентов остальных пользователей.
+type FeatureFlag struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Key         string    `gorm:"size:100;not null;uniqueIndex" json:"key"`
+	Description string    `gorm:"size:500;not null;default:''" json:"description"`
+	Enabled     bool      `gorm:"not null;default:false" json:"enabled"`
+	AdminOnly   bool      `gorm:"not null;default:false" json:"admin_only"`
+	Percentage  int       `gorm:"not null;default:0" json:"percentage"` // 0-100
+	UserIDs     UintArray `gorm:"type:jsonb;not null" json:"user_ids"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (FeatureFlag) TableName() string {
+	return "feature_flags"
+}
+
+// EnabledFor вычисляет флаг для пользователя userID (0 — аноним). bucket — стабильный номер
+// пользователя 0-99 для этого флага, по нему пользователь попадает или не попадает в процент.
+func (f *FeatureFlag) EnabledFor(userID uint, isAdmin bool, bucket int) bool {
+	if !f.Enabled {
+		return false
+	}
+	if userID != 0 {
+		for _, id := range f.UserIDs {
+			if id == userID {
+				return true
+			}
+		}
+	}
+	if f.AdminOnly {
+		return isAdmin
+	}
+	if f.Percentage >= 100 {
+		return true
+	}
+	return userID != 0 && bucket < f.Percentage
+}
@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"errors"
+)
+
+// JSONPayload - тело события (вебхук, событие outbox) в JSONB
+type JSONPayload []byte
+
+// Scan реализует интерфейс sql.Scanner
+func (p *JSONPayload) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*p = nil
+	case []byte:
+		*p = append(JSONPayload(nil), v...)
+	case string:
+		*p = JSONPayload(v)
+	default:
+		return errors.New("failed to scan JSONB value: unexpected type")
+	}
+	return nil
+}
+
+// Value реализует интерфейс driver.Valuer
+func (p JSONPayload) Value() (driver.Value, error) {
+	if len(p) == 0 {
+		return nil, nil
+	}
+	return string(p), nil
+}
+
+// MarshalJSON отдаёт тело как вложенный JSON, а не строку
+func (p JSONPayload) MarshalJSON() ([]byte, error) {
+	if len(p) == 0 {
+		return []byte("null"), nil
+	}
+	return p, nil
+}
@@ -14,6 +14,10 @@ type JWTKey struct {
 	ExpiresAt  time.Time  `gorm:"not null;index" json:"expires_at"`
 	RotatedAt  *time.Time `gorm:"index" json:"rotated_at,omitempty"`
 	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	// Конвертное шифрование секрета (см. пакет envelope): ключ данных, обёрнутый мастер-ключом
+	// версии EncryptionKeyVersion; пустой — секрет зашифрован мастер-ключом напрямую
+	WrappedDataKey       string `gorm:"type:text;not null;default:''" json:"-"`
+	EncryptionKeyVersion int    `gorm:"not null;default:1" json:"encryption_key_version"`
 }
 
 // TableName определяет имя таблицы для GORM.
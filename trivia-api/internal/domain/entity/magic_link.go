@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+// MagicLink — одноразовая ссылка для входа без пароля. Хранится только HMAC токена;
+// DeviceHash привязывает ссылку к устройству, с которого её запросили.
+type MagicLink struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	UserID     uint       `gorm:"not null;index" json:"user_id"`
+	TokenHash  string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	DeviceHash string     `gorm:"size:64;not null" json:"-"`
+	IPAddress  string     `gorm:"size:45;not null;default:''" json:"ip_address"`
+	ExpiresAt  time.Time  `gorm:"not null" json:"expires_at"`
+	ConsumedAt *time.Time `json:"consumed_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (MagicLink) TableName() string {
+	return "magic_links"
+}
+
+// IsExpired сообщает, истёк ли срок действия ссылки
+func (m *MagicLink) IsExpired(now time.Time) bool {
+	return now.After(m.ExpiresAt)
+}
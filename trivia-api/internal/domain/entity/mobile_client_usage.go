@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// MobileClientUsage — число запросов к мобильному API за сутки (UTC) по версии API
+// и версии приложения, определённой по User-Agent
+type MobileClientUsage struct {
+	Day        time.Time `gorm:"primaryKey;type:date" json:"day"`
+	APIVersion string    `gorm:"primaryKey;size:8" json:"api_version"` // v1, v2
+	Platform   string    `gorm:"primaryKey;size:16" json:"platform"`   // ios, android, unknown
+	AppVersion string    `gorm:"primaryKey;size:32" json:"app_version"`
+	Requests   int64     `gorm:"not null;default:0" json:"requests"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (MobileClientUsage) TableName() string {
+	return "mobile_client_usage"
+}
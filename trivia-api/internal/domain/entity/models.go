@@ -0,0 +1,21 @@
+package entity
+
+// PersistentModels возвращает модели, хранящиеся в собственных таблицах. Схема создаётся
+// SQL-миграциями; список нужен проверке расхождений схемы (manage doctor), поэтому новую
+// модель с таблицей нужно добавить сюда.
+func PersistentModels() []interface{} {
+	return []interface{}{
+		&User{}, &UserIdentity{}, &UserFollow{}, &UserLegalAcceptance{}, &WebAuthnCredential{},
+		&RefreshToken{}, &InvalidToken{}, &JWTKey{}, &MagicLink{}, &EmailVerificationCode{},
+		&APIKey{}, &APIKeyUsage{}, &MobileClientUsage{}, &PushDevice{}, &NotificationPreference{},
+		&Notification{}, &AuditLog{}, &FeatureFlag{}, &OutboxEvent{},
+		&Quiz{}, &QuizAdSlot{}, &QuizQuestionHistory{}, &QuizRSVP{},
+		&Question{}, &QuestionTranslation{}, &QuestionReviewComment{}, &Category{}, &Tag{},
+		&UserAnswer{}, &Result{}, &ExportJob{},
+		&AdAsset{}, &AdImpression{}, &WSConnectionSample{},
+		&ReferralCode{}, &Referral{}, &Purchase{}, &UserEntitlement{},
+		&WalletAccount{}, &LedgerTransaction{}, &LedgerEntry{}, &PayoutRequest{}, &PrizeClaim{},
+		&EmailMessage{}, &EmailDeliveryEvent{}, &EmailDeadLetter{}, &EmailTemplate{},
+		&WebhookEndpoint{}, &WebhookDelivery{},
+	}
+}
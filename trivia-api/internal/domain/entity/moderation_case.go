@@ -0,0 +1,38 @@
+package entity
+
+import "time"
+
+// Проверяемые модерацией поля пользовательского текста (moderation_cases.field)
+const (
+	ModerationFieldUsername  = "username"
+	ModerationFieldFirstName = "first_name"
+	ModerationFieldLastName  = "last_name"
+	ModerationFieldChat      = "chat_message"
+)
+
+// Статусы случаев модерации
+const (
+	ModerationCasePending    = "pending"
+	ModerationCaseApproved   = "approved"
+	ModerationCaseRejected   = "rejected"
+	ModerationCaseSuperseded = "superseded" // пользователь сменил текст до решения модератора
+)
+
+// ModerationCase — пограничный пользовательский текст, принятый, но ожидающий решения модератора
+type ModerationCase struct {
+	ID         uint        `gorm:"primaryKey" json:"id"`
+	UserID     uint        `gorm:"not null;index" json:"user_id"`
+	Field      string      `gorm:"size:30;not null" json:"field"`
+	Content    string      `gorm:"size:1000;not null" json:"content"`
+	Matches    StringArray `gorm:"type:jsonb;not null;default:'[]'" json:"matches"` // сработавшие записи списков: "ru:дурак*"
+	Status     string      `gorm:"size:20;not null;default:pending" json:"status"`
+	ReviewedBy *uint       `json:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time  `json:"reviewed_at,omitempty"`
+	Note       string      `gorm:"size:500;not null;default:''" json:"note,omitempty"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (ModerationCase) TableName() string {
+	return "moderation_cases"
+}
@@ -0,0 +1,84 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Типы in-app уведомлений
+const (
+	NotificationTypeResultsAvailable = "results_available"
+	NotificationTypePrizeWon         = "prize_won"
+	NotificationTypeSecurityAlert    = "security_alert"
+	NotificationTypePrizeClaim       = "prize_claim"
+	NotificationTypeQuizReminder     = "quiz_reminder"
+)
+
+// NotificationCategory возвращает категорию настроек, к которой относится тип уведомления.
+// Пустая строка означает, что уведомление нельзя отключить по категории.
+func NotificationCategory(notificationType string) string {
+	switch notificationType {
+	case NotificationTypePrizeWon:
+		return NotificationCategoryWinnerAnnouncement
+	case NotificationTypeSecurityAlert:
+		return NotificationCategorySecurityAlert
+	case NotificationTypeQuizReminder:
+		return NotificationCategoryQuizReminder
+	default:
+		return ""
+	}
+}
+
+// NotificationData - произвольные параметры уведомления (quiz_id, prize и т.п.), хранятся в JSONB
+type NotificationData map[string]interface{}
+
+// Scan реализует интерфейс sql.Scanner
+func (d *NotificationData) Scan(value interface{}) error {
+	if value == nil {
+		*d = NotificationData{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to unmarshal JSONB value: expected []byte")
+	}
+	if len(bytes) == 0 {
+		*d = NotificationData{}
+		return nil
+	}
+
+	return json.Unmarshal(bytes, d)
+}
+
+// Value реализует интерфейс driver.Valuer
+func (d NotificationData) Value() (driver.Value, error) {
+	if len(d) == 0 {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(d)
+}
+
+// Notification представляет уведомление в in-app центре уведомлений
+type Notification struct {
+	ID        uint             `gorm:"primaryKey" json:"id"`
+	UserID    uint             `gorm:"not null;index" json:"user_id"`
+	Type      string           `gorm:"size:32;not null" json:"type"`
+	Title     string           `gorm:"size:255;not null" json:"title"`
+	Body      string           `gorm:"type:text;not null" json:"body"`
+	Data      NotificationData `gorm:"type:jsonb;not null" json:"data"`
+	ReadAt    *time.Time       `json:"read_at,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+// IsRead проверяет, прочитано ли уведомление
+func (n *Notification) IsRead() bool {
+	return n.ReadAt != nil
+}
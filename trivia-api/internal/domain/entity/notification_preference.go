@@ -0,0 +1,82 @@
+package entity
+
+import "time"
+
+// Категории уведомлений, которыми пользователь управляет в настройках
+const (
+	NotificationCategoryQuizReminder       = "quiz_reminder"
+	NotificationCategoryWinnerAnnouncement = "winner_announcement"
+	NotificationCategorySecurityAlert      = "security_alert"
+)
+
+// Каналы доставки уведомлений
+const (
+	NotificationChannelEmail     = "email"
+	NotificationChannelPush      = "push"
+	NotificationChannelWebSocket = "websocket"
+)
+
+// NotificationPreference хранит настройки уведомлений пользователя.
+// Значения по умолчанию задаются миграцией и DefaultNotificationPreference;
+// в GORM-тегах default не указан, иначе GORM не сохранит false при вставке.
+type NotificationPreference struct {
+	UserID              uint      `gorm:"primaryKey;autoIncrement:false" json:"user_id"`
+	QuizReminders       bool      `gorm:"not null" json:"quiz_reminders"`
+	WinnerAnnouncements bool      `gorm:"not null" json:"winner_announcements"`
+	SecurityAlerts      bool      `gorm:"not null" json:"security_alerts"`
+	EmailEnabled        bool      `gorm:"not null" json:"email_enabled"`
+	PushEnabled         bool      `gorm:"not null" json:"push_enabled"`
+	WebSocketEnabled    bool      `gorm:"column:websocket_enabled;not null" json:"websocket_enabled"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (NotificationPreference) TableName() string {
+	return "notification_preferences"
+}
+
+// DefaultNotificationPreference возвращает настройки для пользователя, который их ещё не менял
+func DefaultNotificationPreference(userID uint) *NotificationPreference {
+	return &NotificationPreference{
+		UserID:              userID,
+		QuizReminders:       true,
+		WinnerAnnouncements: true,
+		SecurityAlerts:      true,
+		EmailEnabled:        true,
+		PushEnabled:         true,
+		WebSocketEnabled:    true,
+	}
+}
+
+// Allows проверяет, разрешена ли пользователю категория уведомлений
+func (p *NotificationPreference) Allows(category string) bool {
+	switch category {
+	case NotificationCategoryQuizReminder:
+		return p.QuizReminders
+	case NotificationCategoryWinnerAnnouncement:
+		return p.WinnerAnnouncements
+	case NotificationCategorySecurityAlert:
+		return p.SecurityAlerts
+	default:
+		return true
+	}
+}
+
+// ChannelEnabled проверяет, включён ли канал доставки
+func (p *NotificationPreference) ChannelEnabled(channel string) bool {
+	switch channel {
+	case NotificationChannelEmail:
+		return p.EmailEnabled
+	case NotificationChannelPush:
+		return p.PushEnabled
+	case NotificationChannelWebSocket:
+		return p.WebSocketEnabled
+	default:
+		return true
+	}
+}
+
+// AllowsDelivery проверяет, можно ли доставить уведомление категории по каналу
+func (p *NotificationPreference) AllowsDelivery(category, channel string) bool {
+	return p.Allows(category) && p.ChannelEnabled(channel)
+}
@@ -0,0 +1,117 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Доменные события. Записываются в outbox в транзакции, которая их породила,
+// и доставляются подписчикам шины событий асинхронно.
+const (
+	EventUserRegistered = "user.registered"
+	EventQuizCompleted  = "quiz.completed"
+	EventPrizeAwarded   = "prize.awarded"
+
+	EventQuestionVoided     = "question.voided"     // ответы на снятый с эфира вопрос аннулированы
+	EventEliminationRevoked = "elimination.revoked" // выбывание игрока отменено вторым шансом
+)
+
+// Статусы события outbox
+const (
+	OutboxStatusPending   = "pending"   // ожидает обработки или повтора
+	OutboxStatusProcessed = "processed" // обработано всеми подписчиками
+	OutboxStatusDead      = "dead"      // попытки исчерпаны, нужен разбор вручную
+)
+
+// UserRegisteredPayload — данные события user.registered
+type UserRegisteredPayload struct {
+	UserID   uint   `json:"user_id"`
+	Username string `json:"username"`
+	Source   string `json:"source"` // password, google
+}
+
+// QuizCompletedPayload — данные события quiz.completed: результаты и призы зафиксированы
+type QuizCompletedPayload struct {
+	QuizID         uint         `json:"quiz_id"`
+	Title          string       `json:"title"`
+	ScheduledTime  time.Time    `json:"scheduled_time"`
+	PrizeFund      int          `json:"prize_fund"`
+	WinnerIDs      []uint       `json:"winner_ids"`
+	PrizePerWinner int          `json:"prize_per_winner"`
+	Prizes         map[uint]int `json:"prizes,omitempty"` // приз каждого победителя при лестнице призов (суммы различаются)
+}
+
+// PrizeAwardedPayload — данные события prize.awarded (по одному на победителя)
+type PrizeAwardedPayload struct {
+	QuizID uint `json:"quiz_id"`
+	UserID uint `json:"user_id"`
+	Amount int  `json:"amount"`
+}
+
+// QuestionVoidedPayload — данные события question.voided
+type QuestionVoidedPayload struct {
+	QuizID     uint      `json:"quiz_id"`
+	QuestionID uint      `json:"question_id"`
+	VoidedAt   time.Time `json:"voided_at"`
+}
+
+// EliminationRevokedPayload — данные события elimination.revoked
+type EliminationRevokedPayload struct {
+	QuizID    uint      `json:"quiz_id"`
+	UserID    uint      `json:"user_id"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
+
+// OutboxEvent — доменное событие в таблице outbox. DoneConsumers — подписчики, уже
+// обработавшие событие: при повторе после ошибки одного подписчика остальные не вызываются.
+type OutboxEvent struct {
+	ID            uint        `gorm:"primaryKey" json:"id"`
+	EventType     string      `gorm:"size:64;not null" json:"event_type"`
+	Payload       JSONPayload `gorm:"type:jsonb;not null" json:"payload"`
+	Status        string      `gorm:"size:16;not null;default:'pending'" json:"status"`
+	Attempts      int         `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt time.Time   `gorm:"not null" json:"next_attempt_at"`
+	DoneConsumers StringArray `gorm:"type:jsonb;not null" json:"done_consumers"`
+	LastError     string      `gorm:"size:500;not null;default:''" json:"last_error,omitempty"`
+	ProcessedAt   *time.Time  `json:"processed_at,omitempty"`
+	CreatedAt     time.Time   `json:"created_at"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (OutboxEvent) TableName() string {
+	return "outbox_events"
+}
+
+// NewOutboxEvent создаёт событие с сериализованными данными payload
+func NewOutboxEvent(eventType string, payload interface{}) (OutboxEvent, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return OutboxEvent{}, fmt.Errorf("failed to marshal %s event: %w", eventType, err)
+	}
+	return OutboxEvent{
+		EventType:     eventType,
+		Payload:       data,
+		Status:        OutboxStatusPending,
+		NextAttemptAt: time.Now().UTC(),
+		DoneConsumers: StringArray{},
+	}, nil
+}
+
+// Decode разбирает данные события в dest
+func (e *OutboxEvent) Decode(dest interface{}) error {
+	if err := json.Unmarshal(e.Payload, dest); err != nil {
+		return fmt.Errorf("failed to decode %s event #%d: %w", e.EventType, e.ID, err)
+	}
+	return nil
+}
+
+// DoneBy сообщает, обработал ли подписчик consumer событие
+func (e *OutboxEvent) DoneBy(consumer string) bool {
+	for _, done := range e.DoneConsumers {
+		if done == consumer {
+			return true
+		}
+	}
+	return false
+}
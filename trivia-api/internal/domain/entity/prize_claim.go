@@ -0,0 +1,45 @@
+package entity
+
+import "time"
+
+// Статусы заявки на приз
+const (
+	PrizeClaimPending       = "pending"       // ждёт данных победителя (в том числе после отклонения)
+	PrizeClaimSubmitted     = "submitted"     // данные отправлены, ждёт проверки администратором
+	PrizeClaimVerified      = "verified"      // личность подтверждена, приз выдан
+	PrizeClaimRedistributed = "redistributed" // срок истёк, приз разделён между остальными победителями
+	PrizeClaimForfeited     = "forfeited"     // срок истёк, приз сгорел
+)
+
+// PrizeClaim — заявка победителя на получение приза викторины
+type PrizeClaim struct {
+	ID                uint       `gorm:"primaryKey" json:"id"`
+	QuizID            uint       `gorm:"not null" json:"quiz_id"`
+	UserID            uint       `gorm:"not null" json:"user_id"`
+	Amount            int64      `gorm:"not null" json:"amount"`
+	Status            string     `gorm:"size:20;not null;default:'pending'" json:"status"`
+	TokenHash         string     `gorm:"size:64;not null" json:"-"`
+	PayoutMethod      string     `gorm:"size:30;not null;default:''" json:"payout_method,omitempty"`
+	PayoutDestination string     `gorm:"size:255;not null;default:''" json:"payout_destination,omitempty"`
+	FullName          string     `gorm:"size:150;not null;default:''" json:"full_name,omitempty"`
+	ContactEmail      string     `gorm:"size:255;not null;default:''" json:"contact_email,omitempty"`
+	ContactPhone      string     `gorm:"size:32;not null;default:''" json:"contact_phone,omitempty"`
+	RejectReason      string     `gorm:"size:255;not null;default:''" json:"reject_reason,omitempty"`
+	ReviewerID        *uint      `json:"reviewer_id,omitempty"`
+	ClaimDeadline     time.Time  `gorm:"not null" json:"claim_deadline"`
+	SubmittedAt       *time.Time `json:"submitted_at,omitempty"`
+	VerifiedAt        *time.Time `json:"verified_at,omitempty"`
+	ResolvedAt        *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (PrizeClaim) TableName() string {
+	return "prize_claims"
+}
+
+// IsOpen сообщает, ждёт ли заявка действий победителя или администратора
+func (c *PrizeClaim) IsOpen() bool {
+	return c.Status == PrizeClaimPending || c.Status == PrizeClaimSubmitted
+}
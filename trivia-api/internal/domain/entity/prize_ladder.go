@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// PrizeTier — ступень лестницы призов: Percent процентов фонда делится между финишировавшими,
+// занявшими место не ниже Top (0 — все финишировавшие)
+type PrizeTier struct {
+	Top     int `json:"top,omitempty"`
+	Percent int `json:"percent"`
+}
+
+// PrizeLadder — лестница призов викторины в JSONB. Пустая лестница — фонд делится поровну
+// между победителями.
+type PrizeLadder []PrizeTier
+
+// Scan реализует интерфейс sql.Scanner для PrizeLadder
+func (l *PrizeLadder) Scan(value interface{}) error {
+	var bytes []byte
+	switch v := value.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("failed to unmarshal JSONB value: unexpected type")
+	}
+	if len(bytes) == 0 {
+		*l = nil
+		return nil
+	}
+	return json.Unmarshal(bytes, l)
+}
+
+// Value реализует интерфейс driver.Valuer для PrizeLadder
+func (l PrizeLadder) Value() (driver.Value, error) {
+	if len(l) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(l)
+}
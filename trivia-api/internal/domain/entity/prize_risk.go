@@ -0,0 +1,56 @@
+package entity
+
+import "time"
+
+// QuizSessionRisk — оценка риска адреса, с которого игрок подключался к викторине (VPN, прокси,
+// дата-центр). Строка на каждый адрес; при подведении итогов учитывается наибольшая оценка.
+type QuizSessionRisk struct {
+	QuizID      uint        `gorm:"primaryKey" json:"quiz_id"`
+	UserID      uint        `gorm:"primaryKey" json:"user_id"`
+	IPAddress   string      `gorm:"primaryKey;size:45" json:"ip_address"`
+	Score       int         `gorm:"not null;default:0" json:"score"`                 // 0–100
+	Reasons     StringArray `gorm:"type:jsonb;not null;default:'[]'" json:"reasons"` // "list:vpn", "proxycheck:hosting"
+	Attempts    int         `gorm:"not null;default:1" json:"attempts"`
+	FirstSeenAt time.Time   `gorm:"not null" json:"first_seen_at"`
+	LastSeenAt  time.Time   `gorm:"not null" json:"last_seen_at"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (QuizSessionRisk) TableName() string {
+	return "quiz_session_risks"
+}
+
+// Действия с победителем, подключавшимся с рискованного адреса (ipRisk.action)
+const (
+	PrizeRiskActionFlag    = "flag"    // приз выплачивается, победитель отмечается для проверки
+	PrizeRiskActionExclude = "exclude" // выплата задерживается до решения администратора
+)
+
+// Статусы проверки победителя
+const (
+	PrizeRiskReviewPending  = "pending"
+	PrizeRiskReviewApproved = "approved"
+	PrizeRiskReviewRejected = "rejected"
+)
+
+// PrizeRiskReview — победитель викторины, подключавшийся с адреса с оценкой риска не ниже порога
+type PrizeRiskReview struct {
+	ID          uint        `gorm:"primaryKey" json:"id"`
+	QuizID      uint        `gorm:"not null;uniqueIndex:idx_prize_risk_reviews_quiz_user" json:"quiz_id"`
+	UserID      uint        `gorm:"not null;uniqueIndex:idx_prize_risk_reviews_quiz_user" json:"user_id"`
+	Score       int         `gorm:"not null" json:"score"`
+	Reasons     StringArray `gorm:"type:jsonb;not null;default:'[]'" json:"reasons"`
+	IPAddresses StringArray `gorm:"type:jsonb;not null;default:'[]'" json:"ip_addresses"`
+	Amount      int         `gorm:"not null;default:0" json:"amount"` // приз победителя
+	Action      string      `gorm:"size:20;not null" json:"action"`
+	Status      string      `gorm:"size:20;not null;default:pending" json:"status"`
+	ReviewedBy  *uint       `json:"reviewed_by,omitempty"`
+	ReviewedAt  *time.Time  `json:"reviewed_at,omitempty"`
+	Note        string      `gorm:"size:500;not null;default:''" json:"note,omitempty"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (PrizeRiskReview) TableName() string {
+	return "prize_risk_reviews"
+}
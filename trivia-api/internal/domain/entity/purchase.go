@@ -0,0 +1,78 @@
+package entity
+
+import "time"
+
+// Магазины приложений
+const (
+	PurchaseStoreApple  = "apple"
+	PurchaseStoreGoogle = "google"
+)
+
+// Статусы покупки
+const (
+	PurchaseStatusActive  = "active"  // оплачена, подписка действует (в т.ч. льготный период)
+	PurchaseStatusPending = "pending" // оплата ещё не прошла (отложенный платёж Google Play)
+	PurchaseStatusExpired = "expired" // подписка закончилась или приостановлена
+	PurchaseStatusRevoked = "revoked" // возврат средств или отзыв магазином
+)
+
+// Права (entitlements) премиум-тарифа. Какие права даёт продукт, задаётся в purchases.products.
+const (
+	EntitlementPremium        = "premium"
+	EntitlementAdFree         = "ad_free"
+	EntitlementExtraLifelines = "extra_lifelines"
+)
+
+// Purchase — покупка в магазине приложений и её текущее состояние по данным магазина.
+// TransactionID — идентификатор покупки, постоянный для всех продлений подписки:
+// originalTransactionId в App Store и purchaseToken в Google Play.
+type Purchase struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	UserID        uint       `gorm:"not null;index" json:"user_id"`
+	Store         string     `gorm:"size:16;not null;uniqueIndex:idx_purchases_store_transaction" json:"store"`
+	TransactionID string     `gorm:"type:text;not null;uniqueIndex:idx_purchases_store_transaction" json:"-"`
+	ProductID     string     `gorm:"size:255;not null" json:"product_id"`
+	Environment   string     `gorm:"size:16;not null;default:'production'" json:"environment"`
+	Status        string     `gorm:"size:16;not null" json:"status"`
+	AutoRenew     bool       `gorm:"not null;default:false" json:"auto_renew"`
+	PurchasedAt   time.Time  `gorm:"not null" json:"purchased_at"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"` // nil — покупка без срока (non-consumable)
+	RevokedAt     *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (Purchase) TableName() string {
+	return "purchases"
+}
+
+// GrantsAccess сообщает, даёт ли покупка права в момент now
+func (p *Purchase) GrantsAccess(now time.Time) bool {
+	if p.Status != PurchaseStatusActive {
+		return false
+	}
+	return p.ExpiresAt == nil || now.Before(*p.ExpiresAt)
+}
+
+// UserEntitlement — право пользователя, полученное покупкой. Пересчитывается при каждом
+// изменении покупок пользователя; истёкшие права не удаляются, а отсекаются по ExpiresAt.
+type UserEntitlement struct {
+	UserID      uint       `gorm:"primaryKey" json:"-"`
+	Entitlement string     `gorm:"primaryKey;size:50" json:"entitlement"`
+	PurchaseID  uint       `gorm:"not null" json:"-"`
+	Store       string     `gorm:"size:16;not null" json:"store"`
+	ProductID   string     `gorm:"size:255;not null" json:"product_id"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"` // nil — бессрочно
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (UserEntitlement) TableName() string {
+	return "user_entitlements"
+}
+
+// ActiveAt сообщает, действует ли право в момент now
+func (e *UserEntitlement) ActiveAt(now time.Time) bool {
+	return e.ExpiresAt == nil || now.Before(*e.ExpiresAt)
+}
@@ -0,0 +1,30 @@
+package entity
+
+import "time"
+
+// Платформы и провайдеры push-уведомлений
+const (
+	PushPlatformIOS     = "ios"
+	PushPlatformAndroid = "android"
+
+	PushProviderFCM  = "fcm"
+	PushProviderAPNs = "apns"
+)
+
+// PushDevice хранит push-токен мобильного устройства пользователя
+type PushDevice struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;index" json:"user_id"`
+	Platform  string    `gorm:"size:10;not null" json:"platform"`
+	Provider  string    `gorm:"size:10;not null" json:"provider"`
+	Token     string    `gorm:"size:512;not null;uniqueIndex" json:"-"`
+	DeviceID  string    `gorm:"size:255;not null;default:''" json:"device_id"`
+	Language  string    `gorm:"size:5;not null;default:'ru'" json:"language"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (PushDevice) TableName() string {
+	return "push_devices"
+}
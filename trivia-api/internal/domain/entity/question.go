@@ -1,10 +1,16 @@
 package entity
 
 import (
+	"crypto/sha256"
 	"database/sql/driver"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"strings"
 	"time"
+	"unicode"
+
+	"gorm.io/gorm"
 )
 
 // StringArray - пользовательский тип для работы с JSONB
@@ -42,6 +48,15 @@ func (o StringArray) Value() (driver.Value, error) {
 	return json.Marshal(o)
 }
 
+// Статусы проверки вопроса: draft → pending_review → approved/rejected.
+// В автовыбор и эфир попадают только одобренные вопросы.
+const (
+	QuestionReviewDraft         = "draft"
+	QuestionReviewPendingReview = "pending_review"
+	QuestionReviewApproved      = "approved"
+	QuestionReviewRejected      = "rejected"
+)
+
 // Question представляет вопрос в викторине
 type Question struct {
 	ID            uint        `gorm:"primaryKey" json:"id"`
@@ -53,8 +68,22 @@ type Question struct {
 	CorrectOption int         `gorm:"not null" json:"-"`                      // Скрыто от клиента
 	TimeLimitSec  int         `gorm:"not null;default:10" json:"time_limit_sec"`
 	PointValue    int         `gorm:"not null;default:10" json:"point_value"`
-	Difficulty    int         `gorm:"not null;default:3" json:"difficulty"` // 1-5: very_easy to very_hard
-	IsUsed        bool        `gorm:"not null;default:false" json:"-"`      // Исключён из автовыбора после использования
+	Difficulty    int         `gorm:"not null;default:3" json:"difficulty"`                    // 1-5: very_easy to very_hard
+	IsUsed        bool        `gorm:"not null;default:false" json:"-"`                         // Исключён из автовыбора после использования
+	ImageKey      string      `gorm:"size:255;not null;default:''" json:"image_key,omitempty"` // Картинка в хранилище (опционально)
+	AudioKey      string      `gorm:"size:255;not null;default:''" json:"audio_key,omitempty"` // Аудиофрагмент в хранилище (опционально)
+	ReviewStatus  string      `gorm:"size:20;not null;default:'draft';index" json:"review_status"`
+	ReviewerID    *uint       `json:"reviewer_id,omitempty"`              // Назначенный рецензент
+	ReviewedAt    *time.Time  `json:"reviewed_at,omitempty"`              // Время одобрения или отклонения
+	CategoryID    *uint       `gorm:"index" json:"category_id,omitempty"` // Тема вопроса для выбора из пула
+	Tags          []Tag       `gorm:"many2many:question_tags" json:"tags,omitempty"`
+	Source        string      `gorm:"size:30;not null;default:''" json:"source,omitempty"`       // Внешний источник (entity.QuestionSource*); пусто — создан вручную
+	SourceRef     string      `gorm:"size:100;not null;default:''" json:"source_ref,omitempty"`  // Идентификатор вопроса в источнике
+	License       string      `gorm:"size:100;not null;default:''" json:"license,omitempty"`     // Лицензия контента источника
+	Attribution   string      `gorm:"size:255;not null;default:''" json:"attribution,omitempty"` // Обязательная ссылка на источник
+	ImportID      *uint       `gorm:"index" json:"import_id,omitempty"`                          // Запуск импорта, добавивший вопрос
+	GenerationID  *uint       `gorm:"index" json:"generation_id,omitempty"`                      // Генерация моделью, добавившая вопрос
+	TextHash      string      `gorm:"size:64;not null;default:'';index" json:"-"`                // QuestionTextHash(Text) для поиска дубликатов
 	CreatedAt     time.Time   `json:"created_at"`
 	UpdatedAt     time.Time   `json:"updated_at"`
 }
@@ -64,6 +93,28 @@ func (Question) TableName() string {
 	return "questions"
 }
 
+// BeforeSave пересчитывает хеш текста для поиска дубликатов
+func (q *Question) BeforeSave(tx *gorm.DB) error {
+	if q.Text != "" {
+		q.TextHash = QuestionTextHash(q.Text)
+	}
+	return nil
+}
+
+// QuestionTextHash — SHA-256 текста вопроса без регистра, пробелов и знаков препинания:
+// «Столица Франции?» и «столица франции» считаются одним вопросом.
+// Миграция 000072 считает тот же хеш для существующих вопросов в SQL.
+func QuestionTextHash(text string) string {
+	normalized := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, text)
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
 // IsCorrect проверяет, является ли выбранный вариант правильным
 func (q *Question) IsCorrect(selectedOption int) bool {
 	return selectedOption == q.CorrectOption
@@ -79,6 +130,11 @@ func (q *Question) CalculatePoints(isCorrect bool, responseTimeMs int64) int {
 	return 1
 }
 
+// IsApproved проверяет, что вопрос прошёл проверку и может быть задан в эфире
+func (q *Question) IsApproved() bool {
+	return q.ReviewStatus == QuestionReviewApproved
+}
+
 // OptionsCount возвращает количество вариантов ответа
 func (q *Question) OptionsCount() int {
 	return len(q.Options)
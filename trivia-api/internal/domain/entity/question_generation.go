@@ -0,0 +1,91 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// QuestionSourceAI — вопросы, сгенерированные языковой моделью (questions.source)
+const QuestionSourceAI = "ai"
+
+// Статусы генерации вопросов
+const (
+	QuestionGenerationRunning   = "running"
+	QuestionGenerationCompleted = "completed"
+	QuestionGenerationFailed    = "failed"
+)
+
+// DifficultySpread — сколько вопросов какой сложности (1–5) запрошено у модели, в JSONB
+type DifficultySpread map[int]int
+
+// Total возвращает общее число запрошенных вопросов
+func (s DifficultySpread) Total() int {
+	total := 0
+	for _, count := range s {
+		total += count
+	}
+	return total
+}
+
+// Scan реализует интерфейс sql.Scanner для DifficultySpread
+func (s *DifficultySpread) Scan(value interface{}) error {
+	var bytes []byte
+	switch v := value.(type) {
+	case nil:
+		*s = nil
+		return nil
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("failed to unmarshal JSONB value: unexpected type")
+	}
+	if len(bytes) == 0 {
+		*s = nil
+		return nil
+	}
+	return json.Unmarshal(bytes, s)
+}
+
+// Value реализует интерфейс driver.Valuer для DifficultySpread
+func (s DifficultySpread) Value() (driver.Value, error) {
+	if s == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(s)
+}
+
+// QuestionGeneration — запрос администратора на генерацию вопросов языковой моделью. Сгенерированные
+// вопросы попадают в пул со статусом pending_review и ссылаются на запрос через questions.generation_id.
+// Токены и стоимость запроса учитываются в дневной квоте администратора.
+type QuestionGeneration struct {
+	ID               uint             `gorm:"primaryKey" json:"id"`
+	AdminID          uint             `gorm:"not null;index" json:"admin_id"`
+	Provider         string           `gorm:"size:30;not null" json:"provider"`
+	Model            string           `gorm:"size:100;not null" json:"model"`
+	Topic            string           `gorm:"size:200;not null" json:"topic"`
+	Language         string           `gorm:"size:10;not null" json:"language"`
+	Template         string           `gorm:"size:50;not null" json:"template"`
+	CategoryID       *uint            `json:"category_id,omitempty"` // категория, назначаемая всем вопросам
+	Spread           DifficultySpread `gorm:"type:jsonb;not null" json:"difficulty_spread"`
+	Requested        int              `gorm:"not null" json:"requested"`
+	Status           string           `gorm:"size:20;not null;default:'running'" json:"status"`
+	Generated        int              `gorm:"not null;default:0" json:"generated"` // вопросов в ответе модели
+	Imported         int              `gorm:"not null;default:0" json:"imported"`
+	Duplicates       int              `gorm:"not null;default:0" json:"duplicates"`
+	Skipped          int              `gorm:"not null;default:0" json:"skipped"` // не прошли проверку или сверх запрошенного
+	PromptTokens     int              `gorm:"not null;default:0" json:"prompt_tokens"`
+	CompletionTokens int              `gorm:"not null;default:0" json:"completion_tokens"`
+	CostUSD          float64          `gorm:"type:numeric(12,6);not null;default:0" json:"cost_usd"`
+	Error            string           `gorm:"size:500;not null;default:''" json:"error,omitempty"`
+	CompletedAt      *time.Time       `json:"completed_at,omitempty"`
+	CreatedAt        time.Time        `json:"created_at"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (QuestionGeneration) TableName() string {
+	return "question_generations"
+}
@@ -0,0 +1,40 @@
+package entity
+
+import "time"
+
+// Внешние источники вопросов
+const (
+	QuestionSourceOpenTDB = "opentdb" // Open Trivia DB, https://opentdb.com
+)
+
+// Статусы импорта вопросов
+const (
+	QuestionImportRunning   = "running"
+	QuestionImportCompleted = "completed"
+	QuestionImportFailed    = "failed"
+)
+
+// QuestionImport — запуск импорта вопросов из внешнего источника. Импортированные вопросы
+// попадают в пул со статусом pending_review и ссылаются на запуск через questions.import_id.
+type QuestionImport struct {
+	ID               uint       `gorm:"primaryKey" json:"id"`
+	Source           string     `gorm:"size:30;not null" json:"source"`
+	ExternalCategory string     `gorm:"size:100;not null;default:''" json:"external_category,omitempty"` // фильтр категории источника
+	Difficulty       string     `gorm:"size:20;not null;default:''" json:"difficulty,omitempty"`         // фильтр сложности источника
+	Requested        int        `gorm:"not null" json:"requested"`
+	Status           string     `gorm:"size:20;not null;default:'running'" json:"status"`
+	TriggeredBy      *uint      `json:"triggered_by,omitempty"` // администратор; NULL — запуск по расписанию
+	Fetched          int        `gorm:"not null;default:0" json:"fetched"`
+	Imported         int        `gorm:"not null;default:0" json:"imported"`
+	Duplicates       int        `gorm:"not null;default:0" json:"duplicates"`    // уже есть в пуле или повторяются в ответе источника
+	Skipped          int        `gorm:"not null;default:0" json:"skipped"`       // не прошли проверку (варианты, длина текста)
+	Uncategorized    int        `gorm:"not null;default:0" json:"uncategorized"` // импортированы без категории: нет сопоставления
+	Error            string     `gorm:"size:500;not null;default:''" json:"error,omitempty"`
+	CompletedAt      *time.Time `json:"completed_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (QuestionImport) TableName() string {
+	return "question_imports"
+}
@@ -0,0 +1,30 @@
+package entity
+
+import "time"
+
+// Действия в истории проверки вопроса
+const (
+	QuestionReviewActionComment = "comment"
+	QuestionReviewActionSubmit  = "submit"
+	QuestionReviewActionAssign  = "assign"
+	QuestionReviewActionApprove = "approve"
+	QuestionReviewActionReject  = "reject"
+)
+
+// QuestionReviewComment — запись истории проверки вопроса: комментарий рецензента
+// или смена статуса (FromStatus/ToStatus заполнены только для переходов)
+type QuestionReviewComment struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	QuestionID uint      `gorm:"not null;index" json:"question_id"`
+	AuthorID   uint      `gorm:"not null" json:"author_id"`
+	Action     string    `gorm:"size:20;not null" json:"action"`
+	FromStatus string    `gorm:"size:20;not null;default:''" json:"from_status,omitempty"`
+	ToStatus   string    `gorm:"size:20;not null;default:''" json:"to_status,omitempty"`
+	Body       string    `gorm:"size:1000;not null;default:''" json:"body,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (QuestionReviewComment) TableName() string {
+	return "question_review_comments"
+}
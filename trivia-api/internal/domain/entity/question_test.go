@@ -206,3 +206,38 @@ func TestStringArray_Value_Nil(t *testing.T) {
 	require.True(t, ok, "Value должен возвращать []byte")
 	assert.Equal(t, "[]", string(bytes), "nil должен сериализоваться в []")
 }
+
+func TestQuestion_Localize(t *testing.T) {
+	question := &Question{
+		ID:        7,
+		Text:      "Столица Казахстана?",
+		Options:   StringArray{"Астана", "Алматы"},
+		TextKK:    "Қазақстанның астанасы?",
+		OptionsKK: StringArray{"Астана", "Алматы"},
+	}
+	translations := []QuestionTranslation{
+		{QuestionID: 7, Locale: "en", Text: "Capital of Kazakhstan?", Options: StringArray{"Astana", "Almaty"}},
+		{QuestionID: 7, Locale: "de", Text: "Hauptstadt?", Options: StringArray{"Astana"}}, // число вариантов не совпадает
+	}
+
+	en := question.Localize(translations, []string{"en", "ru"}, "ru")
+	assert.Equal(t, "en", en.Locale)
+	assert.Equal(t, "Capital of Kazakhstan?", en.Text)
+
+	// Казахский берётся из устаревших полей, если отдельного перевода нет
+	kk := question.Localize(translations, []string{"kk", "ru"}, "ru")
+	assert.Equal(t, "kk", kk.Locale)
+	assert.Equal(t, question.TextKK, kk.Text)
+
+	de := question.Localize(translations, []string{"de", "ru"}, "ru")
+	assert.Equal(t, "ru", de.Locale)
+	assert.Equal(t, question.Text, de.Text)
+	assert.Equal(t, question.Options, de.Options)
+}
+
+func TestQuestionTextHash_IgnoresCaseAndPunctuation(t *testing.T) {
+	assert.Equal(t, QuestionTextHash("Столица Франции?"), QuestionTextHash("  столица франции "))
+	assert.Equal(t, QuestionTextHash("What is 2+2?"), QuestionTextHash("what is 22"))
+	assert.NotEqual(t, QuestionTextHash("Столица Франции?"), QuestionTextHash("Столица Германии?"))
+	assert.Len(t, QuestionTextHash("Вопрос"), 64)
+}
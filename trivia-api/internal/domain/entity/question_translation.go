@@ -0,0 +1,69 @@
+package entity
+
+import "time"
+
+// QuestionTranslation — перевод вопроса на один язык. Варианты ответа идут в том же
+// порядке, что и основные: индекс правильного ответа общий для всех языков.
+// Машинный перевод (MachineTranslated) ещё не проверен администратором: его не получают
+// викторины, требующие проверенного казахского текста (Quiz.RequireVerifiedKK).
+type QuestionTranslation struct {
+	ID                uint        `gorm:"primaryKey" json:"id"`
+	QuestionID        uint        `gorm:"not null;uniqueIndex:idx_question_translations_question_locale" json:"question_id"`
+	Locale            string      `gorm:"size:5;not null;uniqueIndex:idx_question_translations_question_locale" json:"locale"`
+	Text              string      `gorm:"size:500;not null" json:"text"`
+	Options           StringArray `gorm:"type:jsonb;not null" json:"options"`
+	MachineTranslated bool        `gorm:"not null;default:false" json:"machine_translated"`
+	MTProvider        string      `gorm:"column:mt_provider;size:30;not null;default:''" json:"mt_provider,omitempty"` // Сервис машинного перевода
+	ReviewedBy        *uint       `json:"reviewed_by,omitempty"`                                                       // Администратор, проверивший машинный перевод
+	ReviewedAt        *time.Time  `json:"reviewed_at,omitempty"`
+	Question          *Question   `gorm:"foreignKey:QuestionID" json:"question,omitempty"` // Загружается в очереди проверки машинных переводов
+	CreatedAt         time.Time   `json:"created_at"`
+	UpdatedAt         time.Time   `json:"updated_at"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (QuestionTranslation) TableName() string {
+	return "question_translations"
+}
+
+// LocalizedQuestion — текст и варианты вопроса на выбранном языке
+type LocalizedQuestion struct {
+	Locale  string      // язык, на котором фактически отдан текст
+	Text    string      // текст вопроса
+	Options StringArray // варианты ответа
+}
+
+// Localize выбирает текст вопроса по цепочке языков chain (запрошенный, затем запасные).
+// baseLocale — язык основных полей Text/Options. Перевод на казахский без отдельной записи
+// берётся из устаревших полей TextKK/OptionsKK; они же важнее непроверенного машинного перевода.
+// Переводы с другим числом вариантов пропускаются, чтобы индекс правильного ответа не разошёлся
+// с основными вариантами.
+func (q *Question) Localize(translations []QuestionTranslation, chain []string, baseLocale string) LocalizedQuestion {
+	byLocale := make(map[string]QuestionTranslation, len(translations))
+	for _, t := range translations {
+		if t.MachineTranslated && t.Locale == "kk" && q.TextKK != "" {
+			continue
+		}
+		if t.QuestionID == q.ID && t.Text != "" && len(t.Options) == len(q.Options) {
+			byLocale[t.Locale] = t
+		}
+	}
+	if _, ok := byLocale["kk"]; !ok && q.TextKK != "" {
+		// Как и GetLocalizedOptions: без казахских вариантов используются основные
+		options := q.OptionsKK
+		if len(options) != len(q.Options) {
+			options = q.Options
+		}
+		byLocale["kk"] = QuestionTranslation{Locale: "kk", Text: q.TextKK, Options: options}
+	}
+
+	for _, locale := range chain {
+		if locale == baseLocale {
+			break
+		}
+		if t, ok := byLocale[locale]; ok {
+			return LocalizedQuestion{Locale: locale, Text: t.Text, Options: t.Options}
+		}
+	}
+	return LocalizedQuestion{Locale: baseLocale, Text: q.Text, Options: q.Options}
+}
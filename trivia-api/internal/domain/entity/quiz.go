@@ -17,20 +17,65 @@ const (
 	QuizQuestionSourceAdminOnly = "admin_only"
 )
 
+// Способы второго шанса: выбывший игрок возвращается в игру один раз за викторину
+const (
+	SecondChanceOff    = "off"    // второй шанс отключён
+	SecondChanceAd     = "ad"     // за просмотр рекламы
+	SecondChancePoints = "points" // за списание с баланса кошелька
+)
+
+// Режимы игры: как ошибки влияют на участие игрока
+const (
+	GameModeElimination = "elimination" // первая ошибка выбивает из игры
+	GameModePointsOnly  = "points_only" // без выбывания, побеждает лучший по очкам
+	GameModeLives       = "lives"       // игрок выбывает после Lives ошибок
+)
+
+// Способы разрешения ничьей между победителями
+const (
+	TieBreakNone         = "none"          // приз делится поровну между всеми победителями
+	TieBreakResponseTime = "response_time" // при равенстве побеждает меньшее суммарное время верных ответов
+)
+
+// Стратегии начисления очков за верный ответ; выбираются при планировании и записываются в результаты
+const (
+	ScoringFlat               = "flat"                // 1 очко за верный ответ
+	ScoringSpeedWeighted      = "speed_weighted"      // стоимость вопроса и бонус за скорость
+	ScoringStreakMultiplier   = "streak_multiplier"   // стоимость вопроса × длина серии верных ответов
+	ScoringDifficultyWeighted = "difficulty_weighted" // стоимость вопроса × сложность
+)
+
 // Quiz представляет викторину
 type Quiz struct {
-	ID                  uint       `gorm:"primaryKey" json:"id"`
-	Title               string     `gorm:"size:100;not null" json:"title"`
-	Description         string     `gorm:"size:500;not null;default:''" json:"description"`
-	ScheduledTime       time.Time  `gorm:"not null;index" json:"scheduled_time"`
-	Status              string     `gorm:"size:20;not null;default:'scheduled';index" json:"status"`
-	QuestionCount       int        `gorm:"not null;default:0" json:"question_count"`
-	PrizeFund           int        `gorm:"not null;default:1000000" json:"prize_fund"`
-	FinishOnZeroPlayers bool       `gorm:"not null;default:false" json:"finish_on_zero_players"`
-	QuestionSourceMode  string     `gorm:"size:20;not null;default:'hybrid'" json:"question_source_mode"`
-	Questions           []Question `gorm:"foreignKey:QuizID" json:"questions,omitempty"`
-	CreatedAt           time.Time  `json:"created_at"`
-	UpdatedAt           time.Time  `json:"updated_at"`
+	ID                  uint        `gorm:"primaryKey" json:"id"`
+	Title               string      `gorm:"size:100;not null" json:"title"`
+	Description         string      `gorm:"size:500;not null;default:''" json:"description"`
+	ScheduledTime       time.Time   `gorm:"not null;index" json:"scheduled_time"`
+	Status              string      `gorm:"size:20;not null;default:'scheduled';index" json:"status"`
+	QuestionCount       int         `gorm:"not null;default:0" json:"question_count"`
+	PrizeFund           int         `gorm:"not null;default:1000000" json:"prize_fund"`
+	FinishOnZeroPlayers bool        `gorm:"not null;default:false" json:"finish_on_zero_players"`
+	QuestionSourceMode  string      `gorm:"size:20;not null;default:'hybrid'" json:"question_source_mode"`
+	SecondChanceMode    string      `gorm:"size:20;not null;default:'off'" json:"second_chance_mode"`
+	SecondChanceCost    int64       `gorm:"not null;default:0" json:"second_chance_cost"`
+	SecondChanceAdID    *uint       `gorm:"column:second_chance_ad_asset_id" json:"second_chance_ad_asset_id,omitempty"`
+	MaxPlayers          int         `gorm:"not null;default:0" json:"max_players"` // Вместимость зала ожидания; 0 — без ограничения
+	Timing              QuizTiming  `gorm:"embedded" json:"timing"`                // Переопределения глобальных таймингов
+	GameMode            string      `gorm:"size:20;not null;default:'elimination'" json:"game_mode"`
+	Lives               int         `gorm:"not null;default:0" json:"lives"`            // Допустимое число ошибок в режиме lives
+	SuddenDeath         bool        `gorm:"not null;default:false" json:"sudden_death"` // Финал на выбывание, если после последнего вопроса осталось несколько игроков
+	TieBreak            string      `gorm:"size:20;not null;default:'none'" json:"tie_break"`
+	ScoringStrategy     string      `gorm:"size:30;not null;default:'flat'" json:"scoring_strategy"`
+	RequireVerifiedKK   bool        `gorm:"column:require_verified_kk;not null;default:false" json:"require_verified_kk"` // Только вопросы с проверенным казахским текстом
+	PrizeLadder         PrizeLadder `gorm:"type:jsonb" json:"prize_ladder,omitempty"`                                     // Ступени распределения фонда; пустая — поровну между победителями
+	AllowedCountries    StringArray `gorm:"type:jsonb;not null;default:'[]'" json:"allowed_countries,omitempty"`          // Страны (ISO 3166-1 alpha-2), игроки из которых получают призы; пусто — без ограничения
+	Sponsor             QuizSponsor `gorm:"embedded;embeddedPrefix:sponsor_" json:"sponsor"`
+	CategoryID          *uint       `gorm:"index" json:"category_id,omitempty"`
+	Category            *Category   `gorm:"foreignKey:CategoryID" json:"category,omitempty"` // Загружается в расписании
+	Tags                []Tag       `gorm:"many2many:quiz_tags" json:"tags,omitempty"`
+	Questions           []Question  `gorm:"foreignKey:QuizID" json:"questions,omitempty"`
+	CreatedAt           time.Time   `json:"created_at"`
+	UpdatedAt           time.Time   `json:"updated_at"`
 }
 
 // TableName определяет имя таблицы для GORM
@@ -57,3 +102,53 @@ func (q *Quiz) IsCompleted() bool {
 func (q *Quiz) IsAdminOnlyMode() bool {
 	return q.QuestionSourceMode == QuizQuestionSourceAdminOnly
 }
+
+// SecondChanceEnabled сообщает, можно ли вернуть выбывшего игрока в эту викторину
+func (q *Quiz) SecondChanceEnabled() bool {
+	return q.SecondChanceMode == SecondChanceAd || q.SecondChanceMode == SecondChancePoints
+}
+
+// HasCapacityLimit сообщает, ограничено ли число одновременных игроков викторины
+func (q *Quiz) HasCapacityLimit() bool {
+	return q.MaxPlayers > 0
+}
+
+// EffectiveGameMode возвращает режим игры; для викторин без режима — выбывание
+func (q *Quiz) EffectiveGameMode() string {
+	if q.GameMode == "" {
+		return GameModeElimination
+	}
+	return q.GameMode
+}
+
+// EliminatesPlayers сообщает, выбывают ли игроки в этой викторине
+func (q *Quiz) EliminatesPlayers() bool {
+	return q.EffectiveGameMode() != GameModePointsOnly
+}
+
+// EffectiveTieBreak возвращает способ разрешения ничьей; по умолчанию ничья не разрешается
+func (q *Quiz) EffectiveTieBreak() string {
+	if q.TieBreak == "" {
+		return TieBreakNone
+	}
+	return q.TieBreak
+}
+
+// EffectiveScoringStrategy возвращает стратегию начисления очков; по умолчанию — 1 очко за верный ответ
+func (q *Quiz) EffectiveScoringStrategy() string {
+	if q.ScoringStrategy == "" {
+		return ScoringFlat
+	}
+	return q.ScoringStrategy
+}
+
+// ApprovedQuestions возвращает вопросы викторины, прошедшие проверку: только они попадают в эфир
+func (q *Quiz) ApprovedQuestions() []Question {
+	approved := make([]Question, 0, len(q.Questions))
+	for _, question := range q.Questions {
+		if question.IsApproved() {
+			approved = append(approved, question)
+		}
+	}
+	return approved
+}
@@ -9,6 +9,7 @@ type QuizAdSlot struct {
 	QuestionAfter int       `gorm:"not null" json:"question_after"` // показывать после вопроса N
 	AdAssetID     uint      `gorm:"not null;index" json:"ad_asset_id"`
 	IsActive      bool      `gorm:"not null;default:true" json:"is_active"`
+	DurationSec   *int      `json:"duration_sec,omitempty"` // переопределение длительности показа (nil — длительность ресурса)
 	CreatedAt     time.Time `json:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at"`
 
@@ -20,3 +21,14 @@ type QuizAdSlot struct {
 func (QuizAdSlot) TableName() string {
 	return "quiz_ad_slots"
 }
+
+// PlaybackDuration возвращает длительность рекламной паузы слота
+func (s *QuizAdSlot) PlaybackDuration() time.Duration {
+	if s.DurationSec != nil && *s.DurationSec > 0 {
+		return time.Duration(*s.DurationSec) * time.Second
+	}
+	if s.AdAsset != nil {
+		return s.AdAsset.PlaybackDuration()
+	}
+	return 0
+}
@@ -0,0 +1,30 @@
+package entity
+
+import "time"
+
+// Моменты викторины, к которым привязываются объявления ведущего
+const (
+	AnnouncementTriggerBeforeStart   = "before_start"   // за OffsetSec секунд до начала
+	AnnouncementTriggerAfterQuestion = "after_question" // после ответа на вопрос QuestionNumber
+	AnnouncementTriggerBeforeFinale  = "before_finale"  // перед финальным вопросом
+)
+
+// QuizAnnouncement — объявление ведущего, запланированное администратором к моменту викторины.
+// Текст — шаблон с подстановками ({participants}, {prize_fund}, ...); рассылается один раз
+// событием quiz:announcement.
+type QuizAnnouncement struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	QuizID         uint       `gorm:"not null;index" json:"quiz_id"`
+	Trigger        string     `gorm:"size:20;not null" json:"trigger"`
+	OffsetSec      int        `gorm:"not null;default:0" json:"offset_sec,omitempty"`      // before_start: за сколько секунд до начала
+	QuestionNumber int        `gorm:"not null;default:0" json:"question_number,omitempty"` // after_question: после какого вопроса
+	Message        string     `gorm:"size:500;not null" json:"message"`
+	SentAt         *time.Time `json:"sent_at,omitempty"` // nil — ещё не отправлено
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// TableName возвращает имя таблицы
+func (QuizAnnouncement) TableName() string {
+	return "quiz_announcements"
+}
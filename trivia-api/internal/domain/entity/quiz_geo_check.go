@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// QuizGeoCheck — страна, из которой игрок подключался к викторине. Строка на каждую страну:
+// при завершении призовой викторины с ограничением регионов (Quiz.AllowedCountries)
+// игрок получает приз, только если все его подключения — из разрешённых стран.
+type QuizGeoCheck struct {
+	QuizID      uint      `gorm:"primaryKey" json:"quiz_id"`
+	UserID      uint      `gorm:"primaryKey" json:"user_id"`
+	Country     string    `gorm:"primaryKey;size:2" json:"country"` // ISO 3166-1 alpha-2; пусто — страна не определена
+	IPAddress   string    `gorm:"size:45;not null;default:''" json:"ip_address"`
+	Attempts    int       `gorm:"not null;default:1" json:"attempts"`
+	FirstSeenAt time.Time `gorm:"not null" json:"first_seen_at"`
+	LastSeenAt  time.Time `gorm:"not null" json:"last_seen_at"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (QuizGeoCheck) TableName() string {
+	return "quiz_geo_checks"
+}
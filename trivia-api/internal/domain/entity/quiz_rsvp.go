@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// QuizRSVP — предварительная запись пользователя на запланированную викторину.
+// По записям рассылаются напоминания перед стартом и оценивается ожидаемая аудитория.
+type QuizRSVP struct {
+	QuizID    uint      `gorm:"primaryKey;autoIncrement:false" json:"quiz_id"`
+	UserID    uint      `gorm:"primaryKey;autoIncrement:false;index" json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (QuizRSVP) TableName() string {
+	return "quiz_rsvps"
+}
@@ -0,0 +1,32 @@
+package entity
+
+// Места показа рекламы в ad_impressions: рекламная пауза между вопросами или брендинг спонсора
+const (
+	AdPlacementBreak          = "ad_break"
+	AdPlacementSponsorStart   = "sponsor_start"   // брендинг в событии quiz:start
+	AdPlacementSponsorResults = "sponsor_results" // брендинг в событии quiz:results_available
+)
+
+// QuizSponsor — постоянный брендинг спонсора викторины. Без имени спонсора брендинга нет.
+type QuizSponsor struct {
+	Name           string `gorm:"size:100;not null;default:''" json:"name"`
+	LogoAssetID    *uint  `json:"logo_asset_id,omitempty"`                                   // Изображение из рекламных материалов
+	PrimaryColor   string `gorm:"size:7;not null;default:''" json:"primary_color,omitempty"` // #rrggbb
+	SecondaryColor string `gorm:"size:7;not null;default:''" json:"secondary_color,omitempty"`
+	ClickURL       string `gorm:"size:500;not null;default:''" json:"click_url,omitempty"`
+}
+
+// IsEmpty сообщает, что спонсор у викторины не задан
+func (s QuizSponsor) IsEmpty() bool {
+	return s.Name == ""
+}
+
+// SponsorBranding — брендинг спонсора для клиентов, с разрешённой ссылкой на логотип
+type SponsorBranding struct {
+	Name           string `json:"name"`
+	LogoAssetID    *uint  `json:"logo_asset_id,omitempty"`
+	LogoURL        string `json:"logo_url,omitempty"`
+	PrimaryColor   string `json:"primary_color,omitempty"`
+	SecondaryColor string `json:"secondary_color,omitempty"`
+	ClickURL       string `json:"click_url,omitempty"`
+}
@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"math"
+	"time"
+)
+
+// QuizTiming — тайминги проведения, переопределённые для конкретной викторины.
+// Незаданное поле (nil) означает значение из глобальной конфигурации QuizManager.
+type QuizTiming struct {
+	QuestionDelayMs     *int     `gorm:"column:question_delay_ms" json:"question_delay_ms,omitempty"`           // Задержка перед отправкой вопроса
+	AnswerRevealDelayMs *int     `gorm:"column:answer_reveal_delay_ms" json:"answer_reveal_delay_ms,omitempty"` // Задержка перед раскрытием ответа
+	TimeLimitMultiplier *float64 `gorm:"column:time_limit_multiplier" json:"time_limit_multiplier,omitempty"`   // Множитель лимита времени всех вопросов
+	AdBreakDurationSec  *int     `gorm:"column:ad_break_duration_sec" json:"ad_break_duration_sec,omitempty"`   // Длительность рекламной паузы, если слот её не задаёт
+}
+
+// IsEmpty сообщает, что викторина проводится полностью по глобальным таймингам
+func (t QuizTiming) IsEmpty() bool {
+	return t.QuestionDelayMs == nil && t.AnswerRevealDelayMs == nil && t.TimeLimitMultiplier == nil && t.AdBreakDurationSec == nil
+}
+
+// QuestionTimeLimit возвращает лимит времени вопроса с учётом множителя викторины
+func (t QuizTiming) QuestionTimeLimit(timeLimitSec int) time.Duration {
+	multiplier := 1.0
+	if t.TimeLimitMultiplier != nil && *t.TimeLimitMultiplier > 0 {
+		multiplier = *t.TimeLimitMultiplier
+	}
+	return ScaleTimeLimit(timeLimitSec, multiplier)
+}
+
+// ScaleTimeLimit умножает лимит времени вопроса на multiplier с точностью до миллисекунды
+func ScaleTimeLimit(timeLimitSec int, multiplier float64) time.Duration {
+	if multiplier <= 0 {
+		multiplier = 1
+	}
+	return time.Duration(math.Round(float64(timeLimitSec)*1000*multiplier)) * time.Millisecond
+}
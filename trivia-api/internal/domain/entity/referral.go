@@ -0,0 +1,51 @@
+package entity
+
+import "time"
+
+// Статусы реферальной записи
+const (
+	ReferralStatusPending  = "pending"  // приглашённый ещё не завершил первую викторину
+	ReferralStatusCredited = "credited" // бонус начислен пригласившему
+	ReferralStatusRejected = "rejected" // запись отклонена антифрод-проверкой
+)
+
+// Причины отклонения реферальной записи
+const (
+	ReferralRejectSameIP       = "same_ip"
+	ReferralRejectSameDevice   = "same_device"
+	ReferralRejectIPLimit      = "ip_limit_exceeded"
+	ReferralRejectSelfReferral = "self_referral"
+)
+
+// ReferralCode хранит персональный код приглашения пользователя
+type ReferralCode struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;uniqueIndex" json:"user_id"`
+	Code      string    `gorm:"size:16;not null;uniqueIndex" json:"code"`
+	CreatedAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (ReferralCode) TableName() string {
+	return "referral_codes"
+}
+
+// Referral связывает приглашённого пользователя с пригласившим
+type Referral struct {
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	ReferrerID      uint       `gorm:"not null;index" json:"referrer_id"`
+	RefereeID       uint       `gorm:"not null;uniqueIndex" json:"referee_id"`
+	Code            string     `gorm:"size:16;not null" json:"code"`
+	Status          string     `gorm:"size:20;not null;default:'pending'" json:"status"`
+	RejectReason    string     `gorm:"size:50;not null;default:''" json:"reject_reason,omitempty"`
+	RefereeIP       string     `gorm:"column:referee_ip;size:50;not null;default:''" json:"-"`
+	RefereeDeviceID string     `gorm:"size:255;not null;default:''" json:"-"`
+	BonusPoints     int        `gorm:"not null;default:0" json:"bonus_points"`
+	CreatedAt       time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	CreditedAt      *time.Time `gorm:"type:timestamp" json:"credited_at,omitempty"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (Referral) TableName() string {
+	return "referrals"
+}
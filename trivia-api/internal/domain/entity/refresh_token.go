@@ -15,10 +15,22 @@ type RefreshToken struct {
 	IsExpired bool       `gorm:"not null;default:false;index" json:"is_expired"`
 	RevokedAt *time.Time `gorm:"index" json:"revoked_at,omitempty"`
 	Reason    string     `gorm:"size:255" json:"reason,omitempty"`
+
+	// Client is the session's client type (web/mobile); it selects the lifetime policy.
+	Client string `gorm:"size:16;not null;default:''" json:"client"`
+	// SessionStartedAt is the login time, carried over to every rotated token of the session.
+	SessionStartedAt time.Time `gorm:"not null" json:"session_started_at"`
+
+	// DeviceKey is the public key (JWK) the mobile client registered for the session; when set,
+	// every refresh must carry a proof signed by the matching private key. Carried over on rotation.
+	DeviceKey string `gorm:"type:text;not null;default:''" json:"-"`
+	// DeviceKeyThumbprint is the RFC 7638 thumbprint of DeviceKey.
+	DeviceKeyThumbprint string `gorm:"size:64;not null;default:''" json:"device_key_thumbprint,omitempty"`
 }
 
 // NewRefreshToken creates a refresh token entity using precomputed SHA-256 token hash.
 func NewRefreshToken(userID uint, tokenHash, deviceID, ipAddress, userAgent string, expiresAt time.Time) *RefreshToken {
+	now := time.Now()
 	return &RefreshToken{
 		UserID:    userID,
 		TokenHash: tokenHash,
@@ -26,8 +38,10 @@ func NewRefreshToken(userID uint, tokenHash, deviceID, ipAddress, userAgent stri
 		IPAddress: ipAddress,
 		UserAgent: userAgent,
 		ExpiresAt: expiresAt,
-		CreatedAt: time.Now(),
+		CreatedAt: now,
 		IsExpired: false,
+		// Rotation overwrites this with the original login time.
+		SessionStartedAt: now,
 	}
 }
 
@@ -54,6 +68,13 @@ func (rt *RefreshToken) SessionInfo() map[string]interface{} {
 		"created_at": rt.CreatedAt,
 		"expires_at": rt.ExpiresAt,
 		"is_expired": rt.IsExpired,
+		"client":     rt.Client,
+	}
+	if !rt.SessionStartedAt.IsZero() {
+		info["session_started_at"] = rt.SessionStartedAt
+	}
+	if rt.DeviceKeyThumbprint != "" {
+		info["device_key_thumbprint"] = rt.DeviceKeyThumbprint
 	}
 
 	if rt.RevokedAt != nil {
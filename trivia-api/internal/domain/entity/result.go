@@ -20,7 +20,14 @@ type Result struct {
 	IsEliminated         bool      `gorm:"not null;default:false" json:"is_eliminated"`
 	EliminatedOnQuestion *int      `gorm:"default:null" json:"eliminated_on_question,omitempty"`
 	EliminationReason    *string   `gorm:"size:50;default:null" json:"elimination_reason,omitempty"`
+	Revived              bool      `gorm:"not null;default:false" json:"revived"` // игрок возвращался в игру вторым шансом
+	GameMode             string    `gorm:"size:20;not null;default:'elimination'" json:"game_mode"`
+	LivesLeft            *int      `gorm:"default:null" json:"lives_left,omitempty"`                // оставшиеся жизни в режиме lives
+	TotalResponseTimeMs  int64     `gorm:"not null;default:0" json:"total_response_time_ms"`        // суммарное время засчитанных верных ответов
+	ScoringStrategy      string    `gorm:"size:30;not null;default:'flat'" json:"scoring_strategy"` // как начислялись очки в Score
 	CompletedAt          time.Time `gorm:"not null" json:"completed_at"`
+	FullName             string    `gorm:"-" json:"full_name,omitempty"` // имя и фамилия, если игрок разрешил их показывать
+	Anonymous            bool      `gorm:"-" json:"anonymous,omitempty"` // игрок скрыт из публичных результатов
 	CreatedAt            time.Time `json:"created_at"`
 	UpdatedAt            time.Time `json:"updated_at"`
 }
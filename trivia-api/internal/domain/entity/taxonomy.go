@@ -0,0 +1,46 @@
+package entity
+
+import "time"
+
+// Category — тематическая рубрика викторин и вопросов пула («История», «Кино»).
+// У викторины и вопроса не больше одной категории; slug используется в адресах и фильтрах.
+type Category struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Slug        string    `gorm:"size:64;not null;uniqueIndex" json:"slug"`
+	Name        string    `gorm:"size:100;not null" json:"name"`
+	Description string    `gorm:"size:500;not null;default:''" json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (Category) TableName() string {
+	return "categories"
+}
+
+// Tag — свободная метка викторины или вопроса («90-е», «новогодняя»); у объекта их может быть несколько
+type Tag struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Slug      string    `gorm:"size:64;not null;uniqueIndex" json:"slug"`
+	Name      string    `gorm:"size:100;not null" json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (Tag) TableName() string {
+	return "tags"
+}
+
+// CategoryLeaderboardEntry — итоги пользователя по завершённым викторинам одной категории
+type CategoryLeaderboardEntry struct {
+	UserID         uint   `json:"user_id"`
+	Username       string `json:"username"`
+	ProfilePicture string `json:"profile_picture"`
+	QuizzesPlayed  int64  `json:"quizzes_played"`
+	TotalScore     int64  `json:"total_score"`
+	WinsCount      int64  `json:"wins_count"`
+	TotalPrizeWon  int64  `json:"total_prize_won"`
+	// false — игрок скрыл себя из публичных лидербордов
+	ShowOnLeaderboards bool `json:"-"`
+	Anonymous          bool `json:"anonymous,omitempty"` // игрок показан заглушкой
+}
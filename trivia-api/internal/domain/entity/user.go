@@ -17,6 +17,7 @@ type User struct {
 	Password            string     `gorm:"size:100;not null" json:"-"`
 	PasswordAuthEnabled bool       `gorm:"not null;default:true" json:"-"`
 	ProfilePicture      string     `gorm:"size:255;not null;default:''" json:"profile_picture"`
+	AvatarStorageKey    string     `gorm:"size:255;not null;default:''" json:"-"` // ключ загруженного аватара в хранилище
 	FirstName           string     `gorm:"size:100;not null;default:''" json:"first_name"`
 	LastName            string     `gorm:"size:100;not null;default:''" json:"last_name"`
 	BirthDate           *time.Time `gorm:"type:date" json:"birth_date,omitempty"`
@@ -29,11 +30,27 @@ type User struct {
 	Language            string     `gorm:"size:5;not null;default:'ru'" json:"language"` // "ru" или "kk"
 	Role                string     `gorm:"size:20;not null;default:'user'" json:"-"`     // "user" или "admin"
 
+	// Настройки приватности публичного профиля (GET /api/users/:id/profile)
+	ProfilePublic     bool `gorm:"not null;default:true" json:"profile_public"`      // false — другим игрокам виден только ник и аватар
+	ShowRecentResults bool `gorm:"not null;default:true" json:"show_recent_results"` // показывать последние игры в профиле
+
+	// Приватность результатов викторин и лидербордов (администраторы видят всё)
+	ShowFullName       bool `gorm:"not null;default:false" json:"show_full_name"`      // показывать имя и фамилию рядом с результатами
+	ShowOnLeaderboards bool `gorm:"not null;default:true" json:"show_on_leaderboards"` // false — в публичных результатах и лидербордах вместо игрока заглушка
+
 	EmailVerifiedAt    *time.Time `gorm:"type:timestamp" json:"email_verified_at,omitempty"`
 	ProfileCompletedAt *time.Time `gorm:"type:timestamp" json:"profile_completed_at,omitempty"`
 	DeletedAt          *time.Time `gorm:"type:timestamp" json:"deleted_at,omitempty"`
 	DeletionReason     string     `gorm:"size:100;default:''" json:"deletion_reason,omitempty"`
 
+	// Защита от мультиаккаунтов: устройство регистрации и теневой бан (пользователю не показываются)
+	RegistrationDeviceHash string     `gorm:"size:64;not null;default:''" json:"-"`
+	ShadowBannedAt         *time.Time `gorm:"type:timestamp" json:"-"`
+	ShadowBanReason        string     `gorm:"size:255;not null;default:''" json:"-"`
+
+	// Страна по IP последнего входа (пакет internal/pkg/geo); пусто — не определена
+	LastLoginCountry string `gorm:"size:2;not null;default:''" json:"-"`
+
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
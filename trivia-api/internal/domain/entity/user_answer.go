@@ -4,6 +4,17 @@ import (
 	"time"
 )
 
+// AnswerReasonQuestionVoided помечает ответ на вопрос, снятый администратором с эфира:
+// ответ не приносит очков и не влияет на выбывание
+const AnswerReasonQuestionVoided = "question_voided"
+
+// AnswerReasonSecondChance помечает ответ, на котором игрок выбыл и был возвращён вторым шансом:
+// ответ не приносит очков, но засчитывается как пройденный
+const AnswerReasonSecondChance = "second_chance"
+
+// AnswerReasonSuddenDeath помечает ответ, на котором игрок выбыл в финале на выбывание
+const AnswerReasonSuddenDeath = "sudden_death"
+
 // UserAnswer представляет ответ пользователя на вопрос
 type UserAnswer struct {
 	ID                uint      `gorm:"primaryKey" json:"id"`
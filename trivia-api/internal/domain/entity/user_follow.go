@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// UserFollow — подписка пользователя FollowerID на игрока FolloweeID. Взаимная подписка делает игроков друзьями.
+type UserFollow struct {
+	FollowerID uint      `gorm:"primaryKey;autoIncrement:false" json:"follower_id"`
+	FolloweeID uint      `gorm:"primaryKey;autoIncrement:false;index" json:"followee_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (UserFollow) TableName() string {
+	return "user_follows"
+}
@@ -0,0 +1,38 @@
+package entity
+
+import "time"
+
+// UserRating — рейтинг мастерства игрока. Пересчитывается после каждой завершённой викторины
+// (RatingService); строка появляется после первой викторины с рейтингом.
+type UserRating struct {
+	UserID      uint      `gorm:"primaryKey" json:"-"`
+	Rating      int       `gorm:"not null" json:"rating"`
+	Games       int       `gorm:"not null;default:0" json:"games"` // викторин, учтённых в рейтинге
+	PeakRating  int       `gorm:"not null" json:"peak_rating"`     // лучший рейтинг за всё время
+	Provisional bool      `gorm:"-" json:"provisional"`            // рейтинг ещё не устоялся: мало викторин
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (UserRating) TableName() string {
+	return "user_ratings"
+}
+
+// RatingHistory — изменение рейтинга игрока по итогам одной викторины
+type RatingHistory struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	UserID       uint      `gorm:"not null;uniqueIndex:idx_rating_history_user_quiz" json:"-"`
+	QuizID       uint      `gorm:"not null;uniqueIndex:idx_rating_history_user_quiz" json:"quiz_id"`
+	Algorithm    string    `gorm:"size:20;not null" json:"algorithm"`
+	RatingBefore int       `gorm:"not null" json:"rating_before"`
+	RatingAfter  int       `gorm:"not null" json:"rating_after"`
+	Delta        int       `gorm:"not null" json:"delta"`
+	Rank         int       `gorm:"not null" json:"rank"`    // место в викторине
+	Players      int       `gorm:"not null" json:"players"` // участников с рейтингом
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (RatingHistory) TableName() string {
+	return "rating_history"
+}
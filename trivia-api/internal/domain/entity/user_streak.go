@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+// UserStreak — серии игрока между викторинами. Обновляется в транзакции подсчёта результата
+// викторины (ResultService.CalculateQuizResult), серия вопросов дня — в транзакции ответа на него
+// (DailyChallengeService.Answer); строка появляется после первой игры.
+type UserStreak struct {
+	UserID             uint       `gorm:"primaryKey" json:"-"`
+	QuizStreak         int        `gorm:"not null;default:0" json:"quiz_streak"`          // викторин подряд без пропуска завершённых
+	BestQuizStreak     int        `gorm:"not null;default:0" json:"best_quiz_streak"`     // самая длинная серия викторин
+	CorrectStreak      int        `gorm:"not null;default:0" json:"correct_streak"`       // верных ответов подряд, через игры
+	BestCorrectStreak  int        `gorm:"not null;default:0" json:"best_correct_streak"`  // самая длинная серия верных ответов
+	SurvivalStreak     int        `gorm:"not null;default:0" json:"survival_streak"`      // викторин подряд без выбывания
+	BestSurvivalStreak int        `gorm:"not null;default:0" json:"best_survival_streak"` // самая длинная серия без выбывания
+	LastQuizID         *uint      `json:"-"`                                              // последняя сыгранная викторина
+	DailyStreak        int        `gorm:"not null;default:0" json:"daily_streak"`         // дней подряд с ответом на вопрос дня
+	BestDailyStreak    int        `gorm:"not null;default:0" json:"best_daily_streak"`    // самая длинная серия вопросов дня
+	LastDailyDay       *time.Time `gorm:"type:date" json:"-"`                             // последний день с ответом на вопрос дня
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (UserStreak) TableName() string {
+	return "user_streaks"
+}
@@ -0,0 +1,122 @@
+package entity
+
+import "time"
+
+// Типы счетов кошелька
+const (
+	WalletAccountTypeUser   = "user"
+	WalletAccountTypeSystem = "system"
+)
+
+// Коды системных счетов (создаются миграцией)
+const (
+	SystemAccountPrizeExpense   = "prize_expense"         // источник призовых начислений
+	SystemAccountPayoutsPending = "payouts_pending"       // средства, зарезервированные под заявки на выплату
+	SystemAccountPayoutsSettled = "payouts_settled"       // выплаченные пользователям средства
+	SystemAccountSecondChance   = "second_chance_revenue" // списания за второй шанс в викторине
+)
+
+// Типы проводок
+const (
+	LedgerTxOpeningBalance = "opening_balance"
+	LedgerTxPrizeCredit    = "prize_credit"
+	LedgerTxPayoutHold     = "payout_hold"
+	LedgerTxPayoutRelease  = "payout_release"
+	LedgerTxPayoutSettle   = "payout_settle"
+	LedgerTxSecondChance   = "second_chance"
+	LedgerTxPrizeShare     = "prize_redistribution"
+)
+
+// Статусы заявки на выплату: requested → approved → paid; requested/approved → rejected; requested → cancelled
+const (
+	PayoutStatusRequested = "requested"
+	PayoutStatusApproved  = "approved"
+	PayoutStatusPaid      = "paid"
+	PayoutStatusRejected  = "rejected"
+	PayoutStatusCancelled = "cancelled"
+)
+
+// WalletAccount представляет счёт в двойной записи: кошелёк пользователя или системный счёт
+type WalletAccount struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    *uint     `gorm:"uniqueIndex" json:"user_id,omitempty"`
+	Type      string    `gorm:"size:20;not null" json:"type"`
+	Code      *string   `gorm:"size:50;uniqueIndex" json:"code,omitempty"`
+	Balance   int64     `gorm:"not null" json:"balance"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (WalletAccount) TableName() string {
+	return "wallet_accounts"
+}
+
+// LedgerTransaction объединяет проводки одной финансовой операции; сумма Entries всегда равна нулю.
+// Пара (Type, Reference) уникальна и делает повторную проводку той же операции невозможной.
+type LedgerTransaction struct {
+	ID          uint          `gorm:"primaryKey" json:"id"`
+	Type        string        `gorm:"size:30;not null" json:"type"`
+	Reference   string        `gorm:"size:100;not null" json:"reference"`
+	Description string        `gorm:"size:255;not null" json:"description"`
+	CreatedAt   time.Time     `json:"created_at"`
+	Entries     []LedgerEntry `gorm:"foreignKey:TransactionID" json:"entries,omitempty"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (LedgerTransaction) TableName() string {
+	return "ledger_transactions"
+}
+
+// IsBalanced проверяет, что проводки транзакции в сумме дают ноль
+func (t *LedgerTransaction) IsBalanced() bool {
+	if len(t.Entries) < 2 {
+		return false
+	}
+	var sum int64
+	for _, e := range t.Entries {
+		if e.Amount == 0 {
+			return false
+		}
+		sum += e.Amount
+	}
+	return sum == 0
+}
+
+// LedgerEntry - проводка по счёту: положительная сумма увеличивает баланс, отрицательная уменьшает
+type LedgerEntry struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	TransactionID uint      `gorm:"not null;index" json:"transaction_id"`
+	AccountID     uint      `gorm:"not null;index" json:"account_id"`
+	Amount        int64     `gorm:"not null" json:"amount"`
+	CreatedAt     time.Time `json:"created_at"`
+
+	Transaction *LedgerTransaction `gorm:"foreignKey:TransactionID" json:"transaction,omitempty"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (LedgerEntry) TableName() string {
+	return "ledger_entries"
+}
+
+// PayoutRequest - заявка пользователя на вывод призовых средств
+type PayoutRequest struct {
+	ID                uint       `gorm:"primaryKey" json:"id"`
+	UserID            uint       `gorm:"not null;index" json:"user_id"`
+	Amount            int64      `gorm:"not null" json:"amount"`
+	Status            string     `gorm:"size:20;not null" json:"status"`
+	Method            string     `gorm:"size:30;not null" json:"method"`
+	Destination       string     `gorm:"size:255;not null" json:"destination"`
+	AdminID           *uint      `json:"admin_id,omitempty"`
+	RejectReason      string     `gorm:"size:255;not null" json:"reject_reason,omitempty"`
+	ExternalReference string     `gorm:"size:255;not null" json:"external_reference,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	ApprovedAt        *time.Time `json:"approved_at,omitempty"`
+	PaidAt            *time.Time `json:"paid_at,omitempty"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (PayoutRequest) TableName() string {
+	return "payout_requests"
+}
@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+// WebAuthnCredential — ключ доступа (passkey) пользователя. Credential — запись ключа
+// библиотеки WebAuthn (открытый ключ, счётчик подписей, флаги); UserHandle — непрозрачный
+// идентификатор пользователя WebAuthn, общий для всех его ключей.
+type WebAuthnCredential struct {
+	ID           uint        `gorm:"primaryKey" json:"id"`
+	UserID       uint        `gorm:"not null;index" json:"-"`
+	UserHandle   []byte      `gorm:"type:bytea;not null" json:"-"`
+	CredentialID []byte      `gorm:"type:bytea;not null;uniqueIndex" json:"-"`
+	Credential   JSONPayload `gorm:"type:jsonb;not null" json:"-"`
+	Name         string      `gorm:"size:100;not null;default:''" json:"name"`
+	LastUsedAt   *time.Time  `json:"last_used_at,omitempty"`
+	CreatedAt    time.Time   `json:"created_at"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (WebAuthnCredential) TableName() string {
+	return "webauthn_credentials"
+}
@@ -0,0 +1,77 @@
+package entity
+
+import "time"
+
+// События викторин, на которые подписываются вебхуки
+const (
+	WebhookEventQuizScheduled = "quiz:scheduled"
+	WebhookEventQuizStarted   = "quiz:started"
+	WebhookEventQuizCompleted = "quiz:completed"
+	WebhookEventQuizWinners   = "quiz:winners"
+)
+
+// WebhookEvents — все события, доступные для подписки
+var WebhookEvents = []string{
+	WebhookEventQuizScheduled,
+	WebhookEventQuizStarted,
+	WebhookEventQuizCompleted,
+	WebhookEventQuizWinners,
+}
+
+// Статусы доставки вебхука
+const (
+	WebhookDeliveryPending   = "pending"   // ожидает отправки или повтора
+	WebhookDeliverySucceeded = "succeeded" // получатель ответил 2xx
+	WebhookDeliveryFailed    = "failed"    // попытки исчерпаны или получатель отключён
+)
+
+// WebhookEndpoint — адрес партнёра, на который отправляются события викторин.
+// Secret подписывает тело запроса (HMAC-SHA256) и наружу не отдаётся.
+type WebhookEndpoint struct {
+	ID          uint        `gorm:"primaryKey" json:"id"`
+	URL         string      `gorm:"size:500;not null" json:"url"`
+	Secret      string      `gorm:"size:128;not null" json:"-"`
+	Events      StringArray `gorm:"type:jsonb;not null" json:"events"`
+	Description string      `gorm:"size:255;not null;default:''" json:"description"`
+	Active      bool        `gorm:"not null;default:true" json:"active"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (WebhookEndpoint) TableName() string {
+	return "webhook_endpoints"
+}
+
+// Subscribed сообщает, подписан ли адрес на событие
+func (e *WebhookEndpoint) Subscribed(eventType string) bool {
+	for _, event := range e.Events {
+		if event == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery — отправка одного события на один адрес. EventID общий для всех
+// адресов события: по нему получатель отбрасывает повторы.
+type WebhookDelivery struct {
+	ID             uint        `gorm:"primaryKey" json:"id"`
+	EndpointID     uint        `gorm:"not null;index" json:"endpoint_id"`
+	EventID        string      `gorm:"size:36;not null" json:"event_id"`
+	EventType      string      `gorm:"size:64;not null" json:"event_type"`
+	Payload        JSONPayload `gorm:"type:jsonb;not null" json:"payload"`
+	Status         string      `gorm:"size:16;not null;default:'pending'" json:"status"`
+	Attempts       int         `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt  time.Time   `gorm:"not null" json:"next_attempt_at"`
+	LastStatusCode int         `gorm:"not null;default:0" json:"last_status_code,omitempty"`
+	LastError      string      `gorm:"size:500;not null;default:''" json:"last_error,omitempty"`
+	DeliveredAt    *time.Time  `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time   `json:"created_at"`
+	UpdatedAt      time.Time   `json:"updated_at"`
+}
+
+// TableName определяет имя таблицы для GORM
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// WSConnectionSample — снимок количества WebSocket-подключений одного экземпляра сервера
+type WSConnectionSample struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	InstanceID  string    `gorm:"size:100;not null" json:"instance_id"`
+	Connections int       `gorm:"not null" json:"connections"`
+	SampledAt   time.Time `gorm:"not null;index" json:"sampled_at"`
+}
+
+// TableName возвращает имя таблицы
+func (WSConnectionSample) TableName() string {
+	return "ws_connection_samples"
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+// DeviceAccounts — устройство регистрации и число созданных с него аккаунтов
+type DeviceAccounts struct {
+	DeviceHash    string    `json:"device_hash"`
+	AccountCount  int64     `json:"account_count"`
+	BannedCount   int64     `json:"shadow_banned_count"`
+	LastCreatedAt time.Time `json:"last_created_at"`
+}
+
+// AbuseRepository определяет методы защиты от мультиаккаунтов
+type AbuseRepository interface {
+	// CountAccountsByDevice возвращает число неудалённых аккаунтов, зарегистрированных с устройства
+	CountAccountsByDevice(deviceHash string) (int64, error)
+
+	// ListSharedDevices возвращает устройства, с которых зарегистрировано не меньше minAccounts аккаунтов
+	// (больше всего аккаунтов — первыми), и общее количество таких устройств
+	ListSharedDevices(minAccounts int, limit, offset int) ([]DeviceAccounts, int64, error)
+
+	// ListUsersByDevice возвращает аккаунты, зарегистрированные с устройства
+	ListUsersByDevice(deviceHash string) ([]entity.User, error)
+
+	// ListShadowBanned возвращает аккаунты с теневым баном (новые баны первыми) и общее количество
+	ListShadowBanned(limit, offset int) ([]entity.User, int64, error)
+
+	// SetShadowBan ставит (bannedAt != nil) или снимает теневой бан. apperrors.ErrNotFound, если пользователя нет.
+	SetShadowBan(userID uint, bannedAt *time.Time, reason string) error
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+// DailyCount — значение метрики за день
+type DailyCount struct {
+	Day   time.Time `json:"day"`
+	Count int64     `json:"count"`
+}
+
+// DailyParticipation — участие в викторинах за день
+type DailyParticipation struct {
+	Day                    time.Time `json:"day"`
+	Quizzes                int64     `json:"quizzes"`
+	Participants           int64     `json:"participants"`
+	AvgParticipantsPerQuiz float64   `json:"avg_participants_per_quiz"`
+}
+
+// CohortActivity — количество активных пользователей когорты через WeekOffset недель после регистрации
+type CohortActivity struct {
+	Cohort      time.Time `json:"cohort"`
+	WeekOffset  int       `json:"week_offset"`
+	ActiveUsers int64     `json:"active_users"`
+}
+
+// DailyAverage — среднее значение метрики за день
+type DailyAverage struct {
+	Day     time.Time `json:"day"`
+	Average float64   `json:"average"`
+	Peak    int64     `json:"peak"`
+}
+
+// AdImpressionStats — показы рекламы за день. Breaks и Impressions учитывают рекламные паузы,
+// SponsorImpressions — зрителей брендинга спонсоров в событиях викторины.
+type AdImpressionStats struct {
+	Day                time.Time `json:"day"`
+	Breaks             int64     `json:"breaks"`
+	Impressions        int64     `json:"impressions"`
+	SponsorImpressions int64     `json:"sponsor_impressions"`
+}
+
+// AdAssetImpressions — показы по рекламному материалу за период, включая логотипы спонсоров
+type AdAssetImpressions struct {
+	AdAssetID   uint   `json:"ad_asset_id"`
+	Title       string `json:"title"`
+	Breaks      int64  `json:"breaks"`
+	Impressions int64  `json:"impressions"`
+}
+
+// AnalyticsRepository выполняет агрегирующие запросы для админ-аналитики
+type AnalyticsRepository interface {
+	// CountActiveUsers возвращает количество уникальных активных пользователей за период
+	CountActiveUsers(from, to time.Time) (int64, error)
+
+	// DailyActiveUsers возвращает DAU по дням начиная с from
+	DailyActiveUsers(from time.Time) ([]DailyCount, error)
+
+	// DailyRegistrations возвращает количество регистраций по дням начиная с from
+	DailyRegistrations(from time.Time) ([]DailyCount, error)
+
+	// DailyParticipation возвращает участие в завершённых викторинах по дням начиная с from
+	DailyParticipation(from time.Time) ([]DailyParticipation, error)
+
+	// CohortSizes возвращает размеры недельных когорт регистрации начиная с from
+	CohortSizes(from time.Time) ([]DailyCount, error)
+
+	// CohortActivity возвращает активность недельных когорт по неделям после регистрации
+	CohortActivity(from time.Time) ([]CohortActivity, error)
+
+	// DailyConcurrentConnections возвращает среднее и пиковое число WS-подключений по дням
+	DailyConcurrentConnections(from time.Time) ([]DailyAverage, error)
+
+	// DailyAdImpressions возвращает показы рекламы по дням начиная с from
+	DailyAdImpressions(from time.Time) ([]AdImpressionStats, error)
+
+	// TopAdAssets возвращает рекламные материалы с наибольшим числом показов
+	TopAdAssets(from time.Time, limit int) ([]AdAssetImpressions, error)
+
+	// RecordConnectionSample сохраняет снимок количества WS-подключений
+	RecordConnectionSample(sample *entity.WSConnectionSample) error
+
+	// DeleteConnectionSamplesBefore удаляет устаревшие снимки подключений
+	DeleteConnectionSamplesBefore(before time.Time) error
+}
+
+// DifficultyAccuracy — ответы игрока на вопросы одной сложности. Difficulty = 0 — итог по всем сложностям.
+type DifficultyAccuracy struct {
+	Difficulty int   `json:"difficulty"`
+	Answered   int64 `json:"answered"`
+	Correct    int64 `json:"correct"`
+}
+
+// WeeklyResponseTime — среднее время ответа игрока за неделю
+type WeeklyResponseTime struct {
+	Week              time.Time `json:"week"`
+	Answers           int64     `json:"answers"`
+	AvgResponseTimeMs float64   `json:"avg_response_time_ms"`
+}
+
+// EliminationStage — сколько раз игрок выбывал на вопросе с номером QuestionNumber
+type EliminationStage struct {
+	QuestionNumber int   `json:"question_number"`
+	Count          int64 `json:"count"`
+}
+
+// CategoryAccuracy — ответы игрока на вопросы одной темы
+type CategoryAccuracy struct {
+	CategoryID uint   `json:"category_id"`
+	Slug       string `json:"slug"`
+	Name       string `json:"name"`
+	Answered   int64  `json:"answered"`
+	Correct    int64  `json:"correct"`
+}
+
+// UserAnalyticsRepository выполняет агрегирующие запросы по ответам одного игрока (user_answers).
+// Ответы на снятые с эфира вопросы не учитываются.
+type UserAnalyticsRepository interface {
+	// UserAccuracyByDifficulty возвращает ответы по сложности вопроса и итоговую строку (Difficulty = 0)
+	UserAccuracyByDifficulty(userID uint, from time.Time) ([]DifficultyAccuracy, error)
+
+	// UserWeeklyResponseTime возвращает среднее время ответа по неделям (без пропущенных вопросов)
+	UserWeeklyResponseTime(userID uint, from time.Time) ([]WeeklyResponseTime, error)
+
+	// UserEliminationStages возвращает гистограмму номеров вопросов, на которых игрок выбывал
+	UserEliminationStages(userID uint, from time.Time) ([]EliminationStage, error)
+
+	// UserCategoryAccuracy возвращает ответы по темам вопросов
+	UserCategoryAccuracy(userID uint, from time.Time) ([]CategoryAccuracy, error)
+
+	// CountUserQuizzes возвращает количество сыгранных викторин
+	CountUserQuizzes(userID uint, from time.Time) (int64, error)
+}
+
+// QuestionAnswerStats — агрегаты ответов на вопрос викторины для админ-статистики
+type QuestionAnswerStats struct {
+	QuestionID      uint    `json:"question_id"`
+	EliminatedCount int     `json:"eliminated_count"`
+	ByTimeout       int     `json:"by_timeout"`
+	ByWrongAnswer   int     `json:"by_wrong_answer"`
+	AvgResponseMs   float64 `json:"avg_response_ms"`
+	TotalAnswers    int     `json:"total_answers"`
+	PassedCount     int     `json:"passed_count"` // ответили правильно и не выбыли
+}
+
+// QuizAnswerStatsRepository выполняет агрегирующие запросы по ответам одной викторины
+type QuizAnswerStatsRepository interface {
+	// QuizAvgResponseTime возвращает среднее время ответа в викторине (без пропущенных вопросов)
+	QuizAvgResponseTime(quizID uint) (float64, error)
+
+	// QuizQuestionStats возвращает агрегаты ответов по вопросам викторины в порядке question_id
+	QuizQuestionStats(quizID uint) ([]QuestionAnswerStats, error)
+}
+
+// StatisticsBackend — источник статистики викторин и личной аналитики игроков: PostgreSQL
+// (по умолчанию) либо хранилище аналитики, наполняемое потоком событий игры
+type StatisticsBackend interface {
+	QuizAnswerStatsRepository
+	UserAnalyticsRepository
+}
+
+// AdImpressionRepository сохраняет показы рекламных пауз
+type AdImpressionRepository interface {
+	Create(impression *entity.AdImpression) error
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+// APIKeyRepository определяет методы для работы с API-ключами партнёров и их статистикой
+type APIKeyRepository interface {
+	// Create сохраняет ключ
+	Create(key *entity.APIKey) error
+
+	// GetByID возвращает ключ по ID; apperrors.ErrNotFound, если ключа нет
+	GetByID(id uint) (*entity.APIKey, error)
+
+	// GetByHash возвращает ключ по SHA-256 от ключа; apperrors.ErrNotFound, если ключа нет
+	GetByHash(keyHash string) (*entity.APIKey, error)
+
+	// List возвращает все ключи, новые первыми
+	List() ([]entity.APIKey, error)
+
+	// Revoke отзывает ключ; apperrors.ErrNotFound, если ключа нет или он уже отозван
+	Revoke(id uint, at time.Time) error
+
+	// AddUsage прибавляет счётчики к суточной статистике ключей и обновляет last_used_at
+	AddUsage(usage []entity.APIKeyUsage, lastUsed map[uint]time.Time) error
+
+	// ListUsage возвращает суточную статистику ключа начиная с since (старые дни первыми)
+	ListUsage(keyID uint, since time.Time) ([]entity.APIKeyUsage, error)
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+// AuditLogFilters задаёт фильтры журнала аудита; пустые поля не ограничивают выборку
+type AuditLogFilters struct {
+	Action     string
+	ActorID    *uint
+	TargetType string
+	TargetID   string
+	From       *time.Time
+	To         *time.Time
+}
+
+// AuditLogRepository интерфейс для работы с журналом аудита (только добавление и чтение)
+type AuditLogRepository interface {
+	// Create добавляет запись в журнал
+	Create(ctx context.Context, entry *entity.AuditLog) error
+
+	// List возвращает записи (новые первыми) и общее количество
+	List(filters AuditLogFilters, limit, offset int) ([]entity.AuditLog, int64, error)
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"time"
 )
 
@@ -25,9 +26,30 @@ type CacheRepository interface {
 	SRem(key string, members ...interface{}) error
 	// SIsMember checks if a member exists in a Set.
 	SIsMember(key string, member interface{}) (bool, error)
+	// SCard returns the number of members in a Set (0 if the key does not exist).
+	SCard(key string) (int64, error)
 	// Expire sets a TTL on a key (duration-based, unlike ExpireAt which is time-based).
 	Expire(key string, expiration time.Duration) error
 	// ExistsBatch проверяет существование нескольких ключей пакетно через Pipeline.
 	// Возвращает map[key]bool. Один roundtrip вместо N отдельных Exists.
 	ExistsBatch(keys []string) (map[string]bool, error)
+	// RPushTrim добавляет значения в конец списка, оставляет последние maxLen элементов
+	// и продлевает TTL списка. Используется для журнала событий викторины.
+	RPushTrim(key string, maxLen int64, expiration time.Duration, values ...interface{}) error
+	// LRange возвращает элементы списка с индексами start..stop (-1 — последний элемент).
+	LRange(key string, start, stop int64) ([]string, error)
+
+	// Redis Sorted Set operations for the waiting room admission queue
+	// ZAddNX adds a member with the given score unless it is already present; returns true if added.
+	ZAddNX(key string, score float64, member interface{}) (bool, error)
+	// ZRank returns the 0-based rank of a member ordered by score, or -1 if the member is absent.
+	ZRank(key string, member interface{}) (int64, error)
+	// ZRem removes members from a Sorted Set.
+	ZRem(key string, members ...interface{}) error
+	// ZCard returns the number of members in a Sorted Set (0 if the key does not exist).
+	ZCard(key string) (int64, error)
+	// ZRange returns members with ranks start..stop ordered by score (-1 is the last member).
+	ZRange(key string, start, stop int64) ([]string, error)
+	// WithContext возвращает репозиторий, выполняющий команды с контекстом ctx (отмена, трейсинг)
+	WithContext(ctx context.Context) CacheRepository
 }
@@ -0,0 +1,29 @@
+package repository
+
+import "time"
+
+// QuizResultCursor — позиция последнего результата страницы в результатах викторины
+// (порядок rank ASC, score DESC, id ASC)
+type QuizResultCursor struct {
+	Rank  int  `json:"r"`
+	Score int  `json:"s"`
+	ID    uint `json:"i"`
+}
+
+// UserResultCursor — позиция последнего результата страницы в истории игр пользователя
+// (порядок created_at DESC, id DESC)
+type UserResultCursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uint      `json:"i"`
+}
+
+// LeaderboardCursor — позиция последнего пользователя страницы в лидерборде
+// (порядок wins_count DESC, total_prize_won DESC, id ASC)
+type LeaderboardCursor struct {
+	WinsCount     int64 `json:"w"`
+	TotalPrizeWon int64 `json:"p"`
+	ID            uint  `json:"i"`
+	// Position — место пользователя в лидерборде; в запросе не участвует,
+	// нужно для нумерации мест на следующей странице
+	Position int `json:"n"`
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+// DailyLeaderboardEntry — игрок в лидерборде вопроса дня
+type DailyLeaderboardEntry struct {
+	UserID         uint
+	Username       string
+	ProfilePicture string
+	ResponseTimeMs int64
+	// false — игрок скрыл себя из публичных лидербордов
+	ShowOnLeaderboards bool
+}
+
+// DailyStreakUpdate продлевает серию вопросов дня игрока; вызывается в транзакции сохранения ответа
+type DailyStreakUpdate func(streak *entity.UserStreak)
+
+// DailyChallengeRepository хранит вопросы дня и ответы на них
+type DailyChallengeRepository interface {
+	// GetChallenge возвращает вопрос дня вместе с вопросом (ErrNotFound — ещё не выбран)
+	GetChallenge(day time.Time) (*entity.DailyChallenge, error)
+	// CreateChallenge сохраняет вопрос дня, если его ещё нет, и возвращает сохранённый:
+	// при одновременном выборе на нескольких инстансах побеждает первый
+	CreateChallenge(challenge *entity.DailyChallenge) (*entity.DailyChallenge, error)
+	// CountAnswers возвращает число ответов за день и верных среди них
+	CountAnswers(day time.Time) (total, correct int64, err error)
+	// GetAnswer возвращает ответ пользователя за день (ErrNotFound — не отвечал)
+	GetAnswer(day time.Time, userID uint) (*entity.DailyChallengeAnswer, error)
+	// SaveAnswer сохраняет ответ и в той же транзакции обновляет серию пользователя.
+	// Повторный ответ за день — ErrConflict.
+	SaveAnswer(answer *entity.DailyChallengeAnswer, update DailyStreakUpdate) error
+	// GetLeaderboard возвращает верно ответивших за день, быстрые первыми, и их количество;
+	// удалённые и теневые аккаунты не показываются
+	GetLeaderboard(day time.Time, limit, offset int) ([]DailyLeaderboardEntry, int64, error)
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+// EmailRepository определяет методы для журнала отправки email, событий доставки и недоставленных писем
+type EmailRepository interface {
+	// CreateMessage записывает письмо, принятое провайдером
+	CreateMessage(message *entity.EmailMessage) error
+
+	// ListMessages возвращает письма (новые первыми) и общее количество; пустые фильтры — любые
+	ListMessages(recipient, status string, limit, offset int) ([]entity.EmailMessage, int64, error)
+
+	// ListEvents возвращает события доставки письма по времени; apperrors.ErrNotFound, если письма нет
+	ListEvents(messageID uint) ([]entity.EmailDeliveryEvent, error)
+
+	// RecordEvent записывает событие доставки и связывает его с письмом по provider + provider_message_id.
+	// Если status не пуст, статус письма меняется, только когда текущий входит в fromStatuses.
+	// Повтор события с тем же ExternalID не записывается: recorded=false.
+	RecordEvent(event *entity.EmailDeliveryEvent, status string, fromStatuses []string) (recorded bool, err error)
+
+	// CreateDeadLetter записывает недоставленное письмо
+	CreateDeadLetter(letter *entity.EmailDeadLetter) error
+
+	// GetDeadLetter возвращает недоставленное письмо; apperrors.ErrNotFound, если его нет
+	GetDeadLetter(id uint) (*entity.EmailDeadLetter, error)
+
+	// ListDeadLetters возвращает недоставленные письма (новые первыми); pendingOnly — ещё не переотправленные
+	ListDeadLetters(pendingOnly bool, limit, offset int) ([]entity.EmailDeadLetter, int64, error)
+
+	// ClaimDeadLetterRetry отмечает письмо переотправляемым (retried_at = now), если оно ещё не переотправлено;
+	// false — письмо уже забрал другой запрос
+	ClaimDeadLetterRetry(id uint, now time.Time) (bool, error)
+
+	// UpdateDeadLetter сохраняет результат повторной отправки
+	UpdateDeadLetter(letter *entity.EmailDeadLetter) error
+}
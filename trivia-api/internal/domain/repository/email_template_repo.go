@@ -0,0 +1,27 @@
+package repository
+
+import "github.com/yourusername/trivia-api/internal/domain/entity"
+
+// EmailTemplateRepository определяет методы для версий шаблонов писем
+type EmailTemplateRepository interface {
+	// GetActive возвращает активную версию шаблона; apperrors.ErrNotFound, если её нет
+	GetActive(key, locale string) (*entity.EmailTemplate, error)
+
+	// GetVersion возвращает версию шаблона; apperrors.ErrNotFound, если её нет
+	GetVersion(key, locale string, version int) (*entity.EmailTemplate, error)
+
+	// ListActive возвращает активные версии всех шаблонов
+	ListActive() ([]entity.EmailTemplate, error)
+
+	// ListVersions возвращает версии шаблона (новые первыми)
+	ListVersions(key, locale string) ([]entity.EmailTemplate, error)
+
+	// CreateVersion сохраняет следующую версию шаблона и делает её активной
+	CreateVersion(tpl *entity.EmailTemplate) error
+
+	// Activate делает версию активной; apperrors.ErrNotFound, если её нет
+	Activate(key, locale string, version int) error
+
+	// DeactivateAll снимает активную версию: шаблон снова берётся из файлов
+	DeactivateAll(key, locale string) error
+}
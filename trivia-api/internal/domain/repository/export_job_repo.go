@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+// AnswerExportRow — ответ игрока для выгрузки ответов викторины
+type AnswerExportRow struct {
+	ID                uint      `json:"id"`
+	UserID            uint      `json:"user_id"`
+	Username          string    `json:"username"`
+	QuestionNumber    *int      `json:"question_number"` // номер вопроса в викторине из истории показа
+	QuestionID        uint      `json:"question_id"`
+	QuestionText      string    `json:"question_text"`
+	SelectedOption    int       `json:"selected_option"`
+	IsCorrect         bool      `json:"is_correct"`
+	ResponseTimeMs    int64     `json:"response_time_ms"`
+	Score             int       `json:"score"`
+	IsEliminated      bool      `json:"is_eliminated"`
+	EliminationReason string    `json:"elimination_reason"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// ExportJobRepository хранит задания фоновых выгрузок и читает данные для них порциями
+type ExportJobRepository interface {
+	Create(job *entity.ExportJob) error
+	// GetByID возвращает задание; apperrors.ErrNotFound, если его нет
+	GetByID(id uint) (*entity.ExportJob, error)
+	// ListByQuiz возвращает последние задания викторины, новые первыми
+	ListByQuiz(quizID uint, limit int) ([]entity.ExportJob, error)
+	// Claim забирает ожидающее задание или задание, обработчик которого не продлил аренду,
+	// переводит его в running и занимает до now+lease. nil — заданий нет.
+	Claim(now time.Time, lease time.Duration) (*entity.ExportJob, error)
+	// UpdateProgress сохраняет число записанных строк и продлевает аренду
+	UpdateProgress(id uint, rowsDone int64, leaseUntil time.Time) error
+	// Save сохраняет задание целиком
+	Save(job *entity.ExportJob) error
+	// ListExpired возвращает выполненные задания, срок хранения файлов которых истёк
+	ListExpired(now time.Time, limit int) ([]entity.ExportJob, error)
+
+	// CountQuizResults возвращает количество результатов викторины
+	CountQuizResults(quizID uint) (int64, error)
+	// ListQuizResultsAfter возвращает результаты в порядке (rank, id) после заданной пары
+	ListQuizResultsAfter(quizID uint, afterRank int, afterID uint, limit int) ([]entity.Result, error)
+	// CountQuizAnswers возвращает количество ответов викторины
+	CountQuizAnswers(quizID uint) (int64, error)
+	// ListQuizAnswersAfter возвращает ответы викторины с id больше afterID в порядке id
+	ListQuizAnswersAfter(quizID uint, afterID uint, limit int) ([]AnswerExportRow, error)
+}
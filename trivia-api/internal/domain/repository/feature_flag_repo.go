@@ -0,0 +1,21 @@
+package repository
+
+import "github.com/yourusername/trivia-api/internal/domain/entity"
+
+// FeatureFlagRepository определяет методы для работы с флагами функций
+type FeatureFlagRepository interface {
+	// List возвращает все флаги, отсортированные по ключу
+	List() ([]entity.FeatureFlag, error)
+
+	// GetByKey возвращает флаг по ключу; apperrors.ErrNotFound, если флага нет
+	GetByKey(key string) (*entity.FeatureFlag, error)
+
+	// Create создаёт флаг; apperrors.ErrConflict, если флаг с таким ключом уже есть
+	Create(flag *entity.FeatureFlag) error
+
+	// Update сохраняет изменённый флаг
+	Update(flag *entity.FeatureFlag) error
+
+	// Delete удаляет флаг по ключу; apperrors.ErrNotFound, если флага нет
+	Delete(key string) error
+}
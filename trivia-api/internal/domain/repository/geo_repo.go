@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+// GeoBlockedAttempt — подключение к викторине из страны вне её списка разрешённых
+type GeoBlockedAttempt struct {
+	QuizID     uint      `json:"quiz_id"`
+	QuizTitle  string    `json:"quiz_title"`
+	UserID     uint      `json:"user_id"`
+	Username   string    `json:"username"`
+	Country    string    `json:"country"` // пусто — страна не определена
+	IPAddress  string    `json:"ip_address"`
+	Attempts   int       `json:"attempts"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+}
+
+// GeoCountryCount — число игроков викторины, подключавшихся из страны
+type GeoCountryCount struct {
+	Country string `json:"country"`
+	Players int64  `json:"players"`
+	Allowed bool   `json:"allowed"`
+}
+
+// GeoRepository хранит страны подключений игроков
+type GeoRepository interface {
+	// RecordQuizJoin сохраняет подключение к викторине: повторное из той же страны
+	// увеличивает attempts и обновляет ip_address и last_seen_at
+	RecordQuizJoin(check *entity.QuizGeoCheck) error
+	// UpdateLoginCountry записывает страну последнего входа пользователя
+	UpdateLoginCountry(userID uint, country string) error
+	// ListBlocked возвращает подключения к викторинам с ограничением регионов из стран вне списка
+	// (новые первыми) и общее количество; quizID = 0 — все викторины. Неопределённая страна
+	// считается заблокированной, если не allowUnknown.
+	ListBlocked(quizID uint, allowUnknown bool, limit, offset int) ([]GeoBlockedAttempt, int64, error)
+	// CountByCountry возвращает число игроков викторины по странам подключения
+	CountByCountry(quizID uint) ([]GeoCountryCount, error)
+}
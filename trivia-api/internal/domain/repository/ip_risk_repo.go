@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+// RiskySession — подключение к викторине с адреса с оценкой риска для админ-панели
+type RiskySession struct {
+	QuizID     uint               `json:"quiz_id"`
+	QuizTitle  string             `json:"quiz_title"`
+	UserID     uint               `json:"user_id"`
+	Username   string             `json:"username"`
+	IPAddress  string             `json:"ip_address"`
+	Score      int                `json:"score"`
+	Reasons    entity.StringArray `json:"reasons"`
+	Attempts   int                `json:"attempts"`
+	LastSeenAt time.Time          `json:"last_seen_at"`
+}
+
+// IPRiskRepository хранит оценки риска адресов подключений и проверки победителей
+type IPRiskRepository interface {
+	// RecordSession сохраняет оценку адреса подключения к викторине: повторное подключение
+	// с того же адреса увеличивает attempts и обновляет оценку
+	RecordSession(session *entity.QuizSessionRisk) error
+	// ListSessions возвращает подключения с оценкой не ниже minScore (новые первыми) и общее
+	// количество; quizID = 0 — все викторины
+	ListSessions(quizID uint, minScore, limit, offset int) ([]RiskySession, int64, error)
+	// ListReviews возвращает проверки победителей, старые первыми; пустой status — все
+	ListReviews(status string, quizID uint, limit, offset int) ([]entity.PrizeRiskReview, int64, error)
+	// GetReview возвращает проверку победителя
+	GetReview(id uint) (*entity.PrizeRiskReview, error)
+	// ResolveReview записывает решение, только если проверка ещё ожидает его (иначе ErrConflict)
+	ResolveReview(review *entity.PrizeRiskReview) error
+	// ForfeitPrize записывает отказ и в той же транзакции снимает с победителя задержанный приз:
+	// results.is_winner и prize_fund, users.wins_count и total_prize_won
+	ForfeitPrize(review *entity.PrizeRiskReview) error
+}
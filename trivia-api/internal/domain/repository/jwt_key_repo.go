@@ -28,6 +28,7 @@ type JWTKeyRepository interface {
 
 	// DeactivateKey помечает ключ как неактивный (ротированный).
 	// Устанавливает IsActive = false и RotatedAt = rotatedAtTime.
+	// Возвращает ErrNotFound, если активного ключа с таким ID нет (например, его уже ротировал другой инстанс).
 	DeactivateKey(ctx context.Context, id string, rotatedAtTime time.Time) error
 
 	// ListAllKeys извлекает все ключи из хранилища (например, для инициализации).
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+// MagicLinkRepository хранит ссылки для входа без пароля
+type MagicLinkRepository interface {
+	Create(link *entity.MagicLink) error
+	// GetByTokenHash возвращает ссылку по HMAC токена (ErrNotFound, если её нет)
+	GetByTokenHash(tokenHash string) (*entity.MagicLink, error)
+	// GetLatestByUserID возвращает последнюю выданную пользователю ссылку (ErrNotFound, если их нет)
+	GetLatestByUserID(userID uint) (*entity.MagicLink, error)
+	// CountSince возвращает число ссылок, выданных пользователю после since
+	CountSince(userID uint, since time.Time) (int64, error)
+	// Consume помечает ссылку использованной; false — её уже использовали
+	Consume(id uint, at time.Time) (bool, error)
+	DeleteByUserID(userID uint) error
+}
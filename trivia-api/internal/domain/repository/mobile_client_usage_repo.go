@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+// MobileClientUsageRepository хранит суточную статистику запросов мобильных клиентов
+type MobileClientUsageRepository interface {
+	// AddUsage прибавляет счётчики к суточной статистике
+	AddUsage(usage []entity.MobileClientUsage) error
+
+	// ListUsage возвращает статистику начиная с since (старые дни первыми)
+	ListUsage(since time.Time) ([]entity.MobileClientUsage, error)
+}
@@ -0,0 +1,17 @@
+package repository
+
+import "github.com/yourusername/trivia-api/internal/domain/entity"
+
+// ModerationRepository хранит очередь модерации пользовательского текста
+type ModerationRepository interface {
+	// Create сохраняет случай; прежние ожидающие случаи того же поля пользователя
+	// помечаются superseded — решение по старому тексту уже не нужно
+	Create(moderationCase *entity.ModerationCase) error
+	// GetByID возвращает случай; apperrors.ErrNotFound, если его нет
+	GetByID(id uint) (*entity.ModerationCase, error)
+	// List возвращает случаи со статусом status (пусто — все) и полем field (пусто — все),
+	// старые первыми, и общее количество
+	List(status, field string, limit, offset int) ([]entity.ModerationCase, int64, error)
+	// Resolve записывает решение по ожидающему случаю; apperrors.ErrConflict, если решение уже принято
+	Resolve(moderationCase *entity.ModerationCase) error
+}
@@ -0,0 +1,21 @@
+package repository
+
+import "github.com/yourusername/trivia-api/internal/domain/entity"
+
+// NotificationRepository интерфейс для работы с in-app уведомлениями
+type NotificationRepository interface {
+	// CreateBatch сохраняет несколько уведомлений одной вставкой
+	CreateBatch(notifications []*entity.Notification) error
+
+	// ListByUserID возвращает уведомления пользователя (новые первыми) и общее количество
+	ListByUserID(userID uint, unreadOnly bool, limit, offset int) ([]entity.Notification, int64, error)
+
+	// CountUnread возвращает количество непрочитанных уведомлений пользователя
+	CountUnread(userID uint) (int64, error)
+
+	// MarkRead отмечает уведомления пользователя прочитанными и возвращает число изменённых записей
+	MarkRead(userID uint, ids []uint) (int64, error)
+
+	// MarkAllRead отмечает все уведомления пользователя прочитанными
+	MarkAllRead(userID uint) (int64, error)
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"gorm.io/gorm"
+)
+
+// OutboxRepository определяет методы для работы с таблицей доменных событий (outbox)
+type OutboxRepository interface {
+	// Append записывает события в транзакции tx (nil — отдельной записью)
+	Append(tx *gorm.DB, events ...entity.OutboxEvent) error
+
+	// ClaimDue забирает до limit событий, срок обработки которых наступил к now, и откладывает
+	// их следующую попытку на lease, чтобы другие инстансы не обработали их одновременно
+	ClaimDue(now time.Time, lease time.Duration, limit int) ([]entity.OutboxEvent, error)
+
+	// Update сохраняет результат обработки события
+	Update(event *entity.OutboxEvent) error
+
+	// DeleteProcessedBefore удаляет события, обработанные раньше before
+	DeleteProcessedBefore(before time.Time) (int64, error)
+}
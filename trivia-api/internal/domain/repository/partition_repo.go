@@ -0,0 +1,24 @@
+package repository
+
+import "time"
+
+// QuizPartition — секция таблицы, секционированной по quiz_id (user_answers, results)
+type QuizPartition struct {
+	Table string // родительская таблица
+	Name  string // имя секции
+	From  uint   // нижняя граница quiz_id, включительно
+	To    uint   // верхняя граница quiz_id, не включительно
+}
+
+// PartitionRepository управляет секциями user_answers и results.
+// Секции создаёт БД (функция ensure_quiz_partitions при вставке викторины);
+// репозиторий нужен для отсоединения старых секций.
+type PartitionRepository interface {
+	// ListQuizPartitions возвращает присоединённые секции user_answers и results
+	ListQuizPartitions() ([]QuizPartition, error)
+	// QuizRangeSettledBefore сообщает, что все викторины с id из [from, to) завершены или отменены
+	// и запланированы раньше before, а новых викторин в диапазоне не появится
+	QuizRangeSettledBefore(from, to uint, before time.Time) (bool, error)
+	// ArchivePartition отсоединяет секцию от родительской таблицы и переносит её в схему archive
+	ArchivePartition(partition QuizPartition) error
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+// PrizeClaimFilters задаёт фильтры списка заявок на призы
+type PrizeClaimFilters struct {
+	Status string
+	QuizID *uint
+	UserID *uint
+}
+
+// PrizeClaimShare — доля невостребованного приза, добавленная к заявке другого победителя
+type PrizeClaimShare struct {
+	ClaimID     uint
+	QuizID      uint
+	UserID      uint
+	FromClaimID uint  // просроченная заявка, из которой перераспределена доля
+	Amount      int64 // размер доли
+	Verified    bool  // заявка уже подтверждена: долю нужно зачислить отдельно
+}
+
+// PrizeClaimRepository определяет методы для работы с заявками на призы
+type PrizeClaimRepository interface {
+	// Create сохраняет заявку; false, если заявка этого пользователя по викторине уже есть
+	Create(claim *entity.PrizeClaim) (bool, error)
+
+	GetByID(id uint) (*entity.PrizeClaim, error)
+	GetByQuizAndUser(quizID, userID uint) (*entity.PrizeClaim, error)
+
+	// List возвращает заявки по фильтрам (новые первыми) и общее количество
+	List(filters PrizeClaimFilters, limit, offset int) ([]entity.PrizeClaim, int64, error)
+
+	// Transition атомарно сохраняет статус и данные заявки, если её текущий статус равен fromStatus,
+	// и перечитывает заявку (сумма могла измениться при перераспределении).
+	// apperrors.ErrConflict, если статус уже изменился.
+	Transition(claim *entity.PrizeClaim, fromStatus string) error
+
+	// ResolveOverdue закрывает до limit заявок, не отправленных до дедлайна. При redistribute
+	// сумма просроченной заявки делится поровну между отправленными и подтверждёнными заявками
+	// той же викторины (остаток от деления сгорает); без получателей приз сгорает.
+	// results.prize_fund и users.total_prize_won корректируются в той же транзакции.
+	ResolveOverdue(now time.Time, redistribute bool, limit int) ([]entity.PrizeClaim, []PrizeClaimShare, error)
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+// PublicStatsRepository выполняет лёгкие запросы для публичных счётчиков лендинга
+type PublicStatsRepository interface {
+	// CountPlayers возвращает число неудалённых пользователей
+	CountPlayers() (int64, error)
+
+	// NextScheduledQuiz возвращает ближайшую запланированную викторину (ErrNotFound, если её нет)
+	NextScheduledQuiz() (*entity.Quiz, error)
+
+	// LastCompletedQuiz возвращает последнюю завершённую викторину (ErrNotFound, если её нет)
+	LastCompletedQuiz() (*entity.Quiz, error)
+
+	// OnlineConnections суммирует последние снимки WS-подключений экземпляров, сделанные после since
+	OnlineConnections(since time.Time) (int64, error)
+}
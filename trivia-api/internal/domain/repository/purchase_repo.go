@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+// PurchaseRepository хранит покупки в магазинах приложений и права пользователей
+type PurchaseRepository interface {
+	// SavePurchase создает покупку или обновляет её по (store, transaction_id)
+	SavePurchase(purchase *entity.Purchase) error
+
+	// GetByTransaction находит покупку по магазину и идентификатору покупки (apperrors.ErrNotFound, если нет)
+	GetByTransaction(store, transactionID string) (*entity.Purchase, error)
+
+	// ListByUser возвращает покупки пользователя, новые первыми
+	ListByUser(userID uint) ([]entity.Purchase, error)
+
+	// ReplaceEntitlements заменяет права пользователя в одной транзакции
+	ReplaceEntitlements(userID uint, entitlements []entity.UserEntitlement) error
+
+	// ListEntitlements возвращает права пользователя, включая истёкшие
+	ListEntitlements(userID uint) ([]entity.UserEntitlement, error)
+}
@@ -0,0 +1,36 @@
+package repository
+
+import "github.com/yourusername/trivia-api/internal/domain/entity"
+
+// PushDeviceRepository интерфейс для работы с push-токенами устройств
+type PushDeviceRepository interface {
+	// Upsert сохраняет токен; если токен уже зарегистрирован, переназначает его пользователю
+	Upsert(device *entity.PushDevice) error
+
+	// ListByUserID возвращает устройства пользователя
+	ListByUserID(userID uint) ([]entity.PushDevice, error)
+
+	// ListByUserIDs возвращает устройства нескольких пользователей
+	ListByUserIDs(userIDs []uint) ([]entity.PushDevice, error)
+
+	// ListAfterID возвращает устройства с ID больше afterID (для постраничной рассылки)
+	ListAfterID(afterID uint, limit int) ([]entity.PushDevice, error)
+
+	// DeleteByUserAndToken удаляет токен пользователя
+	DeleteByUserAndToken(userID uint, token string) error
+
+	// DeleteByToken удаляет токен, отклонённый провайдером
+	DeleteByToken(token string) error
+}
+
+// NotificationPreferenceRepository интерфейс для работы с настройками уведомлений
+type NotificationPreferenceRepository interface {
+	// GetByUserID возвращает настройки пользователя
+	GetByUserID(userID uint) (*entity.NotificationPreference, error)
+
+	// GetByUserIDs возвращает сохранённые настройки для набора пользователей
+	GetByUserIDs(userIDs []uint) ([]entity.NotificationPreference, error)
+
+	// Upsert сохраняет настройки пользователя
+	Upsert(pref *entity.NotificationPreference) error
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+// QuestionGenerationRepository хранит запросы генерации вопросов языковой моделью
+type QuestionGenerationRepository interface {
+	Create(generation *entity.QuestionGeneration) error
+	// Save сохраняет итоги генерации
+	Save(generation *entity.QuestionGeneration) error
+	// GetByID возвращает генерацию; apperrors.ErrNotFound, если её нет
+	GetByID(id uint) (*entity.QuestionGeneration, error)
+	// List возвращает генерации, новые первыми, и общее количество
+	List(limit, offset int) ([]entity.QuestionGeneration, int64, error)
+	// UsageSince возвращает, сколько вопросов администратор запросил с момента since (без неудачных
+	// генераций) и во сколько обошлись все его генерации за это время
+	UsageSince(adminID uint, since time.Time) (questions int, costUSD float64, err error)
+}
@@ -0,0 +1,14 @@
+package repository
+
+import "github.com/yourusername/trivia-api/internal/domain/entity"
+
+// QuestionImportRepository хранит историю импорта вопросов из внешних источников
+type QuestionImportRepository interface {
+	Create(run *entity.QuestionImport) error
+	// Save сохраняет итоги запуска
+	Save(run *entity.QuestionImport) error
+	// GetByID возвращает запуск; apperrors.ErrNotFound, если его нет
+	GetByID(id uint) (*entity.QuestionImport, error)
+	// List возвращает запуски, новые первыми, и общее количество
+	List(limit, offset int) ([]entity.QuestionImport, int64, error)
+}
@@ -19,12 +19,20 @@ type QuestionRepository interface {
 	MarkAsUsed(questionIDs []uint) error
 	CountByDifficulty(difficulty int) (int64, error)
 
-	// Гибридная адаптивная система: поиск с приоритетом викторины → пул
-	GetQuizQuestionByDifficulty(quizID uint, difficulty int, excludeIDs []uint) (*entity.Question, error)
-	GetPoolQuestionByDifficulty(difficulty int, excludeIDs []uint) (*entity.Question, error)
+	// Гибридная адаптивная система: поиск с приоритетом викторины → пул.
+	// verifiedKK — только вопросы с проверенным казахским текстом (text_kk не пуст).
+	GetQuizQuestionByDifficulty(quizID uint, difficulty int, excludeIDs []uint, verifiedKK bool) (*entity.Question, error)
+	GetPoolQuestionByDifficulty(difficulty int, excludeIDs []uint, verifiedKK bool) (*entity.Question, error)
+	// GetCategoryPoolQuestionByDifficulty ищет вопрос пула только в указанной категории
+	GetCategoryPoolQuestionByDifficulty(categoryID uint, difficulty int, excludeIDs []uint, verifiedKK bool) (*entity.Question, error)
+	// GetPoolQuestionBySeed детерминированно выбирает по seed доступный вопрос пула без медиа
+	// (для вопроса дня): при том же пуле и seed результат один и тот же
+	GetPoolQuestionBySeed(difficulty int, seed uint64) (*entity.Question, error)
 
 	// Статистика и управление пулом
 	GetPoolStats() (total int64, available int64, byDifficulty map[int]int64, err error)
+	// GetCategoryPoolStats — та же статистика по вопросам пула одной категории
+	GetCategoryPoolStats(categoryID uint) (total int64, available int64, byDifficulty map[int]int64, err error)
 	ResetPoolUsed() (int64, error)
 	// CountAvailablePool возвращает количество доступных (неиспользованных) вопросов в общем пуле
 	CountAvailablePool() (int64, error)
@@ -32,4 +40,11 @@ type QuestionRepository interface {
 	// История проведения викторины (факт заданных вопросов)
 	LogQuizQuestion(quizID uint, questionID uint, questionOrder int) error
 	GetQuizQuestionHistory(quizID uint) ([]entity.QuizQuestionHistory, error)
+
+	// CountMediaReferences возвращает число вопросов, ссылающихся на объект хранилища key
+	// (картинкой или аудио); копии викторин разделяют медиа оригинала
+	CountMediaReferences(key string) (int64, error)
+
+	// ExistingTextHashes возвращает те из хешей текста (entity.QuestionTextHash), вопросы с которыми уже есть
+	ExistingTextHashes(hashes []string) (map[string]bool, error)
 }
@@ -0,0 +1,30 @@
+package repository
+
+import "github.com/yourusername/trivia-api/internal/domain/entity"
+
+// QuestionReviewFilters задаёт фильтры очереди проверки вопросов
+type QuestionReviewFilters struct {
+	Status       string
+	ReviewerID   *uint
+	QuizID       *uint
+	ImportID     *uint // вопросы одного запуска импорта
+	GenerationID *uint // вопросы одной генерации моделью
+	PoolOnly     bool  // только вопросы общего пула (quiz_id IS NULL)
+}
+
+// QuestionReviewRepository определяет методы для проверки вопросов перед эфиром
+type QuestionReviewRepository interface {
+	// TransitionReview атомарно сохраняет review_status, reviewer_id и reviewed_at вопроса,
+	// если его текущий статус равен fromStatus, и пишет запись истории (если entry не nil).
+	// apperrors.ErrConflict, если статус уже изменился.
+	TransitionReview(question *entity.Question, fromStatus string, entry *entity.QuestionReviewComment) error
+
+	// AddComment добавляет комментарий в историю проверки вопроса
+	AddComment(comment *entity.QuestionReviewComment) error
+
+	// ListComments возвращает историю проверки вопроса в хронологическом порядке
+	ListComments(questionID uint) ([]entity.QuestionReviewComment, error)
+
+	// ListQueue возвращает вопросы по фильтрам и общее количество
+	ListQueue(filters QuestionReviewFilters, limit, offset int) ([]entity.Question, int64, error)
+}
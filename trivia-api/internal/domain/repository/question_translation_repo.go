@@ -0,0 +1,29 @@
+package repository
+
+import "github.com/yourusername/trivia-api/internal/domain/entity"
+
+// QuestionTranslationRepository определяет методы для работы с переводами вопросов
+type QuestionTranslationRepository interface {
+	// ListByQuestionIDs возвращает переводы нескольких вопросов одним запросом
+	ListByQuestionIDs(questionIDs []uint) ([]entity.QuestionTranslation, error)
+
+	// Get возвращает перевод вопроса на язык; apperrors.ErrNotFound, если перевода нет
+	Get(questionID uint, locale string) (*entity.QuestionTranslation, error)
+
+	// Upsert создаёт перевод или заменяет существующий перевод вопроса на тот же язык
+	Upsert(translation *entity.QuestionTranslation) error
+
+	// CreateIfMissing сохраняет перевод, только если у вопроса ещё нет перевода на этот язык.
+	// Возвращает false, если перевод уже был (например, сохранён администратором параллельно).
+	CreateIfMissing(translation *entity.QuestionTranslation) (bool, error)
+
+	// ListMachineTranslated возвращает непроверенные машинные переводы на язык вместе с вопросами
+	ListMachineTranslated(locale string, limit, offset int) ([]entity.QuestionTranslation, int64, error)
+
+	// ListUntranslated возвращает до limit вопросов с ID больше afterID (по возрастанию ID),
+	// у которых нет перевода на язык. Отклонённые на проверке вопросы не возвращаются.
+	ListUntranslated(locale string, afterID uint, limit int) ([]entity.Question, error)
+
+	// Delete удаляет перевод вопроса на язык; apperrors.ErrNotFound, если перевода нет
+	Delete(questionID uint, locale string) error
+}
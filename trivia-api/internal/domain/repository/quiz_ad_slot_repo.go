@@ -12,6 +12,8 @@ type QuizAdSlotRepository interface {
 
 	// ListByQuizID возвращает все слоты для викторины с загруженными AdAsset
 	ListByQuizID(quizID uint) ([]entity.QuizAdSlot, error)
+	// ListByQuizIDs возвращает слоты нескольких викторин одним запросом с загруженными AdAsset
+	ListByQuizIDs(quizIDs []uint) ([]entity.QuizAdSlot, error)
 
 	// GetByQuizAndQuestionAfter возвращает слот для конкретного вопроса викторины
 	GetByQuizAndQuestionAfter(quizID uint, questionAfter int) (*entity.QuizAdSlot, error)
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+// QuizAnnouncementRepository хранит объявления ведущего викторин
+type QuizAnnouncementRepository interface {
+	Create(announcement *entity.QuizAnnouncement) error
+	// GetByID возвращает объявление викторины; чужое объявление — ErrNotFound
+	GetByID(quizID, id uint) (*entity.QuizAnnouncement, error)
+	// ListByQuizID возвращает объявления викторины: сначала до начала (по убыванию отступа), затем по ходу игры
+	ListByQuizID(quizID uint) ([]entity.QuizAnnouncement, error)
+	// ListPending возвращает ещё не отправленные объявления викторины
+	ListPending(quizID uint) ([]entity.QuizAnnouncement, error)
+	Update(announcement *entity.QuizAnnouncement) error
+	Delete(quizID, id uint) error
+	// MarkSent помечает объявление отправленным. false — его уже отправил другой процесс.
+	MarkSent(id uint, sentAt time.Time) (bool, error)
+}
@@ -12,12 +12,16 @@ type QuizFilters struct {
 	Search   string     // Поиск по названию/описанию
 	DateFrom *time.Time // Фильтр по дате начала
 	DateTo   *time.Time // Фильтр по дате окончания
+	Category string     // Slug категории
+	Tag      string     // Slug тега
 }
 
 // QuizRepository определяет методы для работы с викторинами
 type QuizRepository interface {
 	Create(quiz *entity.Quiz) error
 	GetByID(id uint) (*entity.Quiz, error)
+	// GetByIDs возвращает викторины с указанными ID одним запросом (отсутствующие пропускаются)
+	GetByIDs(ids []uint) ([]entity.Quiz, error)
 	GetActive() (*entity.Quiz, error)
 	GetScheduled() ([]entity.Quiz, error)
 	GetWithQuestions(id uint) (*entity.Quiz, error)
@@ -32,6 +36,26 @@ type QuizRepository interface {
 	// UpdateScheduleInfo точечно обновляет scheduled_time, status и (опционально) finish_on_zero_players без full Save
 	UpdateScheduleInfo(quizID uint, scheduledTime time.Time, status string, finishOnZeroPlayers *bool) error
 	Update(quiz *entity.Quiz) error
+	// UpdateSecondChance точечно обновляет настройки второго шанса викторины
+	UpdateSecondChance(quizID uint, mode string, cost int64, adAssetID *uint) error
+	// UpdateMaxPlayers точечно обновляет вместимость зала ожидания викторины
+	UpdateMaxPlayers(quizID uint, maxPlayers int) error
+	// UpdateTiming точечно заменяет переопределения таймингов викторины
+	UpdateTiming(quizID uint, timing entity.QuizTiming) error
+	// UpdateGameMode точечно обновляет режим игры викторины
+	UpdateGameMode(quizID uint, mode string, lives int, suddenDeath bool) error
+	// UpdateTieBreak точечно обновляет способ разрешения ничьей между победителями
+	UpdateTieBreak(quizID uint, tieBreak string) error
+	// UpdateScoringStrategy точечно обновляет стратегию начисления очков
+	UpdateScoringStrategy(quizID uint, strategy string) error
+	// UpdateRequireVerifiedKK точечно обновляет требование проверенного казахского текста вопросов
+	UpdateRequireVerifiedKK(quizID uint, require bool) error
+	// UpdatePrizeLadder заменяет лестницу призов викторины (пустая — деление поровну)
+	UpdatePrizeLadder(quizID uint, ladder entity.PrizeLadder) error
+	// UpdateAllowedCountries заменяет список стран, игроки из которых получают призы (пустой — без ограничения)
+	UpdateAllowedCountries(quizID uint, countries entity.StringArray) error
+	// UpdateSponsor заменяет брендинг спонсора викторины (пустой — без спонсора)
+	UpdateSponsor(quizID uint, sponsor entity.QuizSponsor) error
 	List(limit, offset int) ([]entity.Quiz, error)
 	ListWithFilters(filters QuizFilters, limit, offset int) ([]entity.Quiz, int64, error) // Возвращает также total count
 	Delete(id uint) error
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+// QuizRSVPRepository хранит предварительные записи на викторины
+type QuizRSVPRepository interface {
+	// Create записывает пользователя на викторину; повторная запись не ошибка, created == false
+	Create(rsvp *entity.QuizRSVP) (created bool, err error)
+	// Delete отменяет запись; отсутствие записи не ошибка, deleted == false
+	Delete(quizID, userID uint) (deleted bool, err error)
+	Exists(quizID, userID uint) (bool, error)
+	// CountByQuizIDs возвращает число записей по викторинам; викторин без записей нет в результате
+	CountByQuizIDs(quizIDs []uint) (map[uint]int64, error)
+	// ListUserIDs возвращает ID записавшихся на викторину пользователей
+	ListUserIDs(quizID uint) ([]uint, error)
+}
@@ -0,0 +1,32 @@
+package repository
+
+import "github.com/yourusername/trivia-api/internal/domain/entity"
+
+// RatingLeaderboardEntry — игрок в лидерборде по рейтингу
+type RatingLeaderboardEntry struct {
+	UserID         uint
+	Username       string
+	ProfilePicture string
+	Rating         int
+	Games          int
+	// false — игрок скрыл себя из публичных лидербордов
+	ShowOnLeaderboards bool
+}
+
+// RatingUpdate вычисляет новые рейтинги участников викторины по текущим (без записи — нулевые)
+// и возвращает строки рейтинга и истории для сохранения
+type RatingUpdate func(current map[uint]entity.UserRating) ([]entity.UserRating, []entity.RatingHistory)
+
+// RatingRepository хранит рейтинг мастерства игроков и историю его изменений
+type RatingRepository interface {
+	// GetByUserID возвращает рейтинг пользователя; до первой викторины с рейтингом — нулевой
+	GetByUserID(userID uint) (*entity.UserRating, error)
+	// ApplyQuiz в одной транзакции блокирует рейтинги userIDs, вызывает update и сохраняет
+	// результат. Если викторина уже учтена, update не вызывается и возвращается false.
+	ApplyQuiz(quizID uint, userIDs []uint, update RatingUpdate) (bool, error)
+	// GetLeaderboard возвращает игроков по убыванию рейтинга и их общее количество;
+	// удалённые и теневые аккаунты не показываются
+	GetLeaderboard(limit, offset int) ([]RatingLeaderboardEntry, int64, error)
+	// ListHistory возвращает изменения рейтинга пользователя, новые первыми, и их количество
+	ListHistory(userID uint, limit, offset int) ([]entity.RatingHistory, int64, error)
+}
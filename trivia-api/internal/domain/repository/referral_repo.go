@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+// ReferralStats содержит агрегированную статистику конверсии рефералов
+type ReferralStats struct {
+	TotalReferrals    int64   `json:"total_referrals"`
+	PendingReferrals  int64   `json:"pending_referrals"`
+	CreditedReferrals int64   `json:"credited_referrals"`
+	RejectedReferrals int64   `json:"rejected_referrals"`
+	ConversionRate    float64 `json:"conversion_rate"`
+	TotalBonusPoints  int64   `json:"total_bonus_points"`
+}
+
+// ReferrerSummary содержит статистику по одному пригласившему пользователю
+type ReferrerSummary struct {
+	ReferrerID        uint   `json:"referrer_id"`
+	Username          string `json:"username"`
+	TotalReferrals    int64  `json:"total_referrals"`
+	CreditedReferrals int64  `json:"credited_referrals"`
+	RejectedReferrals int64  `json:"rejected_referrals"`
+	TotalBonusPoints  int64  `json:"total_bonus_points"`
+}
+
+// ReferralRepository интерфейс для работы с реферальной программой
+type ReferralRepository interface {
+	// CreateCode сохраняет персональный код пользователя
+	CreateCode(code *entity.ReferralCode) error
+
+	// GetCodeByUserID возвращает код пользователя
+	GetCodeByUserID(userID uint) (*entity.ReferralCode, error)
+
+	// GetCodeByCode находит владельца кода
+	GetCodeByCode(code string) (*entity.ReferralCode, error)
+
+	// Create сохраняет реферальную запись
+	Create(referral *entity.Referral) error
+
+	// GetByRefereeID возвращает реферальную запись приглашённого пользователя
+	GetByRefereeID(refereeID uint) (*entity.Referral, error)
+
+	// ListByReferrer возвращает приглашения пользователя с пагинацией
+	ListByReferrer(referrerID uint, limit, offset int) ([]entity.Referral, int64, error)
+
+	// CountByReferrerAndStatus возвращает количество приглашений пользователя в указанном статусе
+	CountByReferrerAndStatus(referrerID uint, status string) (int64, error)
+
+	// CountByRefereeIP возвращает количество приглашений с указанного IP
+	CountByRefereeIP(referrerID uint, ip string) (int64, error)
+
+	// ExistsForDevice проверяет, регистрировался ли уже приглашённый с этого устройства у данного пригласившего
+	ExistsForDevice(referrerID uint, deviceID string) (bool, error)
+
+	// MarkCredited атомарно переводит pending-запись в credited и начисляет бонус пригласившему.
+	// Возвращает false, если запись уже была обработана.
+	MarkCredited(referralID uint, bonusPoints int, creditedAt time.Time) (bool, error)
+
+	// GetStats возвращает агрегированную статистику за период (нулевые значения — без ограничения)
+	GetStats(from, to time.Time) (*ReferralStats, error)
+
+	// GetTopReferrers возвращает пригласивших с наибольшим числом засчитанных рефералов
+	GetTopReferrers(limit int) ([]ReferrerSummary, error)
+
+	// List возвращает реферальные записи с фильтром по статусу
+	List(status string, limit, offset int) ([]entity.Referral, int64, error)
+}
@@ -41,4 +41,11 @@ type RefreshTokenRepository interface {
 
 	// MarkOldestAsExpiredForUser помечает самые старые токены пользователя как истекшие, оставляя только limit токенов
 	MarkOldestAsExpiredForUser(userID uint, limit int) error
+
+	// SetDeviceKey привязывает токен к открытому ключу устройства (JWK и его отпечаток)
+	SetDeviceKey(id uint, deviceKey, thumbprint string) error
+
+	// MarkAsExpiredByDeviceKey помечает истекшими все токены пользователя, привязанные к ключу
+	// с отпечатком thumbprint, и возвращает их количество
+	MarkAsExpiredByDeviceKey(userID uint, thumbprint string) (int64, error)
 }
@@ -1,21 +1,50 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/yourusername/trivia-api/internal/domain/entity"
 	"gorm.io/gorm"
 )
 
+// WinnerCriteria — правило отбора победителей, зависящее от режима игры викторины
+type WinnerCriteria struct {
+	GameMode      string // entity.GameMode*
+	QuestionCount int    // число засчитанных вопросов викторины
+	TieBreak      string // entity.TieBreak*: как разрешать ничью между отобранными победителями
+}
+
 // ResultRepository определяет методы для работы с результатами
 type ResultRepository interface {
 	SaveUserAnswer(answer *entity.UserAnswer) error
+	// SaveUserAnswersBatch сохраняет ответы одним многострочным INSERT. Ответы, уже сохранённые
+	// для той же пары (пользователь, вопрос) викторины, пропускаются; возвращает число вставленных строк.
+	SaveUserAnswersBatch(answers []entity.UserAnswer) (int64, error)
+	// VoidQuestionAnswers аннулирует ответы на снятый с эфира вопрос: обнуляет очки и снимает
+	// правильность и выбывание. Возвращает число изменённых ответов.
+	VoidQuestionAnswers(quizID, questionID uint) (int64, error)
+	// ReviveEliminatedAnswer снимает выбывание с ответа пользователя, на котором он выбыл,
+	// и помечает ответ вторым шансом. Возвращает число изменённых ответов.
+	ReviveEliminatedAnswer(quizID, userID uint) (int64, error)
 	GetUserAnswers(userID uint, quizID uint) ([]entity.UserAnswer, error)
 	GetQuizUserAnswers(quizID uint) ([]entity.UserAnswer, error)
 	SaveResult(result *entity.Result) error
 	GetQuizResults(quizID uint, limit, offset int) ([]entity.Result, int64, error)
+	// GetQuizResultsAfter возвращает до limit результатов викторины после позиции after (nil — с начала)
+	GetQuizResultsAfter(quizID uint, after *QuizResultCursor, limit int) ([]entity.Result, error)
 	GetAllQuizResults(quizID uint) ([]entity.Result, error)
 	GetUserResult(userID uint, quizID uint) (*entity.Result, error)
 	GetUserResults(userID uint, limit, offset int) ([]entity.Result, int64, error)
-	CalculateRanks(tx *gorm.DB, quizID uint) error
+	// GetUserResultsAfter возвращает до limit результатов пользователя после позиции after (nil — с начала)
+	GetUserResultsAfter(userID uint, after *UserResultCursor, limit int) ([]entity.Result, error)
+	// GetUserBestWinStreak возвращает самую длинную серию побед пользователя подряд (по времени завершения викторин)
+	GetUserBestWinStreak(userID uint) (int, error)
+	// CalculateRanks пересчитывает ранги; при tieBreak = entity.TieBreakResponseTime равные результаты
+	// различаются суммарным временем верных ответов
+	CalculateRanks(tx *gorm.DB, quizID uint, tieBreak string) error
 	GetQuizWinners(quizID uint) ([]entity.Result, error)
-	FindAndUpdateWinners(tx *gorm.DB, quizID uint, questionCount int, totalPrizeFund int) ([]uint, int, error)
+	// FindAndUpdateWinners отбирает победителей по criteria, делит между ними фонд и отмечает их в results
+	FindAndUpdateWinners(tx *gorm.DB, quizID uint, criteria WinnerCriteria, totalPrizeFund int) ([]uint, int, error)
+	// WithContext возвращает репозиторий, выполняющий запросы с контекстом ctx (отмена, трейсинг)
+	WithContext(ctx context.Context) ResultRepository
 }
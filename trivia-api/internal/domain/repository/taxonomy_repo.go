@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+// TaxonomyRepository хранит категории и теги викторин и вопросов
+type TaxonomyRepository interface {
+	CreateCategory(category *entity.Category) error
+	UpdateCategory(category *entity.Category) error
+	DeleteCategory(id uint) error
+	// GetCategoryByID и GetCategoryBySlug возвращают apperrors.ErrNotFound, если категории нет
+	GetCategoryByID(id uint) (*entity.Category, error)
+	GetCategoryBySlug(slug string) (*entity.Category, error)
+	ListCategories() ([]entity.Category, error)
+
+	CreateTag(tag *entity.Tag) error
+	UpdateTag(tag *entity.Tag) error
+	DeleteTag(id uint) error
+	GetTagByID(id uint) (*entity.Tag, error)
+	ListTags() ([]entity.Tag, error)
+	// GetTagsBySlugs возвращает найденные теги; отсутствующие slug пропускаются
+	GetTagsBySlugs(slugs []string) ([]entity.Tag, error)
+
+	// SetQuizTaxonomy и SetQuestionTaxonomy заменяют категорию и набор тегов в одной транзакции
+	SetQuizTaxonomy(quizID uint, categoryID *uint, tags []entity.Tag) error
+	SetQuestionTaxonomy(questionID uint, categoryID *uint, tags []entity.Tag) error
+
+	// GetCategoryLeaderboard ранжирует пользователей по завершённым викторинам категории:
+	// победы, затем сумма очков. Возвращает также общее число участников.
+	GetCategoryLeaderboard(categoryID uint, limit, offset int) ([]entity.CategoryLeaderboardEntry, int64, error)
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+// FollowListItem — игрок в списке подписок или подписчиков
+type FollowListItem struct {
+	UserID         uint      `json:"user_id"`
+	Username       string    `json:"username"`
+	ProfilePicture string    `json:"profile_picture"`
+	Mutual         bool      `json:"mutual"` // подписка взаимная: игроки друзья
+	FollowedAt     time.Time `json:"followed_at"`
+}
+
+// UserFollowRepository хранит подписки между игроками
+type UserFollowRepository interface {
+	// Create подписывает пользователя на игрока; повторная подписка не ошибка, created == false
+	Create(follow *entity.UserFollow) (created bool, err error)
+	// Delete отменяет подписку; отсутствие подписки не ошибка, deleted == false
+	Delete(followerID, followeeID uint) (deleted bool, err error)
+	Exists(followerID, followeeID uint) (bool, error)
+	// CountFollowing возвращает число подписок пользователя
+	CountFollowing(followerID uint) (int64, error)
+	// CountFollowers возвращает число подписчиков игрока
+	CountFollowers(followeeID uint) (int64, error)
+	// CountCreatedSince возвращает число подписок пользователя, оформленных после since (защита от спама)
+	CountCreatedSince(followerID uint, since time.Time) (int64, error)
+	// ListFollowing возвращает страницу подписок пользователя (от новых к старым) и их общее число
+	ListFollowing(followerID uint, limit, offset int) ([]FollowListItem, int64, error)
+	// ListFollowers возвращает страницу подписчиков игрока (от новых к старым) и их общее число
+	ListFollowers(followeeID uint, limit, offset int) ([]FollowListItem, int64, error)
+	// ListFollowerIDs возвращает ID всех подписчиков игрока
+	ListFollowerIDs(followeeID uint) ([]uint, error)
+	// GetFriendsLeaderboard возвращает лидерборд из пользователя и его подписок (порядок как у общего лидерборда)
+	GetFriendsLeaderboard(userID uint, limit, offset int) ([]entity.User, int64, error)
+}
@@ -2,6 +2,13 @@ package repository
 
 import "github.com/yourusername/trivia-api/internal/domain/entity"
 
+// LegalVersionCount — число пользователей, чьё последнее согласие дано на эту пару версий
+type LegalVersionCount struct {
+	TOSVersion     string
+	PrivacyVersion string
+	Users          int64
+}
+
 // UserLegalAcceptanceRepository интерфейс для работы с согласиями пользователей
 type UserLegalAcceptanceRepository interface {
 	// Create сохраняет новое согласие пользователя
@@ -15,4 +22,10 @@ type UserLegalAcceptanceRepository interface {
 
 	// DeleteByUserID removes legal acceptance records for anonymization flows.
 	DeleteByUserID(userID uint) error
+
+	// CountLatestByVersion группирует неудалённых пользователей по версиям их последнего согласия
+	CountLatestByVersion() ([]LegalVersionCount, error)
+
+	// CountUsers возвращает число неудалённых пользователей — базу для охвата согласий
+	CountUsers() (int64, error)
 }
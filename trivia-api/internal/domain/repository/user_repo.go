@@ -1,13 +1,20 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/yourusername/trivia-api/internal/domain/entity"
 )
 
 // UserRepository определяет методы для работы с пользователями
 type UserRepository interface {
 	Create(user *entity.User) error
+	// CreateWithEvent создаёт пользователя и событие outbox, построенное по созданному
+	// пользователю (с ID), в одной транзакции
+	CreateWithEvent(user *entity.User, event func(user *entity.User) (entity.OutboxEvent, error)) error
 	GetByID(id uint) (*entity.User, error)
+	// GetByIDs возвращает пользователей с указанными ID одним запросом (отсутствующие пропускаются)
+	GetByIDs(ids []uint) ([]entity.User, error)
 	GetByEmail(email string) (*entity.User, error)
 	GetByUsername(username string) (*entity.User, error)
 	Update(user *entity.User) error
@@ -18,4 +25,8 @@ type UserRepository interface {
 	List(limit, offset int) ([]entity.User, error)
 	// GetLeaderboard возвращает пользователей для лидерборда с пагинацией и общим количеством
 	GetLeaderboard(limit, offset int) ([]entity.User, int64, error)
+	// GetLeaderboardAfter возвращает до limit пользователей лидерборда после позиции after (nil — с начала)
+	GetLeaderboardAfter(after *LeaderboardCursor, limit int) ([]entity.User, error)
+	// WithContext возвращает репозиторий, выполняющий запросы с контекстом ctx (отмена, трейсинг)
+	WithContext(ctx context.Context) UserRepository
 }
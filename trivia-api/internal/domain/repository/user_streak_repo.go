@@ -0,0 +1,12 @@
+package repository
+
+import "github.com/yourusername/trivia-api/internal/domain/entity"
+
+// UserStreakRepository читает серии игроков. Серии пишет ResultService в транзакции
+// подсчёта результата викторины.
+type UserStreakRepository interface {
+	// GetByUserID возвращает серии пользователя; до первой сыгранной викторины — нулевые
+	GetByUserID(userID uint) (*entity.UserStreak, error)
+	// GetByUserIDs возвращает серии пользователей одним запросом (без сыгранных викторин — нет в карте)
+	GetByUserIDs(userIDs []uint) (map[uint]entity.UserStreak, error)
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+var (
+	// ErrInsufficientFunds означает, что проводка сделала бы баланс кошелька пользователя отрицательным.
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	// ErrLedgerTransactionExists означает, что операция с таким типом и reference уже проведена.
+	ErrLedgerTransactionExists = errors.New("ledger transaction already exists")
+	// ErrUnbalancedTransaction означает, что сумма проводок транзакции не равна нулю.
+	ErrUnbalancedTransaction = errors.New("ledger transaction is not balanced")
+)
+
+// PayoutFilters задаёт фильтры списка заявок на выплату
+type PayoutFilters struct {
+	UserID *uint
+	Status string
+}
+
+// LedgerReconciliation - результат сверки двойной записи
+type LedgerReconciliation struct {
+	TotalBalance           int64 `json:"total_balance"`           // сумма балансов всех счетов, должна быть 0
+	UnbalancedTransactions int64 `json:"unbalanced_transactions"` // транзакции с ненулевой суммой проводок
+	MismatchedAccounts     int64 `json:"mismatched_accounts"`     // счета, у которых баланс не равен сумме проводок
+	UserBalances           int64 `json:"user_balances"`           // обязательства перед пользователями
+	PendingPayouts         int64 `json:"pending_payouts"`         // зарезервировано под заявки
+	SettledPayouts         int64 `json:"settled_payouts"`         // выплачено
+	PrizesIssued           int64 `json:"prizes_issued"`           // всего начислено призов
+	Balanced               bool  `json:"balanced"`
+}
+
+// WalletRepository интерфейс для работы с кошельками, журналом проводок и заявками на выплату
+type WalletRepository interface {
+	// GetOrCreateUserAccount возвращает кошелёк пользователя, создавая его при необходимости
+	GetOrCreateUserAccount(userID uint) (*entity.WalletAccount, error)
+
+	// GetSystemAccount возвращает системный счёт по коду
+	GetSystemAccount(code string) (*entity.WalletAccount, error)
+
+	// PostTransaction атомарно сохраняет транзакцию с проводками и обновляет балансы счетов
+	PostTransaction(txn *entity.LedgerTransaction) error
+
+	// ListEntriesByAccount возвращает проводки по счёту (новые первыми) и общее количество
+	ListEntriesByAccount(accountID uint, limit, offset int) ([]entity.LedgerEntry, int64, error)
+
+	// CreatePayoutRequest атомарно создаёт заявку и резервирует средства проводкой hold.
+	// Reference проводки заполняется после получения ID заявки.
+	CreatePayoutRequest(payout *entity.PayoutRequest, hold *entity.LedgerTransaction) error
+
+	// GetPayoutRequest возвращает заявку по ID
+	GetPayoutRequest(id uint) (*entity.PayoutRequest, error)
+
+	// ListPayoutRequests возвращает заявки по фильтрам и общее количество
+	ListPayoutRequests(filters PayoutFilters, limit, offset int) ([]entity.PayoutRequest, int64, error)
+
+	// SumPayouts возвращает сумму заявок пользователя в указанных статусах
+	SumPayouts(userID uint, statuses []string) (int64, error)
+
+	// TransitionPayout атомарно переводит заявку из fromStatus в payout.Status
+	// и, если posting не nil, проводит связанную транзакцию. Возвращает ErrConflict, если статус уже изменился.
+	TransitionPayout(payout *entity.PayoutRequest, fromStatus string, posting *entity.LedgerTransaction) error
+
+	// Reconcile выполняет сверку журнала проводок и балансов
+	Reconcile() (*LedgerReconciliation, error)
+}
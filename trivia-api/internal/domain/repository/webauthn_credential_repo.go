@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+// WebAuthnCredentialRepository хранит ключи доступа (passkeys)
+type WebAuthnCredentialRepository interface {
+	// Create сохраняет ключ (ErrConflict, если ключ с таким credential ID уже зарегистрирован)
+	Create(credential *entity.WebAuthnCredential) error
+	// GetByCredentialID возвращает ключ по его идентификатору (ErrNotFound, если его нет)
+	GetByCredentialID(credentialID []byte) (*entity.WebAuthnCredential, error)
+	ListByUserID(userID uint) ([]entity.WebAuthnCredential, error)
+	CountByUserID(userID uint) (int64, error)
+	// UpdateAfterLogin сохраняет запись ключа после входа (счётчик подписей, флаги)
+	UpdateAfterLogin(id uint, credential entity.JSONPayload, usedAt time.Time) error
+	// Delete удаляет ключ пользователя (ErrNotFound, если у пользователя такого ключа нет)
+	Delete(userID, id uint) error
+	DeleteByUserID(userID uint) error
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+// WebhookRepository определяет методы для работы с вебхуками и журналом их доставки
+type WebhookRepository interface {
+	// CreateEndpoint регистрирует адрес
+	CreateEndpoint(endpoint *entity.WebhookEndpoint) error
+
+	// GetEndpoint возвращает адрес по ID; apperrors.ErrNotFound, если адреса нет
+	GetEndpoint(id uint) (*entity.WebhookEndpoint, error)
+
+	// ListEndpoints возвращает все адреса; activeOnly — только включённые
+	ListEndpoints(activeOnly bool) ([]entity.WebhookEndpoint, error)
+
+	// UpdateEndpoint сохраняет изменённый адрес
+	UpdateEndpoint(endpoint *entity.WebhookEndpoint) error
+
+	// DeleteEndpoint удаляет адрес вместе с журналом доставки; apperrors.ErrNotFound, если адреса нет
+	DeleteEndpoint(id uint) error
+
+	// CreateDeliveries добавляет доставки события
+	CreateDeliveries(deliveries []entity.WebhookDelivery) error
+
+	// ClaimDueDeliveries забирает до limit доставок, срок которых наступил к now, и откладывает
+	// их следующую попытку на lease, чтобы другие инстансы не отправили их одновременно
+	ClaimDueDeliveries(now time.Time, lease time.Duration, limit int) ([]entity.WebhookDelivery, error)
+
+	// UpdateDelivery сохраняет результат попытки доставки
+	UpdateDelivery(delivery *entity.WebhookDelivery) error
+
+	// ListDeliveries возвращает доставки адреса (новые первыми) и общее количество; status "" — любые
+	ListDeliveries(endpointID uint, status string, limit, offset int) ([]entity.WebhookDelivery, int64, error)
+
+	// DeleteDeliveriesBefore удаляет записи журнала доставки, созданные раньше before
+	DeleteDeliveriesBefore(before time.Time) (int64, error)
+}
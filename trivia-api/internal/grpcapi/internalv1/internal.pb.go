@@ -0,0 +1,611 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        (unknown)
+// source: internal/v1/internal.proto
+
+package internalv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ValidateTokenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateTokenRequest) Reset() {
+	*x = ValidateTokenRequest{}
+	mi := &file_internal_v1_internal_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateTokenRequest) ProtoMessage() {}
+
+func (x *ValidateTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_v1_internal_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateTokenRequest.ProtoReflect.Descriptor instead.
+func (*ValidateTokenRequest) Descriptor() ([]byte, []int) {
+	return file_internal_v1_internal_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ValidateTokenRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type ValidateTokenResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Valid         bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	UserId        uint64                 `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	Role          string                 `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"`
+	ExpiresAt     int64                  `protobuf:"varint,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	ErrorType     string                 `protobuf:"bytes,6,opt,name=error_type,json=errorType,proto3" json:"error_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateTokenResponse) Reset() {
+	*x = ValidateTokenResponse{}
+	mi := &file_internal_v1_internal_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateTokenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateTokenResponse) ProtoMessage() {}
+
+func (x *ValidateTokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_v1_internal_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateTokenResponse.ProtoReflect.Descriptor instead.
+func (*ValidateTokenResponse) Descriptor() ([]byte, []int) {
+	return file_internal_v1_internal_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ValidateTokenResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *ValidateTokenResponse) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ValidateTokenResponse) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *ValidateTokenResponse) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *ValidateTokenResponse) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *ValidateTokenResponse) GetErrorType() string {
+	if x != nil {
+		return x.ErrorType
+	}
+	return ""
+}
+
+type GetQuizStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	QuizId        uint64                 `protobuf:"varint,1,opt,name=quiz_id,json=quizId,proto3" json:"quiz_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetQuizStatusRequest) Reset() {
+	*x = GetQuizStatusRequest{}
+	mi := &file_internal_v1_internal_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetQuizStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetQuizStatusRequest) ProtoMessage() {}
+
+func (x *GetQuizStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_v1_internal_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetQuizStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetQuizStatusRequest) Descriptor() ([]byte, []int) {
+	return file_internal_v1_internal_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetQuizStatusRequest) GetQuizId() uint64 {
+	if x != nil {
+		return x.QuizId
+	}
+	return 0
+}
+
+type GetQuizStatusResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	QuizId          uint64                 `protobuf:"varint,1,opt,name=quiz_id,json=quizId,proto3" json:"quiz_id,omitempty"`
+	Title           string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Status          string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	ScheduledTime   int64                  `protobuf:"varint,4,opt,name=scheduled_time,json=scheduledTime,proto3" json:"scheduled_time,omitempty"`
+	QuestionCount   int32                  `protobuf:"varint,5,opt,name=question_count,json=questionCount,proto3" json:"question_count,omitempty"`
+	CurrentQuestion int32                  `protobuf:"varint,6,opt,name=current_question,json=currentQuestion,proto3" json:"current_question,omitempty"`
+	PlayerCount     int32                  `protobuf:"varint,7,opt,name=player_count,json=playerCount,proto3" json:"player_count,omitempty"`
+	PrizeFund       int64                  `protobuf:"varint,8,opt,name=prize_fund,json=prizeFund,proto3" json:"prize_fund,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetQuizStatusResponse) Reset() {
+	*x = GetQuizStatusResponse{}
+	mi := &file_internal_v1_internal_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetQuizStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetQuizStatusResponse) ProtoMessage() {}
+
+func (x *GetQuizStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_v1_internal_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetQuizStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetQuizStatusResponse) Descriptor() ([]byte, []int) {
+	return file_internal_v1_internal_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetQuizStatusResponse) GetQuizId() uint64 {
+	if x != nil {
+		return x.QuizId
+	}
+	return 0
+}
+
+func (x *GetQuizStatusResponse) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *GetQuizStatusResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *GetQuizStatusResponse) GetScheduledTime() int64 {
+	if x != nil {
+		return x.ScheduledTime
+	}
+	return 0
+}
+
+func (x *GetQuizStatusResponse) GetQuestionCount() int32 {
+	if x != nil {
+		return x.QuestionCount
+	}
+	return 0
+}
+
+func (x *GetQuizStatusResponse) GetCurrentQuestion() int32 {
+	if x != nil {
+		return x.CurrentQuestion
+	}
+	return 0
+}
+
+func (x *GetQuizStatusResponse) GetPlayerCount() int32 {
+	if x != nil {
+		return x.PlayerCount
+	}
+	return 0
+}
+
+func (x *GetQuizStatusResponse) GetPrizeFund() int64 {
+	if x != nil {
+		return x.PrizeFund
+	}
+	return 0
+}
+
+type GetLeaderboardRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Page          int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLeaderboardRequest) Reset() {
+	*x = GetLeaderboardRequest{}
+	mi := &file_internal_v1_internal_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLeaderboardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLeaderboardRequest) ProtoMessage() {}
+
+func (x *GetLeaderboardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_v1_internal_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLeaderboardRequest.ProtoReflect.Descriptor instead.
+func (*GetLeaderboardRequest) Descriptor() ([]byte, []int) {
+	return file_internal_v1_internal_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetLeaderboardRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *GetLeaderboardRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type LeaderboardEntry struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Rank           int32                  `protobuf:"varint,1,opt,name=rank,proto3" json:"rank,omitempty"`
+	UserId         uint64                 `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Username       string                 `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
+	ProfilePicture string                 `protobuf:"bytes,4,opt,name=profile_picture,json=profilePicture,proto3" json:"profile_picture,omitempty"`
+	WinsCount      int64                  `protobuf:"varint,5,opt,name=wins_count,json=winsCount,proto3" json:"wins_count,omitempty"`
+	TotalPrizeWon  int64                  `protobuf:"varint,6,opt,name=total_prize_won,json=totalPrizeWon,proto3" json:"total_prize_won,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *LeaderboardEntry) Reset() {
+	*x = LeaderboardEntry{}
+	mi := &file_internal_v1_internal_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaderboardEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaderboardEntry) ProtoMessage() {}
+
+func (x *LeaderboardEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_v1_internal_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaderboardEntry.ProtoReflect.Descriptor instead.
+func (*LeaderboardEntry) Descriptor() ([]byte, []int) {
+	return file_internal_v1_internal_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *LeaderboardEntry) GetRank() int32 {
+	if x != nil {
+		return x.Rank
+	}
+	return 0
+}
+
+func (x *LeaderboardEntry) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *LeaderboardEntry) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *LeaderboardEntry) GetProfilePicture() string {
+	if x != nil {
+		return x.ProfilePicture
+	}
+	return ""
+}
+
+func (x *LeaderboardEntry) GetWinsCount() int64 {
+	if x != nil {
+		return x.WinsCount
+	}
+	return 0
+}
+
+func (x *LeaderboardEntry) GetTotalPrizeWon() int64 {
+	if x != nil {
+		return x.TotalPrizeWon
+	}
+	return 0
+}
+
+type GetLeaderboardResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*LeaderboardEntry    `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	Total         int64                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Page          int32                  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLeaderboardResponse) Reset() {
+	*x = GetLeaderboardResponse{}
+	mi := &file_internal_v1_internal_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLeaderboardResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLeaderboardResponse) ProtoMessage() {}
+
+func (x *GetLeaderboardResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_v1_internal_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLeaderboardResponse.ProtoReflect.Descriptor instead.
+func (*GetLeaderboardResponse) Descriptor() ([]byte, []int) {
+	return file_internal_v1_internal_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetLeaderboardResponse) GetUsers() []*LeaderboardEntry {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *GetLeaderboardResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *GetLeaderboardResponse) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *GetLeaderboardResponse) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+var File_internal_v1_internal_proto protoreflect.FileDescriptor
+
+const file_internal_v1_internal_proto_rawDesc = "" +
+	"\n" +
+	"\x1ainternal/v1/internal.proto\x12\x12trivia.internal.v1\",\n" +
+	"\x14ValidateTokenRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\"\xae\x01\n" +
+	"\x15ValidateTokenResponse\x12\x14\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x04R\x06userId\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\x12\x12\n" +
+	"\x04role\x18\x04 \x01(\tR\x04role\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x05 \x01(\x03R\texpiresAt\x12\x1d\n" +
+	"\n" +
+	"error_type\x18\x06 \x01(\tR\terrorType\"/\n" +
+	"\x14GetQuizStatusRequest\x12\x17\n" +
+	"\aquiz_id\x18\x01 \x01(\x04R\x06quizId\"\x99\x02\n" +
+	"\x15GetQuizStatusResponse\x12\x17\n" +
+	"\aquiz_id\x18\x01 \x01(\x04R\x06quizId\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12%\n" +
+	"\x0escheduled_time\x18\x04 \x01(\x03R\rscheduledTime\x12%\n" +
+	"\x0equestion_count\x18\x05 \x01(\x05R\rquestionCount\x12)\n" +
+	"\x10current_question\x18\x06 \x01(\x05R\x0fcurrentQuestion\x12!\n" +
+	"\fplayer_count\x18\a \x01(\x05R\vplayerCount\x12\x1d\n" +
+	"\n" +
+	"prize_fund\x18\b \x01(\x03R\tprizeFund\"H\n" +
+	"\x15GetLeaderboardRequest\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\"\xcb\x01\n" +
+	"\x10LeaderboardEntry\x12\x12\n" +
+	"\x04rank\x18\x01 \x01(\x05R\x04rank\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x04R\x06userId\x12\x1a\n" +
+	"\busername\x18\x03 \x01(\tR\busername\x12'\n" +
+	"\x0fprofile_picture\x18\x04 \x01(\tR\x0eprofilePicture\x12\x1d\n" +
+	"\n" +
+	"wins_count\x18\x05 \x01(\x03R\twinsCount\x12&\n" +
+	"\x0ftotal_prize_won\x18\x06 \x01(\x03R\rtotalPrizeWon\"\x9b\x01\n" +
+	"\x16GetLeaderboardResponse\x12:\n" +
+	"\x05users\x18\x01 \x03(\v2$.trivia.internal.v1.LeaderboardEntryR\x05users\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x03R\x05total\x12\x12\n" +
+	"\x04page\x18\x03 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize2w\n" +
+	"\x0fTokenValidation\x12d\n" +
+	"\rValidateToken\x12(.trivia.internal.v1.ValidateTokenRequest\x1a).trivia.internal.v1.ValidateTokenResponse2r\n" +
+	"\n" +
+	"QuizStatus\x12d\n" +
+	"\rGetQuizStatus\x12(.trivia.internal.v1.GetQuizStatusRequest\x1a).trivia.internal.v1.GetQuizStatusResponse2{\n" +
+	"\x10LeaderboardQuery\x12g\n" +
+	"\x0eGetLeaderboard\x12).trivia.internal.v1.GetLeaderboardRequest\x1a*.trivia.internal.v1.GetLeaderboardResponseBKZIgithub.com/yourusername/trivia-api/internal/grpcapi/internalv1;internalv1b\x06proto3"
+
+var (
+	file_internal_v1_internal_proto_rawDescOnce sync.Once
+	file_internal_v1_internal_proto_rawDescData []byte
+)
+
+func file_internal_v1_internal_proto_rawDescGZIP() []byte {
+	file_internal_v1_internal_proto_rawDescOnce.Do(func() {
+		file_internal_v1_internal_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_internal_v1_internal_proto_rawDesc), len(file_internal_v1_internal_proto_rawDesc)))
+	})
+	return file_internal_v1_internal_proto_rawDescData
+}
+
+var file_internal_v1_internal_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_internal_v1_internal_proto_goTypes = []any{
+	(*ValidateTokenRequest)(nil),   // 0: trivia.internal.v1.ValidateTokenRequest
+	(*ValidateTokenResponse)(nil),  // 1: trivia.internal.v1.ValidateTokenResponse
+	(*GetQuizStatusRequest)(nil),   // 2: trivia.internal.v1.GetQuizStatusRequest
+	(*GetQuizStatusResponse)(nil),  // 3: trivia.internal.v1.GetQuizStatusResponse
+	(*GetLeaderboardRequest)(nil),  // 4: trivia.internal.v1.GetLeaderboardRequest
+	(*LeaderboardEntry)(nil),       // 5: trivia.internal.v1.LeaderboardEntry
+	(*GetLeaderboardResponse)(nil), // 6: trivia.internal.v1.GetLeaderboardResponse
+}
+var file_internal_v1_internal_proto_depIdxs = []int32{
+	5, // 0: trivia.internal.v1.GetLeaderboardResponse.users:type_name -> trivia.internal.v1.LeaderboardEntry
+	0, // 1: trivia.internal.v1.TokenValidation.ValidateToken:input_type -> trivia.internal.v1.ValidateTokenRequest
+	2, // 2: trivia.internal.v1.QuizStatus.GetQuizStatus:input_type -> trivia.internal.v1.GetQuizStatusRequest
+	4, // 3: trivia.internal.v1.LeaderboardQuery.GetLeaderboard:input_type -> trivia.internal.v1.GetLeaderboardRequest
+	1, // 4: trivia.internal.v1.TokenValidation.ValidateToken:output_type -> trivia.internal.v1.ValidateTokenResponse
+	3, // 5: trivia.internal.v1.QuizStatus.GetQuizStatus:output_type -> trivia.internal.v1.GetQuizStatusResponse
+	6, // 6: trivia.internal.v1.LeaderboardQuery.GetLeaderboard:output_type -> trivia.internal.v1.GetLeaderboardResponse
+	4, // [4:7] is the sub-list for method output_type
+	1, // [1:4] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_internal_v1_internal_proto_init() }
+func file_internal_v1_internal_proto_init() {
+	if File_internal_v1_internal_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_internal_v1_internal_proto_rawDesc), len(file_internal_v1_internal_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   3,
+		},
+		GoTypes:           file_internal_v1_internal_proto_goTypes,
+		DependencyIndexes: file_internal_v1_internal_proto_depIdxs,
+		MessageInfos:      file_internal_v1_internal_proto_msgTypes,
+	}.Build()
+	File_internal_v1_internal_proto = out.File
+	file_internal_v1_internal_proto_goTypes = nil
+	file_internal_v1_internal_proto_depIdxs = nil
+}
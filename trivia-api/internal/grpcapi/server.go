@@ -0,0 +1,151 @@
+// Package grpcapi реализует внутренний gRPC API для межсервисных вызовов.
+// Сервисы используют тот же сервисный слой, что и REST-обработчики Gin.
+package grpcapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/yourusername/trivia-api/internal/config"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/grpcapi/internalv1"
+	"github.com/yourusername/trivia-api/internal/handler/dto"
+	"github.com/yourusername/trivia-api/pkg/auth"
+)
+
+// TokenParser проверяет access-токены (реализуется auth.JWTService)
+type TokenParser interface {
+	ParseToken(ctx context.Context, tokenString string) (*auth.JWTCustomClaims, error)
+}
+
+// QuizReader читает викторины (реализуется service.QuizService)
+type QuizReader interface {
+	GetQuizByID(quizID uint) (*entity.Quiz, error)
+}
+
+// QuizProgressProvider возвращает состояние идущей викторины (реализуется service.QuizManager)
+type QuizProgressProvider interface {
+	GetLiveProgress(quizID uint) (currentQuestion int, playerCount int)
+}
+
+// LeaderboardReader возвращает общий рейтинг (реализуется service.UserService)
+type LeaderboardReader interface {
+	GetLeaderboard(page, pageSize int) (*dto.PaginatedLeaderboardResponse, error)
+}
+
+// Server — gRPC-сервер внутреннего API
+type Server struct {
+	port       string
+	authToken  string
+	grpcServer *grpc.Server
+}
+
+// NewServer создаёт gRPC-сервер и регистрирует на нём сервисы внутреннего API
+func NewServer(
+	cfg config.GRPCConfig,
+	tokens TokenParser,
+	quizzes QuizReader,
+	progress QuizProgressProvider,
+	leaderboard LeaderboardReader,
+) (*Server, error) {
+	if tokens == nil || quizzes == nil || progress == nil || leaderboard == nil {
+		return nil, errors.New("grpcapi: all services are required")
+	}
+	if cfg.Port == "" {
+		return nil, errors.New("grpcapi: port is required")
+	}
+	if cfg.AuthToken == "" {
+		log.Println("[gRPC] ВНИМАНИЕ: grpc.authToken не задан, внутренний API доступен без аутентификации")
+	}
+
+	s := &Server{port: cfg.Port, authToken: cfg.AuthToken}
+	s.grpcServer = grpc.NewServer(grpc.ChainUnaryInterceptor(recoveryInterceptor, s.authInterceptor, loggingInterceptor))
+
+	internalv1.RegisterTokenValidationServer(s.grpcServer, &tokenValidationServer{tokens: tokens})
+	internalv1.RegisterQuizStatusServer(s.grpcServer, &quizStatusServer{quizzes: quizzes, progress: progress})
+	internalv1.RegisterLeaderboardQueryServer(s.grpcServer, &leaderboardQueryServer{leaderboard: leaderboard})
+	return s, nil
+}
+
+// Start начинает принимать соединения на порту grpc.port. Блокирует до остановки сервера.
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", ":"+s.port)
+	if err != nil {
+		return fmt.Errorf("grpcapi: failed to listen on port %s: %w", s.port, err)
+	}
+	return s.Serve(listener)
+}
+
+// Serve обслуживает соединения на переданном listener
+func (s *Server) Serve(listener net.Listener) error {
+	log.Printf("[gRPC] Внутренний API слушает %s", listener.Addr())
+	if err := s.grpcServer.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		return err
+	}
+	return nil
+}
+
+// Stop дожидается завершения текущих вызовов, но не дольше таймаута контекста
+func (s *Server) Stop(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+	}
+}
+
+// authInterceptor проверяет сервисный токен из метаданных authorization: Bearer <token>
+func (s *Server) authInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if s.authToken == "" {
+		return handler(ctx, req)
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "service token is required")
+	}
+	token := strings.TrimPrefix(values[0], "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(s.authToken)) != 1 {
+		return nil, status.Error(codes.Unauthenticated, "invalid service token")
+	}
+	return handler(ctx, req)
+}
+
+// loggingInterceptor пишет в лог медленные и завершившиеся ошибкой вызовы
+func loggingInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	if elapsed := time.Since(start); err != nil {
+		log.Printf("[gRPC] %s завершился ошибкой за %v: %v", info.FullMethod, elapsed, err)
+	} else if elapsed > time.Second {
+		log.Printf("[gRPC] Медленный вызов %s: %v", info.FullMethod, elapsed)
+	}
+	return resp, err
+}
+
+// recoveryInterceptor превращает панику обработчика в ошибку Internal, не роняя процесс
+func recoveryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[gRPC] Паника в %s: %v", info.FullMethod, r)
+			err = status.Error(codes.Internal, "internal error")
+		}
+	}()
+	return handler(ctx, req)
+}
@@ -0,0 +1,127 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/yourusername/trivia-api/internal/config"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/grpcapi/internalv1"
+	"github.com/yourusername/trivia-api/internal/handler/dto"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/pkg/auth"
+)
+
+type stubTokens struct{}
+
+func (stubTokens) ParseToken(_ context.Context, token string) (*auth.JWTCustomClaims, error) {
+	if token != "good" {
+		return nil, errors.New("invalid token")
+	}
+	return &auth.JWTCustomClaims{
+		UserID:           42,
+		Role:             "user",
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Unix(1700000000, 0))},
+	}, nil
+}
+
+type stubQuizzes struct{}
+
+func (stubQuizzes) GetQuizByID(quizID uint) (*entity.Quiz, error) {
+	if quizID != 7 {
+		return nil, apperrors.ErrNotFound
+	}
+	return &entity.Quiz{ID: 7, Title: "Вечерняя", Status: entity.QuizStatusInProgress, QuestionCount: 10}, nil
+}
+
+type stubProgress struct{}
+
+func (stubProgress) GetLiveProgress(uint) (int, int) { return 3, 120 }
+
+type stubLeaderboard struct{}
+
+func (stubLeaderboard) GetLeaderboard(page, pageSize int) (*dto.PaginatedLeaderboardResponse, error) {
+	return &dto.PaginatedLeaderboardResponse{
+		Users:   []*dto.LeaderboardUserDTO{{Rank: 1, UserID: 5, Username: "alice", WinsCount: 3}},
+		Total:   1,
+		Page:    page,
+		PerPage: pageSize,
+	}, nil
+}
+
+func startTestServer(t *testing.T, authToken string) *grpc.ClientConn {
+	t.Helper()
+	server, err := NewServer(config.GRPCConfig{Port: "0", AuthToken: authToken}, stubTokens{}, stubQuizzes{}, stubProgress{}, stubLeaderboard{})
+	require.NoError(t, err)
+
+	listener := bufconn.Listen(1 << 20)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(func() { server.Stop(context.Background()) })
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestServer_InternalServices(t *testing.T) {
+	conn := startTestServer(t, "")
+	ctx := context.Background()
+
+	token, err := internalv1.NewTokenValidationClient(conn).ValidateToken(ctx, &internalv1.ValidateTokenRequest{Token: "good"})
+	require.NoError(t, err)
+	assert.True(t, token.Valid)
+	assert.Equal(t, uint64(42), token.UserId)
+	assert.Equal(t, int64(1700000000), token.ExpiresAt)
+
+	token, err = internalv1.NewTokenValidationClient(conn).ValidateToken(ctx, &internalv1.ValidateTokenRequest{Token: "bad"})
+	require.NoError(t, err)
+	assert.False(t, token.Valid)
+	assert.Equal(t, "token_invalid", token.ErrorType)
+
+	quiz, err := internalv1.NewQuizStatusClient(conn).GetQuizStatus(ctx, &internalv1.GetQuizStatusRequest{QuizId: 7})
+	require.NoError(t, err)
+	assert.Equal(t, "in_progress", quiz.Status)
+	assert.Equal(t, int32(3), quiz.CurrentQuestion)
+	assert.Equal(t, int32(120), quiz.PlayerCount)
+
+	_, err = internalv1.NewQuizStatusClient(conn).GetQuizStatus(ctx, &internalv1.GetQuizStatusRequest{QuizId: 8})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	leaderboard, err := internalv1.NewLeaderboardQueryClient(conn).GetLeaderboard(ctx, &internalv1.GetLeaderboardRequest{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	require.Len(t, leaderboard.Users, 1)
+	assert.Equal(t, "alice", leaderboard.Users[0].Username)
+}
+
+func TestServer_RequiresServiceToken(t *testing.T) {
+	conn := startTestServer(t, "s3cret")
+	client := internalv1.NewQuizStatusClient(conn)
+
+	_, err := client.GetQuizStatus(context.Background(), &internalv1.GetQuizStatusRequest{QuizId: 7})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer wrong")
+	_, err = client.GetQuizStatus(ctx, &internalv1.GetQuizStatusRequest{QuizId: 7})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx = metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer s3cret")
+	_, err = client.GetQuizStatus(ctx, &internalv1.GetQuizStatusRequest{QuizId: 7})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,112 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/yourusername/trivia-api/internal/grpcapi/internalv1"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// tokenValidationServer реализует internalv1.TokenValidationServer
+type tokenValidationServer struct {
+	internalv1.UnimplementedTokenValidationServer
+	tokens TokenParser
+}
+
+// ValidateToken проверяет access-токен так же, как RequireAuth для REST. Недействительный
+// токен — штатный ответ с valid=false, а не ошибка вызова.
+func (s *tokenValidationServer) ValidateToken(ctx context.Context, req *internalv1.ValidateTokenRequest) (*internalv1.ValidateTokenResponse, error) {
+	if req.GetToken() == "" {
+		return &internalv1.ValidateTokenResponse{ErrorType: "token_missing"}, nil
+	}
+	claims, err := s.tokens.ParseToken(ctx, req.GetToken())
+	if err != nil {
+		return &internalv1.ValidateTokenResponse{ErrorType: "token_invalid"}, nil
+	}
+
+	resp := &internalv1.ValidateTokenResponse{
+		Valid:  true,
+		UserId: uint64(claims.UserID),
+		Email:  claims.Email,
+		Role:   claims.Role,
+	}
+	if claims.ExpiresAt != nil {
+		resp.ExpiresAt = claims.ExpiresAt.Unix()
+	}
+	return resp, nil
+}
+
+// quizStatusServer реализует internalv1.QuizStatusServer
+type quizStatusServer struct {
+	internalv1.UnimplementedQuizStatusServer
+	quizzes  QuizReader
+	progress QuizProgressProvider
+}
+
+// GetQuizStatus возвращает сохранённое состояние викторины и ход идущей игры
+func (s *quizStatusServer) GetQuizStatus(ctx context.Context, req *internalv1.GetQuizStatusRequest) (*internalv1.GetQuizStatusResponse, error) {
+	if req.GetQuizId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "quiz_id is required")
+	}
+	quizID := uint(req.GetQuizId())
+
+	quiz, err := s.quizzes.GetQuizByID(quizID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil, status.Errorf(codes.NotFound, "quiz %d not found", quizID)
+		}
+		log.Printf("[gRPC] Ошибка получения викторины %d: %v", quizID, err)
+		return nil, status.Error(codes.Internal, "failed to get quiz")
+	}
+
+	currentQuestion, playerCount := s.progress.GetLiveProgress(quizID)
+	return &internalv1.GetQuizStatusResponse{
+		QuizId:          uint64(quiz.ID),
+		Title:           quiz.Title,
+		Status:          quiz.Status,
+		ScheduledTime:   quiz.ScheduledTime.Unix(),
+		QuestionCount:   int32(quiz.QuestionCount),
+		CurrentQuestion: int32(currentQuestion),
+		PlayerCount:     int32(playerCount),
+		PrizeFund:       int64(quiz.PrizeFund),
+	}, nil
+}
+
+// leaderboardQueryServer реализует internalv1.LeaderboardQueryServer
+type leaderboardQueryServer struct {
+	internalv1.UnimplementedLeaderboardQueryServer
+	leaderboard LeaderboardReader
+}
+
+// GetLeaderboard возвращает страницу общего рейтинга. Границы page/page_size
+// проверяет UserService, как и для REST.
+func (s *leaderboardQueryServer) GetLeaderboard(ctx context.Context, req *internalv1.GetLeaderboardRequest) (*internalv1.GetLeaderboardResponse, error) {
+	page, err := s.leaderboard.GetLeaderboard(int(req.GetPage()), int(req.GetPageSize()))
+	if err != nil {
+		log.Printf("[gRPC] Ошибка получения лидерборда: %v", err)
+		return nil, status.Error(codes.Internal, "failed to get leaderboard")
+	}
+
+	users := make([]*internalv1.LeaderboardEntry, 0, len(page.Users))
+	for _, user := range page.Users {
+		users = append(users, &internalv1.LeaderboardEntry{
+			Rank:           int32(user.Rank),
+			UserId:         uint64(user.UserID),
+			Username:       user.Username,
+			ProfilePicture: user.ProfilePicture,
+			WinsCount:      user.WinsCount,
+			TotalPrizeWon:  user.TotalPrizeWon,
+		})
+	}
+	return &internalv1.GetLeaderboardResponse{
+		Users:    users,
+		Total:    page.Total,
+		Page:     int32(page.Page),
+		PageSize: int32(page.PerPage),
+	}, nil
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/handler/response"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+// AbuseHandler обрабатывает админ-запросы по мультиаккаунтам и теневым банам
+type AbuseHandler struct {
+	abuseService *service.AbuseService
+	auditService *service.AuditService
+}
+
+// NewAbuseHandler создает новый обработчик защиты от мультиаккаунтов
+func NewAbuseHandler(abuseService *service.AbuseService) *AbuseHandler {
+	return &AbuseHandler{abuseService: abuseService}
+}
+
+// SetAuditService подключает журнал аудита
+func (h *AbuseHandler) SetAuditService(auditService *service.AuditService) {
+	h.auditService = auditService
+}
+
+// ShadowBanRequest — причина теневого бана
+type ShadowBanRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// ListSharedDevices возвращает устройства с несколькими аккаунтами
+// GET /api/admin/abuse/devices?min_accounts=2&page=1&page_size=20
+func (h *AbuseHandler) ListSharedDevices(c *gin.Context) {
+	minAccounts, _ := strconv.Atoi(c.DefaultQuery("min_accounts", "2"))
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	devices, total, err := h.abuseService.ListSharedDevices(minAccounts, page, pageSize)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{
+		"devices":   devices,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	}, nil)
+}
+
+// ListDeviceAccounts возвращает аккаунты, зарегистрированные с устройства
+// GET /api/admin/abuse/devices/:hash/users
+func (h *AbuseHandler) ListDeviceAccounts(c *gin.Context) {
+	accounts, err := h.abuseService.ListDeviceAccounts(c.Param("hash"))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{"users": accounts}, nil)
+}
+
+// ListShadowBanned возвращает аккаунты с теневым баном
+// GET /api/admin/abuse/shadow-banned?page=1&page_size=20
+func (h *AbuseHandler) ListShadowBanned(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	users, total, err := h.abuseService.ListShadowBanned(page, pageSize)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{
+		"users":     users,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	}, nil)
+}
+
+// ShadowBan ставит аккаунту теневой бан
+// POST /api/admin/abuse/users/:id/shadow-ban
+func (h *AbuseHandler) ShadowBan(c *gin.Context) {
+	var req ShadowBanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid_request", "reason is required")
+		return
+	}
+	userID := c.MustGet("targetUserID").(uint)
+	if err := h.abuseService.ShadowBan(userID, req.Reason); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionUserShadowBan,
+		TargetType: entity.AuditTargetUser,
+		TargetID:   strconv.FormatUint(uint64(userID), 10),
+		Metadata:   map[string]interface{}{"reason": req.Reason},
+	})
+	response.Success(c, http.StatusOK, gin.H{"message": "User shadow-banned"}, nil)
+}
+
+// LiftShadowBan снимает теневой бан
+// DELETE /api/admin/abuse/users/:id/shadow-ban
+func (h *AbuseHandler) LiftShadowBan(c *gin.Context) {
+	userID := c.MustGet("targetUserID").(uint)
+	if err := h.abuseService.LiftShadowBan(userID); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionUserShadowUnban,
+		TargetType: entity.AuditTargetUser,
+		TargetID:   strconv.FormatUint(uint64(userID), 10),
+	})
+	response.Success(c, http.StatusOK, gin.H{"message": "Shadow ban lifted"}, nil)
+}
@@ -6,6 +6,8 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/handler/response"
 	"github.com/yourusername/trivia-api/internal/service"
 )
 
@@ -13,6 +15,7 @@ import (
 type AdHandler struct {
 	adService         *service.AdService
 	quizAdSlotService *service.QuizAdSlotService
+	auditService      *service.AuditService
 }
 
 // NewAdHandler создаёт новый обработчик рекламы
@@ -23,32 +26,31 @@ func NewAdHandler(adService *service.AdService, quizAdSlotService *service.QuizA
 	}
 }
 
+// SetAuditService подключает журнал аудита
+func (h *AdHandler) SetAuditService(auditService *service.AuditService) {
+	h.auditService = auditService
+}
+
 // UploadAdAsset загружает рекламный медиа-файл
 // POST /api/admin/ads
 func (h *AdHandler) UploadAdAsset(c *gin.Context) {
 	// Получаем файл
 	file, err := c.FormFile("file")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "файл не найден: " + err.Error()})
-		return
-	}
-
-	// Ограничение размера файла (50 MB)
-	if file.Size > 50*1024*1024 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "файл слишком большой (макс. 50 MB)"})
+		response.Error(c, http.StatusBadRequest, "invalid_request", "файл не найден: "+err.Error())
 		return
 	}
 
 	// Получаем параметры
 	title := c.PostForm("title")
 	if title == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "title обязателен"})
+		response.Error(c, http.StatusBadRequest, "invalid_request", "title обязателен")
 		return
 	}
 
 	mediaType := c.PostForm("media_type")
 	if mediaType != "image" && mediaType != "video" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "media_type должен быть 'image' или 'video'"})
+		response.Error(c, http.StatusBadRequest, "invalid_request", "media_type должен быть 'image' или 'video'")
 		return
 	}
 
@@ -64,11 +66,17 @@ func (h *AdHandler) UploadAdAsset(c *gin.Context) {
 	asset, err := h.adService.UploadAdAsset(file, title, mediaType, durationSec)
 	if err != nil {
 		log.Printf("[AdHandler] Ошибка загрузки рекламы: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusBadRequest, "invalid_request", err.Error())
 		return
 	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionAdUpload,
+		TargetType: entity.AuditTargetAdAsset,
+		TargetID:   strconv.FormatUint(uint64(asset.ID), 10),
+		After:      asset,
+	})
 
-	c.JSON(http.StatusCreated, asset)
+	response.Success(c, http.StatusCreated, asset, nil)
 }
 
 // ListAdAssets возвращает список всех рекламных ресурсов
@@ -77,11 +85,11 @@ func (h *AdHandler) ListAdAssets(c *gin.Context) {
 	assets, err := h.adService.ListAdAssets()
 	if err != nil {
 		log.Printf("[AdHandler] Ошибка получения списка рекламы: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "не удалось получить список"})
+		response.Error(c, http.StatusInternalServerError, "internal_server_error", nil)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"items": assets})
+	response.Success(c, http.StatusOK, gin.H{"items": assets}, nil)
 }
 
 // DeleteAdAsset удаляет рекламный ресурс
@@ -89,17 +97,27 @@ func (h *AdHandler) ListAdAssets(c *gin.Context) {
 func (h *AdHandler) DeleteAdAsset(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "некорректный ID"})
+		response.Error(c, http.StatusBadRequest, "invalid_request", "некорректный ID")
 		return
 	}
 
+	before, _ := h.adService.GetAdAsset(uint(id))
 	if err := h.adService.DeleteAdAsset(uint(id)); err != nil {
 		log.Printf("[AdHandler] Ошибка удаления рекламы #%d: %v", id, err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusBadRequest, "invalid_request", err.Error())
 		return
 	}
+	entry := service.AuditEntry{
+		Action:     entity.AuditActionAdDelete,
+		TargetType: entity.AuditTargetAdAsset,
+		TargetID:   strconv.FormatUint(id, 10),
+	}
+	if before != nil {
+		entry.Before = before
+	}
+	recordAudit(c, h.auditService, entry)
 
-	c.JSON(http.StatusOK, gin.H{"message": "реклама удалена"})
+	response.Success(c, http.StatusOK, gin.H{"message": "реклама удалена"}, nil)
 }
 
 // --- Рекламные слоты викторины ---
@@ -109,24 +127,24 @@ func (h *AdHandler) DeleteAdAsset(c *gin.Context) {
 func (h *AdHandler) CreateAdSlot(c *gin.Context) {
 	quizID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "некорректный quiz_id"})
+		response.Error(c, http.StatusBadRequest, "invalid_request", "некорректный quiz_id")
 		return
 	}
 
 	var req service.CreateSlotRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.InvalidRequest(c, err)
 		return
 	}
 
 	slot, err := h.quizAdSlotService.CreateSlot(uint(quizID), req)
 	if err != nil {
 		log.Printf("[AdHandler] Ошибка создания слота для викторины #%d: %v", quizID, err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusBadRequest, "invalid_request", err.Error())
 		return
 	}
 
-	c.JSON(http.StatusCreated, slot)
+	response.Success(c, http.StatusCreated, slot, nil)
 }
 
 // ListAdSlots возвращает все слоты викторины
@@ -134,18 +152,18 @@ func (h *AdHandler) CreateAdSlot(c *gin.Context) {
 func (h *AdHandler) ListAdSlots(c *gin.Context) {
 	quizID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "некорректный quiz_id"})
+		response.Error(c, http.StatusBadRequest, "invalid_request", "некорректный quiz_id")
 		return
 	}
 
 	slots, err := h.quizAdSlotService.ListSlots(uint(quizID))
 	if err != nil {
 		log.Printf("[AdHandler] Ошибка получения слотов для викторины #%d: %v", quizID, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "не удалось получить слоты"})
+		response.Error(c, http.StatusInternalServerError, "internal_server_error", nil)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"items": slots})
+	response.Success(c, http.StatusOK, gin.H{"items": slots}, nil)
 }
 
 // UpdateAdSlot обновляет рекламный слот
@@ -153,26 +171,24 @@ func (h *AdHandler) ListAdSlots(c *gin.Context) {
 func (h *AdHandler) UpdateAdSlot(c *gin.Context) {
 	slotID, err := strconv.ParseUint(c.Param("slotId"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "некорректный slot_id"})
+		response.Error(c, http.StatusBadRequest, "invalid_request", "некорректный slot_id")
 		return
 	}
 
-	var req struct {
-		IsActive bool `json:"is_active"`
-	}
+	var req service.UpdateSlotRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.InvalidRequest(c, err)
 		return
 	}
 
-	slot, err := h.quizAdSlotService.UpdateSlot(uint(slotID), req.IsActive)
+	slot, err := h.quizAdSlotService.UpdateSlot(uint(slotID), req)
 	if err != nil {
 		log.Printf("[AdHandler] Ошибка обновления слота #%d: %v", slotID, err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusBadRequest, "invalid_request", err.Error())
 		return
 	}
 
-	c.JSON(http.StatusOK, slot)
+	response.Success(c, http.StatusOK, slot, nil)
 }
 
 // DeleteAdSlot удаляет рекламный слот
@@ -180,15 +196,15 @@ func (h *AdHandler) UpdateAdSlot(c *gin.Context) {
 func (h *AdHandler) DeleteAdSlot(c *gin.Context) {
 	slotID, err := strconv.ParseUint(c.Param("slotId"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "некорректный slot_id"})
+		response.Error(c, http.StatusBadRequest, "invalid_request", "некорректный slot_id")
 		return
 	}
 
 	if err := h.quizAdSlotService.DeleteSlot(uint(slotID)); err != nil {
 		log.Printf("[AdHandler] Ошибка удаления слота #%d: %v", slotID, err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusBadRequest, "invalid_request", err.Error())
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "слот удалён"})
+	response.Success(c, http.StatusOK, gin.H{"message": "слот удалён"}, nil)
 }
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/trivia-api/internal/admingraph"
+)
+
+// AdminGraphQLHandler обрабатывает GraphQL-запросы админ-панели
+type AdminGraphQLHandler struct {
+	graph *admingraph.Graph
+}
+
+// NewAdminGraphQLHandler создает новый обработчик GraphQL админ-панели
+func NewAdminGraphQLHandler(graph *admingraph.Graph) *AdminGraphQLHandler {
+	return &AdminGraphQLHandler{graph: graph}
+}
+
+// graphQLRequest — тело GraphQL-запроса
+type graphQLRequest struct {
+	Query         string                 `json:"query" binding:"required"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Query выполняет GraphQL-запрос. Ошибки отдельных полей возвращаются в поле errors
+// ответа со статусом 200, как принято в GraphQL.
+// POST /api/admin/graphql
+func (h *AdminGraphQLHandler) Query(c *gin.Context) {
+	var req graphQLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Request body must contain a GraphQL query", "error_type": "validation_error"})
+		return
+	}
+
+	response := h.graph.Exec(c.Request.Context(), req.Query, req.OperationName, req.Variables)
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yourusername/trivia-api/internal/middleware"
+	"github.com/yourusername/trivia-api/internal/service"
+	"github.com/yourusername/trivia-api/pkg/auth"
+	"github.com/yourusername/trivia-api/pkg/auth/manager"
+)
+
+// adminPanelCSRFScript подставляет nonce в изменяющие запросы fetch: nonce семейства,
+// полученный в X-CSRF-Token/X-CSRF-Family прошлого ответа, иначе nonce страницы.
+// Семейство вычисляется так же, как middleware.CSRFFamily.
+const adminPanelCSRFScript = `(function(){
+var m=document.querySelector('meta[name="csrf-token"]'),root=m&&m.content,fam={};
+function family(u){var p=new URL(u,location.href).pathname.replace(/^\/api\//,'').replace(/^\/+|\/+$/g,'').split('/');
+if(p.length>1&&(p[0]==='admin'||(p[0]==='auth'&&p[1]==='admin')))return p[0]+'/'+p[1];return p[0];}
+var f=window.fetch;window.fetch=function(input,init){init=init||{};var req=typeof input!=='string'&&!(input instanceof URL);
+var url=req?input.url:String(input),method=(init.method||(req&&input.method)||'GET').toUpperCase();
+if(root&&['GET','HEAD','OPTIONS'].indexOf(method)<0){var h=new Headers(init.headers||(req?input.headers:undefined));
+h.set('X-CSRF-Token',fam[family(url)]||root);init.headers=h;}
+return f.call(this,input,init).then(function(r){var n=r.headers.get('X-CSRF-Token'),k=r.headers.get('X-CSRF-Family');
+if(n&&k)fam[k]=n;return r;});};
+window.csrfTokenFor=function(u){return fam[family(u)]||root;};})();`
+
+// AdminPanelHandler отдаёт статические страницы админ-панели. В HTML-страницы, открытые
+// администратором, подставляется nonce CSRF (meta csrf-token) и скрипт, добавляющий его
+// к запросам; остальные файлы отдаются как есть.
+type AdminPanelHandler struct {
+	files        http.FileSystem
+	fileServer   http.Handler
+	jwtService   *auth.JWTService
+	tokenManager *manager.TokenManager
+	csrfNonces   *service.CSRFNonceService
+}
+
+// NewAdminPanelHandler создает обработчик страниц админ-панели из каталога dir, доступных по prefix
+func NewAdminPanelHandler(dir, prefix string, jwtService *auth.JWTService, tokenManager *manager.TokenManager, csrfNonces *service.CSRFNonceService) *AdminPanelHandler {
+	files := http.Dir(dir)
+	return &AdminPanelHandler{
+		files:        files,
+		fileServer:   http.StripPrefix(prefix, http.FileServer(files)),
+		jwtService:   jwtService,
+		tokenManager: tokenManager,
+		csrfNonces:   csrfNonces,
+	}
+}
+
+// Serve отдаёт файл админ-панели
+// GET /admin/*filepath
+func (h *AdminPanelHandler) Serve(c *gin.Context) {
+	name := path.Clean("/" + c.Param("filepath"))
+	if strings.HasSuffix(c.Param("filepath"), "/") {
+		name = path.Join(name, "index.html")
+	}
+	if !strings.HasSuffix(name, ".html") {
+		h.fileServer.ServeHTTP(c.Writer, c.Request)
+		return
+	}
+
+	userID, ok := h.adminUserID(c)
+	if !ok {
+		h.fileServer.ServeHTTP(c.Writer, c.Request)
+		return
+	}
+	page, err := h.readFile(name)
+	if err != nil {
+		h.fileServer.ServeHTTP(c.Writer, c.Request)
+		return
+	}
+
+	sessionID := h.tokenManager.EnsureAdminCSRFSession(c.Writer, c.Request)
+	nonce, err := h.csrfNonces.IssueNonce(c.Request.Context(), sessionID, userID)
+	if err != nil {
+		log.Printf("[AdminPanel] Ошибка выдачи CSRF nonce для пользователя %d: %v", userID, err)
+		c.String(http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	// Страница с nonce не должна попадать в кеш браузера или прокси
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "text/html; charset=utf-8", injectCSRFNonce(page, nonce, middleware.CSPNonce(c)))
+}
+
+// adminUserID возвращает ID администратора по access-токену из куки
+func (h *AdminPanelHandler) adminUserID(c *gin.Context) (uint, bool) {
+	token, err := h.tokenManager.GetAccessTokenFromCookie(c.Request)
+	if err != nil {
+		return 0, false
+	}
+	claims, err := h.jwtService.ParseToken(c, token)
+	if err != nil || claims.Role != "admin" {
+		return 0, false
+	}
+	return claims.UserID, true
+}
+
+func (h *AdminPanelHandler) readFile(name string) ([]byte, error) {
+	file, err := h.files.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(file)
+}
+
+// injectCSRFNonce добавляет meta csrf-token и скрипт перед </head> (или в начало страницы без head).
+// scriptNonce — nonce Content-Security-Policy, без которого встроенный скрипт не выполнится.
+func injectCSRFNonce(page []byte, nonce, scriptNonce string) []byte {
+	scriptTag := "<script>"
+	if scriptNonce != "" {
+		scriptTag = fmt.Sprintf(`<script nonce="%s">`, scriptNonce)
+	}
+	snippet := []byte(fmt.Sprintf(`<meta name="csrf-token" content="%s">%s%s</script>`, nonce, scriptTag, adminPanelCSRFScript))
+	idx := bytes.Index(bytes.ToLower(page), []byte("</head>"))
+	if idx < 0 {
+		return append(snippet, page...)
+	}
+	out := make([]byte, 0, len(page)+len(snippet))
+	out = append(out, page[:idx]...)
+	out = append(out, snippet...)
+	return append(out, page[idx:]...)
+}
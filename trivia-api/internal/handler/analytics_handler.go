@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/trivia-api/internal/handler/response"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+// AnalyticsHandler обрабатывает запросы админ-аналитики
+type AnalyticsHandler struct {
+	analyticsService    *service.AnalyticsService
+	mobileClientService *service.MobileClientService
+}
+
+// NewAnalyticsHandler создает новый обработчик аналитики
+func NewAnalyticsHandler(analyticsService *service.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{analyticsService: analyticsService}
+}
+
+// SetMobileClientService подключает статистику версий мобильных клиентов
+func (h *AnalyticsHandler) SetMobileClientService(mobileClientService *service.MobileClientService) {
+	h.mobileClientService = mobileClientService
+}
+
+// GetDashboard возвращает агрегированные метрики для админ-панели
+// GET /api/admin/analytics?days=30
+func (h *AnalyticsHandler) GetDashboard(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", strconv.Itoa(service.DefaultAnalyticsDays)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid days parameter", "error_type": "validation_error"})
+		return
+	}
+
+	dashboard, err := h.analyticsService.GetDashboard(days)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrValidation) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "error_type": "validation_error"})
+			return
+		}
+		log.Printf("[AnalyticsHandler] Ошибка получения аналитики: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error", "error_type": "internal_server_error"})
+		return
+	}
+	c.JSON(http.StatusOK, dashboard)
+}
+
+// GetMobileClients возвращает суточное число запросов к мобильному API по версии API,
+// платформе и версии приложения
+// GET /api/admin/analytics/mobile-clients?days=30
+func (h *AnalyticsHandler) GetMobileClients(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "validation_error", "invalid days parameter")
+		return
+	}
+
+	usage, err := h.mobileClientService.GetUsage(days)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	byAPIVersion := make(map[string]int64)
+	for _, row := range usage {
+		byAPIVersion[row.APIVersion] += row.Requests
+	}
+	response.Success(c, http.StatusOK, gin.H{
+		"items":          usage,
+		"by_api_version": byAPIVersion,
+	}, nil)
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/handler/response"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+// AnnouncementHandler управляет объявлениями ведущего викторины (админ-панель)
+type AnnouncementHandler struct {
+	announcementService *service.AnnouncementService
+	auditService        *service.AuditService
+}
+
+// NewAnnouncementHandler создает обработчик объявлений ведущего
+func NewAnnouncementHandler(announcementService *service.AnnouncementService) *AnnouncementHandler {
+	return &AnnouncementHandler{announcementService: announcementService}
+}
+
+// SetAuditService подключает журнал аудита
+func (h *AnnouncementHandler) SetAuditService(auditService *service.AuditService) {
+	h.auditService = auditService
+}
+
+// AnnouncementScheduleRequest — объявление, привязанное к моменту викторины
+type AnnouncementScheduleRequest struct {
+	Trigger        string `json:"trigger" binding:"required"`
+	OffsetSec      int    `json:"offset_sec"`
+	QuestionNumber int    `json:"question_number"`
+	Message        string `json:"message" binding:"required"`
+}
+
+func (r AnnouncementScheduleRequest) input() service.AnnouncementInput {
+	return service.AnnouncementInput{
+		Trigger:        r.Trigger,
+		OffsetSec:      r.OffsetSec,
+		QuestionNumber: r.QuestionNumber,
+		Message:        r.Message,
+	}
+}
+
+// ListAnnouncements возвращает объявления викторины и доступные подстановки
+// GET /api/quizzes/:id/announcements
+func (h *AnnouncementHandler) ListAnnouncements(c *gin.Context) {
+	announcements, err := h.announcementService.ListAnnouncements(c.MustGet("quizID").(uint))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{
+		"items":        announcements,
+		"placeholders": service.AnnouncementVars,
+	}, nil)
+}
+
+// CreateAnnouncement добавляет объявление к викторине
+// POST /api/quizzes/:id/announcements
+func (h *AnnouncementHandler) CreateAnnouncement(c *gin.Context) {
+	quizID := c.MustGet("quizID").(uint)
+
+	var req AnnouncementScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	announcement, err := h.announcementService.CreateAnnouncement(quizID, req.input())
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	h.recordAudit(c, entity.AuditActionQuizAnnouncementCreate, quizID, nil, announcement)
+	response.Success(c, http.StatusCreated, announcement, nil)
+}
+
+// UpdateAnnouncement заменяет момент и текст ещё не отправленного объявления
+// PUT /api/quizzes/:id/announcements/:announcementId
+func (h *AnnouncementHandler) UpdateAnnouncement(c *gin.Context) {
+	quizID := c.MustGet("quizID").(uint)
+	announcementID := c.MustGet("announcementID").(uint)
+
+	var req AnnouncementScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	before, err := h.announcementService.GetAnnouncement(quizID, announcementID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	announcement, err := h.announcementService.UpdateAnnouncement(quizID, announcementID, req.input())
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	h.recordAudit(c, entity.AuditActionQuizAnnouncementUpdate, quizID, before, announcement)
+	response.Success(c, http.StatusOK, announcement, nil)
+}
+
+// DeleteAnnouncement удаляет объявление викторины
+// DELETE /api/quizzes/:id/announcements/:announcementId
+func (h *AnnouncementHandler) DeleteAnnouncement(c *gin.Context) {
+	quizID := c.MustGet("quizID").(uint)
+	announcementID := c.MustGet("announcementID").(uint)
+
+	before, err := h.announcementService.GetAnnouncement(quizID, announcementID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	if err := h.announcementService.DeleteAnnouncement(quizID, announcementID); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	h.recordAudit(c, entity.AuditActionQuizAnnouncementDelete, quizID, before, nil)
+	response.Success(c, http.StatusOK, gin.H{"message": "Announcement deleted"}, nil)
+}
+
+func (h *AnnouncementHandler) recordAudit(c *gin.Context, action string, quizID uint, before, after *entity.QuizAnnouncement) {
+	entry := service.AuditEntry{
+		Action:     action,
+		TargetType: entity.AuditTargetQuiz,
+		TargetID:   strconv.FormatUint(uint64(quizID), 10),
+	}
+	// Типизированный nil в interface{} записался бы как пустое значение
+	if before != nil {
+		entry.Before = before
+	}
+	if after != nil {
+		entry.After = after
+	}
+	recordAudit(c, h.auditService, entry)
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/handler/response"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+// APIKeyHandler управляет API-ключами партнёров (админ-панель)
+type APIKeyHandler struct {
+	apiKeyService *service.APIKeyService
+	auditService  *service.AuditService
+}
+
+// NewAPIKeyHandler создает обработчик API-ключей
+func NewAPIKeyHandler(apiKeyService *service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{apiKeyService: apiKeyService}
+}
+
+// SetAuditService подключает журнал аудита
+func (h *APIKeyHandler) SetAuditService(auditService *service.AuditService) {
+	h.auditService = auditService
+}
+
+// CreateAPIKeyRequest — тело запроса выпуска ключа
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" binding:"required,max=100"`
+	Scopes    []string   `json:"scopes" binding:"required"`
+	RateLimit int        `json:"rate_limit" binding:"min=0"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// ListKeys возвращает выпущенные ключи и доступные права
+// GET /api/admin/api-keys
+func (h *APIKeyHandler) ListKeys(c *gin.Context) {
+	keys, err := h.apiKeyService.ListKeys()
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{
+		"items":  keys,
+		"scopes": entity.APIKeyScopes,
+	}, nil)
+}
+
+// CreateKey выпускает ключ. Сам ключ возвращается только в этом ответе.
+// POST /api/admin/api-keys
+func (h *APIKeyHandler) CreateKey(c *gin.Context) {
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	adminID := c.MustGet("user_id").(uint)
+	key, rawKey, err := h.apiKeyService.CreateKey(service.APIKeyInput{
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		RateLimit: req.RateLimit,
+		ExpiresAt: req.ExpiresAt,
+	}, adminID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionAPIKeyCreate,
+		TargetType: entity.AuditTargetAPIKey,
+		TargetID:   strconv.FormatUint(uint64(key.ID), 10),
+		After:      key,
+	})
+	response.Success(c, http.StatusCreated, gin.H{
+		"api_key": key,
+		"key":     rawKey,
+	}, nil)
+}
+
+// RevokeKey отзывает ключ
+// DELETE /api/admin/api-keys/:id
+func (h *APIKeyHandler) RevokeKey(c *gin.Context) {
+	keyID := c.MustGet("apiKeyID").(uint)
+
+	before, err := h.apiKeyService.GetKey(keyID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	if err := h.apiKeyService.RevokeKey(keyID); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionAPIKeyRevoke,
+		TargetType: entity.AuditTargetAPIKey,
+		TargetID:   strconv.FormatUint(uint64(keyID), 10),
+		Before:     before,
+	})
+	response.Success(c, http.StatusOK, gin.H{"message": "API key revoked"}, nil)
+}
+
+// GetUsage возвращает суточную статистику запросов ключа
+// GET /api/admin/api-keys/:id/usage?days=30
+func (h *APIKeyHandler) GetUsage(c *gin.Context) {
+	keyID := c.MustGet("apiKeyID").(uint)
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "30"))
+
+	usage, err := h.apiKeyService.GetUsage(keyID, days)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	var totals struct {
+		Requests    int64 `json:"requests"`
+		RateLimited int64 `json:"rate_limited"`
+		Errors      int64 `json:"errors"`
+	}
+	for _, day := range usage {
+		totals.Requests += day.Requests
+		totals.RateLimited += day.RateLimited
+		totals.Errors += day.Errors
+	}
+	response.Success(c, http.StatusOK, gin.H{
+		"items":  usage,
+		"totals": totals,
+	}, nil)
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+// AuditHandler обрабатывает запросы к журналу аудита
+type AuditHandler struct {
+	auditService *service.AuditService
+}
+
+// NewAuditHandler создает новый обработчик журнала аудита
+func NewAuditHandler(auditService *service.AuditService) *AuditHandler {
+	return &AuditHandler{auditService: auditService}
+}
+
+// ListAuditLog возвращает записи журнала аудита с фильтрами
+// GET /api/admin/audit?action=quiz.cancel&actor_id=1&target_type=quiz&target_id=5&from=2025-01-01T00:00:00Z&to=...&page=1&page_size=50
+func (h *AuditHandler) ListAuditLog(c *gin.Context) {
+	filters := repository.AuditLogFilters{
+		Action:     c.Query("action"),
+		TargetType: c.Query("target_type"),
+		TargetID:   c.Query("target_id"),
+	}
+	if actor := c.Query("actor_id"); actor != "" {
+		id, err := strconv.ParseUint(actor, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid actor_id", "error_type": "validation_error"})
+			return
+		}
+		actorID := uint(id)
+		filters.ActorID = &actorID
+	}
+	for param, dst := range map[string]**time.Time{"from": &filters.From, "to": &filters.To} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + param + " (RFC3339 expected)", "error_type": "validation_error"})
+			return
+		}
+		*dst = &t
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "50"))
+
+	entries, total, err := h.auditService.List(filters, page, pageSize)
+	if err != nil {
+		log.Printf("[AuditHandler] Ошибка получения журнала аудита: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error", "error_type": "internal_server_error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"items":     entries,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	})
+}
+
+// recordAudit дополняет запись данными запроса (администратор/пользователь, IP, User-Agent)
+// и сохраняет её. Ничего не делает, если аудит не подключён.
+func recordAudit(c *gin.Context, auditService *service.AuditService, entry service.AuditEntry) {
+	if auditService == nil {
+		return
+	}
+	if entry.ActorID == 0 {
+		if userID, ok := c.Get("user_id"); ok {
+			if id, ok := userID.(uint); ok {
+				entry.ActorID = id
+			}
+		}
+	}
+	entry.IPAddress = c.ClientIP()
+	entry.UserAgent = c.Request.UserAgent()
+	auditService.Record(c.Request.Context(), entry)
+}
+
+// recordLoginAudit фиксирует успешный или неудачный вход. При неудаче пользователь
+// неизвестен, поэтому в записи остаются email и причина отказа.
+func recordLoginAudit(c *gin.Context, auditService *service.AuditService, email, deviceID, client string, userID uint, loginErr error) {
+	entry := service.AuditEntry{
+		Action:     entity.AuditActionLogin,
+		ActorID:    userID,
+		TargetType: entity.AuditTargetUser,
+		Metadata:   map[string]interface{}{"client": client, "device_id": deviceID},
+	}
+	if loginErr != nil {
+		reason := loginErr.Error()
+		if len(reason) > 255 {
+			reason = reason[:255]
+		}
+		entry.Action = entity.AuditActionLoginFailed
+		entry.Metadata["email"] = strings.ToLower(strings.TrimSpace(email))
+		entry.Metadata["reason"] = reason
+	} else {
+		entry.TargetID = strconv.FormatUint(uint64(userID), 10)
+	}
+	recordAudit(c, auditService, entry)
+}
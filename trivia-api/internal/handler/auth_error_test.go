@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/trivia-api/internal/handler/response"
+	"github.com/yourusername/trivia-api/internal/middleware"
+	"github.com/yourusername/trivia-api/internal/pkg/i18n"
+	"github.com/yourusername/trivia-api/pkg/auth/manager"
+)
+
+// stubNegotiator выбирает язык из «профиля» пользователя по его ID
+type stubNegotiator struct {
+	locales   *i18n.Locales
+	languages map[uint]string
+}
+
+func (s stubNegotiator) NegotiateLocale(explicit string, userID uint, acceptLanguage string) i18n.Resolution {
+	return s.locales.Negotiate(explicit, s.languages[userID], acceptLanguage)
+}
+
+func TestWriteAuthError_LocalizedMessage(t *testing.T) {
+	negotiator := stubNegotiator{
+		locales:   i18n.NewLocales("ru", []string{"kk", "en"}),
+		languages: map[uint]string{42: "kk"},
+	}
+
+	tests := []struct {
+		name           string
+		userID         uint
+		acceptLanguage string
+		wantMessage    string
+		wantLanguage   string
+	}{
+		{"default locale", 0, "", "Сессия истекла", "ru"},
+		{"accept-language", 0, "en-US,en;q=0.9", "Session expired", "en"},
+		{"user language wins over header", 42, "en", "Сессия мерзімі аяқталды", "kk"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, w := newTestGinContext("POST", "/test", nil)
+			c.Request.Header.Set("Accept-Language", tt.acceptLanguage)
+			if tt.userID != 0 {
+				c.Set("user_id", tt.userID)
+			}
+			middleware.Locale(negotiator)(c)
+
+			writeAuthError(c, &manager.TokenError{Type: manager.ExpiredAccessToken})
+
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+			resp := parseJSONResponse(t, w)
+			assert.Equal(t, "token_expired", resp["error_type"])
+			assert.Equal(t, tt.wantMessage, resp["error"])
+			assert.Equal(t, tt.wantLanguage, w.Header().Get("Content-Language"))
+		})
+	}
+}
+
+func TestResponseError_WithoutNegotiator(t *testing.T) {
+	c, w := newTestGinContext("GET", "/test", nil)
+	c.Request.Header.Set("Accept-Language", "de, kk-KZ;q=0.5")
+
+	response.Error(c, http.StatusBadRequest, "validation_error", "title is required")
+
+	resp := parseJSONResponse(t, w)
+	assert.Equal(t, "Деректерді тексеру қатесі", resp["error"])
+	assert.Equal(t, "title is required", resp["details"])
+}
@@ -1,15 +1,21 @@
 package handler
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/handler/response"
 	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/pkg/i18n"
 	"github.com/yourusername/trivia-api/internal/service"
 	"github.com/yourusername/trivia-api/internal/websocket"
 	"github.com/yourusername/trivia-api/pkg/auth/manager"
@@ -20,6 +26,9 @@ type AuthHandler struct {
 	authService  *service.AuthService
 	tokenManager *manager.TokenManager
 	wsHub        websocket.HubInterface
+	auditService *service.AuditService
+	csrfNonces   *service.CSRFNonceService
+	userService  *service.UserService
 }
 
 // NewAuthHandler создает новый обработчик аутентификации
@@ -31,21 +40,40 @@ func NewAuthHandler(authService *service.AuthService, tokenManager *manager.Toke
 	}
 }
 
+// SetAuditService подключает журнал аудита
+func (h *AuthHandler) SetAuditService(auditService *service.AuditService) {
+	h.auditService = auditService
+}
+
+// SetUserService подключает серии игрока к ответу /users/me
+func (h *AuthHandler) SetUserService(userService *service.UserService) {
+	h.userService = userService
+}
+
+// SetCSRFNonceService включает выдачу nonce админ-панели (режим synchronizer token)
+func (h *AuthHandler) SetCSRFNonceService(csrfNonces *service.CSRFNonceService) {
+	h.csrfNonces = csrfNonces
+}
+
 // Структуры запросов и ответов
 
 // RegisterRequest представляет запрос на регистрацию
 type RegisterRequest struct {
-	Username  string `json:"username" binding:"required,min=3,max=50"`
+	Username  string `json:"username" binding:"required,min=3,max=50,username_charset"`
 	Email     string `json:"email" binding:"required,email"`
-	Password  string `json:"password" binding:"required,min=6,max=50"`
+	Password  string `json:"password" binding:"required,password_strength"`
 	FirstName string `json:"first_name" binding:"required,min=1,max=100"`
 	LastName  string `json:"last_name" binding:"required,min=1,max=100"`
-	BirthDate string `json:"birth_date" binding:"required"` // format: "2006-01-02"
+	BirthDate string `json:"birth_date" binding:"required,birth_date=18"` // format: "2006-01-02"
 	Gender    string `json:"gender" binding:"required,oneof=male female other prefer_not_to_say"`
 
 	TOSAccepted     bool `json:"tos_accepted" binding:"required"`
 	PrivacyAccepted bool `json:"privacy_accepted" binding:"required"`
 	MarketingOptIn  bool `json:"marketing_opt_in"`
+
+	ReferralCode string `json:"referral_code" binding:"omitempty,max=16"`
+	// Необязательный идентификатор устройства; без него используется кука device_id
+	DeviceID string `json:"device_id" binding:"omitempty,max=255"`
 }
 
 // LoginRequest представляет запрос на вход
@@ -126,18 +154,23 @@ type SessionInfo struct {
 	UserAgent string    `json:"user_agent"`
 	CreatedAt time.Time `json:"created_at"`
 	ExpiresAt time.Time `json:"expires_at"`
+	// Client и SessionStartedAt — тип клиента и время входа (для политики времени жизни сессии)
+	Client           string    `json:"client"`
+	SessionStartedAt time.Time `json:"session_started_at"`
+	// DeviceKeyThumbprint — отпечаток ключа устройства, к которому привязана мобильная сессия
+	DeviceKeyThumbprint string `json:"device_key_thumbprint,omitempty"`
 }
 
 // ChangePasswordRequest представляет запрос на изменение пароля
 type ChangePasswordRequest struct {
 	OldPassword string `json:"old_password" binding:"required"`
-	NewPassword string `json:"new_password" binding:"required,min=6"`
+	NewPassword string `json:"new_password" binding:"required,password_strength"`
 }
 
 // ResetPasswordRequest представляет запрос на сброс пароля администратором
 type ResetPasswordRequest struct {
 	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,min=6"`
+	Password string `json:"password" binding:"required,password_strength"`
 }
 
 // RevokeSessionRequest представляет запрос на отзыв отдельной сессии
@@ -149,14 +182,14 @@ type RevokeSessionRequest struct {
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.InvalidRequest(c, err)
 		return
 	}
 
 	// Парсим birth_date
 	birthDate, parseErr := time.Parse("2006-01-02", req.BirthDate)
 	if parseErr != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid birth_date format, expected YYYY-MM-DD"})
+		response.Error(c, http.StatusBadRequest, "invalid_request", "invalid birth_date format, expected YYYY-MM-DD")
 		return
 	}
 
@@ -174,6 +207,8 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		MarketingOptIn:  req.MarketingOptIn,
 		IP:              c.ClientIP(),
 		UserAgent:       c.Request.UserAgent(),
+		ReferralCode:    req.ReferralCode,
+		DeviceID:        h.webDeviceID(c, req.DeviceID),
 	}
 
 	user, err := h.authService.RegisterUser(input)
@@ -205,21 +240,21 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	h.tokenManager.SetCSRFSecretCookie(c.Writer, tokenResp.CSRFSecret)
 
 	// Возвращаем только необходимые данные в JSON
-	c.JSON(http.StatusCreated, gin.H{
+	response.Success(c, http.StatusCreated, gin.H{
 		"user":        serializeUserForClient(user),
 		"accessToken": tokenResp.AccessToken, // Access токен для информации (уже в куке)
 		"csrfToken":   tokenResp.CSRFToken,   // CSRF токен (хеш) для последующих запросов
 		"userId":      tokenResp.UserID,
 		"expiresIn":   tokenResp.ExpiresIn,
 		"tokenType":   "Bearer",
-	})
+	}, nil)
 }
 
 // Login обрабатывает запрос на вход
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		response.InvalidRequest(c, err)
 		return
 	}
 
@@ -234,8 +269,9 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	}
 
 	// Используем обновленный AuthService.LoginUser
-	tokenResp, err := h.authService.LoginUser(req.Email, req.Password, deviceID, ipAddress, userAgent)
+	tokenResp, err := h.authService.LoginUser(c.Request.Context(), req.Email, req.Password, deviceID, ipAddress, userAgent)
 	if err != nil {
+		recordLoginAudit(c, h.auditService, req.Email, deviceID, "web", 0, err)
 		h.handleAuthError(c, err)
 		return
 	}
@@ -251,6 +287,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	h.tokenManager.SetAccessTokenCookie(c.Writer, tokenResp.AccessToken)
 	// Устанавливаем CSRF Secret Cookie
 	h.tokenManager.SetCSRFSecretCookie(c.Writer, tokenResp.CSRFSecret)
+	recordLoginAudit(c, h.auditService, req.Email, deviceID, "web", tokenResp.UserID, nil)
 
 	// Получаем информацию о пользователе
 	user, userErr := h.authService.GetUserByID(tokenResp.UserID)
@@ -259,14 +296,14 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	}
 
 	// Формируем ответ
-	c.JSON(http.StatusOK, gin.H{
+	response.Success(c, http.StatusOK, gin.H{
 		"user":        serializeUserForClient(user),
 		"accessToken": tokenResp.AccessToken,
 		"csrfToken":   tokenResp.CSRFToken, // Возвращаем хеш
 		"userId":      tokenResp.UserID,
 		"expiresIn":   tokenResp.ExpiresIn,
 		"tokenType":   "Bearer",
-	})
+	}, nil)
 }
 
 // RefreshToken обновляет access токен с помощью refresh токена
@@ -331,13 +368,13 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	h.tokenManager.SetCSRFSecretCookie(c.Writer, tokenResp.CSRFSecret)
 
 	// Формируем ответ
-	c.JSON(http.StatusOK, gin.H{
+	response.Success(c, http.StatusOK, gin.H{
 		"accessToken": tokenResp.AccessToken,
 		"csrfToken":   tokenResp.CSRFToken, // Возвращаем новый хеш
 		"userId":      tokenResp.UserID,
 		"expiresIn":   tokenResp.ExpiresIn,
 		"tokenType":   "Bearer",
-	})
+	}, nil)
 }
 
 // GetMe возвращает информацию о текущем пользователе
@@ -345,22 +382,36 @@ func (h *AuthHandler) GetMe(c *gin.Context) {
 	// Получаем ID пользователя из контекста (установлен middleware)
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
 		return
 	}
 
 	user, err := h.authService.GetUserByID(userID.(uint))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		response.FromError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, serializeUserForClient(user))
+	result := serializeUserForClient(user)
+	if h.userService != nil {
+		// Серии и рейтинг не критичны для профиля: ошибка чтения не мешает ответу
+		if streaks, err := h.userService.GetStreaks(user.ID); err != nil {
+			log.Printf("[AuthHandler] Не удалось получить серии пользователя %d: %v", user.ID, err)
+		} else if streaks != nil {
+			result["streaks"] = streaks
+		}
+		if rating, err := h.userService.GetRating(user.ID); err != nil {
+			log.Printf("[AuthHandler] Не удалось получить рейтинг пользователя %d: %v", user.ID, err)
+		} else if rating != nil {
+			result["rating"] = rating
+		}
+	}
+	response.Success(c, http.StatusOK, result, nil)
 }
 
 // UpdateProfileRequest представляет запрос на обновление профиля
 type UpdateProfileRequest struct {
-	Username       string `json:"username" binding:"omitempty,min=3,max=50"`
+	Username       string `json:"username" binding:"omitempty,min=3,max=50,username_charset"`
 	ProfilePicture string `json:"profile_picture" binding:"omitempty,max=255"`
 }
 
@@ -370,21 +421,21 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 
 	var req UpdateProfileRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.InvalidRequest(c, err)
 		return
 	}
 
 	if err := h.authService.UpdateUserProfile(userID, req.Username, req.ProfilePicture); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeAuthError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Profile updated successfully"})
+	response.Success(c, http.StatusOK, gin.H{"message": "Profile updated successfully"}, nil)
 }
 
 // UpdateLanguageRequest представляет запрос на изменение языка пользователя
 type UpdateLanguageRequest struct {
-	Language string `json:"language" binding:"required,oneof=ru kk"`
+	Language string `json:"language" binding:"required"` // Один из localization.supportedLocales
 }
 
 // UpdateLanguage обновляет язык интерфейса пользователя
@@ -392,26 +443,30 @@ type UpdateLanguageRequest struct {
 func (h *AuthHandler) UpdateLanguage(c *gin.Context) {
 	userID := c.MustGet("user_id").(uint)
 
+	allowed := h.authService.SupportedLanguages()
+	invalidLanguage := gin.H{"allowed_values": allowed}
 	var req UpdateLanguageRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":          "Invalid language. Allowed: ru, kk",
-			"allowed_values": []string{"ru", "kk"},
-		})
+		response.Error(c, http.StatusBadRequest, "validation_error", invalidLanguage)
 		return
 	}
+	req.Language = i18n.Normalize(req.Language)
 
 	if err := h.authService.UpdateUserLanguage(userID, req.Language); err != nil {
+		if errors.Is(err, apperrors.ErrValidation) {
+			response.Error(c, http.StatusBadRequest, "validation_error", invalidLanguage)
+			return
+		}
 		log.Printf("[AuthHandler] Ошибка обновления языка для пользователя ID=%d: %v", userID, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update language"})
+		response.Error(c, http.StatusInternalServerError, "internal_server_error", nil)
 		return
 	}
 
 	log.Printf("[AuthHandler] Язык пользователя ID=%d обновлен на '%s'", userID, req.Language)
-	c.JSON(http.StatusOK, gin.H{
+	response.Success(c, http.StatusOK, gin.H{
 		"message":  "Language updated successfully",
 		"language": req.Language,
-	})
+	}, nil)
 }
 
 // Logout обрабатывает выход пользователя.
@@ -425,11 +480,11 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 			h.tokenManager.ClearRefreshTokenCookie(c.Writer)
 			h.tokenManager.ClearAccessTokenCookie(c.Writer)
 			h.tokenManager.ClearCSRFSecretCookie(c.Writer) // Очищаем куку секрета
-			c.JSON(http.StatusOK, gin.H{"message": "Already logged out or session expired"})
+			response.Success(c, http.StatusOK, gin.H{"message": "Already logged out or session expired"}, nil)
 			return
 		}
 		log.Printf("[AuthHandler] Logout: Error reading refresh token cookie: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not process logout due to cookie error"})
+		response.Error(c, http.StatusInternalServerError, "internal_server_error", nil)
 		return
 	}
 
@@ -438,7 +493,7 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 		h.tokenManager.ClearRefreshTokenCookie(c.Writer)
 		h.tokenManager.ClearAccessTokenCookie(c.Writer)
 		h.tokenManager.ClearCSRFSecretCookie(c.Writer) // Очищаем куку секрета
-		c.JSON(http.StatusOK, gin.H{"message": "Invalid session state"})
+		response.Success(c, http.StatusOK, gin.H{"message": "Invalid session state"}, nil)
 		return
 	}
 
@@ -452,7 +507,7 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 	h.tokenManager.ClearCSRFSecretCookie(c.Writer) // Очищаем куку секрета
 
 	log.Println("[AuthHandler] Logout: User logged out successfully.")
-	c.JSON(http.StatusOK, gin.H{"message": "Successfully logged out"})
+	response.Success(c, http.StatusOK, gin.H{"message": "Successfully logged out"}, nil)
 }
 
 // GetProfile обрабатывает запрос на получение профиля пользователя
@@ -463,9 +518,15 @@ func (h *AuthHandler) LogoutAllDevices(c *gin.Context) {
 
 	if err := h.authService.RevokeAllUserSessions(userID, "user_logout_all"); err != nil {
 		log.Printf("[AuthHandler] Ошибка при выходе из всех сессий: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Не удалось выйти из всех сессий", "error_type": "internal_error"})
+		response.Error(c, http.StatusInternalServerError, "internal_server_error", nil)
 		return
 	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionLogoutAll,
+		TargetType: entity.AuditTargetUser,
+		TargetID:   strconv.FormatUint(uint64(userID), 10),
+		Metadata:   map[string]interface{}{"client": "web"},
+	})
 
 	if h.wsHub != nil {
 		logoutEvent := map[string]interface{}{
@@ -483,7 +544,7 @@ func (h *AuthHandler) LogoutAllDevices(c *gin.Context) {
 	h.tokenManager.ClearAccessTokenCookie(c.Writer)
 	h.tokenManager.ClearCSRFSecretCookie(c.Writer) // Очищаем куку секрета
 
-	c.JSON(http.StatusOK, gin.H{"message": "Выход из всех сессий выполнен успешно"})
+	response.Success(c, http.StatusOK, gin.H{"message": "Выход из всех сессий выполнен успешно"}, nil)
 }
 
 // GetActiveSessions возвращает список активных сессий пользователя
@@ -491,14 +552,14 @@ func (h *AuthHandler) GetActiveSessions(c *gin.Context) {
 	// Получаем ID пользователя из контекста
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
 		return
 	}
 
 	// Получаем список сессий
 	sessions, err := h.authService.GetUserActiveSessions(userID.(uint))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get active sessions"})
+		response.Error(c, http.StatusInternalServerError, "internal_server_error", nil)
 		return
 	}
 
@@ -512,13 +573,16 @@ func (h *AuthHandler) GetActiveSessions(c *gin.Context) {
 			UserAgent: session.UserAgent,
 			CreatedAt: session.CreatedAt,
 			ExpiresAt: session.ExpiresAt,
+
+			Client:           session.Client,
+			SessionStartedAt: session.SessionStartedAt,
 		})
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	response.Success(c, http.StatusOK, gin.H{
 		"sessions": result,
 		"count":    len(result),
-	})
+	}, nil)
 }
 
 // ResetAuth обрабатывает запрос на сброс состояния аутентификации
@@ -529,7 +593,7 @@ func (h *AuthHandler) ResetAuth(c *gin.Context) {
 	isAdmin, exists := c.Get("is_admin")
 	isAdminBool, ok := isAdmin.(bool)
 	if !exists || !ok || !isAdminBool {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		response.Error(c, http.StatusForbidden, "forbidden", nil)
 		return
 	}
 
@@ -540,7 +604,7 @@ func (h *AuthHandler) ResetAuth(c *gin.Context) {
 
 	var req ResetRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.InvalidRequest(c, err)
 		return
 	}
 
@@ -549,11 +613,16 @@ func (h *AuthHandler) ResetAuth(c *gin.Context) {
 	// Вызываем сервис для сброса статуса инвалидации в репозитории invalid_tokens
 	if err := h.authService.ResetUserTokenInvalidation(req.UserID); err != nil {
 		log.Printf("[AuthHandler] Ошибка сброса инвалидации для пользователя ID=%d: %v", req.UserID, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset token invalidation"})
+		response.Error(c, http.StatusInternalServerError, "internal_server_error", nil)
 		return
 	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionTokenInvalidationReset,
+		TargetType: entity.AuditTargetUser,
+		TargetID:   strconv.FormatUint(uint64(req.UserID), 10),
+	})
 
-	c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("Token invalidation status reset for user %d", req.UserID)})
+	response.Success(c, http.StatusOK, gin.H{"message": fmt.Sprintf("Token invalidation status reset for user %d", req.UserID)}, nil)
 }
 
 // CheckRefreshToken проверяет валидность refresh-токена без его обновления
@@ -573,10 +642,7 @@ func (h *AuthHandler) CheckRefreshToken(c *gin.Context) {
 				refreshToken = req.RefreshToken
 			} else {
 				log.Printf("[AuthHandler] Ошибка валидации данных при проверке refresh-токена: %v", err)
-				c.JSON(http.StatusBadRequest, gin.H{
-					"error":      "Требуется refresh-токен",
-					"error_type": "token_invalid",
-				})
+				response.Error(c, http.StatusBadRequest, "token_invalid", nil)
 				return
 			}
 		}
@@ -587,10 +653,7 @@ func (h *AuthHandler) CheckRefreshToken(c *gin.Context) {
 		}
 		if err := c.ShouldBindJSON(&req); err != nil {
 			log.Printf("[AuthHandler] Ошибка валидации данных при проверке refresh-токена: %v", err)
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error":      "Требуется refresh-токен",
-				"error_type": "token_invalid",
-			})
+			response.Error(c, http.StatusBadRequest, "token_invalid", nil)
 			return
 		}
 		refreshToken = req.RefreshToken
@@ -600,17 +663,14 @@ func (h *AuthHandler) CheckRefreshToken(c *gin.Context) {
 	isValid, err := h.authService.CheckRefreshToken(refreshToken)
 	if err != nil {
 		log.Printf("[AuthHandler] Ошибка при проверке refresh-токена: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":      "Ошибка проверки токена",
-			"error_type": "server_error",
-		})
+		response.Error(c, http.StatusInternalServerError, "internal_server_error", nil)
 		return
 	}
 
 	// Возвращаем результат проверки
-	c.JSON(http.StatusOK, gin.H{
+	response.Success(c, http.StatusOK, gin.H{
 		"valid": isValid,
-	})
+	}, nil)
 }
 
 // GetTokenInfo возвращает информацию о сроке действия токенов
@@ -630,10 +690,7 @@ func (h *AuthHandler) GetTokenInfo(c *gin.Context) {
 				refreshToken = req.RefreshToken
 			} else {
 				log.Printf("[AuthHandler] Ошибка валидации данных при получении информации о токене: %v", err)
-				c.JSON(http.StatusBadRequest, gin.H{
-					"error":      "Требуется refresh-токен",
-					"error_type": "token_invalid",
-				})
+				response.Error(c, http.StatusBadRequest, "token_invalid", nil)
 				return
 			}
 		}
@@ -644,10 +701,7 @@ func (h *AuthHandler) GetTokenInfo(c *gin.Context) {
 		}
 		if err := c.ShouldBindJSON(&req); err != nil {
 			log.Printf("[AuthHandler] Ошибка валидации данных при получении информации о токене: %v", err)
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error":      "Требуется refresh-токен",
-				"error_type": "token_invalid",
-			})
+			response.Error(c, http.StatusBadRequest, "token_invalid", nil)
 			return
 		}
 		refreshToken = req.RefreshToken
@@ -660,10 +714,7 @@ func (h *AuthHandler) GetTokenInfo(c *gin.Context) {
 		info, err := h.tokenManager.GetTokenInfo(refreshToken)
 		if err != nil {
 			log.Printf("[AuthHandler] Ошибка при получении информации о токене: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":      "Ошибка получения информации о токене",
-				"error_type": "server_error",
-			})
+			response.Error(c, http.StatusInternalServerError, "internal_server_error", nil)
 			return
 		}
 		tokenInfo = info
@@ -672,10 +723,7 @@ func (h *AuthHandler) GetTokenInfo(c *gin.Context) {
 		info, err := h.authService.GetTokenInfo(refreshToken)
 		if err != nil {
 			log.Printf("[AuthHandler] Ошибка при получении информации о токене: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":      "Ошибка получения информации о токене",
-				"error_type": "server_error",
-			})
+			response.Error(c, http.StatusInternalServerError, "internal_server_error", nil)
 			return
 		}
 
@@ -690,7 +738,7 @@ func (h *AuthHandler) GetTokenInfo(c *gin.Context) {
 	}
 
 	// Возвращаем информацию о сроке действия токенов
-	c.JSON(http.StatusOK, tokenInfo)
+	response.Success(c, http.StatusOK, tokenInfo, nil)
 }
 
 // DebugToken анализирует JWT токен без проверки подписи
@@ -700,7 +748,7 @@ func (h *AuthHandler) DebugToken(c *gin.Context) {
 	isAdmin, exists := c.Get("is_admin")
 	isAdminBool, ok := isAdmin.(bool)
 	if !exists || !ok || !isAdminBool {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Только для администраторов"})
+		response.Error(c, http.StatusForbidden, "forbidden", nil)
 		return
 	}
 
@@ -710,7 +758,7 @@ func (h *AuthHandler) DebugToken(c *gin.Context) {
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		log.Printf("[AuthHandler] Ошибка валидации данных при отладке токена: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Требуется токен"})
+		response.Error(c, http.StatusBadRequest, "invalid_request", "token is required")
 		return
 	}
 
@@ -718,7 +766,42 @@ func (h *AuthHandler) DebugToken(c *gin.Context) {
 	result := h.authService.DebugToken(req.Token)
 
 	// Возвращаем информацию о токене
-	c.JSON(http.StatusOK, result)
+	response.Success(c, http.StatusOK, result, nil)
+}
+
+// RotateKeys ротирует ключ подписи JWT вне расписания. Выданные прежним ключом токены
+// принимаются до конца периода проверки (jwt.rotation.retention).
+// POST /api/auth/admin/rotate-keys
+func (h *AuthHandler) RotateKeys(c *gin.Context) {
+	rotation, err := h.tokenManager.RotateJWTKeysManually(c.Request.Context())
+	if err != nil {
+		log.Printf("[AuthHandler] Ошибка ротации ключей JWT: %v", err)
+		response.FromError(c, err)
+		return
+	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionJWTKeyRotate,
+		TargetType: entity.AuditTargetJWTKey,
+		TargetID:   rotation.NewKeyID,
+		Metadata:   map[string]interface{}{"previous_key_id": rotation.PreviousKeyID, "trigger": rotation.Trigger},
+	})
+	response.Success(c, http.StatusOK, gin.H{
+		"previous_key_id": rotation.PreviousKeyID,
+		"new_key_id":      rotation.NewKeyID,
+		"rotated_at":      rotation.At,
+	}, nil)
+}
+
+// ListKeys возвращает ключи подписи JWT с возрастом и использованием на этом инстансе
+// GET /api/auth/admin/keys
+func (h *AuthHandler) ListKeys(c *gin.Context) {
+	keys, err := h.tokenManager.JWTKeyStatuses(c.Request.Context())
+	if err != nil {
+		log.Printf("[AuthHandler] Ошибка получения ключей JWT: %v", err)
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{"keys": keys}, nil)
 }
 
 // ChangePassword обрабатывает запрос на изменение пароля пользователя
@@ -728,7 +811,7 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 	var req ChangePasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		log.Printf("[ChangePassword] Ошибка валидации запроса: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.InvalidRequest(c, err)
 		return
 	}
 
@@ -736,12 +819,12 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 
 	if err := h.authService.ChangePassword(userID, req.OldPassword, req.NewPassword); err != nil {
 		log.Printf("[ChangePassword] Ошибка при изменении пароля: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.Error(c, http.StatusBadRequest, "invalid_request", err.Error())
 		return
 	}
 
 	log.Printf("[ChangePassword] Пароль успешно изменен для пользователя ID=%d", userID)
-	c.JSON(http.StatusOK, gin.H{"message": "password changed successfully"})
+	response.Success(c, http.StatusOK, gin.H{"message": "password changed successfully"}, nil)
 }
 
 // AdminResetPassword обрабатывает запрос на сброс пароля администратором
@@ -750,37 +833,43 @@ func (h *AuthHandler) AdminResetPassword(c *gin.Context) {
 	isAdmin, exists := c.Get("is_admin")
 	isAdminBool, ok := isAdmin.(bool)
 	if !exists || !ok || !isAdminBool {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Только для администраторов"})
+		response.Error(c, http.StatusForbidden, "forbidden", nil)
 		return
 	}
 
 	var req ResetPasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.InvalidRequest(c, err)
 		return
 	}
 
 	// Находим пользователя по email
 	user, err := h.authService.GetUserByEmail(req.Email)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Пользователь не найден"})
+		response.Error(c, http.StatusNotFound, "not_found", nil)
 		return
 	}
 
 	// Обновляем пароль без проверки старого пароля
 	if err := h.authService.AdminResetPassword(user.ID, req.Password); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при сбросе пароля"})
+		response.Error(c, http.StatusInternalServerError, "internal_server_error", nil)
 		return
 	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionPasswordReset,
+		TargetType: entity.AuditTargetUser,
+		TargetID:   strconv.FormatUint(uint64(user.ID), 10),
+		Metadata:   map[string]interface{}{"email": user.Email},
+	})
 
-	c.JSON(http.StatusOK, gin.H{
+	response.Success(c, http.StatusOK, gin.H{
 		"message": "Пароль успешно сброшен",
 		"user": gin.H{
 			"id":       user.ID,
 			"username": user.Username,
 			"email":    user.Email,
 		},
-	})
+	}, nil)
 }
 
 // RevokeSession обрабатывает запрос на отзыв отдельной сессии
@@ -789,19 +878,19 @@ func (h *AuthHandler) RevokeSession(c *gin.Context) {
 
 	var req RevokeSessionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректные данные запроса", "error_type": "invalid_request"})
+		response.InvalidRequest(c, err)
 		return
 	}
 
 	// Проверяем, что сессия принадлежит пользователю
 	token, err := h.authService.GetRefreshTokenByID(req.SessionID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Сессия не найдена", "error_type": "session_not_found"})
+		response.Error(c, http.StatusNotFound, "session_not_found", nil)
 		return
 	}
 
 	if token.UserID != userID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Доступ запрещен", "error_type": "forbidden"})
+		response.Error(c, http.StatusForbidden, "forbidden", nil)
 		return
 	}
 
@@ -815,7 +904,7 @@ func (h *AuthHandler) RevokeSession(c *gin.Context) {
 	err = h.authService.RevokeSessionByID(req.SessionID, reason)
 	if err != nil {
 		log.Printf("[AuthHandler] Ошибка при отзыве сессии: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при отзыве сессии", "error_type": "internal_error"})
+		response.Error(c, http.StatusInternalServerError, "internal_server_error", nil)
 		return
 	}
 
@@ -837,7 +926,7 @@ func (h *AuthHandler) RevokeSession(c *gin.Context) {
 		}
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Сессия успешно завершена", "session_id": req.SessionID})
+	response.Success(c, http.StatusOK, gin.H{"message": "Сессия успешно завершена", "session_id": req.SessionID}, nil)
 }
 
 // GetSessionLimit возвращает текущий лимит сессий для пользователя
@@ -845,7 +934,7 @@ func (h *AuthHandler) GetSessionLimit(c *gin.Context) {
 	// Получаем ID пользователя из контекста
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не аутентифицирован", "error_type": "unauthorized"})
+		response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
 		return
 	}
 
@@ -862,15 +951,15 @@ func (h *AuthHandler) GetSessionLimit(c *gin.Context) {
 	sessions, err := h.authService.GetUserActiveSessions(userID.(uint))
 	if err != nil {
 		log.Printf("[AuthHandler] Ошибка при получении активных сессий: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении активных сессий", "error_type": "internal_error"})
+		response.Error(c, http.StatusInternalServerError, "internal_server_error", nil)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	response.Success(c, http.StatusOK, gin.H{
 		"limit":     limit,
 		"current":   len(sessions),
 		"remaining": limit - len(sessions),
-	})
+	}, nil)
 }
 
 // UpdateSessionLimit обновляет лимит сессий для пользователя (админ-функция)
@@ -880,15 +969,12 @@ func (h *AuthHandler) UpdateSessionLimit(c *gin.Context) {
 	isAdmin, exists := c.Get("is_admin")
 	isAdminBool, ok := isAdmin.(bool)
 	if !exists || !ok || !isAdminBool {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Требуются права администратора", "error_type": "forbidden"})
+		response.Error(c, http.StatusForbidden, "forbidden", nil)
 		return
 	}
 
 	log.Printf("[AuthHandler] Попытка вызова UpdateSessionLimit администратором ID=%d. Этот функционал управляется конфигурацией.", c.MustGet("user_id").(uint))
-	c.JSON(http.StatusNotImplemented, gin.H{
-		"message":    "Обновление лимита сессий через API больше не поддерживается. Этот параметр управляется конфигурационным файлом.",
-		"error_type": "not_implemented",
-	})
+	response.Error(c, http.StatusNotImplemented, "not_implemented", "session limit is managed by the configuration file")
 }
 
 // GenerateWsTicket генерирует краткоживущий токен для WebSocket подключения
@@ -896,7 +982,7 @@ func (h *AuthHandler) GenerateWsTicket(c *gin.Context) {
 	// Получаем ID пользователя из контекста (установлен middleware)
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized", "error_type": "token_missing"})
+		response.Error(c, http.StatusUnauthorized, "token_missing", nil)
 		return
 	}
 
@@ -906,7 +992,7 @@ func (h *AuthHandler) GenerateWsTicket(c *gin.Context) {
 		// Если email нет в контексте, получаем из БД
 		user, err := h.authService.GetUserByID(userID.(uint))
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user data"})
+			response.Error(c, http.StatusInternalServerError, "internal_server_error", nil)
 			return
 		}
 		email = user.Email
@@ -916,17 +1002,12 @@ func (h *AuthHandler) GenerateWsTicket(c *gin.Context) {
 	ticket, err := h.authService.GenerateWsTicket(c.Request.Context(), userID.(uint), email.(string))
 	if err != nil {
 		log.Printf("[AuthHandler] Ошибка генерации WS-тикета: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate WebSocket ticket"})
+		response.Error(c, http.StatusInternalServerError, "internal_server_error", nil)
 		return
 	}
 
-	// Возвращаем тикет клиенту
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data": gin.H{
-			"ticket": ticket,
-		},
-	})
+	// Возвращаем тикет клиенту (этот эндпоинт отдаёт конверт всегда)
+	response.Enveloped(c, http.StatusOK, gin.H{"ticket": ticket}, nil)
 }
 
 // GetCSRFToken возвращает хеш CSRF-токена, извлекая секрет из HttpOnly cookie
@@ -937,7 +1018,7 @@ func (h *AuthHandler) GetCSRFToken(c *gin.Context) {
 	if !exists {
 		// Эта ситуация не должна возникать, если используется RequireAuth
 		log.Printf("[AuthHandler] GetCSRFToken: User ID not found in context")
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized", "error_type": "context_missing_user"})
+		response.Error(c, http.StatusUnauthorized, "context_missing_user", nil)
 		return
 	}
 
@@ -946,20 +1027,33 @@ func (h *AuthHandler) GetCSRFToken(c *gin.Context) {
 	if err != nil {
 		log.Printf("[AuthHandler] GetCSRFToken: Ошибка получения CSRF секрета из cookie для пользователя ID=%v: %v", userID, err)
 		// Отправляем ошибку, указывающую на проблему с получением токена
-		c.JSON(http.StatusForbidden, gin.H{"error": "CSRF secret cookie missing or invalid", "error_type": "csrf_secret_cookie_error"})
+		response.Error(c, http.StatusForbidden, "csrf_secret_cookie_error", nil)
 		return
 	}
 	if csrfSecretCookie == "" {
 		log.Printf("[AuthHandler] GetCSRFToken: Пустое значение CSRF секрета в cookie для пользователя ID=%v", userID)
-		c.JSON(http.StatusForbidden, gin.H{"error": "Invalid CSRF secret cookie value", "error_type": "csrf_secret_cookie_empty"})
+		response.Error(c, http.StatusForbidden, "csrf_secret_cookie_empty", nil)
 		return
 	}
 
 	// Хешируем секрет с помощью публичной функции из TokenManager
 	csrfTokenHash := manager.HashCSRFSecret(csrfSecretCookie)
+	result := gin.H{"csrf_token": csrfTokenHash}
+
+	// В режиме synchronizer token администратор дополнительно получает nonce для /api/admin
+	if h.csrfNonces != nil && c.GetBool("is_admin") {
+		sessionID := h.tokenManager.EnsureAdminCSRFSession(c.Writer, c.Request)
+		nonce, err := h.csrfNonces.IssueNonce(c.Request.Context(), sessionID, userID.(uint))
+		if err != nil {
+			log.Printf("[AuthHandler] GetCSRFToken: Ошибка выдачи CSRF nonce для пользователя ID=%v: %v", userID, err)
+			response.Error(c, http.StatusInternalServerError, "internal_server_error", nil)
+			return
+		}
+		result["admin_csrf_token"] = nonce
+	}
 
 	// Возвращаем хеш токена
-	c.JSON(http.StatusOK, gin.H{"csrf_token": csrfTokenHash})
+	response.Success(c, http.StatusOK, result, nil)
 }
 
 // Вспомогательные функции
@@ -967,57 +1061,72 @@ func (h *AuthHandler) GetCSRFToken(c *gin.Context) {
 // Вспомогательные методы для проверки CSRF токена и обработки ошибок
 
 // handleAuthError обрабатывает ошибки аутентификации и возвращает соответствующие HTTP-ответы
+// webDeviceID возвращает идентификатор браузера для лимита аккаунтов на устройство.
+// Если клиент его не передал и куки нет, генерируется новый и сохраняется в куке.
+func (h *AuthHandler) webDeviceID(c *gin.Context, requested string) string {
+	if requested = strings.TrimSpace(requested); requested != "" {
+		return requested
+	}
+	if cookie, err := c.Cookie(manager.DeviceIDCookie); err == nil && cookie != "" && len(cookie) <= 255 {
+		return cookie
+	}
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		log.Printf("[AuthHandler] Не удалось сгенерировать идентификатор устройства: %v", err)
+		return ""
+	}
+	deviceID := "web-" + hex.EncodeToString(buf)
+	h.tokenManager.SetDeviceIDCookie(c.Writer, deviceID)
+	return deviceID
+}
+
 func (h *AuthHandler) handleAuthError(c *gin.Context, err error) {
-	var tokenErr *manager.TokenError
 	log.Printf("[AuthHandler] Auth Error: %v", err) // Логируем полную ошибку для отладки
+	writeAuthError(c, err)
+}
 
-	if errors.Is(err, service.ErrFeatureDisabled) {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Feature is disabled", "error_type": "feature_disabled"})
-	} else if errors.Is(err, service.ErrLinkRequired) {
-		c.JSON(http.StatusConflict, gin.H{"error": "Google account requires explicit linking", "error_type": "link_required"})
-	} else if errors.Is(err, service.ErrEmailNotVerified) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Email is not verified", "error_type": "email_not_verified"})
-	} else if errors.Is(err, service.ErrInvalidVerificationCode) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid verification code", "error_type": "invalid_verification_code"})
-	} else if errors.Is(err, service.ErrVerificationExpired) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Verification code expired", "error_type": "verification_expired"})
-	} else if errors.Is(err, service.ErrVerificationAttemptsExceeded) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Verification attempts exceeded", "error_type": "verification_attempts_exceeded"})
-	} else if errors.Is(err, service.ErrVerificationResendCooldown) {
-		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests", "error_type": "rate_limited"})
-	} else if errors.Is(err, service.ErrGoogleTokenVerificationFailed) {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Google token verification failed", "error_type": "token_invalid"})
-	} else if errors.As(err, &tokenErr) {
-		switch tokenErr.Type {
-		case manager.ExpiredRefreshToken, manager.ExpiredAccessToken:
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Сессия истекла", "error_type": "token_expired"})
-		case manager.InvalidRefreshToken, manager.InvalidAccessToken:
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Недействительный токен", "error_type": "token_invalid"})
-		case manager.InvalidCSRFToken:
-			c.JSON(http.StatusForbidden, gin.H{"error": "Недействительный CSRF токен", "error_type": "csrf_mismatch"})
-		case manager.UserNotFound:
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Неверные учетные данные", "error_type": "invalid_credentials"})
-		case manager.TokenGenerationFailed:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка обработки запроса", "error_type": "token_generation_failed"})
-		case manager.TooManySessions:
-			c.JSON(http.StatusConflict, gin.H{"error": "Превышен лимит активных сессий", "error_type": "too_many_sessions"})
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Внутренняя ошибка сервера", "error_type": "internal_server_error"})
-		}
-	} else if errors.Is(err, apperrors.ErrUnauthorized) {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Ошибка аутентификации или неверные данные", "error_type": "unauthorized"})
-	} else if errors.Is(err, apperrors.ErrForbidden) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Доступ запрещен", "error_type": "forbidden"})
-	} else if errors.Is(err, apperrors.ErrNotFound) {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Запрашиваемый ресурс не найден", "error_type": "not_found"})
-	} else if errors.Is(err, apperrors.ErrConflict) {
-		c.JSON(http.StatusConflict, gin.H{"error": "Конфликт данных", "error_type": "conflict"})
-	} else if errors.Is(err, apperrors.ErrValidation) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Ошибка валидации данных", "error_type": "validation_error"})
-	} else if errors.Is(err, apperrors.ErrExpiredToken) {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Токен истек", "error_type": "token_expired"})
-	} else {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Внутренняя ошибка сервера", "error_type": "internal_server_error"})
+// writeAuthError переводит ошибку сервиса аутентификации в HTTP-статус и код ошибки.
+// Общая для веб- и мобильного обработчиков, чтобы клиенты получали одинаковые коды;
+// ошибки apperrors и manager.TokenError разбирает response.FromError.
+func writeAuthError(c *gin.Context, err error) {
+	var rejected *service.ContentRejectedError
+	switch {
+	case errors.Is(err, service.ErrFeatureDisabled):
+		response.Error(c, http.StatusNotFound, "feature_disabled", nil)
+	case errors.Is(err, service.ErrLinkRequired):
+		response.Error(c, http.StatusConflict, "link_required", nil)
+	case errors.Is(err, service.ErrEmailNotVerified):
+		response.Error(c, http.StatusForbidden, "email_not_verified", nil)
+	case errors.Is(err, service.ErrInvalidVerificationCode):
+		response.Error(c, http.StatusBadRequest, "invalid_verification_code", nil)
+	case errors.Is(err, service.ErrVerificationExpired):
+		response.Error(c, http.StatusBadRequest, "verification_expired", nil)
+	case errors.Is(err, service.ErrVerificationAttemptsExceeded):
+		response.Error(c, http.StatusBadRequest, "verification_attempts_exceeded", nil)
+	case errors.Is(err, service.ErrVerificationResendCooldown):
+		response.Error(c, http.StatusTooManyRequests, "rate_limited", nil)
+	case errors.Is(err, service.ErrDeviceAccountLimit):
+		response.Error(c, http.StatusTooManyRequests, "device_account_limit", nil)
+	case errors.Is(err, service.ErrInvalidReferralCode):
+		response.Error(c, http.StatusBadRequest, "invalid_referral_code", nil)
+	case errors.As(err, &rejected):
+		response.Error(c, http.StatusBadRequest, "inappropriate_content", gin.H{"field": rejected.Field})
+	case errors.Is(err, service.ErrMagicLinkInvalid):
+		response.Error(c, http.StatusBadRequest, "invalid_magic_link", nil)
+	case errors.Is(err, service.ErrMagicLinkExpired):
+		response.Error(c, http.StatusBadRequest, "magic_link_expired", nil)
+	case errors.Is(err, service.ErrMagicLinkDeviceMismatch):
+		response.Error(c, http.StatusForbidden, "magic_link_device_mismatch", nil)
+	case errors.Is(err, service.ErrWebAuthnCeremonyInvalid):
+		response.Error(c, http.StatusBadRequest, "webauthn_session_invalid", nil)
+	case errors.Is(err, service.ErrWebAuthnVerificationFailed):
+		response.Error(c, http.StatusUnauthorized, "passkey_invalid", nil)
+	case errors.Is(err, service.ErrPasskeyRequired):
+		response.Error(c, http.StatusForbidden, "passkey_required", nil)
+	case errors.Is(err, service.ErrGoogleTokenVerificationFailed):
+		response.Error(c, http.StatusUnauthorized, "token_invalid", "Google token verification failed")
+	default:
+		response.FromError(c, err)
 	}
 }
 
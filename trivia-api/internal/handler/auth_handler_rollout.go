@@ -3,9 +3,12 @@ package handler
 import (
 	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/yourusername/trivia-api/internal/handler/response"
 	"github.com/yourusername/trivia-api/internal/service"
+	"github.com/yourusername/trivia-api/pkg/auth/manager"
 )
 
 type VerifyEmailConfirmRequest struct {
@@ -29,6 +32,13 @@ type GoogleLinkRequest struct {
 	Platform     string `json:"platform"`
 }
 
+// MagicLinkRequest — запрос ссылки для входа без пароля. device_id передают мобильные
+// клиенты; браузер привязывается к куке device_id.
+type MagicLinkRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	DeviceID string `json:"device_id" binding:"omitempty,max=255"`
+}
+
 type DeleteAccountRequest struct {
 	Password string `json:"password"`
 	Reason   string `json:"reason"`
@@ -40,14 +50,14 @@ func (h *AuthHandler) SendEmailVerificationCode(c *gin.Context) {
 		h.handleAuthError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "verification code sent"})
+	response.Success(c, http.StatusOK, gin.H{"message": "verification code sent"}, nil)
 }
 
 func (h *AuthHandler) ConfirmEmailVerificationCode(c *gin.Context) {
 	userID := c.MustGet("user_id").(uint)
 	var req VerifyEmailConfirmRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "error_type": "validation_error"})
+		response.ValidationError(c, err)
 		return
 	}
 
@@ -55,7 +65,7 @@ func (h *AuthHandler) ConfirmEmailVerificationCode(c *gin.Context) {
 		h.handleAuthError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "email verified"})
+	response.Success(c, http.StatusOK, gin.H{"message": "email verified"}, nil)
 }
 
 func (h *AuthHandler) GetEmailVerificationStatus(c *gin.Context) {
@@ -65,13 +75,13 @@ func (h *AuthHandler) GetEmailVerificationStatus(c *gin.Context) {
 		h.handleAuthError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, status)
+	response.Success(c, http.StatusOK, status, nil)
 }
 
 func (h *AuthHandler) GoogleExchange(c *gin.Context) {
 	var req GoogleExchangeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "error_type": "validation_error"})
+		response.ValidationError(c, err)
 		return
 	}
 
@@ -89,11 +99,7 @@ func (h *AuthHandler) GoogleExchange(c *gin.Context) {
 	result, err := h.authService.ExchangeGoogleAuth(c.Request.Context(), input)
 	if err != nil {
 		if errors.Is(err, service.ErrLinkRequired) {
-			c.JSON(http.StatusConflict, gin.H{
-				"error":      "Google account requires explicit linking",
-				"error_type": "link_required",
-				"user":       serializeUserForClient(result.User),
-			})
+			response.Error(c, http.StatusConflict, "link_required", gin.H{"user": serializeUserForClient(result.User)})
 			return
 		}
 		h.handleAuthError(c, err)
@@ -104,14 +110,66 @@ func (h *AuthHandler) GoogleExchange(c *gin.Context) {
 	h.tokenManager.SetAccessTokenCookie(c.Writer, result.Token.AccessToken)
 	h.tokenManager.SetCSRFSecretCookie(c.Writer, result.Token.CSRFSecret)
 
-	c.JSON(http.StatusOK, gin.H{
+	response.Success(c, http.StatusOK, gin.H{
 		"user":        serializeUserForClient(result.User),
 		"accessToken": result.Token.AccessToken,
 		"csrfToken":   result.Token.CSRFToken,
 		"userId":      result.Token.UserID,
 		"expiresIn":   result.Token.ExpiresIn,
 		"tokenType":   "Bearer",
-	})
+	}, nil)
+}
+
+// RequestMagicLink отправляет ссылку для входа. Ответ одинаков для зарегистрированных
+// и неизвестных адресов.
+func (h *AuthHandler) RequestMagicLink(c *gin.Context) {
+	var req MagicLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	deviceID := h.webDeviceID(c, req.DeviceID)
+	if err := h.authService.RequestMagicLink(c.Request.Context(), req.Email, deviceID, c.ClientIP()); err != nil {
+		h.handleAuthError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{"message": "if the email is registered, a login link has been sent"}, nil)
+}
+
+// VerifyMagicLink использует ссылку и выдаёт пару токенов, как при входе по паролю.
+// Ссылку нужно открыть на устройстве, с которого её запросили.
+func (h *AuthHandler) VerifyMagicLink(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		response.Error(c, http.StatusBadRequest, "validation_error", "token is required")
+		return
+	}
+	deviceID := strings.TrimSpace(c.Query("device_id"))
+	if deviceID == "" {
+		deviceID, _ = c.Cookie(manager.DeviceIDCookie)
+	}
+
+	tokenResp, user, err := h.authService.LoginWithMagicLink(c.Request.Context(), token, deviceID, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		recordLoginAudit(c, h.auditService, "", deviceID, "magic_link", 0, err)
+		h.handleAuthError(c, err)
+		return
+	}
+
+	h.tokenManager.SetRefreshTokenCookie(c.Writer, tokenResp.RefreshToken)
+	h.tokenManager.SetAccessTokenCookie(c.Writer, tokenResp.AccessToken)
+	h.tokenManager.SetCSRFSecretCookie(c.Writer, tokenResp.CSRFSecret)
+	recordLoginAudit(c, h.auditService, user.Email, deviceID, "magic_link", user.ID, nil)
+
+	response.Success(c, http.StatusOK, gin.H{
+		"user":        serializeUserForClient(user),
+		"accessToken": tokenResp.AccessToken,
+		"csrfToken":   tokenResp.CSRFToken,
+		"userId":      tokenResp.UserID,
+		"expiresIn":   tokenResp.ExpiresIn,
+		"tokenType":   "Bearer",
+	}, nil)
 }
 
 func (h *AuthHandler) GoogleLink(c *gin.Context) {
@@ -119,7 +177,7 @@ func (h *AuthHandler) GoogleLink(c *gin.Context) {
 
 	var req GoogleLinkRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "error_type": "validation_error"})
+		response.ValidationError(c, err)
 		return
 	}
 
@@ -140,10 +198,10 @@ func (h *AuthHandler) GoogleLink(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	response.Success(c, http.StatusOK, gin.H{
 		"message": "google account linked",
 		"user":    serializeUserForClient(user),
-	})
+	}, nil)
 }
 
 func (h *AuthHandler) DeleteMe(c *gin.Context) {
@@ -167,5 +225,5 @@ func (h *AuthHandler) DeleteMe(c *gin.Context) {
 	h.tokenManager.ClearRefreshTokenCookie(c.Writer)
 	h.tokenManager.ClearCSRFSecretCookie(c.Writer)
 
-	c.JSON(http.StatusOK, gin.H{"message": "account deleted"})
+	response.Success(c, http.StatusOK, gin.H{"message": "account deleted"}, nil)
 }
@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/handler/response"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+// PasskeyRegisterFinishRequest — ответ navigator.credentials.create, пересланный как есть
+type PasskeyRegisterFinishRequest struct {
+	SessionID  string          `json:"session_id" binding:"required"`
+	Name       string          `json:"name" binding:"omitempty,max=100"`
+	Credential json.RawMessage `json:"credential" binding:"required"`
+}
+
+// PasskeyLoginFinishRequest — ответ navigator.credentials.get, пересланный как есть
+type PasskeyLoginFinishRequest struct {
+	SessionID  string          `json:"session_id" binding:"required"`
+	Credential json.RawMessage `json:"credential" binding:"required"`
+	DeviceID   string          `json:"device_id" binding:"omitempty,max=255"`
+}
+
+// BeginPasskeyRegistration возвращает параметры для navigator.credentials.create
+// POST /api/auth/webauthn/register/begin
+func (h *AuthHandler) BeginPasskeyRegistration(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	options, sessionID, err := h.authService.BeginPasskeyRegistration(c.Request.Context(), userID)
+	if err != nil {
+		h.handleAuthError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{"session_id": sessionID, "options": options}, nil)
+}
+
+// FinishPasskeyRegistration проверяет созданный ключ и сохраняет его
+// POST /api/auth/webauthn/register/finish
+func (h *AuthHandler) FinishPasskeyRegistration(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	var req PasskeyRegisterFinishRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	credential, err := h.authService.FinishPasskeyRegistration(c.Request.Context(), userID, req.SessionID, req.Name, req.Credential)
+	if err != nil {
+		h.handleAuthError(c, err)
+		return
+	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionPasskeyRegister,
+		TargetType: entity.AuditTargetUser,
+		TargetID:   strconv.FormatUint(uint64(userID), 10),
+		Metadata:   map[string]interface{}{"credential_id": credential.ID, "name": credential.Name},
+	})
+	response.Success(c, http.StatusCreated, credential, nil)
+}
+
+// ListPasskeys возвращает ключи текущего пользователя
+// GET /api/auth/webauthn/credentials
+func (h *AuthHandler) ListPasskeys(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	credentials, err := h.authService.ListPasskeys(userID)
+	if err != nil {
+		h.handleAuthError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{"credentials": credentials}, nil)
+}
+
+// DeletePasskey удаляет ключ текущего пользователя
+// DELETE /api/auth/webauthn/credentials/:id
+func (h *AuthHandler) DeletePasskey(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid_request", "некорректный ID")
+		return
+	}
+
+	if err := h.authService.DeletePasskey(c.Request.Context(), userID, uint(id)); err != nil {
+		h.handleAuthError(c, err)
+		return
+	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionPasskeyDelete,
+		TargetType: entity.AuditTargetUser,
+		TargetID:   strconv.FormatUint(uint64(userID), 10),
+		Metadata:   map[string]interface{}{"credential_id": id},
+	})
+	response.Success(c, http.StatusOK, gin.H{"message": "passkey deleted"}, nil)
+}
+
+// BeginPasskeyLogin возвращает параметры для navigator.credentials.get. Email не нужен:
+// браузер сам предлагает ключи, сохранённые для сайта.
+// POST /api/auth/webauthn/login/begin
+func (h *AuthHandler) BeginPasskeyLogin(c *gin.Context) {
+	options, sessionID, err := h.authService.BeginPasskeyLogin()
+	if err != nil {
+		h.handleAuthError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{"session_id": sessionID, "options": options}, nil)
+}
+
+// FinishPasskeyLogin проверяет подпись ключа и выдаёт пару токенов, как при входе по паролю
+// POST /api/auth/webauthn/login/finish
+func (h *AuthHandler) FinishPasskeyLogin(c *gin.Context) {
+	var req PasskeyLoginFinishRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	deviceID := h.webDeviceID(c, req.DeviceID)
+	tokenResp, user, err := h.authService.LoginWithPasskey(c.Request.Context(), req.SessionID, req.Credential, deviceID, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		recordLoginAudit(c, h.auditService, "", deviceID, "passkey", 0, err)
+		h.handleAuthError(c, err)
+		return
+	}
+
+	h.tokenManager.SetRefreshTokenCookie(c.Writer, tokenResp.RefreshToken)
+	h.tokenManager.SetAccessTokenCookie(c.Writer, tokenResp.AccessToken)
+	h.tokenManager.SetCSRFSecretCookie(c.Writer, tokenResp.CSRFSecret)
+	recordLoginAudit(c, h.auditService, user.Email, deviceID, "passkey", user.ID, nil)
+
+	response.Success(c, http.StatusOK, gin.H{
+		"user":        serializeUserForClient(user),
+		"accessToken": tokenResp.AccessToken,
+		"csrfToken":   tokenResp.CSRFToken,
+		"userId":      tokenResp.UserID,
+		"expiresIn":   tokenResp.ExpiresIn,
+		"tokenType":   "Bearer",
+	}, nil)
+}
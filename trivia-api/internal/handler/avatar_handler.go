@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+// AvatarHandler обрабатывает загрузку аватаров пользователей
+type AvatarHandler struct {
+	avatarService *service.AvatarService
+}
+
+// NewAvatarHandler создает новый обработчик аватаров
+func NewAvatarHandler(avatarService *service.AvatarService) *AvatarHandler {
+	return &AvatarHandler{avatarService: avatarService}
+}
+
+// UploadAvatar загружает изображение аватара (multipart, поле "avatar")
+// POST /api/users/me/avatar
+func (h *AvatarHandler) UploadAvatar(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	file, err := c.FormFile("avatar")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Avatar file is required", "error_type": "validation_error"})
+		return
+	}
+	if file.Size > service.MaxAvatarUploadBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error":      fmt.Sprintf("Avatar must not exceed %d MB", service.MaxAvatarUploadBytes/(1024*1024)),
+			"error_type": "file_too_large",
+		})
+		return
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read avatar file", "error_type": "validation_error"})
+		return
+	}
+	defer src.Close()
+
+	url, err := h.avatarService.UploadAvatar(userID, src)
+	if err != nil {
+		h.handleAvatarError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"profile_picture": url})
+}
+
+// DeleteAvatar удаляет аватар пользователя
+// DELETE /api/users/me/avatar
+func (h *AvatarHandler) DeleteAvatar(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	if err := h.avatarService.DeleteAvatar(userID); err != nil {
+		h.handleAvatarError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"profile_picture": ""})
+}
+
+func (h *AvatarHandler) handleAvatarError(c *gin.Context, err error) {
+	if errors.Is(err, apperrors.ErrValidation) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "error_type": "validation_error"})
+	} else if errors.Is(err, apperrors.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found", "error_type": "not_found"})
+	} else {
+		log.Printf("[AvatarHandler] Ошибка обработки аватара: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error", "error_type": "internal_server_error"})
+	}
+}
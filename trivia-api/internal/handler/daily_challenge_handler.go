@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/trivia-api/internal/handler/response"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+// DailyChallengeHandler обрабатывает вопрос дня
+type DailyChallengeHandler struct {
+	dailyService *service.DailyChallengeService
+}
+
+// NewDailyChallengeHandler создает обработчик вопроса дня
+func NewDailyChallengeHandler(dailyService *service.DailyChallengeService) *DailyChallengeHandler {
+	return &DailyChallengeHandler{dailyService: dailyService}
+}
+
+// DailyAnswerRequest представляет ответ на вопрос дня
+type DailyAnswerRequest struct {
+	QuestionID     uint `json:"question_id" binding:"required"`
+	SelectedOption *int `json:"selected_option" binding:"required"`
+}
+
+// GetToday возвращает вопрос дня; после ответа — вместе с ответом и верным вариантом
+// GET /api/daily-challenge
+func (h *DailyChallengeHandler) GetToday(c *gin.Context) {
+	view, err := h.dailyService.GetToday(c.MustGet("user_id").(uint))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, view, nil)
+}
+
+// Answer принимает ответ на вопрос дня
+// POST /api/daily-challenge/answer
+func (h *DailyChallengeHandler) Answer(c *gin.Context) {
+	var req DailyAnswerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid_request", "question_id and selected_option are required")
+		return
+	}
+	result, err := h.dailyService.Answer(c.MustGet("user_id").(uint), req.QuestionID, *req.SelectedOption)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, result, nil)
+}
+
+// GetLeaderboard возвращает лидерборд вопроса дня
+// GET /api/daily-challenge/leaderboard?date=2026-10-16&page=1&page_size=20
+func (h *DailyChallengeHandler) GetLeaderboard(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	users, total, err := h.dailyService.GetLeaderboard(c.Query("date"), page, pageSize)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{
+		"users":     users,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	}, nil)
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yourusername/trivia-api/internal/handler/response"
+)
+
+// DBStatsSource возвращает статистику пула соединений (*sql.DB)
+type DBStatsSource interface {
+	Stats() sql.DBStats
+}
+
+// SlowQueryCounter возвращает число медленных запросов и их порог (database.SlowQueryLogger)
+type SlowQueryCounter interface {
+	SlowQueries() int64
+	Threshold() time.Duration
+}
+
+// DBStatsHandler отдаёт администраторам состояние пула соединений с БД
+type DBStatsHandler struct {
+	db          DBStatsSource
+	slowQueries SlowQueryCounter
+}
+
+// NewDBStatsHandler создает обработчик статистики БД. slowQueries может быть nil,
+// если логирование медленных запросов отключено.
+func NewDBStatsHandler(db DBStatsSource, slowQueries SlowQueryCounter) *DBStatsHandler {
+	return &DBStatsHandler{db: db, slowQueries: slowQueries}
+}
+
+// GetStats возвращает статистику пула соединений этого инстанса
+// GET /api/admin/db/stats
+func (h *DBStatsHandler) GetStats(c *gin.Context) {
+	stats := h.db.Stats()
+
+	// Доля занятых соединений от лимита; без лимита (0) считаем от открытых
+	var utilization float64
+	if stats.MaxOpenConnections > 0 {
+		utilization = float64(stats.InUse) / float64(stats.MaxOpenConnections)
+	} else if stats.OpenConnections > 0 {
+		utilization = float64(stats.InUse) / float64(stats.OpenConnections)
+	}
+
+	body := gin.H{
+		"max_open_connections": stats.MaxOpenConnections,
+		"open_connections":     stats.OpenConnections,
+		"in_use":               stats.InUse,
+		"idle":                 stats.Idle,
+		"utilization":          utilization,
+		"wait_count":           stats.WaitCount,
+		"wait_duration_ms":     stats.WaitDuration.Milliseconds(),
+		"max_idle_closed":      stats.MaxIdleClosed,
+		"max_idle_time_closed": stats.MaxIdleTimeClosed,
+		"max_lifetime_closed":  stats.MaxLifetimeClosed,
+	}
+	if h.slowQueries != nil {
+		body["slow_queries"] = h.slowQueries.SlowQueries()
+		body["slow_query_threshold_ms"] = h.slowQueries.Threshold().Milliseconds()
+	}
+	response.Success(c, http.StatusOK, body, nil)
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/yourusername/trivia-api/internal/domain/entity" // Используем правильный путь модуля
 	"github.com/yourusername/trivia-api/internal/handler/helper"
+	"github.com/yourusername/trivia-api/internal/pkg/i18n"
 )
 
 // QuestionResponse представляет вопрос в формате для ответа клиенту
@@ -13,6 +14,7 @@ type QuestionResponse struct {
 	QuizID       uint                    `json:"quiz_id"`
 	Text         string                  `json:"text"`
 	Options      []helper.QuestionOption `json:"options"`
+	Locale       string                  `json:"locale,omitempty"` // Язык, на котором отдан текст (может быть запасным)
 	TimeLimitSec int                     `json:"time_limit_sec"`
 	PointValue   int                     `json:"point_value"`
 	CreatedAt    time.Time               `json:"created_at"`
@@ -21,18 +23,36 @@ type QuestionResponse struct {
 
 // QuizResponse представляет викторину в формате для ответа клиенту
 type QuizResponse struct {
-	ID                  uint               `json:"id"`
-	Title               string             `json:"title"`
-	Description         string             `json:"description,omitempty"`
-	ScheduledTime       time.Time          `json:"scheduled_time"`
-	Status              string             `json:"status"`
-	QuestionCount       int                `json:"question_count"`
-	PrizeFund           int                `json:"prize_fund"`
-	FinishOnZeroPlayers bool               `json:"finish_on_zero_players"`
-	QuestionSourceMode  string             `json:"question_source_mode"`
-	Questions           []QuestionResponse `json:"questions,omitempty"` // Слайс DTO вопросов
-	CreatedAt           time.Time          `json:"created_at"`
-	UpdatedAt           time.Time          `json:"updated_at"`
+	ID                  uint                    `json:"id"`
+	Title               string                  `json:"title"`
+	Description         string                  `json:"description,omitempty"`
+	ScheduledTime       time.Time               `json:"scheduled_time"`
+	Status              string                  `json:"status"`
+	QuestionCount       int                     `json:"question_count"`
+	PrizeFund           int                     `json:"prize_fund"`
+	FinishOnZeroPlayers bool                    `json:"finish_on_zero_players"`
+	QuestionSourceMode  string                  `json:"question_source_mode"`
+	SecondChanceMode    string                  `json:"second_chance_mode"`
+	SecondChanceCost    int64                   `json:"second_chance_cost,omitempty"`
+	MaxPlayers          int                     `json:"max_players,omitempty"`
+	GameMode            string                  `json:"game_mode"`
+	Lives               int                     `json:"lives,omitempty"` // Только в режиме lives
+	SuddenDeath         bool                    `json:"sudden_death"`
+	TieBreak            string                  `json:"tie_break"`
+	ScoringStrategy     string                  `json:"scoring_strategy"`
+	RequireVerifiedKK   bool                    `json:"require_verified_kk"`
+	PrizeLadder         entity.PrizeLadder      `json:"prize_ladder,omitempty"`      // Пустая — фонд делится поровну
+	AllowedCountries    []string                `json:"allowed_countries,omitempty"` // Призы — только игрокам из этих стран
+	Timing              *entity.QuizTiming      `json:"timing,omitempty"`            // Только заданные переопределения таймингов
+	Sponsor             *entity.SponsorBranding `json:"sponsor,omitempty"`           // Заполняется в карточке викторины
+	CategoryID          *uint                   `json:"category_id,omitempty"`
+	RSVPCount           *int64                  `json:"rsvp_count,omitempty"`
+	Tags                []entity.Tag            `json:"tags,omitempty"`      // Заполняются в списке викторин
+	Questions           []QuestionResponse      `json:"questions,omitempty"` // Слайс DTO вопросов
+	Locale              string                  `json:"locale,omitempty"`    // Выбранный язык контента
+	LocaleFallbacks     []string                `json:"locale_fallbacks,omitempty"`
+	CreatedAt           time.Time               `json:"created_at"`
+	UpdatedAt           time.Time               `json:"updated_at"`
 }
 
 // AskedQuestionDetailsResponse содержит детали фактически заданного вопроса.
@@ -64,6 +84,8 @@ type ResultResponse struct {
 	UserID               uint      `json:"user_id"`
 	QuizID               uint      `json:"quiz_id"`
 	Username             string    `json:"username"`
+	FullName             string    `json:"full_name,omitempty"` // только если игрок разрешил показывать имя
+	Anonymous            bool      `json:"anonymous,omitempty"` // игрок скрыт из публичных результатов: username — заглушка, user_id = 0
 	ProfilePicture       string    `json:"profile_picture,omitempty"`
 	Score                int       `json:"score"`
 	CorrectAnswers       int       `json:"correct_answers"`
@@ -74,6 +96,8 @@ type ResultResponse struct {
 	IsEliminated         bool      `json:"is_eliminated"`
 	EliminatedOnQuestion *int      `json:"eliminated_on_question,omitempty"`
 	EliminationReason    *string   `json:"elimination_reason,omitempty"`
+	Revived              bool      `json:"revived"`
+	ScoringStrategy      string    `json:"scoring_strategy"` // стратегия, по которой начислены очки
 	CompletedAt          time.Time `json:"completed_at"`
 }
 
@@ -85,6 +109,14 @@ type PaginatedResultResponse struct {
 	PerPage int               `json:"per_page"`
 }
 
+// CursorResultResponse представляет страницу результатов при пагинации курсором.
+// NextCursor пуст на последней странице.
+type CursorResultResponse struct {
+	Results    []*ResultResponse `json:"results"`
+	PerPage    int               `json:"per_page"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+}
+
 // NewQuestionResponse создает DTO для вопроса
 // Примечание: Эта функция используется внутри NewQuizResponse
 func NewQuestionResponse(q *entity.Question) QuestionResponse {
@@ -158,6 +190,11 @@ func NewQuizResponse(quiz *entity.Quiz, includeQuestions bool) *QuizResponse {
 		}
 	}
 
+	var timing *entity.QuizTiming
+	if !quiz.Timing.IsEmpty() {
+		timing = &quiz.Timing
+	}
+
 	return &QuizResponse{
 		ID:                  quiz.ID,
 		Title:               quiz.Title,
@@ -168,12 +205,40 @@ func NewQuizResponse(quiz *entity.Quiz, includeQuestions bool) *QuizResponse {
 		PrizeFund:           quiz.PrizeFund,
 		FinishOnZeroPlayers: quiz.FinishOnZeroPlayers,
 		QuestionSourceMode:  questionSourceMode,
+		SecondChanceMode:    quiz.SecondChanceMode,
+		SecondChanceCost:    quiz.SecondChanceCost,
+		MaxPlayers:          quiz.MaxPlayers,
+		GameMode:            quiz.EffectiveGameMode(),
+		Lives:               quiz.Lives,
+		SuddenDeath:         quiz.SuddenDeath,
+		TieBreak:            quiz.EffectiveTieBreak(),
+		ScoringStrategy:     quiz.EffectiveScoringStrategy(),
+		RequireVerifiedKK:   quiz.RequireVerifiedKK,
+		PrizeLadder:         quiz.PrizeLadder,
+		AllowedCountries:    quiz.AllowedCountries,
+		Timing:              timing,
+		CategoryID:          quiz.CategoryID,
+		Tags:                quiz.Tags,
 		Questions:           questionsDTO,
 		CreatedAt:           quiz.CreatedAt,
 		UpdatedAt:           quiz.UpdatedAt,
 	}
 }
 
+// ApplyLocalization подставляет в DTO викторины тексты вопросов на выбранном языке
+func ApplyLocalization(resp *QuizResponse, resolution i18n.Resolution, localized map[uint]entity.LocalizedQuestion) {
+	resp.Locale = resolution.Locale
+	resp.LocaleFallbacks = resolution.Fallbacks
+	for i := range resp.Questions {
+		question := &resp.Questions[i]
+		if l, ok := localized[question.ID]; ok {
+			question.Text = l.Text
+			question.Options = helper.ConvertOptionsToObjects(l.Options)
+			question.Locale = l.Locale
+		}
+	}
+}
+
 // NewResultResponse создает DTO для результата
 func NewResultResponse(result *entity.Result) *ResultResponse {
 	if result == nil {
@@ -184,6 +249,8 @@ func NewResultResponse(result *entity.Result) *ResultResponse {
 		UserID:               result.UserID,
 		QuizID:               result.QuizID,
 		Username:             result.Username,
+		FullName:             result.FullName,
+		Anonymous:            result.Anonymous,
 		ProfilePicture:       result.ProfilePicture,
 		Score:                result.Score,
 		CorrectAnswers:       result.CorrectAnswers,
@@ -194,6 +261,8 @@ func NewResultResponse(result *entity.Result) *ResultResponse {
 		IsEliminated:         result.IsEliminated,
 		EliminatedOnQuestion: result.EliminatedOnQuestion,
 		EliminationReason:    result.EliminationReason,
+		Revived:              result.Revived,
+		ScoringStrategy:      result.ScoringStrategy,
 		CompletedAt:          result.CompletedAt,
 	}
 }
@@ -208,6 +277,46 @@ func NewListQuizResponse(quizzes []entity.Quiz) []*QuizResponse {
 	return list
 }
 
+// ApplyRSVPCounts проставляет викторинам списка число записавшихся; nil counts — счётчики неизвестны
+func ApplyRSVPCounts(list []*QuizResponse, counts map[uint]int64) {
+	if counts == nil {
+		return
+	}
+	for _, resp := range list {
+		count := counts[resp.ID]
+		resp.RSVPCount = &count
+	}
+}
+
+// ScheduledQuizResponse — викторина в расписании: отсчёт до старта, категория и число
+// зарегистрированных участников
+type ScheduledQuizResponse struct {
+	*QuizResponse
+	StartsInSeconds int64            `json:"starts_in_seconds"`
+	Category        *entity.Category `json:"category,omitempty"`
+	RegisteredCount int64            `json:"registered_count"`
+}
+
+// NewScheduledQuizListResponse создает расписание викторин на момент now.
+// registrations — число участников по ID викторины.
+func NewScheduledQuizListResponse(quizzes []entity.Quiz, registrations map[uint]int64, now time.Time) []*ScheduledQuizResponse {
+	list := make([]*ScheduledQuizResponse, len(quizzes))
+	for i := range quizzes {
+		quiz := &quizzes[i]
+		startsIn := int64(quiz.ScheduledTime.Sub(now).Seconds())
+		if startsIn < 0 {
+			startsIn = 0
+		}
+		list[i] = &ScheduledQuizResponse{
+			QuizResponse:    NewQuizResponse(quiz, false),
+			StartsInSeconds: startsIn,
+			Category:        quiz.Category,
+			RegisteredCount: registrations[quiz.ID],
+		}
+	}
+	return list
+}
+
 // NewListResultResponse создает слайс DTO для списка результатов
 func NewListResultResponse(results []entity.Result) []*ResultResponse {
 	list := make([]*ResultResponse, len(results))
@@ -226,3 +335,12 @@ func NewPaginatedResultResponse(results []entity.Result, total int64, page, perP
 		PerPage: perPage,
 	}
 }
+
+// NewCursorResultResponse создает DTO для страницы результатов, полученной по курсору
+func NewCursorResultResponse(results []entity.Result, perPage int, nextCursor string) *CursorResultResponse {
+	return &CursorResultResponse{
+		Results:    NewListResultResponse(results),
+		PerPage:    perPage,
+		NextCursor: nextCursor,
+	}
+}
@@ -1,13 +1,20 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
 // LeaderboardUserDTO представляет одного пользователя в лидерборде
 type LeaderboardUserDTO struct {
-	Rank           int    `json:"rank"`            // Место пользователя в рейтинге
-	UserID         uint   `json:"user_id"`         // ID пользователя
-	Username       string `json:"username"`        // Имя пользователя
-	ProfilePicture string `json:"profile_picture"` // Аватар пользователя
-	WinsCount      int64  `json:"wins_count"`      // Количество побед
-	TotalPrizeWon  int64  `json:"total_prize_won"` // Общая сумма выигранных призов
+	Rank           int    `json:"rank"`                // Место пользователя в рейтинге
+	UserID         uint   `json:"user_id"`             // ID пользователя
+	Username       string `json:"username"`            // Имя пользователя
+	ProfilePicture string `json:"profile_picture"`     // Аватар пользователя
+	WinsCount      int64  `json:"wins_count"`          // Количество побед
+	TotalPrizeWon  int64  `json:"total_prize_won"`     // Общая сумма выигранных призов
+	Anonymous      bool   `json:"anonymous,omitempty"` // Игрок скрыт из лидербордов: username — заглушка, user_id = 0
 }
 
 // PaginatedLeaderboardResponse представляет пагинированный ответ для лидерборда
@@ -17,3 +24,63 @@ type PaginatedLeaderboardResponse struct {
 	Page    int                   `json:"page"`     // Текущая страница
 	PerPage int                   `json:"per_page"` // Количество пользователей на странице
 }
+
+// RatingLeaderboardUserDTO представляет игрока в лидерборде по рейтингу мастерства
+type RatingLeaderboardUserDTO struct {
+	Rank           int    `json:"rank"`                // Место в лидерборде
+	UserID         uint   `json:"user_id"`             // ID пользователя
+	Username       string `json:"username"`            // Имя пользователя
+	ProfilePicture string `json:"profile_picture"`     // Аватар пользователя
+	Rating         int    `json:"rating"`              // Рейтинг мастерства
+	Games          int    `json:"games"`               // Викторин, учтённых в рейтинге
+	Provisional    bool   `json:"provisional"`         // Рейтинг ещё не устоялся
+	Anonymous      bool   `json:"anonymous,omitempty"` // Игрок скрыт из лидербордов: username — заглушка, user_id = 0
+}
+
+// PaginatedRatingLeaderboardResponse представляет страницу лидерборда по рейтингу
+type PaginatedRatingLeaderboardResponse struct {
+	Users   []*RatingLeaderboardUserDTO `json:"users"`    // Список игроков на странице
+	Total   int64                       `json:"total"`    // Игроков с рейтингом
+	Page    int                         `json:"page"`     // Текущая страница
+	PerPage int                         `json:"per_page"` // Количество игроков на странице
+}
+
+// CursorLeaderboardResponse представляет страницу лидерборда при пагинации курсором
+type CursorLeaderboardResponse struct {
+	Users      []*LeaderboardUserDTO `json:"users"`                 // Список пользователей на странице
+	PerPage    int                   `json:"per_page"`              // Количество пользователей на странице
+	NextCursor string                `json:"next_cursor,omitempty"` // Курсор следующей страницы (пуст на последней)
+}
+
+// PublicProfileDTO представляет публичный профиль игрока
+type PublicProfileDTO struct {
+	UserID         uint                      `json:"user_id"`                  // ID пользователя
+	Username       string                    `json:"username"`                 // Имя пользователя
+	ProfilePicture string                    `json:"profile_picture"`          // Аватар пользователя
+	Private        bool                      `json:"private"`                  // Владелец скрыл профиль: статистика не отдаётся
+	Stats          *PublicProfileStatsDTO    `json:"stats,omitempty"`          // Статистика (нет у скрытого профиля)
+	RecentResults  []*PublicProfileResultDTO `json:"recent_results,omitempty"` // Последние игры (нет, если владелец их скрыл)
+}
+
+// PublicProfileStatsDTO представляет статистику игрока в публичном профиле
+type PublicProfileStatsDTO struct {
+	GamesPlayed   int64              `json:"games_played"`      // Сыграно викторин
+	WinsCount     int64              `json:"wins_count"`        // Количество побед
+	TotalPrizeWon int64              `json:"total_prize_won"`   // Общая сумма выигранных призов
+	HighestScore  int64              `json:"highest_score"`     // Лучший счёт за игру
+	BestWinStreak int                `json:"best_win_streak"`   // Самая длинная серия побед подряд
+	Streaks       *entity.UserStreak `json:"streaks,omitempty"` // Серии викторин, верных ответов и игр без выбывания
+	Rating        *entity.UserRating `json:"rating,omitempty"`  // Рейтинг мастерства
+}
+
+// PublicProfileResultDTO представляет одну игру в публичном профиле
+type PublicProfileResultDTO struct {
+	QuizID         uint      `json:"quiz_id"`         // ID викторины
+	Score          int       `json:"score"`           // Набранные очки
+	CorrectAnswers int       `json:"correct_answers"` // Верные ответы
+	TotalQuestions int       `json:"total_questions"` // Всего вопросов
+	Rank           int       `json:"rank"`            // Место в викторине
+	IsWinner       bool      `json:"is_winner"`       // Победа
+	PrizeFund      int       `json:"prize_fund"`      // Выигранный приз
+	CompletedAt    time.Time `json:"completed_at"`    // Время завершения
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/handler/response"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+// maxEmailCallbackBody ограничивает тело обратного вызова провайдера
+const maxEmailCallbackBody = 256 * 1024
+
+// EmailHandler обрабатывает обратные вызовы провайдеров о доставке и админ-запросы по журналу писем
+type EmailHandler struct {
+	dispatcher   *service.EmailDispatcher
+	auditService *service.AuditService
+}
+
+// NewEmailHandler создает новый обработчик писем
+func NewEmailHandler(dispatcher *service.EmailDispatcher) *EmailHandler {
+	return &EmailHandler{dispatcher: dispatcher}
+}
+
+// SetAuditService подключает журнал аудита
+func (h *EmailHandler) SetAuditService(auditService *service.AuditService) {
+	h.auditService = auditService
+}
+
+// HandleCallback принимает события доставки от провайдера
+// POST /api/webhooks/email/:provider
+func (h *EmailHandler) HandleCallback(c *gin.Context) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxEmailCallbackBody))
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			response.Error(c, http.StatusRequestEntityTooLarge, "payload_too_large", "callback body is too large")
+			return
+		}
+		response.Error(c, http.StatusBadRequest, "invalid_request", "failed to read callback body")
+		return
+	}
+
+	recorded, err := h.dispatcher.HandleCallback(c.Request.Context(), c.Param("provider"), service.EmailCallbackRequest{
+		Header: c.Request.Header,
+		Query:  c.Request.URL.Query(),
+		Body:   body,
+	})
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{"recorded": recorded}, nil)
+}
+
+// ListMessages возвращает журнал отправленных писем
+// GET /api/admin/email/messages?recipient=&status=&page=1&page_size=20
+func (h *EmailHandler) ListMessages(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	messages, total, err := h.dispatcher.ListMessages(c.Query("recipient"), c.Query("status"), page, pageSize)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{
+		"messages":  messages,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	}, nil)
+}
+
+// ListMessageEvents возвращает события доставки письма
+// GET /api/admin/email/messages/:id/events
+func (h *EmailHandler) ListMessageEvents(c *gin.Context) {
+	messageID := c.MustGet("emailMessageID").(uint)
+	events, err := h.dispatcher.ListMessageEvents(messageID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{"events": events}, nil)
+}
+
+// ListDeadLetters возвращает недоставленные письма
+// GET /api/admin/email/dead-letters?pending=true&page=1&page_size=20
+func (h *EmailHandler) ListDeadLetters(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	pendingOnly := c.Query("pending") == "true"
+
+	letters, total, err := h.dispatcher.ListDeadLetters(pendingOnly, page, pageSize)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{
+		"dead_letters": letters,
+		"total":        total,
+		"page":         page,
+		"page_size":    pageSize,
+	}, nil)
+}
+
+// RetryDeadLetter переотправляет недоставленное письмо
+// POST /api/admin/email/dead-letters/:id/retry
+func (h *EmailHandler) RetryDeadLetter(c *gin.Context) {
+	letterID := c.MustGet("deadLetterID").(uint)
+	letter, err := h.dispatcher.RetryDeadLetter(c.Request.Context(), letterID)
+	if letter != nil {
+		recordAudit(c, h.auditService, service.AuditEntry{
+			Action:     entity.AuditActionEmailDeadLetterRetry,
+			TargetType: entity.AuditTargetEmail,
+			TargetID:   strconv.FormatUint(uint64(letterID), 10),
+			Metadata:   map[string]interface{}{"resent": err == nil, "provider": letter.Provider},
+		})
+	}
+	if err != nil {
+		if letter != nil {
+			response.Error(c, http.StatusBadGateway, "email_send_failed", letter.LastError)
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{"dead_letter": letter}, nil)
+}
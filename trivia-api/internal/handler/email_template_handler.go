@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/handler/response"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+// EmailTemplateHandler обрабатывает админ-запросы по шаблонам писем
+type EmailTemplateHandler struct {
+	templateService *service.EmailTemplateService
+	auditService    *service.AuditService
+}
+
+// NewEmailTemplateHandler создает новый обработчик шаблонов писем
+func NewEmailTemplateHandler(templateService *service.EmailTemplateService) *EmailTemplateHandler {
+	return &EmailTemplateHandler{templateService: templateService}
+}
+
+// SetAuditService подключает журнал аудита
+func (h *EmailTemplateHandler) SetAuditService(auditService *service.AuditService) {
+	h.auditService = auditService
+}
+
+// SaveEmailTemplateRequest — новая версия шаблона
+type SaveEmailTemplateRequest struct {
+	Subject string `json:"subject" binding:"required"`
+	Text    string `json:"text" binding:"required"`
+	HTML    string `json:"html"`
+}
+
+// PreviewEmailTemplateRequest — предпросмотр: черновик, сохранённая версия или текущий шаблон
+type PreviewEmailTemplateRequest struct {
+	Locale  string            `json:"locale" binding:"required"`
+	Version int               `json:"version"`
+	Subject string            `json:"subject"`
+	Text    string            `json:"text"`
+	HTML    string            `json:"html"`
+	Data    map[string]string `json:"data"`
+}
+
+// ListTemplates возвращает шаблоны по языкам и их активные версии
+// GET /api/admin/email/templates
+func (h *EmailTemplateHandler) ListTemplates(c *gin.Context) {
+	templates, err := h.templateService.ListTemplates()
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{"templates": templates}, nil)
+}
+
+// ListVersions возвращает сохранённые версии шаблона
+// GET /api/admin/email/templates/:key/:locale/versions
+func (h *EmailTemplateHandler) ListVersions(c *gin.Context) {
+	versions, err := h.templateService.ListVersions(c.Param("key"), c.Param("locale"))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{"versions": versions}, nil)
+}
+
+// SaveVersion сохраняет новую активную версию шаблона
+// POST /api/admin/email/templates/:key/:locale
+func (h *EmailTemplateHandler) SaveVersion(c *gin.Context) {
+	var req SaveEmailTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid_request", "subject and text are required")
+		return
+	}
+	adminID := c.MustGet("user_id").(uint)
+
+	tpl, err := h.templateService.SaveVersion(c.Param("key"), c.Param("locale"), service.EmailTemplateInput{
+		Subject: req.Subject,
+		Text:    req.Text,
+		HTML:    req.HTML,
+	}, adminID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionEmailTemplateSave,
+		TargetType: entity.AuditTargetEmail,
+		TargetID:   tpl.Key + "/" + tpl.Locale,
+		Metadata:   map[string]interface{}{"version": tpl.Version},
+	})
+	response.Success(c, http.StatusCreated, gin.H{"template": tpl}, nil)
+}
+
+// ActivateVersion возвращает шаблону сохранённую ранее версию
+// POST /api/admin/email/templates/:key/:locale/versions/:version/activate
+func (h *EmailTemplateHandler) ActivateVersion(c *gin.Context) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version <= 0 {
+		response.Error(c, http.StatusBadRequest, "invalid_request", "invalid version")
+		return
+	}
+	key, locale := c.Param("key"), c.Param("locale")
+	if err := h.templateService.ActivateVersion(key, locale, version); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionEmailTemplateActivate,
+		TargetType: entity.AuditTargetEmail,
+		TargetID:   key + "/" + locale,
+		Metadata:   map[string]interface{}{"version": version},
+	})
+	response.Success(c, http.StatusOK, gin.H{"message": "Template version activated"}, nil)
+}
+
+// ResetToFile снимает активную версию: шаблон снова берётся из файлов
+// DELETE /api/admin/email/templates/:key/:locale
+func (h *EmailTemplateHandler) ResetToFile(c *gin.Context) {
+	key, locale := c.Param("key"), c.Param("locale")
+	if err := h.templateService.ResetToFile(key, locale); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionEmailTemplateReset,
+		TargetType: entity.AuditTargetEmail,
+		TargetID:   key + "/" + locale,
+	})
+	response.Success(c, http.StatusOK, gin.H{"message": "Template reset to default"}, nil)
+}
+
+// Preview собирает письмо на примерах значений, не отправляя его
+// POST /api/admin/email/templates/:key/preview
+func (h *EmailTemplateHandler) Preview(c *gin.Context) {
+	var req PreviewEmailTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid_request", "locale is required")
+		return
+	}
+	var draft *service.EmailTemplateInput
+	if req.Subject != "" || req.Text != "" || req.HTML != "" {
+		draft = &service.EmailTemplateInput{Subject: req.Subject, Text: req.Text, HTML: req.HTML}
+	}
+
+	preview, err := h.templateService.Preview(c.Param("key"), req.Locale, req.Version, draft, req.Data)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{"preview": preview}, nil)
+}
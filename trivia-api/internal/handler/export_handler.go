@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/handler/response"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+// ExportHandler обрабатывает фоновые выгрузки результатов и ответов викторин
+type ExportHandler struct {
+	exportService *service.ExportService
+}
+
+// NewExportHandler создает обработчик выгрузок
+func NewExportHandler(exportService *service.ExportService) *ExportHandler {
+	return &ExportHandler{exportService: exportService}
+}
+
+// CreateExportRequest — параметры новой выгрузки
+type CreateExportRequest struct {
+	Kind   string `json:"kind"`   // results или answers
+	Format string `json:"format"` // csv, xlsx или json
+}
+
+// CreateExport ставит выгрузку викторины в очередь
+// POST /api/quizzes/:id/exports
+func (h *ExportHandler) CreateExport(c *gin.Context) {
+	var req CreateExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid_request", "invalid request body")
+		return
+	}
+	if req.Kind == "" {
+		req.Kind = entity.ExportKindResults
+	}
+	if req.Format == "" {
+		req.Format = entity.ExportFormatCSV
+	}
+
+	job, err := h.exportService.CreateJob(c.Request.Context(), c.MustGet("quizID").(uint), c.MustGet("user_id").(uint), req.Kind, req.Format)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusAccepted, job, nil)
+}
+
+// ListExports возвращает последние выгрузки викторины
+// GET /api/quizzes/:id/exports
+func (h *ExportHandler) ListExports(c *gin.Context) {
+	jobs, err := h.exportService.ListJobs(c.Request.Context(), c.MustGet("quizID").(uint))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, jobs, nil)
+}
+
+// GetExport возвращает прогресс выгрузки и ссылку на скачивание готового файла
+// GET /api/quizzes/:id/exports/:jobId
+func (h *ExportHandler) GetExport(c *gin.Context) {
+	jobID, err := strconv.ParseUint(c.Param("jobId"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid_request", "некорректный ID выгрузки")
+		return
+	}
+	job, err := h.exportService.GetJob(c.Request.Context(), c.MustGet("quizID").(uint), uint(jobID))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, job, nil)
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/handler/response"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+// FeatureFlagHandler управляет флагами функций (админ-панель) и отдаёт клиенту его флаги
+type FeatureFlagHandler struct {
+	featureFlagService *service.FeatureFlagService
+	auditService       *service.AuditService
+}
+
+// NewFeatureFlagHandler создает обработчик флагов функций
+func NewFeatureFlagHandler(featureFlagService *service.FeatureFlagService) *FeatureFlagHandler {
+	return &FeatureFlagHandler{featureFlagService: featureFlagService}
+}
+
+// SetAuditService подключает журнал аудита
+func (h *FeatureFlagHandler) SetAuditService(auditService *service.AuditService) {
+	h.auditService = auditService
+}
+
+// FeatureFlagRequest — настройки флага
+type FeatureFlagRequest struct {
+	Description string `json:"description" binding:"max=500"`
+	Enabled     bool   `json:"enabled"`
+	AdminOnly   bool   `json:"admin_only"`
+	Percentage  int    `json:"percentage" binding:"min=0,max=100"`
+	UserIDs     []uint `json:"user_ids"`
+}
+
+// CreateFeatureFlagRequest — тело запроса создания флага
+type CreateFeatureFlagRequest struct {
+	Key string `json:"key" binding:"required"`
+	FeatureFlagRequest
+}
+
+func (r FeatureFlagRequest) input() service.FeatureFlagInput {
+	return service.FeatureFlagInput{
+		Description: r.Description,
+		Enabled:     r.Enabled,
+		AdminOnly:   r.AdminOnly,
+		Percentage:  r.Percentage,
+		UserIDs:     r.UserIDs,
+	}
+}
+
+// ListFeatureFlags возвращает все флаги
+// GET /api/admin/feature-flags
+func (h *FeatureFlagHandler) ListFeatureFlags(c *gin.Context) {
+	flags, err := h.featureFlagService.List()
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, flags, nil)
+}
+
+// CreateFeatureFlag создаёт флаг
+// POST /api/admin/feature-flags
+func (h *FeatureFlagHandler) CreateFeatureFlag(c *gin.Context) {
+	var req CreateFeatureFlagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	flag, err := h.featureFlagService.Create(req.Key, req.input())
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionFeatureFlagCreate,
+		TargetType: entity.AuditTargetFeatureFlag,
+		TargetID:   flag.Key,
+		After:      flag,
+	})
+	response.Success(c, http.StatusCreated, flag, nil)
+}
+
+// UpdateFeatureFlag заменяет настройки флага
+// PUT /api/admin/feature-flags/:key
+func (h *FeatureFlagHandler) UpdateFeatureFlag(c *gin.Context) {
+	var req FeatureFlagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	key := c.Param("key")
+	before, err := h.featureFlagService.Get(key)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	flag, err := h.featureFlagService.Update(key, req.input())
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionFeatureFlagUpdate,
+		TargetType: entity.AuditTargetFeatureFlag,
+		TargetID:   key,
+		Before:     before,
+		After:      flag,
+	})
+	response.Success(c, http.StatusOK, flag, nil)
+}
+
+// DeleteFeatureFlag удаляет флаг; дальше действует значение из конфигурации
+// DELETE /api/admin/feature-flags/:key
+func (h *FeatureFlagHandler) DeleteFeatureFlag(c *gin.Context) {
+	key := c.Param("key")
+	before, err := h.featureFlagService.Get(key)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	if err := h.featureFlagService.Delete(key); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionFeatureFlagDelete,
+		TargetType: entity.AuditTargetFeatureFlag,
+		TargetID:   key,
+		Before:     before,
+	})
+	response.Success(c, http.StatusOK, gin.H{"message": "Feature flag deleted"}, nil)
+}
+
+// GetMyFeatures возвращает значения всех флагов для текущего пользователя
+// GET /api/users/me/features
+func (h *FeatureFlagHandler) GetMyFeatures(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	id, _ := userID.(uint)
+	response.Success(c, http.StatusOK, h.featureFlagService.Evaluate(id, c.GetBool("is_admin")), nil)
+}
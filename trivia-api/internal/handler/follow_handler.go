@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/trivia-api/internal/handler/response"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+// FollowHandler обрабатывает подписки между игроками и лидерборд друзей
+type FollowHandler struct {
+	followService *service.FollowService
+}
+
+// NewFollowHandler создает обработчик подписок
+func NewFollowHandler(followService *service.FollowService) *FollowHandler {
+	return &FollowHandler{followService: followService}
+}
+
+// GetFollowStatus возвращает подписки между текущим пользователем и игроком
+// GET /api/users/:id/follow
+func (h *FollowHandler) GetFollowStatus(c *gin.Context) {
+	userID, ok := followTargetID(c)
+	if !ok {
+		return
+	}
+	status, err := h.followService.GetStatus(c.MustGet("user_id").(uint), userID)
+	if err != nil {
+		h.handleFollowError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, status, nil)
+}
+
+// Follow подписывает текущего пользователя на игрока
+// POST /api/users/:id/follow
+func (h *FollowHandler) Follow(c *gin.Context) {
+	userID, ok := followTargetID(c)
+	if !ok {
+		return
+	}
+	status, err := h.followService.Follow(c.MustGet("user_id").(uint), userID)
+	if err != nil {
+		h.handleFollowError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, status, nil)
+}
+
+// Unfollow отменяет подписку текущего пользователя на игрока
+// DELETE /api/users/:id/follow
+func (h *FollowHandler) Unfollow(c *gin.Context) {
+	userID, ok := followTargetID(c)
+	if !ok {
+		return
+	}
+	status, err := h.followService.Unfollow(c.MustGet("user_id").(uint), userID)
+	if err != nil {
+		h.handleFollowError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, status, nil)
+}
+
+// ListFollowing возвращает подписки текущего пользователя
+// GET /api/users/me/following?page=1&page_size=20
+func (h *FollowHandler) ListFollowing(c *gin.Context) {
+	page, pageSize := followPagination(c)
+	list, err := h.followService.ListFollowing(c.MustGet("user_id").(uint), page, pageSize)
+	if err != nil {
+		h.handleFollowError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, list, nil)
+}
+
+// ListFollowers возвращает подписчиков текущего пользователя
+// GET /api/users/me/followers?page=1&page_size=20
+func (h *FollowHandler) ListFollowers(c *gin.Context) {
+	page, pageSize := followPagination(c)
+	list, err := h.followService.ListFollowers(c.MustGet("user_id").(uint), page, pageSize)
+	if err != nil {
+		h.handleFollowError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, list, nil)
+}
+
+// GetFriendsLeaderboard возвращает лидерборд из текущего пользователя и его подписок
+// GET /api/users/me/friends/leaderboard?page=1&page_size=20
+func (h *FollowHandler) GetFriendsLeaderboard(c *gin.Context) {
+	page, pageSize := followPagination(c)
+	leaderboard, err := h.followService.GetFriendsLeaderboard(c.MustGet("user_id").(uint), page, pageSize)
+	if err != nil {
+		h.handleFollowError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, leaderboard, nil)
+}
+
+func (h *FollowHandler) handleFollowError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrFollowRateLimited) {
+		response.Error(c, http.StatusTooManyRequests, "rate_limited", nil)
+		return
+	}
+	response.FromError(c, err)
+}
+
+// followTargetID разбирает ID игрока из пути; при ошибке отвечает 400
+func followTargetID(c *gin.Context) (uint, bool) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid_request", "некорректный ID пользователя")
+		return 0, false
+	}
+	return uint(userID), true
+}
+
+func followPagination(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	return page, pageSize
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/trivia-api/internal/handler/response"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+// GeoHandler обрабатывает отчёты о региональных ограничениях призов (админ-панель)
+type GeoHandler struct {
+	geoService *service.GeoService
+}
+
+// NewGeoHandler создает обработчик отчётов геолокации
+func NewGeoHandler(geoService *service.GeoService) *GeoHandler {
+	return &GeoHandler{geoService: geoService}
+}
+
+// ListBlocked возвращает подключения к викторинам из стран вне списка разрешённых
+// GET /api/admin/geo/blocked?quiz_id=1&page=1&page_size=20
+func (h *GeoHandler) ListBlocked(c *gin.Context) {
+	quizID, ok := parseOptionalQuizID(c)
+	if !ok {
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	attempts, total, err := h.geoService.ListBlocked(quizID, page, pageSize)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{
+		"attempts":  attempts,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	}, nil)
+}
+
+// GetQuizReport возвращает число игроков викторины по странам подключения
+// GET /api/admin/geo/quizzes/:id
+func (h *GeoHandler) GetQuizReport(c *gin.Context) {
+	report, err := h.geoService.QuizReport(c.MustGet("quizID").(uint))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, report, nil)
+}
+
+// parseOptionalQuizID читает необязательный фильтр ?quiz_id=; при ошибке ответ уже отправлен
+func parseOptionalQuizID(c *gin.Context) (uint, bool) {
+	raw := c.Query("quiz_id")
+	if raw == "" {
+		return 0, true
+	}
+	id, err := strconv.ParseUint(raw, 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid_request", "quiz_id must be a positive integer")
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/handler/response"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+// IPRiskHandler обрабатывает рискованные подключения и проверки победителей (админ-панель)
+type IPRiskHandler struct {
+	ipRiskService *service.IPRiskService
+	auditService  *service.AuditService
+}
+
+// NewIPRiskHandler создает обработчик проверок победителей
+func NewIPRiskHandler(ipRiskService *service.IPRiskService) *IPRiskHandler {
+	return &IPRiskHandler{ipRiskService: ipRiskService}
+}
+
+// SetAuditService подключает журнал аудита
+func (h *IPRiskHandler) SetAuditService(auditService *service.AuditService) {
+	h.auditService = auditService
+}
+
+// ResolvePrizeReviewRequest — комментарий администратора к решению
+type ResolvePrizeReviewRequest struct {
+	Note string `json:"note" binding:"max=500"`
+}
+
+// ListSessions возвращает подключения к викторинам с рискованных адресов (новые первыми)
+// GET /api/admin/abuse/ip-risk/sessions?quiz_id=1&min_score=75&page=1&page_size=20
+func (h *IPRiskHandler) ListSessions(c *gin.Context) {
+	quizID, ok := parseOptionalQuizID(c)
+	if !ok {
+		return
+	}
+	minScore, _ := strconv.Atoi(c.Query("min_score"))
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	sessions, total, err := h.ipRiskService.ListSessions(quizID, minScore, page, pageSize)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{
+		"sessions":  sessions,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	}, nil)
+}
+
+// ListReviews возвращает проверки победителей, старые первыми
+// GET /api/admin/abuse/prize-reviews?status=pending&quiz_id=1&page=1&page_size=20
+func (h *IPRiskHandler) ListReviews(c *gin.Context) {
+	quizID, ok := parseOptionalQuizID(c)
+	if !ok {
+		return
+	}
+	status := c.DefaultQuery("status", entity.PrizeRiskReviewPending)
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	reviews, total, err := h.ipRiskService.ListReviews(status, quizID, page, pageSize)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{
+		"reviews":   reviews,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	}, nil)
+}
+
+// ApproveReview признаёт победу честной; задержанный приз выплачивается
+// POST /api/admin/abuse/prize-reviews/:id/approve
+func (h *IPRiskHandler) ApproveReview(c *gin.Context) {
+	h.resolve(c, entity.AuditActionPrizeReviewApprove, h.ipRiskService.ApproveReview)
+}
+
+// RejectReview отклоняет победу; задержанный приз снимается
+// POST /api/admin/abuse/prize-reviews/:id/reject
+func (h *IPRiskHandler) RejectReview(c *gin.Context) {
+	h.resolve(c, entity.AuditActionPrizeReviewReject, h.ipRiskService.RejectReview)
+}
+
+func (h *IPRiskHandler) resolve(c *gin.Context, action string, decide func(reviewID, adminID uint, note string) (*entity.PrizeRiskReview, error)) {
+	var req ResolvePrizeReviewRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.InvalidRequest(c, err)
+			return
+		}
+	}
+	adminID := c.MustGet("user_id").(uint)
+	review, err := decide(c.MustGet("reviewID").(uint), adminID, req.Note)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     action,
+		TargetType: entity.AuditTargetPrizeReview,
+		TargetID:   strconv.FormatUint(uint64(review.ID), 10),
+		Metadata: map[string]interface{}{
+			"quiz_id": review.QuizID,
+			"user_id": review.UserID,
+			"score":   review.Score,
+			"amount":  review.Amount,
+			"action":  review.Action,
+		},
+	})
+	response.Success(c, http.StatusOK, review, nil)
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yourusername/trivia-api/internal/handler/response"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+// LegalHandler обрабатывает повторное принятие пользовательского соглашения и политики
+// конфиденциальности после обновления их версий
+type LegalHandler struct {
+	legalService *service.LegalService
+}
+
+// NewLegalHandler создает обработчик согласий с документами
+func NewLegalHandler(legalService *service.LegalService) *LegalHandler {
+	return &LegalHandler{legalService: legalService}
+}
+
+// AcceptLegalRequest — тело запроса принятия текущих версий документов
+type AcceptLegalRequest struct {
+	TOSVersion     string `json:"tos_version" binding:"required,max=32"`
+	PrivacyVersion string `json:"privacy_version" binding:"required,max=32"`
+	// Необязательно; без поля сохраняется прежнее согласие на рассылку
+	MarketingOptIn *bool `json:"marketing_opt_in"`
+}
+
+// AcceptLegal записывает принятие текущих версий документов. Если клиент показал
+// устаревшие версии, возвращает 409 — нужно перечитать версии и показать документы заново.
+// POST /api/auth/accept-legal, POST /api/mobile/auth/accept-legal
+func (h *LegalHandler) AcceptLegal(c *gin.Context) {
+	var req AcceptLegalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	acceptance, err := h.legalService.Accept(service.LegalAcceptInput{
+		UserID:         c.MustGet("user_id").(uint),
+		TOSVersion:     req.TOSVersion,
+		PrivacyVersion: req.PrivacyVersion,
+		MarketingOptIn: req.MarketingOptIn,
+		IP:             c.ClientIP(),
+		UserAgent:      c.Request.UserAgent(),
+	})
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{
+		"tos_version":      acceptance.TOSVersion,
+		"privacy_version":  acceptance.PrivacyVersion,
+		"marketing_opt_in": acceptance.MarketingOptIn,
+		"accepted_at":      acceptance.AcceptedAt,
+	}, nil)
+}
+
+// GetLegalStatus сообщает текущие версии документов и нужно ли пользователю их принять
+// GET /api/auth/legal, GET /api/mobile/auth/legal
+func (h *LegalHandler) GetLegalStatus(c *gin.Context) {
+	required, err := h.legalService.AcceptanceRequired(c.MustGet("user_id").(uint))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	versions := h.legalService.CurrentVersions()
+	response.Success(c, http.StatusOK, gin.H{
+		"tos_version":         versions.TOSVersion,
+		"privacy_version":     versions.PrivacyVersion,
+		"acceptance_required": required,
+	}, nil)
+}
+
+// GetCoverage возвращает охват текущих версий документов среди пользователей
+// GET /api/admin/legal/coverage
+func (h *LegalHandler) GetCoverage(c *gin.Context) {
+	report, err := h.legalService.Coverage()
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, report, nil)
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/handler/response"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+// MaintenanceHandler управляет режимом обслуживания «только чтение» (админ-панель)
+type MaintenanceHandler struct {
+	maintenanceService *service.MaintenanceService
+	auditService       *service.AuditService
+}
+
+// NewMaintenanceHandler создает обработчик режима обслуживания
+func NewMaintenanceHandler(maintenanceService *service.MaintenanceService) *MaintenanceHandler {
+	return &MaintenanceHandler{maintenanceService: maintenanceService}
+}
+
+// SetAuditService подключает журнал аудита
+func (h *MaintenanceHandler) SetAuditService(auditService *service.AuditService) {
+	h.auditService = auditService
+}
+
+// SetMaintenanceRequest — тело запроса переключения режима обслуживания
+type SetMaintenanceRequest struct {
+	Enabled       *bool  `json:"enabled" binding:"required"`
+	Message       string `json:"message" binding:"max=500"`
+	RetryAfterSec int    `json:"retry_after" binding:"min=0,max=86400"`
+}
+
+// GetMaintenance возвращает состояние режима обслуживания
+// GET /api/admin/maintenance
+func (h *MaintenanceHandler) GetMaintenance(c *gin.Context) {
+	response.Success(c, http.StatusOK, h.maintenanceService.State(), nil)
+}
+
+// SetMaintenance включает или выключает режим обслуживания на всех инстансах
+// PUT /api/admin/maintenance
+func (h *MaintenanceHandler) SetMaintenance(c *gin.Context) {
+	var req SetMaintenanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	before := h.maintenanceService.State()
+	state, err := h.maintenanceService.Set(*req.Enabled, req.Message, req.RetryAfterSec)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionMaintenanceToggle,
+		TargetType: entity.AuditTargetSystem,
+		TargetID:   "maintenance",
+		Before:     before,
+		After:      state,
+	})
+	response.Success(c, http.StatusOK, state, nil)
+}
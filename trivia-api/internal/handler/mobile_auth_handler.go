@@ -1,14 +1,17 @@
 package handler
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/handler/response"
 	"github.com/yourusername/trivia-api/internal/service"
 	"github.com/yourusername/trivia-api/internal/websocket"
 	"github.com/yourusername/trivia-api/pkg/auth/manager"
@@ -23,6 +26,7 @@ type MobileAuthHandler struct {
 	authService  *service.AuthService
 	tokenManager *manager.TokenManager
 	wsHub        websocket.HubInterface
+	auditService *service.AuditService
 }
 
 // NewMobileAuthHandler создает новый обработчик мобильной аутентификации
@@ -34,6 +38,11 @@ func NewMobileAuthHandler(authService *service.AuthService, tokenManager *manage
 	}
 }
 
+// SetAuditService подключает журнал аудита
+func (h *MobileAuthHandler) SetAuditService(auditService *service.AuditService) {
+	h.auditService = auditService
+}
+
 // --- Mobile-specific request/response DTOs ---
 // Отдельные DTO, чтобы не менять JSON-теги manager.TokenResponse (web)
 
@@ -72,22 +81,29 @@ type MobileLoginRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required"`
 	DeviceID string `json:"device_id" binding:"required"`
+	// DeviceKey — необязательный открытый ключ устройства (JWK EC P-256); с ним refresh-токен
+	// обновляется только с доказательством, подписанным закрытым ключом (заголовок DPoP)
+	DeviceKey json.RawMessage `json:"device_key"`
 }
 
 // MobileRegisterRequest — запрос на регистрацию от mobile
 type MobileRegisterRequest struct {
-	Username  string `json:"username" binding:"required,min=3,max=50"`
+	Username  string `json:"username" binding:"required,min=3,max=50,username_charset"`
 	Email     string `json:"email" binding:"required,email"`
-	Password  string `json:"password" binding:"required,min=6,max=50"`
+	Password  string `json:"password" binding:"required,password_strength"`
 	DeviceID  string `json:"device_id" binding:"required"`
 	FirstName string `json:"first_name" binding:"required,min=1,max=100"`
 	LastName  string `json:"last_name" binding:"required,min=1,max=100"`
-	BirthDate string `json:"birth_date" binding:"required"` // format: "2006-01-02"
+	BirthDate string `json:"birth_date" binding:"required,birth_date=18"` // format: "2006-01-02"
 	Gender    string `json:"gender" binding:"required,oneof=male female other prefer_not_to_say"`
 
 	TOSAccepted     bool `json:"tos_accepted" binding:"required"`
 	PrivacyAccepted bool `json:"privacy_accepted" binding:"required"`
 	MarketingOptIn  bool `json:"marketing_opt_in"`
+
+	ReferralCode string `json:"referral_code" binding:"omitempty,max=16"`
+
+	DeviceKey json.RawMessage `json:"device_key"` // см. MobileLoginRequest.DeviceKey
 }
 
 // --- Handlers ---
@@ -97,7 +113,12 @@ type MobileRegisterRequest struct {
 func (h *MobileAuthHandler) MobileLogin(c *gin.Context) {
 	var req MobileLoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		response.InvalidRequest(c, err)
+		return
+	}
+	deviceKey, err := parseDeviceKey(req.DeviceKey)
+	if err != nil {
+		response.FromError(c, err)
 		return
 	}
 
@@ -105,8 +126,9 @@ func (h *MobileAuthHandler) MobileLogin(c *gin.Context) {
 	userAgent := c.Request.UserAgent()
 
 	// Используем тот же AuthService.LoginUser — общая бизнес-логика
-	tokenResp, err := h.authService.LoginUser(req.Email, req.Password, req.DeviceID, ipAddress, userAgent)
+	tokenResp, err := h.authService.LoginUser(manager.WithClient(c.Request.Context(), manager.ClientMobile), req.Email, req.Password, req.DeviceID, ipAddress, userAgent)
 	if err != nil {
+		recordLoginAudit(c, h.auditService, req.Email, req.DeviceID, "mobile", 0, err)
 		h.handleAuthError(c, err)
 		return
 	}
@@ -116,6 +138,10 @@ func (h *MobileAuthHandler) MobileLogin(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate authentication tokens"})
 		return
 	}
+	if !h.bindDeviceKey(c, deviceKey, tokenResp.RefreshToken) {
+		return
+	}
+	recordLoginAudit(c, h.auditService, req.Email, req.DeviceID, "mobile", tokenResp.UserID, nil)
 
 	// Получаем информацию о пользователе
 	user, userErr := h.authService.GetUserByID(tokenResp.UserID)
@@ -124,7 +150,7 @@ func (h *MobileAuthHandler) MobileLogin(c *gin.Context) {
 	}
 
 	// Возвращаем токены в JSON (БЕЗ cookies, БЕЗ CSRF)
-	c.JSON(http.StatusOK, MobileAuthResponse{
+	respondMobile(c, http.StatusOK, MobileAuthResponse{
 		User:         serializeUserForClient(user),
 		AccessToken:  tokenResp.AccessToken,
 		RefreshToken: tokenResp.RefreshToken,
@@ -139,7 +165,12 @@ func (h *MobileAuthHandler) MobileLogin(c *gin.Context) {
 func (h *MobileAuthHandler) MobileRegister(c *gin.Context) {
 	var req MobileRegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.InvalidRequest(c, err)
+		return
+	}
+	deviceKey, err := parseDeviceKey(req.DeviceKey)
+	if err != nil {
+		response.FromError(c, err)
 		return
 	}
 
@@ -163,6 +194,8 @@ func (h *MobileAuthHandler) MobileRegister(c *gin.Context) {
 		MarketingOptIn:  req.MarketingOptIn,
 		IP:              c.ClientIP(),
 		UserAgent:       c.Request.UserAgent(),
+		DeviceID:        req.DeviceID,
+		ReferralCode:    req.ReferralCode,
 	}
 
 	user, err := h.authService.RegisterUser(input)
@@ -174,7 +207,7 @@ func (h *MobileAuthHandler) MobileRegister(c *gin.Context) {
 	log.Printf("[MobileAuth] Пользователь ID=%d (%s) зарегистрирован через mobile", user.ID, user.Email)
 
 	// Генерируем токены
-	tokenResp, err := h.tokenManager.GenerateTokenPair(user.ID, req.DeviceID, c.ClientIP(), c.Request.UserAgent())
+	tokenResp, err := h.tokenManager.GenerateTokenPairForClient(manager.ClientMobile, user.ID, req.DeviceID, c.ClientIP(), c.Request.UserAgent())
 	if err != nil {
 		h.handleAuthError(c, fmt.Errorf("failed to generate tokens after registration: %w", err))
 		return
@@ -185,8 +218,11 @@ func (h *MobileAuthHandler) MobileRegister(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate authentication tokens"})
 		return
 	}
+	if !h.bindDeviceKey(c, deviceKey, tokenResp.RefreshToken) {
+		return
+	}
 
-	c.JSON(http.StatusCreated, MobileAuthResponse{
+	respondMobile(c, http.StatusCreated, MobileAuthResponse{
 		User:         serializeUserForClient(user),
 		AccessToken:  tokenResp.AccessToken,
 		RefreshToken: tokenResp.RefreshToken,
@@ -201,17 +237,16 @@ func (h *MobileAuthHandler) MobileRegister(c *gin.Context) {
 func (h *MobileAuthHandler) MobileRefresh(c *gin.Context) {
 	var req MobileRefreshRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		response.InvalidRequest(c, err)
 		return
 	}
 
 	ipAddress := c.ClientIP()
 	userAgent := c.Request.UserAgent()
 
-	// Вызываем TokenManager.RefreshTokens напрямую, без CSRF-валидации.
-	// TokenManager.RefreshTokens внутри НЕ проверяет CSRF — проверка была в web handler.
-	// Передаём пустой csrfTokenHeader — TokenManager его не использует.
-	tokenResp, err := h.tokenManager.RefreshTokens(req.RefreshToken, "", req.DeviceID, ipAddress, userAgent)
+	// Вызываем TokenManager напрямую, без CSRF-валидации (проверка CSRF есть только в web handler).
+	// Сессии, привязанные к ключу устройства, требуют доказательство из заголовка DPoP.
+	tokenResp, err := h.tokenManager.RefreshTokensWithProof(manager.ClientMobile, req.RefreshToken, deviceProofFromRequest(c), req.DeviceID, ipAddress, userAgent)
 	if err != nil {
 		h.handleAuthError(c, err)
 		return
@@ -223,7 +258,7 @@ func (h *MobileAuthHandler) MobileRefresh(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, MobileRefreshResponse{
+	respondMobile(c, http.StatusOK, MobileRefreshResponse{
 		AccessToken:  tokenResp.AccessToken,
 		RefreshToken: tokenResp.RefreshToken,
 		UserID:       tokenResp.UserID,
@@ -238,7 +273,7 @@ func (h *MobileAuthHandler) MobileRefresh(c *gin.Context) {
 func (h *MobileAuthHandler) MobileLogout(c *gin.Context) {
 	var req MobileLogoutRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		response.InvalidRequest(c, err)
 		return
 	}
 
@@ -299,11 +334,15 @@ func (h *MobileAuthHandler) MobileUpdateProfile(c *gin.Context) {
 
 	var req UpdateProfileRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.InvalidRequest(c, err)
 		return
 	}
 
 	if err := h.authService.UpdateUserProfile(userID.(uint), req.Username, req.ProfilePicture); err != nil {
+		if errors.Is(err, service.ErrInappropriateContent) {
+			writeAuthError(c, err)
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -334,6 +373,10 @@ func (h *MobileAuthHandler) MobileGetActiveSessions(c *gin.Context) {
 			UserAgent: session.UserAgent,
 			CreatedAt: session.CreatedAt,
 			ExpiresAt: session.ExpiresAt,
+
+			Client:              session.Client,
+			SessionStartedAt:    session.SessionStartedAt,
+			DeviceKeyThumbprint: session.DeviceKeyThumbprint,
 		})
 	}
 
@@ -353,7 +396,7 @@ func (h *MobileAuthHandler) MobileRevokeSession(c *gin.Context) {
 
 	var req RevokeSessionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "error_type": "invalid_request"})
+		response.InvalidRequest(c, err)
 		return
 	}
 
@@ -409,6 +452,12 @@ func (h *MobileAuthHandler) MobileLogoutAllDevices(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to logout from all devices", "error_type": "internal_error"})
 		return
 	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionLogoutAll,
+		TargetType: entity.AuditTargetUser,
+		TargetID:   strconv.FormatUint(uint64(userID.(uint)), 10),
+		Metadata:   map[string]interface{}{"client": "mobile"},
+	})
 
 	logoutEvent := map[string]interface{}{
 		"event":     "logout_all_devices",
@@ -433,55 +482,8 @@ func (h *MobileAuthHandler) sendWebSocketNotification(userID uint, event map[str
 // --- Error handling ---
 
 // handleAuthError обрабатывает ошибки аутентификации для mobile.
-// Использует тот же паттерн errors.As/errors.Is, что и web handler.
+// Коды и статусы общие с web handler (writeAuthError).
 func (h *MobileAuthHandler) handleAuthError(c *gin.Context, err error) {
-	var tokenErr *manager.TokenError
 	log.Printf("[MobileAuth] Auth Error: %v", err)
-
-	if errors.Is(err, service.ErrFeatureDisabled) {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Feature is disabled", "error_type": "feature_disabled"})
-	} else if errors.Is(err, service.ErrLinkRequired) {
-		c.JSON(http.StatusConflict, gin.H{"error": "Google account requires explicit linking", "error_type": "link_required"})
-	} else if errors.Is(err, service.ErrEmailNotVerified) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Email is not verified", "error_type": "email_not_verified"})
-	} else if errors.Is(err, service.ErrInvalidVerificationCode) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid verification code", "error_type": "invalid_verification_code"})
-	} else if errors.Is(err, service.ErrVerificationExpired) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Verification code expired", "error_type": "verification_expired"})
-	} else if errors.Is(err, service.ErrVerificationAttemptsExceeded) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Verification attempts exceeded", "error_type": "verification_attempts_exceeded"})
-	} else if errors.Is(err, service.ErrVerificationResendCooldown) {
-		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests", "error_type": "rate_limited"})
-	} else if errors.Is(err, service.ErrGoogleTokenVerificationFailed) {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Google token verification failed", "error_type": "token_invalid"})
-	} else if errors.As(err, &tokenErr) {
-		switch tokenErr.Type {
-		case manager.ExpiredRefreshToken, manager.ExpiredAccessToken:
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Session expired", "error_type": "token_expired"})
-		case manager.InvalidRefreshToken, manager.InvalidAccessToken:
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token", "error_type": "token_invalid"})
-		case manager.UserNotFound:
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials", "error_type": "invalid_credentials"})
-		case manager.TokenGenerationFailed:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Token generation failed", "error_type": "token_generation_failed"})
-		case manager.TooManySessions:
-			c.JSON(http.StatusConflict, gin.H{"error": "Too many active sessions", "error_type": "too_many_sessions"})
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Authentication error", "error_type": string(tokenErr.Type)})
-		}
-	} else if errors.Is(err, apperrors.ErrUnauthorized) {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials", "error_type": "unauthorized"})
-	} else if errors.Is(err, apperrors.ErrForbidden) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied", "error_type": "forbidden"})
-	} else if errors.Is(err, apperrors.ErrNotFound) {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Resource not found", "error_type": "not_found"})
-	} else if errors.Is(err, apperrors.ErrConflict) {
-		c.JSON(http.StatusConflict, gin.H{"error": "Data conflict", "error_type": "conflict"})
-	} else if errors.Is(err, apperrors.ErrValidation) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation error", "error_type": "validation_error"})
-	} else if errors.Is(err, apperrors.ErrExpiredToken) {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token expired", "error_type": "token_expired"})
-	} else {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error", "error_type": "internal_server_error"})
-	}
+	writeAuthError(c, err)
 }
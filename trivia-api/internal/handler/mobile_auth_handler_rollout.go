@@ -1,11 +1,14 @@
 package handler
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/yourusername/trivia-api/internal/handler/response"
 	"github.com/yourusername/trivia-api/internal/service"
+	"github.com/yourusername/trivia-api/pkg/auth/manager"
 )
 
 type MobileVerifyEmailConfirmRequest struct {
@@ -19,6 +22,8 @@ type MobileGoogleExchangeRequest struct {
 	CodeVerifier string `json:"code_verifier"`
 	Platform     string `json:"platform"`
 	DeviceID     string `json:"device_id" binding:"required"`
+
+	DeviceKey json.RawMessage `json:"device_key"` // см. MobileLoginRequest.DeviceKey
 }
 
 type MobileGoogleLinkRequest struct {
@@ -47,7 +52,7 @@ func (h *MobileAuthHandler) MobileConfirmEmailVerificationCode(c *gin.Context) {
 	userID := c.MustGet("user_id").(uint)
 	var req MobileVerifyEmailConfirmRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "error_type": "validation_error"})
+		response.ValidationError(c, err)
 		return
 	}
 
@@ -71,7 +76,12 @@ func (h *MobileAuthHandler) MobileGetEmailVerificationStatus(c *gin.Context) {
 func (h *MobileAuthHandler) MobileGoogleExchange(c *gin.Context) {
 	var req MobileGoogleExchangeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "error_type": "validation_error"})
+		response.ValidationError(c, err)
+		return
+	}
+	deviceKey, err := parseDeviceKey(req.DeviceKey)
+	if err != nil {
+		response.FromError(c, err)
 		return
 	}
 
@@ -85,7 +95,7 @@ func (h *MobileAuthHandler) MobileGoogleExchange(c *gin.Context) {
 		IPAddress:    c.ClientIP(),
 		UserAgent:    c.Request.UserAgent(),
 	}
-	result, err := h.authService.ExchangeGoogleAuth(c.Request.Context(), input)
+	result, err := h.authService.ExchangeGoogleAuth(manager.WithClient(c.Request.Context(), manager.ClientMobile), input)
 	if err != nil {
 		if errors.Is(err, service.ErrLinkRequired) {
 			c.JSON(http.StatusConflict, gin.H{
@@ -98,8 +108,11 @@ func (h *MobileAuthHandler) MobileGoogleExchange(c *gin.Context) {
 		h.handleAuthError(c, err)
 		return
 	}
+	if !h.bindDeviceKey(c, deviceKey, result.Token.RefreshToken) {
+		return
+	}
 
-	c.JSON(http.StatusOK, MobileAuthResponse{
+	respondMobile(c, http.StatusOK, MobileAuthResponse{
 		User:         serializeUserForClient(result.User),
 		AccessToken:  result.Token.AccessToken,
 		RefreshToken: result.Token.RefreshToken,
@@ -113,7 +126,7 @@ func (h *MobileAuthHandler) MobileGoogleLink(c *gin.Context) {
 	userID := c.MustGet("user_id").(uint)
 	var req MobileGoogleLinkRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "error_type": "validation_error"})
+		response.ValidationError(c, err)
 		return
 	}
 
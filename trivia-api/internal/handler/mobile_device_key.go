@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/handler/response"
+	"github.com/yourusername/trivia-api/internal/service"
+	"github.com/yourusername/trivia-api/pkg/auth/manager"
+)
+
+// MobileRotateDeviceKeyRequest — запрос на смену ключа устройства сессии. Если сессия уже
+// привязана к ключу, запрос подписывается текущим ключом (заголовок DPoP).
+type MobileRotateDeviceKeyRequest struct {
+	RefreshToken string          `json:"refresh_token" binding:"required"`
+	DeviceID     string          `json:"device_id" binding:"required"`
+	DeviceKey    json.RawMessage `json:"device_key" binding:"required"`
+}
+
+// parseDeviceKey разбирает необязательный ключ устройства из запроса входа; пустое поле — nil
+func parseDeviceKey(raw json.RawMessage) (*manager.DeviceKey, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	return manager.ParseDeviceKey(raw)
+}
+
+// deviceProofFromRequest возвращает доказательство владения ключом устройства из заголовка DPoP
+func deviceProofFromRequest(c *gin.Context) *manager.DeviceProof {
+	return &manager.DeviceProof{
+		Token:  c.GetHeader(manager.DeviceProofHeader),
+		Method: c.Request.Method,
+		Path:   c.Request.URL.Path,
+	}
+}
+
+// bindDeviceKey привязывает только что выданную сессию к ключу устройства. Если привязать
+// не удалось, сессия отзывается: клиент рассчитывает на привязку и не должен получить токены без неё.
+func (h *MobileAuthHandler) bindDeviceKey(c *gin.Context, key *manager.DeviceKey, refreshToken string) bool {
+	if key == nil {
+		return true
+	}
+	if err := h.tokenManager.BindDeviceKey(refreshToken, key); err != nil {
+		if revokeErr := h.tokenManager.RevokeRefreshToken(refreshToken); revokeErr != nil {
+			log.Printf("[MobileAuth] Не удалось отозвать сессию без привязки ключа устройства: %v", revokeErr)
+		}
+		h.handleAuthError(c, err)
+		return false
+	}
+	return true
+}
+
+// MobileRotateDeviceKey привязывает сессию к новому ключу устройства и выдаёт новую пару токенов.
+// Старый refresh-токен отзывается, как при обычном обновлении.
+func (h *MobileAuthHandler) MobileRotateDeviceKey(c *gin.Context) {
+	var req MobileRotateDeviceKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+	newKey, err := manager.ParseDeviceKey(req.DeviceKey)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	tokenResp, err := h.tokenManager.RotateDeviceKey(manager.ClientMobile, req.RefreshToken, deviceProofFromRequest(c), newKey, req.DeviceID, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		h.handleAuthError(c, err)
+		return
+	}
+
+	respondMobile(c, http.StatusOK, MobileRefreshResponse{
+		AccessToken:  tokenResp.AccessToken,
+		RefreshToken: tokenResp.RefreshToken,
+		UserID:       tokenResp.UserID,
+		ExpiresIn:    tokenResp.ExpiresIn,
+		TokenType:    "Bearer",
+	})
+}
+
+// MobileRevokeDeviceKey завершает все сессии пользователя, привязанные к ключу устройства
+// (устройство утеряно или ключ скомпрометирован). Отпечаток ключа — в списке сессий.
+func (h *MobileAuthHandler) MobileRevokeDeviceKey(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized", "error_type": "token_missing"})
+		return
+	}
+	thumbprint := c.Param("thumbprint")
+
+	revoked, err := h.tokenManager.RevokeDeviceKey(userID.(uint), thumbprint)
+	if err != nil {
+		h.handleAuthError(c, err)
+		return
+	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionDeviceKeyRevoke,
+		TargetType: entity.AuditTargetUser,
+		TargetID:   strconv.FormatUint(uint64(userID.(uint)), 10),
+		Metadata:   map[string]interface{}{"thumbprint": thumbprint, "revoked_sessions": revoked},
+	})
+
+	revokeEvent := map[string]interface{}{
+		"event":      "device_key_revoked",
+		"user_id":    userID,
+		"thumbprint": thumbprint,
+		"timestamp":  time.Now().Format(time.RFC3339),
+	}
+	if err := h.sendWebSocketNotification(userID.(uint), revokeEvent); err != nil {
+		log.Printf("[MobileAuth] Failed to send WebSocket device key revoke notification: %v", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":          "Device key revoked",
+		"revoked_sessions": revoked,
+	})
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/yourusername/trivia-api/internal/middleware"
+)
+
+// mobileVersioned — ответ мобильного API, форма которого зависит от версии API запроса.
+// Обработчики и сервисы общие для /api/mobile и /api/mobile/v2, различается только ответ.
+type mobileVersioned interface {
+	forAPIVersion(version string) interface{}
+}
+
+// respondMobile отправляет ответ в форме версии API запроса (middleware.APIVersion)
+func respondMobile(c *gin.Context, status int, payload mobileVersioned) {
+	c.JSON(status, payload.forAPIVersion(middleware.APIVersionFromContext(c)))
+}
+
+// mobileAuthResponseV2 — MobileAuthResponse в v2: поля в snake_case, как в запросах
+type mobileAuthResponseV2 struct {
+	User         interface{} `json:"user"`
+	AccessToken  string      `json:"access_token"`
+	RefreshToken string      `json:"refresh_token"`
+	UserID       uint        `json:"user_id"`
+	ExpiresIn    int         `json:"expires_in"`
+	TokenType    string      `json:"token_type"`
+}
+
+func (r MobileAuthResponse) forAPIVersion(version string) interface{} {
+	if version != middleware.APIVersionV2 {
+		return r
+	}
+	return mobileAuthResponseV2{
+		User:         r.User,
+		AccessToken:  r.AccessToken,
+		RefreshToken: r.RefreshToken,
+		UserID:       r.UserID,
+		ExpiresIn:    r.ExpiresIn,
+		TokenType:    r.TokenType,
+	}
+}
+
+// mobileRefreshResponseV2 — MobileRefreshResponse в v2
+type mobileRefreshResponseV2 struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	UserID       uint   `json:"user_id"`
+	ExpiresIn    int    `json:"expires_in"`
+	TokenType    string `json:"token_type"`
+}
+
+func (r MobileRefreshResponse) forAPIVersion(version string) interface{} {
+	if version != middleware.APIVersionV2 {
+		return r
+	}
+	return mobileRefreshResponseV2(r)
+}
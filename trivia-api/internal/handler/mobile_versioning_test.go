@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/middleware"
+)
+
+// recordedClient — запрос, учтённый ClientVersionRecorder
+type recordedClient struct {
+	apiVersion, platform, appVersion string
+}
+
+type fakeClientRecorder struct {
+	calls []recordedClient
+}
+
+func (f *fakeClientRecorder) RecordClientVersion(apiVersion, platform, appVersion string) {
+	f.calls = append(f.calls, recordedClient{apiVersion, platform, appVersion})
+}
+
+func TestRespondMobile_VersionedRoutes(t *testing.T) {
+	since := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2027, 4, 1, 0, 0, 0, 0, time.UTC)
+	recorder := &fakeClientRecorder{}
+
+	router := gin.New()
+	register := func(mobile *gin.RouterGroup) {
+		mobile.POST("/auth/refresh", func(c *gin.Context) {
+			respondMobile(c, http.StatusOK, MobileRefreshResponse{AccessToken: "a", RefreshToken: "r", UserID: 7, ExpiresIn: 900, TokenType: "Bearer"})
+		})
+	}
+	api := router.Group("/api")
+	register(api.Group("/mobile", middleware.APIVersion(middleware.APIVersionV1, &middleware.APIDeprecation{
+		Since: since, Sunset: sunset, Prefix: "/api/mobile/", SuccessorPrefix: "/api/mobile/v2/",
+	}, recorder)))
+	register(api.Group("/mobile/v2", middleware.APIVersion(middleware.APIVersionV2, nil, recorder)))
+
+	do := func(path, userAgent string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.Header.Set("User-Agent", userAgent)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		return w
+	}
+
+	w := do("/api/mobile/auth/refresh", "TriviaApp/2.3.1 (iOS 17.2; iPhone15,2) CFNetwork/1490 Darwin/23.2.0")
+	assert.Equal(t, "@1792108800", w.Header().Get("Deprecation"))
+	assert.Equal(t, "Thu, 01 Apr 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+	assert.Equal(t, `</api/mobile/v2/auth/refresh>; rel="successor-version"`, w.Header().Get("Link"))
+	v1 := parseJSONResponse(t, w)
+	assert.Equal(t, "a", v1["accessToken"])
+	assert.NotContains(t, v1, "access_token")
+
+	w = do("/api/mobile/v2/auth/refresh", "okhttp/4.12.0 TriviaApp/3.0.0-beta.1 (Android 14)")
+	assert.Empty(t, w.Header().Get("Deprecation"))
+	assert.Empty(t, w.Header().Get("Link"))
+	v2 := parseJSONResponse(t, w)
+	assert.Equal(t, "a", v2["access_token"])
+	assert.Equal(t, float64(7), v2["user_id"])
+	assert.NotContains(t, v2, "accessToken")
+
+	assert.Equal(t, []recordedClient{
+		{middleware.APIVersionV1, middleware.ClientPlatformIOS, "2.3.1"},
+		{middleware.APIVersionV2, middleware.ClientPlatformAndroid, "3.0.0-beta.1"},
+	}, recorder.calls)
+}
+
+func TestParseClientUserAgent(t *testing.T) {
+	assert.Equal(t, middleware.ClientInfo{App: "TriviaApp", Version: "2.3.1", Platform: middleware.ClientPlatformIOS},
+		middleware.ParseClientUserAgent("TriviaApp/2.3.1 (iOS 17.2; iPhone15,2)"))
+	assert.Equal(t, middleware.ClientInfo{App: "Dart", Version: "3.4", Platform: middleware.ClientUnknown},
+		middleware.ParseClientUserAgent("Dart/3.4 (dart:io)"))
+	assert.Equal(t, middleware.ClientInfo{App: "TriviaApp", Version: middleware.ClientUnknown, Platform: middleware.ClientPlatformAndroid},
+		middleware.ParseClientUserAgent("Dalvik/2.1.0 (Linux; Android 14) TriviaApp/<script>"))
+	assert.Equal(t, middleware.ClientUnknown, middleware.ParseClientUserAgent("").App)
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/handler/response"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+// ModerationHandler обрабатывает очередь модерации пользовательского текста (админ-панель)
+type ModerationHandler struct {
+	moderationService *service.ModerationService
+	auditService      *service.AuditService
+}
+
+// NewModerationHandler создает обработчик очереди модерации
+func NewModerationHandler(moderationService *service.ModerationService) *ModerationHandler {
+	return &ModerationHandler{moderationService: moderationService}
+}
+
+// SetAuditService подключает журнал аудита
+func (h *ModerationHandler) SetAuditService(auditService *service.AuditService) {
+	h.auditService = auditService
+}
+
+// ResolveModerationCaseRequest — комментарий администратора к решению
+type ResolveModerationCaseRequest struct {
+	Note string `json:"note" binding:"max=500"`
+}
+
+// ListCases возвращает случаи модерации, старые первыми
+// GET /api/admin/moderation/cases?status=pending&field=username&page=1&page_size=20
+func (h *ModerationHandler) ListCases(c *gin.Context) {
+	status := c.DefaultQuery("status", entity.ModerationCasePending)
+	field := c.Query("field")
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	cases, total, err := h.moderationService.ListCases(status, field, page, pageSize)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{
+		"cases":     cases,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	}, nil)
+}
+
+// ApproveCase оставляет текст пользователя как есть
+// POST /api/admin/moderation/cases/:id/approve
+func (h *ModerationHandler) ApproveCase(c *gin.Context) {
+	h.resolve(c, entity.AuditActionModerationApprove, h.moderationService.Approve)
+}
+
+// RejectCase отклоняет текст: имя пользователя заменяется на player<ID>, имя и фамилия очищаются
+// POST /api/admin/moderation/cases/:id/reject
+func (h *ModerationHandler) RejectCase(c *gin.Context) {
+	h.resolve(c, entity.AuditActionModerationReject, h.moderationService.Reject)
+}
+
+func (h *ModerationHandler) resolve(c *gin.Context, action string, decide func(caseID, adminID uint, note string) (*entity.ModerationCase, error)) {
+	var req ResolveModerationCaseRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.InvalidRequest(c, err)
+			return
+		}
+	}
+	adminID := c.MustGet("user_id").(uint)
+	moderationCase, err := decide(c.MustGet("caseID").(uint), adminID, req.Note)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     action,
+		TargetType: entity.AuditTargetModeration,
+		TargetID:   strconv.FormatUint(uint64(moderationCase.ID), 10),
+		Metadata: map[string]interface{}{
+			"user_id": moderationCase.UserID,
+			"field":   moderationCase.Field,
+			"content": moderationCase.Content,
+		},
+	})
+	response.Success(c, http.StatusOK, moderationCase, nil)
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/trivia-api/internal/handler/response"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+// NotificationHandler обрабатывает запросы центра уведомлений и настроек уведомлений
+type NotificationHandler struct {
+	notificationService *service.NotificationService
+}
+
+// NewNotificationHandler создает новый обработчик уведомлений
+func NewNotificationHandler(notificationService *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{notificationService: notificationService}
+}
+
+// MarkNotificationsReadRequest содержит ID уведомлений, которые нужно отметить прочитанными
+type MarkNotificationsReadRequest struct {
+	IDs []uint `json:"ids" binding:"required,min=1,max=100"`
+}
+
+// UpdatePreferencesRequest содержит изменяемые настройки уведомлений
+type UpdatePreferencesRequest struct {
+	QuizReminders       *bool `json:"quiz_reminders"`
+	WinnerAnnouncements *bool `json:"winner_announcements"`
+	SecurityAlerts      *bool `json:"security_alerts"`
+	EmailEnabled        *bool `json:"email_enabled"`
+	PushEnabled         *bool `json:"push_enabled"`
+	WebSocketEnabled    *bool `json:"websocket_enabled"`
+}
+
+// ListNotifications возвращает уведомления пользователя
+// GET /api/users/me/notifications?page=1&page_size=20&unread_only=true
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	unreadOnly, _ := strconv.ParseBool(c.DefaultQuery("unread_only", "false"))
+
+	list, err := h.notificationService.ListNotifications(userID, unreadOnly, page, pageSize)
+	if err != nil {
+		h.handleNotificationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// GetUnreadCount возвращает количество непрочитанных уведомлений
+// GET /api/users/me/notifications/unread-count
+func (h *NotificationHandler) GetUnreadCount(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	count, err := h.notificationService.GetUnreadCount(userID)
+	if err != nil {
+		h.handleNotificationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"unread_count": count})
+}
+
+// MarkRead отмечает уведомления прочитанными
+// POST /api/users/me/notifications/read
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	var req MarkNotificationsReadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	updated, err := h.notificationService.MarkRead(userID, req.IDs)
+	if err != nil {
+		h.handleNotificationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"updated": updated})
+}
+
+// MarkAllRead отмечает все уведомления прочитанными
+// POST /api/users/me/notifications/read-all
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	updated, err := h.notificationService.MarkAllRead(userID)
+	if err != nil {
+		h.handleNotificationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"updated": updated})
+}
+
+// GetPreferences возвращает настройки уведомлений пользователя
+// GET /api/users/me/notifications/preferences
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	pref, err := h.notificationService.GetPreferences(userID)
+	if err != nil {
+		h.handleNotificationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, pref)
+}
+
+// UpdatePreferences частично обновляет настройки уведомлений
+// PUT /api/users/me/notifications/preferences
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	var req UpdatePreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	pref, err := h.notificationService.UpdatePreferences(userID, service.UpdateNotificationPreferencesInput{
+		QuizReminders:       req.QuizReminders,
+		WinnerAnnouncements: req.WinnerAnnouncements,
+		SecurityAlerts:      req.SecurityAlerts,
+		EmailEnabled:        req.EmailEnabled,
+		PushEnabled:         req.PushEnabled,
+		WebSocketEnabled:    req.WebSocketEnabled,
+	})
+	if err != nil {
+		h.handleNotificationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, pref)
+}
+
+func (h *NotificationHandler) handleNotificationError(c *gin.Context, err error) {
+	if errors.Is(err, apperrors.ErrValidation) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "error_type": "validation_error"})
+	} else if errors.Is(err, apperrors.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found", "error_type": "not_found"})
+	} else {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error", "error_type": "internal_server_error"})
+	}
+}
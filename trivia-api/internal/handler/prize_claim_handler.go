@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/handler/response"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+// PrizeClaimHandler обрабатывает заявки победителей на призы
+type PrizeClaimHandler struct {
+	claimService *service.PrizeClaimService
+	auditService *service.AuditService
+}
+
+// NewPrizeClaimHandler создает новый обработчик заявок на призы
+func NewPrizeClaimHandler(claimService *service.PrizeClaimService) *PrizeClaimHandler {
+	return &PrizeClaimHandler{claimService: claimService}
+}
+
+// SetAuditService подключает журнал аудита
+func (h *PrizeClaimHandler) SetAuditService(auditService *service.AuditService) {
+	h.auditService = auditService
+}
+
+// SubmitPrizeClaimRequest — данные победителя для выплаты приза
+type SubmitPrizeClaimRequest struct {
+	Token             string `json:"token" binding:"required"`
+	PayoutMethod      string `json:"payout_method" binding:"required"`
+	PayoutDestination string `json:"payout_destination"`
+	FullName          string `json:"full_name" binding:"required"`
+	ContactEmail      string `json:"contact_email"`
+	ContactPhone      string `json:"contact_phone"`
+}
+
+// RejectPrizeClaimRequest — причина отклонения заявки
+type RejectPrizeClaimRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// GetClaim возвращает заявку текущего пользователя на приз викторины
+// GET /api/quizzes/:id/claim
+func (h *PrizeClaimHandler) GetClaim(c *gin.Context) {
+	claim, err := h.claimService.GetClaim(c.MustGet("user_id").(uint), c.MustGet("quizID").(uint))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, claim, nil)
+}
+
+// Submit отправляет данные для выплаты приза по токену заявки
+// POST /api/quizzes/:id/claim
+func (h *PrizeClaimHandler) Submit(c *gin.Context) {
+	var req SubmitPrizeClaimRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid_request", "token, payout_method and full_name are required")
+		return
+	}
+	claim, err := h.claimService.Submit(c.MustGet("user_id").(uint), c.MustGet("quizID").(uint), service.SubmitPrizeClaimInput{
+		Token:             req.Token,
+		PayoutMethod:      req.PayoutMethod,
+		PayoutDestination: req.PayoutDestination,
+		FullName:          req.FullName,
+		ContactEmail:      req.ContactEmail,
+		ContactPhone:      req.ContactPhone,
+	})
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, claim, nil)
+}
+
+// ListClaims возвращает заявки на призы
+// GET /api/admin/prize-claims?status=submitted&quiz_id=&page=1&page_size=20
+func (h *PrizeClaimHandler) ListClaims(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	quizID, _ := strconv.ParseUint(c.Query("quiz_id"), 10, 32)
+
+	claims, total, err := h.claimService.ListClaims(c.Query("status"), uint(quizID), page, pageSize)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{
+		"claims":    claims,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	}, nil)
+}
+
+// Verify подтверждает личность победителя и выдаёт приз
+// POST /api/admin/prize-claims/:id/verify
+func (h *PrizeClaimHandler) Verify(c *gin.Context) {
+	claim, err := h.claimService.Verify(c.MustGet("user_id").(uint), c.MustGet("claimID").(uint))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	h.recordClaimAudit(c, entity.AuditActionPrizeClaimVerify, claim)
+	response.Success(c, http.StatusOK, claim, nil)
+}
+
+// Reject возвращает заявку победителю на исправление данных
+// POST /api/admin/prize-claims/:id/reject
+func (h *PrizeClaimHandler) Reject(c *gin.Context) {
+	var req RejectPrizeClaimRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid_request", "reason is required")
+		return
+	}
+	claim, err := h.claimService.Reject(c.MustGet("user_id").(uint), c.MustGet("claimID").(uint), req.Reason)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	h.recordClaimAudit(c, entity.AuditActionPrizeClaimReject, claim)
+	response.Success(c, http.StatusOK, claim, nil)
+}
+
+func (h *PrizeClaimHandler) recordClaimAudit(c *gin.Context, action string, claim *entity.PrizeClaim) {
+	metadata := map[string]interface{}{
+		"quiz_id": claim.QuizID,
+		"user_id": claim.UserID,
+		"amount":  claim.Amount,
+		"status":  claim.Status,
+	}
+	if claim.RejectReason != "" {
+		metadata["reason"] = claim.RejectReason
+	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     action,
+		TargetType: entity.AuditTargetPrizeClaim,
+		TargetID:   strconv.FormatUint(uint64(claim.ID), 10),
+		Metadata:   metadata,
+	})
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yourusername/trivia-api/internal/handler/response"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+// PublicStatsHandler отдаёт публичные счётчики для лендинга (без авторизации и API-ключа)
+type PublicStatsHandler struct {
+	publicStatsService *service.PublicStatsService
+}
+
+// NewPublicStatsHandler создает обработчик публичных счётчиков
+func NewPublicStatsHandler(publicStatsService *service.PublicStatsService) *PublicStatsHandler {
+	return &PublicStatsHandler{publicStatsService: publicStatsService}
+}
+
+// GetStats возвращает число игроков, онлайн, отсчёт до ближайшей викторины и последний призовой фонд
+// GET /api/public/stats
+func (h *PublicStatsHandler) GetStats(c *gin.Context) {
+	stats, err := h.publicStatsService.GetStats()
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	// Встраиваемые виджеты могут кешировать ответ на CDN и в браузере не дольше, чем он живёт в Redis
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(service.PublicStatsCacheTTL.Seconds())))
+	response.Success(c, http.StatusOK, stats, nil)
+}
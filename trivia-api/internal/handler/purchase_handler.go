@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/trivia-api/internal/handler/response"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+// maxPurchaseNotificationBody ограничивает тело серверного уведомления магазина
+const maxPurchaseNotificationBody = 256 * 1024
+
+// PurchaseHandler обрабатывает покупки в магазинах приложений и права пользователя
+type PurchaseHandler struct {
+	purchaseService *service.PurchaseService
+}
+
+// NewPurchaseHandler создает новый обработчик покупок
+func NewPurchaseHandler(purchaseService *service.PurchaseService) *PurchaseHandler {
+	return &PurchaseHandler{purchaseService: purchaseService}
+}
+
+// VerifyPurchaseRequest — покупка, совершённая в приложении
+type VerifyPurchaseRequest struct {
+	Store         string `json:"store" binding:"required,oneof=apple google"`
+	ProductID     string `json:"product_id" binding:"required,max=255"`
+	TransactionID string `json:"transaction_id" binding:"required,max=4096"` // transactionId StoreKit или purchaseToken Google Play
+}
+
+// GetMyEntitlements возвращает действующие права пользователя
+// GET /api/users/me/entitlements
+func (h *PurchaseHandler) GetMyEntitlements(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	entitlements, err := h.purchaseService.GetEntitlements(userID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	active := make([]string, 0, len(entitlements))
+	for _, e := range entitlements {
+		active = append(active, e.Entitlement)
+	}
+	response.Success(c, http.StatusOK, gin.H{
+		"entitlements": entitlements,
+		"active":       active,
+	}, nil)
+}
+
+// VerifyPurchase проверяет покупку в магазине и выдаёт права
+// POST /api/mobile/users/me/purchases
+func (h *PurchaseHandler) VerifyPurchase(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	var req VerifyPurchaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	purchase, err := h.purchaseService.VerifyPurchase(c.Request.Context(), userID, req.Store, service.PurchaseVerifyRequest{
+		ProductID:     req.ProductID,
+		TransactionID: req.TransactionID,
+	})
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	entitlements, err := h.purchaseService.GetEntitlements(userID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{
+		"purchase":     purchase,
+		"entitlements": entitlements,
+	}, nil)
+}
+
+// ListMyPurchases возвращает покупки пользователя
+// GET /api/mobile/users/me/purchases
+func (h *PurchaseHandler) ListMyPurchases(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	purchases, err := h.purchaseService.ListPurchases(userID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{"purchases": purchases}, nil)
+}
+
+// HandleStoreNotification принимает серверные уведомления магазина о продлении, истечении
+// и возврате покупок
+// POST /api/webhooks/purchases/:store
+func (h *PurchaseHandler) HandleStoreNotification(c *gin.Context) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxPurchaseNotificationBody))
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			response.Error(c, http.StatusRequestEntityTooLarge, "payload_too_large", "notification body is too large")
+			return
+		}
+		response.Error(c, http.StatusBadRequest, "invalid_request", "failed to read notification body")
+		return
+	}
+
+	err = h.purchaseService.HandleNotification(c.Request.Context(), c.Param("store"), service.PurchaseNotificationRequest{
+		Header: c.Request.Header,
+		Query:  c.Request.URL.Query(),
+		Body:   body,
+	})
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{"received": true}, nil)
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/trivia-api/internal/handler/response"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+// PushNotificationHandler обрабатывает регистрацию устройств для push-уведомлений
+type PushNotificationHandler struct {
+	pushService *service.PushNotificationService
+}
+
+// NewPushNotificationHandler создает новый обработчик push-уведомлений
+func NewPushNotificationHandler(pushService *service.PushNotificationService) *PushNotificationHandler {
+	return &PushNotificationHandler{pushService: pushService}
+}
+
+// RegisterDeviceRequest содержит данные регистрации push-токена
+type RegisterDeviceRequest struct {
+	Token    string `json:"token" binding:"required,max=512"`
+	Platform string `json:"platform" binding:"required,oneof=ios android"`
+	Provider string `json:"provider" binding:"omitempty,oneof=fcm apns"`
+	DeviceID string `json:"device_id" binding:"omitempty,max=255"`
+	Language string `json:"language" binding:"omitempty,oneof=ru kk"`
+}
+
+// UnregisterDeviceRequest содержит токен для удаления
+type UnregisterDeviceRequest struct {
+	Token string `json:"token" binding:"required,max=512"`
+}
+
+// RegisterDevice регистрирует push-токен устройства
+// POST /api/mobile/notifications/devices
+func (h *PushNotificationHandler) RegisterDevice(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	var req RegisterDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	device, err := h.pushService.RegisterDevice(userID, service.RegisterPushDeviceInput{
+		Token:    req.Token,
+		Platform: req.Platform,
+		Provider: req.Provider,
+		DeviceID: req.DeviceID,
+		Language: req.Language,
+	})
+	if err != nil {
+		h.handlePushError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, device)
+}
+
+// ListDevices возвращает зарегистрированные устройства пользователя
+// GET /api/mobile/notifications/devices
+func (h *PushNotificationHandler) ListDevices(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	devices, err := h.pushService.ListDevices(userID)
+	if err != nil {
+		h.handlePushError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"devices": devices})
+}
+
+// UnregisterDevice удаляет push-токен устройства (например, при выходе из аккаунта)
+// DELETE /api/mobile/notifications/devices
+func (h *PushNotificationHandler) UnregisterDevice(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	var req UnregisterDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	if err := h.pushService.UnregisterDevice(userID, req.Token); err != nil {
+		h.handlePushError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Device unregistered"})
+}
+
+func (h *PushNotificationHandler) handlePushError(c *gin.Context, err error) {
+	if errors.Is(err, apperrors.ErrValidation) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "error_type": "validation_error"})
+	} else if errors.Is(err, apperrors.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Device not found", "error_type": "not_found"})
+	} else {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error", "error_type": "internal_server_error"})
+	}
+}
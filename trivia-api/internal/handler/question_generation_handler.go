@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/handler/response"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+// QuestionGenerationHandler обрабатывает генерацию вопросов языковой моделью (админ-панель)
+type QuestionGenerationHandler struct {
+	generationService *service.QuestionGenerationService
+	auditService      *service.AuditService
+}
+
+// NewQuestionGenerationHandler создает обработчик генерации вопросов
+func NewQuestionGenerationHandler(generationService *service.QuestionGenerationService) *QuestionGenerationHandler {
+	return &QuestionGenerationHandler{generationService: generationService}
+}
+
+// SetAuditService подключает журнал аудита
+func (h *QuestionGenerationHandler) SetAuditService(auditService *service.AuditService) {
+	h.auditService = auditService
+}
+
+// Generate запускает генерацию вопросов в очередь проверки; итоги — в GetGeneration
+// POST /api/admin/question-generations
+func (h *QuestionGenerationHandler) Generate(c *gin.Context) {
+	var req service.QuestionGenerationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+	adminID := c.MustGet("user_id").(uint)
+	generation, err := h.generationService.Generate(adminID, req)
+	if errors.Is(err, service.ErrGenerationQuotaExceeded) {
+		response.Error(c, http.StatusTooManyRequests, "generation_quota_exceeded", err.Error())
+		return
+	}
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionQuestionGenerate,
+		TargetType: entity.AuditTargetGeneration,
+		TargetID:   strconv.FormatUint(uint64(generation.ID), 10),
+		Metadata: map[string]interface{}{
+			"topic":     generation.Topic,
+			"template":  generation.Template,
+			"requested": generation.Requested,
+			"model":     generation.Model,
+		},
+	})
+	response.Success(c, http.StatusAccepted, generation, nil)
+}
+
+// ListGenerations возвращает историю генерации, шаблоны запросов и расход администратора за сутки
+// GET /api/admin/question-generations?page=1&page_size=20
+func (h *QuestionGenerationHandler) ListGenerations(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	generations, total, err := h.generationService.ListGenerations(page, pageSize)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	usage, err := h.generationService.Usage(c.MustGet("user_id").(uint))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{
+		"generations": generations,
+		"templates":   h.generationService.Templates(),
+		"usage":       usage,
+		"total":       total,
+		"page":        page,
+		"page_size":   pageSize,
+	}, nil)
+}
+
+// GetGeneration возвращает статус и итоги генерации; вопросы — в очереди проверки с ?generation_id=
+// GET /api/admin/question-generations/:id
+func (h *QuestionGenerationHandler) GetGeneration(c *gin.Context) {
+	generation, err := h.generationService.GetGeneration(c.MustGet("generationID").(uint))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, generation, nil)
+}
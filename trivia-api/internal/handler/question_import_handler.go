@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/handler/response"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+// QuestionImportHandler обрабатывает импорт вопросов из внешних источников (админ-панель)
+type QuestionImportHandler struct {
+	importService *service.QuestionImportService
+	auditService  *service.AuditService
+}
+
+// NewQuestionImportHandler создает обработчик импорта вопросов
+func NewQuestionImportHandler(importService *service.QuestionImportService) *QuestionImportHandler {
+	return &QuestionImportHandler{importService: importService}
+}
+
+// SetAuditService подключает журнал аудита
+func (h *QuestionImportHandler) SetAuditService(auditService *service.AuditService) {
+	h.auditService = auditService
+}
+
+// StartImportRequest — параметры импорта
+type StartImportRequest struct {
+	Source string `json:"source" binding:"required"`
+	service.QuestionImportQuery
+}
+
+// StartImport загружает вопросы из источника в очередь проверки
+// POST /api/admin/question-imports
+func (h *QuestionImportHandler) StartImport(c *gin.Context) {
+	var req StartImportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+	adminID := c.MustGet("user_id").(uint)
+	run, err := h.importService.Import(c.Request.Context(), &adminID, req.Source, req.QuestionImportQuery)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionQuestionImport,
+		TargetType: entity.AuditTargetImport,
+		TargetID:   strconv.FormatUint(uint64(run.ID), 10),
+		Metadata: map[string]interface{}{
+			"source":   run.Source,
+			"status":   run.Status,
+			"imported": run.Imported,
+		},
+	})
+	if run.Status == entity.QuestionImportFailed {
+		response.Error(c, http.StatusBadGateway, "import_failed", run)
+		return
+	}
+	response.Success(c, http.StatusCreated, run, nil)
+}
+
+// ListImports возвращает историю импорта и подключённые источники
+// GET /api/admin/question-imports?page=1&page_size=20
+func (h *QuestionImportHandler) ListImports(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	runs, total, err := h.importService.ListImports(page, pageSize)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{
+		"imports":   runs,
+		"sources":   h.importService.Sources(),
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	}, nil)
+}
+
+// GetImport возвращает итоги запуска импорта; вопросы запуска — в очереди проверки с ?import_id=
+// GET /api/admin/question-imports/:id
+func (h *QuestionImportHandler) GetImport(c *gin.Context) {
+	run, err := h.importService.GetImport(c.MustGet("importID").(uint))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, run, nil)
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/handler/response"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+// QuestionMediaHandler обрабатывает загрузку картинок и аудио вопросов (админ-панель)
+type QuestionMediaHandler struct {
+	mediaService *service.QuestionMediaService
+	auditService *service.AuditService
+}
+
+// NewQuestionMediaHandler создает новый обработчик медиа вопросов
+func NewQuestionMediaHandler(mediaService *service.QuestionMediaService) *QuestionMediaHandler {
+	return &QuestionMediaHandler{mediaService: mediaService}
+}
+
+// SetAuditService подключает журнал аудита
+func (h *QuestionMediaHandler) SetAuditService(auditService *service.AuditService) {
+	h.auditService = auditService
+}
+
+// UploadMedia загружает картинку или аудио вопроса (multipart: file, kind=image|audio)
+// POST /api/admin/questions/:id/media
+func (h *QuestionMediaHandler) UploadMedia(c *gin.Context) {
+	questionID := c.MustGet("questionID").(uint)
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid_request", "file is required")
+		return
+	}
+	kind := c.PostForm("kind")
+	if kind != service.QuestionMediaImage && kind != service.QuestionMediaAudio {
+		response.Error(c, http.StatusBadRequest, "invalid_request", "kind must be image or audio")
+		return
+	}
+
+	question, err := h.mediaService.UploadMedia(questionID, kind, file)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	h.recordMediaAudit(c, entity.AuditActionQuestionMediaUpload, question, kind)
+	h.respond(c, question)
+}
+
+// DeleteMedia открепляет картинку или аудио от вопроса
+// DELETE /api/admin/questions/:id/media/:kind
+func (h *QuestionMediaHandler) DeleteMedia(c *gin.Context) {
+	questionID := c.MustGet("questionID").(uint)
+	kind := c.Param("kind")
+
+	question, err := h.mediaService.DeleteMedia(questionID, kind)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	h.recordMediaAudit(c, entity.AuditActionQuestionMediaDelete, question, kind)
+	h.respond(c, question)
+}
+
+// respond отдаёт ключи медиа вопроса и ссылки на них
+func (h *QuestionMediaHandler) respond(c *gin.Context, question *entity.Question) {
+	media, err := h.mediaService.QuestionMedia(c.Request.Context(), question)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{
+		"question_id": question.ID,
+		"image_key":   question.ImageKey,
+		"audio_key":   question.AudioKey,
+		"media":       media,
+	}, nil)
+}
+
+func (h *QuestionMediaHandler) recordMediaAudit(c *gin.Context, action string, question *entity.Question, kind string) {
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     action,
+		TargetType: entity.AuditTargetQuestion,
+		TargetID:   strconv.FormatUint(uint64(question.ID), 10),
+		Metadata:   map[string]interface{}{"kind": kind, "image_key": question.ImageKey, "audio_key": question.AudioKey},
+	})
+}
@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/handler/response"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+// QuestionReviewHandler обрабатывает проверку вопросов перед эфиром (админ-панель)
+type QuestionReviewHandler struct {
+	reviewService *service.QuestionReviewService
+	auditService  *service.AuditService
+}
+
+// NewQuestionReviewHandler создает новый обработчик проверки вопросов
+func NewQuestionReviewHandler(reviewService *service.QuestionReviewService) *QuestionReviewHandler {
+	return &QuestionReviewHandler{reviewService: reviewService}
+}
+
+// SetAuditService подключает журнал аудита
+func (h *QuestionReviewHandler) SetAuditService(auditService *service.AuditService) {
+	h.auditService = auditService
+}
+
+// ReviewCommentRequest — комментарий к вопросу или к решению по нему
+type ReviewCommentRequest struct {
+	Comment string `json:"comment"`
+}
+
+// AssignReviewerRequest — назначение рецензента
+type AssignReviewerRequest struct {
+	ReviewerID uint `json:"reviewer_id" binding:"required"`
+}
+
+// ListQueue возвращает очередь проверки вопросов
+// GET /api/admin/question-reviews?status=pending_review&reviewer_id=&quiz_id=&import_id=&generation_id=&page=1&page_size=20
+func (h *QuestionReviewHandler) ListQueue(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	reviewerID, _ := strconv.ParseUint(c.Query("reviewer_id"), 10, 32)
+	quizID, _ := strconv.ParseUint(c.Query("quiz_id"), 10, 32)
+	importID, _ := strconv.ParseUint(c.Query("import_id"), 10, 32)
+	generationID, _ := strconv.ParseUint(c.Query("generation_id"), 10, 32)
+
+	questions, total, err := h.reviewService.ListQueue(c.Query("status"), uint(reviewerID), uint(quizID), uint(importID), uint(generationID), page, pageSize)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{
+		"questions": questions,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	}, nil)
+}
+
+// GetReview возвращает статус проверки вопроса и историю комментариев
+// GET /api/admin/questions/:id/review
+func (h *QuestionReviewHandler) GetReview(c *gin.Context) {
+	review, err := h.reviewService.GetReview(c.MustGet("questionID").(uint))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, review, nil)
+}
+
+// Submit отправляет вопрос на проверку
+// POST /api/admin/questions/:id/review/submit
+func (h *QuestionReviewHandler) Submit(c *gin.Context) {
+	var req ReviewCommentRequest
+	if !h.bindOptional(c, &req) {
+		return
+	}
+	question, err := h.reviewService.Submit(c.MustGet("user_id").(uint), c.MustGet("questionID").(uint), req.Comment)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	h.recordReviewAudit(c, entity.AuditActionQuestionReviewSubmit, question, req.Comment)
+	response.Success(c, http.StatusOK, question, nil)
+}
+
+// AssignReviewer назначает рецензента вопроса
+// POST /api/admin/questions/:id/review/assign
+func (h *QuestionReviewHandler) AssignReviewer(c *gin.Context) {
+	var req AssignReviewerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid_request", "reviewer_id is required")
+		return
+	}
+	question, err := h.reviewService.AssignReviewer(c.MustGet("user_id").(uint), c.MustGet("questionID").(uint), req.ReviewerID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	h.recordReviewAudit(c, entity.AuditActionQuestionReviewAssign, question, "")
+	response.Success(c, http.StatusOK, question, nil)
+}
+
+// Approve одобряет вопрос для эфира
+// POST /api/admin/questions/:id/review/approve
+func (h *QuestionReviewHandler) Approve(c *gin.Context) {
+	var req ReviewCommentRequest
+	if !h.bindOptional(c, &req) {
+		return
+	}
+	question, err := h.reviewService.Approve(c.MustGet("user_id").(uint), c.MustGet("questionID").(uint), req.Comment)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	h.recordReviewAudit(c, entity.AuditActionQuestionReviewApprove, question, req.Comment)
+	response.Success(c, http.StatusOK, question, nil)
+}
+
+// Reject отклоняет вопрос с обязательным комментарием
+// POST /api/admin/questions/:id/review/reject
+func (h *QuestionReviewHandler) Reject(c *gin.Context) {
+	var req ReviewCommentRequest
+	if !h.bindOptional(c, &req) {
+		return
+	}
+	question, err := h.reviewService.Reject(c.MustGet("user_id").(uint), c.MustGet("questionID").(uint), req.Comment)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	h.recordReviewAudit(c, entity.AuditActionQuestionReviewReject, question, req.Comment)
+	response.Success(c, http.StatusOK, question, nil)
+}
+
+// AddComment добавляет комментарий рецензента
+// POST /api/admin/questions/:id/review/comments
+func (h *QuestionReviewHandler) AddComment(c *gin.Context) {
+	var req ReviewCommentRequest
+	if !h.bindOptional(c, &req) {
+		return
+	}
+	comment, err := h.reviewService.AddComment(c.MustGet("user_id").(uint), c.MustGet("questionID").(uint), req.Comment)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusCreated, comment, nil)
+}
+
+// bindOptional разбирает необязательное JSON-тело; пустое тело допустимо
+func (h *QuestionReviewHandler) bindOptional(c *gin.Context, req *ReviewCommentRequest) bool {
+	if c.Request.ContentLength == 0 {
+		return true
+	}
+	if err := c.ShouldBindJSON(req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid_request", "invalid request body")
+		return false
+	}
+	return true
+}
+
+func (h *QuestionReviewHandler) recordReviewAudit(c *gin.Context, action string, question *entity.Question, comment string) {
+	metadata := map[string]interface{}{"review_status": question.ReviewStatus}
+	if question.ReviewerID != nil {
+		metadata["reviewer_id"] = *question.ReviewerID
+	}
+	if comment != "" {
+		metadata["comment"] = comment
+	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     action,
+		TargetType: entity.AuditTargetQuestion,
+		TargetID:   strconv.FormatUint(uint64(question.ID), 10),
+		Metadata:   metadata,
+	})
+}
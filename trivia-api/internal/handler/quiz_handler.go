@@ -14,7 +14,10 @@ import (
 	"github.com/yourusername/trivia-api/internal/domain/entity"
 	"github.com/yourusername/trivia-api/internal/domain/repository"
 	"github.com/yourusername/trivia-api/internal/handler/dto"
+	"github.com/yourusername/trivia-api/internal/handler/response"
 	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/pkg/export"
+	"github.com/yourusername/trivia-api/internal/pkg/ical"
 	"github.com/yourusername/trivia-api/internal/service"
 )
 
@@ -23,6 +26,12 @@ type QuizHandler struct {
 	quizService   *service.QuizService
 	resultService *service.ResultService
 	quizManager   *service.QuizManager
+	auditService  *service.AuditService
+
+	translationService *service.TranslationService
+	taxonomyService    *service.TaxonomyService
+	rsvpService        *service.RSVPService
+	sponsorService     *service.SponsorService
 }
 
 // NewQuizHandler создает новый обработчик викторин
@@ -38,6 +47,58 @@ func NewQuizHandler(
 	}
 }
 
+// SetAuditService подключает журнал аудита
+func (h *QuizHandler) SetAuditService(auditService *service.AuditService) {
+	h.auditService = auditService
+}
+
+// SetTranslationService подключает переводы вопросов
+func (h *QuizHandler) SetTranslationService(translationService *service.TranslationService) {
+	h.translationService = translationService
+}
+
+// SetTaxonomyService подключает категории вопросов пула
+func (h *QuizHandler) SetTaxonomyService(taxonomyService *service.TaxonomyService) {
+	h.taxonomyService = taxonomyService
+}
+
+// SetRSVPService подключает счётчики предварительной записи в списках викторин
+func (h *QuizHandler) SetRSVPService(rsvpService *service.RSVPService) {
+	h.rsvpService = rsvpService
+}
+
+// SetSponsorService подключает брендинг спонсора в карточке викторины
+func (h *QuizHandler) SetSponsorService(sponsorService *service.SponsorService) {
+	h.sponsorService = sponsorService
+}
+
+// quizDetail возвращает карточку викторины с брендингом спонсора
+func (h *QuizHandler) quizDetail(quiz *entity.Quiz) *dto.QuizResponse {
+	resp := dto.NewQuizResponse(quiz, false)
+	if h.sponsorService != nil {
+		resp.Sponsor = h.sponsorService.Branding(quiz)
+	}
+	return resp
+}
+
+// rsvpCounts возвращает число записавшихся по викторинам; nil, если запись не подключена
+// или счётчики получить не удалось (список отдаётся без них)
+func (h *QuizHandler) rsvpCounts(quizzes []entity.Quiz) map[uint]int64 {
+	if h.rsvpService == nil || len(quizzes) == 0 {
+		return nil
+	}
+	quizIDs := make([]uint, len(quizzes))
+	for i := range quizzes {
+		quizIDs[i] = quizzes[i].ID
+	}
+	counts, err := h.rsvpService.Counts(quizIDs)
+	if err != nil {
+		log.Printf("[QuizHandler] Ошибка получения числа записавшихся: %v", err)
+		return nil
+	}
+	return counts
+}
+
 // CreateQuizRequest представляет запрос на создание викторины
 type CreateQuizRequest struct {
 	Title               string    `json:"title" binding:"required,min=3,max=100"`
@@ -46,13 +107,14 @@ type CreateQuizRequest struct {
 	PrizeFund           int       `json:"prize_fund"`             // Опционально, 0 = дефолт
 	FinishOnZeroPlayers bool      `json:"finish_on_zero_players"` // false по умолчанию
 	QuestionSourceMode  string    `json:"question_source_mode,omitempty"`
+	MaxPlayers          int       `json:"max_players" binding:"omitempty,min=0"` // 0 — без ограничения
 }
 
 // CreateQuiz обрабатывает запрос на создание викторины
 func (h *QuizHandler) CreateQuiz(c *gin.Context) {
 	var req CreateQuizRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.InvalidRequest(c, err)
 		return
 	}
 
@@ -69,13 +131,21 @@ func (h *QuizHandler) CreateQuiz(c *gin.Context) {
 		return
 	}
 
+	if req.MaxPlayers > 0 {
+		if _, err := h.quizManager.SetQuizCapacity(quiz.ID, req.MaxPlayers); err != nil {
+			h.handleQuizError(c, err)
+			return
+		}
+		quiz.MaxPlayers = req.MaxPlayers
+	}
+
 	// Auto-планирование викторины
 	if err := h.quizManager.ScheduleQuiz(quiz.ID, req.ScheduledTime); err != nil {
 		log.Printf("[CreateQuiz] Schedule failed for quiz #%d: %v", quiz.ID, err)
 		c.Header("X-Quiz-Schedule-Warning", err.Error())
 	}
 
-	c.JSON(http.StatusCreated, dto.NewQuizResponse(quiz, false))
+	response.Success(c, http.StatusCreated, dto.NewQuizResponse(quiz, false), nil)
 }
 
 // GetQuiz возвращает информацию о викторине
@@ -88,7 +158,7 @@ func (h *QuizHandler) GetQuiz(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.NewQuizResponse(quiz, false))
+	response.Success(c, http.StatusOK, h.quizDetail(quiz), nil)
 }
 
 // GetActiveQuiz возвращает информацию об активной викторине
@@ -96,7 +166,7 @@ func (h *QuizHandler) GetActiveQuiz(c *gin.Context) {
 	// Проверяем сначала в QuizManager
 	activeQuiz := h.quizManager.GetActiveQuiz()
 	if activeQuiz != nil {
-		c.JSON(http.StatusOK, dto.NewQuizResponse(activeQuiz, false))
+		response.Success(c, http.StatusOK, h.quizDetail(activeQuiz), nil)
 		return
 	}
 
@@ -107,10 +177,11 @@ func (h *QuizHandler) GetActiveQuiz(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.NewQuizResponse(quiz, false))
+	response.Success(c, http.StatusOK, h.quizDetail(quiz), nil)
 }
 
-// GetScheduledQuizzes возвращает список запланированных викторин
+// GetScheduledQuizzes возвращает расписание викторин с отсчётом до старта, категорией
+// и числом зарегистрированных участников
 func (h *QuizHandler) GetScheduledQuizzes(c *gin.Context) {
 	quizzes, err := h.quizService.GetScheduledQuizzes()
 	if err != nil {
@@ -119,7 +190,71 @@ func (h *QuizHandler) GetScheduledQuizzes(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.NewListQuizResponse(quizzes))
+	quizIDs := make([]uint, len(quizzes))
+	for i := range quizzes {
+		quizIDs[i] = quizzes[i].ID
+	}
+	registrations := h.quizService.GetRegistrationCounts(quizIDs)
+	list := dto.NewScheduledQuizListResponse(quizzes, registrations, time.Now())
+	if counts := h.rsvpCounts(quizzes); counts != nil {
+		for _, item := range list {
+			count := counts[item.ID]
+			item.RSVPCount = &count
+		}
+	}
+	response.Success(c, http.StatusOK, list, nil)
+}
+
+// defaultCalendarEventDuration — длительность события, если оценить окончание викторины не удалось
+const defaultCalendarEventDuration = 30 * time.Minute
+
+// GetScheduleCalendar отдаёт расписание викторин в формате iCalendar для подписки из календарей
+// GET /api/quizzes/calendar.ics
+func (h *QuizHandler) GetScheduleCalendar(c *gin.Context) {
+	quizzes, err := h.quizService.GetScheduledQuizzes()
+	if err != nil {
+		log.Printf("[QuizHandler] Ошибка при получении запланированных викторин для календаря: %v", err)
+		h.handleQuizError(c, err)
+		return
+	}
+
+	calendar := &ical.Calendar{
+		ProductID: "-//Trivia//Quiz Schedule//RU",
+		Name:      "Trivia",
+		Events:    make([]ical.Event, 0, len(quizzes)),
+	}
+	for i := range quizzes {
+		quiz := &quizzes[i]
+		end, err := h.quizManager.EstimateQuizEnd(quiz)
+		if err != nil {
+			log.Printf("[QuizHandler] Не удалось оценить окончание викторины #%d: %v", quiz.ID, err)
+			end = quiz.ScheduledTime.Add(defaultCalendarEventDuration)
+		}
+		event := ical.Event{
+			UID:         fmt.Sprintf("quiz-%d@trivia-api", quiz.ID),
+			Start:       quiz.ScheduledTime,
+			End:         end,
+			Summary:     quiz.Title,
+			Description: calendarDescription(quiz),
+			Updated:     quiz.UpdatedAt,
+		}
+		if quiz.Category != nil {
+			event.Categories = []string{quiz.Category.Name}
+		}
+		calendar.Events = append(calendar.Events, event)
+	}
+
+	c.Header("Content-Disposition", `inline; filename="quizzes.ics"`)
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(calendar.Render(time.Now())))
+}
+
+// calendarDescription собирает описание события: текст викторины и призовой фонд
+func calendarDescription(quiz *entity.Quiz) string {
+	description := fmt.Sprintf("Призовой фонд: %d", quiz.PrizeFund)
+	if quiz.Description != "" {
+		description = quiz.Description + "\n\n" + description
+	}
+	return description
 }
 
 // AddQuestionsRequest представляет запрос на добавление вопросов
@@ -141,7 +276,7 @@ func (h *QuizHandler) AddQuestions(c *gin.Context) {
 
 	var req AddQuestionsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.InvalidRequest(c, err)
 		return
 	}
 
@@ -149,7 +284,7 @@ func (h *QuizHandler) AddQuestions(c *gin.Context) {
 	questions := make([]entity.Question, 0, len(req.Questions))
 	for _, q := range req.Questions {
 		if q.CorrectOption < 0 || q.CorrectOption >= len(q.Options) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid correct_option index %d for question '%s'", q.CorrectOption, q.Text)})
+			response.Error(c, http.StatusBadRequest, "validation_error", fmt.Sprintf("invalid correct_option index %d for question '%s'", q.CorrectOption, q.Text))
 			return
 		}
 		questions = append(questions, entity.Question{
@@ -168,25 +303,97 @@ func (h *QuizHandler) AddQuestions(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Questions added successfully"})
+	response.Success(c, http.StatusOK, gin.H{"message": "Questions added successfully"}, nil)
 }
 
 // ScheduleQuizRequest представляет запрос на планирование викторины
 type ScheduleQuizRequest struct {
 	ScheduledTime       time.Time `json:"scheduled_time" binding:"required"`
 	FinishOnZeroPlayers *bool     `json:"finish_on_zero_players,omitempty"`
+	// Timing заменяет переопределения таймингов викторины; не передан — тайминги не меняются
+	Timing *entity.QuizTiming `json:"timing,omitempty"`
+	// ScoringStrategy — стратегия начисления очков (flat, speed_weighted, streak_multiplier,
+	// difficulty_weighted); не передана — стратегия не меняется
+	ScoringStrategy string `json:"scoring_strategy,omitempty"`
+	// RequireVerifiedKK — брать только вопросы с проверенным казахским текстом; не передан — не меняется
+	RequireVerifiedKK *bool `json:"require_verified_kk,omitempty"`
 }
 
-// ScheduleQuiz обрабатывает запрос на планирование времени викторины
+// ScheduleQuiz обрабатывает запрос на планирование времени викторины.
+// С ?dry_run=true возвращает отчёт проверки расписания без изменений.
+// Если критические проверки не пройдены, отвечает 409 с отчётом, пока не передан ?force=true.
 func (h *QuizHandler) ScheduleQuiz(c *gin.Context) {
 	quizID := c.MustGet("quizID").(uint) // Получаем из контекста
 
 	var req ScheduleQuizRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.InvalidRequest(c, err)
+		return
+	}
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid_request", "dry_run must be a boolean")
+		return
+	}
+	force, err := strconv.ParseBool(c.DefaultQuery("force", "false"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid_request", "force must be a boolean")
 		return
 	}
 
+	if req.Timing != nil {
+		if err := service.ValidateQuizTiming(*req.Timing); err != nil {
+			h.handleQuizError(c, err)
+			return
+		}
+	}
+	if req.ScoringStrategy != "" {
+		if err := service.ValidateScoringStrategy(req.ScoringStrategy); err != nil {
+			h.handleQuizError(c, err)
+			return
+		}
+	}
+
+	report, err := h.quizManager.ValidateSchedule(quizID, req.ScheduledTime, req.Timing)
+	if err != nil {
+		h.handleQuizError(c, err)
+		return
+	}
+	if dryRun {
+		response.Success(c, http.StatusOK, report, nil)
+		return
+	}
+	if !report.Valid {
+		if !force {
+			response.Error(c, http.StatusConflict, "schedule_validation_failed", report)
+			return
+		}
+		log.Printf("[QuizHandler] Викторина %d планируется с force=true несмотря на проверки: %+v", quizID, report.Failures())
+	}
+
+	before, _ := h.quizService.GetQuizByID(quizID)
+
+	// Тайминги, стратегия очков и требование к казахскому тексту сохраняются до планирования:
+	// планировщик читает викторину из базы
+	if req.Timing != nil {
+		if err := h.quizService.UpdateQuizTiming(quizID, *req.Timing); err != nil {
+			h.handleQuizError(c, err)
+			return
+		}
+	}
+	if req.ScoringStrategy != "" {
+		if err := h.quizService.ConfigureScoringStrategy(quizID, req.ScoringStrategy); err != nil {
+			h.handleQuizError(c, err)
+			return
+		}
+	}
+	if req.RequireVerifiedKK != nil {
+		if err := h.quizService.ConfigureKazakhRequirement(quizID, *req.RequireVerifiedKK); err != nil {
+			h.handleQuizError(c, err)
+			return
+		}
+	}
+
 	// Сначала обновляем время в базе данных
 	if err := h.quizService.ScheduleQuiz(quizID, req.ScheduledTime, req.FinishOnZeroPlayers); err != nil {
 		h.handleQuizError(c, err)
@@ -198,20 +405,23 @@ func (h *QuizHandler) ScheduleQuiz(c *gin.Context) {
 		h.handleQuizError(c, err)
 		return
 	}
+	h.recordQuizAudit(c, entity.AuditActionQuizSchedule, quizID, before)
 
-	c.JSON(http.StatusOK, gin.H{"message": "Quiz scheduled successfully"})
+	response.Success(c, http.StatusOK, gin.H{"message": "Quiz scheduled successfully"}, nil)
 }
 
 // CancelQuiz обрабатывает запрос на отмену викторины
 func (h *QuizHandler) CancelQuiz(c *gin.Context) {
 	quizID := c.MustGet("quizID").(uint) // Получаем из контекста
+	before, _ := h.quizService.GetQuizByID(quizID)
 
 	if err := h.quizManager.CancelQuiz(quizID); err != nil {
 		h.handleQuizError(c, err)
 		return
 	}
+	h.recordQuizAudit(c, entity.AuditActionQuizCancel, quizID, before)
 
-	c.JSON(http.StatusOK, gin.H{"message": "Quiz cancelled successfully"})
+	response.Success(c, http.StatusOK, gin.H{"message": "Quiz cancelled successfully"}, nil)
 }
 
 // GetQuizWithQuestions возвращает викторину вместе с вопросами
@@ -224,9 +434,19 @@ func (h *QuizHandler) GetQuizWithQuestions(c *gin.Context) {
 		return
 	}
 
-	response := dto.NewQuizResponse(quiz, true)
+	quizResp := dto.NewQuizResponse(quiz, true)
+	if h.translationService != nil {
+		resolution := negotiateLocale(c, h.translationService)
+		localized, err := h.translationService.LocalizeQuestions(quiz.Questions, resolution)
+		if err != nil {
+			// Без переводов отдаём вопросы на языке по умолчанию
+			log.Printf("[QuizHandler] Ошибка получения переводов вопросов викторины %d: %v", quizID, err)
+		} else {
+			dto.ApplyLocalization(quizResp, resolution, localized)
+		}
+	}
 
-	c.JSON(http.StatusOK, response)
+	response.Success(c, http.StatusOK, quizResp, nil)
 }
 
 // GetQuizAskedQuestions returns actual asked questions from quiz history.
@@ -239,12 +459,12 @@ func (h *QuizHandler) GetQuizAskedQuestions(c *gin.Context) {
 		return
 	}
 
-	response := make([]dto.AskedQuizQuestionResponse, 0, len(askedQuestions))
+	items := make([]dto.AskedQuizQuestionResponse, 0, len(askedQuestions))
 	for _, item := range askedQuestions {
 		if item.Question == nil {
 			continue
 		}
-		response = append(response, dto.NewAskedQuizQuestionResponse(
+		items = append(items, dto.NewAskedQuizQuestionResponse(
 			item.QuestionOrder,
 			item.AskedAt,
 			item.Source,
@@ -252,12 +472,14 @@ func (h *QuizHandler) GetQuizAskedQuestions(c *gin.Context) {
 		))
 	}
 
-	c.JSON(http.StatusOK, response)
+	response.Success(c, http.StatusOK, items, nil)
 }
 
-// GetQuizResults возвращает пагинированные результаты викторины
+// GetQuizResults возвращает пагинированные результаты викторины. Публично (и партнёрам) игроки
+// показываются с учётом их настроек приватности, администратору (GET /api/quizzes/:id/results/full) — полностью.
 func (h *QuizHandler) GetQuizResults(c *gin.Context) {
 	quizID := c.MustGet("quizID").(uint) // Получаем из контекста
+	fullAccess := c.GetBool("is_admin")
 
 	// Получаем параметры пагинации из query
 	pageStr := c.DefaultQuery("page", "1")
@@ -273,15 +495,37 @@ func (h *QuizHandler) GetQuizResults(c *gin.Context) {
 		pageSize = 10 // Можно взять из конфига
 	}
 
+	// Пагинация курсором: ?cursor= (пустой для первой страницы) вместо ?page=
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		results, nextCursor, err := h.resultService.GetQuizResultsByCursor(quizID, cursor, pageSize, fullAccess)
+		if err != nil {
+			h.handleQuizError(c, err)
+			return
+		}
+		response.Success(c, http.StatusOK, dto.NewCursorResultResponse(results, pageSize, nextCursor), nil)
+		return
+	}
+
 	// Вызываем сервис с пагинацией
-	results, total, err := h.resultService.GetQuizResults(quizID, page, pageSize)
+	results, total, err := h.resultService.GetQuizResults(quizID, page, pageSize, fullAccess)
 	if err != nil {
 		h.handleQuizError(c, err) // Используем стандартизированный обработчик
 		return
 	}
 
 	// Возвращаем пагинированный DTO
-	c.JSON(http.StatusOK, dto.NewPaginatedResultResponse(results, total, page, pageSize))
+	response.Success(c, http.StatusOK, dto.NewPaginatedResultResponse(results, total, page, pageSize), nil)
+}
+
+// GetQuizTimeline возвращает хронику завершённой викторины по вопросам
+// GET /api/quizzes/:id/timeline
+func (h *QuizHandler) GetQuizTimeline(c *gin.Context) {
+	timeline, err := h.resultService.GetQuizTimeline(c.MustGet("quizID").(uint))
+	if err != nil {
+		h.handleQuizError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, timeline, nil)
 }
 
 // GetUserQuizResult возвращает результат пользователя для конкретной викторины
@@ -306,7 +550,7 @@ func (h *QuizHandler) GetUserQuizResult(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.NewResultResponse(result))
+	response.Success(c, http.StatusOK, dto.NewResultResponse(result), nil)
 }
 
 // GetQuizWinners возвращает список всех победителей викторины (без пагинации)
@@ -320,15 +564,15 @@ func (h *QuizHandler) GetQuizWinners(c *gin.Context) {
 	}
 
 	// Конвертируем в DTO
-	response := make([]dto.ResultResponse, len(winners))
+	items := make([]dto.ResultResponse, len(winners))
 	for i, w := range winners {
-		response[i] = *dto.NewResultResponse(&w)
+		items[i] = *dto.NewResultResponse(&w)
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"winners": response,
+	response.Success(c, http.StatusOK, gin.H{
+		"winners": items,
 		"total":   len(winners),
-	})
+	}, nil)
 }
 
 // ListQuizzes возвращает список викторин с пагинацией и фильтрацией
@@ -348,8 +592,10 @@ func (h *QuizHandler) ListQuizzes(c *gin.Context) {
 
 	// Собираем фильтры из query-параметров
 	filters := repository.QuizFilters{
-		Status: c.Query("status"), // scheduled, in_progress, completed, cancelled
-		Search: c.Query("search"), // Поиск по title/description
+		Status:   c.Query("status"),   // scheduled, in_progress, completed, cancelled
+		Search:   c.Query("search"),   // Поиск по title/description
+		Category: c.Query("category"), // Slug категории
+		Tag:      c.Query("tag"),      // Slug тега
 	}
 
 	// Парсим даты если переданы
@@ -371,12 +617,14 @@ func (h *QuizHandler) ListQuizzes(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"quizzes": dto.NewListQuizResponse(quizzes),
+	list := dto.NewListQuizResponse(quizzes)
+	dto.ApplyRSVPCounts(list, h.rsvpCounts(quizzes))
+	response.Success(c, http.StatusOK, gin.H{
+		"quizzes": list,
 		"total":   total,
 		"page":    page,
 		"size":    pageSize,
-	})
+	}, nil)
 }
 
 // DuplicateQuizRequest представляет запрос на дублирование викторины
@@ -390,7 +638,7 @@ func (h *QuizHandler) DuplicateQuiz(c *gin.Context) {
 
 	var req DuplicateQuizRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Неверный формат запроса: %v", err)})
+		response.InvalidRequest(c, err)
 		return
 	}
 
@@ -413,7 +661,356 @@ func (h *QuizHandler) DuplicateQuiz(c *gin.Context) {
 
 	// Отправляем ответ с данными новой викторины
 	// Указываем false, чтобы не включать вопросы в ответ (они только что созданы)
-	c.JSON(http.StatusCreated, dto.NewQuizResponse(newQuiz, false))
+	response.Success(c, http.StatusCreated, dto.NewQuizResponse(newQuiz, false), nil)
+}
+
+// StartQuizSimulationRequest представляет запрос на воспроизведение завершённой викторины
+type StartQuizSimulationRequest struct {
+	// Speed — ускорение относительно реального времени (1-100), 0 — без пауз
+	Speed float64 `json:"speed"`
+}
+
+// StartQuizSimulation запускает воспроизведение завершённой викторины по записанным ответам.
+// Результаты считаются в песочнице и не меняют results; отчёт доступен через GetQuizSimulation.
+// POST /api/quizzes/:id/simulations
+func (h *QuizHandler) StartQuizSimulation(c *gin.Context) {
+	quizID := c.MustGet("quizID").(uint)
+
+	var req StartQuizSimulationRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.InvalidRequest(c, err)
+			return
+		}
+	}
+
+	run, err := h.quizManager.StartSimulation(quizID, req.Speed)
+	if err != nil {
+		h.handleQuizError(c, err)
+		return
+	}
+	response.Success(c, http.StatusAccepted, run, nil)
+}
+
+// GetQuizSimulation возвращает состояние прогона симуляции и отчёт после завершения
+// GET /api/quizzes/:id/simulations/:runId
+func (h *QuizHandler) GetQuizSimulation(c *gin.Context) {
+	quizID := c.MustGet("quizID").(uint)
+
+	run, err := h.quizManager.GetSimulation(quizID, c.Param("runId"))
+	if err != nil {
+		h.handleQuizError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, run, nil)
+}
+
+// GetQuizPreview возвращает предпросмотр викторины перед эфиром: вопросы, которые вероятно будут
+// выбраны, кривую сложности, оценку длительности и расстановку рекламы. Пул вопросов не расходуется.
+// GET /api/quizzes/:id/preview
+func (h *QuizHandler) GetQuizPreview(c *gin.Context) {
+	quizID := c.MustGet("quizID").(uint)
+
+	preview, err := h.quizManager.PreviewQuiz(quizID)
+	if err != nil {
+		h.handleQuizError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, preview, nil)
+}
+
+// ExtendQuestionRequest — продление текущего вопроса
+type ExtendQuestionRequest struct {
+	Seconds int `json:"seconds" binding:"required"`
+}
+
+// SkipQuestionRequest — снятие текущего вопроса с эфира
+type SkipQuestionRequest struct {
+	Reason string `json:"reason"`
+}
+
+// AnnouncementRequest — объявление участникам идущей викторины
+type AnnouncementRequest struct {
+	Message string `json:"message" binding:"required"`
+}
+
+// PauseQuestion ставит таймер текущего вопроса на паузу
+// POST /api/quizzes/:id/live/pause
+func (h *QuizHandler) PauseQuestion(c *gin.Context) {
+	quizID := c.MustGet("quizID").(uint)
+
+	status, err := h.quizManager.PauseQuestion(quizID)
+	if err != nil {
+		h.handleQuizError(c, err)
+		return
+	}
+	h.recordLiveAudit(c, entity.AuditActionQuizLivePause, quizID, gin.H{"question_id": status.QuestionID, "remaining_ms": status.RemainingMs})
+	response.Success(c, http.StatusOK, status, nil)
+}
+
+// ResumeQuestion снимает таймер текущего вопроса с паузы
+// POST /api/quizzes/:id/live/resume
+func (h *QuizHandler) ResumeQuestion(c *gin.Context) {
+	quizID := c.MustGet("quizID").(uint)
+
+	status, err := h.quizManager.ResumeQuestion(quizID)
+	if err != nil {
+		h.handleQuizError(c, err)
+		return
+	}
+	h.recordLiveAudit(c, entity.AuditActionQuizLiveResume, quizID, gin.H{"question_id": status.QuestionID, "remaining_ms": status.RemainingMs})
+	response.Success(c, http.StatusOK, status, nil)
+}
+
+// ExtendQuestion продлевает текущий вопрос
+// POST /api/quizzes/:id/live/extend
+func (h *QuizHandler) ExtendQuestion(c *gin.Context) {
+	quizID := c.MustGet("quizID").(uint)
+
+	var req ExtendQuestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid_request", "seconds is required")
+		return
+	}
+	status, err := h.quizManager.ExtendQuestion(quizID, req.Seconds)
+	if err != nil {
+		h.handleQuizError(c, err)
+		return
+	}
+	h.recordLiveAudit(c, entity.AuditActionQuizLiveExtend, quizID, gin.H{"question_id": status.QuestionID, "seconds": req.Seconds})
+	response.Success(c, http.StatusOK, status, nil)
+}
+
+// SkipQuestion снимает текущий вопрос с эфира; ответы на него не засчитываются
+// POST /api/quizzes/:id/live/skip
+func (h *QuizHandler) SkipQuestion(c *gin.Context) {
+	quizID := c.MustGet("quizID").(uint)
+
+	var req SkipQuestionRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid_request", "invalid request body")
+			return
+		}
+	}
+	status, err := h.quizManager.SkipQuestion(quizID, req.Reason)
+	if err != nil {
+		h.handleQuizError(c, err)
+		return
+	}
+	metadata := gin.H{"question_id": status.QuestionID, "number": status.Number}
+	if req.Reason != "" {
+		metadata["reason"] = req.Reason
+	}
+	h.recordLiveAudit(c, entity.AuditActionQuizLiveSkip, quizID, metadata)
+	response.Success(c, http.StatusOK, status, nil)
+}
+
+// Announce рассылает участникам идущей викторины объявление
+// POST /api/quizzes/:id/live/announce
+func (h *QuizHandler) Announce(c *gin.Context) {
+	quizID := c.MustGet("quizID").(uint)
+
+	var req AnnouncementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid_request", "message is required")
+		return
+	}
+	if err := h.quizManager.Announce(quizID, req.Message); err != nil {
+		h.handleQuizError(c, err)
+		return
+	}
+	h.recordLiveAudit(c, entity.AuditActionQuizLiveAnnounce, quizID, gin.H{"message": req.Message})
+	response.Success(c, http.StatusOK, gin.H{"message": "Announcement sent"}, nil)
+}
+
+// GetDeliveryStats возвращает задержку доставки текущего вопроса игрокам (по подтверждениям этого узла)
+// GET /api/quizzes/:id/live/delivery
+func (h *QuizHandler) GetDeliveryStats(c *gin.Context) {
+	stats, err := h.quizManager.GetDeliveryStats(c.MustGet("quizID").(uint))
+	if err != nil {
+		h.handleQuizError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, stats, nil)
+}
+
+// QuizCapacityRequest — вместимость викторины
+type QuizCapacityRequest struct {
+	MaxPlayers *int `json:"max_players" binding:"required,min=0"` // 0 — без ограничения
+}
+
+// AdmitPlayerRequest — допуск игрока из очереди администратором
+type AdmitPlayerRequest struct {
+	UserID uint `json:"user_id" binding:"required"`
+}
+
+// GetAdmission возвращает заполненность викторины и очередь допуска
+// GET /api/quizzes/:id/admission
+func (h *QuizHandler) GetAdmission(c *gin.Context) {
+	status, err := h.quizManager.GetAdmissionStatus(c.MustGet("quizID").(uint))
+	if err != nil {
+		h.handleQuizError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, status, nil)
+}
+
+// SetQuizCapacity меняет вместимость викторины; при увеличении очередь продвигается сразу
+// PUT /api/quizzes/:id/capacity
+func (h *QuizHandler) SetQuizCapacity(c *gin.Context) {
+	quizID := c.MustGet("quizID").(uint)
+
+	var req QuizCapacityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid_request", "max_players must be a non-negative number")
+		return
+	}
+	status, err := h.quizManager.SetQuizCapacity(quizID, *req.MaxPlayers)
+	if err != nil {
+		h.handleQuizError(c, err)
+		return
+	}
+	h.recordLiveAudit(c, entity.AuditActionQuizCapacity, quizID, gin.H{"max_players": *req.MaxPlayers})
+	response.Success(c, http.StatusOK, status, nil)
+}
+
+// AdmitPlayer впускает игрока из очереди вне очереди и сверх вместимости
+// POST /api/quizzes/:id/admission/admit
+func (h *QuizHandler) AdmitPlayer(c *gin.Context) {
+	quizID := c.MustGet("quizID").(uint)
+
+	var req AdmitPlayerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid_request", "user_id is required")
+		return
+	}
+	status, err := h.quizManager.AdmitQueuedPlayer(quizID, req.UserID)
+	if err != nil {
+		h.handleQuizError(c, err)
+		return
+	}
+	h.recordLiveAudit(c, entity.AuditActionQuizAdmit, quizID, gin.H{"user_id": req.UserID})
+	response.Success(c, http.StatusOK, status, nil)
+}
+
+// GameModeRequest — режим игры викторины
+type GameModeRequest struct {
+	GameMode    string `json:"game_mode" binding:"required"` // elimination | points_only | lives
+	Lives       int    `json:"lives"`                        // только для lives: 1–10
+	SuddenDeath bool   `json:"sudden_death"`                 // финал на выбывание после последнего вопроса
+}
+
+// SetGameMode задаёт режим игры викторины до её старта
+// PUT /api/quizzes/:id/game-mode
+func (h *QuizHandler) SetGameMode(c *gin.Context) {
+	quizID := c.MustGet("quizID").(uint)
+
+	var req GameModeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid_request", "game_mode is required")
+		return
+	}
+	if err := h.quizService.ConfigureGameMode(quizID, req.GameMode, req.Lives, req.SuddenDeath); err != nil {
+		h.handleQuizError(c, err)
+		return
+	}
+	quiz, err := h.quizService.GetQuizByID(quizID)
+	if err != nil {
+		h.handleQuizError(c, err)
+		return
+	}
+	h.recordLiveAudit(c, entity.AuditActionQuizGameMode, quizID, gin.H{
+		"game_mode":    req.GameMode,
+		"lives":        req.Lives,
+		"sudden_death": req.SuddenDeath,
+	})
+	response.Success(c, http.StatusOK, dto.NewQuizResponse(quiz, false), nil)
+}
+
+// TieBreakRequest — способ разрешения ничьей между победителями
+type TieBreakRequest struct {
+	TieBreak string `json:"tie_break" binding:"required"` // none | response_time
+}
+
+// SetTieBreak задаёт способ разрешения ничьей между победителями до завершения викторины
+// PUT /api/quizzes/:id/tie-break
+func (h *QuizHandler) SetTieBreak(c *gin.Context) {
+	quizID := c.MustGet("quizID").(uint)
+
+	var req TieBreakRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid_request", "tie_break is required")
+		return
+	}
+	if err := h.quizService.ConfigureTieBreak(quizID, req.TieBreak); err != nil {
+		h.handleQuizError(c, err)
+		return
+	}
+	quiz, err := h.quizService.GetQuizByID(quizID)
+	if err != nil {
+		h.handleQuizError(c, err)
+		return
+	}
+	h.recordLiveAudit(c, entity.AuditActionQuizTieBreak, quizID, gin.H{"tie_break": req.TieBreak})
+	response.Success(c, http.StatusOK, dto.NewQuizResponse(quiz, false), nil)
+}
+
+// PrizeLadderRequest — лестница призов викторины
+type PrizeLadderRequest struct {
+	Tiers entity.PrizeLadder `json:"tiers"` // пустой список — фонд делится поровну между победителями
+}
+
+// SetPrizeLadder заменяет лестницу призов викторины до её завершения
+// PUT /api/quizzes/:id/prize-ladder
+func (h *QuizHandler) SetPrizeLadder(c *gin.Context) {
+	quizID := c.MustGet("quizID").(uint)
+
+	var req PrizeLadderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid_request", "tiers must be a list of {top, percent}")
+		return
+	}
+	if err := h.quizService.ConfigurePrizeLadder(quizID, req.Tiers); err != nil {
+		h.handleQuizError(c, err)
+		return
+	}
+	quiz, err := h.quizService.GetQuizByID(quizID)
+	if err != nil {
+		h.handleQuizError(c, err)
+		return
+	}
+	h.recordLiveAudit(c, entity.AuditActionQuizPrizeLadder, quizID, gin.H{"tiers": req.Tiers})
+	response.Success(c, http.StatusOK, dto.NewQuizResponse(quiz, false), nil)
+}
+
+// AllowedRegionsRequest — страны, игроки из которых получают призы викторины
+type AllowedRegionsRequest struct {
+	Countries []string `json:"countries"` // коды ISO 3166-1 alpha-2; пустой список снимает ограничение
+}
+
+// SetAllowedRegions заменяет список стран, игроки из которых получают призы, до завершения викторины
+// PUT /api/quizzes/:id/allowed-regions
+func (h *QuizHandler) SetAllowedRegions(c *gin.Context) {
+	quizID := c.MustGet("quizID").(uint)
+
+	var req AllowedRegionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid_request", "countries must be a list of ISO 3166-1 alpha-2 codes")
+		return
+	}
+	countries, err := h.quizService.ConfigureAllowedCountries(quizID, req.Countries)
+	if err != nil {
+		h.handleQuizError(c, err)
+		return
+	}
+	quiz, err := h.quizService.GetQuizByID(quizID)
+	if err != nil {
+		h.handleQuizError(c, err)
+		return
+	}
+	h.recordLiveAudit(c, entity.AuditActionQuizAllowedRegions, quizID, gin.H{"countries": countries})
+	response.Success(c, http.StatusOK, dto.NewQuizResponse(quiz, false), nil)
 }
 
 // ExportQuizResults экспортирует результаты викторины в CSV или Excel формате
@@ -477,7 +1074,7 @@ func (h *QuizHandler) exportCSV(c *gin.Context, results []entity.Result, quiz *e
 		}
 		elimReason := ""
 		if r.EliminationReason != nil {
-			elimReason = translateEliminationReason(*r.EliminationReason)
+			elimReason = export.EliminationReasonLabel(*r.EliminationReason)
 		}
 		prize := ""
 		if r.PrizeFund > 0 {
@@ -486,7 +1083,7 @@ func (h *QuizHandler) exportCSV(c *gin.Context, results []entity.Result, quiz *e
 
 		writer.Write([]string{
 			strconv.Itoa(r.Rank),
-			sanitizeForExcel(r.Username),
+			export.SanitizeCell(r.Username),
 			strconv.Itoa(r.Score),
 			strconv.Itoa(r.CorrectAnswers),
 			strconv.Itoa(r.TotalQuestions),
@@ -516,7 +1113,7 @@ func (h *QuizHandler) exportXLSX(c *gin.Context, results []entity.Result, quiz *
 	sw, err := f.NewStreamWriter(sheetName)
 	if err != nil {
 		log.Printf("[QuizHandler] Ошибка создания StreamWriter: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create Excel file"})
+		response.Error(c, http.StatusInternalServerError, "internal_server_error", nil)
 		return
 	}
 
@@ -545,14 +1142,14 @@ func (h *QuizHandler) exportXLSX(c *gin.Context, results []entity.Result, quiz *
 		}
 		elimReason := ""
 		if r.EliminationReason != nil {
-			elimReason = translateEliminationReason(*r.EliminationReason)
+			elimReason = export.EliminationReasonLabel(*r.EliminationReason)
 		}
 		prize := 0
 		if r.PrizeFund > 0 {
 			prize = r.PrizeFund
 		}
 
-		row := []interface{}{r.Rank, sanitizeForExcel(r.Username), r.Score, r.CorrectAnswers, r.TotalQuestions, winner, eliminated, elimQuestion, elimReason, prize}
+		row := []interface{}{r.Rank, export.SanitizeCell(r.Username), r.Score, r.CorrectAnswers, r.TotalQuestions, winner, eliminated, elimQuestion, elimReason, prize}
 		if err := sw.SetRow(cell, row); err != nil {
 			log.Printf("[QuizHandler] Ошибка записи строки %d: %v", rowNum, err)
 		}
@@ -568,32 +1165,6 @@ func (h *QuizHandler) exportXLSX(c *gin.Context, results []entity.Result, quiz *
 	}
 }
 
-// sanitizeForExcel экранирует данные для защиты от formula injection в Excel/CSV
-func sanitizeForExcel(s string) string {
-	if len(s) == 0 {
-		return s
-	}
-	// Символы, начинающие формулу в Excel/LibreOffice: = + - @ \t \r
-	if s[0] == '=' || s[0] == '+' || s[0] == '-' || s[0] == '@' || s[0] == '\t' || s[0] == '\r' {
-		return "'" + s
-	}
-	return s
-}
-
-// translateEliminationReason переводит причину выбытия на русский
-func translateEliminationReason(reason string) string {
-	switch reason {
-	case "time_exceeded", "no_answer_timeout":
-		return "Время истекло"
-	case "incorrect_answer":
-		return "Неверный ответ"
-	case "disconnected":
-		return "Отключился"
-	default:
-		return reason
-	}
-}
-
 // GetQuizStatistics возвращает расширенную статистику викторины
 func (h *QuizHandler) GetQuizStatistics(c *gin.Context) {
 	quizID := c.MustGet("quizID").(uint)
@@ -604,7 +1175,7 @@ func (h *QuizHandler) GetQuizStatistics(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, stats)
+	response.Success(c, http.StatusOK, stats, nil)
 }
 
 // BulkUploadQuestionPoolRequest представляет запрос на массовую загрузку вопросов
@@ -618,6 +1189,7 @@ type BulkUploadQuestionPoolRequest struct {
 		Difficulty    int      `json:"difficulty" binding:"required,min=1,max=5"` // ОБЯЗАТЕЛЬНОЕ поле
 		TimeLimitSec  int      `json:"time_limit_sec,omitempty"`                  // По умолчанию 20 сек
 		PointValue    int      `json:"point_value,omitempty"`                     // По умолчанию 10
+		CategoryID    *uint    `json:"category_id,omitempty"`                     // Тема для тематических викторин
 	} `json:"questions" binding:"required,min=1"`
 }
 
@@ -626,19 +1198,24 @@ type BulkUploadQuestionPoolRequest struct {
 func (h *QuizHandler) BulkUploadQuestionPool(c *gin.Context) {
 	var req BulkUploadQuestionPoolRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.InvalidRequest(c, err)
 		return
 	}
 
 	// Преобразуем данные в формат entity.Question
 	questions := make([]entity.Question, 0, len(req.Questions))
+	checkedCategories := make(map[uint]bool)
 	for i, q := range req.Questions {
 		if q.CorrectOption < 0 || q.CorrectOption >= len(q.Options) {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": fmt.Sprintf("invalid correct_option index %d for question #%d", q.CorrectOption, i+1),
-			})
+			response.Error(c, http.StatusBadRequest, "validation_error", fmt.Sprintf("invalid correct_option index %d for question #%d", q.CorrectOption, i+1))
 			return
 		}
+		if q.CategoryID != nil && !checkedCategories[*q.CategoryID] {
+			if !h.categoryExists(c, *q.CategoryID, i+1) {
+				return
+			}
+			checkedCategories[*q.CategoryID] = true
+		}
 
 		// Дефолтные значения
 		timeLimitSec := q.TimeLimitSec
@@ -661,6 +1238,7 @@ func (h *QuizHandler) BulkUploadQuestionPool(c *gin.Context) {
 			IsUsed:        false, // Новые вопросы не использованы
 			TimeLimitSec:  timeLimitSec,
 			PointValue:    pointValue,
+			CategoryID:    q.CategoryID,
 		})
 	}
 
@@ -676,29 +1254,72 @@ func (h *QuizHandler) BulkUploadQuestionPool(c *gin.Context) {
 		difficultyCount[q.Difficulty]++
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	response.Success(c, http.StatusOK, gin.H{
 		"message":       "Questions uploaded successfully",
 		"total":         len(questions),
 		"by_difficulty": difficultyCount,
-	})
+	}, nil)
 }
 
-// GetPoolStats возвращает статистику пула вопросов
+// categoryExists проверяет категорию вопроса #number из загрузки и отвечает ошибкой, если её нет
+func (h *QuizHandler) categoryExists(c *gin.Context, categoryID uint, number int) bool {
+	if h.taxonomyService == nil {
+		response.Error(c, http.StatusBadRequest, "validation_error", "question categories are not available")
+		return false
+	}
+	if _, err := h.taxonomyService.GetCategory(categoryID); err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			response.Error(c, http.StatusBadRequest, "validation_error", fmt.Sprintf("unknown category_id %d for question #%d", categoryID, number))
+			return false
+		}
+		response.FromError(c, err)
+		return false
+	}
+	return true
+}
+
+// GetPoolStats возвращает статистику пула вопросов, с ?category=<slug> — одной категории
 // GET /api/admin/question-pool/stats
 func (h *QuizHandler) GetPoolStats(c *gin.Context) {
-	totalCount, availableCount, byDifficulty, err := h.quizService.GetPoolStats()
+	var category *entity.Category
+	if slug := c.Query("category"); slug != "" {
+		if h.taxonomyService == nil {
+			response.Error(c, http.StatusBadRequest, "validation_error", "question categories are not available")
+			return
+		}
+		var err error
+		if category, err = h.taxonomyService.GetCategoryBySlug(slug); err != nil {
+			response.FromError(c, err)
+			return
+		}
+	}
+
+	var (
+		totalCount, availableCount int64
+		byDifficulty               map[int]int64
+		err                        error
+	)
+	if category != nil {
+		totalCount, availableCount, byDifficulty, err = h.quizService.GetCategoryPoolStats(category.ID)
+	} else {
+		totalCount, availableCount, byDifficulty, err = h.quizService.GetPoolStats()
+	}
 	if err != nil {
 		log.Printf("[QuizHandler] Error getting pool stats: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get pool stats"})
+		response.Error(c, http.StatusInternalServerError, "internal_server_error", nil)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	stats := gin.H{
 		"total":         totalCount,
 		"used":          totalCount - availableCount,
 		"available":     availableCount,
 		"by_difficulty": byDifficulty,
-	})
+	}
+	if category != nil {
+		stats["category"] = category
+	}
+	response.Success(c, http.StatusOK, stats, nil)
 }
 
 // ResetPoolUsed сбрасывает флаг is_used для всех вопросов пула
@@ -707,30 +1328,56 @@ func (h *QuizHandler) ResetPoolUsed(c *gin.Context) {
 	resetCount, err := h.quizService.ResetPoolUsed()
 	if err != nil {
 		log.Printf("[QuizHandler] Error resetting pool: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset pool"})
+		response.Error(c, http.StatusInternalServerError, "internal_server_error", nil)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	response.Success(c, http.StatusOK, gin.H{
 		"message": "Pool questions reset successfully",
 		"count":   resetCount,
-	})
+	}, nil)
 }
 
-// handleQuizError обрабатывает ошибки от сервисов викторин и отправляет соответствующий HTTP ответ
+// handleQuizError отправляет ответ для ошибки сервисов викторин (см. response.FromError)
 func (h *QuizHandler) handleQuizError(c *gin.Context, err error) {
-	if errors.Is(err, apperrors.ErrNotFound) {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-	} else if errors.Is(err, apperrors.ErrConflict) {
-		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
-	} else if errors.Is(err, apperrors.ErrValidation) {
-		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
-	} else if errors.Is(err, apperrors.ErrUnauthorized) {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-	} else if errors.Is(err, apperrors.ErrForbidden) {
-		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
-	} else {
-		log.Printf("ERROR: Internal server error in QuizHandler: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	response.FromError(c, err)
+}
+
+// recordQuizAudit записывает в журнал аудита изменение расписания/статуса викторины
+// со снимками до и после операции
+func (h *QuizHandler) recordQuizAudit(c *gin.Context, action string, quizID uint, before *entity.Quiz) {
+	if h.auditService == nil {
+		return
+	}
+	entry := service.AuditEntry{
+		Action:     action,
+		TargetType: entity.AuditTargetQuiz,
+		TargetID:   strconv.FormatUint(uint64(quizID), 10),
+	}
+	if before != nil {
+		entry.Before = quizAuditSnapshot(before)
+	}
+	if after, err := h.quizService.GetQuizByID(quizID); err == nil {
+		entry.After = quizAuditSnapshot(after)
+	}
+	recordAudit(c, h.auditService, entry)
+}
+
+// recordLiveAudit записывает в журнал аудита команду администратора во время эфира
+func (h *QuizHandler) recordLiveAudit(c *gin.Context, action string, quizID uint, metadata gin.H) {
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     action,
+		TargetType: entity.AuditTargetQuiz,
+		TargetID:   strconv.FormatUint(uint64(quizID), 10),
+		Metadata:   metadata,
+	})
+}
+
+func quizAuditSnapshot(q *entity.Quiz) gin.H {
+	return gin.H{
+		"title":                  q.Title,
+		"scheduled_time":         q.ScheduledTime,
+		"status":                 q.Status,
+		"finish_on_zero_players": q.FinishOnZeroPlayers,
 	}
 }
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+// ReferralHandler обрабатывает запросы реферальной программы
+type ReferralHandler struct {
+	referralService *service.ReferralService
+}
+
+// NewReferralHandler создает новый обработчик реферальной программы
+func NewReferralHandler(referralService *service.ReferralService) *ReferralHandler {
+	return &ReferralHandler{referralService: referralService}
+}
+
+// GetMyReferral возвращает код приглашения и список приглашённых пользователей
+// GET /api/users/me/referral?page=1&page_size=20
+func (h *ReferralHandler) GetMyReferral(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	info, err := h.referralService.GetMyReferralInfo(userID, page, pageSize)
+	if err != nil {
+		h.handleReferralError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, info)
+}
+
+// GetStats возвращает статистику конверсии рефералов
+// GET /api/admin/referrals/stats?from=2025-01-01&to=2025-02-01
+func (h *ReferralHandler) GetStats(c *gin.Context) {
+	from, err := parseReferralDate(c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from date, expected YYYY-MM-DD"})
+		return
+	}
+	to, err := parseReferralDate(c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to date, expected YYYY-MM-DD"})
+		return
+	}
+
+	stats, err := h.referralService.GetStats(from, to)
+	if err != nil {
+		h.handleReferralError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetTopReferrers возвращает самых активных пригласивших
+// GET /api/admin/referrals/top?limit=10
+func (h *ReferralHandler) GetTopReferrers(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	referrers, err := h.referralService.GetTopReferrers(limit)
+	if err != nil {
+		h.handleReferralError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"referrers": referrers})
+}
+
+// ListReferrals возвращает реферальные записи с фильтром по статусу
+// GET /api/admin/referrals?status=rejected&page=1&page_size=20
+func (h *ReferralHandler) ListReferrals(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	referrals, total, err := h.referralService.ListReferrals(c.Query("status"), page, pageSize)
+	if err != nil {
+		h.handleReferralError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"referrals": referrals,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	})
+}
+
+func (h *ReferralHandler) handleReferralError(c *gin.Context, err error) {
+	if errors.Is(err, apperrors.ErrValidation) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "error_type": "validation_error"})
+	} else if errors.Is(err, apperrors.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Referral not found", "error_type": "not_found"})
+	} else {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error", "error_type": "internal_server_error"})
+	}
+}
+
+func parseReferralDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
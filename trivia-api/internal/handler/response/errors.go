@@ -0,0 +1,105 @@
+package response
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/pkg/auth/manager"
+)
+
+// Classify возвращает HTTP-статус и код ошибки для ошибок apperrors и manager.TokenError.
+// Неизвестные ошибки считаются внутренними (500, internal_server_error).
+func Classify(err error) (int, string) {
+	var tokenErr *manager.TokenError
+	if errors.As(err, &tokenErr) {
+		switch tokenErr.Type {
+		case manager.ExpiredRefreshToken, manager.ExpiredAccessToken:
+			return http.StatusUnauthorized, "token_expired"
+		case manager.InvalidRefreshToken, manager.InvalidAccessToken, manager.TokenRevoked:
+			return http.StatusUnauthorized, "token_invalid"
+		case manager.InvalidCSRFToken:
+			return http.StatusForbidden, "csrf_mismatch"
+		case manager.InvalidDeviceProof:
+			return http.StatusUnauthorized, "device_proof_invalid"
+		case manager.UserNotFound:
+			return http.StatusUnauthorized, "invalid_credentials"
+		case manager.InactiveUser:
+			return http.StatusForbidden, "forbidden"
+		case manager.TokenGenerationFailed:
+			return http.StatusInternalServerError, "token_generation_failed"
+		case manager.TooManySessions:
+			return http.StatusConflict, "too_many_sessions"
+		}
+		return http.StatusInternalServerError, "internal_server_error"
+	}
+
+	switch {
+	case errors.Is(err, apperrors.ErrValidation):
+		return http.StatusBadRequest, "validation_error"
+	case errors.Is(err, apperrors.ErrNotFound):
+		return http.StatusNotFound, "not_found"
+	case errors.Is(err, apperrors.ErrConflict):
+		return http.StatusConflict, "conflict"
+	case errors.Is(err, apperrors.ErrExpiredToken):
+		return http.StatusUnauthorized, "token_expired"
+	case errors.Is(err, apperrors.ErrUnauthorized):
+		return http.StatusUnauthorized, "unauthorized"
+	case errors.Is(err, apperrors.ErrForbidden):
+		return http.StatusForbidden, "forbidden"
+	}
+	return http.StatusInternalServerError, "internal_server_error"
+}
+
+// FromError отправляет ответ для ошибки сервиса (см. Classify). Текст ошибок валидации,
+// конфликтов и «не найдено» передаётся в details; внутренние ошибки только логируются.
+func FromError(c *gin.Context, err error) {
+	status, code := Classify(err)
+	switch code {
+	case "validation_error", "conflict", "not_found":
+		Error(c, status, code, err.Error())
+	case "internal_server_error", "token_generation_failed":
+		log.Printf("[API] Внутренняя ошибка %s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+		Error(c, status, code, nil)
+	default:
+		Error(c, status, code, nil)
+	}
+}
+
+// ErrorHandler — middleware, которое отправляет ответ для ошибки, переданной обработчиком
+// через c.Error (если ответ ещё не записан), и превращает панику обработчика в 500
+// в едином формате вместо обрыва соединения.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				if errors.Is(asError(recovered), http.ErrAbortHandler) {
+					panic(recovered)
+				}
+				log.Printf("[API] Паника в обработчике %s %s: %v\n%s", c.Request.Method, c.Request.URL.Path, recovered, debug.Stack())
+				if !c.Writer.Written() {
+					Error(c, http.StatusInternalServerError, "internal_server_error", nil)
+				}
+				c.Abort()
+			}
+		}()
+
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		FromError(c, c.Errors.Last().Err)
+	}
+}
+
+func asError(value interface{}) error {
+	if err, ok := value.(error); ok {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+// Package response формирует ответы HTTP API в едином формате:
+// успешные — {success, data, meta}, ошибки — {success, error, error_type, details}.
+package response
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yourusername/trivia-api/internal/middleware"
+	"github.com/yourusername/trivia-api/internal/pkg/i18n"
+	"github.com/yourusername/trivia-api/internal/pkg/validation"
+)
+
+// EnvelopeHeader — заголовок запроса, которым клиент включает конверт для успешных ответов.
+// Без него Success отдаёт data как есть: существующие клиенты читают ответы без конверта.
+const EnvelopeHeader = "X-Response-Envelope"
+
+// Meta — дополнительные сведения об ответе (пагинация, предупреждения)
+type Meta map[string]interface{}
+
+// Envelope — единый формат ответа API
+type Envelope struct {
+	Success   bool        `json:"success"`
+	Data      interface{} `json:"data,omitempty"`
+	Meta      Meta        `json:"meta,omitempty"`
+	Error     string      `json:"error,omitempty"`      // сообщение на языке пользователя
+	ErrorType string      `json:"error_type,omitempty"` // машиночитаемый код ошибки
+	Details   interface{} `json:"details,omitempty"`    // уточнение без перевода
+
+	Fields []validation.FieldError `json:"fields,omitempty"` // ошибки полей запроса (InvalidRequest, ValidationError)
+}
+
+// Success отправляет успешный ответ. В конверт data и meta заворачиваются, если клиент
+// запросил это заголовком EnvelopeHeader; иначе отправляется data без meta.
+func Success(c *gin.Context, status int, data interface{}, meta Meta) {
+	if !wantsEnvelope(c) {
+		c.JSON(status, data)
+		return
+	}
+	Enveloped(c, status, data, meta)
+}
+
+// Enveloped всегда отправляет успешный ответ в конверте. Для эндпоинтов, которые отдавали
+// конверт и до появления заголовка EnvelopeHeader.
+func Enveloped(c *gin.Context, status int, data interface{}, meta Meta) {
+	c.JSON(status, Envelope{Success: true, Data: data, Meta: meta})
+}
+
+// Error отправляет ошибку с кодом code и сообщением из каталога i18n на языке пользователя
+// (см. middleware.RequestLocale). details не переводится: обычно это текст ошибки валидации.
+func Error(c *gin.Context, status int, code string, details interface{}) {
+	message, locale := i18n.ErrorMessage(code, middleware.RequestLocale(c).Chain())
+	if locale != "" {
+		c.Header("Content-Language", locale)
+	}
+	if s, ok := details.(string); ok && s == "" {
+		details = nil
+	}
+	c.JSON(status, Envelope{Error: message, ErrorType: code, Details: details})
+}
+
+// InvalidRequest отправляет 400 invalid_request для ошибки привязки тела или параметров запроса
+// (c.ShouldBind*): ошибки отдельных полей — в fields, их сводка — в details.
+func InvalidRequest(c *gin.Context, err error) {
+	bindingError(c, "invalid_request", err)
+}
+
+// ValidationError — то же, что InvalidRequest, с кодом validation_error
+func ValidationError(c *gin.Context, err error) {
+	bindingError(c, "validation_error", err)
+}
+
+func bindingError(c *gin.Context, code string, err error) {
+	chain := middleware.RequestLocale(c).Chain()
+	fields := validation.FieldErrors(err, chain)
+	if len(fields) == 0 {
+		Error(c, http.StatusBadRequest, code, err.Error())
+		return
+	}
+	message, locale := i18n.ErrorMessage(code, chain)
+	if locale != "" {
+		c.Header("Content-Language", locale)
+	}
+	c.JSON(http.StatusBadRequest, Envelope{Error: message, ErrorType: code, Details: validation.Summary(fields), Fields: fields})
+}
+
+func wantsEnvelope(c *gin.Context) bool {
+	switch c.GetHeader(EnvelopeHeader) {
+	case "1", "true":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+package response
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/pkg/auth/manager"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func perform(t *testing.T, header string, handler gin.HandlerFunc) (*httptest.ResponseRecorder, map[string]interface{}) {
+	t.Helper()
+	router := gin.New()
+	router.Use(ErrorHandler())
+	router.GET("/test", handler)
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	if header != "" {
+		req.Header.Set(EnvelopeHeader, header)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body), w.Body.String())
+	return w, body
+}
+
+func TestSuccess_LegacyShapeWithoutHeader(t *testing.T) {
+	_, body := perform(t, "", func(c *gin.Context) {
+		Success(c, http.StatusOK, gin.H{"id": 1}, Meta{"page": 1})
+	})
+	assert.Equal(t, map[string]interface{}{"id": float64(1)}, body)
+}
+
+func TestSuccess_EnvelopeOnRequest(t *testing.T) {
+	_, body := perform(t, "1", func(c *gin.Context) {
+		Success(c, http.StatusOK, gin.H{"id": 1}, Meta{"page": 1})
+	})
+	assert.Equal(t, true, body["success"])
+	assert.Equal(t, map[string]interface{}{"id": float64(1)}, body["data"])
+	assert.Equal(t, map[string]interface{}{"page": float64(1)}, body["meta"])
+}
+
+func TestError_Format(t *testing.T) {
+	w, body := perform(t, "", func(c *gin.Context) {
+		Error(c, http.StatusBadRequest, "validation_error", "")
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, false, body["success"])
+	assert.Equal(t, "validation_error", body["error_type"])
+	assert.Equal(t, "Validation error", body["error"])
+	assert.NotContains(t, body, "details")
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{fmt.Errorf("%w: title is required", apperrors.ErrValidation), http.StatusBadRequest, "validation_error"},
+		{fmt.Errorf("quiz 5: %w", apperrors.ErrNotFound), http.StatusNotFound, "not_found"},
+		{apperrors.ErrConflict, http.StatusConflict, "conflict"},
+		{apperrors.ErrForbidden, http.StatusForbidden, "forbidden"},
+		{apperrors.ErrExpiredToken, http.StatusUnauthorized, "token_expired"},
+		{&manager.TokenError{Type: manager.ExpiredAccessToken}, http.StatusUnauthorized, "token_expired"},
+		{&manager.TokenError{Type: manager.TokenRevoked}, http.StatusUnauthorized, "token_invalid"},
+		{&manager.TokenError{Type: manager.InvalidCSRFToken}, http.StatusForbidden, "csrf_mismatch"},
+		{&manager.TokenError{Type: manager.InvalidDeviceProof}, http.StatusUnauthorized, "device_proof_invalid"},
+		{&manager.TokenError{Type: manager.DatabaseError}, http.StatusInternalServerError, "internal_server_error"},
+		{assert.AnError, http.StatusInternalServerError, "internal_server_error"},
+	}
+	for _, tt := range tests {
+		status, code := Classify(tt.err)
+		assert.Equal(t, tt.wantStatus, status, tt.err.Error())
+		assert.Equal(t, tt.wantCode, code, tt.err.Error())
+	}
+}
+
+func TestErrorHandler_RendersContextError(t *testing.T) {
+	w, body := perform(t, "", func(c *gin.Context) {
+		_ = c.Error(fmt.Errorf("%w: title is required", apperrors.ErrValidation))
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "validation_error", body["error_type"])
+	assert.Equal(t, "validation failed: title is required", body["details"])
+}
+
+func TestErrorHandler_RecoversPanic(t *testing.T) {
+	w, body := perform(t, "", func(c *gin.Context) {
+		panic("boom")
+	})
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "internal_server_error", body["error_type"])
+}
+
+func TestInvalidRequest_FieldErrors(t *testing.T) {
+	w, body := perform(t, "", func(c *gin.Context) {
+		var req struct {
+			Email string `json:"email" binding:"required,email"`
+		}
+		c.Request.Body = http.NoBody
+		err := c.ShouldBindJSON(&req)
+		require.Error(t, err)
+		InvalidRequest(c, fmt.Errorf("bind: %w", err))
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "invalid_request", body["error_type"])
+	assert.Equal(t, "body: This field is required", body["details"])
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"field": "body", "rule": "required", "message": "This field is required",
+	}}, body["fields"])
+
+	_, body = perform(t, "", func(c *gin.Context) {
+		ValidationError(c, fmt.Errorf("not a binding error"))
+	})
+	assert.Equal(t, "validation_error", body["error_type"])
+	assert.Equal(t, "not a binding error", body["details"])
+	assert.Nil(t, body["fields"])
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/trivia-api/internal/handler/response"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+// RSVPHandler обрабатывает предварительную запись на запланированные викторины
+type RSVPHandler struct {
+	rsvpService *service.RSVPService
+}
+
+// NewRSVPHandler создает обработчик предварительной записи
+func NewRSVPHandler(rsvpService *service.RSVPService) *RSVPHandler {
+	return &RSVPHandler{rsvpService: rsvpService}
+}
+
+// GetRSVP возвращает, записан ли пользователь на викторину, и число записавшихся
+// GET /api/quizzes/:id/rsvp
+func (h *RSVPHandler) GetRSVP(c *gin.Context) {
+	status, err := h.rsvpService.GetStatus(c.MustGet("user_id").(uint), c.MustGet("quizID").(uint))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, status, nil)
+}
+
+// RSVP записывает пользователя на викторину
+// POST /api/quizzes/:id/rsvp
+func (h *RSVPHandler) RSVP(c *gin.Context) {
+	status, err := h.rsvpService.RSVP(c.MustGet("user_id").(uint), c.MustGet("quizID").(uint))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, status, nil)
+}
+
+// CancelRSVP отменяет запись пользователя на викторину
+// DELETE /api/quizzes/:id/rsvp
+func (h *RSVPHandler) CancelRSVP(c *gin.Context) {
+	status, err := h.rsvpService.CancelRSVP(c.MustGet("user_id").(uint), c.MustGet("quizID").(uint))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, status, nil)
+}
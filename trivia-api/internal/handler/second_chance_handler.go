@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/handler/response"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+// SecondChanceHandler обрабатывает возвращение выбывших игроков в викторину
+type SecondChanceHandler struct {
+	secondChanceService *service.SecondChanceService
+	auditService        *service.AuditService
+}
+
+// NewSecondChanceHandler создает новый обработчик второго шанса
+func NewSecondChanceHandler(secondChanceService *service.SecondChanceService) *SecondChanceHandler {
+	return &SecondChanceHandler{secondChanceService: secondChanceService}
+}
+
+// SetAuditService подключает журнал аудита
+func (h *SecondChanceHandler) SetAuditService(auditService *service.AuditService) {
+	h.auditService = auditService
+}
+
+// SecondChanceConfigRequest — настройки второго шанса викторины
+type SecondChanceConfigRequest struct {
+	Mode      string `json:"mode" binding:"required"`
+	Cost      int64  `json:"cost"`
+	AdAssetID *uint  `json:"ad_asset_id"`
+}
+
+// Start начинает второй шанс: выдаёт рекламный ролик или стоимость в очках
+// POST /api/quizzes/:id/second-chance/start
+func (h *SecondChanceHandler) Start(c *gin.Context) {
+	offer, err := h.secondChanceService.Start(c.MustGet("user_id").(uint), c.MustGet("quizID").(uint))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, offer, nil)
+}
+
+// Revive возвращает выбывшего игрока в викторину
+// POST /api/quizzes/:id/second-chance
+func (h *SecondChanceHandler) Revive(c *gin.Context) {
+	quizID := c.MustGet("quizID").(uint)
+	if err := h.secondChanceService.Revive(c.Request.Context(), c.MustGet("user_id").(uint), quizID); err != nil {
+		if errors.Is(err, service.ErrInsufficientFunds) {
+			response.Error(c, http.StatusBadRequest, "insufficient_funds", "not enough points for a second chance")
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{"quiz_id": quizID, "revived": true}, nil)
+}
+
+// Configure задаёт режим второго шанса викторины (админ-панель)
+// PUT /api/quizzes/:id/second-chance
+func (h *SecondChanceHandler) Configure(c *gin.Context) {
+	var req SecondChanceConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid_request", "mode is required")
+		return
+	}
+	quiz, err := h.secondChanceService.Configure(c.MustGet("quizID").(uint), service.SecondChanceConfig{
+		Mode:      req.Mode,
+		Cost:      req.Cost,
+		AdAssetID: req.AdAssetID,
+	})
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	metadata := map[string]interface{}{"mode": quiz.SecondChanceMode, "cost": quiz.SecondChanceCost}
+	if quiz.SecondChanceAdID != nil {
+		metadata["ad_asset_id"] = *quiz.SecondChanceAdID
+	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionQuizSecondChance,
+		TargetType: entity.AuditTargetQuiz,
+		TargetID:   strconv.FormatUint(uint64(quiz.ID), 10),
+		Metadata:   metadata,
+	})
+	response.Success(c, http.StatusOK, gin.H{
+		"quiz_id":     quiz.ID,
+		"mode":        quiz.SecondChanceMode,
+		"cost":        quiz.SecondChanceCost,
+		"ad_asset_id": quiz.SecondChanceAdID,
+	}, nil)
+}
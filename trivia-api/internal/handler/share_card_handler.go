@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/trivia-api/internal/handler/response"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+// ShareCardHandler обрабатывает карточки результатов для соцсетей
+type ShareCardHandler struct {
+	shareCardService *service.ShareCardService
+}
+
+// NewShareCardHandler создает обработчик карточек результатов
+func NewShareCardHandler(shareCardService *service.ShareCardService) *ShareCardHandler {
+	return &ShareCardHandler{shareCardService: shareCardService}
+}
+
+// GetMyResultShare возвращает ссылки на карточку результата текущего пользователя
+// GET /api/quizzes/:id/my-result/share
+func (h *ShareCardHandler) GetMyResultShare(c *gin.Context) {
+	card, err := h.shareCardService.GetMyResultCard(c.Request.Context(), c.MustGet("user_id").(uint), c.MustGet("quizID").(uint))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, card, nil)
+}
+
+// GetSharePage отдаёт публичную страницу карточки с метаданными OpenGraph для превью в соцсетях
+// GET /share/:token
+func (h *ShareCardHandler) GetSharePage(c *gin.Context) {
+	page, err := h.shareCardService.GetPage(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	// Содержимое по адресу не меняется: новый результат — новый адрес
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.Data(http.StatusOK, "text/html; charset=utf-8", page)
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/handler/response"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+// SponsorHandler управляет брендингом спонсора викторины (админ-панель)
+type SponsorHandler struct {
+	sponsorService *service.SponsorService
+	auditService   *service.AuditService
+}
+
+// NewSponsorHandler создает обработчик брендинга спонсоров
+func NewSponsorHandler(sponsorService *service.SponsorService) *SponsorHandler {
+	return &SponsorHandler{sponsorService: sponsorService}
+}
+
+// SetAuditService подключает журнал аудита
+func (h *SponsorHandler) SetAuditService(auditService *service.AuditService) {
+	h.auditService = auditService
+}
+
+// SponsorRequest — брендинг спонсора викторины; пустое имя убирает спонсора
+type SponsorRequest struct {
+	Name           string `json:"name"`
+	LogoAssetID    *uint  `json:"logo_asset_id"`
+	PrimaryColor   string `json:"primary_color"`
+	SecondaryColor string `json:"secondary_color"`
+	ClickURL       string `json:"click_url"`
+}
+
+// Configure заменяет брендинг спонсора викторины
+// PUT /api/quizzes/:id/sponsor
+func (h *SponsorHandler) Configure(c *gin.Context) {
+	quizID := c.MustGet("quizID").(uint)
+
+	var req SponsorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+	quiz, err := h.sponsorService.Configure(quizID, entity.QuizSponsor{
+		Name:           req.Name,
+		LogoAssetID:    req.LogoAssetID,
+		PrimaryColor:   req.PrimaryColor,
+		SecondaryColor: req.SecondaryColor,
+		ClickURL:       req.ClickURL,
+	})
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionQuizSponsor,
+		TargetType: entity.AuditTargetQuiz,
+		TargetID:   strconv.FormatUint(uint64(quizID), 10),
+		After:      quiz.Sponsor,
+	})
+	response.Success(c, http.StatusOK, gin.H{
+		"quiz_id": quizID,
+		"sponsor": h.sponsorService.Branding(quiz),
+	}, nil)
+}
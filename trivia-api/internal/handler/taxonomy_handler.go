@@ -0,0 +1,281 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/handler/response"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+// TaxonomyHandler отдаёт категории и теги викторин и управляет ими (админ-панель)
+type TaxonomyHandler struct {
+	taxonomyService *service.TaxonomyService
+	auditService    *service.AuditService
+}
+
+// NewTaxonomyHandler создает обработчик категорий и тегов
+func NewTaxonomyHandler(taxonomyService *service.TaxonomyService) *TaxonomyHandler {
+	return &TaxonomyHandler{taxonomyService: taxonomyService}
+}
+
+// SetAuditService подключает журнал аудита
+func (h *TaxonomyHandler) SetAuditService(auditService *service.AuditService) {
+	h.auditService = auditService
+}
+
+// CategoryRequest — поля категории
+type CategoryRequest struct {
+	Slug        string `json:"slug" binding:"required,max=64"`
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description" binding:"max=500"`
+}
+
+// TagRequest — поля тега
+type TagRequest struct {
+	Slug string `json:"slug" binding:"required,max=64"`
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+// TaxonomyAssignmentRequest — категория и теги викторины или вопроса; отсутствующая категория снимается
+type TaxonomyAssignmentRequest struct {
+	CategoryID *uint    `json:"category_id"`
+	Tags       []string `json:"tags" binding:"max=20,dive,max=64"`
+}
+
+// ListCategories возвращает все категории
+// GET /api/categories
+func (h *TaxonomyHandler) ListCategories(c *gin.Context) {
+	categories, err := h.taxonomyService.ListCategories()
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{"categories": categories}, nil)
+}
+
+// ListTags возвращает все теги
+// GET /api/tags
+func (h *TaxonomyHandler) ListTags(c *gin.Context) {
+	tags, err := h.taxonomyService.ListTags()
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{"tags": tags}, nil)
+}
+
+// GetCategoryLeaderboard возвращает лидерборд по завершённым викторинам категории
+// GET /api/categories/:slug/leaderboard?page=1&page_size=10
+func (h *TaxonomyHandler) GetCategoryLeaderboard(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	leaderboard, err := h.taxonomyService.GetCategoryLeaderboard(c.Param("slug"), page, pageSize)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, leaderboard, nil)
+}
+
+// CreateCategory создаёт категорию
+// POST /api/admin/categories
+func (h *TaxonomyHandler) CreateCategory(c *gin.Context) {
+	var req CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	category, err := h.taxonomyService.CreateCategory(service.CategoryInput(req))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionCategoryCreate,
+		TargetType: entity.AuditTargetCategory,
+		TargetID:   strconv.FormatUint(uint64(category.ID), 10),
+		After:      category,
+	})
+	response.Success(c, http.StatusCreated, category, nil)
+}
+
+// UpdateCategory заменяет поля категории
+// PUT /api/admin/categories/:id
+func (h *TaxonomyHandler) UpdateCategory(c *gin.Context) {
+	var req CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	categoryID := c.MustGet("categoryID").(uint)
+	before, err := h.taxonomyService.GetCategory(categoryID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	category, err := h.taxonomyService.UpdateCategory(categoryID, service.CategoryInput(req))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionCategoryUpdate,
+		TargetType: entity.AuditTargetCategory,
+		TargetID:   strconv.FormatUint(uint64(categoryID), 10),
+		Before:     before,
+		After:      category,
+	})
+	response.Success(c, http.StatusOK, category, nil)
+}
+
+// DeleteCategory удаляет категорию; викторины и вопросы остаются без категории
+// DELETE /api/admin/categories/:id
+func (h *TaxonomyHandler) DeleteCategory(c *gin.Context) {
+	categoryID := c.MustGet("categoryID").(uint)
+	before, err := h.taxonomyService.GetCategory(categoryID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	if err := h.taxonomyService.DeleteCategory(categoryID); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionCategoryDelete,
+		TargetType: entity.AuditTargetCategory,
+		TargetID:   strconv.FormatUint(uint64(categoryID), 10),
+		Before:     before,
+	})
+	response.Success(c, http.StatusOK, gin.H{"message": "Category deleted"}, nil)
+}
+
+// CreateTag создаёт тег
+// POST /api/admin/tags
+func (h *TaxonomyHandler) CreateTag(c *gin.Context) {
+	var req TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	tag, err := h.taxonomyService.CreateTag(service.TagInput(req))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionTagCreate,
+		TargetType: entity.AuditTargetTag,
+		TargetID:   strconv.FormatUint(uint64(tag.ID), 10),
+		After:      tag,
+	})
+	response.Success(c, http.StatusCreated, tag, nil)
+}
+
+// UpdateTag заменяет поля тега
+// PUT /api/admin/tags/:id
+func (h *TaxonomyHandler) UpdateTag(c *gin.Context) {
+	var req TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	tagID := c.MustGet("tagID").(uint)
+	before, err := h.taxonomyService.GetTag(tagID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	tag, err := h.taxonomyService.UpdateTag(tagID, service.TagInput(req))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionTagUpdate,
+		TargetType: entity.AuditTargetTag,
+		TargetID:   strconv.FormatUint(uint64(tagID), 10),
+		Before:     before,
+		After:      tag,
+	})
+	response.Success(c, http.StatusOK, tag, nil)
+}
+
+// DeleteTag удаляет тег и снимает его с викторин и вопросов
+// DELETE /api/admin/tags/:id
+func (h *TaxonomyHandler) DeleteTag(c *gin.Context) {
+	tagID := c.MustGet("tagID").(uint)
+	before, err := h.taxonomyService.GetTag(tagID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	if err := h.taxonomyService.DeleteTag(tagID); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionTagDelete,
+		TargetType: entity.AuditTargetTag,
+		TargetID:   strconv.FormatUint(uint64(tagID), 10),
+		Before:     before,
+	})
+	response.Success(c, http.StatusOK, gin.H{"message": "Tag deleted"}, nil)
+}
+
+// SetQuizTaxonomy заменяет категорию и теги викторины
+// PUT /api/quizzes/:id/taxonomy
+func (h *TaxonomyHandler) SetQuizTaxonomy(c *gin.Context) {
+	var req TaxonomyAssignmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	quizID := c.MustGet("quizID").(uint)
+	assignment, err := h.taxonomyService.SetQuizTaxonomy(quizID, req.CategoryID, req.Tags)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionQuizTaxonomy,
+		TargetType: entity.AuditTargetQuiz,
+		TargetID:   strconv.FormatUint(uint64(quizID), 10),
+		After:      assignment,
+	})
+	response.Success(c, http.StatusOK, assignment, nil)
+}
+
+// SetQuestionTaxonomy заменяет категорию и теги вопроса
+// PUT /api/admin/questions/:id/taxonomy
+func (h *TaxonomyHandler) SetQuestionTaxonomy(c *gin.Context) {
+	var req TaxonomyAssignmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	questionID := c.MustGet("questionID").(uint)
+	assignment, err := h.taxonomyService.SetQuestionTaxonomy(questionID, req.CategoryID, req.Tags)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionQuestionTaxonomy,
+		TargetType: entity.AuditTargetQuestion,
+		TargetID:   strconv.FormatUint(uint64(questionID), 10),
+		After:      assignment,
+	})
+	response.Success(c, http.StatusOK, assignment, nil)
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yourusername/trivia-api/internal/handler/response"
+)
+
+// TimeHandler отдаёт время сервера для синхронизации часов клиента
+type TimeHandler struct{}
+
+// NewTimeHandler создает обработчик времени сервера
+func NewTimeHandler() *TimeHandler {
+	return &TimeHandler{}
+}
+
+// ServerTimeResponse — время сервера. Клиент считает смещение часов как
+// ((received_at_ms - client_time) + (server_time_ms - время получения ответа)) / 2.
+type ServerTimeResponse struct {
+	ServerTime   string   `json:"server_time"`           // RFC 3339 с наносекундами, UTC
+	ServerTimeMs float64  `json:"server_time_ms"`        // unix ms с дробной частью, момент отправки ответа
+	ReceivedAtMs float64  `json:"received_at_ms"`        // unix ms с дробной частью, момент приёма запроса
+	ClientTime   *float64 `json:"client_time,omitempty"` // эхо параметра client_time
+}
+
+// GetTime возвращает время сервера с точностью до микросекунд
+// GET /api/time?client_time=1737564125000.25
+func (h *TimeHandler) GetTime(c *gin.Context) {
+	receivedAt := time.Now()
+	resp := ServerTimeResponse{ReceivedAtMs: unixMillis(receivedAt)}
+	if raw := c.Query("client_time"); raw != "" {
+		clientTime, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid_request", "client_time must be unix milliseconds")
+			return
+		}
+		resp.ClientTime = &clientTime
+	}
+
+	c.Header("Cache-Control", "no-store")
+	sentAt := time.Now()
+	resp.ServerTime = sentAt.UTC().Format(time.RFC3339Nano)
+	resp.ServerTimeMs = unixMillis(sentAt)
+	response.Success(c, http.StatusOK, resp, nil)
+}
+
+// unixMillis переводит время в unix-миллисекунды с микросекундной дробной частью
+func unixMillis(t time.Time) float64 {
+	return float64(t.UnixMicro()) / 1000
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeHandler_GetTime(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/time", NewTimeHandler().GetTime)
+
+	before := float64(time.Now().UnixMicro()) / 1000
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/time?client_time=1737564125000.25", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+
+	var resp ServerTimeResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.ClientTime)
+	assert.Equal(t, 1737564125000.25, *resp.ClientTime)
+	assert.GreaterOrEqual(t, resp.ReceivedAtMs, before)
+	assert.GreaterOrEqual(t, resp.ServerTimeMs, resp.ReceivedAtMs)
+	serverTime, err := time.Parse(time.RFC3339Nano, resp.ServerTime)
+	require.NoError(t, err)
+	assert.InDelta(t, resp.ServerTimeMs, float64(serverTime.UnixMicro())/1000, 0.001)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/time?client_time=soon", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
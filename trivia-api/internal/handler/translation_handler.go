@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/pkg/i18n"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+// TranslationHandler обрабатывает управление переводами вопросов (админ-панель)
+type TranslationHandler struct {
+	translationService *service.TranslationService
+	assistService      *service.TranslationAssistService
+	auditService       *service.AuditService
+}
+
+// NewTranslationHandler создает новый обработчик переводов
+func NewTranslationHandler(translationService *service.TranslationService) *TranslationHandler {
+	return &TranslationHandler{translationService: translationService}
+}
+
+// SetAuditService подключает журнал аудита
+func (h *TranslationHandler) SetAuditService(auditService *service.AuditService) {
+	h.auditService = auditService
+}
+
+// SetAssistService подключает машинное предзаполнение переводов (translationAssist.enabled)
+func (h *TranslationHandler) SetAssistService(assistService *service.TranslationAssistService) {
+	h.assistService = assistService
+}
+
+// UpsertTranslationRequest — тело запроса перевода вопроса
+type UpsertTranslationRequest struct {
+	Text    string   `json:"text" binding:"required"`
+	Options []string `json:"options" binding:"required"`
+}
+
+// ListTranslations возвращает переводы вопроса и список поддерживаемых языков
+// GET /api/admin/questions/:id/translations
+func (h *TranslationHandler) ListTranslations(c *gin.Context) {
+	questionID := c.MustGet("questionID").(uint)
+
+	translations, err := h.translationService.ListTranslations(questionID)
+	if err != nil {
+		h.handleError(c, err, "получения переводов", questionID)
+		return
+	}
+	locales := h.translationService.Locales()
+	c.JSON(http.StatusOK, gin.H{
+		"items":             translations,
+		"default_locale":    locales.Default(),
+		"supported_locales": locales.Supported(),
+	})
+}
+
+// UpsertTranslation создаёт или заменяет перевод вопроса на язык
+// PUT /api/admin/questions/:id/translations/:locale
+func (h *TranslationHandler) UpsertTranslation(c *gin.Context) {
+	questionID := c.MustGet("questionID").(uint)
+
+	var req UpsertTranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Request body must contain text and options", "error_type": "validation_error"})
+		return
+	}
+
+	translation, err := h.translationService.UpsertTranslation(questionID, c.Param("locale"), req.Text, req.Options)
+	if err != nil {
+		h.handleError(c, err, "сохранения перевода", questionID)
+		return
+	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionTranslationUpsert,
+		TargetType: entity.AuditTargetQuestion,
+		TargetID:   strconv.FormatUint(uint64(questionID), 10),
+		After:      translation,
+	})
+	c.JSON(http.StatusOK, translation)
+}
+
+// AcceptTranslationRequest — правка машинного перевода при проверке; пустые поля оставляют машинный текст
+type AcceptTranslationRequest struct {
+	Text    string   `json:"text"`
+	Options []string `json:"options"`
+}
+
+// AcceptTranslation отмечает машинный перевод проверенным, при необходимости с правками
+// POST /api/admin/questions/:id/translations/:locale/accept
+func (h *TranslationHandler) AcceptTranslation(c *gin.Context) {
+	questionID := c.MustGet("questionID").(uint)
+
+	var req AcceptTranslationRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "error_type": "validation_error"})
+			return
+		}
+	}
+
+	translation, err := h.translationService.AcceptMachineTranslation(questionID, c.Param("locale"), c.MustGet("user_id").(uint), req.Text, req.Options)
+	if err != nil {
+		h.handleError(c, err, "проверки машинного перевода", questionID)
+		return
+	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionTranslationAccept,
+		TargetType: entity.AuditTargetQuestion,
+		TargetID:   strconv.FormatUint(uint64(questionID), 10),
+		After:      translation,
+		Metadata:   map[string]interface{}{"edited": req.Text != "" || req.Options != nil},
+	})
+	c.JSON(http.StatusOK, translation)
+}
+
+// ListMachineTranslations возвращает очередь непроверенных машинных переводов и состояние предзаполнения
+// GET /api/admin/translations/machine?locale=kk&page=1&page_size=20
+func (h *TranslationHandler) ListMachineTranslations(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	translations, total, err := h.translationService.ListMachineTranslated(c.DefaultQuery("locale", "kk"), page, pageSize)
+	if err != nil {
+		h.handleError(c, err, "получения машинных переводов", 0)
+		return
+	}
+	body := gin.H{
+		"items":     translations,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	}
+	if h.assistService != nil {
+		body["assist"] = h.assistService.Status()
+	}
+	c.JSON(http.StatusOK, body)
+}
+
+// RunMachineTranslation запускает проход машинного перевода, не дожидаясь расписания;
+// итоги — в assist.last_run очереди машинных переводов
+// POST /api/admin/translations/machine/run
+func (h *TranslationHandler) RunMachineTranslation(c *gin.Context) {
+	if err := h.assistService.Trigger(); err != nil {
+		h.handleError(c, err, "запуска машинного перевода", 0)
+		return
+	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionTranslationAssistRun,
+		TargetType: entity.AuditTargetSystem,
+	})
+	c.JSON(http.StatusAccepted, gin.H{"message": "Machine translation started"})
+}
+
+// DeleteTranslation удаляет перевод вопроса на язык
+// DELETE /api/admin/questions/:id/translations/:locale
+func (h *TranslationHandler) DeleteTranslation(c *gin.Context) {
+	questionID := c.MustGet("questionID").(uint)
+	locale := i18n.Normalize(c.Param("locale"))
+
+	if err := h.translationService.DeleteTranslation(questionID, locale); err != nil {
+		h.handleError(c, err, "удаления перевода", questionID)
+		return
+	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionTranslationDelete,
+		TargetType: entity.AuditTargetQuestion,
+		TargetID:   strconv.FormatUint(uint64(questionID), 10),
+		Metadata:   map[string]interface{}{"locale": locale},
+	})
+	c.JSON(http.StatusOK, gin.H{"message": "Translation deleted"})
+}
+
+func (h *TranslationHandler) handleError(c *gin.Context, err error, operation string, questionID uint) {
+	switch {
+	case errors.Is(err, apperrors.ErrValidation):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "error_type": "validation_error"})
+	case errors.Is(err, apperrors.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Question or translation not found", "error_type": "not_found"})
+	case errors.Is(err, apperrors.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "error_type": "conflict"})
+	default:
+		log.Printf("[TranslationHandler] Ошибка %s вопроса %d: %v", operation, questionID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error", "error_type": "internal_server_error"})
+	}
+}
+
+// negotiateLocale выбирает язык контента для запроса: ?lang=, язык из профиля
+// аутентифицированного пользователя, Accept-Language. Выбранный язык отдаётся
+// в заголовке Content-Language.
+func negotiateLocale(c *gin.Context, translationService *service.TranslationService) i18n.Resolution {
+	var userID uint
+	if value, ok := c.Get("user_id"); ok {
+		userID, _ = value.(uint)
+	}
+	resolution := translationService.NegotiateLocale(c.Query("lang"), userID, c.GetHeader("Accept-Language"))
+	c.Header("Content-Language", resolution.Locale)
+	c.Header("Vary", "Accept-Language")
+	return resolution
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/trivia-api/internal/handler/response"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+// UserAnalyticsHandler обрабатывает запросы личной статистики игрока
+type UserAnalyticsHandler struct {
+	userAnalyticsService *service.UserAnalyticsService
+}
+
+// NewUserAnalyticsHandler создает обработчик личной статистики
+func NewUserAnalyticsHandler(userAnalyticsService *service.UserAnalyticsService) *UserAnalyticsHandler {
+	return &UserAnalyticsHandler{userAnalyticsService: userAnalyticsService}
+}
+
+// GetMyAnalytics возвращает статистику текущего пользователя: точность по сложности и темам,
+// время ответа по неделям и вопросы, на которых он выбывал
+// GET /api/users/me/analytics?days=90
+func (h *UserAnalyticsHandler) GetMyAnalytics(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", strconv.Itoa(service.DefaultUserAnalyticsDays)))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "validation_error", "invalid days parameter")
+		return
+	}
+
+	analytics, err := h.userAnalyticsService.GetUserAnalytics(c.MustGet("user_id").(uint), days)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, analytics, nil)
+}
@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/yourusername/trivia-api/internal/handler/response"
 	"github.com/yourusername/trivia-api/internal/service"
 )
 
@@ -12,6 +13,7 @@ import (
 type UserHandler struct {
 	userService   *service.UserService
 	resultService *service.ResultService
+	ratingService *service.RatingService
 }
 
 // NewUserHandler создает новый обработчик пользователей
@@ -22,6 +24,11 @@ func NewUserHandler(userService *service.UserService, resultService *service.Res
 	}
 }
 
+// SetRatingService подключает лидерборд и историю рейтинга мастерства
+func (h *UserHandler) SetRatingService(ratingService *service.RatingService) {
+	h.ratingService = ratingService
+}
+
 // GetLeaderboard обрабатывает запрос на получение лидерборда
 func (h *UserHandler) GetLeaderboard(c *gin.Context) {
 	// Получаем параметры пагинации из query
@@ -40,23 +47,49 @@ func (h *UserHandler) GetLeaderboard(c *gin.Context) {
 		pageSize = 100 // Максимальный лимит
 	}
 
+	// Лидерборд по рейтингу мастерства: ?sort=rating
+	if sort := c.Query("sort"); sort != "" && sort != "wins" {
+		if sort != "rating" || h.ratingService == nil {
+			response.Error(c, http.StatusBadRequest, "invalid_sort", "sort must be wins or rating")
+			return
+		}
+		leaderboard, err := h.ratingService.GetLeaderboard(page, pageSize)
+		if err != nil {
+			response.FromError(c, err)
+			return
+		}
+		response.Success(c, http.StatusOK, leaderboard, nil)
+		return
+	}
+
+	// Пагинация курсором: ?cursor= (пустой для первой страницы) вместо ?page=
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		leaderboard, err := h.userService.GetLeaderboardByCursor(cursor, pageSize)
+		if err != nil {
+			response.FromError(c, err)
+			return
+		}
+		response.Success(c, http.StatusOK, leaderboard, nil)
+		return
+	}
+
 	// Вызываем сервис
 	leaderboard, err := h.userService.GetLeaderboard(page, pageSize)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error getting leaderboard"})
+		response.Error(c, http.StatusInternalServerError, "internal_server_error", nil)
 		return
 	}
 
-	c.JSON(http.StatusOK, leaderboard)
+	response.Success(c, http.StatusOK, leaderboard, nil)
 }
 
 // GetMyResults возвращает историю игр текущего пользователя
-// GET /api/users/me/results?page=1&page_size=10
+// GET /api/users/me/results?page=1&page_size=10 или ?cursor=&page_size=10
 func (h *UserHandler) GetMyResults(c *gin.Context) {
 	// Получаем user_id из контекста (установлен middleware RequireAuth)
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		response.Error(c, http.StatusUnauthorized, "unauthorized", nil)
 		return
 	}
 
@@ -79,19 +112,119 @@ func (h *UserHandler) GetMyResults(c *gin.Context) {
 	// Вызываем сервис
 	uid, ok := userID.(uint)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID type in context"})
+		response.Error(c, http.StatusInternalServerError, "internal_server_error", nil)
+		return
+	}
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		results, nextCursor, err := h.resultService.GetUserResultsByCursor(uid, cursor, pageSize)
+		if err != nil {
+			response.FromError(c, err)
+			return
+		}
+		body := gin.H{"results": results, "page_size": pageSize}
+		if nextCursor != "" {
+			body["next_cursor"] = nextCursor
+		}
+		response.Success(c, http.StatusOK, body, nil)
 		return
 	}
 	results, total, err := h.resultService.GetUserResults(uid, page, pageSize)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get game history"})
+		response.Error(c, http.StatusInternalServerError, "internal_server_error", nil)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	response.Success(c, http.StatusOK, gin.H{
 		"results":   results,
 		"total":     total,
 		"page":      page,
 		"page_size": pageSize,
+	}, nil)
+}
+
+// UpdatePrivacyRequest содержит изменяемые настройки приватности профиля
+type UpdatePrivacyRequest struct {
+	ProfilePublic      *bool `json:"profile_public"`
+	ShowRecentResults  *bool `json:"show_recent_results"`
+	ShowFullName       *bool `json:"show_full_name"`
+	ShowOnLeaderboards *bool `json:"show_on_leaderboards"`
+}
+
+// GetPublicProfile возвращает публичный профиль игрока с учётом его настроек приватности
+// GET /api/users/:id/profile
+func (h *UserHandler) GetPublicProfile(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid_request", "некорректный ID пользователя")
+		return
+	}
+	viewerID := c.MustGet("user_id").(uint)
+	isAdmin, _ := c.Get("is_admin")
+	viewerIsAdmin, _ := isAdmin.(bool)
+
+	profile, err := h.userService.GetPublicProfile(uint(userID), viewerID, viewerIsAdmin)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, profile, nil)
+}
+
+// UpdatePrivacy изменяет настройки приватности профиля, результатов и лидербордов текущего пользователя
+// PUT /api/users/me/privacy
+func (h *UserHandler) UpdatePrivacy(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	var req UpdatePrivacyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	user, err := h.userService.UpdatePrivacy(userID, service.UpdatePrivacyInput{
+		ProfilePublic:      req.ProfilePublic,
+		ShowRecentResults:  req.ShowRecentResults,
+		ShowFullName:       req.ShowFullName,
+		ShowOnLeaderboards: req.ShowOnLeaderboards,
 	})
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{
+		"profile_public":       user.ProfilePublic,
+		"show_recent_results":  user.ShowRecentResults,
+		"show_full_name":       user.ShowFullName,
+		"show_on_leaderboards": user.ShowOnLeaderboards,
+	}, nil)
+}
+
+// GetMyRatingHistory возвращает изменения рейтинга мастерства текущего пользователя
+// GET /api/users/me/rating/history?page=1&page_size=20
+func (h *UserHandler) GetMyRatingHistory(c *gin.Context) {
+	if h.ratingService == nil {
+		response.Error(c, http.StatusNotFound, "not_found", "rating is disabled")
+		return
+	}
+	userID := c.MustGet("user_id").(uint)
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	rating, err := h.ratingService.GetUserRating(userID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	history, total, err := h.ratingService.GetHistory(userID, page, pageSize)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{
+		"rating":    rating,
+		"history":   history,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	}, nil)
 }
@@ -0,0 +1,269 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/handler/response"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+// WalletHandler обрабатывает запросы кошелька и заявок на выплату
+type WalletHandler struct {
+	walletService *service.WalletService
+	auditService  *service.AuditService
+}
+
+// NewWalletHandler создает новый обработчик кошелька
+func NewWalletHandler(walletService *service.WalletService) *WalletHandler {
+	return &WalletHandler{walletService: walletService}
+}
+
+// SetAuditService подключает журнал аудита
+func (h *WalletHandler) SetAuditService(auditService *service.AuditService) {
+	h.auditService = auditService
+}
+
+// RequestPayoutRequest содержит данные заявки на выплату
+type RequestPayoutRequest struct {
+	Amount      int64  `json:"amount" binding:"required,gt=0"`
+	Method      string `json:"method" binding:"required,max=30"`
+	Destination string `json:"destination" binding:"required,max=255"`
+}
+
+// RejectPayoutRequest содержит причину отклонения заявки
+type RejectPayoutRequest struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+// MarkPayoutPaidRequest содержит внешний идентификатор платежа
+type MarkPayoutPaidRequest struct {
+	ExternalReference string `json:"external_reference" binding:"omitempty,max=255"`
+}
+
+// GetMyWallet возвращает баланс кошелька пользователя
+// GET /api/users/me/wallet
+func (h *WalletHandler) GetMyWallet(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	wallet, err := h.walletService.GetWallet(userID)
+	if err != nil {
+		h.handleWalletError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, wallet)
+}
+
+// GetMyTransactions возвращает историю операций по кошельку
+// GET /api/users/me/wallet/transactions?page=1&page_size=20
+func (h *WalletHandler) GetMyTransactions(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	entries, total, err := h.walletService.GetTransactions(userID, page, pageSize)
+	if err != nil {
+		h.handleWalletError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"transactions": entries,
+		"total":        total,
+		"page":         page,
+		"page_size":    pageSize,
+	})
+}
+
+// GetMyPayouts возвращает заявки пользователя на выплату
+// GET /api/users/me/wallet/payouts?page=1&page_size=20
+func (h *WalletHandler) GetMyPayouts(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	payouts, total, err := h.walletService.ListMyPayouts(userID, page, pageSize)
+	if err != nil {
+		h.handleWalletError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"payouts":   payouts,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	})
+}
+
+// RequestPayout создаёт заявку на выплату
+// POST /api/users/me/wallet/payouts
+func (h *WalletHandler) RequestPayout(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	var req RequestPayoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	payout, err := h.walletService.RequestPayout(userID, service.RequestPayoutInput{
+		Amount:      req.Amount,
+		Method:      req.Method,
+		Destination: req.Destination,
+	})
+	if err != nil {
+		h.handleWalletError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, payout)
+}
+
+// CancelPayout отменяет заявку пользователя до одобрения
+// POST /api/users/me/wallet/payouts/:id/cancel
+func (h *WalletHandler) CancelPayout(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	payoutID, ok := parsePayoutID(c)
+	if !ok {
+		return
+	}
+
+	payout, err := h.walletService.CancelPayout(userID, payoutID)
+	if err != nil {
+		h.handleWalletError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, payout)
+}
+
+// ListPayouts возвращает заявки на выплату для администратора
+// GET /api/admin/wallet/payouts?status=requested&page=1&page_size=20
+func (h *WalletHandler) ListPayouts(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	payouts, total, err := h.walletService.ListPayouts(c.Query("status"), page, pageSize)
+	if err != nil {
+		h.handleWalletError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"payouts":   payouts,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	})
+}
+
+// ApprovePayout одобряет заявку на выплату
+// POST /api/admin/wallet/payouts/:id/approve
+func (h *WalletHandler) ApprovePayout(c *gin.Context) {
+	adminID := c.MustGet("user_id").(uint)
+	payoutID, ok := parsePayoutID(c)
+	if !ok {
+		return
+	}
+
+	payout, err := h.walletService.ApprovePayout(adminID, payoutID)
+	if err != nil {
+		h.handleWalletError(c, err)
+		return
+	}
+	h.recordPayoutAudit(c, entity.AuditActionPayoutApprove, payout, nil)
+	c.JSON(http.StatusOK, payout)
+}
+
+// RejectPayout отклоняет заявку и возвращает средства на баланс
+// POST /api/admin/wallet/payouts/:id/reject
+func (h *WalletHandler) RejectPayout(c *gin.Context) {
+	adminID := c.MustGet("user_id").(uint)
+	payoutID, ok := parsePayoutID(c)
+	if !ok {
+		return
+	}
+
+	var req RejectPayoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	payout, err := h.walletService.RejectPayout(adminID, payoutID, req.Reason)
+	if err != nil {
+		h.handleWalletError(c, err)
+		return
+	}
+	h.recordPayoutAudit(c, entity.AuditActionPayoutReject, payout, map[string]interface{}{"reason": req.Reason})
+	c.JSON(http.StatusOK, payout)
+}
+
+// MarkPayoutPaid отмечает одобренную заявку выплаченной
+// POST /api/admin/wallet/payouts/:id/paid
+func (h *WalletHandler) MarkPayoutPaid(c *gin.Context) {
+	adminID := c.MustGet("user_id").(uint)
+	payoutID, ok := parsePayoutID(c)
+	if !ok {
+		return
+	}
+
+	var req MarkPayoutPaidRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	payout, err := h.walletService.MarkPayoutPaid(adminID, payoutID, req.ExternalReference)
+	if err != nil {
+		h.handleWalletError(c, err)
+		return
+	}
+	h.recordPayoutAudit(c, entity.AuditActionPayoutPaid, payout, map[string]interface{}{"external_reference": req.ExternalReference})
+	c.JSON(http.StatusOK, payout)
+}
+
+// GetReconciliation возвращает результат сверки журнала проводок
+// GET /api/admin/wallet/reconciliation
+func (h *WalletHandler) GetReconciliation(c *gin.Context) {
+	report, err := h.walletService.Reconcile()
+	if err != nil {
+		h.handleWalletError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// recordPayoutAudit записывает решение администратора по заявке на выплату
+func (h *WalletHandler) recordPayoutAudit(c *gin.Context, action string, payout *entity.PayoutRequest, metadata map[string]interface{}) {
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     action,
+		TargetType: entity.AuditTargetPayout,
+		TargetID:   strconv.FormatUint(uint64(payout.ID), 10),
+		After:      payout,
+		Metadata:   metadata,
+	})
+}
+
+func parsePayoutID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payout ID", "error_type": "validation_error"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func (h *WalletHandler) handleWalletError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrInsufficientFunds) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Insufficient funds", "error_type": "insufficient_funds"})
+	} else if errors.Is(err, apperrors.ErrValidation) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "error_type": "validation_error"})
+	} else if errors.Is(err, apperrors.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Payout request not found", "error_type": "not_found"})
+	} else if errors.Is(err, apperrors.ErrConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "error_type": "conflict"})
+	} else {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error", "error_type": "internal_server_error"})
+	}
+}
@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/handler/response"
+	"github.com/yourusername/trivia-api/internal/service"
+)
+
+// WebhookHandler управляет вебхуками партнёров (админ-панель)
+type WebhookHandler struct {
+	webhookService *service.WebhookService
+	auditService   *service.AuditService
+}
+
+// NewWebhookHandler создает обработчик вебхуков
+func NewWebhookHandler(webhookService *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhookService: webhookService}
+}
+
+// SetAuditService подключает журнал аудита
+func (h *WebhookHandler) SetAuditService(auditService *service.AuditService) {
+	h.auditService = auditService
+}
+
+// WebhookEndpointRequest — тело запроса создания и изменения адреса
+type WebhookEndpointRequest struct {
+	URL         string   `json:"url" binding:"required,max=500"`
+	Secret      string   `json:"secret" binding:"max=128"`
+	Events      []string `json:"events" binding:"required"`
+	Description string   `json:"description" binding:"max=255"`
+	Active      *bool    `json:"active"`
+}
+
+func (r WebhookEndpointRequest) input() service.WebhookEndpointInput {
+	active := true
+	if r.Active != nil {
+		active = *r.Active
+	}
+	return service.WebhookEndpointInput{
+		URL:         r.URL,
+		Secret:      r.Secret,
+		Events:      r.Events,
+		Description: r.Description,
+		Active:      active,
+	}
+}
+
+// ListEndpoints возвращает зарегистрированные адреса и доступные события
+// GET /api/admin/webhooks
+func (h *WebhookHandler) ListEndpoints(c *gin.Context) {
+	endpoints, err := h.webhookService.ListEndpoints()
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{
+		"items":  endpoints,
+		"events": entity.WebhookEvents,
+	}, nil)
+}
+
+// CreateEndpoint регистрирует адрес. Секрет подписи возвращается только в этом ответе.
+// POST /api/admin/webhooks
+func (h *WebhookHandler) CreateEndpoint(c *gin.Context) {
+	var req WebhookEndpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	endpoint, secret, err := h.webhookService.CreateEndpoint(req.input())
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionWebhookCreate,
+		TargetType: entity.AuditTargetWebhook,
+		TargetID:   strconv.FormatUint(uint64(endpoint.ID), 10),
+		After:      endpoint,
+	})
+	response.Success(c, http.StatusCreated, gin.H{
+		"endpoint": endpoint,
+		"secret":   secret,
+	}, nil)
+}
+
+// UpdateEndpoint заменяет настройки адреса; пустой secret оставляет прежний
+// PUT /api/admin/webhooks/:id
+func (h *WebhookHandler) UpdateEndpoint(c *gin.Context) {
+	endpointID := c.MustGet("endpointID").(uint)
+
+	var req WebhookEndpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, err)
+		return
+	}
+
+	before, err := h.webhookService.GetEndpoint(endpointID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	endpoint, err := h.webhookService.UpdateEndpoint(endpointID, req.input())
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionWebhookUpdate,
+		TargetType: entity.AuditTargetWebhook,
+		TargetID:   strconv.FormatUint(uint64(endpointID), 10),
+		Before:     before,
+		After:      endpoint,
+		Metadata:   map[string]interface{}{"secret_rotated": req.Secret != ""},
+	})
+	response.Success(c, http.StatusOK, endpoint, nil)
+}
+
+// DeleteEndpoint удаляет адрес вместе с журналом доставки
+// DELETE /api/admin/webhooks/:id
+func (h *WebhookHandler) DeleteEndpoint(c *gin.Context) {
+	endpointID := c.MustGet("endpointID").(uint)
+
+	before, err := h.webhookService.GetEndpoint(endpointID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	if err := h.webhookService.DeleteEndpoint(endpointID); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	recordAudit(c, h.auditService, service.AuditEntry{
+		Action:     entity.AuditActionWebhookDelete,
+		TargetType: entity.AuditTargetWebhook,
+		TargetID:   strconv.FormatUint(uint64(endpointID), 10),
+		Before:     before,
+	})
+	response.Success(c, http.StatusOK, gin.H{"message": "Webhook endpoint deleted"}, nil)
+}
+
+// ListDeliveries возвращает журнал доставки адреса
+// GET /api/admin/webhooks/:id/deliveries?status=failed&page=1&page_size=50
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	endpointID := c.MustGet("endpointID").(uint)
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "50"))
+
+	deliveries, total, err := h.webhookService.ListDeliveries(endpointID, c.Query("status"), page, pageSize)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, gin.H{
+		"items":     deliveries,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	}, nil)
+}
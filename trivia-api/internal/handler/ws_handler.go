@@ -1,19 +1,24 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	gorillaws "github.com/gorilla/websocket"
 	"github.com/yourusername/trivia-api/internal/config"
+	"github.com/yourusername/trivia-api/internal/pkg/tracing"
 	"github.com/yourusername/trivia-api/internal/service"
 	"github.com/yourusername/trivia-api/internal/websocket"
 	"github.com/yourusername/trivia-api/pkg/auth"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // WSHandler обрабатывает WebSocket соединения
@@ -24,6 +29,8 @@ type WSHandler struct {
 	jwtService  *auth.JWTService
 	wsConfig    config.WebSocketConfig // Конфигурация WebSocket для лимитов
 	upgrader    gorillaws.Upgrader     // Упгрейдер с origins из конфига
+	geoService  *service.GeoService    // nil — страна подключения к викторине не определяется
+	ipRisk      *service.IPRiskService // nil — адрес подключения к викторине не оценивается
 }
 
 // NewWSHandler создает новый обработчик WebSocket
@@ -72,29 +79,71 @@ func NewWSHandler(
 	return handler
 }
 
-// HandleConnection обрабатывает входящее WebSocket соединение
-func (h *WSHandler) HandleConnection(c *gin.Context) {
+// SetGeoService включает запись страны подключения к викторине (региональные ограничения призов)
+func (h *WSHandler) SetGeoService(geoService *service.GeoService) {
+	h.geoService = geoService
+}
+
+// SetIPRiskService включает оценку адреса подключения к викторине (VPN, прокси, дата-центры)
+func (h *WSHandler) SetIPRiskService(ipRisk *service.IPRiskService) {
+	h.ipRisk = ipRisk
+}
+
+// authenticateTicket проверяет тикет из параметра ?ticket=. Тикет общий для WebSocket
+// и потока событий SSE. При ошибке ответ клиенту уже отправлен.
+func (h *WSHandler) authenticateTicket(c *gin.Context) (websocket.ClientSession, bool) {
 	// Получаем тикет из запроса (?ticket=... а не ?token=...)
 	ticket := c.Query("ticket")
 	// НЕ логируем тикет - это секретные данные аутентификации
 
 	if ticket == "" {
-		// Попробуем также проверить параметр 'token' для обратной совместимости, если нужно
-		// ticket = c.Query("token")
-		// if ticket == "" {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing authentication ticket parameter"})
-		return
-		// }
+		return websocket.ClientSession{}, false
 	}
 
-	// Проверяем тикет с использованием специальной функции ParseWSTicket
-	claims, err := h.jwtService.ParseWSTicket(c.Request.Context(), ticket)
+	// Проверяем тикет; ключ подписи и время выдачи запоминаются для последующей проверки авторизации соединения
+	session, err := h.jwtService.ParseSessionTicket(c.Request.Context(), ticket)
 	if err != nil {
 		log.Printf("WebSocket: Invalid or expired ticket - %v", err)
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired ticket"})
+		return websocket.ClientSession{}, false
+	}
+	return session, true
+}
+
+// HandleConnection обрабатывает входящее WebSocket соединение
+func (h *WSHandler) HandleConnection(c *gin.Context) {
+	session, ok := h.authenticateTicket(c)
+	if !ok {
 		return
 	}
 
+	var err error
+
+	// Версия протокола (?v=2) согласуется до upgrade, чтобы неподдерживаемую версию отклонить обычным HTTP-ответом.
+	// Клиенты без параметра работают по протоколу v1 и могут согласовать версию позже сообщением client:hello.
+	requestedVersion := 0
+	if v := c.Query("v"); v != "" {
+		if requestedVersion, err = strconv.Atoi(v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid protocol version", "error_type": "invalid_protocol_version"})
+			return
+		}
+	}
+	protocolVersion, err := websocket.NegotiateProtocolVersion(requestedVersion)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":       err.Error(),
+			"error_type":  "unsupported_protocol_version",
+			"min_version": websocket.MinProtocolVersion,
+			"max_version": websocket.CurrentProtocolVersion,
+		})
+		return
+	}
+	var capabilities []string
+	if raw := c.Query("capabilities"); raw != "" {
+		capabilities = strings.Split(raw, ",")
+	}
+
 	// Устанавливаем соединение
 	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
@@ -103,7 +152,7 @@ func (h *WSHandler) HandleConnection(c *gin.Context) {
 		return
 	}
 
-	log.Printf("WebSocket: Connection upgraded for UserID: %d", claims.UserID)
+	log.Printf("WebSocket: Connection upgraded for UserID: %d", session.UserID)
 
 	// Создаем конфигурацию клиента из WebSocket config
 	clientConfig := websocket.ClientConfig{
@@ -112,13 +161,23 @@ func (h *WSHandler) HandleConnection(c *gin.Context) {
 		PongWait:       time.Duration(h.wsConfig.Limits.PongWait) * time.Second,
 		WriteWait:      time.Duration(h.wsConfig.Limits.WriteWait) * time.Second,
 		MaxMessageSize: int64(h.wsConfig.Limits.MaxMessageSize),
+		WriteBudget:    time.Duration(h.wsConfig.Fanout.WriteBudgetMs) * time.Millisecond,
+		MaxBatch:       h.wsConfig.Fanout.MaxBatch,
 	}
 
 	// Создаем нового клиента с конфигурацией из config.yaml
-	client := websocket.NewClientWithConfig(h.wsHub, conn, fmt.Sprintf("%d", claims.UserID), clientConfig)
+	client := websocket.NewClientWithConfig(h.wsHub, conn, fmt.Sprintf("%d", session.UserID), clientConfig)
+	client.SetRemoteIP(c.ClientIP())
+	client.SetSession(session)
+	h.wsManager.SetClientProtocol(client, protocolVersion, capabilities)
 
 	// Запускаем прослушивание сообщений
 	client.StartPumps(h.wsManager.HandleMessage)
+
+	// Клиенту, указавшему версию, сразу сообщаем результат согласования
+	if requestedVersion != 0 {
+		h.wsManager.SendHello(client)
+	}
 }
 
 // registerMessageHandlers регистрирует обработчики для различных типов сообщений
@@ -126,7 +185,8 @@ func (h *WSHandler) registerMessageHandlers() {
 	// Обработчик для события готовности пользователя
 	h.wsManager.RegisterHandler("user:ready", func(data json.RawMessage, client *websocket.Client) error {
 		var readyEvent struct {
-			QuizID uint `json:"quiz_id"`
+			QuizID  uint  `json:"quiz_id"`
+			LastSeq int64 `json:"last_seq"` // номер последнего полученного события (при переподключении)
 		}
 		// Ошибка парсинга - фатальна для этого сообщения
 		if err := json.Unmarshal(data, &readyEvent); err != nil {
@@ -147,6 +207,9 @@ func (h *WSHandler) registerMessageHandlers() {
 			// Можно отправить ошибку клиенту
 			h.wsManager.SendErrorToClient(client, "subscribe_error", fmt.Sprintf("Failed to subscribe to quiz %d", readyEvent.QuizID))
 			// return err // Не возвращаем ошибку, чтобы не закрывать соединение сразу
+		} else {
+			// Переподключившийся клиент получает пропущенные события текущего вопроса
+			h.wsManager.ReplayQuizEvents(client, readyEvent.QuizID, readyEvent.LastSeq)
 		}
 		// ===>>> КОНЕЦ ИЗМЕНЕНИЯ <<<===
 
@@ -161,6 +224,14 @@ func (h *WSHandler) registerMessageHandlers() {
 			log.Printf("[WSHandler] Ошибка при обработке HandleReadyEvent для пользователя %d, викторины %d: %v", userID, readyEvent.QuizID, err)
 			// Опционально: отправить ошибку клиенту
 			h.wsManager.SendErrorToClient(client, "ready_error", err.Error())
+		} else {
+			// Страна и оценка риска адреса подключения проверяются при подведении итогов
+			if h.geoService != nil {
+				go h.geoService.RecordQuizJoin(userID, readyEvent.QuizID, client.RemoteIP())
+			}
+			if h.ipRisk != nil {
+				go h.ipRisk.RecordQuizJoin(userID, readyEvent.QuizID, client.RemoteIP())
+			}
 		}
 		return nil // Возвращаем nil, чтобы не закрывать соединение
 	})
@@ -185,13 +256,25 @@ func (h *WSHandler) registerMessageHandlers() {
 			return err // Ошибка парсинга ID фатальна
 		}
 
-		// Вызываем QuizManager, логируем ошибку, но не закрываем соединение
-		if err := h.quizManager.ProcessAnswer(
+		// Вызываем QuizManager; каждый ответ — отдельный трейс, т.к. WS-соединение живёт дольше любого запроса
+		ctx, span := tracing.Tracer().Start(context.Background(), "WS user:answer",
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.Int64("enduser.id", int64(userID)),
+				attribute.Int64("quiz.question_id", int64(answerEvent.QuestionID)),
+			),
+		)
+		err = h.quizManager.ProcessAnswer(
+			ctx,
 			userID,
 			answerEvent.QuestionID,
 			answerEvent.SelectedOption,
 			answerEvent.Timestamp,
-		); err != nil {
+		)
+		tracing.EndSpan(span, err)
+
+		// Логируем ошибку, но не закрываем соединение
+		if err != nil {
 			log.Printf("[WSHandler] Ошибка при обработке ProcessAnswer для пользователя %d, вопроса %d: %v", userID, answerEvent.QuestionID, err)
 			// Отправляем специфичную ошибку клиенту
 			h.wsManager.SendErrorToClient(client, "answer_error", err.Error())
@@ -199,6 +282,27 @@ func (h *WSHandler) registerMessageHandlers() {
 		return nil // Возвращаем nil, чтобы не закрывать соединение
 	})
 
+	// Подтверждение получения вопроса: от него отсчитывается персональный дедлайн игрока,
+	// который сервер присылает в quiz:question_deadline
+	h.wsManager.RegisterHandler("user:question_received", func(data json.RawMessage, client *websocket.Client) error {
+		var ackEvent struct {
+			QuestionID uint `json:"question_id"`
+		}
+		if err := json.Unmarshal(data, &ackEvent); err != nil || ackEvent.QuestionID == 0 {
+			h.wsManager.SendErrorToClient(client, "invalid_format", "user:question_received requires question_id")
+			return nil
+		}
+		userID, err := h.parseUserID(client)
+		if err != nil {
+			return err
+		}
+		if err := h.quizManager.AcknowledgeQuestion(context.Background(), userID, ackEvent.QuestionID); err != nil {
+			// Опоздавшее подтверждение не мешает ответу: дедлайн игрока остаётся общим
+			log.Printf("[WSHandler] Подтверждение вопроса %d от пользователя %d не принято: %v", ackEvent.QuestionID, userID, err)
+		}
+		return nil
+	})
+
 	// Обработчик для проверки соединения
 	h.wsManager.RegisterHandler("user:heartbeat", func(data json.RawMessage, client *websocket.Client) error {
 		// Отправляем ответ клиенту
@@ -212,6 +316,32 @@ func (h *WSHandler) registerMessageHandlers() {
 		return nil // Никогда не закрываем соединение из-за heartbeat
 	})
 
+	// Синхронизация часов: клиент присылает своё время, сервер возвращает его вместе
+	// с моментами приёма и отправки (unix ms с дробной частью), клиент считает смещение по NTP
+	h.wsManager.RegisterHandler("user:time_sync", func(data json.RawMessage, client *websocket.Client) error {
+		receivedAt := time.Now()
+		var syncEvent struct {
+			ID         string  `json:"id"`
+			ClientTime float64 `json:"client_time"`
+		}
+		if err := json.Unmarshal(data, &syncEvent); err != nil || syncEvent.ClientTime <= 0 {
+			h.wsManager.SendErrorToClient(client, "invalid_format", "user:time_sync requires client_time")
+			return nil
+		}
+		syncResponse := map[string]interface{}{
+			"client_time":         syncEvent.ClientTime,
+			"server_receive_time": unixMillis(receivedAt),
+			"server_send_time":    unixMillis(time.Now()),
+		}
+		if syncEvent.ID != "" {
+			syncResponse["id"] = syncEvent.ID
+		}
+		if err := h.wsManager.SendEventToUser(client.UserID, "server:time_sync", syncResponse); err != nil {
+			log.Printf("[WSHandler] WARNING: Ошибка при отправке server:time_sync пользователю %s: %v", client.UserID, err)
+		}
+		return nil
+	})
+
 	// Обработчик для resync (восстановление состояния после reconnect)
 	h.wsManager.RegisterHandler("user:resync", func(data json.RawMessage, client *websocket.Client) error {
 		var resyncEvent struct {
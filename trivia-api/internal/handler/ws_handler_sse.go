@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/trivia-api/internal/websocket"
+)
+
+const (
+	// sseRetryMs — пауза перед переподключением, которую сервер предлагает EventSource
+	sseRetryMs = 3000
+	// sseDefaultKeepAlive — интервал keep-alive, если в конфигурации он не задан
+	sseDefaultKeepAlive = 15 * time.Second
+	// sseDefaultWriteWait — тайм-аут записи одного события, если в конфигурации он не задан
+	sseDefaultWriteWait = 10 * time.Second
+)
+
+// HandleQuizEvents отдаёт события викторины потоком Server-Sent Events — запасной канал
+// только для чтения для сетей, где WebSocket заблокирован. Аутентификация — тот же тикет, что и для /ws.
+// Переподключение: номер последнего события передаётся заголовком Last-Event-ID
+// или параметром ?last_event_id=, пропущенные события повторяются из журнала.
+// GET /api/quizzes/:id/events?ticket=...
+func (h *WSHandler) HandleQuizEvents(c *gin.Context) {
+	if !h.wsConfig.SSE.Enabled {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Event stream is disabled", "error_type": "sse_disabled"})
+		return
+	}
+	session, ok := h.authenticateTicket(c)
+	if !ok {
+		return
+	}
+	quizID := c.MustGet("quizID").(uint)
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	var lastSeq int64
+	if lastEventID != "" {
+		var err error
+		if lastSeq, err = strconv.ParseInt(lastEventID, 10, 64); err != nil || lastSeq < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Last-Event-ID", "error_type": "validation_error"})
+			return
+		}
+	}
+
+	stream, err := h.wsManager.SubscribeQuizStream(quizID, lastSeq)
+	if err != nil {
+		log.Printf("[SSE] Ошибка подписки пользователя %d на события викторины %d: %v", session.UserID, quizID, err)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Event stream is unavailable", "error_type": "sse_unavailable"})
+		return
+	}
+	defer stream.Close()
+
+	keepAlive := time.Duration(h.wsConfig.SSE.KeepAliveSec) * time.Second
+	if keepAlive <= 0 {
+		keepAlive = sseDefaultKeepAlive
+	}
+	writeWait := time.Duration(h.wsConfig.Limits.WriteWait) * time.Second
+	if writeWait <= 0 {
+		writeWait = sseDefaultWriteWait
+	}
+
+	// Поток живёт дольше WriteTimeout сервера: дедлайн выставляется на каждую запись,
+	// чтобы зависший клиент не держал соединение бесконечно
+	controller := http.NewResponseController(c.Writer)
+	write := func(payload string) bool {
+		if err := controller.SetWriteDeadline(time.Now().Add(writeWait)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return false
+		}
+		if _, err := io.WriteString(c.Writer, payload); err != nil {
+			return false
+		}
+		c.Writer.Flush()
+		return true
+	}
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no") // nginx не должен буферизовать поток
+	c.Status(http.StatusOK)
+	if !write(fmt.Sprintf("retry: %d\n\n", sseRetryMs)) {
+		return
+	}
+	log.Printf("[SSE] Пользователь %d подписан на поток событий викторины %d (после seq %d)", session.UserID, quizID, lastSeq)
+
+	ticker := time.NewTicker(keepAlive)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-stream.Events():
+			if !ok {
+				// Подписчик не успевал получать события — клиент переподключится с Last-Event-ID
+				log.Printf("[SSE] Поток событий викторины %d для пользователя %d закрыт сервером", quizID, session.UserID)
+				return
+			}
+			if !write(formatSSEEvent(event)) {
+				return
+			}
+		case <-ticker.C:
+			if !write(": keep-alive\n\n") {
+				return
+			}
+		}
+	}
+}
+
+var sseLineBreaks = strings.NewReplacer("\r", "", "\n", "")
+
+// formatSSEEvent кодирует событие в формат text/event-stream. Поле id задаётся только для
+// журналируемых событий, чтобы Last-Event-ID всегда указывал на позицию в журнале.
+func formatSSEEvent(event websocket.StreamEvent) string {
+	payload := ""
+	if event.Seq > 0 {
+		payload += fmt.Sprintf("id: %d\n", event.Seq)
+	}
+	// Переводы строк в JSON допустимы только как пробельные символы (в строках они
+	// экранированы), поэтому их удаление не меняет данные, но не разрывает поле data
+	data := sseLineBreaks.Replace(string(event.Data))
+	return payload + fmt.Sprintf("event: %s\ndata: %s\n\n", event.Type, data)
+}
@@ -0,0 +1,163 @@
+//go:build integration
+
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/handler"
+	"github.com/yourusername/trivia-api/internal/middleware"
+	"github.com/yourusername/trivia-api/pkg/auth/manager"
+)
+
+// newAuthServer поднимает HTTP-сервер с маршрутами /api/auth, как в cmd/api (без rate limit)
+func newAuthServer(t *testing.T, a *app) *httptest.Server {
+	t.Helper()
+	// Куки без Secure, как при GIN_MODE=debug: тестовый сервер работает по http
+	a.tokenManager.SetProductionMode(false)
+	authHandler := handler.NewAuthHandler(a.authService, a.tokenManager, a.wsHub)
+	authMiddleware := middleware.NewAuthMiddlewareWithManager(a.jwtService, a.tokenManager)
+
+	router := gin.New()
+	authGroup := router.Group("/api/auth")
+	authGroup.POST("/register", authHandler.Register)
+	authGroup.POST("/login", authHandler.Login)
+	authGroup.POST("/refresh", authHandler.RefreshToken)
+	authed := authGroup.Group("", authMiddleware.RequireAuth())
+	authed.POST("/ws-ticket", authMiddleware.RequireCSRF(), authHandler.GenerateWsTicket)
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// tokenResponse — поля ответа register, login и refresh, нужные тесту
+type tokenResponse struct {
+	AccessToken string `json:"accessToken"`
+	CSRFToken   string `json:"csrfToken"`
+	UserID      uint   `json:"userId"`
+}
+
+// postJSON отправляет POST с JSON-телом и декодирует ответ в out, проверяя код статуса
+func postJSON(t *testing.T, client *http.Client, url string, body interface{}, csrfToken string, wantStatus int, out interface{}) {
+	t.Helper()
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		require.NoError(t, err)
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if csrfToken != "" {
+		req.Header.Set(manager.CSRFHeader, csrfToken)
+	}
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var raw json.RawMessage
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&raw))
+	require.Equal(t, wantStatus, resp.StatusCode, "POST %s: %s", url, raw)
+	if out != nil {
+		require.NoError(t, json.Unmarshal(raw, out))
+	}
+}
+
+func TestAuthFlow(t *testing.T) {
+	a := newApp(t)
+	server := newAuthServer(t, a)
+	jar, err := cookiejar.New(nil)
+	require.NoError(t, err)
+	client := &http.Client{Jar: jar, Timeout: 10 * time.Second}
+
+	suffix := time.Now().UnixNano() % 1e9
+	email := fmt.Sprintf("player%d@example.com", suffix)
+	const password = "Secret12345"
+
+	// Регистрация сразу выдаёт пару токенов и ставит куки
+	var registered tokenResponse
+	postJSON(t, client, server.URL+"/api/auth/register", map[string]interface{}{
+		"username":         fmt.Sprintf("player%d", suffix),
+		"email":            email,
+		"password":         password,
+		"first_name":       "Айдар",
+		"last_name":        "Серикович",
+		"birth_date":       "1995-06-15",
+		"gender":           "male",
+		"tos_accepted":     true,
+		"privacy_accepted": true,
+	}, "", http.StatusCreated, &registered)
+	require.NotZero(t, registered.UserID)
+	assert.NotEmpty(t, registered.AccessToken)
+
+	var registeredEvents int64
+	require.NoError(t, testDB.Model(&entity.OutboxEvent{}).
+		Where("event_type = ? AND (payload->>'user_id')::bigint = ?", entity.EventUserRegistered, registered.UserID).
+		Count(&registeredEvents).Error)
+	assert.EqualValues(t, 1, registeredEvents, "событие регистрации записано в outbox")
+
+	// Вход с новой сессией: у пользователя две активные refresh-сессии
+	var loggedIn tokenResponse
+	postJSON(t, client, server.URL+"/api/auth/login", map[string]string{
+		"email":    email,
+		"password": password,
+	}, "", http.StatusOK, &loggedIn)
+	assert.Equal(t, registered.UserID, loggedIn.UserID)
+	require.NotEmpty(t, loggedIn.CSRFToken)
+	assert.Equal(t, 2, countActiveSessions(t, loggedIn.UserID))
+
+	// Без CSRF-заголовка refresh отклоняется
+	postJSON(t, client, server.URL+"/api/auth/refresh", nil, "", http.StatusForbidden, nil)
+
+	// Refresh ротирует токен: сессия заменяется, а не добавляется
+	var refreshed tokenResponse
+	postJSON(t, client, server.URL+"/api/auth/refresh", nil, loggedIn.CSRFToken, http.StatusOK, &refreshed)
+	require.NotEmpty(t, refreshed.AccessToken)
+	assert.NotEqual(t, loggedIn.AccessToken, refreshed.AccessToken)
+	assert.Equal(t, 2, countActiveSessions(t, loggedIn.UserID))
+
+	// WS-тикет выдаётся по кукам обновлённой сессии и подписан ключом из БД
+	var ticket struct {
+		Success bool `json:"success"`
+		Data    struct {
+			Ticket string `json:"ticket"`
+		} `json:"data"`
+	}
+	postJSON(t, client, server.URL+"/api/auth/ws-ticket", nil, refreshed.CSRFToken, http.StatusOK, &ticket)
+	require.True(t, ticket.Success)
+	claims, err := a.jwtService.ParseWSTicket(context.Background(), ticket.Data.Ticket)
+	require.NoError(t, err)
+	assert.Equal(t, loggedIn.UserID, claims.UserID)
+
+	// Неверный пароль не создаёт сессию
+	postJSON(t, client, server.URL+"/api/auth/login", map[string]string{
+		"email":    email,
+		"password": "Wrong12345",
+	}, "", http.StatusUnauthorized, nil)
+	assert.Equal(t, 2, countActiveSessions(t, loggedIn.UserID))
+}
+
+// countActiveSessions считает неотозванные refresh-токены пользователя
+func countActiveSessions(t *testing.T, userID uint) int {
+	t.Helper()
+	var count int64
+	require.NoError(t, testDB.Model(&entity.RefreshToken{}).
+		Where("user_id = ? AND is_expired = false AND expires_at > ?", userID, time.Now()).
+		Count(&count).Error)
+	return int(count)
+}
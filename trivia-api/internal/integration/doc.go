@@ -0,0 +1,12 @@
+// Package integration содержит интеграционные тесты API на настоящих PostgreSQL и Redis.
+// Контейнеры поднимает dockertest, схема создаётся миграциями из каталога migrations,
+// а сервисы собираются из тех же репозиториев, что и в cmd/api. Тесты покрывают
+// сквозные сценарии (регистрация → вход → обновление токенов → WS-тикет, викторина от
+// планирования до результатов), в которых важны SQL, транзакции и ключи Redis, а не моки.
+//
+// Тесты собираются только с тегом integration и требуют доступного Docker:
+//
+//	go test -tags integration ./internal/integration/...
+//
+// или make test-integration.
+package integration
@@ -0,0 +1,265 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/ory/dockertest/v3"
+	"github.com/ory/dockertest/v3/docker"
+	"gorm.io/gorm"
+
+	"github.com/yourusername/trivia-api/internal/config"
+	"github.com/yourusername/trivia-api/internal/pkg/envelope"
+	pgRepo "github.com/yourusername/trivia-api/internal/repository/postgres"
+	redisRepo "github.com/yourusername/trivia-api/internal/repository/redis"
+	"github.com/yourusername/trivia-api/internal/service"
+	"github.com/yourusername/trivia-api/internal/service/quizmanager"
+	ws "github.com/yourusername/trivia-api/internal/websocket"
+	"github.com/yourusername/trivia-api/pkg/auth"
+	"github.com/yourusername/trivia-api/pkg/auth/manager"
+	"github.com/yourusername/trivia-api/pkg/database"
+)
+
+const (
+	postgresImage = "13-alpine" // как в docker-compose.yml
+	redisImage    = "7-alpine"
+
+	// Ключ шифрования JWT-ключей в БД (32 байта в hex), только для тестового окружения
+	testJWTKeyEncryptionKey = "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+)
+
+// Окружение, общее для всех тестов пакета: контейнеры поднимаются один раз в TestMain.
+// Тесты не очищают БД между собой и создают собственные данные (уникальные email, свои викторины).
+var (
+	testCfg   *config.Config
+	testDB    *gorm.DB
+	testRedis redis.UniversalClient
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	os.Exit(runIntegration(m))
+}
+
+// runIntegration поднимает PostgreSQL и Redis, применяет миграции и запускает тесты.
+// Контейнеры удаляются по завершении; если процесс упадёт, Docker удалит их по таймауту.
+func runIntegration(m *testing.M) int {
+	pool, err := dockertest.NewPool("")
+	if err != nil {
+		log.Printf("integration: docker is not available: %v", err)
+		return 1
+	}
+	if err := pool.Client.Ping(); err != nil {
+		log.Printf("integration: docker is not reachable: %v", err)
+		return 1
+	}
+	pool.MaxWait = 2 * time.Minute
+
+	postgres, err := startContainer(pool, &dockertest.RunOptions{
+		Repository: "postgres",
+		Tag:        postgresImage,
+		Env:        []string{"POSTGRES_USER=trivia", "POSTGRES_PASSWORD=trivia", "POSTGRES_DB=trivia_test"},
+	})
+	if err != nil {
+		log.Printf("integration: %v", err)
+		return 1
+	}
+	defer pool.Purge(postgres)
+
+	redisContainer, err := startContainer(pool, &dockertest.RunOptions{Repository: "redis", Tag: redisImage})
+	if err != nil {
+		log.Printf("integration: %v", err)
+		return 1
+	}
+	defer pool.Purge(redisContainer)
+
+	// Конфигурация та же, что у API (config/config.yaml и значения по умолчанию); подключения — к контейнерам
+	pgHost, pgPort, _ := net.SplitHostPort(postgres.GetHostPort("5432/tcp"))
+	env := map[string]string{
+		"GIN_MODE":                  "debug",
+		"DATABASE_HOST":             pgHost,
+		"DATABASE_PORT":             pgPort,
+		"DATABASE_USER":             "trivia",
+		"DATABASE_PASSWORD":         "trivia",
+		"DATABASE_DBNAME":           "trivia_test",
+		"DATABASE_SSLMODE":          "disable",
+		"DATABASE_LOG_LEVEL":        "warn",
+		"REDIS_ADDR":                redisContainer.GetHostPort("6379/tcp"),
+		"DB_JWT_KEY_ENCRYPTION_KEY": testJWTKeyEncryptionKey,
+	}
+	for key, value := range env {
+		os.Setenv(key, value)
+	}
+	testCfg, err = config.Load("../../config/config.yaml")
+	if err != nil {
+		log.Printf("integration: %v", err)
+		return 1
+	}
+
+	if err := pool.Retry(func() error {
+		db, err := database.NewPostgresDB(testCfg.Database)
+		if err != nil {
+			return err
+		}
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		if err := sqlDB.Ping(); err != nil {
+			return err
+		}
+		testDB = db
+		return nil
+	}); err != nil {
+		log.Printf("integration: postgres did not start: %v", err)
+		return 1
+	}
+	if err := pool.Retry(func() error {
+		client, err := database.NewUniversalRedisClient(testCfg.Redis)
+		if err != nil {
+			return err
+		}
+		testRedis = client
+		return nil
+	}); err != nil {
+		log.Printf("integration: redis did not start: %v", err)
+		return 1
+	}
+
+	migrator, err := database.NewMigrator(testDB, "../../migrations")
+	if err != nil {
+		log.Printf("integration: %v", err)
+		return 1
+	}
+	if err := migrator.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		log.Printf("integration: migrations failed: %v", err)
+		return 1
+	}
+
+	return m.Run()
+}
+
+// startContainer запускает контейнер, который Docker удалит после остановки или через 10 минут
+func startContainer(pool *dockertest.Pool, opts *dockertest.RunOptions) (*dockertest.Resource, error) {
+	resource, err := pool.RunWithOptions(opts, func(hc *docker.HostConfig) {
+		hc.AutoRemove = true
+		hc.RestartPolicy = docker.RestartPolicy{Name: "no"}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("start %s:%s: %w", opts.Repository, opts.Tag, err)
+	}
+	if err := resource.Expire(600); err != nil {
+		return nil, err
+	}
+	return resource, nil
+}
+
+// app — сервисы API, собранные как в cmd/api поверх контейнеров окружения
+type app struct {
+	userRepo     *pgRepo.UserRepo
+	quizRepo     *pgRepo.QuizRepo
+	questionRepo *pgRepo.QuestionRepo
+	resultRepo   *pgRepo.ResultRepo
+
+	tokenManager  *manager.TokenManager
+	jwtService    *auth.JWTService
+	authService   *service.AuthService
+	quizService   *service.QuizService
+	reviewService *service.QuestionReviewService
+	resultService *service.ResultService
+	quizManager   *service.QuizManager
+	wsHub         ws.HubInterface
+}
+
+// newApp собирает сервисы; фоновые горутины останавливаются по завершении теста
+func newApp(t *testing.T) *app {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	a := &app{
+		userRepo:     pgRepo.NewUserRepo(testDB),
+		quizRepo:     pgRepo.NewQuizRepo(testDB),
+		questionRepo: pgRepo.NewQuestionRepo(testDB),
+		resultRepo:   pgRepo.NewResultRepo(testDB),
+	}
+	cacheRepo, err := redisRepo.NewCacheRepo(testRedis)
+	mustNoError(t, err)
+	refreshTokenRepo, err := pgRepo.NewRefreshTokenRepo(testDB)
+	mustNoError(t, err)
+	jwtKeyring, err := envelope.NewKeyring(testCfg.JWT.DBJWTKeyEncryptionKeyVersion, testCfg.JWT.DBKeyEncryptionKeys())
+	mustNoError(t, err)
+	jwtKeyRepo, err := pgRepo.NewPostgresJWTKeyRepository(testDB, jwtKeyring)
+	mustNoError(t, err)
+	invalidTokenRepo := pgRepo.NewInvalidTokenRepo(testDB)
+
+	a.tokenManager, err = manager.NewTokenManager(refreshTokenRepo, a.userRepo, jwtKeyRepo)
+	mustNoError(t, err)
+	a.jwtService, err = auth.NewJWTService(testCfg.JWT.ExpirationHrs, invalidTokenRepo, testCfg.JWT.WSTicketExpirySec,
+		testCfg.JWT.CleanupInterval, a.tokenManager, &ws.NoOpPubSub{}, ctx)
+	mustNoError(t, err)
+	a.tokenManager.SetJWTService(a.jwtService)
+
+	a.authService, err = service.NewAuthService(a.userRepo, a.jwtService, a.tokenManager, refreshTokenRepo, invalidTokenRepo,
+		pgRepo.NewUserLegalAcceptanceRepo(testDB))
+	mustNoError(t, err)
+	a.authService.SetLegalVersions(testCfg.Legal.TOSVersion, testCfg.Legal.PrivacyVersion)
+	a.authService.SetEmailVerificationRepository(pgRepo.NewEmailVerificationRepo(testDB))
+	a.authService.SetIdentityRepository(pgRepo.NewUserIdentityRepo(testDB))
+
+	// Доменные события пишутся в outbox; диспетчер не запускается, тесты проверяют сами записи
+	eventBus := service.NewEventBus(pgRepo.NewOutboxRepo(testDB), service.EventBusConfig{BatchSize: 100, MaxAttempts: 3})
+	a.authService.SetEventBus(eventBus)
+
+	shardedHub := ws.NewShardedHub(testCfg.WebSocket, &ws.NoOpPubSub{}, cacheRepo)
+	go shardedHub.Run()
+	a.wsHub = shardedHub
+	wsManager := ws.NewManager(shardedHub)
+
+	quizConfig := quizmanager.DefaultConfig()
+	a.quizService = service.NewQuizService(a.quizRepo, a.questionRepo, cacheRepo, quizConfig, testDB)
+	a.reviewService = service.NewQuestionReviewService(a.questionRepo, pgRepo.NewQuestionReviewRepo(testDB), a.userRepo)
+	a.reviewService.SetQuizCache(a.quizService)
+	a.resultService = service.NewResultService(a.resultRepo, a.userRepo, a.quizRepo, a.questionRepo,
+		pgRepo.NewAnalyticsRepo(testDB), cacheRepo, testDB, wsManager, quizConfig)
+	a.resultService.SetEventBus(eventBus)
+	a.quizManager = service.NewQuizManager(a.quizRepo, a.questionRepo, a.resultRepo, a.resultService, cacheRepo, wsManager,
+		testDB, pgRepo.NewQuizAdSlotRepository(testDB))
+	t.Cleanup(a.quizManager.Shutdown)
+	return a
+}
+
+// mustNoError останавливает тест при ошибке сборки окружения
+func mustNoError(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// eventually повторяет check, пока он не вернёт nil, не дольше timeout
+func eventually(t *testing.T, timeout time.Duration, check func() error) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for {
+		err := check()
+		if err == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("condition not met within %v: %v", timeout, err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
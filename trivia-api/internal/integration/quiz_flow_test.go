@@ -0,0 +1,214 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/seed"
+	"github.com/yourusername/trivia-api/internal/service"
+	"github.com/yourusername/trivia-api/internal/service/quizmanager"
+)
+
+//go:embed testdata/quiz.json
+var quizFixtureJSON []byte
+
+// quizFixture — викторина из testdata/quiz.json
+type quizFixture struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	PrizeFund   int    `json:"prize_fund"`
+	Questions   []struct {
+		Text          string   `json:"text"`
+		Options       []string `json:"options"`
+		CorrectOption int      `json:"correct_option"`
+		TimeLimitSec  int      `json:"time_limit_sec"`
+		Difficulty    int      `json:"difficulty"`
+	} `json:"questions"`
+}
+
+// Тайминги для теста: без анонсов и зала ожидания, секундный отсчёт и короткие паузы
+var fastTiming = quizmanager.Timing{
+	CountdownSeconds:     1,
+	QuestionDelayMs:      100,
+	AnswerRevealDelayMs:  100,
+	InterQuestionDelayMs: 300,
+}
+
+// demoPlayers создаёт демо-аккаунты пакета seed и возвращает администратора и count игроков
+func demoPlayers(t *testing.T, count int) (entity.User, []entity.User) {
+	t.Helper()
+	require.NoError(t, seed.Users(testDB, count, "demo12345"))
+	var admin entity.User
+	require.NoError(t, testDB.Where("username = ?", "demo_admin").First(&admin).Error)
+	players := make([]entity.User, count)
+	for i := range players {
+		require.NoError(t, testDB.Where("username = ?", fmt.Sprintf("demo_player_%d", i+1)).First(&players[i]).Error)
+	}
+	return admin, players
+}
+
+// createFixtureQuiz создаёт викторину из фикстуры и проводит её вопросы через проверку
+func createFixtureQuiz(t *testing.T, a *app, adminID uint) *entity.Quiz {
+	t.Helper()
+	var fixture quizFixture
+	require.NoError(t, json.Unmarshal(quizFixtureJSON, &fixture))
+
+	quiz, err := a.quizService.CreateQuiz(fixture.Title, fixture.Description, time.Now().Add(time.Hour),
+		fixture.PrizeFund, false, entity.QuizQuestionSourceAdminOnly)
+	require.NoError(t, err)
+
+	questions := make([]entity.Question, 0, len(fixture.Questions))
+	for _, q := range fixture.Questions {
+		questions = append(questions, entity.Question{
+			Text:          q.Text,
+			Options:       entity.StringArray(q.Options),
+			CorrectOption: q.CorrectOption,
+			TimeLimitSec:  q.TimeLimitSec,
+			PointValue:    10,
+			Difficulty:    q.Difficulty,
+		})
+	}
+	require.NoError(t, a.quizService.AddQuestions(quiz.ID, questions))
+
+	stored, err := a.questionRepo.GetByQuizID(quiz.ID)
+	require.NoError(t, err)
+	require.Len(t, stored, len(fixture.Questions))
+	for _, q := range stored {
+		require.Equal(t, entity.QuestionReviewDraft, q.ReviewStatus, "новые вопросы попадают в черновики")
+		_, err := a.reviewService.Submit(adminID, q.ID, "")
+		require.NoError(t, err)
+		_, err = a.reviewService.Approve(adminID, q.ID, "")
+		require.NoError(t, err)
+	}
+	return quiz
+}
+
+func TestQuizFlow(t *testing.T) {
+	a := newApp(t)
+	a.quizManager.SetTiming(fastTiming)
+	admin, players := demoPlayers(t, 3)
+	winner, wrong, silent := players[0], players[1], players[2]
+
+	var statsBefore entity.User
+	require.NoError(t, testDB.First(&statsBefore, winner.ID).Error)
+
+	quiz := createFixtureQuiz(t, a, admin.ID)
+	stored, err := a.questionRepo.GetByQuizID(quiz.ID)
+	require.NoError(t, err)
+	correct := make(map[uint]int, len(stored))
+	for _, q := range stored {
+		correct[q.ID] = q.CorrectOption
+	}
+
+	require.NoError(t, a.quizManager.ScheduleQuiz(quiz.ID, time.Now().Add(2*time.Second)))
+	for _, p := range players {
+		require.NoError(t, a.quizManager.HandleReadyEvent(p.ID, quiz.ID))
+	}
+
+	answer := func(userID, questionID uint, option int) {
+		t.Helper()
+		require.NoError(t, a.quizManager.ProcessAnswer(context.Background(), userID, questionID, option, time.Now().UnixMilli()))
+	}
+
+	// Вопрос 1: «wrong» ошибается, остальные отвечают верно
+	q1 := waitForQuestion(t, a, winner.ID, quiz.ID, 1)
+	answer(winner.ID, q1, correct[q1])
+	answer(wrong.ID, q1, (correct[q1]+1)%4)
+	answer(silent.ID, q1, correct[q1])
+
+	// Вопрос 2: «silent» не отвечает и выбывает по таймауту
+	q2 := waitForQuestion(t, a, winner.ID, quiz.ID, 2)
+	answer(winner.ID, q2, correct[q2])
+
+	var results []entity.Result
+	eventually(t, 60*time.Second, func() error {
+		completed, err := a.quizRepo.GetByID(quiz.ID)
+		if err != nil {
+			return err
+		}
+		if completed.Status != entity.QuizStatusCompleted {
+			return fmt.Errorf("quiz status is %s", completed.Status)
+		}
+		results, err = a.resultService.GetQuizResultsAll(quiz.ID)
+		if err != nil {
+			return err
+		}
+		if len(results) != len(players) {
+			return fmt.Errorf("%d results saved", len(results))
+		}
+		for _, r := range results {
+			if r.IsWinner {
+				return nil
+			}
+		}
+		return fmt.Errorf("winners are not decided yet")
+	})
+
+	byUser := make(map[uint]entity.Result, len(results))
+	for _, r := range results {
+		byUser[r.UserID] = r
+	}
+	won := byUser[winner.ID]
+	assert.True(t, won.IsWinner)
+	assert.Equal(t, 1, won.Rank)
+	assert.Equal(t, 2, won.CorrectAnswers)
+	assert.Equal(t, quiz.PrizeFund, won.PrizeFund, "единственный победитель забирает весь фонд")
+
+	assertEliminated(t, byUser[wrong.ID], 1, "incorrect_answer")
+	assertEliminated(t, byUser[silent.ID], 2, "no_answer_timeout")
+
+	// Статистика победителя и события outbox записаны в той же транзакции, что и призы
+	var statsAfter entity.User
+	require.NoError(t, testDB.First(&statsAfter, winner.ID).Error)
+	assert.Equal(t, statsBefore.GamesPlayed+1, statsAfter.GamesPlayed)
+	assert.Equal(t, statsBefore.WinsCount+1, statsAfter.WinsCount)
+
+	for _, eventType := range []string{entity.EventQuizCompleted, entity.EventPrizeAwarded} {
+		var count int64
+		require.NoError(t, testDB.Model(&entity.OutboxEvent{}).
+			Where("event_type = ? AND (payload->>'quiz_id')::bigint = ?", eventType, quiz.ID).
+			Count(&count).Error)
+		assert.EqualValues(t, 1, count, eventType)
+	}
+}
+
+// waitForQuestion ждёт, пока викторина покажет вопрос с номером number, и возвращает его ID
+func waitForQuestion(t *testing.T, a *app, userID, quizID uint, number int) uint {
+	t.Helper()
+	var state *service.QuizStateResponse
+	eventually(t, 30*time.Second, func() error {
+		var err error
+		state, err = a.quizManager.GetCurrentState(userID, quizID)
+		if err != nil {
+			return err
+		}
+		if state.CurrentQuestion == nil || state.CurrentQuestion.Number != number {
+			return fmt.Errorf("question %d is not shown yet (status %s)", number, state.Status)
+		}
+		return nil
+	})
+	return state.CurrentQuestion.QuestionID
+}
+
+func assertEliminated(t *testing.T, r entity.Result, onQuestion int, reason string) {
+	t.Helper()
+	assert.True(t, r.IsEliminated)
+	assert.False(t, r.IsWinner)
+	assert.Zero(t, r.PrizeFund)
+	if assert.NotNil(t, r.EliminatedOnQuestion) {
+		assert.Equal(t, onQuestion, *r.EliminatedOnQuestion)
+	}
+	if assert.NotNil(t, r.EliminationReason) {
+		assert.Equal(t, reason, *r.EliminationReason)
+	}
+}
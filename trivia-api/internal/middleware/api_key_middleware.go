@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// APIKeyHeader — заголовок, в котором партнёр передаёт API-ключ
+const APIKeyHeader = "X-API-Key"
+
+// apiKeyContextKey — ключ gin.Context с ключом API запроса
+const apiKeyContextKey = "api_key"
+
+// APIKeyAuthenticator проверяет API-ключи и учитывает их использование (service.APIKeyService)
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, rawKey string) (*entity.APIKey, error)
+	RecordAPIKeyUsage(keyID uint, status int)
+}
+
+// RequireAPIKey пропускает запросы с действующим ключом в заголовке X-API-Key.
+// Cookies и CSRF не используются: маршруты партнёров вызываются сервер-сервер.
+// Ответ на каждый принятый ключом запрос, включая 429, попадает в статистику ключа.
+func RequireAPIKey(authenticator APIKeyAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rawKey := c.GetHeader(APIKeyHeader)
+		if rawKey == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "API key is required", "error_type": "api_key_missing"})
+			return
+		}
+
+		key, err := authenticator.AuthenticateAPIKey(c.Request.Context(), rawKey)
+		if err != nil {
+			if errors.Is(err, apperrors.ErrUnauthorized) {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key", "error_type": "api_key_invalid"})
+				return
+			}
+			log.Printf("[APIKey] Ошибка проверки ключа: %v", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Internal server error", "error_type": "internal_server_error"})
+			return
+		}
+
+		c.Set(apiKeyContextKey, key)
+		c.Next()
+		authenticator.RecordAPIKeyUsage(key.ID, c.Writer.Status())
+	}
+}
+
+// RequireAPIKeyScope отвечает 403 api_key_scope, если ключу не выдано право scope.
+// Ставится после RequireAPIKey.
+func RequireAPIKeyScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key, ok := APIKeyFromContext(c)
+		if !ok || !key.HasScope(scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "API key lacks scope " + scope, "error_type": "api_key_scope"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// APIKeyFromContext возвращает ключ API, которым аутентифицирован запрос
+func APIKeyFromContext(c *gin.Context) (*entity.APIKey, bool) {
+	value, ok := c.Get(apiKeyContextKey)
+	if !ok {
+		return nil, false
+	}
+	key, ok := value.(*entity.APIKey)
+	return key, ok
+}
@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Версии мобильного API
+const (
+	APIVersionV1 = "v1" // /api/mobile/...
+	APIVersionV2 = "v2" // /api/mobile/v2/...
+)
+
+const (
+	apiVersionKey = "api_version"
+	clientInfoKey = "client_info"
+)
+
+// Платформы мобильных клиентов
+const (
+	ClientPlatformIOS     = "ios"
+	ClientPlatformAndroid = "android"
+	ClientUnknown         = "unknown"
+)
+
+var clientVersionPattern = regexp.MustCompile(`^[0-9A-Za-z][0-9A-Za-z._+-]{0,31}$`)
+
+// Токены User-Agent, которые добавляет HTTP-стек платформы, а не приложение
+var platformProducts = map[string]bool{
+	"mozilla": true, "dalvik": true, "okhttp": true, "cfnetwork": true, "darwin": true, "expo": true,
+}
+
+// ClientInfo — приложение, отправившее запрос, по заголовку User-Agent
+// («TriviaApp/2.3.1 (iOS 17.2; iPhone15,2)»)
+type ClientInfo struct {
+	App      string
+	Version  string
+	Platform string
+}
+
+// ClientVersionRecorder учитывает запросы мобильных клиентов для аналитики (service.MobileClientService)
+type ClientVersionRecorder interface {
+	RecordClientVersion(apiVersion, platform, appVersion string)
+}
+
+// APIDeprecation описывает вывод версии API из эксплуатации: заголовки Deprecation (RFC 9745),
+// Sunset (RFC 8594) и Link на тот же эндпоинт в новой версии
+type APIDeprecation struct {
+	Since           time.Time // когда версия объявлена устаревшей
+	Sunset          time.Time // когда версия будет отключена; нулевое — дата не объявлена
+	Prefix          string    // префикс пути устаревшей версии (/api/mobile/)
+	SuccessorPrefix string    // префикс пути новой версии (/api/mobile/v2/)
+}
+
+// APIVersion отмечает запросы группы маршрутов версией API и определяет версию клиента по
+// User-Agent. Для устаревшей версии (deprecation != nil) ответы получают заголовки о выводе
+// из эксплуатации. recorder (может быть nil) учитывает запрос в статистике клиентов.
+func APIVersion(version string, deprecation *APIDeprecation, recorder ClientVersionRecorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client := ParseClientUserAgent(c.Request.UserAgent())
+		c.Set(apiVersionKey, version)
+		c.Set(clientInfoKey, client)
+
+		if deprecation != nil {
+			c.Header("Deprecation", fmt.Sprintf("@%d", deprecation.Since.Unix()))
+			if !deprecation.Sunset.IsZero() {
+				c.Header("Sunset", deprecation.Sunset.UTC().Format(http.TimeFormat))
+			}
+			if deprecation.SuccessorPrefix != "" && strings.HasPrefix(c.Request.URL.Path, deprecation.Prefix) {
+				successor := deprecation.SuccessorPrefix + strings.TrimPrefix(c.Request.URL.Path, deprecation.Prefix)
+				c.Header("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, successor))
+			}
+		}
+		if recorder != nil {
+			recorder.RecordClientVersion(version, client.Platform, client.Version)
+		}
+		c.Next()
+	}
+}
+
+// APIVersionFromContext возвращает версию API запроса; вне версионированных групп — v1
+func APIVersionFromContext(c *gin.Context) string {
+	if version := c.GetString(apiVersionKey); version != "" {
+		return version
+	}
+	return APIVersionV1
+}
+
+// ClientInfoFromContext возвращает сведения о клиенте, определённые APIVersion
+func ClientInfoFromContext(c *gin.Context) ClientInfo {
+	if client, ok := c.Get(clientInfoKey); ok {
+		return client.(ClientInfo)
+	}
+	return ParseClientUserAgent(c.Request.UserAgent())
+}
+
+// ParseClientUserAgent определяет приложение, его версию и платформу по User-Agent.
+// Приложение — первый продукт вида Имя/версия, не относящийся к HTTP-стеку платформы.
+func ParseClientUserAgent(userAgent string) ClientInfo {
+	client := ClientInfo{App: ClientUnknown, Version: ClientUnknown, Platform: clientPlatform(userAgent)}
+	for _, token := range strings.Fields(userAgent) {
+		name, version, ok := strings.Cut(token, "/")
+		if !ok || name == "" || strings.HasPrefix(name, "(") || platformProducts[strings.ToLower(name)] {
+			continue
+		}
+		client.App = name
+		if clientVersionPattern.MatchString(version) {
+			client.Version = version
+		}
+		break
+	}
+	return client
+}
+
+func clientPlatform(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+	switch {
+	case strings.Contains(ua, "android"), strings.Contains(ua, "okhttp"), strings.Contains(ua, "dalvik"):
+		return ClientPlatformAndroid
+	case strings.Contains(ua, "ios"), strings.Contains(ua, "iphone"), strings.Contains(ua, "ipad"),
+		strings.Contains(ua, "cfnetwork"), strings.Contains(ua, "darwin"):
+		return ClientPlatformIOS
+	}
+	return ClientUnknown
+}
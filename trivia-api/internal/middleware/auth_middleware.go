@@ -1,20 +1,41 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strings"
 
 	"log"
 
 	"github.com/gin-gonic/gin"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
 	"github.com/yourusername/trivia-api/pkg/auth"
 	"github.com/yourusername/trivia-api/pkg/auth/manager"
 )
 
+// CSRFFamilyHeader — заголовок ответа с семейством запросов, для которого выдан следующий nonce
+const CSRFFamilyHeader = "X-CSRF-Family"
+
+// adminRouteKey — ключ gin.Context, которым AdminOnly помечает маршруты админ-панели
+const adminRouteKey = "admin_route"
+
+// CSRFNonceVerifier проверяет и ротирует nonce админ-панели (service.CSRFNonceService)
+type CSRFNonceVerifier interface {
+	VerifyNonce(ctx context.Context, sessionID string, userID uint, family, nonce string) (string, error)
+}
+
 // AuthMiddleware обеспечивает аутентификацию для защищенных маршрутов
 type AuthMiddleware struct {
 	jwtService   *auth.JWTService
 	tokenManager *manager.TokenManager
+	adminNonces  CSRFNonceVerifier
+
+	// Проверка принятия текущих версий документов (SetLegalAcceptance)
+	legal               LegalAcceptanceChecker
+	legalTOSVersion     string
+	legalPrivacyVersion string
+	legalExempt         map[string]struct{}
 }
 
 // NewAuthMiddlewareWithManager создает новый middleware с использованием TokenManager
@@ -25,6 +46,12 @@ func NewAuthMiddlewareWithManager(jwtService *auth.JWTService, tokenManager *man
 	}
 }
 
+// SetAdminCSRFNonces включает для маршрутов админ-панели (после AdminOnly) режим
+// synchronizer token вместо Double Submit Cookie
+func (m *AuthMiddleware) SetAdminCSRFNonces(verifier CSRFNonceVerifier) {
+	m.adminNonces = verifier
+}
+
 // RequireAuth проверяет, аутентифицирован ли пользователь
 func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -90,6 +117,10 @@ func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 			c.Set("is_admin", true)
 		}
 
+		if !m.requireLegalAcceptance(c, claims.UserID) {
+			return
+		}
+
 		c.Next()
 	}
 }
@@ -114,12 +145,14 @@ func (m *AuthMiddleware) AdminOnly() gin.HandlerFunc {
 			return
 		}
 
+		c.Set(adminRouteKey, true)
 		c.Next()
 	}
 }
 
 // RequireCSRF проверяет наличие и валидность CSRF токена для state-changing методов.
-// Реализует Double Submit Cookie с использованием секрета в JWT.
+// Реализует Double Submit Cookie с использованием секрета в JWT; на маршрутах админ-панели
+// при включённом SetAdminCSRFNonces — synchronizer token (requireAdminNonce).
 // Должен применяться ПОСЛЕ RequireAuth.
 func (m *AuthMiddleware) RequireCSRF() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -130,6 +163,11 @@ func (m *AuthMiddleware) RequireCSRF() gin.HandlerFunc {
 			return
 		}
 
+		if m.adminNonces != nil && c.GetBool(adminRouteKey) {
+			m.requireAdminNonce(c)
+			return
+		}
+
 		// Проверяем, что TokenManager доступен
 		if m.tokenManager == nil {
 			log.Printf("[CSRF Middleware] Ошибка: TokenManager не инициализирован.")
@@ -234,3 +272,48 @@ func (m *AuthMiddleware) RequireCSRF() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// requireAdminNonce проверяет nonce из X-CSRF-Token по CSRF-сессии админ-панели и отдаёт
+// следующий nonce семейства запросов в заголовках X-CSRF-Token и X-CSRF-Family
+func (m *AuthMiddleware) requireAdminNonce(c *gin.Context) {
+	nonce := c.GetHeader(manager.CSRFHeader)
+	if nonce == "" {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "CSRF token missing from header", "error_type": "csrf_token_missing"})
+		return
+	}
+	sessionID, err := m.tokenManager.GetAdminCSRFSessionFromCookie(c.Request)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "CSRF session cookie missing", "error_type": "csrf_session_missing"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	uid, _ := userID.(uint)
+	family := CSRFFamily(c.Request.URL.Path)
+	next, err := m.adminNonces.VerifyNonce(c.Request.Context(), sessionID, uid, family, nonce)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrForbidden) {
+			log.Printf("[CSRF Middleware] Invalid admin CSRF nonce for user %d, family %s", uid, family)
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Invalid CSRF nonce", "error_type": "csrf_nonce_invalid"})
+			return
+		}
+		log.Printf("[CSRF Middleware] Ошибка проверки CSRF nonce: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "CSRF protection error", "error_type": "internal_server_error"})
+		return
+	}
+
+	c.Header(manager.CSRFHeader, next)
+	c.Header(CSRFFamilyHeader, family)
+	c.Next()
+}
+
+// CSRFFamily возвращает семейство запросов для nonce админ-панели: раздел API после /api/
+// (/api/quizzes/5/schedule → quizzes), для /api/admin/* и /api/auth/admin/* — два сегмента
+// (/api/admin/webhooks/3 → admin/webhooks). Скрипт админ-панели вычисляет его так же.
+func CSRFFamily(path string) string {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(path, "/api/"), "/"), "/")
+	if len(parts) >= 2 && (parts[0] == "admin" || (parts[0] == "auth" && parts[1] == "admin")) {
+		return parts[0] + "/" + parts[1]
+	}
+	return parts[0]
+}
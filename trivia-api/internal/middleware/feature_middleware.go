@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yourusername/trivia-api/internal/pkg/i18n"
+)
+
+const featureEvaluatorKey = "feature_evaluator"
+
+// FeatureEvaluator вычисляет флаг функции для пользователя (service.FeatureFlagService)
+type FeatureEvaluator interface {
+	FeatureEnabled(key string, userID uint, isAdmin bool) bool
+}
+
+// FeatureFlags подключает флаги функций к запросу. Как и язык, флаг вычисляется лениво
+// в FeatureEnabled, когда RequireAuth уже положил user_id и is_admin.
+func FeatureFlags(evaluator FeatureEvaluator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(featureEvaluatorKey, evaluator)
+		c.Next()
+	}
+}
+
+// FeatureEnabled сообщает, включён ли флаг key для пользователя запроса.
+// Без middleware FeatureFlags все флаги выключены.
+func FeatureEnabled(c *gin.Context, key string) bool {
+	value, ok := c.Get(featureEvaluatorKey)
+	if !ok {
+		return false
+	}
+	var userID uint
+	if id, ok := c.Get("user_id"); ok {
+		userID, _ = id.(uint)
+	}
+	return value.(FeatureEvaluator).FeatureEnabled(key, userID, c.GetBool("is_admin"))
+}
+
+// RequireFeature отвечает 404 feature_disabled, если флаг key выключен для пользователя.
+// Для флагов с раскаткой по пользователям ставится после RequireAuth.
+func RequireFeature(key string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if FeatureEnabled(c, key) {
+			c.Next()
+			return
+		}
+		message, locale := i18n.ErrorMessage("feature_disabled", RequestLocale(c).Chain())
+		if locale != "" {
+			c.Header("Content-Language", locale)
+		}
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error":      message,
+			"error_type": "feature_disabled",
+		})
+	}
+}
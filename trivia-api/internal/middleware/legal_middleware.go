@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yourusername/trivia-api/internal/pkg/i18n"
+)
+
+// LegalAcceptanceChecker сообщает, должен ли пользователь принять новые версии документов
+// (service.LegalService)
+type LegalAcceptanceChecker interface {
+	AcceptanceRequired(userID uint) (bool, error)
+}
+
+// SetLegalAcceptance включает в RequireAuth проверку принятия текущих версий пользовательского
+// соглашения и политики конфиденциальности. exempt — пути, доступные и без повторного принятия
+// (само принятие, профиль, выход, удаление аккаунта). Версии возвращаются клиенту в ответе 428.
+func (m *AuthMiddleware) SetLegalAcceptance(checker LegalAcceptanceChecker, tosVersion, privacyVersion string, exempt ...string) {
+	m.legal = checker
+	m.legalTOSVersion = tosVersion
+	m.legalPrivacyVersion = privacyVersion
+	m.legalExempt = make(map[string]struct{}, len(exempt))
+	for _, path := range exempt {
+		m.legalExempt[path] = struct{}{}
+	}
+}
+
+// requireLegalAcceptance отвечает 428 legal_acceptance_required, если пользователь не принял
+// текущие версии документов. Ошибка проверки запрос не блокирует.
+func (m *AuthMiddleware) requireLegalAcceptance(c *gin.Context, userID uint) bool {
+	if m.legal == nil {
+		return true
+	}
+	if _, ok := m.legalExempt[c.Request.URL.Path]; ok {
+		return true
+	}
+	required, err := m.legal.AcceptanceRequired(userID)
+	if err != nil {
+		log.Printf("[AuthMiddleware] Не удалось проверить согласие пользователя %d с документами: %v", userID, err)
+		return true
+	}
+	if !required {
+		return true
+	}
+
+	message, locale := i18n.ErrorMessage("legal_acceptance_required", RequestLocale(c).Chain())
+	if locale != "" {
+		c.Header("Content-Language", locale)
+	}
+	c.AbortWithStatusJSON(http.StatusPreconditionRequired, gin.H{
+		"error":           message,
+		"error_type":      "legal_acceptance_required",
+		"tos_version":     m.legalTOSVersion,
+		"privacy_version": m.legalPrivacyVersion,
+	})
+	return false
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/yourusername/trivia-api/internal/pkg/i18n"
+)
+
+const (
+	localeNegotiatorKey = "locale_negotiator"
+	localeResolutionKey = "locale_resolution"
+)
+
+// LocaleNegotiator выбирает язык ответа по явному выбору клиента, языку из профиля
+// пользователя (userID 0 — аноним) и заголовку Accept-Language
+type LocaleNegotiator interface {
+	NegotiateLocale(explicit string, userID uint, acceptLanguage string) i18n.Resolution
+}
+
+// Locale подключает выбор языка ответа к запросу. Сам язык выбирается лениво в RequestLocale:
+// к этому моменту RequireAuth уже положил user_id, а профиль читается только когда язык нужен.
+func Locale(negotiator LocaleNegotiator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(localeNegotiatorKey, negotiator)
+		c.Next()
+	}
+}
+
+// RequestLocale возвращает язык ответа для запроса. Без middleware Locale
+// используются только языки из Accept-Language.
+func RequestLocale(c *gin.Context) i18n.Resolution {
+	if value, ok := c.Get(localeResolutionKey); ok {
+		return value.(i18n.Resolution)
+	}
+
+	var resolution i18n.Resolution
+	if value, ok := c.Get(localeNegotiatorKey); ok {
+		var userID uint
+		if id, ok := c.Get("user_id"); ok {
+			userID, _ = id.(uint)
+		}
+		resolution = value.(LocaleNegotiator).NegotiateLocale(c.Query("lang"), userID, c.GetHeader("Accept-Language"))
+	} else if tags := i18n.ParseAcceptLanguage(c.GetHeader("Accept-Language")); len(tags) > 0 {
+		resolution = i18n.Resolution{Locale: i18n.Normalize(tags[0]), Fallbacks: tags[1:]}
+	}
+	c.Set(localeResolutionKey, resolution)
+	return resolution
+}
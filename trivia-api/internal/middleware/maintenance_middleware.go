@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yourusername/trivia-api/internal/pkg/i18n"
+)
+
+// MaintenanceStatus сообщает, включён ли режим обслуживания (service.MaintenanceService)
+type MaintenanceStatus interface {
+	Active() bool
+	RetryAfter() int
+}
+
+// Maintenance отклоняет изменяющие запросы с 503 и Retry-After, пока включён режим
+// обслуживания. GET, HEAD и OPTIONS проходят всегда. exempt — пути, которые работают
+// и в режиме обслуживания (вход, обновление токенов, переключение самого режима).
+func Maintenance(status MaintenanceStatus, exempt ...string) gin.HandlerFunc {
+	exemptPaths := make(map[string]struct{}, len(exempt))
+	for _, path := range exempt {
+		exemptPaths[path] = struct{}{}
+	}
+
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+		if !status.Active() {
+			c.Next()
+			return
+		}
+		if _, ok := exemptPaths[c.Request.URL.Path]; ok {
+			c.Next()
+			return
+		}
+
+		retryAfter := status.RetryAfter()
+		message, locale := i18n.ErrorMessage("maintenance", RequestLocale(c).Chain())
+		if locale != "" {
+			c.Header("Content-Language", locale)
+		}
+		c.Header("Retry-After", fmt.Sprintf("%d", retryAfter))
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+			"error":       message,
+			"error_type":  "maintenance",
+			"retry_after": retryAfter,
+		})
+	}
+}
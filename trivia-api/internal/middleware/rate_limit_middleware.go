@@ -4,21 +4,30 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/yourusername/trivia-api/internal/config"
 )
 
 // RateLimitConfig содержит настройки rate limiting
 type RateLimitConfig struct {
-	// MaxRequests — максимальное количество запросов за Window
+	// MaxRequests — максимальное количество запросов с одного IP за Window (0 — без лимита по IP)
 	MaxRequests int
-	// Window — временное окно для подсчёта запросов
+	// MaxUserRequests — максимальное количество запросов одного пользователя за Window (0 — без лимита)
+	MaxUserRequests int
+	// Window — скользящее временное окно для подсчёта запросов
 	Window time.Duration
 	// KeyPrefix — префикс для ключей в Redis
 	KeyPrefix string
+	// Group — группа маршрутов в секции rateLimits; её значения (см. RateLimiter.SetRules)
+	// переопределяют поля выше на каждом запросе, поэтому лимиты меняются без перезапуска
+	Group string
 }
 
 // DefaultAuthRateLimitConfig возвращает конфигурацию по умолчанию для auth endpoints
@@ -27,6 +36,7 @@ func DefaultAuthRateLimitConfig() RateLimitConfig {
 		MaxRequests: 20,              // 20 запросов
 		Window:      1 * time.Minute, // за 1 минуту
 		KeyPrefix:   "rl:auth",
+		Group:       "auth",
 	}
 }
 
@@ -36,12 +46,78 @@ func StrictAuthRateLimitConfig() RateLimitConfig {
 		MaxRequests: 5,               // 5 попыток
 		Window:      1 * time.Minute, // за 1 минуту
 		KeyPrefix:   "rl:auth:strict",
+		Group:       "auth_strict",
 	}
 }
 
-// RateLimiter создаёт middleware для rate limiting на основе Redis
+// RateLimitConfigFor возвращает лимиты группы маршрутов из config.yaml (секция rateLimits).
+// Незаданные в конфиге значения берутся из fallback.
+func RateLimitConfigFor(rules map[string]config.RateLimitRule, group string, fallback RateLimitConfig) RateLimitConfig {
+	cfg := fallback
+	if cfg.KeyPrefix == "" {
+		cfg.KeyPrefix = "rl:" + group
+	}
+	rule, ok := rules[group]
+	if !ok {
+		return cfg
+	}
+	if rule.Requests != nil {
+		cfg.MaxRequests = *rule.Requests
+	}
+	if rule.UserRequests != nil {
+		cfg.MaxUserRequests = *rule.UserRequests
+	}
+	if rule.Window > 0 {
+		cfg.Window = rule.Window
+	}
+	return cfg
+}
+
+// slidingWindowScript реализует скользящее окно на sorted set: в set хранятся метки
+// времени принятых запросов, устаревшие удаляются перед подсчётом. Время берётся у Redis,
+// чтобы экземпляры API с рассинхронизированными часами считали одинаково.
+// Возвращает {allowed, count, reset_ms}.
+var slidingWindowScript = redis.NewScript(`
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local window = tonumber(ARGV[1])
+local limit = tonumber(ARGV[2])
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], 0, now - window)
+local count = redis.call('ZCARD', KEYS[1])
+local allowed = 0
+if count < limit then
+  redis.call('ZADD', KEYS[1], now, ARGV[3])
+  count = count + 1
+  allowed = 1
+end
+redis.call('PEXPIRE', KEYS[1], window)
+
+local reset = window
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+if oldest[2] then
+  reset = tonumber(oldest[2]) + window - now
+end
+return {allowed, count, reset}
+`)
+
+// rateLimitResult — результат проверки лимита
+type rateLimitResult struct {
+	allowed   bool
+	limit     int
+	remaining int
+	reset     time.Duration // через сколько освободится место в окне
+}
+
+// rateLimitContextKey — ключ gin.Context с самым строгим из применённых лимитов
+const rateLimitContextKey = "rate_limit_result"
+
+// RateLimiter создаёт middleware для rate limiting на основе Redis (скользящее окно)
 type RateLimiter struct {
 	redisClient redis.UniversalClient
+
+	rulesMu sync.RWMutex
+	rules   map[string]config.RateLimitRule
 }
 
 // NewRateLimiter создает новый RateLimiter
@@ -49,10 +125,33 @@ func NewRateLimiter(redisClient redis.UniversalClient) *RateLimiter {
 	return &RateLimiter{redisClient: redisClient}
 }
 
+// SetRules задаёт лимиты групп маршрутов из конфигурации (секция rateLimits).
+// Можно вызывать на работающем сервере: новые значения применяются к следующим запросам.
+func (rl *RateLimiter) SetRules(rules map[string]config.RateLimitRule) {
+	rl.rulesMu.Lock()
+	defer rl.rulesMu.Unlock()
+	rl.rules = rules
+}
+
+// effective накладывает на конфигурацию middleware текущие лимиты её группы
+func (rl *RateLimiter) effective(cfg RateLimitConfig) RateLimitConfig {
+	if cfg.Group == "" {
+		return cfg
+	}
+	rl.rulesMu.RLock()
+	defer rl.rulesMu.RUnlock()
+	return RateLimitConfigFor(rl.rules, cfg.Group, cfg)
+}
+
 // Limit возвращает Gin middleware с заданной конфигурацией
 // Ключ формируется из IP + endpoint path
-func (rl *RateLimiter) Limit(cfg RateLimitConfig) gin.HandlerFunc {
+func (rl *RateLimiter) Limit(base RateLimitConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		cfg := rl.effective(base)
+		if cfg.MaxRequests <= 0 {
+			c.Next()
+			return
+		}
 		clientIP := c.ClientIP()
 		path := c.FullPath() // Gin route pattern, e.g. "/api/auth/login"
 		if path == "" {
@@ -60,111 +159,144 @@ func (rl *RateLimiter) Limit(cfg RateLimitConfig) gin.HandlerFunc {
 		}
 
 		key := fmt.Sprintf("%s:%s:%s", cfg.KeyPrefix, clientIP, path)
-
-		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-		defer cancel()
-
-		// Инкрементируем счётчик
-		count, err := rl.redisClient.Incr(ctx, key).Result()
-		if err != nil {
-			// При ошибке Redis пропускаем запрос (fail-open), но логируем
-			log.Printf("[RateLimiter] Redis error for key %s: %v. Allowing request (fail-open).", key, err)
-			c.Next()
+		if !rl.enforce(c, key, cfg.MaxRequests, cfg.Window, fmt.Sprintf("IP=%s path=%s", clientIP, path)) {
 			return
 		}
+		c.Next()
+	}
+}
 
-		// Если это первый запрос в окне — устанавливаем TTL
-		if count == 1 {
-			if err := rl.redisClient.Expire(ctx, key, cfg.Window).Err(); err != nil {
-				log.Printf("[RateLimiter] Failed to set TTL for key %s: %v", key, err)
-			}
+// LimitByIP ограничивает количество запросов по IP (без привязки к path)
+// Полезно для глобального лимита на группу endpoints
+func (rl *RateLimiter) LimitByIP(base RateLimitConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cfg := rl.effective(base)
+		if cfg.MaxRequests <= 0 {
+			c.Next()
+			return
 		}
-
-		// Устанавливаем заголовки rate limit
-		remaining := cfg.MaxRequests - int(count)
-		if remaining < 0 {
-			remaining = 0
+		clientIP := c.ClientIP()
+		key := fmt.Sprintf("%s:%s", cfg.KeyPrefix, clientIP)
+		if !rl.enforce(c, key, cfg.MaxRequests, cfg.Window, fmt.Sprintf("IP=%s (group)", clientIP)) {
+			return
 		}
+		c.Next()
+	}
+}
 
-		ttl, _ := rl.redisClient.TTL(ctx, key).Result()
-		retryAfter := int(ttl.Seconds())
-		if retryAfter < 0 {
-			retryAfter = int(cfg.Window.Seconds())
+// LimitByUser ограничивает количество запросов аутентифицированного пользователя на группу endpoints.
+// Должен стоять после RequireAuth; без user_id в контексте запрос пропускается.
+func (rl *RateLimiter) LimitByUser(base RateLimitConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cfg := rl.effective(base)
+		userID, exists := c.Get("user_id")
+		if !exists || cfg.MaxUserRequests <= 0 {
+			c.Next()
+			return
 		}
-
-		c.Header("X-RateLimit-Limit", fmt.Sprintf("%d", cfg.MaxRequests))
-		c.Header("X-RateLimit-Remaining", fmt.Sprintf("%d", remaining))
-		c.Header("X-RateLimit-Reset", fmt.Sprintf("%d", retryAfter))
-
-		// Проверяем лимит
-		if int(count) > cfg.MaxRequests {
-			log.Printf("[RateLimiter] Rate limit exceeded for IP=%s path=%s. Count=%d, Limit=%d",
-				clientIP, path, count, cfg.MaxRequests)
-
-			c.Header("Retry-After", fmt.Sprintf("%d", retryAfter))
-			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
-				"error":       "Too many requests. Please try again later.",
-				"error_type":  "rate_limited",
-				"retry_after": retryAfter,
-			})
+		key := fmt.Sprintf("%s:user:%v", cfg.KeyPrefix, userID)
+		if !rl.enforce(c, key, cfg.MaxUserRequests, cfg.Window, fmt.Sprintf("user=%v", userID)) {
 			return
 		}
-
 		c.Next()
 	}
 }
 
-// LimitByIP ограничивает количество запросов по IP (без привязки к path)
-// Полезно для глобального лимита на группу endpoints
-func (rl *RateLimiter) LimitByIP(cfg RateLimitConfig) gin.HandlerFunc {
+// LimitByAPIKey ограничивает количество запросов одного API-ключа за окно. Лимит ключа
+// (APIKey.RateLimit) заменяет MaxRequests группы. Должен стоять после RequireAPIKey.
+func (rl *RateLimiter) LimitByAPIKey(base RateLimitConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		clientIP := c.ClientIP()
-		key := fmt.Sprintf("%s:%s", cfg.KeyPrefix, clientIP)
-
-		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-		defer cancel()
-
-		count, err := rl.redisClient.Incr(ctx, key).Result()
-		if err != nil {
-			log.Printf("[RateLimiter] Redis error for key %s: %v. Allowing request (fail-open).", key, err)
+		cfg := rl.effective(base)
+		key, ok := APIKeyFromContext(c)
+		if !ok {
 			c.Next()
 			return
 		}
-
-		if count == 1 {
-			if err := rl.redisClient.Expire(ctx, key, cfg.Window).Err(); err != nil {
-				log.Printf("[RateLimiter] Failed to set TTL for key %s: %v", key, err)
-			}
+		limit := cfg.MaxRequests
+		if key.RateLimit > 0 {
+			limit = key.RateLimit
 		}
-
-		remaining := cfg.MaxRequests - int(count)
-		if remaining < 0 {
-			remaining = 0
+		if limit <= 0 {
+			c.Next()
+			return
 		}
-
-		ttl, _ := rl.redisClient.TTL(ctx, key).Result()
-		retryAfter := int(ttl.Seconds())
-		if retryAfter < 0 {
-			retryAfter = int(cfg.Window.Seconds())
+		redisKey := fmt.Sprintf("%s:key:%d", cfg.KeyPrefix, key.ID)
+		if !rl.enforce(c, redisKey, limit, cfg.Window, fmt.Sprintf("api_key=%d", key.ID)) {
+			return
 		}
+		c.Next()
+	}
+}
+
+// enforce проверяет лимит для ключа, выставляет заголовки и при превышении прерывает запрос.
+// Возвращает false, если запрос отклонён.
+func (rl *RateLimiter) enforce(c *gin.Context, key string, limit int, window time.Duration, subject string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	res, err := rl.check(ctx, key, limit, window)
+	if err != nil {
+		// При ошибке Redis пропускаем запрос (fail-open), но логируем
+		log.Printf("[RateLimiter] Redis error for key %s: %v. Allowing request (fail-open).", key, err)
+		return true
+	}
 
-		c.Header("X-RateLimit-Limit", fmt.Sprintf("%d", cfg.MaxRequests))
-		c.Header("X-RateLimit-Remaining", fmt.Sprintf("%d", remaining))
-		c.Header("X-RateLimit-Reset", fmt.Sprintf("%d", retryAfter))
+	setRateLimitHeaders(c, res)
 
-		if int(count) > cfg.MaxRequests {
-			log.Printf("[RateLimiter] Rate limit exceeded for IP=%s (group). Count=%d, Limit=%d",
-				clientIP, count, cfg.MaxRequests)
+	if !res.allowed {
+		retryAfter := ceilSeconds(res.reset)
+		log.Printf("[RateLimiter] Rate limit exceeded for %s. Limit=%d per %v", subject, limit, window)
 
-			c.Header("Retry-After", fmt.Sprintf("%d", retryAfter))
-			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
-				"error":       "Too many requests. Please try again later.",
-				"error_type":  "rate_limited",
-				"retry_after": retryAfter,
-			})
+		c.Header("Retry-After", fmt.Sprintf("%d", retryAfter))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+			"error":       "Too many requests. Please try again later.",
+			"error_type":  "rate_limited",
+			"retry_after": retryAfter,
+		})
+		return false
+	}
+	return true
+}
+
+// check атомарно учитывает запрос в скользящем окне
+func (rl *RateLimiter) check(ctx context.Context, key string, limit int, window time.Duration) (rateLimitResult, error) {
+	values, err := slidingWindowScript.Run(ctx, rl.redisClient, []string{key},
+		window.Milliseconds(), limit, uuid.NewString()).Int64Slice()
+	if err != nil {
+		return rateLimitResult{}, err
+	}
+	if len(values) != 3 {
+		return rateLimitResult{}, fmt.Errorf("unexpected rate limit script result: %v", values)
+	}
+
+	remaining := limit - int(values[1])
+	if remaining < 0 {
+		remaining = 0
+	}
+	return rateLimitResult{
+		allowed:   values[0] == 1,
+		limit:     limit,
+		remaining: remaining,
+		reset:     time.Duration(values[2]) * time.Millisecond,
+	}, nil
+}
+
+// setRateLimitHeaders выставляет заголовки X-RateLimit-*. Если к запросу применено несколько
+// лимитов (по IP и по пользователю), в заголовках остаётся самый строгий из них.
+// X-RateLimit-Reset — количество секунд до освобождения места в окне.
+func setRateLimitHeaders(c *gin.Context, res rateLimitResult) {
+	if prev, ok := c.Get(rateLimitContextKey); ok {
+		if p, ok := prev.(rateLimitResult); ok && p.remaining < res.remaining {
 			return
 		}
-
-		c.Next()
 	}
+	c.Set(rateLimitContextKey, res)
+
+	c.Header("X-RateLimit-Limit", fmt.Sprintf("%d", res.limit))
+	c.Header("X-RateLimit-Remaining", fmt.Sprintf("%d", res.remaining))
+	c.Header("X-RateLimit-Reset", fmt.Sprintf("%d", ceilSeconds(res.reset)))
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
 }
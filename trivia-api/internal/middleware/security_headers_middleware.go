@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yourusername/trivia-api/internal/config"
+)
+
+const cspNonceKey = "csp_nonce"
+
+// cspNoncePlaceholder в Content-Security-Policy заменяется nonce запроса
+const cspNoncePlaceholder = "{nonce}"
+
+// SecurityHeadersPolicy — значения заголовков безопасности группы маршрутов.
+// Пустое значение означает, что заголовок не отправляется.
+type SecurityHeadersPolicy struct {
+	ContentSecurityPolicy     string
+	StrictTransportSecurity   string
+	ContentTypeOptions        string
+	FrameOptions              string
+	ReferrerPolicy            string
+	PermissionsPolicy         string
+	CrossOriginResourcePolicy string
+}
+
+const defaultPermissionsPolicy = "camera=(), microphone=(), geolocation=(), payment=(), usb=()"
+
+// DefaultSecurityHeadersPolicy — заголовки API: ответы не должны исполняться или встраиваться
+func DefaultSecurityHeadersPolicy() SecurityHeadersPolicy {
+	return SecurityHeadersPolicy{
+		ContentSecurityPolicy:     "default-src 'none'; frame-ancestors 'none'; base-uri 'none'; form-action 'none'",
+		StrictTransportSecurity:   "max-age=31536000; includeSubDomains",
+		ContentTypeOptions:        "nosniff",
+		FrameOptions:              "DENY",
+		ReferrerPolicy:            "no-referrer",
+		PermissionsPolicy:         defaultPermissionsPolicy,
+		CrossOriginResourcePolicy: "same-origin",
+	}
+}
+
+// AdminSecurityHeadersPolicy — заголовки страниц админ-панели: скрипты только с того же origin
+// или с nonce запроса (его получают встроенные скрипты AdminPanelHandler), запросы только к API
+// этого же сервера, встраивание во фреймы запрещено.
+func AdminSecurityHeadersPolicy() SecurityHeadersPolicy {
+	policy := DefaultSecurityHeadersPolicy()
+	policy.ContentSecurityPolicy = "default-src 'self'; script-src 'self' 'nonce-{nonce}'; style-src 'self' 'unsafe-inline'; " +
+		"img-src 'self' data: https:; font-src 'self'; connect-src 'self'; object-src 'none'; " +
+		"base-uri 'self'; form-action 'self'; frame-ancestors 'none'"
+	policy.ReferrerPolicy = "same-origin"
+	return policy
+}
+
+// UploadsSecurityHeadersPolicy — заголовки загруженных файлов: их можно встраивать на других
+// сайтах и в приложениях, но содержимое (например, SVG) не исполняет скрипты.
+func UploadsSecurityHeadersPolicy() SecurityHeadersPolicy {
+	policy := DefaultSecurityHeadersPolicy()
+	policy.ContentSecurityPolicy = "default-src 'none'; img-src 'self' data:; media-src 'self'; style-src 'unsafe-inline'; sandbox"
+	policy.FrameOptions = ""
+	policy.ReferrerPolicy = "strict-origin-when-cross-origin"
+	policy.CrossOriginResourcePolicy = "cross-origin"
+	return policy
+}
+
+// SecurityHeadersPolicyFor накладывает на политику группы по умолчанию значения из конфигурации (секция securityHeaders)
+func SecurityHeadersPolicyFor(rules map[string]config.SecurityHeadersRule, group string, fallback SecurityHeadersPolicy) SecurityHeadersPolicy {
+	policy := fallback
+	rule, ok := rules[group]
+	if !ok {
+		return policy
+	}
+	override := func(dst *string, src *string) {
+		if src != nil {
+			*dst = *src
+		}
+	}
+	override(&policy.ContentSecurityPolicy, rule.ContentSecurityPolicy)
+	override(&policy.StrictTransportSecurity, rule.StrictTransportSecurity)
+	override(&policy.ContentTypeOptions, rule.ContentTypeOptions)
+	override(&policy.FrameOptions, rule.FrameOptions)
+	override(&policy.ReferrerPolicy, rule.ReferrerPolicy)
+	override(&policy.PermissionsPolicy, rule.PermissionsPolicy)
+	override(&policy.CrossOriginResourcePolicy, rule.CrossOriginResourcePolicy)
+	return policy
+}
+
+// SecurityHeaders выставляет заголовки безопасности политики. Middleware группы маршрутов
+// заменяет заголовки, выставленные общим middleware, и убирает отключённые в её политике.
+// Strict-Transport-Security отправляется только на запросы по HTTPS (в том числе через прокси).
+func SecurityHeaders(policy SecurityHeadersPolicy) gin.HandlerFunc {
+	needsNonce := strings.Contains(policy.ContentSecurityPolicy, cspNoncePlaceholder)
+
+	return func(c *gin.Context) {
+		csp := policy.ContentSecurityPolicy
+		if needsNonce {
+			nonce, err := newCSPNonce()
+			if err != nil {
+				// Без nonce встроенные скрипты просто не выполнятся
+				csp = strings.ReplaceAll(csp, " 'nonce-"+cspNoncePlaceholder+"'", "")
+			} else {
+				c.Set(cspNonceKey, nonce)
+				csp = strings.ReplaceAll(csp, cspNoncePlaceholder, nonce)
+			}
+		}
+
+		hsts := policy.StrictTransportSecurity
+		if !isHTTPS(c) {
+			hsts = ""
+		}
+
+		setOrDeleteHeader(c, "Content-Security-Policy", csp)
+		setOrDeleteHeader(c, "Strict-Transport-Security", hsts)
+		setOrDeleteHeader(c, "X-Content-Type-Options", policy.ContentTypeOptions)
+		setOrDeleteHeader(c, "X-Frame-Options", policy.FrameOptions)
+		setOrDeleteHeader(c, "Referrer-Policy", policy.ReferrerPolicy)
+		setOrDeleteHeader(c, "Permissions-Policy", policy.PermissionsPolicy)
+		setOrDeleteHeader(c, "Cross-Origin-Resource-Policy", policy.CrossOriginResourcePolicy)
+		c.Next()
+	}
+}
+
+// CSPNonce возвращает nonce Content-Security-Policy запроса для атрибута nonce встроенных скриптов.
+// Пустая строка — политика маршрута не использует nonce.
+func CSPNonce(c *gin.Context) string {
+	return c.GetString(cspNonceKey)
+}
+
+func newCSPNonce() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buf), nil
+}
+
+func isHTTPS(c *gin.Context) bool {
+	return c.Request.TLS != nil || strings.EqualFold(c.GetHeader("X-Forwarded-Proto"), "https")
+}
+
+func setOrDeleteHeader(c *gin.Context, name, value string) {
+	if value == "" {
+		c.Writer.Header().Del(name)
+		return
+	}
+	c.Header(name, value)
+}
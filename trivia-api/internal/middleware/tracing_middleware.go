@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/yourusername/trivia-api/internal/pkg/tracing"
+)
+
+const (
+	// RequestIDHeader — заголовок с идентификатором запроса (принимается от клиента/балансировщика и возвращается в ответе)
+	RequestIDHeader = "X-Request-ID"
+	// TraceIDHeader — заголовок ответа с trace ID, по которому запрос ищется в системе трейсинга
+	TraceIDHeader = "X-Trace-ID"
+
+	maxRequestIDLength = 128
+)
+
+// Tracing создаёт серверный спан на каждый HTTP-запрос и выдаёт ему request ID.
+// Входящий контекст трейса (traceparent) продолжается, контекст со спаном кладётся
+// в c.Request, поэтому сервисы и репозитории, получившие c.Request.Context(), попадают в тот же трейс.
+func Tracing() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+		ctx = tracing.WithRequestID(ctx, requestID)
+
+		route := c.FullPath()
+		spanName := c.Request.Method + " " + route
+		if route == "" {
+			spanName = c.Request.Method
+		}
+		ctx, span := tracing.Tracer().Start(ctx, spanName,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", c.Request.Method),
+				attribute.String("http.route", route),
+				attribute.String("url.path", c.Request.URL.Path),
+				attribute.String("client.address", c.ClientIP()),
+				attribute.String("user_agent.original", c.Request.UserAgent()),
+				attribute.String("request.id", requestID),
+			),
+		)
+		defer span.End()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Set("request_id", requestID)
+		c.Header(RequestIDHeader, requestID)
+		if sc := span.SpanContext(); sc.IsValid() {
+			c.Header(TraceIDHeader, sc.TraceID().String())
+		}
+
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if userID, ok := c.Get("user_id"); ok {
+			span.SetAttributes(attribute.String("enduser.id", fmt.Sprint(userID)))
+		}
+		if len(c.Errors) > 0 {
+			span.RecordError(c.Errors.Last())
+		}
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
+
+// validRequestID принимает только короткие идентификаторы из печатных ASCII-символов,
+// чтобы клиент не мог протащить в логи и заголовки произвольные данные
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
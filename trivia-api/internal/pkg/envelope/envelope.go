@@ -0,0 +1,171 @@
+// Package envelope шифрует значения колонок БД конвертным шифрованием: каждое значение
+// шифруется своим ключом данных (AES-256-GCM), а ключ данных — мастер-ключом версии KeyVersion.
+// Смена мастер-ключа перешифровывает только ключи данных (Rewrap), сами значения не меняются.
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// keySize — длина мастер-ключа и ключа данных (AES-256)
+const keySize = 32
+
+// ErrUnknownKeyVersion возвращается, если значение зашифровано мастер-ключом, которого нет в Keyring
+var ErrUnknownKeyVersion = errors.New("unknown master key version")
+
+// Sealed — зашифрованное значение в том виде, в каком оно хранится в колонках
+type Sealed struct {
+	Ciphertext string // hex(nonce || ciphertext) значения
+	WrappedKey string // hex(nonce || ciphertext) ключа данных; "" — значение зашифровано мастер-ключом напрямую (до конвертного шифрования)
+	KeyVersion int    // версия мастер-ключа, которым зашифрован ключ данных
+}
+
+// Keyring хранит мастер-ключи по версиям. Новые значения шифруются текущей версией,
+// прежние версии нужны только для расшифровки до перешифрования.
+type Keyring struct {
+	current int
+	keys    map[int][]byte
+}
+
+// NewKeyring создает набор мастер-ключей. keysHex — HEX-представления 32-байтовых ключей по версиям;
+// ключ версии current обязателен.
+func NewKeyring(current int, keysHex map[int]string) (*Keyring, error) {
+	if current < 1 {
+		return nil, fmt.Errorf("master key version must be positive, got %d", current)
+	}
+	k := &Keyring{current: current, keys: make(map[int][]byte, len(keysHex))}
+	for version, keyHex := range keysHex {
+		key, err := hex.DecodeString(keyHex)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode master key version %d from hex: %w", version, err)
+		}
+		if len(key) != keySize {
+			return nil, fmt.Errorf("invalid master key version %d length: must be %d bytes (for AES-256), got %d bytes", version, keySize, len(key))
+		}
+		k.keys[version] = key
+	}
+	if _, ok := k.keys[current]; !ok {
+		return nil, fmt.Errorf("master key version %d is required", current)
+	}
+	return k, nil
+}
+
+// CurrentVersion возвращает версию мастер-ключа, которой шифруются новые значения
+func (k *Keyring) CurrentVersion() int {
+	return k.current
+}
+
+// Seal шифрует значение новым ключом данных, обёрнутым текущим мастер-ключом
+func (k *Keyring) Seal(plaintext []byte) (Sealed, error) {
+	dataKey := make([]byte, keySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return Sealed{}, fmt.Errorf("failed to generate data key: %w", err)
+	}
+	ciphertext, err := encrypt(dataKey, plaintext)
+	if err != nil {
+		return Sealed{}, err
+	}
+	wrappedKey, err := encrypt(k.keys[k.current], dataKey)
+	if err != nil {
+		return Sealed{}, fmt.Errorf("failed to wrap data key: %w", err)
+	}
+	return Sealed{Ciphertext: ciphertext, WrappedKey: wrappedKey, KeyVersion: k.current}, nil
+}
+
+// Open расшифровывает значение. Значения без ключа данных расшифровываются мастер-ключом напрямую.
+func (k *Keyring) Open(s Sealed) ([]byte, error) {
+	masterKey, ok := k.keys[s.KeyVersion]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownKeyVersion, s.KeyVersion)
+	}
+	if s.WrappedKey == "" {
+		return decrypt(masterKey, s.Ciphertext)
+	}
+	dataKey, err := decrypt(masterKey, s.WrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+	return decrypt(dataKey, s.Ciphertext)
+}
+
+// NeedsRewrap сообщает, что значение зашифровано не текущим мастер-ключом или без ключа данных
+func (k *Keyring) NeedsRewrap(s Sealed) bool {
+	return s.WrappedKey == "" || s.KeyVersion != k.current
+}
+
+// Rewrap перешифровывает ключ данных текущим мастер-ключом. Значение без ключа данных
+// шифруется заново под новым ключом данных.
+func (k *Keyring) Rewrap(s Sealed) (Sealed, error) {
+	if s.WrappedKey == "" {
+		plaintext, err := k.Open(s)
+		if err != nil {
+			return Sealed{}, err
+		}
+		return k.Seal(plaintext)
+	}
+	masterKey, ok := k.keys[s.KeyVersion]
+	if !ok {
+		return Sealed{}, fmt.Errorf("%w: %d", ErrUnknownKeyVersion, s.KeyVersion)
+	}
+	dataKey, err := decrypt(masterKey, s.WrappedKey)
+	if err != nil {
+		return Sealed{}, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+	wrappedKey, err := encrypt(k.keys[k.current], dataKey)
+	if err != nil {
+		return Sealed{}, fmt.Errorf("failed to wrap data key: %w", err)
+	}
+	return Sealed{Ciphertext: s.Ciphertext, WrappedKey: wrappedKey, KeyVersion: k.current}, nil
+}
+
+// encrypt шифрует данные AES-GCM и возвращает hex(nonce || ciphertext)
+func encrypt(key, plaintext []byte) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return hex.EncodeToString(gcm.Seal(nonce, nonce, plaintext, nil)), nil
+}
+
+// decrypt расшифровывает hex(nonce || ciphertext)
+func decrypt(key []byte, encryptedHex string) ([]byte, error) {
+	data, err := hex.DecodeString(encryptedHex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode ciphertext from hex: %w", err)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonceSize := gcm.NonceSize()
+	if len(data) < nonceSize {
+		return nil, errors.New("ciphertext is too short to contain nonce")
+	}
+	plaintext, err := gcm.Open(nil, data[:nonceSize], data[nonceSize:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt with GCM: %w", err)
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AES cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+	return gcm, nil
+}
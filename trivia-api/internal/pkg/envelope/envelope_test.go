@@ -0,0 +1,94 @@
+package envelope
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	masterV1 = strings.Repeat("11", 32)
+	masterV2 = strings.Repeat("22", 32)
+)
+
+func TestKeyring_SealOpen(t *testing.T) {
+	k, err := NewKeyring(1, map[int]string{1: masterV1})
+	require.NoError(t, err)
+
+	sealed, err := k.Seal([]byte("jwt-secret"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, sealed.KeyVersion)
+	assert.NotEmpty(t, sealed.WrappedKey)
+	assert.False(t, k.NeedsRewrap(sealed))
+
+	plaintext, err := k.Open(sealed)
+	require.NoError(t, err)
+	assert.Equal(t, "jwt-secret", string(plaintext))
+
+	// Каждое значение получает свой ключ данных
+	other, err := k.Seal([]byte("jwt-secret"))
+	require.NoError(t, err)
+	assert.NotEqual(t, sealed.WrappedKey, other.WrappedKey)
+}
+
+func TestKeyring_RewrapToNewMasterKey(t *testing.T) {
+	old, err := NewKeyring(1, map[int]string{1: masterV1})
+	require.NoError(t, err)
+	sealed, err := old.Seal([]byte("jwt-secret"))
+	require.NoError(t, err)
+
+	// Новый мастер-ключ текущий, прежний оставлен для расшифровки
+	k, err := NewKeyring(2, map[int]string{1: masterV1, 2: masterV2})
+	require.NoError(t, err)
+	plaintext, err := k.Open(sealed)
+	require.NoError(t, err)
+	assert.Equal(t, "jwt-secret", string(plaintext))
+	require.True(t, k.NeedsRewrap(sealed))
+
+	rewrapped, err := k.Rewrap(sealed)
+	require.NoError(t, err)
+	assert.Equal(t, 2, rewrapped.KeyVersion)
+	assert.Equal(t, sealed.Ciphertext, rewrapped.Ciphertext, "значение не перешифровывается")
+	assert.False(t, k.NeedsRewrap(rewrapped))
+
+	// После перешифрования прежний мастер-ключ больше не нужен
+	onlyNew, err := NewKeyring(2, map[int]string{2: masterV2})
+	require.NoError(t, err)
+	plaintext, err = onlyNew.Open(rewrapped)
+	require.NoError(t, err)
+	assert.Equal(t, "jwt-secret", string(plaintext))
+	_, err = onlyNew.Open(sealed)
+	assert.ErrorIs(t, err, ErrUnknownKeyVersion)
+}
+
+func TestKeyring_RewrapLegacyValue(t *testing.T) {
+	k, err := NewKeyring(1, map[int]string{1: masterV1})
+	require.NoError(t, err)
+	// Значение, зашифрованное мастер-ключом напрямую, как до конвертного шифрования
+	ciphertext, err := encrypt(k.keys[1], []byte("legacy-secret"))
+	require.NoError(t, err)
+	legacy := Sealed{Ciphertext: ciphertext, KeyVersion: 1}
+
+	plaintext, err := k.Open(legacy)
+	require.NoError(t, err)
+	assert.Equal(t, "legacy-secret", string(plaintext))
+	require.True(t, k.NeedsRewrap(legacy))
+
+	rewrapped, err := k.Rewrap(legacy)
+	require.NoError(t, err)
+	assert.NotEmpty(t, rewrapped.WrappedKey)
+	plaintext, err = k.Open(rewrapped)
+	require.NoError(t, err)
+	assert.Equal(t, "legacy-secret", string(plaintext))
+}
+
+func TestNewKeyring_Validation(t *testing.T) {
+	_, err := NewKeyring(2, map[int]string{1: masterV1})
+	assert.ErrorContains(t, err, "version 2 is required")
+	_, err = NewKeyring(1, map[int]string{1: "abcd"})
+	assert.ErrorContains(t, err, "must be 32 bytes")
+	_, err = NewKeyring(0, map[int]string{0: masterV1})
+	assert.Error(t, err)
+}
@@ -0,0 +1,271 @@
+// Package export пишет табличные выгрузки (CSV, XLSX, JSON) построчно, не держа данные в памяти целиком.
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Column — колонка выгрузки. Key — имя поля в JSON, Title — заголовок в CSV и XLSX.
+// Label, если задан, переводит значение в читаемый вид для CSV и XLSX; JSON получает значение как есть.
+type Column struct {
+	Key   string
+	Title string
+	Label func(value interface{}) interface{}
+}
+
+// Writer пишет строки выгрузки; значения строки идут в порядке колонок
+type Writer interface {
+	WriteRow(values []interface{}) error
+	// Close дописывает файл; после Close писать нельзя
+	Close() error
+}
+
+// ContentType возвращает MIME-тип файла выгрузки
+func ContentType(format string) string {
+	switch format {
+	case "xlsx":
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case "json":
+		return "application/json"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// NewWriter создает писатель выгрузки формата csv, xlsx или json
+func NewWriter(format string, w io.Writer, columns []Column) (Writer, error) {
+	switch format {
+	case "csv":
+		return newCSVWriter(w, columns)
+	case "xlsx":
+		return newXLSXWriter(w, columns)
+	case "json":
+		return newJSONWriter(w, columns), nil
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+// SanitizeCell экранирует строку для защиты от formula injection в Excel/CSV
+func SanitizeCell(s string) string {
+	if len(s) == 0 {
+		return s
+	}
+	// Символы, начинающие формулу в Excel/LibreOffice: = + - @ \t \r
+	if s[0] == '=' || s[0] == '+' || s[0] == '-' || s[0] == '@' || s[0] == '\t' || s[0] == '\r' {
+		return "'" + s
+	}
+	return s
+}
+
+// EliminationReasonLabel переводит причину выбытия на русский
+func EliminationReasonLabel(reason string) string {
+	switch reason {
+	case "time_exceeded", "no_answer_timeout":
+		return "Время истекло"
+	case "incorrect_answer":
+		return "Неверный ответ"
+	case "disconnected":
+		return "Отключился"
+	default:
+		return reason
+	}
+}
+
+// LabelYesNo — Label для логических колонок
+func LabelYesNo(value interface{}) interface{} {
+	if b, ok := value.(bool); ok && b {
+		return "Да"
+	}
+	return "Нет"
+}
+
+// LabelEliminationReason — Label для колонки причины выбытия (string или *string)
+func LabelEliminationReason(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return EliminationReasonLabel(v)
+	case *string:
+		if v != nil {
+			return EliminationReasonLabel(*v)
+		}
+	}
+	return ""
+}
+
+// spreadsheetCell готовит значение для CSV и XLSX: Label колонки и экранирование строк
+func spreadsheetCell(column Column, value interface{}) interface{} {
+	if column.Label != nil {
+		value = column.Label(value)
+	}
+	switch v := value.(type) {
+	case string:
+		return SanitizeCell(v)
+	case *int:
+		if v == nil {
+			return ""
+		}
+		return *v
+	case *string:
+		if v == nil {
+			return ""
+		}
+		return SanitizeCell(*v)
+	default:
+		return v
+	}
+}
+
+type csvWriter struct {
+	columns []Column
+	w       *csv.Writer
+	record  []string
+}
+
+func newCSVWriter(w io.Writer, columns []Column) (*csvWriter, error) {
+	// BOM для корректного отображения UTF-8 в Excel
+	if _, err := w.Write([]byte{0xEF, 0xBB, 0xBF}); err != nil {
+		return nil, err
+	}
+	cw := &csvWriter{columns: columns, w: csv.NewWriter(w), record: make([]string, len(columns))}
+	for i, column := range columns {
+		cw.record[i] = column.Title
+	}
+	return cw, cw.w.Write(cw.record)
+}
+
+func (cw *csvWriter) WriteRow(values []interface{}) error {
+	for i, column := range cw.columns {
+		switch v := spreadsheetCell(column, values[i]).(type) {
+		case string:
+			cw.record[i] = v
+		case int:
+			cw.record[i] = strconv.Itoa(v)
+		case int64:
+			cw.record[i] = strconv.FormatInt(v, 10)
+		case time.Time:
+			cw.record[i] = v.UTC().Format(time.RFC3339)
+		default:
+			cw.record[i] = fmt.Sprint(v)
+		}
+	}
+	return cw.w.Write(cw.record)
+}
+
+func (cw *csvWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// xlsxWriter пишет лист через StreamWriter: строки сбрасываются во временный файл excelize, а не в память
+type xlsxWriter struct {
+	columns []Column
+	out     io.Writer
+	file    *excelize.File
+	sheet   *excelize.StreamWriter
+	row     int
+	cells   []interface{}
+}
+
+const xlsxSheetName = "Данные"
+
+func newXLSXWriter(w io.Writer, columns []Column) (*xlsxWriter, error) {
+	f := excelize.NewFile()
+	if err := f.SetSheetName("Sheet1", xlsxSheetName); err != nil {
+		f.Close()
+		return nil, err
+	}
+	sheet, err := f.NewStreamWriter(xlsxSheetName)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	xw := &xlsxWriter{columns: columns, out: w, file: f, sheet: sheet, row: 1, cells: make([]interface{}, len(columns))}
+	for i, column := range columns {
+		xw.cells[i] = column.Title
+	}
+	if err := sheet.SetRow("A1", xw.cells); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return xw, nil
+}
+
+func (xw *xlsxWriter) WriteRow(values []interface{}) error {
+	xw.row++
+	for i, column := range xw.columns {
+		xw.cells[i] = spreadsheetCell(column, values[i])
+	}
+	cell, err := excelize.CoordinatesToCellName(1, xw.row)
+	if err != nil {
+		return err
+	}
+	return xw.sheet.SetRow(cell, xw.cells)
+}
+
+func (xw *xlsxWriter) Close() error {
+	defer xw.file.Close()
+	if err := xw.sheet.Flush(); err != nil {
+		return err
+	}
+	return xw.file.Write(xw.out)
+}
+
+// jsonWriter пишет массив объектов, сохраняя порядок полей колонок
+type jsonWriter struct {
+	columns []Column
+	w       *bufio.Writer
+	rows    int
+	err     error
+}
+
+func newJSONWriter(w io.Writer, columns []Column) *jsonWriter {
+	jw := &jsonWriter{columns: columns, w: bufio.NewWriter(w)}
+	jw.write([]byte("["))
+	return jw
+}
+
+func (jw *jsonWriter) write(p []byte) {
+	if jw.err == nil {
+		_, jw.err = jw.w.Write(p)
+	}
+}
+
+func (jw *jsonWriter) WriteRow(values []interface{}) error {
+	if jw.rows > 0 {
+		jw.write([]byte(","))
+	}
+	jw.rows++
+	jw.write([]byte("\n{"))
+	for i, column := range jw.columns {
+		if i > 0 {
+			jw.write([]byte(","))
+		}
+		key, _ := json.Marshal(column.Key)
+		value, err := json.Marshal(values[i])
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", column.Key, err)
+		}
+		jw.write(key)
+		jw.write([]byte(":"))
+		jw.write(value)
+	}
+	jw.write([]byte("}"))
+	return jw.err
+}
+
+func (jw *jsonWriter) Close() error {
+	jw.write([]byte("\n]\n"))
+	if jw.err != nil {
+		return jw.err
+	}
+	return jw.w.Flush()
+}
@@ -0,0 +1,81 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+var testColumns = []Column{
+	{Key: "name", Title: "Имя"},
+	{Key: "winner", Title: "Победитель", Label: LabelYesNo},
+	{Key: "reason", Title: "Причина", Label: LabelEliminationReason},
+	{Key: "question", Title: "Вопрос"},
+}
+
+func writeTestRows(t *testing.T, format string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewWriter(format, &buf, testColumns)
+	require.NoError(t, err)
+	reason := "incorrect_answer"
+	question := 4
+	require.NoError(t, w.WriteRow([]interface{}{"=HYPERLINK(\"x\")", true, &reason, &question}))
+	require.NoError(t, w.WriteRow([]interface{}{"player, 2", false, (*string)(nil), (*int)(nil)}))
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestCSVWriter(t *testing.T) {
+	out := writeTestRows(t, "csv")
+
+	assert.True(t, bytes.HasPrefix(out, []byte{0xEF, 0xBB, 0xBF}), "BOM для Excel")
+	assert.Equal(t, "Имя,Победитель,Причина,Вопрос\n"+
+		"\"'=HYPERLINK(\"\"x\"\")\",Да,Неверный ответ,4\n"+
+		"\"player, 2\",Нет,,\n", string(out[3:]))
+}
+
+func TestJSONWriter_KeepsRawValues(t *testing.T) {
+	out := writeTestRows(t, "json")
+
+	var rows []map[string]interface{}
+	require.NoError(t, json.Unmarshal(out, &rows))
+	require.Len(t, rows, 2)
+	assert.Equal(t, "=HYPERLINK(\"x\")", rows[0]["name"], "JSON не экранируется для Excel")
+	assert.Equal(t, true, rows[0]["winner"])
+	assert.Equal(t, "incorrect_answer", rows[0]["reason"])
+	assert.Nil(t, rows[1]["question"])
+}
+
+func TestJSONWriter_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter("json", &buf, testColumns)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	var rows []map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &rows))
+	assert.Empty(t, rows)
+}
+
+func TestXLSXWriter(t *testing.T) {
+	out := writeTestRows(t, "xlsx")
+
+	f, err := excelize.OpenReader(bytes.NewReader(out))
+	require.NoError(t, err)
+	defer f.Close()
+	rows, err := f.GetRows(xlsxSheetName)
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, []string{"Имя", "Победитель", "Причина", "Вопрос"}, rows[0])
+	assert.Equal(t, []string{"'=HYPERLINK(\"x\")", "Да", "Неверный ответ", "4"}, rows[1])
+}
+
+func TestNewWriter_UnknownFormat(t *testing.T) {
+	_, err := NewWriter("pdf", &bytes.Buffer{}, testColumns)
+	assert.Error(t, err)
+}
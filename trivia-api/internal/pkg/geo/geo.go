@@ -0,0 +1,86 @@
+// Package geo определяет страну клиента по IP-адресу: локальная база MaxMind (GeoIP2/GeoLite2
+// Country или City в формате .mmdb) или веб-сервис MaxMind GeoIP2 Precision.
+package geo
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Resolver определяет страну IP-адреса. Возвращает код ISO 3166-1 alpha-2 в верхнем регистре
+// или пустую строку, если страна неизвестна (адреса нет в базе).
+type Resolver interface {
+	Country(ctx context.Context, ip net.IP) (string, error)
+	Name() string
+}
+
+// IsPublic сообщает, можно ли определить страну адреса: частные, loopback и link-local
+// адреса в базах не встречаются
+func IsPublic(ip net.IP) bool {
+	return ip != nil && !ip.IsPrivate() && !ip.IsLoopback() && !ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() && !ip.IsUnspecified() && !ip.IsMulticast()
+}
+
+// NormalizeCountry приводит код страны к виду ISO 3166-1 alpha-2 ("kz" → "KZ");
+// пустая строка — код некорректен
+func NormalizeCountry(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 2 || code[0] < 'A' || code[0] > 'Z' || code[1] < 'A' || code[1] > 'Z' {
+		return ""
+	}
+	return code
+}
+
+// cacheLimit — при переполнении кеш очищается целиком: адреса игроков одной викторины
+// повторяются в пределах часов, вытеснение по возрасту не нужно
+const cacheLimit = 100000
+
+type cachedCountry struct {
+	country   string
+	expiresAt time.Time
+}
+
+// CachedResolver запоминает ответы другого Resolver на ttl: веб-сервис платный и медленнее
+// локальной базы, а игроки переподключаются с тех же адресов. Ошибки не кешируются.
+type CachedResolver struct {
+	resolver Resolver
+	ttl      time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cachedCountry
+}
+
+// NewCachedResolver создает кеш поверх resolver
+func NewCachedResolver(resolver Resolver, ttl time.Duration) *CachedResolver {
+	return &CachedResolver{resolver: resolver, ttl: ttl, entries: make(map[string]cachedCountry)}
+}
+
+// Name возвращает имя исходного провайдера
+func (c *CachedResolver) Name() string { return c.resolver.Name() }
+
+// Country возвращает страну из кеша или запрашивает её у провайдера
+func (c *CachedResolver) Country(ctx context.Context, ip net.IP) (string, error) {
+	key := ip.String()
+	now := time.Now()
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.country, nil
+	}
+
+	country, err := c.resolver.Country(ctx, ip)
+	if err != nil {
+		return "", err
+	}
+	c.mu.Lock()
+	if len(c.entries) >= cacheLimit {
+		c.entries = make(map[string]cachedCountry)
+	}
+	c.entries[key] = cachedCountry{country: country, expiresAt: now.Add(c.ttl)}
+	c.mu.Unlock()
+	return country, nil
+}
@@ -0,0 +1,223 @@
+package geo
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Записи секции данных тестовой базы
+var (
+	// {"country": {"iso_code": "KZ"}}; вложенная карта начинается со смещения 9
+	recordKZ = concat([]byte{0xE1}, mmdbString("country"), []byte{0xE1}, mmdbString("iso_code"), mmdbString("KZ"))
+	// {"registered_country": указатель на вложенную карту recordKZ}
+	recordRegistered = concat([]byte{0xE1}, mmdbString("registered_country"), []byte{0x20, 0x09})
+	// {"country": {"iso_code": "de"}}
+	recordDE = concat([]byte{0xE1}, mmdbString("country"), []byte{0xE1}, mmdbString("iso_code"), mmdbString("de"))
+)
+
+func concat(parts ...[]byte) []byte {
+	var out []byte
+	for _, p := range parts {
+		out = append(out, p...)
+	}
+	return out
+}
+
+func mmdbString(s string) []byte {
+	return append([]byte{0x40 | byte(len(s))}, s...)
+}
+
+type testNode struct {
+	child [2]int // >=0 — узел, -1 — пусто, <= -2 — запись данных с номером -child-2
+}
+
+// buildMMDB собирает базу формата MaxMind DB с записями records по сетям networks
+func buildMMDB(t *testing.T, ipVersion, recordSize int, networks map[string]int, records [][]byte) []byte {
+	t.Helper()
+	nodes := []testNode{{child: [2]int{-1, -1}}}
+	for cidr, record := range networks {
+		_, network, err := net.ParseCIDR(cidr)
+		require.NoError(t, err)
+		ones, _ := network.Mask.Size()
+		bits := []byte(network.IP)
+		if v4 := network.IP.To4(); v4 != nil && ipVersion == 6 {
+			bits, ones = append(make([]byte, 12), v4...), ones+96
+		}
+		node := 0
+		for i := 0; i < ones; i++ {
+			bit := (bits[i/8] >> (7 - uint(i%8))) & 1
+			if i == ones-1 {
+				nodes[node].child[bit] = -record - 2
+				break
+			}
+			if nodes[node].child[bit] < 0 {
+				nodes = append(nodes, testNode{child: [2]int{-1, -1}})
+				nodes[node].child[bit] = len(nodes) - 1
+			}
+			node = nodes[node].child[bit]
+		}
+	}
+
+	var data []byte
+	offsets := make([]int, len(records))
+	for i, record := range records {
+		offsets[i] = len(data)
+		data = append(data, record...)
+	}
+	nodeCount := len(nodes)
+	value := func(child int) uint32 {
+		switch {
+		case child >= 0:
+			return uint32(child)
+		case child == -1:
+			return uint32(nodeCount)
+		}
+		return uint32(nodeCount + dataSectionSeparator + offsets[-child-2])
+	}
+
+	var tree []byte
+	for _, n := range nodes {
+		left, right := value(n.child[0]), value(n.child[1])
+		switch recordSize {
+		case 24:
+			tree = append(tree, byte(left>>16), byte(left>>8), byte(left), byte(right>>16), byte(right>>8), byte(right))
+		case 28:
+			tree = append(tree, byte(left>>16), byte(left>>8), byte(left), byte(left>>24)<<4|byte(right>>24)&0x0f,
+				byte(right>>16), byte(right>>8), byte(right))
+		default:
+			tree = binary.BigEndian.AppendUint32(tree, left)
+			tree = binary.BigEndian.AppendUint32(tree, right)
+		}
+	}
+
+	meta := concat([]byte{0xE3},
+		mmdbString("node_count"), binary.BigEndian.AppendUint32([]byte{0xC4}, uint32(nodeCount)),
+		mmdbString("record_size"), binary.BigEndian.AppendUint16([]byte{0xA2}, uint16(recordSize)),
+		mmdbString("ip_version"), binary.BigEndian.AppendUint16([]byte{0xA2}, uint16(ipVersion)),
+	)
+	return concat(tree, make([]byte, dataSectionSeparator), data, metadataMarker, meta)
+}
+
+func TestMaxMindDB_Country(t *testing.T) {
+	records := [][]byte{recordKZ, recordRegistered, recordDE}
+	for _, tc := range []struct {
+		ipVersion, recordSize int
+	}{{4, 24}, {4, 28}, {6, 28}, {6, 32}} {
+		networks := map[string]int{"1.0.0.0/8": 0, "2.0.0.0/8": 1, "3.0.0.0/16": 2}
+		if tc.ipVersion == 6 {
+			networks["2a00::/16"] = 2
+		}
+		db, err := NewMaxMindDB(buildMMDB(t, tc.ipVersion, tc.recordSize, networks, records))
+		require.NoError(t, err)
+
+		cases := map[string]string{
+			"1.2.3.4":   "KZ",
+			"2.200.0.1": "KZ", // registered_country через указатель
+			"3.0.9.9":   "DE",
+			"3.1.0.1":   "",
+			"8.8.8.8":   "",
+		}
+		if tc.ipVersion == 6 {
+			cases["2a00:1450::1"] = "DE"
+		} else {
+			cases["2a00:1450::1"] = ""
+		}
+		for ip, want := range cases {
+			country, err := db.Country(context.Background(), net.ParseIP(ip))
+			require.NoError(t, err, "%s (v%d/%d)", ip, tc.ipVersion, tc.recordSize)
+			assert.Equal(t, want, country, "%s (v%d/%d)", ip, tc.ipVersion, tc.recordSize)
+		}
+	}
+}
+
+func TestNewMaxMindDB_RejectsCorruptFile(t *testing.T) {
+	_, err := NewMaxMindDB([]byte("not a database"))
+	assert.ErrorIs(t, err, errCorruptDB)
+
+	raw := buildMMDB(t, 4, 24, map[string]int{"1.0.0.0/8": 0}, [][]byte{recordKZ})
+	_, err = NewMaxMindDB(raw[len(raw)-60:]) // дерево обрезано
+	assert.Error(t, err)
+}
+
+func TestMaxMindWebService_Country(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, key, _ := r.BasicAuth()
+		if user != "42" || key != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"code":"AUTHORIZATION_INVALID","error":"invalid license key"}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/geoip/v2.1/country/5.34.0.1":
+			_, _ = w.Write([]byte(`{"country":{"iso_code":"KZ"},"registered_country":{"iso_code":"RU"}}`))
+		case "/geoip/v2.1/country/5.34.0.2":
+			_, _ = w.Write([]byte(`{"registered_country":{"iso_code":"RU"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":"IP_ADDRESS_NOT_FOUND","error":"not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	ws, err := NewMaxMindWebService(server.URL, "42", "secret", time.Second)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	country, err := ws.Country(ctx, net.ParseIP("5.34.0.1"))
+	require.NoError(t, err)
+	assert.Equal(t, "KZ", country)
+	country, err = ws.Country(ctx, net.ParseIP("5.34.0.2"))
+	require.NoError(t, err)
+	assert.Equal(t, "RU", country)
+	country, err = ws.Country(ctx, net.ParseIP("5.34.0.3"))
+	require.NoError(t, err)
+	assert.Equal(t, "", country, "адреса нет в базе — страна неизвестна")
+
+	wrongKey, err := NewMaxMindWebService(server.URL, "42", "wrong", time.Second)
+	require.NoError(t, err)
+	_, err = wrongKey.Country(ctx, net.ParseIP("5.34.0.1"))
+	assert.ErrorContains(t, err, "AUTHORIZATION_INVALID")
+}
+
+// countingResolver считает обращения к провайдеру
+type countingResolver struct {
+	calls int
+}
+
+func (r *countingResolver) Name() string { return "counting" }
+
+func (r *countingResolver) Country(context.Context, net.IP) (string, error) {
+	r.calls++
+	return "KZ", nil
+}
+
+func TestCachedResolver(t *testing.T) {
+	inner := &countingResolver{}
+	cached := NewCachedResolver(inner, time.Hour)
+	for i := 0; i < 3; i++ {
+		country, err := cached.Country(context.Background(), net.ParseIP("5.34.0.1"))
+		require.NoError(t, err)
+		assert.Equal(t, "KZ", country)
+	}
+	assert.Equal(t, 1, inner.calls)
+}
+
+func TestIsPublicAndNormalizeCountry(t *testing.T) {
+	assert.True(t, IsPublic(net.ParseIP("5.34.0.1")))
+	assert.False(t, IsPublic(net.ParseIP("10.1.2.3")))
+	assert.False(t, IsPublic(net.ParseIP("127.0.0.1")))
+	assert.False(t, IsPublic(net.ParseIP("fe80::1")))
+	assert.False(t, IsPublic(nil))
+
+	assert.Equal(t, "KZ", NormalizeCountry(" kz "))
+	assert.Equal(t, "", NormalizeCountry("KAZ"))
+	assert.Equal(t, "", NormalizeCountry("k1"))
+}
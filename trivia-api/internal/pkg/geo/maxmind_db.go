@@ -0,0 +1,347 @@
+package geo
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"os"
+)
+
+// metadataMarker предшествует метаданным в конце файла .mmdb
+var metadataMarker = []byte("\xAB\xCD\xEFMaxMind.com")
+
+// dataSectionSeparator — нулевые байты между деревом поиска и секцией данных
+const dataSectionSeparator = 16
+
+// Типы полей секции данных формата MaxMind DB
+const (
+	typeExtended  = 0
+	typePointer   = 1
+	typeString    = 2
+	typeDouble    = 3
+	typeBytes     = 4
+	typeUint16    = 5
+	typeUint32    = 6
+	typeMap       = 7
+	typeInt32     = 8
+	typeUint64    = 9
+	typeUint128   = 10
+	typeArray     = 11
+	typeContainer = 12
+	typeEndMarker = 13
+	typeBool      = 14
+	typeFloat     = 15
+)
+
+var errCorruptDB = errors.New("corrupt MaxMind database")
+
+// MaxMindDB — база MaxMind (GeoIP2/GeoLite2 Country или City), загруженная в память.
+// Читается без внешних зависимостей; безопасна для одновременного использования.
+type MaxMindDB struct {
+	tree       []byte
+	data       decoder
+	nodeCount  uint
+	recordSize uint
+	ipVersion  uint
+	ipv4Start  uint // узел, с которого ищутся IPv4-адреса в базе IPv6 (после 96 нулевых бит)
+}
+
+// OpenMaxMindDB загружает базу из файла .mmdb
+func OpenMaxMindDB(path string) (*MaxMindDB, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read MaxMind database: %w", err)
+	}
+	return NewMaxMindDB(raw)
+}
+
+// NewMaxMindDB разбирает содержимое файла .mmdb
+func NewMaxMindDB(raw []byte) (*MaxMindDB, error) {
+	markerAt := bytes.LastIndex(raw, metadataMarker)
+	if markerAt < 0 {
+		return nil, fmt.Errorf("%w: metadata not found", errCorruptDB)
+	}
+	metaValue, _, err := decoder{buf: raw[markerAt+len(metadataMarker):]}.decode(0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode MaxMind metadata: %w", err)
+	}
+	meta, ok := metaValue.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: metadata is not a map", errCorruptDB)
+	}
+	db := &MaxMindDB{
+		nodeCount:  metaUint(meta, "node_count"),
+		recordSize: metaUint(meta, "record_size"),
+		ipVersion:  metaUint(meta, "ip_version"),
+	}
+	if db.recordSize != 24 && db.recordSize != 28 && db.recordSize != 32 {
+		return nil, fmt.Errorf("%w: unsupported record size %d", errCorruptDB, db.recordSize)
+	}
+	if db.ipVersion != 4 && db.ipVersion != 6 {
+		return nil, fmt.Errorf("%w: unsupported ip version %d", errCorruptDB, db.ipVersion)
+	}
+	treeSize := db.nodeCount * db.recordSize / 4
+	if treeSize+dataSectionSeparator > uint(markerAt) {
+		return nil, fmt.Errorf("%w: search tree exceeds file size", errCorruptDB)
+	}
+	db.tree = raw[:treeSize]
+	db.data = decoder{buf: raw[treeSize+dataSectionSeparator : markerAt]}
+
+	if db.ipVersion == 6 {
+		node := uint(0)
+		for i := 0; i < 96 && node < db.nodeCount; i++ {
+			node = db.readNode(node, 0)
+		}
+		db.ipv4Start = node
+	}
+	return db, nil
+}
+
+// Name возвращает имя провайдера
+func (db *MaxMindDB) Name() string { return "maxmind_db" }
+
+// Country возвращает страну адреса: country.iso_code записи, иначе registered_country.iso_code
+func (db *MaxMindDB) Country(_ context.Context, ip net.IP) (string, error) {
+	record, err := db.lookup(ip)
+	if err != nil || record == nil {
+		return "", err
+	}
+	for _, key := range []string{"country", "registered_country"} {
+		if country, ok := record[key].(map[string]interface{}); ok {
+			if code, ok := country["iso_code"].(string); ok && code != "" {
+				return NormalizeCountry(code), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// lookup возвращает запись адреса; nil — адреса нет в базе
+func (db *MaxMindDB) lookup(ip net.IP) (map[string]interface{}, error) {
+	node := uint(0)
+	bits := ip.To4()
+	switch {
+	case bits != nil && db.ipVersion == 6:
+		node = db.ipv4Start
+	case bits == nil && db.ipVersion == 4:
+		return nil, nil // IPv6-адреса в базе IPv4 нет
+	case bits == nil:
+		if bits = ip.To16(); bits == nil {
+			return nil, fmt.Errorf("invalid IP address %q", ip)
+		}
+	}
+
+	for i := 0; i < len(bits)*8 && node < db.nodeCount; i++ {
+		bit := (bits[i>>3] >> (7 - uint(i&7))) & 1
+		node = db.readNode(node, bit)
+	}
+	if node <= db.nodeCount {
+		return nil, nil // node == nodeCount — адрес не найден
+	}
+	offset := node - db.nodeCount - dataSectionSeparator
+	value, _, err := db.data.decode(offset)
+	if err != nil {
+		return nil, err
+	}
+	record, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: record is not a map", errCorruptDB)
+	}
+	return record, nil
+}
+
+// readNode возвращает левую (bit=0) или правую (bit=1) запись узла дерева поиска
+func (db *MaxMindDB) readNode(node uint, bit byte) uint {
+	b := db.tree
+	switch db.recordSize {
+	case 24:
+		off := node*6 + uint(bit)*3
+		return uint(b[off])<<16 | uint(b[off+1])<<8 | uint(b[off+2])
+	case 28:
+		off := node * 7
+		if bit == 0 {
+			return uint(b[off+3]>>4)<<24 | uint(b[off])<<16 | uint(b[off+1])<<8 | uint(b[off+2])
+		}
+		return uint(b[off+3]&0x0f)<<24 | uint(b[off+4])<<16 | uint(b[off+5])<<8 | uint(b[off+6])
+	default:
+		off := node*8 + uint(bit)*4
+		return uint(binary.BigEndian.Uint32(b[off : off+4]))
+	}
+}
+
+func metaUint(meta map[string]interface{}, key string) uint {
+	switch v := meta[key].(type) {
+	case uint64:
+		return uint(v)
+	case int64:
+		return uint(v)
+	}
+	return 0
+}
+
+// decoder читает значения секции данных; смещения указателей отсчитываются от начала buf
+type decoder struct {
+	buf []byte
+}
+
+func (d decoder) decode(offset uint) (interface{}, uint, error) {
+	if offset >= uint(len(d.buf)) {
+		return nil, 0, fmt.Errorf("%w: offset %d out of range", errCorruptDB, offset)
+	}
+	ctrl := d.buf[offset]
+	offset++
+	typ := ctrl >> 5
+	if typ == typePointer {
+		target, next, err := d.pointer(ctrl, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		value, _, err := d.decode(target)
+		return value, next, err
+	}
+	if typ == typeExtended {
+		if offset >= uint(len(d.buf)) {
+			return nil, 0, fmt.Errorf("%w: truncated type", errCorruptDB)
+		}
+		typ = 7 + d.buf[offset]
+		offset++
+	}
+	size, offset, err := d.size(ctrl, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	return d.value(typ, size, offset)
+}
+
+func (d decoder) pointer(ctrl byte, offset uint) (uint, uint, error) {
+	n := uint((ctrl>>3)&0x3) + 1
+	raw, err := d.bytes(offset, n)
+	if err != nil {
+		return 0, 0, err
+	}
+	var prefix uint
+	if n < 4 {
+		prefix = uint(ctrl & 0x7)
+	}
+	value := prefix
+	for _, b := range raw {
+		value = value<<8 | uint(b)
+	}
+	switch n {
+	case 2:
+		value += 2048
+	case 3:
+		value += 526336
+	}
+	return value, offset + n, nil
+}
+
+func (d decoder) size(ctrl byte, offset uint) (uint, uint, error) {
+	size := uint(ctrl & 0x1f)
+	if size < 29 {
+		return size, offset, nil
+	}
+	n := size - 28
+	raw, err := d.bytes(offset, n)
+	if err != nil {
+		return 0, 0, err
+	}
+	var extra uint
+	for _, b := range raw {
+		extra = extra<<8 | uint(b)
+	}
+	switch n {
+	case 1:
+		size = 29 + extra
+	case 2:
+		size = 285 + extra
+	default:
+		size = 65821 + extra
+	}
+	return size, offset + n, nil
+}
+
+func (d decoder) bytes(offset, n uint) ([]byte, error) {
+	if offset+n > uint(len(d.buf)) {
+		return nil, fmt.Errorf("%w: value out of range", errCorruptDB)
+	}
+	return d.buf[offset : offset+n], nil
+}
+
+func (d decoder) value(typ byte, size, offset uint) (interface{}, uint, error) {
+	switch typ {
+	case typeMap:
+		m := make(map[string]interface{}, size)
+		for i := uint(0); i < size; i++ {
+			key, next, err := d.decode(offset)
+			if err != nil {
+				return nil, 0, err
+			}
+			name, ok := key.(string)
+			if !ok {
+				return nil, 0, fmt.Errorf("%w: map key is not a string", errCorruptDB)
+			}
+			value, next, err := d.decode(next)
+			if err != nil {
+				return nil, 0, err
+			}
+			m[name] = value
+			offset = next
+		}
+		return m, offset, nil
+	case typeArray:
+		items := make([]interface{}, 0, size)
+		for i := uint(0); i < size; i++ {
+			value, next, err := d.decode(offset)
+			if err != nil {
+				return nil, 0, err
+			}
+			items = append(items, value)
+			offset = next
+		}
+		return items, offset, nil
+	case typeBool:
+		return size != 0, offset, nil
+	case typeContainer, typeEndMarker:
+		return nil, offset, nil
+	}
+
+	raw, err := d.bytes(offset, size)
+	if err != nil {
+		return nil, 0, err
+	}
+	next := offset + size
+	switch typ {
+	case typeString:
+		return string(raw), next, nil
+	case typeBytes, typeUint128:
+		return raw, next, nil
+	case typeDouble:
+		if size != 8 {
+			return nil, 0, fmt.Errorf("%w: double of size %d", errCorruptDB, size)
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(raw)), next, nil
+	case typeFloat:
+		if size != 4 {
+			return nil, 0, fmt.Errorf("%w: float of size %d", errCorruptDB, size)
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(raw))), next, nil
+	case typeUint16, typeUint32, typeUint64:
+		var v uint64
+		for _, b := range raw {
+			v = v<<8 | uint64(b)
+		}
+		return v, next, nil
+	case typeInt32:
+		var v uint32
+		for _, b := range raw {
+			v = v<<8 | uint32(b)
+		}
+		return int64(int32(v)), next, nil
+	}
+	return nil, 0, fmt.Errorf("%w: unknown type %d", errCorruptDB, typ)
+}
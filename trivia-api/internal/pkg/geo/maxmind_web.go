@@ -0,0 +1,84 @@
+package geo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// MaxMindWebService обращается к веб-сервису MaxMind GeoIP2 Precision (Country)
+type MaxMindWebService struct {
+	baseURL    string
+	accountID  string
+	licenseKey string
+	client     *http.Client
+}
+
+// NewMaxMindWebService создает клиент веб-сервиса; пустой baseURL — https://geoip.maxmind.com
+func NewMaxMindWebService(baseURL, accountID, licenseKey string, timeout time.Duration) (*MaxMindWebService, error) {
+	if accountID == "" || licenseKey == "" {
+		return nil, fmt.Errorf("maxmind account id and license key are required")
+	}
+	if baseURL == "" {
+		baseURL = "https://geoip.maxmind.com"
+	}
+	return &MaxMindWebService{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		accountID:  accountID,
+		licenseKey: licenseKey,
+		client:     &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Name возвращает имя провайдера
+func (s *MaxMindWebService) Name() string { return "maxmind_web" }
+
+// Country запрашивает страну адреса; адрес, которого нет в базе сервиса, — пустая строка
+func (s *MaxMindWebService) Country(ctx context.Context, ip net.IP) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL+"/geoip/v2.1/country/"+ip.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth(s.accountID, s.licenseKey)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("maxmind request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("failed to read maxmind response: %w", err)
+	}
+
+	var result struct {
+		Country struct {
+			ISOCode string `json:"iso_code"`
+		} `json:"country"`
+		RegisteredCountry struct {
+			ISOCode string `json:"iso_code"`
+		} `json:"registered_country"`
+		Code  string `json:"code"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil && resp.StatusCode == http.StatusOK {
+		return "", fmt.Errorf("failed to decode maxmind response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		switch result.Code {
+		case "IP_ADDRESS_NOT_FOUND", "IP_ADDRESS_RESERVED":
+			return "", nil
+		}
+		return "", fmt.Errorf("maxmind returned status %d: %s %s", resp.StatusCode, result.Code, result.Error)
+	}
+	if result.Country.ISOCode != "" {
+		return NormalizeCountry(result.Country.ISOCode), nil
+	}
+	return NormalizeCountry(result.RegisteredCountry.ISOCode), nil
+}
@@ -0,0 +1,225 @@
+// Package httpcache кеширует готовые ответы публичных GET-эндпоинтов в Redis и отвечает
+// 304 Not Modified на If-None-Match, чтобы опрашивающие клиенты не нагружали базу.
+//
+// Ответы группируются по пространствам имён (NamespaceQuizzes, NamespaceLeaderboard).
+// Invalidate увеличивает версию пространства: ключи старой версии больше не читаются
+// и истекают по TTL, поэтому инвалидация одинаково работает на всех инстансах.
+package httpcache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// Пространства имён кешируемых ответов
+const (
+	NamespaceQuizzes     = "quizzes"     // списки викторин (ListQuizzes, GetScheduledQuizzes)
+	NamespaceLeaderboard = "leaderboard" // лидерборд
+)
+
+const keyPrefix = "httpcache:"
+
+// Invalidator сбрасывает закешированные ответы пространств имён.
+// Его принимают сервисы, изменяющие данные кешируемых эндпоинтов.
+type Invalidator interface {
+	Invalidate(namespaces ...string)
+}
+
+// Store хранит ответы в CacheRepository
+type Store struct {
+	cache repository.CacheRepository
+	ttl   time.Duration
+	vary  []string
+}
+
+// entry — закешированный ответ
+type entry struct {
+	ETag        string `json:"etag"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body"`
+}
+
+// New создает хранилище ответов. ttl ограничивает срок жизни ответа, если данные изменились
+// в обход Invalidate. vary — заголовки запроса, от которых зависит тело ответа.
+func New(cache repository.CacheRepository, ttl time.Duration, vary ...string) *Store {
+	return &Store{cache: cache, ttl: ttl, vary: vary}
+}
+
+// Invalidate сбрасывает ответы указанных пространств имён
+func (s *Store) Invalidate(namespaces ...string) {
+	for _, namespace := range namespaces {
+		if _, err := s.cache.Increment(versionKey(namespace)); err != nil {
+			log.Printf("[HTTPCache] Ошибка инвалидации %s: %v", namespace, err)
+		}
+	}
+}
+
+// Middleware кеширует успешные (200) ответы GET-запросов в пространстве namespace.
+// Ключ ответа — путь с query-параметрами и значения заголовков vary.
+// Ответ отдаётся с ETag и Cache-Control: no-cache, чтобы клиенты перепроверяли его через If-None-Match.
+func (s *Store) Middleware(namespace string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.Next()
+			return
+		}
+		cache := s.cache.WithContext(c.Request.Context())
+
+		version, err := cache.Get(versionKey(namespace))
+		if errors.Is(err, apperrors.ErrNotFound) {
+			version = "0"
+		} else if err != nil {
+			// Redis недоступен: отвечаем без кеша
+			c.Next()
+			return
+		}
+		key := s.entryKey(namespace, version, c)
+
+		var cached entry
+		if err := cache.GetJSON(key, &cached); err == nil {
+			c.Header("X-Cache", "HIT")
+			s.write(c, &cached)
+			c.Abort()
+			return
+		}
+
+		original := c.Writer
+		buffered := &bufferedWriter{ResponseWriter: original}
+		c.Writer = buffered
+		defer func() { c.Writer = original }()
+
+		c.Next()
+
+		c.Writer = original
+		if !buffered.Written() {
+			// Обработчик ничего не записал (например, передал ошибку через c.Error)
+			return
+		}
+		if buffered.statusCode() != http.StatusOK || len(c.Errors) > 0 {
+			original.WriteHeader(buffered.statusCode())
+			_, _ = original.Write(buffered.body.Bytes())
+			return
+		}
+
+		fresh := &entry{
+			ETag:        etagFor(buffered.body.Bytes()),
+			ContentType: original.Header().Get("Content-Type"),
+			Body:        buffered.body.Bytes(),
+		}
+		if err := cache.SetJSON(key, fresh, s.ttl); err != nil {
+			log.Printf("[HTTPCache] Ошибка сохранения ответа %s: %v", c.Request.URL.Path, err)
+		}
+		c.Header("X-Cache", "MISS")
+		s.write(c, fresh)
+	}
+}
+
+// write отправляет закешированный ответ или 304, если у клиента та же версия
+func (s *Store) write(c *gin.Context, e *entry) {
+	c.Header("ETag", e.ETag)
+	c.Header("Cache-Control", "no-cache")
+	if len(s.vary) > 0 {
+		c.Header("Vary", strings.Join(s.vary, ", "))
+	}
+	if etagMatches(c.GetHeader("If-None-Match"), e.ETag) {
+		c.Status(http.StatusNotModified)
+		c.Writer.WriteHeaderNow()
+		return
+	}
+	c.Data(http.StatusOK, e.ContentType, e.Body)
+}
+
+func (s *Store) entryKey(namespace, version string, c *gin.Context) string {
+	var b strings.Builder
+	b.WriteString(keyPrefix)
+	b.WriteString(namespace)
+	b.WriteString(":")
+	b.WriteString(version)
+	b.WriteString(":")
+	b.WriteString(c.Request.URL.RequestURI())
+	for _, header := range s.vary {
+		b.WriteString("|")
+		b.WriteString(c.GetHeader(header))
+	}
+	return b.String()
+}
+
+func versionKey(namespace string) string {
+	return keyPrefix + "version:" + namespace
+}
+
+func etagFor(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches сравнивает If-None-Match с ETag (слабое сравнение, список через запятую, «*»)
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// bufferedWriter накапливает ответ обработчика, чтобы до отправки посчитать ETag
+type bufferedWriter struct {
+	gin.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	w.status = code
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	w.WriteHeaderNow()
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	w.WriteHeaderNow()
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) Status() int {
+	return w.statusCode()
+}
+
+func (w *bufferedWriter) Size() int {
+	return w.body.Len()
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.status != 0
+}
+
+func (w *bufferedWriter) statusCode() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
@@ -0,0 +1,154 @@
+package httpcache
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// memoryCache — CacheRepository в памяти с методами, которые использует Store
+type memoryCache struct {
+	repository.CacheRepository
+	values map[string]string
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{values: map[string]string{}}
+}
+
+func (m *memoryCache) WithContext(ctx context.Context) repository.CacheRepository { return m }
+
+func (m *memoryCache) Get(key string) (string, error) {
+	value, ok := m.values[key]
+	if !ok {
+		return "", apperrors.ErrNotFound
+	}
+	return value, nil
+}
+
+func (m *memoryCache) Increment(key string) (int64, error) {
+	n, _ := strconv.ParseInt(m.values[key], 10, 64)
+	n++
+	m.values[key] = strconv.FormatInt(n, 10)
+	return n, nil
+}
+
+func (m *memoryCache) SetJSON(key string, value interface{}, expiration time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	m.values[key] = string(data)
+	return nil
+}
+
+func (m *memoryCache) GetJSON(key string, dest interface{}) error {
+	value, ok := m.values[key]
+	if !ok {
+		return apperrors.ErrNotFound
+	}
+	return json.Unmarshal([]byte(value), dest)
+}
+
+func setupRouter(store *Store, calls *int, payload *string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/quizzes", store.Middleware(NamespaceQuizzes), func(c *gin.Context) {
+		*calls++
+		c.JSON(http.StatusOK, gin.H{"title": *payload})
+	})
+	router.GET("/broken", store.Middleware(NamespaceQuizzes), func(c *gin.Context) {
+		*calls++
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "boom"})
+	})
+	return router
+}
+
+func get(router *gin.Engine, path, ifNoneMatch string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestMiddleware_CachesAndRevalidates(t *testing.T) {
+	store := New(newMemoryCache(), time.Minute)
+	calls, payload := 0, "first"
+	router := setupRouter(store, &calls, &payload)
+
+	first := get(router, "/quizzes?page=1", "")
+	require.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.Equal(t, "MISS", first.Header().Get("X-Cache"))
+	assert.JSONEq(t, `{"title":"first"}`, first.Body.String())
+
+	// Повторный запрос отдаётся из кеша без вызова обработчика
+	second := get(router, "/quizzes?page=1", "")
+	assert.Equal(t, "HIT", second.Header().Get("X-Cache"))
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, 1, calls)
+
+	// Клиент с актуальной версией получает 304 без тела
+	notModified := get(router, "/quizzes?page=1", etag)
+	assert.Equal(t, http.StatusNotModified, notModified.Code)
+	assert.Empty(t, notModified.Body.String())
+	assert.Equal(t, 1, calls)
+
+	// Другие query-параметры — другой ключ
+	get(router, "/quizzes?page=2", "")
+	assert.Equal(t, 2, calls)
+}
+
+func TestMiddleware_Invalidate(t *testing.T) {
+	store := New(newMemoryCache(), time.Minute)
+	calls, payload := 0, "first"
+	router := setupRouter(store, &calls, &payload)
+
+	etag := get(router, "/quizzes", "").Header().Get("ETag")
+
+	payload = "second"
+	store.Invalidate(NamespaceQuizzes)
+
+	fresh := get(router, "/quizzes", etag)
+	assert.Equal(t, http.StatusOK, fresh.Code)
+	assert.JSONEq(t, `{"title":"second"}`, fresh.Body.String())
+	assert.NotEqual(t, etag, fresh.Header().Get("ETag"))
+	assert.Equal(t, 2, calls)
+}
+
+func TestMiddleware_DoesNotCacheErrors(t *testing.T) {
+	store := New(newMemoryCache(), time.Minute)
+	calls, payload := 0, ""
+	router := setupRouter(store, &calls, &payload)
+
+	for i := 0; i < 2; i++ {
+		w := get(router, "/broken", "")
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Empty(t, w.Header().Get("ETag"))
+	}
+	assert.Equal(t, 2, calls)
+}
+
+func TestEtagMatches(t *testing.T) {
+	assert.True(t, etagMatches(`"abc"`, `"abc"`))
+	assert.True(t, etagMatches(`W/"abc"`, `"abc"`))
+	assert.True(t, etagMatches(`"x", "abc"`, `"abc"`))
+	assert.True(t, etagMatches(`*`, `"abc"`))
+	assert.False(t, etagMatches(``, `"abc"`))
+	assert.False(t, etagMatches(`"abd"`, `"abc"`))
+}
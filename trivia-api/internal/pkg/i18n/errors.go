@@ -0,0 +1,198 @@
+package i18n
+
+// catalogFallbackLocale — язык, на котором в каталоге есть все сообщения
+const catalogFallbackLocale = "en"
+
+// errorCatalog — сообщения об ошибках API по коду (error_type) и языку
+var errorCatalog = map[string]map[string]string{
+	"feature_disabled": {
+		"ru": "Функция отключена",
+		"kk": "Функция өшірілген",
+		"en": "Feature is disabled",
+	},
+	"link_required": {
+		"ru": "Аккаунт Google нужно привязать явно",
+		"kk": "Google аккаунтын тікелей байланыстыру қажет",
+		"en": "Google account requires explicit linking",
+	},
+	"email_not_verified": {
+		"ru": "Email не подтверждён",
+		"kk": "Email расталмаған",
+		"en": "Email is not verified",
+	},
+	"invalid_verification_code": {
+		"ru": "Неверный код подтверждения",
+		"kk": "Растау коды қате",
+		"en": "Invalid verification code",
+	},
+	"verification_expired": {
+		"ru": "Срок действия кода подтверждения истёк",
+		"kk": "Растау кодының мерзімі өтті",
+		"en": "Verification code expired",
+	},
+	"verification_attempts_exceeded": {
+		"ru": "Превышено число попыток подтверждения",
+		"kk": "Растау әрекеттерінің саны шектен асты",
+		"en": "Verification attempts exceeded",
+	},
+	"rate_limited": {
+		"ru": "Слишком много запросов, попробуйте позже",
+		"kk": "Сұраныстар тым көп, кейінірек қайталаңыз",
+		"en": "Too many requests, try again later",
+	},
+	"maintenance": {
+		"ru": "Идут технические работы, изменения временно недоступны",
+		"kk": "Техникалық жұмыстар жүріп жатыр, өзгерістер уақытша қолжетімсіз",
+		"en": "Maintenance in progress, changes are temporarily unavailable",
+	},
+	"legal_acceptance_required": {
+		"ru": "Примите обновлённые пользовательское соглашение и политику конфиденциальности",
+		"kk": "Жаңартылған пайдаланушы келісімі мен құпиялылық саясатын қабылдаңыз",
+		"en": "Please accept the updated terms of service and privacy policy",
+	},
+	"invalid_referral_code": {
+		"ru": "Неверный реферальный код",
+		"kk": "Реферал коды қате",
+		"en": "Invalid referral code",
+	},
+	"token_missing": {
+		"ru": "Требуется авторизация",
+		"kk": "Авторизация қажет",
+		"en": "Authentication required",
+	},
+	"token_invalid": {
+		"ru": "Недействительный токен",
+		"kk": "Токен жарамсыз",
+		"en": "Invalid token",
+	},
+	"token_expired": {
+		"ru": "Сессия истекла",
+		"kk": "Сессия мерзімі аяқталды",
+		"en": "Session expired",
+	},
+	"csrf_mismatch": {
+		"ru": "Недействительный CSRF токен",
+		"kk": "CSRF токені жарамсыз",
+		"en": "Invalid CSRF token",
+	},
+	"context_missing_user": {
+		"ru": "Требуется авторизация",
+		"kk": "Авторизация қажет",
+		"en": "Authentication required",
+	},
+	"csrf_secret_cookie_error": {
+		"ru": "CSRF cookie отсутствует или повреждена",
+		"kk": "CSRF cookie жоқ немесе бүлінген",
+		"en": "CSRF secret cookie missing or invalid",
+	},
+	"csrf_secret_cookie_empty": {
+		"ru": "Пустое значение CSRF cookie",
+		"kk": "CSRF cookie мәні бос",
+		"en": "Invalid CSRF secret cookie value",
+	},
+	"session_not_found": {
+		"ru": "Сессия не найдена",
+		"kk": "Сессия табылмады",
+		"en": "Session not found",
+	},
+	"invalid_credentials": {
+		"ru": "Неверные учетные данные",
+		"kk": "Кіру деректері қате",
+		"en": "Invalid credentials",
+	},
+	"token_generation_failed": {
+		"ru": "Ошибка обработки запроса",
+		"kk": "Сұранысты өңдеу қатесі",
+		"en": "Failed to process request",
+	},
+	"too_many_sessions": {
+		"ru": "Превышен лимит активных сессий",
+		"kk": "Белсенді сессиялар саны шектен асты",
+		"en": "Too many active sessions",
+	},
+	"device_proof_invalid": {
+		"ru": "Недействительное подтверждение ключа устройства",
+		"kk": "Құрылғы кілтінің растауы жарамсыз",
+		"en": "Invalid device key proof",
+	},
+	"import_failed": {
+		"ru": "Не удалось загрузить вопросы из источника",
+		"kk": "Дереккөзден сұрақтарды жүктеу мүмкін болмады",
+		"en": "Failed to import questions from the source",
+	},
+	"generation_quota_exceeded": {
+		"ru": "Дневная квота генерации вопросов исчерпана",
+		"kk": "Сұрақтарды генерациялаудың күндік квотасы таусылды",
+		"en": "Daily question generation quota exceeded",
+	},
+	"inappropriate_content": {
+		"ru": "Текст не прошёл модерацию",
+		"kk": "Мәтін модерациядан өтпеді",
+		"en": "Text contains inappropriate content",
+	},
+	"schedule_validation_failed": {
+		"ru": "Время викторины не прошло проверку расписания",
+		"kk": "Викторина уақыты кесте тексеруінен өтпеді",
+		"en": "Quiz schedule failed validation",
+	},
+	"unauthorized": {
+		"ru": "Ошибка аутентификации или неверные данные",
+		"kk": "Аутентификация қатесі немесе деректер қате",
+		"en": "Authentication failed",
+	},
+	"forbidden": {
+		"ru": "Доступ запрещен",
+		"kk": "Кіруге тыйым салынған",
+		"en": "Access denied",
+	},
+	"not_found": {
+		"ru": "Запрашиваемый ресурс не найден",
+		"kk": "Сұралған ресурс табылмады",
+		"en": "Resource not found",
+	},
+	"conflict": {
+		"ru": "Конфликт данных",
+		"kk": "Деректер қайшылығы",
+		"en": "Data conflict",
+	},
+	"validation_error": {
+		"ru": "Ошибка валидации данных",
+		"kk": "Деректерді тексеру қатесі",
+		"en": "Validation error",
+	},
+	"invalid_request": {
+		"ru": "Некорректные данные запроса",
+		"kk": "Сұраныс деректері дұрыс емес",
+		"en": "Invalid request data",
+	},
+	"not_implemented": {
+		"ru": "Операция не поддерживается",
+		"kk": "Операцияға қолдау көрсетілмейді",
+		"en": "Operation is not supported",
+	},
+	"internal_server_error": {
+		"ru": "Внутренняя ошибка сервера",
+		"kk": "Сервердің ішкі қатесі",
+		"en": "Internal server error",
+	},
+}
+
+// ErrorMessage возвращает сообщение для кода ошибки на первом языке цепочки chain,
+// для которого есть перевод (с учётом языка без региона), иначе на английском.
+// Вторым значением возвращается язык сообщения. Неизвестный код возвращается как есть.
+func ErrorMessage(code string, chain []string) (string, string) {
+	messages, ok := errorCatalog[code]
+	if !ok {
+		return code, ""
+	}
+	for _, tag := range chain {
+		tag = Normalize(tag)
+		if message, ok := messages[tag]; ok {
+			return message, tag
+		}
+		if message, ok := messages[base(tag)]; ok {
+			return message, base(tag)
+		}
+	}
+	return messages[catalogFallbackLocale], catalogFallbackLocale
+}
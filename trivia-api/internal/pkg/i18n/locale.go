@@ -0,0 +1,169 @@
+// Package i18n выбирает язык контента: явный выбор клиента, настройка пользователя,
+// заголовок Accept-Language и язык по умолчанию, а также цепочку запасных языков.
+package i18n
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// tagPattern — поддерживаемая форма тега: язык ISO 639-1 и необязательный регион ISO 3166-1
+// ("kk", "pt-BR"). Длина тега не превышает 5 символов — столько вмещает users.language.
+var tagPattern = regexp.MustCompile(`^[a-z]{2}(-[A-Z]{2})?$`)
+
+// Normalize приводит тег языка к каноническому виду: "KK" → "kk", "pt_br" → "pt-BR"
+func Normalize(tag string) string {
+	tag = strings.ReplaceAll(strings.TrimSpace(tag), "_", "-")
+	language, region, found := strings.Cut(tag, "-")
+	if !found {
+		return strings.ToLower(language)
+	}
+	return strings.ToLower(language) + "-" + strings.ToUpper(region)
+}
+
+// IsValidTag проверяет, что тег (после нормализации) имеет поддерживаемую форму
+func IsValidTag(tag string) bool {
+	return tagPattern.MatchString(Normalize(tag))
+}
+
+// base возвращает язык без региона: "pt-BR" → "pt"
+func base(tag string) string {
+	language, _, _ := strings.Cut(tag, "-")
+	return language
+}
+
+// Resolution — выбранный язык и запасные языки в порядке применения
+type Resolution struct {
+	Locale    string   `json:"locale"`
+	Fallbacks []string `json:"fallbacks"`
+}
+
+// Chain возвращает выбранный язык и запасные одним списком
+func (r Resolution) Chain() []string {
+	return append([]string{r.Locale}, r.Fallbacks...)
+}
+
+// Locales — поддерживаемые языки контента и язык по умолчанию
+type Locales struct {
+	defaultLocale string
+	supported     []string
+	set           map[string]bool
+}
+
+// NewLocales создаёт набор языков. Язык по умолчанию всегда считается поддерживаемым.
+func NewLocales(defaultLocale string, supported []string) *Locales {
+	l := &Locales{defaultLocale: Normalize(defaultLocale), set: make(map[string]bool)}
+	for _, tag := range append([]string{defaultLocale}, supported...) {
+		tag = Normalize(tag)
+		if tag == "" || l.set[tag] {
+			continue
+		}
+		l.set[tag] = true
+		l.supported = append(l.supported, tag)
+	}
+	return l
+}
+
+// Default возвращает язык по умолчанию — язык основных полей контента
+func (l *Locales) Default() string {
+	return l.defaultLocale
+}
+
+// Supported возвращает поддерживаемые языки; первым идёт язык по умолчанию
+func (l *Locales) Supported() []string {
+	return append([]string(nil), l.supported...)
+}
+
+// IsSupported проверяет, что язык входит в список поддерживаемых
+func (l *Locales) IsSupported(tag string) bool {
+	return l.set[Normalize(tag)]
+}
+
+// Match возвращает поддерживаемый язык для тега: точное совпадение или тот же язык
+// без региона ("kk-KZ" → "kk"). Пустая строка — подходящего языка нет.
+func (l *Locales) Match(tag string) string {
+	tag = Normalize(tag)
+	if tag == "" {
+		return ""
+	}
+	if l.set[tag] {
+		return tag
+	}
+	if l.set[base(tag)] {
+		return base(tag)
+	}
+	return ""
+}
+
+// Negotiate выбирает язык ответа. Приоритет: явный выбор клиента (?lang=), настройка
+// пользователя, Accept-Language, язык по умолчанию. Запасные языки — тот же язык без
+// региона, затем язык по умолчанию.
+func (l *Locales) Negotiate(explicit, userPreference, acceptLanguage string) Resolution {
+	locale := l.Match(explicit)
+	if locale == "" {
+		locale = l.Match(userPreference)
+	}
+	if locale == "" {
+		for _, tag := range ParseAcceptLanguage(acceptLanguage) {
+			if locale = l.Match(tag); locale != "" {
+				break
+			}
+		}
+	}
+	if locale == "" {
+		locale = l.defaultLocale
+	}
+	return Resolution{Locale: locale, Fallbacks: l.fallbacks(locale)}
+}
+
+func (l *Locales) fallbacks(locale string) []string {
+	fallbacks := []string{}
+	if b := base(locale); b != locale && l.set[b] {
+		fallbacks = append(fallbacks, b)
+	}
+	if locale != l.defaultLocale {
+		fallbacks = append(fallbacks, l.defaultLocale)
+	}
+	return fallbacks
+}
+
+// ParseAcceptLanguage разбирает заголовок Accept-Language и возвращает теги по убыванию
+// веса q. Теги с q=0 и "*" пропускаются.
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if ok && strings.TrimSpace(name) == "q" {
+				parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+				if err != nil {
+					parsed = 0
+				}
+				q = parsed
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		tags = append(tags, weighted{tag: tag, q: q})
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+
+	result := make([]string, len(tags))
+	for i, t := range tags {
+		result[i] = t.tag
+	}
+	return result
+}
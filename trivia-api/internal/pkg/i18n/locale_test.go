@@ -0,0 +1,54 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "kk", Normalize(" KK "))
+	assert.Equal(t, "pt-BR", Normalize("pt_br"))
+	assert.True(t, IsValidTag("en-us"))
+	assert.False(t, IsValidTag("english"))
+}
+
+func TestParseAcceptLanguage_OrdersByWeight(t *testing.T) {
+	tags := ParseAcceptLanguage("en;q=0.5, kk-KZ, *;q=0.1, de;q=0, ru;q=0.8")
+	assert.Equal(t, []string{"kk-KZ", "ru", "en"}, tags)
+	assert.Empty(t, ParseAcceptLanguage(""))
+}
+
+func TestLocales_Negotiate(t *testing.T) {
+	l := NewLocales("ru", []string{"kk", "en"})
+
+	tests := []struct {
+		name                   string
+		explicit, user, accept string
+		wantLocale             string
+		wantFallbacks          []string
+	}{
+		{"default", "", "", "", "ru", []string{}},
+		{"explicit wins", "en", "kk", "kk", "en", []string{"ru"}},
+		{"user preference before header", "", "kk", "en", "kk", []string{"ru"}},
+		{"unsupported explicit is ignored", "de", "", "en-GB,kk;q=0.5", "en", []string{"ru"}},
+		{"header region falls back to base", "", "", "kk-KZ", "kk", []string{"ru"}},
+		{"nothing supported", "", "fr", "de", "ru", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := l.Negotiate(tt.explicit, tt.user, tt.accept)
+			assert.Equal(t, tt.wantLocale, res.Locale)
+			assert.Equal(t, tt.wantFallbacks, res.Fallbacks)
+		})
+	}
+}
+
+func TestLocales_FallbackToBaseLanguage(t *testing.T) {
+	l := NewLocales("ru", []string{"pt", "pt-BR"})
+
+	res := l.Negotiate("pt-br", "", "")
+	assert.Equal(t, "pt-BR", res.Locale)
+	assert.Equal(t, []string{"pt-BR", "pt", "ru"}, res.Chain())
+	assert.Equal(t, []string{"ru", "pt", "pt-BR"}, l.Supported())
+}
@@ -0,0 +1,131 @@
+package i18n
+
+import "strings"
+
+// validationCatalog — сообщения об ошибках валидации полей по правилу и языку.
+// {param} заменяется параметром правила (например, min=3 → 3).
+var validationCatalog = map[string]map[string]string{
+	"required": {
+		"ru": "Обязательное поле",
+		"kk": "Міндетті өріс",
+		"en": "This field is required",
+	},
+	"email": {
+		"ru": "Некорректный email",
+		"kk": "Email дұрыс емес",
+		"en": "Must be a valid email address",
+	},
+	"url": {
+		"ru": "Некорректный URL",
+		"kk": "URL дұрыс емес",
+		"en": "Must be a valid URL",
+	},
+	"min_length": {
+		"ru": "Не короче {param} символов",
+		"kk": "Кемінде {param} таңба",
+		"en": "Must be at least {param} characters long",
+	},
+	"max_length": {
+		"ru": "Не длиннее {param} символов",
+		"kk": "Ең көбі {param} таңба",
+		"en": "Must be at most {param} characters long",
+	},
+	"len_length": {
+		"ru": "Должно содержать ровно {param} символов",
+		"kk": "Дәл {param} таңба болуы керек",
+		"en": "Must be exactly {param} characters long",
+	},
+	"min_items": {
+		"ru": "Не меньше {param} элементов",
+		"kk": "Кемінде {param} элемент",
+		"en": "Must contain at least {param} items",
+	},
+	"max_items": {
+		"ru": "Не больше {param} элементов",
+		"kk": "Ең көбі {param} элемент",
+		"en": "Must contain at most {param} items",
+	},
+	"min": {
+		"ru": "Не меньше {param}",
+		"kk": "Кемінде {param}",
+		"en": "Must be at least {param}",
+	},
+	"max": {
+		"ru": "Не больше {param}",
+		"kk": "Ең көбі {param}",
+		"en": "Must be at most {param}",
+	},
+	"gt": {
+		"ru": "Должно быть больше {param}",
+		"kk": "{param} мәнінен үлкен болуы керек",
+		"en": "Must be greater than {param}",
+	},
+	"lt": {
+		"ru": "Должно быть меньше {param}",
+		"kk": "{param} мәнінен кіші болуы керек",
+		"en": "Must be less than {param}",
+	},
+	"oneof": {
+		"ru": "Допустимые значения: {param}",
+		"kk": "Рұқсат етілген мәндер: {param}",
+		"en": "Must be one of: {param}",
+	},
+	"password_strength": {
+		"ru": "Пароль должен содержать от 8 до 72 символов, хотя бы одну букву и одну цифру",
+		"kk": "Құпиясөз 8-ден 72-ге дейін таңбадан, кемінде бір әріп пен бір цифрдан тұруы керек",
+		"en": "Password must be 8 to 72 characters long and contain at least one letter and one digit",
+	},
+	"username_charset": {
+		"ru": "Допустимы буквы, цифры и символы _ . -; имя должно начинаться с буквы или цифры",
+		"kk": "Әріптер, цифрлар және _ . - таңбалары рұқсат; аты әріптен немесе цифрдан басталуы керек",
+		"en": "Only letters, digits and _ . - are allowed; must start with a letter or digit",
+	},
+	"birth_date": {
+		"ru": "Дата рождения в формате ГГГГ-ММ-ДД, не в будущем и не раньше чем 120 лет назад",
+		"kk": "Туған күні ЖЖЖЖ-АА-КК форматында, болашақта емес және 120 жылдан бұрын емес",
+		"en": "Birth date must be YYYY-MM-DD, not in the future and within the last 120 years",
+	},
+	"min_age": {
+		"ru": "Возраст должен быть не меньше {param} лет",
+		"kk": "Жасы кемінде {param} болуы керек",
+		"en": "Must be at least {param} years old",
+	},
+	"type": {
+		"ru": "Неверный тип значения, ожидается {param}",
+		"kk": "Мән түрі қате, күтілетіні {param}",
+		"en": "Invalid value type, expected {param}",
+	},
+	"json": {
+		"ru": "Тело запроса не является корректным JSON",
+		"kk": "Сұраныс денесі дұрыс JSON емес",
+		"en": "Request body is not valid JSON",
+	},
+	"invalid": {
+		"ru": "Некорректное значение",
+		"kk": "Мән дұрыс емес",
+		"en": "Invalid value",
+	},
+}
+
+// FieldMessage возвращает сообщение об ошибке валидации поля по ключу сообщения на первом языке
+// цепочки chain, для которого есть перевод, иначе на английском. Неизвестный ключ даёт
+// общее сообщение invalid.
+func FieldMessage(key, param string, chain []string) string {
+	messages, ok := validationCatalog[key]
+	if !ok {
+		messages = validationCatalog["invalid"]
+	}
+	message := messages[catalogFallbackLocale]
+	for _, tag := range chain {
+		tag = Normalize(tag)
+		if m, ok := messages[tag]; ok {
+			message = m
+			break
+		}
+		if m, ok := messages[base(tag)]; ok {
+			message = m
+			break
+		}
+	}
+	return strings.ReplaceAll(message, "{param}", strings.ReplaceAll(param, " ", ", "))
+}
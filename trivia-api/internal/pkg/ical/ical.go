@@ -0,0 +1,109 @@
+// Package ical формирует календари iCalendar (RFC 5545) для подписки на расписание викторин.
+package ical
+
+import (
+	"strings"
+	"time"
+)
+
+// maxLineOctets — максимальная длина строки содержимого без CRLF (RFC 5545, 3.1)
+const maxLineOctets = 75
+
+// dateTimeFormat — дата-время в UTC (форма #2, RFC 5545, 3.3.5)
+const dateTimeFormat = "20060102T150405Z"
+
+// Event — одно событие календаря (VEVENT)
+type Event struct {
+	UID         string // стабильный идентификатор: по нему клиенты обновляют событие
+	Start       time.Time
+	End         time.Time // нулевое значение — без DTEND
+	Summary     string
+	Description string
+	Categories  []string
+	Updated     time.Time // LAST-MODIFIED; нулевое значение — не выводится
+}
+
+// Calendar — календарь (VCALENDAR) с набором событий
+type Calendar struct {
+	ProductID string // PRODID, например "-//Trivia//Quiz Schedule//RU"
+	Name      string // X-WR-CALNAME — название календаря в клиентах
+	Events    []Event
+}
+
+// Render сериализует календарь; stamp подставляется в DTSTAMP всех событий
+func (c *Calendar) Render(stamp time.Time) string {
+	var b strings.Builder
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:"+escapeText(c.ProductID))
+	writeLine(&b, "CALSCALE:GREGORIAN")
+	writeLine(&b, "METHOD:PUBLISH")
+	if c.Name != "" {
+		writeLine(&b, "X-WR-CALNAME:"+escapeText(c.Name))
+	}
+	for i := range c.Events {
+		c.Events[i].render(&b, stamp)
+	}
+	writeLine(&b, "END:VCALENDAR")
+	return b.String()
+}
+
+func (e *Event) render(b *strings.Builder, stamp time.Time) {
+	writeLine(b, "BEGIN:VEVENT")
+	writeLine(b, "UID:"+escapeText(e.UID))
+	writeLine(b, "DTSTAMP:"+formatTime(stamp))
+	writeLine(b, "DTSTART:"+formatTime(e.Start))
+	if !e.End.IsZero() {
+		writeLine(b, "DTEND:"+formatTime(e.End))
+	}
+	writeLine(b, "SUMMARY:"+escapeText(e.Summary))
+	if e.Description != "" {
+		writeLine(b, "DESCRIPTION:"+escapeText(e.Description))
+	}
+	if len(e.Categories) > 0 {
+		escaped := make([]string, len(e.Categories))
+		for i, category := range e.Categories {
+			escaped[i] = escapeText(category)
+		}
+		writeLine(b, "CATEGORIES:"+strings.Join(escaped, ","))
+	}
+	if !e.Updated.IsZero() {
+		writeLine(b, "LAST-MODIFIED:"+formatTime(e.Updated))
+	}
+	writeLine(b, "END:VEVENT")
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(dateTimeFormat)
+}
+
+// escapeText экранирует значение типа TEXT: обратную косую черту, запятую, точку с запятой
+// и переводы строк (RFC 5545, 3.3.11)
+func escapeText(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	replacer := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`, "\r", `\n`)
+	return replacer.Replace(s)
+}
+
+// writeLine записывает строку содержимого с CRLF, перенося её по 75 октетов.
+// Перенос не разрывает многобайтовые символы UTF-8.
+func writeLine(b *strings.Builder, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// Строка продолжения начинается с пробела, который входит в лимит
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+func isRuneStart(c byte) bool {
+	return c&0xC0 != 0x80
+}
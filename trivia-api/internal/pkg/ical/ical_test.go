@@ -0,0 +1,58 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalendar_Render(t *testing.T) {
+	start := time.Date(2026, 10, 20, 20, 0, 0, 0, time.FixedZone("ALMT", 5*3600))
+	cal := &Calendar{
+		ProductID: "-//Trivia//Quiz Schedule//RU",
+		Name:      "Викторины",
+		Events: []Event{{
+			UID:         "quiz-7@trivia",
+			Start:       start,
+			End:         start.Add(30 * time.Minute),
+			Summary:     "Кино, музыка; и всё\\прочее",
+			Description: "Фонд: 1000000\nКино",
+			Categories:  []string{"Кино"},
+		}},
+	}
+
+	out := cal.Render(time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC))
+
+	assert.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(out, "END:VEVENT\r\nEND:VCALENDAR\r\n"))
+	assert.Contains(t, out, "DTSTAMP:20261016T120000Z\r\n")
+	assert.Contains(t, out, "DTSTART:20261020T150000Z\r\n")
+	assert.Contains(t, out, "DTEND:20261020T153000Z\r\n")
+	assert.Contains(t, out, `SUMMARY:Кино\, музыка\; и всё\\прочее`+"\r\n")
+	assert.Contains(t, out, `DESCRIPTION:Фонд: 1000000\nКино`+"\r\n")
+	assert.Contains(t, out, "CATEGORIES:Кино\r\n")
+	assert.NotContains(t, out, "LAST-MODIFIED")
+}
+
+func TestWriteLine_FoldsAt75OctetsWithoutSplittingRunes(t *testing.T) {
+	var b strings.Builder
+	writeLine(&b, "SUMMARY:"+strings.Repeat("Ж", 100))
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+	require.Greater(t, len(lines), 1)
+	var unfolded strings.Builder
+	for i, line := range lines {
+		assert.LessOrEqual(t, len(line), maxLineOctets)
+		if i > 0 {
+			require.True(t, strings.HasPrefix(line, " "))
+			line = line[1:]
+		}
+		assert.True(t, utf8.ValidString(line))
+		unfolded.WriteString(line)
+	}
+	assert.Equal(t, "SUMMARY:"+strings.Repeat("Ж", 100), unfolded.String())
+}
@@ -0,0 +1,144 @@
+// Package iprisk оценивает риск IP-адреса подключения (0–100): адреса дата-центров, VPN и Tor
+// по локальным спискам диапазонов и, опционально, по ответу внешнего провайдера (proxycheck.io).
+package iprisk
+
+import (
+	"context"
+	"net"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Категории локальных списков диапазонов (lists/<категория>.txt)
+const (
+	CategoryDatacenter = "datacenter"
+	CategoryVPN        = "vpn"
+	CategoryTor        = "tor"
+)
+
+// Categories — категории списков в порядке загрузки
+var Categories = []string{CategoryDatacenter, CategoryVPN, CategoryTor}
+
+// categoryScores — оценка адреса, попавшего в список категории
+var categoryScores = map[string]int{
+	CategoryDatacenter: 70,
+	CategoryVPN:        90,
+	CategoryTor:        100,
+}
+
+// Assessment — оценка риска адреса
+type Assessment struct {
+	Score   int      `json:"score"`   // 0 — обычный адрес, 100 — заведомо анонимизирующий
+	Reasons []string `json:"reasons"` // "list:vpn", "proxycheck:hosting"
+}
+
+// add учитывает признак: оценка — максимум по признакам
+func (a *Assessment) add(score int, reason string) {
+	if score > a.Score {
+		a.Score = score
+	}
+	a.Reasons = append(a.Reasons, reason)
+}
+
+// Provider — внешний сервис оценки адресов
+type Provider interface {
+	Assess(ctx context.Context, ip net.IP) (Assessment, error)
+	Name() string
+}
+
+// cacheLimit — при переполнении кеш очищается целиком, как в geo.CachedResolver
+const cacheLimit = 100000
+
+type cachedAssessment struct {
+	assessment Assessment
+	expiresAt  time.Time
+}
+
+// Scorer объединяет локальные списки и провайдера. Ответы провайдера кешируются на ttl,
+// ошибки не кешируются. Безопасен для одновременного использования.
+type Scorer struct {
+	provider Provider
+	ttl      time.Duration
+
+	mu      sync.RWMutex
+	lists   *RangeList
+	entries map[string]cachedAssessment
+}
+
+// NewScorer создает оценщик; provider может быть nil — только локальные списки,
+// ttl = 0 — ответы провайдера не кешируются
+func NewScorer(lists *RangeList, provider Provider, ttl time.Duration) *Scorer {
+	return &Scorer{lists: lists, provider: provider, ttl: ttl, entries: make(map[string]cachedAssessment)}
+}
+
+// SetLists заменяет локальные списки (периодическое обновление списков Tor и VPN)
+func (s *Scorer) SetLists(lists *RangeList) {
+	s.mu.Lock()
+	s.lists = lists
+	s.mu.Unlock()
+}
+
+// ProviderName возвращает имя провайдера; пусто — только локальные списки
+func (s *Scorer) ProviderName() string {
+	if s.provider == nil {
+		return ""
+	}
+	return s.provider.Name()
+}
+
+// Score оценивает адрес. При ошибке провайдера возвращается оценка по локальным спискам вместе с ошибкой.
+func (s *Scorer) Score(ctx context.Context, ip net.IP) (Assessment, error) {
+	var assessment Assessment
+	s.mu.RLock()
+	lists := s.lists
+	s.mu.RUnlock()
+	if lists != nil {
+		for _, category := range lists.Lookup(ip) {
+			assessment.add(categoryScores[category], "list:"+category)
+		}
+	}
+	if s.provider == nil {
+		return assessment, nil
+	}
+
+	remote, err := s.providerScore(ctx, ip)
+	if err != nil {
+		return assessment, err
+	}
+	for _, reason := range remote.Reasons {
+		assessment.add(remote.Score, reason)
+	}
+	if remote.Score > assessment.Score {
+		assessment.Score = remote.Score
+	}
+	sort.Strings(assessment.Reasons)
+	return assessment, nil
+}
+
+func (s *Scorer) providerScore(ctx context.Context, ip net.IP) (Assessment, error) {
+	key := ip.String()
+	now := time.Now()
+	if s.ttl > 0 {
+		s.mu.RLock()
+		entry, ok := s.entries[key]
+		s.mu.RUnlock()
+		if ok && now.Before(entry.expiresAt) {
+			return entry.assessment, nil
+		}
+	}
+
+	assessment, err := s.provider.Assess(ctx, ip)
+	if err != nil {
+		return Assessment{}, err
+	}
+	if s.ttl > 0 {
+		s.mu.Lock()
+		if len(s.entries) >= cacheLimit {
+			s.entries = make(map[string]cachedAssessment)
+		}
+		s.entries[key] = cachedAssessment{assessment: assessment, expiresAt: now.Add(s.ttl)}
+		s.mu.Unlock()
+	}
+	return assessment, nil
+}
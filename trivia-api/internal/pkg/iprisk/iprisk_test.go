@@ -0,0 +1,121 @@
+package iprisk
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadRangeLists_Builtin(t *testing.T) {
+	lists, err := LoadRangeLists("")
+	require.NoError(t, err)
+	assert.Greater(t, lists.Len(), 0)
+
+	assert.Equal(t, []string{CategoryDatacenter}, lists.Lookup(net.ParseIP("3.120.0.1")))
+	assert.Equal(t, []string{CategoryDatacenter}, lists.Lookup(net.ParseIP("78.47.255.1")), "сеть /15")
+	assert.Empty(t, lists.Lookup(net.ParseIP("5.34.0.1")))
+	assert.Empty(t, lists.Lookup(net.ParseIP("2a00:1450::1")))
+}
+
+func TestLoadRangeLists_Directory(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "vpn.txt"), []byte("# VPN\n185.65.134.0/23\n2a03:1b20::/32 # comment\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tor.txt"), []byte("185.65.135.7\n"), 0o600))
+
+	lists, err := LoadRangeLists(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{CategoryVPN, CategoryTor}, lists.Lookup(net.ParseIP("185.65.135.7")))
+	assert.Equal(t, []string{CategoryVPN}, lists.Lookup(net.ParseIP("185.65.134.200")))
+	assert.Empty(t, lists.Lookup(net.ParseIP("185.65.136.1")))
+	assert.Equal(t, []string{CategoryVPN}, lists.Lookup(net.ParseIP("2a03:1b20:1::5")))
+	assert.Equal(t, []string{CategoryDatacenter}, lists.Lookup(net.ParseIP("3.120.0.1")), "встроенный список без файла в каталоге")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tor.txt"), []byte("not-an-ip\n"), 0o600))
+	_, err = LoadRangeLists(dir)
+	assert.ErrorContains(t, err, "tor list, line 1")
+}
+
+func TestProxyCheck_Assess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("key") != "secret" {
+			_, _ = w.Write([]byte(`{"status":"denied","message":"invalid key"}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/v2/185.65.135.7":
+			_, _ = w.Write([]byte(`{"status":"ok","185.65.135.7":{"proxy":"yes","type":"VPN","risk":66}}`))
+		case "/v2/8.8.8.8":
+			_, _ = w.Write([]byte(`{"status":"ok","8.8.8.8":{"proxy":"no","type":"Hosting","risk":0}}`))
+		default:
+			_, _ = w.Write([]byte(`{"status":"ok","5.34.0.1":{"proxy":"no","type":"Residential","risk":12}}`))
+		}
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	provider := NewProxyCheck(server.URL, "secret", time.Second)
+	assessment, err := provider.Assess(ctx, net.ParseIP("185.65.135.7"))
+	require.NoError(t, err)
+	assert.Equal(t, Assessment{Score: 90, Reasons: []string{"proxycheck:vpn"}}, assessment)
+
+	assessment, err = provider.Assess(ctx, net.ParseIP("8.8.8.8"))
+	require.NoError(t, err)
+	assert.Equal(t, Assessment{Score: 70, Reasons: []string{"proxycheck:hosting"}}, assessment)
+
+	assessment, err = provider.Assess(ctx, net.ParseIP("5.34.0.1"))
+	require.NoError(t, err)
+	assert.Equal(t, 12, assessment.Score)
+
+	_, err = NewProxyCheck(server.URL, "wrong", time.Second).Assess(ctx, net.ParseIP("5.34.0.1"))
+	assert.ErrorContains(t, err, "invalid key")
+}
+
+// stubProvider считает обращения и может возвращать ошибку
+type stubProvider struct {
+	assessment Assessment
+	err        error
+	calls      int
+}
+
+func (p *stubProvider) Name() string { return "stub" }
+
+func (p *stubProvider) Assess(context.Context, net.IP) (Assessment, error) {
+	p.calls++
+	return p.assessment, p.err
+}
+
+func TestScorer_Score(t *testing.T) {
+	lists, err := LoadRangeLists("")
+	require.NoError(t, err)
+	provider := &stubProvider{assessment: Assessment{Score: 95, Reasons: []string{"proxycheck:vpn"}}}
+	scorer := NewScorer(lists, provider, time.Hour)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		assessment, err := scorer.Score(ctx, net.ParseIP("3.120.0.1"))
+		require.NoError(t, err)
+		assert.Equal(t, 95, assessment.Score)
+		assert.Equal(t, []string{"list:datacenter", "proxycheck:vpn"}, assessment.Reasons)
+	}
+	assert.Equal(t, 1, provider.calls, "ответ провайдера кешируется")
+
+	// Ошибка провайдера: остаётся оценка по спискам
+	failing := NewScorer(lists, &stubProvider{err: errors.New("timeout")}, time.Hour)
+	assessment, err := failing.Score(ctx, net.ParseIP("3.120.0.1"))
+	assert.Error(t, err)
+	assert.Equal(t, 70, assessment.Score)
+
+	local := NewScorer(lists, nil, 0)
+	assessment, err = local.Score(ctx, net.ParseIP("5.34.0.1"))
+	require.NoError(t, err)
+	assert.Equal(t, Assessment{}, assessment)
+}
@@ -0,0 +1,100 @@
+package iprisk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ProxyCheck обращается к API proxycheck.io (v2): признак прокси/VPN, тип адреса и оценка риска
+type ProxyCheck struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+// NewProxyCheck создает клиент proxycheck.io; пустой baseURL — https://proxycheck.io,
+// пустой apiKey — бесплатный лимит запросов без ключа
+func NewProxyCheck(baseURL, apiKey string, timeout time.Duration) *ProxyCheck {
+	if baseURL == "" {
+		baseURL = "https://proxycheck.io"
+	}
+	return &ProxyCheck{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// Name возвращает имя провайдера
+func (p *ProxyCheck) Name() string { return "proxycheck" }
+
+// Assess запрашивает оценку адреса. Прокси и VPN оцениваются не ниже списка VPN,
+// хостинг — не ниже списка дата-центров, иначе — оценка risk сервиса.
+func (p *ProxyCheck) Assess(ctx context.Context, ip net.IP) (Assessment, error) {
+	query := url.Values{"vpn": {"1"}, "risk": {"1"}}
+	if p.apiKey != "" {
+		query.Set("key", p.apiKey)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/v2/"+ip.String()+"?"+query.Encode(), nil)
+	if err != nil {
+		return Assessment{}, err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return Assessment{}, fmt.Errorf("proxycheck request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return Assessment{}, fmt.Errorf("failed to read proxycheck response: %w", err)
+	}
+
+	// Ответ — объект, где данные адреса лежат под ключом самого адреса
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(data, &body); err != nil {
+		return Assessment{}, fmt.Errorf("failed to decode proxycheck response (status %d): %w", resp.StatusCode, err)
+	}
+	var status, message string
+	_ = json.Unmarshal(body["status"], &status)
+	_ = json.Unmarshal(body["message"], &message)
+	if status != "ok" && status != "warning" {
+		return Assessment{}, fmt.Errorf("proxycheck returned status %q: %s", status, message)
+	}
+	raw, ok := body[ip.String()]
+	if !ok {
+		return Assessment{}, nil
+	}
+	var result struct {
+		Proxy string `json:"proxy"`
+		Type  string `json:"type"`
+		Risk  int    `json:"risk"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return Assessment{}, fmt.Errorf("failed to decode proxycheck result: %w", err)
+	}
+
+	assessment := Assessment{Score: result.Risk}
+	kind := strings.ToLower(strings.ReplaceAll(result.Type, " ", "_"))
+	switch {
+	case result.Proxy == "yes":
+		if kind == "" {
+			kind = "proxy"
+		}
+		assessment.add(categoryScores[CategoryVPN], "proxycheck:"+kind)
+	case kind == "hosting":
+		assessment.add(categoryScores[CategoryDatacenter], "proxycheck:"+kind)
+	case result.Risk > 0:
+		assessment.Reasons = append(assessment.Reasons, "proxycheck:risk")
+	}
+	if assessment.Score > 100 {
+		assessment.Score = 100
+	}
+	return assessment, nil
+}
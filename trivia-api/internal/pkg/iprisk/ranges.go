@@ -0,0 +1,145 @@
+package iprisk
+
+import (
+	"bufio"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+//go:embed lists/*.txt
+var builtinLists embed.FS
+
+// prefixSet — набор сетей одной категории. Сети хранятся по длине префикса, поэтому
+// проверка адреса — по одному поиску в карте на каждую встречающуюся длину.
+type prefixSet struct {
+	byLength map[int]map[[16]byte]struct{}
+	lengths  []int // по убыванию
+}
+
+func (p *prefixSet) add(network *net.IPNet) {
+	ones, bits := network.Mask.Size()
+	if bits == 32 {
+		ones += 96 // IPv4 хранится как IPv4-mapped IPv6
+	}
+	key := maskedKey(network.IP.To16(), ones)
+	if p.byLength[ones] == nil {
+		p.byLength[ones] = make(map[[16]byte]struct{})
+		p.lengths = append(p.lengths, ones)
+		sort.Sort(sort.Reverse(sort.IntSlice(p.lengths)))
+	}
+	p.byLength[ones][key] = struct{}{}
+}
+
+func (p *prefixSet) contains(ip16 net.IP) bool {
+	for _, length := range p.lengths {
+		if _, ok := p.byLength[length][maskedKey(ip16, length)]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func maskedKey(ip16 net.IP, ones int) [16]byte {
+	var key [16]byte
+	copy(key[:], ip16)
+	for i := range key {
+		switch {
+		case ones >= 8:
+			ones -= 8
+		case ones > 0:
+			key[i] &= byte(0xff << (8 - ones))
+			ones = 0
+		default:
+			key[i] = 0
+		}
+	}
+	return key
+}
+
+// RangeList — локальные списки сетей по категориям (дата-центры, VPN, Tor)
+type RangeList struct {
+	sets    map[string]*prefixSet
+	entries int
+}
+
+// LoadRangeLists загружает списки категорий. Файл <категория>.txt в dir заменяет встроенный
+// список категории (lists/). Строка — сеть CIDR или отдельный адрес, # — комментарий.
+func LoadRangeLists(dir string) (*RangeList, error) {
+	list := &RangeList{sets: make(map[string]*prefixSet, len(Categories))}
+	for _, category := range Categories {
+		var data []byte
+		var err error
+		if dir != "" {
+			data, err = os.ReadFile(filepath.Join(dir, category+".txt"))
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return nil, fmt.Errorf("failed to read %s list: %w", category, err)
+			}
+		}
+		if data == nil {
+			if data, err = builtinLists.ReadFile("lists/" + category + ".txt"); err != nil {
+				return nil, fmt.Errorf("no builtin list for category %q", category)
+			}
+		}
+		if err := list.parse(category, string(data)); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+func (l *RangeList) parse(category, data string) error {
+	set := &prefixSet{byLength: make(map[int]map[[16]byte]struct{})}
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		if line == "" {
+			continue
+		}
+		if !strings.Contains(line, "/") {
+			if ip := net.ParseIP(line); ip != nil && ip.To4() != nil {
+				line += "/32"
+			} else {
+				line += "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(line)
+		if err != nil {
+			return fmt.Errorf("%s list, line %d: invalid network %q", category, lineNo, line)
+		}
+		set.add(network)
+		l.entries++
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to parse %s list: %w", category, err)
+	}
+	l.sets[category] = set
+	return nil
+}
+
+// Len возвращает общее число сетей в списках
+func (l *RangeList) Len() int { return l.entries }
+
+// Lookup возвращает категории, в списках которых есть адрес
+func (l *RangeList) Lookup(ip net.IP) []string {
+	ip16 := ip.To16()
+	if ip16 == nil {
+		return nil
+	}
+	var categories []string
+	for _, category := range Categories {
+		if set := l.sets[category]; set != nil && set.contains(ip16) {
+			categories = append(categories, category)
+		}
+	}
+	return categories
+}
@@ -0,0 +1,217 @@
+// Package moderation проверяет пользовательский текст (имена, поля профиля, сообщения чата)
+// по спискам слов для каждого языка. Перед сравнением текст нормализуется: регистр, ё→е,
+// leetspeak (f4ck, 3ба), латинские буквы, похожие на кириллические (xуй), и повторы (fuuuck).
+//
+// Формат списка (wordlists/<locale>.txt): строка — слово целиком, слово* — все слова с этого
+// начала, ?строка — пограничный случай (текст принимается, но уходит в очередь модерации),
+// # — комментарий.
+package moderation
+
+import (
+	"bufio"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+//go:embed wordlists/*.txt
+var builtinWordlists embed.FS
+
+// Verdict — решение по тексту
+type Verdict string
+
+const (
+	Allow  Verdict = "allow"  // текст принимается
+	Review Verdict = "review" // текст принимается и уходит в очередь модерации
+	Block  Verdict = "block"  // текст отклоняется
+)
+
+func (v Verdict) rank() int {
+	switch v {
+	case Block:
+		return 2
+	case Review:
+		return 1
+	}
+	return 0
+}
+
+// Match — сработавшая запись списка
+type Match struct {
+	Locale  string  `json:"locale"`
+	Entry   string  `json:"entry"` // запись списка как в файле, без ?
+	Verdict Verdict `json:"verdict"`
+}
+
+// Result — итог проверки текста: самое строгое решение среди совпадений
+type Result struct {
+	Verdict Verdict
+	Matches []Match
+}
+
+// minEmbeddedLength — короче этого слово внутри другого слова или в тексте с разделителями
+// (f.u.c.k) не ищется: у коротких слов слишком много случайных совпадений
+const minEmbeddedLength = 4
+
+type entry struct {
+	locale  string
+	raw     string
+	word    string // нормализованное слово
+	prefix  bool
+	verdict Verdict
+}
+
+// Filter проверяет текст по спискам слов всех подключённых языков: язык имени или сообщения
+// заранее неизвестен. Безопасен для одновременного использования.
+type Filter struct {
+	entries []entry
+}
+
+// LoadWordlists читает списки языков locales. Файл <locale>.txt из dir (если dir задан и файл есть)
+// заменяет встроенный список языка.
+func LoadWordlists(dir string, locales []string) (map[string][]string, error) {
+	lists := make(map[string][]string, len(locales))
+	for _, locale := range locales {
+		var data []byte
+		var err error
+		if dir != "" {
+			data, err = os.ReadFile(filepath.Join(dir, locale+".txt"))
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return nil, fmt.Errorf("failed to read %s wordlist: %w", locale, err)
+			}
+		}
+		if data == nil {
+			if data, err = builtinWordlists.ReadFile("wordlists/" + locale + ".txt"); err != nil {
+				return nil, fmt.Errorf("no wordlist for locale %q", locale)
+			}
+		}
+		scanner := bufio.NewScanner(strings.NewReader(string(data)))
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" && !strings.HasPrefix(line, "#") {
+				lists[locale] = append(lists[locale], line)
+			}
+		}
+	}
+	return lists, nil
+}
+
+// NewFilter создает фильтр по спискам слов (язык → записи в формате файла списка)
+func NewFilter(wordlists map[string][]string) *Filter {
+	f := &Filter{}
+	for locale, lines := range wordlists {
+		for _, line := range lines {
+			e := entry{locale: locale, verdict: Block}
+			if strings.HasPrefix(line, "?") {
+				e.verdict = Review
+				line = line[1:]
+			}
+			e.raw = line
+			if strings.HasSuffix(line, "*") {
+				e.prefix = true
+				line = strings.TrimSuffix(line, "*")
+			}
+			if e.word = normalize(strings.ToLower(line)); e.word != "" {
+				f.entries = append(f.entries, e)
+			}
+		}
+	}
+	return f
+}
+
+// Check проверяет текст. Слово списка целиком (или начало слова для записей со *) даёт решение
+// записи; запрещённое слово внутри другого слова или собранное из разделённых букв (f.u.c.k) —
+// только Review: это может быть и безобидное слово.
+func (f *Filter) Check(text string) Result {
+	result := Result{Verdict: Allow}
+	add := func(e entry, verdict Verdict) {
+		for _, m := range result.Matches {
+			if m.Locale == e.locale && m.Entry == e.raw {
+				return
+			}
+		}
+		result.Matches = append(result.Matches, Match{Locale: e.locale, Entry: e.raw, Verdict: verdict})
+		if verdict.rank() > result.Verdict.rank() {
+			result.Verdict = verdict
+		}
+	}
+
+	tokens := tokenize(text)
+	for _, token := range tokens {
+		for _, e := range f.entries {
+			switch {
+			case token == e.word, e.prefix && strings.HasPrefix(token, e.word):
+				add(e, e.verdict)
+			case utf8.RuneCountInString(e.word) >= minEmbeddedLength && strings.Contains(token, e.word):
+				add(e, Review)
+			}
+		}
+	}
+	if len(tokens) > 1 {
+		compact := strings.Join(tokens, "")
+		for _, e := range f.entries {
+			if e.verdict == Block && utf8.RuneCountInString(e.word) >= minEmbeddedLength && strings.Contains(compact, e.word) {
+				add(e, Review)
+			}
+		}
+	}
+	return result
+}
+
+// tokenize делит текст на нормализованные слова. Символы leetspeak (@, $, !, |) считаются
+// частью слова, остальные знаки — разделителями.
+func tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("@$!|", r)
+	})
+	tokens := fields[:0]
+	for _, field := range fields {
+		if token := normalize(field); token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+// Замены для слов с кириллицей: похожие латинские буквы и цифры (xуй, 3ае6ал)
+var cyrillicFolding = map[rune]rune{
+	'a': 'а', 'b': 'в', 'c': 'с', 'e': 'е', 'h': 'н', 'i': 'і', 'k': 'к', 'm': 'м', 'o': 'о',
+	'p': 'р', 't': 'т', 'x': 'х', 'y': 'у', 'ё': 'е',
+	'0': 'о', '3': 'з', '4': 'ч', '6': 'б', '@': 'а', '$': 'с',
+}
+
+// Замены для латинских слов (leetspeak)
+var latinFolding = map[rune]rune{
+	'0': 'o', '1': 'i', '3': 'e', '4': 'a', '5': 's', '7': 't', '@': 'a', '$': 's', '!': 'i', '|': 'i',
+}
+
+// normalize приводит слово в нижнем регистре к единому написанию и убирает повторы букв
+func normalize(word string) string {
+	folding := latinFolding
+	for _, r := range word {
+		if unicode.Is(unicode.Cyrillic, r) {
+			folding = cyrillicFolding
+			break
+		}
+	}
+	var b strings.Builder
+	var last rune
+	for _, r := range word {
+		if folded, ok := folding[r]; ok {
+			r = folded
+		}
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			continue // символ leetspeak без замены
+		}
+		if r != last {
+			b.WriteRune(r)
+		}
+		last = r
+	}
+	return b.String()
+}
@@ -0,0 +1,79 @@
+package moderation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func builtinFilter(t *testing.T) *Filter {
+	t.Helper()
+	lists, err := LoadWordlists("", []string{"ru", "kk", "en"})
+	require.NoError(t, err)
+	return NewFilter(lists)
+}
+
+func TestFilter_Check(t *testing.T) {
+	filter := builtinFilter(t)
+	cases := []struct {
+		text    string
+		verdict Verdict
+	}{
+		{"Aidos_2001", Allow},
+		{"Айгерим", Allow},
+		{"Scunthorpe", Review}, // запрещённое слово внутри другого
+		{"class_assistant", Allow},
+		{"FuckMaster", Block},
+		{"fuuuuck", Block},
+		{"f4ck3r", Allow}, // f4ck → fack: не совпадает
+		{"sh1t_happens", Block},
+		{"$hit", Block},
+		{"f.u.c.k", Review},
+		{"Пиздец", Block},
+		{"xуйня", Block}, // латинская x
+		{"блядь", Block},
+		{"суки", Block},
+		{"сукно", Allow},
+		{"дурак99", Review},
+		{"admin_official", Review},
+		{"Қаншық", Block},
+		{"сукин-сын", Review}, // «суки» внутри слова — на проверку
+	}
+	for _, tc := range cases {
+		t.Run(tc.text, func(t *testing.T) {
+			result := filter.Check(tc.text)
+			assert.Equal(t, tc.verdict, result.Verdict, "matches: %v", result.Matches)
+			if tc.verdict != Allow {
+				assert.NotEmpty(t, result.Matches)
+			}
+		})
+	}
+}
+
+func TestFilter_MatchesReportLocaleAndEntry(t *testing.T) {
+	result := builtinFilter(t).Check("Ебанат и дурак")
+	assert.Equal(t, Block, result.Verdict)
+	assert.Contains(t, result.Matches, Match{Locale: "ru", Entry: "еба*", Verdict: Block})
+	assert.Contains(t, result.Matches, Match{Locale: "ru", Entry: "дурак*", Verdict: Review})
+}
+
+func TestLoadWordlists_DirOverridesBuiltin(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "en.txt"), []byte("# custom\nbanana\n?kiwi*\n"), 0o600))
+
+	lists, err := LoadWordlists(dir, []string{"en", "ru"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"banana", "?kiwi*"}, lists["en"])
+	assert.NotEmpty(t, lists["ru"], "языки без файла в каталоге берут встроенный список")
+
+	filter := NewFilter(lists)
+	assert.Equal(t, Block, filter.Check("Banana").Verdict)
+	assert.Equal(t, Review, filter.Check("kiwifruit").Verdict)
+	assert.Equal(t, Allow, filter.Check("fuck").Verdict, "встроенный английский список заменён")
+
+	_, err = LoadWordlists("", []string{"de"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,43 @@
+// Package pagination кодирует позиции keyset-пагинации в непрозрачные курсоры next_cursor.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// maxCursorLength ограничивает длину курсора из запроса: настоящие курсоры намного короче
+const maxCursorLength = 512
+
+// EncodeCursor кодирует позицию в непрозрачную строку (base64url от JSON).
+// Клиент не должен разбирать курсор: формат может меняться.
+func EncodeCursor(position interface{}) (string, error) {
+	raw, err := json.Marshal(position)
+	if err != nil {
+		return "", fmt.Errorf("encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// DecodeCursor разбирает курсор, полученный от EncodeCursor, в position.
+// Пустой курсор означает первую страницу: возвращается false без ошибки.
+// Повреждённый курсор — ошибка apperrors.ErrValidation.
+func DecodeCursor(cursor string, position interface{}) (bool, error) {
+	if cursor == "" {
+		return false, nil
+	}
+	if len(cursor) > maxCursorLength {
+		return false, fmt.Errorf("%w: cursor is too long", apperrors.ErrValidation)
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return false, fmt.Errorf("%w: invalid cursor", apperrors.ErrValidation)
+	}
+	if err := json.Unmarshal(raw, position); err != nil {
+		return false, fmt.Errorf("%w: invalid cursor", apperrors.ErrValidation)
+	}
+	return true, nil
+}
@@ -0,0 +1,46 @@
+package pagination
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+type testPosition struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uint      `json:"i"`
+}
+
+func TestCursor_RoundTrip(t *testing.T) {
+	in := testPosition{CreatedAt: time.Date(2025, 3, 1, 12, 30, 0, 123456000, time.UTC), ID: 42}
+	cursor, err := EncodeCursor(in)
+	require.NoError(t, err)
+	assert.NotContains(t, cursor, "=")
+
+	var out testPosition
+	ok, err := DecodeCursor(cursor, &out)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, in.CreatedAt.Equal(out.CreatedAt))
+	assert.Equal(t, in.ID, out.ID)
+}
+
+func TestDecodeCursor_Empty(t *testing.T) {
+	var out testPosition
+	ok, err := DecodeCursor("", &out)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	var out testPosition
+	for _, cursor := range []string{"!!!", "bm90LWpzb24", string(make([]byte, maxCursorLength+1))} {
+		_, err := DecodeCursor(cursor, &out)
+		assert.True(t, errors.Is(err, apperrors.ErrValidation), "cursor %q", cursor)
+	}
+}
@@ -0,0 +1,132 @@
+// Package rating пересчитывает рейтинг мастерства игроков по итогам викторины: результат
+// игрока сравнивается с ожидаемым при силе соперников. Алгоритм выбирается по имени (New),
+// чтобы его можно было заменить или донастроить, не трогая хранение рейтинга.
+package rating
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// AlgorithmElo — многопользовательский Эло: каждый игрок сыгран с каждым
+const AlgorithmElo = "elo"
+
+// Player — участник викторины до пересчёта
+type Player struct {
+	UserID uint
+	Rating int
+	Games  int // викторин с рейтингом до этой
+	Rank   int // место в викторине (1 — лучшее); равные места — ничья, 0 — без места
+}
+
+// Algorithm пересчитывает рейтинги участников одной викторины
+type Algorithm interface {
+	Name() string
+	// Initial — рейтинг игрока до первой викторины
+	Initial() int
+	// Update возвращает новые рейтинги в порядке players
+	Update(players []Player) []int
+}
+
+// Config — параметры алгоритма
+type Config struct {
+	Initial          int     // рейтинг новичка
+	KFactor          float64 // максимальное изменение за викторину
+	ProvisionalK     float64 // K для первых ProvisionalGames викторин: рейтинг новичка быстрее находит уровень
+	ProvisionalGames int
+}
+
+// New создает алгоритм по имени
+func New(name string, cfg Config) (Algorithm, error) {
+	switch name {
+	case AlgorithmElo, "":
+		return NewElo(cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown rating algorithm %q", name)
+	}
+}
+
+// Elo — Эло для викторины с многими участниками. Фактический результат игрока — доля соперников,
+// оказавшихся ниже по месту (ничья — половина), ожидаемый — средняя вероятность победы над
+// соперником по разнице рейтингов. Изменение — K × (фактический − ожидаемый), не больше K.
+type Elo struct {
+	cfg Config
+}
+
+// NewElo создает алгоритм Эло; нулевые параметры заменяются значениями по умолчанию
+func NewElo(cfg Config) *Elo {
+	if cfg.Initial <= 0 {
+		cfg.Initial = 1500
+	}
+	if cfg.KFactor <= 0 {
+		cfg.KFactor = 32
+	}
+	if cfg.ProvisionalK <= 0 {
+		cfg.ProvisionalK = cfg.KFactor
+	}
+	return &Elo{cfg: cfg}
+}
+
+// Name возвращает имя алгоритма
+func (e *Elo) Name() string { return AlgorithmElo }
+
+// Initial возвращает рейтинг новичка
+func (e *Elo) Initial() int { return e.cfg.Initial }
+
+// Update пересчитывает рейтинги. Ожидаемый результат считается по группам одинакового рейтинга,
+// поэтому время растёт с числом различных рейтингов, а не с квадратом числа игроков.
+func (e *Elo) Update(players []Player) []int {
+	n := len(players)
+	updated := make([]int, n)
+	if n < 2 {
+		for i, p := range players {
+			updated[i] = p.Rating
+		}
+		return updated
+	}
+
+	// Игрок без места проигрывает всем, у кого оно есть, и играет вничью с такими же
+	ranks := make([]int, n)
+	for i, p := range players {
+		ranks[i] = p.Rank
+		if ranks[i] <= 0 {
+			ranks[i] = math.MaxInt32
+		}
+	}
+	sorted := append([]int(nil), ranks...)
+	sort.Ints(sorted)
+
+	ratingCounts := make(map[int]int)
+	for _, p := range players {
+		ratingCounts[p.Rating]++
+	}
+
+	opponents := float64(n - 1)
+	for i, p := range players {
+		below := n - sort.SearchInts(sorted, ranks[i]+1)
+		tied := n - below - sort.SearchInts(sorted, ranks[i]) - 1
+		actual := (float64(below) + 0.5*float64(tied)) / opponents
+
+		var expected float64
+		for rating, count := range ratingCounts {
+			if rating == p.Rating {
+				count-- // себя не считаем
+			}
+			expected += float64(count) * winProbability(p.Rating, rating)
+		}
+		expected /= opponents
+
+		k := e.cfg.KFactor
+		if p.Games < e.cfg.ProvisionalGames {
+			k = e.cfg.ProvisionalK
+		}
+		updated[i] = p.Rating + int(math.Round(k*(actual-expected)))
+	}
+	return updated
+}
+
+// winProbability — ожидаемый результат игрока с рейтингом a против соперника с рейтингом b
+func winProbability(a, b int) float64 {
+	return 1 / (1 + math.Pow(10, float64(b-a)/400))
+}
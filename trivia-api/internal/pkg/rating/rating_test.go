@@ -0,0 +1,58 @@
+package rating
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElo_Update(t *testing.T) {
+	elo := NewElo(Config{Initial: 1500, KFactor: 32, ProvisionalK: 64, ProvisionalGames: 5})
+
+	// Равные соперники: победитель получает столько, сколько теряет последний
+	updated := elo.Update([]Player{
+		{UserID: 1, Rating: 1500, Games: 10, Rank: 1},
+		{UserID: 2, Rating: 1500, Games: 10, Rank: 2},
+		{UserID: 3, Rating: 1500, Games: 10, Rank: 3},
+	})
+	assert.Equal(t, []int{1516, 1500, 1484}, updated)
+
+	// Ничья равных — без изменений
+	assert.Equal(t, []int{1500, 1500}, elo.Update([]Player{
+		{Rating: 1500, Games: 10, Rank: 1},
+		{Rating: 1500, Games: 10, Rank: 1},
+	}))
+
+	// Победа над сильным соперником стоит больше, чем над слабым
+	upset := elo.Update([]Player{
+		{Rating: 1300, Games: 10, Rank: 1},
+		{Rating: 1700, Games: 10, Rank: 2},
+	})
+	expected := elo.Update([]Player{
+		{Rating: 1700, Games: 10, Rank: 1},
+		{Rating: 1300, Games: 10, Rank: 2},
+	})
+	assert.Equal(t, 29, upset[0]-1300)
+	assert.Equal(t, 3, expected[0]-1700)
+
+	// Новичок меняется быстрее; игрок без места ниже всех
+	updated = elo.Update([]Player{
+		{Rating: 1500, Games: 0, Rank: 1},
+		{Rating: 1500, Games: 10, Rank: 0},
+	})
+	assert.Equal(t, []int{1532, 1484}, updated)
+
+	// Один участник — рейтинг не меняется
+	assert.Equal(t, []int{1600}, elo.Update([]Player{{Rating: 1600, Rank: 1}}))
+}
+
+func TestNew(t *testing.T) {
+	algorithm, err := New("elo", Config{})
+	require.NoError(t, err)
+	assert.Equal(t, AlgorithmElo, algorithm.Name())
+	assert.Equal(t, 1500, algorithm.Initial())
+
+	_, err = New("glicko", Config{})
+	assert.ErrorContains(t, err, "unknown rating algorithm")
+}
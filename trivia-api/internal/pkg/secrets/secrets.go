@@ -0,0 +1,59 @@
+// Package secrets загружает секреты приложения (ключ шифрования JWT-ключей, пароли, API-ключи)
+// из внешнего источника: переменных окружения, смонтированных файлов или HashiCorp Vault,
+// чтобы в production они не хранились открытым текстом в config.yaml.
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotFound возвращается, если провайдер не знает секрета с таким именем
+var ErrNotFound = errors.New("secret not found")
+
+// Provider возвращает значение секрета по имени (например, "database_password")
+type Provider interface {
+	Get(ctx context.Context, name string) (string, error)
+}
+
+// EnvProvider читает секрет из переменной окружения с именем секрета в верхнем регистре:
+// database_password → DATABASE_PASSWORD
+type EnvProvider struct{}
+
+// Get реализует Provider
+func (EnvProvider) Get(_ context.Context, name string) (string, error) {
+	value, ok := os.LookupEnv(strings.ToUpper(name))
+	if !ok || value == "" {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+// FileProvider читает секрет из файла Dir/<name>: Docker secrets (/run/secrets),
+// Kubernetes Secret, смонтированный как том, или файлы, выложенные Vault Agent
+type FileProvider struct {
+	Dir string
+}
+
+// Get реализует Provider
+func (p FileProvider) Get(_ context.Context, name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return "", fmt.Errorf("invalid secret name %q", name)
+	}
+	data, err := os.ReadFile(filepath.Join(p.Dir, name))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("failed to read secret %s: %w", name, err)
+	}
+	value := strings.TrimRight(string(data), "\r\n")
+	if value == "" {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
@@ -0,0 +1,78 @@
+package secrets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileProvider_Get(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "database_password"), []byte("s3cret\n"), 0o600))
+	p := FileProvider{Dir: dir}
+
+	value, err := p.Get(context.Background(), "database_password")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", value)
+
+	_, err = p.Get(context.Background(), "redis_password")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = p.Get(context.Background(), "../etc/passwd")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrNotFound)
+}
+
+func TestVaultProvider_ReadsKVv2Once(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		assert.Equal(t, "/v1/kv/data/trivia-api/prod", r.URL.Path)
+		assert.Equal(t, "team", r.Header.Get("X-Vault-Namespace"))
+		_, _ = w.Write([]byte(`{"data":{"data":{"database_password":"pg-pass","redis_password":""},"metadata":{"version":3}}}`))
+	}))
+	defer server.Close()
+
+	p, err := NewVaultProvider(VaultConfig{Addr: server.URL, Token: "root", Namespace: "team", Mount: "kv", Path: "trivia-api/prod"})
+	require.NoError(t, err)
+
+	value, err := p.Get(context.Background(), "database_password")
+	require.NoError(t, err)
+	assert.Equal(t, "pg-pass", value)
+
+	_, err = p.Get(context.Background(), "redis_password")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Equal(t, 1, requests)
+}
+
+func TestVaultProvider_PermissionDenied(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+	}))
+	defer server.Close()
+
+	p, err := NewVaultProvider(VaultConfig{Addr: server.URL, Token: "wrong", Path: "trivia-api"})
+	require.NoError(t, err)
+
+	_, err = p.Get(context.Background(), "database_password")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "403")
+	assert.NotErrorIs(t, err, ErrNotFound)
+}
+
+func TestNewVaultProvider_RequiresToken(t *testing.T) {
+	_, err := NewVaultProvider(VaultConfig{Addr: "http://vault:8200", Path: "trivia-api"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,138 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// VaultConfig содержит параметры доступа к HashiCorp Vault (KV secrets engine v2)
+type VaultConfig struct {
+	Addr      string        // например, https://vault.internal:8200
+	Token     string        // токен Vault; пусто — читается из TokenFile
+	TokenFile string        // файл с токеном (Vault Agent, Kubernetes auth)
+	Namespace string        // namespace Vault Enterprise/HCP; пусто — корневой
+	Mount     string        // путь KV v2 движка; по умолчанию "secret"
+	Path      string        // путь секрета внутри движка, например "trivia-api/production"
+	Timeout   time.Duration // таймаут запроса; по умолчанию 10s
+}
+
+// VaultProvider читает секреты из одной записи KV v2: имя секрета — поле записи.
+// Запись загружается один раз при первом обращении; для ротации секретов нужен перезапуск.
+type VaultProvider struct {
+	cfg    VaultConfig
+	client *http.Client
+
+	mu     sync.Mutex
+	data   map[string]string
+	loaded bool
+}
+
+// NewVaultProvider создает провайдер секретов Vault
+func NewVaultProvider(cfg VaultConfig) (*VaultProvider, error) {
+	if cfg.Addr == "" {
+		return nil, fmt.Errorf("vault address is required")
+	}
+	if u, err := url.Parse(cfg.Addr); err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid vault address %q", cfg.Addr)
+	}
+	if cfg.Path == "" {
+		return nil, fmt.Errorf("vault secret path is required")
+	}
+	if cfg.Token == "" && cfg.TokenFile != "" {
+		token, err := os.ReadFile(cfg.TokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read vault token file: %w", err)
+		}
+		cfg.Token = strings.TrimSpace(string(token))
+	}
+	if cfg.Token == "" {
+		return nil, fmt.Errorf("vault token is required (VAULT_TOKEN or token file)")
+	}
+	if cfg.Mount == "" {
+		cfg.Mount = "secret"
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	return &VaultProvider{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}, nil
+}
+
+// Get реализует Provider
+func (p *VaultProvider) Get(ctx context.Context, name string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.loaded {
+		data, err := p.fetch(ctx)
+		if err != nil {
+			return "", err
+		}
+		p.data = data
+		p.loaded = true
+	}
+	value, ok := p.data[name]
+	if !ok || value == "" {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+// fetch читает запись KV v2: GET /v1/<mount>/data/<path>
+func (p *VaultProvider) fetch(ctx context.Context) (map[string]string, error) {
+	endpoint := strings.TrimRight(p.cfg.Addr, "/") + "/v1/" +
+		strings.Trim(p.cfg.Mount, "/") + "/data/" + strings.Trim(p.cfg.Path, "/")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", p.cfg.Token)
+	if p.cfg.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.cfg.Namespace)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("vault request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		// Тело ответа Vault содержит только список ошибок, секретов в нём нет
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("vault returned status %d for %s/%s: %s",
+			resp.StatusCode, p.cfg.Mount, p.cfg.Path, strings.TrimSpace(string(body)))
+	}
+
+	var payload struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("failed to decode vault response: %w", err)
+	}
+
+	data := make(map[string]string, len(payload.Data.Data))
+	for key, value := range payload.Data.Data {
+		switch v := value.(type) {
+		case string:
+			data[key] = v
+		case nil:
+		default:
+			data[key] = fmt.Sprint(v)
+		}
+	}
+	return data, nil
+}
@@ -0,0 +1,237 @@
+// Package sharecard рисует карточки результатов викторины для соцсетей: PNG и SVG размера
+// OpenGraph (1200×630) и HTML-страницу с метаданными OpenGraph для превью ссылок.
+package sharecard
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	// Width и Height — размер карточки, рекомендуемый OpenGraph для превью
+	Width  = 1200
+	Height = 630
+
+	marginX = 80
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var templates = template.Must(template.ParseFS(templateFS, "templates/*.tmpl"))
+
+// Card — данные карточки результата
+type Card struct {
+	SiteName       string
+	QuizTitle      string
+	Username       string
+	Rank           int
+	Score          int
+	CorrectAnswers int
+	TotalQuestions int
+	IsWinner       bool
+	Prize          int
+}
+
+// Headline — крупная строка карточки: победа или место
+func (c Card) Headline() string {
+	if c.IsWinner {
+		return "Победа!"
+	}
+	if c.Rank > 0 {
+		return fmt.Sprintf("%d место", c.Rank)
+	}
+	return "Участие"
+}
+
+// Stats — строка со счётом, верными ответами и призом
+func (c Card) Stats() string {
+	stats := fmt.Sprintf("Очки: %d · Верных: %d/%d", c.Score, c.CorrectAnswers, c.TotalQuestions)
+	if c.Prize > 0 {
+		stats += " · Приз: " + FormatPrize(c.Prize)
+	}
+	return stats
+}
+
+// Description — описание для OpenGraph
+func (c Card) Description() string {
+	return fmt.Sprintf("%s: %s в викторине «%s». %s", c.Username, strings.ToLower(c.Headline()), c.QuizTitle, c.Stats())
+}
+
+// FormatPrize форматирует сумму приза с разделителями разрядов: 50000 → «50 000 ₸»
+func FormatPrize(amount int) string {
+	digits := strconv.Itoa(amount)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteRune(d)
+	}
+	return b.String() + " ₸"
+}
+
+// RenderSVG рисует карточку в SVG по шаблону card.svg.tmpl; текст экранируется шаблоном
+func RenderSVG(card Card) ([]byte, error) {
+	var buf bytes.Buffer
+	err := templates.ExecuteTemplate(&buf, "card.svg.tmpl", map[string]interface{}{
+		"Width":    Width,
+		"Height":   Height,
+		"Card":     card,
+		"Title":    truncateRunes(card.QuizTitle, 40),
+		"Headline": card.Headline(),
+		"Stats":    card.Stats(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render svg card: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Page — адреса для HTML-страницы карточки
+type Page struct {
+	PageURL  string
+	ImageURL string
+}
+
+// RenderPage рисует HTML-страницу с метаданными OpenGraph и Twitter Card по шаблону page.html.tmpl
+func RenderPage(card Card, page Page) ([]byte, error) {
+	var buf bytes.Buffer
+	err := templates.ExecuteTemplate(&buf, "page.html.tmpl", map[string]interface{}{
+		"SiteName":    card.SiteName,
+		"Title":       fmt.Sprintf("%s — %s", card.Username, card.QuizTitle),
+		"Description": card.Description(),
+		"PageURL":     page.PageURL,
+		"ImageURL":    page.ImageURL,
+		"Width":       Width,
+		"Height":      Height,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render share page: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+var (
+	fontsOnce sync.Once
+	fontsErr  error
+	faces     struct {
+		site, title, user, headline, stats font.Face
+	}
+	// facesMu — начертания opentype нельзя использовать из нескольких горутин одновременно
+	facesMu sync.Mutex
+)
+
+// loadFaces разбирает встроенные шрифты Go один раз на процесс
+func loadFaces() error {
+	fontsOnce.Do(func() {
+		regular, err := opentype.Parse(goregular.TTF)
+		if err != nil {
+			fontsErr = err
+			return
+		}
+		bold, err := opentype.Parse(gobold.TTF)
+		if err != nil {
+			fontsErr = err
+			return
+		}
+		newFace := func(f *opentype.Font, size float64) font.Face {
+			if fontsErr != nil {
+				return nil
+			}
+			face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+			if err != nil {
+				fontsErr = err
+			}
+			return face
+		}
+		faces.site = newFace(regular, 32)
+		faces.title = newFace(bold, 56)
+		faces.user = newFace(regular, 40)
+		faces.headline = newFace(bold, 120)
+		faces.stats = newFace(regular, 36)
+	})
+	return fontsErr
+}
+
+// RenderPNG рисует карточку в PNG той же раскладкой, что и SVG
+func RenderPNG(card Card) ([]byte, error) {
+	if err := loadFaces(); err != nil {
+		return nil, fmt.Errorf("failed to load card fonts: %w", err)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.RGBA{R: 0x1e, G: 0x1b, B: 0x4b, A: 0xff}), image.Point{}, draw.Src)
+	accent := color.RGBA{R: 0x63, G: 0x66, B: 0xf1, A: 0xff}
+	if card.IsWinner {
+		accent = color.RGBA{R: 0xfa, G: 0xcc, B: 0x15, A: 0xff}
+	}
+	draw.Draw(img, image.Rect(0, 0, Width, 12), image.NewUniform(accent), image.Point{}, draw.Src)
+
+	facesMu.Lock()
+	defer facesMu.Unlock()
+	maxWidth := fixed.I(Width - 2*marginX)
+	lines := []struct {
+		face  font.Face
+		color color.Color
+		y     int
+		text  string
+	}{
+		{faces.site, color.RGBA{R: 0xa5, G: 0xb4, B: 0xfc, A: 0xff}, 110, card.SiteName},
+		{faces.title, color.White, 190, card.QuizTitle},
+		{faces.user, color.RGBA{R: 0xc7, G: 0xd2, B: 0xfe, A: 0xff}, 260, card.Username},
+		{faces.headline, color.White, 420, card.Headline()},
+		// Во встроенных шрифтах Go нет знака тенге
+		{faces.stats, color.RGBA{R: 0xe0, G: 0xe7, B: 0xff, A: 0xff}, 540, strings.ReplaceAll(card.Stats(), "₸", "тг")},
+	}
+	for _, line := range lines {
+		drawer := &font.Drawer{Dst: img, Src: image.NewUniform(line.color), Face: line.face, Dot: fixed.P(marginX, line.y)}
+		drawer.DrawString(fitWidth(drawer, line.text, maxWidth))
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode png card: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// fitWidth обрезает текст с многоточием, чтобы он поместился в maxWidth
+func fitWidth(drawer *font.Drawer, text string, maxWidth fixed.Int26_6) string {
+	if drawer.MeasureString(text) <= maxWidth {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		candidate := strings.TrimSpace(string(runes)) + "…"
+		if drawer.MeasureString(candidate) <= maxWidth {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// truncateRunes обрезает текст до max символов с многоточием
+func truncateRunes(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return strings.TrimSpace(string(runes[:max-1])) + "…"
+}
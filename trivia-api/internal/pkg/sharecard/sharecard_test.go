@@ -0,0 +1,65 @@
+package sharecard
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testCard() Card {
+	return Card{
+		SiteName:       "Trivia",
+		QuizTitle:      "Кино <и> музыка",
+		Username:       "player\"1",
+		Rank:           1,
+		Score:          9,
+		CorrectAnswers: 9,
+		TotalQuestions: 10,
+		IsWinner:       true,
+		Prize:          125000,
+	}
+}
+
+func TestRenderPNG_OpenGraphSize(t *testing.T) {
+	out, err := RenderPNG(testCard())
+	require.NoError(t, err)
+
+	img, err := png.Decode(bytes.NewReader(out))
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, Width, Height), img.Bounds())
+}
+
+func TestRenderSVG_EscapesText(t *testing.T) {
+	out, err := RenderSVG(testCard())
+	require.NoError(t, err)
+
+	svg := string(out)
+	assert.True(t, strings.HasPrefix(svg, "<svg"))
+	assert.Contains(t, svg, "Кино &lt;и&gt; музыка")
+	assert.NotContains(t, svg, "<и>")
+	assert.Contains(t, svg, "Приз: 125 000 ₸")
+}
+
+func TestRenderPage_OpenGraphMetadata(t *testing.T) {
+	out, err := RenderPage(testCard(), Page{PageURL: "https://example.com/share/abc", ImageURL: "https://cdn.example.com/share/abc.png"})
+	require.NoError(t, err)
+
+	page := string(out)
+	assert.Contains(t, page, `<meta property="og:image" content="https://cdn.example.com/share/abc.png">`)
+	assert.Contains(t, page, `<meta property="og:url" content="https://example.com/share/abc">`)
+	assert.Contains(t, page, `<meta name="twitter:card" content="summary_large_image">`)
+	assert.NotContains(t, page, `player"1`, "кавычки в атрибутах экранируются")
+}
+
+func TestCard_Headline(t *testing.T) {
+	assert.Equal(t, "Победа!", testCard().Headline())
+	assert.Equal(t, "3 место", Card{Rank: 3}.Headline())
+	assert.Equal(t, "Участие", Card{}.Headline())
+	assert.Equal(t, "1 000 000 ₸", FormatPrize(1000000))
+	assert.Equal(t, "500 ₸", FormatPrize(500))
+}
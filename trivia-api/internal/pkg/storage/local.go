@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// LocalStorage хранит объекты на локальном диске; файлы раздаются сервером по publicBaseURL
+type LocalStorage struct {
+	baseDir       string
+	publicBaseURL string
+}
+
+// NewLocalStorage создает хранилище в baseDir, доступное по publicBaseURL (например, "/uploads")
+func NewLocalStorage(baseDir, publicBaseURL string) (*LocalStorage, error) {
+	if err := os.MkdirAll(baseDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create storage dir %s: %w", baseDir, err)
+	}
+	return &LocalStorage{baseDir: baseDir, publicBaseURL: publicBaseURL}, nil
+}
+
+// filePath возвращает путь к файлу объекта, не допуская выхода за пределы baseDir (путь очищается как абсолютный)
+func (s *LocalStorage) filePath(key string) (string, error) {
+	cleaned := path.Clean("/" + key)
+	if cleaned == "/" {
+		return "", fmt.Errorf("%w: invalid storage key %q", apperrors.ErrValidation, key)
+	}
+	return filepath.Join(s.baseDir, filepath.FromSlash(cleaned)), nil
+}
+
+// Store сохраняет объект в файл
+func (s *LocalStorage) Store(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	filePath, err := s.filePath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return fmt.Errorf("failed to create storage dir: %w", err)
+	}
+
+	dst, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	_, err = io.Copy(dst, body)
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(filePath) // Удаляем частично записанный файл
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
+
+// Get открывает файл объекта
+func (s *LocalStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	filePath, err := s.filePath(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	return f, nil
+}
+
+// Delete удаляет файл объекта
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	filePath, err := s.filePath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
+
+// SignedURL возвращает публичную ссылку: локальные файлы раздаются без подписи
+func (s *LocalStorage) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	return s.PublicURL(key), nil
+}
+
+// PublicURL возвращает ссылку на файл
+func (s *LocalStorage) PublicURL(key string) string {
+	return joinURL(s.publicBaseURL, key)
+}
@@ -0,0 +1,33 @@
+// Package storage предоставляет абстракцию хранилища загружаемых файлов
+// (рекламные материалы, аватары) с реализациями на локальном диске и в S3-совместимом хранилище.
+package storage
+
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+)
+
+// Storage хранит объекты по ключу вида "ads/ad_123.mp4"
+type Storage interface {
+	// Store сохраняет объект. size может быть -1, если размер неизвестен.
+	Store(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+
+	// Get открывает объект на чтение. Возвращает apperrors.ErrNotFound, если объекта нет.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete удаляет объект. Отсутствие объекта не считается ошибкой.
+	Delete(ctx context.Context, key string) error
+
+	// SignedURL возвращает временную ссылку на объект
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+
+	// PublicURL возвращает постоянную публичную ссылку на объект
+	PublicURL(key string) string
+}
+
+// joinURL объединяет базовый URL и ключ объекта
+func joinURL(base, key string) string {
+	return strings.TrimRight(base, "/") + "/" + strings.TrimLeft(key, "/")
+}
@@ -0,0 +1,81 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func setupRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+	return recorder
+}
+
+func TestRedisHook_CreatesChildSpan(t *testing.T) {
+	recorder := setupRecorder(t)
+	hook := RedisHook{}
+
+	ctx, parent := StartSpan(context.Background(), "parent")
+	cmd := redis.NewStringCmd(ctx, "get", "key")
+	cmd.SetErr(redis.Nil)
+
+	cmdCtx, err := hook.BeforeProcess(ctx, cmd)
+	require.NoError(t, err)
+	require.NoError(t, hook.AfterProcess(cmdCtx, cmd))
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "redis.get", spans[0].Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	// redis.Nil — не ошибка
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+}
+
+func TestRedisHook_SkipsWithoutParent(t *testing.T) {
+	recorder := setupRecorder(t)
+	hook := RedisHook{}
+
+	cmd := redis.NewStatusCmd(context.Background(), "set", "key", "value")
+	cmdCtx, err := hook.BeforeProcess(context.Background(), cmd)
+	require.NoError(t, err)
+	require.NoError(t, hook.AfterProcess(cmdCtx, cmd))
+
+	assert.Empty(t, recorder.Ended())
+}
+
+func TestRedisHook_PipelineRecordsError(t *testing.T) {
+	recorder := setupRecorder(t)
+	hook := RedisHook{}
+
+	ctx, parent := StartSpan(context.Background(), "parent")
+	ok := redis.NewStatusCmd(ctx, "set", "a", "1")
+	failed := redis.NewIntCmd(ctx, "incr", "a")
+	failed.SetErr(errors.New("WRONGTYPE"))
+	cmds := []redis.Cmder{ok, failed}
+
+	pipeCtx, err := hook.BeforeProcessPipeline(ctx, cmds)
+	require.NoError(t, err)
+	require.NoError(t, hook.AfterProcessPipeline(pipeCtx, cmds))
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "redis.pipeline", spans[0].Name())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	// Спан родителя не завершён хуком повторно и не помечен ошибкой
+	assert.Equal(t, "parent", spans[1].Name())
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+}
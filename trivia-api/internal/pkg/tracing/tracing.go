@@ -0,0 +1,145 @@
+// Package tracing настраивает OpenTelemetry: экспорт трейсов по OTLP/HTTP,
+// W3C-пропагацию контекста и инструментирование GORM и Redis.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName — имя трейсера, под которым создаются все спаны приложения
+const instrumentationName = "github.com/yourusername/trivia-api"
+
+// Config содержит параметры экспорта трейсов
+type Config struct {
+	Enabled     bool
+	Endpoint    string  // адрес OTLP/HTTP коллектора, например http://otel-collector:4318
+	Insecure    bool    // отправка без TLS (используется, если схема не указана в Endpoint)
+	ServiceName string  // service.name в ресурсах трейсов
+	Environment string  // deployment.environment.name
+	SampleRatio float64 // доля корневых трейсов, попадающих в выборку (0..1)
+}
+
+// Init настраивает глобальный TracerProvider и пропагатор W3C Trace Context.
+// Пропагатор настраивается всегда, чтобы trace-заголовки пробрасывались даже при выключенном экспорте.
+// Возвращает функцию, которая дописывает накопленные спаны при остановке сервиса.
+func Init(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx, exporterOptions(cfg)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create otlp exporter: %w", err)
+	}
+
+	serviceName := cfg.ServiceName
+	if serviceName == "" {
+		serviceName = "trivia-api"
+	}
+	attrs := []attribute.KeyValue{attribute.String("service.name", serviceName)}
+	if cfg.Environment != "" {
+		attrs = append(attrs, attribute.String("deployment.environment.name", cfg.Environment))
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(attrs...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+
+	ratio := cfg.SampleRatio
+	if ratio <= 0 || ratio > 1 {
+		ratio = 1
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		// Решение о выборке наследуется от вызывающей стороны, если она передала traceparent
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// exporterOptions разбирает Endpoint: допускается как полный URL, так и host:port
+func exporterOptions(cfg Config) []otlptracehttp.Option {
+	var opts []otlptracehttp.Option
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		// Без явного адреса экспортер читает стандартные переменные OTEL_EXPORTER_OTLP_*
+		return opts
+	}
+	if strings.Contains(endpoint, "://") {
+		if u, err := url.Parse(endpoint); err == nil {
+			opts = append(opts, otlptracehttp.WithEndpoint(u.Host))
+			if u.Scheme == "http" {
+				opts = append(opts, otlptracehttp.WithInsecure())
+			}
+			if u.Path != "" && u.Path != "/" {
+				opts = append(opts, otlptracehttp.WithURLPath(u.Path))
+			}
+			return opts
+		}
+	}
+	opts = append(opts, otlptracehttp.WithEndpoint(endpoint))
+	if cfg.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	return opts
+}
+
+// Tracer возвращает трейсер приложения
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// StartSpan начинает внутренний спан (например, вокруг метода сервиса)
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// EndSpan завершает спан, помечая его ошибкой, если err не nil
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+type requestIDKey struct{}
+
+// WithRequestID сохраняет идентификатор запроса в контексте
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext возвращает идентификатор запроса или пустую строку
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// hasParent сообщает, есть ли в контексте активный спан. Инструментирование БД и Redis
+// создаёт спаны только внутри трейса запроса, чтобы фоновые задачи не порождали
+// тысячи одиночных трейсов.
+func hasParent(ctx context.Context) bool {
+	return ctx != nil && trace.SpanContextFromContext(ctx).IsValid()
+}
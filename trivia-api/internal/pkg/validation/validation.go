@@ -0,0 +1,231 @@
+// Package validation превращает ошибки привязки запроса (gin binding, go-playground/validator,
+// encoding/json) в ошибки отдельных полей и добавляет правила, общие для веб- и мобильных
+// обработчиков: password_strength, username_charset, birth_date.
+//
+// Правила регистрируются в валидаторе gin при импорте пакета.
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/yourusername/trivia-api/internal/pkg/i18n"
+)
+
+// BirthDateLayout — формат даты рождения в запросах
+const BirthDateLayout = "2006-01-02"
+
+const (
+	passwordMinLength = 8
+	passwordMaxLength = 72 // bcrypt учитывает только первые 72 байта
+	maxAgeYears       = 120
+)
+
+var usernamePattern = regexp.MustCompile(`^[\p{L}\p{N}][\p{L}\p{N}_.\-]*$`)
+
+// FieldError — ошибка валидации одного поля запроса
+type FieldError struct {
+	Field   string `json:"field"`           // путь поля в JSON (items[0].text); body — запрос целиком
+	Rule    string `json:"rule"`            // нарушенное правило: required, min, password_strength, type, json...
+	Param   string `json:"param,omitempty"` // параметр правила (min=3 → 3)
+	Message string `json:"message"`         // сообщение на языке пользователя
+}
+
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		if err := Register(v); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Register настраивает валидатор: имена полей берутся из тегов json, добавляются правила
+// password_strength, username_charset и birth_date[=минимальный возраст].
+func Register(v *validator.Validate) error {
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"json", "form", "uri"} {
+			name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			if name != "" {
+				return name
+			}
+		}
+		return field.Name
+	})
+	rules := map[string]validator.Func{
+		"password_strength": func(fl validator.FieldLevel) bool { return IsStrongPassword(fl.Field().String()) },
+		"username_charset":  func(fl validator.FieldLevel) bool { return usernamePattern.MatchString(fl.Field().String()) },
+		"birth_date":        validateBirthDate,
+	}
+	for tag, fn := range rules {
+		if err := v.RegisterValidation(tag, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsStrongPassword проверяет правило password_strength: 8–72 символа, хотя бы одна буква и одна цифра
+func IsStrongPassword(password string) bool {
+	length := utf8.RuneCountInString(password)
+	if length < passwordMinLength || len(password) > passwordMaxLength {
+		return false
+	}
+	var hasLetter, hasDigit bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		}
+	}
+	return hasLetter && hasDigit
+}
+
+// ParseBirthDate разбирает дату рождения и проверяет границы: не в будущем и не старше 120 лет
+func ParseBirthDate(value string) (time.Time, bool) {
+	date, err := time.Parse(BirthDateLayout, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	now := time.Now().UTC()
+	if date.After(now) || date.Before(now.AddDate(-maxAgeYears, 0, 0)) {
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+// validateBirthDate — правило birth_date для строки ГГГГ-ММ-ДД или time.Time; параметр — минимальный возраст
+func validateBirthDate(fl validator.FieldLevel) bool {
+	date, ok := birthDateValue(fl.Field())
+	if !ok {
+		return false
+	}
+	if minAge, err := strconv.Atoi(fl.Param()); err == nil && minAge > 0 {
+		return !date.After(time.Now().UTC().AddDate(-minAge, 0, 0))
+	}
+	return true
+}
+
+func birthDateValue(field reflect.Value) (time.Time, bool) {
+	if !field.IsValid() {
+		return time.Time{}, false
+	}
+	switch value := field.Interface().(type) {
+	case string:
+		return ParseBirthDate(value)
+	case time.Time:
+		return ParseBirthDate(value.Format(BirthDateLayout))
+	}
+	return time.Time{}, false
+}
+
+// FieldErrors превращает ошибку привязки запроса в ошибки полей с сообщениями на языках chain.
+// Для ошибок, не связанных с полями, возвращает nil.
+func FieldErrors(err error, chain []string) []FieldError {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			fields = append(fields, FieldError{
+				Field:   fieldPath(fe.Namespace()),
+				Rule:    fe.Tag(),
+				Param:   fe.Param(),
+				Message: i18n.FieldMessage(messageKey(fe), fe.Param(), chain),
+			})
+		}
+		return fields
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		field := typeErr.Field
+		if field == "" {
+			field = "body"
+		}
+		expected := typeErr.Type.Kind().String()
+		return []FieldError{{Field: field, Rule: "type", Param: expected, Message: i18n.FieldMessage("type", expected, chain)}}
+	}
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return []FieldError{{Field: "body", Rule: "json", Message: i18n.FieldMessage("json", "", chain)}}
+	}
+	if errors.Is(err, io.EOF) {
+		return []FieldError{{Field: "body", Rule: "required", Message: i18n.FieldMessage("required", "", chain)}}
+	}
+	return nil
+}
+
+// Summary склеивает ошибки полей в одну строку «поле: сообщение; ...»
+func Summary(fields []FieldError) string {
+	parts := make([]string, 0, len(fields))
+	for _, f := range fields {
+		parts = append(parts, f.Field+": "+f.Message)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// fieldPath убирает имя структуры запроса из пути поля: LoginRequest.email → email
+func fieldPath(namespace string) string {
+	if i := strings.IndexByte(namespace, '.'); i >= 0 {
+		return namespace[i+1:]
+	}
+	return namespace
+}
+
+// messageKey выбирает сообщение для правила: для min/max строк и списков — про длину и число элементов
+func messageKey(fe validator.FieldError) string {
+	rule := fe.Tag()
+	switch rule {
+	case "gte":
+		rule = "min"
+	case "lte":
+		rule = "max"
+	case "birth_date":
+		if isUnderage(fe) {
+			return "min_age"
+		}
+		return rule
+	case "min", "max", "len":
+	default:
+		return rule
+	}
+
+	switch fe.Kind() {
+	case reflect.String:
+		return rule + "_length"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if rule != "len" {
+			return rule + "_items"
+		}
+	}
+	if rule == "len" {
+		return "invalid"
+	}
+	return rule
+}
+
+// isUnderage сообщает, что дата рождения корректна, но возраст меньше параметра правила birth_date
+func isUnderage(fe validator.FieldError) bool {
+	minAge, err := strconv.Atoi(fe.Param())
+	if err != nil || minAge <= 0 {
+		return false
+	}
+	date, ok := birthDateValue(reflect.ValueOf(fe.Value()))
+	return ok && date.After(time.Now().UTC().AddDate(-minAge, 0, 0))
+}
@@ -0,0 +1,86 @@
+package validation
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type signupRequest struct {
+	Username  string   `json:"username" binding:"required,min=3,max=50,username_charset"`
+	Password  string   `json:"password" binding:"required,password_strength"`
+	BirthDate string   `json:"birth_date" binding:"required,birth_date=18"`
+	Tags      []string `json:"tags" binding:"omitempty,max=2"`
+	Age       int      `json:"age" binding:"omitempty,min=18"`
+}
+
+func bindJSON(t *testing.T, body string) error {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	require.NoError(t, err)
+	var dst signupRequest
+	return binding.JSON.Bind(req, &dst)
+}
+
+func fieldsByName(fields []FieldError) map[string]FieldError {
+	byName := make(map[string]FieldError, len(fields))
+	for _, f := range fields {
+		byName[f.Field] = f
+	}
+	return byName
+}
+
+func TestFieldErrors_ValidatorRules(t *testing.T) {
+	adult := time.Now().AddDate(-30, 0, 0).Format(BirthDateLayout)
+	require.NoError(t, bindJSON(t, `{"username":"Игрок_1","password":"secret123","birth_date":"`+adult+`"}`))
+
+	minor := time.Now().AddDate(-10, 0, 0).Format(BirthDateLayout)
+	err := bindJSON(t, `{"username":"_x","password":"12345678","birth_date":"`+minor+`","tags":["a","b","c"],"age":5}`)
+	require.Error(t, err)
+
+	fields := fieldsByName(FieldErrors(err, nil))
+	require.Len(t, fields, 5)
+	assert.Equal(t, "min", fields["username"].Rule)
+	assert.Equal(t, "3", fields["username"].Param)
+	assert.Equal(t, "Must be at least 3 characters long", fields["username"].Message)
+	assert.Equal(t, "password_strength", fields["password"].Rule)
+	assert.Equal(t, "birth_date", fields["birth_date"].Rule)
+	assert.Equal(t, "Must be at least 18 years old", fields["birth_date"].Message)
+	assert.Equal(t, "Must contain at most 2 items", fields["tags"].Message)
+	assert.Equal(t, "Must be at least 18", fields["age"].Message)
+
+	err = bindJSON(t, `{"username":"a b c","password":"secret123","birth_date":"31.12.1990"}`)
+	fields = fieldsByName(FieldErrors(err, []string{"ru"}))
+	assert.Equal(t, "username_charset", fields["username"].Rule)
+	assert.Equal(t, "birth_date", fields["birth_date"].Rule)
+	assert.Contains(t, fields["birth_date"].Message, "ГГГГ-ММ-ДД")
+	assert.Equal(t, "Обязательное поле", FieldErrors(bindJSON(t, `{"username":"abc"}`), []string{"ru"})[0].Message)
+}
+
+func TestFieldErrors_DecodeErrors(t *testing.T) {
+	fields := FieldErrors(bindJSON(t, `{"username": 5}`), nil)
+	require.Len(t, fields, 1)
+	assert.Equal(t, FieldError{Field: "username", Rule: "type", Param: "string", Message: "Invalid value type, expected string"}, fields[0])
+
+	fields = FieldErrors(bindJSON(t, `{"username": `), nil)
+	require.Len(t, fields, 1)
+	assert.Equal(t, "json", fields[0].Rule)
+
+	fields = FieldErrors(bindJSON(t, ``), nil)
+	require.Len(t, fields, 1)
+	assert.Equal(t, "body", fields[0].Field)
+	assert.Equal(t, "required", fields[0].Rule)
+}
+
+func TestIsStrongPassword(t *testing.T) {
+	assert.True(t, IsStrongPassword("пароль2024"))
+	assert.False(t, IsStrongPassword("short1"))
+	assert.False(t, IsStrongPassword("onlyletters"))
+	assert.False(t, IsStrongPassword("1234567890"))
+	assert.False(t, IsStrongPassword(strings.Repeat("a1", 37)))
+}
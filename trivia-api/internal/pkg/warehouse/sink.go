@@ -0,0 +1,189 @@
+package warehouse
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// Sink принимает пачку событий. Write либо принимает пачку целиком, либо возвращает ошибку;
+// при повторе после ошибки часть событий может прийти дважды (дубликаты убираются по event_id).
+type Sink interface {
+	Write(ctx context.Context, events []Event) error
+	Close() error
+}
+
+// Типы приёмников
+const (
+	SinkKafka = "kafka" // топик Kafka через Confluent REST Proxy
+	SinkHTTP  = "http"  // POST пачки в NDJSON (ClickHouse HTTP, Vector, собственный сборщик)
+	SinkFile  = "file"  // NDJSON-файлы в каталоге для загрузчика (bq load, clickhouse-local)
+)
+
+// SinkConfig содержит настройки приёмника
+type SinkConfig struct {
+	Type      string
+	URL       string        // kafka: адрес REST Proxy; http: адрес приёма пачек
+	Topic     string        // kafka: топик
+	AuthToken string        // kafka, http: токен заголовка Authorization: Bearer
+	Dir       string        // file: каталог для файлов
+	Timeout   time.Duration // kafka, http: таймаут запроса
+}
+
+// NewSink создаёт приёмник по cfg.Type
+func NewSink(cfg SinkConfig) (Sink, error) {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	client := &http.Client{Timeout: cfg.Timeout}
+	switch cfg.Type {
+	case SinkKafka:
+		if cfg.URL == "" || cfg.Topic == "" {
+			return nil, fmt.Errorf("kafka sink requires url and topic")
+		}
+		endpoint, err := url.JoinPath(cfg.URL, "topics", url.PathEscape(cfg.Topic))
+		if err != nil {
+			return nil, fmt.Errorf("invalid kafka rest proxy url: %w", err)
+		}
+		return &kafkaSink{client: client, endpoint: endpoint, token: cfg.AuthToken}, nil
+	case SinkHTTP:
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("http sink requires url")
+		}
+		return &httpSink{client: client, endpoint: cfg.URL, token: cfg.AuthToken}, nil
+	case SinkFile:
+		if cfg.Dir == "" {
+			return nil, fmt.Errorf("file sink requires dir")
+		}
+		if err := os.MkdirAll(cfg.Dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create warehouse dir: %w", err)
+		}
+		return &fileSink{dir: cfg.Dir}, nil
+	default:
+		return nil, fmt.Errorf("unknown warehouse sink %q", cfg.Type)
+	}
+}
+
+// EncodeNDJSON пишет события по одному JSON-объекту на строку
+func EncodeNDJSON(w io.Writer, events []Event) error {
+	enc := json.NewEncoder(w)
+	for i := range events {
+		if err := enc.Encode(&events[i]); err != nil {
+			return fmt.Errorf("failed to encode event %s: %w", events[i].EventID, err)
+		}
+	}
+	return nil
+}
+
+// post отправляет тело запросом POST и считает ошибкой любой ответ, кроме 2xx
+func post(ctx context.Context, client *http.Client, endpoint, contentType, token string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("warehouse sink responded %d: %s", resp.StatusCode, bytes.TrimSpace(snippet))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+type httpSink struct {
+	client   *http.Client
+	endpoint string
+	token    string
+}
+
+func (s *httpSink) Write(ctx context.Context, events []Event) error {
+	var body bytes.Buffer
+	if err := EncodeNDJSON(&body, events); err != nil {
+		return err
+	}
+	return post(ctx, s.client, s.endpoint, "application/x-ndjson", s.token, body.Bytes())
+}
+
+func (s *httpSink) Close() error { return nil }
+
+// kafkaSink пишет в топик через REST Proxy (API v2). Ключ записи — ID викторины,
+// поэтому события одной викторины попадают в одну партицию и сохраняют порядок.
+type kafkaSink struct {
+	client   *http.Client
+	endpoint string
+	token    string
+}
+
+type kafkaRecord struct {
+	Key   string `json:"key"`
+	Value *Event `json:"value"`
+}
+
+func (s *kafkaSink) Write(ctx context.Context, events []Event) error {
+	records := make([]kafkaRecord, len(events))
+	for i := range events {
+		records[i] = kafkaRecord{Key: strconv.FormatUint(uint64(events[i].QuizID), 10), Value: &events[i]}
+	}
+	body, err := json.Marshal(map[string]interface{}{"records": records})
+	if err != nil {
+		return fmt.Errorf("failed to encode kafka records: %w", err)
+	}
+	return post(ctx, s.client, s.endpoint, "application/vnd.kafka.json.v2+json", s.token, body)
+}
+
+func (s *kafkaSink) Close() error { return nil }
+
+// fileSink пишет каждую пачку в отдельный файл events-<время>-<n>.ndjson. Файл сначала пишется
+// с расширением .tmp и переименовывается целиком, так что загрузчик не увидит недописанный файл.
+type fileSink struct {
+	dir string
+	seq atomic.Int64
+}
+
+func (s *fileSink) Write(ctx context.Context, events []Event) error {
+	name := fmt.Sprintf("events-%s-%d.ndjson", time.Now().UTC().Format("20060102T150405.000000000Z"), s.seq.Add(1))
+	final := filepath.Join(s.dir, name)
+	tmp := final + ".tmp"
+
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("failed to create warehouse file: %w", err)
+	}
+	w := bufio.NewWriter(f)
+	err = EncodeNDJSON(w, events)
+	if err == nil {
+		err = w.Flush()
+	}
+	if errClose := f.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write warehouse file: %w", err)
+	}
+	if err := os.Rename(tmp, final); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to publish warehouse file: %w", err)
+	}
+	return nil
+}
+
+func (s *fileSink) Close() error { return nil }
@@ -0,0 +1,147 @@
+// Package warehouse описывает нормализованные события для хранилища аналитики (BigQuery, ClickHouse)
+// и приёмники, в которые они отправляются пачками.
+//
+// Схема события версионируется по типу: SchemaVersions хранит текущую версию данных каждого типа.
+// Совместимые изменения (новое необязательное поле) версию не меняют; удаление или переименование
+// поля, смена типа или смысла значения — повышают её, и загрузчик различает строки по schema_version.
+package warehouse
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Типы событий
+const (
+	EventAnswerSubmitted  = "answer_submitted"  // ответ записан в user_answers (selected_option -1 — ответа не было)
+	EventElimination      = "elimination"       // игрок выбыл на вопросе
+	EventQuizCompleted    = "quiz_completed"    // результаты и призы викторины зафиксированы
+	EventConnectionOpened = "connection_opened" // WebSocket-соединение зарегистрировано
+	EventConnectionClosed = "connection_closed" // WebSocket-соединение закрыто
+
+	// Исправления уже отправленных событий: хранилище учитывает их при подсчёте статистики
+	EventQuestionVoided     = "question_voided"     // вопрос снят с эфира, ответы на него аннулированы
+	EventEliminationRevoked = "elimination_revoked" // выбывания игрока до occurred_at отменены вторым шансом
+)
+
+// SchemaVersions — текущая версия данных каждого типа события
+var SchemaVersions = map[string]int{
+	EventAnswerSubmitted:  1,
+	EventElimination:      1,
+	EventQuizCompleted:    1,
+	EventConnectionOpened: 1,
+	EventConnectionClosed: 1,
+
+	EventQuestionVoided:     1,
+	EventEliminationRevoked: 1,
+}
+
+// Источник события
+const (
+	SourceLive     = "live"     // событие отправлено во время игры
+	SourceBackfill = "backfill" // событие восстановлено из БД
+)
+
+// Event — событие в формате хранилища. EventID стабилен: повторная отправка и backfill дают тот же ID,
+// поэтому дубликаты убираются в хранилище (ReplacingMergeTree в ClickHouse, MERGE в BigQuery).
+type Event struct {
+	EventID       string          `json:"event_id"`
+	EventType     string          `json:"event_type"`
+	SchemaVersion int             `json:"schema_version"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	QuizID        uint            `json:"quiz_id,omitempty"`
+	UserID        uint            `json:"user_id,omitempty"`
+	Source        string          `json:"source"`
+	Data          json.RawMessage `json:"data"`
+}
+
+// AnswerSubmittedData — данные answer_submitted
+type AnswerSubmittedData struct {
+	QuestionID     uint  `json:"question_id"`
+	QuestionNumber int   `json:"question_number,omitempty"` // номер вопроса в викторине, 0 — неизвестен
+	SelectedOption int   `json:"selected_option"`
+	IsCorrect      bool  `json:"is_correct"`
+	ResponseTimeMs int64 `json:"response_time_ms"`
+	Score          int   `json:"score"`
+}
+
+// EliminationData — данные elimination
+type EliminationData struct {
+	QuestionID     uint   `json:"question_id"`
+	QuestionNumber int    `json:"question_number,omitempty"`
+	Reason         string `json:"reason"`
+}
+
+// QuizCompletedData — данные quiz_completed
+type QuizCompletedData struct {
+	Title         string    `json:"title"`
+	ScheduledTime time.Time `json:"scheduled_time"`
+	PrizeFund     int       `json:"prize_fund"`
+	WinnerIDs     []uint    `json:"winner_ids"`
+}
+
+// ConnectionData — данные connection_opened и connection_closed
+type ConnectionData struct {
+	ConnectionID string `json:"connection_id"`
+	InstanceID   string `json:"instance_id,omitempty"`
+	DurationMs   int64  `json:"duration_ms,omitempty"` // только connection_closed
+	Reason       string `json:"reason,omitempty"`      // только connection_closed: closed, replaced
+}
+
+// QuestionVoidedData — данные question_voided
+type QuestionVoidedData struct {
+	QuestionID uint `json:"question_id"`
+}
+
+// EliminationRevokedData — данные elimination_revoked
+type EliminationRevokedData struct {
+	Reason string `json:"reason"` // second_chance
+}
+
+// NewEvent создаёт событие с текущей версией схемы типа eventType
+func NewEvent(eventType, eventID, source string, occurredAt time.Time, quizID, userID uint, data interface{}) (Event, error) {
+	version, ok := SchemaVersions[eventType]
+	if !ok {
+		return Event{}, fmt.Errorf("unknown warehouse event type %q", eventType)
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, fmt.Errorf("failed to marshal %s event: %w", eventType, err)
+	}
+	return Event{
+		EventID:       eventID,
+		EventType:     eventType,
+		SchemaVersion: version,
+		OccurredAt:    occurredAt.UTC(),
+		QuizID:        quizID,
+		UserID:        userID,
+		Source:        source,
+		Data:          payload,
+	}, nil
+}
+
+// AnswerEventID — ID события answer_submitted: ответ на вопрос у игрока один (уникальный индекс user_answers)
+func AnswerEventID(quizID, userID, questionID uint) string {
+	return fmt.Sprintf("answer:%d:%d:%d", quizID, userID, questionID)
+}
+
+// EliminationEventID — ID события elimination; после второго шанса игрок может выбыть ещё раз на другом вопросе
+func EliminationEventID(quizID, userID, questionID uint) string {
+	return fmt.Sprintf("elimination:%d:%d:%d", quizID, userID, questionID)
+}
+
+// QuizCompletedEventID — ID события quiz_completed
+func QuizCompletedEventID(quizID uint) string {
+	return fmt.Sprintf("quiz_completed:%d", quizID)
+}
+
+// QuestionVoidedEventID — ID события question_voided
+func QuestionVoidedEventID(quizID, questionID uint) string {
+	return fmt.Sprintf("question_voided:%d:%d", quizID, questionID)
+}
+
+// EliminationRevokedEventID — ID события elimination_revoked: второй шанс даётся один раз за викторину
+func EliminationRevokedEventID(quizID, userID uint) string {
+	return fmt.Sprintf("elimination_revoked:%d:%d", quizID, userID)
+}
@@ -0,0 +1,130 @@
+package warehouse
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testEvents(t *testing.T) []Event {
+	t.Helper()
+	at := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	answer, err := NewEvent(EventAnswerSubmitted, AnswerEventID(3, 7, 11), SourceLive, at, 3, 7,
+		AnswerSubmittedData{QuestionID: 11, QuestionNumber: 2, SelectedOption: 1, IsCorrect: true, ResponseTimeMs: 2300, Score: 1})
+	require.NoError(t, err)
+	completed, err := NewEvent(EventQuizCompleted, QuizCompletedEventID(3), SourceLive, at, 3, 0,
+		QuizCompletedData{Title: "Кино", WinnerIDs: []uint{7}})
+	require.NoError(t, err)
+	return []Event{answer, completed}
+}
+
+func TestNewEvent(t *testing.T) {
+	events := testEvents(t)
+
+	assert.Equal(t, "answer:3:7:11", events[0].EventID)
+	assert.Equal(t, SchemaVersions[EventAnswerSubmitted], events[0].SchemaVersion)
+	assert.JSONEq(t, `{"question_id":11,"question_number":2,"selected_option":1,"is_correct":true,"response_time_ms":2300,"score":1}`, string(events[0].Data))
+
+	_, err := NewEvent("answer", "x", SourceLive, time.Now(), 0, 0, nil)
+	assert.Error(t, err, "неизвестный тип события")
+}
+
+func TestHTTPSink(t *testing.T) {
+	var lines []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/x-ndjson", r.Header.Get("Content-Type"))
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+	}))
+	defer server.Close()
+
+	sink, err := NewSink(SinkConfig{Type: SinkHTTP, URL: server.URL, AuthToken: "secret"})
+	require.NoError(t, err)
+	require.NoError(t, sink.Write(context.Background(), testEvents(t)))
+
+	require.Len(t, lines, 2)
+	var decoded Event
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &decoded))
+	assert.Equal(t, EventQuizCompleted, decoded.EventType)
+	assert.Equal(t, uint(3), decoded.QuizID)
+}
+
+func TestHTTPSink_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "table is read-only", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	sink, err := NewSink(SinkConfig{Type: SinkHTTP, URL: server.URL})
+	require.NoError(t, err)
+	err = sink.Write(context.Background(), testEvents(t))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "503")
+	assert.Contains(t, err.Error(), "table is read-only")
+}
+
+func TestKafkaSink(t *testing.T) {
+	var body struct {
+		Records []struct {
+			Key   string `json:"key"`
+			Value Event  `json:"value"`
+		} `json:"records"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rest/topics/trivia-events", r.URL.Path)
+		assert.Equal(t, "application/vnd.kafka.json.v2+json", r.Header.Get("Content-Type"))
+		data, _ := io.ReadAll(r.Body)
+		assert.NoError(t, json.Unmarshal(data, &body))
+	}))
+	defer server.Close()
+
+	sink, err := NewSink(SinkConfig{Type: SinkKafka, URL: server.URL + "/rest", Topic: "trivia-events"})
+	require.NoError(t, err)
+	require.NoError(t, sink.Write(context.Background(), testEvents(t)))
+
+	require.Len(t, body.Records, 2)
+	assert.Equal(t, "3", body.Records[0].Key, "ключ — ID викторины")
+	assert.Equal(t, "answer:3:7:11", body.Records[0].Value.EventID)
+}
+
+func TestFileSink(t *testing.T) {
+	dir := t.TempDir()
+	sink, err := NewSink(SinkConfig{Type: SinkFile, Dir: dir})
+	require.NoError(t, err)
+
+	require.NoError(t, sink.Write(context.Background(), testEvents(t)))
+	require.NoError(t, sink.Write(context.Background(), testEvents(t)[:1]))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	require.NoError(t, err)
+	require.Len(t, files, 2, "по файлу на пачку, без временных файлов")
+	for _, file := range files {
+		assert.True(t, strings.HasSuffix(file, ".ndjson"), file)
+	}
+	content, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(content), "\n"))
+}
+
+func TestNewSink_Validation(t *testing.T) {
+	_, err := NewSink(SinkConfig{Type: SinkKafka, URL: "http://proxy"})
+	assert.Error(t, err, "нужен топик")
+	_, err = NewSink(SinkConfig{Type: SinkHTTP})
+	assert.Error(t, err, "нужен адрес")
+	_, err = NewSink(SinkConfig{Type: "bigquery"})
+	assert.Error(t, err)
+}
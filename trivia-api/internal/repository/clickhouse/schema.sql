@@ -0,0 +1,65 @@
+-- Схема ClickHouse для statistics.backend: clickhouse.
+-- Выполняется один раз в базе statistics.clickhouse.database:
+--   clickhouse-client --database trivia --multiquery < internal/repository/clickhouse/schema.sql
+
+-- События игры (internal/pkg/warehouse). Повторная отправка и backfill дают тот же event_id:
+-- ReplacingMergeTree оставляет последнюю полученную версию, запросы читают таблицу с FINAL.
+CREATE TABLE IF NOT EXISTS trivia_events
+(
+    event_id       String,
+    event_type     LowCardinality(String),
+    schema_version UInt16,
+    occurred_at    DateTime64(3, 'UTC'),
+    quiz_id        UInt64 DEFAULT 0,
+    user_id        UInt64 DEFAULT 0,
+    source         LowCardinality(String),
+    data           String,
+    ingested_at    DateTime64(3, 'UTC') DEFAULT now64(3)
+)
+ENGINE = ReplacingMergeTree(ingested_at)
+PARTITION BY toYYYYMM(occurred_at)
+ORDER BY (event_type, quiz_id, user_id, event_id);
+
+-- Приём событий.
+-- warehouse.sink: http — пачки NDJSON принимает сам ClickHouse:
+--   warehouse.url: http://clickhouse:8123/?user=writer&password=...&query=INSERT%20INTO%20trivia.trivia_events%20FORMAT%20JSONEachRow&input_format_json_read_objects_as_strings=1
+--   warehouse.authToken оставьте пустым: учётные данные ClickHouse передаются в адресе.
+-- warehouse.sink: kafka — таблица с движком Kafka и materialized view:
+--
+-- CREATE TABLE trivia_events_queue
+-- (
+--     event_id String, event_type String, schema_version UInt16, occurred_at DateTime64(3, 'UTC'),
+--     quiz_id UInt64, user_id UInt64, source String, data String
+-- )
+-- ENGINE = Kafka
+-- SETTINGS kafka_broker_list = 'kafka:9092', kafka_topic_list = 'trivia-events',
+--          kafka_group_name = 'clickhouse-trivia', kafka_format = 'JSONEachRow',
+--          input_format_json_read_objects_as_strings = 1;
+--
+-- CREATE MATERIALIZED VIEW trivia_events_mv TO trivia_events AS
+-- SELECT event_id, event_type, schema_version, occurred_at, quiz_id, user_id, source, data
+-- FROM trivia_events_queue;
+
+-- Справочники сложности и темы вопросов для личной аналитики. Читаются из PostgreSQL целиком
+-- раз в несколько минут; пользователю достаточно права SELECT на questions и categories.
+CREATE DICTIONARY IF NOT EXISTS trivia_questions
+(
+    id          UInt64,
+    difficulty  Int32 DEFAULT 0,
+    category_id UInt64 DEFAULT 0
+)
+PRIMARY KEY id
+SOURCE(POSTGRESQL(host 'postgres' port 5432 user 'clickhouse_ro' password '' db 'trivia_db' table 'questions'))
+LAYOUT(HASHED())
+LIFETIME(MIN 300 MAX 600);
+
+CREATE DICTIONARY IF NOT EXISTS trivia_categories
+(
+    id   UInt64,
+    slug String DEFAULT '',
+    name String DEFAULT ''
+)
+PRIMARY KEY id
+SOURCE(POSTGRESQL(host 'postgres' port 5432 user 'clickhouse_ro' password '' db 'trivia_db' table 'categories'))
+LAYOUT(HASHED())
+LIFETIME(MIN 300 MAX 600);
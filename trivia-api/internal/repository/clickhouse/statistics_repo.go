@@ -0,0 +1,322 @@
+// Package clickhouse реализует источник статистики поверх ClickHouse: агрегаты считаются
+// по таблице trivia_events, которую наполняет поток событий игры (internal/pkg/warehouse).
+// Схема таблицы и словарей вопросов и тем — schema.sql рядом с пакетом.
+package clickhouse
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+)
+
+// Config содержит параметры HTTP-интерфейса ClickHouse
+type Config struct {
+	URL      string
+	Database string
+	User     string
+	Password string
+	Timeout  time.Duration
+}
+
+// StatisticsRepo реализует repository.StatisticsBackend запросами к ClickHouse по HTTP
+type StatisticsRepo struct {
+	client   *http.Client
+	endpoint string
+	database string
+	user     string
+	password string
+}
+
+// NewStatisticsRepo создает новый экземпляр
+func NewStatisticsRepo(cfg Config) (*StatisticsRepo, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("clickhouse url is required")
+	}
+	if _, err := url.Parse(cfg.URL); err != nil {
+		return nil, fmt.Errorf("invalid clickhouse url: %w", err)
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	return &StatisticsRepo{
+		client:   &http.Client{Timeout: cfg.Timeout},
+		endpoint: cfg.URL,
+		database: cfg.Database,
+		user:     cfg.User,
+		password: cfg.Password,
+	}, nil
+}
+
+// Ping проверяет доступность ClickHouse и наличие таблицы событий
+func (r *StatisticsRepo) Ping(ctx context.Context) error {
+	var rows []struct {
+		Count int64 `json:"count"`
+	}
+	return r.query(ctx, `SELECT count() AS count FROM trivia_events WHERE 0`, nil, &rows)
+}
+
+// answersSQL — ответы с учётом исправлений: ответы на снятые вопросы не считаются верными
+// и не выбивают (voided), выбывания до второго шанса отменены. filter применяется к ответам,
+// выбываниям и отменам и может ссылаться на quiz_id, user_id и occurred_at.
+func answersSQL(filter string) string {
+	return `
+	SELECT a.quiz_id AS quiz_id, a.user_id AS user_id, a.occurred_at AS occurred_at,
+	       a.question_id AS question_id, a.question_number AS question_number,
+	       a.selected_option AS selected_option, a.response_time_ms AS response_time_ms,
+	       v.question_id IS NOT NULL AS voided,
+	       a.is_correct AND NOT voided AS is_correct,
+	       if(voided, '', ifNull(e.reason, '')) AS elimination_reason
+	FROM (
+		SELECT quiz_id, user_id, occurred_at,
+		       JSONExtractUInt(data, 'question_id') AS question_id,
+		       JSONExtractInt(data, 'question_number') AS question_number,
+		       JSONExtractInt(data, 'selected_option') AS selected_option,
+		       JSONExtractInt(data, 'response_time_ms') AS response_time_ms,
+		       JSONExtractBool(data, 'is_correct') AS is_correct
+		FROM trivia_events FINAL
+		WHERE event_type = 'answer_submitted' AND ` + filter + `
+	) AS a
+	LEFT JOIN (
+		SELECT DISTINCT quiz_id, JSONExtractUInt(data, 'question_id') AS question_id
+		FROM trivia_events FINAL
+		WHERE event_type = 'question_voided'
+	) AS v ON v.quiz_id = a.quiz_id AND v.question_id = a.question_id
+	LEFT JOIN (
+		SELECT el.quiz_id AS quiz_id, el.user_id AS user_id, el.question_id AS question_id, el.reason AS reason
+		FROM (
+			SELECT quiz_id, user_id, occurred_at,
+			       JSONExtractUInt(data, 'question_id') AS question_id,
+			       JSONExtractString(data, 'reason') AS reason
+			FROM trivia_events FINAL
+			WHERE event_type = 'elimination' AND ` + filter + `
+		) AS el
+		LEFT JOIN (
+			SELECT quiz_id, user_id, occurred_at AS revoked_at
+			FROM trivia_events FINAL
+			WHERE event_type = 'elimination_revoked' AND ` + filter + `
+		) AS r ON r.quiz_id = el.quiz_id AND r.user_id = el.user_id
+		WHERE r.revoked_at IS NULL OR el.occurred_at > r.revoked_at
+	) AS e ON e.quiz_id = a.quiz_id AND e.user_id = a.user_id AND e.question_id = a.question_id`
+}
+
+const (
+	quizFilter = `quiz_id = {quiz:UInt64}`
+	userFilter = `user_id = {user:UInt64} AND occurred_at >= toDateTime64({from:Int64}, 3, 'UTC')`
+)
+
+func quizParams(quizID uint) map[string]string {
+	return map[string]string{"quiz": strconv.FormatUint(uint64(quizID), 10)}
+}
+
+func userParams(userID uint, from time.Time) map[string]string {
+	return map[string]string{
+		"user": strconv.FormatUint(uint64(userID), 10),
+		"from": strconv.FormatInt(from.Unix(), 10),
+	}
+}
+
+// QuizAvgResponseTime возвращает среднее время ответа в викторине (без пропущенных вопросов)
+func (r *StatisticsRepo) QuizAvgResponseTime(quizID uint) (float64, error) {
+	var rows []struct {
+		Avg float64 `json:"avg"`
+	}
+	err := r.query(context.Background(), `
+		SELECT ifNotFinite(avg(JSONExtractInt(data, 'response_time_ms')), 0) AS avg
+		FROM trivia_events FINAL
+		WHERE event_type = 'answer_submitted' AND `+quizFilter+` AND JSONExtractInt(data, 'response_time_ms') > 0`,
+		quizParams(quizID), &rows)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get quiz average response time: %w", err)
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	return rows[0].Avg, nil
+}
+
+// QuizQuestionStats возвращает агрегаты ответов по вопросам викторины в порядке question_id
+func (r *StatisticsRepo) QuizQuestionStats(quizID uint) ([]repository.QuestionAnswerStats, error) {
+	var rows []repository.QuestionAnswerStats
+	err := r.query(context.Background(), `
+		SELECT question_id,
+		       countIf(elimination_reason != '') AS eliminated_count,
+		       countIf(elimination_reason IN ('time_exceeded', 'no_answer_timeout')) AS by_timeout,
+		       countIf(elimination_reason = 'incorrect_answer') AS by_wrong_answer,
+		       ifNotFinite(avgIf(response_time_ms, response_time_ms > 0), 0) AS avg_response_ms,
+		       count() AS total_answers,
+		       countIf(is_correct AND elimination_reason = '') AS passed_count
+		FROM (`+answersSQL(quizFilter)+`)
+		GROUP BY question_id
+		ORDER BY question_id`,
+		quizParams(quizID), &rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quiz question stats: %w", err)
+	}
+	return rows, nil
+}
+
+// UserAccuracyByDifficulty возвращает ответы по сложности вопроса и итоговую строку (Difficulty = 0)
+func (r *StatisticsRepo) UserAccuracyByDifficulty(userID uint, from time.Time) ([]repository.DifficultyAccuracy, error) {
+	var rows []repository.DifficultyAccuracy
+	err := r.query(context.Background(), `
+		SELECT dictGet('trivia_questions', 'difficulty', question_id) AS difficulty,
+		       count() AS answered, countIf(is_correct) AS correct
+		FROM (`+answersSQL(userFilter)+`)
+		WHERE NOT voided AND dictHas('trivia_questions', question_id)
+		GROUP BY difficulty WITH ROLLUP
+		ORDER BY difficulty`,
+		userParams(userID, from), &rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user accuracy by difficulty: %w", err)
+	}
+	return rows, nil
+}
+
+// UserWeeklyResponseTime возвращает среднее время ответа по неделям (без пропущенных вопросов)
+func (r *StatisticsRepo) UserWeeklyResponseTime(userID uint, from time.Time) ([]repository.WeeklyResponseTime, error) {
+	var rows []struct {
+		Week              int64   `json:"week"`
+		Answers           int64   `json:"answers"`
+		AvgResponseTimeMs float64 `json:"avg_response_time_ms"`
+	}
+	err := r.query(context.Background(), `
+		SELECT toUnixTimestamp(toDateTime(toMonday(occurred_at), 'UTC')) AS week, count() AS answers,
+		       avg(response_time_ms) AS avg_response_time_ms
+		FROM (`+answersSQL(userFilter)+`)
+		WHERE NOT voided AND selected_option >= 0 AND response_time_ms > 0
+		GROUP BY week
+		ORDER BY week`,
+		userParams(userID, from), &rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user response time trend: %w", err)
+	}
+	result := make([]repository.WeeklyResponseTime, len(rows))
+	for i, row := range rows {
+		result[i] = repository.WeeklyResponseTime{
+			Week:              time.Unix(row.Week, 0).UTC(),
+			Answers:           row.Answers,
+			AvgResponseTimeMs: row.AvgResponseTimeMs,
+		}
+	}
+	return result, nil
+}
+
+// UserEliminationStages возвращает гистограмму номеров вопросов, на которых игрок выбывал.
+// Выбывания с неизвестным номером вопроса (события без question_number) не учитываются.
+func (r *StatisticsRepo) UserEliminationStages(userID uint, from time.Time) ([]repository.EliminationStage, error) {
+	var rows []repository.EliminationStage
+	err := r.query(context.Background(), `
+		SELECT question_number, count() AS count
+		FROM (`+answersSQL(userFilter)+`)
+		WHERE elimination_reason != '' AND question_number > 0
+		GROUP BY question_number
+		ORDER BY question_number`,
+		userParams(userID, from), &rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user elimination stages: %w", err)
+	}
+	return rows, nil
+}
+
+// UserCategoryAccuracy возвращает ответы по темам вопросов; вопросы без темы не учитываются
+func (r *StatisticsRepo) UserCategoryAccuracy(userID uint, from time.Time) ([]repository.CategoryAccuracy, error) {
+	var rows []repository.CategoryAccuracy
+	err := r.query(context.Background(), `
+		SELECT category_id,
+		       dictGet('trivia_categories', 'slug', category_id) AS slug,
+		       dictGet('trivia_categories', 'name', category_id) AS name,
+		       count() AS answered, countIf(is_correct) AS correct
+		FROM (
+			SELECT dictGetOrDefault('trivia_questions', 'category_id', question_id, toUInt64(0)) AS category_id, is_correct
+			FROM (`+answersSQL(userFilter)+`)
+			WHERE NOT voided
+		)
+		WHERE dictHas('trivia_categories', category_id)
+		GROUP BY category_id, slug, name
+		ORDER BY answered DESC, category_id`,
+		userParams(userID, from), &rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user category accuracy: %w", err)
+	}
+	return rows, nil
+}
+
+// CountUserQuizzes возвращает количество сыгранных викторин: викторин, в которых у игрока есть ответы
+func (r *StatisticsRepo) CountUserQuizzes(userID uint, from time.Time) (int64, error) {
+	var rows []struct {
+		Count int64 `json:"count"`
+	}
+	err := r.query(context.Background(), `
+		SELECT uniqExact(quiz_id) AS count
+		FROM trivia_events
+		WHERE event_type = 'answer_submitted' AND `+userFilter,
+		userParams(userID, from), &rows)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count user quizzes: %w", err)
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	return rows[0].Count, nil
+}
+
+// query выполняет запрос с параметрами {name:Type} и разбирает строки ответа FORMAT JSON в dest
+func (r *StatisticsRepo) query(ctx context.Context, query string, params map[string]string, dest interface{}) error {
+	values := url.Values{}
+	if r.database != "" {
+		values.Set("database", r.database)
+	}
+	// Отсутствие строки в LEFT JOIN — NULL, а не значение по умолчанию; 64-битные числа — без кавычек
+	values.Set("join_use_nulls", "1")
+	values.Set("output_format_json_quote_64bit_integers", "0")
+	for name, value := range params {
+		values.Set("param_"+name, value)
+	}
+	endpoint := r.endpoint
+	if strings.Contains(endpoint, "?") {
+		endpoint += "&" + values.Encode()
+	} else {
+		endpoint += "?" + values.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint,
+		bytes.NewBufferString(strings.TrimSpace(query)+"\nFORMAT JSON"))
+	if err != nil {
+		return err
+	}
+	if r.user != "" {
+		req.Header.Set("X-ClickHouse-User", r.user)
+		req.Header.Set("X-ClickHouse-Key", r.password)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("clickhouse responded %d: %s", resp.StatusCode, bytes.TrimSpace(snippet))
+	}
+	var body struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("failed to decode clickhouse response: %w", err)
+	}
+	if len(body.Data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(body.Data, dest); err != nil {
+		return fmt.Errorf("failed to decode clickhouse rows: %w", err)
+	}
+	return nil
+}
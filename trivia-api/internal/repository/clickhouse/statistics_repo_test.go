@@ -0,0 +1,105 @@
+package clickhouse
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+)
+
+// clickhouseStub отвечает rows в формате FORMAT JSON и запоминает последний запрос
+type clickhouseStub struct {
+	rows    string
+	status  int
+	query   string
+	params  map[string]string
+	headers http.Header
+}
+
+func (s *clickhouseStub) start(t *testing.T) *StatisticsRepo {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		s.query = string(body)
+		s.headers = r.Header.Clone()
+		s.params = map[string]string{}
+		for name, values := range r.URL.Query() {
+			s.params[name] = values[0]
+		}
+		if s.status != 0 {
+			http.Error(w, "Code: 60. DB::Exception: Table trivia.trivia_events does not exist", s.status)
+			return
+		}
+		_, _ = io.WriteString(w, `{"meta":[],"data":`+s.rows+`,"rows":1}`)
+	}))
+	t.Cleanup(server.Close)
+
+	repo, err := NewStatisticsRepo(Config{URL: server.URL, Database: "trivia", User: "reader", Password: "secret"})
+	require.NoError(t, err)
+	return repo
+}
+
+func TestStatisticsRepo_QuizQuestionStats(t *testing.T) {
+	stub := &clickhouseStub{rows: `[
+		{"question_id":11,"eliminated_count":3,"by_timeout":1,"by_wrong_answer":2,"avg_response_ms":2150.5,"total_answers":10,"passed_count":7},
+		{"question_id":12,"eliminated_count":0,"by_timeout":0,"by_wrong_answer":0,"avg_response_ms":0,"total_answers":7,"passed_count":0}
+	]`}
+	repo := stub.start(t)
+
+	rows, err := repo.QuizQuestionStats(42)
+	require.NoError(t, err)
+
+	assert.Equal(t, []repository.QuestionAnswerStats{
+		{QuestionID: 11, EliminatedCount: 3, ByTimeout: 1, ByWrongAnswer: 2, AvgResponseMs: 2150.5, TotalAnswers: 10, PassedCount: 7},
+		{QuestionID: 12, TotalAnswers: 7},
+	}, rows)
+	assert.Equal(t, "42", stub.params["param_quiz"], "ID передаётся параметром, а не подставляется в текст запроса")
+	assert.Equal(t, "trivia", stub.params["database"])
+	assert.Equal(t, "1", stub.params["join_use_nulls"])
+	assert.Equal(t, "reader", stub.headers.Get("X-ClickHouse-User"))
+	assert.Equal(t, "secret", stub.headers.Get("X-ClickHouse-Key"))
+	assert.Contains(t, stub.query, "{quiz:UInt64}")
+	assert.Contains(t, stub.query, "'question_voided'", "исправления учитываются")
+	assert.True(t, strings.HasSuffix(stub.query, "FORMAT JSON"))
+}
+
+func TestStatisticsRepo_UserWeeklyResponseTime(t *testing.T) {
+	week := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+	stub := &clickhouseStub{rows: `[{"week":1791763200,"answers":14,"avg_response_time_ms":3120}]`}
+	repo := stub.start(t)
+
+	from := time.Date(2026, 7, 18, 9, 30, 0, 0, time.UTC)
+	rows, err := repo.UserWeeklyResponseTime(7, from)
+	require.NoError(t, err)
+
+	require.Len(t, rows, 1)
+	assert.Equal(t, week, rows[0].Week)
+	assert.Equal(t, int64(14), rows[0].Answers)
+	assert.Equal(t, "7", stub.params["param_user"])
+	assert.Equal(t, "1784367000", stub.params["param_from"])
+}
+
+func TestStatisticsRepo_EmptyAndErrors(t *testing.T) {
+	stub := &clickhouseStub{rows: `[]`}
+	repo := stub.start(t)
+
+	count, err := repo.CountUserQuizzes(7, time.Now())
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	stub.status = http.StatusNotFound
+	_, err = repo.QuizAvgResponseTime(42)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+	assert.Contains(t, err.Error(), "does not exist")
+
+	_, err = NewStatisticsRepo(Config{})
+	assert.Error(t, err)
+}
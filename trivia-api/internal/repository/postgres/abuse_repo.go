@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// AbuseRepo реализует repository.AbuseRepository
+type AbuseRepo struct {
+	db *gorm.DB
+}
+
+// NewAbuseRepo создает новый экземпляр
+func NewAbuseRepo(db *gorm.DB) *AbuseRepo {
+	return &AbuseRepo{db: db}
+}
+
+// CountAccountsByDevice возвращает число неудалённых аккаунтов, зарегистрированных с устройства
+func (r *AbuseRepo) CountAccountsByDevice(deviceHash string) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.User{}).
+		Where("registration_device_hash = ? AND deleted_at IS NULL", deviceHash).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count accounts by device: %w", err)
+	}
+	return count, nil
+}
+
+// ListSharedDevices возвращает устройства с несколькими аккаунтами
+func (r *AbuseRepo) ListSharedDevices(minAccounts int, limit, offset int) ([]repository.DeviceAccounts, int64, error) {
+	grouped := r.db.Model(&entity.User{}).
+		Select("registration_device_hash AS device_hash, COUNT(*) AS account_count, "+
+			"COUNT(shadow_banned_at) AS banned_count, MAX(created_at) AS last_created_at").
+		Where("registration_device_hash <> '' AND deleted_at IS NULL").
+		Group("registration_device_hash").
+		Having("COUNT(*) >= ?", minAccounts)
+
+	var total int64
+	if err := r.db.Table("(?) AS devices", grouped).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count shared devices: %w", err)
+	}
+	var devices []repository.DeviceAccounts
+	if err := grouped.Order("account_count DESC, last_created_at DESC").Limit(limit).Offset(offset).Scan(&devices).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list shared devices: %w", err)
+	}
+	return devices, total, nil
+}
+
+// ListUsersByDevice возвращает аккаунты, зарегистрированные с устройства
+func (r *AbuseRepo) ListUsersByDevice(deviceHash string) ([]entity.User, error) {
+	var users []entity.User
+	err := r.db.Where("registration_device_hash = ?", deviceHash).
+		Order("created_at ASC, id ASC").
+		Find(&users).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users by device: %w", err)
+	}
+	return users, nil
+}
+
+// ListShadowBanned возвращает аккаунты с теневым баном
+func (r *AbuseRepo) ListShadowBanned(limit, offset int) ([]entity.User, int64, error) {
+	query := r.db.Model(&entity.User{}).Where("shadow_banned_at IS NOT NULL")
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count shadow-banned users: %w", err)
+	}
+	var users []entity.User
+	if err := query.Order("shadow_banned_at DESC, id DESC").Limit(limit).Offset(offset).Find(&users).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list shadow-banned users: %w", err)
+	}
+	return users, total, nil
+}
+
+// SetShadowBan ставит или снимает теневой бан
+func (r *AbuseRepo) SetShadowBan(userID uint, bannedAt *time.Time, reason string) error {
+	result := r.db.Model(&entity.User{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"shadow_banned_at":  bannedAt,
+			"shadow_ban_reason": reason,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update shadow ban: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
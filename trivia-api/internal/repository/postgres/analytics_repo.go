@@ -0,0 +1,329 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	"gorm.io/gorm"
+)
+
+// activitySQL объединяет источники активности пользователя: входы/обновления сессий и участие в викторинах
+const activitySQL = `
+	SELECT user_id, created_at FROM refresh_tokens WHERE created_at >= @from AND created_at < @to
+	UNION ALL
+	SELECT user_id, created_at FROM results WHERE created_at >= @from AND created_at < @to`
+
+// AnalyticsRepo реализует repository.AnalyticsRepository
+type AnalyticsRepo struct {
+	db *gorm.DB
+}
+
+// NewAnalyticsRepo создает новый экземпляр
+func NewAnalyticsRepo(db *gorm.DB) *AnalyticsRepo {
+	return &AnalyticsRepo{db: db}
+}
+
+// CountActiveUsers возвращает количество уникальных активных пользователей за период
+func (r *AnalyticsRepo) CountActiveUsers(from, to time.Time) (int64, error) {
+	var count int64
+	err := r.db.Raw(`SELECT COUNT(DISTINCT user_id) FROM (`+activitySQL+`) a`,
+		map[string]interface{}{"from": from, "to": to}).Scan(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count active users: %w", err)
+	}
+	return count, nil
+}
+
+// DailyActiveUsers возвращает DAU по дням начиная с from
+func (r *AnalyticsRepo) DailyActiveUsers(from time.Time) ([]repository.DailyCount, error) {
+	var rows []repository.DailyCount
+	err := r.db.Raw(`
+		SELECT date_trunc('day', created_at) AS day, COUNT(DISTINCT user_id) AS count
+		FROM (`+activitySQL+`) a
+		GROUP BY 1 ORDER BY 1`,
+		map[string]interface{}{"from": from, "to": time.Now()}).Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily active users: %w", err)
+	}
+	return rows, nil
+}
+
+// DailyRegistrations возвращает количество регистраций по дням начиная с from
+func (r *AnalyticsRepo) DailyRegistrations(from time.Time) ([]repository.DailyCount, error) {
+	var rows []repository.DailyCount
+	err := r.db.Raw(`
+		SELECT date_trunc('day', created_at) AS day, COUNT(*) AS count
+		FROM users WHERE created_at >= ?
+		GROUP BY 1 ORDER BY 1`, from).Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily registrations: %w", err)
+	}
+	return rows, nil
+}
+
+// DailyParticipation возвращает участие в завершённых викторинах по дням начиная с from
+func (r *AnalyticsRepo) DailyParticipation(from time.Time) ([]repository.DailyParticipation, error) {
+	var rows []repository.DailyParticipation
+	err := r.db.Raw(`
+		SELECT day, COUNT(*) AS quizzes, SUM(participants) AS participants,
+		       AVG(participants)::float8 AS avg_participants_per_quiz
+		FROM (
+			SELECT date_trunc('day', q.scheduled_time) AS day, q.id,
+			       (SELECT COUNT(*) FROM results r WHERE r.quiz_id = q.id) AS participants
+			FROM quizzes q
+			WHERE q.status = ? AND q.scheduled_time >= ?
+		) per_quiz
+		GROUP BY day ORDER BY day`, entity.QuizStatusCompleted, from).Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quiz participation: %w", err)
+	}
+	return rows, nil
+}
+
+// CohortSizes возвращает размеры недельных когорт регистрации начиная с from
+func (r *AnalyticsRepo) CohortSizes(from time.Time) ([]repository.DailyCount, error) {
+	var rows []repository.DailyCount
+	err := r.db.Raw(`
+		SELECT date_trunc('week', created_at) AS day, COUNT(*) AS count
+		FROM users WHERE created_at >= ?
+		GROUP BY 1 ORDER BY 1`, from).Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cohort sizes: %w", err)
+	}
+	return rows, nil
+}
+
+// CohortActivity возвращает активность недельных когорт по неделям после регистрации
+func (r *AnalyticsRepo) CohortActivity(from time.Time) ([]repository.CohortActivity, error) {
+	var rows []repository.CohortActivity
+	err := r.db.Raw(`
+		WITH cohorts AS (
+			SELECT id AS user_id, date_trunc('week', created_at) AS cohort
+			FROM users WHERE created_at >= @from
+		),
+		activity AS (
+			SELECT DISTINCT user_id, date_trunc('week', created_at) AS week
+			FROM (`+activitySQL+`) a
+		)
+		SELECT c.cohort,
+		       (EXTRACT(EPOCH FROM (a.week - c.cohort)) / 604800)::int AS week_offset,
+		       COUNT(DISTINCT c.user_id) AS active_users
+		FROM cohorts c
+		JOIN activity a ON a.user_id = c.user_id AND a.week >= c.cohort
+		GROUP BY 1, 2 ORDER BY 1, 2`,
+		map[string]interface{}{"from": from, "to": time.Now()}).Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cohort activity: %w", err)
+	}
+	return rows, nil
+}
+
+// DailyConcurrentConnections возвращает среднее и пиковое число WS-подключений по дням.
+// Снимки всех экземпляров суммируются поминутно, затем усредняются за день.
+func (r *AnalyticsRepo) DailyConcurrentConnections(from time.Time) ([]repository.DailyAverage, error) {
+	var rows []repository.DailyAverage
+	err := r.db.Raw(`
+		SELECT date_trunc('day', minute) AS day, AVG(total)::float8 AS average, MAX(total) AS peak
+		FROM (
+			SELECT date_trunc('minute', sampled_at) AS minute, SUM(connections) AS total
+			FROM ws_connection_samples WHERE sampled_at >= ?
+			GROUP BY 1
+		) per_minute
+		GROUP BY 1 ORDER BY 1`, from).Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get concurrent connections: %w", err)
+	}
+	return rows, nil
+}
+
+// DailyAdImpressions возвращает показы рекламы по дням начиная с from
+func (r *AnalyticsRepo) DailyAdImpressions(from time.Time) ([]repository.AdImpressionStats, error) {
+	var rows []repository.AdImpressionStats
+	err := r.db.Raw(`
+		SELECT date_trunc('day', shown_at) AS day,
+			COUNT(*) FILTER (WHERE placement = ?) AS breaks,
+			COALESCE(SUM(viewers) FILTER (WHERE placement = ?), 0) AS impressions,
+			COALESCE(SUM(viewers) FILTER (WHERE placement <> ?), 0) AS sponsor_impressions
+		FROM ad_impressions WHERE shown_at >= ?
+		GROUP BY 1 ORDER BY 1`, entity.AdPlacementBreak, entity.AdPlacementBreak, entity.AdPlacementBreak, from).Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ad impressions: %w", err)
+	}
+	return rows, nil
+}
+
+// TopAdAssets возвращает рекламные материалы с наибольшим числом показов
+func (r *AnalyticsRepo) TopAdAssets(from time.Time, limit int) ([]repository.AdAssetImpressions, error) {
+	var rows []repository.AdAssetImpressions
+	err := r.db.Raw(`
+		SELECT i.ad_asset_id, a.title, COUNT(*) FILTER (WHERE i.placement = ?) AS breaks, COALESCE(SUM(i.viewers), 0) AS impressions
+		FROM ad_impressions i
+		JOIN ad_assets a ON a.id = i.ad_asset_id
+		WHERE i.shown_at >= ?
+		GROUP BY i.ad_asset_id, a.title
+		ORDER BY impressions DESC
+		LIMIT ?`, entity.AdPlacementBreak, from, limit).Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top ad assets: %w", err)
+	}
+	return rows, nil
+}
+
+// RecordConnectionSample сохраняет снимок количества WS-подключений
+func (r *AnalyticsRepo) RecordConnectionSample(sample *entity.WSConnectionSample) error {
+	if err := r.db.Create(sample).Error; err != nil {
+		return fmt.Errorf("failed to record connection sample: %w", err)
+	}
+	return nil
+}
+
+// DeleteConnectionSamplesBefore удаляет устаревшие снимки подключений
+func (r *AnalyticsRepo) DeleteConnectionSamplesBefore(before time.Time) error {
+	if err := r.db.Where("sampled_at < ?", before).Delete(&entity.WSConnectionSample{}).Error; err != nil {
+		return fmt.Errorf("failed to delete connection samples: %w", err)
+	}
+	return nil
+}
+
+// userAnswersSQL — учитываемые ответы игрока за период: без ответов на снятые с эфира вопросы
+const userAnswersSQL = `
+	SELECT a.quiz_id, a.question_id, a.is_correct, a.is_eliminated, a.elimination_reason,
+	       a.selected_option, a.response_time_ms, a.created_at
+	FROM user_answers a
+	WHERE a.user_id = @user AND a.created_at >= @from AND a.elimination_reason IS DISTINCT FROM '` + entity.AnswerReasonQuestionVoided + `'`
+
+// UserAccuracyByDifficulty возвращает ответы по сложности вопроса и итоговую строку (Difficulty = 0)
+func (r *AnalyticsRepo) UserAccuracyByDifficulty(userID uint, from time.Time) ([]repository.DifficultyAccuracy, error) {
+	var rows []repository.DifficultyAccuracy
+	err := r.db.Raw(`
+		SELECT COALESCE(q.difficulty, 0) AS difficulty,
+		       COUNT(*) AS answered, COUNT(*) FILTER (WHERE a.is_correct) AS correct
+		FROM (`+userAnswersSQL+`) a
+		JOIN questions q ON q.id = a.question_id
+		GROUP BY ROLLUP (q.difficulty)
+		ORDER BY 1`,
+		map[string]interface{}{"user": userID, "from": from}).Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user accuracy by difficulty: %w", err)
+	}
+	return rows, nil
+}
+
+// UserWeeklyResponseTime возвращает среднее время ответа по неделям (без пропущенных вопросов)
+func (r *AnalyticsRepo) UserWeeklyResponseTime(userID uint, from time.Time) ([]repository.WeeklyResponseTime, error) {
+	var rows []repository.WeeklyResponseTime
+	err := r.db.Raw(`
+		SELECT date_trunc('week', created_at) AS week, COUNT(*) AS answers,
+		       AVG(response_time_ms)::float8 AS avg_response_time_ms
+		FROM (`+userAnswersSQL+`) a
+		WHERE selected_option >= 0 AND response_time_ms > 0
+		GROUP BY 1 ORDER BY 1`,
+		map[string]interface{}{"user": userID, "from": from}).Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user response time trend: %w", err)
+	}
+	return rows, nil
+}
+
+// UserEliminationStages возвращает гистограмму номеров вопросов, на которых игрок выбывал.
+// Номер вопроса берётся из истории показа викторины; выбывание, отменённое вторым шансом, не считается.
+func (r *AnalyticsRepo) UserEliminationStages(userID uint, from time.Time) ([]repository.EliminationStage, error) {
+	var rows []repository.EliminationStage
+	err := r.db.Raw(`
+		SELECT h.question_order AS question_number, COUNT(*) AS count
+		FROM (`+userAnswersSQL+`) a
+		JOIN quiz_question_history h ON h.quiz_id = a.quiz_id AND h.question_id = a.question_id
+		WHERE a.is_eliminated AND a.elimination_reason IS DISTINCT FROM @second_chance
+		GROUP BY 1 ORDER BY 1`,
+		map[string]interface{}{"user": userID, "from": from, "second_chance": entity.AnswerReasonSecondChance}).Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user elimination stages: %w", err)
+	}
+	return rows, nil
+}
+
+// UserCategoryAccuracy возвращает ответы по темам вопросов; вопросы без темы не учитываются
+func (r *AnalyticsRepo) UserCategoryAccuracy(userID uint, from time.Time) ([]repository.CategoryAccuracy, error) {
+	var rows []repository.CategoryAccuracy
+	err := r.db.Raw(`
+		SELECT c.id AS category_id, c.slug, c.name,
+		       COUNT(*) AS answered, COUNT(*) FILTER (WHERE a.is_correct) AS correct
+		FROM (`+userAnswersSQL+`) a
+		JOIN questions q ON q.id = a.question_id
+		JOIN categories c ON c.id = q.category_id
+		GROUP BY c.id, c.slug, c.name
+		ORDER BY answered DESC, c.id`,
+		map[string]interface{}{"user": userID, "from": from}).Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user category accuracy: %w", err)
+	}
+	return rows, nil
+}
+
+// CountUserQuizzes возвращает количество сыгранных викторин
+func (r *AnalyticsRepo) CountUserQuizzes(userID uint, from time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.Result{}).
+		Where("user_id = ? AND created_at >= ?", userID, from).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count user quizzes: %w", err)
+	}
+	return count, nil
+}
+
+// QuizAvgResponseTime возвращает среднее время ответа в викторине (без пропущенных вопросов)
+func (r *AnalyticsRepo) QuizAvgResponseTime(quizID uint) (float64, error) {
+	var avg float64
+	err := r.db.Table("user_answers").
+		Select("COALESCE(AVG(response_time_ms), 0)").
+		Where("quiz_id = ? AND response_time_ms > 0", quizID).
+		Scan(&avg).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to get quiz average response time: %w", err)
+	}
+	return avg, nil
+}
+
+// QuizQuestionStats возвращает агрегаты ответов по вопросам викторины в порядке question_id
+func (r *AnalyticsRepo) QuizQuestionStats(quizID uint) ([]repository.QuestionAnswerStats, error) {
+	var rows []repository.QuestionAnswerStats
+	err := r.db.Table("user_answers").
+		Select(`
+			question_id,
+			COUNT(*) FILTER (WHERE is_eliminated = true) as eliminated_count,
+			COUNT(*) FILTER (WHERE elimination_reason IN ('time_exceeded', 'no_answer_timeout')) as by_timeout,
+			COUNT(*) FILTER (WHERE elimination_reason = 'incorrect_answer') as by_wrong_answer,
+			COALESCE(AVG(response_time_ms) FILTER (WHERE response_time_ms > 0), 0) as avg_response_ms,
+			COUNT(*) as total_answers,
+			COUNT(*) FILTER (WHERE is_correct = true AND is_eliminated = false) as passed_count
+		`).
+		Where("quiz_id = ?", quizID).
+		Group("question_id").
+		Order("question_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quiz question stats: %w", err)
+	}
+	return rows, nil
+}
+
+// AdImpressionRepo реализует repository.AdImpressionRepository
+type AdImpressionRepo struct {
+	db *gorm.DB
+}
+
+// NewAdImpressionRepo создает новый экземпляр
+func NewAdImpressionRepo(db *gorm.DB) *AdImpressionRepo {
+	return &AdImpressionRepo{db: db}
+}
+
+// Create сохраняет показ рекламной паузы
+func (r *AdImpressionRepo) Create(impression *entity.AdImpression) error {
+	if err := r.db.Create(impression).Error; err != nil {
+		return fmt.Errorf("failed to record ad impression: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// APIKeyRepo реализует repository.APIKeyRepository
+type APIKeyRepo struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepo создает новый экземпляр
+func NewAPIKeyRepo(db *gorm.DB) *APIKeyRepo {
+	return &APIKeyRepo{db: db}
+}
+
+// Create сохраняет ключ
+func (r *APIKeyRepo) Create(key *entity.APIKey) error {
+	if err := r.db.Create(key).Error; err != nil {
+		return fmt.Errorf("failed to create api key: %w", err)
+	}
+	return nil
+}
+
+// GetByID возвращает ключ по ID
+func (r *APIKeyRepo) GetByID(id uint) (*entity.APIKey, error) {
+	var key entity.APIKey
+	err := r.db.First(&key, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api key %d: %w", id, err)
+	}
+	return &key, nil
+}
+
+// GetByHash возвращает ключ по SHA-256 от ключа
+func (r *APIKeyRepo) GetByHash(keyHash string) (*entity.APIKey, error) {
+	var key entity.APIKey
+	err := r.db.Where("key_hash = ?", keyHash).First(&key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api key by hash: %w", err)
+	}
+	return &key, nil
+}
+
+// List возвращает все ключи, новые первыми
+func (r *APIKeyRepo) List() ([]entity.APIKey, error) {
+	var keys []entity.APIKey
+	if err := r.db.Order("id DESC").Find(&keys).Error; err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	return keys, nil
+}
+
+// Revoke отзывает ключ
+func (r *APIKeyRepo) Revoke(id uint, at time.Time) error {
+	result := r.db.Model(&entity.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{"revoked_at": at, "updated_at": at})
+	if result.Error != nil {
+		return fmt.Errorf("failed to revoke api key %d: %w", id, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
+
+// AddUsage прибавляет счётчики к суточной статистике. Инстансы API сбрасывают свои счётчики
+// независимо, поэтому значения складываются в ON CONFLICT, а не перезаписываются.
+func (r *APIKeyRepo) AddUsage(usage []entity.APIKeyUsage, lastUsed map[uint]time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if len(usage) > 0 {
+			err := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "key_id"}, {Name: "day"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{
+					"requests":     gorm.Expr("api_key_usage.requests + EXCLUDED.requests"),
+					"rate_limited": gorm.Expr("api_key_usage.rate_limited + EXCLUDED.rate_limited"),
+					"errors":       gorm.Expr("api_key_usage.errors + EXCLUDED.errors"),
+				}),
+			}).Create(&usage).Error
+			if err != nil {
+				return fmt.Errorf("failed to add api key usage: %w", err)
+			}
+		}
+		for keyID, at := range lastUsed {
+			err := tx.Model(&entity.APIKey{}).
+				Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", keyID, at).
+				UpdateColumn("last_used_at", at).Error
+			if err != nil {
+				return fmt.Errorf("failed to update api key %d last_used_at: %w", keyID, err)
+			}
+		}
+		return nil
+	})
+}
+
+// ListUsage возвращает суточную статистику ключа начиная с since
+func (r *APIKeyRepo) ListUsage(keyID uint, since time.Time) ([]entity.APIKeyUsage, error) {
+	var usage []entity.APIKeyUsage
+	err := r.db.Where("key_id = ? AND day >= ?", keyID, since).Order("day ASC").Find(&usage).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api key usage: %w", err)
+	}
+	return usage, nil
+}
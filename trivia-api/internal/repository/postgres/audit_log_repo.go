@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	"gorm.io/gorm"
+)
+
+// AuditLogRepo реализует repository.AuditLogRepository
+type AuditLogRepo struct {
+	db *gorm.DB
+}
+
+// NewAuditLogRepo создает новый экземпляр
+func NewAuditLogRepo(db *gorm.DB) *AuditLogRepo {
+	return &AuditLogRepo{db: db}
+}
+
+// Create добавляет запись в журнал
+func (r *AuditLogRepo) Create(ctx context.Context, entry *entity.AuditLog) error {
+	if err := r.db.WithContext(ctx).Create(entry).Error; err != nil {
+		return fmt.Errorf("failed to create audit log entry: %w", err)
+	}
+	return nil
+}
+
+// List возвращает записи (новые первыми) и общее количество
+func (r *AuditLogRepo) List(filters repository.AuditLogFilters, limit, offset int) ([]entity.AuditLog, int64, error) {
+	query := r.db.Model(&entity.AuditLog{})
+	if filters.Action != "" {
+		query = query.Where("action = ?", filters.Action)
+	}
+	if filters.ActorID != nil {
+		query = query.Where("actor_id = ?", *filters.ActorID)
+	}
+	if filters.TargetType != "" {
+		query = query.Where("target_type = ?", filters.TargetType)
+	}
+	if filters.TargetID != "" {
+		query = query.Where("target_id = ?", filters.TargetID)
+	}
+	if filters.From != nil {
+		query = query.Where("created_at >= ?", *filters.From)
+	}
+	if filters.To != nil {
+		query = query.Where("created_at < ?", *filters.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit log entries: %w", err)
+	}
+
+	var entries []entity.AuditLog
+	if err := query.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&entries).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list audit log entries: %w", err)
+	}
+	return entries, total, nil
+}
@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DailyChallengeRepo реализует repository.DailyChallengeRepository
+type DailyChallengeRepo struct {
+	db *gorm.DB
+}
+
+// NewDailyChallengeRepo создает новый экземпляр
+func NewDailyChallengeRepo(db *gorm.DB) *DailyChallengeRepo {
+	return &DailyChallengeRepo{db: db}
+}
+
+// GetChallenge возвращает вопрос дня с вопросом
+func (r *DailyChallengeRepo) GetChallenge(day time.Time) (*entity.DailyChallenge, error) {
+	var challenge entity.DailyChallenge
+	err := r.db.Preload("Question").Where("day = ?", day.Format(time.DateOnly)).First(&challenge).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily challenge: %w", err)
+	}
+	return &challenge, nil
+}
+
+// CreateChallenge сохраняет вопрос дня, если день ещё свободен, и возвращает сохранённый
+func (r *DailyChallengeRepo) CreateChallenge(challenge *entity.DailyChallenge) (*entity.DailyChallenge, error) {
+	if err := r.db.Omit("Question").Clauses(clause.OnConflict{DoNothing: true}).Create(challenge).Error; err != nil {
+		return nil, fmt.Errorf("failed to create daily challenge: %w", err)
+	}
+	return r.GetChallenge(challenge.Day)
+}
+
+// CountAnswers возвращает число ответов за день и верных среди них
+func (r *DailyChallengeRepo) CountAnswers(day time.Time) (int64, int64, error) {
+	var counts struct {
+		Total   int64
+		Correct int64
+	}
+	if err := r.db.Model(&entity.DailyChallengeAnswer{}).
+		Select("COUNT(*) AS total, COUNT(*) FILTER (WHERE is_correct) AS correct").
+		Where("day = ?", day.Format(time.DateOnly)).
+		Scan(&counts).Error; err != nil {
+		return 0, 0, fmt.Errorf("failed to count daily answers: %w", err)
+	}
+	return counts.Total, counts.Correct, nil
+}
+
+// GetAnswer возвращает ответ пользователя за день
+func (r *DailyChallengeRepo) GetAnswer(day time.Time, userID uint) (*entity.DailyChallengeAnswer, error) {
+	var answer entity.DailyChallengeAnswer
+	err := r.db.Where("day = ? AND user_id = ?", day.Format(time.DateOnly), userID).First(&answer).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily answer: %w", err)
+	}
+	return &answer, nil
+}
+
+// SaveAnswer сохраняет ответ и обновляет серию вопросов дня под блокировкой строки серий
+func (r *DailyChallengeRepo) SaveAnswer(answer *entity.DailyChallengeAnswer, update repository.DailyStreakUpdate) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(answer).Error; err != nil {
+			if isUniqueViolation(err) {
+				return fmt.Errorf("%w: daily challenge already answered", apperrors.ErrConflict)
+			}
+			return fmt.Errorf("failed to save daily answer: %w", err)
+		}
+
+		streak := entity.UserStreak{UserID: answer.UserID}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ?", answer.UserID).Limit(1).Find(&streak).Error; err != nil {
+			return fmt.Errorf("failed to load user streaks: %w", err)
+		}
+		update(&streak)
+		if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&streak).Error; err != nil {
+			return fmt.Errorf("failed to save user streaks: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetLeaderboard возвращает верно ответивших за день, быстрые первыми
+func (r *DailyChallengeRepo) GetLeaderboard(day time.Time, limit, offset int) ([]repository.DailyLeaderboardEntry, int64, error) {
+	query := r.db.Table("daily_challenge_answers a").
+		Joins("JOIN users u ON u.id = a.user_id").
+		Where("a.day = ? AND a.is_correct AND u.deleted_at IS NULL AND u.shadow_banned_at IS NULL", day.Format(time.DateOnly))
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count daily leaderboard: %w", err)
+	}
+	var entries []repository.DailyLeaderboardEntry
+	if err := query.
+		Select("a.user_id, u.username, u.profile_picture, a.response_time_ms, u.show_on_leaderboards").
+		Order("a.response_time_ms ASC, a.id ASC").
+		Limit(limit).Offset(offset).
+		Scan(&entries).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get daily leaderboard: %w", err)
+	}
+	return entries, total, nil
+}
@@ -0,0 +1,174 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EmailRepo реализует repository.EmailRepository
+type EmailRepo struct {
+	db *gorm.DB
+}
+
+// NewEmailRepo создает новый экземпляр
+func NewEmailRepo(db *gorm.DB) *EmailRepo {
+	return &EmailRepo{db: db}
+}
+
+// CreateMessage записывает письмо, принятое провайдером
+func (r *EmailRepo) CreateMessage(message *entity.EmailMessage) error {
+	if err := r.db.Create(message).Error; err != nil {
+		return fmt.Errorf("failed to create email message: %w", err)
+	}
+	return nil
+}
+
+// ListMessages возвращает письма (новые первыми) и общее количество
+func (r *EmailRepo) ListMessages(recipient, status string, limit, offset int) ([]entity.EmailMessage, int64, error) {
+	query := r.db.Model(&entity.EmailMessage{})
+	if recipient != "" {
+		query = query.Where("recipient = ?", recipient)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count email messages: %w", err)
+	}
+	var messages []entity.EmailMessage
+	if err := query.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&messages).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list email messages: %w", err)
+	}
+	return messages, total, nil
+}
+
+// ListEvents возвращает события доставки письма по времени
+func (r *EmailRepo) ListEvents(messageID uint) ([]entity.EmailDeliveryEvent, error) {
+	var count int64
+	if err := r.db.Model(&entity.EmailMessage{}).Where("id = ?", messageID).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("failed to get email message %d: %w", messageID, err)
+	}
+	if count == 0 {
+		return nil, apperrors.ErrNotFound
+	}
+
+	var events []entity.EmailDeliveryEvent
+	if err := r.db.Where("message_id = ?", messageID).Order("occurred_at ASC, id ASC").Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to list email events: %w", err)
+	}
+	return events, nil
+}
+
+// RecordEvent записывает событие доставки и обновляет статус письма в одной транзакции.
+// Условие на текущий статус не даёт событию, пришедшему не по порядку, откатить статус назад.
+func (r *EmailRepo) RecordEvent(event *entity.EmailDeliveryEvent, status string, fromStatuses []string) (bool, error) {
+	recorded := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var message entity.EmailMessage
+		err := tx.Select("id").
+			Where("provider = ? AND provider_message_id = ?", event.Provider, event.ProviderMessageID).
+			First(&message).Error
+		switch {
+		case err == nil:
+			event.MessageID = &message.ID
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return fmt.Errorf("failed to find email message: %w", err)
+		}
+
+		result := tx.Clauses(clause.OnConflict{
+			Columns:     []clause.Column{{Name: "provider"}, {Name: "external_id"}},
+			TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "external_id <> ''"}}},
+			DoNothing:   true,
+		}).Create(event)
+		if result.Error != nil {
+			return fmt.Errorf("failed to create email event: %w", result.Error)
+		}
+		if result.RowsAffected == 0 || event.MessageID == nil {
+			recorded = result.RowsAffected > 0
+			return nil
+		}
+		recorded = true
+
+		if err := tx.Model(&entity.EmailMessage{}).
+			Where("id = ? AND (last_event_at IS NULL OR last_event_at < ?)", *event.MessageID, event.OccurredAt).
+			Updates(map[string]interface{}{"last_event_at": event.OccurredAt, "updated_at": time.Now()}).Error; err != nil {
+			return fmt.Errorf("failed to update email message: %w", err)
+		}
+		if status == "" {
+			return nil
+		}
+		if err := tx.Model(&entity.EmailMessage{}).
+			Where("id = ? AND status IN ?", *event.MessageID, fromStatuses).
+			Updates(map[string]interface{}{"status": status, "updated_at": time.Now()}).Error; err != nil {
+			return fmt.Errorf("failed to update email message status: %w", err)
+		}
+		return nil
+	})
+	return recorded, err
+}
+
+// CreateDeadLetter записывает недоставленное письмо
+func (r *EmailRepo) CreateDeadLetter(letter *entity.EmailDeadLetter) error {
+	if err := r.db.Create(letter).Error; err != nil {
+		return fmt.Errorf("failed to create email dead letter: %w", err)
+	}
+	return nil
+}
+
+// GetDeadLetter возвращает недоставленное письмо по ID
+func (r *EmailRepo) GetDeadLetter(id uint) (*entity.EmailDeadLetter, error) {
+	var letter entity.EmailDeadLetter
+	err := r.db.First(&letter, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get email dead letter %d: %w", id, err)
+	}
+	return &letter, nil
+}
+
+// ListDeadLetters возвращает недоставленные письма (новые первыми) и общее количество
+func (r *EmailRepo) ListDeadLetters(pendingOnly bool, limit, offset int) ([]entity.EmailDeadLetter, int64, error) {
+	query := r.db.Model(&entity.EmailDeadLetter{})
+	if pendingOnly {
+		query = query.Where("retried_at IS NULL")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count email dead letters: %w", err)
+	}
+	var letters []entity.EmailDeadLetter
+	if err := query.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&letters).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list email dead letters: %w", err)
+	}
+	return letters, total, nil
+}
+
+// ClaimDeadLetterRetry отмечает письмо переотправляемым условным UPDATE, чтобы два запроса не отправили его дважды
+func (r *EmailRepo) ClaimDeadLetterRetry(id uint, now time.Time) (bool, error) {
+	result := r.db.Model(&entity.EmailDeadLetter{}).
+		Where("id = ? AND retried_at IS NULL", id).
+		Updates(map[string]interface{}{"retried_at": now, "updated_at": now})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to claim email dead letter %d: %w", id, result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// UpdateDeadLetter сохраняет результат повторной отправки
+func (r *EmailRepo) UpdateDeadLetter(letter *entity.EmailDeadLetter) error {
+	if err := r.db.Save(letter).Error; err != nil {
+		return fmt.Errorf("failed to update email dead letter: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// EmailTemplateRepo реализует repository.EmailTemplateRepository
+type EmailTemplateRepo struct {
+	db *gorm.DB
+}
+
+// NewEmailTemplateRepo создает новый экземпляр
+func NewEmailTemplateRepo(db *gorm.DB) *EmailTemplateRepo {
+	return &EmailTemplateRepo{db: db}
+}
+
+// GetActive возвращает активную версию шаблона
+func (r *EmailTemplateRepo) GetActive(key, locale string) (*entity.EmailTemplate, error) {
+	var tpl entity.EmailTemplate
+	err := r.db.Where("key = ? AND locale = ? AND is_active", key, locale).First(&tpl).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get email template %s/%s: %w", key, locale, err)
+	}
+	return &tpl, nil
+}
+
+// GetVersion возвращает версию шаблона
+func (r *EmailTemplateRepo) GetVersion(key, locale string, version int) (*entity.EmailTemplate, error) {
+	var tpl entity.EmailTemplate
+	err := r.db.Where("key = ? AND locale = ? AND version = ?", key, locale, version).First(&tpl).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get email template %s/%s v%d: %w", key, locale, version, err)
+	}
+	return &tpl, nil
+}
+
+// ListActive возвращает активные версии всех шаблонов
+func (r *EmailTemplateRepo) ListActive() ([]entity.EmailTemplate, error) {
+	var templates []entity.EmailTemplate
+	if err := r.db.Where("is_active").Order("key, locale").Find(&templates).Error; err != nil {
+		return nil, fmt.Errorf("failed to list email templates: %w", err)
+	}
+	return templates, nil
+}
+
+// ListVersions возвращает версии шаблона (новые первыми)
+func (r *EmailTemplateRepo) ListVersions(key, locale string) ([]entity.EmailTemplate, error) {
+	var templates []entity.EmailTemplate
+	if err := r.db.Where("key = ? AND locale = ?", key, locale).Order("version DESC").Find(&templates).Error; err != nil {
+		return nil, fmt.Errorf("failed to list email template versions: %w", err)
+	}
+	return templates, nil
+}
+
+// CreateVersion сохраняет следующую версию и делает её активной. Одновременное сохранение
+// той же версии отклоняется уникальным индексом (apperrors.ErrConflict).
+func (r *EmailTemplateRepo) CreateVersion(tpl *entity.EmailTemplate) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var latest int
+		if err := tx.Model(&entity.EmailTemplate{}).
+			Where("key = ? AND locale = ?", tpl.Key, tpl.Locale).
+			Select("COALESCE(MAX(version), 0)").Scan(&latest).Error; err != nil {
+			return fmt.Errorf("failed to get latest email template version: %w", err)
+		}
+		if err := tx.Model(&entity.EmailTemplate{}).
+			Where("key = ? AND locale = ? AND is_active", tpl.Key, tpl.Locale).
+			Update("is_active", false).Error; err != nil {
+			return fmt.Errorf("failed to deactivate email template: %w", err)
+		}
+		tpl.Version = latest + 1
+		tpl.IsActive = true
+		return tx.Create(tpl).Error
+	})
+	if isUniqueViolation(err) {
+		return fmt.Errorf("%w: template was changed concurrently, retry", apperrors.ErrConflict)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create email template version: %w", err)
+	}
+	return nil
+}
+
+// Activate делает версию активной, снимая активность с остальных
+func (r *EmailTemplateRepo) Activate(key, locale string, version int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.EmailTemplate{}).
+			Where("key = ? AND locale = ? AND is_active AND version <> ?", key, locale, version).
+			Update("is_active", false).Error; err != nil {
+			return fmt.Errorf("failed to deactivate email template: %w", err)
+		}
+		result := tx.Model(&entity.EmailTemplate{}).
+			Where("key = ? AND locale = ? AND version = ?", key, locale, version).
+			Update("is_active", true)
+		if result.Error != nil {
+			return fmt.Errorf("failed to activate email template: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return apperrors.ErrNotFound
+		}
+		return nil
+	})
+}
+
+// DeactivateAll снимает активную версию шаблона
+func (r *EmailTemplateRepo) DeactivateAll(key, locale string) error {
+	if err := r.db.Model(&entity.EmailTemplate{}).
+		Where("key = ? AND locale = ? AND is_active", key, locale).
+		Update("is_active", false).Error; err != nil {
+		return fmt.Errorf("failed to deactivate email template: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// ExportJobRepo реализует repository.ExportJobRepository
+type ExportJobRepo struct {
+	db *gorm.DB
+}
+
+// NewExportJobRepo создает новый экземпляр
+func NewExportJobRepo(db *gorm.DB) *ExportJobRepo {
+	return &ExportJobRepo{db: db}
+}
+
+// Create сохраняет новое задание выгрузки
+func (r *ExportJobRepo) Create(job *entity.ExportJob) error {
+	if err := r.db.Create(job).Error; err != nil {
+		return fmt.Errorf("failed to create export job: %w", err)
+	}
+	return nil
+}
+
+// GetByID возвращает задание выгрузки
+func (r *ExportJobRepo) GetByID(id uint) (*entity.ExportJob, error) {
+	var job entity.ExportJob
+	if err := r.db.First(&job, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get export job: %w", err)
+	}
+	return &job, nil
+}
+
+// ListByQuiz возвращает последние задания викторины, новые первыми
+func (r *ExportJobRepo) ListByQuiz(quizID uint, limit int) ([]entity.ExportJob, error) {
+	var jobs []entity.ExportJob
+	if err := r.db.Where("quiz_id = ?", quizID).Order("id DESC").Limit(limit).Find(&jobs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list export jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// Claim забирает одно задание. SKIP LOCKED не даёт двум инстансам забрать одну строку;
+// задание упавшего обработчика забирается снова, когда истекает его аренда.
+func (r *ExportJobRepo) Claim(now time.Time, lease time.Duration) (*entity.ExportJob, error) {
+	var jobs []entity.ExportJob
+	err := r.db.Raw(`
+		UPDATE export_jobs
+		SET status = @running, lease_until = @lease_until, attempts = attempts + 1,
+		    started_at = COALESCE(started_at, @now), updated_at = @now
+		WHERE id = (
+			SELECT id FROM export_jobs
+			WHERE status = @pending OR (status = @running AND lease_until < @now)
+			ORDER BY id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		map[string]interface{}{
+			"now":         now,
+			"lease_until": now.Add(lease),
+			"pending":     entity.ExportJobPending,
+			"running":     entity.ExportJobRunning,
+		}).Scan(&jobs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim export job: %w", err)
+	}
+	if len(jobs) == 0 {
+		return nil, nil
+	}
+	return &jobs[0], nil
+}
+
+// UpdateProgress сохраняет число записанных строк и продлевает аренду
+func (r *ExportJobRepo) UpdateProgress(id uint, rowsDone int64, leaseUntil time.Time) error {
+	err := r.db.Model(&entity.ExportJob{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"rows_done": rowsDone, "lease_until": leaseUntil}).Error
+	if err != nil {
+		return fmt.Errorf("failed to update export job progress: %w", err)
+	}
+	return nil
+}
+
+// Save сохраняет задание целиком
+func (r *ExportJobRepo) Save(job *entity.ExportJob) error {
+	if err := r.db.Save(job).Error; err != nil {
+		return fmt.Errorf("failed to save export job: %w", err)
+	}
+	return nil
+}
+
+// ListExpired возвращает выполненные задания, срок хранения файлов которых истёк
+func (r *ExportJobRepo) ListExpired(now time.Time, limit int) ([]entity.ExportJob, error) {
+	var jobs []entity.ExportJob
+	err := r.db.Where("status = ? AND expires_at < ?", entity.ExportJobCompleted, now).
+		Order("id").Limit(limit).Find(&jobs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired export jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// CountQuizResults возвращает количество результатов викторины
+func (r *ExportJobRepo) CountQuizResults(quizID uint) (int64, error) {
+	var count int64
+	if err := r.db.Model(&entity.Result{}).Where("quiz_id = ?", quizID).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count quiz results: %w", err)
+	}
+	return count, nil
+}
+
+// ListQuizResultsAfter возвращает результаты в порядке (rank, id) после заданной пары
+func (r *ExportJobRepo) ListQuizResultsAfter(quizID uint, afterRank int, afterID uint, limit int) ([]entity.Result, error) {
+	var results []entity.Result
+	err := r.db.Where("quiz_id = ? AND (rank, id) > (?, ?)", quizID, afterRank, afterID).
+		Order("rank, id").Limit(limit).Find(&results).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quiz results: %w", err)
+	}
+	return results, nil
+}
+
+// CountQuizAnswers возвращает количество ответов викторины
+func (r *ExportJobRepo) CountQuizAnswers(quizID uint) (int64, error) {
+	var count int64
+	if err := r.db.Model(&entity.UserAnswer{}).Where("quiz_id = ?", quizID).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count quiz answers: %w", err)
+	}
+	return count, nil
+}
+
+// ListQuizAnswersAfter возвращает ответы викторины с id больше afterID в порядке id
+func (r *ExportJobRepo) ListQuizAnswersAfter(quizID uint, afterID uint, limit int) ([]repository.AnswerExportRow, error) {
+	var rows []repository.AnswerExportRow
+	err := r.db.Raw(`
+		SELECT a.id, a.user_id, COALESCE(u.username, '') AS username, h.question_order AS question_number,
+		       a.question_id, COALESCE(q.text, '') AS question_text, a.selected_option, a.is_correct,
+		       a.response_time_ms, a.score, a.is_eliminated, COALESCE(a.elimination_reason, '') AS elimination_reason,
+		       a.created_at
+		FROM user_answers a
+		LEFT JOIN users u ON u.id = a.user_id
+		LEFT JOIN questions q ON q.id = a.question_id
+		LEFT JOIN quiz_question_history h ON h.quiz_id = a.quiz_id AND h.question_id = a.question_id
+		WHERE a.quiz_id = ? AND a.id > ?
+		ORDER BY a.id
+		LIMIT ?`, quizID, afterID, limit).Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quiz answers: %w", err)
+	}
+	return rows, nil
+}
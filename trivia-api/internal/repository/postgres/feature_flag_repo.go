@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// FeatureFlagRepo реализует repository.FeatureFlagRepository
+type FeatureFlagRepo struct {
+	db *gorm.DB
+}
+
+// NewFeatureFlagRepo создает новый экземпляр
+func NewFeatureFlagRepo(db *gorm.DB) *FeatureFlagRepo {
+	return &FeatureFlagRepo{db: db}
+}
+
+// List возвращает все флаги, отсортированные по ключу
+func (r *FeatureFlagRepo) List() ([]entity.FeatureFlag, error) {
+	var flags []entity.FeatureFlag
+	if err := r.db.Order("key ASC").Find(&flags).Error; err != nil {
+		return nil, fmt.Errorf("failed to list feature flags: %w", err)
+	}
+	return flags, nil
+}
+
+// GetByKey возвращает флаг по ключу
+func (r *FeatureFlagRepo) GetByKey(key string) (*entity.FeatureFlag, error) {
+	var flag entity.FeatureFlag
+	err := r.db.Where("key = ?", key).First(&flag).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feature flag %s: %w", key, err)
+	}
+	return &flag, nil
+}
+
+// Create создаёт флаг
+func (r *FeatureFlagRepo) Create(flag *entity.FeatureFlag) error {
+	if err := r.db.Create(flag).Error; err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("%w: feature flag %s already exists", apperrors.ErrConflict, flag.Key)
+		}
+		return fmt.Errorf("failed to create feature flag: %w", err)
+	}
+	return nil
+}
+
+// Update сохраняет изменённый флаг
+func (r *FeatureFlagRepo) Update(flag *entity.FeatureFlag) error {
+	if err := r.db.Save(flag).Error; err != nil {
+		return fmt.Errorf("failed to update feature flag: %w", err)
+	}
+	return nil
+}
+
+// Delete удаляет флаг по ключу
+func (r *FeatureFlagRepo) Delete(key string) error {
+	result := r.db.Where("key = ?", key).Delete(&entity.FeatureFlag{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete feature flag: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
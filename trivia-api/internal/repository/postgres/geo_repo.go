@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GeoRepo реализует repository.GeoRepository
+type GeoRepo struct {
+	db *gorm.DB
+}
+
+// NewGeoRepo создает новый экземпляр
+func NewGeoRepo(db *gorm.DB) *GeoRepo {
+	return &GeoRepo{db: db}
+}
+
+// RecordQuizJoin сохраняет страну подключения к викторине
+func (r *GeoRepo) RecordQuizJoin(check *entity.QuizGeoCheck) error {
+	err := r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "quiz_id"}, {Name: "user_id"}, {Name: "country"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"attempts":     gorm.Expr("quiz_geo_checks.attempts + 1"),
+			"ip_address":   gorm.Expr("EXCLUDED.ip_address"),
+			"last_seen_at": gorm.Expr("EXCLUDED.last_seen_at"),
+		}),
+	}).Create(check).Error
+	if err != nil {
+		return fmt.Errorf("failed to record quiz geo check: %w", err)
+	}
+	return nil
+}
+
+// UpdateLoginCountry записывает страну последнего входа, не меняя updated_at
+func (r *GeoRepo) UpdateLoginCountry(userID uint, country string) error {
+	err := r.db.Model(&entity.User{}).Where("id = ?", userID).UpdateColumn("last_login_country", country).Error
+	if err != nil {
+		return fmt.Errorf("failed to update login country: %w", err)
+	}
+	return nil
+}
+
+// ListBlocked возвращает подключения из стран вне списка разрешённых
+func (r *GeoRepo) ListBlocked(quizID uint, allowUnknown bool, limit, offset int) ([]repository.GeoBlockedAttempt, int64, error) {
+	query := r.db.Table("quiz_geo_checks AS g").
+		Joins("JOIN quizzes q ON q.id = g.quiz_id").
+		Joins("LEFT JOIN users u ON u.id = g.user_id").
+		Where("jsonb_array_length(q.allowed_countries) > 0 AND NOT q.allowed_countries @> to_jsonb(g.country::text)")
+	if allowUnknown {
+		query = query.Where("g.country <> ''")
+	}
+	if quizID != 0 {
+		query = query.Where("g.quiz_id = ?", quizID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count blocked geo attempts: %w", err)
+	}
+	var attempts []repository.GeoBlockedAttempt
+	err := query.
+		Select("g.quiz_id, q.title AS quiz_title, g.user_id, COALESCE(u.username, '') AS username, " +
+			"g.country, g.ip_address, g.attempts, g.last_seen_at").
+		Order("g.last_seen_at DESC, g.quiz_id DESC, g.user_id DESC").
+		Limit(limit).Offset(offset).
+		Scan(&attempts).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list blocked geo attempts: %w", err)
+	}
+	return attempts, total, nil
+}
+
+// CountByCountry возвращает число игроков викторины по странам, больше всего — первыми
+func (r *GeoRepo) CountByCountry(quizID uint) ([]repository.GeoCountryCount, error) {
+	var counts []repository.GeoCountryCount
+	err := r.db.Model(&entity.QuizGeoCheck{}).
+		Select("country, COUNT(DISTINCT user_id) AS players").
+		Where("quiz_id = ?", quizID).
+		Group("country").
+		Order("players DESC, country ASC").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count quiz players by country: %w", err)
+	}
+	return counts, nil
+}
@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IPRiskRepo реализует repository.IPRiskRepository
+type IPRiskRepo struct {
+	db *gorm.DB
+}
+
+// NewIPRiskRepo создает новый экземпляр
+func NewIPRiskRepo(db *gorm.DB) *IPRiskRepo {
+	return &IPRiskRepo{db: db}
+}
+
+// RecordSession сохраняет оценку адреса подключения к викторине
+func (r *IPRiskRepo) RecordSession(session *entity.QuizSessionRisk) error {
+	err := r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "quiz_id"}, {Name: "user_id"}, {Name: "ip_address"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"attempts":     gorm.Expr("quiz_session_risks.attempts + 1"),
+			"score":        gorm.Expr("EXCLUDED.score"),
+			"reasons":      gorm.Expr("EXCLUDED.reasons"),
+			"last_seen_at": gorm.Expr("EXCLUDED.last_seen_at"),
+		}),
+	}).Create(session).Error
+	if err != nil {
+		return fmt.Errorf("failed to record quiz session risk: %w", err)
+	}
+	return nil
+}
+
+// ListSessions возвращает подключения с оценкой не ниже minScore
+func (r *IPRiskRepo) ListSessions(quizID uint, minScore, limit, offset int) ([]repository.RiskySession, int64, error) {
+	query := r.db.Table("quiz_session_risks AS s").
+		Joins("JOIN quizzes q ON q.id = s.quiz_id").
+		Joins("LEFT JOIN users u ON u.id = s.user_id").
+		Where("s.score >= ?", minScore)
+	if quizID != 0 {
+		query = query.Where("s.quiz_id = ?", quizID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count risky sessions: %w", err)
+	}
+	var sessions []repository.RiskySession
+	err := query.
+		Select("s.quiz_id, q.title AS quiz_title, s.user_id, COALESCE(u.username, '') AS username, " +
+			"s.ip_address, s.score, s.reasons, s.attempts, s.last_seen_at").
+		Order("s.last_seen_at DESC, s.quiz_id DESC, s.user_id DESC").
+		Limit(limit).Offset(offset).
+		Scan(&sessions).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list risky sessions: %w", err)
+	}
+	return sessions, total, nil
+}
+
+// ListReviews возвращает проверки победителей, старые первыми
+func (r *IPRiskRepo) ListReviews(status string, quizID uint, limit, offset int) ([]entity.PrizeRiskReview, int64, error) {
+	query := r.db.Model(&entity.PrizeRiskReview{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if quizID != 0 {
+		query = query.Where("quiz_id = ?", quizID)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count prize risk reviews: %w", err)
+	}
+	var reviews []entity.PrizeRiskReview
+	if err := query.Order("id ASC").Limit(limit).Offset(offset).Find(&reviews).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list prize risk reviews: %w", err)
+	}
+	return reviews, total, nil
+}
+
+// GetReview возвращает проверку победителя
+func (r *IPRiskRepo) GetReview(id uint) (*entity.PrizeRiskReview, error) {
+	var review entity.PrizeRiskReview
+	if err := r.db.First(&review, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get prize risk review: %w", err)
+	}
+	return &review, nil
+}
+
+// ResolveReview записывает решение, только если проверка ещё ожидает его (два администратора одновременно)
+func (r *IPRiskRepo) ResolveReview(review *entity.PrizeRiskReview) error {
+	return resolvePrizeRiskReview(r.db, review)
+}
+
+// ForfeitPrize записывает отказ и снимает с победителя задержанный приз
+func (r *IPRiskRepo) ForfeitPrize(review *entity.PrizeRiskReview) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := resolvePrizeRiskReview(tx, review); err != nil {
+			return err
+		}
+		err := tx.Model(&entity.Result{}).
+			Where("quiz_id = ? AND user_id = ? AND is_winner = true", review.QuizID, review.UserID).
+			Updates(map[string]interface{}{"is_winner": false, "prize_fund": 0}).Error
+		if err != nil {
+			return fmt.Errorf("failed to revoke winner result: %w", err)
+		}
+		err = tx.Model(&entity.User{}).Where("id = ?", review.UserID).Updates(map[string]interface{}{
+			"wins_count":      gorm.Expr("GREATEST(wins_count - 1, 0)"),
+			"total_prize_won": gorm.Expr("GREATEST(total_prize_won - ?, 0)", review.Amount),
+		}).Error
+		if err != nil {
+			return fmt.Errorf("failed to revoke winner stats: %w", err)
+		}
+		return nil
+	})
+}
+
+func resolvePrizeRiskReview(db *gorm.DB, review *entity.PrizeRiskReview) error {
+	result := db.Model(&entity.PrizeRiskReview{}).
+		Where("id = ? AND status = ?", review.ID, entity.PrizeRiskReviewPending).
+		Updates(map[string]interface{}{
+			"status":      review.Status,
+			"reviewed_by": review.ReviewedBy,
+			"reviewed_at": review.ReviewedAt,
+			"note":        review.Note,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to resolve prize risk review: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: prize risk review is already resolved", apperrors.ErrConflict)
+	}
+	return nil
+}
@@ -4,65 +4,66 @@ package postgres
 
 import (
 	"context"
-	"crypto/aes"
-	"crypto/cipher"
-	"crypto/rand"
-	"encoding/hex"
 	"errors"
 	"fmt"
-	"io"
 	"log"
 	"time"
 
 	"gorm.io/gorm"
 
 	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/pkg/envelope"
 	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors" // Используем ваш пакет ошибок
 )
 
+// defaultJWTKeyValidationWindow — сколько ротированный ключ ещё принимается при проверке подписи
+const defaultJWTKeyValidationWindow = 7 * 24 * time.Hour
+
 // PostgresJWTKeyRepository реализует JWTKeyRepository для PostgreSQL.
 type PostgresJWTKeyRepository struct {
-	db            *gorm.DB
-	encryptionKey []byte // Ключ для шифрования/дешифрования JWT ключей в БД
+	db               *gorm.DB
+	keyring          *envelope.Keyring // Мастер-ключи для шифрования/дешифрования JWT ключей в БД
+	validationWindow time.Duration     // сколько ротированный ключ остаётся в GetValidationKeys
 }
 
 // NewPostgresJWTKeyRepository создает новый экземпляр PostgresJWTKeyRepository.
-// encryptionKeyHex должен быть строкой HEX представления ключа шифрования (32 байта для AES-256 -> 64 hex символа).
-func NewPostgresJWTKeyRepository(db *gorm.DB, encryptionKeyHex string) (*PostgresJWTKeyRepository, error) {
+// Секреты ключей шифруются конвертным шифрованием под текущим мастер-ключом keyring.
+func NewPostgresJWTKeyRepository(db *gorm.DB, keyring *envelope.Keyring) (*PostgresJWTKeyRepository, error) {
 	if db == nil {
 		return nil, errors.New("gorm DB instance is required")
 	}
-	if encryptionKeyHex == "" {
-		return nil, errors.New("encryption key is required for JWTKeyRepository")
-	}
-
-	keyBytes, err := hex.DecodeString(encryptionKeyHex)
-	if err != nil {
-		return nil, fmt.Errorf("failed to decode encryption key from hex: %w", err)
-	}
-
-	// Проверяем длину ключа (16 для AES-128, 24 для AES-192, 32 для AES-256)
-	if len(keyBytes) != 32 { // Рекомендуем AES-256
-		return nil, fmt.Errorf("invalid encryption key length: must be 32 bytes (for AES-256), got %d bytes", len(keyBytes))
+	if keyring == nil {
+		return nil, errors.New("encryption keyring is required for JWTKeyRepository")
 	}
 
 	return &PostgresJWTKeyRepository{
-		db:            db,
-		encryptionKey: keyBytes,
+		db:               db,
+		keyring:          keyring,
+		validationWindow: defaultJWTKeyValidationWindow,
 	}, nil
 }
 
+// SetValidationWindow задаёт, сколько ротированный ключ ещё принимается при проверке подписи
+// (должно быть не меньше времени жизни access-токена и WS-тикета)
+func (r *PostgresJWTKeyRepository) SetValidationWindow(window time.Duration) {
+	if window > 0 {
+		r.validationWindow = window
+	}
+}
+
 // --- Реализация методов интерфейса JWTKeyRepository ---
 
 // CreateKey создает новый ключ подписи JWT в хранилище.
 func (r *PostgresJWTKeyRepository) CreateKey(ctx context.Context, key *entity.JWTKey) error {
 	// Шифруем секрет ключа перед сохранением
-	encryptedSecret, err := r.encryptKey(key.Key)
+	sealed, err := r.keyring.Seal([]byte(key.Key))
 	if err != nil {
 		return fmt.Errorf("failed to encrypt JWT key secret: %w", err)
 	}
 	keyToSave := *key // Копируем, чтобы не изменять оригинальный объект
-	keyToSave.Key = encryptedSecret
+	keyToSave.Key = sealed.Ciphertext
+	keyToSave.WrappedDataKey = sealed.WrappedKey
+	keyToSave.EncryptionKeyVersion = sealed.KeyVersion
 
 	return r.db.WithContext(ctx).Create(&keyToSave).Error
 }
@@ -78,7 +79,7 @@ func (r *PostgresJWTKeyRepository) GetKeyByID(ctx context.Context, id string) (*
 		return nil, err
 	}
 
-	decryptedSecret, err := r.decryptKey(encryptedKey.Key)
+	decryptedSecret, err := r.decryptKey(&encryptedKey)
 	if err != nil {
 		// Критическая ошибка, если не можем расшифровать ключ из БД
 		log.Printf("CRITICAL: Failed to decrypt JWT key ID %s from DB: %v", encryptedKey.ID, err)
@@ -105,7 +106,7 @@ func (r *PostgresJWTKeyRepository) GetActiveKey(ctx context.Context) (*entity.JW
 		return nil, fmt.Errorf("failed to get active JWT key: %w", err)
 	}
 
-	decryptedSecret, err := r.decryptKey(encryptedKey.Key)
+	decryptedSecret, err := r.decryptKey(&encryptedKey)
 	if err != nil {
 		log.Printf("CRITICAL: Failed to decrypt active JWT key ID %s from DB: %v", encryptedKey.ID, err)
 		return nil, fmt.Errorf("failed to decrypt active JWT key secret for ID %s: %w", encryptedKey.ID, err)
@@ -117,10 +118,9 @@ func (r *PostgresJWTKeyRepository) GetActiveKey(ctx context.Context) (*entity.JW
 // GetValidationKeys извлекает все ключи, которые могут быть использованы для проверки подписи.
 func (r *PostgresJWTKeyRepository) GetValidationKeys(ctx context.Context) ([]*entity.JWTKey, error) {
 	var encryptedKeys []*entity.JWTKey
-	// Выбираем активные ключи ИЛИ неактивные, но у которых RotatedAt не слишком давно
-	// (например, в течение последних N дней, чтобы старые токены еще могли быть проверены)
-	// И ExpiresAt еще не наступило.
-	validationWindow := time.Now().Add(-7 * 24 * time.Hour) // Пример: 7 дней для проверки старых токенов
+	// Выбираем активные ключи ИЛИ неактивные, но ротированные не раньше validationWindow назад,
+	// чтобы выданные ими токены ещё могли быть проверены. И ExpiresAt еще не наступило.
+	validationWindow := time.Now().Add(-r.validationWindow)
 
 	err := r.db.WithContext(ctx).
 		Where("(is_active = ? AND expires_at > ?) OR (is_active = ? AND rotated_at > ? AND expires_at > ?)",
@@ -140,7 +140,7 @@ func (r *PostgresJWTKeyRepository) GetValidationKeys(ctx context.Context) ([]*en
 
 	decryptedKeys := make([]*entity.JWTKey, 0, len(encryptedKeys))
 	for _, ek := range encryptedKeys {
-		decryptedSecret, decErr := r.decryptKey(ek.Key)
+		decryptedSecret, decErr := r.decryptKey(ek)
 		if decErr != nil {
 			log.Printf("CRITICAL: Failed to decrypt JWT validation key ID %s from DB: %v. Skipping key.", ek.ID, decErr)
 			continue // Пропускаем ключ, если не можем расшифровать
@@ -152,17 +152,18 @@ func (r *PostgresJWTKeyRepository) GetValidationKeys(ctx context.Context) ([]*en
 	return decryptedKeys, nil
 }
 
-// DeactivateKey помечает ключ как неактивный.
+// DeactivateKey помечает ключ как неактивный. Уже деактивированный ключ не меняется
+// (ErrNotFound), поэтому одновременная ротация на двух инстансах создаёт только один новый ключ.
 func (r *PostgresJWTKeyRepository) DeactivateKey(ctx context.Context, id string, rotatedAtTime time.Time) error {
 	result := r.db.WithContext(ctx).Model(&entity.JWTKey{}).
-		Where("id = ?", id).
+		Where("id = ? AND is_active = ?", id, true).
 		Updates(map[string]interface{}{"is_active": false, "rotated_at": rotatedAtTime})
 
 	if result.Error != nil {
 		return fmt.Errorf("failed to deactivate JWT key ID %s: %w", id, result.Error)
 	}
 	if result.RowsAffected == 0 {
-		return fmt.Errorf("JWT key ID %s not found for deactivation: %w", id, apperrors.ErrNotFound)
+		return fmt.Errorf("active JWT key ID %s not found for deactivation: %w", id, apperrors.ErrNotFound)
 	}
 	return nil
 }
@@ -177,7 +178,7 @@ func (r *PostgresJWTKeyRepository) ListAllKeys(ctx context.Context) ([]*entity.J
 
 	decryptedKeys := make([]*entity.JWTKey, 0, len(encryptedKeys))
 	for _, ek := range encryptedKeys {
-		decryptedSecret, decErr := r.decryptKey(ek.Key)
+		decryptedSecret, decErr := r.decryptKey(ek)
 		if decErr != nil {
 			log.Printf("CRITICAL: Failed to decrypt JWT key ID %s from DB during ListAllKeys: %v. Skipping key.", ek.ID, decErr)
 			continue
@@ -206,59 +207,69 @@ func (r *PostgresJWTKeyRepository) PruneExpiredKeys(ctx context.Context, gracePe
 	return result.RowsAffected, nil
 }
 
-// --- Вспомогательные функции шифрования/дешифрования ---
-
-// encryptKey шифрует секрет ключа JWT с использованием AES-GCM.
-// Возвращает hex-строку зашифрованных данных (nonce + ciphertext).
-func (r *PostgresJWTKeyRepository) encryptKey(plaintextKey string) (string, error) {
-	block, err := aes.NewCipher(r.encryptionKey)
-	if err != nil {
-		return "", fmt.Errorf("failed to create AES cipher: %w", err)
-	}
-
-	gcm, err := cipher.NewGCM(block)
-	if err != nil {
-		return "", fmt.Errorf("failed to create GCM: %w", err)
-	}
-
-	nonce := make([]byte, gcm.NonceSize())
-	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
-		return "", fmt.Errorf("failed to generate nonce: %w", err)
-	}
-
-	ciphertext := gcm.Seal(nil, nonce, []byte(plaintextKey), nil)
-	// Сохраняем nonce вместе с ciphertext: nonce || ciphertext
-	encryptedData := append(nonce, ciphertext...)
-	return hex.EncodeToString(encryptedData), nil
+// JWTKeyReencryptResult — итог перешифрования ключей под текущий мастер-ключ
+type JWTKeyReencryptResult struct {
+	Total       int // всего ключей в таблице
+	Reencrypted int // перешифровано (или было бы перешифровано при dryRun)
+	Skipped     int // ключ изменился или удалён во время перешифрования
 }
 
-// decryptKey дешифрует секрет ключа JWT из hex-строки.
-func (r *PostgresJWTKeyRepository) decryptKey(encryptedKeyHex string) (string, error) {
-	encryptedData, err := hex.DecodeString(encryptedKeyHex)
-	if err != nil {
-		return "", fmt.Errorf("failed to decode encrypted key from hex: %w", err)
+// ReencryptKeys перешифровывает ключи данных всех JWT-ключей текущим мастер-ключом; ключи,
+// зашифрованные мастер-ключом напрямую, переводятся на конвертное шифрование. Строка обновляется
+// условно (по прежней версии и ключу данных), поэтому команду можно запускать при работающих
+// инстансах и повторно. После перешифрования прежний мастер-ключ можно убрать из конфигурации.
+func (r *PostgresJWTKeyRepository) ReencryptKeys(ctx context.Context, dryRun bool) (JWTKeyReencryptResult, error) {
+	var result JWTKeyReencryptResult
+	var keys []*entity.JWTKey
+	if err := r.db.WithContext(ctx).Order("created_at").Find(&keys).Error; err != nil {
+		return result, fmt.Errorf("failed to list JWT keys for re-encryption: %w", err)
 	}
+	result.Total = len(keys)
 
-	block, err := aes.NewCipher(r.encryptionKey)
-	if err != nil {
-		return "", fmt.Errorf("failed to create AES cipher for decryption: %w", err)
+	for _, key := range keys {
+		sealed := sealedJWTKey(key)
+		if !r.keyring.NeedsRewrap(sealed) {
+			continue
+		}
+		rewrapped, err := r.keyring.Rewrap(sealed)
+		if err != nil {
+			return result, fmt.Errorf("failed to re-encrypt JWT key ID %s: %w", key.ID, err)
+		}
+		if dryRun {
+			result.Reencrypted++
+			continue
+		}
+		update := r.db.WithContext(ctx).Model(&entity.JWTKey{}).
+			Where("id = ? AND encryption_key_version = ? AND wrapped_data_key = ?", key.ID, key.EncryptionKeyVersion, key.WrappedDataKey).
+			Updates(map[string]interface{}{
+				"key":                    rewrapped.Ciphertext,
+				"wrapped_data_key":       rewrapped.WrappedKey,
+				"encryption_key_version": rewrapped.KeyVersion,
+			})
+		if update.Error != nil {
+			return result, fmt.Errorf("failed to save re-encrypted JWT key ID %s: %w", key.ID, update.Error)
+		}
+		if update.RowsAffected == 0 {
+			result.Skipped++
+			continue
+		}
+		result.Reencrypted++
 	}
+	return result, nil
+}
 
-	gcm, err := cipher.NewGCM(block)
-	if err != nil {
-		return "", fmt.Errorf("failed to create GCM for decryption: %w", err)
-	}
+// --- Вспомогательные функции шифрования/дешифрования ---
 
-	nonceSize := gcm.NonceSize()
-	if len(encryptedData) < nonceSize {
-		return "", errors.New("encrypted data is too short to contain nonce")
-	}
+// sealedJWTKey возвращает зашифрованный секрет ключа в том виде, в каком он хранится в колонках
+func sealedJWTKey(key *entity.JWTKey) envelope.Sealed {
+	return envelope.Sealed{Ciphertext: key.Key, WrappedKey: key.WrappedDataKey, KeyVersion: key.EncryptionKeyVersion}
+}
 
-	nonce, ciphertext := encryptedData[:nonceSize], encryptedData[nonceSize:]
-	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+// decryptKey дешифрует секрет ключа JWT, прочитанного из БД.
+func (r *PostgresJWTKeyRepository) decryptKey(key *entity.JWTKey) (string, error) {
+	plaintext, err := r.keyring.Open(sealedJWTKey(key))
 	if err != nil {
-		return "", fmt.Errorf("failed to decrypt key with GCM: %w", err)
+		return "", err
 	}
-
 	return string(plaintext), nil
 }
@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// MagicLinkRepo реализует repository.MagicLinkRepository
+type MagicLinkRepo struct {
+	db *gorm.DB
+}
+
+// NewMagicLinkRepo создает репозиторий ссылок для входа
+func NewMagicLinkRepo(db *gorm.DB) *MagicLinkRepo {
+	return &MagicLinkRepo{db: db}
+}
+
+func (r *MagicLinkRepo) Create(link *entity.MagicLink) error {
+	return r.db.Create(link).Error
+}
+
+func (r *MagicLinkRepo) GetByTokenHash(tokenHash string) (*entity.MagicLink, error) {
+	var link entity.MagicLink
+	if err := r.db.Where("token_hash = ?", tokenHash).First(&link).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get magic link: %w", err)
+	}
+	return &link, nil
+}
+
+func (r *MagicLinkRepo) GetLatestByUserID(userID uint) (*entity.MagicLink, error) {
+	var link entity.MagicLink
+	if err := r.db.Where("user_id = ?", userID).Order("created_at DESC").First(&link).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get latest magic link: %w", err)
+	}
+	return &link, nil
+}
+
+func (r *MagicLinkRepo) CountSince(userID uint, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.MagicLink{}).
+		Where("user_id = ? AND created_at > ?", userID, since).
+		Count(&count).Error
+	return count, err
+}
+
+// Consume использует ссылку условным UPDATE, чтобы два одновременных перехода не выдали две сессии
+func (r *MagicLinkRepo) Consume(id uint, at time.Time) (bool, error) {
+	result := r.db.Model(&entity.MagicLink{}).
+		Where("id = ? AND consumed_at IS NULL", id).
+		Update("consumed_at", at)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *MagicLinkRepo) DeleteByUserID(userID uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&entity.MagicLink{}).Error
+}
@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MobileClientUsageRepo реализует repository.MobileClientUsageRepository
+type MobileClientUsageRepo struct {
+	db *gorm.DB
+}
+
+// NewMobileClientUsageRepo создает новый экземпляр
+func NewMobileClientUsageRepo(db *gorm.DB) *MobileClientUsageRepo {
+	return &MobileClientUsageRepo{db: db}
+}
+
+// AddUsage прибавляет счётчики к суточной статистике. Инстансы API сбрасывают свои счётчики
+// независимо, поэтому значения складываются в ON CONFLICT, а не перезаписываются.
+func (r *MobileClientUsageRepo) AddUsage(usage []entity.MobileClientUsage) error {
+	if len(usage) == 0 {
+		return nil
+	}
+	err := r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "day"}, {Name: "api_version"}, {Name: "platform"}, {Name: "app_version"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"requests": gorm.Expr("mobile_client_usage.requests + EXCLUDED.requests"),
+		}),
+	}).Create(&usage).Error
+	if err != nil {
+		return fmt.Errorf("failed to add mobile client usage: %w", err)
+	}
+	return nil
+}
+
+// ListUsage возвращает статистику начиная с since
+func (r *MobileClientUsageRepo) ListUsage(since time.Time) ([]entity.MobileClientUsage, error) {
+	var usage []entity.MobileClientUsage
+	err := r.db.Where("day >= ?", since).
+		Order("day ASC, api_version ASC, platform ASC, app_version ASC").
+		Find(&usage).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list mobile client usage: %w", err)
+	}
+	return usage, nil
+}
@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// ModerationRepo реализует repository.ModerationRepository
+type ModerationRepo struct {
+	db *gorm.DB
+}
+
+// NewModerationRepo создает новый экземпляр
+func NewModerationRepo(db *gorm.DB) *ModerationRepo {
+	return &ModerationRepo{db: db}
+}
+
+// Create сохраняет случай модерации, закрывая прежний ожидающий случай того же поля
+func (r *ModerationRepo) Create(moderationCase *entity.ModerationCase) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.ModerationCase{}).
+			Where("user_id = ? AND field = ? AND status = ?", moderationCase.UserID, moderationCase.Field, entity.ModerationCasePending).
+			Update("status", entity.ModerationCaseSuperseded).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(moderationCase).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create moderation case: %w", err)
+	}
+	return nil
+}
+
+// GetByID возвращает случай модерации
+func (r *ModerationRepo) GetByID(id uint) (*entity.ModerationCase, error) {
+	var moderationCase entity.ModerationCase
+	if err := r.db.First(&moderationCase, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get moderation case: %w", err)
+	}
+	return &moderationCase, nil
+}
+
+// List возвращает случаи модерации, старые первыми
+func (r *ModerationRepo) List(status, field string, limit, offset int) ([]entity.ModerationCase, int64, error) {
+	query := r.db.Model(&entity.ModerationCase{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if field != "" {
+		query = query.Where("field = ?", field)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count moderation cases: %w", err)
+	}
+	var cases []entity.ModerationCase
+	if err := query.Order("id ASC").Limit(limit).Offset(offset).Find(&cases).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list moderation cases: %w", err)
+	}
+	return cases, total, nil
+}
+
+// Resolve записывает решение, только если случай ещё ожидает его (два модератора одновременно)
+func (r *ModerationRepo) Resolve(moderationCase *entity.ModerationCase) error {
+	result := r.db.Model(&entity.ModerationCase{}).
+		Where("id = ? AND status = ?", moderationCase.ID, entity.ModerationCasePending).
+		Updates(map[string]interface{}{
+			"status":      moderationCase.Status,
+			"reviewed_by": moderationCase.ReviewedBy,
+			"reviewed_at": moderationCase.ReviewedAt,
+			"note":        moderationCase.Note,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to resolve moderation case: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: moderation case is already resolved", apperrors.ErrConflict)
+	}
+	return nil
+}
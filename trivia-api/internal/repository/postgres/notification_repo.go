@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"gorm.io/gorm"
+)
+
+// notificationBatchSize ограничивает размер одной вставки при массовой рассылке
+const notificationBatchSize = 500
+
+// NotificationRepo реализует repository.NotificationRepository
+type NotificationRepo struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepo создает новый экземпляр
+func NewNotificationRepo(db *gorm.DB) *NotificationRepo {
+	return &NotificationRepo{db: db}
+}
+
+// CreateBatch сохраняет несколько уведомлений одной вставкой
+func (r *NotificationRepo) CreateBatch(notifications []*entity.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	if err := r.db.CreateInBatches(notifications, notificationBatchSize).Error; err != nil {
+		return fmt.Errorf("failed to create notifications: %w", err)
+	}
+	return nil
+}
+
+// ListByUserID возвращает уведомления пользователя (новые первыми) и общее количество
+func (r *NotificationRepo) ListByUserID(userID uint, unreadOnly bool, limit, offset int) ([]entity.Notification, int64, error) {
+	query := r.db.Model(&entity.Notification{}).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count notifications: %w", err)
+	}
+
+	var notifications []entity.Notification
+	if err := query.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&notifications).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list notifications: %w", err)
+	}
+	return notifications, total, nil
+}
+
+// CountUnread возвращает количество непрочитанных уведомлений пользователя
+func (r *NotificationRepo) CountUnread(userID uint) (int64, error) {
+	var count int64
+	if err := r.db.Model(&entity.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+	return count, nil
+}
+
+// MarkRead отмечает уведомления пользователя прочитанными и возвращает число изменённых записей
+func (r *NotificationRepo) MarkRead(userID uint, ids []uint) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	result := r.db.Model(&entity.Notification{}).
+		Where("user_id = ? AND id IN ? AND read_at IS NULL", userID, ids).
+		Update("read_at", time.Now())
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to mark notifications as read: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// MarkAllRead отмечает все уведомления пользователя прочитанными
+func (r *NotificationRepo) MarkAllRead(userID uint) (int64, error) {
+	result := r.db.Model(&entity.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now())
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to mark all notifications as read: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
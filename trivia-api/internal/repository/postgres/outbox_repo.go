@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"gorm.io/gorm"
+)
+
+// OutboxRepo реализует repository.OutboxRepository
+type OutboxRepo struct {
+	db *gorm.DB
+}
+
+// NewOutboxRepo создает новый экземпляр
+func NewOutboxRepo(db *gorm.DB) *OutboxRepo {
+	return &OutboxRepo{db: db}
+}
+
+// Append записывает события в транзакции tx
+func (r *OutboxRepo) Append(tx *gorm.DB, events ...entity.OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	if tx == nil {
+		tx = r.db
+	}
+	if err := tx.Create(&events).Error; err != nil {
+		return fmt.Errorf("failed to append outbox events: %w", err)
+	}
+	return nil
+}
+
+// ClaimDue забирает события, срок обработки которых наступил (как ClaimDueDeliveries вебхуков)
+func (r *OutboxRepo) ClaimDue(now time.Time, lease time.Duration, limit int) ([]entity.OutboxEvent, error) {
+	var events []entity.OutboxEvent
+	err := r.db.Raw(`
+		UPDATE outbox_events SET next_attempt_at = @lease_until
+		WHERE id IN (
+			SELECT id FROM outbox_events
+			WHERE status = @pending AND next_attempt_at <= @now
+			ORDER BY id
+			LIMIT @limit
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		map[string]interface{}{
+			"now":         now,
+			"lease_until": now.Add(lease),
+			"pending":     entity.OutboxStatusPending,
+			"limit":       limit,
+		}).Scan(&events).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox events: %w", err)
+	}
+	return events, nil
+}
+
+// Update сохраняет результат обработки события
+func (r *OutboxRepo) Update(event *entity.OutboxEvent) error {
+	if err := r.db.Save(event).Error; err != nil {
+		return fmt.Errorf("failed to update outbox event: %w", err)
+	}
+	return nil
+}
+
+// DeleteProcessedBefore удаляет обработанные события старше before
+func (r *OutboxRepo) DeleteProcessedBefore(before time.Time) (int64, error) {
+	result := r.db.Where("status = ? AND processed_at < ?", entity.OutboxStatusProcessed, before).Delete(&entity.OutboxEvent{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete processed outbox events: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
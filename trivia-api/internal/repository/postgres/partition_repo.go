@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+)
+
+// archiveSchema — схема, в которую переносятся отсоединённые секции
+const archiveSchema = "archive"
+
+// partitionBoundRe разбирает границы RANGE-секции: FOR VALUES FROM ('100') TO ('200')
+var partitionBoundRe = regexp.MustCompile(`FROM \('?(\d+)'?\) TO \('?(\d+)'?\)`)
+
+// PartitionRepo реализует repository.PartitionRepository
+type PartitionRepo struct {
+	db *gorm.DB
+}
+
+// NewPartitionRepo создает новый репозиторий секций
+func NewPartitionRepo(db *gorm.DB) *PartitionRepo {
+	return &PartitionRepo{db: db}
+}
+
+// ListQuizPartitions возвращает секции user_answers и results текущей схемы, упорядоченные по границе
+func (r *PartitionRepo) ListQuizPartitions() ([]repository.QuizPartition, error) {
+	var rows []struct {
+		ParentTable string
+		Name        string
+		Bound       string
+	}
+	err := r.db.Raw(`
+		SELECT parent.relname AS parent_table, child.relname AS name,
+		       pg_get_expr(child.relpartbound, child.oid) AS bound
+		FROM pg_inherits i
+		JOIN pg_class parent ON parent.oid = i.inhparent
+		JOIN pg_class child ON child.oid = i.inhrelid
+		JOIN pg_namespace ns ON ns.oid = parent.relnamespace
+		WHERE ns.nspname = current_schema() AND parent.relname IN ('user_answers', 'results')
+		ORDER BY child.relname`).Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list partitions: %w", err)
+	}
+
+	partitions := make([]repository.QuizPartition, 0, len(rows))
+	for _, row := range rows {
+		from, to, err := parsePartitionBound(row.Bound)
+		if err != nil {
+			return nil, fmt.Errorf("partition %s: %w", row.Name, err)
+		}
+		partitions = append(partitions, repository.QuizPartition{Table: row.ParentTable, Name: row.Name, From: from, To: to})
+	}
+	return partitions, nil
+}
+
+// QuizRangeSettledBefore проверяет, что диапазон викторин закрыт (есть викторины с id >= to)
+// и в нём нет незавершённых или запланированных позже before викторин
+func (r *PartitionRepo) QuizRangeSettledBefore(from, to uint, before time.Time) (bool, error) {
+	var settled bool
+	err := r.db.Raw(`
+		SELECT EXISTS (SELECT 1 FROM quizzes WHERE id >= @to)
+		   AND NOT EXISTS (
+		       SELECT 1 FROM quizzes
+		       WHERE id >= @from AND id < @to
+		         AND (status NOT IN @finished OR scheduled_time >= @before))`,
+		map[string]interface{}{
+			"from":     from,
+			"to":       to,
+			"before":   before,
+			"finished": []string{entity.QuizStatusCompleted, entity.QuizStatusCancelled},
+		}).Scan(&settled).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check quiz range %d-%d: %w", from, to, err)
+	}
+	return settled, nil
+}
+
+// ArchivePartition отсоединяет секцию и переносит её в схему archive одной транзакцией
+func (r *PartitionRepo) ArchivePartition(partition repository.QuizPartition) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		detach := fmt.Sprintf("ALTER TABLE %s DETACH PARTITION %s",
+			pq.QuoteIdentifier(partition.Table), pq.QuoteIdentifier(partition.Name))
+		if err := tx.Exec(detach).Error; err != nil {
+			return fmt.Errorf("failed to detach partition %s: %w", partition.Name, err)
+		}
+		move := fmt.Sprintf("ALTER TABLE %s SET SCHEMA %s", pq.QuoteIdentifier(partition.Name), archiveSchema)
+		if err := tx.Exec(move).Error; err != nil {
+			return fmt.Errorf("failed to move partition %s to %s: %w", partition.Name, archiveSchema, err)
+		}
+		return nil
+	})
+}
+
+// parsePartitionBound возвращает границы RANGE-секции из pg_get_expr(relpartbound)
+func parsePartitionBound(bound string) (uint, uint, error) {
+	match := partitionBoundRe.FindStringSubmatch(bound)
+	if match == nil {
+		return 0, 0, fmt.Errorf("unexpected partition bound %q", bound)
+	}
+	from, err := strconv.ParseUint(match[1], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid partition bound %q: %w", bound, err)
+	}
+	to, err := strconv.ParseUint(match[2], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid partition bound %q: %w", bound, err)
+	}
+	return uint(from), uint(to), nil
+}
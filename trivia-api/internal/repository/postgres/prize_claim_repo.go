@@ -0,0 +1,212 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PrizeClaimRepo реализует repository.PrizeClaimRepository
+type PrizeClaimRepo struct {
+	db *gorm.DB
+}
+
+// NewPrizeClaimRepo создает новый экземпляр
+func NewPrizeClaimRepo(db *gorm.DB) *PrizeClaimRepo {
+	return &PrizeClaimRepo{db: db}
+}
+
+// Create сохраняет заявку, если у пользователя ещё нет заявки по этой викторине
+func (r *PrizeClaimRepo) Create(claim *entity.PrizeClaim) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "quiz_id"}, {Name: "user_id"}},
+		DoNothing: true,
+	}).Create(claim)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to create prize claim: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetByID возвращает заявку по ID
+func (r *PrizeClaimRepo) GetByID(id uint) (*entity.PrizeClaim, error) {
+	var claim entity.PrizeClaim
+	if err := r.db.First(&claim, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get prize claim: %w", err)
+	}
+	return &claim, nil
+}
+
+// GetByQuizAndUser возвращает заявку пользователя по викторине
+func (r *PrizeClaimRepo) GetByQuizAndUser(quizID, userID uint) (*entity.PrizeClaim, error) {
+	var claim entity.PrizeClaim
+	if err := r.db.Where("quiz_id = ? AND user_id = ?", quizID, userID).First(&claim).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get prize claim: %w", err)
+	}
+	return &claim, nil
+}
+
+// List возвращает заявки по фильтрам (новые первыми) и общее количество
+func (r *PrizeClaimRepo) List(filters repository.PrizeClaimFilters, limit, offset int) ([]entity.PrizeClaim, int64, error) {
+	query := r.db.Model(&entity.PrizeClaim{})
+	if filters.Status != "" {
+		query = query.Where("status = ?", filters.Status)
+	}
+	if filters.QuizID != nil {
+		query = query.Where("quiz_id = ?", *filters.QuizID)
+	}
+	if filters.UserID != nil {
+		query = query.Where("user_id = ?", *filters.UserID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count prize claims: %w", err)
+	}
+	var claims []entity.PrizeClaim
+	if err := query.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&claims).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list prize claims: %w", err)
+	}
+	return claims, total, nil
+}
+
+// Transition атомарно переводит заявку из fromStatus в claim.Status и перечитывает её
+func (r *PrizeClaimRepo) Transition(claim *entity.PrizeClaim, fromStatus string) error {
+	claim.UpdatedAt = time.Now()
+	result := r.db.Model(claim).
+		Clauses(clause.Returning{}).
+		Where("status = ?", fromStatus).
+		Updates(map[string]interface{}{
+			"status":             claim.Status,
+			"payout_method":      claim.PayoutMethod,
+			"payout_destination": claim.PayoutDestination,
+			"full_name":          claim.FullName,
+			"contact_email":      claim.ContactEmail,
+			"contact_phone":      claim.ContactPhone,
+			"reject_reason":      claim.RejectReason,
+			"reviewer_id":        claim.ReviewerID,
+			"submitted_at":       claim.SubmittedAt,
+			"verified_at":        claim.VerifiedAt,
+			"resolved_at":        claim.ResolvedAt,
+			"updated_at":         claim.UpdatedAt,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update prize claim: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: prize claim is no longer %s", apperrors.ErrConflict, fromStatus)
+	}
+	return nil
+}
+
+// ResolveOverdue закрывает просроченные заявки и перераспределяет или списывает их призы
+func (r *PrizeClaimRepo) ResolveOverdue(now time.Time, redistribute bool, limit int) ([]entity.PrizeClaim, []repository.PrizeClaimShare, error) {
+	var expired []entity.PrizeClaim
+	var shares []repository.PrizeClaimShare
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		expired, shares = nil, nil
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND claim_deadline < ?", entity.PrizeClaimPending, now).
+			Order("quiz_id ASC, id ASC").
+			Limit(limit).
+			Find(&expired).Error; err != nil {
+			return fmt.Errorf("failed to select overdue prize claims: %w", err)
+		}
+
+		beneficiariesByQuiz := make(map[uint][]entity.PrizeClaim)
+		for i := range expired {
+			claim := &expired[i]
+
+			var beneficiaries []entity.PrizeClaim
+			if redistribute {
+				var ok bool
+				if beneficiaries, ok = beneficiariesByQuiz[claim.QuizID]; !ok {
+					if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+						Where("quiz_id = ? AND status IN ?", claim.QuizID, []string{entity.PrizeClaimSubmitted, entity.PrizeClaimVerified}).
+						Order("id ASC").
+						Find(&beneficiaries).Error; err != nil {
+						return fmt.Errorf("failed to select prize claim beneficiaries: %w", err)
+					}
+					beneficiariesByQuiz[claim.QuizID] = beneficiaries
+				}
+			}
+
+			var share int64
+			if len(beneficiaries) > 0 {
+				share = claim.Amount / int64(len(beneficiaries))
+			}
+			claim.Status = entity.PrizeClaimForfeited
+			if share > 0 {
+				claim.Status = entity.PrizeClaimRedistributed
+			}
+			claim.ResolvedAt = &now
+			claim.UpdatedAt = now
+			if err := tx.Model(claim).Updates(map[string]interface{}{
+				"status":      claim.Status,
+				"resolved_at": claim.ResolvedAt,
+				"updated_at":  claim.UpdatedAt,
+			}).Error; err != nil {
+				return fmt.Errorf("failed to resolve prize claim %d: %w", claim.ID, err)
+			}
+			if err := adjustPrize(tx, claim.QuizID, claim.UserID, -claim.Amount); err != nil {
+				return err
+			}
+
+			if share == 0 {
+				continue
+			}
+			for j := range beneficiaries {
+				beneficiary := &beneficiaries[j]
+				if err := tx.Model(beneficiary).Updates(map[string]interface{}{
+					"amount":     gorm.Expr("amount + ?", share),
+					"updated_at": now,
+				}).Error; err != nil {
+					return fmt.Errorf("failed to add share to prize claim %d: %w", beneficiary.ID, err)
+				}
+				if err := adjustPrize(tx, beneficiary.QuizID, beneficiary.UserID, share); err != nil {
+					return err
+				}
+				shares = append(shares, repository.PrizeClaimShare{
+					ClaimID:     beneficiary.ID,
+					QuizID:      beneficiary.QuizID,
+					UserID:      beneficiary.UserID,
+					FromClaimID: claim.ID,
+					Amount:      share,
+					Verified:    beneficiary.Status == entity.PrizeClaimVerified,
+				})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return expired, shares, nil
+}
+
+// adjustPrize изменяет выигрыш пользователя в результатах викторины и в его статистике
+func adjustPrize(tx *gorm.DB, quizID, userID uint, delta int64) error {
+	if err := tx.Model(&entity.Result{}).
+		Where("quiz_id = ? AND user_id = ?", quizID, userID).
+		Update("prize_fund", gorm.Expr("GREATEST(prize_fund + ?, 0)", delta)).Error; err != nil {
+		return fmt.Errorf("failed to adjust result prize: %w", err)
+	}
+	if err := tx.Model(&entity.User{}).
+		Where("id = ?", userID).
+		Update("total_prize_won", gorm.Expr("GREATEST(total_prize_won + ?, 0)", delta)).Error; err != nil {
+		return fmt.Errorf("failed to adjust user prize total: %w", err)
+	}
+	return nil
+}
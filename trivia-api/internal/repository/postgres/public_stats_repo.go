@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// PublicStatsRepo реализует PublicStatsRepository
+type PublicStatsRepo struct {
+	db *gorm.DB
+}
+
+// NewPublicStatsRepo создает новый экземпляр
+func NewPublicStatsRepo(db *gorm.DB) *PublicStatsRepo {
+	return &PublicStatsRepo{db: db}
+}
+
+// CountPlayers возвращает число неудалённых пользователей
+func (r *PublicStatsRepo) CountPlayers() (int64, error) {
+	var count int64
+	if err := r.db.Model(&entity.User{}).Where("deleted_at IS NULL").Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count players: %w", err)
+	}
+	return count, nil
+}
+
+// NextScheduledQuiz возвращает ближайшую запланированную викторину
+func (r *PublicStatsRepo) NextScheduledQuiz() (*entity.Quiz, error) {
+	return r.firstQuiz("next scheduled", r.db.
+		Where("status = ? AND scheduled_time > ?", entity.QuizStatusScheduled, time.Now()).
+		Order("scheduled_time"))
+}
+
+// LastCompletedQuiz возвращает последнюю завершённую викторину
+func (r *PublicStatsRepo) LastCompletedQuiz() (*entity.Quiz, error) {
+	return r.firstQuiz("last completed", r.db.
+		Where("status = ?", entity.QuizStatusCompleted).
+		Order("scheduled_time DESC"))
+}
+
+func (r *PublicStatsRepo) firstQuiz(what string, query *gorm.DB) (*entity.Quiz, error) {
+	var quiz entity.Quiz
+	err := query.Select("id", "title", "scheduled_time", "status", "prize_fund").First(&quiz).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get %s quiz: %w", what, err)
+	}
+	return &quiz, nil
+}
+
+// OnlineConnections суммирует последний снимок каждого экземпляра, сделанный после since.
+// Экземпляры без свежих снимков (остановленные) не учитываются.
+func (r *PublicStatsRepo) OnlineConnections(since time.Time) (int64, error) {
+	var total int64
+	err := r.db.Raw(`
+		SELECT COALESCE(SUM(connections), 0) FROM (
+			SELECT DISTINCT ON (instance_id) connections
+			FROM ws_connection_samples WHERE sampled_at >= ?
+			ORDER BY instance_id, sampled_at DESC
+		) latest`, since).Scan(&total).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to get online connections: %w", err)
+	}
+	return total, nil
+}
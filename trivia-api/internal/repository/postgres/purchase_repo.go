@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PurchaseRepo реализует repository.PurchaseRepository
+type PurchaseRepo struct {
+	db *gorm.DB
+}
+
+// NewPurchaseRepo создает новый экземпляр
+func NewPurchaseRepo(db *gorm.DB) *PurchaseRepo {
+	return &PurchaseRepo{db: db}
+}
+
+// SavePurchase создает покупку или обновляет её состояние по (store, transaction_id).
+// Владелец покупки (user_id) при обновлении не меняется.
+func (r *PurchaseRepo) SavePurchase(purchase *entity.Purchase) error {
+	err := r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "store"}, {Name: "transaction_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"product_id", "environment", "status", "auto_renew", "purchased_at", "expires_at", "revoked_at", "updated_at",
+		}),
+	}).Create(purchase).Error
+	if err != nil {
+		return fmt.Errorf("failed to save purchase: %w", err)
+	}
+	return nil
+}
+
+// GetByTransaction находит покупку по магазину и идентификатору покупки
+func (r *PurchaseRepo) GetByTransaction(store, transactionID string) (*entity.Purchase, error) {
+	var purchase entity.Purchase
+	err := r.db.Where("store = ? AND transaction_id = ?", store, transactionID).First(&purchase).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get purchase: %w", err)
+	}
+	return &purchase, nil
+}
+
+// ListByUser возвращает покупки пользователя, новые первыми
+func (r *PurchaseRepo) ListByUser(userID uint) ([]entity.Purchase, error) {
+	var purchases []entity.Purchase
+	if err := r.db.Where("user_id = ?", userID).Order("purchased_at DESC, id DESC").Find(&purchases).Error; err != nil {
+		return nil, fmt.Errorf("failed to list purchases: %w", err)
+	}
+	return purchases, nil
+}
+
+// ReplaceEntitlements заменяет права пользователя в одной транзакции
+func (r *PurchaseRepo) ReplaceEntitlements(userID uint, entitlements []entity.UserEntitlement) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&entity.UserEntitlement{}).Error; err != nil {
+			return err
+		}
+		if len(entitlements) == 0 {
+			return nil
+		}
+		return tx.Create(&entitlements).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to replace entitlements of user %d: %w", userID, err)
+	}
+	return nil
+}
+
+// ListEntitlements возвращает права пользователя, включая истёкшие
+func (r *PurchaseRepo) ListEntitlements(userID uint) ([]entity.UserEntitlement, error) {
+	var entitlements []entity.UserEntitlement
+	if err := r.db.Where("user_id = ?", userID).Order("entitlement ASC").Find(&entitlements).Error; err != nil {
+		return nil, fmt.Errorf("failed to list entitlements: %w", err)
+	}
+	return entitlements, nil
+}
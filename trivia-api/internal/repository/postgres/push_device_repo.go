@@ -0,0 +1,133 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PushDeviceRepo реализует PushDeviceRepository
+type PushDeviceRepo struct {
+	db *gorm.DB
+}
+
+// NewPushDeviceRepo создает новый экземпляр
+func NewPushDeviceRepo(db *gorm.DB) *PushDeviceRepo {
+	return &PushDeviceRepo{db: db}
+}
+
+// Upsert сохраняет токен устройства. Токен уникален: при повторной регистрации
+// (например, после смены аккаунта на устройстве) запись переназначается новому пользователю.
+func (r *PushDeviceRepo) Upsert(device *entity.PushDevice) error {
+	err := r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "token"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"user_id":    device.UserID,
+			"platform":   device.Platform,
+			"provider":   device.Provider,
+			"device_id":  device.DeviceID,
+			"language":   device.Language,
+			"updated_at": gorm.Expr("NOW()"),
+		}),
+	}).Create(device).Error
+	if err != nil {
+		return fmt.Errorf("failed to upsert push device: %w", err)
+	}
+	return nil
+}
+
+// ListByUserID возвращает устройства пользователя
+func (r *PushDeviceRepo) ListByUserID(userID uint) ([]entity.PushDevice, error) {
+	var devices []entity.PushDevice
+	if err := r.db.Where("user_id = ?", userID).Order("updated_at DESC").Find(&devices).Error; err != nil {
+		return nil, fmt.Errorf("failed to list push devices: %w", err)
+	}
+	return devices, nil
+}
+
+// ListByUserIDs возвращает устройства нескольких пользователей
+func (r *PushDeviceRepo) ListByUserIDs(userIDs []uint) ([]entity.PushDevice, error) {
+	var devices []entity.PushDevice
+	if len(userIDs) == 0 {
+		return devices, nil
+	}
+	if err := r.db.Where("user_id IN ?", userIDs).Find(&devices).Error; err != nil {
+		return nil, fmt.Errorf("failed to list push devices: %w", err)
+	}
+	return devices, nil
+}
+
+// ListAfterID возвращает следующую страницу устройств по возрастанию ID
+func (r *PushDeviceRepo) ListAfterID(afterID uint, limit int) ([]entity.PushDevice, error) {
+	var devices []entity.PushDevice
+	if err := r.db.Where("id > ?", afterID).Order("id ASC").Limit(limit).Find(&devices).Error; err != nil {
+		return nil, fmt.Errorf("failed to list push devices: %w", err)
+	}
+	return devices, nil
+}
+
+// DeleteByUserAndToken удаляет токен пользователя
+func (r *PushDeviceRepo) DeleteByUserAndToken(userID uint, token string) error {
+	res := r.db.Where("user_id = ? AND token = ?", userID, token).Delete(&entity.PushDevice{})
+	if res.Error != nil {
+		return fmt.Errorf("failed to delete push device: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
+
+// DeleteByToken удаляет токен, отклонённый провайдером
+func (r *PushDeviceRepo) DeleteByToken(token string) error {
+	if err := r.db.Where("token = ?", token).Delete(&entity.PushDevice{}).Error; err != nil {
+		return fmt.Errorf("failed to delete push device: %w", err)
+	}
+	return nil
+}
+
+// NotificationPreferenceRepo реализует NotificationPreferenceRepository
+type NotificationPreferenceRepo struct {
+	db *gorm.DB
+}
+
+// NewNotificationPreferenceRepo создает новый экземпляр
+func NewNotificationPreferenceRepo(db *gorm.DB) *NotificationPreferenceRepo {
+	return &NotificationPreferenceRepo{db: db}
+}
+
+// GetByUserID возвращает настройки пользователя
+func (r *NotificationPreferenceRepo) GetByUserID(userID uint) (*entity.NotificationPreference, error) {
+	var pref entity.NotificationPreference
+	if err := r.db.Where("user_id = ?", userID).First(&pref).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get notification preferences: %w", err)
+	}
+	return &pref, nil
+}
+
+// GetByUserIDs возвращает сохранённые настройки для набора пользователей
+func (r *NotificationPreferenceRepo) GetByUserIDs(userIDs []uint) ([]entity.NotificationPreference, error) {
+	var prefs []entity.NotificationPreference
+	if len(userIDs) == 0 {
+		return prefs, nil
+	}
+	if err := r.db.Where("user_id IN ?", userIDs).Find(&prefs).Error; err != nil {
+		return nil, fmt.Errorf("failed to get notification preferences: %w", err)
+	}
+	return prefs, nil
+}
+
+// Upsert сохраняет настройки пользователя
+func (r *NotificationPreferenceRepo) Upsert(pref *entity.NotificationPreference) error {
+	if err := r.db.Save(pref).Error; err != nil {
+		return fmt.Errorf("failed to save notification preferences: %w", err)
+	}
+	return nil
+}
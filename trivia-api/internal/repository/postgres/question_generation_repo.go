@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// QuestionGenerationRepo реализует repository.QuestionGenerationRepository
+type QuestionGenerationRepo struct {
+	db *gorm.DB
+}
+
+// NewQuestionGenerationRepo создает новый экземпляр
+func NewQuestionGenerationRepo(db *gorm.DB) *QuestionGenerationRepo {
+	return &QuestionGenerationRepo{db: db}
+}
+
+// Create сохраняет новый запрос генерации
+func (r *QuestionGenerationRepo) Create(generation *entity.QuestionGeneration) error {
+	if err := r.db.Create(generation).Error; err != nil {
+		return fmt.Errorf("failed to create question generation: %w", err)
+	}
+	return nil
+}
+
+// Save сохраняет итоги генерации
+func (r *QuestionGenerationRepo) Save(generation *entity.QuestionGeneration) error {
+	if err := r.db.Save(generation).Error; err != nil {
+		return fmt.Errorf("failed to save question generation: %w", err)
+	}
+	return nil
+}
+
+// GetByID возвращает запрос генерации
+func (r *QuestionGenerationRepo) GetByID(id uint) (*entity.QuestionGeneration, error) {
+	var generation entity.QuestionGeneration
+	if err := r.db.First(&generation, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get question generation: %w", err)
+	}
+	return &generation, nil
+}
+
+// List возвращает запросы генерации, новые первыми
+func (r *QuestionGenerationRepo) List(limit, offset int) ([]entity.QuestionGeneration, int64, error) {
+	var total int64
+	if err := r.db.Model(&entity.QuestionGeneration{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count question generations: %w", err)
+	}
+	var generations []entity.QuestionGeneration
+	if err := r.db.Order("id DESC").Limit(limit).Offset(offset).Find(&generations).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list question generations: %w", err)
+	}
+	return generations, total, nil
+}
+
+// UsageSince суммирует запрошенные вопросы и стоимость генераций администратора с момента since
+func (r *QuestionGenerationRepo) UsageSince(adminID uint, since time.Time) (int, float64, error) {
+	var usage struct {
+		Questions int
+		CostUSD   float64
+	}
+	err := r.db.Model(&entity.QuestionGeneration{}).
+		Select("COALESCE(SUM(requested) FILTER (WHERE status <> ?), 0) AS questions, COALESCE(SUM(cost_usd), 0) AS cost_usd", entity.QuestionGenerationFailed).
+		Where("admin_id = ? AND created_at >= ?", adminID, since).
+		Scan(&usage).Error
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to sum question generation usage: %w", err)
+	}
+	return usage.Questions, usage.CostUSD, nil
+}
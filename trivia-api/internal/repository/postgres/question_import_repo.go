@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// QuestionImportRepo реализует repository.QuestionImportRepository
+type QuestionImportRepo struct {
+	db *gorm.DB
+}
+
+// NewQuestionImportRepo создает новый экземпляр
+func NewQuestionImportRepo(db *gorm.DB) *QuestionImportRepo {
+	return &QuestionImportRepo{db: db}
+}
+
+// Create сохраняет новый запуск импорта
+func (r *QuestionImportRepo) Create(run *entity.QuestionImport) error {
+	if err := r.db.Create(run).Error; err != nil {
+		return fmt.Errorf("failed to create question import: %w", err)
+	}
+	return nil
+}
+
+// Save сохраняет итоги запуска
+func (r *QuestionImportRepo) Save(run *entity.QuestionImport) error {
+	if err := r.db.Save(run).Error; err != nil {
+		return fmt.Errorf("failed to save question import: %w", err)
+	}
+	return nil
+}
+
+// GetByID возвращает запуск импорта
+func (r *QuestionImportRepo) GetByID(id uint) (*entity.QuestionImport, error) {
+	var run entity.QuestionImport
+	if err := r.db.First(&run, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get question import: %w", err)
+	}
+	return &run, nil
+}
+
+// List возвращает запуски импорта, новые первыми
+func (r *QuestionImportRepo) List(limit, offset int) ([]entity.QuestionImport, int64, error) {
+	var total int64
+	if err := r.db.Model(&entity.QuestionImport{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count question imports: %w", err)
+	}
+	var runs []entity.QuestionImport
+	if err := r.db.Order("id DESC").Limit(limit).Offset(offset).Find(&runs).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list question imports: %w", err)
+	}
+	return runs, total, nil
+}
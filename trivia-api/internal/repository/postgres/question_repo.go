@@ -71,6 +71,7 @@ func (r *QuestionRepo) GetRandomQuestions(limit int) ([]entity.Question, error)
 	sql := `
 		SELECT * FROM questions 
 		TABLESAMPLE SYSTEM_ROWS(?)
+		WHERE review_status = 'approved'
 		ORDER BY RANDOM()
 		LIMIT ?
 	`
@@ -84,7 +85,7 @@ func (r *QuestionRepo) GetRandomQuestions(limit int) ([]entity.Question, error)
 	err := r.db.Raw(sql, sampleSize, limit).Scan(&questions).Error
 	if err != nil {
 		// Fallback на старый метод, если TABLESAMPLE не поддерживается или пустой результат
-		err = r.db.Order("RANDOM()").Limit(limit).Find(&questions).Error
+		err = r.db.Where("review_status = ?", entity.QuestionReviewApproved).Order("RANDOM()").Limit(limit).Find(&questions).Error
 		if err != nil {
 			return nil, err
 		}
@@ -92,7 +93,7 @@ func (r *QuestionRepo) GetRandomQuestions(limit int) ([]entity.Question, error)
 
 	// Если TABLESAMPLE вернул пустой результат (маленькая таблица), используем fallback
 	if len(questions) == 0 {
-		err = r.db.Order("RANDOM()").Limit(limit).Find(&questions).Error
+		err = r.db.Where("review_status = ?", entity.QuestionReviewApproved).Order("RANDOM()").Limit(limit).Find(&questions).Error
 		if err != nil {
 			return nil, err
 		}
@@ -106,16 +107,41 @@ func (r *QuestionRepo) Update(question *entity.Question) error {
 	return r.db.Save(question).Error
 }
 
+// CountMediaReferences возвращает число вопросов, ссылающихся на объект хранилища
+func (r *QuestionRepo) CountMediaReferences(key string) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.Question{}).
+		Where("image_key = ? OR audio_key = ?", key, key).
+		Count(&count).Error
+	return count, err
+}
+
+// ExistingTextHashes возвращает хеши текста, с которыми уже есть вопросы
+func (r *QuestionRepo) ExistingTextHashes(hashes []string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+	if len(hashes) == 0 {
+		return existing, nil
+	}
+	var found []string
+	if err := r.db.Model(&entity.Question{}).Where("text_hash IN ?", hashes).Distinct().Pluck("text_hash", &found).Error; err != nil {
+		return nil, err
+	}
+	for _, hash := range found {
+		existing[hash] = true
+	}
+	return existing, nil
+}
+
 // Delete удаляет вопрос
 func (r *QuestionRepo) Delete(id uint) error {
 	return r.db.Delete(&entity.Question{}, id).Error
 }
 
-// GetRandomByDifficulty возвращает случайные неиспользованные вопросы заданной сложности
+// GetRandomByDifficulty возвращает случайные неиспользованные одобренные вопросы заданной сложности
 func (r *QuestionRepo) GetRandomByDifficulty(difficulty int, limit int, excludeIDs []uint) ([]entity.Question, error) {
 	var questions []entity.Question
 
-	query := r.db.Where("difficulty = ? AND is_used = ?", difficulty, false)
+	query := r.db.Where("difficulty = ? AND is_used = ? AND review_status = ?", difficulty, false, entity.QuestionReviewApproved)
 
 	// Исключаем уже использованные в текущей викторине вопросы
 	if len(excludeIDs) > 0 {
@@ -136,23 +162,28 @@ func (r *QuestionRepo) MarkAsUsed(questionIDs []uint) error {
 		Update("is_used", true).Error
 }
 
-// CountByDifficulty возвращает количество неиспользованных вопросов заданной сложности
+// CountByDifficulty возвращает количество неиспользованных одобренных вопросов заданной сложности
 func (r *QuestionRepo) CountByDifficulty(difficulty int) (int64, error) {
 	var count int64
 	err := r.db.Model(&entity.Question{}).
-		Where("difficulty = ? AND is_used = ?", difficulty, false).
+		Where("difficulty = ? AND is_used = ? AND review_status = ?", difficulty, false, entity.QuestionReviewApproved).
 		Count(&count).Error
 	return count, err
 }
 
-// GetQuizQuestionByDifficulty ищет неиспользованный вопрос викторины по сложности
-// Используется для гибридной адаптивной системы (приоритет вопросов викторины)
-func (r *QuestionRepo) GetQuizQuestionByDifficulty(quizID uint, difficulty int, excludeIDs []uint) (*entity.Question, error) {
+// GetQuizQuestionByDifficulty ищет неиспользованный одобренный вопрос викторины по сложности
+// Используется для гибридной адаптивной системы (приоритет вопросов викторины).
+// verifiedKK — только вопросы с проверенным казахским текстом: ручной или принятый перевод
+// попадает в text_kk, а непроверенный машинный остаётся только в question_translations.
+func (r *QuestionRepo) GetQuizQuestionByDifficulty(quizID uint, difficulty int, excludeIDs []uint, verifiedKK bool) (*entity.Question, error) {
 	var question entity.Question
-	query := r.db.Where("quiz_id = ? AND difficulty = ? AND is_used = ?", quizID, difficulty, false)
+	query := r.db.Where("quiz_id = ? AND difficulty = ? AND is_used = ? AND review_status = ?", quizID, difficulty, false, entity.QuestionReviewApproved)
 	if len(excludeIDs) > 0 {
 		query = query.Where("id NOT IN ?", excludeIDs)
 	}
+	if verifiedKK {
+		query = query.Where("text_kk <> ''")
+	}
 	err := query.Order("RANDOM()").First(&question).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, nil
@@ -165,12 +196,60 @@ func (r *QuestionRepo) GetQuizQuestionByDifficulty(quizID uint, difficulty int,
 
 // GetPoolQuestionByDifficulty ищет вопрос в общем пуле (quiz_id IS NULL) по сложности
 // Используется адаптивной системой когда у викторины нет своих вопросов нужной сложности
-func (r *QuestionRepo) GetPoolQuestionByDifficulty(difficulty int, excludeIDs []uint) (*entity.Question, error) {
+func (r *QuestionRepo) GetPoolQuestionByDifficulty(difficulty int, excludeIDs []uint, verifiedKK bool) (*entity.Question, error) {
+	var question entity.Question
+	query := r.db.Where("quiz_id IS NULL AND difficulty = ? AND is_used = ? AND review_status = ?", difficulty, false, entity.QuestionReviewApproved)
+	if len(excludeIDs) > 0 {
+		query = query.Where("id NOT IN ?", excludeIDs)
+	}
+	if verifiedKK {
+		query = query.Where("text_kk <> ''")
+	}
+	err := query.Order("RANDOM()").First(&question).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &question, nil
+}
+
+// GetPoolQuestionBySeed выбирает доступный вопрос пула без картинки и аудио: seed по модулю числа
+// подходящих вопросов — смещение в порядке id
+func (r *QuestionRepo) GetPoolQuestionBySeed(difficulty int, seed uint64) (*entity.Question, error) {
+	query := r.db.Model(&entity.Question{}).
+		Where("quiz_id IS NULL AND difficulty = ? AND is_used = ? AND review_status = ?", difficulty, false, entity.QuestionReviewApproved).
+		Where("image_key = '' AND audio_key = ''")
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, nil
+	}
 	var question entity.Question
-	query := r.db.Where("quiz_id IS NULL AND difficulty = ? AND is_used = ?", difficulty, false)
+	if err := query.Order("id").Offset(int(seed % uint64(count))).Limit(1).Find(&question).Error; err != nil {
+		return nil, err
+	}
+	if question.ID == 0 {
+		return nil, nil
+	}
+	return &question, nil
+}
+
+// GetCategoryPoolQuestionByDifficulty ищет вопрос общего пула заданной категории по сложности.
+// Используется адаптивной системой для тематических викторин раньше остального пула.
+func (r *QuestionRepo) GetCategoryPoolQuestionByDifficulty(categoryID uint, difficulty int, excludeIDs []uint, verifiedKK bool) (*entity.Question, error) {
+	var question entity.Question
+	query := r.db.Where("quiz_id IS NULL AND category_id = ? AND difficulty = ? AND is_used = ? AND review_status = ?",
+		categoryID, difficulty, false, entity.QuestionReviewApproved)
 	if len(excludeIDs) > 0 {
 		query = query.Where("id NOT IN ?", excludeIDs)
 	}
+	if verifiedKK {
+		query = query.Where("text_kk <> ''")
+	}
 	err := query.Order("RANDOM()").First(&question).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, nil
@@ -181,8 +260,19 @@ func (r *QuestionRepo) GetPoolQuestionByDifficulty(difficulty int, excludeIDs []
 	return &question, nil
 }
 
-// GetPoolStats возвращает статистику общего пула вопросов (1 SQL с GROUP BY)
+// GetPoolStats возвращает статистику общего пула вопросов (1 SQL с GROUP BY).
+// Доступными считаются только неиспользованные одобренные вопросы.
 func (r *QuestionRepo) GetPoolStats() (total int64, available int64, byDifficulty map[int]int64, err error) {
+	return r.poolStats(r.db.Where("quiz_id IS NULL"))
+}
+
+// GetCategoryPoolStats возвращает статистику вопросов общего пула одной категории
+func (r *QuestionRepo) GetCategoryPoolStats(categoryID uint) (total int64, available int64, byDifficulty map[int]int64, err error) {
+	return r.poolStats(r.db.Where("quiz_id IS NULL AND category_id = ?", categoryID))
+}
+
+// poolStats считает вопросы пула, отобранные scope, по сложности и доступности
+func (r *QuestionRepo) poolStats(scope *gorm.DB) (total int64, available int64, byDifficulty map[int]int64, err error) {
 	byDifficulty = make(map[int]int64)
 
 	// Инициализируем все уровни сложности 1-5 нулями
@@ -193,13 +283,13 @@ func (r *QuestionRepo) GetPoolStats() (total int64, available int64, byDifficult
 	type stat struct {
 		Difficulty int
 		IsUsed     bool
+		Approved   bool
 		Count      int64
 	}
 	var stats []stat
-	err = r.db.Model(&entity.Question{}).
-		Select("difficulty, is_used, COUNT(*) as count").
-		Where("quiz_id IS NULL").
-		Group("difficulty, is_used").
+	err = scope.Model(&entity.Question{}).
+		Select("difficulty, is_used, review_status = ? AS approved, COUNT(*) as count", entity.QuestionReviewApproved).
+		Group("difficulty, is_used, approved").
 		Scan(&stats).Error
 	if err != nil {
 		return 0, 0, nil, err
@@ -207,19 +297,19 @@ func (r *QuestionRepo) GetPoolStats() (total int64, available int64, byDifficult
 
 	for _, s := range stats {
 		total += s.Count
-		if !s.IsUsed {
+		if !s.IsUsed && s.Approved {
 			available += s.Count
-			byDifficulty[s.Difficulty] = s.Count
+			byDifficulty[s.Difficulty] += s.Count
 		}
 	}
 	return total, available, byDifficulty, nil
 }
 
-// CountAvailablePool возвращает количество доступных (неиспользованных) вопросов в общем пуле
+// CountAvailablePool возвращает количество доступных (неиспользованных одобренных) вопросов в общем пуле
 func (r *QuestionRepo) CountAvailablePool() (int64, error) {
 	var count int64
 	err := r.db.Model(&entity.Question{}).
-		Where("quiz_id IS NULL AND is_used = ?", false).
+		Where("quiz_id IS NULL AND is_used = ? AND review_status = ?", false, entity.QuestionReviewApproved).
 		Count(&count).Error
 	return count, err
 }
@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// QuestionReviewRepo реализует repository.QuestionReviewRepository
+type QuestionReviewRepo struct {
+	db *gorm.DB
+}
+
+// NewQuestionReviewRepo создает новый экземпляр
+func NewQuestionReviewRepo(db *gorm.DB) *QuestionReviewRepo {
+	return &QuestionReviewRepo{db: db}
+}
+
+// TransitionReview атомарно переводит вопрос из fromStatus в question.ReviewStatus и пишет историю
+func (r *QuestionReviewRepo) TransitionReview(question *entity.Question, fromStatus string, entry *entity.QuestionReviewComment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		question.UpdatedAt = time.Now()
+		result := tx.Model(&entity.Question{}).
+			Where("id = ? AND review_status = ?", question.ID, fromStatus).
+			Updates(map[string]interface{}{
+				"review_status": question.ReviewStatus,
+				"reviewer_id":   question.ReviewerID,
+				"reviewed_at":   question.ReviewedAt,
+				"updated_at":    question.UpdatedAt,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to update question review: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("%w: question is no longer %s", apperrors.ErrConflict, fromStatus)
+		}
+
+		if entry != nil {
+			if err := tx.Create(entry).Error; err != nil {
+				return fmt.Errorf("failed to save question review entry: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// AddComment добавляет комментарий в историю проверки вопроса
+func (r *QuestionReviewRepo) AddComment(comment *entity.QuestionReviewComment) error {
+	if err := r.db.Create(comment).Error; err != nil {
+		return fmt.Errorf("failed to save question review comment: %w", err)
+	}
+	return nil
+}
+
+// ListComments возвращает историю проверки вопроса в хронологическом порядке
+func (r *QuestionReviewRepo) ListComments(questionID uint) ([]entity.QuestionReviewComment, error) {
+	var comments []entity.QuestionReviewComment
+	err := r.db.Where("question_id = ?", questionID).
+		Order("created_at ASC, id ASC").
+		Find(&comments).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list question review comments: %w", err)
+	}
+	return comments, nil
+}
+
+// ListQueue возвращает вопросы по фильтрам (старые первыми) и общее количество
+func (r *QuestionReviewRepo) ListQueue(filters repository.QuestionReviewFilters, limit, offset int) ([]entity.Question, int64, error) {
+	query := r.db.Model(&entity.Question{})
+	if filters.Status != "" {
+		query = query.Where("review_status = ?", filters.Status)
+	}
+	if filters.ReviewerID != nil {
+		query = query.Where("reviewer_id = ?", *filters.ReviewerID)
+	}
+	if filters.QuizID != nil {
+		query = query.Where("quiz_id = ?", *filters.QuizID)
+	} else if filters.PoolOnly {
+		query = query.Where("quiz_id IS NULL")
+	}
+	if filters.ImportID != nil {
+		query = query.Where("import_id = ?", *filters.ImportID)
+	}
+	if filters.GenerationID != nil {
+		query = query.Where("generation_id = ?", *filters.GenerationID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count review queue: %w", err)
+	}
+
+	var questions []entity.Question
+	if err := query.Order("updated_at ASC, id ASC").Limit(limit).Offset(offset).Find(&questions).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list review queue: %w", err)
+	}
+	return questions, total, nil
+}
@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// QuestionTranslationRepo реализует repository.QuestionTranslationRepository
+type QuestionTranslationRepo struct {
+	db *gorm.DB
+}
+
+// NewQuestionTranslationRepo создает новый экземпляр
+func NewQuestionTranslationRepo(db *gorm.DB) *QuestionTranslationRepo {
+	return &QuestionTranslationRepo{db: db}
+}
+
+// ListByQuestionIDs возвращает переводы нескольких вопросов одним запросом
+func (r *QuestionTranslationRepo) ListByQuestionIDs(questionIDs []uint) ([]entity.QuestionTranslation, error) {
+	var translations []entity.QuestionTranslation
+	if len(questionIDs) == 0 {
+		return translations, nil
+	}
+	err := r.db.Where("question_id IN ?", questionIDs).
+		Order("question_id ASC, locale ASC").
+		Find(&translations).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list question translations: %w", err)
+	}
+	return translations, nil
+}
+
+// Get возвращает перевод вопроса на язык
+func (r *QuestionTranslationRepo) Get(questionID uint, locale string) (*entity.QuestionTranslation, error) {
+	var translation entity.QuestionTranslation
+	err := r.db.Where("question_id = ? AND locale = ?", questionID, locale).First(&translation).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question translation: %w", err)
+	}
+	return &translation, nil
+}
+
+// Upsert создаёт перевод или заменяет существующий перевод вопроса на тот же язык
+func (r *QuestionTranslationRepo) Upsert(translation *entity.QuestionTranslation) error {
+	err := r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "question_id"}, {Name: "locale"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"text":               translation.Text,
+			"options":            translation.Options,
+			"machine_translated": translation.MachineTranslated,
+			"mt_provider":        translation.MTProvider,
+			"reviewed_by":        translation.ReviewedBy,
+			"reviewed_at":        translation.ReviewedAt,
+			"updated_at":         gorm.Expr("NOW()"),
+		}),
+	}).Create(translation).Error
+	if err != nil {
+		return fmt.Errorf("failed to upsert question translation: %w", err)
+	}
+	return nil
+}
+
+// Delete удаляет перевод вопроса на язык
+func (r *QuestionTranslationRepo) Delete(questionID uint, locale string) error {
+	result := r.db.Where("question_id = ? AND locale = ?", questionID, locale).Delete(&entity.QuestionTranslation{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete question translation: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
+
+// CreateIfMissing сохраняет перевод, если у вопроса ещё нет перевода на этот язык
+func (r *QuestionTranslationRepo) CreateIfMissing(translation *entity.QuestionTranslation) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "question_id"}, {Name: "locale"}},
+		DoNothing: true,
+	}).Create(translation)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to create question translation: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ListMachineTranslated возвращает непроверенные машинные переводы на язык, старые первыми
+func (r *QuestionTranslationRepo) ListMachineTranslated(locale string, limit, offset int) ([]entity.QuestionTranslation, int64, error) {
+	var translations []entity.QuestionTranslation
+	var total int64
+	query := r.db.Model(&entity.QuestionTranslation{}).Where("locale = ? AND machine_translated", locale)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count machine translations: %w", err)
+	}
+	err := query.Preload("Question").
+		Order("id ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&translations).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list machine translations: %w", err)
+	}
+	return translations, total, nil
+}
+
+// ListUntranslated возвращает вопросы без перевода на язык. Для казахского вопрос
+// с заполненным устаревшим text_kk считается переведённым.
+func (r *QuestionTranslationRepo) ListUntranslated(locale string, afterID uint, limit int) ([]entity.Question, error) {
+	var questions []entity.Question
+	query := r.db.Where("id > ? AND review_status <> ?", afterID, entity.QuestionReviewRejected).
+		Where("NOT EXISTS (SELECT 1 FROM question_translations t WHERE t.question_id = questions.id AND t.locale = ?)", locale)
+	if locale == "kk" {
+		query = query.Where("COALESCE(text_kk, '') = ''")
+	}
+	err := query.Order("id ASC").Limit(limit).Find(&questions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list untranslated questions: %w", err)
+	}
+	return questions, nil
+}
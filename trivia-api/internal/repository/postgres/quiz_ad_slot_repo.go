@@ -42,6 +42,22 @@ func (r *QuizAdSlotRepository) ListByQuizID(quizID uint) ([]entity.QuizAdSlot, e
 	return slots, nil
 }
 
+// ListByQuizIDs возвращает слоты нескольких викторин с загруженными AdAsset
+func (r *QuizAdSlotRepository) ListByQuizIDs(quizIDs []uint) ([]entity.QuizAdSlot, error) {
+	var slots []entity.QuizAdSlot
+	if len(quizIDs) == 0 {
+		return slots, nil
+	}
+	err := r.db.Preload("AdAsset").
+		Where("quiz_id IN ?", quizIDs).
+		Order("quiz_id ASC, question_after ASC").
+		Find(&slots).Error
+	if err != nil {
+		return nil, err
+	}
+	return slots, nil
+}
+
 // GetByQuizAndQuestionAfter возвращает активный слот для конкретного вопроса викторины
 func (r *QuizAdSlotRepository) GetByQuizAndQuestionAfter(quizID uint, questionAfter int) (*entity.QuizAdSlot, error) {
 	var slot entity.QuizAdSlot
@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// QuizAnnouncementRepo реализует repository.QuizAnnouncementRepository
+type QuizAnnouncementRepo struct {
+	db *gorm.DB
+}
+
+// NewQuizAnnouncementRepo создает новый экземпляр
+func NewQuizAnnouncementRepo(db *gorm.DB) *QuizAnnouncementRepo {
+	return &QuizAnnouncementRepo{db: db}
+}
+
+// announcementOrder — сначала объявления до начала, затем по ходу викторины
+const announcementOrder = "CASE trigger WHEN 'before_start' THEN 0 WHEN 'after_question' THEN 1 ELSE 2 END, " +
+	"offset_sec DESC, question_number ASC, id ASC"
+
+// Create сохраняет объявление
+func (r *QuizAnnouncementRepo) Create(announcement *entity.QuizAnnouncement) error {
+	if err := r.db.Create(announcement).Error; err != nil {
+		return fmt.Errorf("failed to create quiz announcement: %w", err)
+	}
+	return nil
+}
+
+// GetByID возвращает объявление викторины
+func (r *QuizAnnouncementRepo) GetByID(quizID, id uint) (*entity.QuizAnnouncement, error) {
+	var announcement entity.QuizAnnouncement
+	err := r.db.Where("quiz_id = ?", quizID).First(&announcement, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quiz announcement %d: %w", id, err)
+	}
+	return &announcement, nil
+}
+
+// ListByQuizID возвращает объявления викторины
+func (r *QuizAnnouncementRepo) ListByQuizID(quizID uint) ([]entity.QuizAnnouncement, error) {
+	var announcements []entity.QuizAnnouncement
+	if err := r.db.Where("quiz_id = ?", quizID).Order(announcementOrder).Find(&announcements).Error; err != nil {
+		return nil, fmt.Errorf("failed to list quiz announcements: %w", err)
+	}
+	return announcements, nil
+}
+
+// ListPending возвращает неотправленные объявления викторины
+func (r *QuizAnnouncementRepo) ListPending(quizID uint) ([]entity.QuizAnnouncement, error) {
+	var announcements []entity.QuizAnnouncement
+	err := r.db.Where("quiz_id = ? AND sent_at IS NULL", quizID).Order(announcementOrder).Find(&announcements).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending quiz announcements: %w", err)
+	}
+	return announcements, nil
+}
+
+// Update сохраняет изменения объявления
+func (r *QuizAnnouncementRepo) Update(announcement *entity.QuizAnnouncement) error {
+	if err := r.db.Save(announcement).Error; err != nil {
+		return fmt.Errorf("failed to update quiz announcement: %w", err)
+	}
+	return nil
+}
+
+// Delete удаляет объявление викторины
+func (r *QuizAnnouncementRepo) Delete(quizID, id uint) error {
+	result := r.db.Where("quiz_id = ?", quizID).Delete(&entity.QuizAnnouncement{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete quiz announcement: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
+
+// MarkSent атомарно помечает объявление отправленным: условие sent_at IS NULL
+// не даёт двум процессам разослать одно объявление
+func (r *QuizAnnouncementRepo) MarkSent(id uint, sentAt time.Time) (bool, error) {
+	result := r.db.Model(&entity.QuizAnnouncement{}).
+		Where("id = ? AND sent_at IS NULL", id).
+		Update("sent_at", sentAt)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to mark quiz announcement %d sent: %w", id, result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
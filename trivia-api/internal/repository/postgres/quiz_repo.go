@@ -42,6 +42,16 @@ func (r *QuizRepo) GetByID(id uint) (*entity.Quiz, error) {
 	return &quiz, nil
 }
 
+// GetByIDs возвращает викторины с указанными ID
+func (r *QuizRepo) GetByIDs(ids []uint) ([]entity.Quiz, error) {
+	var quizzes []entity.Quiz
+	if len(ids) == 0 {
+		return quizzes, nil
+	}
+	err := r.db.Where("id IN ?", ids).Find(&quizzes).Error
+	return quizzes, err
+}
+
 // GetActive возвращает активную викторину
 func (r *QuizRepo) GetActive() (*entity.Quiz, error) {
 	var quiz entity.Quiz
@@ -55,10 +65,10 @@ func (r *QuizRepo) GetActive() (*entity.Quiz, error) {
 	return &quiz, nil
 }
 
-// GetScheduled возвращает все запланированные викторины
+// GetScheduled возвращает все запланированные викторины вместе с категорией и тегами
 func (r *QuizRepo) GetScheduled() ([]entity.Quiz, error) {
 	var quizzes []entity.Quiz
-	err := r.db.Where("status = ? AND scheduled_time > ?", entity.QuizStatusScheduled, time.Now()).
+	err := r.db.Preload("Category").Preload("Tags").Where("status = ? AND scheduled_time > ?", entity.QuizStatusScheduled, time.Now()).
 		Order("scheduled_time").
 		Find(&quizzes).Error
 	if err != nil {
@@ -164,6 +174,140 @@ func (r *QuizRepo) Update(quiz *entity.Quiz) error {
 	return r.db.Save(quiz).Error
 }
 
+// UpdateSecondChance точечно обновляет настройки второго шанса викторины
+func (r *QuizRepo) UpdateSecondChance(quizID uint, mode string, cost int64, adAssetID *uint) error {
+	return r.db.Model(&entity.Quiz{}).
+		Where("id = ?", quizID).
+		Updates(map[string]interface{}{
+			"second_chance_mode":        mode,
+			"second_chance_cost":        cost,
+			"second_chance_ad_asset_id": adAssetID,
+		}).Error
+}
+
+// UpdateMaxPlayers точечно обновляет вместимость зала ожидания викторины
+func (r *QuizRepo) UpdateMaxPlayers(quizID uint, maxPlayers int) error {
+	result := r.db.Model(&entity.Quiz{}).Where("id = ?", quizID).Update("max_players", maxPlayers)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update quiz max players: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
+
+// UpdateTiming точечно заменяет переопределения таймингов викторины; nil-поля сбрасываются в NULL
+func (r *QuizRepo) UpdateTiming(quizID uint, timing entity.QuizTiming) error {
+	result := r.db.Model(&entity.Quiz{}).Where("id = ?", quizID).Updates(map[string]interface{}{
+		"question_delay_ms":      timing.QuestionDelayMs,
+		"answer_reveal_delay_ms": timing.AnswerRevealDelayMs,
+		"time_limit_multiplier":  timing.TimeLimitMultiplier,
+		"ad_break_duration_sec":  timing.AdBreakDurationSec,
+	})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update quiz timing: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
+
+// UpdateGameMode точечно обновляет режим игры викторины
+func (r *QuizRepo) UpdateGameMode(quizID uint, mode string, lives int, suddenDeath bool) error {
+	result := r.db.Model(&entity.Quiz{}).Where("id = ?", quizID).Updates(map[string]interface{}{
+		"game_mode":    mode,
+		"lives":        lives,
+		"sudden_death": suddenDeath,
+	})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update quiz game mode: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
+
+// UpdateTieBreak точечно обновляет способ разрешения ничьей между победителями
+func (r *QuizRepo) UpdateTieBreak(quizID uint, tieBreak string) error {
+	result := r.db.Model(&entity.Quiz{}).Where("id = ?", quizID).Update("tie_break", tieBreak)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update quiz tie-break: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
+
+// UpdateScoringStrategy точечно обновляет стратегию начисления очков
+func (r *QuizRepo) UpdateScoringStrategy(quizID uint, strategy string) error {
+	result := r.db.Model(&entity.Quiz{}).Where("id = ?", quizID).Update("scoring_strategy", strategy)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update quiz scoring strategy: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
+
+// UpdateRequireVerifiedKK точечно обновляет требование проверенного казахского текста вопросов
+func (r *QuizRepo) UpdateRequireVerifiedKK(quizID uint, require bool) error {
+	result := r.db.Model(&entity.Quiz{}).Where("id = ?", quizID).Update("require_verified_kk", require)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update quiz kk requirement: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
+
+// UpdatePrizeLadder заменяет лестницу призов викторины (пустая — деление поровну)
+func (r *QuizRepo) UpdatePrizeLadder(quizID uint, ladder entity.PrizeLadder) error {
+	result := r.db.Model(&entity.Quiz{}).Where("id = ?", quizID).Update("prize_ladder", ladder)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update quiz prize ladder: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
+
+// UpdateAllowedCountries заменяет список стран, игроки из которых получают призы
+func (r *QuizRepo) UpdateAllowedCountries(quizID uint, countries entity.StringArray) error {
+	result := r.db.Model(&entity.Quiz{}).Where("id = ?", quizID).Update("allowed_countries", countries)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update quiz allowed countries: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
+
+// UpdateSponsor точечно заменяет брендинг спонсора викторины
+func (r *QuizRepo) UpdateSponsor(quizID uint, sponsor entity.QuizSponsor) error {
+	result := r.db.Model(&entity.Quiz{}).Where("id = ?", quizID).Updates(map[string]interface{}{
+		"sponsor_name":            sponsor.Name,
+		"sponsor_logo_asset_id":   sponsor.LogoAssetID,
+		"sponsor_primary_color":   sponsor.PrimaryColor,
+		"sponsor_secondary_color": sponsor.SecondaryColor,
+		"sponsor_click_url":       sponsor.ClickURL,
+	})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update quiz sponsor: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
+
 // List возвращает список викторин с пагинацией
 func (r *QuizRepo) List(limit, offset int) ([]entity.Quiz, error) {
 	var quizzes []entity.Quiz
@@ -197,6 +341,14 @@ func (r *QuizRepo) ListWithFilters(filters repository.QuizFilters, limit, offset
 		query = query.Where("scheduled_time <= ?", *filters.DateTo)
 	}
 
+	if filters.Category != "" {
+		query = query.Where("category_id IN (SELECT id FROM categories WHERE slug = ?)", filters.Category)
+	}
+
+	if filters.Tag != "" {
+		query = query.Where("id IN (SELECT qt.quiz_id FROM quiz_tags qt JOIN tags t ON t.id = qt.tag_id WHERE t.slug = ?)", filters.Tag)
+	}
+
 	// Получаем total count
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -205,12 +357,13 @@ func (r *QuizRepo) ListWithFilters(filters repository.QuizFilters, limit, offset
 	// Preserve legacy ordering for "no filters" mode (id DESC).
 	// With active filters keep business-oriented ordering by scheduled_time DESC.
 	orderBy := "id DESC"
-	if filters.Status != "" || filters.Search != "" || filters.DateFrom != nil || filters.DateTo != nil {
+	if filters.Status != "" || filters.Search != "" || filters.DateFrom != nil || filters.DateTo != nil ||
+		filters.Category != "" || filters.Tag != "" {
 		orderBy = "scheduled_time DESC"
 	}
 
-	// Применяем пагинацию и сортировку
-	err := query.Limit(limit).Offset(offset).Order(orderBy).Find(&quizzes).Error
+	// Применяем пагинацию и сортировку; теги нужны карточкам в списке
+	err := query.Preload("Tags").Limit(limit).Offset(offset).Order(orderBy).Find(&quizzes).Error
 	if err != nil {
 		return nil, 0, err
 	}
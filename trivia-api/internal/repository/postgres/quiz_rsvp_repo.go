@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// QuizRSVPRepo реализует repository.QuizRSVPRepository
+type QuizRSVPRepo struct {
+	db *gorm.DB
+}
+
+// NewQuizRSVPRepo создает новый экземпляр
+func NewQuizRSVPRepo(db *gorm.DB) *QuizRSVPRepo {
+	return &QuizRSVPRepo{db: db}
+}
+
+// Create записывает пользователя на викторину; повторная запись игнорируется
+func (r *QuizRSVPRepo) Create(rsvp *entity.QuizRSVP) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(rsvp)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to create quiz rsvp: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Delete отменяет запись пользователя на викторину
+func (r *QuizRSVPRepo) Delete(quizID, userID uint) (bool, error) {
+	result := r.db.Where("quiz_id = ? AND user_id = ?", quizID, userID).Delete(&entity.QuizRSVP{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete quiz rsvp: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Exists проверяет, записан ли пользователь на викторину
+func (r *QuizRSVPRepo) Exists(quizID, userID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&entity.QuizRSVP{}).Where("quiz_id = ? AND user_id = ?", quizID, userID).Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check quiz rsvp: %w", err)
+	}
+	return count > 0, nil
+}
+
+// CountByQuizIDs считает записи по викторинам одним запросом
+func (r *QuizRSVPRepo) CountByQuizIDs(quizIDs []uint) (map[uint]int64, error) {
+	counts := make(map[uint]int64, len(quizIDs))
+	if len(quizIDs) == 0 {
+		return counts, nil
+	}
+	var rows []struct {
+		QuizID uint
+		Count  int64
+	}
+	err := r.db.Model(&entity.QuizRSVP{}).
+		Select("quiz_id, COUNT(*) AS count").
+		Where("quiz_id IN ?", quizIDs).
+		Group("quiz_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count quiz rsvps: %w", err)
+	}
+	for _, row := range rows {
+		counts[row.QuizID] = row.Count
+	}
+	return counts, nil
+}
+
+// ListUserIDs возвращает ID записавшихся пользователей в порядке записи
+func (r *QuizRSVPRepo) ListUserIDs(quizID uint) ([]uint, error) {
+	var userIDs []uint
+	err := r.db.Model(&entity.QuizRSVP{}).
+		Where("quiz_id = ?", quizID).
+		Order("created_at ASC").
+		Pluck("user_id", &userIDs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quiz rsvp users: %w", err)
+	}
+	return userIDs, nil
+}
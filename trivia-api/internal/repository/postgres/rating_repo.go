@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RatingRepo реализует repository.RatingRepository
+type RatingRepo struct {
+	db *gorm.DB
+}
+
+// NewRatingRepo создает новый экземпляр
+func NewRatingRepo(db *gorm.DB) *RatingRepo {
+	return &RatingRepo{db: db}
+}
+
+// GetByUserID возвращает рейтинг пользователя; без записи — нулевой рейтинг
+func (r *RatingRepo) GetByUserID(userID uint) (*entity.UserRating, error) {
+	rating := entity.UserRating{UserID: userID}
+	if err := r.db.Where("user_id = ?", userID).Limit(1).Find(&rating).Error; err != nil {
+		return nil, fmt.Errorf("failed to get user rating: %w", err)
+	}
+	return &rating, nil
+}
+
+// ApplyQuiz пересчитывает рейтинги участников викторины. Строки рейтинга блокируются в порядке
+// user_id, чтобы параллельный пересчёт другой викторины с теми же игроками ждал, а не читал
+// устаревший рейтинг; уникальный индекс истории не даёт учесть викторину дважды.
+func (r *RatingRepo) ApplyQuiz(quizID uint, userIDs []uint, update repository.RatingUpdate) (bool, error) {
+	applied := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var rated int64
+		if err := tx.Model(&entity.RatingHistory{}).Where("quiz_id = ?", quizID).Count(&rated).Error; err != nil {
+			return fmt.Errorf("failed to check rating history: %w", err)
+		}
+		if rated > 0 {
+			return nil
+		}
+
+		// Новичкам нужна строка, чтобы её можно было заблокировать
+		placeholders := make([]entity.UserRating, len(userIDs))
+		for i, userID := range userIDs {
+			placeholders[i] = entity.UserRating{UserID: userID}
+		}
+		if len(placeholders) > 0 {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&placeholders).Error; err != nil {
+				return fmt.Errorf("failed to create user ratings: %w", err)
+			}
+		}
+
+		var ratings []entity.UserRating
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id IN ?", userIDs).Order("user_id").Find(&ratings).Error; err != nil {
+			return fmt.Errorf("failed to lock user ratings: %w", err)
+		}
+		current := make(map[uint]entity.UserRating, len(ratings))
+		for _, rating := range ratings {
+			current[rating.UserID] = rating
+		}
+
+		updated, history := update(current)
+		if len(updated) > 0 {
+			if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&updated).Error; err != nil {
+				return fmt.Errorf("failed to save user ratings: %w", err)
+			}
+		}
+		if len(history) > 0 {
+			if err := tx.Create(&history).Error; err != nil {
+				return fmt.Errorf("failed to save rating history: %w", err)
+			}
+		}
+		applied = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return applied, nil
+}
+
+// GetLeaderboard возвращает игроков по убыванию рейтинга
+func (r *RatingRepo) GetLeaderboard(limit, offset int) ([]repository.RatingLeaderboardEntry, int64, error) {
+	query := r.db.Table("user_ratings ur").
+		Joins("JOIN users u ON u.id = ur.user_id").
+		Where("ur.games > 0 AND u.deleted_at IS NULL AND u.shadow_banned_at IS NULL")
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count rating leaderboard: %w", err)
+	}
+	var entries []repository.RatingLeaderboardEntry
+	if err := query.
+		Select("ur.user_id, u.username, u.profile_picture, ur.rating, ur.games, u.show_on_leaderboards").
+		Order("ur.rating DESC, ur.user_id ASC").
+		Limit(limit).Offset(offset).
+		Scan(&entries).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get rating leaderboard: %w", err)
+	}
+	return entries, total, nil
+}
+
+// ListHistory возвращает изменения рейтинга пользователя, новые первыми
+func (r *RatingRepo) ListHistory(userID uint, limit, offset int) ([]entity.RatingHistory, int64, error) {
+	query := r.db.Model(&entity.RatingHistory{}).Where("user_id = ?", userID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count rating history: %w", err)
+	}
+	var history []entity.RatingHistory
+	if err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&history).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list rating history: %w", err)
+	}
+	return history, total, nil
+}
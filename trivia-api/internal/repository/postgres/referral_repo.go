@@ -0,0 +1,251 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// ReferralRepo реализует ReferralRepository
+type ReferralRepo struct {
+	db *gorm.DB
+}
+
+// NewReferralRepo создает новый экземпляр
+func NewReferralRepo(db *gorm.DB) *ReferralRepo {
+	return &ReferralRepo{db: db}
+}
+
+// CreateCode сохраняет персональный код пользователя
+func (r *ReferralRepo) CreateCode(code *entity.ReferralCode) error {
+	if err := r.db.Create(code).Error; err != nil {
+		return fmt.Errorf("failed to create referral code: %w", err)
+	}
+	return nil
+}
+
+// GetCodeByUserID возвращает код пользователя
+func (r *ReferralRepo) GetCodeByUserID(userID uint) (*entity.ReferralCode, error) {
+	var code entity.ReferralCode
+	if err := r.db.Where("user_id = ?", userID).First(&code).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get referral code: %w", err)
+	}
+	return &code, nil
+}
+
+// GetCodeByCode находит владельца кода
+func (r *ReferralRepo) GetCodeByCode(code string) (*entity.ReferralCode, error) {
+	var rc entity.ReferralCode
+	if err := r.db.Where("code = ?", code).First(&rc).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get referral code: %w", err)
+	}
+	return &rc, nil
+}
+
+// Create сохраняет реферальную запись
+func (r *ReferralRepo) Create(referral *entity.Referral) error {
+	if err := r.db.Create(referral).Error; err != nil {
+		return fmt.Errorf("failed to create referral: %w", err)
+	}
+	return nil
+}
+
+// GetByRefereeID возвращает реферальную запись приглашённого пользователя
+func (r *ReferralRepo) GetByRefereeID(refereeID uint) (*entity.Referral, error) {
+	var referral entity.Referral
+	if err := r.db.Where("referee_id = ?", refereeID).First(&referral).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get referral: %w", err)
+	}
+	return &referral, nil
+}
+
+// ListByReferrer возвращает приглашения пользователя с пагинацией
+func (r *ReferralRepo) ListByReferrer(referrerID uint, limit, offset int) ([]entity.Referral, int64, error) {
+	var referrals []entity.Referral
+	var total int64
+
+	query := r.db.Model(&entity.Referral{}).Where("referrer_id = ?", referrerID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count referrals: %w", err)
+	}
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&referrals).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list referrals: %w", err)
+	}
+	return referrals, total, nil
+}
+
+// CountByReferrerAndStatus возвращает количество приглашений пользователя в указанном статусе
+func (r *ReferralRepo) CountByReferrerAndStatus(referrerID uint, status string) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.Referral{}).
+		Where("referrer_id = ? AND status = ?", referrerID, status).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count referrals by status: %w", err)
+	}
+	return count, nil
+}
+
+// CountByRefereeIP возвращает количество приглашений пользователя с указанного IP
+func (r *ReferralRepo) CountByRefereeIP(referrerID uint, ip string) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.Referral{}).
+		Where("referrer_id = ? AND referee_ip = ?", referrerID, ip).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count referrals by ip: %w", err)
+	}
+	return count, nil
+}
+
+// ExistsForDevice проверяет, было ли у пригласившего приглашение с этого устройства
+func (r *ReferralRepo) ExistsForDevice(referrerID uint, deviceID string) (bool, error) {
+	var count int64
+	err := r.db.Model(&entity.Referral{}).
+		Where("referrer_id = ? AND referee_device_id = ?", referrerID, deviceID).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check referral device: %w", err)
+	}
+	return count > 0, nil
+}
+
+// MarkCredited переводит запись в credited и начисляет бонус пригласившему в одной транзакции
+func (r *ReferralRepo) MarkCredited(referralID uint, bonusPoints int, creditedAt time.Time) (bool, error) {
+	credited := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var referral entity.Referral
+		if err := tx.Where("id = ?", referralID).First(&referral).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperrors.ErrNotFound
+			}
+			return err
+		}
+
+		res := tx.Model(&entity.Referral{}).
+			Where("id = ? AND status = ?", referralID, entity.ReferralStatusPending).
+			Updates(map[string]interface{}{
+				"status":       entity.ReferralStatusCredited,
+				"bonus_points": bonusPoints,
+				"credited_at":  creditedAt,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			// Уже обработана параллельным вызовом
+			return nil
+		}
+
+		if bonusPoints > 0 {
+			if err := tx.Model(&entity.User{}).Where("id = ?", referral.ReferrerID).
+				UpdateColumn("total_score", gorm.Expr("total_score + ?", bonusPoints)).Error; err != nil {
+				return err
+			}
+		}
+		credited = true
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return false, err
+		}
+		return false, fmt.Errorf("failed to credit referral: %w", err)
+	}
+	return credited, nil
+}
+
+// GetStats возвращает агрегированную статистику за период
+func (r *ReferralRepo) GetStats(from, to time.Time) (*repository.ReferralStats, error) {
+	query := r.db.Model(&entity.Referral{})
+	if !from.IsZero() {
+		query = query.Where("created_at >= ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("created_at < ?", to)
+	}
+
+	var row struct {
+		Total    int64
+		Pending  int64
+		Credited int64
+		Rejected int64
+		Bonus    int64
+	}
+	err := query.Select(
+		"COUNT(*) AS total, "+
+			"COUNT(*) FILTER (WHERE status = ?) AS pending, "+
+			"COUNT(*) FILTER (WHERE status = ?) AS credited, "+
+			"COUNT(*) FILTER (WHERE status = ?) AS rejected, "+
+			"COALESCE(SUM(bonus_points), 0) AS bonus",
+		entity.ReferralStatusPending, entity.ReferralStatusCredited, entity.ReferralStatusRejected,
+	).Scan(&row).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get referral stats: %w", err)
+	}
+
+	stats := &repository.ReferralStats{
+		TotalReferrals:    row.Total,
+		PendingReferrals:  row.Pending,
+		CreditedReferrals: row.Credited,
+		RejectedReferrals: row.Rejected,
+		TotalBonusPoints:  row.Bonus,
+	}
+	if row.Total > 0 {
+		stats.ConversionRate = float64(row.Credited) / float64(row.Total) * 100
+	}
+	return stats, nil
+}
+
+// GetTopReferrers возвращает пригласивших с наибольшим числом засчитанных рефералов
+func (r *ReferralRepo) GetTopReferrers(limit int) ([]repository.ReferrerSummary, error) {
+	var summaries []repository.ReferrerSummary
+	err := r.db.Table("referrals r").
+		Select("r.referrer_id, u.username, "+
+			"COUNT(*) AS total_referrals, "+
+			"COUNT(*) FILTER (WHERE r.status = ?) AS credited_referrals, "+
+			"COUNT(*) FILTER (WHERE r.status = ?) AS rejected_referrals, "+
+			"COALESCE(SUM(r.bonus_points), 0) AS total_bonus_points",
+			entity.ReferralStatusCredited, entity.ReferralStatusRejected).
+		Joins("JOIN users u ON u.id = r.referrer_id").
+		Group("r.referrer_id, u.username").
+		Order("credited_referrals DESC, total_referrals DESC").
+		Limit(limit).
+		Scan(&summaries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top referrers: %w", err)
+	}
+	return summaries, nil
+}
+
+// List возвращает реферальные записи с фильтром по статусу
+func (r *ReferralRepo) List(status string, limit, offset int) ([]entity.Referral, int64, error) {
+	var referrals []entity.Referral
+	var total int64
+
+	query := r.db.Model(&entity.Referral{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count referrals: %w", err)
+	}
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&referrals).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list referrals: %w", err)
+	}
+	return referrals, total, nil
+}
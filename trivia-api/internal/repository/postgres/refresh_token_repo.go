@@ -146,6 +146,38 @@ func (r *RefreshTokenRepo) MarkAllAsExpiredForUser(userID uint) error {
 	return nil
 }
 
+// SetDeviceKey привязывает токен к открытому ключу устройства
+func (r *RefreshTokenRepo) SetDeviceKey(id uint, deviceKey, thumbprint string) error {
+	result := r.db.Model(&entity.RefreshToken{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"device_key":            deviceKey,
+			"device_key_thumbprint": thumbprint,
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("ошибка привязки ключа устройства к refresh токену ID=%d: %w", id, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
+
+// MarkAsExpiredByDeviceKey помечает истекшими активные токены пользователя, привязанные к ключу устройства
+func (r *RefreshTokenRepo) MarkAsExpiredByDeviceKey(userID uint, thumbprint string) (int64, error) {
+	result := r.db.Model(&entity.RefreshToken{}).
+		Where("user_id = ? AND device_key_thumbprint = ? AND expires_at > ?", userID, thumbprint, time.Now()).
+		Updates(map[string]interface{}{
+			"expires_at": time.Now().Add(-1 * time.Hour),
+		})
+
+	if result.Error != nil {
+		return 0, fmt.Errorf("ошибка отзыва токенов пользователя %d по ключу устройства: %w", userID, result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
 // CleanupExpiredTokens удаляет истекшие токены из базы данных
 func (r *RefreshTokenRepo) CleanupExpiredTokens() (int64, error) {
 	result := r.db.Where("expires_at <= ?", time.Now()).Delete(&entity.RefreshToken{})
@@ -1,12 +1,15 @@
 package postgres
 
 import (
+	"context"
 	"errors"
 	"log"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
 	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
 )
 
@@ -20,11 +23,56 @@ func NewResultRepo(db *gorm.DB) *ResultRepo {
 	return &ResultRepo{db: db}
 }
 
+// WithContext возвращает копию репозитория, выполняющую запросы с контекстом ctx
+func (r *ResultRepo) WithContext(ctx context.Context) repository.ResultRepository {
+	return &ResultRepo{db: r.db.WithContext(ctx)}
+}
+
 // SaveUserAnswer сохраняет ответ пользователя
 func (r *ResultRepo) SaveUserAnswer(answer *entity.UserAnswer) error {
 	return r.db.Create(answer).Error
 }
 
+// answerBatchSize ограничивает число строк в одном INSERT (лимит параметров PostgreSQL — 65535)
+const answerBatchSize = 1000
+
+// SaveUserAnswersBatch сохраняет ответы многострочным INSERT ... ON CONFLICT DO NOTHING.
+// Конфликт по uidx_user_answers_user_quiz_question делает повторную запись пачки безопасной.
+func (r *ResultRepo) SaveUserAnswersBatch(answers []entity.UserAnswer) (int64, error) {
+	if len(answers) == 0 {
+		return 0, nil
+	}
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "quiz_id"}, {Name: "question_id"}},
+		DoNothing: true,
+	}).CreateInBatches(&answers, answerBatchSize)
+	return result.RowsAffected, result.Error
+}
+
+// VoidQuestionAnswers аннулирует ответы на вопрос викторины, снятый администратором с эфира
+func (r *ResultRepo) VoidQuestionAnswers(quizID, questionID uint) (int64, error) {
+	result := r.db.Model(&entity.UserAnswer{}).
+		Where("quiz_id = ? AND question_id = ?", quizID, questionID).
+		Updates(map[string]interface{}{
+			"score":              0,
+			"is_correct":         false,
+			"is_eliminated":      false,
+			"elimination_reason": entity.AnswerReasonQuestionVoided,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// ReviveEliminatedAnswer помечает выбивший пользователя ответ вторым шансом
+func (r *ResultRepo) ReviveEliminatedAnswer(quizID, userID uint) (int64, error) {
+	result := r.db.Model(&entity.UserAnswer{}).
+		Where("quiz_id = ? AND user_id = ? AND is_eliminated = true", quizID, userID).
+		Updates(map[string]interface{}{
+			"is_eliminated":      false,
+			"elimination_reason": entity.AnswerReasonSecondChance,
+		})
+	return result.RowsAffected, result.Error
+}
+
 // GetUserAnswers возвращает все ответы пользователя для конкретной викторины
 func (r *ResultRepo) GetUserAnswers(userID uint, quizID uint) ([]entity.UserAnswer, error) {
 	var answers []entity.UserAnswer
@@ -66,7 +114,7 @@ func (r *ResultRepo) GetQuizResults(quizID uint, limit, offset int) ([]entity.Re
 	// Затем получаем результаты для текущей страницы
 	// ИЗМЕНЕНИЕ: Сортируем по rank ASC после его расчета
 	err = tx.Where("quiz_id = ?", quizID).
-		Order("rank ASC, score DESC"). // Сортируем по рангу, затем по очкам для одинаковых рангов
+		Order("rank ASC, score DESC, id ASC"). // Сортируем по рангу, затем по очкам для одинаковых рангов
 		Limit(limit).
 		Offset(offset).
 		Find(&results).Error
@@ -83,6 +131,21 @@ func (r *ResultRepo) GetQuizResults(quizID uint, limit, offset int) ([]entity.Re
 	return results, total, nil
 }
 
+// GetQuizResultsAfter возвращает до limit результатов викторины, следующих за позицией after
+// в порядке GetQuizResults (keyset-пагинация: без OFFSET и без подсчёта общего количества).
+func (r *ResultRepo) GetQuizResultsAfter(quizID uint, after *repository.QuizResultCursor, limit int) ([]entity.Result, error) {
+	var results []entity.Result
+	query := r.db.Where("quiz_id = ?", quizID)
+	if after != nil {
+		query = query.Where("(rank > ? OR (rank = ? AND score < ?) OR (rank = ? AND score = ? AND id > ?))",
+			after.Rank, after.Rank, after.Score, after.Rank, after.Score, after.ID)
+	}
+	err := query.Order("rank ASC, score DESC, id ASC").
+		Limit(limit).
+		Find(&results).Error
+	return results, err
+}
+
 // GetAllQuizResults возвращает ВСЕ результаты для викторины, отсортированные по очкам.
 // Используется для внутренней логики, где нужна полная картина.
 // ИЗМЕНЕНИЕ: Теперь сортирует по rank ASC
@@ -122,24 +185,62 @@ func (r *ResultRepo) GetUserResults(userID uint, limit, offset int) ([]entity.Re
 
 	// Затем получаем результаты с пагинацией
 	err := r.db.Where("user_id = ?", userID).
-		Order("created_at DESC").
+		Order("created_at DESC, id DESC").
 		Limit(limit).
 		Offset(offset).
 		Find(&results).Error
 	return results, total, err
 }
 
+// GetUserResultsAfter возвращает до limit результатов пользователя, следующих за позицией after
+// в порядке GetUserResults (от новых к старым).
+func (r *ResultRepo) GetUserResultsAfter(userID uint, after *repository.UserResultCursor, limit int) ([]entity.Result, error) {
+	var results []entity.Result
+	query := r.db.Where("user_id = ?", userID)
+	if after != nil {
+		query = query.Where("(created_at, id) < (?, ?)", after.CreatedAt, after.ID)
+	}
+	err := query.Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&results).Error
+	return results, err
+}
+
+// GetUserBestWinStreak возвращает самую длинную серию побед пользователя подряд. Серии — «острова»
+// подряд идущих побед в порядке завершения викторин (разность номеров строк постоянна внутри серии).
+func (r *ResultRepo) GetUserBestWinStreak(userID uint) (int, error) {
+	var best int
+	err := r.db.Raw(`
+		SELECT COALESCE(MAX(streak), 0) FROM (
+			SELECT COUNT(*) AS streak FROM (
+				SELECT is_winner,
+					ROW_NUMBER() OVER (ORDER BY completed_at, id) -
+					ROW_NUMBER() OVER (PARTITION BY is_winner ORDER BY completed_at, id) AS grp
+				FROM results
+				WHERE user_id = ?
+			) ordered
+			WHERE is_winner
+			GROUP BY grp
+		) streaks`, userID).Scan(&best).Error
+	return best, err
+}
+
 // CalculateRanks вычисляет и сохраняет ранги всех участников викторины, используя SQL.
 // ВНИМАНИЕ: Эта функция больше НЕ определяет победителей и НЕ рассчитывает призы.
-// Она только вычисляет и сохраняет ранг, основываясь на 'score' и 'correct_answers'.
+// Она только вычисляет и сохраняет ранг, основываясь на 'score' и 'correct_answers'
+// (и 'total_response_time_ms' при разрешении ничьей по времени).
 // ИЗМЕНЕНО: Принимает транзакцию tx *gorm.DB
-func (r *ResultRepo) CalculateRanks(tx *gorm.DB, quizID uint) error {
+func (r *ResultRepo) CalculateRanks(tx *gorm.DB, quizID uint, tieBreak string) error {
+	order := "score DESC, correct_answers DESC"
+	if tieBreak == entity.TieBreakResponseTime {
+		order += ", total_response_time_ms ASC"
+	}
 	// Используем сырой SQL-запрос для эффективности
 	sql := `
 	WITH RankedResults AS (
 	    SELECT
 	        id,
-	        RANK() OVER (ORDER BY score DESC, correct_answers DESC) as calculated_rank
+	        RANK() OVER (ORDER BY ` + order + `) as calculated_rank
 	    FROM results
 	    WHERE quiz_id = ?
 	)
@@ -178,22 +279,56 @@ func (r *ResultRepo) GetQuizWinners(quizID uint) ([]entity.Result, error) {
 // FindAndUpdateWinners находит победителей, рассчитывает приз и обновляет их статус в БД ВНУТРИ ПЕРЕДАННОЙ ТРАНЗАКЦИИ.
 // ИЗМЕНЕНО: Принимает транзакцию tx *gorm.DB.
 // ИЗМЕНЕНО: Возвращает слайс ID победителей, приз на человека и ошибку.
-func (r *ResultRepo) FindAndUpdateWinners(tx *gorm.DB, quizID uint, questionCount int, totalPrizeFund int) ([]uint, int, error) {
+func (r *ResultRepo) FindAndUpdateWinners(tx *gorm.DB, quizID uint, criteria repository.WinnerCriteria, totalPrizeFund int) ([]uint, int, error) {
 	var winnerCount int64
 	prizePerWinner := 0
 	winnerIDs := []uint{} // Инициализируем слайс
 
 	// Шаг 1: Найти ID победителей В ПЕРЕДАННОЙ ТРАНЗАКЦИИ
-	// Ищем ID победителей (правильные ответы == кол-ву вопросов и не выбыли)
 	// Используем Pluck для получения только user_id
-	if err := tx.Model(&entity.Result{}).
-		Where("quiz_id = ? AND correct_answers = ? AND is_eliminated = false", quizID, questionCount).
-		Pluck("user_id", &winnerIDs).Error; err != nil { // Получаем user_id, а не id результата
+	query := tx.Model(&entity.Result{}).Where("quiz_id = ?", quizID)
+	switch criteria.GameMode {
+	case entity.GameModePointsOnly:
+		// Без выбывания побеждают лучшие по очкам (ранги уже пересчитаны в этой транзакции)
+		query = query.Where("rank = 1 AND score > 0")
+	case entity.GameModeLives:
+		// Победители — все, у кого остались жизни
+		query = query.Where("is_eliminated = false AND correct_answers > 0")
+	default:
+		// Победители ответили правильно на все вопросы и не выбыли
+		query = query.Where("correct_answers = ? AND is_eliminated = false", criteria.QuestionCount)
+	}
+	if err := query.Pluck("user_id", &winnerIDs).Error; err != nil { // Получаем user_id, а не id результата
 		log.Printf("Error finding winner user IDs for quiz %d within transaction: %v", quizID, err)
 		// Не откатываем, транзакция управляется извне
 		return nil, 0, err
 	}
 
+	// Шаг 1б: Разрешение ничьей — из отобранных остаются лучшие по числу верных ответов,
+	// а среди них — с наименьшим суммарным временем верных ответов
+	if criteria.TieBreak == entity.TieBreakResponseTime && len(winnerIDs) > 1 {
+		var best entity.Result
+		if err := tx.Model(&entity.Result{}).
+			Select("correct_answers, total_response_time_ms").
+			Where("quiz_id = ? AND user_id IN ?", quizID, winnerIDs).
+			Order("correct_answers DESC, total_response_time_ms ASC").
+			Limit(1).
+			Take(&best).Error; err != nil {
+			log.Printf("Error applying tie-break for quiz %d within transaction: %v", quizID, err)
+			return nil, 0, err
+		}
+		var tiedIDs []uint
+		if err := tx.Model(&entity.Result{}).
+			Where("quiz_id = ? AND user_id IN ? AND correct_answers = ? AND total_response_time_ms = ?",
+				quizID, winnerIDs, best.CorrectAnswers, best.TotalResponseTimeMs).
+			Pluck("user_id", &tiedIDs).Error; err != nil {
+			log.Printf("Error applying tie-break for quiz %d within transaction: %v", quizID, err)
+			return nil, 0, err
+		}
+		log.Printf("[ResultRepo] FindAndUpdateWinners: Quiz %d, tie-break by response time: %d of %d candidates remain", quizID, len(tiedIDs), len(winnerIDs))
+		winnerIDs = tiedIDs
+	}
+
 	winnerCount = int64(len(winnerIDs))
 
 	// Шаг 2: Рассчитать приз
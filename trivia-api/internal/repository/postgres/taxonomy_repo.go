@@ -0,0 +1,239 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// TaxonomyRepo реализует repository.TaxonomyRepository
+type TaxonomyRepo struct {
+	db *gorm.DB
+}
+
+// NewTaxonomyRepo создает новый экземпляр
+func NewTaxonomyRepo(db *gorm.DB) *TaxonomyRepo {
+	return &TaxonomyRepo{db: db}
+}
+
+// CreateCategory создаёт категорию
+func (r *TaxonomyRepo) CreateCategory(category *entity.Category) error {
+	if err := r.db.Create(category).Error; err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("%w: category %s already exists", apperrors.ErrConflict, category.Slug)
+		}
+		return fmt.Errorf("failed to create category: %w", err)
+	}
+	return nil
+}
+
+// UpdateCategory сохраняет изменённую категорию
+func (r *TaxonomyRepo) UpdateCategory(category *entity.Category) error {
+	if err := r.db.Save(category).Error; err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("%w: category %s already exists", apperrors.ErrConflict, category.Slug)
+		}
+		return fmt.Errorf("failed to update category: %w", err)
+	}
+	return nil
+}
+
+// DeleteCategory удаляет категорию; викторины и вопросы остаются без категории (ON DELETE SET NULL)
+func (r *TaxonomyRepo) DeleteCategory(id uint) error {
+	result := r.db.Delete(&entity.Category{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete category: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
+
+// GetCategoryByID возвращает категорию по ID
+func (r *TaxonomyRepo) GetCategoryByID(id uint) (*entity.Category, error) {
+	var category entity.Category
+	err := r.db.First(&category, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+	return &category, nil
+}
+
+// GetCategoryBySlug возвращает категорию по slug
+func (r *TaxonomyRepo) GetCategoryBySlug(slug string) (*entity.Category, error) {
+	var category entity.Category
+	err := r.db.Where("slug = ?", slug).First(&category).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category %s: %w", slug, err)
+	}
+	return &category, nil
+}
+
+// ListCategories возвращает все категории по названию
+func (r *TaxonomyRepo) ListCategories() ([]entity.Category, error) {
+	var categories []entity.Category
+	if err := r.db.Order("name ASC, id ASC").Find(&categories).Error; err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	return categories, nil
+}
+
+// CreateTag создаёт тег
+func (r *TaxonomyRepo) CreateTag(tag *entity.Tag) error {
+	if err := r.db.Create(tag).Error; err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("%w: tag %s already exists", apperrors.ErrConflict, tag.Slug)
+		}
+		return fmt.Errorf("failed to create tag: %w", err)
+	}
+	return nil
+}
+
+// UpdateTag сохраняет изменённый тег
+func (r *TaxonomyRepo) UpdateTag(tag *entity.Tag) error {
+	if err := r.db.Save(tag).Error; err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("%w: tag %s already exists", apperrors.ErrConflict, tag.Slug)
+		}
+		return fmt.Errorf("failed to update tag: %w", err)
+	}
+	return nil
+}
+
+// DeleteTag удаляет тег вместе с его привязками к викторинам и вопросам
+func (r *TaxonomyRepo) DeleteTag(id uint) error {
+	result := r.db.Delete(&entity.Tag{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete tag: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
+
+// GetTagByID возвращает тег по ID
+func (r *TaxonomyRepo) GetTagByID(id uint) (*entity.Tag, error) {
+	var tag entity.Tag
+	err := r.db.First(&tag, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag: %w", err)
+	}
+	return &tag, nil
+}
+
+// ListTags возвращает все теги по названию
+func (r *TaxonomyRepo) ListTags() ([]entity.Tag, error) {
+	var tags []entity.Tag
+	if err := r.db.Order("name ASC, id ASC").Find(&tags).Error; err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	return tags, nil
+}
+
+// GetTagsBySlugs возвращает теги с указанными slug
+func (r *TaxonomyRepo) GetTagsBySlugs(slugs []string) ([]entity.Tag, error) {
+	var tags []entity.Tag
+	if len(slugs) == 0 {
+		return tags, nil
+	}
+	if err := r.db.Where("slug IN ?", slugs).Order("name ASC, id ASC").Find(&tags).Error; err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+	return tags, nil
+}
+
+// SetQuizTaxonomy заменяет категорию и теги викторины
+func (r *TaxonomyRepo) SetQuizTaxonomy(quizID uint, categoryID *uint, tags []entity.Tag) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.Quiz{}).Where("id = ?", quizID).Update("category_id", categoryID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return apperrors.ErrNotFound
+		}
+		return replaceTags(tx.Model(&entity.Quiz{ID: quizID}).Association("Tags"), tags)
+	})
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return err
+		}
+		return fmt.Errorf("failed to set quiz taxonomy: %w", err)
+	}
+	return nil
+}
+
+// SetQuestionTaxonomy заменяет категорию и теги вопроса
+func (r *TaxonomyRepo) SetQuestionTaxonomy(questionID uint, categoryID *uint, tags []entity.Tag) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.Question{}).Where("id = ?", questionID).Update("category_id", categoryID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return apperrors.ErrNotFound
+		}
+		return replaceTags(tx.Model(&entity.Question{ID: questionID}).Association("Tags"), tags)
+	})
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return err
+		}
+		return fmt.Errorf("failed to set question taxonomy: %w", err)
+	}
+	return nil
+}
+
+// replaceTags заменяет строки связующей таблицы; пустой набор снимает все теги
+func replaceTags(association *gorm.Association, tags []entity.Tag) error {
+	if len(tags) == 0 {
+		return association.Clear()
+	}
+	return association.Replace(tags)
+}
+
+// GetCategoryLeaderboard ранжирует участников завершённых викторин категории
+func (r *TaxonomyRepo) GetCategoryLeaderboard(categoryID uint, limit, offset int) ([]entity.CategoryLeaderboardEntry, int64, error) {
+	base := func() *gorm.DB {
+		return r.db.Table("results r").
+			Joins("JOIN quizzes q ON q.id = r.quiz_id").
+			Joins("JOIN users u ON u.id = r.user_id").
+			Where("q.category_id = ? AND q.status = ? AND u.deleted_at IS NULL", categoryID, entity.QuizStatusCompleted)
+	}
+
+	var total int64
+	if err := base().Distinct("r.user_id").Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count category leaderboard: %w", err)
+	}
+
+	var entries []entity.CategoryLeaderboardEntry
+	err := base().
+		Select(`r.user_id, u.username, u.profile_picture, u.show_on_leaderboards,
+			COUNT(DISTINCT r.quiz_id) AS quizzes_played,
+			COALESCE(SUM(r.score), 0) AS total_score,
+			COUNT(*) FILTER (WHERE r.is_winner) AS wins_count,
+			COALESCE(SUM(r.prize_fund) FILTER (WHERE r.is_winner), 0) AS total_prize_won`).
+		Group("r.user_id, u.username, u.profile_picture, u.show_on_leaderboards").
+		Order("wins_count DESC, total_score DESC, r.user_id ASC").
+		Limit(limit).
+		Offset(offset).
+		Scan(&entries).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get category leaderboard: %w", err)
+	}
+	return entries, total, nil
+}
@@ -0,0 +1,152 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UserFollowRepo реализует repository.UserFollowRepository
+type UserFollowRepo struct {
+	db *gorm.DB
+}
+
+// NewUserFollowRepo создает новый экземпляр
+func NewUserFollowRepo(db *gorm.DB) *UserFollowRepo {
+	return &UserFollowRepo{db: db}
+}
+
+// Create подписывает пользователя на игрока; повторная подписка игнорируется
+func (r *UserFollowRepo) Create(follow *entity.UserFollow) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(follow)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to create user follow: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Delete отменяет подписку
+func (r *UserFollowRepo) Delete(followerID, followeeID uint) (bool, error) {
+	result := r.db.Where("follower_id = ? AND followee_id = ?", followerID, followeeID).Delete(&entity.UserFollow{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete user follow: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Exists проверяет, подписан ли пользователь на игрока
+func (r *UserFollowRepo) Exists(followerID, followeeID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&entity.UserFollow{}).
+		Where("follower_id = ? AND followee_id = ?", followerID, followeeID).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check user follow: %w", err)
+	}
+	return count > 0, nil
+}
+
+// CountFollowing считает подписки пользователя
+func (r *UserFollowRepo) CountFollowing(followerID uint) (int64, error) {
+	var count int64
+	if err := r.db.Model(&entity.UserFollow{}).Where("follower_id = ?", followerID).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count following: %w", err)
+	}
+	return count, nil
+}
+
+// CountFollowers считает подписчиков игрока
+func (r *UserFollowRepo) CountFollowers(followeeID uint) (int64, error) {
+	var count int64
+	if err := r.db.Model(&entity.UserFollow{}).Where("followee_id = ?", followeeID).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count followers: %w", err)
+	}
+	return count, nil
+}
+
+// CountCreatedSince считает подписки пользователя, оформленные после since
+func (r *UserFollowRepo) CountCreatedSince(followerID uint, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.UserFollow{}).
+		Where("follower_id = ? AND created_at > ?", followerID, since).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count recent follows: %w", err)
+	}
+	return count, nil
+}
+
+// ListFollowing возвращает подписки пользователя с признаком взаимности
+func (r *UserFollowRepo) ListFollowing(followerID uint, limit, offset int) ([]repository.FollowListItem, int64, error) {
+	return r.list("followee_id", "follower_id", followerID, limit, offset)
+}
+
+// ListFollowers возвращает подписчиков игрока с признаком взаимности
+func (r *UserFollowRepo) ListFollowers(followeeID uint, limit, offset int) ([]repository.FollowListItem, int64, error) {
+	return r.list("follower_id", "followee_id", followeeID, limit, offset)
+}
+
+// list возвращает игроков из колонки otherColumn подписок, где ownerColumn = userID.
+// Взаимность — наличие встречной подписки.
+func (r *UserFollowRepo) list(otherColumn, ownerColumn string, userID uint, limit, offset int) ([]repository.FollowListItem, int64, error) {
+	var total int64
+	if err := r.db.Model(&entity.UserFollow{}).Where(ownerColumn+" = ?", userID).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count follows: %w", err)
+	}
+
+	items := make([]repository.FollowListItem, 0)
+	err := r.db.Table("user_follows AS f").
+		Select("u.id AS user_id, u.username, u.profile_picture, f.created_at AS followed_at, "+
+			"EXISTS (SELECT 1 FROM user_follows b WHERE b.follower_id = f."+otherColumn+" AND b.followee_id = f."+ownerColumn+") AS mutual").
+		Joins("JOIN users u ON u.id = f."+otherColumn).
+		Where("f."+ownerColumn+" = ?", userID).
+		Order("f.created_at DESC, u.id DESC").
+		Limit(limit).
+		Offset(offset).
+		Scan(&items).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list follows: %w", err)
+	}
+	return items, total, nil
+}
+
+// ListFollowerIDs возвращает ID всех подписчиков игрока
+func (r *UserFollowRepo) ListFollowerIDs(followeeID uint) ([]uint, error) {
+	var userIDs []uint
+	err := r.db.Model(&entity.UserFollow{}).
+		Where("followee_id = ?", followeeID).
+		Pluck("follower_id", &userIDs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list follower ids: %w", err)
+	}
+	return userIDs, nil
+}
+
+// GetFriendsLeaderboard возвращает лидерборд из пользователя и его подписок
+func (r *UserFollowRepo) GetFriendsLeaderboard(userID uint, limit, offset int) ([]entity.User, int64, error) {
+	query := func() *gorm.DB {
+		return r.db.Model(&entity.User{}).
+			Where("deleted_at IS NULL").
+			Where("id = ? OR id IN (?)", userID,
+				r.db.Model(&entity.UserFollow{}).Select("followee_id").Where("follower_id = ?", userID))
+	}
+
+	var total int64
+	if err := query().Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count friends leaderboard: %w", err)
+	}
+	var users []entity.User
+	err := query().
+		Order("wins_count DESC, total_prize_won DESC, id ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&users).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get friends leaderboard: %w", err)
+	}
+	return users, total, nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
 	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
 	"gorm.io/gorm"
 )
@@ -56,3 +57,32 @@ func (r *UserLegalAcceptanceRepo) DeleteByUserID(userID uint) error {
 	}
 	return nil
 }
+
+// CountLatestByVersion группирует неудалённых пользователей по версиям их последнего согласия
+func (r *UserLegalAcceptanceRepo) CountLatestByVersion() ([]repository.LegalVersionCount, error) {
+	var counts []repository.LegalVersionCount
+	err := r.db.Raw(`
+		SELECT latest.tos_version, latest.privacy_version, COUNT(*) AS users
+		FROM (
+			SELECT DISTINCT ON (user_id) user_id, tos_version, privacy_version
+			FROM user_legal_acceptances
+			ORDER BY user_id, accepted_at DESC, id DESC
+		) latest
+		JOIN users u ON u.id = latest.user_id AND u.deleted_at IS NULL
+		GROUP BY latest.tos_version, latest.privacy_version
+		ORDER BY users DESC, latest.tos_version DESC, latest.privacy_version DESC`).
+		Scan(&counts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count legal acceptances by version: %w", err)
+	}
+	return counts, nil
+}
+
+// CountUsers возвращает число неудалённых пользователей
+func (r *UserLegalAcceptanceRepo) CountUsers() (int64, error) {
+	var count int64
+	if err := r.db.Model(&entity.User{}).Where("deleted_at IS NULL").Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count users: %w", err)
+	}
+	return count, nil
+}
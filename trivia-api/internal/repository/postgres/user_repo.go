@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"context"
 	"errors"
 	"log"
 	"time"
@@ -9,6 +10,7 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
 	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
 )
 
@@ -22,11 +24,30 @@ func NewUserRepo(db *gorm.DB) *UserRepo {
 	return &UserRepo{db: db}
 }
 
+// WithContext возвращает копию репозитория, выполняющую запросы с контекстом ctx
+func (r *UserRepo) WithContext(ctx context.Context) repository.UserRepository {
+	return &UserRepo{db: r.db.WithContext(ctx)}
+}
+
 // Create создает нового пользователя
 func (r *UserRepo) Create(user *entity.User) error {
 	return r.db.Create(user).Error
 }
 
+// CreateWithEvent создаёт пользователя и событие outbox в одной транзакции
+func (r *UserRepo) CreateWithEvent(user *entity.User, event func(user *entity.User) (entity.OutboxEvent, error)) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		outboxEvent, err := event(user)
+		if err != nil {
+			return err
+		}
+		return tx.Create(&outboxEvent).Error
+	})
+}
+
 // GetByID возвращает пользователя по ID
 func (r *UserRepo) GetByID(id uint) (*entity.User, error) {
 	var user entity.User
@@ -40,6 +61,16 @@ func (r *UserRepo) GetByID(id uint) (*entity.User, error) {
 	return &user, nil
 }
 
+// GetByIDs возвращает пользователей с указанными ID
+func (r *UserRepo) GetByIDs(ids []uint) ([]entity.User, error) {
+	var users []entity.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+	err := r.db.Where("id IN ?", ids).Find(&users).Error
+	return users, err
+}
+
 // GetByEmail возвращает пользователя по email
 func (r *UserRepo) GetByEmail(email string) (*entity.User, error) {
 	var user entity.User
@@ -187,7 +218,7 @@ func (r *UserRepo) GetLeaderboard(limit, offset int) ([]entity.User, int64, erro
 	err = tx.Order("wins_count DESC, total_prize_won DESC, id ASC").
 		Limit(limit).
 		Offset(offset).
-		Select("id", "username", "profile_picture", "wins_count", "total_prize_won"). // Выбираем только нужные поля
+		Select("id", "username", "profile_picture", "wins_count", "total_prize_won", "show_on_leaderboards"). // Выбираем только нужные поля
 		Find(&users).Error
 	if err != nil {
 		tx.Rollback()
@@ -201,3 +232,19 @@ func (r *UserRepo) GetLeaderboard(limit, offset int) ([]entity.User, int64, erro
 
 	return users, total, nil
 }
+
+// GetLeaderboardAfter возвращает до limit пользователей лидерборда, следующих за позицией after
+// в порядке GetLeaderboard (keyset-пагинация: без OFFSET и без подсчёта общего количества).
+func (r *UserRepo) GetLeaderboardAfter(after *repository.LeaderboardCursor, limit int) ([]entity.User, error) {
+	var users []entity.User
+	query := r.db.Model(&entity.User{})
+	if after != nil {
+		query = query.Where("(wins_count < ? OR (wins_count = ? AND total_prize_won < ?) OR (wins_count = ? AND total_prize_won = ? AND id > ?))",
+			after.WinsCount, after.WinsCount, after.TotalPrizeWon, after.WinsCount, after.TotalPrizeWon, after.ID)
+	}
+	err := query.Order("wins_count DESC, total_prize_won DESC, id ASC").
+		Limit(limit).
+		Select("id", "username", "profile_picture", "wins_count", "total_prize_won", "show_on_leaderboards").
+		Find(&users).Error
+	return users, err
+}
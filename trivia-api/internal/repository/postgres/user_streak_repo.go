@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"gorm.io/gorm"
+)
+
+// UserStreakRepo реализует repository.UserStreakRepository
+type UserStreakRepo struct {
+	db *gorm.DB
+}
+
+// NewUserStreakRepo создает новый экземпляр
+func NewUserStreakRepo(db *gorm.DB) *UserStreakRepo {
+	return &UserStreakRepo{db: db}
+}
+
+// GetByUserID возвращает серии пользователя; без записи — нулевые серии
+func (r *UserStreakRepo) GetByUserID(userID uint) (*entity.UserStreak, error) {
+	streak := entity.UserStreak{UserID: userID}
+	if err := r.db.Where("user_id = ?", userID).Limit(1).Find(&streak).Error; err != nil {
+		return nil, fmt.Errorf("failed to get user streaks: %w", err)
+	}
+	return &streak, nil
+}
+
+// GetByUserIDs возвращает серии пользователей по ID
+func (r *UserStreakRepo) GetByUserIDs(userIDs []uint) (map[uint]entity.UserStreak, error) {
+	result := make(map[uint]entity.UserStreak, len(userIDs))
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+	var streaks []entity.UserStreak
+	if err := r.db.Where("user_id IN ?", userIDs).Find(&streaks).Error; err != nil {
+		return nil, fmt.Errorf("failed to get user streaks: %w", err)
+	}
+	for _, s := range streaks {
+		result[s.UserID] = s
+	}
+	return result, nil
+}
@@ -0,0 +1,248 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WalletRepo реализует repository.WalletRepository
+type WalletRepo struct {
+	db *gorm.DB
+}
+
+// NewWalletRepo создает новый экземпляр
+func NewWalletRepo(db *gorm.DB) *WalletRepo {
+	return &WalletRepo{db: db}
+}
+
+// GetOrCreateUserAccount возвращает кошелёк пользователя, создавая его при необходимости
+func (r *WalletRepo) GetOrCreateUserAccount(userID uint) (*entity.WalletAccount, error) {
+	account := entity.WalletAccount{UserID: &userID, Type: entity.WalletAccountTypeUser}
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&account).Error; err != nil {
+		return nil, fmt.Errorf("failed to create wallet account: %w", err)
+	}
+
+	var existing entity.WalletAccount
+	if err := r.db.Where("user_id = ?", userID).First(&existing).Error; err != nil {
+		return nil, fmt.Errorf("failed to get wallet account: %w", err)
+	}
+	return &existing, nil
+}
+
+// GetSystemAccount возвращает системный счёт по коду
+func (r *WalletRepo) GetSystemAccount(code string) (*entity.WalletAccount, error) {
+	var account entity.WalletAccount
+	if err := r.db.Where("code = ?", code).First(&account).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get system account %s: %w", code, err)
+	}
+	return &account, nil
+}
+
+// PostTransaction атомарно сохраняет транзакцию с проводками и обновляет балансы счетов
+func (r *WalletRepo) PostTransaction(txn *entity.LedgerTransaction) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return postLedgerTransaction(tx, txn)
+	})
+}
+
+// postLedgerTransaction проводит транзакцию внутри уже открытой транзакции БД
+func postLedgerTransaction(tx *gorm.DB, txn *entity.LedgerTransaction) error {
+	if !txn.IsBalanced() {
+		return repository.ErrUnbalancedTransaction
+	}
+
+	entries := txn.Entries
+	if err := tx.Omit("Entries").Create(txn).Error; err != nil {
+		if isUniqueViolation(err) {
+			return repository.ErrLedgerTransactionExists
+		}
+		return fmt.Errorf("failed to create ledger transaction: %w", err)
+	}
+
+	// Обновляем балансы в порядке возрастания ID счёта, чтобы параллельные проводки не взаимоблокировались
+	ordered := make([]entity.LedgerEntry, len(entries))
+	copy(ordered, entries)
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].AccountID < ordered[j].AccountID })
+	for _, e := range ordered {
+		result := tx.Model(&entity.WalletAccount{}).
+			Where("id = ? AND (type <> ? OR balance + ? >= 0)", e.AccountID, entity.WalletAccountTypeUser, e.Amount).
+			Updates(map[string]interface{}{
+				"balance":    gorm.Expr("balance + ?", e.Amount),
+				"updated_at": time.Now(),
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to update wallet balance: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return repository.ErrInsufficientFunds
+		}
+	}
+
+	for i := range entries {
+		entries[i].TransactionID = txn.ID
+	}
+	if err := tx.Create(&entries).Error; err != nil {
+		return fmt.Errorf("failed to create ledger entries: %w", err)
+	}
+	txn.Entries = entries
+	return nil
+}
+
+// ListEntriesByAccount возвращает проводки по счёту (новые первыми) и общее количество
+func (r *WalletRepo) ListEntriesByAccount(accountID uint, limit, offset int) ([]entity.LedgerEntry, int64, error) {
+	query := r.db.Model(&entity.LedgerEntry{}).Where("account_id = ?", accountID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count ledger entries: %w", err)
+	}
+
+	var entries []entity.LedgerEntry
+	if err := query.Preload("Transaction").
+		Order("created_at DESC, id DESC").
+		Limit(limit).Offset(offset).
+		Find(&entries).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list ledger entries: %w", err)
+	}
+	return entries, total, nil
+}
+
+// CreatePayoutRequest атомарно создаёт заявку и резервирует средства проводкой hold
+func (r *WalletRepo) CreatePayoutRequest(payout *entity.PayoutRequest, hold *entity.LedgerTransaction) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(payout).Error; err != nil {
+			return fmt.Errorf("failed to create payout request: %w", err)
+		}
+		hold.Reference = fmt.Sprintf("payout:%d", payout.ID)
+		return postLedgerTransaction(tx, hold)
+	})
+}
+
+// GetPayoutRequest возвращает заявку по ID
+func (r *WalletRepo) GetPayoutRequest(id uint) (*entity.PayoutRequest, error) {
+	var payout entity.PayoutRequest
+	if err := r.db.First(&payout, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get payout request: %w", err)
+	}
+	return &payout, nil
+}
+
+// ListPayoutRequests возвращает заявки по фильтрам и общее количество
+func (r *WalletRepo) ListPayoutRequests(filters repository.PayoutFilters, limit, offset int) ([]entity.PayoutRequest, int64, error) {
+	query := r.db.Model(&entity.PayoutRequest{})
+	if filters.UserID != nil {
+		query = query.Where("user_id = ?", *filters.UserID)
+	}
+	if filters.Status != "" {
+		query = query.Where("status = ?", filters.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count payout requests: %w", err)
+	}
+
+	var payouts []entity.PayoutRequest
+	if err := query.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&payouts).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list payout requests: %w", err)
+	}
+	return payouts, total, nil
+}
+
+// SumPayouts возвращает сумму заявок пользователя в указанных статусах
+func (r *WalletRepo) SumPayouts(userID uint, statuses []string) (int64, error) {
+	var total int64
+	if err := r.db.Model(&entity.PayoutRequest{}).
+		Where("user_id = ? AND status IN ?", userID, statuses).
+		Select("COALESCE(SUM(amount), 0)").Scan(&total).Error; err != nil {
+		return 0, fmt.Errorf("failed to sum payout requests: %w", err)
+	}
+	return total, nil
+}
+
+// TransitionPayout атомарно переводит заявку из fromStatus в payout.Status и проводит связанную транзакцию
+func (r *WalletRepo) TransitionPayout(payout *entity.PayoutRequest, fromStatus string, posting *entity.LedgerTransaction) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		payout.UpdatedAt = time.Now()
+		result := tx.Model(&entity.PayoutRequest{}).
+			Where("id = ? AND status = ?", payout.ID, fromStatus).
+			Updates(map[string]interface{}{
+				"status":             payout.Status,
+				"admin_id":           payout.AdminID,
+				"reject_reason":      payout.RejectReason,
+				"external_reference": payout.ExternalReference,
+				"approved_at":        payout.ApprovedAt,
+				"paid_at":            payout.PaidAt,
+				"updated_at":         payout.UpdatedAt,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to update payout request: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("%w: payout request is no longer %s", apperrors.ErrConflict, fromStatus)
+		}
+
+		if posting != nil {
+			return postLedgerTransaction(tx, posting)
+		}
+		return nil
+	})
+}
+
+// Reconcile выполняет сверку журнала проводок и балансов
+func (r *WalletRepo) Reconcile() (*repository.LedgerReconciliation, error) {
+	var report repository.LedgerReconciliation
+
+	if err := r.db.Model(&entity.WalletAccount{}).
+		Select("COALESCE(SUM(balance), 0)").Scan(&report.TotalBalance).Error; err != nil {
+		return nil, fmt.Errorf("failed to sum wallet balances: %w", err)
+	}
+	if err := r.db.Raw(`
+		SELECT COUNT(*) FROM (
+			SELECT transaction_id FROM ledger_entries GROUP BY transaction_id HAVING SUM(amount) <> 0
+		) t`).Scan(&report.UnbalancedTransactions).Error; err != nil {
+		return nil, fmt.Errorf("failed to check ledger transactions: %w", err)
+	}
+	if err := r.db.Raw(`
+		SELECT COUNT(*) FROM wallet_accounts a
+		LEFT JOIN (SELECT account_id, SUM(amount) AS total FROM ledger_entries GROUP BY account_id) e ON e.account_id = a.id
+		WHERE a.balance <> COALESCE(e.total, 0)`).Scan(&report.MismatchedAccounts).Error; err != nil {
+		return nil, fmt.Errorf("failed to check wallet balances: %w", err)
+	}
+	if err := r.db.Model(&entity.WalletAccount{}).Where("type = ?", entity.WalletAccountTypeUser).
+		Select("COALESCE(SUM(balance), 0)").Scan(&report.UserBalances).Error; err != nil {
+		return nil, fmt.Errorf("failed to sum user balances: %w", err)
+	}
+
+	systemBalances := map[string]*int64{
+		entity.SystemAccountPayoutsPending: &report.PendingPayouts,
+		entity.SystemAccountPayoutsSettled: &report.SettledPayouts,
+		entity.SystemAccountPrizeExpense:   &report.PrizesIssued,
+	}
+	for code, target := range systemBalances {
+		account, err := r.GetSystemAccount(code)
+		if err != nil {
+			return nil, err
+		}
+		*target = account.Balance
+	}
+	// Счёт призовых расходов уменьшается при каждом начислении, поэтому выданные призы — его баланс со знаком минус
+	report.PrizesIssued = -report.PrizesIssued
+
+	report.Balanced = report.TotalBalance == 0 && report.UnbalancedTransactions == 0 && report.MismatchedAccounts == 0
+	return &report, nil
+}
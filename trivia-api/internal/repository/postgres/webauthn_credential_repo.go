@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// WebAuthnCredentialRepo реализует repository.WebAuthnCredentialRepository
+type WebAuthnCredentialRepo struct {
+	db *gorm.DB
+}
+
+// NewWebAuthnCredentialRepo создает репозиторий ключей доступа
+func NewWebAuthnCredentialRepo(db *gorm.DB) *WebAuthnCredentialRepo {
+	return &WebAuthnCredentialRepo{db: db}
+}
+
+func (r *WebAuthnCredentialRepo) Create(credential *entity.WebAuthnCredential) error {
+	if err := r.db.Create(credential).Error; err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("%w: passkey is already registered", apperrors.ErrConflict)
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *WebAuthnCredentialRepo) GetByCredentialID(credentialID []byte) (*entity.WebAuthnCredential, error) {
+	var credential entity.WebAuthnCredential
+	if err := r.db.Where("credential_id = ?", credentialID).First(&credential).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get passkey: %w", err)
+	}
+	return &credential, nil
+}
+
+func (r *WebAuthnCredentialRepo) ListByUserID(userID uint) ([]entity.WebAuthnCredential, error) {
+	var credentials []entity.WebAuthnCredential
+	err := r.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&credentials).Error
+	return credentials, err
+}
+
+func (r *WebAuthnCredentialRepo) CountByUserID(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.WebAuthnCredential{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+func (r *WebAuthnCredentialRepo) UpdateAfterLogin(id uint, credential entity.JSONPayload, usedAt time.Time) error {
+	return r.db.Model(&entity.WebAuthnCredential{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"credential": credential, "last_used_at": usedAt}).Error
+}
+
+func (r *WebAuthnCredentialRepo) Delete(userID, id uint) error {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&entity.WebAuthnCredential{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
+
+func (r *WebAuthnCredentialRepo) DeleteByUserID(userID uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&entity.WebAuthnCredential{}).Error
+}
@@ -0,0 +1,147 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// WebhookRepo реализует repository.WebhookRepository
+type WebhookRepo struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepo создает новый экземпляр
+func NewWebhookRepo(db *gorm.DB) *WebhookRepo {
+	return &WebhookRepo{db: db}
+}
+
+// CreateEndpoint регистрирует адрес
+func (r *WebhookRepo) CreateEndpoint(endpoint *entity.WebhookEndpoint) error {
+	if err := r.db.Create(endpoint).Error; err != nil {
+		return fmt.Errorf("failed to create webhook endpoint: %w", err)
+	}
+	return nil
+}
+
+// GetEndpoint возвращает адрес по ID
+func (r *WebhookRepo) GetEndpoint(id uint) (*entity.WebhookEndpoint, error) {
+	var endpoint entity.WebhookEndpoint
+	err := r.db.First(&endpoint, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook endpoint %d: %w", id, err)
+	}
+	return &endpoint, nil
+}
+
+// ListEndpoints возвращает все адреса
+func (r *WebhookRepo) ListEndpoints(activeOnly bool) ([]entity.WebhookEndpoint, error) {
+	var endpoints []entity.WebhookEndpoint
+	query := r.db.Order("id ASC")
+	if activeOnly {
+		query = query.Where("active = ?", true)
+	}
+	if err := query.Find(&endpoints).Error; err != nil {
+		return nil, fmt.Errorf("failed to list webhook endpoints: %w", err)
+	}
+	return endpoints, nil
+}
+
+// UpdateEndpoint сохраняет изменённый адрес
+func (r *WebhookRepo) UpdateEndpoint(endpoint *entity.WebhookEndpoint) error {
+	if err := r.db.Save(endpoint).Error; err != nil {
+		return fmt.Errorf("failed to update webhook endpoint: %w", err)
+	}
+	return nil
+}
+
+// DeleteEndpoint удаляет адрес; журнал доставки удаляется каскадом
+func (r *WebhookRepo) DeleteEndpoint(id uint) error {
+	result := r.db.Delete(&entity.WebhookEndpoint{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete webhook endpoint: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
+
+// CreateDeliveries добавляет доставки события
+func (r *WebhookRepo) CreateDeliveries(deliveries []entity.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	if err := r.db.Create(&deliveries).Error; err != nil {
+		return fmt.Errorf("failed to create webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+// ClaimDueDeliveries забирает доставки, срок которых наступил. SKIP LOCKED не даёт двум
+// инстансам забрать одну строку, а сдвиг next_attempt_at — повторно забрать её до конца попытки.
+func (r *WebhookRepo) ClaimDueDeliveries(now time.Time, lease time.Duration, limit int) ([]entity.WebhookDelivery, error) {
+	var deliveries []entity.WebhookDelivery
+	err := r.db.Raw(`
+		UPDATE webhook_deliveries SET next_attempt_at = @lease_until, updated_at = @now
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = @pending AND next_attempt_at <= @now
+			ORDER BY next_attempt_at
+			LIMIT @limit
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		map[string]interface{}{
+			"now":         now,
+			"lease_until": now.Add(lease),
+			"pending":     entity.WebhookDeliveryPending,
+			"limit":       limit,
+		}).Scan(&deliveries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// UpdateDelivery сохраняет результат попытки доставки
+func (r *WebhookRepo) UpdateDelivery(delivery *entity.WebhookDelivery) error {
+	if err := r.db.Save(delivery).Error; err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// ListDeliveries возвращает доставки адреса (новые первыми) и общее количество
+func (r *WebhookRepo) ListDeliveries(endpointID uint, status string, limit, offset int) ([]entity.WebhookDelivery, int64, error) {
+	query := r.db.Model(&entity.WebhookDelivery{}).Where("endpoint_id = ?", endpointID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count webhook deliveries: %w", err)
+	}
+	var deliveries []entity.WebhookDelivery
+	if err := query.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&deliveries).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	return deliveries, total, nil
+}
+
+// DeleteDeliveriesBefore удаляет старые записи журнала доставки
+func (r *WebhookRepo) DeleteDeliveriesBefore(before time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", before).Delete(&entity.WebhookDelivery{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete old webhook deliveries: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
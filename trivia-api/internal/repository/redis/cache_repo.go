@@ -9,6 +9,7 @@ import (
 
 	"github.com/go-redis/redis/v8"
 
+	"github.com/yourusername/trivia-api/internal/domain/repository"
 	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
 )
 
@@ -30,6 +31,11 @@ func NewCacheRepo(client redis.UniversalClient) (*CacheRepo, error) {
 	}, nil
 }
 
+// WithContext возвращает копию репозитория, выполняющую команды с контекстом ctx
+func (r *CacheRepo) WithContext(ctx context.Context) repository.CacheRepository {
+	return &CacheRepo{client: r.client, ctx: ctx}
+}
+
 // Set сохраняет значение в кеше
 func (r *CacheRepo) Set(key string, value interface{}, expiration time.Duration) error {
 	return r.client.Set(r.ctx, key, value, expiration).Err()
@@ -124,11 +130,73 @@ func (r *CacheRepo) SIsMember(key string, member interface{}) (bool, error) {
 	return r.client.SIsMember(r.ctx, key, member).Result()
 }
 
+// SCard возвращает число элементов Set; для отсутствующего ключа — 0.
+// Используется для подсчёта зарегистрированных участников викторины.
+func (r *CacheRepo) SCard(key string) (int64, error) {
+	return r.client.SCard(r.ctx, key).Result()
+}
+
 // Expire устанавливает TTL для ключа (duration-based).
 func (r *CacheRepo) Expire(key string, expiration time.Duration) error {
 	return r.client.Expire(r.ctx, key, expiration).Err()
 }
 
+// ============================================================================
+// Redis List Operations (для журнала событий викторины)
+// ============================================================================
+
+// RPushTrim добавляет значения в конец списка, обрезает его до последних maxLen элементов
+// и продлевает TTL — одной транзакцией, чтобы список не рос без ограничений.
+func (r *CacheRepo) RPushTrim(key string, maxLen int64, expiration time.Duration, values ...interface{}) error {
+	_, err := r.client.TxPipelined(r.ctx, func(pipe redis.Pipeliner) error {
+		pipe.RPush(r.ctx, key, values...)
+		pipe.LTrim(r.ctx, key, -maxLen, -1)
+		pipe.Expire(r.ctx, key, expiration)
+		return nil
+	})
+	return err
+}
+
+// LRange возвращает элементы списка с индексами start..stop.
+func (r *CacheRepo) LRange(key string, start, stop int64) ([]string, error) {
+	return r.client.LRange(r.ctx, key, start, stop).Result()
+}
+
+// ============================================================================
+// Redis Sorted Set Operations (для очереди допуска в зал ожидания)
+// ============================================================================
+
+// ZAddNX добавляет элемент с весом score, если его ещё нет в Sorted Set.
+// Возвращает true, если элемент добавлен.
+func (r *CacheRepo) ZAddNX(key string, score float64, member interface{}) (bool, error) {
+	added, err := r.client.ZAddNX(r.ctx, key, &redis.Z{Score: score, Member: member}).Result()
+	return added > 0, err
+}
+
+// ZRank возвращает позицию элемента (с 0) по возрастанию веса; -1, если элемента нет.
+func (r *CacheRepo) ZRank(key string, member interface{}) (int64, error) {
+	rank, err := r.client.ZRank(r.ctx, key, fmt.Sprint(member)).Result()
+	if errors.Is(err, redis.Nil) {
+		return -1, nil
+	}
+	return rank, err
+}
+
+// ZRem удаляет один или несколько элементов из Sorted Set.
+func (r *CacheRepo) ZRem(key string, members ...interface{}) error {
+	return r.client.ZRem(r.ctx, key, members...).Err()
+}
+
+// ZCard возвращает число элементов Sorted Set; для отсутствующего ключа — 0.
+func (r *CacheRepo) ZCard(key string) (int64, error) {
+	return r.client.ZCard(r.ctx, key).Result()
+}
+
+// ZRange возвращает элементы с позициями start..stop по возрастанию веса.
+func (r *CacheRepo) ZRange(key string, start, stop int64) ([]string, error) {
+	return r.client.ZRange(r.ctx, key, start, stop).Result()
+}
+
 // ExistsBatch проверяет существование нескольких ключей одним Pipeline запросом.
 // Вместо N отдельных Exists() — один roundtrip к Redis.
 func (r *CacheRepo) ExistsBatch(keys []string) (map[string]bool, error) {
@@ -0,0 +1,55 @@
+package seed
+
+import "github.com/yourusername/trivia-api/internal/domain/entity"
+
+// seedQuestion — вопрос встроенного демонстрационного набора
+type seedQuestion struct {
+	Category   string
+	Text       string
+	Options    []string
+	Correct    int
+	Difficulty int
+}
+
+var categories = []entity.Category{
+	{Slug: "geography", Name: "География"},
+	{Slug: "history", Name: "История"},
+	{Slug: "science", Name: "Наука"},
+	{Slug: "sport", Name: "Спорт"},
+}
+
+var questions = []seedQuestion{
+	{"geography", "Столица Казахстана?", []string{"Алматы", "Астана", "Шымкент", "Караганда"}, 1, 1},
+	{"geography", "Самое глубокое озеро мира?", []string{"Байкал", "Танганьика", "Каспийское море", "Балхаш"}, 0, 2},
+	{"geography", "Какая река самая длинная в Казахстане?", []string{"Урал", "Сырдарья", "Иртыш", "Или"}, 2, 3},
+	{"geography", "На каком материке находится пустыня Атакама?", []string{"Африка", "Австралия", "Азия", "Южная Америка"}, 3, 3},
+	{"geography", "Сколько часовых поясов в России?", []string{"9", "11", "12", "7"}, 1, 4},
+	{"geography", "Какое море не имеет берегов?", []string{"Саргассово", "Мёртвое", "Аральское", "Белое"}, 0, 5},
+	{"history", "В каком году человек впервые полетел в космос?", []string{"1957", "1961", "1965", "1969"}, 1, 1},
+	{"history", "Кто построил первую пирамиду со ступенями?", []string{"Хеопс", "Имхотеп", "Рамзес II", "Тутанхамон"}, 1, 4},
+	{"history", "В каком году пала Берлинская стена?", []string{"1985", "1989", "1991", "1993"}, 1, 2},
+	{"history", "Какой город был столицей Золотой Орды?", []string{"Сарай", "Отрар", "Туркестан", "Казань"}, 0, 4},
+	{"history", "Кто открыл Америку в 1492 году?", []string{"Магеллан", "Васко да Гама", "Колумб", "Кук"}, 2, 1},
+	{"history", "В каком веке жил аль-Фараби?", []string{"VII", "IX–X", "XII", "XIV"}, 1, 5},
+	{"science", "Химический символ золота?", []string{"Ag", "Au", "Gd", "Go"}, 1, 1},
+	{"science", "Сколько планет в Солнечной системе?", []string{"7", "8", "9", "10"}, 1, 1},
+	{"science", "Какая частица не имеет заряда?", []string{"Протон", "Электрон", "Нейтрон", "Позитрон"}, 2, 2},
+	{"science", "Скорость света в вакууме примерно равна?", []string{"300 000 км/с", "150 000 км/с", "30 000 км/с", "1 000 000 км/с"}, 0, 3},
+	{"science", "Какой газ преобладает в атмосфере Земли?", []string{"Кислород", "Углекислый газ", "Аргон", "Азот"}, 3, 2},
+	{"science", "Кто сформулировал теорию относительности?", []string{"Ньютон", "Бор", "Эйнштейн", "Планк"}, 2, 1},
+	{"sport", "Сколько игроков в футбольной команде на поле?", []string{"9", "10", "11", "12"}, 2, 1},
+	{"sport", "В каком виде спорта выступал Геннадий Головкин?", []string{"Борьба", "Бокс", "Дзюдо", "Тхэквондо"}, 1, 1},
+	{"sport", "Как часто проводятся летние Олимпийские игры?", []string{"Раз в 2 года", "Раз в 3 года", "Раз в 4 года", "Раз в 5 лет"}, 2, 1},
+	{"sport", "Сколько очков даёт попадание из-за дуги в баскетболе?", []string{"1", "2", "3", "4"}, 2, 2},
+	{"sport", "В каком городе прошли первые Олимпийские игры современности?", []string{"Париж", "Афины", "Лондон", "Рим"}, 1, 3},
+	{"sport", "Какова длина марафонской дистанции?", []string{"40 км", "42,195 км", "41,5 км", "45 км"}, 1, 3},
+}
+
+// adAssets — рекламные ресурсы для показа между вопросами и второго шанса за рекламу.
+// Файлы внешние, поэтому ключей хранилища у них нет.
+var adAssets = []entity.AdAsset{
+	{Title: "Демо-реклама: баннер", MediaType: "image", URL: "https://placehold.co/1080x1920.png?text=Demo+Ad", DurationSec: 5, MimeType: "image/png", Width: 1080, Height: 1920},
+	{Title: "Демо-реклама: квадрат", MediaType: "image", URL: "https://placehold.co/1080x1080.png?text=Demo+Ad", DurationSec: 5, MimeType: "image/png", Width: 1080, Height: 1080},
+	{Title: "Демо-реклама: видео", MediaType: "video", URL: "https://storage.googleapis.com/gtv-videos-bucket/sample/ForBiggerBlazes.mp4",
+		ThumbnailURL: "https://placehold.co/1280x720.png?text=Demo+Video", DurationSec: 15, MimeType: "video/mp4", VideoDurationMs: 15000, Width: 1280, Height: 720},
+}
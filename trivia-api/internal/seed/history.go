@@ -0,0 +1,184 @@
+package seed
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+const (
+	historyQuestions   = 8                // вопросов в сыгранной викторине
+	historyPrizeFund   = 10000            // призовой фонд сыгранной викторины
+	historyQuestionGap = 20 * time.Second // интервал между вопросами
+)
+
+// historyTitle — название n-й сыгранной демо-викторины; по нему повторный запуск её находит
+func historyTitle(n int) string {
+	return fmt.Sprintf("Демо-викторина: архив №%d", n)
+}
+
+// simulateGame разыгрывает викторину на выбывание: игрок отвечает верно с вероятностью, зависящей
+// от сложности вопроса, и выбывает на первой ошибке или пропуске. Возвращает ответы и результаты
+// с рангами, победителями и призами, как их сохранил бы ResultService. Исход детерминирован rng.
+func simulateGame(quiz *entity.Quiz, questions []entity.Question, players []entity.User, rng *rand.Rand) ([]entity.UserAnswer, []entity.Result) {
+	var answers []entity.UserAnswer
+	results := make([]entity.Result, 0, len(players))
+	finishedAt := quiz.ScheduledTime.Add(time.Duration(len(questions)) * historyQuestionGap)
+	for _, player := range players {
+		result := entity.Result{
+			UserID:         player.ID,
+			QuizID:         quiz.ID,
+			Username:       player.Username,
+			ProfilePicture: player.ProfilePicture,
+			TotalQuestions: len(questions),
+			GameMode:       entity.GameModeElimination,
+			CompletedAt:    finishedAt,
+		}
+		for i, q := range questions {
+			answer := entity.UserAnswer{
+				UserID:         player.ID,
+				QuizID:         quiz.ID,
+				QuestionID:     q.ID,
+				SelectedOption: q.CorrectOption,
+				IsCorrect:      true,
+				ResponseTimeMs: int64(1500 + rng.Intn(7000)),
+				Score:          q.PointValue,
+				CreatedAt:      quiz.ScheduledTime.Add(time.Duration(i) * historyQuestionGap),
+			}
+			var reason string
+			switch {
+			case rng.Float64() < 0.95-0.08*float64(q.Difficulty):
+			case rng.Intn(4) == 0:
+				reason = "no_answer_timeout"
+				answer.SelectedOption = -1
+				answer.ResponseTimeMs = int64(q.TimeLimitSec) * 1000
+			default:
+				reason = "incorrect_answer"
+				answer.SelectedOption = (q.CorrectOption + 1 + rng.Intn(len(q.Options)-1)) % len(q.Options)
+			}
+			if reason != "" {
+				answer.IsCorrect = false
+				answer.Score = 0
+				answer.IsEliminated = true
+				answer.EliminationReason = reason
+			}
+			answers = append(answers, answer)
+
+			if reason != "" {
+				questionNumber := i + 1
+				result.IsEliminated = true
+				result.EliminatedOnQuestion = &questionNumber
+				result.EliminationReason = &reason
+				break
+			}
+			result.Score += answer.Score
+			result.CorrectAnswers++
+			result.TotalResponseTimeMs += answer.ResponseTimeMs
+		}
+		results = append(results, result)
+	}
+
+	// Ранги как в ResultRepo.CalculateRanks: по очкам и верным ответам, равные делят место
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].CorrectAnswers > results[j].CorrectAnswers
+	})
+	winners := 0
+	for i := range results {
+		results[i].Rank = i + 1
+		if i > 0 && results[i].Score == results[i-1].Score && results[i].CorrectAnswers == results[i-1].CorrectAnswers {
+			results[i].Rank = results[i-1].Rank
+		}
+		if !results[i].IsEliminated {
+			winners++
+		}
+	}
+	// Фонд делится поровну между дошедшими до конца
+	for i := range results {
+		if !results[i].IsEliminated {
+			results[i].IsWinner = true
+			results[i].PrizeFund = quiz.PrizeFund / winners
+		}
+	}
+	return answers, results
+}
+
+// History создаёт count сыгранных викторин за прошедшие дни с ответами и результатами демо-игроков
+// и начисляет игрокам статистику (игры, очки, победы, призы). Уже созданные (по названию) пропускаются.
+func History(db *gorm.DB, count int, now time.Time) error {
+	if count == 0 {
+		return nil
+	}
+	var players []entity.User
+	if err := db.Where("username LIKE ?", "demo\\_player\\_%").Order("id").Find(&players).Error; err != nil {
+		return err
+	}
+	if len(players) == 0 {
+		return errors.New("history needs demo players: run seed users first")
+	}
+
+	created := 0
+	for n := 1; n <= count; n++ {
+		title := historyTitle(n)
+		var existing int64
+		if err := db.Model(&entity.Quiz{}).Where("title = ? AND description = ?", title, demoDescription).Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			continue
+		}
+		day := now.AddDate(0, 0, -n)
+		quiz := entity.Quiz{
+			Title:         title,
+			ScheduledTime: time.Date(day.Year(), day.Month(), day.Day(), 19, 0, 0, 0, time.UTC),
+			Status:        entity.QuizStatusCompleted,
+			PrizeFund:     historyPrizeFund,
+			GameMode:      entity.GameModeElimination,
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			questions, err := createQuiz(tx, &quiz, pickQuestions(historyQuestions, n))
+			if err != nil {
+				return err
+			}
+			answers, results := simulateGame(&quiz, questions, players, rand.New(rand.NewSource(int64(n))))
+			if err := tx.CreateInBatches(answers, 500).Error; err != nil {
+				return fmt.Errorf("create answers: %w", err)
+			}
+			if err := tx.CreateInBatches(results, 500).Error; err != nil {
+				return fmt.Errorf("create results: %w", err)
+			}
+			for _, r := range results {
+				wins := 0
+				if r.IsWinner {
+					wins = 1
+				}
+				err := tx.Model(&entity.User{}).Where("id = ?", r.UserID).Updates(map[string]interface{}{
+					"games_played":    gorm.Expr("games_played + 1"),
+					"total_score":     gorm.Expr("total_score + ?", r.Score),
+					"highest_score":   gorm.Expr("GREATEST(highest_score, ?)", r.Score),
+					"wins_count":      gorm.Expr("wins_count + ?", wins),
+					"total_prize_won": gorm.Expr("total_prize_won + ?", r.PrizeFund),
+				}).Error
+				if err != nil {
+					return fmt.Errorf("update stats of user %d: %w", r.UserID, err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("create %q: %w", title, err)
+		}
+		created++
+	}
+	log.Printf("[Seed] history: %d played quizzes created, %d already existed", created, count-created)
+	return nil
+}
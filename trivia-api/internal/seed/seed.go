@@ -0,0 +1,252 @@
+// Package seed наполняет базу демонстрационными данными для локальной разработки: администратор и
+// игроки, пул вопросов по темам, ближайшая запланированная викторина, рекламные ресурсы и история
+// сыгранных викторин с результатами. Повторный запуск ничего не дублирует: каждый шаг находит
+// уже созданные данные по username, slug, тексту вопроса, названию ресурса или викторины.
+//
+// Демо-аккаунты создаются с известным паролем, поэтому вызывающая сторона не должна запускать
+// наполнение в production (manage seed требует -force, API с DEV_SEED не стартует при GIN_MODE=release).
+package seed
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+// demoDescription отмечает викторины, созданные наполнением, чтобы повторный запуск их находил
+const demoDescription = "Демо-данные: создано командой manage seed"
+
+// Options — параметры наполнения
+type Options struct {
+	Players        int           // число демо-игроков demo_player_N
+	Password       string        // пароль новых демо-аккаунтов
+	QuizIn         time.Duration // через сколько начнётся запланированная викторина
+	QuizQuestions  int           // вопросов в запланированной викторине
+	HistoryQuizzes int           // сыгранных викторин в истории, по одной на каждый прошедший день
+}
+
+// DefaultOptions возвращает параметры по умолчанию
+func DefaultOptions() Options {
+	return Options{
+		Players:        10,
+		Password:       "demo12345",
+		QuizIn:         10 * time.Minute,
+		QuizQuestions:  10,
+		HistoryQuizzes: 5,
+	}
+}
+
+// Validate проверяет параметры до обращения к БД
+func (o Options) Validate() error {
+	if o.Players < 0 {
+		return fmt.Errorf("invalid number of players %d", o.Players)
+	}
+	if o.Password == "" {
+		return errors.New("password is required")
+	}
+	if o.QuizQuestions < 1 || o.QuizQuestions > len(questions) {
+		return fmt.Errorf("quiz questions must be between 1 and %d", len(questions))
+	}
+	if o.HistoryQuizzes < 0 {
+		return fmt.Errorf("invalid number of history quizzes %d", o.HistoryQuizzes)
+	}
+	return nil
+}
+
+// Run выполняет все шаги наполнения по порядку: пользователи, вопросы, викторина, реклама, история
+func Run(db *gorm.DB, opts Options) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	if err := Users(db, opts.Players, opts.Password); err != nil {
+		return err
+	}
+	if err := QuestionPool(db); err != nil {
+		return err
+	}
+	if err := Quiz(db, time.Now().Add(opts.QuizIn), opts.QuizQuestions); err != nil {
+		return err
+	}
+	if err := Ads(db); err != nil {
+		return err
+	}
+	return History(db, opts.HistoryQuizzes, time.Now())
+}
+
+// demoUsers возвращает администратора demo_admin и count игроков demo_player_N с заполненным
+// профилем и подтверждённым email, чтобы с ними можно было сразу войти и играть
+func demoUsers(count int, password string, now time.Time) []entity.User {
+	birthDate := time.Date(1995, 6, 15, 0, 0, 0, 0, time.UTC)
+	user := func(username, role, firstName string) entity.User {
+		return entity.User{
+			Username:           username,
+			Email:              username + "@example.com",
+			Password:           password,
+			FirstName:          firstName,
+			LastName:           "Demo",
+			BirthDate:          &birthDate,
+			Gender:             "prefer_not_to_say",
+			Language:           "ru",
+			Role:               role,
+			EmailVerifiedAt:    &now,
+			ProfileCompletedAt: &now,
+		}
+	}
+	users := []entity.User{user("demo_admin", "admin", "Admin")}
+	for i := 1; i <= count; i++ {
+		users = append(users, user(fmt.Sprintf("demo_player_%d", i), "user", fmt.Sprintf("Player %d", i)))
+	}
+	return users
+}
+
+// Users создаёт демо-аккаунты; существующие (по username) не меняются
+func Users(db *gorm.DB, count int, password string) error {
+	created := 0
+	for _, user := range demoUsers(count, password, time.Now().UTC()) {
+		result := db.Where(entity.User{Username: user.Username}).FirstOrCreate(&user)
+		if result.Error != nil {
+			return fmt.Errorf("create %s: %w", user.Username, result.Error)
+		}
+		created += int(result.RowsAffected)
+	}
+	log.Printf("[Seed] users: %d created, %d already existed (password for new accounts: %s)", created, count+1-created, password)
+	return nil
+}
+
+// categoryIDs создаёт темы демонстрационного набора и возвращает их ID по slug
+func categoryIDs(db *gorm.DB) (map[string]uint, error) {
+	ids := make(map[string]uint, len(categories))
+	for _, category := range categories {
+		if err := db.Where(entity.Category{Slug: category.Slug}).FirstOrCreate(&category).Error; err != nil {
+			return nil, fmt.Errorf("create category %s: %w", category.Slug, err)
+		}
+		ids[category.Slug] = category.ID
+	}
+	return ids, nil
+}
+
+// newQuestion создаёт одобренный вопрос из набора; quizID nil — вопрос общего пула
+func newQuestion(q seedQuestion, quizID *uint, categoryIDs map[string]uint) entity.Question {
+	var categoryID *uint
+	if id, ok := categoryIDs[q.Category]; ok {
+		categoryID = &id
+	}
+	return entity.Question{
+		QuizID:        quizID,
+		Text:          q.Text,
+		Options:       entity.StringArray(q.Options),
+		CorrectOption: q.Correct,
+		TimeLimitSec:  10,
+		PointValue:    10,
+		Difficulty:    q.Difficulty,
+		ReviewStatus:  entity.QuestionReviewApproved,
+		CategoryID:    categoryID,
+	}
+}
+
+// pickQuestions возвращает count вопросов набора, взятых равномерно, чтобы попали все темы;
+// offset сдвигает выборку, чтобы разные викторины не повторяли друг друга
+func pickQuestions(count, offset int) []seedQuestion {
+	picked := make([]seedQuestion, 0, count)
+	for i := 0; i < count; i++ {
+		picked = append(picked, questions[(i*len(questions)/count+offset)%len(questions)])
+	}
+	return picked
+}
+
+// QuestionPool добавляет в общий пул вопросы набора, которых там ещё нет (по тексту)
+func QuestionPool(db *gorm.DB) error {
+	ids, err := categoryIDs(db)
+	if err != nil {
+		return err
+	}
+	created := 0
+	for _, q := range questions {
+		var existing int64
+		if err := db.Model(&entity.Question{}).Where("quiz_id IS NULL AND text = ?", q.Text).Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			continue
+		}
+		question := newQuestion(q, nil, ids)
+		if err := db.Create(&question).Error; err != nil {
+			return fmt.Errorf("create question %q: %w", q.Text, err)
+		}
+		created++
+	}
+	log.Printf("[Seed] questions: %d added to the pool, %d already there", created, len(questions)-created)
+	return nil
+}
+
+// createQuiz создаёт викторину вместе с собственными вопросами набора
+func createQuiz(tx *gorm.DB, quiz *entity.Quiz, picked []seedQuestion) ([]entity.Question, error) {
+	ids, err := categoryIDs(tx)
+	if err != nil {
+		return nil, err
+	}
+	quiz.Description = demoDescription
+	quiz.QuestionCount = len(picked)
+	quiz.QuestionSourceMode = entity.QuizQuestionSourceAdminOnly
+	if err := tx.Omit("Questions", "Tags", "Category").Create(quiz).Error; err != nil {
+		return nil, fmt.Errorf("create quiz: %w", err)
+	}
+	created := make([]entity.Question, 0, len(picked))
+	for _, q := range picked {
+		question := newQuestion(q, &quiz.ID, ids)
+		if err := tx.Create(&question).Error; err != nil {
+			return nil, fmt.Errorf("create question %q: %w", q.Text, err)
+		}
+		created = append(created, question)
+	}
+	return created, nil
+}
+
+// Quiz создаёт запланированную викторину с собственными вопросами, если предстоящей демо-викторины
+// ещё нет. API ставит её в расписание при старте или через админку.
+func Quiz(db *gorm.DB, startAt time.Time, questionCount int) error {
+	var upcoming entity.Quiz
+	err := db.Where("description = ? AND status = ? AND scheduled_time > ?", demoDescription, entity.QuizStatusScheduled, time.Now()).
+		Order("scheduled_time").First(&upcoming).Error
+	if err == nil {
+		log.Printf("[Seed] quiz: #%d %q is already scheduled at %s", upcoming.ID, upcoming.Title, upcoming.ScheduledTime.Format(time.RFC3339))
+		return nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	quiz := entity.Quiz{
+		Title:         "Демо-викторина " + startAt.Format("02.01 15:04"),
+		ScheduledTime: startAt.UTC(),
+		Status:        entity.QuizStatusScheduled,
+		PrizeFund:     10000,
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if _, err := createQuiz(tx, &quiz, pickQuestions(questionCount, 0)); err != nil {
+			return err
+		}
+		log.Printf("[Seed] quiz: #%d %q scheduled at %s with %d questions",
+			quiz.ID, quiz.Title, quiz.ScheduledTime.Format(time.RFC3339), questionCount)
+		return nil
+	})
+}
+
+// Ads создаёт рекламные ресурсы набора, которых ещё нет (по названию)
+func Ads(db *gorm.DB) error {
+	created := 0
+	for _, asset := range adAssets {
+		result := db.Where(entity.AdAsset{Title: asset.Title}).FirstOrCreate(&asset)
+		if result.Error != nil {
+			return fmt.Errorf("create ad asset %q: %w", asset.Title, result.Error)
+		}
+		created += int(result.RowsAffected)
+	}
+	log.Printf("[Seed] ads: %d created, %d already existed", created, len(adAssets)-created)
+	return nil
+}
@@ -0,0 +1,93 @@
+package seed
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+func TestDemoUsers(t *testing.T) {
+	now := time.Now()
+	users := demoUsers(3, "secret", now)
+
+	require.Len(t, users, 4)
+	assert.Equal(t, "demo_admin", users[0].Username)
+	assert.Equal(t, "admin", users[0].Role)
+	assert.Equal(t, "demo_player_3@example.com", users[3].Email)
+	for _, u := range users {
+		assert.True(t, u.IsProfileComplete(), u.Username)
+		assert.Equal(t, &now, u.EmailVerifiedAt)
+	}
+}
+
+func TestPickQuestions(t *testing.T) {
+	picked := pickQuestions(4, 0)
+	require.Len(t, picked, 4)
+	slugs := map[string]bool{}
+	for _, q := range picked {
+		slugs[q.Category] = true
+	}
+	assert.Len(t, slugs, len(categories), "по вопросу из каждой темы")
+
+	assert.NotEqual(t, pickQuestions(8, 1), pickQuestions(8, 2))
+	assert.Len(t, pickQuestions(len(questions), 3), len(questions))
+}
+
+func TestSimulateGame(t *testing.T) {
+	start := time.Date(2026, 10, 1, 19, 0, 0, 0, time.UTC)
+	quiz := &entity.Quiz{ID: 7, ScheduledTime: start, PrizeFund: 10000}
+	var qs []entity.Question
+	for i, q := range pickQuestions(historyQuestions, 0) {
+		question := newQuestion(q, &quiz.ID, nil)
+		question.ID = uint(100 + i)
+		qs = append(qs, question)
+	}
+	var players []entity.User
+	for i := 1; i <= 30; i++ {
+		players = append(players, entity.User{ID: uint(i), Username: "p"})
+	}
+
+	answers, results := simulateGame(quiz, qs, players, rand.New(rand.NewSource(1)))
+	require.Len(t, results, len(players))
+
+	answersByUser := map[uint][]entity.UserAnswer{}
+	for _, a := range answers {
+		answersByUser[a.UserID] = append(answersByUser[a.UserID], a)
+	}
+	winners, prizes := 0, 0
+	for i, r := range results {
+		playerAnswers := answersByUser[r.UserID]
+		last := playerAnswers[len(playerAnswers)-1]
+		if r.IsEliminated {
+			require.NotNil(t, r.EliminatedOnQuestion)
+			assert.Len(t, playerAnswers, *r.EliminatedOnQuestion, "ответы заканчиваются на вопросе выбывания")
+			assert.True(t, last.IsEliminated)
+			assert.Equal(t, *r.EliminationReason, last.EliminationReason)
+			assert.NotEqual(t, qs[len(playerAnswers)-1].CorrectOption, last.SelectedOption)
+			assert.False(t, r.IsWinner)
+		} else {
+			assert.Len(t, playerAnswers, len(qs))
+			assert.True(t, r.IsWinner)
+			winners++
+		}
+		assert.Equal(t, 10*r.CorrectAnswers, r.Score)
+		assert.Equal(t, start.Add(time.Duration(len(qs))*historyQuestionGap), r.CompletedAt)
+		if i > 0 {
+			assert.GreaterOrEqual(t, results[i-1].Score, r.Score, "результаты по убыванию очков")
+			assert.Equal(t, results[i-1].Score == r.Score, results[i-1].Rank == r.Rank, "равные очки — равный ранг")
+		}
+		prizes += r.PrizeFund
+	}
+	assert.Equal(t, 1, results[0].Rank)
+	if winners > 0 {
+		assert.Equal(t, 10000/winners*winners, prizes)
+	}
+
+	again, _ := simulateGame(quiz, qs, players, rand.New(rand.NewSource(1)))
+	assert.Equal(t, answers, again, "исход определяется rng")
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// ErrDeviceAccountLimit возвращается, если с устройства зарегистрировано максимальное число аккаунтов
+var ErrDeviceAccountLimit = errors.New("device_account_limit")
+
+const (
+	// DeviceLimitBlock — регистрация сверх лимита отклоняется
+	DeviceLimitBlock = "block"
+	// DeviceLimitShadowBan — аккаунт сверх лимита создаётся с теневым баном
+	DeviceLimitShadowBan = "shadow_ban"
+
+	// shadowBanReasonDeviceLimit — причина автоматического теневого бана
+	shadowBanReasonDeviceLimit = "device_account_limit"
+)
+
+// DeviceRegistration — результат проверки устройства перед регистрацией
+type DeviceRegistration struct {
+	DeviceHash string // пусто, если клиент не передал устройство
+	ShadowBan  bool   // аккаунт создаётся с теневым баном
+}
+
+// ShadowBannedUser — аккаунт с теневым баном для админ-панели
+type ShadowBannedUser struct {
+	ID             uint      `json:"id"`
+	Username       string    `json:"username"`
+	Email          string    `json:"email"`
+	DeviceHash     string    `json:"device_hash,omitempty"`
+	ShadowBannedAt time.Time `json:"shadow_banned_at"`
+	Reason         string    `json:"reason"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// DeviceAccount — аккаунт, зарегистрированный с устройства
+type DeviceAccount struct {
+	ID              uint       `json:"id"`
+	Username        string     `json:"username"`
+	Email           string     `json:"email"`
+	GamesPlayed     int64      `json:"games_played"`
+	WinsCount       int64      `json:"wins_count"`
+	ShadowBannedAt  *time.Time `json:"shadow_banned_at,omitempty"`
+	ShadowBanReason string     `json:"shadow_ban_reason,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// AbuseService ограничивает число аккаунтов на устройство и ведёт теневые баны:
+// аккаунт с теневым баном играет как обычно, но не может стать победителем викторины.
+type AbuseService struct {
+	abuseRepo            repository.AbuseRepository
+	maxAccountsPerDevice int
+	overLimitAction      string
+}
+
+// NewAbuseService создает сервис защиты от мультиаккаунтов. maxAccountsPerDevice = 0 — без ограничения.
+func NewAbuseService(abuseRepo repository.AbuseRepository, maxAccountsPerDevice int, overLimitAction string) (*AbuseService, error) {
+	if abuseRepo == nil {
+		return nil, fmt.Errorf("abuse repository is required")
+	}
+	switch overLimitAction {
+	case DeviceLimitBlock, DeviceLimitShadowBan:
+	default:
+		return nil, fmt.Errorf("unknown device limit action %q", overLimitAction)
+	}
+	return &AbuseService{
+		abuseRepo:            abuseRepo,
+		maxAccountsPerDevice: maxAccountsPerDevice,
+		overLimitAction:      overLimitAction,
+	}, nil
+}
+
+// CheckRegistration проверяет лимит аккаунтов на устройство перед регистрацией.
+// Проверка не атомарна с созданием аккаунта: параллельные регистрации могут превысить лимит на единицы.
+func (s *AbuseService) CheckRegistration(deviceID string) (*DeviceRegistration, error) {
+	registration := &DeviceRegistration{DeviceHash: HashDeviceID(deviceID)}
+	if registration.DeviceHash == "" || s.maxAccountsPerDevice <= 0 {
+		return registration, nil
+	}
+
+	count, err := s.abuseRepo.CountAccountsByDevice(registration.DeviceHash)
+	if err != nil {
+		return nil, err
+	}
+	if count < int64(s.maxAccountsPerDevice) {
+		return registration, nil
+	}
+	if s.overLimitAction == DeviceLimitBlock {
+		return nil, ErrDeviceAccountLimit
+	}
+	registration.ShadowBan = true
+	return registration, nil
+}
+
+// ListSharedDevices возвращает устройства, с которых зарегистрировано несколько аккаунтов
+func (s *AbuseService) ListSharedDevices(minAccounts, page, pageSize int) ([]repository.DeviceAccounts, int64, error) {
+	if minAccounts < 2 {
+		minAccounts = 2
+	}
+	page, pageSize = normalizeWalletPage(page, pageSize)
+	return s.abuseRepo.ListSharedDevices(minAccounts, pageSize, (page-1)*pageSize)
+}
+
+// ListDeviceAccounts возвращает аккаунты, зарегистрированные с устройства
+func (s *AbuseService) ListDeviceAccounts(deviceHash string) ([]DeviceAccount, error) {
+	deviceHash = strings.ToLower(strings.TrimSpace(deviceHash))
+	if len(deviceHash) != sha256.Size*2 {
+		return nil, fmt.Errorf("%w: invalid device hash", apperrors.ErrValidation)
+	}
+	users, err := s.abuseRepo.ListUsersByDevice(deviceHash)
+	if err != nil {
+		return nil, err
+	}
+	accounts := make([]DeviceAccount, len(users))
+	for i, u := range users {
+		accounts[i] = DeviceAccount{
+			ID:              u.ID,
+			Username:        u.Username,
+			Email:           u.Email,
+			GamesPlayed:     u.GamesPlayed,
+			WinsCount:       u.WinsCount,
+			ShadowBannedAt:  u.ShadowBannedAt,
+			ShadowBanReason: u.ShadowBanReason,
+			CreatedAt:       u.CreatedAt,
+		}
+	}
+	return accounts, nil
+}
+
+// ListShadowBanned возвращает аккаунты с теневым баном
+func (s *AbuseService) ListShadowBanned(page, pageSize int) ([]ShadowBannedUser, int64, error) {
+	page, pageSize = normalizeWalletPage(page, pageSize)
+	users, total, err := s.abuseRepo.ListShadowBanned(pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	banned := make([]ShadowBannedUser, 0, len(users))
+	for _, u := range users {
+		if u.ShadowBannedAt == nil {
+			continue
+		}
+		banned = append(banned, ShadowBannedUser{
+			ID:             u.ID,
+			Username:       u.Username,
+			Email:          u.Email,
+			DeviceHash:     u.RegistrationDeviceHash,
+			ShadowBannedAt: *u.ShadowBannedAt,
+			Reason:         u.ShadowBanReason,
+			CreatedAt:      u.CreatedAt,
+		})
+	}
+	return banned, total, nil
+}
+
+// ShadowBan ставит аккаунту теневой бан: с этого момента он не может стать победителем
+func (s *AbuseService) ShadowBan(userID uint, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" || len([]rune(reason)) > 255 {
+		return fmt.Errorf("%w: reason is required and must be at most 255 characters", apperrors.ErrValidation)
+	}
+	now := time.Now()
+	if err := s.abuseRepo.SetShadowBan(userID, &now, reason); err != nil {
+		return err
+	}
+	log.Printf("[AbuseService] Теневой бан пользователя ID=%d: %s", userID, reason)
+	return nil
+}
+
+// LiftShadowBan снимает теневой бан; уже подведённые итоги викторин не пересчитываются
+func (s *AbuseService) LiftShadowBan(userID uint) error {
+	if err := s.abuseRepo.SetShadowBan(userID, nil, ""); err != nil {
+		return err
+	}
+	log.Printf("[AbuseService] Снят теневой бан пользователя ID=%d", userID)
+	return nil
+}
+
+// HashDeviceID возвращает SHA-256 идентификатора устройства; сам идентификатор не хранится
+func HashDeviceID(deviceID string) string {
+	deviceID = strings.TrimSpace(deviceID)
+	if deviceID == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(deviceID))
+	return hex.EncodeToString(sum[:])
+}
+
+// applyDeviceRegistration переносит результат проверки устройства в нового пользователя
+func applyDeviceRegistration(user *entity.User, registration *DeviceRegistration) {
+	if registration == nil {
+		return
+	}
+	user.RegistrationDeviceHash = registration.DeviceHash
+	if registration.ShadowBan {
+		now := time.Now()
+		user.ShadowBannedAt = &now
+		user.ShadowBanReason = shadowBanReasonDeviceLimit
+	}
+}
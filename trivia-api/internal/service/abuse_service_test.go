@@ -0,0 +1,113 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// memoryAbuseRepo хранит число аккаунтов на устройство и теневые баны в памяти
+type memoryAbuseRepo struct {
+	deviceAccounts map[string]int64
+	bans           map[uint]string
+}
+
+func (r *memoryAbuseRepo) CountAccountsByDevice(deviceHash string) (int64, error) {
+	return r.deviceAccounts[deviceHash], nil
+}
+
+func (r *memoryAbuseRepo) ListSharedDevices(minAccounts, limit, offset int) ([]repository.DeviceAccounts, int64, error) {
+	return nil, 0, nil
+}
+
+func (r *memoryAbuseRepo) ListUsersByDevice(deviceHash string) ([]entity.User, error) {
+	return nil, nil
+}
+
+func (r *memoryAbuseRepo) ListShadowBanned(limit, offset int) ([]entity.User, int64, error) {
+	return nil, 0, nil
+}
+
+func (r *memoryAbuseRepo) SetShadowBan(userID uint, bannedAt *time.Time, reason string) error {
+	if userID == 0 {
+		return apperrors.ErrNotFound
+	}
+	if bannedAt == nil {
+		delete(r.bans, userID)
+		return nil
+	}
+	r.bans[userID] = reason
+	return nil
+}
+
+func newMemoryAbuseRepo() *memoryAbuseRepo {
+	return &memoryAbuseRepo{deviceAccounts: make(map[string]int64), bans: make(map[uint]string)}
+}
+
+func TestAbuseService_CheckRegistration(t *testing.T) {
+	repo := newMemoryAbuseRepo()
+	repo.deviceAccounts[HashDeviceID("phone-1")] = 3
+
+	shadow, err := NewAbuseService(repo, 3, DeviceLimitShadowBan)
+	require.NoError(t, err)
+
+	// Устройство ниже лимита — обычная регистрация, идентификатор хранится только хешем
+	registration, err := shadow.CheckRegistration("phone-2")
+	require.NoError(t, err)
+	assert.False(t, registration.ShadowBan)
+	assert.Len(t, registration.DeviceHash, 64)
+	assert.NotContains(t, registration.DeviceHash, "phone-2")
+
+	// Сверх лимита аккаунт создаётся, но с теневым баном
+	registration, err = shadow.CheckRegistration(" phone-1 ")
+	require.NoError(t, err)
+	assert.True(t, registration.ShadowBan)
+	user := &entity.User{}
+	applyDeviceRegistration(user, registration)
+	require.NotNil(t, user.ShadowBannedAt)
+	assert.Equal(t, shadowBanReasonDeviceLimit, user.ShadowBanReason)
+	assert.Equal(t, HashDeviceID("phone-1"), user.RegistrationDeviceHash)
+
+	// Без устройства проверка пропускается
+	registration, err = shadow.CheckRegistration("")
+	require.NoError(t, err)
+	assert.Empty(t, registration.DeviceHash)
+	assert.False(t, registration.ShadowBan)
+
+	block, err := NewAbuseService(repo, 3, DeviceLimitBlock)
+	require.NoError(t, err)
+	_, err = block.CheckRegistration("phone-1")
+	assert.ErrorIs(t, err, ErrDeviceAccountLimit)
+
+	// Лимит 0 отключает ограничение
+	unlimited, err := NewAbuseService(repo, 0, DeviceLimitBlock)
+	require.NoError(t, err)
+	_, err = unlimited.CheckRegistration("phone-1")
+	assert.NoError(t, err)
+
+	_, err = NewAbuseService(repo, 3, "ignore")
+	assert.Error(t, err)
+}
+
+func TestAbuseService_ShadowBan(t *testing.T) {
+	repo := newMemoryAbuseRepo()
+	svc, err := NewAbuseService(repo, 3, DeviceLimitShadowBan)
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, svc.ShadowBan(5, "  "), apperrors.ErrValidation)
+	assert.ErrorIs(t, svc.ShadowBan(0, "мультиаккаунт"), apperrors.ErrNotFound)
+
+	require.NoError(t, svc.ShadowBan(5, " мультиаккаунт "))
+	assert.Equal(t, "мультиаккаунт", repo.bans[5])
+	require.NoError(t, svc.LiftShadowBan(5))
+	assert.NotContains(t, repo.bans, uint(5))
+
+	_, err = svc.ListDeviceAccounts("not-a-hash")
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+// VideoInfo содержит метаданные видеофайла
+type VideoInfo struct {
+	Duration time.Duration
+	Width    int
+	Height   int
+}
+
+// AdMediaProcessor извлекает метаданные и превью из загруженных видео.
+// Реализация опциональна: без неё длительность видео берётся из запроса администратора.
+type AdMediaProcessor interface {
+	// ProbeVideo возвращает длительность и размеры видео
+	ProbeVideo(ctx context.Context, path string) (*VideoInfo, error)
+	// ExtractThumbnail сохраняет кадр видео в позиции at как JPEG
+	ExtractThumbnail(ctx context.Context, videoPath, thumbnailPath string, at time.Duration) error
+}
+
+// FFmpegMediaProcessor реализует AdMediaProcessor через ffprobe/ffmpeg
+type FFmpegMediaProcessor struct {
+	ffmpegPath  string
+	ffprobePath string
+}
+
+// NewFFmpegMediaProcessor создает процессор, проверяя наличие бинарников ffmpeg и ffprobe
+func NewFFmpegMediaProcessor(ffmpegPath, ffprobePath string) (*FFmpegMediaProcessor, error) {
+	if ffmpegPath == "" {
+		ffmpegPath = "ffmpeg"
+	}
+	if ffprobePath == "" {
+		ffprobePath = "ffprobe"
+	}
+	ffmpeg, err := exec.LookPath(ffmpegPath)
+	if err != nil {
+		return nil, fmt.Errorf("ffmpeg not found: %w", err)
+	}
+	ffprobe, err := exec.LookPath(ffprobePath)
+	if err != nil {
+		return nil, fmt.Errorf("ffprobe not found: %w", err)
+	}
+	return &FFmpegMediaProcessor{ffmpegPath: ffmpeg, ffprobePath: ffprobe}, nil
+}
+
+type ffprobeOutput struct {
+	Streams []struct {
+		Width  int `json:"width"`
+		Height int `json:"height"`
+	} `json:"streams"`
+	Format struct {
+		Duration string `json:"duration"`
+	} `json:"format"`
+}
+
+// ProbeVideo возвращает длительность и размеры первого видеопотока
+func (p *FFmpegMediaProcessor) ProbeVideo(ctx context.Context, path string) (*VideoInfo, error) {
+	out, err := exec.CommandContext(ctx, p.ffprobePath,
+		"-v", "error",
+		"-select_streams", "v:0",
+		"-show_entries", "stream=width,height:format=duration",
+		"-of", "json",
+		path,
+	).Output()
+	if err != nil {
+		return nil, fmt.Errorf("ffprobe failed: %w", err)
+	}
+
+	var probe ffprobeOutput
+	if err := json.Unmarshal(out, &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse ffprobe output: %w", err)
+	}
+	if len(probe.Streams) == 0 {
+		return nil, fmt.Errorf("no video stream found")
+	}
+	seconds, err := strconv.ParseFloat(probe.Format.Duration, 64)
+	if err != nil || seconds <= 0 {
+		return nil, fmt.Errorf("invalid video duration %q", probe.Format.Duration)
+	}
+
+	return &VideoInfo{
+		Duration: time.Duration(seconds * float64(time.Second)),
+		Width:    probe.Streams[0].Width,
+		Height:   probe.Streams[0].Height,
+	}, nil
+}
+
+// ExtractThumbnail сохраняет один кадр видео, уменьшенный до ширины 640px
+func (p *FFmpegMediaProcessor) ExtractThumbnail(ctx context.Context, videoPath, thumbnailPath string, at time.Duration) error {
+	out, err := exec.CommandContext(ctx, p.ffmpegPath,
+		"-y",
+		"-v", "error",
+		"-ss", strconv.FormatFloat(at.Seconds(), 'f', 3, 64),
+		"-i", videoPath,
+		"-frames:v", "1",
+		"-vf", "scale='min(640,iw)':-2",
+		thumbnailPath,
+	).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("ffmpeg failed: %w: %s", err, out)
+	}
+	return nil
+}
@@ -1,10 +1,12 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log"
 	"mime/multipart"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
@@ -12,24 +14,83 @@ import (
 
 	"github.com/yourusername/trivia-api/internal/domain/entity"
 	"github.com/yourusername/trivia-api/internal/domain/repository"
+	"github.com/yourusername/trivia-api/internal/pkg/storage"
 )
 
+// Ограничения на загружаемые рекламные файлы по умолчанию
+const (
+	DefaultAdMaxImageSizeBytes   = 10 * 1024 * 1024
+	DefaultAdMaxVideoSizeBytes   = 50 * 1024 * 1024
+	DefaultAdMaxVideoDurationSec = 30
+
+	adMinDurationSec  = 3
+	adProbeTimeout    = 30 * time.Second
+	adUploadTimeout   = 5 * time.Minute
+	adThumbnailOffset = time.Second
+	adStoragePrefix   = "ads/"
+)
+
+// adFormat описывает допустимый формат рекламного файла
+type adFormat struct {
+	mediaType string
+	mimeType  string
+}
+
+// Допустимые форматы: расширение → тип ресурса и ожидаемый MIME-тип содержимого
+var adFormats = map[string]adFormat{
+	".jpg":  {mediaType: "image", mimeType: "image/jpeg"},
+	".jpeg": {mediaType: "image", mimeType: "image/jpeg"},
+	".png":  {mediaType: "image", mimeType: "image/png"},
+	".webp": {mediaType: "image", mimeType: "image/webp"},
+	".gif":  {mediaType: "image", mimeType: "image/gif"},
+	".mp4":  {mediaType: "video", mimeType: "video/mp4"},
+	".webm": {mediaType: "video", mimeType: "video/webm"},
+}
+
+// AdUploadLimits содержит ограничения на загружаемые рекламные файлы
+type AdUploadLimits struct {
+	MaxImageSizeBytes   int64
+	MaxVideoSizeBytes   int64
+	MaxVideoDurationSec int
+}
+
 // AdService предоставляет методы для работы с рекламными ресурсами
 type AdService struct {
-	adAssetRepo repository.AdAssetRepository
-	uploadDir   string // директория для загрузки файлов
+	adAssetRepo    repository.AdAssetRepository
+	storage        storage.Storage // хранилище рекламных файлов
+	limits         AdUploadLimits
+	mediaProcessor AdMediaProcessor // опционально: метаданные и превью видео
 }
 
 // NewAdService создаёт новый сервис рекламы
-func NewAdService(adAssetRepo repository.AdAssetRepository, uploadDir string) *AdService {
-	// Создаём директорию для загрузки, если не существует
-	if err := os.MkdirAll(uploadDir, 0755); err != nil {
-		log.Printf("[AdService] WARNING: не удалось создать директорию %s: %v", uploadDir, err)
-	}
+func NewAdService(adAssetRepo repository.AdAssetRepository, store storage.Storage) *AdService {
 	return &AdService{
 		adAssetRepo: adAssetRepo,
-		uploadDir:   uploadDir,
+		storage:     store,
+		limits: AdUploadLimits{
+			MaxImageSizeBytes:   DefaultAdMaxImageSizeBytes,
+			MaxVideoSizeBytes:   DefaultAdMaxVideoSizeBytes,
+			MaxVideoDurationSec: DefaultAdMaxVideoDurationSec,
+		},
+	}
+}
+
+// SetUploadLimits переопределяет ограничения на загружаемые файлы (нулевые значения игнорируются)
+func (s *AdService) SetUploadLimits(limits AdUploadLimits) {
+	if limits.MaxImageSizeBytes > 0 {
+		s.limits.MaxImageSizeBytes = limits.MaxImageSizeBytes
+	}
+	if limits.MaxVideoSizeBytes > 0 {
+		s.limits.MaxVideoSizeBytes = limits.MaxVideoSizeBytes
 	}
+	if limits.MaxVideoDurationSec > 0 {
+		s.limits.MaxVideoDurationSec = limits.MaxVideoDurationSec
+	}
+}
+
+// SetMediaProcessor подключает извлечение длительности и превью видео
+func (s *AdService) SetMediaProcessor(processor AdMediaProcessor) {
+	s.mediaProcessor = processor
 }
 
 // CreateAdAssetRequest DTO для создания рекламного ресурса
@@ -39,32 +100,31 @@ type CreateAdAssetRequest struct {
 	DurationSec int    `json:"duration_sec" binding:"required,min=3,max=30"`
 }
 
-// UploadAdAsset загружает файл и создаёт рекламный ресурс
+// UploadAdAsset загружает файл и создаёт рекламный ресурс.
+// Для видео при подключённом AdMediaProcessor длительность берётся из самого ролика
+// (durationSec игнорируется) и извлекается превью.
 func (s *AdService) UploadAdAsset(file *multipart.FileHeader, title string, mediaType string, durationSec int) (*entity.AdAsset, error) {
 	// Валидация типа файла
 	ext := strings.ToLower(filepath.Ext(file.Filename))
-	allowedExts := map[string]string{
-		".jpg":  "image",
-		".jpeg": "image",
-		".png":  "image",
-		".webp": "image",
-		".gif":  "image",
-		".mp4":  "video",
-		".webm": "video",
-	}
-
-	expectedType, ok := allowedExts[ext]
+	format, ok := adFormats[ext]
 	if !ok {
 		return nil, fmt.Errorf("недопустимый формат файла: %s", ext)
 	}
-	if expectedType != mediaType {
+	if format.mediaType != mediaType {
 		return nil, fmt.Errorf("тип файла %s не соответствует указанному типу %s", ext, mediaType)
 	}
 
-	// Генерируем уникальное имя файла
-	timestamp := time.Now().UnixNano()
-	filename := fmt.Sprintf("ad_%d%s", timestamp, ext)
-	filePath := filepath.Join(s.uploadDir, filename)
+	// Валидация размера
+	maxSize := s.limits.MaxImageSizeBytes
+	if mediaType == "video" {
+		maxSize = s.limits.MaxVideoSizeBytes
+	}
+	if file.Size > maxSize {
+		return nil, fmt.Errorf("файл слишком большой (макс. %d MB)", maxSize/(1024*1024))
+	}
+	if mediaType == "video" && durationSec > s.limits.MaxVideoDurationSec {
+		return nil, fmt.Errorf("длительность видео превышает %d сек", s.limits.MaxVideoDurationSec)
+	}
 
 	// Открываем исходный файл
 	src, err := file.Open()
@@ -73,40 +133,139 @@ func (s *AdService) UploadAdAsset(file *multipart.FileHeader, title string, medi
 	}
 	defer src.Close()
 
-	// Создаём целевой файл
-	dst, err := os.Create(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("не удалось создать файл: %w", err)
+	// Проверяем, что содержимое соответствует расширению
+	header := make([]byte, 512)
+	n, err := io.ReadFull(src, header)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, fmt.Errorf("не удалось прочитать загруженный файл: %w", err)
 	}
-	defer dst.Close()
-
-	// Копируем содержимое
-	if _, err := io.Copy(dst, src); err != nil {
-		os.Remove(filePath) // Удаляем частично записанный файл
-		return nil, fmt.Errorf("не удалось сохранить файл: %w", err)
+	if detected := http.DetectContentType(header[:n]); detected != format.mimeType {
+		return nil, fmt.Errorf("содержимое файла (%s) не соответствует формату %s", detected, ext)
+	}
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("не удалось прочитать загруженный файл: %w", err)
 	}
 
-	// Формируем URL (относительный путь для сервера)
-	url := "/uploads/ads/" + filename
+	// Генерируем уникальный ключ объекта
+	timestamp := time.Now().UnixNano()
+	key := fmt.Sprintf("%sad_%d%s", adStoragePrefix, timestamp, ext)
 
-	// Создаём запись в БД
 	asset := &entity.AdAsset{
 		Title:         title,
 		MediaType:     mediaType,
-		URL:           url,
+		URL:           s.storage.PublicURL(key),
+		StorageKey:    key,
 		DurationSec:   durationSec,
 		FileSizeBytes: file.Size,
+		MimeType:      format.mimeType,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), adUploadTimeout)
+	defer cancel()
+
+	// Видео анализируется по локальной копии до загрузки в хранилище
+	if mediaType == "video" && s.mediaProcessor != nil {
+		if err := s.processVideo(ctx, asset, src, ext, timestamp); err != nil {
+			return nil, err
+		}
+		if _, err := src.Seek(0, io.SeekStart); err != nil {
+			s.deleteObjects(asset.ThumbnailKey)
+			return nil, fmt.Errorf("не удалось прочитать загруженный файл: %w", err)
+		}
 	}
 
+	if err := s.storage.Store(ctx, key, src, file.Size, format.mimeType); err != nil {
+		s.deleteObjects(asset.ThumbnailKey)
+		return nil, fmt.Errorf("не удалось сохранить файл: %w", err)
+	}
+
+	// Создаём запись в БД
 	if err := s.adAssetRepo.Create(asset); err != nil {
-		os.Remove(filePath) // Откатываем загрузку
+		s.deleteObjects(asset.StorageKey, asset.ThumbnailKey) // Откатываем загрузку
 		return nil, fmt.Errorf("не удалось сохранить в БД: %w", err)
 	}
 
-	log.Printf("[AdService] Создан рекламный ресурс #%d: %s (%s, %d сек)", asset.ID, title, mediaType, durationSec)
+	log.Printf("[AdService] Создан рекламный ресурс #%d: %s (%s, %d сек)", asset.ID, title, mediaType, asset.DurationSec)
 	return asset, nil
 }
 
+// processVideo заполняет метаданные видео и загружает превью в хранилище.
+// Ошибка извлечения превью не прерывает загрузку.
+func (s *AdService) processVideo(ctx context.Context, asset *entity.AdAsset, src io.Reader, ext string, timestamp int64) error {
+	tmp, err := os.CreateTemp("", "ad_*"+ext)
+	if err != nil {
+		return fmt.Errorf("не удалось создать временный файл: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = io.Copy(tmp, src)
+	tmp.Close()
+	if err != nil {
+		return fmt.Errorf("не удалось сохранить временный файл: %w", err)
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, adProbeTimeout)
+	defer cancel()
+
+	info, err := s.mediaProcessor.ProbeVideo(probeCtx, tmp.Name())
+	if err != nil {
+		return fmt.Errorf("не удалось прочитать видео: %w", err)
+	}
+
+	asset.VideoDurationMs = int(info.Duration.Milliseconds())
+	asset.Width = info.Width
+	asset.Height = info.Height
+
+	seconds := asset.VideoDurationSec()
+	if seconds < adMinDurationSec {
+		return fmt.Errorf("видео слишком короткое (мин. %d сек)", adMinDurationSec)
+	}
+	if seconds > s.limits.MaxVideoDurationSec {
+		return fmt.Errorf("длительность видео %d сек превышает %d сек", seconds, s.limits.MaxVideoDurationSec)
+	}
+	asset.DurationSec = seconds
+
+	at := adThumbnailOffset
+	if info.Duration < 2*at {
+		at = info.Duration / 2
+	}
+	thumbnailPath := tmp.Name() + "_thumb.jpg"
+	defer os.Remove(thumbnailPath)
+	if err := s.mediaProcessor.ExtractThumbnail(probeCtx, tmp.Name(), thumbnailPath, at); err != nil {
+		log.Printf("[AdService] WARNING: не удалось извлечь превью видео: %v", err)
+		return nil
+	}
+
+	thumbnail, err := os.Open(thumbnailPath)
+	if err != nil {
+		log.Printf("[AdService] WARNING: не удалось открыть превью видео: %v", err)
+		return nil
+	}
+	defer thumbnail.Close()
+
+	thumbnailKey := fmt.Sprintf("%sad_%d_thumb.jpg", adStoragePrefix, timestamp)
+	if err := s.storage.Store(ctx, thumbnailKey, thumbnail, -1, "image/jpeg"); err != nil {
+		log.Printf("[AdService] WARNING: не удалось сохранить превью видео: %v", err)
+		return nil
+	}
+	asset.ThumbnailKey = thumbnailKey
+	asset.ThumbnailURL = s.storage.PublicURL(thumbnailKey)
+	return nil
+}
+
+// deleteObjects удаляет объекты из хранилища, пропуская пустые ключи
+func (s *AdService) deleteObjects(keys ...string) {
+	ctx, cancel := context.WithTimeout(context.Background(), adUploadTimeout)
+	defer cancel()
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		if err := s.storage.Delete(ctx, key); err != nil {
+			log.Printf("[AdService] WARNING: не удалось удалить файл %s: %v", key, err)
+		}
+	}
+}
+
 // ListAdAssets возвращает все рекламные ресурсы
 func (s *AdService) ListAdAssets() ([]entity.AdAsset, error) {
 	return s.adAssetRepo.List()
@@ -139,12 +298,8 @@ func (s *AdService) DeleteAdAsset(id uint) error {
 		return fmt.Errorf("не удалось удалить из БД: %w", err)
 	}
 
-	// Удаляем файл (игнорируем ошибку, если файла нет)
-	filename := filepath.Base(asset.URL)
-	filePath := filepath.Join(s.uploadDir, filename)
-	if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
-		log.Printf("[AdService] WARNING: не удалось удалить файл %s: %v", filePath, err)
-	}
+	// Удаляем файлы ресурса и превью
+	s.deleteObjects(asset.StorageKey, asset.ThumbnailKey)
 
 	log.Printf("[AdService] Удалён рекламный ресурс #%d", id)
 	return nil
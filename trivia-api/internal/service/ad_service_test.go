@@ -0,0 +1,141 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/pkg/storage"
+)
+
+// stubAdAssetRepo сохраняет созданные ресурсы в памяти
+type stubAdAssetRepo struct {
+	created []*entity.AdAsset
+}
+
+func (r *stubAdAssetRepo) Create(asset *entity.AdAsset) error {
+	asset.ID = uint(len(r.created) + 1)
+	r.created = append(r.created, asset)
+	return nil
+}
+func (r *stubAdAssetRepo) GetByID(id uint) (*entity.AdAsset, error) { return nil, nil }
+func (r *stubAdAssetRepo) List() ([]entity.AdAsset, error)          { return nil, nil }
+func (r *stubAdAssetRepo) Update(asset *entity.AdAsset) error       { return nil }
+func (r *stubAdAssetRepo) Delete(id uint) error                     { return nil }
+func (r *stubAdAssetRepo) IsUsedInSlots(id uint) (bool, error)      { return false, nil }
+
+// fakeMediaProcessor возвращает заданную длительность и пишет пустое превью
+type fakeMediaProcessor struct {
+	duration time.Duration
+}
+
+func (p *fakeMediaProcessor) ProbeVideo(ctx context.Context, path string) (*VideoInfo, error) {
+	return &VideoInfo{Duration: p.duration, Width: 1280, Height: 720}, nil
+}
+
+func (p *fakeMediaProcessor) ExtractThumbnail(ctx context.Context, videoPath, thumbnailPath string, at time.Duration) error {
+	return os.WriteFile(thumbnailPath, []byte{0xFF, 0xD8, 0xFF}, 0644)
+}
+
+// mp4Header — минимальный ftyp-бокс MP4-файла
+var mp4Header = []byte{
+	0x00, 0x00, 0x00, 0x18, 'f', 't', 'y', 'p', 'i', 's', 'o', 'm',
+	0x00, 0x00, 0x02, 0x00, 'i', 's', 'o', 'm', 'm', 'p', '4', '1',
+}
+
+func newUploadFileHeader(t *testing.T, filename string, content []byte) *multipart.FileHeader {
+	t.Helper()
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	part, err := w.CreateFormFile("file", filename)
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	form, err := multipart.NewReader(body, w.Boundary()).ReadForm(1 << 20)
+	require.NoError(t, err)
+	return form.File["file"][0]
+}
+
+func newTestAdService(t *testing.T, repo *stubAdAssetRepo) (*AdService, string) {
+	t.Helper()
+	dir := t.TempDir()
+	store, err := storage.NewLocalStorage(dir, "/uploads")
+	require.NoError(t, err)
+	return NewAdService(repo, store), dir
+}
+
+func uploadedFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	var names []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			names = append(names, d.Name())
+		}
+		return err
+	})
+	require.NoError(t, err)
+	return names
+}
+
+func TestAdService_UploadVideo_UsesProbedDurationAndThumbnail(t *testing.T) {
+	repo := &stubAdAssetRepo{}
+	svc, dir := newTestAdService(t, repo)
+	svc.SetMediaProcessor(&fakeMediaProcessor{duration: 12300 * time.Millisecond})
+
+	asset, err := svc.UploadAdAsset(newUploadFileHeader(t, "promo.mp4", mp4Header), "Promo", "video", 5)
+
+	require.NoError(t, err)
+	assert.Equal(t, "video/mp4", asset.MimeType)
+	assert.Equal(t, 12300, asset.VideoDurationMs)
+	assert.Equal(t, 13, asset.DurationSec)
+	assert.Equal(t, 1280, asset.Width)
+	assert.Equal(t, "/uploads/"+asset.StorageKey, asset.URL)
+	assert.Equal(t, "/uploads/"+asset.ThumbnailKey, asset.ThumbnailURL)
+	assert.FileExists(t, filepath.Join(dir, asset.StorageKey))
+	assert.FileExists(t, filepath.Join(dir, asset.ThumbnailKey))
+	assert.Equal(t, 12300*time.Millisecond, asset.PlaybackDuration())
+}
+
+func TestAdService_UploadVideo_RejectsTooLongVideo(t *testing.T) {
+	repo := &stubAdAssetRepo{}
+	svc, dir := newTestAdService(t, repo)
+	svc.SetMediaProcessor(&fakeMediaProcessor{duration: 45 * time.Second})
+
+	_, err := svc.UploadAdAsset(newUploadFileHeader(t, "promo.mp4", mp4Header), "Promo", "video", 10)
+
+	require.Error(t, err)
+	assert.Empty(t, repo.created)
+	assert.Empty(t, uploadedFiles(t, dir), "rejected upload must be removed from disk")
+}
+
+func TestAdService_Upload_RejectsContentMismatch(t *testing.T) {
+	repo := &stubAdAssetRepo{}
+	svc, dir := newTestAdService(t, repo)
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR")
+
+	_, err := svc.UploadAdAsset(newUploadFileHeader(t, "promo.mp4", png), "Promo", "video", 10)
+
+	require.Error(t, err)
+	assert.Empty(t, repo.created)
+	assert.Empty(t, uploadedFiles(t, dir))
+}
+
+func TestAdService_Upload_RejectsOversizedVideo(t *testing.T) {
+	repo := &stubAdAssetRepo{}
+	svc, _ := newTestAdService(t, repo)
+	svc.SetUploadLimits(AdUploadLimits{MaxVideoSizeBytes: int64(len(mp4Header) - 1)})
+
+	_, err := svc.UploadAdAsset(newUploadFileHeader(t, "promo.mp4", mp4Header), "Promo", "video", 10)
+
+	require.Error(t, err)
+	assert.Empty(t, repo.created)
+}
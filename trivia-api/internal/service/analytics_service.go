@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+const (
+	// DefaultAnalyticsDays — период дашборда по умолчанию
+	DefaultAnalyticsDays = 30
+	// MaxAnalyticsDays — максимальный период дашборда
+	MaxAnalyticsDays = 90
+
+	analyticsCacheTTL         = 5 * time.Minute
+	analyticsTopAdAssetsLimit = 10
+	connectionSampleRetention = 90 * 24 * time.Hour
+)
+
+// ConnectionCounter возвращает текущее количество WebSocket-подключений экземпляра
+type ConnectionCounter interface {
+	ClientCount() int
+}
+
+// ActiveUsersStats содержит метрики активных пользователей
+type ActiveUsersStats struct {
+	DAU   int64                   `json:"dau"`
+	WAU   int64                   `json:"wau"`
+	Daily []repository.DailyCount `json:"daily"`
+}
+
+// RetentionCohort содержит удержание недельной когорты регистрации
+type RetentionCohort struct {
+	Cohort time.Time `json:"cohort"`
+	Size   int64     `json:"size"`
+	// Retention[i] — доля когорты (в процентах), активная на i-й неделе после регистрации
+	Retention []float64 `json:"retention"`
+}
+
+// AdImpressionsSummary содержит показы рекламы за период
+type AdImpressionsSummary struct {
+	TotalBreaks             int64                           `json:"total_breaks"`
+	TotalImpressions        int64                           `json:"total_impressions"`
+	TotalSponsorImpressions int64                           `json:"total_sponsor_impressions"`
+	Daily                   []repository.AdImpressionStats  `json:"daily"`
+	TopAssets               []repository.AdAssetImpressions `json:"top_assets"`
+}
+
+// AnalyticsDashboard — агрегированные метрики для админ-панели
+type AnalyticsDashboard struct {
+	Days          int                             `json:"days"`
+	From          time.Time                       `json:"from"`
+	GeneratedAt   time.Time                       `json:"generated_at"`
+	ActiveUsers   ActiveUsersStats                `json:"active_users"`
+	Registrations []repository.DailyCount         `json:"registrations"`
+	Participation []repository.DailyParticipation `json:"participation"`
+	Retention     []RetentionCohort               `json:"retention"`
+	Connections   []repository.DailyAverage       `json:"connections"`
+	AdImpressions AdImpressionsSummary            `json:"ad_impressions"`
+}
+
+// AnalyticsService собирает агрегированную аналитику для администраторов
+type AnalyticsService struct {
+	analyticsRepo repository.AnalyticsRepository
+	cacheRepo     repository.CacheRepository
+}
+
+// NewAnalyticsService создает сервис аналитики
+func NewAnalyticsService(analyticsRepo repository.AnalyticsRepository, cacheRepo repository.CacheRepository) (*AnalyticsService, error) {
+	if analyticsRepo == nil {
+		return nil, fmt.Errorf("analytics repository is required")
+	}
+	return &AnalyticsService{
+		analyticsRepo: analyticsRepo,
+		cacheRepo:     cacheRepo,
+	}, nil
+}
+
+// GetDashboard возвращает метрики за последние days дней. Результат кешируется на 5 минут.
+func (s *AnalyticsService) GetDashboard(days int) (*AnalyticsDashboard, error) {
+	if days < 1 || days > MaxAnalyticsDays {
+		return nil, fmt.Errorf("%w: days must be between 1 and %d", apperrors.ErrValidation, MaxAnalyticsDays)
+	}
+
+	cacheKey := fmt.Sprintf("analytics:dashboard:%d", days)
+	if s.cacheRepo != nil {
+		var cached AnalyticsDashboard
+		err := s.cacheRepo.GetJSON(cacheKey, &cached)
+		if err == nil {
+			return &cached, nil
+		}
+		if !errors.Is(err, apperrors.ErrNotFound) {
+			log.Printf("[AnalyticsService] Ошибка чтения кеша дашборда: %v", err)
+		}
+	}
+
+	dashboard, err := s.buildDashboard(days)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.cacheRepo != nil {
+		if err := s.cacheRepo.SetJSON(cacheKey, dashboard, analyticsCacheTTL); err != nil {
+			log.Printf("[AnalyticsService] Ошибка сохранения дашборда в кеш: %v", err)
+		}
+	}
+	return dashboard, nil
+}
+
+func (s *AnalyticsService) buildDashboard(days int) (*AnalyticsDashboard, error) {
+	now := time.Now().UTC()
+	today := now.Truncate(24 * time.Hour)
+	from := today.AddDate(0, 0, -(days - 1))
+
+	dau, err := s.analyticsRepo.CountActiveUsers(today, now)
+	if err != nil {
+		return nil, err
+	}
+	wau, err := s.analyticsRepo.CountActiveUsers(now.Add(-7*24*time.Hour), now)
+	if err != nil {
+		return nil, err
+	}
+	dailyActive, err := s.analyticsRepo.DailyActiveUsers(from)
+	if err != nil {
+		return nil, err
+	}
+	registrations, err := s.analyticsRepo.DailyRegistrations(from)
+	if err != nil {
+		return nil, err
+	}
+	participation, err := s.analyticsRepo.DailyParticipation(from)
+	if err != nil {
+		return nil, err
+	}
+	cohortSizes, err := s.analyticsRepo.CohortSizes(from)
+	if err != nil {
+		return nil, err
+	}
+	cohortActivity, err := s.analyticsRepo.CohortActivity(from)
+	if err != nil {
+		return nil, err
+	}
+	connections, err := s.analyticsRepo.DailyConcurrentConnections(from)
+	if err != nil {
+		return nil, err
+	}
+	adDaily, err := s.analyticsRepo.DailyAdImpressions(from)
+	if err != nil {
+		return nil, err
+	}
+	topAssets, err := s.analyticsRepo.TopAdAssets(from, analyticsTopAdAssetsLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	ads := AdImpressionsSummary{Daily: adDaily, TopAssets: topAssets}
+	for _, d := range adDaily {
+		ads.TotalBreaks += d.Breaks
+		ads.TotalImpressions += d.Impressions
+		ads.TotalSponsorImpressions += d.SponsorImpressions
+	}
+
+	return &AnalyticsDashboard{
+		Days:        days,
+		From:        from,
+		GeneratedAt: now,
+		ActiveUsers: ActiveUsersStats{
+			DAU:   dau,
+			WAU:   wau,
+			Daily: dailyActive,
+		},
+		Registrations: registrations,
+		Participation: participation,
+		Retention:     buildRetentionCohorts(cohortSizes, cohortActivity),
+		Connections:   connections,
+		AdImpressions: ads,
+	}, nil
+}
+
+// buildRetentionCohorts переводит активность когорт в проценты от размера когорты
+func buildRetentionCohorts(sizes []repository.DailyCount, activity []repository.CohortActivity) []RetentionCohort {
+	cohorts := make([]RetentionCohort, 0, len(sizes))
+	index := make(map[int64]int, len(sizes))
+	for _, size := range sizes {
+		index[size.Day.Unix()] = len(cohorts)
+		cohorts = append(cohorts, RetentionCohort{Cohort: size.Day, Size: size.Count, Retention: []float64{}})
+	}
+
+	for _, a := range activity {
+		i, ok := index[a.Cohort.Unix()]
+		if !ok || a.WeekOffset < 0 || cohorts[i].Size == 0 {
+			continue
+		}
+		cohort := &cohorts[i]
+		for len(cohort.Retention) <= a.WeekOffset {
+			cohort.Retention = append(cohort.Retention, 0)
+		}
+		cohort.Retention[a.WeekOffset] = float64(a.ActiveUsers) * 100 / float64(cohort.Size)
+	}
+	return cohorts
+}
+
+// StartConnectionSampler периодически сохраняет количество WebSocket-подключений экземпляра
+// и удаляет снимки старше 90 дней. Работает до отмены ctx.
+func (s *AnalyticsService) StartConnectionSampler(ctx context.Context, counter ConnectionCounter, instanceID string, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		var lastCleanup time.Time
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				sample := &entity.WSConnectionSample{
+					InstanceID:  instanceID,
+					Connections: counter.ClientCount(),
+					SampledAt:   now,
+				}
+				if err := s.analyticsRepo.RecordConnectionSample(sample); err != nil {
+					log.Printf("[AnalyticsService] Ошибка сохранения снимка подключений: %v", err)
+				}
+
+				if now.Sub(lastCleanup) >= 24*time.Hour {
+					if err := s.analyticsRepo.DeleteConnectionSamplesBefore(now.Add(-connectionSampleRetention)); err != nil {
+						log.Printf("[AnalyticsService] Ошибка удаления устаревших снимков подключений: %v", err)
+					}
+					lastCleanup = now
+				}
+			}
+		}
+	}()
+}
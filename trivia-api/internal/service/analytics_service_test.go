@@ -0,0 +1,43 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+func TestBuildRetentionCohorts(t *testing.T) {
+	week1 := time.Date(2026, 9, 7, 0, 0, 0, 0, time.UTC)
+	week2 := week1.AddDate(0, 0, 7)
+
+	sizes := []repository.DailyCount{
+		{Day: week1, Count: 10},
+		{Day: week2, Count: 4},
+	}
+	activity := []repository.CohortActivity{
+		{Cohort: week1, WeekOffset: 0, ActiveUsers: 10},
+		{Cohort: week1, WeekOffset: 2, ActiveUsers: 3},
+		{Cohort: week2, WeekOffset: 0, ActiveUsers: 2},
+		{Cohort: week2.AddDate(0, 0, 7), WeekOffset: 0, ActiveUsers: 5}, // когорта вне выборки
+	}
+
+	cohorts := buildRetentionCohorts(sizes, activity)
+
+	require.Len(t, cohorts, 2)
+	assert.Equal(t, []float64{100, 0, 30}, cohorts[0].Retention)
+	assert.Equal(t, int64(4), cohorts[1].Size)
+	assert.Equal(t, []float64{50}, cohorts[1].Retention)
+}
+
+func TestAnalyticsService_GetDashboard_RejectsInvalidDays(t *testing.T) {
+	svc := &AnalyticsService{}
+
+	for _, days := range []int{0, -1, MaxAnalyticsDays + 1} {
+		_, err := svc.GetDashboard(days)
+		assert.ErrorIs(t, err, apperrors.ErrValidation, "days=%d", days)
+	}
+}
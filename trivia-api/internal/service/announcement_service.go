@@ -0,0 +1,285 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/service/quizmanager"
+)
+
+// Подстановки в тексте объявления ведущего
+const (
+	AnnouncementVarTitle          = "{title}"
+	AnnouncementVarParticipants   = "{participants}"     // игроков в эфире (до старта — в зале ожидания)
+	AnnouncementVarPrizeFund      = "{prize_fund}"       // призовой фонд викторины
+	AnnouncementVarPrizeShare     = "{prize_share}"      // доля фонда на игрока, если все оставшиеся победят
+	AnnouncementVarMinutesToStart = "{minutes_to_start}" // до начала, с округлением вверх
+	AnnouncementVarQuestion       = "{question}"         // номер отыгранного или финального вопроса
+	AnnouncementVarTotalQuestions = "{total_questions}"
+	AnnouncementVarQuestionsLeft  = "{questions_left}" // сколько вопросов ещё впереди
+)
+
+// AnnouncementVars — допустимые подстановки; неизвестная подстановка в шаблоне — ошибка
+var AnnouncementVars = []string{
+	AnnouncementVarTitle,
+	AnnouncementVarParticipants,
+	AnnouncementVarPrizeFund,
+	AnnouncementVarPrizeShare,
+	AnnouncementVarMinutesToStart,
+	AnnouncementVarQuestion,
+	AnnouncementVarTotalQuestions,
+	AnnouncementVarQuestionsLeft,
+}
+
+var announcementVarPattern = regexp.MustCompile(`\{[a-z_]+\}`)
+
+// autoAnnouncementKeyTTL — сколько помнить об отправке автоматического объявления викторины
+const autoAnnouncementKeyTTL = 24 * time.Hour
+
+// AnnouncementInput — объявление ведущего, заданное администратором или в конфигурации
+type AnnouncementInput struct {
+	Trigger        string // entity.AnnouncementTrigger*
+	OffsetSec      int    // before_start: за сколько секунд до начала
+	QuestionNumber int    // after_question: после какого вопроса
+	Message        string // шаблон с подстановками AnnouncementVars
+}
+
+// AnnouncementConfig содержит настройки объявлений ведущего
+type AnnouncementConfig struct {
+	Automatic []AnnouncementInput // объявления каждой викторины, вдобавок к заданным администратором
+}
+
+// AnnouncementService ведёт объявления ведущего: администратор привязывает их к моментам
+// викторины (за N секунд до начала, после вопроса N, перед финальным вопросом), а QuizManager
+// рассылает наступившие как quiz:announcement. Каждое объявление уходит в эфир один раз:
+// заданные администратором помечаются в БД, автоматические — ключом в Redis.
+type AnnouncementService struct {
+	repo      repository.QuizAnnouncementRepository
+	quizRepo  repository.QuizRepository
+	cacheRepo repository.CacheRepository
+	automatic []AnnouncementInput
+	now       func() time.Time
+}
+
+// NewAnnouncementService создает сервис объявлений; некорректное автоматическое объявление — ошибка конфигурации
+func NewAnnouncementService(repo repository.QuizAnnouncementRepository, quizRepo repository.QuizRepository, cacheRepo repository.CacheRepository, cfg AnnouncementConfig) (*AnnouncementService, error) {
+	automatic := make([]AnnouncementInput, 0, len(cfg.Automatic))
+	for i, input := range cfg.Automatic {
+		input, err := normalizeAnnouncement(input)
+		if err != nil {
+			return nil, fmt.Errorf("automatic announcement #%d: %w", i+1, err)
+		}
+		automatic = append(automatic, input)
+	}
+	return &AnnouncementService{
+		repo:      repo,
+		quizRepo:  quizRepo,
+		cacheRepo: cacheRepo,
+		automatic: automatic,
+		now:       time.Now,
+	}, nil
+}
+
+// normalizeAnnouncement проверяет объявление и обнуляет поля, не относящиеся к его моменту
+func normalizeAnnouncement(input AnnouncementInput) (AnnouncementInput, error) {
+	input.Message = strings.TrimSpace(input.Message)
+	switch input.Trigger {
+	case entity.AnnouncementTriggerBeforeStart:
+		maxOffset := int(quizmanager.MaxLobbyAnnouncementOffset / time.Second)
+		if input.OffsetSec < 1 || input.OffsetSec > maxOffset {
+			return input, fmt.Errorf("%w: offset_sec must be between 1 and %d", apperrors.ErrValidation, maxOffset)
+		}
+		input.QuestionNumber = 0
+	case entity.AnnouncementTriggerAfterQuestion:
+		if input.QuestionNumber < 1 {
+			return input, fmt.Errorf("%w: question_number must be at least 1", apperrors.ErrValidation)
+		}
+		input.OffsetSec = 0
+	case entity.AnnouncementTriggerBeforeFinale:
+		input.OffsetSec, input.QuestionNumber = 0, 0
+	default:
+		return input, fmt.Errorf("%w: unknown trigger %q", apperrors.ErrValidation, input.Trigger)
+	}
+
+	if input.Message == "" {
+		return input, fmt.Errorf("%w: message is required", apperrors.ErrValidation)
+	}
+	if utf8.RuneCountInString(input.Message) > quizmanager.MaxAnnouncementLength {
+		return input, fmt.Errorf("%w: message must be at most %d characters", apperrors.ErrValidation, quizmanager.MaxAnnouncementLength)
+	}
+	for _, placeholder := range announcementVarPattern.FindAllString(input.Message, -1) {
+		if !isAnnouncementVar(placeholder) {
+			return input, fmt.Errorf("%w: unknown placeholder %s", apperrors.ErrValidation, placeholder)
+		}
+	}
+	return input, nil
+}
+
+func isAnnouncementVar(placeholder string) bool {
+	for _, v := range AnnouncementVars {
+		if v == placeholder {
+			return true
+		}
+	}
+	return false
+}
+
+// ListAnnouncements возвращает объявления викторины
+func (s *AnnouncementService) ListAnnouncements(quizID uint) ([]entity.QuizAnnouncement, error) {
+	if _, err := s.quizRepo.GetByID(quizID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByQuizID(quizID)
+}
+
+// GetAnnouncement возвращает объявление викторины
+func (s *AnnouncementService) GetAnnouncement(quizID, id uint) (*entity.QuizAnnouncement, error) {
+	return s.repo.GetByID(quizID, id)
+}
+
+// CreateAnnouncement добавляет объявление к викторине, которая ещё не завершилась
+func (s *AnnouncementService) CreateAnnouncement(quizID uint, input AnnouncementInput) (*entity.QuizAnnouncement, error) {
+	input, err := normalizeAnnouncement(input)
+	if err != nil {
+		return nil, err
+	}
+	quiz, err := s.quizRepo.GetByID(quizID)
+	if err != nil {
+		return nil, err
+	}
+	if quiz.IsCompleted() || quiz.Status == entity.QuizStatusCancelled {
+		return nil, fmt.Errorf("%w: quiz is already over", apperrors.ErrConflict)
+	}
+
+	announcement := &entity.QuizAnnouncement{
+		QuizID:         quizID,
+		Trigger:        input.Trigger,
+		OffsetSec:      input.OffsetSec,
+		QuestionNumber: input.QuestionNumber,
+		Message:        input.Message,
+	}
+	if err := s.repo.Create(announcement); err != nil {
+		return nil, err
+	}
+	return announcement, nil
+}
+
+// UpdateAnnouncement заменяет момент и текст объявления; отправленное объявление не меняется
+func (s *AnnouncementService) UpdateAnnouncement(quizID, id uint, input AnnouncementInput) (*entity.QuizAnnouncement, error) {
+	input, err := normalizeAnnouncement(input)
+	if err != nil {
+		return nil, err
+	}
+	announcement, err := s.repo.GetByID(quizID, id)
+	if err != nil {
+		return nil, err
+	}
+	if announcement.SentAt != nil {
+		return nil, fmt.Errorf("%w: announcement has already been sent", apperrors.ErrConflict)
+	}
+	announcement.Trigger = input.Trigger
+	announcement.OffsetSec = input.OffsetSec
+	announcement.QuestionNumber = input.QuestionNumber
+	announcement.Message = input.Message
+	if err := s.repo.Update(announcement); err != nil {
+		return nil, err
+	}
+	return announcement, nil
+}
+
+// DeleteAnnouncement удаляет объявление викторины
+func (s *AnnouncementService) DeleteAnnouncement(quizID, id uint) error {
+	return s.repo.Delete(quizID, id)
+}
+
+// DueAnnouncements возвращает тексты объявлений, наступивших к моменту викторины, и помечает
+// их отправленными (реализует quizmanager.Announcer). Сначала идут объявления администратора,
+// затем автоматические.
+func (s *AnnouncementService) DueAnnouncements(quiz *entity.Quiz, milestone quizmanager.AnnouncementMilestone) ([]string, error) {
+	pending, err := s.repo.ListPending(quiz.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []string
+	now := s.now()
+	for _, announcement := range pending {
+		if !announcementDue(announcement.Trigger, announcement.OffsetSec, announcement.QuestionNumber, milestone) {
+			continue
+		}
+		claimed, err := s.repo.MarkSent(announcement.ID, now)
+		if err != nil {
+			return messages, err
+		}
+		if claimed {
+			messages = append(messages, renderAnnouncement(announcement.Message, quiz, milestone))
+		}
+	}
+
+	for i, input := range s.automatic {
+		if !announcementDue(input.Trigger, input.OffsetSec, input.QuestionNumber, milestone) {
+			continue
+		}
+		key := fmt.Sprintf("quiz:%d:announcement:auto:%d", quiz.ID, i)
+		claimed, err := s.cacheRepo.SetNX(key, now.UnixMilli(), autoAnnouncementKeyTTL)
+		if err != nil {
+			return messages, err
+		}
+		if claimed {
+			messages = append(messages, renderAnnouncement(input.Message, quiz, milestone))
+		}
+	}
+
+	if len(messages) > 0 {
+		log.Printf("[AnnouncementService] Викторина #%d: %d объявлений ведущего (%s)", quiz.ID, len(messages), milestone.Trigger)
+	}
+	return messages, nil
+}
+
+// announcementDue сообщает, наступил ли момент объявления. Объявление до начала наступает,
+// как только до старта осталось не больше OffsetSec: если викторину запланировали позже,
+// оно уходит сразу.
+func announcementDue(trigger string, offsetSec, questionNumber int, milestone quizmanager.AnnouncementMilestone) bool {
+	if trigger != milestone.Trigger {
+		return false
+	}
+	switch trigger {
+	case entity.AnnouncementTriggerBeforeStart:
+		return milestone.SecondsToStart <= offsetSec
+	case entity.AnnouncementTriggerAfterQuestion:
+		return milestone.QuestionNumber == questionNumber
+	default:
+		return true
+	}
+}
+
+// renderAnnouncement подставляет в шаблон значения на момент викторины
+func renderAnnouncement(message string, quiz *entity.Quiz, milestone quizmanager.AnnouncementMilestone) string {
+	questionsLeft := milestone.TotalQuestions - milestone.QuestionNumber
+	if milestone.Trigger == entity.AnnouncementTriggerBeforeFinale {
+		questionsLeft = 1 // сам финальный вопрос
+	}
+	prizeShare := quiz.PrizeFund
+	if milestone.Participants > 1 {
+		prizeShare = quiz.PrizeFund / milestone.Participants
+	}
+
+	return strings.NewReplacer(
+		AnnouncementVarTitle, quiz.Title,
+		AnnouncementVarParticipants, strconv.Itoa(milestone.Participants),
+		AnnouncementVarPrizeFund, strconv.Itoa(quiz.PrizeFund),
+		AnnouncementVarPrizeShare, strconv.Itoa(prizeShare),
+		AnnouncementVarMinutesToStart, strconv.Itoa((max(milestone.SecondsToStart, 0)+59)/60),
+		AnnouncementVarQuestion, strconv.Itoa(milestone.QuestionNumber),
+		AnnouncementVarTotalQuestions, strconv.Itoa(milestone.TotalQuestions),
+		AnnouncementVarQuestionsLeft, strconv.Itoa(max(questionsLeft, 0)),
+	).Replace(message)
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/service/quizmanager"
+)
+
+// fakeAnnouncementRepo хранит объявления ведущего в памяти
+type fakeAnnouncementRepo struct {
+	items  []*entity.QuizAnnouncement
+	nextID uint
+}
+
+func (r *fakeAnnouncementRepo) Create(announcement *entity.QuizAnnouncement) error {
+	r.nextID++
+	announcement.ID = r.nextID
+	r.items = append(r.items, announcement)
+	return nil
+}
+
+func (r *fakeAnnouncementRepo) GetByID(quizID, id uint) (*entity.QuizAnnouncement, error) {
+	for _, a := range r.items {
+		if a.ID == id && a.QuizID == quizID {
+			copied := *a
+			return &copied, nil
+		}
+	}
+	return nil, apperrors.ErrNotFound
+}
+
+func (r *fakeAnnouncementRepo) ListByQuizID(quizID uint) ([]entity.QuizAnnouncement, error) {
+	var result []entity.QuizAnnouncement
+	for _, a := range r.items {
+		if a.QuizID == quizID {
+			result = append(result, *a)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeAnnouncementRepo) ListPending(quizID uint) ([]entity.QuizAnnouncement, error) {
+	var result []entity.QuizAnnouncement
+	for _, a := range r.items {
+		if a.QuizID == quizID && a.SentAt == nil {
+			result = append(result, *a)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeAnnouncementRepo) Update(announcement *entity.QuizAnnouncement) error {
+	for i, a := range r.items {
+		if a.ID == announcement.ID {
+			copied := *announcement
+			r.items[i] = &copied
+			return nil
+		}
+	}
+	return apperrors.ErrNotFound
+}
+
+func (r *fakeAnnouncementRepo) Delete(quizID, id uint) error {
+	for i, a := range r.items {
+		if a.ID == id && a.QuizID == quizID {
+			r.items = append(r.items[:i], r.items[i+1:]...)
+			return nil
+		}
+	}
+	return apperrors.ErrNotFound
+}
+
+func (r *fakeAnnouncementRepo) MarkSent(id uint, sentAt time.Time) (bool, error) {
+	for _, a := range r.items {
+		if a.ID == id {
+			if a.SentAt != nil {
+				return false, nil
+			}
+			a.SentAt = &sentAt
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func newTestAnnouncementService(t *testing.T, quiz *entity.Quiz, automatic ...AnnouncementInput) (*AnnouncementService, *fakeAnnouncementRepo) {
+	quizRepo := new(MockQuizRepository)
+	quizRepo.On("GetByID", quiz.ID).Return(quiz, nil)
+	repo := &fakeAnnouncementRepo{}
+	cache := &memorySecondChanceCache{values: make(map[string]string)}
+	svc, err := NewAnnouncementService(repo, quizRepo, cache, AnnouncementConfig{Automatic: automatic})
+	require.NoError(t, err)
+	return svc, repo
+}
+
+func TestAnnouncementService_Validation(t *testing.T) {
+	quiz := &entity.Quiz{ID: 1, Status: entity.QuizStatusScheduled}
+	svc, _ := newTestAnnouncementService(t, quiz)
+
+	cases := []AnnouncementInput{
+		{Trigger: "halftime", Message: "Привет"},
+		{Trigger: entity.AnnouncementTriggerBeforeStart, OffsetSec: 0, Message: "Скоро старт"},
+		{Trigger: entity.AnnouncementTriggerBeforeStart, OffsetSec: 7200, Message: "Скоро старт"},
+		{Trigger: entity.AnnouncementTriggerAfterQuestion, Message: "Отлично"},
+		{Trigger: entity.AnnouncementTriggerBeforeFinale, Message: "  "},
+		{Trigger: entity.AnnouncementTriggerBeforeFinale, Message: "Приз {jackpot}"},
+	}
+	for _, input := range cases {
+		_, err := svc.CreateAnnouncement(quiz.ID, input)
+		assert.ErrorIs(t, err, apperrors.ErrValidation, "%+v", input)
+	}
+
+	// Поля другого момента обнуляются
+	announcement, err := svc.CreateAnnouncement(quiz.ID, AnnouncementInput{
+		Trigger: entity.AnnouncementTriggerBeforeFinale, OffsetSec: 30, QuestionNumber: 4, Message: " Финал! ",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, announcement.OffsetSec)
+	assert.Equal(t, 0, announcement.QuestionNumber)
+	assert.Equal(t, "Финал!", announcement.Message)
+
+	// Завершённой викторине объявления не добавляются
+	quiz.Status = entity.QuizStatusCompleted
+	_, err = svc.CreateAnnouncement(quiz.ID, AnnouncementInput{Trigger: entity.AnnouncementTriggerBeforeFinale, Message: "Финал"})
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+
+	// Автоматические объявления проверяются при запуске
+	_, err = NewAnnouncementService(&fakeAnnouncementRepo{}, nil, nil, AnnouncementConfig{
+		Automatic: []AnnouncementInput{{Trigger: entity.AnnouncementTriggerBeforeStart, Message: "Скоро"}},
+	})
+	assert.ErrorContains(t, err, "automatic announcement #1")
+}
+
+func TestAnnouncementService_DueAnnouncements(t *testing.T) {
+	quiz := &entity.Quiz{ID: 7, Title: "Вечерний эфир", Status: entity.QuizStatusScheduled, PrizeFund: 100000}
+	svc, repo := newTestAnnouncementService(t, quiz, AnnouncementInput{
+		Trigger: entity.AnnouncementTriggerBeforeFinale,
+		Message: "Финал! В игре {participants}, каждому по {prize_share}",
+	})
+
+	_, err := svc.CreateAnnouncement(quiz.ID, AnnouncementInput{
+		Trigger: entity.AnnouncementTriggerBeforeStart, OffsetSec: 300,
+		Message: "«{title}» через {minutes_to_start} мин, в зале {participants}, фонд {prize_fund}",
+	})
+	require.NoError(t, err)
+	afterFive, err := svc.CreateAnnouncement(quiz.ID, AnnouncementInput{
+		Trigger: entity.AnnouncementTriggerAfterQuestion, QuestionNumber: 5,
+		Message: "Позади {question} из {total_questions}, осталось {questions_left}",
+	})
+	require.NoError(t, err)
+
+	// До T-5min объявление ещё не наступило
+	lobby := quizmanager.AnnouncementMilestone{Trigger: entity.AnnouncementTriggerBeforeStart, SecondsToStart: 400, Participants: 120}
+	messages, err := svc.DueAnnouncements(quiz, lobby)
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+
+	lobby.SecondsToStart = 295
+	messages, err = svc.DueAnnouncements(quiz, lobby)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"«Вечерний эфир» через 5 мин, в зале 120, фонд 100000"}, messages)
+
+	// Повторный опрос не рассылает его снова
+	lobby.SecondsToStart = 290
+	messages, err = svc.DueAnnouncements(quiz, lobby)
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+
+	// После вопроса 4 — ничего, после вопроса 5 — объявление, и только один раз (переигровка вопроса)
+	afterQuestion := quizmanager.AnnouncementMilestone{Trigger: entity.AnnouncementTriggerAfterQuestion, QuestionNumber: 4, TotalQuestions: 10}
+	messages, err = svc.DueAnnouncements(quiz, afterQuestion)
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+	afterQuestion.QuestionNumber = 5
+	messages, err = svc.DueAnnouncements(quiz, afterQuestion)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Позади 5 из 10, осталось 5"}, messages)
+	messages, err = svc.DueAnnouncements(quiz, afterQuestion)
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+
+	// Отправленное объявление нельзя изменить
+	_, err = svc.UpdateAnnouncement(quiz.ID, afterFive.ID, AnnouncementInput{Trigger: entity.AnnouncementTriggerAfterQuestion, QuestionNumber: 6, Message: "Ещё"})
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+	assert.NotNil(t, repo.items[1].SentAt)
+
+	// Автоматическое объявление перед финалом тоже уходит один раз
+	finale := quizmanager.AnnouncementMilestone{Trigger: entity.AnnouncementTriggerBeforeFinale, QuestionNumber: 10, TotalQuestions: 10, Participants: 8}
+	messages, err = svc.DueAnnouncements(quiz, finale)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Финал! В игре 8, каждому по 12500"}, messages)
+	messages, err = svc.DueAnnouncements(quiz, finale)
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+}
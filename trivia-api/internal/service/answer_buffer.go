@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// Буфер отложенной записи ответов (write-behind). На пике викторины тысячи участников
+// отвечают на вопрос почти одновременно; вместо INSERT на каждый ответ буфер пишет их
+// пачками. Пачка, которую не удалось записать в PostgreSQL, сохраняется в Redis
+// и дописывается позже. Повторная запись безопасна: ответы на уже сохранённую пару
+// (пользователь, вопрос) пропускаются по уникальному индексу.
+const (
+	// answerSpillSetKey — множество ключей пачек, ожидающих записи в БД
+	answerSpillSetKey = "answers:spill"
+	answerSpillKey    = "answers:spill:%d"
+	answerSpillTTL    = 24 * time.Hour
+
+	// answerSpillRetryInterval — как часто повторять запись отложенных в Redis пачек
+	answerSpillRetryInterval = 5 * time.Second
+)
+
+// AnswerBufferConfig содержит настройки буфера записи ответов
+type AnswerBufferConfig struct {
+	BatchSize     int           // размер пачки, при котором запись начинается не дожидаясь интервала
+	FlushInterval time.Duration // максимальная задержка записи ответа
+}
+
+// AnswerBuffer накапливает ответы пользователей и записывает их в БД пачками.
+// Реализует quizmanager.AnswerWriter.
+type AnswerBuffer struct {
+	repo  repository.ResultRepository
+	cache repository.CacheRepository
+	cfg   AnswerBufferConfig
+
+	mu      sync.Mutex
+	pending []entity.UserAnswer
+	closed  bool
+
+	// flushMu упорядочивает записи: пачки уходят в БД по одной
+	flushMu sync.Mutex
+
+	kick chan struct{}
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewAnswerBuffer создает буфер и запускает фоновую запись
+func NewAnswerBuffer(repo repository.ResultRepository, cache repository.CacheRepository, cfg AnswerBufferConfig) *AnswerBuffer {
+	b := &AnswerBuffer{
+		repo:    repo,
+		cache:   cache,
+		cfg:     cfg,
+		pending: make([]entity.UserAnswer, 0, cfg.BatchSize),
+		kick:    make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+// Enqueue ставит ответ в очередь на запись. После Close ответ записывается сразу.
+func (b *AnswerBuffer) Enqueue(ctx context.Context, answer *entity.UserAnswer) error {
+	if answer.CreatedAt.IsZero() {
+		// Время приёма ответа, а не записи пачки: по нему упорядочиваются ответы пользователя
+		answer.CreatedAt = time.Now()
+	}
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return b.repo.WithContext(ctx).SaveUserAnswer(answer)
+	}
+	b.pending = append(b.pending, *answer)
+	full := len(b.pending) >= b.cfg.BatchSize
+	b.mu.Unlock()
+
+	if full {
+		select {
+		case b.kick <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// Flush записывает накопленные ответы и отложенные в Redis пачки.
+// Вызывается перед подсчётом результатов викторины, чтобы расчёт видел все ответы.
+func (b *AnswerBuffer) Flush() error {
+	if err := b.flushPending(); err != nil {
+		return err
+	}
+	return b.replaySpilled()
+}
+
+// Close останавливает фоновую запись и записывает оставшиеся ответы
+func (b *AnswerBuffer) Close() {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	b.closed = true
+	b.mu.Unlock()
+
+	close(b.stop)
+	<-b.done
+}
+
+func (b *AnswerBuffer) run() {
+	defer close(b.done)
+
+	flushTicker := time.NewTicker(b.cfg.FlushInterval)
+	defer flushTicker.Stop()
+	retryTicker := time.NewTicker(answerSpillRetryInterval)
+	defer retryTicker.Stop()
+
+	for {
+		select {
+		case <-b.stop:
+			if err := b.flushPending(); err != nil {
+				log.Printf("[AnswerBuffer] CRITICAL: Не удалось записать ответы при остановке: %v", err)
+			}
+			return
+		case <-b.kick:
+			b.logFlushError(b.flushPending())
+		case <-flushTicker.C:
+			b.logFlushError(b.flushPending())
+		case <-retryTicker.C:
+			b.logFlushError(b.replaySpilled())
+		}
+	}
+}
+
+func (b *AnswerBuffer) logFlushError(err error) {
+	if err != nil {
+		log.Printf("[AnswerBuffer] Ошибка записи ответов: %v", err)
+	}
+}
+
+// flushPending записывает накопленные ответы одной пачкой. Если БД недоступна,
+// пачка откладывается в Redis; если недоступен и Redis, ответы возвращаются в буфер.
+func (b *AnswerBuffer) flushPending() error {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+
+	b.mu.Lock()
+	batch := b.pending
+	b.pending = make([]entity.UserAnswer, 0, b.cfg.BatchSize)
+	b.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	_, err := b.repo.SaveUserAnswersBatch(batch)
+	if err == nil {
+		return nil
+	}
+	log.Printf("[AnswerBuffer] Ошибка записи пачки из %d ответов в БД, откладываем в Redis: %v", len(batch), err)
+
+	if errSpill := b.spill(batch); errSpill != nil {
+		b.mu.Lock()
+		b.pending = append(batch, b.pending...)
+		b.mu.Unlock()
+		return fmt.Errorf("failed to save answers batch: %w; failed to spill to redis: %v", err, errSpill)
+	}
+	return nil
+}
+
+// spill сохраняет пачку в Redis до восстановления БД
+func (b *AnswerBuffer) spill(batch []entity.UserAnswer) error {
+	key := fmt.Sprintf(answerSpillKey, time.Now().UnixNano())
+	if err := b.cache.SetJSON(key, batch, answerSpillTTL); err != nil {
+		return err
+	}
+	return b.cache.SAdd(answerSpillSetKey, key)
+}
+
+// replaySpilled дописывает в БД пачки, отложенные в Redis этим или другим инстансом
+func (b *AnswerBuffer) replaySpilled() error {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+
+	keys, err := b.cache.SMembers(answerSpillSetKey)
+	if err != nil {
+		return fmt.Errorf("failed to list spilled answers: %w", err)
+	}
+	for _, key := range keys {
+		var batch []entity.UserAnswer
+		if err := b.cache.GetJSON(key, &batch); err != nil && !errors.Is(err, apperrors.ErrNotFound) {
+			return fmt.Errorf("failed to read spilled answers %s: %w", key, err)
+		}
+		// Пачку без данных (истекла или уже записана другим инстансом) просто снимаем с учёта
+		if len(batch) > 0 {
+			inserted, err := b.repo.SaveUserAnswersBatch(batch)
+			if err != nil {
+				return fmt.Errorf("failed to replay spilled answers %s: %w", key, err)
+			}
+			log.Printf("[AnswerBuffer] Отложенная пачка %s записана: %d из %d ответов", key, inserted, len(batch))
+		}
+		if err := b.cache.Delete(key); err != nil {
+			log.Printf("[AnswerBuffer] WARNING: Не удалось удалить отложенную пачку %s: %v", key, err)
+		}
+		if err := b.cache.SRem(answerSpillSetKey, key); err != nil {
+			log.Printf("[AnswerBuffer] WARNING: Не удалось снять отложенную пачку %s с учёта: %v", key, err)
+		}
+	}
+	return nil
+}
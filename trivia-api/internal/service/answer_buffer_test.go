@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+// memorySpillCache — memoryQuizCache с множествами для учёта отложенных пачек
+type memorySpillCache struct {
+	*memoryQuizCache
+	sets map[string]map[string]bool
+}
+
+func newMemorySpillCache() *memorySpillCache {
+	return &memorySpillCache{
+		memoryQuizCache: &memoryQuizCache{values: map[string][]byte{}},
+		sets:            map[string]map[string]bool{},
+	}
+}
+
+func (m *memorySpillCache) SAdd(key string, members ...interface{}) error {
+	if m.sets[key] == nil {
+		m.sets[key] = map[string]bool{}
+	}
+	for _, member := range members {
+		m.sets[key][member.(string)] = true
+	}
+	return nil
+}
+
+func (m *memorySpillCache) SMembers(key string) ([]string, error) {
+	members := make([]string, 0, len(m.sets[key]))
+	for member := range m.sets[key] {
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+func (m *memorySpillCache) SRem(key string, members ...interface{}) error {
+	for _, member := range members {
+		delete(m.sets[key], member.(string))
+	}
+	return nil
+}
+
+func newTestAnswerBuffer(repo *MockResultRepoForResultService, cache *memorySpillCache) *AnswerBuffer {
+	// Интервал больше времени теста: запись только по Flush
+	return NewAnswerBuffer(repo, cache, AnswerBufferConfig{BatchSize: 100, FlushInterval: time.Hour})
+}
+
+func TestAnswerBuffer_FlushWritesBatch(t *testing.T) {
+	repo := new(MockResultRepoForResultService)
+	cache := newMemorySpillCache()
+	buffer := newTestAnswerBuffer(repo, cache)
+	defer buffer.Close()
+
+	repo.On("SaveUserAnswersBatch", mock.MatchedBy(func(answers []entity.UserAnswer) bool {
+		return len(answers) == 2 && answers[0].UserID == 1 && answers[1].UserID == 2
+	})).Return(int64(2), nil).Once()
+
+	require.NoError(t, buffer.Enqueue(context.Background(), &entity.UserAnswer{UserID: 1, QuizID: 1, QuestionID: 10}))
+	require.NoError(t, buffer.Enqueue(context.Background(), &entity.UserAnswer{UserID: 2, QuizID: 1, QuestionID: 10}))
+	require.NoError(t, buffer.Flush())
+
+	// Пустой буфер повторно ничего не пишет
+	require.NoError(t, buffer.Flush())
+	repo.AssertExpectations(t)
+}
+
+func TestAnswerBuffer_SpillsToRedisWhenDatabaseFails(t *testing.T) {
+	repo := new(MockResultRepoForResultService)
+	cache := newMemorySpillCache()
+	buffer := newTestAnswerBuffer(repo, cache)
+	defer buffer.Close()
+
+	repo.On("SaveUserAnswersBatch", mock.Anything).Return(int64(0), errors.New("connection refused")).Once()
+
+	require.NoError(t, buffer.Enqueue(context.Background(), &entity.UserAnswer{UserID: 1, QuizID: 1, QuestionID: 10}))
+	require.NoError(t, buffer.flushPending())
+
+	spilled, _ := cache.SMembers(answerSpillSetKey)
+	require.Len(t, spilled, 1)
+
+	// После восстановления БД отложенная пачка дописывается и снимается с учёта
+	repo.On("SaveUserAnswersBatch", mock.MatchedBy(func(answers []entity.UserAnswer) bool {
+		return len(answers) == 1 && answers[0].UserID == 1 && !answers[0].CreatedAt.IsZero()
+	})).Return(int64(1), nil).Once()
+	require.NoError(t, buffer.Flush())
+
+	spilled, _ = cache.SMembers(answerSpillSetKey)
+	assert.Empty(t, spilled)
+	assert.Empty(t, cache.values)
+	repo.AssertExpectations(t)
+}
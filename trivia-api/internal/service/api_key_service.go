@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+const (
+	// apiKeyPrefix отличает ключи API от других секретов (например, при поиске утечек в коде)
+	apiKeyPrefix = "trv_"
+	// apiKeyDisplayLength — сколько первых символов ключа хранится для опознания в админ-панели
+	apiKeyDisplayLength = 12
+	// apiKeyMaxUsageDays — за сколько дней отдаётся статистика использования
+	apiKeyMaxUsageDays = 90
+)
+
+// ErrAPIKeyInvalid — ключ не найден, отозван или истёк
+var ErrAPIKeyInvalid = fmt.Errorf("%w: invalid api key", apperrors.ErrUnauthorized)
+
+// APIKeyInput — параметры выпускаемого ключа
+type APIKeyInput struct {
+	Name      string
+	Scopes    []string
+	RateLimit int // запросов в окне группы api_keys; 0 — лимит группы
+	ExpiresAt *time.Time
+}
+
+// apiKeyUsageKey — ключ счётчиков в памяти: ключ API и сутки (UTC)
+type apiKeyUsageKey struct {
+	keyID uint
+	day   time.Time
+}
+
+// APIKeyService выпускает и проверяет API-ключи партнёров. Статистика запросов копится
+// в памяти и периодически прибавляется к суточным счётчикам в БД, чтобы не писать в БД
+// на каждый запрос.
+type APIKeyService struct {
+	repo repository.APIKeyRepository
+
+	usageMu  sync.Mutex
+	usage    map[apiKeyUsageKey]*entity.APIKeyUsage
+	lastUsed map[uint]time.Time
+}
+
+// NewAPIKeyService создает сервис API-ключей
+func NewAPIKeyService(repo repository.APIKeyRepository) *APIKeyService {
+	return &APIKeyService{
+		repo:     repo,
+		usage:    make(map[apiKeyUsageKey]*entity.APIKeyUsage),
+		lastUsed: make(map[uint]time.Time),
+	}
+}
+
+// CreateKey выпускает ключ. Возвращает сам ключ: он показывается только при создании,
+// в БД хранится лишь его хеш.
+func (s *APIKeyService) CreateKey(input APIKeyInput, createdBy uint) (*entity.APIKey, string, error) {
+	if err := validateAPIKeyInput(input); err != nil {
+		return nil, "", err
+	}
+	rawKey, err := generateAPIKey()
+	if err != nil {
+		return nil, "", err
+	}
+
+	key := &entity.APIKey{
+		Name:      strings.TrimSpace(input.Name),
+		Prefix:    rawKey[:apiKeyDisplayLength],
+		KeyHash:   hashAPIKey(rawKey),
+		Scopes:    entity.StringArray(input.Scopes),
+		RateLimit: input.RateLimit,
+		CreatedBy: createdBy,
+		ExpiresAt: input.ExpiresAt,
+	}
+	if err := s.repo.Create(key); err != nil {
+		return nil, "", err
+	}
+	return key, rawKey, nil
+}
+
+// GetKey возвращает ключ по ID
+func (s *APIKeyService) GetKey(id uint) (*entity.APIKey, error) {
+	return s.repo.GetByID(id)
+}
+
+// ListKeys возвращает все ключи
+func (s *APIKeyService) ListKeys() ([]entity.APIKey, error) {
+	return s.repo.List()
+}
+
+// RevokeKey отзывает ключ; следующий запрос с ним получит 401
+func (s *APIKeyService) RevokeKey(id uint) error {
+	return s.repo.Revoke(id, time.Now().UTC())
+}
+
+// AuthenticateAPIKey находит действующий ключ по его значению из заголовка запроса
+func (s *APIKeyService) AuthenticateAPIKey(ctx context.Context, rawKey string) (*entity.APIKey, error) {
+	if !strings.HasPrefix(rawKey, apiKeyPrefix) {
+		return nil, ErrAPIKeyInvalid
+	}
+	key, err := s.repo.GetByHash(hashAPIKey(rawKey))
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return nil, ErrAPIKeyInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !key.Usable(time.Now()) {
+		return nil, ErrAPIKeyInvalid
+	}
+	return key, nil
+}
+
+// RecordAPIKeyUsage учитывает запрос ключа с итоговым HTTP-статусом ответа
+func (s *APIKeyService) RecordAPIKeyUsage(keyID uint, status int) {
+	now := time.Now().UTC()
+	k := apiKeyUsageKey{keyID: keyID, day: now.Truncate(24 * time.Hour)}
+
+	s.usageMu.Lock()
+	defer s.usageMu.Unlock()
+	counters, ok := s.usage[k]
+	if !ok {
+		counters = &entity.APIKeyUsage{KeyID: keyID, Day: k.day}
+		s.usage[k] = counters
+	}
+	counters.Requests++
+	switch {
+	case status == http.StatusTooManyRequests:
+		counters.RateLimited++
+	case status >= http.StatusBadRequest:
+		counters.Errors++
+	}
+	s.lastUsed[keyID] = now
+}
+
+// Start периодически сбрасывает накопленную статистику в БД. Работает до отмены ctx,
+// при остановке сбрасывает остаток.
+func (s *APIKeyService) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				if err := s.FlushUsage(); err != nil {
+					log.Printf("[APIKeyService] Ошибка сохранения статистики ключей при остановке: %v", err)
+				}
+				return
+			case <-ticker.C:
+				if err := s.FlushUsage(); err != nil {
+					log.Printf("[APIKeyService] Ошибка сохранения статистики ключей: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// FlushUsage сохраняет накопленную статистику. При ошибке счётчики возвращаются в память
+// и будут сохранены следующим проходом.
+func (s *APIKeyService) FlushUsage() error {
+	s.usageMu.Lock()
+	usage, lastUsed := s.usage, s.lastUsed
+	s.usage = make(map[apiKeyUsageKey]*entity.APIKeyUsage)
+	s.lastUsed = make(map[uint]time.Time)
+	s.usageMu.Unlock()
+
+	if len(usage) == 0 {
+		return nil
+	}
+	rows := make([]entity.APIKeyUsage, 0, len(usage))
+	for _, counters := range usage {
+		rows = append(rows, *counters)
+	}
+	if err := s.repo.AddUsage(rows, lastUsed); err != nil {
+		s.restoreUsage(usage, lastUsed)
+		return err
+	}
+	return nil
+}
+
+func (s *APIKeyService) restoreUsage(usage map[apiKeyUsageKey]*entity.APIKeyUsage, lastUsed map[uint]time.Time) {
+	s.usageMu.Lock()
+	defer s.usageMu.Unlock()
+	for k, counters := range usage {
+		if current, ok := s.usage[k]; ok {
+			current.Requests += counters.Requests
+			current.RateLimited += counters.RateLimited
+			current.Errors += counters.Errors
+		} else {
+			s.usage[k] = counters
+		}
+	}
+	for keyID, at := range lastUsed {
+		if at.After(s.lastUsed[keyID]) {
+			s.lastUsed[keyID] = at
+		}
+	}
+}
+
+// GetUsage возвращает суточную статистику ключа за последние days дней (не более 90)
+func (s *APIKeyService) GetUsage(keyID uint, days int) ([]entity.APIKeyUsage, error) {
+	if _, err := s.repo.GetByID(keyID); err != nil {
+		return nil, err
+	}
+	if days < 1 || days > apiKeyMaxUsageDays {
+		days = 30
+	}
+	since := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -(days - 1))
+	return s.repo.ListUsage(keyID, since)
+}
+
+func validateAPIKeyInput(input APIKeyInput) error {
+	if strings.TrimSpace(input.Name) == "" {
+		return fmt.Errorf("%w: name is required", apperrors.ErrValidation)
+	}
+	if len(input.Scopes) == 0 {
+		return fmt.Errorf("%w: at least one scope is required", apperrors.ErrValidation)
+	}
+	for _, scope := range input.Scopes {
+		known := false
+		for _, s := range entity.APIKeyScopes {
+			if scope == s {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("%w: unknown scope %q", apperrors.ErrValidation, scope)
+		}
+	}
+	if input.RateLimit < 0 {
+		return fmt.Errorf("%w: rate_limit must not be negative", apperrors.ErrValidation)
+	}
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now()) {
+		return fmt.Errorf("%w: expires_at must be in the future", apperrors.ErrValidation)
+	}
+	return nil
+}
+
+func generateAPIKey() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+	return apiKeyPrefix + hex.EncodeToString(buf), nil
+}
+
+// hashAPIKey — SHA-256 без соли: ключ — 192 случайных бита, подбор по хешу невозможен,
+// а детерминированный хеш позволяет искать ключ по индексу
+func hashAPIKey(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
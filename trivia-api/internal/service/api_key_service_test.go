@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// fakeAPIKeyRepo — APIKeyRepository в памяти
+type fakeAPIKeyRepo struct {
+	keys     []entity.APIKey
+	usage    map[apiKeyUsageKey]entity.APIKeyUsage
+	addErr   error
+	lastUsed map[uint]time.Time
+}
+
+func newFakeAPIKeyRepo() *fakeAPIKeyRepo {
+	return &fakeAPIKeyRepo{usage: map[apiKeyUsageKey]entity.APIKeyUsage{}, lastUsed: map[uint]time.Time{}}
+}
+
+func (f *fakeAPIKeyRepo) Create(key *entity.APIKey) error {
+	key.ID = uint(len(f.keys) + 1)
+	f.keys = append(f.keys, *key)
+	return nil
+}
+
+func (f *fakeAPIKeyRepo) GetByID(id uint) (*entity.APIKey, error) {
+	if id == 0 || int(id) > len(f.keys) {
+		return nil, apperrors.ErrNotFound
+	}
+	key := f.keys[id-1]
+	return &key, nil
+}
+
+func (f *fakeAPIKeyRepo) GetByHash(keyHash string) (*entity.APIKey, error) {
+	for _, key := range f.keys {
+		if key.KeyHash == keyHash {
+			return &key, nil
+		}
+	}
+	return nil, apperrors.ErrNotFound
+}
+
+func (f *fakeAPIKeyRepo) List() ([]entity.APIKey, error) { return f.keys, nil }
+
+func (f *fakeAPIKeyRepo) Revoke(id uint, at time.Time) error {
+	if id == 0 || int(id) > len(f.keys) || f.keys[id-1].RevokedAt != nil {
+		return apperrors.ErrNotFound
+	}
+	f.keys[id-1].RevokedAt = &at
+	return nil
+}
+
+func (f *fakeAPIKeyRepo) AddUsage(usage []entity.APIKeyUsage, lastUsed map[uint]time.Time) error {
+	if f.addErr != nil {
+		return f.addErr
+	}
+	for _, u := range usage {
+		k := apiKeyUsageKey{keyID: u.KeyID, day: u.Day}
+		current := f.usage[k]
+		current.KeyID, current.Day = u.KeyID, u.Day
+		current.Requests += u.Requests
+		current.RateLimited += u.RateLimited
+		current.Errors += u.Errors
+		f.usage[k] = current
+	}
+	for keyID, at := range lastUsed {
+		f.lastUsed[keyID] = at
+	}
+	return nil
+}
+
+func (f *fakeAPIKeyRepo) ListUsage(keyID uint, since time.Time) ([]entity.APIKeyUsage, error) {
+	var usage []entity.APIKeyUsage
+	for k, u := range f.usage {
+		if k.keyID == keyID && !k.day.Before(since) {
+			usage = append(usage, u)
+		}
+	}
+	return usage, nil
+}
+
+func TestAPIKeyService_CreateAuthenticateRevoke(t *testing.T) {
+	repo := newFakeAPIKeyRepo()
+	svc := NewAPIKeyService(repo)
+
+	key, rawKey, err := svc.CreateKey(APIKeyInput{Name: "Partner", Scopes: []string{entity.APIKeyScopeQuizzesRead}}, 1)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(rawKey, apiKeyPrefix))
+	assert.Equal(t, rawKey[:apiKeyDisplayLength], key.Prefix)
+	assert.NotContains(t, key.KeyHash, rawKey, "ключ хранится только хешем")
+
+	authenticated, err := svc.AuthenticateAPIKey(context.Background(), rawKey)
+	require.NoError(t, err)
+	assert.Equal(t, key.ID, authenticated.ID)
+	assert.True(t, authenticated.HasScope(entity.APIKeyScopeQuizzesRead))
+	assert.False(t, authenticated.HasScope(entity.APIKeyScopeResultsRead))
+
+	_, err = svc.AuthenticateAPIKey(context.Background(), rawKey+"x")
+	assert.ErrorIs(t, err, ErrAPIKeyInvalid)
+
+	require.NoError(t, svc.RevokeKey(key.ID))
+	_, err = svc.AuthenticateAPIKey(context.Background(), rawKey)
+	assert.ErrorIs(t, err, ErrAPIKeyInvalid)
+	assert.ErrorIs(t, svc.RevokeKey(key.ID), apperrors.ErrNotFound)
+}
+
+func TestAPIKeyService_ExpiredKeyAndValidation(t *testing.T) {
+	repo := newFakeAPIKeyRepo()
+	svc := NewAPIKeyService(repo)
+
+	_, _, err := svc.CreateKey(APIKeyInput{Name: "Partner", Scopes: []string{"admin:all"}}, 1)
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	_, _, err = svc.CreateKey(APIKeyInput{Name: "Partner"}, 1)
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	past := time.Now().Add(-time.Hour)
+	_, _, err = svc.CreateKey(APIKeyInput{Name: "Partner", Scopes: []string{entity.APIKeyScopeResultsRead}, ExpiresAt: &past}, 1)
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+
+	future := time.Now().Add(time.Hour)
+	key, rawKey, err := svc.CreateKey(APIKeyInput{Name: "Partner", Scopes: []string{entity.APIKeyScopeResultsRead}, ExpiresAt: &future}, 1)
+	require.NoError(t, err)
+	repo.keys[key.ID-1].ExpiresAt = &past
+	_, err = svc.AuthenticateAPIKey(context.Background(), rawKey)
+	assert.ErrorIs(t, err, ErrAPIKeyInvalid)
+}
+
+func TestAPIKeyService_UsageFlush(t *testing.T) {
+	repo := newFakeAPIKeyRepo()
+	svc := NewAPIKeyService(repo)
+	key, _, err := svc.CreateKey(APIKeyInput{Name: "Partner", Scopes: []string{entity.APIKeyScopeQuizzesRead}}, 1)
+	require.NoError(t, err)
+
+	svc.RecordAPIKeyUsage(key.ID, http.StatusOK)
+	svc.RecordAPIKeyUsage(key.ID, http.StatusTooManyRequests)
+	svc.RecordAPIKeyUsage(key.ID, http.StatusNotFound)
+
+	// Неудачное сохранение не теряет счётчики
+	repo.addErr = errors.New("db down")
+	require.Error(t, svc.FlushUsage())
+	svc.RecordAPIKeyUsage(key.ID, http.StatusOK)
+	repo.addErr = nil
+	require.NoError(t, svc.FlushUsage())
+
+	usage, err := svc.GetUsage(key.ID, 7)
+	require.NoError(t, err)
+	require.Len(t, usage, 1)
+	assert.Equal(t, int64(4), usage[0].Requests)
+	assert.Equal(t, int64(1), usage[0].RateLimited)
+	assert.Equal(t, int64(1), usage[0].Errors)
+	assert.Contains(t, repo.lastUsed, key.ID)
+
+	require.NoError(t, svc.FlushUsage(), "пустой сброс ничего не пишет")
+	_, err = svc.GetUsage(99, 7)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	"github.com/yourusername/trivia-api/internal/pkg/tracing"
+)
+
+// auditWriteTimeout ограничивает запись в журнал, чтобы медленная БД не задерживала ответ
+const auditWriteTimeout = 5 * time.Second
+
+// AuditEntry описывает действие для журнала аудита.
+// Before/After/Metadata сериализуются в JSON; секреты (пароли, токены) в них передавать нельзя.
+type AuditEntry struct {
+	Action     string
+	ActorID    uint // 0 — анонимное действие
+	TargetType string
+	TargetID   string
+	IPAddress  string
+	UserAgent  string
+	Before     interface{}
+	After      interface{}
+	Metadata   map[string]interface{}
+}
+
+// AuditService ведёт журнал аудита административных действий и событий безопасности
+type AuditService struct {
+	auditRepo repository.AuditLogRepository
+}
+
+// NewAuditService создает сервис аудита
+func NewAuditService(auditRepo repository.AuditLogRepository) (*AuditService, error) {
+	if auditRepo == nil {
+		return nil, fmt.Errorf("audit log repository is required")
+	}
+	return &AuditService{auditRepo: auditRepo}, nil
+}
+
+// Record записывает действие в журнал. Ошибка записи логируется и не прерывает
+// само действие: оно уже выполнено. Безопасно вызывать у nil-сервиса (аудит не подключён).
+func (s *AuditService) Record(ctx context.Context, e AuditEntry) {
+	if s == nil {
+		return
+	}
+
+	entry := &entity.AuditLog{
+		Action:     e.Action,
+		TargetType: e.TargetType,
+		TargetID:   e.TargetID,
+		IPAddress:  e.IPAddress,
+		UserAgent:  e.UserAgent,
+		RequestID:  tracing.RequestIDFromContext(ctx),
+		Before:     marshalAuditData(e.Action, e.Before),
+		After:      marshalAuditData(e.Action, e.After),
+		CreatedAt:  time.Now(),
+	}
+	if len(e.Metadata) > 0 {
+		entry.Metadata = marshalAuditData(e.Action, e.Metadata)
+	}
+	if e.ActorID != 0 {
+		actorID := e.ActorID
+		entry.ActorID = &actorID
+	}
+
+	// Запись не должна теряться, если клиент закрыл соединение сразу после ответа
+	writeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), auditWriteTimeout)
+	defer cancel()
+	if err := s.auditRepo.Create(writeCtx, entry); err != nil {
+		log.Printf("[AuditService] Ошибка записи в журнал аудита (action=%s, actor=%d, target=%s:%s): %v",
+			e.Action, e.ActorID, e.TargetType, e.TargetID, err)
+	}
+}
+
+// List возвращает записи журнала с фильтрами и пагинацией
+func (s *AuditService) List(filters repository.AuditLogFilters, page, pageSize int) ([]entity.AuditLog, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 50
+	}
+	return s.auditRepo.List(filters, pageSize, (page-1)*pageSize)
+}
+
+func marshalAuditData(action string, v interface{}) entity.AuditData {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("[AuditService] Не удалось сериализовать данные аудита для %s: %v", action, err)
+		return nil
+	}
+	if string(data) == "null" {
+		return nil
+	}
+	return data
+}
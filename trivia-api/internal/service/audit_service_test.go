@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	"github.com/yourusername/trivia-api/internal/pkg/tracing"
+)
+
+// MockAuditLogRepository реализует repository.AuditLogRepository
+type MockAuditLogRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditLogRepository) Create(ctx context.Context, entry *entity.AuditLog) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func (m *MockAuditLogRepository) List(filters repository.AuditLogFilters, limit, offset int) ([]entity.AuditLog, int64, error) {
+	args := m.Called(filters, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]entity.AuditLog), args.Get(1).(int64), args.Error(2)
+}
+
+func TestAuditService_RecordFillsEntry(t *testing.T) {
+	repo := new(MockAuditLogRepository)
+	svc, err := NewAuditService(repo)
+	require.NoError(t, err)
+
+	var saved *entity.AuditLog
+	repo.On("Create", mock.Anything, mock.AnythingOfType("*entity.AuditLog")).
+		Run(func(args mock.Arguments) { saved = args.Get(1).(*entity.AuditLog) }).
+		Return(nil)
+
+	// Отменённый контекст запроса не должен мешать записи
+	ctx, cancel := context.WithCancel(tracing.WithRequestID(context.Background(), "req-1"))
+	cancel()
+
+	svc.Record(ctx, AuditEntry{
+		Action:     entity.AuditActionQuizCancel,
+		ActorID:    7,
+		TargetType: entity.AuditTargetQuiz,
+		TargetID:   "42",
+		Before:     map[string]string{"status": entity.QuizStatusScheduled},
+		After:      map[string]string{"status": entity.QuizStatusCancelled},
+	})
+
+	require.NotNil(t, saved)
+	require.NotNil(t, saved.ActorID)
+	assert.Equal(t, uint(7), *saved.ActorID)
+	assert.Equal(t, "req-1", saved.RequestID)
+	assert.JSONEq(t, `{"status":"scheduled"}`, string(saved.Before))
+	assert.JSONEq(t, `{"status":"cancelled"}`, string(saved.After))
+	assert.Nil(t, saved.Metadata)
+}
+
+func TestAuditService_RecordIgnoresErrorsAndNilService(t *testing.T) {
+	repo := new(MockAuditLogRepository)
+	svc, err := NewAuditService(repo)
+	require.NoError(t, err)
+
+	repo.On("Create", mock.Anything, mock.Anything).Return(errors.New("db down"))
+	assert.NotPanics(t, func() {
+		svc.Record(context.Background(), AuditEntry{Action: entity.AuditActionLoginFailed})
+	})
+
+	var disabled *AuditService
+	assert.NotPanics(t, func() {
+		disabled.Record(context.Background(), AuditEntry{Action: entity.AuditActionLogin})
+	})
+	repo.AssertNumberOfCalls(t, "Create", 1)
+}
@@ -12,5 +12,11 @@ var (
 	ErrVerificationAttemptsExceeded  = errors.New("verification_attempts_exceeded")
 	ErrVerificationResendCooldown    = errors.New("verification_resend_cooldown")
 	ErrGoogleTokenVerificationFailed = errors.New("google_token_verification_failed")
+	ErrMagicLinkInvalid              = errors.New("magic_link_invalid")
+	ErrMagicLinkExpired              = errors.New("magic_link_expired")
+	ErrMagicLinkDeviceMismatch       = errors.New("magic_link_device_mismatch")
+	ErrWebAuthnCeremonyInvalid       = errors.New("webauthn_ceremony_invalid")
+	ErrWebAuthnVerificationFailed    = errors.New("webauthn_verification_failed")
+	ErrPasskeyRequired               = errors.New("passkey_required")
 )
 
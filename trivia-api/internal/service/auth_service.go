@@ -11,8 +11,11 @@ import (
 	"github.com/yourusername/trivia-api/internal/domain/entity"
 	"github.com/yourusername/trivia-api/internal/domain/repository"
 	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/pkg/i18n"
+	"github.com/yourusername/trivia-api/internal/pkg/tracing"
 	"github.com/yourusername/trivia-api/pkg/auth"
 	"github.com/yourusername/trivia-api/pkg/auth/manager"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // AuthService РїСЂРµРґРѕСЃС‚Р°РІР»СЏРµС‚ РјРµС‚РѕРґС‹ РґР»СЏ СЂР°Р±РѕС‚С‹ СЃ Р°СѓС‚РµРЅС‚РёС„РёРєР°С†РёРµР№ Рё РїРѕР»СЊР·РѕРІР°С‚РµР»СЏРјРё
@@ -27,12 +30,22 @@ type AuthService struct {
 	// Phase 2/3/4 optional dependencies configured from main.
 	emailVerificationService *EmailVerificationService
 	googleOAuthService       *GoogleOAuthService
+	magicLinkService         *MagicLinkService
+	webAuthnService          *WebAuthnService
 	emailVerificationRepo    repository.EmailVerificationRepository
 	identityRepo             repository.UserIdentityRepository
+	referralService          *ReferralService
+	abuseService             *AbuseService
+	pushService              *PushNotificationService
+	notificationService      *NotificationService
+	moderationService        *ModerationService
 	emailVerificationEnabled bool
 	googleOAuthEnabled       bool
+	featureFlags             FeatureEvaluator
+	eventBus                 *EventBus
 	tosVersion               string
 	privacyVersion           string
+	locales                  *i18n.Locales
 }
 
 // RegisterInput СЃРѕРґРµСЂР¶РёС‚ РІСЃРµ РґР°РЅРЅС‹Рµ РґР»СЏ СЂРµРіРёСЃС‚СЂР°С†РёРё
@@ -53,6 +66,10 @@ type RegisterInput struct {
 	// РњРµС‚Р°РґР°РЅРЅС‹Рµ
 	IP        string
 	UserAgent string
+	DeviceID  string
+
+	// Необязательный код приглашения
+	ReferralCode string
 }
 
 // NewAuthService СЃРѕР·РґР°РµС‚ РЅРѕРІС‹Р№ СЃРµСЂРІРёСЃ Р°СѓС‚РµРЅС‚РёС„РёРєР°С†РёРё Рё РІРѕР·РІСЂР°С‰Р°РµС‚ РѕС€РёР±РєСѓ РїСЂРё РїСЂРѕР±Р»РµРјР°С…
@@ -141,6 +158,35 @@ func (s *AuthService) RegisterUser(input RegisterInput) (*entity.User, error) {
 		return nil, fmt.Errorf("failed to check username existence: %w", err)
 	}
 
+	// Модерация: запрещённое слово в имени отклоняет регистрацию, пограничный текст
+	// уходит в очередь модерации после создания аккаунта
+	var flagged []FlaggedText
+	if s.moderationService != nil {
+		if flagged, err = s.moderationService.Screen(
+			ModeratedText{Field: entity.ModerationFieldUsername, Text: input.Username},
+			ModeratedText{Field: entity.ModerationFieldFirstName, Text: input.FirstName},
+			ModeratedText{Field: entity.ModerationFieldLastName, Text: input.LastName},
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	// Лимит аккаунтов на устройство: сверх лимита регистрация отклоняется или аккаунт получает теневой бан
+	var deviceRegistration *DeviceRegistration
+	if s.abuseService != nil {
+		if deviceRegistration, err = s.abuseService.CheckRegistration(input.DeviceID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Проверяем код приглашения до создания пользователя, чтобы не оставлять аккаунт без реферала
+	input.ReferralCode = NormalizeReferralCode(input.ReferralCode)
+	if input.ReferralCode != "" && s.referralService != nil {
+		if _, err := s.referralService.ResolveCode(input.ReferralCode); err != nil {
+			return nil, err
+		}
+	}
+
 	// РћРїСЂРµРґРµР»СЏРµРј, Р·Р°РїРѕР»РЅРµРЅ Р»Рё РїСЂРѕС„РёР»СЊ
 	var profileCompletedAt *time.Time
 	if input.FirstName != "" && input.LastName != "" && input.BirthDate != nil && input.Gender != "" {
@@ -160,9 +206,17 @@ func (s *AuthService) RegisterUser(input RegisterInput) (*entity.User, error) {
 		ProfileCompletedAt:  profileCompletedAt,
 	}
 
-	if err := s.userRepo.Create(user); err != nil {
+	applyDeviceRegistration(user, deviceRegistration)
+
+	if err := createUser(s.userRepo, s.eventBus, user, "password"); err != nil {
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
+	if s.moderationService != nil {
+		s.moderationService.Enqueue(user.ID, flagged)
+	}
+	if user.ShadowBannedAt != nil {
+		log.Printf("[AuthService] Пользователь ID=%d зарегистрирован с теневым баном: превышен лимит аккаунтов на устройство", user.ID)
+	}
 
 	// РЎРѕС…СЂР°РЅСЏРµРј СЋСЂРёРґРёС‡РµСЃРєРѕРµ СЃРѕРіР»Р°СЃРёРµ
 	if s.legalRepo != nil {
@@ -181,6 +235,18 @@ func (s *AuthService) RegisterUser(input RegisterInput) (*entity.User, error) {
 		}
 	}
 
+	// Привязываем приглашение (ошибка не прерывает регистрацию)
+	if input.ReferralCode != "" && s.referralService != nil {
+		if _, err := s.referralService.AttachReferral(ReferralAttachInput{
+			RefereeID: user.ID,
+			Code:      input.ReferralCode,
+			IP:        input.IP,
+			DeviceID:  input.DeviceID,
+		}); err != nil {
+			log.Printf("[AuthService] Ошибка привязки реферала для пользователя ID=%d: %v", user.ID, err)
+		}
+	}
+
 	return user, nil
 }
 
@@ -212,16 +278,27 @@ type AuthResponse struct {
 
 // LoginUser Р°СѓС‚РµРЅС‚РёС„РёС†РёСЂСѓРµС‚ РїРѕР»СЊР·РѕРІР°С‚РµР»СЏ Рё РІРѕР·РІСЂР°С‰Р°РµС‚ РїР°СЂСѓ С‚РѕРєРµРЅРѕРІ
 // РћР±РЅРѕРІР»РµРЅРѕ РґР»СЏ РёСЃРїРѕР»СЊР·РѕРІР°РЅРёСЏ TokenManager
-func (s *AuthService) LoginUser(email, password, deviceID, ipAddress, userAgent string) (*manager.TokenResponse, error) {
-	user, err := s.AuthenticateUser(email, password)
+func (s *AuthService) LoginUser(ctx context.Context, email, password, deviceID, ipAddress, userAgent string) (_ *manager.TokenResponse, err error) {
+	ctx, span := tracing.StartSpan(ctx, "AuthService.LoginUser", attribute.String("auth.device_id", deviceID))
+	defer func() { tracing.EndSpan(span, err) }()
+
+	user, err := s.AuthenticateUser(ctx, email, password)
 	if err != nil {
 		// РћС€РёР±РєР° СѓР¶Рµ Р·Р°Р»РѕРіРёСЂРѕРІР°РЅР° РІ AuthenticateUser
 		// РџСЂРѕР±СЂР°СЃС‹РІР°РµРј РѕС€РёР±РєСѓ (РІРµСЂРѕСЏС‚РЅРѕ, apperrors.ErrUnauthorized)
 		return nil, err
 	}
 
+	// Администратор с ключом доступа входит только по ключу
+	if err = s.CheckLoginMethod(user); err != nil {
+		return nil, err
+	}
+
 	// РСЃРїРѕР»СЊР·СѓРµРј TokenManager РґР»СЏ РіРµРЅРµСЂР°С†РёРё С‚РѕРєРµРЅРѕРІ
-	tokenResp, err := s.tokenManager.GenerateTokenPair(user.ID, deviceID, ipAddress, userAgent)
+	span.SetAttributes(attribute.Int64("enduser.id", int64(user.ID)))
+	_, tokenSpan := tracing.StartSpan(ctx, "TokenManager.GenerateTokenPair")
+	tokenResp, err := s.tokenManager.GenerateTokenPairForClient(manager.ClientFromContext(ctx), user.ID, deviceID, ipAddress, userAgent)
+	tracing.EndSpan(tokenSpan, err)
 	if err != nil {
 		log.Printf("[AuthService] РћС€РёР±РєР° РіРµРЅРµСЂР°С†РёРё С‚РѕРєРµРЅРѕРІ РґР»СЏ РїРѕР»СЊР·РѕРІР°С‚РµР»СЏ ID=%d: %v", user.ID, err)
 		return nil, fmt.Errorf("РѕС€РёР±РєР° РіРµРЅРµСЂР°С†РёРё С‚РѕРєРµРЅРѕРІ")
@@ -229,9 +306,9 @@ func (s *AuthService) LoginUser(email, password, deviceID, ipAddress, userAgent
 
 	// РЎР±СЂРѕСЃ РёРЅРІР°Р»РёРґР°С†РёРё JWT РґР»СЏ РїРѕР»СЊР·РѕРІР°С‚РµР»СЏ РїСЂРё СѓСЃРїРµС€РЅРѕРј РІС…РѕРґРµ
 	// РЎРѕР·РґР°РµРј РєРѕРЅС‚РµРєСЃС‚ РґР»СЏ РІС‹Р·РѕРІР°
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	resetCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
 	defer cancel()
-	s.jwtService.ResetInvalidationForUser(ctx, user.ID)
+	s.jwtService.ResetInvalidationForUser(resetCtx, user.ID)
 
 	log.Printf("[AuthService] РџРѕР»СЊР·РѕРІР°С‚РµР»СЊ ID=%d (%s) СѓСЃРїРµС€РЅРѕ РІРѕС€РµР» РІ СЃРёСЃС‚РµРјСѓ", user.ID, user.Email)
 	return tokenResp, nil
@@ -282,20 +359,35 @@ func (s *AuthService) UpdateUserProfile(userID uint, username, profilePicture st
 		}
 	}
 
+	// Новое имя проверяется модерацией; пограничное уходит в очередь после сохранения
+	var flagged []FlaggedText
+	if s.moderationService != nil && username != user.Username {
+		if flagged, err = s.moderationService.Screen(ModeratedText{Field: entity.ModerationFieldUsername, Text: username}); err != nil {
+			return err
+		}
+	}
+
 	// РСЃРїРѕР»СЊР·СѓРµРј Р±РµР·РѕРїР°СЃРЅС‹Р№ РјРµС‚РѕРґ РѕР±РЅРѕРІР»РµРЅРёСЏ РїСЂРѕС„РёР»СЏ Р±РµР· РёР·РјРµРЅРµРЅРёСЏ РїР°СЂРѕР»СЏ
 	updates := map[string]interface{}{
 		"username":        username,
 		"profile_picture": profilePicture,
 	}
 
-	return s.userRepo.UpdateProfile(userID, updates)
+	if err := s.userRepo.UpdateProfile(userID, updates); err != nil {
+		return err
+	}
+	if s.moderationService != nil {
+		s.moderationService.Enqueue(userID, flagged)
+	}
+	return nil
 }
 
 // UpdateUserLanguage РѕР±РЅРѕРІР»СЏРµС‚ СЏР·С‹Рє РёРЅС‚РµСЂС„РµР№СЃР° РїРѕР»СЊР·РѕРІР°С‚РµР»СЏ
 func (s *AuthService) UpdateUserLanguage(userID uint, language string) error {
-	// Р’Р°Р»РёРґР°С†РёСЏ СЏР·С‹РєР° (ru РёР»Рё kk)
-	if language != "ru" && language != "kk" {
-		return fmt.Errorf("%w: invalid language '%s', allowed: ru, kk", apperrors.ErrValidation, language)
+	// Validate against the configured content locales
+	language = i18n.Normalize(language)
+	if !s.languages().IsSupported(language) {
+		return fmt.Errorf("%w: invalid language '%s', allowed: %s", apperrors.ErrValidation, language, strings.Join(s.SupportedLanguages(), ", "))
 	}
 
 	updates := map[string]interface{}{
@@ -457,7 +549,14 @@ func (s *AuthService) AdminResetPassword(userID uint, newPassword string) error
 	}
 
 	// РРЅРІР°Р»РёРґРёСЂСѓРµРј РІСЃРµ С‚РѕРєРµРЅС‹ РїРѕР»СЊР·РѕРІР°С‚РµР»СЏ
-	return s.LogoutAllDevices(userID)
+	if err := s.LogoutAllDevices(userID); err != nil {
+		return err
+	}
+	// Пользователь узнаёт о сбросе пароля из центра уведомлений и по email
+	if s.notificationService != nil {
+		go s.notificationService.NotifySecurityAlert(userID, SecurityAlertPasswordReset)
+	}
+	return nil
 }
 
 // GetRefreshTokenByUserID РїРѕР»СѓС‡Р°РµС‚ Р°РєС‚РёРІРЅС‹Р№ refresh С‚РѕРєРµРЅ РїРѕР»СЊР·РѕРІР°С‚РµР»СЏ
@@ -476,12 +575,12 @@ func (s *AuthService) GetRefreshTokenByUserID(userID uint) (*entity.RefreshToken
 }
 
 // AuthenticateUser РїСЂРѕРІРµСЂСЏРµС‚ СѓС‡РµС‚РЅС‹Рµ РґР°РЅРЅС‹Рµ РїРѕР»СЊР·РѕРІР°С‚РµР»СЏ Р±РµР· СЃРѕР·РґР°РЅРёСЏ С‚РѕРєРµРЅРѕРІ
-func (s *AuthService) AuthenticateUser(email, password string) (*entity.User, error) {
+func (s *AuthService) AuthenticateUser(ctx context.Context, email, password string) (*entity.User, error) {
 	// РќРѕСЂРјР°Р»РёР·СѓРµРј email
 	email = normalizeEmail(email)
 
 	// РџРѕР»СѓС‡Р°РµРј РїРѕР»СЊР·РѕРІР°С‚РµР»СЏ РїРѕ email
-	user, err := s.userRepo.GetByEmail(email)
+	user, err := s.userRepo.WithContext(ctx).GetByEmail(email)
 	if err != nil {
 		log.Printf("[AuthService] РџРѕР»СЊР·РѕРІР°С‚РµР»СЊ СЃ email %s РЅРµ РЅР°Р№РґРµРЅ: %v", email, err)
 		// Р’РѕР·РІСЂР°С‰Р°РµРј СЃС‚Р°РЅРґР°СЂС‚РЅСѓСЋ РѕС€РёР±РєСѓ
@@ -534,11 +633,23 @@ func (s *AuthService) GetRefreshTokenByID(tokenID uint) (*entity.RefreshToken, e
 // RevokeSessionByID РѕС‚Р·С‹РІР°РµС‚ РєРѕРЅРєСЂРµС‚РЅСѓСЋ СЃРµСЃСЃРёСЋ РїРѕ РµРµ ID
 // РћР±РЅРѕРІР»РµРЅРѕ РґР»СЏ РёСЃРїРѕР»СЊР·РѕРІР°РЅРёСЏ TokenManager
 func (s *AuthService) RevokeSessionByID(sessionID uint, reason string) error {
+	// Владелец сессии нужен только для уведомлений, ошибка поиска не блокирует отзыв
+	var ownerID uint
+	if s.pushService != nil || s.notificationService != nil {
+		if token, err := s.refreshTokenRepo.GetTokenByID(sessionID); err == nil && token.IsValid() {
+			ownerID = token.UserID
+		}
+	}
+
 	if err := s.refreshTokenRepo.MarkTokenAsExpiredByID(sessionID); err != nil {
 		log.Printf("[AuthService] Ошибка отзыва сессии ID=%d: %v", sessionID, err)
 		return fmt.Errorf("ошибка отзыва сессии")
 	}
 
+	if ownerID != 0 {
+		s.notifySessionRevoked(ownerID)
+	}
+
 	log.Printf("[AuthService] Сессия ID=%d успешно отозвана. Причина: %s", sessionID, reason)
 	return nil
 }
@@ -565,6 +676,10 @@ func (s *AuthService) RevokeAllUserSessions(userID uint, reason string) error {
 		}
 	}
 
+	if len(tokens) > 0 {
+		s.notifySessionRevoked(userID)
+	}
+
 	return nil
 }
 
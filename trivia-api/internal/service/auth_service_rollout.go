@@ -6,8 +6,13 @@ import (
 	"strings"
 	"time"
 
+	"github.com/go-webauthn/webauthn/protocol"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
 	"github.com/yourusername/trivia-api/internal/domain/repository"
 	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/pkg/i18n"
+	"github.com/yourusername/trivia-api/pkg/auth/manager"
 )
 
 type DeleteAccountInput struct {
@@ -23,6 +28,16 @@ func (s *AuthService) SetGoogleOAuthService(svc *GoogleOAuthService) {
 	s.googleOAuthService = svc
 }
 
+// SetMagicLinkService enables passwordless login by email link.
+func (s *AuthService) SetMagicLinkService(svc *MagicLinkService) {
+	s.magicLinkService = svc
+}
+
+// SetWebAuthnService enables passkey registration and login.
+func (s *AuthService) SetWebAuthnService(svc *WebAuthnService) {
+	s.webAuthnService = svc
+}
+
 func (s *AuthService) SetEmailVerificationRepository(repo repository.EmailVerificationRepository) {
 	s.emailVerificationRepo = repo
 }
@@ -31,11 +46,50 @@ func (s *AuthService) SetIdentityRepository(repo repository.UserIdentityReposito
 	s.identityRepo = repo
 }
 
+func (s *AuthService) SetReferralService(svc *ReferralService) {
+	s.referralService = svc
+}
+
+func (s *AuthService) SetAbuseService(svc *AbuseService) {
+	s.abuseService = svc
+}
+
+// SetModerationService включает модерацию имени пользователя, имени и фамилии
+func (s *AuthService) SetModerationService(svc *ModerationService) {
+	s.moderationService = svc
+}
+
+func (s *AuthService) SetPushNotificationService(svc *PushNotificationService) {
+	s.pushService = svc
+}
+
+func (s *AuthService) SetNotificationService(svc *NotificationService) {
+	s.notificationService = svc
+}
+
 func (s *AuthService) SetFeatureFlags(emailVerificationEnabled, googleOAuthEnabled bool) {
 	s.emailVerificationEnabled = emailVerificationEnabled
 	s.googleOAuthEnabled = googleOAuthEnabled
 }
 
+// SetFeatureEvaluator подключает флаги функций: включённая в конфигурации верификация email
+// раскатывается на пользователей по флагу entity.FeatureEmailVerification.
+func (s *AuthService) SetFeatureEvaluator(evaluator FeatureEvaluator) {
+	s.featureFlags = evaluator
+}
+
+// SetEventBus включает событие user.registered при регистрации
+func (s *AuthService) SetEventBus(bus *EventBus) {
+	s.eventBus = bus
+}
+
+func (s *AuthService) emailVerificationEnabledFor(userID uint) bool {
+	if !s.emailVerificationEnabled {
+		return false
+	}
+	return s.featureFlags == nil || s.featureFlags.FeatureEnabled(entity.FeatureEmailVerification, userID, false)
+}
+
 func (s *AuthService) SetLegalVersions(tosVersion, privacyVersion string) {
 	if strings.TrimSpace(tosVersion) != "" {
 		s.tosVersion = strings.TrimSpace(tosVersion)
@@ -45,8 +99,25 @@ func (s *AuthService) SetLegalVersions(tosVersion, privacyVersion string) {
 	}
 }
 
+// SetLocales sets the languages a user may choose as their preference.
+func (s *AuthService) SetLocales(locales *i18n.Locales) {
+	s.locales = locales
+}
+
+// SupportedLanguages returns the languages accepted by UpdateUserLanguage.
+func (s *AuthService) SupportedLanguages() []string {
+	return s.languages().Supported()
+}
+
+func (s *AuthService) languages() *i18n.Locales {
+	if s.locales == nil {
+		return i18n.NewLocales("ru", []string{"kk"})
+	}
+	return s.locales
+}
+
 func (s *AuthService) SendVerificationCode(ctx context.Context, userID uint) error {
-	if !s.emailVerificationEnabled {
+	if !s.emailVerificationEnabledFor(userID) {
 		return ErrFeatureDisabled
 	}
 	if s.emailVerificationService == nil {
@@ -56,7 +127,7 @@ func (s *AuthService) SendVerificationCode(ctx context.Context, userID uint) err
 }
 
 func (s *AuthService) ConfirmVerificationCode(ctx context.Context, userID uint, code string) error {
-	if !s.emailVerificationEnabled {
+	if !s.emailVerificationEnabledFor(userID) {
 		return ErrFeatureDisabled
 	}
 	if s.emailVerificationService == nil {
@@ -66,7 +137,7 @@ func (s *AuthService) ConfirmVerificationCode(ctx context.Context, userID uint,
 }
 
 func (s *AuthService) GetVerificationStatus(ctx context.Context, userID uint) (*EmailVerificationStatus, error) {
-	if !s.emailVerificationEnabled {
+	if !s.emailVerificationEnabledFor(userID) {
 		user, err := s.userRepo.GetByID(userID)
 		if err != nil {
 			return nil, err
@@ -103,6 +174,109 @@ func (s *AuthService) LinkGoogleAuth(ctx context.Context, userID uint, input Goo
 	return s.googleOAuthService.Link(ctx, userID, input)
 }
 
+// RequestMagicLink emails a single-use login link bound to deviceID. Unknown addresses
+// get the same response, so the endpoint cannot be used to probe for accounts.
+func (s *AuthService) RequestMagicLink(ctx context.Context, email, deviceID, ipAddress string) error {
+	if s.magicLinkService == nil {
+		return ErrFeatureDisabled
+	}
+	return s.magicLinkService.Request(ctx, email, deviceID, ipAddress)
+}
+
+// LoginWithMagicLink consumes the link and issues the regular token pair for the device
+// that requested it.
+func (s *AuthService) LoginWithMagicLink(ctx context.Context, token, deviceID, ipAddress, userAgent string) (*manager.TokenResponse, *entity.User, error) {
+	if s.magicLinkService == nil {
+		return nil, nil, ErrFeatureDisabled
+	}
+	user, err := s.magicLinkService.Consume(ctx, token, deviceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.CheckLoginMethod(user); err != nil {
+		return nil, nil, err
+	}
+	tokenResp, err := s.tokenManager.GenerateTokenPairForClient(manager.ClientFromContext(ctx), user.ID, deviceID, ipAddress, userAgent)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate tokens after magic link login: %w", err)
+	}
+
+	resetCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	s.jwtService.ResetInvalidationForUser(resetCtx, user.ID)
+	return tokenResp, user, nil
+}
+
+// CheckLoginMethod rejects non-passkey logins for admins who have registered a passkey
+// when webauthn.requireForAdmins is on. It is a no-op while passkeys are disabled.
+func (s *AuthService) CheckLoginMethod(user *entity.User) error {
+	if s.webAuthnService == nil {
+		return nil
+	}
+	return s.webAuthnService.CheckLoginMethod(user)
+}
+
+// BeginPasskeyRegistration returns navigator.credentials.create options for the user and
+// the ceremony session id that FinishPasskeyRegistration expects.
+func (s *AuthService) BeginPasskeyRegistration(ctx context.Context, userID uint) (*protocol.CredentialCreation, string, error) {
+	if s.webAuthnService == nil {
+		return nil, "", ErrFeatureDisabled
+	}
+	return s.webAuthnService.BeginRegistration(ctx, userID)
+}
+
+// FinishPasskeyRegistration verifies the attestation and stores the passkey.
+func (s *AuthService) FinishPasskeyRegistration(ctx context.Context, userID uint, sessionID, name string, credential []byte) (*entity.WebAuthnCredential, error) {
+	if s.webAuthnService == nil {
+		return nil, ErrFeatureDisabled
+	}
+	return s.webAuthnService.FinishRegistration(ctx, userID, sessionID, name, credential)
+}
+
+// ListPasskeys returns the passkeys registered by the user.
+func (s *AuthService) ListPasskeys(userID uint) ([]entity.WebAuthnCredential, error) {
+	if s.webAuthnService == nil {
+		return nil, ErrFeatureDisabled
+	}
+	return s.webAuthnService.ListCredentials(userID)
+}
+
+// DeletePasskey removes one of the user's passkeys.
+func (s *AuthService) DeletePasskey(ctx context.Context, userID, credentialID uint) error {
+	if s.webAuthnService == nil {
+		return ErrFeatureDisabled
+	}
+	return s.webAuthnService.DeleteCredential(ctx, userID, credentialID)
+}
+
+// BeginPasskeyLogin returns navigator.credentials.get options for a discoverable login.
+func (s *AuthService) BeginPasskeyLogin() (*protocol.CredentialAssertion, string, error) {
+	if s.webAuthnService == nil {
+		return nil, "", ErrFeatureDisabled
+	}
+	return s.webAuthnService.BeginLogin()
+}
+
+// LoginWithPasskey verifies the assertion and issues the regular token pair for the device.
+func (s *AuthService) LoginWithPasskey(ctx context.Context, sessionID string, credential []byte, deviceID, ipAddress, userAgent string) (*manager.TokenResponse, *entity.User, error) {
+	if s.webAuthnService == nil {
+		return nil, nil, ErrFeatureDisabled
+	}
+	user, err := s.webAuthnService.FinishLogin(ctx, sessionID, credential)
+	if err != nil {
+		return nil, nil, err
+	}
+	tokenResp, err := s.tokenManager.GenerateTokenPairForClient(manager.ClientFromContext(ctx), user.ID, deviceID, ipAddress, userAgent)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate tokens after passkey login: %w", err)
+	}
+
+	resetCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	s.jwtService.ResetInvalidationForUser(resetCtx, user.ID)
+	return tokenResp, user, nil
+}
+
 func (s *AuthService) DeleteMyAccount(ctx context.Context, userID uint, input DeleteAccountInput) error {
 	user, err := s.userRepo.GetByID(userID)
 	if err != nil {
@@ -131,6 +305,12 @@ func (s *AuthService) DeleteMyAccount(ctx context.Context, userID uint, input De
 	if s.identityRepo != nil {
 		_ = s.identityRepo.DeleteByUserID(userID)
 	}
+	if s.magicLinkService != nil {
+		_ = s.magicLinkService.DeleteByUserID(userID)
+	}
+	if s.webAuthnService != nil {
+		_ = s.webAuthnService.DeleteAllCredentials(userID)
+	}
 	if s.legalRepo != nil {
 		_ = s.legalRepo.DeleteByUserID(userID)
 	}
@@ -172,3 +352,13 @@ func (s *AuthService) DeleteMyAccount(ctx context.Context, userID uint, input De
 	_ = ctx // reserved for future async cleanup hooks
 	return nil
 }
+
+// notifySessionRevoked alerts the user through the in-app center and push, when configured.
+func (s *AuthService) notifySessionRevoked(userID uint) {
+	if s.notificationService != nil {
+		go s.notificationService.NotifySecurityAlert(userID, SecurityAlertSessionRevoked)
+	}
+	if s.pushService != nil {
+		s.pushService.NotifySessionRevoked(userID)
+	}
+}
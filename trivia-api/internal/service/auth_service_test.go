@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
 	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -27,6 +28,11 @@ func (m *MockUserRepository) Create(user *entity.User) error {
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) CreateWithEvent(user *entity.User, event func(user *entity.User) (entity.OutboxEvent, error)) error {
+	args := m.Called(user, event)
+	return args.Error(0)
+}
+
 func (m *MockUserRepository) GetByID(id uint) (*entity.User, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
@@ -35,6 +41,14 @@ func (m *MockUserRepository) GetByID(id uint) (*entity.User, error) {
 	return args.Get(0).(*entity.User), args.Error(1)
 }
 
+func (m *MockUserRepository) GetByIDs(ids []uint) ([]entity.User, error) {
+	args := m.Called(ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.User), args.Error(1)
+}
+
 func (m *MockUserRepository) GetByEmail(email string) (*entity.User, error) {
 	args := m.Called(email)
 	if args.Get(0) == nil {
@@ -92,6 +106,18 @@ func (m *MockUserRepository) GetLeaderboard(limit, offset int) ([]entity.User, i
 	return args.Get(0).([]entity.User), args.Get(1).(int64), args.Error(2)
 }
 
+func (m *MockUserRepository) GetLeaderboardAfter(after *repository.LeaderboardCursor, limit int) ([]entity.User, error) {
+	args := m.Called(after, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.User), args.Error(1)
+}
+
+func (m *MockUserRepository) WithContext(ctx context.Context) repository.UserRepository {
+	return m
+}
+
 // MockRefreshTokenRepository реализует repository.RefreshTokenRepository
 type MockRefreshTokenRepository struct {
 	mock.Mock
@@ -166,6 +192,16 @@ func (m *MockRefreshTokenRepository) MarkOldestAsExpiredForUser(userID uint, lim
 	return args.Error(0)
 }
 
+func (m *MockRefreshTokenRepository) SetDeviceKey(id uint, deviceKey, thumbprint string) error {
+	args := m.Called(id, deviceKey, thumbprint)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) MarkAsExpiredByDeviceKey(userID uint, thumbprint string) (int64, error) {
+	args := m.Called(userID, thumbprint)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 // MockInvalidTokenRepository реализует repository.InvalidTokenRepository
 type MockInvalidTokenRepository struct {
 	mock.Mock
@@ -343,7 +379,7 @@ func TestAuthService_AuthenticateUser_ValidCredentials(t *testing.T) {
 	authService := createTestAuthService(mockUserRepo, nil, nil)
 
 	// Act
-	user, err := authService.AuthenticateUser("test@example.com", plainPassword)
+	user, err := authService.AuthenticateUser(context.Background(), "test@example.com", plainPassword)
 
 	// Assert
 	require.NoError(t, err, "Аутентификация должна быть успешной")
@@ -370,7 +406,7 @@ func TestAuthService_AuthenticateUser_InvalidPassword(t *testing.T) {
 	authService := createTestAuthService(mockUserRepo, nil, nil)
 
 	// Act
-	user, err := authService.AuthenticateUser("test@example.com", "wrongPassword")
+	user, err := authService.AuthenticateUser(context.Background(), "test@example.com", "wrongPassword")
 
 	// Assert
 	assert.Error(t, err, "Должна быть ошибка при неправильном пароле")
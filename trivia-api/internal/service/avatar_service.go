@@ -0,0 +1,180 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // регистрация декодера GIF
+	"image/jpeg"
+	_ "image/png" // регистрация декодера PNG
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/pkg/storage"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // регистрация декодера WebP
+)
+
+const (
+	// AvatarSize — сторона итогового квадратного аватара в пикселях
+	AvatarSize = 256
+	// MaxAvatarUploadBytes — максимальный размер загружаемого изображения
+	MaxAvatarUploadBytes = 5 * 1024 * 1024
+
+	avatarMaxSourcePixels = 40_000_000 // защита от изображений-«бомб» при декодировании
+	avatarJPEGQuality     = 85
+	avatarStoragePrefix   = "avatars/"
+	avatarStorageTimeout  = 30 * time.Second
+)
+
+// Допустимые форматы загружаемых аватаров (по содержимому, а не по расширению)
+var avatarContentTypes = map[string]struct{}{
+	"image/jpeg": {},
+	"image/png":  {},
+	"image/gif":  {},
+	"image/webp": {},
+}
+
+// AvatarService загружает аватары пользователей: обрезает до квадрата,
+// уменьшает до AvatarSize и сохраняет в хранилище загрузок как JPEG.
+type AvatarService struct {
+	userRepo repository.UserRepository
+	storage  storage.Storage
+}
+
+// NewAvatarService создает сервис аватаров
+func NewAvatarService(userRepo repository.UserRepository, store storage.Storage) (*AvatarService, error) {
+	if userRepo == nil {
+		return nil, fmt.Errorf("user repository is required")
+	}
+	if store == nil {
+		return nil, fmt.Errorf("storage is required")
+	}
+	return &AvatarService{userRepo: userRepo, storage: store}, nil
+}
+
+// UploadAvatar обрабатывает изображение, делает его аватаром пользователя и удаляет предыдущий
+// загруженный аватар. Возвращает новый URL аватара.
+func (s *AvatarService) UploadAvatar(userID uint, src io.Reader) (string, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(src, MaxAvatarUploadBytes+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read avatar: %w", err)
+	}
+	if len(data) > MaxAvatarUploadBytes {
+		return "", fmt.Errorf("%w: avatar must not exceed %d MB", apperrors.ErrValidation, MaxAvatarUploadBytes/(1024*1024))
+	}
+
+	avatar, err := processAvatar(data)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), avatarStorageTimeout)
+	defer cancel()
+
+	key := fmt.Sprintf("%s%d/%d.jpg", avatarStoragePrefix, userID, time.Now().UnixNano())
+	if err := s.storage.Store(ctx, key, bytes.NewReader(avatar), int64(len(avatar)), "image/jpeg"); err != nil {
+		return "", fmt.Errorf("failed to store avatar: %w", err)
+	}
+
+	url := s.storage.PublicURL(key)
+	if err := s.userRepo.UpdateProfile(userID, map[string]interface{}{
+		"profile_picture":    url,
+		"avatar_storage_key": key,
+	}); err != nil {
+		s.deleteObject(ctx, key)
+		return "", fmt.Errorf("failed to update profile picture: %w", err)
+	}
+
+	if user.AvatarStorageKey != "" {
+		s.deleteObject(ctx, user.AvatarStorageKey)
+	}
+
+	log.Printf("[AvatarService] Пользователь #%d загрузил аватар %s", userID, key)
+	return url, nil
+}
+
+// DeleteAvatar сбрасывает аватар пользователя и удаляет загруженный файл
+func (s *AvatarService) DeleteAvatar(userID uint) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.userRepo.UpdateProfile(userID, map[string]interface{}{
+		"profile_picture":    "",
+		"avatar_storage_key": "",
+	}); err != nil {
+		return fmt.Errorf("failed to reset profile picture: %w", err)
+	}
+
+	if user.AvatarStorageKey != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), avatarStorageTimeout)
+		defer cancel()
+		s.deleteObject(ctx, user.AvatarStorageKey)
+	}
+	return nil
+}
+
+func (s *AvatarService) deleteObject(ctx context.Context, key string) {
+	if err := s.storage.Delete(ctx, key); err != nil {
+		log.Printf("[AvatarService] WARNING: не удалось удалить аватар %s: %v", key, err)
+	}
+}
+
+// processAvatar проверяет формат изображения, обрезает его по центру до квадрата
+// и уменьшает до AvatarSize. Прозрачные области заливаются белым.
+func processAvatar(data []byte) ([]byte, error) {
+	contentType := http.DetectContentType(data)
+	if _, ok := avatarContentTypes[contentType]; !ok {
+		return nil, fmt.Errorf("%w: unsupported image type %s", apperrors.ErrValidation, contentType)
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid image", apperrors.ErrValidation)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > avatarMaxSourcePixels {
+		return nil, fmt.Errorf("%w: image dimensions %dx%d are not allowed", apperrors.ErrValidation, cfg.Width, cfg.Height)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid image", apperrors.ErrValidation)
+	}
+
+	// Квадрат по центру исходного изображения
+	b := src.Bounds()
+	side := b.Dx()
+	if b.Dy() < side {
+		side = b.Dy()
+	}
+	x0 := b.Min.X + (b.Dx()-side)/2
+	y0 := b.Min.Y + (b.Dy()-side)/2
+	crop := image.Rect(x0, y0, x0+side, y0+side)
+
+	size := AvatarSize
+	if side < size {
+		size = side // не увеличиваем маленькие изображения
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, crop, draw.Over, nil)
+
+	var out bytes.Buffer
+	if err := jpeg.Encode(&out, dst, &jpeg.Options{Quality: avatarJPEGQuality}); err != nil {
+		return nil, fmt.Errorf("failed to encode avatar: %w", err)
+	}
+	return out.Bytes(), nil
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/pkg/storage"
+)
+
+func encodeTestPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestProcessAvatar_CropsAndResizesToSquareJPEG(t *testing.T) {
+	out, err := processAvatar(encodeTestPNG(t, 600, 300))
+	require.NoError(t, err)
+
+	img, err := jpeg.Decode(bytes.NewReader(out))
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, AvatarSize, AvatarSize), img.Bounds())
+}
+
+func TestProcessAvatar_DoesNotUpscaleSmallImages(t *testing.T) {
+	out, err := processAvatar(encodeTestPNG(t, 64, 100))
+	require.NoError(t, err)
+
+	img, err := jpeg.Decode(bytes.NewReader(out))
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 64, 64), img.Bounds())
+}
+
+func TestProcessAvatar_RejectsNonImage(t *testing.T) {
+	_, err := processAvatar([]byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"))
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+}
+
+func TestAvatarService_UploadAvatar_ReplacesPreviousAvatar(t *testing.T) {
+	dir := t.TempDir()
+	store, err := storage.NewLocalStorage(dir, "/uploads")
+	require.NoError(t, err)
+
+	oldKey := "avatars/7/1.jpg"
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "avatars", "7"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, oldKey), []byte("old"), 0644))
+
+	userRepo := new(MockUserRepository)
+	userRepo.On("GetByID", uint(7)).Return(&entity.User{ID: 7, AvatarStorageKey: oldKey}, nil)
+	var newKey string
+	userRepo.On("UpdateProfile", uint(7), mock.MatchedBy(func(updates map[string]interface{}) bool {
+		newKey, _ = updates["avatar_storage_key"].(string)
+		return strings.HasPrefix(newKey, "avatars/7/") && updates["profile_picture"] == "/uploads/"+newKey
+	})).Return(nil)
+
+	svc, err := NewAvatarService(userRepo, store)
+	require.NoError(t, err)
+
+	url, err := svc.UploadAvatar(7, bytes.NewReader(encodeTestPNG(t, 300, 300)))
+
+	require.NoError(t, err)
+	assert.Equal(t, "/uploads/"+newKey, url)
+	assert.FileExists(t, filepath.Join(dir, newKey))
+	assert.NoFileExists(t, filepath.Join(dir, oldKey))
+	userRepo.AssertExpectations(t)
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+const csrfNonceKeyPrefix = "csrf:nonce:"
+
+// ErrCSRFNonceInvalid — nonce не выдан этой сессии, истёк или относится к другому семейству запросов
+var ErrCSRFNonceInvalid = fmt.Errorf("%w: invalid csrf nonce", apperrors.ErrForbidden)
+
+// csrfNonce — запись о выданном nonce. Family пуст у nonce страницы: он принимается
+// для любого семейства запросов, пока не истечёт.
+type csrfNonce struct {
+	UserID  uint   `json:"user_id"`
+	Family  string `json:"family,omitempty"`
+	Retired bool   `json:"retired,omitempty"` // заменён следующим nonce, действует до конца rotationGrace
+}
+
+// CSRFNonceService реализует synchronizer token для админ-панели: nonce хранятся на сервере
+// в рамках CSRF-сессии браузера (HttpOnly cookie) и не зависят от срока жизни access-токена.
+// Каждый принятый nonce семейства запросов заменяется новым; прежний действует ещё
+// rotationGrace, чтобы не отклонять параллельные запросы того же семейства.
+type CSRFNonceService struct {
+	cache         repository.CacheRepository
+	nonceTTL      time.Duration
+	rotationGrace time.Duration
+}
+
+// NewCSRFNonceService создает сервис CSRF nonce
+func NewCSRFNonceService(cache repository.CacheRepository, nonceTTL, rotationGrace time.Duration) *CSRFNonceService {
+	if nonceTTL <= 0 {
+		nonceTTL = 12 * time.Hour
+	}
+	if rotationGrace <= 0 {
+		rotationGrace = 30 * time.Second
+	}
+	return &CSRFNonceService{cache: cache, nonceTTL: nonceTTL, rotationGrace: rotationGrace}
+}
+
+// IssueNonce выдаёт nonce страницы для CSRF-сессии пользователя
+func (s *CSRFNonceService) IssueNonce(ctx context.Context, sessionID string, userID uint) (string, error) {
+	return s.issue(ctx, sessionID, csrfNonce{UserID: userID})
+}
+
+// VerifyNonce проверяет nonce запроса семейства family и возвращает следующий nonce этого семейства
+func (s *CSRFNonceService) VerifyNonce(ctx context.Context, sessionID string, userID uint, family, nonce string) (string, error) {
+	if sessionID == "" || nonce == "" {
+		return "", ErrCSRFNonceInvalid
+	}
+	cache := s.cache.WithContext(ctx)
+	key := csrfNonceKey(sessionID, nonce)
+
+	var record csrfNonce
+	if err := cache.GetJSON(key, &record); err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return "", ErrCSRFNonceInvalid
+		}
+		return "", fmt.Errorf("failed to load csrf nonce: %w", err)
+	}
+	if record.UserID != userID || (record.Family != "" && record.Family != family) {
+		return "", ErrCSRFNonceInvalid
+	}
+	if record.Family != "" && !record.Retired {
+		record.Retired = true
+		if err := cache.SetJSON(key, record, s.rotationGrace); err != nil {
+			return "", fmt.Errorf("failed to retire csrf nonce: %w", err)
+		}
+	}
+	return s.issue(ctx, sessionID, csrfNonce{UserID: userID, Family: family})
+}
+
+func (s *CSRFNonceService) issue(ctx context.Context, sessionID string, record csrfNonce) (string, error) {
+	if sessionID == "" {
+		return "", fmt.Errorf("%w: csrf session is required", apperrors.ErrValidation)
+	}
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate csrf nonce: %w", err)
+	}
+	nonce := hex.EncodeToString(buf)
+	if err := s.cache.WithContext(ctx).SetJSON(csrfNonceKey(sessionID, nonce), record, s.nonceTTL); err != nil {
+		return "", fmt.Errorf("failed to store csrf nonce: %w", err)
+	}
+	return nonce, nil
+}
+
+func csrfNonceKey(sessionID, nonce string) string {
+	return csrfNonceKeyPrefix + sessionID + ":" + nonce
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+)
+
+// ttlJSONCache — memoryJSONCache, запоминающий срок жизни ключей
+type ttlJSONCache struct {
+	*memoryJSONCache
+	ttl map[string]time.Duration
+}
+
+func newTTLJSONCache() *ttlJSONCache {
+	return &ttlJSONCache{
+		memoryJSONCache: &memoryJSONCache{values: map[string][]byte{}},
+		ttl:             map[string]time.Duration{},
+	}
+}
+
+func (m *ttlJSONCache) SetJSON(key string, value interface{}, expiration time.Duration) error {
+	m.ttl[key] = expiration
+	return m.memoryJSONCache.SetJSON(key, value, expiration)
+}
+
+func (m *ttlJSONCache) WithContext(ctx context.Context) repository.CacheRepository { return m }
+
+func TestCSRFNonceService_RotatesPerFamily(t *testing.T) {
+	cache := newTTLJSONCache()
+	svc := NewCSRFNonceService(cache, time.Hour, 30*time.Second)
+	ctx := context.Background()
+
+	root, err := svc.IssueNonce(ctx, "session-1", 7)
+	require.NoError(t, err)
+
+	// Nonce страницы принимается для любого семейства и не расходуется
+	webhooks, err := svc.VerifyNonce(ctx, "session-1", 7, "admin/webhooks", root)
+	require.NoError(t, err)
+	quizzes, err := svc.VerifyNonce(ctx, "session-1", 7, "quizzes", root)
+	require.NoError(t, err)
+	assert.NotEqual(t, webhooks, quizzes)
+
+	// Nonce семейства не подходит для другого семейства
+	_, err = svc.VerifyNonce(ctx, "session-1", 7, "quizzes", webhooks)
+	assert.ErrorIs(t, err, ErrCSRFNonceInvalid)
+
+	// После использования nonce заменяется следующим, а прежний действует только rotationGrace
+	next, err := svc.VerifyNonce(ctx, "session-1", 7, "admin/webhooks", webhooks)
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, cache.ttl[csrfNonceKey("session-1", webhooks)])
+	assert.Equal(t, time.Hour, cache.ttl[csrfNonceKey("session-1", next)])
+
+	_, err = svc.VerifyNonce(ctx, "session-1", 7, "admin/webhooks", webhooks)
+	require.NoError(t, err, "параллельный запрос со старым nonce в пределах grace")
+	assert.Equal(t, 30*time.Second, cache.ttl[csrfNonceKey("session-1", webhooks)])
+
+	delete(cache.values, csrfNonceKey("session-1", webhooks)) // grace истёк
+	_, err = svc.VerifyNonce(ctx, "session-1", 7, "admin/webhooks", webhooks)
+	assert.ErrorIs(t, err, ErrCSRFNonceInvalid)
+}
+
+func TestCSRFNonceService_BoundToSessionAndUser(t *testing.T) {
+	svc := NewCSRFNonceService(newTTLJSONCache(), time.Hour, 30*time.Second)
+	ctx := context.Background()
+
+	root, err := svc.IssueNonce(ctx, "session-1", 7)
+	require.NoError(t, err)
+
+	_, err = svc.VerifyNonce(ctx, "session-2", 7, "quizzes", root)
+	assert.ErrorIs(t, err, ErrCSRFNonceInvalid)
+	_, err = svc.VerifyNonce(ctx, "session-1", 8, "quizzes", root)
+	assert.ErrorIs(t, err, ErrCSRFNonceInvalid)
+	_, err = svc.VerifyNonce(ctx, "", 7, "quizzes", root)
+	assert.ErrorIs(t, err, ErrCSRFNonceInvalid)
+	_, err = svc.VerifyNonce(ctx, "session-1", 7, "quizzes", "")
+	assert.ErrorIs(t, err, ErrCSRFNonceInvalid)
+}
@@ -0,0 +1,364 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/service/quizmanager"
+)
+
+const (
+	// dailyChallengeKeyTTL — срок ключей выдачи и ответа в Redis: покрывает день и смену дня
+	dailyChallengeKeyTTL = 48 * time.Hour
+)
+
+// DailyChallengeConfig содержит настройки вопроса дня
+type DailyChallengeConfig struct {
+	Grace time.Duration // запас к лимиту времени вопроса на сетевую задержку
+}
+
+// DailyChallengeQuestion — вопрос дня для клиента, без верного ответа
+type DailyChallengeQuestion struct {
+	ID           uint               `json:"id"`
+	Text         string             `json:"text"`
+	Options      entity.StringArray `json:"options"`
+	TextKK       string             `json:"text_kk,omitempty"`
+	OptionsKK    entity.StringArray `json:"options_kk,omitempty"`
+	TimeLimitSec int                `json:"time_limit_sec"`
+	Difficulty   int                `json:"difficulty"`
+}
+
+// DailyChallengeView — вопрос дня для игрока: до ответа — с временем выдачи и сроком,
+// после — с его ответом и верным вариантом
+type DailyChallengeView struct {
+	Date          string                       `json:"date"`
+	Question      DailyChallengeQuestion       `json:"question"`
+	IssuedAtMs    int64                        `json:"issued_at_ms,omitempty"`
+	DeadlineMs    int64                        `json:"deadline_ms,omitempty"`
+	Answer        *entity.DailyChallengeAnswer `json:"answer,omitempty"`
+	CorrectOption *int                         `json:"correct_option,omitempty"` // только после ответа
+	NextAtMs      int64                        `json:"next_at_ms"`               // начало следующего дня
+}
+
+// DailyChallengeResult — итог ответа на вопрос дня
+type DailyChallengeResult struct {
+	Answer          *entity.DailyChallengeAnswer `json:"answer"`
+	CorrectOption   int                          `json:"correct_option"`
+	DailyStreak     int                          `json:"daily_streak"`
+	BestDailyStreak int                          `json:"best_daily_streak"`
+}
+
+// DailyLeaderboardUser — игрок в лидерборде вопроса дня
+type DailyLeaderboardUser struct {
+	Rank           int    `json:"rank"`
+	UserID         uint   `json:"user_id"`
+	Username       string `json:"username"`
+	ProfilePicture string `json:"profile_picture"`
+	ResponseTimeMs int64  `json:"response_time_ms"`
+	Anonymous      bool   `json:"anonymous,omitempty"` // игрок скрыл себя из лидербордов
+}
+
+// DailyChallengeService — вопрос дня: один вопрос общего пула на день (UTC) для всех игроков,
+// ответ по REST. Вопрос выбирает AdaptiveQuestionSelector с seed от даты, поэтому инстансы
+// выбирают одинаково, а сложность следует доле верных ответов предыдущего дня. Время ответа
+// отсчитывается от первой выдачи вопроса игроку (ключ в Redis), повторный ответ отсекается
+// SetNX в Redis ещё до уникального индекса базы.
+type DailyChallengeService struct {
+	repo         repository.DailyChallengeRepository
+	questionRepo repository.QuestionRepository
+	cacheRepo    repository.CacheRepository
+	selector     *quizmanager.AdaptiveQuestionSelector
+	cfg          DailyChallengeConfig
+	now          func() time.Time
+
+	mu      sync.Mutex
+	current *entity.DailyChallenge // вопрос текущего дня
+}
+
+// NewDailyChallengeService создает сервис вопроса дня
+func NewDailyChallengeService(repo repository.DailyChallengeRepository, questionRepo repository.QuestionRepository, cacheRepo repository.CacheRepository, cfg DailyChallengeConfig) *DailyChallengeService {
+	return &DailyChallengeService{
+		repo:         repo,
+		questionRepo: questionRepo,
+		cacheRepo:    cacheRepo,
+		selector: quizmanager.NewAdaptiveQuestionSelector(quizmanager.DefaultDifficultyConfig(), &quizmanager.Dependencies{
+			QuestionRepo: questionRepo,
+			CacheRepo:    cacheRepo,
+		}),
+		cfg: cfg,
+		now: time.Now,
+	}
+}
+
+// dailyChallengeDay возвращает календарный день (UTC) момента t
+func dailyChallengeDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+// dailyChallengeSeed — seed выбора вопроса дня: одинаков на всех инстансах
+func dailyChallengeSeed(day time.Time) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte("daily-challenge:" + day.Format(time.DateOnly)))
+	return h.Sum64()
+}
+
+func dailyIssuedKey(day time.Time, userID uint) string {
+	return fmt.Sprintf("daily:%s:issued:%d", day.Format(time.DateOnly), userID)
+}
+
+func dailyAnsweredKey(day time.Time, userID uint) string {
+	return fmt.Sprintf("daily:%s:answered:%d", day.Format(time.DateOnly), userID)
+}
+
+// challenge возвращает вопрос дня day, выбирая его при первом обращении
+func (s *DailyChallengeService) challenge(day time.Time) (*entity.DailyChallenge, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.current != nil && s.current.Day.Equal(day) {
+		return s.current, nil
+	}
+
+	challenge, err := s.repo.GetChallenge(day)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		challenge, err = s.selectChallenge(day)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if challenge.Question == nil {
+		return nil, fmt.Errorf("daily challenge question %d not found", challenge.QuestionID)
+	}
+	challenge.Day = day
+	s.current = challenge
+	return challenge, nil
+}
+
+// selectChallenge выбирает и сохраняет вопрос дня
+func (s *DailyChallengeService) selectChallenge(day time.Time) (*entity.DailyChallenge, error) {
+	passRate := -1.0
+	total, correct, err := s.repo.CountAnswers(day.AddDate(0, 0, -1))
+	if err != nil {
+		return nil, err
+	}
+	if total > 0 {
+		passRate = float64(correct) / float64(total)
+	}
+
+	dayNumber := int(day.Unix() / int64(24*time.Hour/time.Second))
+	question, err := s.selector.SelectDailyQuestion(dayNumber, dailyChallengeSeed(day), passRate)
+	if err != nil {
+		return nil, err
+	}
+	challenge, err := s.repo.CreateChallenge(&entity.DailyChallenge{
+		Day:        day,
+		QuestionID: question.ID,
+		Difficulty: question.Difficulty,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Вопрос дня больше не попадает в автовыбор викторин
+	if challenge.QuestionID == question.ID {
+		if err := s.questionRepo.MarkAsUsed([]uint{question.ID}); err != nil {
+			log.Printf("[DailyChallengeService] Не удалось пометить вопрос %d использованным: %v", question.ID, err)
+		}
+		log.Printf("[DailyChallengeService] Вопрос дня %s: вопрос %d", day.Format(time.DateOnly), question.ID)
+	}
+	return challenge, nil
+}
+
+// GetToday возвращает вопрос дня для пользователя. Первый запрос фиксирует время выдачи:
+// повторные запросы не продлевают время на ответ.
+func (s *DailyChallengeService) GetToday(userID uint) (*DailyChallengeView, error) {
+	now := s.now()
+	day := dailyChallengeDay(now)
+	challenge, err := s.challenge(day)
+	if err != nil {
+		return nil, err
+	}
+	question := challenge.Question
+	view := &DailyChallengeView{
+		Date: day.Format(time.DateOnly),
+		Question: DailyChallengeQuestion{
+			ID:           question.ID,
+			Text:         question.Text,
+			Options:      question.Options,
+			TextKK:       question.TextKK,
+			OptionsKK:    question.OptionsKK,
+			TimeLimitSec: question.TimeLimitSec,
+			Difficulty:   question.Difficulty,
+		},
+		NextAtMs: day.AddDate(0, 0, 1).UnixMilli(),
+	}
+
+	answer, err := s.repo.GetAnswer(day, userID)
+	if err == nil {
+		correct := question.CorrectOption
+		view.Answer = answer
+		view.CorrectOption = &correct
+		return view, nil
+	}
+	if !errors.Is(err, apperrors.ErrNotFound) {
+		return nil, err
+	}
+
+	issuedAt, err := s.issue(day, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	view.IssuedAtMs = issuedAt.UnixMilli()
+	view.DeadlineMs = issuedAt.Add(time.Duration(question.TimeLimitSec) * time.Second).UnixMilli()
+	return view, nil
+}
+
+// issue фиксирует первую выдачу вопроса дня пользователю и возвращает её время
+func (s *DailyChallengeService) issue(day time.Time, userID uint, now time.Time) (time.Time, error) {
+	key := dailyIssuedKey(day, userID)
+	if _, err := s.cacheRepo.SetNX(key, now.UnixMilli(), dailyChallengeKeyTTL); err != nil {
+		return time.Time{}, fmt.Errorf("failed to record daily challenge issue: %w", err)
+	}
+	return s.issuedAt(day, userID)
+}
+
+func (s *DailyChallengeService) issuedAt(day time.Time, userID uint) (time.Time, error) {
+	value, err := s.cacheRepo.Get(dailyIssuedKey(day, userID))
+	if err != nil {
+		return time.Time{}, err
+	}
+	ms, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid daily challenge issue time %q: %w", value, err)
+	}
+	return time.UnixMilli(ms), nil
+}
+
+// Answer принимает ответ на вопрос дня. questionID — вопрос, который видел игрок: ответ на
+// вопрос прошлого дня после полуночи отклоняется. Ответ после лимита времени засчитывается неверным.
+func (s *DailyChallengeService) Answer(userID, questionID uint, selectedOption int) (*DailyChallengeResult, error) {
+	now := s.now()
+	day := dailyChallengeDay(now)
+	challenge, err := s.challenge(day)
+	if err != nil {
+		return nil, err
+	}
+	question := challenge.Question
+	if questionID != question.ID {
+		return nil, fmt.Errorf("%w: daily challenge question has changed", apperrors.ErrConflict)
+	}
+	if selectedOption < 0 || selectedOption >= len(question.Options) {
+		return nil, fmt.Errorf("%w: selected_option out of range", apperrors.ErrValidation)
+	}
+
+	issuedAt, err := s.issuedAt(day, userID)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return nil, fmt.Errorf("%w: daily challenge question was not requested", apperrors.ErrValidation)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Защита от повторной отправки: первый ответ занимает ключ
+	answeredKey := dailyAnsweredKey(day, userID)
+	first, err := s.cacheRepo.SetNX(answeredKey, now.UnixMilli(), dailyChallengeKeyTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock daily challenge answer: %w", err)
+	}
+	if !first {
+		return nil, fmt.Errorf("%w: daily challenge already answered", apperrors.ErrConflict)
+	}
+
+	responseTime := now.Sub(issuedAt)
+	timedOut := responseTime > time.Duration(question.TimeLimitSec)*time.Second+s.cfg.Grace
+	answer := &entity.DailyChallengeAnswer{
+		Day:            day,
+		UserID:         userID,
+		QuestionID:     question.ID,
+		SelectedOption: selectedOption,
+		IsCorrect:      !timedOut && selectedOption == question.CorrectOption,
+		TimedOut:       timedOut,
+		ResponseTimeMs: responseTime.Milliseconds(),
+	}
+	var streak entity.UserStreak
+	err = s.repo.SaveAnswer(answer, func(current *entity.UserStreak) {
+		advanceDailyStreak(current, day)
+		streak = *current
+	})
+	if err != nil {
+		if !errors.Is(err, apperrors.ErrConflict) {
+			// Ответ не сохранён — игрок может отправить его снова
+			if delErr := s.cacheRepo.Delete(answeredKey); delErr != nil {
+				log.Printf("[DailyChallengeService] Не удалось снять блокировку ответа пользователя %d: %v", userID, delErr)
+			}
+		}
+		return nil, err
+	}
+
+	return &DailyChallengeResult{
+		Answer:          answer,
+		CorrectOption:   question.CorrectOption,
+		DailyStreak:     streak.DailyStreak,
+		BestDailyStreak: streak.BestDailyStreak,
+	}, nil
+}
+
+// advanceDailyStreak продлевает серию вопросов дня, если игрок отвечал вчера, иначе начинает
+// заново. Повторный вызов за тот же день ничего не меняет.
+func advanceDailyStreak(streak *entity.UserStreak, day time.Time) {
+	if streak.LastDailyDay != nil {
+		last := dailyChallengeDay(*streak.LastDailyDay)
+		if last.Equal(day) {
+			return
+		}
+		if last.AddDate(0, 0, 1).Equal(day) {
+			streak.DailyStreak++
+		} else {
+			streak.DailyStreak = 1
+		}
+	} else {
+		streak.DailyStreak = 1
+	}
+	streak.BestDailyStreak = max(streak.BestDailyStreak, streak.DailyStreak)
+	lastDay := day
+	streak.LastDailyDay = &lastDay
+}
+
+// GetLeaderboard возвращает лидерборд вопроса дня date (YYYY-MM-DD, пусто — сегодня):
+// верно ответившие, быстрые первыми
+func (s *DailyChallengeService) GetLeaderboard(date string, page, pageSize int) ([]DailyLeaderboardUser, int64, error) {
+	day := dailyChallengeDay(s.now())
+	if date != "" {
+		parsed, err := time.Parse(time.DateOnly, date)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w: date must be YYYY-MM-DD", apperrors.ErrValidation)
+		}
+		day = parsed
+	}
+	page, pageSize = normalizeWalletPage(page, pageSize)
+	offset := (page - 1) * pageSize
+
+	entries, total, err := s.repo.GetLeaderboard(day, pageSize, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	users := make([]DailyLeaderboardUser, len(entries))
+	for i, entry := range entries {
+		identity := publicLeaderboardIdentity(entry.UserID, entry.Username, entry.ProfilePicture, entry.ShowOnLeaderboards)
+		users[i] = DailyLeaderboardUser{
+			Rank:           offset + i + 1,
+			UserID:         identity.UserID,
+			Username:       identity.Username,
+			ProfilePicture: identity.ProfilePicture,
+			ResponseTimeMs: entry.ResponseTimeMs,
+			Anonymous:      identity.Anonymous,
+		}
+	}
+	return users, total, nil
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// fakeDailyChallengeRepo хранит вопросы дня и ответы в памяти
+type fakeDailyChallengeRepo struct {
+	challenges  map[string]*entity.DailyChallenge
+	questions   map[uint]*entity.Question
+	answers     []entity.DailyChallengeAnswer
+	streaks     map[uint]*entity.UserStreak
+	leaderboard []repository.DailyLeaderboardEntry
+}
+
+func newFakeDailyChallengeRepo(questions ...*entity.Question) *fakeDailyChallengeRepo {
+	repo := &fakeDailyChallengeRepo{
+		challenges: make(map[string]*entity.DailyChallenge),
+		questions:  make(map[uint]*entity.Question),
+		streaks:    make(map[uint]*entity.UserStreak),
+	}
+	for _, q := range questions {
+		repo.questions[q.ID] = q
+	}
+	return repo
+}
+
+func (r *fakeDailyChallengeRepo) GetChallenge(day time.Time) (*entity.DailyChallenge, error) {
+	challenge, ok := r.challenges[day.Format(time.DateOnly)]
+	if !ok {
+		return nil, apperrors.ErrNotFound
+	}
+	copied := *challenge
+	copied.Question = r.questions[challenge.QuestionID]
+	return &copied, nil
+}
+
+func (r *fakeDailyChallengeRepo) CreateChallenge(challenge *entity.DailyChallenge) (*entity.DailyChallenge, error) {
+	if _, ok := r.challenges[challenge.Day.Format(time.DateOnly)]; !ok {
+		r.challenges[challenge.Day.Format(time.DateOnly)] = challenge
+	}
+	return r.GetChallenge(challenge.Day)
+}
+
+func (r *fakeDailyChallengeRepo) CountAnswers(day time.Time) (int64, int64, error) {
+	var total, correct int64
+	for _, a := range r.answers {
+		if a.Day.Equal(day) {
+			total++
+			if a.IsCorrect {
+				correct++
+			}
+		}
+	}
+	return total, correct, nil
+}
+
+func (r *fakeDailyChallengeRepo) GetAnswer(day time.Time, userID uint) (*entity.DailyChallengeAnswer, error) {
+	for _, a := range r.answers {
+		if a.Day.Equal(day) && a.UserID == userID {
+			answer := a
+			return &answer, nil
+		}
+	}
+	return nil, apperrors.ErrNotFound
+}
+
+func (r *fakeDailyChallengeRepo) SaveAnswer(answer *entity.DailyChallengeAnswer, update repository.DailyStreakUpdate) error {
+	if _, err := r.GetAnswer(answer.Day, answer.UserID); err == nil {
+		return apperrors.ErrConflict
+	}
+	r.answers = append(r.answers, *answer)
+	streak, ok := r.streaks[answer.UserID]
+	if !ok {
+		streak = &entity.UserStreak{UserID: answer.UserID}
+		r.streaks[answer.UserID] = streak
+	}
+	update(streak)
+	return nil
+}
+
+func (r *fakeDailyChallengeRepo) GetLeaderboard(day time.Time, limit, offset int) ([]repository.DailyLeaderboardEntry, int64, error) {
+	return r.leaderboard, int64(len(r.leaderboard)), nil
+}
+
+func TestDailyChallengeService_AnswerFlow(t *testing.T) {
+	question := &entity.Question{ID: 7, Text: "2+2?", Options: entity.StringArray{"3", "4"}, CorrectOption: 1, TimeLimitSec: 10, Difficulty: 1}
+	repo := newFakeDailyChallengeRepo(question)
+	questionRepo := new(MockQuestionRepoForQuizService)
+	questionRepo.On("GetPoolQuestionBySeed", mock.Anything, mock.Anything).Return(question, nil)
+	questionRepo.On("MarkAsUsed", []uint{7}).Return(nil).Once()
+	cache := &memorySecondChanceCache{values: make(map[string]string)}
+
+	svc := NewDailyChallengeService(repo, questionRepo, cache, DailyChallengeConfig{Grace: time.Second})
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	// Ответ без выдачи вопроса отклоняется
+	_, err := svc.Answer(1, 7, 1)
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+
+	view, err := svc.GetToday(1)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10-16", view.Date)
+	assert.Equal(t, uint(7), view.Question.ID)
+	assert.Nil(t, view.CorrectOption)
+	assert.Equal(t, now.Add(10*time.Second).UnixMilli(), view.DeadlineMs)
+
+	// Повторный запрос не сдвигает время выдачи
+	now = now.Add(4 * time.Second)
+	view, err = svc.GetToday(1)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(6*time.Second).UnixMilli(), view.DeadlineMs)
+
+	// Ответ на вопрос другого дня — конфликт
+	_, err = svc.Answer(1, 8, 1)
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+
+	result, err := svc.Answer(1, 7, 1)
+	require.NoError(t, err)
+	assert.True(t, result.Answer.IsCorrect)
+	assert.Equal(t, int64(4000), result.Answer.ResponseTimeMs)
+	assert.Equal(t, 1, result.DailyStreak)
+
+	// Повторная отправка отсекается в Redis
+	_, err = svc.Answer(1, 7, 0)
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+	assert.Len(t, repo.answers, 1)
+
+	view, err = svc.GetToday(1)
+	require.NoError(t, err)
+	require.NotNil(t, view.Answer)
+	assert.Equal(t, 1, *view.CorrectOption)
+
+	// Ответ после лимита времени засчитывается неверным
+	_, err = svc.GetToday(2)
+	require.NoError(t, err)
+	now = now.Add(12 * time.Second)
+	result, err = svc.Answer(2, 7, 1)
+	require.NoError(t, err)
+	assert.False(t, result.Answer.IsCorrect)
+	assert.True(t, result.Answer.TimedOut)
+	questionRepo.AssertExpectations(t)
+}
+
+func TestAdvanceDailyStreak(t *testing.T) {
+	var streak entity.UserStreak
+	day := time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC)
+
+	advanceDailyStreak(&streak, day)
+	advanceDailyStreak(&streak, day.AddDate(0, 0, 1))
+	advanceDailyStreak(&streak, day.AddDate(0, 0, 1)) // тот же день не считается дважды
+	assert.Equal(t, 2, streak.DailyStreak)
+
+	// Пропущенный день обрывает серию, лучшая сохраняется
+	advanceDailyStreak(&streak, day.AddDate(0, 0, 3))
+	assert.Equal(t, 1, streak.DailyStreak)
+	assert.Equal(t, 2, streak.BestDailyStreak)
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/config"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+// OutgoingEmail — письмо для отправки через провайдера
+type OutgoingEmail struct {
+	Kind           string // entity.EmailKind*
+	To             string
+	Subject        string
+	Text           string
+	HTML           string
+	IdempotencyKey string // провайдеры, которые его поддерживают, не отправят письмо дважды
+}
+
+// EmailProvider отправляет письмо через конкретного провайдера
+type EmailProvider interface {
+	Name() string
+	// Send возвращает идентификатор письма у провайдера — по нему приходят события доставки.
+	// Ошибки, которые имеет смысл повторить, оборачиваются в *EmailSendError с Retryable=true.
+	Send(ctx context.Context, msg OutgoingEmail) (providerMessageID string, err error)
+}
+
+// EmailCallbackRequest — входящий обратный вызов провайдера о доставке
+type EmailCallbackRequest struct {
+	Header http.Header
+	Query  url.Values
+	Body   []byte
+}
+
+// EmailCallbackEvent — событие доставки, разобранное из обратного вызова
+type EmailCallbackEvent struct {
+	ProviderMessageID string
+	ExternalID        string // идентификатор уведомления у провайдера, для отбрасывания повторов
+	EventType         string // entity.EmailEvent*
+	Detail            string
+	OccurredAt        time.Time
+}
+
+// EmailCallbackParser проверяет подлинность обратного вызова (apperrors.ErrUnauthorized при неудаче)
+// и разбирает события доставки. Реализуется провайдерами, которые умеют сообщать о доставке.
+type EmailCallbackParser interface {
+	ParseCallback(ctx context.Context, req EmailCallbackRequest) ([]EmailCallbackEvent, error)
+}
+
+// EmailSendError — ошибка отправки с признаком, стоит ли повторять попытку
+type EmailSendError struct {
+	Retryable  bool
+	RetryAfter time.Duration // задержка, запрошенная провайдером; 0 — по политике повторов
+	Err        error
+}
+
+func (e *EmailSendError) Error() string { return e.Err.Error() }
+
+func (e *EmailSendError) Unwrap() error { return e.Err }
+
+// retryableEmailError помечает ошибку как временную
+func retryableEmailError(err error, retryAfter time.Duration) error {
+	return &EmailSendError{Retryable: true, RetryAfter: retryAfter, Err: err}
+}
+
+// EmailRetryPolicy — повторы отправки через одного провайдера
+type EmailRetryPolicy struct {
+	MaxAttempts    int // попыток, включая первую
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// DefaultEmailRetryPolicy — политика для провайдеров без своих настроек
+var DefaultEmailRetryPolicy = EmailRetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     30 * time.Second,
+}
+
+// backoff возвращает задержку перед попыткой attempt+1 (экспоненциально, не больше MaxBackoff)
+func (p EmailRetryPolicy) backoff(attempt int, requested time.Duration) time.Duration {
+	delay := p.InitialBackoff << attempt
+	if requested > delay {
+		delay = requested
+	}
+	if delay <= 0 || delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	return delay
+}
+
+type registeredEmailProvider struct {
+	provider EmailProvider
+	policy   EmailRetryPolicy
+}
+
+// EmailProviderRegistry хранит настроенных провайдеров и их политики повторов
+type EmailProviderRegistry struct {
+	providers map[string]registeredEmailProvider
+}
+
+// NewEmailProviderRegistry создает пустой реестр
+func NewEmailProviderRegistry() *EmailProviderRegistry {
+	return &EmailProviderRegistry{providers: make(map[string]registeredEmailProvider)}
+}
+
+// Register добавляет провайдера; некорректные поля политики заменяются значениями по умолчанию
+func (r *EmailProviderRegistry) Register(provider EmailProvider, policy EmailRetryPolicy) error {
+	if provider == nil {
+		return fmt.Errorf("email provider is required")
+	}
+	if _, exists := r.providers[provider.Name()]; exists {
+		return fmt.Errorf("email provider %q is already registered", provider.Name())
+	}
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = DefaultEmailRetryPolicy.MaxAttempts
+	}
+	if policy.InitialBackoff <= 0 {
+		policy.InitialBackoff = DefaultEmailRetryPolicy.InitialBackoff
+	}
+	if policy.MaxBackoff < policy.InitialBackoff {
+		policy.MaxBackoff = DefaultEmailRetryPolicy.MaxBackoff
+	}
+	r.providers[provider.Name()] = registeredEmailProvider{provider: provider, policy: policy}
+	return nil
+}
+
+// Get возвращает провайдера и его политику повторов
+func (r *EmailProviderRegistry) Get(name string) (EmailProvider, EmailRetryPolicy, bool) {
+	registered, ok := r.providers[name]
+	return registered.provider, registered.policy, ok
+}
+
+// NewEmailProviderRegistryFromConfig создает провайдеров основной и резервных цепочек из конфигурации.
+// Возвращает реестр и имена провайдеров в порядке отправки.
+func NewEmailProviderRegistryFromConfig(cfg config.EmailConfig) (*EmailProviderRegistry, []string, error) {
+	registry := NewEmailProviderRegistry()
+	var chain []string
+	for _, name := range append([]string{cfg.Provider}, cfg.FallbackProviders...) {
+		name = strings.ToLower(strings.TrimSpace(name))
+		var (
+			provider EmailProvider
+			err      error
+		)
+		switch name {
+		case entity.EmailProviderResend:
+			provider, err = NewResendEmailProvider(cfg.ResendAPIKey, cfg.From, cfg.ResendWebhookSecret)
+		case entity.EmailProviderSMTP:
+			provider, err = NewSMTPEmailProvider(SMTPConfig{
+				Host:     cfg.SMTP.Host,
+				Port:     cfg.SMTP.Port,
+				Username: cfg.SMTP.Username,
+				Password: cfg.SMTP.Password,
+				TLSMode:  cfg.SMTP.TLS,
+				From:     cfg.From,
+			})
+		case entity.EmailProviderSES:
+			provider, err = NewSESEmailProvider(SESConfig{
+				Region:           cfg.SES.Region,
+				Endpoint:         cfg.SES.Endpoint,
+				AccessKey:        cfg.SES.AccessKey,
+				SecretKey:        cfg.SES.SecretKey,
+				ConfigurationSet: cfg.SES.ConfigurationSet,
+				CallbackToken:    cfg.SES.CallbackToken,
+				From:             cfg.From,
+			})
+		default:
+			return nil, nil, fmt.Errorf("unsupported email provider %q", name)
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("email provider %s: %w", name, err)
+		}
+
+		retry := cfg.Retry[name]
+		policy := EmailRetryPolicy{
+			MaxAttempts:    retry.MaxAttempts,
+			InitialBackoff: time.Duration(retry.InitialBackoffMs) * time.Millisecond,
+			MaxBackoff:     time.Duration(retry.MaxBackoffMs) * time.Millisecond,
+		}
+		if err := registry.Register(provider, policy); err != nil {
+			return nil, nil, err
+		}
+		chain = append(chain, name)
+	}
+	return registry, chain, nil
+}
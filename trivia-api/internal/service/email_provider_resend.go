@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/resend/resend-go/v2"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// resendWebhookTolerance — допустимое расхождение времени подписи обратного вызова
+const resendWebhookTolerance = 5 * time.Minute
+
+// resendEventTypes сопоставляет события Resend с событиями доставки
+var resendEventTypes = map[string]string{
+	"email.delivered":        entity.EmailEventDelivered,
+	"email.delivery_delayed": entity.EmailEventDelayed,
+	"email.bounced":          entity.EmailEventBounced,
+	"email.complained":       entity.EmailEventComplained,
+	"email.opened":           entity.EmailEventOpened,
+	"email.clicked":          entity.EmailEventClicked,
+}
+
+// ResendEmailProvider отправляет письма через Resend REST API
+type ResendEmailProvider struct {
+	from          string
+	client        *resend.Client
+	webhookSecret []byte
+	now           func() time.Time
+}
+
+// NewResendEmailProvider создает провайдера Resend. webhookSecret (whsec_...) нужен для
+// обратных вызовов о доставке; пустой — обратные вызовы отклоняются.
+func NewResendEmailProvider(apiKey, from, webhookSecret string) (*ResendEmailProvider, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("resend api key is required")
+	}
+	if from == "" {
+		return nil, fmt.Errorf("email from is required")
+	}
+	var secret []byte
+	if webhookSecret != "" {
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(webhookSecret, "whsec_"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid resend webhook secret: %w", err)
+		}
+		secret = decoded
+	}
+	return &ResendEmailProvider{
+		from:          from,
+		client:        resend.NewClient(apiKey),
+		webhookSecret: secret,
+		now:           time.Now,
+	}, nil
+}
+
+func (p *ResendEmailProvider) Name() string { return entity.EmailProviderResend }
+
+func (p *ResendEmailProvider) Send(ctx context.Context, msg OutgoingEmail) (string, error) {
+	params := &resend.SendEmailRequest{
+		From:    p.from,
+		To:      []string{msg.To},
+		Subject: msg.Subject,
+		Text:    msg.Text,
+		Html:    msg.HTML,
+	}
+	options := &resend.SendEmailOptions{}
+	if key := strings.TrimSpace(msg.IdempotencyKey); key != "" {
+		options.IdempotencyKey = key
+	}
+
+	sent, err := p.client.Emails.SendWithOptions(ctx, params, options)
+	if err != nil {
+		if retryAfter, ok := resendRetryAfter(err); ok {
+			return "", retryableEmailError(fmt.Errorf("resend send failed: %w", err), retryAfter)
+		}
+		return "", fmt.Errorf("resend send failed: %w", err)
+	}
+	return sent.Id, nil
+}
+
+// resendRetryAfter определяет временные ошибки: rate limit и сетевые сбои
+func resendRetryAfter(err error) (time.Duration, bool) {
+	var rateLimitErr *resend.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		if seconds, convErr := strconv.Atoi(strings.TrimSpace(rateLimitErr.RetryAfter)); convErr == nil && seconds > 0 {
+			return time.Duration(seconds) * time.Second, true
+		}
+		return 0, true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && (netErr.Timeout() || netErr.Temporary()) {
+		return 0, true
+	}
+
+	msg := strings.ToLower(err.Error())
+	if strings.Contains(msg, "timeout") || strings.Contains(msg, "temporar") {
+		return 0, true
+	}
+	return 0, false
+}
+
+// resendWebhookPayload — тело обратного вызова Resend
+type resendWebhookPayload struct {
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      struct {
+		EmailID string `json:"email_id"`
+		Bounce  struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"bounce"`
+	} `json:"data"`
+}
+
+// ParseCallback проверяет подпись Svix (svix-id, svix-timestamp, svix-signature) и разбирает событие
+func (p *ResendEmailProvider) ParseCallback(ctx context.Context, req EmailCallbackRequest) ([]EmailCallbackEvent, error) {
+	if len(p.webhookSecret) == 0 {
+		return nil, fmt.Errorf("%w: resend webhook secret is not configured", apperrors.ErrUnauthorized)
+	}
+	id := req.Header.Get("svix-id")
+	timestamp := req.Header.Get("svix-timestamp")
+	if id == "" || timestamp == "" {
+		return nil, fmt.Errorf("%w: missing svix headers", apperrors.ErrUnauthorized)
+	}
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid svix timestamp", apperrors.ErrUnauthorized)
+	}
+	if drift := p.now().Sub(time.Unix(seconds, 0)); drift > resendWebhookTolerance || drift < -resendWebhookTolerance {
+		return nil, fmt.Errorf("%w: svix timestamp is outside the tolerance", apperrors.ErrUnauthorized)
+	}
+
+	mac := hmac.New(sha256.New, p.webhookSecret)
+	mac.Write([]byte(id + "." + timestamp + "."))
+	mac.Write(req.Body)
+	expected := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	valid := false
+	// Заголовок содержит несколько подписей через пробел (при смене секрета): "v1,<base64> v1,<base64>"
+	for _, signature := range strings.Fields(req.Header.Get("svix-signature")) {
+		version, value, found := strings.Cut(signature, ",")
+		if found && version == "v1" && hmac.Equal([]byte(value), []byte(expected)) {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return nil, fmt.Errorf("%w: svix signature mismatch", apperrors.ErrUnauthorized)
+	}
+
+	var payload resendWebhookPayload
+	if err := json.Unmarshal(req.Body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: invalid resend webhook payload: %v", apperrors.ErrValidation, err)
+	}
+	eventType, ok := resendEventTypes[payload.Type]
+	if !ok || payload.Data.EmailID == "" {
+		return nil, nil
+	}
+	occurredAt := payload.CreatedAt
+	if occurredAt.IsZero() {
+		occurredAt = time.Unix(seconds, 0)
+	}
+	return []EmailCallbackEvent{{
+		ProviderMessageID: payload.Data.EmailID,
+		ExternalID:        id,
+		EventType:         eventType,
+		Detail:            strings.TrimSpace(payload.Data.Bounce.Type + " " + payload.Data.Bounce.Message),
+		OccurredAt:        occurredAt,
+	}}, nil
+}
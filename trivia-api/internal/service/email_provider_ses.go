@@ -0,0 +1,315 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+const (
+	sesAlgorithm  = "AWS4-HMAC-SHA256"
+	sesTimeFormat = "20060102T150405Z"
+	sesDateFormat = "20060102"
+	sesSendPath   = "/v2/email/outbound-emails"
+)
+
+// SESConfig содержит параметры Amazon SES (API v2)
+type SESConfig struct {
+	Region           string
+	Endpoint         string // пусто — https://email.<region>.amazonaws.com
+	AccessKey        string
+	SecretKey        string
+	ConfigurationSet string // набор конфигурации с публикацией событий в SNS
+	CallbackToken    string // секрет в query-параметре token адреса подписки SNS
+	From             string
+}
+
+// SESEmailProvider отправляет письма через Amazon SES API v2 с подписью AWS Signature V4.
+// События доставки приходят уведомлениями SNS.
+type SESEmailProvider struct {
+	cfg      SESConfig
+	endpoint *url.URL
+	client   *http.Client
+	now      func() time.Time
+}
+
+// NewSESEmailProvider создает провайдера SES
+func NewSESEmailProvider(cfg SESConfig) (*SESEmailProvider, error) {
+	if cfg.Region == "" {
+		return nil, fmt.Errorf("ses region is required")
+	}
+	if cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return nil, fmt.Errorf("ses credentials are required")
+	}
+	if cfg.From == "" {
+		return nil, fmt.Errorf("email from is required")
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://email." + cfg.Region + ".amazonaws.com"
+	}
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid ses endpoint %q", cfg.Endpoint)
+	}
+	return &SESEmailProvider{
+		cfg:      cfg,
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: 30 * time.Second},
+		now:      time.Now,
+	}, nil
+}
+
+func (p *SESEmailProvider) Name() string { return entity.EmailProviderSES }
+
+type sesContent struct {
+	Data    string `json:"Data"`
+	Charset string `json:"Charset"`
+}
+
+type sesSendRequest struct {
+	FromEmailAddress string `json:"FromEmailAddress"`
+	Destination      struct {
+		ToAddresses []string `json:"ToAddresses"`
+	} `json:"Destination"`
+	Content struct {
+		Simple struct {
+			Subject sesContent `json:"Subject"`
+			Body    struct {
+				Text *sesContent `json:"Text,omitempty"`
+				HTML *sesContent `json:"Html,omitempty"`
+			} `json:"Body"`
+		} `json:"Simple"`
+	} `json:"Content"`
+	ConfigurationSetName string `json:"ConfigurationSetName,omitempty"`
+}
+
+// Send отправляет письмо запросом SendEmail
+func (p *SESEmailProvider) Send(ctx context.Context, msg OutgoingEmail) (string, error) {
+	var payload sesSendRequest
+	payload.FromEmailAddress = p.cfg.From
+	payload.Destination.ToAddresses = []string{msg.To}
+	payload.Content.Simple.Subject = sesContent{Data: msg.Subject, Charset: "UTF-8"}
+	if msg.Text != "" {
+		payload.Content.Simple.Body.Text = &sesContent{Data: msg.Text, Charset: "UTF-8"}
+	}
+	if msg.HTML != "" {
+		payload.Content.Simple.Body.HTML = &sesContent{Data: msg.HTML, Charset: "UTF-8"}
+	}
+	payload.ConfigurationSetName = p.cfg.ConfigurationSet
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode ses request: %w", err)
+	}
+
+	u := *p.endpoint
+	u.Path = strings.TrimRight(u.Path, "/") + sesSendPath
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to build ses request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	p.sign(req, body)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", retryableEmailError(fmt.Errorf("ses send failed: %w", err), 0)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("ses send failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+		// Throttling и ошибки сервиса повторяются, остальные 4xx — ошибка в запросе или адресе
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			retryAfter, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+			return "", retryableEmailError(err, time.Duration(retryAfter)*time.Second)
+		}
+		return "", err
+	}
+
+	var sent struct {
+		MessageID string `json:"MessageId"`
+	}
+	if err := json.Unmarshal(respBody, &sent); err != nil || sent.MessageID == "" {
+		return "", fmt.Errorf("ses send: unexpected response: %s", strings.TrimSpace(string(respBody)))
+	}
+	return sent.MessageID, nil
+}
+
+// sign подписывает запрос заголовком Authorization (AWS Signature V4, сервис ses)
+func (p *SESEmailProvider) sign(req *http.Request, body []byte) {
+	now := p.now().UTC()
+	payloadHash := sha256.Sum256(body)
+	req.Header.Set("X-Amz-Date", now.Format(sesTimeFormat))
+
+	scope := now.Format(sesDateFormat) + "/" + p.cfg.Region + "/ses/aws4_request"
+	signedHeaders := "content-type;host;x-amz-date"
+	canonical := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		"content-type:" + req.Header.Get("Content-Type") + "\n" +
+			"host:" + req.URL.Host + "\n" +
+			"x-amz-date:" + now.Format(sesTimeFormat) + "\n",
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+	canonicalHash := sha256.Sum256([]byte(canonical))
+	stringToSign := sesAlgorithm + "\n" + now.Format(sesTimeFormat) + "\n" + scope + "\n" + hex.EncodeToString(canonicalHash[:])
+
+	key := sesHMAC([]byte("AWS4"+p.cfg.SecretKey), now.Format(sesDateFormat))
+	key = sesHMAC(key, p.cfg.Region)
+	key = sesHMAC(key, "ses")
+	key = sesHMAC(key, "aws4_request")
+	signature := hex.EncodeToString(sesHMAC(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sesAlgorithm, p.cfg.AccessKey, scope, signedHeaders, signature))
+}
+
+func sesHMAC(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// snsEnvelope — уведомление SNS
+type snsEnvelope struct {
+	Type         string    `json:"Type"`
+	MessageID    string    `json:"MessageId"`
+	Message      string    `json:"Message"`
+	SubscribeURL string    `json:"SubscribeURL"`
+	Timestamp    time.Time `json:"Timestamp"`
+}
+
+// sesEvent — событие SES: публикация событий (eventType) или уведомления адреса (notificationType)
+type sesEvent struct {
+	EventType        string `json:"eventType"`
+	NotificationType string `json:"notificationType"`
+	Mail             struct {
+		MessageID string `json:"messageId"`
+	} `json:"mail"`
+	Bounce *struct {
+		BounceType    string `json:"bounceType"`
+		BounceSubType string `json:"bounceSubType"`
+	} `json:"bounce"`
+	Complaint *struct {
+		FeedbackType string `json:"complaintFeedbackType"`
+	} `json:"complaint"`
+	DeliveryDelay *struct {
+		DelayType string `json:"delayType"`
+	} `json:"deliveryDelay"`
+}
+
+// ParseCallback проверяет токен адреса подписки, подтверждает подписку SNS и разбирает событие SES
+func (p *SESEmailProvider) ParseCallback(ctx context.Context, req EmailCallbackRequest) ([]EmailCallbackEvent, error) {
+	token := req.Query.Get("token")
+	if p.cfg.CallbackToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(p.cfg.CallbackToken)) != 1 {
+		return nil, fmt.Errorf("%w: invalid ses callback token", apperrors.ErrUnauthorized)
+	}
+
+	var envelope snsEnvelope
+	if err := json.Unmarshal(req.Body, &envelope); err != nil {
+		return nil, fmt.Errorf("%w: invalid sns payload: %v", apperrors.ErrValidation, err)
+	}
+	switch envelope.Type {
+	case "SubscriptionConfirmation":
+		return nil, p.confirmSubscription(ctx, envelope.SubscribeURL)
+	case "Notification":
+	default:
+		return nil, nil
+	}
+
+	var event sesEvent
+	if err := json.Unmarshal([]byte(envelope.Message), &event); err != nil {
+		return nil, fmt.Errorf("%w: invalid ses event: %v", apperrors.ErrValidation, err)
+	}
+	kind := event.EventType
+	if kind == "" {
+		kind = event.NotificationType
+	}
+
+	var eventType, detail string
+	switch kind {
+	case "Delivery":
+		eventType = entity.EmailEventDelivered
+	case "Bounce":
+		eventType = entity.EmailEventBounced
+		if event.Bounce != nil {
+			detail = strings.TrimSpace(event.Bounce.BounceType + " " + event.Bounce.BounceSubType)
+			// Временный отказ — SES ещё попытается доставить
+			if event.Bounce.BounceType == "Transient" {
+				eventType = entity.EmailEventDelayed
+			}
+		}
+	case "Complaint":
+		eventType = entity.EmailEventComplained
+		if event.Complaint != nil {
+			detail = event.Complaint.FeedbackType
+		}
+	case "DeliveryDelay":
+		eventType = entity.EmailEventDelayed
+		if event.DeliveryDelay != nil {
+			detail = event.DeliveryDelay.DelayType
+		}
+	case "Open":
+		eventType = entity.EmailEventOpened
+	case "Click":
+		eventType = entity.EmailEventClicked
+	default:
+		return nil, nil
+	}
+	if event.Mail.MessageID == "" {
+		return nil, nil
+	}
+
+	occurredAt := envelope.Timestamp
+	if occurredAt.IsZero() {
+		occurredAt = p.now()
+	}
+	return []EmailCallbackEvent{{
+		ProviderMessageID: event.Mail.MessageID,
+		ExternalID:        envelope.MessageID,
+		EventType:         eventType,
+		Detail:            detail,
+		OccurredAt:        occurredAt,
+	}}, nil
+}
+
+// confirmSubscription подтверждает подписку SNS. Запрос уходит только на HTTPS-адрес SNS,
+// чтобы обратный вызов нельзя было использовать для запросов к произвольным адресам.
+func (p *SESEmailProvider) confirmSubscription(ctx context.Context, subscribeURL string) error {
+	u, err := url.Parse(subscribeURL)
+	if err != nil || u.Scheme != "https" || !strings.HasPrefix(u.Hostname(), "sns.") || !strings.HasSuffix(u.Hostname(), ".amazonaws.com") {
+		return fmt.Errorf("%w: invalid sns subscribe url %q", apperrors.ErrValidation, subscribeURL)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to build sns confirmation request: %w", err)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sns subscription confirmation failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("sns subscription confirmation failed: status %d", resp.StatusCode)
+	}
+	log.Printf("[EmailService] Подписка SNS на события SES подтверждена")
+	return nil
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+// Режимы шифрования SMTP
+const (
+	SMTPTLSStartTLS = "starttls" // STARTTLS после подключения (порт 587)
+	SMTPTLSImplicit = "implicit" // TLS с первого байта (порт 465)
+	SMTPTLSNone     = "none"     // без шифрования, только для локальной разработки
+)
+
+// smtpTimeout ограничивает отправку, если у контекста нет своего дедлайна
+const smtpTimeout = 30 * time.Second
+
+// SMTPConfig содержит параметры SMTP-сервера
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	TLSMode  string
+	From     string
+}
+
+// SMTPEmailProvider отправляет письма через SMTP-сервер
+type SMTPEmailProvider struct {
+	cfg  SMTPConfig
+	from *mail.Address
+}
+
+// NewSMTPEmailProvider создает SMTP-провайдера
+func NewSMTPEmailProvider(cfg SMTPConfig) (*SMTPEmailProvider, error) {
+	if cfg.Host == "" {
+		return nil, fmt.Errorf("smtp host is required")
+	}
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid email from %q: %w", cfg.From, err)
+	}
+	if cfg.TLSMode == "" {
+		cfg.TLSMode = SMTPTLSStartTLS
+	}
+	switch cfg.TLSMode {
+	case SMTPTLSStartTLS, SMTPTLSImplicit, SMTPTLSNone:
+	default:
+		return nil, fmt.Errorf("unknown smtp tls mode %q", cfg.TLSMode)
+	}
+	if cfg.Port == 0 {
+		cfg.Port = 587
+		if cfg.TLSMode == SMTPTLSImplicit {
+			cfg.Port = 465
+		}
+	}
+	return &SMTPEmailProvider{cfg: cfg, from: from}, nil
+}
+
+func (p *SMTPEmailProvider) Name() string { return entity.EmailProviderSMTP }
+
+// Send отправляет письмо. Идентификатор письма — сгенерированный Message-ID.
+func (p *SMTPEmailProvider) Send(ctx context.Context, msg OutgoingEmail) (string, error) {
+	messageID, body, err := p.buildMessage(msg)
+	if err != nil {
+		return "", err
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(smtpTimeout)
+	}
+	addr := net.JoinHostPort(p.cfg.Host, strconv.Itoa(p.cfg.Port))
+	dialer := &net.Dialer{Deadline: deadline}
+	var conn net.Conn
+	if p.cfg.TLSMode == SMTPTLSImplicit {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: p.cfg.Host}}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return "", retryableEmailError(fmt.Errorf("smtp connect failed: %w", err), 0)
+	}
+	_ = conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, p.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return "", classifySMTPError("smtp handshake failed", err)
+	}
+	defer client.Close()
+
+	if p.cfg.TLSMode == SMTPTLSStartTLS {
+		if err := client.StartTLS(&tls.Config{ServerName: p.cfg.Host}); err != nil {
+			return "", classifySMTPError("smtp starttls failed", err)
+		}
+	}
+	if p.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", p.cfg.Username, p.cfg.Password, p.cfg.Host)); err != nil {
+			return "", classifySMTPError("smtp auth failed", err)
+		}
+	}
+	if err := client.Mail(p.from.Address); err != nil {
+		return "", classifySMTPError("smtp MAIL FROM failed", err)
+	}
+	if err := client.Rcpt(msg.To); err != nil {
+		return "", classifySMTPError("smtp RCPT TO failed", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return "", classifySMTPError("smtp DATA failed", err)
+	}
+	if _, err := w.Write(body); err != nil {
+		return "", classifySMTPError("smtp write failed", err)
+	}
+	if err := w.Close(); err != nil {
+		return "", classifySMTPError("smtp send failed", err)
+	}
+	_ = client.Quit()
+	return messageID, nil
+}
+
+// buildMessage собирает письмо в формате MIME: text/plain или multipart/alternative с HTML
+func (p *SMTPEmailProvider) buildMessage(msg OutgoingEmail) (string, []byte, error) {
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid recipient %q: %w", msg.To, err)
+	}
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return "", nil, fmt.Errorf("failed to generate message id: %w", err)
+	}
+	domain := p.from.Address[strings.LastIndex(p.from.Address, "@")+1:]
+	messageID := hex.EncodeToString(token) + "@" + domain
+
+	var buf bytes.Buffer
+	header := textproto.MIMEHeader{}
+	header.Set("From", p.from.String())
+	header.Set("To", to.String())
+	header.Set("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header.Set("Date", time.Now().Format(time.RFC1123Z))
+	header.Set("Message-ID", "<"+messageID+">")
+	header.Set("MIME-Version", "1.0")
+
+	if msg.HTML == "" {
+		header.Set("Content-Type", "text/plain; charset=utf-8")
+		header.Set("Content-Transfer-Encoding", "quoted-printable")
+		writeMIMEHeader(&buf, header)
+		if err := writeQuotedPrintable(&buf, msg.Text); err != nil {
+			return "", nil, err
+		}
+		return messageID, buf.Bytes(), nil
+	}
+
+	var parts bytes.Buffer
+	mw := multipart.NewWriter(&parts)
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", msg.Text},
+		{"text/html; charset=utf-8", msg.HTML},
+	} {
+		if part.content == "" {
+			continue
+		}
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to build email part: %w", err)
+		}
+		if err := writeQuotedPrintable(pw, part.content); err != nil {
+			return "", nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return "", nil, fmt.Errorf("failed to build email: %w", err)
+	}
+	header.Set("Content-Type", "multipart/alternative; boundary="+mw.Boundary())
+	writeMIMEHeader(&buf, header)
+	buf.Write(parts.Bytes())
+	return messageID, buf.Bytes(), nil
+}
+
+func writeMIMEHeader(buf *bytes.Buffer, header textproto.MIMEHeader) {
+	for _, key := range []string{"From", "To", "Subject", "Date", "Message-ID", "MIME-Version", "Content-Type", "Content-Transfer-Encoding"} {
+		if value := header.Get(key); value != "" {
+			fmt.Fprintf(buf, "%s: %s\r\n", key, value)
+		}
+	}
+	buf.WriteString("\r\n")
+}
+
+func writeQuotedPrintable(w io.Writer, content string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(content)); err != nil {
+		return fmt.Errorf("failed to encode email body: %w", err)
+	}
+	return qp.Close()
+}
+
+// classifySMTPError считает временными коды 4xx и сетевые сбои; коды 5xx — окончательный отказ
+func classifySMTPError(stage string, err error) error {
+	wrapped := fmt.Errorf("%s: %w", stage, err)
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) {
+		if protoErr.Code >= 400 && protoErr.Code < 500 {
+			return retryableEmailError(wrapped, 0)
+		}
+		return wrapped
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, io.EOF) {
+		return retryableEmailError(wrapped, 0)
+	}
+	return wrapped
+}
@@ -5,108 +5,316 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"net"
-	"strconv"
 	"strings"
 	"time"
 
-	"github.com/resend/resend-go/v2"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
 )
 
 // EmailService sends transactional emails.
 type EmailService interface {
-	SendVerificationCode(ctx context.Context, toEmail, code, idempotencyKey string) error
+	// SendTemplate renders the template (entity.EmailTemplate*) in the recipient's locale and sends it.
+	SendTemplate(ctx context.Context, toEmail, locale, templateKey string, data map[string]string, idempotencyKey string) error
+	SendNotification(ctx context.Context, toEmail, subject, text, idempotencyKey string) error
 }
 
 // NoopEmailService is used when email verification is disabled.
 type NoopEmailService struct{}
 
-func (s *NoopEmailService) SendVerificationCode(ctx context.Context, toEmail, code, idempotencyKey string) error {
-	log.Printf("[EmailService] noop send verification code to=%s", toEmail)
+func (s *NoopEmailService) SendTemplate(ctx context.Context, toEmail, locale, templateKey string, data map[string]string, idempotencyKey string) error {
+	log.Printf("[EmailService] noop send template=%s to=%s", templateKey, toEmail)
 	return nil
 }
 
-// ResendEmailService sends emails via Resend REST API.
-type ResendEmailService struct {
-	from   string
-	client *resend.Client
+func (s *NoopEmailService) SendNotification(ctx context.Context, toEmail, subject, text, idempotencyKey string) error {
+	log.Printf("[EmailService] noop send notification to=%s subject=%q", toEmail, subject)
+	return nil
+}
+
+// EmailDispatcher реализует EmailService поверх реестра провайдеров. Письмо отправляется через
+// первого провайдера цепочки с повторами по его политике; если провайдер недоступен (временные
+// ошибки исчерпаны), — через следующего. Окончательный отказ (неверный адрес, отклонённый запрос)
+// другим провайдерам не передаётся. Принятые письма записываются в журнал, недоставленные —
+// в email_dead_letters.
+type EmailDispatcher struct {
+	registry  *EmailProviderRegistry
+	templates *EmailTemplateService
+	chain     []string
+	repo      repository.EmailRepository
+	wait      func(ctx context.Context, d time.Duration) error
 }
 
-func NewResendEmailService(apiKey, from string) (*ResendEmailService, error) {
-	if apiKey == "" {
-		return nil, fmt.Errorf("resend api key is required")
+// NewEmailDispatcher создает отправщика. providers — основной провайдер и резервные по порядку.
+func NewEmailDispatcher(registry *EmailProviderRegistry, templates *EmailTemplateService, repo repository.EmailRepository, providers []string) (*EmailDispatcher, error) {
+	if registry == nil || templates == nil || repo == nil {
+		return nil, fmt.Errorf("email provider registry, templates and repository are required")
 	}
-	if from == "" {
-		return nil, fmt.Errorf("email from is required")
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("at least one email provider is required")
 	}
-	return &ResendEmailService{
-		from:   from,
-		client: resend.NewClient(apiKey),
+	for _, name := range providers {
+		if _, _, ok := registry.Get(name); !ok {
+			return nil, fmt.Errorf("email provider %q is not registered", name)
+		}
+	}
+	return &EmailDispatcher{
+		registry:  registry,
+		templates: templates,
+		chain:     providers,
+		repo:      repo,
+		wait:      waitContext,
 	}, nil
 }
 
-func (s *ResendEmailService) SendVerificationCode(ctx context.Context, toEmail, code, idempotencyKey string) error {
-	if toEmail == "" || code == "" {
-		return fmt.Errorf("toEmail and code are required")
+// SendTemplate собирает письмо из шаблона на языке получателя и отправляет его
+func (d *EmailDispatcher) SendTemplate(ctx context.Context, toEmail, locale, templateKey string, data map[string]string, idempotencyKey string) error {
+	if toEmail == "" {
+		return fmt.Errorf("toEmail is required")
+	}
+	rendered, err := d.templates.Render(templateKey, locale, data)
+	if err != nil {
+		return err
 	}
+	kind := entity.EmailKindNotification
+	switch templateKey {
+	case entity.EmailTemplateVerificationCode:
+		kind = entity.EmailKindVerification
+	case entity.EmailTemplateMagicLink:
+		kind = entity.EmailKindMagicLink
+	}
+	return d.send(ctx, OutgoingEmail{
+		Kind:           kind,
+		To:             toEmail,
+		Subject:        rendered.Subject,
+		Text:           rendered.Text,
+		HTML:           rendered.HTML,
+		IdempotencyKey: idempotencyKey,
+	})
+}
 
-	params := &resend.SendEmailRequest{
-		From:    s.from,
-		To:      []string{toEmail},
-		Subject: "Verify your email",
-		Text:    fmt.Sprintf("Your verification code is %s. It expires in 15 minutes.", code),
-		Html:    fmt.Sprintf("<p>Your verification code is <strong>%s</strong>.</p><p>It expires in 15 minutes.</p>", code),
+func (d *EmailDispatcher) SendNotification(ctx context.Context, toEmail, subject, text, idempotencyKey string) error {
+	if toEmail == "" || subject == "" {
+		return fmt.Errorf("toEmail and subject are required")
 	}
+	return d.send(ctx, OutgoingEmail{
+		Kind:           entity.EmailKindNotification,
+		To:             toEmail,
+		Subject:        subject,
+		Text:           text,
+		IdempotencyKey: idempotencyKey,
+	})
+}
 
-	options := &resend.SendEmailOptions{}
-	if strings.TrimSpace(idempotencyKey) != "" {
-		options.IdempotencyKey = strings.TrimSpace(idempotencyKey)
+// send отправляет письмо и записывает его в недоставленные, если ни один провайдер его не принял
+func (d *EmailDispatcher) send(ctx context.Context, msg OutgoingEmail) error {
+	_, failure := d.deliver(ctx, msg)
+	if failure == nil {
+		return nil
 	}
 
-	var lastErr error
-	for attempt := 0; attempt < 3; attempt++ {
-		_, err := s.client.Emails.SendWithOptions(ctx, params, options)
-		if err == nil {
-			return nil
-		}
-		lastErr = err
-
-		if wait, ok := resendRetryDelay(err, attempt); ok {
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			case <-time.After(wait):
-				continue
+	letter := &entity.EmailDeadLetter{
+		Kind:           msg.Kind,
+		Recipient:      msg.To,
+		Subject:        msg.Subject,
+		IdempotencyKey: msg.IdempotencyKey,
+		Provider:       failure.provider,
+		Attempts:       failure.attempts,
+		LastError:      truncateEmailError(failure.err),
+	}
+	// Код подтверждения и ссылка для входа не сохраняются: через 15 минут они недействительны,
+	// а в БД хранится только их хеш
+	if msg.Kind != entity.EmailKindVerification && msg.Kind != entity.EmailKindMagicLink {
+		letter.TextBody = msg.Text
+		letter.HTMLBody = msg.HTML
+	}
+	if err := d.repo.CreateDeadLetter(letter); err != nil {
+		log.Printf("[EmailService] Не удалось записать недоставленное письмо to=%s: %v", msg.To, err)
+	}
+	return fmt.Errorf("email send failed: %w", failure.err)
+}
+
+// emailFailure — итог неудачной отправки
+type emailFailure struct {
+	provider string
+	attempts int
+	err      error
+}
+
+// deliver проходит по цепочке провайдеров и записывает принятое письмо в журнал
+func (d *EmailDispatcher) deliver(ctx context.Context, msg OutgoingEmail) (*entity.EmailMessage, *emailFailure) {
+	failure := &emailFailure{}
+	for _, name := range d.chain {
+		provider, policy, _ := d.registry.Get(name)
+		failure.provider = name
+
+		for attempt := 0; attempt < policy.MaxAttempts; attempt++ {
+			failure.attempts++
+			providerMessageID, err := provider.Send(ctx, msg)
+			if err == nil {
+				message := &entity.EmailMessage{
+					Provider:          name,
+					ProviderMessageID: providerMessageID,
+					Kind:              msg.Kind,
+					Recipient:         msg.To,
+					Subject:           msg.Subject,
+					IdempotencyKey:    msg.IdempotencyKey,
+					Status:            entity.EmailStatusSent,
+					Attempts:          failure.attempts,
+				}
+				// Письмо уже отправлено: ошибка журнала не должна приводить к повторной отправке
+				if providerMessageID != "" {
+					if err := d.repo.CreateMessage(message); err != nil {
+						log.Printf("[EmailService] Не удалось записать письмо %s/%s в журнал: %v", name, providerMessageID, err)
+					}
+				}
+				return message, nil
+			}
+			failure.err = err
+
+			var sendErr *EmailSendError
+			if !errors.As(err, &sendErr) || !sendErr.Retryable {
+				log.Printf("[EmailService] %s отклонил письмо to=%s: %v", name, msg.To, err)
+				return nil, failure
+			}
+			if attempt+1 == policy.MaxAttempts {
+				break
+			}
+			if waitErr := d.wait(ctx, policy.backoff(attempt, sendErr.RetryAfter)); waitErr != nil {
+				failure.err = waitErr
+				return nil, failure
 			}
 		}
+		log.Printf("[EmailService] %s недоступен после %d попыток: %v", name, policy.MaxAttempts, failure.err)
+	}
+	return nil, failure
+}
 
-		return fmt.Errorf("resend send failed: %w", err)
+// HandleCallback принимает обратный вызов провайдера о доставке и возвращает число записанных событий
+func (d *EmailDispatcher) HandleCallback(ctx context.Context, providerName string, req EmailCallbackRequest) (int, error) {
+	provider, _, ok := d.registry.Get(providerName)
+	if !ok {
+		return 0, apperrors.ErrNotFound
+	}
+	parser, ok := provider.(EmailCallbackParser)
+	if !ok {
+		return 0, apperrors.ErrNotFound
+	}
+	events, err := parser.ParseCallback(ctx, req)
+	if err != nil {
+		return 0, err
 	}
 
-	return fmt.Errorf("resend send failed after retries: %w", lastErr)
+	recorded := 0
+	for _, event := range events {
+		status, from, _ := entity.EmailStatusAfterEvent(event.EventType)
+		ok, err := d.repo.RecordEvent(&entity.EmailDeliveryEvent{
+			Provider:          providerName,
+			ProviderMessageID: event.ProviderMessageID,
+			ExternalID:        event.ExternalID,
+			EventType:         event.EventType,
+			Detail:            truncateRunes(event.Detail, 500),
+			OccurredAt:        event.OccurredAt,
+		}, status, from)
+		if err != nil {
+			return recorded, err
+		}
+		if ok {
+			recorded++
+		}
+	}
+	return recorded, nil
 }
 
-func resendRetryDelay(err error, attempt int) (time.Duration, bool) {
-	var rateLimitErr *resend.RateLimitError
-	if errors.As(err, &rateLimitErr) {
-		if seconds, convErr := strconv.Atoi(strings.TrimSpace(rateLimitErr.RetryAfter)); convErr == nil && seconds > 0 {
-			if seconds > 30 {
-				seconds = 30
-			}
-			return time.Duration(seconds) * time.Second, true
+// ListMessages возвращает журнал отправленных писем
+func (d *EmailDispatcher) ListMessages(recipient, status string, page, pageSize int) ([]entity.EmailMessage, int64, error) {
+	page, pageSize = normalizeWalletPage(page, pageSize)
+	return d.repo.ListMessages(strings.TrimSpace(recipient), status, pageSize, (page-1)*pageSize)
+}
+
+// ListMessageEvents возвращает события доставки письма
+func (d *EmailDispatcher) ListMessageEvents(messageID uint) ([]entity.EmailDeliveryEvent, error) {
+	return d.repo.ListEvents(messageID)
+}
+
+// ListDeadLetters возвращает недоставленные письма
+func (d *EmailDispatcher) ListDeadLetters(pendingOnly bool, page, pageSize int) ([]entity.EmailDeadLetter, int64, error) {
+	page, pageSize = normalizeWalletPage(page, pageSize)
+	return d.repo.ListDeadLetters(pendingOnly, pageSize, (page-1)*pageSize)
+}
+
+// RetryDeadLetter переотправляет недоставленное письмо. При неудаче письмо остаётся в очереди
+// с новой ошибкой и числом попыток.
+func (d *EmailDispatcher) RetryDeadLetter(ctx context.Context, id uint) (*entity.EmailDeadLetter, error) {
+	letter, err := d.repo.GetDeadLetter(id)
+	if err != nil {
+		return nil, err
+	}
+	if !letter.Retryable() {
+		return nil, fmt.Errorf("%w: email was already resent or its content is not stored", apperrors.ErrConflict)
+	}
+	now := time.Now()
+	claimed, err := d.repo.ClaimDeadLetterRetry(id, now)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, fmt.Errorf("%w: email is already being resent", apperrors.ErrConflict)
+	}
+
+	message, failure := d.deliver(ctx, OutgoingEmail{
+		Kind:           letter.Kind,
+		To:             letter.Recipient,
+		Subject:        letter.Subject,
+		Text:           letter.TextBody,
+		HTML:           letter.HTMLBody,
+		IdempotencyKey: letter.IdempotencyKey,
+	})
+	if failure != nil {
+		letter.RetriedAt = nil
+		letter.Provider = failure.provider
+		letter.Attempts += failure.attempts
+		letter.LastError = truncateEmailError(failure.err)
+	} else {
+		letter.RetriedAt = &now
+		if message.ID != 0 {
+			letter.RetryMessageID = &message.ID
 		}
-		return time.Duration(attempt+1) * time.Second, true
 	}
+	if err := d.repo.UpdateDeadLetter(letter); err != nil {
+		return nil, err
+	}
+	if failure != nil {
+		return letter, fmt.Errorf("email send failed: %w", failure.err)
+	}
+	return letter, nil
+}
 
-	var netErr net.Error
-	if errors.As(err, &netErr) && (netErr.Timeout() || netErr.Temporary()) {
-		return time.Duration(attempt+1) * 500 * time.Millisecond, true
+func truncateEmailError(err error) string {
+	if err == nil {
+		return ""
 	}
+	return truncateRunes(err.Error(), 500)
+}
 
-	msg := strings.ToLower(err.Error())
-	if strings.Contains(msg, "timeout") || strings.Contains(msg, "temporar") {
-		return time.Duration(attempt+1) * 500 * time.Millisecond, true
+// truncateRunes обрезает строку до max символов, не разрывая UTF-8
+func truncateRunes(value string, max int) string {
+	runes := []rune(value)
+	if len(runes) <= max {
+		return value
 	}
+	return string(runes[:max])
+}
 
-	return 0, false
+// waitContext ждёт d или отмены контекста
+func waitContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
 }
@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/pkg/i18n"
+)
+
+// fakeEmailRepo — EmailRepository в памяти
+type fakeEmailRepo struct {
+	messages    []entity.EmailMessage
+	events      []entity.EmailDeliveryEvent
+	deadLetters []entity.EmailDeadLetter
+}
+
+func (f *fakeEmailRepo) CreateMessage(message *entity.EmailMessage) error {
+	message.ID = uint(len(f.messages) + 1)
+	f.messages = append(f.messages, *message)
+	return nil
+}
+
+func (f *fakeEmailRepo) ListMessages(recipient, status string, limit, offset int) ([]entity.EmailMessage, int64, error) {
+	return f.messages, int64(len(f.messages)), nil
+}
+
+func (f *fakeEmailRepo) ListEvents(messageID uint) ([]entity.EmailDeliveryEvent, error) {
+	return f.events, nil
+}
+
+func (f *fakeEmailRepo) RecordEvent(event *entity.EmailDeliveryEvent, status string, fromStatuses []string) (bool, error) {
+	for _, existing := range f.events {
+		if event.ExternalID != "" && existing.Provider == event.Provider && existing.ExternalID == event.ExternalID {
+			return false, nil
+		}
+	}
+	for i := range f.messages {
+		m := &f.messages[i]
+		if m.Provider != event.Provider || m.ProviderMessageID != event.ProviderMessageID {
+			continue
+		}
+		event.MessageID = &m.ID
+		for _, from := range fromStatuses {
+			if status != "" && m.Status == from {
+				m.Status = status
+				break
+			}
+		}
+	}
+	f.events = append(f.events, *event)
+	return true, nil
+}
+
+func (f *fakeEmailRepo) CreateDeadLetter(letter *entity.EmailDeadLetter) error {
+	letter.ID = uint(len(f.deadLetters) + 1)
+	f.deadLetters = append(f.deadLetters, *letter)
+	return nil
+}
+
+func (f *fakeEmailRepo) GetDeadLetter(id uint) (*entity.EmailDeadLetter, error) {
+	if id == 0 || int(id) > len(f.deadLetters) {
+		return nil, apperrors.ErrNotFound
+	}
+	letter := f.deadLetters[id-1]
+	return &letter, nil
+}
+
+func (f *fakeEmailRepo) ListDeadLetters(pendingOnly bool, limit, offset int) ([]entity.EmailDeadLetter, int64, error) {
+	return f.deadLetters, int64(len(f.deadLetters)), nil
+}
+
+func (f *fakeEmailRepo) ClaimDeadLetterRetry(id uint, now time.Time) (bool, error) {
+	letter := &f.deadLetters[id-1]
+	if letter.RetriedAt != nil {
+		return false, nil
+	}
+	letter.RetriedAt = &now
+	return true, nil
+}
+
+func (f *fakeEmailRepo) UpdateDeadLetter(letter *entity.EmailDeadLetter) error {
+	f.deadLetters[letter.ID-1] = *letter
+	return nil
+}
+
+// scriptedEmailProvider возвращает ошибки из errs по очереди, затем успех
+type scriptedEmailProvider struct {
+	name  string
+	errs  []error
+	calls int
+}
+
+func (p *scriptedEmailProvider) Name() string { return p.name }
+
+func (p *scriptedEmailProvider) Send(ctx context.Context, msg OutgoingEmail) (string, error) {
+	p.calls++
+	if p.calls <= len(p.errs) {
+		return "", p.errs[p.calls-1]
+	}
+	return p.name + "-msg-" + strconv.Itoa(p.calls), nil
+}
+
+func newTestEmailDispatcher(t *testing.T, repo *fakeEmailRepo, providers ...EmailProvider) (*EmailDispatcher, *[]time.Duration) {
+	t.Helper()
+	registry := NewEmailProviderRegistry()
+	var chain []string
+	for _, provider := range providers {
+		require.NoError(t, registry.Register(provider, EmailRetryPolicy{MaxAttempts: 3, InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}))
+		chain = append(chain, provider.Name())
+	}
+	templates, err := NewEmailTemplateService(nil, i18n.NewLocales("ru", []string{"kk", "en"}), "")
+	require.NoError(t, err)
+	dispatcher, err := NewEmailDispatcher(registry, templates, repo, chain)
+	require.NoError(t, err)
+	var waits []time.Duration
+	dispatcher.wait = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	return dispatcher, &waits
+}
+
+func TestEmailDispatcher_RetriesWithBackoff(t *testing.T) {
+	repo := &fakeEmailRepo{}
+	primary := &scriptedEmailProvider{name: "resend", errs: []error{
+		retryableEmailError(errors.New("timeout"), 0),
+		retryableEmailError(errors.New("rate limited"), 4*time.Second),
+	}}
+	dispatcher, waits := newTestEmailDispatcher(t, repo, primary)
+
+	require.NoError(t, dispatcher.SendNotification(context.Background(), "a@example.com", "Hi", "text", "key-1"))
+
+	assert.Equal(t, 3, primary.calls)
+	assert.Equal(t, []time.Duration{time.Second, 4 * time.Second}, *waits)
+	require.Len(t, repo.messages, 1)
+	assert.Equal(t, "resend-msg-3", repo.messages[0].ProviderMessageID)
+	assert.Equal(t, 3, repo.messages[0].Attempts)
+	assert.Empty(t, repo.deadLetters)
+}
+
+func TestEmailDispatcher_FallsBackWhenProviderUnavailable(t *testing.T) {
+	repo := &fakeEmailRepo{}
+	unavailable := retryableEmailError(errors.New("connection refused"), 0)
+	primary := &scriptedEmailProvider{name: "resend", errs: []error{unavailable, unavailable, unavailable}}
+	fallback := &scriptedEmailProvider{name: "smtp"}
+	dispatcher, _ := newTestEmailDispatcher(t, repo, primary, fallback)
+
+	require.NoError(t, dispatcher.SendNotification(context.Background(), "a@example.com", "Hi", "text", ""))
+
+	assert.Equal(t, 3, primary.calls)
+	assert.Equal(t, 1, fallback.calls)
+	require.Len(t, repo.messages, 1)
+	assert.Equal(t, "smtp", repo.messages[0].Provider)
+	assert.Equal(t, 4, repo.messages[0].Attempts)
+}
+
+func TestEmailDispatcher_PermanentErrorGoesToDeadLetters(t *testing.T) {
+	repo := &fakeEmailRepo{}
+	primary := &scriptedEmailProvider{name: "resend", errs: []error{errors.New("invalid recipient")}}
+	fallback := &scriptedEmailProvider{name: "smtp"}
+	dispatcher, waits := newTestEmailDispatcher(t, repo, primary, fallback)
+
+	err := dispatcher.SendTemplate(context.Background(), "a@example.com", "kk", entity.EmailTemplateVerificationCode,
+		map[string]string{"username": "player", "code": "123456", "expires_minutes": "15"}, "")
+	require.Error(t, err)
+
+	assert.Equal(t, 1, primary.calls)
+	assert.Zero(t, fallback.calls, "окончательный отказ не передаётся резервному провайдеру")
+	assert.Empty(t, *waits)
+	require.Len(t, repo.deadLetters, 1)
+	letter := repo.deadLetters[0]
+	assert.Equal(t, "resend", letter.Provider)
+	assert.Equal(t, "invalid recipient", letter.LastError)
+	assert.Empty(t, letter.TextBody, "код подтверждения не сохраняется")
+	assert.False(t, letter.Retryable())
+
+	_, err = dispatcher.RetryDeadLetter(context.Background(), letter.ID)
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+}
+
+func TestEmailDispatcher_RetryDeadLetter(t *testing.T) {
+	repo := &fakeEmailRepo{}
+	primary := &scriptedEmailProvider{name: "resend", errs: []error{errors.New("domain not verified")}}
+	dispatcher, _ := newTestEmailDispatcher(t, repo, primary)
+
+	require.Error(t, dispatcher.SendNotification(context.Background(), "a@example.com", "Hi", "text", "key-1"))
+	require.Len(t, repo.deadLetters, 1)
+	require.True(t, repo.deadLetters[0].Retryable())
+
+	letter, err := dispatcher.RetryDeadLetter(context.Background(), 1)
+	require.NoError(t, err)
+	require.NotNil(t, letter.RetriedAt)
+	require.NotNil(t, letter.RetryMessageID)
+	assert.Equal(t, repo.messages[0].ID, *letter.RetryMessageID)
+	assert.Equal(t, "key-1", repo.messages[0].IdempotencyKey)
+
+	_, err = dispatcher.RetryDeadLetter(context.Background(), 1)
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+}
+
+func TestEmailDispatcher_HandleCallback(t *testing.T) {
+	repo := &fakeEmailRepo{}
+	secret := []byte("webhook-secret")
+	provider, err := NewResendEmailProvider("re_test", "noreply@example.com", "whsec_"+base64.StdEncoding.EncodeToString(secret))
+	require.NoError(t, err)
+	now := time.Unix(1_800_000_000, 0)
+	provider.now = func() time.Time { return now }
+	dispatcher, _ := newTestEmailDispatcher(t, repo, provider)
+	require.NoError(t, repo.CreateMessage(&entity.EmailMessage{Provider: "resend", ProviderMessageID: "em_1", Status: entity.EmailStatusSent}))
+
+	body := []byte(`{"type":"email.delivered","created_at":"2027-01-15T08:00:00Z","data":{"email_id":"em_1"}}`)
+	request := func(signature string) EmailCallbackRequest {
+		header := http.Header{}
+		header.Set("svix-id", "msg_1")
+		header.Set("svix-timestamp", strconv.FormatInt(now.Unix(), 10))
+		header.Set("svix-signature", signature)
+		return EmailCallbackRequest{Header: header, Query: url.Values{}, Body: body}
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte("msg_1." + strconv.FormatInt(now.Unix(), 10) + "."))
+	mac.Write(body)
+	valid := "v1," + base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	_, err = dispatcher.HandleCallback(context.Background(), "resend", request("v1,Zm9yZ2Vk"))
+	assert.ErrorIs(t, err, apperrors.ErrUnauthorized)
+
+	recorded, err := dispatcher.HandleCallback(context.Background(), "resend", request(valid))
+	require.NoError(t, err)
+	assert.Equal(t, 1, recorded)
+	assert.Equal(t, entity.EmailStatusDelivered, repo.messages[0].Status)
+
+	recorded, err = dispatcher.HandleCallback(context.Background(), "resend", request(valid))
+	require.NoError(t, err)
+	assert.Zero(t, recorded, "повтор уведомления не записывается")
+
+	_, err = dispatcher.HandleCallback(context.Background(), "ses", request(valid))
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
+
+func TestSESEmailProvider_ParseCallback(t *testing.T) {
+	provider, err := NewSESEmailProvider(SESConfig{
+		Region: "eu-central-1", AccessKey: "AKID", SecretKey: "secret", CallbackToken: "tok", From: "noreply@example.com",
+	})
+	require.NoError(t, err)
+
+	body := []byte(`{"Type":"Notification","MessageId":"sns-1","Timestamp":"2027-01-15T08:00:00Z",` +
+		`"Message":"{\"eventType\":\"Bounce\",\"mail\":{\"messageId\":\"ses-1\"},\"bounce\":{\"bounceType\":\"Transient\",\"bounceSubType\":\"MailboxFull\"}}"}`)
+
+	_, err = provider.ParseCallback(context.Background(), EmailCallbackRequest{Query: url.Values{"token": {"wrong"}}, Body: body})
+	assert.ErrorIs(t, err, apperrors.ErrUnauthorized)
+
+	events, err := provider.ParseCallback(context.Background(), EmailCallbackRequest{Query: url.Values{"token": {"tok"}}, Body: body})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "ses-1", events[0].ProviderMessageID)
+	assert.Equal(t, "sns-1", events[0].ExternalID)
+	assert.Equal(t, entity.EmailEventDelayed, events[0].EventType, "временный отказ — задержка, а не bounce")
+	assert.Equal(t, "Transient MailboxFull", events[0].Detail)
+
+	confirm := []byte(`{"Type":"SubscriptionConfirmation","SubscribeURL":"https://attacker.example.com/confirm"}`)
+	_, err = provider.ParseCallback(context.Background(), EmailCallbackRequest{Query: url.Values{"token": {"tok"}}, Body: confirm})
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+}
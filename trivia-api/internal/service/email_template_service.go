@@ -0,0 +1,441 @@
+package service
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/pkg/i18n"
+)
+
+// builtinEmailTemplates — шаблоны писем, поставляемые с сервером. Файл <key>.<locale>.tmpl
+// содержит блоки {{define "subject"}}, {{define "text"}} и необязательный {{define "html"}}.
+//
+//go:embed email_templates/*.tmpl
+var builtinEmailTemplates embed.FS
+
+// emailTemplateSamples — переменные каждого шаблона с примерами значений. По ним шаблон
+// проверяется при сохранении (неизвестная переменная — ошибка) и строится предпросмотр.
+var emailTemplateSamples = map[string]map[string]string{
+	entity.EmailTemplateVerificationCode: {"username": "player", "code": "123456", "expires_minutes": "15"},
+	entity.EmailTemplatePasswordReset:    {"username": "player"},
+	entity.EmailTemplatePrizeWon:         {"username": "player", "quiz_id": "42", "quiz_title": "Вечерняя викторина", "prize": "5000"},
+	entity.EmailTemplatePrizeClaim: {
+		"username": "player", "quiz_id": "42", "quiz_title": "Вечерняя викторина", "amount": "5000",
+		"claim_token": "a1b2c3d4e5f6", "deadline": "2026-10-18T20:00:00Z",
+	},
+	entity.EmailTemplateSecurityAlert: {"username": "player", "reason": SecurityAlertSessionRevoked},
+	entity.EmailTemplateMagicLink:     {"username": "player", "link": "https://example.com/auth/magic-link?token=abc", "expires_minutes": "15"},
+}
+
+// EmailTemplateInput — содержимое новой версии шаблона
+type EmailTemplateInput struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// RenderedEmail — письмо, собранное из шаблона
+type RenderedEmail struct {
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html"`
+	Locale  string `json:"locale"`
+	Version int    `json:"version"` // 0 — шаблон из файлов
+}
+
+// EmailTemplateInfo — шаблон для языка и его текущий источник
+type EmailTemplateInfo struct {
+	Key           string   `json:"key"`
+	Locale        string   `json:"locale"`
+	Variables     []string `json:"variables"`
+	HasFile       bool     `json:"has_file"`
+	ActiveVersion int      `json:"active_version,omitempty"` // 0 — используется файл или запасной язык
+}
+
+type compiledEmailTemplate struct {
+	subject *texttemplate.Template
+	text    *texttemplate.Template
+	html    *htmltemplate.Template // nil — письмо без HTML-части
+}
+
+// EmailTemplateService выбирает и собирает шаблоны писем. Для каждого языка из цепочки
+// пользователя (язык, язык без региона, язык по умолчанию) активная версия из БД заменяет файл.
+type EmailTemplateService struct {
+	repo    repository.EmailTemplateRepository // nil — только файлы
+	locales *i18n.Locales
+	files   map[string]*compiledEmailTemplate // ключ — key + "." + locale
+
+	mu       sync.RWMutex
+	compiled map[uint]*compiledEmailTemplate // версии из БД неизменяемы, кешируются по ID
+}
+
+// NewEmailTemplateService загружает встроенные шаблоны и файлы из dir (если задан), которые их
+// заменяют. Для каждого шаблона обязателен файл на языке по умолчанию.
+func NewEmailTemplateService(repo repository.EmailTemplateRepository, locales *i18n.Locales, dir string) (*EmailTemplateService, error) {
+	if locales == nil {
+		return nil, fmt.Errorf("locales are required")
+	}
+	s := &EmailTemplateService{
+		repo:     repo,
+		locales:  locales,
+		files:    make(map[string]*compiledEmailTemplate),
+		compiled: make(map[uint]*compiledEmailTemplate),
+	}
+	builtin, err := fs.Sub(builtinEmailTemplates, "email_templates")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.loadFiles(builtin); err != nil {
+		return nil, fmt.Errorf("builtin email templates: %w", err)
+	}
+	if dir != "" {
+		if err := s.loadFiles(os.DirFS(dir)); err != nil {
+			return nil, fmt.Errorf("email templates in %s: %w", dir, err)
+		}
+	}
+	for key := range emailTemplateSamples {
+		if _, ok := s.files[key+"."+locales.Default()]; !ok {
+			return nil, fmt.Errorf("email template %s has no file for default locale %s", key, locales.Default())
+		}
+	}
+	return s, nil
+}
+
+// loadFiles компилирует файлы <key>.<locale>.tmpl и проверяет их на примерах значений
+func (s *EmailTemplateService) loadFiles(fsys fs.FS) error {
+	names, err := fs.Glob(fsys, "*.tmpl")
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		key, locale, ok := strings.Cut(strings.TrimSuffix(path.Base(name), ".tmpl"), ".")
+		if !ok {
+			return fmt.Errorf("%s: expected <key>.<locale>.tmpl", name)
+		}
+		if _, known := emailTemplateSamples[key]; !known {
+			return fmt.Errorf("%s: unknown template %q", name, key)
+		}
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		tpl, err := compileEmailTemplateFile(name, string(content))
+		if err != nil {
+			return err
+		}
+		if _, err := tpl.render(emailTemplateSamples[key]); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		s.files[key+"."+i18n.Normalize(locale)] = tpl
+	}
+	return nil
+}
+
+// Render собирает письмо на языке пользователя
+func (s *EmailTemplateService) Render(key, locale string, data map[string]string) (*RenderedEmail, error) {
+	if _, ok := emailTemplateSamples[key]; !ok {
+		return nil, fmt.Errorf("unknown email template %q", key)
+	}
+	for _, candidate := range s.locales.Negotiate("", locale, "").Chain() {
+		tpl, version, err := s.lookup(key, candidate)
+		if err != nil {
+			return nil, err
+		}
+		if tpl == nil {
+			continue
+		}
+		rendered, err := tpl.render(data)
+		if err != nil {
+			return nil, fmt.Errorf("email template %s/%s: %w", key, candidate, err)
+		}
+		rendered.Locale = candidate
+		rendered.Version = version
+		return rendered, nil
+	}
+	return nil, fmt.Errorf("email template %s has no version for locale %s", key, locale)
+}
+
+// lookup возвращает шаблон языка: активную версию из БД или файл. Ошибка БД не останавливает
+// отправку — используется файл.
+func (s *EmailTemplateService) lookup(key, locale string) (*compiledEmailTemplate, int, error) {
+	if s.repo != nil {
+		stored, err := s.repo.GetActive(key, locale)
+		switch {
+		case err == nil:
+			tpl, err := s.compiledVersion(stored)
+			if err != nil {
+				return nil, 0, err
+			}
+			return tpl, stored.Version, nil
+		case !errors.Is(err, apperrors.ErrNotFound):
+			log.Printf("[EmailTemplateService] Не удалось получить шаблон %s/%s из БД, используется файл: %v", key, locale, err)
+		}
+	}
+	return s.files[key+"."+locale], 0, nil
+}
+
+func (s *EmailTemplateService) compiledVersion(stored *entity.EmailTemplate) (*compiledEmailTemplate, error) {
+	s.mu.RLock()
+	tpl, ok := s.compiled[stored.ID]
+	s.mu.RUnlock()
+	if ok {
+		return tpl, nil
+	}
+	tpl, err := compileEmailTemplate(EmailTemplateInput{Subject: stored.Subject, Text: stored.TextBody, HTML: stored.HTMLBody})
+	if err != nil {
+		return nil, fmt.Errorf("email template %s/%s v%d: %w", stored.Key, stored.Locale, stored.Version, err)
+	}
+	s.mu.Lock()
+	s.compiled[stored.ID] = tpl
+	s.mu.Unlock()
+	return tpl, nil
+}
+
+// ListTemplates возвращает шаблоны по поддерживаемым языкам и их текущие версии
+func (s *EmailTemplateService) ListTemplates() ([]EmailTemplateInfo, error) {
+	active := map[string]int{}
+	if s.repo != nil {
+		stored, err := s.repo.ListActive()
+		if err != nil {
+			return nil, err
+		}
+		for _, tpl := range stored {
+			active[tpl.Key+"."+tpl.Locale] = tpl.Version
+		}
+	}
+
+	keys := make([]string, 0, len(emailTemplateSamples))
+	for key := range emailTemplateSamples {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var infos []EmailTemplateInfo
+	for _, key := range keys {
+		for _, locale := range s.locales.Supported() {
+			_, hasFile := s.files[key+"."+locale]
+			infos = append(infos, EmailTemplateInfo{
+				Key:           key,
+				Locale:        locale,
+				Variables:     emailTemplateVariables(key),
+				HasFile:       hasFile,
+				ActiveVersion: active[key+"."+locale],
+			})
+		}
+	}
+	return infos, nil
+}
+
+// ListVersions возвращает сохранённые версии шаблона
+func (s *EmailTemplateService) ListVersions(key, locale string) ([]entity.EmailTemplate, error) {
+	locale, err := s.validateTarget(key, locale)
+	if err != nil {
+		return nil, err
+	}
+	if s.repo == nil {
+		return []entity.EmailTemplate{}, nil
+	}
+	return s.repo.ListVersions(key, locale)
+}
+
+// SaveVersion проверяет шаблон и сохраняет его новой активной версией
+func (s *EmailTemplateService) SaveVersion(key, locale string, input EmailTemplateInput, adminID uint) (*entity.EmailTemplate, error) {
+	locale, err := s.validateTarget(key, locale)
+	if err != nil {
+		return nil, err
+	}
+	if s.repo == nil {
+		return nil, fmt.Errorf("email template storage is not configured")
+	}
+	input.Subject = strings.TrimSpace(input.Subject)
+	if input.Subject == "" || strings.TrimSpace(input.Text) == "" {
+		return nil, fmt.Errorf("%w: subject and text are required", apperrors.ErrValidation)
+	}
+	if len([]rune(input.Subject)) > 255 {
+		return nil, fmt.Errorf("%w: subject must be at most 255 characters", apperrors.ErrValidation)
+	}
+	tpl, err := compileEmailTemplate(input)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", apperrors.ErrValidation, err)
+	}
+	if _, err := tpl.render(emailTemplateSamples[key]); err != nil {
+		return nil, fmt.Errorf("%w: %v", apperrors.ErrValidation, err)
+	}
+
+	stored := &entity.EmailTemplate{
+		Key:      key,
+		Locale:   locale,
+		Subject:  input.Subject,
+		TextBody: input.Text,
+		HTMLBody: input.HTML,
+	}
+	if adminID != 0 {
+		stored.CreatedBy = &adminID
+	}
+	if err := s.repo.CreateVersion(stored); err != nil {
+		return nil, err
+	}
+	return stored, nil
+}
+
+// ActivateVersion возвращает шаблону сохранённую ранее версию
+func (s *EmailTemplateService) ActivateVersion(key, locale string, version int) error {
+	locale, err := s.validateTarget(key, locale)
+	if err != nil {
+		return err
+	}
+	if s.repo == nil {
+		return apperrors.ErrNotFound
+	}
+	return s.repo.Activate(key, locale, version)
+}
+
+// ResetToFile снимает активную версию: шаблон снова берётся из файлов
+func (s *EmailTemplateService) ResetToFile(key, locale string) error {
+	locale, err := s.validateTarget(key, locale)
+	if err != nil {
+		return err
+	}
+	if s.repo == nil {
+		return nil
+	}
+	return s.repo.DeactivateAll(key, locale)
+}
+
+// Preview собирает письмо из черновика (draft), сохранённой версии (version > 0) или текущего
+// шаблона языка. data дополняет и заменяет примеры значений.
+func (s *EmailTemplateService) Preview(key, locale string, version int, draft *EmailTemplateInput, data map[string]string) (*RenderedEmail, error) {
+	locale, err := s.validateTarget(key, locale)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]string, len(emailTemplateSamples[key])+len(data))
+	for k, v := range emailTemplateSamples[key] {
+		values[k] = v
+	}
+	for k, v := range data {
+		values[k] = v
+	}
+
+	var tpl *compiledEmailTemplate
+	switch {
+	case draft != nil:
+		if tpl, err = compileEmailTemplate(*draft); err != nil {
+			return nil, fmt.Errorf("%w: %v", apperrors.ErrValidation, err)
+		}
+	case version > 0:
+		if s.repo == nil {
+			return nil, apperrors.ErrNotFound
+		}
+		stored, err := s.repo.GetVersion(key, locale, version)
+		if err != nil {
+			return nil, err
+		}
+		if tpl, err = s.compiledVersion(stored); err != nil {
+			return nil, err
+		}
+	default:
+		return s.Render(key, locale, values)
+	}
+
+	rendered, err := tpl.render(values)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", apperrors.ErrValidation, err)
+	}
+	rendered.Locale = locale
+	rendered.Version = version
+	return rendered, nil
+}
+
+// validateTarget проверяет имя шаблона и язык и возвращает нормализованный язык
+func (s *EmailTemplateService) validateTarget(key, locale string) (string, error) {
+	if _, ok := emailTemplateSamples[key]; !ok {
+		return "", fmt.Errorf("%w: email template %q", apperrors.ErrNotFound, key)
+	}
+	locale = i18n.Normalize(locale)
+	if !s.locales.IsSupported(locale) {
+		return "", fmt.Errorf("%w: locale %q is not supported", apperrors.ErrValidation, locale)
+	}
+	return locale, nil
+}
+
+func emailTemplateVariables(key string) []string {
+	variables := make([]string, 0, len(emailTemplateSamples[key]))
+	for name := range emailTemplateSamples[key] {
+		variables = append(variables, name)
+	}
+	sort.Strings(variables)
+	return variables
+}
+
+// compileEmailTemplateFile компилирует файл с блоками subject, text и html
+func compileEmailTemplateFile(name, content string) (*compiledEmailTemplate, error) {
+	text, err := texttemplate.New(name).Option("missingkey=error").Parse(content)
+	if err != nil {
+		return nil, err
+	}
+	tpl := &compiledEmailTemplate{subject: text.Lookup("subject"), text: text.Lookup("text")}
+	if tpl.subject == nil || tpl.text == nil {
+		return nil, fmt.Errorf("%s: subject and text blocks are required", name)
+	}
+	html, err := htmltemplate.New(name).Option("missingkey=error").Parse(content)
+	if err != nil {
+		return nil, err
+	}
+	tpl.html = html.Lookup("html")
+	return tpl, nil
+}
+
+// compileEmailTemplate компилирует версию шаблона из полей. HTML экранирует подставляемые значения.
+func compileEmailTemplate(input EmailTemplateInput) (*compiledEmailTemplate, error) {
+	subject, err := texttemplate.New("subject").Option("missingkey=error").Parse(input.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("subject: %w", err)
+	}
+	text, err := texttemplate.New("text").Option("missingkey=error").Parse(input.Text)
+	if err != nil {
+		return nil, fmt.Errorf("text: %w", err)
+	}
+	tpl := &compiledEmailTemplate{subject: subject, text: text}
+	if strings.TrimSpace(input.HTML) != "" {
+		if tpl.html, err = htmltemplate.New("html").Option("missingkey=error").Parse(input.HTML); err != nil {
+			return nil, fmt.Errorf("html: %w", err)
+		}
+	}
+	return tpl, nil
+}
+
+func (t *compiledEmailTemplate) render(data map[string]string) (*RenderedEmail, error) {
+	var subject, text, html bytes.Buffer
+	if err := t.subject.Execute(&subject, data); err != nil {
+		return nil, err
+	}
+	if err := t.text.Execute(&text, data); err != nil {
+		return nil, err
+	}
+	if t.html != nil {
+		if err := t.html.Execute(&html, data); err != nil {
+			return nil, err
+		}
+	}
+	return &RenderedEmail{
+		// Перевод строки в теме — подстановка заголовков письма
+		Subject: strings.Join(strings.Fields(subject.String()), " "),
+		Text:    strings.TrimSpace(text.String()),
+		HTML:    strings.TrimSpace(html.String()),
+	}, nil
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/pkg/i18n"
+)
+
+// fakeEmailTemplateRepo — EmailTemplateRepository в памяти
+type fakeEmailTemplateRepo struct {
+	templates []entity.EmailTemplate
+}
+
+func (f *fakeEmailTemplateRepo) GetActive(key, locale string) (*entity.EmailTemplate, error) {
+	for _, tpl := range f.templates {
+		if tpl.Key == key && tpl.Locale == locale && tpl.IsActive {
+			return &tpl, nil
+		}
+	}
+	return nil, apperrors.ErrNotFound
+}
+
+func (f *fakeEmailTemplateRepo) GetVersion(key, locale string, version int) (*entity.EmailTemplate, error) {
+	for _, tpl := range f.templates {
+		if tpl.Key == key && tpl.Locale == locale && tpl.Version == version {
+			return &tpl, nil
+		}
+	}
+	return nil, apperrors.ErrNotFound
+}
+
+func (f *fakeEmailTemplateRepo) ListActive() ([]entity.EmailTemplate, error) {
+	var active []entity.EmailTemplate
+	for _, tpl := range f.templates {
+		if tpl.IsActive {
+			active = append(active, tpl)
+		}
+	}
+	return active, nil
+}
+
+func (f *fakeEmailTemplateRepo) ListVersions(key, locale string) ([]entity.EmailTemplate, error) {
+	var versions []entity.EmailTemplate
+	for i := len(f.templates) - 1; i >= 0; i-- {
+		if f.templates[i].Key == key && f.templates[i].Locale == locale {
+			versions = append(versions, f.templates[i])
+		}
+	}
+	return versions, nil
+}
+
+func (f *fakeEmailTemplateRepo) CreateVersion(tpl *entity.EmailTemplate) error {
+	versions, _ := f.ListVersions(tpl.Key, tpl.Locale)
+	_ = f.DeactivateAll(tpl.Key, tpl.Locale)
+	tpl.ID = uint(len(f.templates) + 1)
+	tpl.Version = len(versions) + 1
+	tpl.IsActive = true
+	f.templates = append(f.templates, *tpl)
+	return nil
+}
+
+func (f *fakeEmailTemplateRepo) Activate(key, locale string, version int) error {
+	if _, err := f.GetVersion(key, locale, version); err != nil {
+		return err
+	}
+	for i := range f.templates {
+		tpl := &f.templates[i]
+		if tpl.Key == key && tpl.Locale == locale {
+			tpl.IsActive = tpl.Version == version
+		}
+	}
+	return nil
+}
+
+func (f *fakeEmailTemplateRepo) DeactivateAll(key, locale string) error {
+	for i := range f.templates {
+		if f.templates[i].Key == key && f.templates[i].Locale == locale {
+			f.templates[i].IsActive = false
+		}
+	}
+	return nil
+}
+
+func newTestEmailTemplateService(t *testing.T, repo *fakeEmailTemplateRepo, dir string) *EmailTemplateService {
+	t.Helper()
+	svc, err := NewEmailTemplateService(repo, i18n.NewLocales("ru", []string{"kk", "en", "de"}), dir)
+	require.NoError(t, err)
+	return svc
+}
+
+func TestEmailTemplates_RenderBuiltinWithLocaleFallback(t *testing.T) {
+	svc := newTestEmailTemplateService(t, &fakeEmailTemplateRepo{}, "")
+	data := map[string]string{"username": "player", "code": "654321", "expires_minutes": "10"}
+
+	kk, err := svc.Render(entity.EmailTemplateVerificationCode, "kk-KZ", data)
+	require.NoError(t, err)
+	assert.Equal(t, "kk", kk.Locale)
+	assert.Equal(t, "Email растау коды", kk.Subject)
+	assert.Contains(t, kk.Text, "654321")
+	assert.Contains(t, kk.HTML, "<strong>654321</strong>")
+
+	// Для de нет файла — используется язык по умолчанию
+	de, err := svc.Render(entity.EmailTemplateVerificationCode, "de", data)
+	require.NoError(t, err)
+	assert.Equal(t, "ru", de.Locale)
+
+	_, err = svc.Render(entity.EmailTemplateVerificationCode, "en", map[string]string{"code": "1"})
+	assert.Error(t, err, "отсутствующая переменная — ошибка, а не пустое место в письме")
+}
+
+func TestEmailTemplates_SavedVersionOverridesFileAndCanBeRolledBack(t *testing.T) {
+	repo := &fakeEmailTemplateRepo{}
+	svc := newTestEmailTemplateService(t, repo, "")
+	data := map[string]string{"username": "<b>player</b>", "quiz_title": "Quiz", "quiz_id": "1", "prize": "500"}
+
+	_, err := svc.SaveVersion(entity.EmailTemplatePrizeWon, "ru", EmailTemplateInput{
+		Subject: "Победа в «{{.quiz_title}}»",
+		Text:    "{{.username}}, ваш приз {{.prize}}",
+		HTML:    "<p>{{.username}}, ваш приз {{.prize}}</p>",
+	}, 1)
+	require.NoError(t, err)
+
+	rendered, err := svc.Render(entity.EmailTemplatePrizeWon, "ru", data)
+	require.NoError(t, err)
+	assert.Equal(t, 1, rendered.Version)
+	assert.Equal(t, "Победа в «Quiz»", rendered.Subject)
+	assert.Equal(t, "<p>&lt;b&gt;player&lt;/b&gt;, ваш приз 500</p>", rendered.HTML)
+
+	// Версия для ru не влияет на kk, у которого есть свой файл
+	kk, err := svc.Render(entity.EmailTemplatePrizeWon, "kk", data)
+	require.NoError(t, err)
+	assert.Zero(t, kk.Version)
+
+	require.NoError(t, svc.ResetToFile(entity.EmailTemplatePrizeWon, "ru"))
+	rendered, err = svc.Render(entity.EmailTemplatePrizeWon, "ru", data)
+	require.NoError(t, err)
+	assert.Zero(t, rendered.Version)
+
+	require.NoError(t, svc.ActivateVersion(entity.EmailTemplatePrizeWon, "ru", 1))
+	assert.ErrorIs(t, svc.ActivateVersion(entity.EmailTemplatePrizeWon, "ru", 7), apperrors.ErrNotFound)
+}
+
+func TestEmailTemplates_SaveVersionValidates(t *testing.T) {
+	svc := newTestEmailTemplateService(t, &fakeEmailTemplateRepo{}, "")
+
+	_, err := svc.SaveVersion(entity.EmailTemplatePrizeWon, "ru", EmailTemplateInput{Subject: "Hi {{.unknown}}", Text: "x"}, 1)
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+
+	_, err = svc.SaveVersion(entity.EmailTemplatePrizeWon, "ru", EmailTemplateInput{Subject: "Hi", Text: "{{if}}"}, 1)
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+
+	_, err = svc.SaveVersion(entity.EmailTemplatePrizeWon, "fr", EmailTemplateInput{Subject: "Hi", Text: "x"}, 1)
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+
+	_, err = svc.SaveVersion("newsletter", "ru", EmailTemplateInput{Subject: "Hi", Text: "x"}, 1)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
+
+func TestEmailTemplates_PreviewDraftWithSampleData(t *testing.T) {
+	svc := newTestEmailTemplateService(t, &fakeEmailTemplateRepo{}, "")
+
+	preview, err := svc.Preview(entity.EmailTemplatePrizeClaim, "en", 0, &EmailTemplateInput{
+		Subject: "Claim {{.amount}}\r\nBcc: someone@example.com",
+		Text:    "Code {{.claim_token}} for {{.username}}",
+	}, map[string]string{"username": "alice"})
+	require.NoError(t, err)
+	assert.Equal(t, "Claim 5000 Bcc: someone@example.com", preview.Subject, "перевод строки в теме не проходит в заголовок")
+	assert.Equal(t, "Code a1b2c3d4e5f6 for alice", preview.Text)
+	assert.Empty(t, preview.HTML)
+}
+
+func TestEmailTemplates_DirectoryOverridesBuiltin(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "security_alert.en.tmpl"),
+		[]byte(`{{define "subject"}}Heads up, {{.username}}{{end}}{{define "text"}}Sessions ended.{{end}}`), 0o600))
+	svc := newTestEmailTemplateService(t, &fakeEmailTemplateRepo{}, dir)
+
+	rendered, err := svc.Render(entity.EmailTemplateSecurityAlert, "en", map[string]string{"username": "bob", "reason": "x"})
+	require.NoError(t, err)
+	assert.Equal(t, "Heads up, bob", rendered.Subject)
+	assert.Empty(t, rendered.HTML)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "newsletter.en.tmpl"),
+		[]byte(`{{define "subject"}}x{{end}}{{define "text"}}x{{end}}`), 0o600))
+	_, err = NewEmailTemplateService(nil, i18n.NewLocales("ru", nil), dir)
+	assert.Error(t, err)
+}
@@ -8,6 +8,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math/big"
+	"strconv"
 	"strings"
 	"time"
 
@@ -115,7 +116,12 @@ func (s *EmailVerificationService) SendCode(ctx context.Context, userID uint) er
 	}
 
 	idempotencyKey := fmt.Sprintf("email-verify:%d:%d", user.ID, record.ID)
-	if err := s.emailService.SendVerificationCode(ctx, user.Email, code, idempotencyKey); err != nil {
+	data := map[string]string{
+		"username":        user.Username,
+		"code":            code,
+		"expires_minutes": strconv.Itoa(int(s.verificationTTL.Minutes())),
+	}
+	if err := s.emailService.SendTemplate(ctx, user.Email, user.Language, entity.EmailTemplateVerificationCode, data, idempotencyKey); err != nil {
 		return fmt.Errorf("failed to send verification email: %w", err)
 	}
 
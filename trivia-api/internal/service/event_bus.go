@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+)
+
+const (
+	eventBusRetryBase = 5 * time.Second
+	eventBusRetryMax  = 30 * time.Minute
+	// eventBusClaimLease — на сколько откладывается событие, забранное на обработку
+	eventBusClaimLease = 5 * time.Minute
+)
+
+// EventHandler обрабатывает доменное событие. Доставка «хотя бы один раз»: при ошибке
+// другого подписчика или падении инстанса событие может прийти повторно, поэтому
+// обработчик должен быть идемпотентным.
+type EventHandler func(ctx context.Context, event *entity.OutboxEvent) error
+
+type eventSubscription struct {
+	consumer string
+	types    map[string]struct{}
+	handler  EventHandler
+}
+
+// EventBusConfig содержит настройки обработки событий outbox
+type EventBusConfig struct {
+	BatchSize   int           // событий за один проход
+	MaxAttempts int           // попыток на событие, затем статус dead
+	Retention   time.Duration // срок хранения обработанных событий, 0 — бессрочно
+}
+
+// EventBus — шина доменных событий на основе outbox. Сервисы записывают события в своих
+// транзакциях (Emit), фоновый обработчик любого инстанса доставляет их подписчикам.
+type EventBus struct {
+	repo   repository.OutboxRepository
+	config EventBusConfig
+
+	mu   sync.RWMutex
+	subs []eventSubscription
+
+	kick chan struct{}
+}
+
+// NewEventBus создает шину событий
+func NewEventBus(repo repository.OutboxRepository, config EventBusConfig) *EventBus {
+	return &EventBus{repo: repo, config: config, kick: make(chan struct{}, 1)}
+}
+
+// Subscribe подписывает обработчик consumer на события eventTypes. Имя подписчика
+// хранится в событии после успешной обработки и не должно меняться между релизами.
+func (b *EventBus) Subscribe(consumer string, handler EventHandler, eventTypes ...string) {
+	types := make(map[string]struct{}, len(eventTypes))
+	for _, eventType := range eventTypes {
+		types[eventType] = struct{}{}
+	}
+	b.mu.Lock()
+	b.subs = append(b.subs, eventSubscription{consumer: consumer, types: types, handler: handler})
+	b.mu.Unlock()
+}
+
+// Emit записывает событие в outbox в транзакции tx (nil — отдельной записью).
+// Событие обработается только после фиксации транзакции; после неё стоит вызвать Notify.
+func (b *EventBus) Emit(tx *gorm.DB, eventType string, payload interface{}) error {
+	event, err := entity.NewOutboxEvent(eventType, payload)
+	if err != nil {
+		return err
+	}
+	return b.repo.Append(tx, event)
+}
+
+// Notify будит обработчик, не дожидаясь очередного интервала опроса
+func (b *EventBus) Notify() {
+	select {
+	case b.kick <- struct{}{}:
+	default:
+	}
+}
+
+// Start запускает обработку событий: каждые interval и после Notify.
+// Раз в час удаляет обработанные события старше Retention. Работает до отмены ctx.
+func (b *EventBus) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		cleanup := time.NewTicker(time.Hour)
+		defer cleanup.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-b.kick:
+			case <-cleanup.C:
+				b.cleanup()
+				continue
+			}
+			// Полная пачка — вероятно, есть ещё события: разбираем очередь до конца
+			for {
+				processed, err := b.Dispatch(ctx)
+				if err != nil {
+					log.Printf("[EventBus] Ошибка обработки событий: %v", err)
+					break
+				}
+				if processed < b.config.BatchSize || ctx.Err() != nil {
+					break
+				}
+			}
+		}
+	}()
+}
+
+// Dispatch обрабатывает события, срок которых наступил, и возвращает их число
+func (b *EventBus) Dispatch(ctx context.Context) (int, error) {
+	events, err := b.repo.ClaimDue(time.Now().UTC(), eventBusClaimLease, b.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	for i := range events {
+		b.process(ctx, &events[i])
+	}
+	return len(events), nil
+}
+
+// process вызывает подписчиков, ещё не обработавших событие, и сохраняет результат
+func (b *EventBus) process(ctx context.Context, event *entity.OutboxEvent) {
+	b.mu.RLock()
+	subs := b.subs
+	b.mu.RUnlock()
+
+	event.Attempts++
+	var failures []string
+	for _, sub := range subs {
+		if _, ok := sub.types[event.EventType]; !ok || event.DoneBy(sub.consumer) {
+			continue
+		}
+		if err := callEventHandler(ctx, sub.handler, event); err != nil {
+			log.Printf("[EventBus] Подписчик %s не обработал событие %s #%d: %v", sub.consumer, event.EventType, event.ID, err)
+			failures = append(failures, fmt.Sprintf("%s: %v", sub.consumer, err))
+			continue
+		}
+		event.DoneConsumers = append(event.DoneConsumers, sub.consumer)
+	}
+
+	switch {
+	case len(failures) == 0:
+		now := time.Now().UTC()
+		event.Status = entity.OutboxStatusProcessed
+		event.ProcessedAt = &now
+		event.LastError = ""
+	case event.Attempts >= b.config.MaxAttempts:
+		log.Printf("[EventBus] Событие %s #%d не обработано после %d попыток", event.EventType, event.ID, event.Attempts)
+		event.Status = entity.OutboxStatusDead
+		event.LastError = truncateError(failures)
+	default:
+		event.NextAttemptAt = time.Now().UTC().Add(eventRetryDelay(event.Attempts))
+		event.LastError = truncateError(failures)
+	}
+	if err := b.repo.Update(event); err != nil {
+		log.Printf("[EventBus] Ошибка сохранения события #%d: %v", event.ID, err)
+	}
+}
+
+func (b *EventBus) cleanup() {
+	if b.config.Retention <= 0 {
+		return
+	}
+	deleted, err := b.repo.DeleteProcessedBefore(time.Now().UTC().Add(-b.config.Retention))
+	if err != nil {
+		log.Printf("[EventBus] Ошибка очистки обработанных событий: %v", err)
+		return
+	}
+	if deleted > 0 {
+		log.Printf("[EventBus] Удалено %d обработанных событий", deleted)
+	}
+}
+
+// callEventHandler вызывает обработчик, превращая панику в ошибку: паника одного
+// подписчика не должна останавливать обработку событий
+func callEventHandler(ctx context.Context, handler EventHandler, event *entity.OutboxEvent) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return handler(ctx, event)
+}
+
+// eventRetryDelay — задержка перед повтором: 5с, 10с, 20с, ... не больше 30 минут
+func eventRetryDelay(attempts int) time.Duration {
+	delay := eventBusRetryBase
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= eventBusRetryMax {
+			return eventBusRetryMax
+		}
+	}
+	return delay
+}
+
+func truncateError(failures []string) string {
+	message := fmt.Sprint(failures)
+	if len(message) > 500 {
+		message = message[:500]
+	}
+	return message
+}
+
+// createUser создаёт пользователя. С подключённой шиной событие user.registered
+// записывается в той же транзакции.
+func createUser(repo repository.UserRepository, bus *EventBus, user *entity.User, source string) error {
+	if bus == nil {
+		return repo.Create(user)
+	}
+	err := repo.CreateWithEvent(user, func(created *entity.User) (entity.OutboxEvent, error) {
+		return entity.NewOutboxEvent(entity.EventUserRegistered, entity.UserRegisteredPayload{
+			UserID:   created.ID,
+			Username: created.Username,
+			Source:   source,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	bus.Notify()
+	return nil
+}
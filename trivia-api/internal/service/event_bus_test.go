@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+// fakeOutboxRepo — OutboxRepository в памяти: ClaimDue отдаёт все события в статусе pending
+type fakeOutboxRepo struct {
+	events []entity.OutboxEvent
+}
+
+func (f *fakeOutboxRepo) Append(tx *gorm.DB, events ...entity.OutboxEvent) error {
+	for _, event := range events {
+		event.ID = uint(len(f.events) + 1)
+		f.events = append(f.events, event)
+	}
+	return nil
+}
+
+func (f *fakeOutboxRepo) ClaimDue(now time.Time, lease time.Duration, limit int) ([]entity.OutboxEvent, error) {
+	var due []entity.OutboxEvent
+	for _, event := range f.events {
+		if event.Status == entity.OutboxStatusPending && len(due) < limit {
+			due = append(due, event)
+		}
+	}
+	return due, nil
+}
+
+func (f *fakeOutboxRepo) Update(event *entity.OutboxEvent) error {
+	f.events[event.ID-1] = *event
+	return nil
+}
+
+func (f *fakeOutboxRepo) DeleteProcessedBefore(before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestEventBus_RetriesOnlyFailedConsumers(t *testing.T) {
+	repo := &fakeOutboxRepo{}
+	bus := NewEventBus(repo, EventBusConfig{BatchSize: 10, MaxAttempts: 3})
+
+	var okCalls, flakyCalls int
+	bus.Subscribe("ok", func(ctx context.Context, event *entity.OutboxEvent) error {
+		okCalls++
+		return nil
+	}, entity.EventQuizCompleted)
+	bus.Subscribe("flaky", func(ctx context.Context, event *entity.OutboxEvent) error {
+		flakyCalls++
+		if flakyCalls == 1 {
+			return errors.New("temporary failure")
+		}
+		return nil
+	}, entity.EventQuizCompleted)
+	bus.Subscribe("other", func(ctx context.Context, event *entity.OutboxEvent) error {
+		t.Fatal("consumer of another event type must not be called")
+		return nil
+	}, entity.EventUserRegistered)
+
+	require.NoError(t, bus.Emit(nil, entity.EventQuizCompleted, entity.QuizCompletedPayload{QuizID: 7, WinnerIDs: []uint{1, 2}}))
+
+	processed, err := bus.Dispatch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, processed)
+	event := repo.events[0]
+	assert.Equal(t, entity.OutboxStatusPending, event.Status)
+	assert.Equal(t, entity.StringArray{"ok"}, event.DoneConsumers)
+	assert.Contains(t, event.LastError, "temporary failure")
+	assert.True(t, event.NextAttemptAt.After(time.Now()))
+
+	_, err = bus.Dispatch(context.Background())
+	require.NoError(t, err)
+	event = repo.events[0]
+	assert.Equal(t, entity.OutboxStatusProcessed, event.Status)
+	assert.NotNil(t, event.ProcessedAt)
+	assert.Equal(t, 1, okCalls, "succeeded consumer must not be called again")
+	assert.Equal(t, 2, flakyCalls)
+
+	var payload entity.QuizCompletedPayload
+	require.NoError(t, event.Decode(&payload))
+	assert.Equal(t, []uint{1, 2}, payload.WinnerIDs)
+}
+
+func TestEventBus_DeadAfterMaxAttempts(t *testing.T) {
+	repo := &fakeOutboxRepo{}
+	bus := NewEventBus(repo, EventBusConfig{BatchSize: 10, MaxAttempts: 2})
+	bus.Subscribe("panicky", func(ctx context.Context, event *entity.OutboxEvent) error {
+		panic("boom")
+	}, entity.EventPrizeAwarded)
+
+	require.NoError(t, bus.Emit(nil, entity.EventPrizeAwarded, entity.PrizeAwardedPayload{QuizID: 1, UserID: 2, Amount: 100}))
+
+	for i := 0; i < 3; i++ {
+		_, err := bus.Dispatch(context.Background())
+		require.NoError(t, err)
+	}
+
+	event := repo.events[0]
+	assert.Equal(t, entity.OutboxStatusDead, event.Status)
+	assert.Equal(t, 2, event.Attempts)
+	assert.Contains(t, event.LastError, "panic: boom")
+}
+
+func TestEventRetryDelay(t *testing.T) {
+	assert.Equal(t, 5*time.Second, eventRetryDelay(1))
+	assert.Equal(t, 20*time.Second, eventRetryDelay(3))
+	assert.Equal(t, 30*time.Minute, eventRetryDelay(20))
+}
@@ -0,0 +1,425 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/pkg/export"
+	"github.com/yourusername/trivia-api/internal/pkg/storage"
+)
+
+const (
+	// exportJobLease — на сколько обработчик занимает задание; продлевается после каждой порции строк
+	exportJobLease = 5 * time.Minute
+	// exportMaxAttempts — попыток на задание, включая первую
+	exportMaxAttempts = 3
+	// exportJobsListLimit — сколько последних заданий викторины возвращает список
+	exportJobsListLimit = 50
+)
+
+// ExportConfig содержит настройки фоновых выгрузок
+type ExportConfig struct {
+	BatchSize      int           // строк за один запрос к БД
+	DownloadURLTTL time.Duration // срок действия ссылки на скачивание
+	Retention      time.Duration // сколько хранится готовый файл
+}
+
+// ExportJobView — задание выгрузки с прогрессом и ссылкой на скачивание готового файла
+type ExportJobView struct {
+	*entity.ExportJob
+	Progress             int        `json:"progress"` // 0–100
+	DownloadURL          string     `json:"download_url,omitempty"`
+	DownloadURLExpiresAt *time.Time `json:"download_url_expires_at,omitempty"`
+}
+
+// ExportService формирует выгрузки результатов и ответов викторин в фоне. CreateJob ставит задание
+// в очередь (таблица export_jobs), обработчик пишет файл во временный файл порциями по BatchSize
+// строк, обновляя прогресс, и сохраняет его в хранилище загрузок. Готовый файл скачивается по
+// временной ссылке и удаляется через Retention.
+type ExportService struct {
+	repo     repository.ExportJobRepository
+	quizRepo repository.QuizRepository
+	storage  storage.Storage
+	config   ExportConfig
+	kick     chan struct{}
+}
+
+// NewExportService создает сервис выгрузок
+func NewExportService(repo repository.ExportJobRepository, quizRepo repository.QuizRepository, store storage.Storage, cfg ExportConfig) *ExportService {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 1000
+	}
+	if cfg.DownloadURLTTL <= 0 {
+		cfg.DownloadURLTTL = time.Hour
+	}
+	if cfg.Retention <= 0 {
+		cfg.Retention = 7 * 24 * time.Hour
+	}
+	return &ExportService{
+		repo:     repo,
+		quizRepo: quizRepo,
+		storage:  store,
+		config:   cfg,
+		kick:     make(chan struct{}, 1),
+	}
+}
+
+// CreateJob ставит в очередь выгрузку kind (results, answers) викторины в формате format (csv, xlsx, json)
+func (s *ExportService) CreateJob(ctx context.Context, quizID, requestedBy uint, kind, format string) (*ExportJobView, error) {
+	switch kind {
+	case entity.ExportKindResults, entity.ExportKindAnswers:
+	default:
+		return nil, fmt.Errorf("%w: kind must be results or answers", apperrors.ErrValidation)
+	}
+	switch format {
+	case entity.ExportFormatCSV, entity.ExportFormatXLSX, entity.ExportFormatJSON:
+	default:
+		return nil, fmt.Errorf("%w: format must be csv, xlsx or json", apperrors.ErrValidation)
+	}
+	if _, err := s.quizRepo.GetByID(quizID); err != nil {
+		return nil, err
+	}
+
+	job := &entity.ExportJob{
+		QuizID:      quizID,
+		Kind:        kind,
+		Format:      format,
+		Status:      entity.ExportJobPending,
+		RequestedBy: requestedBy,
+	}
+	if err := s.repo.Create(job); err != nil {
+		return nil, err
+	}
+
+	select {
+	case s.kick <- struct{}{}:
+	default:
+	}
+	return s.view(ctx, job), nil
+}
+
+// GetJob возвращает задание выгрузки викторины; у готового — свежую ссылку на скачивание
+func (s *ExportService) GetJob(ctx context.Context, quizID, jobID uint) (*ExportJobView, error) {
+	job, err := s.repo.GetByID(jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.QuizID != quizID {
+		return nil, apperrors.ErrNotFound
+	}
+	return s.view(ctx, job), nil
+}
+
+// ListJobs возвращает последние задания выгрузки викторины
+func (s *ExportService) ListJobs(ctx context.Context, quizID uint) ([]*ExportJobView, error) {
+	jobs, err := s.repo.ListByQuiz(quizID, exportJobsListLimit)
+	if err != nil {
+		return nil, err
+	}
+	views := make([]*ExportJobView, len(jobs))
+	for i := range jobs {
+		views[i] = s.view(ctx, &jobs[i])
+	}
+	return views, nil
+}
+
+// view дополняет задание прогрессом и, для готового файла, подписанной ссылкой
+func (s *ExportService) view(ctx context.Context, job *entity.ExportJob) *ExportJobView {
+	view := &ExportJobView{ExportJob: job, Progress: job.Progress()}
+	if job.Status != entity.ExportJobCompleted || job.StorageKey == "" {
+		return view
+	}
+	ttl := s.config.DownloadURLTTL
+	if job.ExpiresAt != nil {
+		if untilExpiry := time.Until(*job.ExpiresAt); untilExpiry < ttl {
+			ttl = untilExpiry
+		}
+	}
+	if ttl <= 0 {
+		return view
+	}
+	url, err := s.storage.SignedURL(ctx, job.StorageKey, ttl)
+	if err != nil {
+		log.Printf("[ExportService] Не удалось подписать ссылку на выгрузку #%d: %v", job.ID, err)
+		return view
+	}
+	expiresAt := time.Now().Add(ttl).UTC()
+	view.DownloadURL = url
+	view.DownloadURLExpiresAt = &expiresAt
+	return view
+}
+
+// Start запускает обработку заданий: каждые interval и сразу после CreateJob.
+// Раз в час удаляет файлы с истёкшим сроком хранения. Работает до отмены ctx.
+func (s *ExportService) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		cleanup := time.NewTicker(time.Hour)
+		defer cleanup.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-s.kick:
+			case <-cleanup.C:
+				s.CleanupExpired(ctx)
+				continue
+			}
+			if _, err := s.RunPending(ctx); err != nil {
+				log.Printf("[ExportService] Ошибка обработки выгрузок: %v", err)
+			}
+		}
+	}()
+}
+
+// RunPending выполняет задания, пока они есть в очереди, и возвращает их число
+func (s *ExportService) RunPending(ctx context.Context) (int, error) {
+	processed := 0
+	for ctx.Err() == nil {
+		job, err := s.repo.Claim(time.Now().UTC(), exportJobLease)
+		if err != nil {
+			return processed, err
+		}
+		if job == nil {
+			break
+		}
+		s.runJob(ctx, job)
+		processed++
+	}
+	return processed, nil
+}
+
+// runJob формирует файл задания и сохраняет итог: готово, повтор или отказ
+func (s *ExportService) runJob(ctx context.Context, job *entity.ExportJob) {
+	err := s.produce(ctx, job)
+	now := time.Now().UTC()
+	job.LeaseUntil = nil
+	switch {
+	case err == nil:
+		expiresAt := now.Add(s.config.Retention)
+		job.Status = entity.ExportJobCompleted
+		job.Error = ""
+		job.CompletedAt = &now
+		job.ExpiresAt = &expiresAt
+	case job.Attempts >= exportMaxAttempts:
+		log.Printf("[ExportService] Выгрузка #%d не удалась после %d попыток: %v", job.ID, job.Attempts, err)
+		job.Status = entity.ExportJobFailed
+		job.Error = truncateRunes(err.Error(), 500)
+	default:
+		log.Printf("[ExportService] Выгрузка #%d не удалась, будет повтор: %v", job.ID, err)
+		job.Status = entity.ExportJobPending
+		job.Error = truncateRunes(err.Error(), 500)
+		job.RowsDone = 0
+	}
+	if err := s.repo.Save(job); err != nil {
+		log.Printf("[ExportService] Ошибка сохранения выгрузки #%d: %v", job.ID, err)
+	}
+}
+
+// produce пишет выгрузку во временный файл и переносит его в хранилище
+func (s *ExportService) produce(ctx context.Context, job *entity.ExportJob) error {
+	var (
+		total int64
+		err   error
+	)
+	switch job.Kind {
+	case entity.ExportKindResults:
+		total, err = s.repo.CountQuizResults(job.QuizID)
+	case entity.ExportKindAnswers:
+		total, err = s.repo.CountQuizAnswers(job.QuizID)
+	default:
+		return fmt.Errorf("unknown export kind %q", job.Kind)
+	}
+	if err != nil {
+		return err
+	}
+	job.RowsTotal = total
+	job.RowsDone = 0
+	if err := s.repo.Save(job); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp("", "export-*."+job.Format)
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	switch job.Kind {
+	case entity.ExportKindResults:
+		err = s.writeResults(ctx, job, tmp)
+	case entity.ExportKindAnswers:
+		err = s.writeAnswers(ctx, job, tmp)
+	}
+	if err != nil {
+		return err
+	}
+
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("failed to measure export file: %w", err)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind export file: %w", err)
+	}
+	// Случайная часть ключа: локальное хранилище раздаёт файлы по постоянной ссылке
+	key := fmt.Sprintf("exports/quiz_%d/%s.%s", job.QuizID, uuid.NewString(), job.Format)
+	if err := s.storage.Store(ctx, key, tmp, size, export.ContentType(job.Format)); err != nil {
+		return fmt.Errorf("failed to store export file: %w", err)
+	}
+	job.StorageKey = key
+	job.SizeBytes = size
+	job.FileName = fmt.Sprintf("quiz_%d_%s_%s.%s", job.QuizID, job.Kind, time.Now().UTC().Format("2006-01-02"), job.Format)
+	return nil
+}
+
+var resultExportColumns = []export.Column{
+	{Key: "rank", Title: "Место"},
+	{Key: "user_id", Title: "ID пользователя"},
+	{Key: "username", Title: "Пользователь"},
+	{Key: "score", Title: "Очки"},
+	{Key: "correct_answers", Title: "Правильных"},
+	{Key: "total_questions", Title: "Всего вопросов"},
+	{Key: "is_winner", Title: "Победитель", Label: export.LabelYesNo},
+	{Key: "is_eliminated", Title: "Выбыл", Label: export.LabelYesNo},
+	{Key: "eliminated_on_question", Title: "Вопрос выбытия"},
+	{Key: "elimination_reason", Title: "Причина выбытия", Label: export.LabelEliminationReason},
+	{Key: "prize_fund", Title: "Приз (₸)"},
+}
+
+func (s *ExportService) writeResults(ctx context.Context, job *entity.ExportJob, out io.Writer) error {
+	w, err := export.NewWriter(job.Format, out, resultExportColumns)
+	if err != nil {
+		return err
+	}
+	afterRank, afterID := -1, uint(0)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		results, err := s.repo.ListQuizResultsAfter(job.QuizID, afterRank, afterID, s.config.BatchSize)
+		if err != nil {
+			return err
+		}
+		for _, r := range results {
+			if err := w.WriteRow([]interface{}{
+				r.Rank, r.UserID, r.Username, r.Score, r.CorrectAnswers, r.TotalQuestions,
+				r.IsWinner, r.IsEliminated, r.EliminatedOnQuestion, r.EliminationReason, r.PrizeFund,
+			}); err != nil {
+				return err
+			}
+		}
+		if len(results) == 0 {
+			break
+		}
+		last := results[len(results)-1]
+		afterRank, afterID = last.Rank, last.ID
+		if err := s.reportProgress(job, int64(len(results))); err != nil {
+			return err
+		}
+		if len(results) < s.config.BatchSize {
+			break
+		}
+	}
+	return w.Close()
+}
+
+var answerExportColumns = []export.Column{
+	{Key: "id", Title: "ID ответа"},
+	{Key: "user_id", Title: "ID пользователя"},
+	{Key: "username", Title: "Пользователь"},
+	{Key: "question_number", Title: "Номер вопроса"},
+	{Key: "question_id", Title: "ID вопроса"},
+	{Key: "question_text", Title: "Вопрос"},
+	{Key: "selected_option", Title: "Выбранный вариант", Label: labelSelectedOption},
+	{Key: "is_correct", Title: "Верно", Label: export.LabelYesNo},
+	{Key: "response_time_ms", Title: "Время ответа (мс)"},
+	{Key: "score", Title: "Очки"},
+	{Key: "is_eliminated", Title: "Выбыл на вопросе", Label: export.LabelYesNo},
+	{Key: "elimination_reason", Title: "Причина выбытия", Label: export.LabelEliminationReason},
+	{Key: "created_at", Title: "Время"},
+}
+
+// labelSelectedOption показывает вариант с единицы; -1 — ответа не было
+func labelSelectedOption(value interface{}) interface{} {
+	if option, ok := value.(int); ok && option >= 0 {
+		return option + 1
+	}
+	return ""
+}
+
+func (s *ExportService) writeAnswers(ctx context.Context, job *entity.ExportJob, out io.Writer) error {
+	w, err := export.NewWriter(job.Format, out, answerExportColumns)
+	if err != nil {
+		return err
+	}
+	afterID := uint(0)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		answers, err := s.repo.ListQuizAnswersAfter(job.QuizID, afterID, s.config.BatchSize)
+		if err != nil {
+			return err
+		}
+		for _, a := range answers {
+			if err := w.WriteRow([]interface{}{
+				a.ID, a.UserID, a.Username, a.QuestionNumber, a.QuestionID, a.QuestionText, a.SelectedOption,
+				a.IsCorrect, a.ResponseTimeMs, a.Score, a.IsEliminated, a.EliminationReason, a.CreatedAt,
+			}); err != nil {
+				return err
+			}
+		}
+		if len(answers) == 0 {
+			break
+		}
+		afterID = answers[len(answers)-1].ID
+		if err := s.reportProgress(job, int64(len(answers))); err != nil {
+			return err
+		}
+		if len(answers) < s.config.BatchSize {
+			break
+		}
+	}
+	return w.Close()
+}
+
+// reportProgress сохраняет число записанных строк и продлевает аренду задания
+func (s *ExportService) reportProgress(job *entity.ExportJob, written int64) error {
+	job.RowsDone += written
+	return s.repo.UpdateProgress(job.ID, job.RowsDone, time.Now().UTC().Add(exportJobLease))
+}
+
+// CleanupExpired удаляет файлы выгрузок с истёкшим сроком хранения
+func (s *ExportService) CleanupExpired(ctx context.Context) {
+	jobs, err := s.repo.ListExpired(time.Now().UTC(), 100)
+	if err != nil {
+		log.Printf("[ExportService] Ошибка поиска устаревших выгрузок: %v", err)
+		return
+	}
+	for i := range jobs {
+		job := &jobs[i]
+		if err := s.storage.Delete(ctx, job.StorageKey); err != nil {
+			log.Printf("[ExportService] Не удалось удалить файл выгрузки #%d: %v", job.ID, err)
+			continue
+		}
+		job.Status = entity.ExportJobExpired
+		job.StorageKey = ""
+		if err := s.repo.Save(job); err != nil {
+			log.Printf("[ExportService] Ошибка сохранения выгрузки #%d: %v", job.ID, err)
+		}
+	}
+}
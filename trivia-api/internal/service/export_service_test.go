@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/pkg/storage"
+)
+
+// fakeExportJobRepo — ExportJobRepository в памяти
+type fakeExportJobRepo struct {
+	jobs     []*entity.ExportJob
+	results  []entity.Result
+	answers  []repository.AnswerExportRow
+	failRead error
+	progress []int64
+}
+
+func (f *fakeExportJobRepo) Create(job *entity.ExportJob) error {
+	job.ID = uint(len(f.jobs) + 1)
+	copied := *job
+	f.jobs = append(f.jobs, &copied)
+	return nil
+}
+
+func (f *fakeExportJobRepo) GetByID(id uint) (*entity.ExportJob, error) {
+	for _, job := range f.jobs {
+		if job.ID == id {
+			copied := *job
+			return &copied, nil
+		}
+	}
+	return nil, apperrors.ErrNotFound
+}
+
+func (f *fakeExportJobRepo) ListByQuiz(quizID uint, limit int) ([]entity.ExportJob, error) {
+	var jobs []entity.ExportJob
+	for _, job := range f.jobs {
+		if job.QuizID == quizID {
+			jobs = append(jobs, *job)
+		}
+	}
+	return jobs, nil
+}
+
+func (f *fakeExportJobRepo) Claim(now time.Time, lease time.Duration) (*entity.ExportJob, error) {
+	for _, job := range f.jobs {
+		if job.Status == entity.ExportJobPending {
+			leaseUntil := now.Add(lease)
+			job.Status = entity.ExportJobRunning
+			job.LeaseUntil = &leaseUntil
+			job.Attempts++
+			copied := *job
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeExportJobRepo) UpdateProgress(id uint, rowsDone int64, leaseUntil time.Time) error {
+	f.progress = append(f.progress, rowsDone)
+	return nil
+}
+
+func (f *fakeExportJobRepo) Save(job *entity.ExportJob) error {
+	for i, existing := range f.jobs {
+		if existing.ID == job.ID {
+			copied := *job
+			f.jobs[i] = &copied
+		}
+	}
+	return nil
+}
+
+func (f *fakeExportJobRepo) ListExpired(now time.Time, limit int) ([]entity.ExportJob, error) {
+	var jobs []entity.ExportJob
+	for _, job := range f.jobs {
+		if job.Status == entity.ExportJobCompleted && job.ExpiresAt.Before(now) {
+			jobs = append(jobs, *job)
+		}
+	}
+	return jobs, nil
+}
+
+func (f *fakeExportJobRepo) CountQuizResults(quizID uint) (int64, error) {
+	return int64(len(f.results)), nil
+}
+
+func (f *fakeExportJobRepo) ListQuizResultsAfter(quizID uint, afterRank int, afterID uint, limit int) ([]entity.Result, error) {
+	if f.failRead != nil {
+		return nil, f.failRead
+	}
+	var page []entity.Result
+	for _, r := range f.results {
+		if (r.Rank > afterRank || (r.Rank == afterRank && r.ID > afterID)) && len(page) < limit {
+			page = append(page, r)
+		}
+	}
+	return page, nil
+}
+
+func (f *fakeExportJobRepo) CountQuizAnswers(quizID uint) (int64, error) {
+	return int64(len(f.answers)), nil
+}
+
+func (f *fakeExportJobRepo) ListQuizAnswersAfter(quizID uint, afterID uint, limit int) ([]repository.AnswerExportRow, error) {
+	var page []repository.AnswerExportRow
+	for _, a := range f.answers {
+		if a.ID > afterID && len(page) < limit {
+			page = append(page, a)
+		}
+	}
+	return page, nil
+}
+
+func newExportTestService(t *testing.T, repo *fakeExportJobRepo) (*ExportService, string) {
+	t.Helper()
+	dir := t.TempDir()
+	store, err := storage.NewLocalStorage(dir, "/uploads")
+	require.NoError(t, err)
+	quizRepo := new(MockQuizRepository)
+	quizRepo.On("GetByID", uint(3)).Return(&entity.Quiz{ID: 3, Title: "Кино"}, nil)
+	quizRepo.On("GetByID", mock.Anything).Return(nil, apperrors.ErrNotFound)
+	return NewExportService(repo, quizRepo, store, ExportConfig{BatchSize: 2, DownloadURLTTL: time.Hour, Retention: time.Hour}), dir
+}
+
+func TestExportService_ResultsCSV(t *testing.T) {
+	repo := &fakeExportJobRepo{}
+	for i := 1; i <= 5; i++ {
+		repo.results = append(repo.results, entity.Result{ID: uint(10 + i), QuizID: 3, UserID: uint(i), Username: "player", Rank: i, Score: 10 - i, IsWinner: i == 1})
+	}
+	svc, dir := newExportTestService(t, repo)
+	ctx := context.Background()
+
+	created, err := svc.CreateJob(ctx, 3, 1, entity.ExportKindResults, entity.ExportFormatCSV)
+	require.NoError(t, err)
+	assert.Equal(t, entity.ExportJobPending, created.Status)
+	assert.Empty(t, created.DownloadURL)
+
+	processed, err := svc.RunPending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, processed)
+	assert.Equal(t, []int64{2, 4, 5}, repo.progress, "прогресс сохраняется после каждой порции")
+
+	job, err := svc.GetJob(ctx, 3, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.ExportJobCompleted, job.Status)
+	assert.Equal(t, 100, job.Progress)
+	assert.Equal(t, int64(5), job.RowsTotal)
+	require.NotEmpty(t, job.DownloadURL)
+	assert.True(t, strings.HasPrefix(job.DownloadURL, "/uploads/exports/quiz_3/"))
+	require.NotNil(t, job.DownloadURLExpiresAt)
+
+	content, err := os.ReadFile(filepath.Join(dir, job.StorageKey))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 6)
+	assert.Contains(t, lines[0], "Место")
+	assert.True(t, strings.HasPrefix(lines[1], "1,1,player,9,"))
+	assert.Equal(t, job.SizeBytes, int64(len(content)))
+
+	_, err = svc.GetJob(ctx, 4, created.ID)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound, "задание другой викторины")
+}
+
+func TestExportService_AnswersJSON(t *testing.T) {
+	question := 2
+	repo := &fakeExportJobRepo{answers: []repository.AnswerExportRow{
+		{ID: 1, UserID: 5, Username: "a", QuestionNumber: &question, SelectedOption: 1, IsCorrect: true},
+		{ID: 2, UserID: 6, Username: "b", SelectedOption: -1, EliminationReason: "no_answer_timeout", IsEliminated: true},
+	}}
+	svc, dir := newExportTestService(t, repo)
+	ctx := context.Background()
+
+	created, err := svc.CreateJob(ctx, 3, 1, entity.ExportKindAnswers, entity.ExportFormatJSON)
+	require.NoError(t, err)
+	_, err = svc.RunPending(ctx)
+	require.NoError(t, err)
+
+	job, err := svc.GetJob(ctx, 3, created.ID)
+	require.NoError(t, err)
+	require.Equal(t, entity.ExportJobCompleted, job.Status)
+	content, err := os.ReadFile(filepath.Join(dir, job.StorageKey))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"question_number":2`)
+	assert.Contains(t, string(content), `"selected_option":-1`)
+	assert.Contains(t, string(content), `"elimination_reason":"no_answer_timeout"`)
+}
+
+func TestExportService_RetriesThenFails(t *testing.T) {
+	repo := &fakeExportJobRepo{results: []entity.Result{{ID: 1, Rank: 1}}, failRead: errors.New("db is down")}
+	svc, _ := newExportTestService(t, repo)
+	ctx := context.Background()
+
+	created, err := svc.CreateJob(ctx, 3, 1, entity.ExportKindResults, entity.ExportFormatXLSX)
+	require.NoError(t, err)
+
+	// Неудачное задание возвращается в очередь, и RunPending забирает его снова, пока не кончатся попытки
+	processed, err := svc.RunPending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, exportMaxAttempts, processed)
+
+	job, err := svc.GetJob(ctx, 3, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.ExportJobFailed, job.Status)
+	assert.Equal(t, exportMaxAttempts, job.Attempts)
+	assert.Contains(t, job.Error, "db is down")
+}
+
+func TestExportService_CreateJobValidation(t *testing.T) {
+	svc, _ := newExportTestService(t, &fakeExportJobRepo{})
+	ctx := context.Background()
+
+	_, err := svc.CreateJob(ctx, 3, 1, "users", entity.ExportFormatCSV)
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	_, err = svc.CreateJob(ctx, 3, 1, entity.ExportKindResults, "pdf")
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	_, err = svc.CreateJob(ctx, 99, 1, entity.ExportKindResults, entity.ExportFormatCSV)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
+
+func TestExportService_CleanupExpired(t *testing.T) {
+	repo := &fakeExportJobRepo{results: []entity.Result{{ID: 1, Rank: 1}}}
+	svc, dir := newExportTestService(t, repo)
+	ctx := context.Background()
+
+	created, err := svc.CreateJob(ctx, 3, 1, entity.ExportKindResults, entity.ExportFormatCSV)
+	require.NoError(t, err)
+	_, err = svc.RunPending(ctx)
+	require.NoError(t, err)
+	job, _ := repo.GetByID(created.ID)
+	path := filepath.Join(dir, job.StorageKey)
+	require.FileExists(t, path)
+
+	expired := time.Now().Add(-time.Minute)
+	repo.jobs[0].ExpiresAt = &expired
+	svc.CleanupExpired(ctx)
+
+	assert.NoFileExists(t, path)
+	view, err := svc.GetJob(ctx, 3, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.ExportJobExpired, view.Status)
+	assert.Empty(t, view.DownloadURL)
+}
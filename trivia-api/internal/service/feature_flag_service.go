@@ -0,0 +1,231 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+const (
+	// featureFlagsCacheKey — все флаги одним значением: вычисление флага не ходит в БД
+	featureFlagsCacheKey = "feature_flags:all"
+	featureFlagsCacheTTL = 10 * time.Minute
+
+	// featureFlagsLocalTTL — сколько инстанс держит флаги в памяти. Изменение флага
+	// на другом инстансе применяется не позже чем через этот интервал.
+	featureFlagsLocalTTL = 15 * time.Second
+)
+
+var featureFlagKeyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,99}$`)
+
+// FeatureEvaluator вычисляет флаг функции для пользователя (FeatureFlagService)
+type FeatureEvaluator interface {
+	FeatureEnabled(key string, userID uint, isAdmin bool) bool
+}
+
+// FeatureFlagInput — настраиваемые поля флага
+type FeatureFlagInput struct {
+	Description string
+	Enabled     bool
+	AdminOnly   bool
+	Percentage  int
+	UserIDs     []uint
+}
+
+// FeatureFlagService хранит флаги функций в Postgres с кешем в Redis и в памяти инстанса.
+// Флаг без записи в БД берёт значение из defaults (статическая конфигурация cfg.Features).
+type FeatureFlagService struct {
+	repo     repository.FeatureFlagRepository
+	cache    repository.CacheRepository
+	defaults map[string]bool
+
+	mu       sync.Mutex
+	flags    map[string]entity.FeatureFlag
+	loadedAt time.Time
+}
+
+// NewFeatureFlagService создает сервис флагов функций
+func NewFeatureFlagService(repo repository.FeatureFlagRepository, cache repository.CacheRepository, defaults map[string]bool) *FeatureFlagService {
+	return &FeatureFlagService{repo: repo, cache: cache, defaults: defaults}
+}
+
+// List возвращает все флаги из БД
+func (s *FeatureFlagService) List() ([]entity.FeatureFlag, error) {
+	return s.repo.List()
+}
+
+// Get возвращает флаг по ключу
+func (s *FeatureFlagService) Get(key string) (*entity.FeatureFlag, error) {
+	return s.repo.GetByKey(key)
+}
+
+// Create создаёт флаг
+func (s *FeatureFlagService) Create(key string, input FeatureFlagInput) (*entity.FeatureFlag, error) {
+	if !featureFlagKeyPattern.MatchString(key) {
+		return nil, fmt.Errorf("%w: key must match %s", apperrors.ErrValidation, featureFlagKeyPattern)
+	}
+	flag := &entity.FeatureFlag{Key: key}
+	if err := applyFeatureFlagInput(flag, input); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(flag); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	return flag, nil
+}
+
+// Update заменяет настройки флага
+func (s *FeatureFlagService) Update(key string, input FeatureFlagInput) (*entity.FeatureFlag, error) {
+	flag, err := s.repo.GetByKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyFeatureFlagInput(flag, input); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(flag); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	return flag, nil
+}
+
+// Delete удаляет флаг; дальше действует значение из конфигурации
+func (s *FeatureFlagService) Delete(key string) error {
+	if err := s.repo.Delete(key); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+// FeatureEnabled вычисляет флаг для пользователя userID (0 — аноним)
+func (s *FeatureFlagService) FeatureEnabled(key string, userID uint, isAdmin bool) bool {
+	flags := s.snapshot()
+	flag, ok := flags[key]
+	if !ok {
+		return s.defaults[key]
+	}
+	return flag.EnabledFor(userID, isAdmin, featureBucket(key, userID))
+}
+
+// Evaluate вычисляет все известные флаги (из БД и конфигурации) для пользователя
+func (s *FeatureFlagService) Evaluate(userID uint, isAdmin bool) map[string]bool {
+	flags := s.snapshot()
+	result := make(map[string]bool, len(flags)+len(s.defaults))
+	for key, enabled := range s.defaults {
+		result[key] = enabled
+	}
+	for key, flag := range flags {
+		result[key] = flag.EnabledFor(userID, isAdmin, featureBucket(key, userID))
+	}
+	return result
+}
+
+// snapshot возвращает флаги из памяти, перечитывая их из Redis или БД раз в featureFlagsLocalTTL.
+// Если флаги прочитать не удалось, используется последний известный набор.
+func (s *FeatureFlagService) snapshot() map[string]entity.FeatureFlag {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.flags != nil && time.Since(s.loadedAt) < featureFlagsLocalTTL {
+		return s.flags
+	}
+	flags, err := s.load()
+	s.loadedAt = time.Now()
+	if err != nil {
+		log.Printf("[FeatureFlagService] Ошибка загрузки флагов: %v", err)
+		if s.flags == nil {
+			s.flags = map[string]entity.FeatureFlag{}
+		}
+		return s.flags
+	}
+
+	s.flags = make(map[string]entity.FeatureFlag, len(flags))
+	for _, flag := range flags {
+		s.flags[flag.Key] = flag
+	}
+	return s.flags
+}
+
+func (s *FeatureFlagService) load() ([]entity.FeatureFlag, error) {
+	var flags []entity.FeatureFlag
+	err := s.cache.GetJSON(featureFlagsCacheKey, &flags)
+	if err == nil {
+		return flags, nil
+	}
+	if !errors.Is(err, apperrors.ErrNotFound) {
+		log.Printf("[FeatureFlagService] Ошибка чтения кеша флагов: %v", err)
+	}
+
+	flags, err = s.repo.List()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cache.SetJSON(featureFlagsCacheKey, flags, featureFlagsCacheTTL); err != nil {
+		log.Printf("[FeatureFlagService] Ошибка записи кеша флагов: %v", err)
+	}
+	return flags, nil
+}
+
+// invalidate сбрасывает кеш после изменения флага: этот инстанс перечитает флаги сразу,
+// остальные — по истечении featureFlagsLocalTTL
+func (s *FeatureFlagService) invalidate() {
+	if err := s.cache.Delete(featureFlagsCacheKey); err != nil {
+		log.Printf("[FeatureFlagService] Ошибка сброса кеша флагов: %v", err)
+	}
+	s.mu.Lock()
+	s.loadedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+func applyFeatureFlagInput(flag *entity.FeatureFlag, input FeatureFlagInput) error {
+	if input.Percentage < 0 || input.Percentage > 100 {
+		return fmt.Errorf("%w: percentage must be between 0 and 100", apperrors.ErrValidation)
+	}
+	if len(input.Description) > 500 {
+		return fmt.Errorf("%w: description must be at most 500 characters", apperrors.ErrValidation)
+	}
+
+	userIDs := make(entity.UintArray, 0, len(input.UserIDs))
+	seen := make(map[uint]struct{}, len(input.UserIDs))
+	for _, id := range input.UserIDs {
+		if id == 0 {
+			return fmt.Errorf("%w: user_ids must not contain 0", apperrors.ErrValidation)
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		userIDs = append(userIDs, id)
+	}
+	sort.Slice(userIDs, func(i, j int) bool { return userIDs[i] < userIDs[j] })
+
+	flag.Description = input.Description
+	flag.Enabled = input.Enabled
+	flag.AdminOnly = input.AdminOnly
+	flag.Percentage = input.Percentage
+	flag.UserIDs = userIDs
+	return nil
+}
+
+// featureBucket — стабильный номер пользователя 0-99 для флага. Ключ флага входит в хеш,
+// чтобы разные флаги при одинаковом проценте включались у разных пользователей.
+func featureBucket(key string, userID uint) int {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	h.Write([]byte{':'})
+	h.Write([]byte(strconv.FormatUint(uint64(userID), 10)))
+	return int(h.Sum32() % 100)
+}
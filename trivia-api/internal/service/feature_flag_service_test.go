@@ -0,0 +1,122 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// fakeFeatureFlagRepo — FeatureFlagRepository в памяти, считает чтения списка
+type fakeFeatureFlagRepo struct {
+	flags map[string]entity.FeatureFlag
+	lists int
+}
+
+func (f *fakeFeatureFlagRepo) List() ([]entity.FeatureFlag, error) {
+	f.lists++
+	flags := make([]entity.FeatureFlag, 0, len(f.flags))
+	for _, flag := range f.flags {
+		flags = append(flags, flag)
+	}
+	return flags, nil
+}
+
+func (f *fakeFeatureFlagRepo) GetByKey(key string) (*entity.FeatureFlag, error) {
+	flag, ok := f.flags[key]
+	if !ok {
+		return nil, apperrors.ErrNotFound
+	}
+	return &flag, nil
+}
+
+func (f *fakeFeatureFlagRepo) Create(flag *entity.FeatureFlag) error {
+	if _, ok := f.flags[flag.Key]; ok {
+		return apperrors.ErrConflict
+	}
+	f.flags[flag.Key] = *flag
+	return nil
+}
+
+func (f *fakeFeatureFlagRepo) Update(flag *entity.FeatureFlag) error {
+	f.flags[flag.Key] = *flag
+	return nil
+}
+
+func (f *fakeFeatureFlagRepo) Delete(key string) error {
+	if _, ok := f.flags[key]; !ok {
+		return apperrors.ErrNotFound
+	}
+	delete(f.flags, key)
+	return nil
+}
+
+func newTestFeatureFlagService(defaults map[string]bool) (*FeatureFlagService, *fakeFeatureFlagRepo) {
+	repo := &fakeFeatureFlagRepo{flags: map[string]entity.FeatureFlag{}}
+	cache := &memoryQuizCache{values: map[string][]byte{}}
+	return NewFeatureFlagService(repo, cache, defaults), repo
+}
+
+func TestFeatureFlagService_DefaultsAndOverride(t *testing.T) {
+	svc, repo := newTestFeatureFlagService(map[string]bool{entity.FeatureEmailVerification: true})
+
+	assert.True(t, svc.FeatureEnabled(entity.FeatureEmailVerification, 1, false))
+	assert.False(t, svc.FeatureEnabled("new_mode", 1, false))
+
+	_, err := svc.Create(entity.FeatureEmailVerification, FeatureFlagInput{Enabled: true, UserIDs: []uint{7, 7, 3}})
+	require.NoError(t, err)
+	assert.Equal(t, entity.UintArray{3, 7}, repo.flags[entity.FeatureEmailVerification].UserIDs)
+
+	// Запись в БД перекрывает конфигурацию: включено только для списка пользователей
+	assert.True(t, svc.FeatureEnabled(entity.FeatureEmailVerification, 7, false))
+	assert.False(t, svc.FeatureEnabled(entity.FeatureEmailVerification, 1, false))
+
+	// Повторные проверки берут флаги из памяти
+	lists := repo.lists
+	svc.FeatureEnabled(entity.FeatureEmailVerification, 1, false)
+	assert.Equal(t, lists, repo.lists)
+
+	require.NoError(t, svc.Delete(entity.FeatureEmailVerification))
+	assert.True(t, svc.FeatureEnabled(entity.FeatureEmailVerification, 1, false))
+}
+
+func TestFeatureFlagService_PercentageAndAdminOnly(t *testing.T) {
+	svc, _ := newTestFeatureFlagService(nil)
+
+	_, err := svc.Create("new_mode", FeatureFlagInput{Enabled: true, Percentage: 30})
+	require.NoError(t, err)
+
+	enabled := 0
+	for userID := uint(1); userID <= 1000; userID++ {
+		first := svc.FeatureEnabled("new_mode", userID, false)
+		assert.Equal(t, first, svc.FeatureEnabled("new_mode", userID, false), "bucket must be stable")
+		if first {
+			enabled++
+		}
+	}
+	assert.InDelta(t, 300, enabled, 60)
+	assert.False(t, svc.FeatureEnabled("new_mode", 0, false), "anonymous users are outside partial rollouts")
+
+	_, err = svc.Update("new_mode", FeatureFlagInput{Enabled: true, AdminOnly: true, Percentage: 100})
+	require.NoError(t, err)
+	assert.True(t, svc.FeatureEnabled("new_mode", 5, true))
+	assert.False(t, svc.FeatureEnabled("new_mode", 5, false))
+
+	_, err = svc.Update("new_mode", FeatureFlagInput{Percentage: 100})
+	require.NoError(t, err)
+	assert.False(t, svc.FeatureEnabled("new_mode", 5, true))
+}
+
+func TestFeatureFlagService_Validation(t *testing.T) {
+	svc, _ := newTestFeatureFlagService(nil)
+
+	_, err := svc.Create("Bad Key", FeatureFlagInput{})
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	_, err = svc.Create("ok_key", FeatureFlagInput{Percentage: 101})
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	_, err = svc.Update("missing", FeatureFlagInput{})
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
@@ -0,0 +1,238 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	"github.com/yourusername/trivia-api/internal/handler/dto"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// ErrFollowRateLimited возвращается, если пользователь подписывается слишком часто
+var ErrFollowRateLimited = errors.New("follow_rate_limited")
+
+// EventFriendJoinedWaitingRoom — WebSocket-событие подписчикам: игрок вошёл в зал ожидания викторины
+const EventFriendJoinedWaitingRoom = "friend:joined_waiting_room"
+
+const (
+	// maxFollowing — сколько игроков можно держать в подписках
+	maxFollowing = 1000
+	// maxFollowsPerHour — сколько новых подписок можно оформить за час (защита от спама подписками)
+	maxFollowsPerHour = 60
+)
+
+// FollowEvents отправляет события подписчикам (реализуется websocket.Manager)
+type FollowEvents interface {
+	SendEventToUser(userID string, eventType string, data interface{}) error
+}
+
+// FollowStatus — подписка между текущим пользователем и игроком и счётчики игрока
+type FollowStatus struct {
+	UserID         uint  `json:"user_id"`
+	Following      bool  `json:"following"`   // текущий пользователь подписан на игрока
+	FollowedBy     bool  `json:"followed_by"` // игрок подписан на текущего пользователя
+	Mutual         bool  `json:"mutual"`      // взаимная подписка: игроки друзья
+	FollowersCount int64 `json:"followers_count"`
+	FollowingCount int64 `json:"following_count"`
+}
+
+// FollowList содержит страницу подписок или подписчиков
+type FollowList struct {
+	Users    []repository.FollowListItem `json:"users"`
+	Total    int64                       `json:"total"`
+	Page     int                         `json:"page"`
+	PageSize int                         `json:"page_size"`
+}
+
+// FollowService ведёт подписки между игроками: списки подписок, лидерборд друзей
+// и уведомления подписчиков о входе игрока в зал ожидания
+type FollowService struct {
+	repo     repository.UserFollowRepository
+	userRepo repository.UserRepository
+	events   FollowEvents
+}
+
+// NewFollowService создает сервис подписок
+func NewFollowService(repo repository.UserFollowRepository, userRepo repository.UserRepository) *FollowService {
+	return &FollowService{repo: repo, userRepo: userRepo}
+}
+
+// SetEvents подключает WebSocket-уведомления подписчиков
+func (s *FollowService) SetEvents(events FollowEvents) {
+	s.events = events
+}
+
+// Follow подписывает пользователя на игрока. Повторная подписка ничего не меняет; новые подписки
+// ограничены maxFollowing всего и maxFollowsPerHour в час.
+func (s *FollowService) Follow(followerID, followeeID uint) (*FollowStatus, error) {
+	if followerID == followeeID {
+		return nil, fmt.Errorf("%w: cannot follow yourself", apperrors.ErrValidation)
+	}
+	if err := s.checkFollowee(followeeID); err != nil {
+		return nil, err
+	}
+	following, err := s.repo.Exists(followerID, followeeID)
+	if err != nil {
+		return nil, err
+	}
+	if !following {
+		total, err := s.repo.CountFollowing(followerID)
+		if err != nil {
+			return nil, err
+		}
+		if total >= maxFollowing {
+			return nil, fmt.Errorf("%w: following limit of %d reached", apperrors.ErrConflict, maxFollowing)
+		}
+		recent, err := s.repo.CountCreatedSince(followerID, time.Now().Add(-time.Hour))
+		if err != nil {
+			return nil, err
+		}
+		if recent >= maxFollowsPerHour {
+			return nil, ErrFollowRateLimited
+		}
+		if _, err := s.repo.Create(&entity.UserFollow{FollowerID: followerID, FolloweeID: followeeID}); err != nil {
+			return nil, err
+		}
+	}
+	return s.GetStatus(followerID, followeeID)
+}
+
+// Unfollow отменяет подписку; отсутствие подписки не ошибка
+func (s *FollowService) Unfollow(followerID, followeeID uint) (*FollowStatus, error) {
+	if _, err := s.repo.Delete(followerID, followeeID); err != nil {
+		return nil, err
+	}
+	return s.GetStatus(followerID, followeeID)
+}
+
+// GetStatus возвращает подписки между viewerID и игроком userID и счётчики игрока
+func (s *FollowService) GetStatus(viewerID, userID uint) (*FollowStatus, error) {
+	if err := s.checkFollowee(userID); err != nil {
+		return nil, err
+	}
+	status := &FollowStatus{UserID: userID}
+	var err error
+	if viewerID != userID {
+		if status.Following, err = s.repo.Exists(viewerID, userID); err != nil {
+			return nil, err
+		}
+		if status.FollowedBy, err = s.repo.Exists(userID, viewerID); err != nil {
+			return nil, err
+		}
+		status.Mutual = status.Following && status.FollowedBy
+	}
+	if status.FollowersCount, err = s.repo.CountFollowers(userID); err != nil {
+		return nil, err
+	}
+	if status.FollowingCount, err = s.repo.CountFollowing(userID); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// ListFollowing возвращает страницу подписок пользователя
+func (s *FollowService) ListFollowing(userID uint, page, pageSize int) (*FollowList, error) {
+	page, pageSize = normalizeFollowPage(page, pageSize)
+	users, total, err := s.repo.ListFollowing(userID, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &FollowList{Users: users, Total: total, Page: page, PageSize: pageSize}, nil
+}
+
+// ListFollowers возвращает страницу подписчиков пользователя
+func (s *FollowService) ListFollowers(userID uint, page, pageSize int) (*FollowList, error) {
+	page, pageSize = normalizeFollowPage(page, pageSize)
+	users, total, err := s.repo.ListFollowers(userID, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &FollowList{Users: users, Total: total, Page: page, PageSize: pageSize}, nil
+}
+
+// GetFriendsLeaderboard возвращает лидерборд из пользователя и игроков, на которых он подписан.
+// Подписки, скрывшие себя из лидербордов (show_on_leaderboards), показываются заглушкой, как в
+// публичных лидербордах: подписка не даёт согласия на показ. Свою строку пользователь видит всегда.
+func (s *FollowService) GetFriendsLeaderboard(userID uint, page, pageSize int) (*dto.PaginatedLeaderboardResponse, error) {
+	page, pageSize = normalizeFollowPage(page, pageSize)
+	offset := (page - 1) * pageSize
+	users, total, err := s.repo.GetFriendsLeaderboard(userID, pageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+	userDTOs := make([]*dto.LeaderboardUserDTO, len(users))
+	for i, user := range users {
+		identity := publicLeaderboardIdentity(user.ID, user.Username, user.ProfilePicture, user.ShowOnLeaderboards || user.ID == userID)
+		userDTOs[i] = &dto.LeaderboardUserDTO{
+			Rank:           offset + i + 1,
+			UserID:         identity.UserID,
+			Username:       identity.Username,
+			ProfilePicture: identity.ProfilePicture,
+			WinsCount:      user.WinsCount,
+			TotalPrizeWon:  user.TotalPrizeWon,
+			Anonymous:      identity.Anonymous,
+		}
+	}
+	return &dto.PaginatedLeaderboardResponse{Users: userDTOs, Total: total, Page: page, PerPage: pageSize}, nil
+}
+
+// NotifyFriendJoined сообщает подписчикам игрока, что он вошёл в зал ожидания викторины.
+// Игроки со скрытым профилем свою активность не раскрывают.
+func (s *FollowService) NotifyFriendJoined(quizID, userID uint) {
+	if s.events == nil {
+		return
+	}
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		log.Printf("[FollowService] Не удалось получить пользователя #%d для уведомления подписчиков: %v", userID, err)
+		return
+	}
+	if !user.ProfilePublic {
+		return
+	}
+	followerIDs, err := s.repo.ListFollowerIDs(userID)
+	if err != nil {
+		log.Printf("[FollowService] Не удалось получить подписчиков пользователя #%d: %v", userID, err)
+		return
+	}
+	event := map[string]interface{}{
+		"quiz_id":         quizID,
+		"user_id":         user.ID,
+		"username":        user.Username,
+		"profile_picture": user.ProfilePicture,
+	}
+	for _, followerID := range followerIDs {
+		if err := s.events.SendEventToUser(strconv.FormatUint(uint64(followerID), 10), EventFriendJoinedWaitingRoom, event); err != nil {
+			log.Printf("[FollowService] Ошибка при отправке %s пользователю #%d: %v", EventFriendJoinedWaitingRoom, followerID, err)
+		}
+	}
+}
+
+// checkFollowee проверяет, что игрок существует и не удалён
+func (s *FollowService) checkFollowee(userID uint) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.DeletedAt != nil {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
+
+func normalizeFollowPage(page, pageSize int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 20
+	} else if pageSize > 100 {
+		pageSize = 100
+	}
+	return page, pageSize
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// fakeFollowRepo — UserFollowRepository в памяти
+type fakeFollowRepo struct {
+	follows     []entity.UserFollow
+	leaderboard []entity.User
+}
+
+func (f *fakeFollowRepo) Create(follow *entity.UserFollow) (bool, error) {
+	if exists, _ := f.Exists(follow.FollowerID, follow.FolloweeID); exists {
+		return false, nil
+	}
+	if follow.CreatedAt.IsZero() {
+		follow.CreatedAt = time.Now()
+	}
+	f.follows = append(f.follows, *follow)
+	return true, nil
+}
+
+func (f *fakeFollowRepo) Delete(followerID, followeeID uint) (bool, error) {
+	for i, follow := range f.follows {
+		if follow.FollowerID == followerID && follow.FolloweeID == followeeID {
+			f.follows = append(f.follows[:i], f.follows[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeFollowRepo) Exists(followerID, followeeID uint) (bool, error) {
+	for _, follow := range f.follows {
+		if follow.FollowerID == followerID && follow.FolloweeID == followeeID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeFollowRepo) CountFollowing(followerID uint) (int64, error) {
+	var count int64
+	for _, follow := range f.follows {
+		if follow.FollowerID == followerID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (f *fakeFollowRepo) CountFollowers(followeeID uint) (int64, error) {
+	ids, _ := f.ListFollowerIDs(followeeID)
+	return int64(len(ids)), nil
+}
+
+func (f *fakeFollowRepo) CountCreatedSince(followerID uint, since time.Time) (int64, error) {
+	var count int64
+	for _, follow := range f.follows {
+		if follow.FollowerID == followerID && follow.CreatedAt.After(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (f *fakeFollowRepo) ListFollowing(followerID uint, limit, offset int) ([]repository.FollowListItem, int64, error) {
+	return nil, 0, nil
+}
+
+func (f *fakeFollowRepo) ListFollowers(followeeID uint, limit, offset int) ([]repository.FollowListItem, int64, error) {
+	return nil, 0, nil
+}
+
+func (f *fakeFollowRepo) ListFollowerIDs(followeeID uint) ([]uint, error) {
+	var ids []uint
+	for _, follow := range f.follows {
+		if follow.FolloweeID == followeeID {
+			ids = append(ids, follow.FollowerID)
+		}
+	}
+	return ids, nil
+}
+
+func (f *fakeFollowRepo) GetFriendsLeaderboard(userID uint, limit, offset int) ([]entity.User, int64, error) {
+	return f.leaderboard, int64(len(f.leaderboard)), nil
+}
+
+// recordedFollowEvents запоминает отправленные WebSocket-события
+type recordedFollowEvents struct {
+	recipients []string
+	eventTypes []string
+}
+
+func (r *recordedFollowEvents) SendEventToUser(userID string, eventType string, data interface{}) error {
+	r.recipients = append(r.recipients, userID)
+	r.eventTypes = append(r.eventTypes, eventType)
+	return nil
+}
+
+func newFollowTestUsers() *MockUserRepository {
+	userRepo := new(MockUserRepository)
+	for id := uint(1); id <= 3; id++ {
+		userRepo.On("GetByID", id).Return(&entity.User{ID: id, ProfilePublic: true}, nil)
+	}
+	deletedAt := time.Now()
+	userRepo.On("GetByID", uint(4)).Return(&entity.User{ID: 4, DeletedAt: &deletedAt}, nil)
+	userRepo.On("GetByID", mock.Anything).Return(nil, apperrors.ErrNotFound)
+	return userRepo
+}
+
+func TestFollowService_FollowAndMutual(t *testing.T) {
+	repo := &fakeFollowRepo{}
+	svc := NewFollowService(repo, newFollowTestUsers())
+
+	status, err := svc.Follow(1, 2)
+	require.NoError(t, err)
+	assert.True(t, status.Following)
+	assert.False(t, status.Mutual)
+	assert.Equal(t, int64(1), status.FollowersCount)
+
+	// Повторная подписка ничего не меняет
+	_, err = svc.Follow(1, 2)
+	require.NoError(t, err)
+	assert.Len(t, repo.follows, 1)
+
+	status, err = svc.Follow(2, 1)
+	require.NoError(t, err)
+	assert.True(t, status.Mutual, "встречная подписка делает игроков друзьями")
+
+	status, err = svc.Unfollow(1, 2)
+	require.NoError(t, err)
+	assert.False(t, status.Following)
+	assert.True(t, status.FollowedBy)
+	assert.False(t, status.Mutual)
+}
+
+func TestFollowService_FollowValidation(t *testing.T) {
+	svc := NewFollowService(&fakeFollowRepo{}, newFollowTestUsers())
+
+	_, err := svc.Follow(1, 1)
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	_, err = svc.Follow(1, 4)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound, "на удалённый аккаунт подписаться нельзя")
+	_, err = svc.Follow(1, 99)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
+
+func TestFollowService_FollowRateLimit(t *testing.T) {
+	repo := &fakeFollowRepo{}
+	for i := 0; i < maxFollowsPerHour; i++ {
+		repo.follows = append(repo.follows, entity.UserFollow{FollowerID: 1, FolloweeID: uint(100 + i), CreatedAt: time.Now()})
+	}
+	svc := NewFollowService(repo, newFollowTestUsers())
+
+	_, err := svc.Follow(1, 2)
+	assert.ErrorIs(t, err, ErrFollowRateLimited)
+
+	// Подписки старше часа лимит не занимают
+	for i := range repo.follows {
+		repo.follows[i].CreatedAt = time.Now().Add(-2 * time.Hour)
+	}
+	_, err = svc.Follow(1, 2)
+	assert.NoError(t, err)
+}
+
+func TestFollowService_NotifyFriendJoined(t *testing.T) {
+	repo := &fakeFollowRepo{}
+	userRepo := newFollowTestUsers()
+	svc := NewFollowService(repo, userRepo)
+	events := &recordedFollowEvents{}
+	svc.SetEvents(events)
+	_, _ = repo.Create(&entity.UserFollow{FollowerID: 2, FolloweeID: 1})
+	_, _ = repo.Create(&entity.UserFollow{FollowerID: 3, FolloweeID: 1})
+
+	svc.NotifyFriendJoined(10, 1)
+
+	assert.ElementsMatch(t, []string{"2", "3"}, events.recipients)
+	assert.Equal(t, []string{EventFriendJoinedWaitingRoom, EventFriendJoinedWaitingRoom}, events.eventTypes)
+}
+
+func TestFollowService_NotifyFriendJoined_PrivateProfile(t *testing.T) {
+	repo := &fakeFollowRepo{}
+	userRepo := new(MockUserRepository)
+	userRepo.On("GetByID", uint(1)).Return(&entity.User{ID: 1, ProfilePublic: false}, nil)
+	svc := NewFollowService(repo, userRepo)
+	events := &recordedFollowEvents{}
+	svc.SetEvents(events)
+	_, _ = repo.Create(&entity.UserFollow{FollowerID: 2, FolloweeID: 1})
+
+	svc.NotifyFriendJoined(10, 1)
+
+	assert.Empty(t, events.recipients, "скрытый профиль не раскрывает активность подписчикам")
+}
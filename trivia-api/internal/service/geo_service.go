@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"log"
+	"net"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	"github.com/yourusername/trivia-api/internal/pkg/geo"
+)
+
+// GeoQuizReport — страны подключений к викторине для админ-панели
+type GeoQuizReport struct {
+	QuizID           uint                         `json:"quiz_id"`
+	AllowedCountries []string                     `json:"allowed_countries"`
+	AllowUnknown     bool                         `json:"allow_unknown"`
+	Countries        []repository.GeoCountryCount `json:"countries"`
+}
+
+// GeoService определяет страну клиента по IP при входе и подключении к викторине. Страны
+// подключений проверяются при подведении итогов (ResultService.SetGeoRestrictions): призы
+// викторины с allowed_countries получают только игроки из этих стран.
+type GeoService struct {
+	resolver     geo.Resolver
+	repo         repository.GeoRepository
+	quizRepo     repository.QuizRepository
+	allowUnknown bool
+	timeout      time.Duration
+}
+
+// NewGeoService создает сервис геолокации. allowUnknown — считать разрешённой страну,
+// которую определить не удалось; timeout — на определение страны одного адреса.
+func NewGeoService(
+	resolver geo.Resolver,
+	repo repository.GeoRepository,
+	quizRepo repository.QuizRepository,
+	allowUnknown bool,
+	timeout time.Duration,
+) *GeoService {
+	return &GeoService{
+		resolver:     resolver,
+		repo:         repo,
+		quizRepo:     quizRepo,
+		allowUnknown: allowUnknown,
+		timeout:      timeout,
+	}
+}
+
+// Resolve возвращает страну адреса; пустая строка — адрес частный или страна не определена
+func (s *GeoService) Resolve(ipAddress string) string {
+	ip := net.ParseIP(ipAddress)
+	if !geo.IsPublic(ip) {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	country, err := s.resolver.Country(ctx, ip)
+	if err != nil {
+		log.Printf("[Geo] Не удалось определить страну %s (%s): %v", ipAddress, s.resolver.Name(), err)
+		return ""
+	}
+	return country
+}
+
+// RecordLogin записывает страну входа пользователя. Вызывается в фоне: вход не ждёт определения страны.
+func (s *GeoService) RecordLogin(userID uint, ipAddress string) {
+	if err := s.repo.UpdateLoginCountry(userID, s.Resolve(ipAddress)); err != nil {
+		log.Printf("[Geo] Не удалось записать страну входа пользователя %d: %v", userID, err)
+	}
+}
+
+// RecordQuizJoin записывает страну подключения к викторине. Игрок из страны вне allowed_countries
+// играет как обычно, но не получает приз; попытка видна в ListBlocked. Вызывается в фоне.
+func (s *GeoService) RecordQuizJoin(userID, quizID uint, ipAddress string) {
+	country := s.Resolve(ipAddress)
+	now := time.Now()
+	if err := s.repo.RecordQuizJoin(&entity.QuizGeoCheck{
+		QuizID:      quizID,
+		UserID:      userID,
+		Country:     country,
+		IPAddress:   ipAddress,
+		Attempts:    1,
+		FirstSeenAt: now,
+		LastSeenAt:  now,
+	}); err != nil {
+		log.Printf("[Geo] Не удалось записать страну подключения пользователя %d к викторине %d: %v", userID, quizID, err)
+		return
+	}
+	quiz, err := s.quizRepo.GetByID(quizID)
+	if err != nil {
+		return
+	}
+	if !s.countryAllowed(quiz.AllowedCountries, country) {
+		log.Printf("[Geo] Пользователь %d подключился к викторине %d из региона %q вне разрешённых %v: приз не будет выдан",
+			userID, quizID, country, []string(quiz.AllowedCountries))
+	}
+}
+
+// countryAllowed сообщает, получает ли игрок из страны приз викторины с таким списком стран
+func (s *GeoService) countryAllowed(allowed []string, country string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	if country == "" {
+		return s.allowUnknown
+	}
+	for _, code := range allowed {
+		if code == country {
+			return true
+		}
+	}
+	return false
+}
+
+// ListBlocked возвращает подключения из стран вне списка разрешённых (новые первыми); quizID = 0 — все викторины
+func (s *GeoService) ListBlocked(quizID uint, page, pageSize int) ([]repository.GeoBlockedAttempt, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return s.repo.ListBlocked(quizID, s.allowUnknown, pageSize, (page-1)*pageSize)
+}
+
+// QuizReport возвращает число игроков викторины по странам подключения
+func (s *GeoService) QuizReport(quizID uint) (*GeoQuizReport, error) {
+	quiz, err := s.quizRepo.GetByID(quizID)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.repo.CountByCountry(quizID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range counts {
+		counts[i].Allowed = s.countryAllowed(quiz.AllowedCountries, counts[i].Country)
+	}
+	allowed := []string(quiz.AllowedCountries)
+	if allowed == nil {
+		allowed = []string{}
+	}
+	if counts == nil {
+		counts = []repository.GeoCountryCount{}
+	}
+	return &GeoQuizReport{QuizID: quizID, AllowedCountries: allowed, AllowUnknown: s.allowUnknown, Countries: counts}, nil
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+)
+
+// staticGeoResolver возвращает страну по заранее заданной таблице адресов
+type staticGeoResolver map[string]string
+
+func (r staticGeoResolver) Name() string { return "static" }
+
+func (r staticGeoResolver) Country(_ context.Context, ip net.IP) (string, error) {
+	return r[ip.String()], nil
+}
+
+type fakeGeoRepo struct {
+	checks         []entity.QuizGeoCheck
+	loginCountry   map[uint]string
+	blockedUnknown bool
+}
+
+func (r *fakeGeoRepo) RecordQuizJoin(check *entity.QuizGeoCheck) error {
+	for i := range r.checks {
+		c := &r.checks[i]
+		if c.QuizID == check.QuizID && c.UserID == check.UserID && c.Country == check.Country {
+			c.Attempts++
+			c.IPAddress = check.IPAddress
+			c.LastSeenAt = check.LastSeenAt
+			return nil
+		}
+	}
+	r.checks = append(r.checks, *check)
+	return nil
+}
+
+func (r *fakeGeoRepo) UpdateLoginCountry(userID uint, country string) error {
+	if r.loginCountry == nil {
+		r.loginCountry = make(map[uint]string)
+	}
+	r.loginCountry[userID] = country
+	return nil
+}
+
+func (r *fakeGeoRepo) ListBlocked(quizID uint, allowUnknown bool, limit, offset int) ([]repository.GeoBlockedAttempt, int64, error) {
+	r.blockedUnknown = allowUnknown
+	return nil, 0, nil
+}
+
+func (r *fakeGeoRepo) CountByCountry(quizID uint) ([]repository.GeoCountryCount, error) {
+	players := make(map[string]int64)
+	var counts []repository.GeoCountryCount
+	for _, c := range r.checks {
+		if c.QuizID != quizID {
+			continue
+		}
+		if _, ok := players[c.Country]; !ok {
+			counts = append(counts, repository.GeoCountryCount{Country: c.Country})
+		}
+		players[c.Country]++
+	}
+	for i := range counts {
+		counts[i].Players = players[counts[i].Country]
+	}
+	return counts, nil
+}
+
+func newTestGeoService(allowUnknown bool, quizRepo *MockQuizRepository) (*GeoService, *fakeGeoRepo) {
+	resolver := staticGeoResolver{"5.34.0.1": "KZ", "95.0.0.1": "TR"}
+	repo := &fakeGeoRepo{}
+	return NewGeoService(resolver, repo, quizRepo, allowUnknown, time.Second), repo
+}
+
+func TestGeoService_Resolve(t *testing.T) {
+	svc, _ := newTestGeoService(false, nil)
+
+	assert.Equal(t, "KZ", svc.Resolve("5.34.0.1"))
+	assert.Equal(t, "", svc.Resolve("8.8.8.8"), "адреса нет в базе")
+	assert.Equal(t, "", svc.Resolve("192.168.1.10"), "частные адреса не определяются")
+	assert.Equal(t, "", svc.Resolve("not-an-ip"))
+}
+
+func TestGeoService_RecordQuizJoin(t *testing.T) {
+	quizRepo := new(MockQuizRepository)
+	quizRepo.On("GetByID", uint(1)).Return(&entity.Quiz{ID: 1, AllowedCountries: entity.StringArray{"KZ"}}, nil)
+	svc, repo := newTestGeoService(false, quizRepo)
+
+	svc.RecordQuizJoin(10, 1, "5.34.0.1")
+	svc.RecordQuizJoin(10, 1, "5.34.0.1")
+	svc.RecordQuizJoin(11, 1, "95.0.0.1")
+
+	require.Len(t, repo.checks, 2, "повторное подключение из той же страны — одна запись")
+	assert.Equal(t, "KZ", repo.checks[0].Country)
+	assert.Equal(t, 2, repo.checks[0].Attempts)
+	assert.Equal(t, "TR", repo.checks[1].Country)
+
+	svc.RecordLogin(10, "95.0.0.1")
+	assert.Equal(t, "TR", repo.loginCountry[10])
+}
+
+func TestGeoService_QuizReport(t *testing.T) {
+	quizRepo := new(MockQuizRepository)
+	quizRepo.On("GetByID", uint(1)).Return(&entity.Quiz{ID: 1, AllowedCountries: entity.StringArray{"KZ"}}, nil)
+	quizRepo.On("GetByID", uint(2)).Return(&entity.Quiz{ID: 2}, nil)
+
+	for _, allowUnknown := range []bool{false, true} {
+		svc, repo := newTestGeoService(allowUnknown, quizRepo)
+		svc.RecordQuizJoin(10, 1, "5.34.0.1")
+		svc.RecordQuizJoin(11, 1, "95.0.0.1")
+		svc.RecordQuizJoin(12, 1, "8.8.8.8")
+		svc.RecordQuizJoin(10, 2, "95.0.0.1")
+
+		report, err := svc.QuizReport(1)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"KZ"}, report.AllowedCountries)
+		allowed := make(map[string]bool)
+		for _, c := range report.Countries {
+			allowed[c.Country] = c.Allowed
+			assert.EqualValues(t, 1, c.Players)
+		}
+		assert.Equal(t, map[string]bool{"KZ": true, "TR": false, "": allowUnknown}, allowed)
+
+		// Без списка стран приз получают игроки из любой страны
+		report, err = svc.QuizReport(2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{}, report.AllowedCountries)
+		require.Len(t, report.Countries, 1)
+		assert.True(t, report.Countries[0].Allowed)
+
+		_, _, err = svc.ListBlocked(0, 0, 0)
+		require.NoError(t, err)
+		assert.Equal(t, allowUnknown, repo.blockedUnknown)
+	}
+}
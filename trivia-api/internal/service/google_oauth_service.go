@@ -61,6 +61,8 @@ type GoogleOAuthService struct {
 	jwksMu       sync.RWMutex
 	jwksKeys     map[string]*rsa.PublicKey
 	jwksExpiry   time.Time
+	eventBus     *EventBus
+	loginPolicy  func(user *entity.User) error
 }
 
 func NewGoogleOAuthService(
@@ -87,6 +89,17 @@ func NewGoogleOAuthService(
 	}, nil
 }
 
+// SetEventBus включает событие user.registered при регистрации через Google
+func (s *GoogleOAuthService) SetEventBus(bus *EventBus) {
+	s.eventBus = bus
+}
+
+// SetLoginPolicy задаёт проверку, разрешён ли существующему пользователю вход через Google
+// (например, администратору, для которого обязателен ключ доступа)
+func (s *GoogleOAuthService) SetLoginPolicy(policy func(user *entity.User) error) {
+	s.loginPolicy = policy
+}
+
 func (s *GoogleOAuthService) Exchange(ctx context.Context, input GoogleExchangeInput) (*GoogleAuthResult, error) {
 	idToken := strings.TrimSpace(input.IDToken)
 	if idToken == "" {
@@ -111,7 +124,12 @@ func (s *GoogleOAuthService) Exchange(ctx context.Context, input GoogleExchangeI
 		if userErr != nil {
 			return nil, userErr
 		}
-		tokenResp, tokenErr := s.tokenManager.GenerateTokenPair(user.ID, input.DeviceID, input.IPAddress, input.UserAgent)
+		if s.loginPolicy != nil {
+			if policyErr := s.loginPolicy(user); policyErr != nil {
+				return nil, policyErr
+			}
+		}
+		tokenResp, tokenErr := s.tokenManager.GenerateTokenPairForClient(manager.ClientFromContext(ctx), user.ID, input.DeviceID, input.IPAddress, input.UserAgent)
 		if tokenErr != nil {
 			return nil, tokenErr
 		}
@@ -160,7 +178,7 @@ func (s *GoogleOAuthService) Exchange(ctx context.Context, input GoogleExchangeI
 		user.ProfileCompletedAt = &now
 	}
 
-	if err := s.userRepo.Create(user); err != nil {
+	if err := createUser(s.userRepo, s.eventBus, user, "google"); err != nil {
 		return nil, fmt.Errorf("failed to create user from google auth: %w", err)
 	}
 
@@ -175,7 +193,7 @@ func (s *GoogleOAuthService) Exchange(ctx context.Context, input GoogleExchangeI
 		return nil, fmt.Errorf("failed to create google identity: %w", err)
 	}
 
-	tokenResp, err := s.tokenManager.GenerateTokenPair(user.ID, input.DeviceID, input.IPAddress, input.UserAgent)
+	tokenResp, err := s.tokenManager.GenerateTokenPairForClient(manager.ClientFromContext(ctx), user.ID, input.DeviceID, input.IPAddress, input.UserAgent)
 	if err != nil {
 		return nil, err
 	}
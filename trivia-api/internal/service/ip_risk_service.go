@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/pkg/geo"
+	"github.com/yourusername/trivia-api/internal/pkg/iprisk"
+)
+
+// IPRiskService оценивает адреса подключений к викторинам (VPN, прокси, дата-центры, Tor) и ведёт
+// проверки победителей с рискованными подключениями. Сами проверки создаются при подведении итогов
+// (ResultService.SetIPRiskPolicy); здесь администратор их одобряет или отклоняет.
+type IPRiskService struct {
+	scorer            *iprisk.Scorer
+	repo              repository.IPRiskRepository
+	quizRepo          repository.QuizRepository
+	walletService     *WalletService
+	prizeClaimService *PrizeClaimService
+	threshold         int
+	timeout           time.Duration
+}
+
+// NewIPRiskService создает сервис оценки адресов. threshold — оценка (1–100), с которой
+// подключение считается рискованным; timeout — на оценку одного адреса.
+func NewIPRiskService(
+	scorer *iprisk.Scorer,
+	repo repository.IPRiskRepository,
+	quizRepo repository.QuizRepository,
+	threshold int,
+	timeout time.Duration,
+) *IPRiskService {
+	return &IPRiskService{
+		scorer:    scorer,
+		repo:      repo,
+		quizRepo:  quizRepo,
+		threshold: threshold,
+		timeout:   timeout,
+	}
+}
+
+// SetWalletService подключает зачисление одобренных задержанных призов в кошелёк
+func (s *IPRiskService) SetWalletService(svc *WalletService) {
+	s.walletService = svc
+}
+
+// SetPrizeClaimService подключает заявки на призы: одобренный задержанный приз выплачивается через заявку
+func (s *IPRiskService) SetPrizeClaimService(svc *PrizeClaimService) {
+	s.prizeClaimService = svc
+}
+
+// Assess оценивает адрес; частные и локальные адреса не оцениваются. При ошибке провайдера
+// используется оценка по локальным спискам.
+func (s *IPRiskService) Assess(ipAddress string) iprisk.Assessment {
+	ip := net.ParseIP(ipAddress)
+	if !geo.IsPublic(ip) {
+		return iprisk.Assessment{}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	assessment, err := s.scorer.Score(ctx, ip)
+	if err != nil {
+		log.Printf("[IPRisk] Провайдер %s не оценил адрес %s, используется оценка по спискам: %v", s.scorer.ProviderName(), ipAddress, err)
+	}
+	return assessment
+}
+
+// RecordQuizJoin оценивает адрес подключения к викторине и сохраняет оценку. Вызывается в фоне.
+func (s *IPRiskService) RecordQuizJoin(userID, quizID uint, ipAddress string) {
+	if ipAddress == "" {
+		return
+	}
+	assessment := s.Assess(ipAddress)
+	reasons := entity.StringArray(assessment.Reasons)
+	if reasons == nil {
+		reasons = entity.StringArray{}
+	}
+	now := time.Now()
+	if err := s.repo.RecordSession(&entity.QuizSessionRisk{
+		QuizID:      quizID,
+		UserID:      userID,
+		IPAddress:   ipAddress,
+		Score:       assessment.Score,
+		Reasons:     reasons,
+		Attempts:    1,
+		FirstSeenAt: now,
+		LastSeenAt:  now,
+	}); err != nil {
+		log.Printf("[IPRisk] Не удалось записать оценку подключения пользователя %d к викторине %d: %v", userID, quizID, err)
+		return
+	}
+	if assessment.Score >= s.threshold {
+		log.Printf("[IPRisk] Пользователь %d подключился к викторине %d с рискованного адреса %s (оценка %d, %s)",
+			userID, quizID, ipAddress, assessment.Score, strings.Join(assessment.Reasons, ", "))
+	}
+}
+
+// ListSessions возвращает рискованные подключения (новые первыми); minScore = 0 — порог сервиса
+func (s *IPRiskService) ListSessions(quizID uint, minScore, page, pageSize int) ([]repository.RiskySession, int64, error) {
+	if minScore <= 0 {
+		minScore = s.threshold
+	}
+	if minScore > 100 {
+		return nil, 0, fmt.Errorf("%w: min_score must be between 0 and 100", apperrors.ErrValidation)
+	}
+	page, pageSize = normalizeWalletPage(page, pageSize)
+	return s.repo.ListSessions(quizID, minScore, pageSize, (page-1)*pageSize)
+}
+
+// ListReviews возвращает проверки победителей, старые первыми
+func (s *IPRiskService) ListReviews(status string, quizID uint, page, pageSize int) ([]entity.PrizeRiskReview, int64, error) {
+	switch status {
+	case "", entity.PrizeRiskReviewPending, entity.PrizeRiskReviewApproved, entity.PrizeRiskReviewRejected:
+	default:
+		return nil, 0, fmt.Errorf("%w: unknown review status %q", apperrors.ErrValidation, status)
+	}
+	page, pageSize = normalizeWalletPage(page, pageSize)
+	return s.repo.ListReviews(status, quizID, pageSize, (page-1)*pageSize)
+}
+
+// ApproveReview признаёт победу честной. Задержанный приз (действие exclude) выплачивается
+// так же, как при подведении итогов: через заявку на приз или зачислением в кошелёк.
+func (s *IPRiskService) ApproveReview(reviewID, adminID uint, note string) (*entity.PrizeRiskReview, error) {
+	review, err := s.resolve(reviewID, adminID, note, entity.PrizeRiskReviewApproved)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.ResolveReview(review); err != nil {
+		return nil, err
+	}
+	if review.Action == entity.PrizeRiskActionExclude && review.Amount > 0 {
+		s.payOut(review)
+	}
+	log.Printf("[IPRisk] Проверка #%d одобрена администратором %d: пользователь %d, викторина %d", review.ID, adminID, review.UserID, review.QuizID)
+	return review, nil
+}
+
+// RejectReview отклоняет победу. Задержанный приз (действие exclude) снимается: результат
+// перестаёт быть победным, статистика побед уменьшается. Уже выплаченный приз (flag) не возвращается.
+func (s *IPRiskService) RejectReview(reviewID, adminID uint, note string) (*entity.PrizeRiskReview, error) {
+	review, err := s.resolve(reviewID, adminID, note, entity.PrizeRiskReviewRejected)
+	if err != nil {
+		return nil, err
+	}
+	if review.Action == entity.PrizeRiskActionExclude {
+		err = s.repo.ForfeitPrize(review)
+	} else {
+		err = s.repo.ResolveReview(review)
+	}
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[IPRisk] Проверка #%d отклонена администратором %d: пользователь %d, викторина %d", review.ID, adminID, review.UserID, review.QuizID)
+	return review, nil
+}
+
+func (s *IPRiskService) resolve(reviewID, adminID uint, note, status string) (*entity.PrizeRiskReview, error) {
+	note = strings.TrimSpace(note)
+	if len([]rune(note)) > 500 {
+		return nil, fmt.Errorf("%w: note must be at most 500 characters", apperrors.ErrValidation)
+	}
+	review, err := s.repo.GetReview(reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.Status != entity.PrizeRiskReviewPending {
+		return nil, fmt.Errorf("%w: prize risk review is already resolved", apperrors.ErrConflict)
+	}
+	now := time.Now()
+	review.Status = status
+	review.ReviewedBy = &adminID
+	review.ReviewedAt = &now
+	review.Note = note
+	return review, nil
+}
+
+// payOut выплачивает одобренный задержанный приз (идемпотентно по викторине и пользователю)
+func (s *IPRiskService) payOut(review *entity.PrizeRiskReview) {
+	winner := []uint{review.UserID}
+	if s.prizeClaimService != nil {
+		title := ""
+		if quiz, err := s.quizRepo.GetByID(review.QuizID); err == nil {
+			title = quiz.Title
+		}
+		s.prizeClaimService.CreateForWinners(review.QuizID, title, winner, review.Amount)
+	} else if s.walletService != nil {
+		s.walletService.CreditPrizes(review.QuizID, winner, review.Amount)
+	}
+}
+
+// StartListReload перечитывает локальные списки из dir каждые interval (списки Tor и VPN
+// устаревают за часы). Ошибка чтения оставляет прежние списки.
+func (s *IPRiskService) StartListReload(ctx context.Context, dir string, interval time.Duration) {
+	if dir == "" || interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				lists, err := iprisk.LoadRangeLists(dir)
+				if err != nil {
+					log.Printf("[IPRisk] Не удалось перечитать списки адресов: %v", err)
+					continue
+				}
+				s.scorer.SetLists(lists)
+			}
+		}
+	}()
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/pkg/iprisk"
+)
+
+type fakeIPRiskRepo struct {
+	sessions  []entity.QuizSessionRisk
+	reviews   []entity.PrizeRiskReview
+	forfeited []uint
+}
+
+func (r *fakeIPRiskRepo) RecordSession(session *entity.QuizSessionRisk) error {
+	for i := range r.sessions {
+		s := &r.sessions[i]
+		if s.QuizID == session.QuizID && s.UserID == session.UserID && s.IPAddress == session.IPAddress {
+			s.Attempts++
+			s.Score = session.Score
+			return nil
+		}
+	}
+	r.sessions = append(r.sessions, *session)
+	return nil
+}
+
+func (r *fakeIPRiskRepo) ListSessions(quizID uint, minScore, limit, offset int) ([]repository.RiskySession, int64, error) {
+	var result []repository.RiskySession
+	for _, s := range r.sessions {
+		if s.Score >= minScore && (quizID == 0 || s.QuizID == quizID) {
+			result = append(result, repository.RiskySession{QuizID: s.QuizID, UserID: s.UserID, IPAddress: s.IPAddress, Score: s.Score})
+		}
+	}
+	return result, int64(len(result)), nil
+}
+
+func (r *fakeIPRiskRepo) ListReviews(status string, quizID uint, limit, offset int) ([]entity.PrizeRiskReview, int64, error) {
+	return r.reviews, int64(len(r.reviews)), nil
+}
+
+func (r *fakeIPRiskRepo) GetReview(id uint) (*entity.PrizeRiskReview, error) {
+	if id == 0 || int(id) > len(r.reviews) {
+		return nil, apperrors.ErrNotFound
+	}
+	review := r.reviews[id-1]
+	return &review, nil
+}
+
+func (r *fakeIPRiskRepo) ResolveReview(review *entity.PrizeRiskReview) error {
+	stored := &r.reviews[review.ID-1]
+	if stored.Status != entity.PrizeRiskReviewPending {
+		return apperrors.ErrConflict
+	}
+	*stored = *review
+	return nil
+}
+
+func (r *fakeIPRiskRepo) ForfeitPrize(review *entity.PrizeRiskReview) error {
+	if err := r.ResolveReview(review); err != nil {
+		return err
+	}
+	r.forfeited = append(r.forfeited, review.UserID)
+	return nil
+}
+
+func newTestIPRiskService(t *testing.T) (*IPRiskService, *fakeIPRiskRepo) {
+	t.Helper()
+	lists, err := iprisk.LoadRangeLists("")
+	require.NoError(t, err)
+	repo := &fakeIPRiskRepo{}
+	return NewIPRiskService(iprisk.NewScorer(lists, nil, 0), repo, nil, 70, time.Second), repo
+}
+
+func TestIPRiskService_RecordQuizJoin(t *testing.T) {
+	svc, repo := newTestIPRiskService(t)
+
+	svc.RecordQuizJoin(10, 1, "3.120.0.1") // AWS
+	svc.RecordQuizJoin(10, 1, "3.120.0.1")
+	svc.RecordQuizJoin(11, 1, "5.34.0.1")
+	svc.RecordQuizJoin(12, 1, "192.168.1.10")
+	svc.RecordQuizJoin(13, 1, "")
+
+	require.Len(t, repo.sessions, 3, "без адреса подключение не записывается")
+	assert.Equal(t, 70, repo.sessions[0].Score)
+	assert.Equal(t, entity.StringArray{"list:datacenter"}, repo.sessions[0].Reasons)
+	assert.Equal(t, 2, repo.sessions[0].Attempts)
+	assert.Equal(t, 0, repo.sessions[1].Score)
+	assert.Equal(t, entity.StringArray{}, repo.sessions[2].Reasons, "частный адрес не оценивается")
+
+	sessions, total, err := svc.ListSessions(0, 0, 1, 20)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, total, "по умолчанию — подключения не ниже порога")
+	assert.Equal(t, uint(10), sessions[0].UserID)
+}
+
+func TestIPRiskService_ResolveReviews(t *testing.T) {
+	svc, repo := newTestIPRiskService(t)
+	repo.reviews = []entity.PrizeRiskReview{
+		{ID: 1, QuizID: 1, UserID: 10, Amount: 500, Action: entity.PrizeRiskActionExclude, Status: entity.PrizeRiskReviewPending},
+		{ID: 2, QuizID: 1, UserID: 11, Amount: 500, Action: entity.PrizeRiskActionFlag, Status: entity.PrizeRiskReviewPending},
+		{ID: 3, QuizID: 1, UserID: 12, Amount: 500, Action: entity.PrizeRiskActionExclude, Status: entity.PrizeRiskReviewPending},
+	}
+
+	// Отказ по задержанному призу снимает приз, по отмеченному — только фиксирует решение
+	review, err := svc.RejectReview(1, 99, " VPN в Нидерландах ")
+	require.NoError(t, err)
+	assert.Equal(t, entity.PrizeRiskReviewRejected, review.Status)
+	assert.Equal(t, "VPN в Нидерландах", review.Note)
+	require.NotNil(t, review.ReviewedBy)
+	assert.Equal(t, uint(99), *review.ReviewedBy)
+
+	_, err = svc.RejectReview(2, 99, "")
+	require.NoError(t, err)
+	assert.Equal(t, []uint{10}, repo.forfeited)
+
+	review, err = svc.ApproveReview(3, 99, "")
+	require.NoError(t, err)
+	assert.Equal(t, entity.PrizeRiskReviewApproved, repo.reviews[2].Status)
+	assert.Equal(t, uint(12), review.UserID)
+
+	_, err = svc.ApproveReview(1, 99, "")
+	assert.ErrorIs(t, err, apperrors.ErrConflict, "решение уже принято")
+	_, err = svc.ApproveReview(7, 99, "")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	_, _, err = svc.ListReviews("unknown", 0, 1, 20)
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+}
+
+func TestWithoutUsers(t *testing.T) {
+	assert.Equal(t, []uint{1, 3}, withoutUsers([]uint{1, 2, 3}, map[uint]bool{2: true}))
+	assert.Equal(t, []uint{1, 2}, withoutUsers([]uint{1, 2}, nil))
+	assert.Empty(t, withoutUsers([]uint{2}, map[uint]bool{2: true}))
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// LegalVersions — текущие версии пользовательского соглашения и политики конфиденциальности
+type LegalVersions struct {
+	TOSVersion     string `json:"tos_version"`
+	PrivacyVersion string `json:"privacy_version"`
+}
+
+// LegalAcceptInput — повторное принятие документов пользователем
+type LegalAcceptInput struct {
+	UserID uint
+	// Версии, которые пользователь видел; должны совпадать с текущими
+	TOSVersion     string
+	PrivacyVersion string
+	// nil — сохранить прежнее согласие на рассылку
+	MarketingOptIn *bool
+	IP             string
+	UserAgent      string
+}
+
+// LegalVersionCoverage — число пользователей, чьё последнее согласие дано на эту пару версий
+type LegalVersionCoverage struct {
+	TOSVersion     string `json:"tos_version"`
+	PrivacyVersion string `json:"privacy_version"`
+	Users          int64  `json:"users"`
+	Current        bool   `json:"current"`
+}
+
+// LegalCoverageReport — охват текущих версий документов среди пользователей
+type LegalCoverageReport struct {
+	Current         LegalVersions          `json:"current"`
+	TotalUsers      int64                  `json:"total_users"`
+	AcceptedCurrent int64                  `json:"accepted_current"`
+	Outdated        int64                  `json:"outdated"`       // приняли прежние версии
+	NeverAccepted   int64                  `json:"never_accepted"` // нет ни одного согласия (вход через Google, Apple, ссылку)
+	CoveragePercent float64                `json:"coverage_percent"`
+	ByVersion       []LegalVersionCoverage `json:"by_version"`
+}
+
+// LegalService проверяет, что пользователь принял текущие версии документов, и записывает
+// повторное принятие после их обновления (legal.tosVersion, legal.privacyVersion).
+type LegalService struct {
+	repo     repository.UserLegalAcceptanceRepository
+	versions LegalVersions
+
+	// Пользователи, уже принявшие текущие версии: версии меняются только с перезапуском,
+	// поэтому положительный ответ можно не перепроверять
+	accepted sync.Map // uint -> struct{}
+}
+
+// NewLegalService создает сервис согласий с текущими версиями документов
+func NewLegalService(repo repository.UserLegalAcceptanceRepository, versions LegalVersions) *LegalService {
+	return &LegalService{repo: repo, versions: versions}
+}
+
+// CurrentVersions возвращает текущие версии документов
+func (s *LegalService) CurrentVersions() LegalVersions {
+	return s.versions
+}
+
+// AcceptanceRequired сообщает, что пользователь ещё не принял текущие версии документов
+func (s *LegalService) AcceptanceRequired(userID uint) (bool, error) {
+	if _, ok := s.accepted.Load(userID); ok {
+		return false, nil
+	}
+	latest, err := s.repo.GetLatestByUserID(userID)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if !s.isCurrent(latest.TOSVersion, latest.PrivacyVersion) {
+		return true, nil
+	}
+	s.accepted.Store(userID, struct{}{})
+	return false, nil
+}
+
+// Accept записывает принятие текущих версий. Если пользователь видел другие версии
+// (документы обновились, пока он читал), возвращает ErrConflict.
+func (s *LegalService) Accept(input LegalAcceptInput) (*entity.UserLegalAcceptance, error) {
+	if !s.isCurrent(input.TOSVersion, input.PrivacyVersion) {
+		return nil, fmt.Errorf("%w: current legal versions are tos %s, privacy %s", apperrors.ErrConflict, s.versions.TOSVersion, s.versions.PrivacyVersion)
+	}
+
+	marketingOptIn := false
+	if input.MarketingOptIn != nil {
+		marketingOptIn = *input.MarketingOptIn
+	} else if latest, err := s.repo.GetLatestByUserID(input.UserID); err == nil {
+		marketingOptIn = latest.MarketingOptIn
+	} else if !errors.Is(err, apperrors.ErrNotFound) {
+		return nil, err
+	}
+
+	acceptance := &entity.UserLegalAcceptance{
+		UserID:         input.UserID,
+		TOSVersion:     s.versions.TOSVersion,
+		PrivacyVersion: s.versions.PrivacyVersion,
+		MarketingOptIn: marketingOptIn,
+		AcceptedAt:     time.Now(),
+		IP:             input.IP,
+		UserAgent:      input.UserAgent,
+	}
+	if err := s.repo.Create(acceptance); err != nil {
+		return nil, err
+	}
+	s.accepted.Store(input.UserID, struct{}{})
+	return acceptance, nil
+}
+
+// Coverage считает, сколько пользователей приняли текущие версии документов
+func (s *LegalService) Coverage() (*LegalCoverageReport, error) {
+	counts, err := s.repo.CountLatestByVersion()
+	if err != nil {
+		return nil, err
+	}
+	total, err := s.repo.CountUsers()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &LegalCoverageReport{Current: s.versions, TotalUsers: total, ByVersion: make([]LegalVersionCoverage, 0, len(counts))}
+	var withAcceptance int64
+	for _, count := range counts {
+		current := s.isCurrent(count.TOSVersion, count.PrivacyVersion)
+		report.ByVersion = append(report.ByVersion, LegalVersionCoverage{
+			TOSVersion:     count.TOSVersion,
+			PrivacyVersion: count.PrivacyVersion,
+			Users:          count.Users,
+			Current:        current,
+		})
+		withAcceptance += count.Users
+		if current {
+			report.AcceptedCurrent += count.Users
+		} else {
+			report.Outdated += count.Users
+		}
+	}
+	if total > withAcceptance {
+		report.NeverAccepted = total - withAcceptance
+	}
+	if total > 0 {
+		report.CoveragePercent = float64(report.AcceptedCurrent) * 100 / float64(total)
+	}
+	return report, nil
+}
+
+func (s *LegalService) isCurrent(tosVersion, privacyVersion string) bool {
+	return tosVersion == s.versions.TOSVersion && privacyVersion == s.versions.PrivacyVersion
+}
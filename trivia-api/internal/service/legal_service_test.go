@@ -0,0 +1,102 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+type memoryLegalRepo struct {
+	acceptances []*entity.UserLegalAcceptance
+	counts      []repository.LegalVersionCount
+	users       int64
+	reads       int
+}
+
+func (r *memoryLegalRepo) Create(acceptance *entity.UserLegalAcceptance) error {
+	r.acceptances = append(r.acceptances, acceptance)
+	return nil
+}
+
+func (r *memoryLegalRepo) GetLatestByUserID(userID uint) (*entity.UserLegalAcceptance, error) {
+	r.reads++
+	for i := len(r.acceptances) - 1; i >= 0; i-- {
+		if r.acceptances[i].UserID == userID {
+			return r.acceptances[i], nil
+		}
+	}
+	return nil, apperrors.ErrNotFound
+}
+
+func (r *memoryLegalRepo) GetAllByUserID(userID uint) ([]*entity.UserLegalAcceptance, error) {
+	return nil, nil
+}
+
+func (r *memoryLegalRepo) DeleteByUserID(userID uint) error { return nil }
+
+func (r *memoryLegalRepo) CountLatestByVersion() ([]repository.LegalVersionCount, error) {
+	return r.counts, nil
+}
+
+func (r *memoryLegalRepo) CountUsers() (int64, error) { return r.users, nil }
+
+func TestLegalService_ReacceptanceAfterVersionBump(t *testing.T) {
+	repo := &memoryLegalRepo{acceptances: []*entity.UserLegalAcceptance{
+		{UserID: 1, TOSVersion: "1.0", PrivacyVersion: "1.0", MarketingOptIn: true},
+	}}
+	svc := NewLegalService(repo, LegalVersions{TOSVersion: "2.0", PrivacyVersion: "1.0"})
+
+	required, err := svc.AcceptanceRequired(1)
+	require.NoError(t, err)
+	assert.True(t, required, "принята прежняя версия соглашения")
+	required, err = svc.AcceptanceRequired(2)
+	require.NoError(t, err)
+	assert.True(t, required, "согласий нет")
+
+	// Клиент показал устаревшую версию
+	_, err = svc.Accept(LegalAcceptInput{UserID: 1, TOSVersion: "1.0", PrivacyVersion: "1.0"})
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+
+	acceptance, err := svc.Accept(LegalAcceptInput{UserID: 1, TOSVersion: "2.0", PrivacyVersion: "1.0", IP: "10.0.0.1"})
+	require.NoError(t, err)
+	assert.True(t, acceptance.MarketingOptIn, "без marketing_opt_in сохраняется прежнее согласие")
+	assert.Equal(t, "10.0.0.1", acceptance.IP)
+
+	reads := repo.reads
+	required, err = svc.AcceptanceRequired(1)
+	require.NoError(t, err)
+	assert.False(t, required)
+	assert.Equal(t, reads, repo.reads, "принятие запоминается без повторного чтения")
+
+	optOut := false
+	acceptance, err = svc.Accept(LegalAcceptInput{UserID: 2, TOSVersion: "2.0", PrivacyVersion: "1.0", MarketingOptIn: &optOut})
+	require.NoError(t, err)
+	assert.False(t, acceptance.MarketingOptIn)
+}
+
+func TestLegalService_Coverage(t *testing.T) {
+	repo := &memoryLegalRepo{
+		counts: []repository.LegalVersionCount{
+			{TOSVersion: "2.0", PrivacyVersion: "1.0", Users: 30},
+			{TOSVersion: "1.0", PrivacyVersion: "1.0", Users: 50},
+		},
+		users: 100,
+	}
+	svc := NewLegalService(repo, LegalVersions{TOSVersion: "2.0", PrivacyVersion: "1.0"})
+
+	report, err := svc.Coverage()
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), report.TotalUsers)
+	assert.Equal(t, int64(30), report.AcceptedCurrent)
+	assert.Equal(t, int64(50), report.Outdated)
+	assert.Equal(t, int64(20), report.NeverAccepted)
+	assert.InDelta(t, 30.0, report.CoveragePercent, 0.001)
+	require.Len(t, report.ByVersion, 2)
+	assert.True(t, report.ByVersion[0].Current)
+	assert.False(t, report.ByVersion[1].Current)
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/config"
+)
+
+// Провайдеры языковых моделей
+const (
+	LLMProviderOpenAI    = "openai"
+	LLMProviderAnthropic = "anthropic"
+)
+
+// LLMRequest — запрос к языковой модели
+type LLMRequest struct {
+	System    string // инструкции модели
+	Prompt    string // сообщение пользователя
+	MaxTokens int    // предел токенов ответа
+}
+
+// LLMResponse — ответ модели и израсходованные токены
+type LLMResponse struct {
+	Text             string
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// LLMProvider обращается к языковой модели конкретного провайдера
+type LLMProvider interface {
+	Name() string
+	Model() string
+	// Complete возвращает ответ модели. Ответ с непустым числом токенов может прийти вместе
+	// с ошибкой (например, модель упёрлась в MaxTokens) — токены всё равно оплачены.
+	Complete(ctx context.Context, req LLMRequest) (*LLMResponse, error)
+}
+
+// NewLLMProviderFromConfig создает провайдера по секции aiQuestions
+func NewLLMProviderFromConfig(cfg config.AIQuestionsConfig) (LLMProvider, error) {
+	timeout := time.Duration(cfg.TimeoutSec) * time.Second
+	switch cfg.Provider {
+	case LLMProviderOpenAI:
+		return NewOpenAILLMProvider(cfg.BaseURL, cfg.APIKey, cfg.Model, timeout)
+	case LLMProviderAnthropic:
+		return NewAnthropicLLMProvider(cfg.BaseURL, cfg.APIKey, cfg.Model, timeout)
+	default:
+		return nil, fmt.Errorf("unknown llm provider %q", cfg.Provider)
+	}
+}
+
+// postLLMJSON отправляет JSON-запрос к API модели и разбирает ответ в out. Текст ошибки
+// провайдера ({"error": {"message": ...}}) попадает в возвращаемую ошибку.
+func postLLMJSON(ctx context.Context, client *http.Client, url string, header http.Header, body, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header = header.Clone()
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("llm request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read llm response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var failure struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(data, &failure) == nil && failure.Error.Message != "" {
+			return fmt.Errorf("llm returned HTTP %d: %s", resp.StatusCode, failure.Error.Message)
+		}
+		return fmt.Errorf("llm returned HTTP %d", resp.StatusCode)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode llm response: %w", err)
+	}
+	return nil
+}
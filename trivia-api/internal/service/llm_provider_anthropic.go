@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// anthropicAPIVersion — версия Messages API, передаётся в заголовке anthropic-version
+const anthropicAPIVersion = "2023-06-01"
+
+// AnthropicLLMProvider обращается к Messages API Anthropic
+type AnthropicLLMProvider struct {
+	baseURL string
+	apiKey  string
+	model   string
+	client  *http.Client
+}
+
+// NewAnthropicLLMProvider создает провайдера; пустой baseURL — https://api.anthropic.com
+func NewAnthropicLLMProvider(baseURL, apiKey, model string, timeout time.Duration) (*AnthropicLLMProvider, error) {
+	if apiKey == "" || model == "" {
+		return nil, fmt.Errorf("anthropic api key and model are required")
+	}
+	if baseURL == "" {
+		baseURL = "https://api.anthropic.com"
+	}
+	return &AnthropicLLMProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		model:   model,
+		client:  &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func (p *AnthropicLLMProvider) Name() string { return LLMProviderAnthropic }
+
+func (p *AnthropicLLMProvider) Model() string { return p.model }
+
+func (p *AnthropicLLMProvider) Complete(ctx context.Context, req LLMRequest) (*LLMResponse, error) {
+	body := map[string]interface{}{
+		"model":      p.model,
+		"max_tokens": req.MaxTokens,
+		"system":     req.System,
+		"messages":   []map[string]string{{"role": "user", "content": req.Prompt}},
+	}
+	var message struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+		StopReason string `json:"stop_reason"`
+		Usage      struct {
+			InputTokens  int `json:"input_tokens"`
+			OutputTokens int `json:"output_tokens"`
+		} `json:"usage"`
+	}
+	header := http.Header{}
+	header.Set("x-api-key", p.apiKey)
+	header.Set("anthropic-version", anthropicAPIVersion)
+	if err := postLLMJSON(ctx, p.client, p.baseURL+"/v1/messages", header, body, &message); err != nil {
+		return nil, err
+	}
+
+	resp := &LLMResponse{PromptTokens: message.Usage.InputTokens, CompletionTokens: message.Usage.OutputTokens}
+	var text strings.Builder
+	for _, block := range message.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	resp.Text = text.String()
+	if message.StopReason == "max_tokens" {
+		return resp, fmt.Errorf("model response truncated at max_tokens")
+	}
+	return resp, nil
+}
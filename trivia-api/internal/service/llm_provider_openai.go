@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// OpenAILLMProvider обращается к Chat Completions API OpenAI или совместимому с ним
+// (Azure OpenAI через прокси, vLLM, Ollama, OpenRouter)
+type OpenAILLMProvider struct {
+	baseURL string
+	apiKey  string
+	model   string
+	client  *http.Client
+}
+
+// NewOpenAILLMProvider создает провайдера; пустой baseURL — https://api.openai.com
+func NewOpenAILLMProvider(baseURL, apiKey, model string, timeout time.Duration) (*OpenAILLMProvider, error) {
+	if apiKey == "" || model == "" {
+		return nil, fmt.Errorf("openai api key and model are required")
+	}
+	if baseURL == "" {
+		baseURL = "https://api.openai.com"
+	}
+	return &OpenAILLMProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		model:   model,
+		client:  &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func (p *OpenAILLMProvider) Name() string { return LLMProviderOpenAI }
+
+func (p *OpenAILLMProvider) Model() string { return p.model }
+
+func (p *OpenAILLMProvider) Complete(ctx context.Context, req LLMRequest) (*LLMResponse, error) {
+	type message struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	}
+	body := map[string]interface{}{
+		"model":      p.model,
+		"max_tokens": req.MaxTokens,
+		"messages":   []message{{Role: "system", Content: req.System}, {Role: "user", Content: req.Prompt}},
+	}
+	var completion struct {
+		Choices []struct {
+			Message      message `json:"message"`
+			FinishReason string  `json:"finish_reason"`
+		} `json:"choices"`
+		Usage struct {
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+		} `json:"usage"`
+	}
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+p.apiKey)
+	if err := postLLMJSON(ctx, p.client, p.baseURL+"/v1/chat/completions", header, body, &completion); err != nil {
+		return nil, err
+	}
+
+	resp := &LLMResponse{PromptTokens: completion.Usage.PromptTokens, CompletionTokens: completion.Usage.CompletionTokens}
+	if len(completion.Choices) == 0 {
+		return resp, fmt.Errorf("openai returned no choices")
+	}
+	choice := completion.Choices[0]
+	resp.Text = choice.Message.Content
+	if choice.FinishReason == "length" {
+		return resp, fmt.Errorf("model response truncated at max_tokens")
+	}
+	return resp, nil
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/config"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// MagicLinkService выдаёт и проверяет одноразовые ссылки для входа без пароля. Ссылка
+// привязана к устройству, с которого её запросили: переход с другого устройства отклоняется,
+// а сама ссылка при этом остаётся действительной, поэтому почтовые сканеры, открывающие
+// ссылки из писем, не расходуют её.
+type MagicLinkService struct {
+	userRepo       repository.UserRepository
+	linkRepo       repository.MagicLinkRepository
+	emailService   EmailService
+	linkURL        string
+	ttl            time.Duration
+	resendCooldown time.Duration
+	maxPerHour     int
+	secret         []byte
+	now            func() time.Time
+}
+
+// NewMagicLinkService создает сервис ссылок для входа
+func NewMagicLinkService(
+	userRepo repository.UserRepository,
+	linkRepo repository.MagicLinkRepository,
+	emailService EmailService,
+	cfg config.MagicLinkConfig,
+) (*MagicLinkService, error) {
+	if userRepo == nil {
+		return nil, fmt.Errorf("user repository is required")
+	}
+	if linkRepo == nil {
+		return nil, fmt.Errorf("magic link repository is required")
+	}
+	if emailService == nil {
+		return nil, fmt.Errorf("email service is required")
+	}
+	if _, err := url.Parse(cfg.URL); err != nil || cfg.URL == "" {
+		return nil, fmt.Errorf("magic link url is required")
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = 15 * time.Minute
+	}
+	if cfg.ResendCooldownSec <= 0 {
+		cfg.ResendCooldownSec = 60
+	}
+	if cfg.MaxPerHour <= 0 {
+		cfg.MaxPerHour = 5
+	}
+	return &MagicLinkService{
+		userRepo:       userRepo,
+		linkRepo:       linkRepo,
+		emailService:   emailService,
+		linkURL:        cfg.URL,
+		ttl:            cfg.TTL,
+		resendCooldown: time.Duration(cfg.ResendCooldownSec) * time.Second,
+		maxPerHour:     cfg.MaxPerHour,
+		secret:         []byte(cfg.Secret),
+		now:            time.Now,
+	}, nil
+}
+
+// Request отправляет ссылку для входа на email. Для неизвестного адреса, а также при
+// превышении лимитов письмо не отправляется, но ошибка не возвращается, чтобы по ответу
+// нельзя было узнать, зарегистрирован ли адрес.
+func (s *MagicLinkService) Request(ctx context.Context, email, deviceID, ipAddress string) error {
+	email = normalizeEmail(email)
+	deviceID = strings.TrimSpace(deviceID)
+	if email == "" {
+		return fmt.Errorf("%w: email is required", apperrors.ErrValidation)
+	}
+	if deviceID == "" {
+		return fmt.Errorf("%w: device id is required", apperrors.ErrValidation)
+	}
+
+	user, err := s.userRepo.WithContext(ctx).GetByEmail(email)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			log.Printf("[MagicLinkService] Ссылка запрошена для незарегистрированного email %s", email)
+			return nil
+		}
+		return err
+	}
+	if user.DeletedAt != nil {
+		return nil
+	}
+
+	now := s.now()
+	latest, err := s.linkRepo.GetLatestByUserID(user.ID)
+	if err != nil && !errors.Is(err, apperrors.ErrNotFound) {
+		return err
+	}
+	if latest != nil && now.Before(latest.CreatedAt.Add(s.resendCooldown)) {
+		log.Printf("[MagicLinkService] Повторный запрос ссылки для пользователя ID=%d раньше %s", user.ID, s.resendCooldown)
+		return nil
+	}
+	sent, err := s.linkRepo.CountSince(user.ID, now.Add(-time.Hour))
+	if err != nil {
+		return err
+	}
+	if sent >= int64(s.maxPerHour) {
+		log.Printf("[MagicLinkService] Лимит ссылок в час исчерпан для пользователя ID=%d", user.ID)
+		return nil
+	}
+
+	token, err := generateMagicLinkToken()
+	if err != nil {
+		return fmt.Errorf("failed to generate magic link token: %w", err)
+	}
+	link := &entity.MagicLink{
+		UserID:     user.ID,
+		TokenHash:  s.sign("token", token),
+		DeviceHash: s.sign("device", deviceID),
+		IPAddress:  ipAddress,
+		ExpiresAt:  now.Add(s.ttl),
+		CreatedAt:  now,
+	}
+	if err := s.linkRepo.Create(link); err != nil {
+		return fmt.Errorf("failed to create magic link: %w", err)
+	}
+
+	loginURL, err := s.buildURL(token)
+	if err != nil {
+		return err
+	}
+	data := map[string]string{
+		"username":        user.Username,
+		"link":            loginURL,
+		"expires_minutes": strconv.Itoa(int(s.ttl.Minutes())),
+	}
+	idempotencyKey := fmt.Sprintf("magic-link:%d:%d", user.ID, link.ID)
+	if err := s.emailService.SendTemplate(ctx, user.Email, user.Language, entity.EmailTemplateMagicLink, data, idempotencyKey); err != nil {
+		return fmt.Errorf("failed to send magic link email: %w", err)
+	}
+	return nil
+}
+
+// Consume проверяет ссылку и помечает её использованной. deviceID должен совпадать с
+// устройством, с которого ссылку запросили.
+func (s *MagicLinkService) Consume(ctx context.Context, token, deviceID string) (*entity.User, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, ErrMagicLinkInvalid
+	}
+	link, err := s.linkRepo.GetByTokenHash(s.sign("token", token))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil, ErrMagicLinkInvalid
+		}
+		return nil, err
+	}
+	now := s.now()
+	if link.ConsumedAt != nil {
+		return nil, ErrMagicLinkInvalid
+	}
+	if link.IsExpired(now) {
+		return nil, ErrMagicLinkExpired
+	}
+	deviceHash := s.sign("device", strings.TrimSpace(deviceID))
+	if subtle.ConstantTimeCompare([]byte(deviceHash), []byte(link.DeviceHash)) != 1 {
+		log.Printf("[MagicLinkService] Ссылка ID=%d открыта на другом устройстве", link.ID)
+		return nil, ErrMagicLinkDeviceMismatch
+	}
+
+	consumed, err := s.linkRepo.Consume(link.ID, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to consume magic link: %w", err)
+	}
+	if !consumed {
+		return nil, ErrMagicLinkInvalid
+	}
+
+	user, err := s.userRepo.WithContext(ctx).GetByID(link.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user.DeletedAt != nil {
+		return nil, ErrMagicLinkInvalid
+	}
+
+	// Переход по ссылке из письма подтверждает владение адресом
+	if user.EmailVerifiedAt == nil {
+		updates := map[string]interface{}{"email_verified_at": &now}
+		if user.ProfileCompletedAt == nil && user.IsProfileComplete() {
+			updates["profile_completed_at"] = &now
+		}
+		if err := s.userRepo.UpdateProfile(user.ID, updates); err != nil {
+			log.Printf("[MagicLinkService] Не удалось отметить email пользователя ID=%d подтверждённым: %v", user.ID, err)
+		} else {
+			user.EmailVerifiedAt = &now
+		}
+	}
+	return user, nil
+}
+
+// DeleteByUserID удаляет ссылки пользователя (при удалении аккаунта)
+func (s *MagicLinkService) DeleteByUserID(userID uint) error {
+	return s.linkRepo.DeleteByUserID(userID)
+}
+
+// sign возвращает HMAC значения; purpose разделяет хеши токенов и устройств
+func (s *MagicLinkService) sign(purpose, value string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(purpose + ":" + value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *MagicLinkService) buildURL(token string) (string, error) {
+	u, err := url.Parse(s.linkURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid magic link url: %w", err)
+	}
+	query := u.Query()
+	query.Set("token", token)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+func generateMagicLinkToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/config"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// fakeMagicLinkRepo — MagicLinkRepository в памяти
+type fakeMagicLinkRepo struct {
+	links []*entity.MagicLink
+}
+
+func (f *fakeMagicLinkRepo) Create(link *entity.MagicLink) error {
+	link.ID = uint(len(f.links) + 1)
+	f.links = append(f.links, link)
+	return nil
+}
+
+func (f *fakeMagicLinkRepo) GetByTokenHash(tokenHash string) (*entity.MagicLink, error) {
+	for _, link := range f.links {
+		if link.TokenHash == tokenHash {
+			copied := *link
+			return &copied, nil
+		}
+	}
+	return nil, apperrors.ErrNotFound
+}
+
+func (f *fakeMagicLinkRepo) GetLatestByUserID(userID uint) (*entity.MagicLink, error) {
+	for i := len(f.links) - 1; i >= 0; i-- {
+		if f.links[i].UserID == userID {
+			copied := *f.links[i]
+			return &copied, nil
+		}
+	}
+	return nil, apperrors.ErrNotFound
+}
+
+func (f *fakeMagicLinkRepo) CountSince(userID uint, since time.Time) (int64, error) {
+	var count int64
+	for _, link := range f.links {
+		if link.UserID == userID && link.CreatedAt.After(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (f *fakeMagicLinkRepo) Consume(id uint, at time.Time) (bool, error) {
+	for _, link := range f.links {
+		if link.ID == id && link.ConsumedAt == nil {
+			link.ConsumedAt = &at
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeMagicLinkRepo) DeleteByUserID(userID uint) error {
+	return nil
+}
+
+// linkEmailService запоминает ссылки из отправленных писем
+type linkEmailService struct {
+	NoopEmailService
+	links []string
+}
+
+func (s *linkEmailService) SendTemplate(_ context.Context, toEmail, locale, templateKey string, data map[string]string, idempotencyKey string) error {
+	s.links = append(s.links, data["link"])
+	return nil
+}
+
+func newTestMagicLinkService(t *testing.T, users *MockUserRepository) (*MagicLinkService, *fakeMagicLinkRepo, *linkEmailService, *time.Time) {
+	t.Helper()
+	repo := &fakeMagicLinkRepo{}
+	emails := &linkEmailService{}
+	svc, err := NewMagicLinkService(users, repo, emails, config.MagicLinkConfig{
+		URL:    "https://quiz.example.com/auth/magic-link?utm=email",
+		TTL:    15 * time.Minute,
+		Secret: "test-secret",
+	})
+	require.NoError(t, err)
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	return svc, repo, emails, &now
+}
+
+func magicLinkToken(t *testing.T, link string) string {
+	t.Helper()
+	u, err := url.Parse(link)
+	require.NoError(t, err)
+	assert.Equal(t, "email", u.Query().Get("utm"))
+	return u.Query().Get("token")
+}
+
+func TestMagicLink_SingleUseAndBoundToDevice(t *testing.T) {
+	users := new(MockUserRepository)
+	user := &entity.User{ID: 7, Email: "player@example.com", Username: "player"}
+	users.On("GetByEmail", "player@example.com").Return(user, nil)
+	users.On("GetByID", uint(7)).Return(user, nil)
+	users.On("UpdateProfile", uint(7), mock.Anything).Return(nil)
+	svc, repo, emails, _ := newTestMagicLinkService(t, users)
+
+	require.NoError(t, svc.Request(context.Background(), " Player@Example.com ", "web-device-a", "10.0.0.1"))
+	require.Len(t, emails.links, 1)
+	token := magicLinkToken(t, emails.links[0])
+	require.NotEmpty(t, token)
+	assert.NotContains(t, repo.links[0].TokenHash, token, "в БД хранится только HMAC токена")
+
+	// Переход с другого устройства (пересланная ссылка, почтовый сканер) не расходует ссылку
+	_, err := svc.Consume(context.Background(), token, "web-device-b")
+	assert.ErrorIs(t, err, ErrMagicLinkDeviceMismatch)
+
+	loggedIn, err := svc.Consume(context.Background(), token, "web-device-a")
+	require.NoError(t, err)
+	assert.Equal(t, uint(7), loggedIn.ID)
+	assert.NotNil(t, loggedIn.EmailVerifiedAt, "переход по ссылке подтверждает email")
+
+	_, err = svc.Consume(context.Background(), token, "web-device-a")
+	assert.ErrorIs(t, err, ErrMagicLinkInvalid)
+	_, err = svc.Consume(context.Background(), "forged", "web-device-a")
+	assert.ErrorIs(t, err, ErrMagicLinkInvalid)
+}
+
+func TestMagicLink_ExpiresAndRateLimits(t *testing.T) {
+	users := new(MockUserRepository)
+	user := &entity.User{ID: 7, Email: "player@example.com", Username: "player"}
+	users.On("GetByEmail", "player@example.com").Return(user, nil)
+	users.On("GetByEmail", "ghost@example.com").Return(nil, apperrors.ErrNotFound)
+	svc, _, emails, now := newTestMagicLinkService(t, users)
+
+	require.NoError(t, svc.Request(context.Background(), "player@example.com", "device", ""))
+	// Повтор в пределах паузы и неизвестный адрес выглядят как успех, но письмо не уходит
+	require.NoError(t, svc.Request(context.Background(), "player@example.com", "device", ""))
+	require.NoError(t, svc.Request(context.Background(), "ghost@example.com", "device", ""))
+	require.Len(t, emails.links, 1)
+
+	for i := 0; i < 6; i++ {
+		*now = now.Add(2 * time.Minute)
+		require.NoError(t, svc.Request(context.Background(), "player@example.com", "device", ""))
+	}
+	assert.Len(t, emails.links, 5, "не больше maxPerHour ссылок в час")
+
+	*now = now.Add(16 * time.Minute)
+	_, err := svc.Consume(context.Background(), magicLinkToken(t, emails.links[0]), "device")
+	assert.ErrorIs(t, err, ErrMagicLinkExpired)
+
+	err = svc.Request(context.Background(), "player@example.com", "", "")
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+}
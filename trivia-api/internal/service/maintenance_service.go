@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+const (
+	// maintenanceStateKey — состояние режима обслуживания, общее для всех инстансов
+	maintenanceStateKey = "maintenance:state"
+
+	// MaintenanceEvent — событие WebSocket о включении и выключении режима обслуживания
+	MaintenanceEvent = "system:maintenance"
+)
+
+// MaintenanceState — состояние режима обслуживания «только чтение»
+type MaintenanceState struct {
+	Enabled       bool       `json:"enabled"`
+	Message       string     `json:"message,omitempty"`
+	RetryAfterSec int        `json:"retry_after"`
+	Since         *time.Time `json:"since,omitempty"`
+	// Forced — режим включён в конфигурации и через API не выключается
+	Forced bool `json:"forced,omitempty"`
+}
+
+// MaintenanceBroadcaster рассылает событие всем подключённым клиентам (websocket.Manager)
+type MaintenanceBroadcaster interface {
+	BroadcastEvent(eventType string, data interface{}) error
+}
+
+// MaintenanceService хранит режим обслуживания в Redis и держит локальную копию,
+// которую проверяют middleware и планировщик викторин без обращения к Redis.
+type MaintenanceService struct {
+	cache       repository.CacheRepository
+	broadcaster MaintenanceBroadcaster
+	forced      MaintenanceState // режим из конфигурации
+
+	mu    sync.RWMutex
+	state MaintenanceState
+}
+
+// NewMaintenanceService создает сервис режима обслуживания. Если forced.Enabled, режим
+// включён конфигурацией независимо от состояния в Redis. broadcaster может быть nil.
+func NewMaintenanceService(cache repository.CacheRepository, broadcaster MaintenanceBroadcaster, forced MaintenanceState) *MaintenanceService {
+	s := &MaintenanceService{cache: cache, broadcaster: broadcaster, forced: forced}
+	if forced.Enabled {
+		now := time.Now()
+		s.forced.Forced = true
+		s.forced.Since = &now
+	}
+	s.state = s.effective(MaintenanceState{RetryAfterSec: forced.RetryAfterSec})
+	return s
+}
+
+// State возвращает текущее состояние режима обслуживания
+func (s *MaintenanceService) State() MaintenanceState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.state
+}
+
+// Active сообщает, включён ли режим обслуживания
+func (s *MaintenanceService) Active() bool {
+	return s.State().Enabled
+}
+
+// RetryAfter возвращает рекомендуемую паузу перед повтором записи, в секундах
+func (s *MaintenanceService) RetryAfter() int {
+	return s.State().RetryAfterSec
+}
+
+// Set включает или выключает режим обслуживания на всех инстансах и рассылает
+// клиентам событие MaintenanceEvent. retryAfterSec <= 0 — значение из конфигурации.
+func (s *MaintenanceService) Set(enabled bool, message string, retryAfterSec int) (MaintenanceState, error) {
+	if !enabled && s.forced.Enabled {
+		return s.State(), fmt.Errorf("%w: maintenance mode is enabled in configuration", apperrors.ErrConflict)
+	}
+	if retryAfterSec <= 0 {
+		retryAfterSec = s.forced.RetryAfterSec
+	}
+
+	stored := MaintenanceState{Enabled: enabled, RetryAfterSec: retryAfterSec}
+	if enabled {
+		now := time.Now()
+		stored.Message = message
+		stored.Since = &now
+	}
+	if err := s.cache.SetJSON(maintenanceStateKey, stored, 0); err != nil {
+		return s.State(), fmt.Errorf("failed to store maintenance state: %w", err)
+	}
+
+	state := s.apply(stored)
+	if s.broadcaster != nil {
+		if err := s.broadcaster.BroadcastEvent(MaintenanceEvent, state); err != nil {
+			log.Printf("[MaintenanceService] Ошибка рассылки события режима обслуживания: %v", err)
+		}
+	}
+	log.Printf("[MaintenanceService] Режим обслуживания: enabled=%t", state.Enabled)
+	return state, nil
+}
+
+// Refresh перечитывает состояние из Redis. При ошибке Redis остаётся последнее известное состояние.
+func (s *MaintenanceService) Refresh() error {
+	var stored MaintenanceState
+	err := s.cache.GetJSON(maintenanceStateKey, &stored)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		stored = MaintenanceState{RetryAfterSec: s.forced.RetryAfterSec}
+	} else if err != nil {
+		return fmt.Errorf("failed to load maintenance state: %w", err)
+	}
+	s.apply(stored)
+	return nil
+}
+
+// Start загружает состояние и перечитывает его каждые interval, чтобы переключение
+// на одном инстансе применялось на остальных. Работает до отмены ctx.
+func (s *MaintenanceService) Start(ctx context.Context, interval time.Duration) {
+	if err := s.Refresh(); err != nil {
+		log.Printf("[MaintenanceService] %v", err)
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.Refresh(); err != nil {
+					log.Printf("[MaintenanceService] %v", err)
+				}
+			}
+		}
+	}()
+}
+
+func (s *MaintenanceService) apply(stored MaintenanceState) MaintenanceState {
+	state := s.effective(stored)
+	s.mu.Lock()
+	s.state = state
+	s.mu.Unlock()
+	return state
+}
+
+// effective накладывает режим из конфигурации на состояние из Redis
+func (s *MaintenanceService) effective(stored MaintenanceState) MaintenanceState {
+	if !s.forced.Enabled {
+		return stored
+	}
+	state := s.forced
+	if stored.Enabled && stored.Message != "" {
+		state.Message = stored.Message
+	}
+	return state
+}
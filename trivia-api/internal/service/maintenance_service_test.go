@@ -0,0 +1,56 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+type recordingBroadcaster struct {
+	events []string
+}
+
+func (r *recordingBroadcaster) BroadcastEvent(eventType string, data interface{}) error {
+	r.events = append(r.events, eventType)
+	return nil
+}
+
+func TestMaintenanceService_SetSharedAcrossInstances(t *testing.T) {
+	cache := &memoryQuizCache{values: map[string][]byte{}}
+	broadcaster := &recordingBroadcaster{}
+	first := NewMaintenanceService(cache, broadcaster, MaintenanceState{RetryAfterSec: 300})
+	second := NewMaintenanceService(cache, nil, MaintenanceState{RetryAfterSec: 300})
+
+	state, err := first.Set(true, "Обновление", 0)
+	require.NoError(t, err)
+	assert.True(t, state.Enabled)
+	assert.Equal(t, 300, state.RetryAfterSec)
+	assert.Equal(t, []string{MaintenanceEvent}, broadcaster.events)
+
+	// Второй инстанс узнаёт о режиме при очередном чтении из Redis
+	assert.False(t, second.Active())
+	require.NoError(t, second.Refresh())
+	assert.True(t, second.Active())
+	assert.Equal(t, "Обновление", second.State().Message)
+
+	_, err = first.Set(false, "", 0)
+	require.NoError(t, err)
+	require.NoError(t, second.Refresh())
+	assert.False(t, second.Active())
+}
+
+func TestMaintenanceService_ForcedByConfig(t *testing.T) {
+	cache := &memoryQuizCache{values: map[string][]byte{}}
+	svc := NewMaintenanceService(cache, nil, MaintenanceState{Enabled: true, RetryAfterSec: 120})
+	require.NoError(t, svc.Refresh())
+	assert.True(t, svc.Active())
+	assert.Equal(t, 120, svc.RetryAfter())
+
+	_, err := svc.Set(false, "", 0)
+	assert.True(t, errors.Is(err, apperrors.ErrConflict))
+	assert.True(t, svc.Active())
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+)
+
+const (
+	// mobileClientMaxUsageDays — за сколько дней отдаётся статистика клиентов
+	mobileClientMaxUsageDays = 90
+	// mobileClientMaxKeys — сколько разных сочетаний версий копится в памяти между сбросами.
+	// Версия приложения приходит от клиента, поэтому остальные попадают в «other».
+	mobileClientMaxKeys = 1000
+	mobileClientOther   = "other"
+)
+
+// mobileClientUsageKey — ключ счётчиков в памяти
+type mobileClientUsageKey struct {
+	day        time.Time
+	apiVersion string
+	platform   string
+	appVersion string
+}
+
+// MobileClientService ведёт статистику запросов мобильных клиентов по версии API и версии
+// приложения. Счётчики копятся в памяти и периодически сбрасываются в БД (Start).
+type MobileClientService struct {
+	repo repository.MobileClientUsageRepository
+
+	mu    sync.Mutex
+	usage map[mobileClientUsageKey]int64
+}
+
+// NewMobileClientService создает сервис статистики мобильных клиентов
+func NewMobileClientService(repo repository.MobileClientUsageRepository) *MobileClientService {
+	return &MobileClientService{
+		repo:  repo,
+		usage: make(map[mobileClientUsageKey]int64),
+	}
+}
+
+// RecordClientVersion учитывает запрос к мобильному API
+func (s *MobileClientService) RecordClientVersion(apiVersion, platform, appVersion string) {
+	k := mobileClientUsageKey{
+		day:        time.Now().UTC().Truncate(24 * time.Hour),
+		apiVersion: apiVersion,
+		platform:   platform,
+		appVersion: appVersion,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.usage[k]; !ok && len(s.usage) >= mobileClientMaxKeys {
+		k.appVersion = mobileClientOther
+	}
+	s.usage[k]++
+}
+
+// Start периодически сбрасывает накопленную статистику в БД. Работает до отмены ctx,
+// при остановке сбрасывает остаток.
+func (s *MobileClientService) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				if err := s.FlushUsage(); err != nil {
+					log.Printf("[MobileClientService] Ошибка сохранения статистики клиентов при остановке: %v", err)
+				}
+				return
+			case <-ticker.C:
+				if err := s.FlushUsage(); err != nil {
+					log.Printf("[MobileClientService] Ошибка сохранения статистики клиентов: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// FlushUsage сохраняет накопленную статистику. При ошибке счётчики возвращаются в память
+// и будут сохранены следующим проходом.
+func (s *MobileClientService) FlushUsage() error {
+	s.mu.Lock()
+	usage := s.usage
+	s.usage = make(map[mobileClientUsageKey]int64)
+	s.mu.Unlock()
+
+	if len(usage) == 0 {
+		return nil
+	}
+	rows := make([]entity.MobileClientUsage, 0, len(usage))
+	for k, requests := range usage {
+		rows = append(rows, entity.MobileClientUsage{
+			Day:        k.day,
+			APIVersion: k.apiVersion,
+			Platform:   k.platform,
+			AppVersion: k.appVersion,
+			Requests:   requests,
+		})
+	}
+	if err := s.repo.AddUsage(rows); err != nil {
+		s.mu.Lock()
+		for k, requests := range usage {
+			s.usage[k] += requests
+		}
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// GetUsage возвращает суточную статистику за последние days дней (не более 90)
+func (s *MobileClientService) GetUsage(days int) ([]entity.MobileClientUsage, error) {
+	if days < 1 || days > mobileClientMaxUsageDays {
+		days = 30
+	}
+	since := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -(days - 1))
+	return s.repo.ListUsage(since)
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+// fakeMobileClientUsageRepo — MobileClientUsageRepository в памяти
+type fakeMobileClientUsageRepo struct {
+	rows   []entity.MobileClientUsage
+	addErr error
+	since  time.Time
+}
+
+func (f *fakeMobileClientUsageRepo) AddUsage(usage []entity.MobileClientUsage) error {
+	if f.addErr != nil {
+		return f.addErr
+	}
+	f.rows = append(f.rows, usage...)
+	return nil
+}
+
+func (f *fakeMobileClientUsageRepo) ListUsage(since time.Time) ([]entity.MobileClientUsage, error) {
+	f.since = since
+	return f.rows, nil
+}
+
+func (f *fakeMobileClientUsageRepo) requests(apiVersion, appVersion string) int64 {
+	var total int64
+	for _, row := range f.rows {
+		if row.APIVersion == apiVersion && row.AppVersion == appVersion {
+			total += row.Requests
+		}
+	}
+	return total
+}
+
+func TestMobileClientService_FlushUsage(t *testing.T) {
+	repo := &fakeMobileClientUsageRepo{addErr: errors.New("db down")}
+	svc := NewMobileClientService(repo)
+
+	svc.RecordClientVersion("v1", "ios", "2.3.1")
+	svc.RecordClientVersion("v1", "ios", "2.3.1")
+	svc.RecordClientVersion("v2", "android", "3.0.0")
+
+	// Ошибка БД не теряет счётчики
+	require.Error(t, svc.FlushUsage())
+	svc.RecordClientVersion("v1", "ios", "2.3.1")
+	repo.addErr = nil
+	require.NoError(t, svc.FlushUsage())
+	assert.Len(t, repo.rows, 2)
+	assert.Equal(t, int64(3), repo.requests("v1", "2.3.1"))
+	assert.Equal(t, int64(1), repo.requests("v2", "3.0.0"))
+
+	require.NoError(t, svc.FlushUsage())
+	assert.Len(t, repo.rows, 2, "пустой сброс не пишет в БД")
+}
+
+func TestMobileClientService_KeyLimit(t *testing.T) {
+	repo := &fakeMobileClientUsageRepo{}
+	svc := NewMobileClientService(repo)
+	for i := 0; i < mobileClientMaxKeys+5; i++ {
+		svc.RecordClientVersion("v1", "android", fmt.Sprintf("1.0.%d", i))
+	}
+	svc.RecordClientVersion("v1", "android", "1.0.0") // уже учтённая версия не уходит в other
+
+	require.NoError(t, svc.FlushUsage())
+	assert.Equal(t, int64(5), repo.requests("v1", mobileClientOther))
+	assert.Equal(t, int64(2), repo.requests("v1", "1.0.0"))
+}
+
+func TestMobileClientService_GetUsage(t *testing.T) {
+	repo := &fakeMobileClientUsageRepo{}
+	svc := NewMobileClientService(repo)
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	_, err := svc.GetUsage(7)
+	require.NoError(t, err)
+	assert.Equal(t, today.AddDate(0, 0, -6), repo.since)
+
+	_, err = svc.GetUsage(1000)
+	require.NoError(t, err)
+	assert.Equal(t, today.AddDate(0, 0, -29), repo.since)
+}
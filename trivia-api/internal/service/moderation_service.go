@@ -0,0 +1,216 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/pkg/moderation"
+)
+
+// ErrInappropriateContent — текст отклонён модерацией (см. ContentRejectedError)
+var ErrInappropriateContent = errors.New("inappropriate_content")
+
+// ContentRejectedError сообщает, текст какого поля отклонён модерацией
+type ContentRejectedError struct {
+	Field string
+}
+
+func (e *ContentRejectedError) Error() string { return "inappropriate_content: " + e.Field }
+
+func (e *ContentRejectedError) Unwrap() error { return ErrInappropriateContent }
+
+// ModeratedText — текст поля, проверяемый модерацией
+type ModeratedText struct {
+	Field string // entity.ModerationField*
+	Text  string
+}
+
+// FlaggedText — пограничный текст: принимается, но после сохранения уходит в очередь (Enqueue)
+type FlaggedText struct {
+	ModeratedText
+	Matches []string // "ru:дурак*"
+}
+
+// ModerationService проверяет пользовательский текст по спискам слов и ведёт очередь
+// модерации пограничных случаев. Точки применения: регистрация, смена профиля и чат —
+// через Screen/Enqueue или ModerateText.
+type ModerationService struct {
+	filter   *moderation.Filter
+	repo     repository.ModerationRepository
+	userRepo repository.UserRepository
+}
+
+// NewModerationService создает сервис модерации
+func NewModerationService(filter *moderation.Filter, repo repository.ModerationRepository, userRepo repository.UserRepository) *ModerationService {
+	return &ModerationService{filter: filter, repo: repo, userRepo: userRepo}
+}
+
+// Screen проверяет тексты до сохранения. Для запрещённого текста возвращает *ContentRejectedError
+// первого такого поля; пограничные тексты возвращаются для Enqueue.
+func (s *ModerationService) Screen(texts ...ModeratedText) ([]FlaggedText, error) {
+	var flagged []FlaggedText
+	for _, text := range texts {
+		if text.Text == "" {
+			continue
+		}
+		result := s.filter.Check(text.Text)
+		switch result.Verdict {
+		case moderation.Block:
+			log.Printf("[Moderation] Отклонено поле %s: %v", text.Field, result.Matches)
+			return nil, &ContentRejectedError{Field: text.Field}
+		case moderation.Review:
+			matches := make([]string, len(result.Matches))
+			for i, match := range result.Matches {
+				matches[i] = match.Locale + ":" + match.Entry
+			}
+			flagged = append(flagged, FlaggedText{ModeratedText: text, Matches: matches})
+		}
+	}
+	return flagged, nil
+}
+
+// Enqueue ставит пограничные тексты пользователя в очередь модерации. Вызывается после
+// сохранения текста; ошибка только логируется — текст уже принят.
+func (s *ModerationService) Enqueue(userID uint, flagged []FlaggedText) {
+	for _, text := range flagged {
+		moderationCase := &entity.ModerationCase{
+			UserID:  userID,
+			Field:   text.Field,
+			Content: text.Text,
+			Matches: text.Matches,
+			Status:  entity.ModerationCasePending,
+		}
+		if err := s.repo.Create(moderationCase); err != nil {
+			log.Printf("[Moderation] Не удалось поставить в очередь поле %s пользователя %d: %v", text.Field, userID, err)
+		}
+	}
+}
+
+// ModerateText проверяет и при необходимости ставит в очередь один текст, который сохраняется
+// сразу (например, сообщение чата): *ContentRejectedError — текст не публикуется.
+func (s *ModerationService) ModerateText(userID uint, field, text string) error {
+	flagged, err := s.Screen(ModeratedText{Field: field, Text: text})
+	if err != nil {
+		return err
+	}
+	s.Enqueue(userID, flagged)
+	return nil
+}
+
+// ListCases возвращает случаи модерации, старые первыми
+func (s *ModerationService) ListCases(status, field string, page, pageSize int) ([]entity.ModerationCase, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return s.repo.List(status, field, pageSize, (page-1)*pageSize)
+}
+
+// Approve оставляет текст как есть
+func (s *ModerationService) Approve(caseID, adminID uint, note string) (*entity.ModerationCase, error) {
+	moderationCase, err := s.pendingCase(caseID)
+	if err != nil {
+		return nil, err
+	}
+	return s.resolve(moderationCase, entity.ModerationCaseApproved, adminID, note)
+}
+
+// Reject отклоняет текст. Имя пользователя заменяется на player<ID>, имя и фамилия очищаются
+// (профиль снова требует заполнения) — если пользователь ещё не сменил текст сам.
+// Для остальных полей (чат) решение только записывается.
+func (s *ModerationService) Reject(caseID, adminID uint, note string) (*entity.ModerationCase, error) {
+	moderationCase, err := s.pendingCase(caseID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.resetField(moderationCase); err != nil {
+		return nil, err
+	}
+	return s.resolve(moderationCase, entity.ModerationCaseRejected, adminID, note)
+}
+
+func (s *ModerationService) pendingCase(caseID uint) (*entity.ModerationCase, error) {
+	moderationCase, err := s.repo.GetByID(caseID)
+	if err != nil {
+		return nil, err
+	}
+	if moderationCase.Status != entity.ModerationCasePending {
+		return nil, fmt.Errorf("%w: moderation case is %s", apperrors.ErrConflict, moderationCase.Status)
+	}
+	return moderationCase, nil
+}
+
+func (s *ModerationService) resolve(moderationCase *entity.ModerationCase, status string, adminID uint, note string) (*entity.ModerationCase, error) {
+	now := time.Now()
+	moderationCase.Status = status
+	moderationCase.ReviewedBy = &adminID
+	moderationCase.ReviewedAt = &now
+	moderationCase.Note = note
+	if err := s.repo.Resolve(moderationCase); err != nil {
+		return nil, err
+	}
+	return moderationCase, nil
+}
+
+// resetField сбрасывает отклонённое поле пользователя
+func (s *ModerationService) resetField(moderationCase *entity.ModerationCase) error {
+	user, err := s.userRepo.GetByID(moderationCase.UserID)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return nil // аккаунт удалён — сбрасывать нечего
+	}
+	if err != nil {
+		return err
+	}
+
+	var updates map[string]interface{}
+	switch moderationCase.Field {
+	case entity.ModerationFieldUsername:
+		if user.Username != moderationCase.Content {
+			return nil
+		}
+		username, err := s.placeholderUsername(user.ID)
+		if err != nil {
+			return err
+		}
+		updates = map[string]interface{}{"username": username}
+	case entity.ModerationFieldFirstName, entity.ModerationFieldLastName:
+		if (moderationCase.Field == entity.ModerationFieldFirstName && user.FirstName != moderationCase.Content) ||
+			(moderationCase.Field == entity.ModerationFieldLastName && user.LastName != moderationCase.Content) {
+			return nil
+		}
+		updates = map[string]interface{}{moderationCase.Field: "", "profile_completed_at": nil}
+	default:
+		return nil
+	}
+	if err := s.userRepo.UpdateProfile(user.ID, updates); err != nil {
+		return fmt.Errorf("failed to reset %s: %w", moderationCase.Field, err)
+	}
+	return nil
+}
+
+// placeholderUsername подбирает свободное имя player<ID>, player<ID>_2, ...
+func (s *ModerationService) placeholderUsername(userID uint) (string, error) {
+	base := "player" + strconv.FormatUint(uint64(userID), 10)
+	for attempt := 1; attempt <= 10; attempt++ {
+		candidate := base
+		if attempt > 1 {
+			candidate = base + "_" + strconv.Itoa(attempt)
+		}
+		_, err := s.userRepo.GetByUsername(candidate)
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to check username availability: %w", err)
+		}
+	}
+	return "", fmt.Errorf("no free placeholder username for user %d", userID)
+}
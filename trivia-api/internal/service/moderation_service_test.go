@@ -0,0 +1,202 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/pkg/moderation"
+)
+
+// fakeModerationRepo — ModerationRepository в памяти
+type fakeModerationRepo struct {
+	cases []entity.ModerationCase
+}
+
+func (r *fakeModerationRepo) Create(moderationCase *entity.ModerationCase) error {
+	for i := range r.cases {
+		c := &r.cases[i]
+		if c.UserID == moderationCase.UserID && c.Field == moderationCase.Field && c.Status == entity.ModerationCasePending {
+			c.Status = entity.ModerationCaseSuperseded
+		}
+	}
+	moderationCase.ID = uint(len(r.cases) + 1)
+	r.cases = append(r.cases, *moderationCase)
+	return nil
+}
+
+func (r *fakeModerationRepo) GetByID(id uint) (*entity.ModerationCase, error) {
+	if id == 0 || int(id) > len(r.cases) {
+		return nil, apperrors.ErrNotFound
+	}
+	c := r.cases[id-1]
+	return &c, nil
+}
+
+func (r *fakeModerationRepo) List(status, field string, limit, offset int) ([]entity.ModerationCase, int64, error) {
+	var result []entity.ModerationCase
+	for _, c := range r.cases {
+		if (status == "" || c.Status == status) && (field == "" || c.Field == field) {
+			result = append(result, c)
+		}
+	}
+	return result, int64(len(result)), nil
+}
+
+func (r *fakeModerationRepo) Resolve(moderationCase *entity.ModerationCase) error {
+	stored := &r.cases[moderationCase.ID-1]
+	if stored.Status != entity.ModerationCasePending {
+		return apperrors.ErrConflict
+	}
+	*stored = *moderationCase
+	return nil
+}
+
+func newTestModerationService(t *testing.T, userRepo *MockUserRepository) (*ModerationService, *fakeModerationRepo) {
+	t.Helper()
+	lists, err := moderation.LoadWordlists("", []string{"ru", "kk", "en"})
+	require.NoError(t, err)
+	repo := &fakeModerationRepo{}
+	return NewModerationService(moderation.NewFilter(lists), repo, userRepo), repo
+}
+
+func TestModerationService_Screen(t *testing.T) {
+	svc, _ := newTestModerationService(t, nil)
+
+	flagged, err := svc.Screen(
+		ModeratedText{Field: entity.ModerationFieldUsername, Text: "Aidos_2001"},
+		ModeratedText{Field: entity.ModerationFieldFirstName, Text: "Дурак"},
+		ModeratedText{Field: entity.ModerationFieldLastName, Text: ""},
+	)
+	require.NoError(t, err)
+	require.Len(t, flagged, 1)
+	assert.Equal(t, entity.ModerationFieldFirstName, flagged[0].Field)
+	assert.Equal(t, []string{"ru:дурак*"}, flagged[0].Matches)
+
+	_, err = svc.Screen(
+		ModeratedText{Field: entity.ModerationFieldUsername, Text: "player"},
+		ModeratedText{Field: entity.ModerationFieldLastName, Text: "Fuuuck"},
+	)
+	var rejected *ContentRejectedError
+	require.True(t, errors.As(err, &rejected))
+	assert.Equal(t, entity.ModerationFieldLastName, rejected.Field)
+	assert.ErrorIs(t, err, ErrInappropriateContent)
+}
+
+func TestModerationService_EnqueueSupersedesPendingCase(t *testing.T) {
+	svc, repo := newTestModerationService(t, nil)
+
+	require.NoError(t, svc.ModerateText(7, entity.ModerationFieldUsername, "дурак1"))
+	require.NoError(t, svc.ModerateText(7, entity.ModerationFieldUsername, "дурак2"))
+	require.NoError(t, svc.ModerateText(7, entity.ModerationFieldUsername, "Aidos"))
+
+	require.Len(t, repo.cases, 2, "допустимый текст в очередь не попадает")
+	assert.Equal(t, entity.ModerationCaseSuperseded, repo.cases[0].Status)
+	assert.Equal(t, entity.ModerationCasePending, repo.cases[1].Status)
+
+	cases, total, err := svc.ListCases(entity.ModerationCasePending, "", 1, 20)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, total)
+	assert.Equal(t, "дурак2", cases[0].Content)
+}
+
+func TestModerationService_RejectResetsUsername(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	svc, repo := newTestModerationService(t, userRepo)
+	svc.Enqueue(42, []FlaggedText{{ModeratedText: ModeratedText{Field: entity.ModerationFieldUsername, Text: "дурак42"}}})
+
+	userRepo.On("GetByID", uint(42)).Return(&entity.User{ID: 42, Username: "дурак42"}, nil)
+	userRepo.On("GetByUsername", "player42").Return(&entity.User{ID: 99}, nil)
+	userRepo.On("GetByUsername", "player42_2").Return(nil, apperrors.ErrNotFound)
+	userRepo.On("UpdateProfile", uint(42), map[string]interface{}{"username": "player42_2"}).Return(nil)
+
+	resolved, err := svc.Reject(1, 5, "оскорбление")
+	require.NoError(t, err)
+	assert.Equal(t, entity.ModerationCaseRejected, resolved.Status)
+	require.NotNil(t, resolved.ReviewedBy)
+	assert.Equal(t, uint(5), *resolved.ReviewedBy)
+	assert.Equal(t, "оскорбление", repo.cases[0].Note)
+	userRepo.AssertExpectations(t)
+
+	_, err = svc.Approve(1, 5, "")
+	assert.ErrorIs(t, err, apperrors.ErrConflict, "решение по случаю принимается один раз")
+}
+
+func TestModerationService_RejectKeepsChangedName(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	svc, repo := newTestModerationService(t, userRepo)
+	svc.Enqueue(42, []FlaggedText{{ModeratedText: ModeratedText{Field: entity.ModerationFieldFirstName, Text: "Дурак"}}})
+	svc.Enqueue(43, []FlaggedText{{ModeratedText: ModeratedText{Field: entity.ModerationFieldLastName, Text: "Дураков"}}})
+
+	// Пользователь 42 уже сменил имя сам: сбрасывать нечего
+	userRepo.On("GetByID", uint(42)).Return(&entity.User{ID: 42, FirstName: "Айдос"}, nil)
+	_, err := svc.Reject(1, 5, "")
+	require.NoError(t, err)
+	userRepo.AssertNotCalled(t, "UpdateProfile", uint(42), mock.Anything)
+
+	now := time.Now()
+	userRepo.On("GetByID", uint(43)).Return(&entity.User{ID: 43, LastName: "Дураков", ProfileCompletedAt: &now}, nil)
+	userRepo.On("UpdateProfile", uint(43), map[string]interface{}{"last_name": "", "profile_completed_at": nil}).Return(nil)
+	_, err = svc.Reject(2, 5, "")
+	require.NoError(t, err)
+	assert.Equal(t, entity.ModerationCaseRejected, repo.cases[1].Status)
+	userRepo.AssertExpectations(t)
+}
+
+func TestAuthService_RegisterUser_Moderation(t *testing.T) {
+	birthDate := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	input := RegisterInput{
+		Username:        "FuckMaster",
+		Email:           "new@example.com",
+		Password:        "password123",
+		FirstName:       "Test",
+		LastName:        "User",
+		BirthDate:       &birthDate,
+		Gender:          "male",
+		TOSAccepted:     true,
+		PrivacyAccepted: true,
+	}
+
+	t.Run("blocked username", func(t *testing.T) {
+		userRepo := new(MockUserRepository)
+		userRepo.On("GetByEmail", "new@example.com").Return(nil, apperrors.ErrNotFound)
+		userRepo.On("GetByUsername", "FuckMaster").Return(nil, apperrors.ErrNotFound)
+		authService := createTestAuthService(userRepo, nil, nil)
+		moderationService, _ := newTestModerationService(t, userRepo)
+		authService.SetModerationService(moderationService)
+
+		_, err := authService.RegisterUser(input)
+		var rejected *ContentRejectedError
+		require.True(t, errors.As(err, &rejected))
+		assert.Equal(t, entity.ModerationFieldUsername, rejected.Field)
+		userRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("borderline first name is queued", func(t *testing.T) {
+		input := input
+		input.Username = "newuser"
+		input.FirstName = "Дурак"
+		userRepo := new(MockUserRepository)
+		userRepo.On("GetByEmail", "new@example.com").Return(nil, apperrors.ErrNotFound)
+		userRepo.On("GetByUsername", "newuser").Return(nil, apperrors.ErrNotFound)
+		userRepo.On("Create", mock.AnythingOfType("*entity.User")).Run(func(args mock.Arguments) {
+			args.Get(0).(*entity.User).ID = 10
+		}).Return(nil)
+		authService := createTestAuthService(userRepo, nil, nil)
+		moderationService, repo := newTestModerationService(t, userRepo)
+		authService.SetModerationService(moderationService)
+
+		user, err := authService.RegisterUser(input)
+		require.NoError(t, err)
+		assert.Equal(t, "Дурак", user.FirstName)
+		require.Len(t, repo.cases, 1)
+		assert.Equal(t, uint(10), repo.cases[0].UserID)
+		assert.Equal(t, entity.ModerationFieldFirstName, repo.cases[0].Field)
+	})
+}
@@ -0,0 +1,341 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/websocket"
+)
+
+// Типы событий уведомлений в WebSocket
+const (
+	EventNotificationNew = "notification:new"
+)
+
+// Типы уведомлений безопасности (поле data.reason)
+const (
+	SecurityAlertSessionRevoked = "session_revoked"
+	SecurityAlertPasswordReset  = "password_reset"
+)
+
+// UpdateNotificationPreferencesInput содержит изменяемые настройки (nil — без изменений)
+type UpdateNotificationPreferencesInput struct {
+	QuizReminders       *bool
+	WinnerAnnouncements *bool
+	SecurityAlerts      *bool
+	EmailEnabled        *bool
+	PushEnabled         *bool
+	WebSocketEnabled    *bool
+}
+
+// NotificationList содержит страницу уведомлений пользователя
+type NotificationList struct {
+	Notifications []entity.Notification `json:"notifications"`
+	Total         int64                 `json:"total"`
+	UnreadCount   int64                 `json:"unread_count"`
+	Page          int                   `json:"page"`
+	PageSize      int                   `json:"page_size"`
+}
+
+// notificationEmailTemplates — шаблоны писем по типам уведомлений; email отправляется только для этих типов
+var notificationEmailTemplates = map[string]string{
+	entity.NotificationTypePrizeWon:      entity.EmailTemplatePrizeWon,
+	entity.NotificationTypePrizeClaim:    entity.EmailTemplatePrizeClaim,
+	entity.NotificationTypeSecurityAlert: entity.EmailTemplateSecurityAlert,
+}
+
+// securityAlertEmailTemplates — шаблоны писем для причин уведомления безопасности со своим текстом
+var securityAlertEmailTemplates = map[string]string{
+	SecurityAlertPasswordReset: entity.EmailTemplatePasswordReset,
+}
+
+// NotificationService управляет in-app центром уведомлений и настройками каналов доставки.
+// Уведомление всегда сохраняется в центре уведомлений; WebSocket и email доставляются
+// с учётом категорий и каналов, выбранных пользователем.
+type NotificationService struct {
+	notificationRepo repository.NotificationRepository
+	prefRepo         repository.NotificationPreferenceRepository
+	wsManager        *websocket.Manager
+
+	// Опциональная доставка по email (настраивается из main)
+	emailService EmailService
+	userRepo     repository.UserRepository
+}
+
+// NewNotificationService создает сервис уведомлений
+func NewNotificationService(
+	notificationRepo repository.NotificationRepository,
+	prefRepo repository.NotificationPreferenceRepository,
+	wsManager *websocket.Manager,
+) (*NotificationService, error) {
+	if notificationRepo == nil {
+		return nil, fmt.Errorf("notification repository is required")
+	}
+	if prefRepo == nil {
+		return nil, fmt.Errorf("notification preference repository is required")
+	}
+	return &NotificationService{
+		notificationRepo: notificationRepo,
+		prefRepo:         prefRepo,
+		wsManager:        wsManager,
+	}, nil
+}
+
+// SetEmailService включает доставку важных уведомлений по email
+func (s *NotificationService) SetEmailService(emailService EmailService, userRepo repository.UserRepository) {
+	s.emailService = emailService
+	s.userRepo = userRepo
+}
+
+// GetPreferences возвращает настройки уведомлений (значения по умолчанию, если пользователь их не менял)
+func (s *NotificationService) GetPreferences(userID uint) (*entity.NotificationPreference, error) {
+	pref, err := s.prefRepo.GetByUserID(userID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return entity.DefaultNotificationPreference(userID), nil
+		}
+		return nil, err
+	}
+	return pref, nil
+}
+
+// UpdatePreferences частично обновляет настройки уведомлений
+func (s *NotificationService) UpdatePreferences(userID uint, input UpdateNotificationPreferencesInput) (*entity.NotificationPreference, error) {
+	pref, err := s.GetPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+	if input.QuizReminders != nil {
+		pref.QuizReminders = *input.QuizReminders
+	}
+	if input.WinnerAnnouncements != nil {
+		pref.WinnerAnnouncements = *input.WinnerAnnouncements
+	}
+	if input.SecurityAlerts != nil {
+		pref.SecurityAlerts = *input.SecurityAlerts
+	}
+	if input.EmailEnabled != nil {
+		pref.EmailEnabled = *input.EmailEnabled
+	}
+	if input.PushEnabled != nil {
+		pref.PushEnabled = *input.PushEnabled
+	}
+	if input.WebSocketEnabled != nil {
+		pref.WebSocketEnabled = *input.WebSocketEnabled
+	}
+	pref.UpdatedAt = time.Now()
+	if err := s.prefRepo.Upsert(pref); err != nil {
+		return nil, err
+	}
+	return pref, nil
+}
+
+// ListNotifications возвращает страницу уведомлений пользователя
+func (s *NotificationService) ListNotifications(userID uint, unreadOnly bool, page, pageSize int) (*NotificationList, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	notifications, total, err := s.notificationRepo.ListByUserID(userID, unreadOnly, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, err
+	}
+	unread, err := s.notificationRepo.CountUnread(userID)
+	if err != nil {
+		return nil, err
+	}
+	if notifications == nil {
+		notifications = []entity.Notification{}
+	}
+
+	return &NotificationList{
+		Notifications: notifications,
+		Total:         total,
+		UnreadCount:   unread,
+		Page:          page,
+		PageSize:      pageSize,
+	}, nil
+}
+
+// GetUnreadCount возвращает количество непрочитанных уведомлений
+func (s *NotificationService) GetUnreadCount(userID uint) (int64, error) {
+	return s.notificationRepo.CountUnread(userID)
+}
+
+// MarkRead отмечает указанные уведомления прочитанными
+func (s *NotificationService) MarkRead(userID uint, ids []uint) (int64, error) {
+	if len(ids) == 0 {
+		return 0, fmt.Errorf("%w: ids are required", apperrors.ErrValidation)
+	}
+	if len(ids) > 100 {
+		return 0, fmt.Errorf("%w: too many ids (max 100)", apperrors.ErrValidation)
+	}
+	return s.notificationRepo.MarkRead(userID, ids)
+}
+
+// MarkAllRead отмечает все уведомления пользователя прочитанными
+func (s *NotificationService) MarkAllRead(userID uint) (int64, error) {
+	return s.notificationRepo.MarkAllRead(userID)
+}
+
+// NotifyResultsAvailable уведомляет участников о готовности результатов викторины
+func (s *NotificationService) NotifyResultsAvailable(quizID uint, quizTitle string, userIDs []uint) {
+	s.notifyUsers(userIDs, entity.NotificationTypeResultsAvailable,
+		"Результаты викторины готовы",
+		fmt.Sprintf("Итоги викторины «%s» подведены. Посмотрите своё место в таблице.", quizTitle),
+		entity.NotificationData{"quiz_id": quizID, "quiz_title": quizTitle},
+	)
+}
+
+// NotifyPrizeWon уведомляет победителей о выигрыше
+func (s *NotificationService) NotifyPrizeWon(quizID uint, quizTitle string, winnerIDs []uint, prize int) {
+	s.notifyUsers(winnerIDs, entity.NotificationTypePrizeWon,
+		"Поздравляем с победой!",
+		fmt.Sprintf("Вы выиграли %d в викторине «%s»", prize, quizTitle),
+		entity.NotificationData{"quiz_id": quizID, "quiz_title": quizTitle, "prize": prize},
+	)
+}
+
+// NotifyQuizReminder напоминает записавшимся участникам о скором начале викторины
+func (s *NotificationService) NotifyQuizReminder(quizID uint, quizTitle string, userIDs []uint, startsAt time.Time) {
+	minutes := int(time.Until(startsAt).Round(time.Minute).Minutes())
+	if minutes < 1 {
+		minutes = 1
+	}
+	s.notifyUsers(userIDs, entity.NotificationTypeQuizReminder,
+		"Викторина скоро начнётся",
+		fmt.Sprintf("Викторина «%s», на которую вы записались, начнётся через %d мин.", quizTitle, minutes),
+		entity.NotificationData{"quiz_id": quizID, "quiz_title": quizTitle, "scheduled_time": startsAt.UTC().Format(time.RFC3339)},
+	)
+}
+
+// NotifyPrizeClaim отправляет победителю токен заявки на приз. Уведомление нельзя отключить:
+// без него победитель не сможет получить выигрыш.
+func (s *NotificationService) NotifyPrizeClaim(userID, quizID uint, quizTitle string, amount int64, token string, deadline time.Time) {
+	s.notifyUsers([]uint{userID}, entity.NotificationTypePrizeClaim,
+		"Получите свой приз",
+		fmt.Sprintf("Отправьте данные для выплаты выигрыша %d в викторине «%s» до %s", amount, quizTitle, deadline.UTC().Format("02.01.2006 15:04 UTC")),
+		entity.NotificationData{
+			"quiz_id":     quizID,
+			"quiz_title":  quizTitle,
+			"amount":      amount,
+			"claim_token": token,
+			"deadline":    deadline.UTC().Format(time.RFC3339),
+		},
+	)
+}
+
+// NotifySecurityAlert уведомляет пользователя о событии безопасности (например, отзыве сессии)
+func (s *NotificationService) NotifySecurityAlert(userID uint, reason string) {
+	body := "Одна или несколько ваших сессий были завершены. Если это были не вы, смените пароль."
+	if reason == SecurityAlertPasswordReset {
+		body = "Администратор сбросил пароль вашего аккаунта, все сессии завершены."
+	}
+	s.notifyUsers([]uint{userID}, entity.NotificationTypeSecurityAlert,
+		"Уведомление безопасности",
+		body,
+		entity.NotificationData{"reason": reason},
+	)
+}
+
+// notifyUsers сохраняет уведомления и доставляет их онлайн-пользователям и по email
+func (s *NotificationService) notifyUsers(userIDs []uint, notificationType, title, body string, data entity.NotificationData) {
+	if len(userIDs) == 0 {
+		return
+	}
+
+	notifications := make([]*entity.Notification, 0, len(userIDs))
+	for _, userID := range userIDs {
+		notifications = append(notifications, &entity.Notification{
+			UserID: userID,
+			Type:   notificationType,
+			Title:  title,
+			Body:   body,
+			Data:   data,
+		})
+	}
+	if err := s.notificationRepo.CreateBatch(notifications); err != nil {
+		log.Printf("[NotificationService] Ошибка сохранения уведомлений %s для %d пользователей: %v", notificationType, len(userIDs), err)
+		return
+	}
+
+	prefs, err := s.preferencesFor(userIDs)
+	if err != nil {
+		log.Printf("[NotificationService] Ошибка получения настроек уведомлений: %v", err)
+		return
+	}
+
+	category := entity.NotificationCategory(notificationType)
+	for _, n := range notifications {
+		pref := prefs[n.UserID]
+		if s.wsManager != nil && pref.AllowsDelivery(category, entity.NotificationChannelWebSocket) {
+			if err := s.wsManager.SendEventToUser(strconv.FormatUint(uint64(n.UserID), 10), EventNotificationNew, n); err != nil {
+				log.Printf("[NotificationService] Ошибка отправки уведомления ID=%d по WebSocket: %v", n.ID, err)
+			}
+		}
+		if pref.AllowsDelivery(category, entity.NotificationChannelEmail) {
+			s.sendEmail(n)
+		}
+	}
+}
+
+// preferencesFor возвращает настройки для каждого пользователя, подставляя значения по умолчанию
+func (s *NotificationService) preferencesFor(userIDs []uint) (map[uint]*entity.NotificationPreference, error) {
+	stored, err := s.prefRepo.GetByUserIDs(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	prefs := make(map[uint]*entity.NotificationPreference, len(userIDs))
+	for i := range stored {
+		prefs[stored[i].UserID] = &stored[i]
+	}
+	for _, id := range userIDs {
+		if _, ok := prefs[id]; !ok {
+			prefs[id] = entity.DefaultNotificationPreference(id)
+		}
+	}
+	return prefs, nil
+}
+
+// sendEmail отправляет копию важного уведомления на email пользователя
+func (s *NotificationService) sendEmail(n *entity.Notification) {
+	templateKey, ok := notificationEmailTemplates[n.Type]
+	if !ok || s.emailService == nil || s.userRepo == nil {
+		return
+	}
+	if reasonTemplate, ok := securityAlertEmailTemplates[fmt.Sprint(n.Data["reason"])]; ok && n.Type == entity.NotificationTypeSecurityAlert {
+		templateKey = reasonTemplate
+	}
+
+	user, err := s.userRepo.GetByID(n.UserID)
+	if err != nil {
+		log.Printf("[NotificationService] Не удалось получить пользователя ID=%d для email-уведомления: %v", n.UserID, err)
+		return
+	}
+	if user.Email == "" || user.EmailVerifiedAt == nil {
+		return
+	}
+
+	params := make(map[string]string, len(n.Data)+1)
+	for k, v := range n.Data {
+		params[k] = fmt.Sprint(v)
+	}
+	params["username"] = user.Username
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	idempotencyKey := fmt.Sprintf("notification-%d", n.ID)
+	if err := s.emailService.SendTemplate(ctx, user.Email, user.Language, templateKey, params, idempotencyKey); err != nil {
+		log.Printf("[NotificationService] Ошибка отправки email-уведомления ID=%d: %v", n.ID, err)
+	}
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// ============================================================================
+// Моки для NotificationService
+// ============================================================================
+
+// MockNotificationRepository реализует repository.NotificationRepository
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) CreateBatch(notifications []*entity.Notification) error {
+	args := m.Called(notifications)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) ListByUserID(userID uint, unreadOnly bool, limit, offset int) ([]entity.Notification, int64, error) {
+	args := m.Called(userID, unreadOnly, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]entity.Notification), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockNotificationRepository) CountUnread(userID uint) (int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockNotificationRepository) MarkRead(userID uint, ids []uint) (int64, error) {
+	args := m.Called(userID, ids)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockNotificationRepository) MarkAllRead(userID uint) (int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// recordingEmailService запоминает адресатов email-уведомлений
+type recordingEmailService struct {
+	NoopEmailService
+	recipients []string
+}
+
+func (s *recordingEmailService) SendNotification(_ context.Context, toEmail, subject, text, idempotencyKey string) error {
+	s.recipients = append(s.recipients, toEmail)
+	return nil
+}
+
+func (s *recordingEmailService) SendTemplate(_ context.Context, toEmail, locale, templateKey string, data map[string]string, idempotencyKey string) error {
+	s.recipients = append(s.recipients, toEmail)
+	return nil
+}
+
+func createTestNotificationService(t *testing.T) (*NotificationService, *MockNotificationRepository, *MockNotificationPreferenceRepository) {
+	notificationRepo := new(MockNotificationRepository)
+	prefRepo := new(MockNotificationPreferenceRepository)
+	svc, err := NewNotificationService(notificationRepo, prefRepo, nil)
+	require.NoError(t, err)
+	return svc, notificationRepo, prefRepo
+}
+
+// ============================================================================
+// Тесты
+// ============================================================================
+
+func TestNotificationGetPreferences_DefaultsWhenMissing(t *testing.T) {
+	svc, _, prefRepo := createTestNotificationService(t)
+	prefRepo.On("GetByUserID", uint(5)).Return(nil, apperrors.ErrNotFound)
+
+	pref, err := svc.GetPreferences(5)
+	require.NoError(t, err)
+	assert.True(t, pref.QuizReminders)
+	assert.True(t, pref.SecurityAlerts)
+	assert.True(t, pref.EmailEnabled)
+	assert.True(t, pref.PushEnabled)
+	assert.True(t, pref.WebSocketEnabled)
+}
+
+func TestNotificationUpdatePreferences_PartialUpdate(t *testing.T) {
+	svc, _, prefRepo := createTestNotificationService(t)
+	prefRepo.On("GetByUserID", uint(5)).Return(nil, apperrors.ErrNotFound)
+	prefRepo.On("Upsert", mock.AnythingOfType("*entity.NotificationPreference")).Return(nil)
+
+	disabled := false
+	pref, err := svc.UpdatePreferences(5, UpdateNotificationPreferencesInput{PushEnabled: &disabled})
+	require.NoError(t, err)
+	assert.False(t, pref.PushEnabled)
+	assert.True(t, pref.EmailEnabled)
+	assert.True(t, pref.WinnerAnnouncements)
+}
+
+func TestNotificationMarkRead_RequiresIDs(t *testing.T) {
+	svc, _, _ := createTestNotificationService(t)
+
+	_, err := svc.MarkRead(1, nil)
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+}
+
+func TestNotificationPrizeWon_PersistsForAllAndEmailsOnlyEnabledChannel(t *testing.T) {
+	svc, notificationRepo, prefRepo := createTestNotificationService(t)
+	userRepo := new(MockUserRepository)
+	emailSvc := &recordingEmailService{}
+	svc.SetEmailService(emailSvc, userRepo)
+
+	verifiedAt := time.Now()
+	notificationRepo.On("CreateBatch", mock.MatchedBy(func(n []*entity.Notification) bool {
+		return len(n) == 2 && n[0].Type == entity.NotificationTypePrizeWon
+	})).Return(nil)
+	prefRepo.On("GetByUserIDs", []uint{1, 2}).Return([]entity.NotificationPreference{
+		{UserID: 2, WinnerAnnouncements: true, EmailEnabled: false},
+	}, nil)
+	userRepo.On("GetByID", uint(1)).Return(&entity.User{ID: 1, Email: "one@example.com", EmailVerifiedAt: &verifiedAt}, nil)
+
+	svc.NotifyPrizeWon(10, "Quiz", []uint{1, 2}, 500)
+
+	notificationRepo.AssertExpectations(t)
+	assert.Equal(t, []string{"one@example.com"}, emailSvc.recipients)
+	userRepo.AssertNotCalled(t, "GetByID", uint(2))
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+)
+
+// PartitionRetentionService отсоединяет секции user_answers и results со старыми викторинами
+// и переносит их в схему archive. Данные архивных секций не попадают в запросы приложения;
+// их можно выгрузить и удалить средствами БД.
+type PartitionRetentionService struct {
+	repo      repository.PartitionRepository
+	retention time.Duration
+}
+
+// NewPartitionRetentionService создает сервис хранения секций. Секция архивируется, когда все её
+// викторины завершены и запланированы раньше, чем retention назад.
+func NewPartitionRetentionService(repo repository.PartitionRepository, retention time.Duration) *PartitionRetentionService {
+	return &PartitionRetentionService{repo: repo, retention: retention}
+}
+
+// ArchiveExpired архивирует устаревшие секции и возвращает их имена
+func (s *PartitionRetentionService) ArchiveExpired(now time.Time) ([]string, error) {
+	partitions, err := s.repo.ListQuizPartitions()
+	if err != nil {
+		return nil, err
+	}
+	cutoff := now.Add(-s.retention)
+
+	// Секции user_answers и results одного диапазона проверяются одним запросом
+	type quizRange struct{ from, to uint }
+	settled := make(map[quizRange]bool)
+
+	var archived []string
+	for _, partition := range partitions {
+		key := quizRange{partition.From, partition.To}
+		expired, checked := settled[key]
+		if !checked {
+			expired, err = s.repo.QuizRangeSettledBefore(partition.From, partition.To, cutoff)
+			if err != nil {
+				return archived, err
+			}
+			settled[key] = expired
+		}
+		if !expired {
+			continue
+		}
+		if err := s.repo.ArchivePartition(partition); err != nil {
+			return archived, err
+		}
+		log.Printf("[PartitionRetention] Секция %s (викторины %d-%d) перенесена в архив", partition.Name, partition.From, partition.To-1)
+		archived = append(archived, partition.Name)
+	}
+	return archived, nil
+}
+
+// Start периодически архивирует устаревшие секции. Работает до отмены ctx.
+func (s *PartitionRetentionService) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				if _, err := s.ArchiveExpired(now); err != nil {
+					log.Printf("[PartitionRetention] Ошибка архивации секций: %v", err)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+)
+
+// fakePartitionRepo — PartitionRepository в памяти: settled задаёт завершённые диапазоны по нижней границе
+type fakePartitionRepo struct {
+	partitions []repository.QuizPartition
+	settled    map[uint]bool
+	checks     int
+	archived   []string
+}
+
+func (f *fakePartitionRepo) ListQuizPartitions() ([]repository.QuizPartition, error) {
+	return f.partitions, nil
+}
+
+func (f *fakePartitionRepo) QuizRangeSettledBefore(from, to uint, before time.Time) (bool, error) {
+	f.checks++
+	return f.settled[from], nil
+}
+
+func (f *fakePartitionRepo) ArchivePartition(partition repository.QuizPartition) error {
+	f.archived = append(f.archived, partition.Name)
+	return nil
+}
+
+func TestPartitionRetentionService_ArchiveExpired(t *testing.T) {
+	repo := &fakePartitionRepo{
+		partitions: []repository.QuizPartition{
+			{Table: "results", Name: "results_p0000000000", From: 0, To: 100},
+			{Table: "results", Name: "results_p0000000100", From: 100, To: 200},
+			{Table: "user_answers", Name: "user_answers_p0000000000", From: 0, To: 100},
+			{Table: "user_answers", Name: "user_answers_p0000000100", From: 100, To: 200},
+		},
+		settled: map[uint]bool{0: true},
+	}
+	svc := NewPartitionRetentionService(repo, 90*24*time.Hour)
+
+	archived, err := svc.ArchiveExpired(time.Now())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"results_p0000000000", "user_answers_p0000000000"}, archived)
+	assert.Equal(t, archived, repo.archived)
+	// Диапазон проверяется один раз для обеих таблиц
+	assert.Equal(t, 2, repo.checks)
+}
@@ -0,0 +1,329 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+const (
+	// PrizeClaimPolicyRedistribute — невостребованный приз делится между остальными заявителями
+	PrizeClaimPolicyRedistribute = "redistribute"
+	// PrizeClaimPolicyForfeit — невостребованный приз сгорает
+	PrizeClaimPolicyForfeit = "forfeit"
+
+	// prizeClaimWalletMethod — получение приза на кошелёк приложения (если кошелёк включён)
+	prizeClaimWalletMethod = "wallet"
+	// prizeClaimSweepBatch — сколько просроченных заявок закрывается за один проход
+	prizeClaimSweepBatch = 100
+)
+
+// PrizeClaimNotifier отправляет победителю токен заявки (реализуется NotificationService)
+type PrizeClaimNotifier interface {
+	NotifyPrizeClaim(userID, quizID uint, quizTitle string, amount int64, token string, deadline time.Time)
+}
+
+// SubmitPrizeClaimInput содержит данные победителя для выплаты приза
+type SubmitPrizeClaimInput struct {
+	Token             string
+	PayoutMethod      string
+	PayoutDestination string
+	FullName          string
+	ContactEmail      string
+	ContactPhone      string
+}
+
+// PrizeClaimService ведёт заявки победителей на призы: победитель получает токен заявки,
+// до дедлайна отправляет данные для выплаты, администратор подтверждает личность
+// (email победителя должен быть подтверждён). Просроченные заявки закрываются фоновой задачей.
+type PrizeClaimService struct {
+	claimRepo     repository.PrizeClaimRepository
+	userRepo      repository.UserRepository
+	notifier      PrizeClaimNotifier
+	walletService *WalletService
+	claimWindow   time.Duration
+	redistribute  bool
+	now           func() time.Time
+}
+
+// NewPrizeClaimService создает сервис заявок на призы
+func NewPrizeClaimService(claimRepo repository.PrizeClaimRepository, userRepo repository.UserRepository, claimWindow time.Duration, unclaimedPolicy string) (*PrizeClaimService, error) {
+	if claimRepo == nil {
+		return nil, fmt.Errorf("prize claim repository is required")
+	}
+	if userRepo == nil {
+		return nil, fmt.Errorf("user repository is required")
+	}
+	if claimWindow <= 0 {
+		return nil, fmt.Errorf("claim window must be positive")
+	}
+	switch unclaimedPolicy {
+	case PrizeClaimPolicyRedistribute, PrizeClaimPolicyForfeit:
+	default:
+		return nil, fmt.Errorf("unknown unclaimed prize policy %q", unclaimedPolicy)
+	}
+	return &PrizeClaimService{
+		claimRepo:    claimRepo,
+		userRepo:     userRepo,
+		claimWindow:  claimWindow,
+		redistribute: unclaimedPolicy == PrizeClaimPolicyRedistribute,
+		now:          time.Now,
+	}, nil
+}
+
+// SetNotifier включает отправку токенов заявок победителям
+func (s *PrizeClaimService) SetNotifier(notifier PrizeClaimNotifier) {
+	s.notifier = notifier
+}
+
+// SetWalletService включает получение призов на кошелёк приложения
+func (s *PrizeClaimService) SetWalletService(svc *WalletService) {
+	s.walletService = svc
+}
+
+// CreateForWinners создаёт заявки победителям викторины и отправляет им токены.
+// Повторный вызов для той же викторины безопасен: существующие заявки не меняются.
+func (s *PrizeClaimService) CreateForWinners(quizID uint, quizTitle string, winnerIDs []uint, prize int) {
+	if prize <= 0 || len(winnerIDs) == 0 {
+		return
+	}
+
+	deadline := s.now().Add(s.claimWindow)
+	created := 0
+	for _, userID := range winnerIDs {
+		token, err := generatePrizeClaimToken()
+		if err != nil {
+			log.Printf("[PrizeClaimService] %v", err)
+			return
+		}
+		claim := &entity.PrizeClaim{
+			QuizID:        quizID,
+			UserID:        userID,
+			Amount:        int64(prize),
+			Status:        entity.PrizeClaimPending,
+			TokenHash:     hashPrizeClaimToken(token),
+			ClaimDeadline: deadline,
+		}
+		ok, err := s.claimRepo.Create(claim)
+		if err != nil {
+			log.Printf("[PrizeClaimService] Ошибка создания заявки пользователя ID=%d по викторине #%d: %v", userID, quizID, err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		created++
+		if s.notifier != nil {
+			s.notifier.NotifyPrizeClaim(userID, quizID, quizTitle, claim.Amount, token, deadline)
+		}
+	}
+	log.Printf("[PrizeClaimService] Викторина #%d: создано заявок на призы %d из %d, срок до %s", quizID, created, len(winnerIDs), deadline.Format(time.RFC3339))
+}
+
+// GetClaim возвращает заявку пользователя по викторине
+func (s *PrizeClaimService) GetClaim(userID, quizID uint) (*entity.PrizeClaim, error) {
+	return s.claimRepo.GetByQuizAndUser(quizID, userID)
+}
+
+// Submit сохраняет данные победителя для выплаты и передаёт заявку на проверку
+func (s *PrizeClaimService) Submit(userID, quizID uint, input SubmitPrizeClaimInput) (*entity.PrizeClaim, error) {
+	claim, err := s.claimRepo.GetByQuizAndUser(quizID, userID)
+	if err != nil {
+		return nil, err
+	}
+	token := strings.TrimSpace(input.Token)
+	if subtle.ConstantTimeCompare([]byte(hashPrizeClaimToken(token)), []byte(claim.TokenHash)) != 1 {
+		return nil, fmt.Errorf("%w: invalid claim token", apperrors.ErrForbidden)
+	}
+	if claim.Status != entity.PrizeClaimPending {
+		return nil, fmt.Errorf("%w: prize claim is already %s", apperrors.ErrConflict, claim.Status)
+	}
+	now := s.now()
+	if now.After(claim.ClaimDeadline) {
+		return nil, fmt.Errorf("%w: claim deadline has passed", apperrors.ErrConflict)
+	}
+
+	method := strings.TrimSpace(input.PayoutMethod)
+	destination := strings.TrimSpace(input.PayoutDestination)
+	if method == prizeClaimWalletMethod && s.walletService != nil {
+		destination = ""
+	} else if _, ok := payoutMethods[method]; !ok {
+		return nil, fmt.Errorf("%w: unsupported payout method %q", apperrors.ErrValidation, method)
+	} else if destination == "" || len(destination) > 255 {
+		return nil, fmt.Errorf("%w: payout destination is required and must be at most 255 characters", apperrors.ErrValidation)
+	}
+	fullName := strings.TrimSpace(input.FullName)
+	if fullName == "" || len([]rune(fullName)) > 150 {
+		return nil, fmt.Errorf("%w: full name is required and must be at most 150 characters", apperrors.ErrValidation)
+	}
+	email := strings.TrimSpace(input.ContactEmail)
+	phone := strings.TrimSpace(input.ContactPhone)
+	if email == "" && phone == "" {
+		return nil, fmt.Errorf("%w: contact email or phone is required", apperrors.ErrValidation)
+	}
+	if len(email) > 255 || (email != "" && !strings.Contains(email, "@")) {
+		return nil, fmt.Errorf("%w: invalid contact email", apperrors.ErrValidation)
+	}
+	if len(phone) > 32 {
+		return nil, fmt.Errorf("%w: contact phone must be at most 32 characters", apperrors.ErrValidation)
+	}
+
+	claim.Status = entity.PrizeClaimSubmitted
+	claim.PayoutMethod = method
+	claim.PayoutDestination = destination
+	claim.FullName = fullName
+	claim.ContactEmail = email
+	claim.ContactPhone = phone
+	claim.RejectReason = ""
+	claim.SubmittedAt = &now
+	if err := s.claimRepo.Transition(claim, entity.PrizeClaimPending); err != nil {
+		return nil, err
+	}
+	return claim, nil
+}
+
+// ListClaims возвращает заявки для администратора
+func (s *PrizeClaimService) ListClaims(status string, quizID uint, page, pageSize int) ([]entity.PrizeClaim, int64, error) {
+	switch status {
+	case "", entity.PrizeClaimPending, entity.PrizeClaimSubmitted, entity.PrizeClaimVerified,
+		entity.PrizeClaimRedistributed, entity.PrizeClaimForfeited:
+	default:
+		return nil, 0, fmt.Errorf("%w: unknown prize claim status %q", apperrors.ErrValidation, status)
+	}
+	filters := repository.PrizeClaimFilters{Status: status}
+	if quizID > 0 {
+		filters.QuizID = &quizID
+	}
+	page, pageSize = normalizeWalletPage(page, pageSize)
+	return s.claimRepo.List(filters, pageSize, (page-1)*pageSize)
+}
+
+// Verify подтверждает личность победителя и выдаёт приз. Email победителя должен быть подтверждён.
+func (s *PrizeClaimService) Verify(adminID, claimID uint) (*entity.PrizeClaim, error) {
+	claim, err := s.claimRepo.GetByID(claimID)
+	if err != nil {
+		return nil, err
+	}
+	if claim.Status != entity.PrizeClaimSubmitted {
+		return nil, fmt.Errorf("%w: only submitted claims can be verified, claim is %s", apperrors.ErrConflict, claim.Status)
+	}
+	user, err := s.userRepo.GetByID(claim.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user.EmailVerifiedAt == nil {
+		return nil, fmt.Errorf("%w: winner email is not verified", apperrors.ErrConflict)
+	}
+
+	now := s.now()
+	claim.Status = entity.PrizeClaimVerified
+	claim.ReviewerID = &adminID
+	claim.VerifiedAt = &now
+	claim.ResolvedAt = &now
+	if err := s.claimRepo.Transition(claim, entity.PrizeClaimSubmitted); err != nil {
+		return nil, err
+	}
+
+	if claim.PayoutMethod == prizeClaimWalletMethod && s.walletService != nil {
+		s.walletService.CreditPrizes(claim.QuizID, []uint{claim.UserID}, int(claim.Amount))
+	}
+	return claim, nil
+}
+
+// Reject возвращает заявку победителю на исправление данных; дедлайн не продлевается
+func (s *PrizeClaimService) Reject(adminID, claimID uint, reason string) (*entity.PrizeClaim, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" || len([]rune(reason)) > 255 {
+		return nil, fmt.Errorf("%w: reason is required and must be at most 255 characters", apperrors.ErrValidation)
+	}
+	claim, err := s.claimRepo.GetByID(claimID)
+	if err != nil {
+		return nil, err
+	}
+	if claim.Status != entity.PrizeClaimSubmitted {
+		return nil, fmt.Errorf("%w: only submitted claims can be rejected, claim is %s", apperrors.ErrConflict, claim.Status)
+	}
+
+	claim.Status = entity.PrizeClaimPending
+	claim.RejectReason = reason
+	claim.ReviewerID = &adminID
+	claim.SubmittedAt = nil
+	if err := s.claimRepo.Transition(claim, entity.PrizeClaimSubmitted); err != nil {
+		return nil, err
+	}
+	return claim, nil
+}
+
+// ResolveOverdue закрывает заявки, не отправленные до дедлайна, и зачисляет доли
+// перераспределённых призов уже подтверждённым победителям. Возвращает число закрытых заявок.
+func (s *PrizeClaimService) ResolveOverdue(now time.Time) (int, error) {
+	expired, shares, err := s.claimRepo.ResolveOverdue(now, s.redistribute, prizeClaimSweepBatch)
+	if err != nil {
+		return 0, err
+	}
+	if len(expired) == 0 {
+		return 0, nil
+	}
+
+	// Ещё не подтверждённые заявки получат долю вместе с суммой заявки при подтверждении
+	for _, share := range shares {
+		if !share.Verified {
+			continue
+		}
+		claim, err := s.claimRepo.GetByID(share.ClaimID)
+		if err != nil {
+			log.Printf("[PrizeClaimService] Ошибка получения заявки ID=%d: %v", share.ClaimID, err)
+			continue
+		}
+		if claim.PayoutMethod != prizeClaimWalletMethod || s.walletService == nil {
+			continue
+		}
+		if err := s.walletService.CreditPrizeShare(share.QuizID, share.UserID, share.FromClaimID, share.Amount); err != nil {
+			log.Printf("[PrizeClaimService] Ошибка зачисления доли приза пользователю ID=%d: %v", share.UserID, err)
+		}
+	}
+	log.Printf("[PrizeClaimService] Закрыто просроченных заявок: %d, перераспределено долей: %d", len(expired), len(shares))
+	return len(expired), nil
+}
+
+// Start запускает фоновое закрытие просроченных заявок
+func (s *PrizeClaimService) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				if _, err := s.ResolveOverdue(now); err != nil {
+					log.Printf("[PrizeClaimService] Ошибка закрытия просроченных заявок: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+func generatePrizeClaimToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate prize claim token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func hashPrizeClaimToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// memoryPrizeClaimRepo хранит заявки в памяти; ResolveOverdue отдаёт заранее заданный результат
+type memoryPrizeClaimRepo struct {
+	claims  map[uint]*entity.PrizeClaim
+	nextID  uint
+	expired []entity.PrizeClaim
+	shares  []repository.PrizeClaimShare
+}
+
+func (r *memoryPrizeClaimRepo) Create(claim *entity.PrizeClaim) (bool, error) {
+	for _, stored := range r.claims {
+		if stored.QuizID == claim.QuizID && stored.UserID == claim.UserID {
+			return false, nil
+		}
+	}
+	r.nextID++
+	claim.ID = r.nextID
+	stored := *claim
+	r.claims[claim.ID] = &stored
+	return true, nil
+}
+
+func (r *memoryPrizeClaimRepo) GetByID(id uint) (*entity.PrizeClaim, error) {
+	stored, ok := r.claims[id]
+	if !ok {
+		return nil, apperrors.ErrNotFound
+	}
+	claim := *stored
+	return &claim, nil
+}
+
+func (r *memoryPrizeClaimRepo) GetByQuizAndUser(quizID, userID uint) (*entity.PrizeClaim, error) {
+	for id, stored := range r.claims {
+		if stored.QuizID == quizID && stored.UserID == userID {
+			return r.GetByID(id)
+		}
+	}
+	return nil, apperrors.ErrNotFound
+}
+
+func (r *memoryPrizeClaimRepo) List(filters repository.PrizeClaimFilters, limit, offset int) ([]entity.PrizeClaim, int64, error) {
+	return nil, 0, nil
+}
+
+func (r *memoryPrizeClaimRepo) Transition(claim *entity.PrizeClaim, fromStatus string) error {
+	stored := r.claims[claim.ID]
+	if stored.Status != fromStatus {
+		return apperrors.ErrConflict
+	}
+	amount := stored.Amount
+	*stored = *claim
+	stored.Amount = amount
+	claim.Amount = amount
+	return nil
+}
+
+func (r *memoryPrizeClaimRepo) ResolveOverdue(now time.Time, redistribute bool, limit int) ([]entity.PrizeClaim, []repository.PrizeClaimShare, error) {
+	return r.expired, r.shares, nil
+}
+
+// prizeClaimNotifierRecorder запоминает токены, отправленные победителям
+type prizeClaimNotifierRecorder struct {
+	tokens map[uint]string
+}
+
+func (r *prizeClaimNotifierRecorder) NotifyPrizeClaim(userID, quizID uint, quizTitle string, amount int64, token string, deadline time.Time) {
+	r.tokens[userID] = token
+}
+
+func newTestPrizeClaimService(t *testing.T) (*PrizeClaimService, *memoryPrizeClaimRepo, *prizeClaimNotifierRecorder, *MockUserRepository) {
+	repo := &memoryPrizeClaimRepo{claims: make(map[uint]*entity.PrizeClaim)}
+	userRepo := new(MockUserRepository)
+	svc, err := NewPrizeClaimService(repo, userRepo, 72*time.Hour, PrizeClaimPolicyRedistribute)
+	require.NoError(t, err)
+	notifier := &prizeClaimNotifierRecorder{tokens: make(map[uint]string)}
+	svc.SetNotifier(notifier)
+	return svc, repo, notifier, userRepo
+}
+
+func validClaimInput(token string) SubmitPrizeClaimInput {
+	return SubmitPrizeClaimInput{
+		Token:             token,
+		PayoutMethod:      "kaspi",
+		PayoutDestination: "+77010000000",
+		FullName:          "Айгерим Нурланова",
+		ContactPhone:      "+77010000000",
+	}
+}
+
+func TestPrizeClaimService_SubmitAndVerify(t *testing.T) {
+	svc, repo, notifier, userRepo := newTestPrizeClaimService(t)
+
+	svc.CreateForWinners(1, "Вечерняя викторина", []uint{5, 6}, 500)
+	require.Len(t, repo.claims, 2)
+	token := notifier.tokens[5]
+	require.NotEmpty(t, token)
+	assert.NotEqual(t, token, repo.claims[1].TokenHash)
+
+	// Повторная финализация не создаёт заявки и не рассылает новые токены
+	notifier.tokens = make(map[uint]string)
+	svc.CreateForWinners(1, "Вечерняя викторина", []uint{5, 6}, 500)
+	assert.Len(t, repo.claims, 2)
+	assert.Empty(t, notifier.tokens)
+
+	_, err := svc.Submit(5, 1, validClaimInput("wrong"))
+	assert.ErrorIs(t, err, apperrors.ErrForbidden)
+	_, err = svc.Submit(7, 1, validClaimInput(token))
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	input := validClaimInput(token)
+	input.PayoutMethod = "wallet" // кошелёк выключен
+	_, err = svc.Submit(5, 1, input)
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	input = validClaimInput(token)
+	input.ContactPhone = ""
+	_, err = svc.Submit(5, 1, input)
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+
+	claim, err := svc.Submit(5, 1, validClaimInput(token))
+	require.NoError(t, err)
+	assert.Equal(t, entity.PrizeClaimSubmitted, claim.Status)
+	_, err = svc.Submit(5, 1, validClaimInput(token))
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+
+	// Отклонённую заявку победитель исправляет и отправляет снова
+	claim, err = svc.Reject(20, claim.ID, "ФИО не совпадает с владельцем карты")
+	require.NoError(t, err)
+	assert.Equal(t, entity.PrizeClaimPending, claim.Status)
+	_, err = svc.Submit(5, 1, validClaimInput(token))
+	require.NoError(t, err)
+
+	// Личность подтверждается только при подтверждённом email
+	userRepo.On("GetByID", uint(5)).Return(&entity.User{ID: 5}, nil).Once()
+	_, err = svc.Verify(20, claim.ID)
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+	verifiedAt := time.Now()
+	userRepo.On("GetByID", uint(5)).Return(&entity.User{ID: 5, EmailVerifiedAt: &verifiedAt}, nil).Once()
+	claim, err = svc.Verify(20, claim.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.PrizeClaimVerified, claim.Status)
+	assert.Equal(t, uint(20), *claim.ReviewerID)
+	assert.Empty(t, claim.RejectReason)
+}
+
+func TestPrizeClaimService_DeadlineAndWallet(t *testing.T) {
+	svc, repo, notifier, userRepo := newTestPrizeClaimService(t)
+	wallet, walletRepo := createTestWalletService(t)
+	svc.SetWalletService(wallet)
+	svc.CreateForWinners(1, "Вечерняя викторина", []uint{5, 6}, 500)
+
+	// После дедлайна данные не принимаются
+	svc.now = func() time.Time { return time.Now().Add(73 * time.Hour) }
+	_, err := svc.Submit(6, 1, validClaimInput(notifier.tokens[6]))
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+	svc.now = time.Now
+
+	input := validClaimInput(notifier.tokens[5])
+	input.PayoutMethod = "wallet"
+	claim, err := svc.Submit(5, 1, input)
+	require.NoError(t, err)
+	assert.Empty(t, claim.PayoutDestination)
+
+	verifiedAt := time.Now()
+	userRepo.On("GetByID", uint(5)).Return(&entity.User{ID: 5, EmailVerifiedAt: &verifiedAt}, nil)
+	walletRepo.On("GetOrCreateUserAccount", uint(5)).Return(&entity.WalletAccount{ID: 10}, nil)
+	walletRepo.On("PostTransaction", mock.MatchedBy(func(txn *entity.LedgerTransaction) bool {
+		return txn.Type == entity.LedgerTxPrizeCredit && txn.Reference == "quiz:1:user:5" && txn.Entries[0].Amount == 500
+	})).Return(nil).Once()
+	_, err = svc.Verify(20, claim.ID)
+	require.NoError(t, err)
+
+	// Доля невостребованного приза зачисляется подтверждённому победителю отдельной проводкой
+	repo.expired = []entity.PrizeClaim{*repo.claims[2]}
+	repo.shares = []repository.PrizeClaimShare{{ClaimID: claim.ID, QuizID: 1, UserID: 5, FromClaimID: 2, Amount: 500, Verified: true}}
+	walletRepo.On("PostTransaction", mock.MatchedBy(func(txn *entity.LedgerTransaction) bool {
+		return txn.Type == entity.LedgerTxPrizeShare && txn.Reference == "quiz:1:user:5:claim:2" && txn.Entries[0].Amount == 500
+	})).Return(nil).Once()
+	resolved, err := svc.ResolveOverdue(time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 1, resolved)
+	walletRepo.AssertExpectations(t)
+
+	_, err = NewPrizeClaimService(repo, userRepo, time.Hour, "keep")
+	assert.Error(t, err)
+}
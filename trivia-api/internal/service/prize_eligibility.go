@@ -0,0 +1,36 @@
+package service
+
+import (
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"gorm.io/gorm"
+)
+
+// SetGeoRestrictions включает ограничение призов по регионам: в викторине с allowed_countries
+// приз получают игроки, все подключения которых к ней (quiz_geo_checks) — из этих стран.
+// allowUnknown — допускать игроков, страну которых определить не удалось.
+func (s *ResultService) SetGeoRestrictions(allowUnknown bool) {
+	s.geoRestricted = true
+	s.geoAllowUnknown = allowUnknown
+}
+
+// prizeEligibleUsers возвращает запрос аккаунтов из userIDs, допущенных к призам викторины:
+// без теневого бана, с подтверждённым email (если это требуется) и из разрешённых стран
+func (s *ResultService) prizeEligibleUsers(tx *gorm.DB, quiz *entity.Quiz, userIDs []uint) *gorm.DB {
+	query := tx.Model(&entity.User{}).Where("id IN ? AND shadow_banned_at IS NULL", userIDs)
+	if s.requireVerifiedForPrizes {
+		query = query.Where("email_verified_at IS NOT NULL")
+	}
+	if !s.geoRestricted || len(quiz.AllowedCountries) == 0 {
+		return query
+	}
+
+	allowed := append([]string{}, quiz.AllowedCountries...)
+	if s.geoAllowUnknown {
+		allowed = append(allowed, "")
+	} else {
+		// Игрок без записанного подключения (например, без IP) — страна неизвестна
+		query = query.Where("EXISTS (SELECT 1 FROM quiz_geo_checks g WHERE g.quiz_id = ? AND g.user_id = users.id)", quiz.ID)
+	}
+	return query.Where("NOT EXISTS (SELECT 1 FROM quiz_geo_checks g WHERE g.quiz_id = ? AND g.user_id = users.id AND g.country NOT IN ?)",
+		quiz.ID, allowed)
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+
+	"gorm.io/gorm"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// maxPrizeLadderTiers — сколько ступеней может быть в лестнице призов
+const maxPrizeLadderTiers = 10
+
+// ValidatePrizeLadder проверяет лестницу призов: доли ступеней в процентах дают ровно 100
+func ValidatePrizeLadder(ladder entity.PrizeLadder) error {
+	if len(ladder) == 0 {
+		return nil
+	}
+	if len(ladder) > maxPrizeLadderTiers {
+		return fmt.Errorf("%w: prize ladder must have at most %d tiers", apperrors.ErrValidation, maxPrizeLadderTiers)
+	}
+	total := 0
+	for i, tier := range ladder {
+		if tier.Top < 0 {
+			return fmt.Errorf("%w: tier %d: top must not be negative", apperrors.ErrValidation, i+1)
+		}
+		if tier.Percent < 1 || tier.Percent > 100 {
+			return fmt.Errorf("%w: tier %d: percent must be between 1 and 100", apperrors.ErrValidation, i+1)
+		}
+		total += tier.Percent
+	}
+	if total != 100 {
+		return fmt.Errorf("%w: prize ladder percents must sum to 100, got %d", apperrors.ErrValidation, total)
+	}
+	return nil
+}
+
+// ladderFinisher — финишировавший игрок в порядке распределения призов
+type ladderFinisher struct {
+	UserID uint
+	Place  int // место среди финишировавших; равные результаты делят место
+}
+
+// allocatePrizeLadder распределяет фонд по ступеням без потерь: доля ступени — процент фонда
+// с округлением вниз, остаток от округления долей достаётся первой ступени. Внутри ступени сумма
+// делится поровну, остаток деления — по единице первым по порядку. finishers упорядочены
+// от лучшего к худшему. Возвращает приз каждого попавшего хотя бы в одну ступень.
+func allocatePrizeLadder(ladder entity.PrizeLadder, fund int, finishers []ladderFinisher) map[uint]int {
+	prizes := make(map[uint]int)
+	if len(ladder) == 0 || len(finishers) == 0 {
+		return prizes
+	}
+	if fund < 0 {
+		fund = 0
+	}
+
+	amounts := make([]int, len(ladder))
+	allocated := 0
+	for i, tier := range ladder {
+		amounts[i] = fund * tier.Percent / 100
+		allocated += amounts[i]
+	}
+	amounts[0] += fund - allocated
+
+	for i, tier := range ladder {
+		members := make([]uint, 0, len(finishers))
+		for _, finisher := range finishers {
+			if tier.Top == 0 || finisher.Place <= tier.Top {
+				members = append(members, finisher.UserID)
+			}
+		}
+		if len(members) == 0 {
+			continue
+		}
+		share, remainder := amounts[i]/len(members), amounts[i]%len(members)
+		for j, userID := range members {
+			prize := share
+			if j < remainder {
+				prize++
+			}
+			prizes[userID] += prize
+		}
+	}
+	return prizes
+}
+
+// prizeGroup — победители, получающие одинаковый приз
+type prizeGroup struct {
+	Amount  int
+	UserIDs []uint
+}
+
+// groupPrizes группирует победителей по сумме приза (от большей к меньшей), сохраняя порядок winnerIDs:
+// сервисы выплат и уведомлений принимают одну сумму на список победителей
+func groupPrizes(winnerIDs []uint, prizes map[uint]int) []prizeGroup {
+	index := make(map[int]int)
+	groups := make([]prizeGroup, 0)
+	for _, userID := range winnerIDs {
+		amount := prizes[userID]
+		i, ok := index[amount]
+		if !ok {
+			i = len(groups)
+			index[amount] = i
+			groups = append(groups, prizeGroup{Amount: amount})
+		}
+		groups[i].UserIDs = append(groups[i].UserIDs, userID)
+	}
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].Amount > groups[j].Amount })
+	return groups
+}
+
+// equalPrizes — приз каждого победителя при делении фонда поровну
+func equalPrizes(winnerIDs []uint, prizePerWinner int) map[uint]int {
+	prizes := make(map[uint]int, len(winnerIDs))
+	for _, userID := range winnerIDs {
+		prizes[userID] = prizePerWinner
+	}
+	return prizes
+}
+
+// allocateLadderPrizes распределяет фонд викторины по лестнице призов в транзакции tx.
+// Финишировавшие — не выбывшие игроки хотя бы с одним верным ответом в порядке рангов
+// (при равенстве — по времени верных ответов и ID); аккаунты, не допущенные к призам, пропускаются.
+func (s *ResultService) allocateLadderPrizes(tx *gorm.DB, quiz *entity.Quiz, fund int) ([]uint, map[uint]int, error) {
+	var results []entity.Result
+	if err := tx.Model(&entity.Result{}).
+		Select("user_id, rank").
+		Where("quiz_id = ? AND is_eliminated = false AND correct_answers > 0", quiz.ID).
+		Order("rank ASC, total_response_time_ms ASC, user_id ASC").
+		Find(&results).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to load finishers: %w", err)
+	}
+
+	finishers := make([]ladderFinisher, 0, len(results))
+	if len(results) > 0 {
+		userIDs := make([]uint, 0, len(results))
+		for _, result := range results {
+			userIDs = append(userIDs, result.UserID)
+		}
+		var eligibleIDs []uint
+		if err := s.prizeEligibleUsers(tx, quiz, userIDs).Pluck("id", &eligibleIDs).Error; err != nil {
+			return nil, nil, fmt.Errorf("failed to apply prize eligibility gate to finishers: %w", err)
+		}
+		eligible := make(map[uint]bool, len(eligibleIDs))
+		for _, id := range eligibleIDs {
+			eligible[id] = true
+		}
+		lastRank := 0
+		for _, result := range results {
+			if !eligible[result.UserID] {
+				continue
+			}
+			place := len(finishers) + 1
+			if len(finishers) > 0 && result.Rank == lastRank {
+				place = finishers[len(finishers)-1].Place
+			}
+			lastRank = result.Rank
+			finishers = append(finishers, ladderFinisher{UserID: result.UserID, Place: place})
+		}
+	}
+
+	prizes := allocatePrizeLadder(quiz.PrizeLadder, fund, finishers)
+	winnerIDs := make([]uint, 0, len(prizes))
+	for _, finisher := range finishers {
+		if _, ok := prizes[finisher.UserID]; ok {
+			winnerIDs = append(winnerIDs, finisher.UserID)
+		}
+	}
+
+	if err := tx.Model(&entity.Result{}).
+		Where("quiz_id = ?", quiz.ID).
+		Updates(map[string]interface{}{"is_winner": false, "prize_fund": 0}).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to reset winners: %w", err)
+	}
+	for _, group := range groupPrizes(winnerIDs, prizes) {
+		if err := tx.Model(&entity.Result{}).
+			Where("quiz_id = ? AND user_id IN ?", quiz.ID, group.UserIDs).
+			Updates(map[string]interface{}{"is_winner": true, "prize_fund": group.Amount}).Error; err != nil {
+			return nil, nil, fmt.Errorf("failed to update ladder winners: %w", err)
+		}
+	}
+	return winnerIDs, prizes, nil
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+func sumPrizes(prizes map[uint]int) int {
+	total := 0
+	for _, prize := range prizes {
+		total += prize
+	}
+	return total
+}
+
+func TestAllocatePrizeLadder_Tiers(t *testing.T) {
+	ladder := entity.PrizeLadder{{Top: 1, Percent: 50}, {Top: 3, Percent: 30}, {Percent: 20}}
+	finishers := []ladderFinisher{{UserID: 1, Place: 1}, {UserID: 2, Place: 2}, {UserID: 3, Place: 3}, {UserID: 4, Place: 4}}
+
+	prizes := allocatePrizeLadder(ladder, 1000, finishers)
+	// 500 первому; 300 на троих; 200 на четверых
+	assert.Equal(t, map[uint]int{1: 650, 2: 150, 3: 150, 4: 50}, prizes)
+	assert.Equal(t, 1000, sumPrizes(prizes))
+}
+
+func TestAllocatePrizeLadder_ExactRemainder(t *testing.T) {
+	ladder := entity.PrizeLadder{{Top: 1, Percent: 33}, {Percent: 67}}
+	finishers := []ladderFinisher{{UserID: 7, Place: 1}, {UserID: 8, Place: 2}, {UserID: 9, Place: 3}}
+
+	prizes := allocatePrizeLadder(ladder, 101, finishers)
+	// Доли: 33 и 67, остаток 1 — первой ступени. 67 на троих: 23, 22, 22
+	assert.Equal(t, map[uint]int{7: 57, 8: 22, 9: 22}, prizes)
+	assert.Equal(t, 101, sumPrizes(prizes))
+}
+
+func TestAllocatePrizeLadder_TiedPlacesShareTier(t *testing.T) {
+	ladder := entity.PrizeLadder{{Top: 1, Percent: 60}, {Percent: 40}}
+	finishers := []ladderFinisher{{UserID: 1, Place: 1}, {UserID: 2, Place: 1}, {UserID: 3, Place: 3}}
+
+	prizes := allocatePrizeLadder(ladder, 100, finishers)
+	assert.Equal(t, map[uint]int{1: 44, 2: 43, 3: 13}, prizes)
+	assert.Equal(t, 100, sumPrizes(prizes))
+
+	assert.Empty(t, allocatePrizeLadder(ladder, 100, nil), "без финишировавших фонд не распределяется")
+}
+
+func TestValidatePrizeLadder(t *testing.T) {
+	assert.NoError(t, ValidatePrizeLadder(nil))
+	assert.NoError(t, ValidatePrizeLadder(entity.PrizeLadder{{Top: 1, Percent: 50}, {Top: 10, Percent: 30}, {Percent: 20}}))
+	assert.ErrorIs(t, ValidatePrizeLadder(entity.PrizeLadder{{Top: 1, Percent: 50}, {Percent: 40}}), apperrors.ErrValidation)
+	assert.ErrorIs(t, ValidatePrizeLadder(entity.PrizeLadder{{Top: -1, Percent: 100}}), apperrors.ErrValidation)
+	assert.ErrorIs(t, ValidatePrizeLadder(entity.PrizeLadder{{Top: 1, Percent: 0}, {Percent: 100}}), apperrors.ErrValidation)
+}
+
+func TestGroupPrizes(t *testing.T) {
+	groups := groupPrizes([]uint{1, 2, 3, 4}, map[uint]int{1: 650, 2: 150, 3: 150, 4: 50})
+	assert.Equal(t, []prizeGroup{
+		{Amount: 650, UserIDs: []uint{1}},
+		{Amount: 150, UserIDs: []uint{2, 3}},
+		{Amount: 50, UserIDs: []uint{4}},
+	}, groups)
+}
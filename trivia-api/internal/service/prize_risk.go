@@ -0,0 +1,100 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SetIPRiskPolicy включает проверку победителей, подключавшихся к викторине с адресов с оценкой
+// риска не ниже threshold (VPN, прокси, дата-центры): такие победители попадают в очередь
+// prize_risk_reviews. action — entity.PrizeRiskActionFlag (приз выплачивается) или
+// entity.PrizeRiskActionExclude (выплата задерживается до решения администратора).
+func (s *ResultService) SetIPRiskPolicy(threshold int, action string) {
+	s.ipRiskThreshold = threshold
+	s.ipRiskAction = action
+}
+
+// reviewHighRiskWinners создает в транзакции tx проверки победителей с рискованными подключениями
+// и возвращает победителей, выплата которым задерживается. Повторное подведение итогов проверки не дублирует.
+func (s *ResultService) reviewHighRiskWinners(tx *gorm.DB, quizID uint, winnerIDs []uint, prizes map[uint]int) (map[uint]bool, error) {
+	if s.ipRiskThreshold <= 0 || len(winnerIDs) == 0 {
+		return nil, nil
+	}
+	var sessions []entity.QuizSessionRisk
+	if err := tx.Where("quiz_id = ? AND user_id IN ? AND score >= ?", quizID, winnerIDs, s.ipRiskThreshold).
+		Order("score DESC").
+		Find(&sessions).Error; err != nil {
+		return nil, fmt.Errorf("failed to load risky sessions of winners: %w", err)
+	}
+	if len(sessions) == 0 {
+		return nil, nil
+	}
+
+	reviews := make(map[uint]*entity.PrizeRiskReview)
+	var order []uint
+	for _, session := range sessions {
+		review, ok := reviews[session.UserID]
+		if !ok {
+			review = &entity.PrizeRiskReview{
+				QuizID:      quizID,
+				UserID:      session.UserID,
+				Score:       session.Score, // сессии упорядочены по убыванию оценки
+				Reasons:     entity.StringArray{},
+				IPAddresses: entity.StringArray{},
+				Amount:      prizes[session.UserID],
+				Action:      s.ipRiskAction,
+				Status:      entity.PrizeRiskReviewPending,
+			}
+			reviews[session.UserID] = review
+			order = append(order, session.UserID)
+		}
+		review.IPAddresses = append(review.IPAddresses, session.IPAddress)
+		for _, reason := range session.Reasons {
+			if !containsString(review.Reasons, reason) {
+				review.Reasons = append(review.Reasons, reason)
+			}
+		}
+	}
+
+	held := make(map[uint]bool)
+	for _, userID := range order {
+		review := reviews[userID]
+		sort.Strings(review.Reasons)
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(review).Error; err != nil {
+			return nil, fmt.Errorf("failed to create prize risk review: %w", err)
+		}
+		if s.ipRiskAction == entity.PrizeRiskActionExclude {
+			held[userID] = true
+		}
+	}
+	log.Printf("[ResultService] Викторина #%d: победителей с рискованными подключениями — %d (%s)", quizID, len(order), s.ipRiskAction)
+	return held, nil
+}
+
+// withoutUsers возвращает userIDs без пользователей из excluded
+func withoutUsers(userIDs []uint, excluded map[uint]bool) []uint {
+	if len(excluded) == 0 {
+		return userIDs
+	}
+	kept := make([]uint, 0, len(userIDs))
+	for _, id := range userIDs {
+		if !excluded[id] {
+			kept = append(kept, id)
+		}
+	}
+	return kept
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
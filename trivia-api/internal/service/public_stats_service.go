@@ -0,0 +1,143 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+const (
+	publicStatsCacheKey = "public:stats"
+	// PublicStatsCacheTTL — время жизни снимка публичных счётчиков в Redis
+	PublicStatsCacheTTL = 30 * time.Second
+	// publicStatsSampleWindow — снимки подключений старше окна не учитываются
+	// (сэмплер пишет их раз в минуту, см. AnalyticsService.StartConnectionSampler)
+	publicStatsSampleWindow = 2 * time.Minute
+)
+
+// PublicNextQuiz — ближайшая запланированная викторина для обратного отсчёта на лендинге
+type PublicNextQuiz struct {
+	ID              uint      `json:"id"`
+	Title           string    `json:"title"`
+	ScheduledTime   time.Time `json:"scheduled_time"`
+	StartsInSeconds int64     `json:"starts_in_seconds"`
+}
+
+// PublicStats — счётчики для встраивания на лендинг
+type PublicStats struct {
+	TotalPlayers  int64           `json:"total_players"`
+	OnlinePlayers int64           `json:"online_players"`
+	NextQuiz      *PublicNextQuiz `json:"next_quiz"`       // nil — нет запланированных викторин
+	LastPrizePool *int            `json:"last_prize_pool"` // призовой фонд последней завершённой викторины
+	GeneratedAt   time.Time       `json:"generated_at"`
+}
+
+// PublicStatsService собирает публичные счётчики. Снимок кешируется в Redis на PublicStatsCacheTTL,
+// одновременные промахи кеша объединяются, поэтому нагрузка на БД не зависит от числа посетителей лендинга.
+type PublicStatsService struct {
+	repo      repository.PublicStatsRepository
+	cacheRepo repository.CacheRepository
+	// counter — подключения этого экземпляра, пока сэмплер не записал первый снимок
+	counter ConnectionCounter
+	loads   singleflight.Group
+}
+
+// NewPublicStatsService создает сервис публичных счётчиков
+func NewPublicStatsService(repo repository.PublicStatsRepository, cacheRepo repository.CacheRepository, counter ConnectionCounter) *PublicStatsService {
+	return &PublicStatsService{repo: repo, cacheRepo: cacheRepo, counter: counter}
+}
+
+// GetStats возвращает счётчики. Обратный отсчёт до викторины пересчитывается на каждый запрос,
+// остальные значения могут отставать на время жизни кеша.
+func (s *PublicStatsService) GetStats() (*PublicStats, error) {
+	stats, err := s.cachedStats()
+	if err != nil {
+		return nil, err
+	}
+	result := *stats
+	if stats.NextQuiz != nil {
+		next := *stats.NextQuiz
+		next.StartsInSeconds = int64(time.Until(next.ScheduledTime).Seconds())
+		if next.StartsInSeconds < 0 {
+			next.StartsInSeconds = 0
+		}
+		result.NextQuiz = &next
+	}
+	return &result, nil
+}
+
+func (s *PublicStatsService) cachedStats() (*PublicStats, error) {
+	if s.cacheRepo != nil {
+		var cached PublicStats
+		err := s.cacheRepo.GetJSON(publicStatsCacheKey, &cached)
+		if err == nil {
+			return &cached, nil
+		}
+		if !errors.Is(err, apperrors.ErrNotFound) {
+			log.Printf("[PublicStatsService] Ошибка чтения кеша счётчиков: %v", err)
+		}
+	}
+
+	value, err, _ := s.loads.Do(publicStatsCacheKey, func() (interface{}, error) {
+		stats, err := s.buildStats()
+		if err != nil {
+			return nil, err
+		}
+		if s.cacheRepo != nil {
+			if err := s.cacheRepo.SetJSON(publicStatsCacheKey, stats, PublicStatsCacheTTL); err != nil {
+				log.Printf("[PublicStatsService] Ошибка сохранения счётчиков в кеш: %v", err)
+			}
+		}
+		return stats, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*PublicStats), nil
+}
+
+func (s *PublicStatsService) buildStats() (*PublicStats, error) {
+	now := time.Now().UTC()
+	stats := &PublicStats{GeneratedAt: now}
+
+	players, err := s.repo.CountPlayers()
+	if err != nil {
+		return nil, err
+	}
+	stats.TotalPlayers = players
+
+	online, err := s.repo.OnlineConnections(now.Add(-publicStatsSampleWindow))
+	if err != nil {
+		return nil, err
+	}
+	if s.counter != nil {
+		if local := int64(s.counter.ClientCount()); local > online {
+			online = local
+		}
+	}
+	stats.OnlinePlayers = online
+
+	next, err := s.repo.NextScheduledQuiz()
+	switch {
+	case err == nil:
+		stats.NextQuiz = &PublicNextQuiz{ID: next.ID, Title: next.Title, ScheduledTime: next.ScheduledTime.UTC()}
+	case !errors.Is(err, apperrors.ErrNotFound):
+		return nil, fmt.Errorf("failed to get next quiz: %w", err)
+	}
+
+	last, err := s.repo.LastCompletedQuiz()
+	switch {
+	case err == nil:
+		prizeFund := last.PrizeFund
+		stats.LastPrizePool = &prizeFund
+	case !errors.Is(err, apperrors.ErrNotFound):
+		return nil, fmt.Errorf("failed to get last completed quiz: %w", err)
+	}
+	return stats, nil
+}
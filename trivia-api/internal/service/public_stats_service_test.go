@@ -0,0 +1,88 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+type fakePublicStatsRepo struct {
+	players int64
+	online  int64
+	next    *entity.Quiz
+	last    *entity.Quiz
+	calls   int
+}
+
+func (f *fakePublicStatsRepo) CountPlayers() (int64, error) {
+	f.calls++
+	return f.players, nil
+}
+
+func (f *fakePublicStatsRepo) NextScheduledQuiz() (*entity.Quiz, error) {
+	if f.next == nil {
+		return nil, apperrors.ErrNotFound
+	}
+	return f.next, nil
+}
+
+func (f *fakePublicStatsRepo) LastCompletedQuiz() (*entity.Quiz, error) {
+	if f.last == nil {
+		return nil, apperrors.ErrNotFound
+	}
+	return f.last, nil
+}
+
+func (f *fakePublicStatsRepo) OnlineConnections(since time.Time) (int64, error) {
+	return f.online, nil
+}
+
+type fixedConnectionCounter int
+
+func (c fixedConnectionCounter) ClientCount() int { return int(c) }
+
+func TestPublicStatsService_GetStats(t *testing.T) {
+	repo := &fakePublicStatsRepo{
+		players: 1200,
+		online:  340,
+		next:    &entity.Quiz{ID: 5, Title: "Вечерняя викторина", ScheduledTime: time.Now().Add(10 * time.Minute)},
+		last:    &entity.Quiz{ID: 4, PrizeFund: 500000},
+	}
+	cache := &memoryJSONCache{values: map[string][]byte{}}
+	svc := NewPublicStatsService(repo, cache, fixedConnectionCounter(12))
+
+	stats, err := svc.GetStats()
+	require.NoError(t, err)
+	assert.EqualValues(t, 1200, stats.TotalPlayers)
+	assert.EqualValues(t, 340, stats.OnlinePlayers)
+	require.NotNil(t, stats.NextQuiz)
+	assert.Equal(t, uint(5), stats.NextQuiz.ID)
+	assert.InDelta(t, 600, stats.NextQuiz.StartsInSeconds, 2)
+	require.NotNil(t, stats.LastPrizePool)
+	assert.Equal(t, 500000, *stats.LastPrizePool)
+
+	// Второе чтение — из кеша, отсчёт пересчитывается от закешированного времени старта
+	repo.players = 1300
+	stats, err = svc.GetStats()
+	require.NoError(t, err)
+	assert.EqualValues(t, 1200, stats.TotalPlayers)
+	assert.Equal(t, 1, repo.calls)
+	require.NotNil(t, stats.NextQuiz)
+	assert.InDelta(t, 600, stats.NextQuiz.StartsInSeconds, 2)
+}
+
+func TestPublicStatsService_GetStats_Empty(t *testing.T) {
+	// Снимков подключений ещё нет — берётся счётчик своего экземпляра
+	svc := NewPublicStatsService(&fakePublicStatsRepo{players: 3}, nil, fixedConnectionCounter(7))
+
+	stats, err := svc.GetStats()
+	require.NoError(t, err)
+	assert.EqualValues(t, 7, stats.OnlinePlayers)
+	assert.Nil(t, stats.NextQuiz)
+	assert.Nil(t, stats.LastPrizePool)
+}
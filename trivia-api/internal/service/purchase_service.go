@@ -0,0 +1,346 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/config"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// PurchaseProduct — продукт магазина и права, которые он даёт
+type PurchaseProduct struct {
+	ID           string
+	Entitlements []string
+	Subscription bool // автовозобновляемая подписка; иначе разовая покупка без срока
+}
+
+// PurchaseVerifyRequest — покупка, которую нужно проверить в магазине
+type PurchaseVerifyRequest struct {
+	ProductID     string
+	TransactionID string // transactionId App Store или purchaseToken Google Play
+	Subscription  bool   // заполняется сервисом по настройкам продукта
+}
+
+// StorePurchase — состояние покупки по данным магазина
+type StorePurchase struct {
+	TransactionID    string // идентификатор, постоянный для всех продлений подписки
+	ProductID        string
+	Environment      string // production или sandbox
+	Status           string // entity.PurchaseStatus*
+	AutoRenew        bool
+	PurchasedAt      time.Time
+	ExpiresAt        *time.Time
+	RevokedAt        *time.Time
+	NeedsAcknowledge bool // Google Play: покупку нужно подтвердить, иначе магазин вернёт деньги
+}
+
+// PurchaseNotificationRequest — входящее серверное уведомление магазина
+type PurchaseNotificationRequest struct {
+	Header http.Header
+	Query  url.Values
+	Body   []byte
+}
+
+// PurchaseNotification — покупка, о которой сообщил магазин. Состояние покупки
+// всегда перечитывается через API магазина; уведомлению доверяется только отзыв
+// покупки, о котором API Google Play не сообщает.
+type PurchaseNotification struct {
+	Request PurchaseVerifyRequest // ProductID может быть пуст — тогда берётся из сохранённой покупки
+	Revoked bool
+}
+
+// PurchaseStoreClient проверяет покупки через серверный API магазина
+type PurchaseStoreClient interface {
+	Store() string
+	// Verify возвращает состояние покупки. Если магазин не знает покупку — apperrors.ErrValidation.
+	Verify(ctx context.Context, req PurchaseVerifyRequest) (*StorePurchase, error)
+	// ParseNotification проверяет подлинность уведомления (apperrors.ErrUnauthorized при неудаче)
+	// и возвращает покупку, о которой оно; nil — уведомление без покупки (тестовое).
+	ParseNotification(ctx context.Context, req PurchaseNotificationRequest) (*PurchaseNotification, error)
+}
+
+// PurchaseAcknowledger подтверждает получение покупки в магазинах, которые этого требуют
+type PurchaseAcknowledger interface {
+	Acknowledge(ctx context.Context, req PurchaseVerifyRequest) error
+}
+
+// PurchaseService проверяет покупки в магазинах приложений и ведёт права пользователей
+// (премиум-тариф: без рекламы, дополнительные подсказки). Продления, истечения и возвраты
+// приходят серверными уведомлениями магазинов; истёкшие права отсекаются по сроку при чтении.
+type PurchaseService struct {
+	repo     repository.PurchaseRepository
+	products map[string]PurchaseProduct
+	stores   map[string]PurchaseStoreClient
+	now      func() time.Time
+}
+
+// NewPurchaseService создает сервис покупок для перечисленных продуктов
+func NewPurchaseService(repo repository.PurchaseRepository, products []PurchaseProduct) (*PurchaseService, error) {
+	if len(products) == 0 {
+		return nil, fmt.Errorf("at least one purchase product is required")
+	}
+	byID := make(map[string]PurchaseProduct, len(products))
+	for _, product := range products {
+		if product.ID == "" || len(product.Entitlements) == 0 {
+			return nil, fmt.Errorf("purchase product must have id and entitlements")
+		}
+		if _, ok := byID[product.ID]; ok {
+			return nil, fmt.Errorf("purchase product %q is listed more than once", product.ID)
+		}
+		byID[product.ID] = product
+	}
+	return &PurchaseService{
+		repo:     repo,
+		products: byID,
+		stores:   make(map[string]PurchaseStoreClient),
+		now:      time.Now,
+	}, nil
+}
+
+// NewPurchaseServiceFromConfig создает сервис покупок и клиентов настроенных магазинов
+func NewPurchaseServiceFromConfig(repo repository.PurchaseRepository, cfg config.PurchasesConfig) (*PurchaseService, error) {
+	products := make([]PurchaseProduct, 0, len(cfg.Products))
+	for _, product := range cfg.Products {
+		products = append(products, PurchaseProduct{
+			ID:           product.ID,
+			Entitlements: product.Entitlements,
+			Subscription: product.Subscription,
+		})
+	}
+	s, err := NewPurchaseService(repo, products)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Apple.IssuerID != "" {
+		apple, err := NewAppStorePurchaseClient(AppStoreConfig{
+			IssuerID:       cfg.Apple.IssuerID,
+			KeyID:          cfg.Apple.KeyID,
+			PrivateKeyFile: cfg.Apple.PrivateKeyFile,
+			BundleID:       cfg.Apple.BundleID,
+			Sandbox:        cfg.Apple.Environment == "sandbox",
+			AllowSandbox:   cfg.Apple.AllowSandbox,
+		})
+		if err != nil {
+			return nil, err
+		}
+		s.RegisterStore(apple)
+	}
+	if cfg.Google.PackageName != "" {
+		google, err := NewGooglePlayPurchaseClient(GooglePlayConfig{
+			PackageName:        cfg.Google.PackageName,
+			ServiceAccountFile: cfg.Google.ServiceAccountFile,
+			NotificationToken:  cfg.Google.NotificationToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		s.RegisterStore(google)
+	}
+	return s, nil
+}
+
+// RegisterStore подключает магазин
+func (s *PurchaseService) RegisterStore(client PurchaseStoreClient) {
+	s.stores[client.Store()] = client
+}
+
+// VerifyPurchase проверяет покупку, присланную клиентом после оплаты, сохраняет её за
+// пользователем и пересчитывает его права. Покупка другого пользователя — apperrors.ErrConflict.
+func (s *PurchaseService) VerifyPurchase(ctx context.Context, userID uint, store string, req PurchaseVerifyRequest) (*entity.Purchase, error) {
+	client, ok := s.stores[store]
+	if !ok {
+		return nil, fmt.Errorf("%w: store %q is not supported", apperrors.ErrValidation, store)
+	}
+	product, ok := s.products[req.ProductID]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown product %q", apperrors.ErrValidation, req.ProductID)
+	}
+	req.TransactionID = strings.TrimSpace(req.TransactionID)
+	if req.TransactionID == "" {
+		return nil, fmt.Errorf("%w: transaction_id is required", apperrors.ErrValidation)
+	}
+	req.Subscription = product.Subscription
+
+	storePurchase, err := client.Verify(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	// Права выдаются по продукту из ответа магазина: подписку могли перевести на другой продукт группы
+	storeProduct, ok := s.products[storePurchase.ProductID]
+	if !ok {
+		return nil, fmt.Errorf("%w: purchase is for unknown product %q", apperrors.ErrValidation, storePurchase.ProductID)
+	}
+	// Вид продукта задаёт проверку в магазине: подписка, выданная за разовую покупку, прошла бы
+	// без срока действия и дала бы права навсегда
+	if storeProduct.Subscription != product.Subscription {
+		return nil, fmt.Errorf("%w: purchase is for product %q, not %q", apperrors.ErrValidation, storePurchase.ProductID, req.ProductID)
+	}
+
+	purchase, err := s.repo.GetByTransaction(store, storePurchase.TransactionID)
+	switch {
+	case err == nil && purchase.UserID != userID:
+		return nil, fmt.Errorf("%w: purchase belongs to another account", apperrors.ErrConflict)
+	case errors.Is(err, apperrors.ErrNotFound):
+		purchase = &entity.Purchase{UserID: userID, Store: store}
+	case err != nil:
+		return nil, err
+	}
+	if err := s.savePurchase(ctx, client, purchase, storePurchase, req); err != nil {
+		return nil, err
+	}
+	return purchase, nil
+}
+
+// HandleNotification обрабатывает серверное уведомление магазина: продление, истечение,
+// отмену автопродления или возврат. Уведомления о покупках, которые ни один пользователь
+// ещё не подтвердил, пропускаются — права выдаст VerifyPurchase.
+func (s *PurchaseService) HandleNotification(ctx context.Context, store string, req PurchaseNotificationRequest) error {
+	client, ok := s.stores[store]
+	if !ok {
+		return apperrors.ErrNotFound
+	}
+	notification, err := client.ParseNotification(ctx, req)
+	if err != nil || notification == nil {
+		return err
+	}
+
+	verifyReq := notification.Request
+	purchase, err := s.repo.GetByTransaction(store, verifyReq.TransactionID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			log.Printf("[PurchaseService] Уведомление %s о неизвестной покупке пропущено", store)
+			return nil
+		}
+		return err
+	}
+	if verifyReq.ProductID == "" {
+		verifyReq.ProductID = purchase.ProductID
+	}
+	verifyReq.Subscription = s.products[verifyReq.ProductID].Subscription
+
+	if notification.Revoked {
+		now := s.now()
+		purchase.Status = entity.PurchaseStatusRevoked
+		purchase.RevokedAt = &now
+		if err := s.repo.SavePurchase(purchase); err != nil {
+			return err
+		}
+		return s.refreshEntitlements(purchase.UserID)
+	}
+
+	storePurchase, err := client.Verify(ctx, verifyReq)
+	if err != nil {
+		return err
+	}
+	return s.savePurchase(ctx, client, purchase, storePurchase, verifyReq)
+}
+
+// savePurchase переносит состояние из магазина в покупку, сохраняет её, подтверждает
+// оплаченную покупку в магазине и пересчитывает права пользователя
+func (s *PurchaseService) savePurchase(ctx context.Context, client PurchaseStoreClient, purchase *entity.Purchase, storePurchase *StorePurchase, req PurchaseVerifyRequest) error {
+	purchase.TransactionID = storePurchase.TransactionID
+	purchase.ProductID = storePurchase.ProductID
+	purchase.Environment = storePurchase.Environment
+	purchase.Status = storePurchase.Status
+	purchase.AutoRenew = storePurchase.AutoRenew
+	purchase.PurchasedAt = storePurchase.PurchasedAt
+	purchase.ExpiresAt = storePurchase.ExpiresAt
+	if purchase.RevokedAt == nil || storePurchase.RevokedAt == nil {
+		purchase.RevokedAt = storePurchase.RevokedAt
+	}
+	if err := s.repo.SavePurchase(purchase); err != nil {
+		return err
+	}
+
+	if acknowledger, ok := client.(PurchaseAcknowledger); ok && storePurchase.NeedsAcknowledge && purchase.Status == entity.PurchaseStatusActive {
+		// Неподтверждённая покупка не теряется: магазин пришлёт уведомление, и подтверждение повторится
+		if err := acknowledger.Acknowledge(ctx, req); err != nil {
+			log.Printf("[PurchaseService] Не удалось подтвердить покупку %d в %s: %v", purchase.ID, purchase.Store, err)
+		}
+	}
+	return s.refreshEntitlements(purchase.UserID)
+}
+
+// refreshEntitlements пересчитывает права пользователя по всем его действующим покупкам.
+// Если право дают несколько покупок, берётся самый поздний срок (бессрочная покупка главнее).
+func (s *PurchaseService) refreshEntitlements(userID uint) error {
+	purchases, err := s.repo.ListByUser(userID)
+	if err != nil {
+		return err
+	}
+
+	now := s.now()
+	byKey := make(map[string]entity.UserEntitlement)
+	for i := range purchases {
+		purchase := &purchases[i]
+		if !purchase.GrantsAccess(now) {
+			continue
+		}
+		for _, key := range s.products[purchase.ProductID].Entitlements {
+			current, ok := byKey[key]
+			if ok && (current.ExpiresAt == nil || (purchase.ExpiresAt != nil && !purchase.ExpiresAt.After(*current.ExpiresAt))) {
+				continue
+			}
+			byKey[key] = entity.UserEntitlement{
+				UserID:      userID,
+				Entitlement: key,
+				PurchaseID:  purchase.ID,
+				Store:       purchase.Store,
+				ProductID:   purchase.ProductID,
+				ExpiresAt:   purchase.ExpiresAt,
+				UpdatedAt:   now,
+			}
+		}
+	}
+
+	entitlements := make([]entity.UserEntitlement, 0, len(byKey))
+	for _, entitlement := range byKey {
+		entitlements = append(entitlements, entitlement)
+	}
+	return s.repo.ReplaceEntitlements(userID, entitlements)
+}
+
+// GetEntitlements возвращает действующие права пользователя
+func (s *PurchaseService) GetEntitlements(userID uint) ([]entity.UserEntitlement, error) {
+	entitlements, err := s.repo.ListEntitlements(userID)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	active := make([]entity.UserEntitlement, 0, len(entitlements))
+	for i := range entitlements {
+		if entitlements[i].ActiveAt(now) {
+			active = append(active, entitlements[i])
+		}
+	}
+	return active, nil
+}
+
+// HasEntitlement сообщает, есть ли у пользователя действующее право
+func (s *PurchaseService) HasEntitlement(userID uint, entitlement string) (bool, error) {
+	entitlements, err := s.GetEntitlements(userID)
+	if err != nil {
+		return false, err
+	}
+	for _, e := range entitlements {
+		if e.Entitlement == entitlement {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ListPurchases возвращает покупки пользователя
+func (s *PurchaseService) ListPurchases(userID uint) ([]entity.Purchase, error) {
+	return s.repo.ListByUser(userID)
+}
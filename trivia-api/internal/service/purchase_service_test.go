@@ -0,0 +1,303 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// fakePurchaseRepo — PurchaseRepository в памяти
+type fakePurchaseRepo struct {
+	purchases    []entity.Purchase
+	entitlements map[uint][]entity.UserEntitlement
+}
+
+func newFakePurchaseRepo() *fakePurchaseRepo {
+	return &fakePurchaseRepo{entitlements: make(map[uint][]entity.UserEntitlement)}
+}
+
+func (f *fakePurchaseRepo) SavePurchase(purchase *entity.Purchase) error {
+	for i := range f.purchases {
+		if f.purchases[i].Store == purchase.Store && f.purchases[i].TransactionID == purchase.TransactionID {
+			purchase.ID = f.purchases[i].ID
+			purchase.UserID = f.purchases[i].UserID
+			f.purchases[i] = *purchase
+			return nil
+		}
+	}
+	purchase.ID = uint(len(f.purchases) + 1)
+	f.purchases = append(f.purchases, *purchase)
+	return nil
+}
+
+func (f *fakePurchaseRepo) GetByTransaction(store, transactionID string) (*entity.Purchase, error) {
+	for _, p := range f.purchases {
+		if p.Store == store && p.TransactionID == transactionID {
+			return &p, nil
+		}
+	}
+	return nil, apperrors.ErrNotFound
+}
+
+func (f *fakePurchaseRepo) ListByUser(userID uint) ([]entity.Purchase, error) {
+	var result []entity.Purchase
+	for _, p := range f.purchases {
+		if p.UserID == userID {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakePurchaseRepo) ReplaceEntitlements(userID uint, entitlements []entity.UserEntitlement) error {
+	f.entitlements[userID] = entitlements
+	return nil
+}
+
+func (f *fakePurchaseRepo) ListEntitlements(userID uint) ([]entity.UserEntitlement, error) {
+	return f.entitlements[userID], nil
+}
+
+// fakePurchaseStore отвечает заранее заданными состояниями покупок
+type fakePurchaseStore struct {
+	purchases    map[string]*StorePurchase
+	notification *PurchaseNotification
+	acknowledged []string
+}
+
+func (f *fakePurchaseStore) Store() string { return entity.PurchaseStoreGoogle }
+
+func (f *fakePurchaseStore) Verify(ctx context.Context, req PurchaseVerifyRequest) (*StorePurchase, error) {
+	purchase, ok := f.purchases[req.TransactionID]
+	if !ok {
+		return nil, apperrors.ErrValidation
+	}
+	copied := *purchase
+	// Как App Store: без признака подписки статус подписки не запрашивается и срока нет
+	if !req.Subscription {
+		copied.ExpiresAt = nil
+		copied.AutoRenew = false
+	}
+	return &copied, nil
+}
+
+func (f *fakePurchaseStore) ParseNotification(ctx context.Context, req PurchaseNotificationRequest) (*PurchaseNotification, error) {
+	return f.notification, nil
+}
+
+func (f *fakePurchaseStore) Acknowledge(ctx context.Context, req PurchaseVerifyRequest) error {
+	f.acknowledged = append(f.acknowledged, req.TransactionID)
+	return nil
+}
+
+func newTestPurchaseService(t *testing.T, now time.Time) (*PurchaseService, *fakePurchaseRepo, *fakePurchaseStore) {
+	t.Helper()
+	repo := newFakePurchaseRepo()
+	svc, err := NewPurchaseService(repo, []PurchaseProduct{
+		{ID: "premium.monthly", Entitlements: []string{entity.EntitlementPremium, entity.EntitlementAdFree}, Subscription: true},
+		{ID: "adfree.lifetime", Entitlements: []string{entity.EntitlementAdFree}},
+	})
+	require.NoError(t, err)
+	svc.now = func() time.Time { return now }
+	store := &fakePurchaseStore{purchases: make(map[string]*StorePurchase)}
+	svc.RegisterStore(store)
+	return svc, repo, store
+}
+
+func entitlementKeys(entitlements []entity.UserEntitlement) map[string]*time.Time {
+	keys := make(map[string]*time.Time, len(entitlements))
+	for _, e := range entitlements {
+		keys[e.Entitlement] = e.ExpiresAt
+	}
+	return keys
+}
+
+func TestPurchaseService_VerifyPurchase(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	svc, _, store := newTestPurchaseService(t, now)
+	monthEnd := now.AddDate(0, 1, 0)
+	store.purchases["sub-token"] = &StorePurchase{
+		TransactionID: "sub-token", ProductID: "premium.monthly", Status: entity.PurchaseStatusActive,
+		AutoRenew: true, PurchasedAt: now, ExpiresAt: &monthEnd, NeedsAcknowledge: true,
+	}
+	store.purchases["lifetime-token"] = &StorePurchase{
+		TransactionID: "lifetime-token", ProductID: "adfree.lifetime", Status: entity.PurchaseStatusActive, PurchasedAt: now,
+	}
+	ctx := context.Background()
+
+	purchase, err := svc.VerifyPurchase(ctx, 7, entity.PurchaseStoreGoogle, PurchaseVerifyRequest{ProductID: "premium.monthly", TransactionID: "sub-token"})
+	require.NoError(t, err)
+	assert.Equal(t, uint(7), purchase.UserID)
+	assert.Equal(t, []string{"sub-token"}, store.acknowledged)
+
+	entitlements, err := svc.GetEntitlements(7)
+	require.NoError(t, err)
+	keys := entitlementKeys(entitlements)
+	require.Len(t, keys, 2)
+	assert.Equal(t, monthEnd, *keys[entity.EntitlementPremium])
+
+	// Бессрочная покупка главнее подписки для общего права
+	_, err = svc.VerifyPurchase(ctx, 7, entity.PurchaseStoreGoogle, PurchaseVerifyRequest{ProductID: "adfree.lifetime", TransactionID: "lifetime-token"})
+	require.NoError(t, err)
+	entitlements, _ = svc.GetEntitlements(7)
+	keys = entitlementKeys(entitlements)
+	assert.Nil(t, keys[entity.EntitlementAdFree])
+
+	ok, err := svc.HasEntitlement(7, entity.EntitlementPremium)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// Чужая покупка и неизвестные продукт/магазин отклоняются
+	_, err = svc.VerifyPurchase(ctx, 8, entity.PurchaseStoreGoogle, PurchaseVerifyRequest{ProductID: "premium.monthly", TransactionID: "sub-token"})
+	assert.True(t, errors.Is(err, apperrors.ErrConflict))
+	_, err = svc.VerifyPurchase(ctx, 7, entity.PurchaseStoreGoogle, PurchaseVerifyRequest{ProductID: "coins.100", TransactionID: "sub-token"})
+	assert.True(t, errors.Is(err, apperrors.ErrValidation))
+	_, err = svc.VerifyPurchase(ctx, 7, entity.PurchaseStoreApple, PurchaseVerifyRequest{ProductID: "premium.monthly", TransactionID: "sub-token"})
+	assert.True(t, errors.Is(err, apperrors.ErrValidation))
+}
+
+// Транзакция подписки, выданная за разовую покупку, не даёт бессрочных прав
+func TestPurchaseService_VerifyPurchaseRejectsMismatchedProduct(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	svc, repo, store := newTestPurchaseService(t, now)
+	monthEnd := now.AddDate(0, 1, 0)
+	store.purchases["sub-token"] = &StorePurchase{
+		TransactionID: "sub-token", ProductID: "premium.monthly", Status: entity.PurchaseStatusActive,
+		AutoRenew: true, PurchasedAt: now, ExpiresAt: &monthEnd,
+	}
+	store.purchases["lifetime-token"] = &StorePurchase{
+		TransactionID: "lifetime-token", ProductID: "adfree.lifetime", Status: entity.PurchaseStatusActive, PurchasedAt: now,
+	}
+	ctx := context.Background()
+
+	_, err := svc.VerifyPurchase(ctx, 7, entity.PurchaseStoreGoogle, PurchaseVerifyRequest{ProductID: "adfree.lifetime", TransactionID: "sub-token"})
+	assert.True(t, errors.Is(err, apperrors.ErrValidation))
+	_, err = svc.VerifyPurchase(ctx, 7, entity.PurchaseStoreGoogle, PurchaseVerifyRequest{ProductID: "premium.monthly", TransactionID: "lifetime-token"})
+	assert.True(t, errors.Is(err, apperrors.ErrValidation))
+
+	assert.Empty(t, repo.purchases, "покупка не сохраняется")
+	ok, err := svc.HasEntitlement(7, entity.EntitlementPremium)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	ok, err = svc.HasEntitlement(7, entity.EntitlementAdFree)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestPurchaseService_HandleNotification(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	svc, repo, store := newTestPurchaseService(t, now)
+	monthEnd := now.AddDate(0, 1, 0)
+	store.purchases["sub-token"] = &StorePurchase{
+		TransactionID: "sub-token", ProductID: "premium.monthly", Status: entity.PurchaseStatusActive, PurchasedAt: now, ExpiresAt: &monthEnd,
+	}
+	ctx := context.Background()
+
+	// Уведомление о покупке, которую ещё никто не подтвердил, пропускается
+	store.notification = &PurchaseNotification{Request: PurchaseVerifyRequest{TransactionID: "sub-token"}}
+	require.NoError(t, svc.HandleNotification(ctx, entity.PurchaseStoreGoogle, PurchaseNotificationRequest{}))
+	assert.Empty(t, repo.purchases)
+
+	_, err := svc.VerifyPurchase(ctx, 7, entity.PurchaseStoreGoogle, PurchaseVerifyRequest{ProductID: "premium.monthly", TransactionID: "sub-token"})
+	require.NoError(t, err)
+
+	// Продление: срок перечитывается из магазина, продукт берётся из сохранённой покупки
+	nextMonthEnd := monthEnd.AddDate(0, 1, 0)
+	store.purchases["sub-token"].ExpiresAt = &nextMonthEnd
+	require.NoError(t, svc.HandleNotification(ctx, entity.PurchaseStoreGoogle, PurchaseNotificationRequest{}))
+	entitlements, _ := svc.GetEntitlements(7)
+	assert.Equal(t, nextMonthEnd, *entitlementKeys(entitlements)[entity.EntitlementPremium])
+
+	// Истёкшее право не возвращается, даже если уведомления не было
+	svc.now = func() time.Time { return nextMonthEnd.Add(time.Second) }
+	entitlements, _ = svc.GetEntitlements(7)
+	assert.Empty(t, entitlements)
+	svc.now = func() time.Time { return now }
+
+	// Возврат отзывает покупку и права
+	store.notification = &PurchaseNotification{Request: PurchaseVerifyRequest{TransactionID: "sub-token"}, Revoked: true}
+	require.NoError(t, svc.HandleNotification(ctx, entity.PurchaseStoreGoogle, PurchaseNotificationRequest{}))
+	assert.Equal(t, entity.PurchaseStatusRevoked, repo.purchases[0].Status)
+	entitlements, _ = svc.GetEntitlements(7)
+	assert.Empty(t, entitlements)
+
+	assert.True(t, errors.Is(svc.HandleNotification(ctx, "amazon", PurchaseNotificationRequest{}), apperrors.ErrNotFound))
+}
+
+func TestGooglePlayPurchaseClient_ParseNotification(t *testing.T) {
+	client := &GooglePlayPurchaseClient{cfg: GooglePlayConfig{PackageName: "com.example.trivia", NotificationToken: "secret"}}
+	pubsub := func(notification string) []byte {
+		body, _ := json.Marshal(map[string]interface{}{
+			"message": map[string]string{"data": base64.StdEncoding.EncodeToString([]byte(notification))},
+		})
+		return body
+	}
+	query := url.Values{"token": {"secret"}}
+	ctx := context.Background()
+
+	_, err := client.ParseNotification(ctx, PurchaseNotificationRequest{Query: url.Values{"token": {"wrong"}}, Body: pubsub(`{}`)})
+	assert.True(t, errors.Is(err, apperrors.ErrUnauthorized))
+
+	n, err := client.ParseNotification(ctx, PurchaseNotificationRequest{Query: query, Body: pubsub(
+		`{"packageName":"com.example.trivia","subscriptionNotification":{"notificationType":2,"purchaseToken":"tok","subscriptionId":"premium.monthly"}}`)})
+	require.NoError(t, err)
+	assert.Equal(t, PurchaseVerifyRequest{ProductID: "premium.monthly", TransactionID: "tok"}, n.Request)
+	assert.False(t, n.Revoked)
+
+	n, err = client.ParseNotification(ctx, PurchaseNotificationRequest{Query: query, Body: pubsub(
+		`{"packageName":"com.example.trivia","voidedPurchaseNotification":{"purchaseToken":"tok","orderId":"GPA.1","productType":1}}`)})
+	require.NoError(t, err)
+	assert.True(t, n.Revoked)
+	assert.Equal(t, "tok", n.Request.TransactionID)
+
+	n, err = client.ParseNotification(ctx, PurchaseNotificationRequest{Query: query, Body: pubsub(
+		`{"packageName":"com.example.trivia","testNotification":{"version":"1.0"}}`)})
+	require.NoError(t, err)
+	assert.Nil(t, n)
+}
+
+func TestAppStorePurchaseClient_ParseNotification(t *testing.T) {
+	client := &AppStorePurchaseClient{cfg: AppStoreConfig{BundleID: "com.example.trivia"}}
+	jws := func(payload interface{}) string {
+		raw, _ := json.Marshal(payload)
+		return "eyJhbGciOiJFUzI1NiJ9." + base64.RawURLEncoding.EncodeToString(raw) + ".c2ln"
+	}
+	body := func(payload interface{}) []byte {
+		raw, _ := json.Marshal(map[string]string{"signedPayload": jws(payload)})
+		return raw
+	}
+	ctx := context.Background()
+
+	n, err := client.ParseNotification(ctx, PurchaseNotificationRequest{Body: body(map[string]interface{}{
+		"notificationType": "DID_RENEW",
+		"data": map[string]string{
+			"bundleId":              "com.example.trivia",
+			"signedTransactionInfo": jws(map[string]string{"originalTransactionId": "2000000123", "productId": "premium.monthly"}),
+		},
+	})})
+	require.NoError(t, err)
+	assert.Equal(t, PurchaseVerifyRequest{ProductID: "premium.monthly", TransactionID: "2000000123"}, n.Request)
+
+	n, err = client.ParseNotification(ctx, PurchaseNotificationRequest{Body: body(map[string]interface{}{"notificationType": "TEST"})})
+	require.NoError(t, err)
+	assert.Nil(t, n)
+
+	_, err = client.ParseNotification(ctx, PurchaseNotificationRequest{Body: body(map[string]interface{}{
+		"notificationType": "REFUND",
+		"data":             map[string]string{"bundleId": "com.other.app", "signedTransactionInfo": jws(map[string]string{})},
+	})})
+	assert.True(t, errors.Is(err, apperrors.ErrValidation))
+
+	_, err = client.ParseNotification(ctx, PurchaseNotificationRequest{Body: []byte(`{}`)})
+	assert.True(t, errors.Is(err, apperrors.ErrValidation))
+}
@@ -0,0 +1,336 @@
+package service
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+const (
+	appStoreProductionHost = "https://api.storekit.itunes.apple.com"
+	appStoreSandboxHost    = "https://api.storekit-sandbox.itunes.apple.com"
+)
+
+// Статусы подписки в ответе App Store Server API (/inApps/v1/subscriptions)
+const (
+	appStoreSubscriptionActive       = 1
+	appStoreSubscriptionExpired      = 2
+	appStoreSubscriptionBillingRetry = 3
+	appStoreSubscriptionGracePeriod  = 4
+	appStoreSubscriptionRevoked      = 5
+)
+
+// AppStoreConfig — ключ App Store Server API (App Store Connect → Users and Access → In-App Purchase)
+type AppStoreConfig struct {
+	IssuerID       string
+	KeyID          string
+	PrivateKeyFile string // .p8 ключ
+	BundleID       string
+	Sandbox        bool // проверять покупки только в песочнице (разработка)
+	AllowSandbox   bool // в production искать в песочнице покупки, которых нет в боевом окружении (App Review)
+}
+
+// AppStorePurchaseClient проверяет покупки через App Store Server API
+type AppStorePurchaseClient struct {
+	cfg        AppStoreConfig
+	hosts      []string
+	privateKey *ecdsa.PrivateKey
+	httpClient *http.Client
+
+	mu          sync.Mutex
+	bearer      string
+	bearerIssue time.Time
+}
+
+// NewAppStorePurchaseClient создает клиента App Store Server API
+func NewAppStorePurchaseClient(cfg AppStoreConfig) (*AppStorePurchaseClient, error) {
+	if cfg.IssuerID == "" || cfg.KeyID == "" || cfg.PrivateKeyFile == "" || cfg.BundleID == "" {
+		return nil, fmt.Errorf("app store issuer id, key id, private key file and bundle id are required")
+	}
+	raw, err := os.ReadFile(cfg.PrivateKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read app store key: %w", err)
+	}
+	key, err := jwt.ParseECPrivateKeyFromPEM(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse app store key: %w", err)
+	}
+	hosts := []string{appStoreProductionHost}
+	switch {
+	case cfg.Sandbox:
+		hosts = []string{appStoreSandboxHost}
+	case cfg.AllowSandbox:
+		hosts = append(hosts, appStoreSandboxHost)
+	}
+	return &AppStorePurchaseClient{
+		cfg:        cfg,
+		hosts:      hosts,
+		privateKey: key,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func (c *AppStorePurchaseClient) Store() string { return entity.PurchaseStoreApple }
+
+// apiToken возвращает JWT для App Store Server API. Apple отклоняет токены со сроком больше часа.
+func (c *AppStorePurchaseClient) apiToken() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.bearer != "" && time.Since(c.bearerIssue) < 50*time.Minute {
+		return c.bearer, nil
+	}
+
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": c.cfg.IssuerID,
+		"iat": now.Unix(),
+		"exp": now.Add(55 * time.Minute).Unix(),
+		"aud": "appstoreconnect-v1",
+		"bid": c.cfg.BundleID,
+	})
+	token.Header["kid"] = c.cfg.KeyID
+	signed, err := token.SignedString(c.privateKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign app store token: %w", err)
+	}
+	c.bearer = signed
+	c.bearerIssue = now
+	return signed, nil
+}
+
+// appStoreTransaction — поля JWSTransactionDecodedPayload
+type appStoreTransaction struct {
+	TransactionID         string `json:"transactionId"`
+	OriginalTransactionID string `json:"originalTransactionId"`
+	BundleID              string `json:"bundleId"`
+	ProductID             string `json:"productId"`
+	PurchaseDate          int64  `json:"purchaseDate"`
+	OriginalPurchaseDate  int64  `json:"originalPurchaseDate"`
+	ExpiresDate           int64  `json:"expiresDate"`
+	RevocationDate        int64  `json:"revocationDate"`
+	Environment           string `json:"environment"`
+}
+
+// appStoreRenewalInfo — поля JWSRenewalInfoDecodedPayload
+type appStoreRenewalInfo struct {
+	AutoRenewStatus        int   `json:"autoRenewStatus"`
+	GracePeriodExpiresDate int64 `json:"gracePeriodExpiresDate"`
+}
+
+// Verify находит транзакцию и для подписки — её текущий статус
+func (c *AppStorePurchaseClient) Verify(ctx context.Context, req PurchaseVerifyRequest) (*StorePurchase, error) {
+	var txResp struct {
+		SignedTransactionInfo string `json:"signedTransactionInfo"`
+	}
+	host, err := c.get(ctx, "", "/inApps/v1/transactions/"+url.PathEscape(req.TransactionID), &txResp)
+	if err != nil {
+		return nil, err
+	}
+	var tx appStoreTransaction
+	if err := decodeJWSPayload(txResp.SignedTransactionInfo, &tx); err != nil {
+		return nil, fmt.Errorf("app store transaction: %w", err)
+	}
+	if tx.BundleID != c.cfg.BundleID {
+		return nil, fmt.Errorf("%w: purchase is for another app", apperrors.ErrValidation)
+	}
+
+	purchase := &StorePurchase{
+		TransactionID: tx.OriginalTransactionID,
+		ProductID:     tx.ProductID,
+		Environment:   appStoreEnvironment(tx.Environment),
+		Status:        entity.PurchaseStatusActive,
+		PurchasedAt:   appStoreTime(tx.OriginalPurchaseDate),
+	}
+	if tx.RevocationDate > 0 {
+		revokedAt := appStoreTime(tx.RevocationDate)
+		purchase.Status = entity.PurchaseStatusRevoked
+		purchase.RevokedAt = &revokedAt
+	}
+	if !req.Subscription {
+		return purchase, nil
+	}
+	return purchase, c.applySubscriptionStatus(ctx, host, purchase)
+}
+
+// applySubscriptionStatus дополняет покупку статусом подписки: последняя транзакция,
+// срок действия с учётом льготного периода и автопродление
+func (c *AppStorePurchaseClient) applySubscriptionStatus(ctx context.Context, host string, purchase *StorePurchase) error {
+	var statusResp struct {
+		Data []struct {
+			LastTransactions []struct {
+				OriginalTransactionID string `json:"originalTransactionId"`
+				Status                int    `json:"status"`
+				SignedTransactionInfo string `json:"signedTransactionInfo"`
+				SignedRenewalInfo     string `json:"signedRenewalInfo"`
+			} `json:"lastTransactions"`
+		} `json:"data"`
+	}
+	if _, err := c.get(ctx, host, "/inApps/v1/subscriptions/"+url.PathEscape(purchase.TransactionID), &statusResp); err != nil {
+		return err
+	}
+
+	for _, group := range statusResp.Data {
+		for _, last := range group.LastTransactions {
+			if last.OriginalTransactionID != purchase.TransactionID {
+				continue
+			}
+			var tx appStoreTransaction
+			if err := decodeJWSPayload(last.SignedTransactionInfo, &tx); err != nil {
+				return fmt.Errorf("app store subscription transaction: %w", err)
+			}
+			var renewal appStoreRenewalInfo
+			if last.SignedRenewalInfo != "" {
+				if err := decodeJWSPayload(last.SignedRenewalInfo, &renewal); err != nil {
+					return fmt.Errorf("app store renewal info: %w", err)
+				}
+			}
+
+			// Подписку могли перевести на другой продукт той же группы
+			purchase.ProductID = tx.ProductID
+			purchase.AutoRenew = renewal.AutoRenewStatus == 1
+			expiresAt := appStoreTime(tx.ExpiresDate)
+			purchase.ExpiresAt = &expiresAt
+			switch last.Status {
+			case appStoreSubscriptionActive:
+				purchase.Status = entity.PurchaseStatusActive
+			case appStoreSubscriptionGracePeriod:
+				purchase.Status = entity.PurchaseStatusActive
+				if renewal.GracePeriodExpiresDate > 0 {
+					graceEnds := appStoreTime(renewal.GracePeriodExpiresDate)
+					purchase.ExpiresAt = &graceEnds
+				}
+			case appStoreSubscriptionRevoked:
+				revokedAt := appStoreTime(tx.RevocationDate)
+				purchase.Status = entity.PurchaseStatusRevoked
+				purchase.RevokedAt = &revokedAt
+			case appStoreSubscriptionExpired, appStoreSubscriptionBillingRetry:
+				purchase.Status = entity.PurchaseStatusExpired
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: subscription status not found", apperrors.ErrValidation)
+}
+
+// get выполняет запрос к App Store Server API. Если host пуст, покупка ищется во всех
+// разрешённых окружениях по очереди; возвращается окружение, в котором она нашлась.
+func (c *AppStorePurchaseClient) get(ctx context.Context, host, path string, out interface{}) (string, error) {
+	hosts := c.hosts
+	if host != "" {
+		hosts = []string{host}
+	}
+	bearer, err := c.apiToken()
+	if err != nil {
+		return "", err
+	}
+
+	for _, h := range hosts {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, h+path, nil)
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Authorization", "Bearer "+bearer)
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return "", fmt.Errorf("app store request failed: %w", err)
+		}
+		body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		resp.Body.Close()
+		if err != nil {
+			return "", fmt.Errorf("app store request failed: %w", err)
+		}
+		switch {
+		case resp.StatusCode == http.StatusOK:
+			if err := json.Unmarshal(body, out); err != nil {
+				return "", fmt.Errorf("failed to decode app store response: %w", err)
+			}
+			return h, nil
+		case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusBadRequest:
+			continue
+		default:
+			return "", fmt.Errorf("app store request failed: status=%d body=%s", resp.StatusCode, truncateRunes(string(body), 512))
+		}
+	}
+	return "", fmt.Errorf("%w: purchase not found in app store", apperrors.ErrValidation)
+}
+
+// appStoreNotification — поля ResponseBodyV2DecodedPayload
+type appStoreNotification struct {
+	NotificationType string `json:"notificationType"`
+	Data             struct {
+		BundleID              string `json:"bundleId"`
+		SignedTransactionInfo string `json:"signedTransactionInfo"`
+	} `json:"data"`
+}
+
+// ParseNotification разбирает App Store Server Notification V2. Подпись уведомления не
+// проверяется: из него берётся только идентификатор покупки, а её состояние перечитывается
+// через API, поэтому поддельное уведомление ничего не меняет.
+func (c *AppStorePurchaseClient) ParseNotification(ctx context.Context, req PurchaseNotificationRequest) (*PurchaseNotification, error) {
+	var body struct {
+		SignedPayload string `json:"signedPayload"`
+	}
+	if err := json.Unmarshal(req.Body, &body); err != nil || body.SignedPayload == "" {
+		return nil, fmt.Errorf("%w: invalid app store notification", apperrors.ErrValidation)
+	}
+	var notification appStoreNotification
+	if err := decodeJWSPayload(body.SignedPayload, &notification); err != nil {
+		return nil, fmt.Errorf("%w: invalid app store notification: %v", apperrors.ErrValidation, err)
+	}
+	if notification.NotificationType == "TEST" || notification.Data.SignedTransactionInfo == "" {
+		return nil, nil
+	}
+	if notification.Data.BundleID != c.cfg.BundleID {
+		return nil, fmt.Errorf("%w: notification is for another app", apperrors.ErrValidation)
+	}
+
+	var tx appStoreTransaction
+	if err := decodeJWSPayload(notification.Data.SignedTransactionInfo, &tx); err != nil {
+		return nil, fmt.Errorf("%w: invalid app store notification: %v", apperrors.ErrValidation, err)
+	}
+	return &PurchaseNotification{Request: PurchaseVerifyRequest{
+		ProductID:     tx.ProductID,
+		TransactionID: tx.OriginalTransactionID,
+	}}, nil
+}
+
+// decodeJWSPayload декодирует полезную нагрузку JWS без проверки подписи
+func decodeJWSPayload(jws string, out interface{}) error {
+	parts := strings.Split(jws, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("malformed jws")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return fmt.Errorf("malformed jws payload: %w", err)
+	}
+	return json.Unmarshal(payload, out)
+}
+
+// appStoreTime переводит миллисекунды Unix из ответа App Store во время
+func appStoreTime(ms int64) time.Time {
+	return time.UnixMilli(ms).UTC()
+}
+
+func appStoreEnvironment(environment string) string {
+	if environment == "Sandbox" || environment == "Xcode" {
+		return "sandbox"
+	}
+	return "production"
+}
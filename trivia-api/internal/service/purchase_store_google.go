@@ -0,0 +1,266 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+const (
+	googlePlayScope   = "https://www.googleapis.com/auth/androidpublisher"
+	googlePlayAPIBase = "https://androidpublisher.googleapis.com/androidpublisher/v3/applications/"
+)
+
+// GooglePlayConfig — доступ к Google Play Developer API и проверка уведомлений RTDN
+type GooglePlayConfig struct {
+	PackageName        string
+	ServiceAccountFile string // JSON-ключ сервисного аккаунта с доступом к Play Console
+	NotificationToken  string // секрет в адресе push-подписки Pub/Sub (?token=)
+}
+
+// GooglePlayPurchaseClient проверяет покупки через Google Play Developer API
+type GooglePlayPurchaseClient struct {
+	cfg        GooglePlayConfig
+	tokens     *googleTokenSource
+	httpClient *http.Client
+	apiBase    string
+}
+
+// NewGooglePlayPurchaseClient создает клиента Google Play Developer API
+func NewGooglePlayPurchaseClient(cfg GooglePlayConfig) (*GooglePlayPurchaseClient, error) {
+	if cfg.PackageName == "" || cfg.NotificationToken == "" {
+		return nil, fmt.Errorf("google play package name and notification token are required")
+	}
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	tokens, err := newGoogleTokenSource(cfg.ServiceAccountFile, googlePlayScope, httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("google play: %w", err)
+	}
+	return &GooglePlayPurchaseClient{
+		cfg:        cfg,
+		tokens:     tokens,
+		httpClient: httpClient,
+		apiBase:    googlePlayAPIBase + url.PathEscape(cfg.PackageName),
+	}, nil
+}
+
+func (c *GooglePlayPurchaseClient) Store() string { return entity.PurchaseStoreGoogle }
+
+// Verify возвращает состояние подписки (subscriptionsv2) или разовой покупки (products)
+func (c *GooglePlayPurchaseClient) Verify(ctx context.Context, req PurchaseVerifyRequest) (*StorePurchase, error) {
+	if req.Subscription {
+		return c.verifySubscription(ctx, req)
+	}
+	return c.verifyProduct(ctx, req)
+}
+
+func (c *GooglePlayPurchaseClient) verifySubscription(ctx context.Context, req PurchaseVerifyRequest) (*StorePurchase, error) {
+	var sub struct {
+		SubscriptionState    string          `json:"subscriptionState"`
+		StartTime            time.Time       `json:"startTime"`
+		AcknowledgementState string          `json:"acknowledgementState"`
+		TestPurchase         json.RawMessage `json:"testPurchase"`
+		LineItems            []struct {
+			ProductID        string    `json:"productId"`
+			ExpiryTime       time.Time `json:"expiryTime"`
+			AutoRenewingPlan *struct {
+				AutoRenewEnabled bool `json:"autoRenewEnabled"`
+			} `json:"autoRenewingPlan"`
+		} `json:"lineItems"`
+	}
+	if err := c.call(ctx, http.MethodGet, "/purchases/subscriptionsv2/tokens/"+url.PathEscape(req.TransactionID), &sub); err != nil {
+		return nil, err
+	}
+	if len(sub.LineItems) == 0 {
+		return nil, fmt.Errorf("%w: subscription has no line items", apperrors.ErrValidation)
+	}
+
+	item := sub.LineItems[0]
+	expiresAt := item.ExpiryTime.UTC()
+	purchase := &StorePurchase{
+		TransactionID:    req.TransactionID,
+		ProductID:        item.ProductID,
+		Environment:      "production",
+		AutoRenew:        item.AutoRenewingPlan != nil && item.AutoRenewingPlan.AutoRenewEnabled,
+		PurchasedAt:      sub.StartTime.UTC(),
+		ExpiresAt:        &expiresAt,
+		NeedsAcknowledge: sub.AcknowledgementState == "ACKNOWLEDGEMENT_STATE_PENDING",
+	}
+	if len(sub.TestPurchase) > 0 {
+		purchase.Environment = "sandbox"
+	}
+	switch sub.SubscriptionState {
+	// Отменённая подписка действует до конца оплаченного периода
+	case "SUBSCRIPTION_STATE_ACTIVE", "SUBSCRIPTION_STATE_IN_GRACE_PERIOD", "SUBSCRIPTION_STATE_CANCELED":
+		purchase.Status = entity.PurchaseStatusActive
+	case "SUBSCRIPTION_STATE_PENDING":
+		purchase.Status = entity.PurchaseStatusPending
+	default: // ON_HOLD, PAUSED, EXPIRED, PENDING_PURCHASE_CANCELED
+		purchase.Status = entity.PurchaseStatusExpired
+	}
+	return purchase, nil
+}
+
+func (c *GooglePlayPurchaseClient) verifyProduct(ctx context.Context, req PurchaseVerifyRequest) (*StorePurchase, error) {
+	var product struct {
+		PurchaseState        int    `json:"purchaseState"`
+		PurchaseTimeMillis   string `json:"purchaseTimeMillis"`
+		AcknowledgementState int    `json:"acknowledgementState"`
+		PurchaseType         *int   `json:"purchaseType"`
+	}
+	path := "/purchases/products/" + url.PathEscape(req.ProductID) + "/tokens/" + url.PathEscape(req.TransactionID)
+	if err := c.call(ctx, http.MethodGet, path, &product); err != nil {
+		return nil, err
+	}
+
+	purchasedMs, _ := strconv.ParseInt(product.PurchaseTimeMillis, 10, 64)
+	purchase := &StorePurchase{
+		TransactionID:    req.TransactionID,
+		ProductID:        req.ProductID,
+		Environment:      "production",
+		PurchasedAt:      time.UnixMilli(purchasedMs).UTC(),
+		NeedsAcknowledge: product.AcknowledgementState == 0,
+	}
+	// purchaseType есть только у тестовых и промо-покупок; 0 — тестовая
+	if product.PurchaseType != nil && *product.PurchaseType == 0 {
+		purchase.Environment = "sandbox"
+	}
+	switch product.PurchaseState {
+	case 0:
+		purchase.Status = entity.PurchaseStatusActive
+	case 2:
+		purchase.Status = entity.PurchaseStatusPending
+	default:
+		purchase.Status = entity.PurchaseStatusRevoked
+		now := time.Now().UTC()
+		purchase.RevokedAt = &now
+	}
+	return purchase, nil
+}
+
+// Acknowledge подтверждает покупку. Без подтверждения в течение трёх дней Google Play
+// возвращает деньги и отзывает покупку.
+func (c *GooglePlayPurchaseClient) Acknowledge(ctx context.Context, req PurchaseVerifyRequest) error {
+	kind := "/purchases/products/"
+	if req.Subscription {
+		kind = "/purchases/subscriptions/"
+	}
+	return c.call(ctx, http.MethodPost, kind+url.PathEscape(req.ProductID)+"/tokens/"+url.PathEscape(req.TransactionID)+":acknowledge", nil)
+}
+
+// call выполняет запрос к Google Play Developer API; out == nil — тело ответа не нужно
+func (c *GooglePlayPurchaseClient) call(ctx context.Context, method, path string, out interface{}) error {
+	accessToken, err := c.tokens.Token(ctx)
+	if err != nil {
+		return err
+	}
+	var body io.Reader
+	if method == http.MethodPost {
+		body = bytes.NewReader([]byte("{}"))
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.apiBase+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("google play request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusNoContent:
+		if out == nil {
+			return nil
+		}
+		if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(out); err != nil {
+			return fmt.Errorf("failed to decode google play response: %w", err)
+		}
+		return nil
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusGone:
+		return fmt.Errorf("%w: purchase not found in google play", apperrors.ErrValidation)
+	default:
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("google play request failed: status=%d body=%s", resp.StatusCode, string(respBody))
+	}
+}
+
+// googlePlayNotification — DeveloperNotification из сообщения Pub/Sub
+type googlePlayNotification struct {
+	PackageName              string `json:"packageName"`
+	SubscriptionNotification *struct {
+		PurchaseToken  string `json:"purchaseToken"`
+		SubscriptionID string `json:"subscriptionId"`
+	} `json:"subscriptionNotification"`
+	OneTimeProductNotification *struct {
+		PurchaseToken string `json:"purchaseToken"`
+		SKU           string `json:"sku"`
+	} `json:"oneTimeProductNotification"`
+	VoidedPurchaseNotification *struct {
+		PurchaseToken string `json:"purchaseToken"`
+	} `json:"voidedPurchaseNotification"`
+}
+
+// ParseNotification разбирает Real-time developer notification, доставленное push-подпиской
+// Pub/Sub. Подлинность проверяется по секрету в адресе подписки.
+func (c *GooglePlayPurchaseClient) ParseNotification(ctx context.Context, req PurchaseNotificationRequest) (*PurchaseNotification, error) {
+	token := req.Query.Get("token")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(c.cfg.NotificationToken)) != 1 {
+		return nil, fmt.Errorf("%w: invalid google play notification token", apperrors.ErrUnauthorized)
+	}
+
+	var envelope struct {
+		Message struct {
+			Data string `json:"data"`
+		} `json:"message"`
+	}
+	if err := json.Unmarshal(req.Body, &envelope); err != nil {
+		return nil, fmt.Errorf("%w: invalid pub/sub payload: %v", apperrors.ErrValidation, err)
+	}
+	data, err := base64.StdEncoding.DecodeString(envelope.Message.Data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid pub/sub message data: %v", apperrors.ErrValidation, err)
+	}
+	var notification googlePlayNotification
+	if err := json.Unmarshal(data, &notification); err != nil {
+		return nil, fmt.Errorf("%w: invalid google play notification: %v", apperrors.ErrValidation, err)
+	}
+	if notification.PackageName != c.cfg.PackageName {
+		return nil, nil
+	}
+
+	switch {
+	case notification.VoidedPurchaseNotification != nil:
+		return &PurchaseNotification{
+			Request: PurchaseVerifyRequest{TransactionID: notification.VoidedPurchaseNotification.PurchaseToken},
+			Revoked: true,
+		}, nil
+	case notification.SubscriptionNotification != nil:
+		// subscriptionId в новых уведомлениях не заполняется — продукт берётся из покупки
+		return &PurchaseNotification{Request: PurchaseVerifyRequest{
+			ProductID:     notification.SubscriptionNotification.SubscriptionID,
+			TransactionID: notification.SubscriptionNotification.PurchaseToken,
+		}}, nil
+	case notification.OneTimeProductNotification != nil:
+		return &PurchaseNotification{Request: PurchaseVerifyRequest{
+			ProductID:     notification.OneTimeProductNotification.SKU,
+			TransactionID: notification.OneTimeProductNotification.PurchaseToken,
+		}}, nil
+	}
+	return nil, nil // testNotification
+}
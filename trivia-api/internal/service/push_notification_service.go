@@ -0,0 +1,383 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// Шаблоны push-уведомлений
+const (
+	PushTemplateQuizReminder   = "quiz_reminder"
+	PushTemplateQuizWinner     = "quiz_winner"
+	PushTemplateSessionRevoked = "session_revoked"
+)
+
+const pushBroadcastBatchSize = 500
+
+// messageTemplate — заголовок и текст уведомления с плейсхолдерами вида {name}
+type messageTemplate struct {
+	Title string
+	Body  string
+}
+
+// pushTemplates содержит тексты уведомлений по языкам; плейсхолдеры вида {name} подставляются из params
+var pushTemplates = map[string]map[string]messageTemplate{
+	PushTemplateQuizReminder: {
+		"ru": {Title: "Викторина скоро начнётся", Body: "«{quiz_title}» начнётся через {minutes} мин. Не пропустите!"},
+		"kk": {Title: "Викторина жақында басталады", Body: "«{quiz_title}» {minutes} минуттан кейін басталады. Өткізіп алмаңыз!"},
+	},
+	PushTemplateQuizWinner: {
+		"ru": {Title: "Поздравляем с победой!", Body: "Вы выиграли {prize} в викторине «{quiz_title}»"},
+		"kk": {Title: "Жеңісіңізбен құттықтаймыз!", Body: "Сіз «{quiz_title}» викторинасында {prize} ұттыңыз"},
+	},
+	PushTemplateSessionRevoked: {
+		"ru": {Title: "Сессия завершена", Body: "Одна из ваших сессий была завершена. Если это были не вы, смените пароль."},
+		"kk": {Title: "Сессия аяқталды", Body: "Сессияларыңыздың бірі аяқталды. Егер бұл сіз болмасаңыз, құпиясөзді өзгертіңіз."},
+	},
+}
+
+// renderPushTemplate подставляет параметры в шаблон на языке устройства (fallback — ru)
+func renderPushTemplate(name, language string, params map[string]string) (PushMessage, bool) {
+	byLang, ok := pushTemplates[name]
+	if !ok {
+		return PushMessage{}, false
+	}
+	tpl, ok := byLang[language]
+	if !ok {
+		tpl = byLang["ru"]
+	}
+	return renderTemplate(tpl, params), true
+}
+
+// renderTemplate подставляет параметры в заголовок и текст шаблона
+func renderTemplate(tpl messageTemplate, params map[string]string) PushMessage {
+	pairs := make([]string, 0, len(params)*2)
+	for k, v := range params {
+		pairs = append(pairs, "{"+k+"}", v)
+	}
+	r := strings.NewReplacer(pairs...)
+	return PushMessage{Title: r.Replace(tpl.Title), Body: r.Replace(tpl.Body)}
+}
+
+// pushJob — задача рассылки для воркера
+type pushJob struct {
+	userIDs   []uint // пусто при broadcast
+	broadcast bool
+	category  string
+	template  string
+	params    map[string]string
+	data      map[string]string
+}
+
+// RegisterPushDeviceInput содержит данные регистрации push-токена
+type RegisterPushDeviceInput struct {
+	Token    string
+	Platform string
+	Provider string
+	DeviceID string
+	Language string
+}
+
+// PushNotificationService регистрирует устройства и рассылает push-уведомления через FCM/APNs.
+// Рассылка выполняется пулом воркеров, вызывающий код только ставит задачу в очередь.
+type PushNotificationService struct {
+	deviceRepo repository.PushDeviceRepository
+	prefRepo   repository.NotificationPreferenceRepository
+	senders    map[string]PushSender
+	queue      chan pushJob
+	workers    int
+}
+
+// NewPushNotificationService создает сервис push-уведомлений
+func NewPushNotificationService(
+	deviceRepo repository.PushDeviceRepository,
+	prefRepo repository.NotificationPreferenceRepository,
+	senders []PushSender,
+	workers int,
+	queueSize int,
+) (*PushNotificationService, error) {
+	if deviceRepo == nil {
+		return nil, fmt.Errorf("push device repository is required")
+	}
+	if prefRepo == nil {
+		return nil, fmt.Errorf("notification preference repository is required")
+	}
+	if workers <= 0 {
+		workers = 4
+	}
+	if queueSize <= 0 {
+		queueSize = 1000
+	}
+
+	senderMap := make(map[string]PushSender, len(senders))
+	for _, sender := range senders {
+		if sender != nil {
+			senderMap[sender.Provider()] = sender
+		}
+	}
+
+	return &PushNotificationService{
+		deviceRepo: deviceRepo,
+		prefRepo:   prefRepo,
+		senders:    senderMap,
+		queue:      make(chan pushJob, queueSize),
+		workers:    workers,
+	}, nil
+}
+
+// Start запускает воркеры рассылки; они завершаются при отмене ctx
+func (s *PushNotificationService) Start(ctx context.Context) {
+	for i := 0; i < s.workers; i++ {
+		go s.worker(ctx)
+	}
+	log.Printf("[PushService] Запущено %d воркеров рассылки", s.workers)
+}
+
+func (s *PushNotificationService) worker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-s.queue:
+			s.processJob(ctx, job)
+		}
+	}
+}
+
+// enqueue ставит задачу в очередь без блокировки; при переполнении задача отбрасывается
+func (s *PushNotificationService) enqueue(job pushJob) {
+	select {
+	case s.queue <- job:
+	default:
+		log.Printf("[PushService] Очередь переполнена, уведомление %s отброшено", job.template)
+	}
+}
+
+// RegisterDevice сохраняет push-токен устройства пользователя
+func (s *PushNotificationService) RegisterDevice(userID uint, input RegisterPushDeviceInput) (*entity.PushDevice, error) {
+	token := strings.TrimSpace(input.Token)
+	if token == "" {
+		return nil, fmt.Errorf("%w: token is required", apperrors.ErrValidation)
+	}
+
+	platform := strings.ToLower(strings.TrimSpace(input.Platform))
+	provider := strings.ToLower(strings.TrimSpace(input.Provider))
+	switch platform {
+	case entity.PushPlatformIOS:
+		if provider == "" {
+			provider = entity.PushProviderAPNs
+		}
+	case entity.PushPlatformAndroid:
+		if provider == "" {
+			provider = entity.PushProviderFCM
+		}
+		if provider != entity.PushProviderFCM {
+			return nil, fmt.Errorf("%w: android devices support only fcm", apperrors.ErrValidation)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unsupported platform", apperrors.ErrValidation)
+	}
+	if provider != entity.PushProviderFCM && provider != entity.PushProviderAPNs {
+		return nil, fmt.Errorf("%w: unsupported provider", apperrors.ErrValidation)
+	}
+
+	language := strings.TrimSpace(input.Language)
+	if language != "kk" {
+		language = "ru"
+	}
+
+	device := &entity.PushDevice{
+		UserID:   userID,
+		Platform: platform,
+		Provider: provider,
+		Token:    token,
+		DeviceID: strings.TrimSpace(input.DeviceID),
+		Language: language,
+	}
+	if err := s.deviceRepo.Upsert(device); err != nil {
+		return nil, err
+	}
+	return device, nil
+}
+
+// UnregisterDevice удаляет push-токен пользователя
+func (s *PushNotificationService) UnregisterDevice(userID uint, token string) error {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return fmt.Errorf("%w: token is required", apperrors.ErrValidation)
+	}
+	return s.deviceRepo.DeleteByUserAndToken(userID, token)
+}
+
+// ListDevices возвращает зарегистрированные устройства пользователя
+func (s *PushNotificationService) ListDevices(userID uint) ([]entity.PushDevice, error) {
+	return s.deviceRepo.ListByUserID(userID)
+}
+
+// NotifyQuizStartingSoon рассылает напоминание о скором начале викторины всем пользователям с push-токенами
+func (s *PushNotificationService) NotifyQuizStartingSoon(quiz *entity.Quiz) {
+	minutes := int(time.Until(quiz.ScheduledTime).Round(time.Minute).Minutes())
+	if minutes < 1 {
+		minutes = 1
+	}
+	s.enqueue(pushJob{
+		broadcast: true,
+		category:  entity.NotificationCategoryQuizReminder,
+		template:  PushTemplateQuizReminder,
+		params: map[string]string{
+			"quiz_title": quiz.Title,
+			"minutes":    strconv.Itoa(minutes),
+		},
+		data: map[string]string{
+			"type":    "quiz_reminder",
+			"quiz_id": strconv.FormatUint(uint64(quiz.ID), 10),
+		},
+	})
+}
+
+// NotifyQuizWinners уведомляет победителей викторины
+func (s *PushNotificationService) NotifyQuizWinners(quizID uint, quizTitle string, winnerIDs []uint, prize int) {
+	if len(winnerIDs) == 0 {
+		return
+	}
+	s.enqueue(pushJob{
+		userIDs:  winnerIDs,
+		category: entity.NotificationCategoryWinnerAnnouncement,
+		template: PushTemplateQuizWinner,
+		params: map[string]string{
+			"quiz_title": quizTitle,
+			"prize":      strconv.Itoa(prize),
+		},
+		data: map[string]string{
+			"type":    "quiz_winner",
+			"quiz_id": strconv.FormatUint(uint64(quizID), 10),
+		},
+	})
+}
+
+// NotifySessionRevoked предупреждает пользователя об отзыве сессии
+func (s *PushNotificationService) NotifySessionRevoked(userID uint) {
+	s.enqueue(pushJob{
+		userIDs:  []uint{userID},
+		category: entity.NotificationCategorySecurityAlert,
+		template: PushTemplateSessionRevoked,
+		data:     map[string]string{"type": "session_revoked"},
+	})
+}
+
+// processJob выполняет fan-out задачи по устройствам с учётом настроек пользователей
+func (s *PushNotificationService) processJob(ctx context.Context, job pushJob) {
+	if !job.broadcast {
+		devices, err := s.deviceRepo.ListByUserIDs(job.userIDs)
+		if err != nil {
+			log.Printf("[PushService] Ошибка получения устройств для %s: %v", job.template, err)
+			return
+		}
+		s.deliver(ctx, job, devices)
+		return
+	}
+
+	var afterID uint
+	for {
+		devices, err := s.deviceRepo.ListAfterID(afterID, pushBroadcastBatchSize)
+		if err != nil {
+			log.Printf("[PushService] Ошибка получения устройств для рассылки %s: %v", job.template, err)
+			return
+		}
+		if len(devices) == 0 {
+			return
+		}
+		s.deliver(ctx, job, devices)
+		afterID = devices[len(devices)-1].ID
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// deliver фильтрует устройства по настройкам владельцев и отправляет уведомления
+func (s *PushNotificationService) deliver(ctx context.Context, job pushJob, devices []entity.PushDevice) {
+	if len(devices) == 0 {
+		return
+	}
+
+	allowed, err := s.allowedUsers(devices, job.category)
+	if err != nil {
+		log.Printf("[PushService] Ошибка получения настроек уведомлений: %v", err)
+		return
+	}
+
+	sent := 0
+	for _, device := range devices {
+		if !allowed[device.UserID] {
+			continue
+		}
+		sender, ok := s.senders[device.Provider]
+		if !ok {
+			continue
+		}
+		msg, ok := renderPushTemplate(job.template, device.Language, job.params)
+		if !ok {
+			log.Printf("[PushService] Неизвестный шаблон %s", job.template)
+			return
+		}
+		msg.Data = job.data
+
+		sendCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		err := sender.Send(sendCtx, device.Token, msg)
+		cancel()
+		if err != nil {
+			if errors.Is(err, ErrPushTokenInvalid) {
+				if delErr := s.deviceRepo.DeleteByToken(device.Token); delErr != nil {
+					log.Printf("[PushService] Ошибка удаления недействительного токена устройства ID=%d: %v", device.ID, delErr)
+				}
+				continue
+			}
+			log.Printf("[PushService] Ошибка отправки %s на устройство ID=%d (%s): %v", job.template, device.ID, device.Provider, err)
+			continue
+		}
+		sent++
+	}
+	log.Printf("[PushService] Уведомление %s: отправлено %d из %d устройств", job.template, sent, len(devices))
+}
+
+// allowedUsers возвращает набор пользователей, разрешивших категорию уведомлений и push-канал
+func (s *PushNotificationService) allowedUsers(devices []entity.PushDevice, category string) (map[uint]bool, error) {
+	userIDs := make([]uint, 0, len(devices))
+	seen := make(map[uint]struct{}, len(devices))
+	for _, d := range devices {
+		if _, ok := seen[d.UserID]; !ok {
+			seen[d.UserID] = struct{}{}
+			userIDs = append(userIDs, d.UserID)
+		}
+	}
+
+	prefs, err := s.prefRepo.GetByUserIDs(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	prefByUser := make(map[uint]entity.NotificationPreference, len(prefs))
+	for _, p := range prefs {
+		prefByUser[p.UserID] = p
+	}
+
+	allowed := make(map[uint]bool, len(userIDs))
+	for _, id := range userIDs {
+		pref, ok := prefByUser[id]
+		if !ok {
+			allowed[id] = entity.DefaultNotificationPreference(id).AllowsDelivery(category, entity.NotificationChannelPush)
+			continue
+		}
+		allowed[id] = pref.AllowsDelivery(category, entity.NotificationChannelPush)
+	}
+	return allowed, nil
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// ============================================================================
+// Моки для PushNotificationService
+// ============================================================================
+
+// MockPushDeviceRepository реализует repository.PushDeviceRepository
+type MockPushDeviceRepository struct {
+	mock.Mock
+}
+
+func (m *MockPushDeviceRepository) Upsert(device *entity.PushDevice) error {
+	args := m.Called(device)
+	return args.Error(0)
+}
+
+func (m *MockPushDeviceRepository) ListByUserID(userID uint) ([]entity.PushDevice, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.PushDevice), args.Error(1)
+}
+
+func (m *MockPushDeviceRepository) ListByUserIDs(userIDs []uint) ([]entity.PushDevice, error) {
+	args := m.Called(userIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.PushDevice), args.Error(1)
+}
+
+func (m *MockPushDeviceRepository) ListAfterID(afterID uint, limit int) ([]entity.PushDevice, error) {
+	args := m.Called(afterID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.PushDevice), args.Error(1)
+}
+
+func (m *MockPushDeviceRepository) DeleteByUserAndToken(userID uint, token string) error {
+	args := m.Called(userID, token)
+	return args.Error(0)
+}
+
+func (m *MockPushDeviceRepository) DeleteByToken(token string) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+// MockNotificationPreferenceRepository реализует repository.NotificationPreferenceRepository
+type MockNotificationPreferenceRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationPreferenceRepository) GetByUserID(userID uint) (*entity.NotificationPreference, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.NotificationPreference), args.Error(1)
+}
+
+func (m *MockNotificationPreferenceRepository) GetByUserIDs(userIDs []uint) ([]entity.NotificationPreference, error) {
+	args := m.Called(userIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.NotificationPreference), args.Error(1)
+}
+
+func (m *MockNotificationPreferenceRepository) Upsert(pref *entity.NotificationPreference) error {
+	args := m.Called(pref)
+	return args.Error(0)
+}
+
+// recordingPushSender запоминает отправленные сообщения и возвращает заданные ошибки по токену
+type recordingPushSender struct {
+	provider string
+	errors   map[string]error
+	sent     map[string]PushMessage
+}
+
+func newRecordingPushSender(provider string) *recordingPushSender {
+	return &recordingPushSender{provider: provider, errors: map[string]error{}, sent: map[string]PushMessage{}}
+}
+
+func (s *recordingPushSender) Provider() string { return s.provider }
+
+func (s *recordingPushSender) Send(_ context.Context, token string, msg PushMessage) error {
+	if err := s.errors[token]; err != nil {
+		return err
+	}
+	s.sent[token] = msg
+	return nil
+}
+
+func createTestPushService(t *testing.T, senders ...PushSender) (*PushNotificationService, *MockPushDeviceRepository, *MockNotificationPreferenceRepository) {
+	deviceRepo := new(MockPushDeviceRepository)
+	prefRepo := new(MockNotificationPreferenceRepository)
+	svc, err := NewPushNotificationService(deviceRepo, prefRepo, senders, 1, 10)
+	require.NoError(t, err)
+	return svc, deviceRepo, prefRepo
+}
+
+// ============================================================================
+// Тесты
+// ============================================================================
+
+func TestPushRegisterDevice_DerivesProviderFromPlatform(t *testing.T) {
+	svc, deviceRepo, _ := createTestPushService(t)
+	deviceRepo.On("Upsert", mock.AnythingOfType("*entity.PushDevice")).Return(nil)
+
+	device, err := svc.RegisterDevice(1, RegisterPushDeviceInput{Token: "tok", Platform: "ios", Language: "en"})
+	require.NoError(t, err)
+	assert.Equal(t, entity.PushProviderAPNs, device.Provider)
+	assert.Equal(t, "ru", device.Language)
+
+	_, err = svc.RegisterDevice(1, RegisterPushDeviceInput{Token: "tok", Platform: "android", Provider: "apns"})
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+}
+
+func TestPushDeliver_RespectsPreferencesAndLanguage(t *testing.T) {
+	fcm := newRecordingPushSender(entity.PushProviderFCM)
+	svc, deviceRepo, prefRepo := createTestPushService(t, fcm)
+
+	devices := []entity.PushDevice{
+		{ID: 1, UserID: 1, Provider: entity.PushProviderFCM, Token: "opted-out", Language: "ru"},
+		{ID: 2, UserID: 2, Provider: entity.PushProviderFCM, Token: "kk-user", Language: "kk"},
+	}
+	deviceRepo.On("ListByUserIDs", []uint{1, 2}).Return(devices, nil)
+	prefRepo.On("GetByUserIDs", []uint{1, 2}).Return([]entity.NotificationPreference{
+		{UserID: 1, WinnerAnnouncements: false},
+	}, nil)
+
+	svc.processJob(context.Background(), pushJob{
+		userIDs:  []uint{1, 2},
+		category: entity.NotificationCategoryWinnerAnnouncement,
+		template: PushTemplateQuizWinner,
+		params:   map[string]string{"quiz_title": "Quiz", "prize": "500"},
+	})
+
+	assert.NotContains(t, fcm.sent, "opted-out")
+	require.Contains(t, fcm.sent, "kk-user")
+	assert.Equal(t, "Сіз «Quiz» викторинасында 500 ұттыңыз", fcm.sent["kk-user"].Body)
+}
+
+func TestPushDeliver_DeletesInvalidTokens(t *testing.T) {
+	apns := newRecordingPushSender(entity.PushProviderAPNs)
+	apns.errors["stale"] = ErrPushTokenInvalid
+	svc, deviceRepo, prefRepo := createTestPushService(t, apns)
+
+	deviceRepo.On("ListAfterID", uint(0), pushBroadcastBatchSize).Return([]entity.PushDevice{
+		{ID: 7, UserID: 3, Provider: entity.PushProviderAPNs, Token: "stale"},
+	}, nil)
+	deviceRepo.On("ListAfterID", uint(7), pushBroadcastBatchSize).Return([]entity.PushDevice{}, nil)
+	deviceRepo.On("DeleteByToken", "stale").Return(nil)
+	prefRepo.On("GetByUserIDs", []uint{3}).Return([]entity.NotificationPreference{}, nil)
+
+	svc.processJob(context.Background(), pushJob{
+		broadcast: true,
+		category:  entity.NotificationCategoryQuizReminder,
+		template:  PushTemplateQuizReminder,
+		params:    map[string]string{"quiz_title": "Quiz", "minutes": "10"},
+	})
+
+	deviceRepo.AssertCalled(t, "DeleteByToken", "stale")
+	assert.Empty(t, apns.sent)
+}
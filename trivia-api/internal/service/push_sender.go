@@ -0,0 +1,249 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+// ErrPushTokenInvalid возвращается провайдером, если токен устройства больше не действителен.
+// Такие токены удаляются из БД.
+var ErrPushTokenInvalid = errors.New("push_token_invalid")
+
+// PushMessage — содержимое push-уведомления
+type PushMessage struct {
+	Title string
+	Body  string
+	Data  map[string]string
+}
+
+// PushSender отправляет уведомление на одно устройство через конкретного провайдера
+type PushSender interface {
+	Provider() string
+	Send(ctx context.Context, token string, msg PushMessage) error
+}
+
+// NoopPushSender используется, когда провайдер не настроен (локальная разработка)
+type NoopPushSender struct {
+	provider string
+}
+
+// NewNoopPushSender создает заглушку для указанного провайдера
+func NewNoopPushSender(provider string) *NoopPushSender {
+	return &NoopPushSender{provider: provider}
+}
+
+func (s *NoopPushSender) Provider() string { return s.provider }
+
+func (s *NoopPushSender) Send(ctx context.Context, token string, msg PushMessage) error {
+	log.Printf("[PushSender] noop %s send title=%q", s.provider, msg.Title)
+	return nil
+}
+
+// ============================================================================
+// FCM (HTTP v1 API)
+// ============================================================================
+
+const fcmScope = "https://www.googleapis.com/auth/firebase.messaging"
+
+// FCMPushSender отправляет уведомления через Firebase Cloud Messaging
+type FCMPushSender struct {
+	tokens     *googleTokenSource
+	httpClient *http.Client
+}
+
+// NewFCMPushSender создает отправителя FCM из JSON-файла сервисного аккаунта
+func NewFCMPushSender(serviceAccountFile string) (*FCMPushSender, error) {
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	tokens, err := newGoogleTokenSource(serviceAccountFile, fcmScope, httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("fcm: %w", err)
+	}
+	if tokens.account.ProjectID == "" {
+		return nil, fmt.Errorf("fcm service account has no project_id")
+	}
+	return &FCMPushSender{tokens: tokens, httpClient: httpClient}, nil
+}
+
+func (s *FCMPushSender) Provider() string { return entity.PushProviderFCM }
+
+func (s *FCMPushSender) Send(ctx context.Context, token string, msg PushMessage) error {
+	accessToken, err := s.tokens.Token(ctx)
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]interface{}{
+		"message": map[string]interface{}{
+			"token": token,
+			"notification": map[string]string{
+				"title": msg.Title,
+				"body":  msg.Body,
+			},
+			"data":    msg.Data,
+			"android": map[string]string{"priority": "high"},
+		},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("https://fcm.googleapis.com/v1/projects/%s/messages:send", s.tokens.account.ProjectID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("fcm send failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode == http.StatusNotFound || bytes.Contains(respBody, []byte("UNREGISTERED")) {
+		return ErrPushTokenInvalid
+	}
+	return fmt.Errorf("fcm send failed: status=%d body=%s", resp.StatusCode, string(respBody))
+}
+
+// ============================================================================
+// APNs (HTTP/2 provider API, token-based auth)
+// ============================================================================
+
+// APNsPushSender отправляет уведомления через Apple Push Notification service
+type APNsPushSender struct {
+	keyID      string
+	teamID     string
+	bundleID   string
+	host       string
+	privateKey *ecdsa.PrivateKey
+	httpClient *http.Client
+
+	mu          sync.Mutex
+	bearer      string
+	bearerIssue time.Time
+}
+
+// NewAPNsPushSender создает отправителя APNs из .p8 ключа
+func NewAPNsPushSender(keyFile, keyID, teamID, bundleID string, production bool) (*APNsPushSender, error) {
+	if keyFile == "" || keyID == "" || teamID == "" || bundleID == "" {
+		return nil, fmt.Errorf("apns key file, key id, team id and bundle id are required")
+	}
+	raw, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read apns key: %w", err)
+	}
+	key, err := jwt.ParseECPrivateKeyFromPEM(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse apns key: %w", err)
+	}
+	host := "https://api.sandbox.push.apple.com"
+	if production {
+		host = "https://api.push.apple.com"
+	}
+	return &APNsPushSender{
+		keyID:      keyID,
+		teamID:     teamID,
+		bundleID:   bundleID,
+		host:       host,
+		privateKey: key,
+		httpClient: &http.Client{Timeout: 10 * time.Second}, // net/http согласует HTTP/2 через ALPN
+	}, nil
+}
+
+func (s *APNsPushSender) Provider() string { return entity.PushProviderAPNs }
+
+// providerToken возвращает JWT для APNs. Apple требует обновлять его не чаще раза в 20 минут
+// и не реже раза в час.
+func (s *APNsPushSender) providerToken() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.bearer != "" && time.Since(s.bearerIssue) < 50*time.Minute {
+		return s.bearer, nil
+	}
+
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": s.teamID,
+		"iat": now.Unix(),
+	})
+	token.Header["kid"] = s.keyID
+	signed, err := token.SignedString(s.privateKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign apns token: %w", err)
+	}
+	s.bearer = signed
+	s.bearerIssue = now
+	return signed, nil
+}
+
+func (s *APNsPushSender) Send(ctx context.Context, token string, msg PushMessage) error {
+	bearer, err := s.providerToken()
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]interface{}{
+		"aps": map[string]interface{}{
+			"alert": map[string]string{
+				"title": msg.Title,
+				"body":  msg.Body,
+			},
+			"sound": "default",
+		},
+	}
+	for k, v := range msg.Data {
+		payload[k] = v
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.host+"/3/device/"+token, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("authorization", "bearer "+bearer)
+	req.Header.Set("apns-topic", s.bundleID)
+	req.Header.Set("apns-push-type", "alert")
+	req.Header.Set("apns-priority", "10")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("apns send failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var apnsErr struct {
+		Reason string `json:"reason"`
+	}
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&apnsErr)
+	if resp.StatusCode == http.StatusGone || apnsErr.Reason == "BadDeviceToken" || apnsErr.Reason == "Unregistered" {
+		return ErrPushTokenInvalid
+	}
+	return fmt.Errorf("apns send failed: status=%d reason=%s", resp.StatusCode, apnsErr.Reason)
+}
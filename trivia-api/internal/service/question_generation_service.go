@@ -0,0 +1,384 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"math"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"github.com/yourusername/trivia-api/internal/config"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// ErrGenerationQuotaExceeded — дневная квота администратора на генерацию вопросов исчерпана
+var ErrGenerationQuotaExceeded = errors.New("generation_quota_exceeded")
+
+// builtinQuestionPrompts — шаблоны запросов к модели. Файл <name>.tmpl содержит блоки
+// {{define "system"}} и {{define "prompt"}}; имя файла выбирается в запросе генерации.
+//
+//go:embed question_prompts/*.tmpl
+var builtinQuestionPrompts embed.FS
+
+// defaultQuestionPrompt — шаблон, если в запросе он не указан
+const defaultQuestionPrompt = "standard"
+
+// questionLanguages — языки генерации и их названия для модели
+var questionLanguages = map[string]string{"ru": "Russian", "kk": "Kazakh", "en": "English"}
+
+// QuestionGenerationRequest — запрос администратора на генерацию вопросов
+type QuestionGenerationRequest struct {
+	Topic    string                  `json:"topic" binding:"required"`
+	Spread   entity.DifficultySpread `json:"difficulty_spread" binding:"required"` // сложность 1–5 → число вопросов
+	Template string                  `json:"template,omitempty"`                   // пусто — standard
+	Language string                  `json:"language,omitempty"`                   // ru, kk, en; пусто — из настроек
+	Category string                  `json:"category,omitempty"`                   // slug категории для всех вопросов
+}
+
+// QuestionGenerationUsage — расход администратора за текущие сутки UTC
+type QuestionGenerationUsage struct {
+	Questions      int       `json:"questions"`
+	QuestionsLimit int       `json:"questions_limit"` // 0 — без ограничения
+	CostUSD        float64   `json:"cost_usd"`
+	CostLimitUSD   float64   `json:"cost_limit_usd"` // 0 — без ограничения
+	ResetsAt       time.Time `json:"resets_at"`
+}
+
+// generatedQuestion — вопрос в ответе модели
+type generatedQuestion struct {
+	Text          string   `json:"text"`
+	Options       []string `json:"options"`
+	CorrectOption int      `json:"correct_option"`
+	Difficulty    int      `json:"difficulty"`
+}
+
+type questionPromptTier struct {
+	Difficulty int
+	Count      int
+}
+
+type questionPromptData struct {
+	Topic     string
+	Language  string
+	Total     int
+	MaxLength int
+	Spread    []questionPromptTier
+}
+
+// QuestionGenerationService генерирует вопросы языковой моделью по теме и распределению сложности.
+// Сгенерированные вопросы проходят те же проверки и отбрасывание дубликатов, что и импорт, и попадают
+// в очередь проверки (pending_review) — напрямую в пул модель ничего не добавляет. Генерация идёт
+// в фоне; токены и стоимость сохраняются и ограничиваются дневными квотами администратора.
+type QuestionGenerationService struct {
+	questionRepo   repository.QuestionRepository
+	generationRepo repository.QuestionGenerationRepository
+	categories     categoryLookup
+	provider       LLMProvider
+	cfg            config.AIQuestionsConfig
+	prompts        map[string]*template.Template
+	quotaMu        sync.Mutex // проверка квоты и создание запроса — атомарно
+	saveMu         sync.Mutex // проверка дубликатов и сохранение вопросов
+	running        sync.WaitGroup
+	now            func() time.Time
+}
+
+// NewQuestionGenerationService создает сервис генерации и разбирает встроенные шаблоны запросов
+func NewQuestionGenerationService(
+	questionRepo repository.QuestionRepository,
+	generationRepo repository.QuestionGenerationRepository,
+	categories categoryLookup,
+	provider LLMProvider,
+	cfg config.AIQuestionsConfig,
+) (*QuestionGenerationService, error) {
+	files, err := fs.Glob(builtinQuestionPrompts, "question_prompts/*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	prompts := make(map[string]*template.Template, len(files))
+	for _, file := range files {
+		tmpl, err := template.New(path.Base(file)).Option("missingkey=error").ParseFS(builtinQuestionPrompts, file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse question prompt %s: %w", file, err)
+		}
+		if tmpl.Lookup("system") == nil || tmpl.Lookup("prompt") == nil {
+			return nil, fmt.Errorf("question prompt %s must define system and prompt", file)
+		}
+		prompts[strings.TrimSuffix(path.Base(file), ".tmpl")] = tmpl
+	}
+	return &QuestionGenerationService{
+		questionRepo:   questionRepo,
+		generationRepo: generationRepo,
+		categories:     categories,
+		provider:       provider,
+		cfg:            cfg,
+		prompts:        prompts,
+		now:            time.Now,
+	}, nil
+}
+
+// Templates возвращает имена доступных шаблонов запросов
+func (s *QuestionGenerationService) Templates() []string {
+	names := make([]string, 0, len(s.prompts))
+	for name := range s.prompts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Usage возвращает расход администратора за текущие сутки UTC
+func (s *QuestionGenerationService) Usage(adminID uint) (*QuestionGenerationUsage, error) {
+	now := s.now().UTC()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	questions, cost, err := s.generationRepo.UsageSince(adminID, dayStart)
+	if err != nil {
+		return nil, err
+	}
+	return &QuestionGenerationUsage{
+		Questions:      questions,
+		QuestionsLimit: s.cfg.DailyQuestionsPerAdmin,
+		CostUSD:        cost,
+		CostLimitUSD:   s.cfg.DailyCostPerAdminUSD,
+		ResetsAt:       dayStart.AddDate(0, 0, 1),
+	}, nil
+}
+
+// Generate проверяет запрос и квоты, сохраняет генерацию со статусом running и запускает её в фоне.
+// Итоги — в GetGeneration. ErrGenerationQuotaExceeded, если квота администратора исчерпана.
+func (s *QuestionGenerationService) Generate(adminID uint, req QuestionGenerationRequest) (*entity.QuestionGeneration, error) {
+	generation, llmRequest, err := s.prepare(adminID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.quotaMu.Lock()
+	defer s.quotaMu.Unlock()
+	usage, err := s.Usage(adminID)
+	if err != nil {
+		return nil, err
+	}
+	if usage.QuestionsLimit > 0 && usage.Questions+generation.Requested > usage.QuestionsLimit {
+		return nil, fmt.Errorf("%w: %d of %d questions used today", ErrGenerationQuotaExceeded, usage.Questions, usage.QuestionsLimit)
+	}
+	if usage.CostLimitUSD > 0 && usage.CostUSD >= usage.CostLimitUSD {
+		return nil, fmt.Errorf("%w: $%.2f of $%.2f spent today", ErrGenerationQuotaExceeded, usage.CostUSD, usage.CostLimitUSD)
+	}
+	if err := s.generationRepo.Create(generation); err != nil {
+		return nil, err
+	}
+
+	// Фоновая генерация работает со своей копией: вызывающий отдаёт запись клиенту
+	background := *generation
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(s.cfg.TimeoutSec)*time.Second)
+		defer cancel()
+		s.execute(ctx, &background, llmRequest)
+	}()
+	return generation, nil
+}
+
+// prepare проверяет запрос и собирает запись генерации и запрос к модели
+func (s *QuestionGenerationService) prepare(adminID uint, req QuestionGenerationRequest) (*entity.QuestionGeneration, LLMRequest, error) {
+	topic := strings.Join(strings.Fields(req.Topic), " ")
+	if length := utf8.RuneCountInString(topic); length < 3 || length > 200 {
+		return nil, LLMRequest{}, fmt.Errorf("%w: topic must be between 3 and 200 characters", apperrors.ErrValidation)
+	}
+	data := questionPromptData{Topic: topic, MaxLength: maxImportedQuestionLength}
+	for difficulty, count := range req.Spread {
+		if difficulty < 1 || difficulty > 5 || count < 0 {
+			return nil, LLMRequest{}, fmt.Errorf("%w: difficulty_spread keys must be 1-5 with non-negative counts", apperrors.ErrValidation)
+		}
+		if count > 0 {
+			data.Spread = append(data.Spread, questionPromptTier{Difficulty: difficulty, Count: count})
+			data.Total += count
+		}
+	}
+	if data.Total < 1 || data.Total > s.cfg.MaxQuestionsPerRequest {
+		return nil, LLMRequest{}, fmt.Errorf("%w: difficulty_spread must request between 1 and %d questions", apperrors.ErrValidation, s.cfg.MaxQuestionsPerRequest)
+	}
+	sort.Slice(data.Spread, func(i, j int) bool { return data.Spread[i].Difficulty < data.Spread[j].Difficulty })
+
+	templateName := req.Template
+	if templateName == "" {
+		templateName = defaultQuestionPrompt
+	}
+	prompt, ok := s.prompts[templateName]
+	if !ok {
+		return nil, LLMRequest{}, fmt.Errorf("%w: unknown prompt template %q", apperrors.ErrValidation, templateName)
+	}
+	language := req.Language
+	if language == "" {
+		language = s.cfg.Language
+	}
+	data.Language, ok = questionLanguages[language]
+	if !ok {
+		return nil, LLMRequest{}, fmt.Errorf("%w: language must be ru, kk or en", apperrors.ErrValidation)
+	}
+
+	var categoryID *uint
+	if req.Category != "" {
+		category, err := s.categories.GetCategoryBySlug(req.Category)
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil, LLMRequest{}, fmt.Errorf("%w: unknown category %q", apperrors.ErrValidation, req.Category)
+		}
+		if err != nil {
+			return nil, LLMRequest{}, err
+		}
+		categoryID = &category.ID
+	}
+
+	var system, user bytes.Buffer
+	if err := prompt.ExecuteTemplate(&system, "system", data); err != nil {
+		return nil, LLMRequest{}, fmt.Errorf("failed to render question prompt %s: %w", templateName, err)
+	}
+	if err := prompt.ExecuteTemplate(&user, "prompt", data); err != nil {
+		return nil, LLMRequest{}, fmt.Errorf("failed to render question prompt %s: %w", templateName, err)
+	}
+
+	spread := make(entity.DifficultySpread, len(data.Spread))
+	for _, tier := range data.Spread {
+		spread[tier.Difficulty] = tier.Count
+	}
+	generation := &entity.QuestionGeneration{
+		AdminID:    adminID,
+		Provider:   s.provider.Name(),
+		Model:      s.provider.Model(),
+		Topic:      topic,
+		Language:   language,
+		Template:   templateName,
+		CategoryID: categoryID,
+		Spread:     spread,
+		Requested:  data.Total,
+		Status:     entity.QuestionGenerationRunning,
+	}
+	return generation, LLMRequest{
+		System:    strings.TrimSpace(system.String()),
+		Prompt:    strings.TrimSpace(user.String()),
+		MaxTokens: s.cfg.MaxTokens,
+	}, nil
+}
+
+// execute обращается к модели, сохраняет прошедшие проверку вопросы и итоги генерации
+func (s *QuestionGenerationService) execute(ctx context.Context, generation *entity.QuestionGeneration, req LLMRequest) {
+	if err := s.generate(ctx, generation, req); err != nil {
+		generation.Status = entity.QuestionGenerationFailed
+		generation.Error = truncateRunes(err.Error(), 500)
+		log.Printf("[QuestionGeneration] Генерация #%d (%s/%s) не выполнена: %v", generation.ID, generation.Provider, generation.Model, err)
+	} else {
+		generation.Status = entity.QuestionGenerationCompleted
+		log.Printf("[QuestionGeneration] Генерация #%d: получено %d, добавлено %d, дубликатов %d, пропущено %d, $%.4f",
+			generation.ID, generation.Generated, generation.Imported, generation.Duplicates, generation.Skipped, generation.CostUSD)
+	}
+	now := s.now().UTC()
+	generation.CompletedAt = &now
+	if err := s.generationRepo.Save(generation); err != nil {
+		log.Printf("[QuestionGeneration] ERROR: не удалось сохранить итоги генерации #%d: %v", generation.ID, err)
+	}
+}
+
+// generate заполняет расход и счётчики generation; ошибка — генерация не удалась
+func (s *QuestionGenerationService) generate(ctx context.Context, generation *entity.QuestionGeneration, req LLMRequest) error {
+	resp, err := s.provider.Complete(ctx, req)
+	if resp != nil {
+		generation.PromptTokens = resp.PromptTokens
+		generation.CompletionTokens = resp.CompletionTokens
+		cost := float64(resp.PromptTokens)*s.cfg.InputPricePerMillion/1e6 + float64(resp.CompletionTokens)*s.cfg.OutputPricePerMillion/1e6
+		generation.CostUSD = math.Round(cost*1e6) / 1e6
+	}
+	if err != nil {
+		return err
+	}
+
+	generated, err := parseGeneratedQuestions(resp.Text)
+	if err != nil {
+		return err
+	}
+	generation.Generated = len(generated)
+	if len(generated) > generation.Requested {
+		generation.Skipped += len(generated) - generation.Requested
+		generated = generated[:generation.Requested]
+	}
+
+	candidates := make([]entity.Question, 0, len(generated))
+	for _, item := range generated {
+		difficulty := item.Difficulty
+		if difficulty < 1 || difficulty > 5 {
+			difficulty = defaultImportedDifficulty
+		}
+		question, ok := newPoolQuestion(item.Text, item.Options, item.CorrectOption, difficulty)
+		if !ok {
+			generation.Skipped++
+			continue
+		}
+		question.Source = entity.QuestionSourceAI
+		question.SourceRef = truncateRunes(generation.Provider+"/"+generation.Model, 100)
+		question.CategoryID = generation.CategoryID
+		question.GenerationID = &generation.ID
+		candidates = append(candidates, question)
+	}
+
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+	questions, duplicates, err := dropDuplicateQuestions(s.questionRepo, candidates)
+	if err != nil {
+		return err
+	}
+	generation.Duplicates = duplicates
+	if len(questions) == 0 {
+		return nil
+	}
+	if err := s.questionRepo.CreateBatch(questions); err != nil {
+		return fmt.Errorf("failed to save generated questions: %w", err)
+	}
+	generation.Imported = len(questions)
+	return nil
+}
+
+// parseGeneratedQuestions достаёт JSON-массив вопросов из ответа модели
+func parseGeneratedQuestions(text string) ([]generatedQuestion, error) {
+	var questions []generatedQuestion
+	if err := decodeModelJSONArray(text, &questions); err != nil {
+		return nil, err
+	}
+	return questions, nil
+}
+
+// decodeModelJSONArray разбирает JSON-массив из ответа модели в out. Модели иногда оборачивают
+// ответ в ```json или добавляют пояснения — берётся текст от первой [ до последней ].
+func decodeModelJSONArray(text string, out interface{}) error {
+	start := strings.Index(text, "[")
+	end := strings.LastIndex(text, "]")
+	if start < 0 || end < start {
+		return fmt.Errorf("model response contains no JSON array")
+	}
+	if err := json.Unmarshal([]byte(text[start:end+1]), out); err != nil {
+		return fmt.Errorf("failed to parse model response: %w", err)
+	}
+	return nil
+}
+
+// ListGenerations возвращает запросы генерации, новые первыми
+func (s *QuestionGenerationService) ListGenerations(page, pageSize int) ([]entity.QuestionGeneration, int64, error) {
+	page, pageSize = normalizeReviewPage(page, pageSize)
+	return s.generationRepo.List(pageSize, (page-1)*pageSize)
+}
+
+// GetGeneration возвращает запрос генерации
+func (s *QuestionGenerationService) GetGeneration(id uint) (*entity.QuestionGeneration, error) {
+	return s.generationRepo.GetByID(id)
+}
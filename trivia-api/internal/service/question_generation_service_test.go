@@ -0,0 +1,304 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/config"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// fakeLLMProvider отвечает заранее заданным текстом и запоминает запросы
+type fakeLLMProvider struct {
+	mu       sync.Mutex
+	text     string
+	err      error
+	requests []LLMRequest
+}
+
+func (p *fakeLLMProvider) Name() string { return "fake" }
+
+func (p *fakeLLMProvider) Model() string { return "fake-model" }
+
+func (p *fakeLLMProvider) Complete(ctx context.Context, req LLMRequest) (*LLMResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.requests = append(p.requests, req)
+	return &LLMResponse{Text: p.text, PromptTokens: 1000, CompletionTokens: 2000}, p.err
+}
+
+// fakeQuestionGenerationRepo — QuestionGenerationRepository в памяти; генерации идут в фоне
+type fakeQuestionGenerationRepo struct {
+	mu          sync.Mutex
+	generations []entity.QuestionGeneration
+	now         func() time.Time
+}
+
+func (r *fakeQuestionGenerationRepo) Create(generation *entity.QuestionGeneration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	generation.ID = uint(len(r.generations) + 1)
+	generation.CreatedAt = r.now()
+	r.generations = append(r.generations, *generation)
+	return nil
+}
+
+func (r *fakeQuestionGenerationRepo) Save(generation *entity.QuestionGeneration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.generations[generation.ID-1] = *generation
+	return nil
+}
+
+func (r *fakeQuestionGenerationRepo) GetByID(id uint) (*entity.QuestionGeneration, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if id == 0 || int(id) > len(r.generations) {
+		return nil, apperrors.ErrNotFound
+	}
+	generation := r.generations[id-1]
+	return &generation, nil
+}
+
+func (r *fakeQuestionGenerationRepo) List(limit, offset int) ([]entity.QuestionGeneration, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.generations, int64(len(r.generations)), nil
+}
+
+func (r *fakeQuestionGenerationRepo) UsageSince(adminID uint, since time.Time) (int, float64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	questions, cost := 0, 0.0
+	for _, generation := range r.generations {
+		if generation.AdminID != adminID || generation.CreatedAt.Before(since) {
+			continue
+		}
+		if generation.Status != entity.QuestionGenerationFailed {
+			questions += generation.Requested
+		}
+		cost += generation.CostUSD
+	}
+	return questions, cost, nil
+}
+
+const generatedQuestionsResponse = "Here you go:\n```json\n" + `[
+  {"text": "What is the chemical symbol for gold?", "options": ["Au", "Ag", "Gd"], "correct_option": 0, "difficulty": 2},
+  {"text": "What is the capital of France?", "options": ["Paris", "Lyon", "Nice"], "correct_option": 0, "difficulty": 4},
+  {"text": "Which planet has the most moons?", "options": ["Earth", "Saturn", "Mars", "Venus"], "correct_option": 1, "difficulty": 9},
+  {"text": "How many bones are in the adult human body?", "options": ["206", "201"], "correct_option": 0, "difficulty": 4}
+]` + "\n```"
+
+func newTestQuestionGenerationService(t *testing.T, provider LLMProvider, cfg config.AIQuestionsConfig, pool ...entity.Question) (*QuestionGenerationService, *importQuestionRepo, *fakeQuestionGenerationRepo) {
+	t.Helper()
+	questions := &importQuestionRepo{}
+	_ = questions.CreateBatch(pool)
+	clock := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	generations := &fakeQuestionGenerationRepo{now: func() time.Time { return clock }}
+	categories := importCategories{"science": {ID: 4, Slug: "science"}}
+	svc, err := NewQuestionGenerationService(questions, generations, categories, provider, cfg)
+	require.NoError(t, err)
+	svc.now = generations.now
+	return svc, questions, generations
+}
+
+func testAIQuestionsConfig() config.AIQuestionsConfig {
+	return config.AIQuestionsConfig{
+		MaxTokens:              4000,
+		TimeoutSec:             5,
+		InputPricePerMillion:   0.15,
+		OutputPricePerMillion:  0.6,
+		Language:               "ru",
+		MaxQuestionsPerRequest: 20,
+	}
+}
+
+func TestQuestionGenerationService_Generate(t *testing.T) {
+	provider := &fakeLLMProvider{text: generatedQuestionsResponse}
+	svc, questions, generations := newTestQuestionGenerationService(t, provider, testAIQuestionsConfig(),
+		entity.Question{Text: "What is the capital of France", Options: entity.StringArray{"Paris", "Rome"}})
+	assert.Equal(t, []string{"kids", "standard"}, svc.Templates())
+
+	generation, err := svc.Generate(7, QuestionGenerationRequest{
+		Topic:    "  Science\nand nature ",
+		Spread:   entity.DifficultySpread{2: 1, 4: 2, 5: 0},
+		Language: "kk",
+		Category: "science",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, entity.QuestionGenerationRunning, generation.Status)
+	assert.Equal(t, 3, generation.Requested)
+	assert.Equal(t, "Science and nature", generation.Topic)
+	assert.Equal(t, "standard", generation.Template)
+	assert.Equal(t, entity.DifficultySpread{2: 1, 4: 2}, generation.Spread)
+	svc.running.Wait()
+
+	require.Len(t, provider.requests, 1)
+	req := provider.requests[0]
+	assert.Equal(t, 4000, req.MaxTokens)
+	assert.Contains(t, req.System, "JSON array")
+	assert.Contains(t, req.Prompt, "3 multiple-choice trivia questions in Kazakh about: Science and nature")
+	assert.Contains(t, req.Prompt, "- 1 question(s) of difficulty 2\n- 2 question(s) of difficulty 4")
+
+	stored, err := svc.GetGeneration(generation.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.QuestionGenerationCompleted, stored.Status)
+	assert.Equal(t, 4, stored.Generated)
+	assert.Equal(t, 2, stored.Imported)
+	assert.Equal(t, 1, stored.Duplicates, "вопрос уже есть в пуле")
+	assert.Equal(t, 1, stored.Skipped, "сверх запрошенного")
+	assert.Equal(t, 1000, stored.PromptTokens)
+	assert.Equal(t, 2000, stored.CompletionTokens)
+	assert.InDelta(t, 0.00135, stored.CostUSD, 1e-9)
+	assert.NotNil(t, stored.CompletedAt)
+	assert.Len(t, generations.generations, 1)
+
+	generated := questions.questions[1:]
+	require.Len(t, generated, 2)
+	assert.Equal(t, 2, generated[0].Difficulty)
+	assert.Equal(t, 3, generated[1].Difficulty, "сложность вне 1–5 — средняя")
+	assert.Equal(t, "Saturn", generated[1].Options[generated[1].CorrectOption])
+	for _, question := range generated {
+		assert.Nil(t, question.QuizID)
+		assert.Equal(t, entity.QuestionReviewPendingReview, question.ReviewStatus, "модель не добавляет вопросы в эфир")
+		assert.Equal(t, entity.QuestionSourceAI, question.Source)
+		assert.Equal(t, "fake/fake-model", question.SourceRef)
+		assert.Equal(t, &generation.ID, question.GenerationID)
+		require.NotNil(t, question.CategoryID)
+		assert.Equal(t, uint(4), *question.CategoryID)
+	}
+}
+
+func TestQuestionGenerationService_ModelFailure(t *testing.T) {
+	provider := &fakeLLMProvider{text: `[{"text": "Truncated`, err: errors.New("model response truncated at max_tokens")}
+	svc, questions, _ := newTestQuestionGenerationService(t, provider, testAIQuestionsConfig())
+
+	generation, err := svc.Generate(7, QuestionGenerationRequest{Topic: "History", Spread: entity.DifficultySpread{3: 5}})
+	require.NoError(t, err)
+	svc.running.Wait()
+
+	stored, err := svc.GetGeneration(generation.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.QuestionGenerationFailed, stored.Status)
+	assert.Contains(t, stored.Error, "max_tokens")
+	assert.InDelta(t, 0.00135, stored.CostUSD, 1e-9, "оплаченные токены учитываются и при ошибке")
+	assert.Empty(t, questions.questions)
+
+	// Ответ без JSON-массива — тоже неудача
+	provider.err = nil
+	provider.text = "Sorry, I cannot help with that."
+	generation, err = svc.Generate(7, QuestionGenerationRequest{Topic: "History", Spread: entity.DifficultySpread{3: 5}})
+	require.NoError(t, err)
+	svc.running.Wait()
+	stored, err = svc.GetGeneration(generation.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.QuestionGenerationFailed, stored.Status)
+	assert.Contains(t, stored.Error, "no JSON array")
+}
+
+func TestQuestionGenerationService_Quota(t *testing.T) {
+	cfg := testAIQuestionsConfig()
+	cfg.DailyQuestionsPerAdmin = 5
+	cfg.DailyCostPerAdminUSD = 0.002
+	provider := &fakeLLMProvider{text: "[]"}
+	svc, _, generations := newTestQuestionGenerationService(t, provider, cfg)
+
+	_, err := svc.Generate(7, QuestionGenerationRequest{Topic: "Space", Spread: entity.DifficultySpread{3: 3}})
+	require.NoError(t, err)
+	svc.running.Wait()
+
+	_, err = svc.Generate(7, QuestionGenerationRequest{Topic: "Space", Spread: entity.DifficultySpread{3: 3}})
+	assert.ErrorIs(t, err, ErrGenerationQuotaExceeded, "3 + 3 > 5 вопросов за сутки")
+	_, err = svc.Generate(7, QuestionGenerationRequest{Topic: "Space", Spread: entity.DifficultySpread{3: 2}})
+	require.NoError(t, err)
+	svc.running.Wait()
+
+	usage, err := svc.Usage(7)
+	require.NoError(t, err)
+	assert.Equal(t, 5, usage.Questions)
+	assert.InDelta(t, 0.0027, usage.CostUSD, 1e-9)
+	assert.Equal(t, time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC), usage.ResetsAt)
+
+	// Квота по стоимости исчерпана, даже если вопросов хватает — но только у этого администратора
+	generations.generations[1].Requested = 0
+	_, err = svc.Generate(7, QuestionGenerationRequest{Topic: "Space", Spread: entity.DifficultySpread{3: 1}})
+	assert.ErrorIs(t, err, ErrGenerationQuotaExceeded)
+	_, err = svc.Generate(8, QuestionGenerationRequest{Topic: "Space", Spread: entity.DifficultySpread{3: 5}})
+	require.NoError(t, err)
+	svc.running.Wait()
+
+	// На следующие сутки UTC квота обновляется
+	svc.now = func() time.Time { return time.Date(2026, 10, 17, 0, 30, 0, 0, time.UTC) }
+	generations.now = svc.now
+	_, err = svc.Generate(7, QuestionGenerationRequest{Topic: "Space", Spread: entity.DifficultySpread{3: 5}})
+	require.NoError(t, err)
+	svc.running.Wait()
+}
+
+func TestQuestionGenerationService_Validation(t *testing.T) {
+	svc, _, generations := newTestQuestionGenerationService(t, &fakeLLMProvider{}, testAIQuestionsConfig())
+
+	invalid := []QuestionGenerationRequest{
+		{Topic: "ab", Spread: entity.DifficultySpread{3: 1}},
+		{Topic: "Space", Spread: entity.DifficultySpread{6: 1}},
+		{Topic: "Space", Spread: entity.DifficultySpread{3: -1, 4: 2}},
+		{Topic: "Space", Spread: entity.DifficultySpread{3: 0}},
+		{Topic: "Space", Spread: entity.DifficultySpread{3: 15, 4: 6}},
+		{Topic: "Space", Spread: entity.DifficultySpread{3: 1}, Template: "poetry"},
+		{Topic: "Space", Spread: entity.DifficultySpread{3: 1}, Language: "de"},
+		{Topic: "Space", Spread: entity.DifficultySpread{3: 1}, Category: "sport"},
+	}
+	for _, req := range invalid {
+		_, err := svc.Generate(7, req)
+		assert.ErrorIs(t, err, apperrors.ErrValidation, "%+v", req)
+	}
+	assert.Empty(t, generations.generations)
+}
+
+func TestLLMProviders_Complete(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "test-model", body["model"])
+		assert.EqualValues(t, 100, body["max_tokens"])
+		switch r.URL.Path {
+		case "/v1/chat/completions":
+			assert.Equal(t, "Bearer sk-test", r.Header.Get("Authorization"))
+			assert.Len(t, body["messages"], 2)
+			_, _ = w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "[]"}, "finish_reason": "stop"}],
+				"usage": {"prompt_tokens": 12, "completion_tokens": 3}}`))
+		case "/v1/messages":
+			assert.Equal(t, "sk-ant-test", r.Header.Get("x-api-key"))
+			assert.Equal(t, anthropicAPIVersion, r.Header.Get("anthropic-version"))
+			assert.Equal(t, "system", body["system"])
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"type": "error", "error": {"type": "rate_limit_error", "message": "Number of requests has exceeded your rate limit"}}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	req := LLMRequest{System: "system", Prompt: "prompt", MaxTokens: 100}
+
+	openAI, err := NewLLMProviderFromConfig(config.AIQuestionsConfig{Provider: LLMProviderOpenAI, BaseURL: server.URL, APIKey: "sk-test", Model: "test-model", TimeoutSec: 5})
+	require.NoError(t, err)
+	resp, err := openAI.Complete(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, &LLMResponse{Text: "[]", PromptTokens: 12, CompletionTokens: 3}, resp)
+
+	anthropic, err := NewLLMProviderFromConfig(config.AIQuestionsConfig{Provider: LLMProviderAnthropic, BaseURL: server.URL, APIKey: "sk-ant-test", Model: "test-model", TimeoutSec: 5})
+	require.NoError(t, err)
+	_, err = anthropic.Complete(context.Background(), req)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "HTTP 429: Number of requests has exceeded your rate limit")
+}
@@ -0,0 +1,355 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+const (
+	// maxImportedQuestionLength — предел длины текста вопроса и варианта (questions.text)
+	maxImportedQuestionLength = 500
+	// defaultImportedDifficulty — сложность вопросов, сложность которых источник не указал или она не сопоставлена
+	defaultImportedDifficulty = 3
+)
+
+// defaultImportDifficulties — сопоставление сложности источника со шкалой 1–5, если в настройках его нет
+var defaultImportDifficulties = map[string]int{"easy": 2, "medium": 3, "hard": 4}
+
+// ExternalQuestion — вопрос внешнего источника до сопоставления с пулом
+type ExternalQuestion struct {
+	Ref           string // идентификатор в источнике, если он есть
+	Category      string // название категории в источнике
+	Difficulty    string // сложность в терминах источника
+	Text          string
+	Options       []string
+	CorrectOption int
+}
+
+// QuestionImportQuery — что запросить у источника
+type QuestionImportQuery struct {
+	Amount           int    `json:"amount"`
+	ExternalCategory string `json:"external_category,omitempty"` // фильтр категории в терминах источника
+	Difficulty       string `json:"difficulty,omitempty"`        // фильтр сложности в терминах источника
+}
+
+// QuestionSource — внешний источник вопросов
+type QuestionSource interface {
+	Name() string
+	License() string     // лицензия контента, сохраняется в questions.license
+	Attribution() string // обязательная ссылка на источник, сохраняется в questions.attribution
+	MaxAmount() int      // предел вопросов за один запрос
+	Fetch(ctx context.Context, query QuestionImportQuery) ([]ExternalQuestion, error)
+}
+
+// QuestionSourceMapping сопоставляет категории и сложность источника с пулом
+type QuestionSourceMapping struct {
+	Categories   map[string]string // название категории источника (без учёта регистра) → slug категории
+	Difficulties map[string]int    // сложность источника (без учёта регистра) → 1–5
+}
+
+// ScheduledQuestionImport — импорт, выполняемый по расписанию
+type ScheduledQuestionImport struct {
+	Source string
+	Query  QuestionImportQuery
+}
+
+// categoryLookup находит категорию по slug (TaxonomyService)
+type categoryLookup interface {
+	GetCategoryBySlug(slug string) (*entity.Category, error)
+}
+
+type registeredQuestionSource struct {
+	source  QuestionSource
+	mapping QuestionSourceMapping
+}
+
+// QuestionImportService импортирует вопросы из внешних источников в общий пул. Вопросы
+// сопоставляются с категориями и сложностью пула, дубликаты (тот же текст без учёта регистра
+// и знаков препинания, см. entity.QuestionTextHash) отбрасываются, остальные попадают
+// в очередь проверки (pending_review) — в эфир импортированный вопрос не попадёт без одобрения.
+type QuestionImportService struct {
+	questionRepo repository.QuestionRepository
+	importRepo   repository.QuestionImportRepository
+	categories   categoryLookup
+	sources      map[string]registeredQuestionSource
+	mu           sync.Mutex // один импорт за раз: иначе проверка дубликатов пропустит параллельный запуск
+}
+
+// NewQuestionImportService создает сервис импорта; источники подключаются через RegisterSource
+func NewQuestionImportService(
+	questionRepo repository.QuestionRepository,
+	importRepo repository.QuestionImportRepository,
+	categories categoryLookup,
+) *QuestionImportService {
+	return &QuestionImportService{
+		questionRepo: questionRepo,
+		importRepo:   importRepo,
+		categories:   categories,
+		sources:      make(map[string]registeredQuestionSource),
+	}
+}
+
+// RegisterSource подключает источник с сопоставлением категорий и сложности
+func (s *QuestionImportService) RegisterSource(source QuestionSource, mapping QuestionSourceMapping) {
+	normalized := QuestionSourceMapping{
+		Categories:   make(map[string]string, len(mapping.Categories)),
+		Difficulties: make(map[string]int, len(mapping.Difficulties)),
+	}
+	for external, slug := range mapping.Categories {
+		normalized.Categories[strings.ToLower(strings.TrimSpace(external))] = slug
+	}
+	difficulties := mapping.Difficulties
+	if len(difficulties) == 0 {
+		difficulties = defaultImportDifficulties
+	}
+	for external, difficulty := range difficulties {
+		normalized.Difficulties[strings.ToLower(strings.TrimSpace(external))] = difficulty
+	}
+	s.sources[source.Name()] = registeredQuestionSource{source: source, mapping: normalized}
+}
+
+// Sources возвращает имена подключённых источников
+func (s *QuestionImportService) Sources() []string {
+	names := make([]string, 0, len(s.sources))
+	for name := range s.sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Import загружает вопросы из источника и добавляет новые в пул на проверку. triggeredBy — администратор
+// (nil — запуск по расписанию). Ошибка источника не возвращается: запуск сохраняется со статусом failed.
+func (s *QuestionImportService) Import(ctx context.Context, triggeredBy *uint, sourceName string, query QuestionImportQuery) (*entity.QuestionImport, error) {
+	registered, ok := s.sources[sourceName]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown question source %q", apperrors.ErrValidation, sourceName)
+	}
+	if query.Amount < 1 || query.Amount > registered.source.MaxAmount() {
+		return nil, fmt.Errorf("%w: amount must be between 1 and %d", apperrors.ErrValidation, registered.source.MaxAmount())
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	run := &entity.QuestionImport{
+		Source:           sourceName,
+		ExternalCategory: query.ExternalCategory,
+		Difficulty:       query.Difficulty,
+		Requested:        query.Amount,
+		Status:           entity.QuestionImportRunning,
+		TriggeredBy:      triggeredBy,
+	}
+	if err := s.importRepo.Create(run); err != nil {
+		return nil, err
+	}
+
+	if err := s.importQuestions(ctx, registered, run, query); err != nil {
+		run.Status = entity.QuestionImportFailed
+		run.Error = truncateRunes(err.Error(), 500)
+		log.Printf("[QuestionImport] Импорт #%d из %s не выполнен: %v", run.ID, sourceName, err)
+	} else {
+		run.Status = entity.QuestionImportCompleted
+		log.Printf("[QuestionImport] Импорт #%d из %s: получено %d, добавлено %d, дубликатов %d, пропущено %d",
+			run.ID, sourceName, run.Fetched, run.Imported, run.Duplicates, run.Skipped)
+	}
+	now := time.Now().UTC()
+	run.CompletedAt = &now
+	if err := s.importRepo.Save(run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// importQuestions загружает вопросы, отбрасывает дубликаты и сохраняет остальные, заполняя счётчики run
+func (s *QuestionImportService) importQuestions(ctx context.Context, registered registeredQuestionSource, run *entity.QuestionImport, query QuestionImportQuery) error {
+	fetched, err := registered.source.Fetch(ctx, query)
+	if err != nil {
+		return err
+	}
+	run.Fetched = len(fetched)
+
+	candidates := make([]entity.Question, 0, len(fetched))
+	categoryIDs := make(map[string]*uint)
+	for _, external := range fetched {
+		question, ok := s.toQuestion(registered, external, run.ID)
+		if !ok {
+			run.Skipped++
+			continue
+		}
+		question.CategoryID = s.resolveCategory(registered, external.Category, categoryIDs)
+		candidates = append(candidates, question)
+	}
+
+	questions, duplicates, err := dropDuplicateQuestions(s.questionRepo, candidates)
+	if err != nil {
+		return err
+	}
+	run.Duplicates = duplicates
+	for _, question := range questions {
+		if question.CategoryID == nil {
+			run.Uncategorized++
+		}
+	}
+	if len(questions) == 0 {
+		return nil
+	}
+	if err := s.questionRepo.CreateBatch(questions); err != nil {
+		return fmt.Errorf("failed to save imported questions: %w", err)
+	}
+	run.Imported = len(questions)
+	return nil
+}
+
+// toQuestion проверяет вопрос источника и переводит его в вопрос пула; false — вопрос пропускается
+func (s *QuestionImportService) toQuestion(registered registeredQuestionSource, external ExternalQuestion, importID uint) (entity.Question, bool) {
+	difficulty, ok := registered.mapping.Difficulties[strings.ToLower(strings.TrimSpace(external.Difficulty))]
+	if !ok || difficulty < 1 || difficulty > 5 {
+		difficulty = defaultImportedDifficulty
+	}
+	question, ok := newPoolQuestion(external.Text, external.Options, external.CorrectOption, difficulty)
+	if !ok {
+		return entity.Question{}, false
+	}
+	question.Source = registered.source.Name()
+	question.SourceRef = external.Ref
+	question.License = registered.source.License()
+	question.Attribution = registered.source.Attribution()
+	question.ImportID = &importID
+	return question, true
+}
+
+// newPoolQuestion проверяет текст и варианты ответа вопроса из внешнего источника и создаёт вопрос
+// общего пула на проверке (pending_review); false — пустой или слишком длинный текст, меньше двух
+// вариантов, повтор варианта без учёта регистра или неверный индекс правильного ответа.
+func newPoolQuestion(text string, options []string, correctOption, difficulty int) (entity.Question, bool) {
+	text = strings.TrimSpace(text)
+	if text == "" || utf8.RuneCountInString(text) > maxImportedQuestionLength {
+		return entity.Question{}, false
+	}
+	if len(options) < 2 || correctOption < 0 || correctOption >= len(options) {
+		return entity.Question{}, false
+	}
+	normalized := make(entity.StringArray, len(options))
+	distinct := make(map[string]bool, len(options))
+	for i, option := range options {
+		option = strings.TrimSpace(option)
+		key := strings.ToLower(option)
+		if option == "" || distinct[key] || utf8.RuneCountInString(option) > maxImportedQuestionLength {
+			return entity.Question{}, false
+		}
+		distinct[key] = true
+		normalized[i] = option
+	}
+	return entity.Question{
+		Text:          text,
+		Options:       normalized,
+		CorrectOption: correctOption,
+		TimeLimitSec:  10,
+		PointValue:    1,
+		Difficulty:    difficulty,
+		ReviewStatus:  entity.QuestionReviewPendingReview,
+	}, true
+}
+
+// dropDuplicateQuestions отбрасывает вопросы, повторяющиеся в пачке или уже имеющиеся в пуле
+// (см. entity.QuestionTextHash). Возвращает оставшиеся вопросы и число отброшенных.
+func dropDuplicateQuestions(repo repository.QuestionRepository, candidates []entity.Question) ([]entity.Question, int, error) {
+	unique := make([]entity.Question, 0, len(candidates))
+	hashes := make([]string, 0, len(candidates))
+	seen := make(map[string]bool, len(candidates))
+	duplicates := 0
+	for _, question := range candidates {
+		question.TextHash = entity.QuestionTextHash(question.Text)
+		if seen[question.TextHash] {
+			duplicates++
+			continue
+		}
+		seen[question.TextHash] = true
+		unique = append(unique, question)
+		hashes = append(hashes, question.TextHash)
+	}
+	if len(unique) == 0 {
+		return unique, duplicates, nil
+	}
+
+	existing, err := repo.ExistingTextHashes(hashes)
+	if err != nil {
+		return nil, 0, err
+	}
+	questions := unique[:0]
+	for _, question := range unique {
+		if existing[question.TextHash] {
+			duplicates++
+			continue
+		}
+		questions = append(questions, question)
+	}
+	return questions, duplicates, nil
+}
+
+// resolveCategory находит категорию пула по категории источника; cache — найденные за запуск
+func (s *QuestionImportService) resolveCategory(registered registeredQuestionSource, externalCategory string, cache map[string]*uint) *uint {
+	slug, ok := registered.mapping.Categories[strings.ToLower(strings.TrimSpace(externalCategory))]
+	if !ok || s.categories == nil {
+		return nil
+	}
+	if id, cached := cache[slug]; cached {
+		return id
+	}
+	var id *uint
+	category, err := s.categories.GetCategoryBySlug(slug)
+	switch {
+	case err == nil:
+		id = &category.ID
+	case errors.Is(err, apperrors.ErrNotFound):
+		log.Printf("[QuestionImport] WARNING: категория %q из сопоставления %s не найдена", slug, registered.source.Name())
+	default:
+		log.Printf("[QuestionImport] WARNING: не удалось загрузить категорию %q: %v", slug, err)
+	}
+	cache[slug] = id
+	return id
+}
+
+// ListImports возвращает запуски импорта, новые первыми
+func (s *QuestionImportService) ListImports(page, pageSize int) ([]entity.QuestionImport, int64, error) {
+	page, pageSize = normalizeReviewPage(page, pageSize)
+	return s.importRepo.List(pageSize, (page-1)*pageSize)
+}
+
+// GetImport возвращает запуск импорта
+func (s *QuestionImportService) GetImport(id uint) (*entity.QuestionImport, error) {
+	return s.importRepo.GetByID(id)
+}
+
+// Start выполняет импорты runs каждые interval. Работает до отмены ctx.
+func (s *QuestionImportService) Start(ctx context.Context, interval time.Duration, runs []ScheduledQuestionImport) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				for _, scheduled := range runs {
+					if _, err := s.Import(ctx, nil, scheduled.Source, scheduled.Query); err != nil {
+						log.Printf("[QuestionImport] Ошибка импорта по расписанию из %s: %v", scheduled.Source, err)
+					}
+				}
+			}
+		}
+	}()
+}
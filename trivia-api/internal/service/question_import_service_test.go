@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// importQuestionRepo хранит вопросы пула в памяти
+type importQuestionRepo struct {
+	repository.QuestionRepository
+	questions []entity.Question
+}
+
+func (r *importQuestionRepo) CreateBatch(questions []entity.Question) error {
+	for _, question := range questions {
+		question.ID = uint(len(r.questions) + 1)
+		question.TextHash = entity.QuestionTextHash(question.Text) // как BeforeSave
+		r.questions = append(r.questions, question)
+	}
+	return nil
+}
+
+func (r *importQuestionRepo) ExistingTextHashes(hashes []string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+	for _, hash := range hashes {
+		for _, question := range r.questions {
+			if question.TextHash == hash {
+				existing[hash] = true
+			}
+		}
+	}
+	return existing, nil
+}
+
+// fakeQuestionImportRepo — QuestionImportRepository в памяти
+type fakeQuestionImportRepo struct {
+	runs []entity.QuestionImport
+}
+
+func (r *fakeQuestionImportRepo) Create(run *entity.QuestionImport) error {
+	run.ID = uint(len(r.runs) + 1)
+	r.runs = append(r.runs, *run)
+	return nil
+}
+
+func (r *fakeQuestionImportRepo) Save(run *entity.QuestionImport) error {
+	r.runs[run.ID-1] = *run
+	return nil
+}
+
+func (r *fakeQuestionImportRepo) GetByID(id uint) (*entity.QuestionImport, error) {
+	if id == 0 || int(id) > len(r.runs) {
+		return nil, apperrors.ErrNotFound
+	}
+	run := r.runs[id-1]
+	return &run, nil
+}
+
+func (r *fakeQuestionImportRepo) List(limit, offset int) ([]entity.QuestionImport, int64, error) {
+	return r.runs, int64(len(r.runs)), nil
+}
+
+type importCategories map[string]*entity.Category
+
+func (c importCategories) GetCategoryBySlug(slug string) (*entity.Category, error) {
+	category, ok := c[slug]
+	if !ok {
+		return nil, apperrors.ErrNotFound
+	}
+	return category, nil
+}
+
+type openTDBResult struct {
+	Category, Difficulty, Question, Correct string
+	Incorrect                               []string
+}
+
+// newOpenTDBServer отвечает как Open Trivia DB с encode=url3986 и запоминает параметры запроса
+func newOpenTDBServer(t *testing.T, responseCode int, results []openTDBResult, query *url.Values) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api.php", r.URL.Path)
+		*query = r.URL.Query()
+		encoded := make([]map[string]interface{}, 0, len(results))
+		for _, result := range results {
+			incorrect := make([]string, len(result.Incorrect))
+			for i, option := range result.Incorrect {
+				incorrect[i] = url.PathEscape(option)
+			}
+			encoded = append(encoded, map[string]interface{}{
+				"type":              "multiple",
+				"category":          url.PathEscape(result.Category),
+				"difficulty":        url.PathEscape(result.Difficulty),
+				"question":          url.PathEscape(result.Question),
+				"correct_answer":    url.PathEscape(result.Correct),
+				"incorrect_answers": incorrect,
+			})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"response_code": responseCode, "results": encoded})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestQuestionImportService(serverURL string, pool ...entity.Question) (*QuestionImportService, *importQuestionRepo, *fakeQuestionImportRepo) {
+	questions := &importQuestionRepo{}
+	_ = questions.CreateBatch(pool)
+	runs := &fakeQuestionImportRepo{}
+	categories := importCategories{"science": {ID: 4, Slug: "science"}}
+	svc := NewQuestionImportService(questions, runs, categories)
+	svc.RegisterSource(NewOpenTDBSource(serverURL, 5*time.Second), QuestionSourceMapping{
+		Categories: map[string]string{"Science: Computers": "science", "Sports": "sport"},
+	})
+	return svc, questions, runs
+}
+
+func TestQuestionImportService_OpenTDB(t *testing.T) {
+	var query url.Values
+	server := newOpenTDBServer(t, 0, []openTDBResult{
+		{Category: "Science: Computers", Difficulty: "hard", Question: "What does \"CPU\" stand for?", Correct: "Central Processing Unit", Incorrect: []string{"Central Process Unit", "Computer Personal Unit"}},
+		{Category: "History", Difficulty: "easy", Question: "Who painted the Mona Lisa?", Correct: "Leonardo da Vinci", Incorrect: []string{"Michelangelo", "Raphael"}},
+		{Category: "Sports", Difficulty: "medium", Question: "who painted the mona lisa", Correct: "Leonardo da Vinci", Incorrect: []string{"Donatello"}},
+		{Category: "Geography", Difficulty: "medium", Question: "What is the capital of France?", Correct: "Paris", Incorrect: []string{"Lyon"}},
+		{Category: "Geography", Difficulty: "medium", Question: "Largest ocean?", Correct: "Pacific", Incorrect: []string{"pacific"}},
+		{Category: "Sports", Difficulty: "extreme", Question: "How many players are in a football team?", Correct: "11", Incorrect: []string{"9", "10"}},
+	}, &query)
+	svc, questions, runs := newTestQuestionImportService(server.URL, entity.Question{Text: "What is the capital of France", Options: entity.StringArray{"Paris", "Rome"}})
+	adminID := uint(9)
+
+	run, err := svc.Import(context.Background(), &adminID, entity.QuestionSourceOpenTDB, QuestionImportQuery{Amount: 6, ExternalCategory: "18", Difficulty: "hard"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "6", query.Get("amount"))
+	assert.Equal(t, "18", query.Get("category"))
+	assert.Equal(t, "hard", query.Get("difficulty"))
+	assert.Equal(t, "url3986", query.Get("encode"))
+
+	assert.Equal(t, entity.QuestionImportCompleted, run.Status)
+	assert.Equal(t, 6, run.Fetched)
+	assert.Equal(t, 3, run.Imported)
+	assert.Equal(t, 2, run.Duplicates, "повтор в ответе источника и вопрос, уже имеющийся в пуле")
+	assert.Equal(t, 1, run.Skipped, "варианты ответа совпадают без учёта регистра")
+	assert.Equal(t, 2, run.Uncategorized, "History не сопоставлена, категории sport нет в пуле")
+	assert.NotNil(t, run.CompletedAt)
+	assert.Equal(t, &adminID, run.TriggeredBy)
+	assert.Equal(t, *run, runs.runs[0])
+
+	imported := questions.questions[1:]
+	require.Len(t, imported, 3)
+	cpu := imported[0]
+	assert.Equal(t, `What does "CPU" stand for?`, cpu.Text)
+	assert.Equal(t, "Central Processing Unit", cpu.Options[cpu.CorrectOption])
+	assert.Len(t, cpu.Options, 3)
+	assert.Equal(t, 4, cpu.Difficulty, "hard → 4 по умолчанию")
+	require.NotNil(t, cpu.CategoryID)
+	assert.Equal(t, uint(4), *cpu.CategoryID)
+	for _, question := range imported {
+		assert.Nil(t, question.QuizID, "импорт идёт в общий пул")
+		assert.Equal(t, entity.QuestionReviewPendingReview, question.ReviewStatus, "в эфир — только после проверки")
+		assert.Equal(t, entity.QuestionSourceOpenTDB, question.Source)
+		assert.Equal(t, "CC BY-SA 4.0", question.License)
+		assert.Contains(t, question.Attribution, "opentdb.com")
+		assert.Equal(t, &run.ID, question.ImportID)
+	}
+	assert.Equal(t, 2, imported[1].Difficulty)
+	assert.Equal(t, 3, imported[2].Difficulty, "несопоставленная сложность — средняя")
+
+	// Повторный импорт тех же вопросов ничего не добавляет
+	run, err = svc.Import(context.Background(), nil, entity.QuestionSourceOpenTDB, QuestionImportQuery{Amount: 6})
+	require.NoError(t, err)
+	assert.Equal(t, 0, run.Imported)
+	assert.Equal(t, 5, run.Duplicates)
+	assert.Nil(t, run.TriggeredBy)
+	assert.Len(t, questions.questions, 4)
+}
+
+func TestQuestionImportService_SourceFailure(t *testing.T) {
+	var query url.Values
+	server := newOpenTDBServer(t, 5, nil, &query)
+	svc, questions, _ := newTestQuestionImportService(server.URL)
+
+	run, err := svc.Import(context.Background(), nil, entity.QuestionSourceOpenTDB, QuestionImportQuery{Amount: 10})
+	require.NoError(t, err)
+	assert.Equal(t, entity.QuestionImportFailed, run.Status)
+	assert.Contains(t, run.Error, "rate limit")
+	assert.Empty(t, questions.questions)
+
+	stored, err := svc.GetImport(run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.QuestionImportFailed, stored.Status)
+}
+
+func TestQuestionImportService_Validation(t *testing.T) {
+	svc, _, runs := newTestQuestionImportService("http://127.0.0.1:0")
+	assert.Equal(t, []string{entity.QuestionSourceOpenTDB}, svc.Sources())
+
+	_, err := svc.Import(context.Background(), nil, "jservice", QuestionImportQuery{Amount: 10})
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	_, err = svc.Import(context.Background(), nil, entity.QuestionSourceOpenTDB, QuestionImportQuery{Amount: 0})
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	_, err = svc.Import(context.Background(), nil, entity.QuestionSourceOpenTDB, QuestionImportQuery{Amount: 51})
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	assert.Empty(t, runs.runs)
+}
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/pkg/storage"
+	"github.com/yourusername/trivia-api/internal/service/quizmanager"
+)
+
+// Ограничения на медиа вопросов по умолчанию
+const (
+	DefaultQuestionMaxImageSizeBytes = 5 * 1024 * 1024
+	DefaultQuestionMaxAudioSizeBytes = 10 * 1024 * 1024
+	DefaultQuestionMediaURLTTL       = 2 * time.Hour
+
+	questionMediaUploadTimeout = 2 * time.Minute
+	questionMediaPrefix        = "questions/"
+)
+
+// Виды медиа вопроса
+const (
+	QuestionMediaImage = "image"
+	QuestionMediaAudio = "audio"
+)
+
+// questionMediaFormat описывает допустимый формат медиа вопроса
+type questionMediaFormat struct {
+	kind     string
+	mimeType string // Content-Type объекта в хранилище
+	sniffed  string // результат http.DetectContentType для содержимого
+}
+
+// Допустимые форматы медиа: расширение → вид, MIME-тип и ожидаемый результат распознавания содержимого
+var questionMediaFormats = map[string]questionMediaFormat{
+	".jpg":  {kind: QuestionMediaImage, mimeType: "image/jpeg", sniffed: "image/jpeg"},
+	".jpeg": {kind: QuestionMediaImage, mimeType: "image/jpeg", sniffed: "image/jpeg"},
+	".png":  {kind: QuestionMediaImage, mimeType: "image/png", sniffed: "image/png"},
+	".webp": {kind: QuestionMediaImage, mimeType: "image/webp", sniffed: "image/webp"},
+	".gif":  {kind: QuestionMediaImage, mimeType: "image/gif", sniffed: "image/gif"},
+	".mp3":  {kind: QuestionMediaAudio, mimeType: "audio/mpeg", sniffed: "audio/mpeg"},
+	".ogg":  {kind: QuestionMediaAudio, mimeType: "audio/ogg", sniffed: "application/ogg"},
+	".wav":  {kind: QuestionMediaAudio, mimeType: "audio/wav", sniffed: "audio/wave"},
+	".m4a":  {kind: QuestionMediaAudio, mimeType: "audio/mp4", sniffed: "video/mp4"}, // контейнер MP4 распознаётся как видео
+}
+
+// QuestionMediaLimits содержит ограничения на загружаемые медиа вопросов
+type QuestionMediaLimits struct {
+	MaxImageSizeBytes int64
+	MaxAudioSizeBytes int64
+}
+
+// QuestionMediaService управляет картинками и аудиофрагментами вопросов в хранилище
+// и выдаёт на них подписанные ссылки для quiz:question
+type QuestionMediaService struct {
+	questionRepo repository.QuestionRepository
+	storage      storage.Storage
+	limits       QuestionMediaLimits
+	urlTTL       time.Duration
+}
+
+// NewQuestionMediaService создаёт сервис медиа вопросов
+func NewQuestionMediaService(questionRepo repository.QuestionRepository, store storage.Storage) *QuestionMediaService {
+	return &QuestionMediaService{
+		questionRepo: questionRepo,
+		storage:      store,
+		limits: QuestionMediaLimits{
+			MaxImageSizeBytes: DefaultQuestionMaxImageSizeBytes,
+			MaxAudioSizeBytes: DefaultQuestionMaxAudioSizeBytes,
+		},
+		urlTTL: DefaultQuestionMediaURLTTL,
+	}
+}
+
+// SetUploadLimits переопределяет ограничения на загружаемые файлы (нулевые значения игнорируются)
+func (s *QuestionMediaService) SetUploadLimits(limits QuestionMediaLimits) {
+	if limits.MaxImageSizeBytes > 0 {
+		s.limits.MaxImageSizeBytes = limits.MaxImageSizeBytes
+	}
+	if limits.MaxAudioSizeBytes > 0 {
+		s.limits.MaxAudioSizeBytes = limits.MaxAudioSizeBytes
+	}
+}
+
+// SetURLTTL задаёт срок действия подписанных ссылок на медиа
+func (s *QuestionMediaService) SetURLTTL(ttl time.Duration) {
+	if ttl > 0 {
+		s.urlTTL = ttl
+	}
+}
+
+// UploadMedia сохраняет картинку или аудио вопроса, заменяя прежнее медиа того же вида
+func (s *QuestionMediaService) UploadMedia(questionID uint, kind string, file *multipart.FileHeader) (*entity.Question, error) {
+	format, err := s.validate(kind, file)
+	if err != nil {
+		return nil, err
+	}
+
+	question, err := s.questionRepo.GetByID(questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open uploaded file: %w", err)
+	}
+	defer src.Close()
+	if err := checkMediaContent(src, format); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), questionMediaUploadTimeout)
+	defer cancel()
+
+	ext := strings.ToLower(filepath.Ext(file.Filename))
+	key := fmt.Sprintf("%sq%d_%s_%d%s", questionMediaPrefix, question.ID, kind, time.Now().UnixNano(), ext)
+	if err := s.storage.Store(ctx, key, src, file.Size, format.mimeType); err != nil {
+		return nil, fmt.Errorf("failed to store media: %w", err)
+	}
+
+	previous := setMediaKey(question, kind, key)
+	if err := s.questionRepo.Update(question); err != nil {
+		s.deleteObject(ctx, key)
+		return nil, fmt.Errorf("failed to save question media: %w", err)
+	}
+	s.release(ctx, previous)
+
+	log.Printf("[QuestionMediaService] Вопрос #%d: загружено медиа %s (%s, %d байт)", question.ID, kind, key, file.Size)
+	return question, nil
+}
+
+// DeleteMedia открепляет медиа вида kind от вопроса
+func (s *QuestionMediaService) DeleteMedia(questionID uint, kind string) (*entity.Question, error) {
+	if kind != QuestionMediaImage && kind != QuestionMediaAudio {
+		return nil, fmt.Errorf("%w: media kind must be image or audio", apperrors.ErrValidation)
+	}
+	question, err := s.questionRepo.GetByID(questionID)
+	if err != nil {
+		return nil, err
+	}
+	previous := setMediaKey(question, kind, "")
+	if previous == "" {
+		return question, nil
+	}
+	if err := s.questionRepo.Update(question); err != nil {
+		return nil, fmt.Errorf("failed to save question media: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), questionMediaUploadTimeout)
+	defer cancel()
+	s.release(ctx, previous)
+	return question, nil
+}
+
+// QuestionMedia возвращает подписанные ссылки на медиа вопроса; nil — медиа нет.
+// Реализует quizmanager.QuestionMediaSigner.
+func (s *QuestionMediaService) QuestionMedia(ctx context.Context, question *entity.Question) (*quizmanager.QuestionMedia, error) {
+	if question.ImageKey == "" && question.AudioKey == "" {
+		return nil, nil
+	}
+	media := &quizmanager.QuestionMedia{ExpiresAt: time.Now().Add(s.urlTTL).UnixMilli()}
+	var err error
+	if question.ImageKey != "" {
+		if media.ImageURL, err = s.storage.SignedURL(ctx, question.ImageKey, s.urlTTL); err != nil {
+			return nil, fmt.Errorf("failed to sign image url: %w", err)
+		}
+	}
+	if question.AudioKey != "" {
+		if media.AudioURL, err = s.storage.SignedURL(ctx, question.AudioKey, s.urlTTL); err != nil {
+			return nil, fmt.Errorf("failed to sign audio url: %w", err)
+		}
+	}
+	return media, nil
+}
+
+// validate проверяет вид, расширение и размер загружаемого файла
+func (s *QuestionMediaService) validate(kind string, file *multipart.FileHeader) (questionMediaFormat, error) {
+	ext := strings.ToLower(filepath.Ext(file.Filename))
+	format, ok := questionMediaFormats[ext]
+	if !ok {
+		return format, fmt.Errorf("%w: unsupported media format %q", apperrors.ErrValidation, ext)
+	}
+	if format.kind != kind {
+		return format, fmt.Errorf("%w: %s file is not %s", apperrors.ErrValidation, ext, kind)
+	}
+
+	maxSize := s.limits.MaxImageSizeBytes
+	if kind == QuestionMediaAudio {
+		maxSize = s.limits.MaxAudioSizeBytes
+	}
+	if file.Size > maxSize {
+		return format, fmt.Errorf("%w: file is too large (max %d MB)", apperrors.ErrValidation, maxSize/(1024*1024))
+	}
+	return format, nil
+}
+
+// release удаляет объект из хранилища, если на него больше не ссылается ни один вопрос
+func (s *QuestionMediaService) release(ctx context.Context, key string) {
+	if key == "" {
+		return
+	}
+	refs, err := s.questionRepo.CountMediaReferences(key)
+	if err != nil {
+		log.Printf("[QuestionMediaService] WARNING: не удалось проверить ссылки на %s: %v", key, err)
+		return
+	}
+	if refs == 0 {
+		s.deleteObject(ctx, key)
+	}
+}
+
+func (s *QuestionMediaService) deleteObject(ctx context.Context, key string) {
+	if err := s.storage.Delete(ctx, key); err != nil {
+		log.Printf("[QuestionMediaService] WARNING: не удалось удалить файл %s: %v", key, err)
+	}
+}
+
+// setMediaKey записывает ключ медиа вида kind и возвращает прежний
+func setMediaKey(question *entity.Question, kind, key string) string {
+	var previous string
+	if kind == QuestionMediaImage {
+		previous, question.ImageKey = question.ImageKey, key
+	} else {
+		previous, question.AudioKey = question.AudioKey, key
+	}
+	return previous
+}
+
+// checkMediaContent проверяет, что содержимое файла соответствует расширению, и перематывает его в начало
+func checkMediaContent(src multipart.File, format questionMediaFormat) error {
+	header := make([]byte, 512)
+	n, err := io.ReadFull(src, header)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return fmt.Errorf("failed to read uploaded file: %w", err)
+	}
+	header = header[:n]
+	detected := http.DetectContentType(header)
+	// MP3 без тега ID3 начинается сразу с синхрослова MPEG-кадра
+	mpegFrame := format.mimeType == "audio/mpeg" && len(header) >= 2 && header[0] == 0xFF && header[1]&0xE0 == 0xE0
+	if detected != format.sniffed && !mpegFrame {
+		return fmt.Errorf("%w: file content (%s) does not match %s", apperrors.ErrValidation, detected, format.mimeType)
+	}
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to read uploaded file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/pkg/storage"
+)
+
+// mediaQuestionRepo хранит вопросы в памяти
+type mediaQuestionRepo struct {
+	repository.QuestionRepository
+	questions map[uint]*entity.Question
+}
+
+func (r *mediaQuestionRepo) GetByID(id uint) (*entity.Question, error) {
+	question, ok := r.questions[id]
+	if !ok {
+		return nil, apperrors.ErrNotFound
+	}
+	copied := *question
+	return &copied, nil
+}
+
+func (r *mediaQuestionRepo) Update(question *entity.Question) error {
+	copied := *question
+	r.questions[question.ID] = &copied
+	return nil
+}
+
+func (r *mediaQuestionRepo) CountMediaReferences(key string) (int64, error) {
+	var count int64
+	for _, question := range r.questions {
+		if question.ImageKey == key || question.AudioKey == key {
+			count++
+		}
+	}
+	return count, nil
+}
+
+var testPNG = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x00\x01\x00\x00\x00\x01\x08\x06\x00\x00\x00")
+
+// multipartFile собирает *multipart.FileHeader из содержимого файла
+func multipartFile(t *testing.T, filename string, content []byte) *multipart.FileHeader {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", filename)
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest("POST", "/", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	require.NoError(t, req.ParseMultipartForm(1<<20))
+	return req.MultipartForm.File["file"][0]
+}
+
+func newTestQuestionMediaService(t *testing.T, questions ...entity.Question) (*QuestionMediaService, *mediaQuestionRepo, string) {
+	t.Helper()
+	dir := t.TempDir()
+	store, err := storage.NewLocalStorage(dir, "/uploads")
+	require.NoError(t, err)
+	repo := &mediaQuestionRepo{questions: make(map[uint]*entity.Question)}
+	for i := range questions {
+		repo.questions[questions[i].ID] = &questions[i]
+	}
+	return NewQuestionMediaService(repo, store), repo, dir
+}
+
+func TestQuestionMediaService_UploadReplacesImage(t *testing.T) {
+	svc, repo, dir := newTestQuestionMediaService(t, entity.Question{ID: 1})
+
+	first, err := svc.UploadMedia(1, QuestionMediaImage, multipartFile(t, "cat.png", testPNG))
+	require.NoError(t, err)
+	require.NotEmpty(t, first.ImageKey)
+	assert.FileExists(t, filepath.Join(dir, first.ImageKey))
+
+	second, err := svc.UploadMedia(1, QuestionMediaImage, multipartFile(t, "dog.png", testPNG))
+	require.NoError(t, err)
+	assert.NotEqual(t, first.ImageKey, second.ImageKey)
+	assert.Equal(t, second.ImageKey, repo.questions[1].ImageKey)
+	assert.FileExists(t, filepath.Join(dir, second.ImageKey))
+	// Прежняя картинка больше ни на что не ссылается и удалена
+	_, err = os.Stat(filepath.Join(dir, first.ImageKey))
+	assert.True(t, os.IsNotExist(err))
+
+	media, err := svc.QuestionMedia(context.Background(), repo.questions[1])
+	require.NoError(t, err)
+	require.NotNil(t, media)
+	assert.Equal(t, "/uploads/"+second.ImageKey, media.ImageURL)
+	assert.Empty(t, media.AudioURL)
+	assert.Positive(t, media.ExpiresAt)
+}
+
+func TestQuestionMediaService_DeleteKeepsSharedObject(t *testing.T) {
+	svc, repo, dir := newTestQuestionMediaService(t, entity.Question{ID: 1}, entity.Question{ID: 2})
+
+	uploaded, err := svc.UploadMedia(1, QuestionMediaImage, multipartFile(t, "cat.png", testPNG))
+	require.NoError(t, err)
+	// Копия викторины разделяет картинку оригинала
+	repo.questions[2].ImageKey = uploaded.ImageKey
+
+	_, err = svc.DeleteMedia(1, QuestionMediaImage)
+	require.NoError(t, err)
+	assert.Empty(t, repo.questions[1].ImageKey)
+	assert.FileExists(t, filepath.Join(dir, uploaded.ImageKey))
+
+	_, err = svc.DeleteMedia(2, QuestionMediaImage)
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dir, uploaded.ImageKey))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestQuestionMediaService_Validation(t *testing.T) {
+	svc, _, _ := newTestQuestionMediaService(t, entity.Question{ID: 1})
+
+	// Содержимое не соответствует расширению
+	_, err := svc.UploadMedia(1, QuestionMediaImage, multipartFile(t, "fake.png", []byte("not an image at all")))
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+
+	// Аудио под видом картинки
+	_, err = svc.UploadMedia(1, QuestionMediaImage, multipartFile(t, "clip.mp3", []byte("ID3\x03\x00")))
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+
+	// Превышен размер
+	svc.limits.MaxImageSizeBytes = 8
+	_, err = svc.UploadMedia(1, QuestionMediaImage, multipartFile(t, "cat.png", testPNG))
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+
+	// MP3 без тега ID3 распознаётся по синхрослову кадра
+	question, err := svc.UploadMedia(1, QuestionMediaAudio, multipartFile(t, "clip.mp3", []byte{0xFF, 0xFB, 0x90, 0x64, 0x00}))
+	require.NoError(t, err)
+	assert.NotEmpty(t, question.AudioKey)
+
+	_, err = svc.UploadMedia(42, QuestionMediaAudio, multipartFile(t, "clip.mp3", []byte("ID3\x03\x00")))
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
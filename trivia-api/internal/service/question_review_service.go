@@ -0,0 +1,269 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// maxReviewCommentLength — предел длины комментария рецензента (question_review_comments.body)
+const maxReviewCommentLength = 1000
+
+// QuestionReview — вопрос с историей его проверки
+type QuestionReview struct {
+	Question *entity.Question               `json:"question"`
+	Comments []entity.QuestionReviewComment `json:"comments"`
+}
+
+// QuestionReviewService ведёт проверку вопросов перед эфиром:
+// draft → pending_review → approved/rejected. Адаптивный выбор берёт только одобренные вопросы.
+type QuestionReviewService struct {
+	questionRepo repository.QuestionRepository
+	reviewRepo   repository.QuestionReviewRepository
+	userRepo     repository.UserRepository
+	quizCache    QuizCacheInvalidator
+}
+
+// NewQuestionReviewService создает сервис проверки вопросов
+func NewQuestionReviewService(
+	questionRepo repository.QuestionRepository,
+	reviewRepo repository.QuestionReviewRepository,
+	userRepo repository.UserRepository,
+) *QuestionReviewService {
+	return &QuestionReviewService{
+		questionRepo: questionRepo,
+		reviewRepo:   reviewRepo,
+		userRepo:     userRepo,
+	}
+}
+
+// SetQuizCache подключает сброс кеша викторины при смене статуса её вопросов
+func (s *QuestionReviewService) SetQuizCache(invalidator QuizCacheInvalidator) {
+	s.quizCache = invalidator
+}
+
+// GetReview возвращает вопрос и историю его проверки
+func (s *QuestionReviewService) GetReview(questionID uint) (*QuestionReview, error) {
+	question, err := s.questionRepo.GetByID(questionID)
+	if err != nil {
+		return nil, err
+	}
+	comments, err := s.reviewRepo.ListComments(questionID)
+	if err != nil {
+		return nil, err
+	}
+	return &QuestionReview{Question: question, Comments: comments}, nil
+}
+
+// ListQueue возвращает вопросы по статусу проверки, рецензенту, викторине, запуску импорта
+// и генерации моделью (0 — все)
+func (s *QuestionReviewService) ListQueue(status string, reviewerID, quizID, importID, generationID uint, page, pageSize int) ([]entity.Question, int64, error) {
+	if status != "" && !isQuestionReviewStatus(status) {
+		return nil, 0, fmt.Errorf("%w: unknown review status %q", apperrors.ErrValidation, status)
+	}
+	filters := repository.QuestionReviewFilters{Status: status}
+	if reviewerID != 0 {
+		filters.ReviewerID = &reviewerID
+	}
+	if quizID != 0 {
+		filters.QuizID = &quizID
+	}
+	if importID != 0 {
+		filters.ImportID = &importID
+	}
+	if generationID != 0 {
+		filters.GenerationID = &generationID
+	}
+	page, pageSize = normalizeReviewPage(page, pageSize)
+	return s.reviewRepo.ListQueue(filters, pageSize, (page-1)*pageSize)
+}
+
+// Submit отправляет черновик или отклонённый вопрос на проверку
+func (s *QuestionReviewService) Submit(actorID, questionID uint, comment string) (*entity.Question, error) {
+	comment, err := normalizeReviewComment(comment, false)
+	if err != nil {
+		return nil, err
+	}
+	question, err := s.questionRepo.GetByID(questionID)
+	if err != nil {
+		return nil, err
+	}
+	fromStatus := question.ReviewStatus
+	if fromStatus != entity.QuestionReviewDraft && fromStatus != entity.QuestionReviewRejected {
+		return nil, fmt.Errorf("%w: only draft or rejected questions can be submitted for review", apperrors.ErrConflict)
+	}
+
+	question.ReviewStatus = entity.QuestionReviewPendingReview
+	question.ReviewedAt = nil
+	if err := s.transition(question, fromStatus, actorID, entity.QuestionReviewActionSubmit, comment); err != nil {
+		return nil, err
+	}
+	log.Printf("[QuestionReviewService] Администратор ID=%d отправил вопрос #%d на проверку", actorID, questionID)
+	return question, nil
+}
+
+// AssignReviewer назначает рецензента вопроса; одобрить или отклонить вопрос сможет только он
+func (s *QuestionReviewService) AssignReviewer(actorID, questionID, reviewerID uint) (*entity.Question, error) {
+	reviewer, err := s.userRepo.GetByID(reviewerID)
+	if err != nil {
+		return nil, err
+	}
+	if reviewer.Role != "admin" {
+		return nil, fmt.Errorf("%w: reviewer must be an administrator", apperrors.ErrValidation)
+	}
+	question, err := s.questionRepo.GetByID(questionID)
+	if err != nil {
+		return nil, err
+	}
+	if question.ReviewStatus == entity.QuestionReviewApproved {
+		return nil, fmt.Errorf("%w: question is already approved", apperrors.ErrConflict)
+	}
+
+	question.ReviewerID = &reviewerID
+	if err := s.transition(question, question.ReviewStatus, actorID, entity.QuestionReviewActionAssign, ""); err != nil {
+		return nil, err
+	}
+	log.Printf("[QuestionReviewService] Вопрос #%d: назначен рецензент ID=%d", questionID, reviewerID)
+	return question, nil
+}
+
+// Approve одобряет вопрос, ожидающий проверки; после этого он доступен адаптивному выбору
+func (s *QuestionReviewService) Approve(actorID, questionID uint, comment string) (*entity.Question, error) {
+	comment, err := normalizeReviewComment(comment, false)
+	if err != nil {
+		return nil, err
+	}
+	question, err := s.reviewable(actorID, questionID)
+	if err != nil {
+		return nil, err
+	}
+	if question.ReviewStatus != entity.QuestionReviewPendingReview {
+		return nil, fmt.Errorf("%w: only questions pending review can be approved", apperrors.ErrConflict)
+	}
+
+	now := time.Now()
+	question.ReviewStatus = entity.QuestionReviewApproved
+	question.ReviewerID = &actorID
+	question.ReviewedAt = &now
+	if err := s.transition(question, entity.QuestionReviewPendingReview, actorID, entity.QuestionReviewActionApprove, comment); err != nil {
+		return nil, err
+	}
+	log.Printf("[QuestionReviewService] Администратор ID=%d одобрил вопрос #%d", actorID, questionID)
+	return question, nil
+}
+
+// Reject отклоняет вопрос, ожидающий проверки, или снимает одобренный вопрос с эфира.
+// Комментарий с причиной обязателен.
+func (s *QuestionReviewService) Reject(actorID, questionID uint, comment string) (*entity.Question, error) {
+	comment, err := normalizeReviewComment(comment, true)
+	if err != nil {
+		return nil, err
+	}
+	question, err := s.reviewable(actorID, questionID)
+	if err != nil {
+		return nil, err
+	}
+	fromStatus := question.ReviewStatus
+	if fromStatus != entity.QuestionReviewPendingReview && fromStatus != entity.QuestionReviewApproved {
+		return nil, fmt.Errorf("%w: only pending or approved questions can be rejected", apperrors.ErrConflict)
+	}
+
+	now := time.Now()
+	question.ReviewStatus = entity.QuestionReviewRejected
+	question.ReviewerID = &actorID
+	question.ReviewedAt = &now
+	if err := s.transition(question, fromStatus, actorID, entity.QuestionReviewActionReject, comment); err != nil {
+		return nil, err
+	}
+	log.Printf("[QuestionReviewService] Администратор ID=%d отклонил вопрос #%d: %s", actorID, questionID, comment)
+	return question, nil
+}
+
+// AddComment добавляет комментарий к вопросу без смены статуса
+func (s *QuestionReviewService) AddComment(actorID, questionID uint, body string) (*entity.QuestionReviewComment, error) {
+	body, err := normalizeReviewComment(body, true)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.questionRepo.GetByID(questionID); err != nil {
+		return nil, err
+	}
+	comment := &entity.QuestionReviewComment{
+		QuestionID: questionID,
+		AuthorID:   actorID,
+		Action:     entity.QuestionReviewActionComment,
+		Body:       body,
+	}
+	if err := s.reviewRepo.AddComment(comment); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// reviewable загружает вопрос и проверяет, что решение принимает назначенный рецензент
+func (s *QuestionReviewService) reviewable(actorID, questionID uint) (*entity.Question, error) {
+	question, err := s.questionRepo.GetByID(questionID)
+	if err != nil {
+		return nil, err
+	}
+	if question.ReviewerID != nil && *question.ReviewerID != actorID && question.ReviewStatus == entity.QuestionReviewPendingReview {
+		return nil, fmt.Errorf("%w: question is assigned to another reviewer", apperrors.ErrForbidden)
+	}
+	return question, nil
+}
+
+// transition сохраняет новый статус вопроса с записью в истории и сбрасывает кеш викторины
+func (s *QuestionReviewService) transition(question *entity.Question, fromStatus string, actorID uint, action, comment string) error {
+	entry := &entity.QuestionReviewComment{
+		QuestionID: question.ID,
+		AuthorID:   actorID,
+		Action:     action,
+		FromStatus: fromStatus,
+		ToStatus:   question.ReviewStatus,
+		Body:       comment,
+	}
+	if err := s.reviewRepo.TransitionReview(question, fromStatus, entry); err != nil {
+		return err
+	}
+	if s.quizCache != nil && question.QuizID != nil && fromStatus != question.ReviewStatus {
+		s.quizCache.InvalidateQuiz(*question.QuizID)
+	}
+	return nil
+}
+
+// normalizeReviewComment обрезает пробелы и проверяет длину комментария
+func normalizeReviewComment(comment string, required bool) (string, error) {
+	comment = strings.TrimSpace(comment)
+	if required && comment == "" {
+		return "", fmt.Errorf("%w: comment is required", apperrors.ErrValidation)
+	}
+	if utf8.RuneCountInString(comment) > maxReviewCommentLength {
+		return "", fmt.Errorf("%w: comment must be at most %d characters", apperrors.ErrValidation, maxReviewCommentLength)
+	}
+	return comment, nil
+}
+
+func normalizeReviewPage(page, pageSize int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return page, pageSize
+}
+
+func isQuestionReviewStatus(status string) bool {
+	switch status {
+	case entity.QuestionReviewDraft, entity.QuestionReviewPendingReview,
+		entity.QuestionReviewApproved, entity.QuestionReviewRejected:
+		return true
+	}
+	return false
+}
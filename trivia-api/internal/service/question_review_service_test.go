@@ -0,0 +1,139 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// memoryReviewRepo хранит статусы проверки в том же хранилище, что и mediaQuestionRepo
+type memoryReviewRepo struct {
+	questions *mediaQuestionRepo
+	comments  []entity.QuestionReviewComment
+}
+
+func (r *memoryReviewRepo) TransitionReview(question *entity.Question, fromStatus string, entry *entity.QuestionReviewComment) error {
+	stored := r.questions.questions[question.ID]
+	if stored.ReviewStatus != fromStatus {
+		return fmt.Errorf("%w: question is no longer %s", apperrors.ErrConflict, fromStatus)
+	}
+	stored.ReviewStatus, stored.ReviewerID, stored.ReviewedAt = question.ReviewStatus, question.ReviewerID, question.ReviewedAt
+	if entry != nil {
+		r.comments = append(r.comments, *entry)
+	}
+	return nil
+}
+
+func (r *memoryReviewRepo) AddComment(comment *entity.QuestionReviewComment) error {
+	r.comments = append(r.comments, *comment)
+	return nil
+}
+
+func (r *memoryReviewRepo) ListComments(questionID uint) ([]entity.QuestionReviewComment, error) {
+	var comments []entity.QuestionReviewComment
+	for _, comment := range r.comments {
+		if comment.QuestionID == questionID {
+			comments = append(comments, comment)
+		}
+	}
+	return comments, nil
+}
+
+func (r *memoryReviewRepo) ListQueue(filters repository.QuestionReviewFilters, limit, offset int) ([]entity.Question, int64, error) {
+	return nil, 0, nil
+}
+
+// invalidationRecorder запоминает сброшенные викторины
+type invalidationRecorder struct {
+	quizIDs []uint
+}
+
+func (r *invalidationRecorder) InvalidateQuiz(quizID uint) {
+	r.quizIDs = append(r.quizIDs, quizID)
+}
+
+func newTestQuestionReviewService(questions ...entity.Question) (*QuestionReviewService, *mediaQuestionRepo, *memoryReviewRepo, *MockUserRepository) {
+	questionRepo := &mediaQuestionRepo{questions: make(map[uint]*entity.Question)}
+	for i := range questions {
+		questionRepo.questions[questions[i].ID] = &questions[i]
+	}
+	reviewRepo := &memoryReviewRepo{questions: questionRepo}
+	userRepo := new(MockUserRepository)
+	return NewQuestionReviewService(questionRepo, reviewRepo, userRepo), questionRepo, reviewRepo, userRepo
+}
+
+func TestQuestionReviewService_Workflow(t *testing.T) {
+	quizID := uint(7)
+	svc, questions, reviews, userRepo := newTestQuestionReviewService(entity.Question{ID: 1, QuizID: &quizID, ReviewStatus: entity.QuestionReviewDraft})
+	cache := &invalidationRecorder{}
+	svc.SetQuizCache(cache)
+	userRepo.On("GetByID", uint(20)).Return(&entity.User{ID: 20, Role: "admin"}, nil)
+
+	// Черновик нельзя одобрить в обход проверки
+	_, err := svc.Approve(20, 1, "")
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+
+	_, err = svc.Submit(10, 1, "готов к проверке")
+	require.NoError(t, err)
+	question, err := svc.AssignReviewer(10, 1, 20)
+	require.NoError(t, err)
+	assert.Equal(t, entity.QuestionReviewPendingReview, question.ReviewStatus)
+
+	// Решение принимает только назначенный рецензент
+	_, err = svc.Approve(10, 1, "")
+	assert.ErrorIs(t, err, apperrors.ErrForbidden)
+
+	// Отклонение требует причины
+	_, err = svc.Reject(20, 1, "  ")
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	_, err = svc.Reject(20, 1, "неверный вариант ответа")
+	require.NoError(t, err)
+	assert.Equal(t, entity.QuestionReviewRejected, questions.questions[1].ReviewStatus)
+
+	_, err = svc.Submit(10, 1, "исправлено")
+	require.NoError(t, err)
+	question, err = svc.Approve(20, 1, "")
+	require.NoError(t, err)
+	assert.True(t, question.IsApproved())
+	assert.True(t, questions.questions[1].IsApproved())
+	require.NotNil(t, questions.questions[1].ReviewedAt)
+	assert.Equal(t, uint(20), *questions.questions[1].ReviewerID)
+
+	_, err = svc.AddComment(20, 1, "спасибо")
+	require.NoError(t, err)
+
+	review, err := svc.GetReview(1)
+	require.NoError(t, err)
+	actions := make([]string, len(review.Comments))
+	for i, comment := range review.Comments {
+		actions[i] = comment.Action
+	}
+	assert.Equal(t, []string{
+		entity.QuestionReviewActionSubmit, entity.QuestionReviewActionAssign, entity.QuestionReviewActionReject,
+		entity.QuestionReviewActionSubmit, entity.QuestionReviewActionApprove, entity.QuestionReviewActionComment,
+	}, actions)
+	assert.Equal(t, entity.QuestionReviewPendingReview, reviews.comments[4].FromStatus)
+	assert.Equal(t, entity.QuestionReviewApproved, reviews.comments[4].ToStatus)
+	// Назначение рецензента статус не меняет и кеш викторины не сбрасывает
+	assert.Equal(t, []uint{7, 7, 7, 7}, cache.quizIDs)
+}
+
+func TestQuestionReviewService_AssignRequiresAdmin(t *testing.T) {
+	svc, _, _, userRepo := newTestQuestionReviewService(entity.Question{ID: 1, ReviewStatus: entity.QuestionReviewApproved})
+	userRepo.On("GetByID", uint(5)).Return(&entity.User{ID: 5, Role: "user"}, nil)
+	userRepo.On("GetByID", uint(6)).Return(&entity.User{ID: 6, Role: "admin"}, nil)
+
+	_, err := svc.AssignReviewer(1, 1, 5)
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	_, err = svc.AssignReviewer(1, 1, 6)
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+
+	_, _, err = svc.ListQueue("published", 0, 0, 0, 0, 1, 20)
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+const (
+	// openTDBMaxAmount — больше вопросов за запрос Open Trivia DB не отдаёт
+	openTDBMaxAmount = 50
+	openTDBLicense   = "CC BY-SA 4.0"
+	openTDBCredit    = "Open Trivia Database (https://opentdb.com)"
+)
+
+// openTDBResponseErrors — коды ответа Open Trivia DB, кроме 0 (успех)
+var openTDBResponseErrors = map[int]string{
+	1: "not enough questions for the query",
+	2: "invalid parameter",
+	3: "session token not found",
+	4: "session token exhausted",
+	5: "rate limit exceeded, retry in 5 seconds",
+}
+
+// OpenTDBSource загружает вопросы из Open Trivia DB (https://opentdb.com/api_config.php).
+// Фильтр категории — числовой идентификатор категории источника, сложность — easy, medium или hard.
+type OpenTDBSource struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewOpenTDBSource создает источник Open Trivia DB; пустой baseURL — https://opentdb.com
+func NewOpenTDBSource(baseURL string, timeout time.Duration) *OpenTDBSource {
+	if baseURL == "" {
+		baseURL = "https://opentdb.com"
+	}
+	return &OpenTDBSource{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *OpenTDBSource) Name() string { return entity.QuestionSourceOpenTDB }
+
+func (s *OpenTDBSource) License() string { return openTDBLicense }
+
+func (s *OpenTDBSource) Attribution() string { return openTDBCredit }
+
+func (s *OpenTDBSource) MaxAmount() int { return openTDBMaxAmount }
+
+func (s *OpenTDBSource) Fetch(ctx context.Context, query QuestionImportQuery) ([]ExternalQuestion, error) {
+	params := url.Values{}
+	params.Set("amount", fmt.Sprint(query.Amount))
+	params.Set("encode", "url3986") // без HTML-сущностей в тексте
+	if query.ExternalCategory != "" {
+		params.Set("category", query.ExternalCategory)
+	}
+	if query.Difficulty != "" {
+		params.Set("difficulty", query.Difficulty)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL+"/api.php?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("opentdb request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("opentdb returned HTTP %d", resp.StatusCode)
+	}
+
+	var body struct {
+		ResponseCode int `json:"response_code"`
+		Results      []struct {
+			Difficulty       string   `json:"difficulty"`
+			Category         string   `json:"category"`
+			Question         string   `json:"question"`
+			CorrectAnswer    string   `json:"correct_answer"`
+			IncorrectAnswers []string `json:"incorrect_answers"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode opentdb response: %w", err)
+	}
+	if body.ResponseCode != 0 {
+		reason, ok := openTDBResponseErrors[body.ResponseCode]
+		if !ok {
+			reason = "unknown error"
+		}
+		return nil, fmt.Errorf("opentdb response code %d: %s", body.ResponseCode, reason)
+	}
+
+	questions := make([]ExternalQuestion, 0, len(body.Results))
+	for _, result := range body.Results {
+		options := make([]string, 0, len(result.IncorrectAnswers)+1)
+		for _, option := range result.IncorrectAnswers {
+			options = append(options, openTDBDecode(option))
+		}
+		// Верный ответ источник отдаёт отдельно — ставим его на случайное место
+		correct := rand.Intn(len(options) + 1)
+		options = append(options, "")
+		copy(options[correct+1:], options[correct:])
+		options[correct] = openTDBDecode(result.CorrectAnswer)
+
+		questions = append(questions, ExternalQuestion{
+			Category:      openTDBDecode(result.Category),
+			Difficulty:    openTDBDecode(result.Difficulty),
+			Text:          openTDBDecode(result.Question),
+			Options:       options,
+			CorrectOption: correct,
+		})
+	}
+	return questions, nil
+}
+
+// openTDBDecode раскодирует строку в кодировке url3986; нераскодируемая возвращается как есть
+func openTDBDecode(value string) string {
+	decoded, err := url.PathUnescape(value)
+	if err != nil {
+		return value
+	}
+	return decoded
+}
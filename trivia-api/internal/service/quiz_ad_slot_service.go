@@ -33,6 +33,24 @@ type CreateSlotRequest struct {
 	QuestionAfter int  `json:"question_after" binding:"required,min=1"`
 	AdAssetID     uint `json:"ad_asset_id" binding:"required"`
 	IsActive      bool `json:"is_active"`
+	DurationSec   *int `json:"duration_sec" binding:"omitempty,min=3,max=60"` // переопределение длительности показа
+}
+
+// UpdateSlotRequest DTO для обновления рекламного слота
+type UpdateSlotRequest struct {
+	IsActive    bool `json:"is_active"`
+	DurationSec *int `json:"duration_sec" binding:"omitempty,min=3,max=60"` // nil — длительность ресурса
+}
+
+// validateSlotDuration проверяет, что переопределённая длительность не обрезает видео
+func validateSlotDuration(asset *entity.AdAsset, durationSec *int) error {
+	if durationSec == nil || asset == nil || !asset.IsVideo() || asset.VideoDurationMs == 0 {
+		return nil
+	}
+	if *durationSec < asset.VideoDurationSec() {
+		return fmt.Errorf("длительность слота (%d сек) меньше длительности видео (%d сек)", *durationSec, asset.VideoDurationSec())
+	}
+	return nil
 }
 
 // CreateSlot создаёт рекламный слот для викторины
@@ -53,12 +71,16 @@ func (s *QuizAdSlotService) CreateSlot(quizID uint, req CreateSlotRequest) (*ent
 	if err != nil {
 		return nil, fmt.Errorf("рекламный ресурс не найден: %w", err)
 	}
+	if err := validateSlotDuration(asset, req.DurationSec); err != nil {
+		return nil, err
+	}
 
 	slot := &entity.QuizAdSlot{
 		QuizID:        quizID,
 		QuestionAfter: req.QuestionAfter,
 		AdAssetID:     req.AdAssetID,
 		IsActive:      req.IsActive,
+		DurationSec:   req.DurationSec,
 	}
 
 	if err := s.slotRepo.Create(slot); err != nil {
@@ -83,18 +105,22 @@ func (s *QuizAdSlotService) GetSlotForQuestion(quizID uint, questionNumber int)
 }
 
 // UpdateSlot обновляет рекламный слот
-func (s *QuizAdSlotService) UpdateSlot(slotID uint, isActive bool) (*entity.QuizAdSlot, error) {
+func (s *QuizAdSlotService) UpdateSlot(slotID uint, req UpdateSlotRequest) (*entity.QuizAdSlot, error) {
 	slot, err := s.slotRepo.GetByID(slotID)
 	if err != nil {
 		return nil, fmt.Errorf("слот не найден: %w", err)
 	}
+	if err := validateSlotDuration(slot.AdAsset, req.DurationSec); err != nil {
+		return nil, err
+	}
 
-	slot.IsActive = isActive
+	slot.IsActive = req.IsActive
+	slot.DurationSec = req.DurationSec
 	if err := s.slotRepo.Update(slot); err != nil {
 		return nil, fmt.Errorf("не удалось обновить слот: %w", err)
 	}
 
-	log.Printf("[QuizAdSlotService] Обновлён слот #%d: is_active=%t", slotID, isActive)
+	log.Printf("[QuizAdSlotService] Обновлён слот #%d: is_active=%t", slotID, req.IsActive)
 	return slot, nil
 }
 
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+)
+
+// Кеш горячих чтений викторин (read-through): GetQuizWithQuestions и GetActiveQuiz
+// на старте викторины запрашиваются всеми клиентами одновременно.
+const (
+	quizWithQuestionsCacheKey = "quiz:with_questions:%d"
+	activeQuizCacheKey        = "quiz:active"
+
+	quizWithQuestionsCacheTTL = 5 * time.Minute
+	// Активная викторина меняется при старте и завершении; короткий TTL страхует
+	// от переходов статуса, которые прошли мимо инвалидации
+	activeQuizCacheTTL = 30 * time.Second
+)
+
+// QuizCacheInvalidator сбрасывает закешированные данные викторины после её изменения
+type QuizCacheInvalidator interface {
+	InvalidateQuiz(quizID uint)
+}
+
+// cachedQuizWithQuestions — викторина с вопросами в кеше. Поля вопросов с json:"-"
+// (правильный ответ, признак использования) хранятся отдельно, по индексу вопроса.
+type cachedQuizWithQuestions struct {
+	Quiz    *entity.Quiz           `json:"quiz"`
+	Secrets []cachedQuestionSecret `json:"secrets"`
+}
+
+type cachedQuestionSecret struct {
+	CorrectOption int  `json:"correct_option"`
+	IsUsed        bool `json:"is_used"`
+}
+
+func newCachedQuizWithQuestions(quiz *entity.Quiz) *cachedQuizWithQuestions {
+	secrets := make([]cachedQuestionSecret, len(quiz.Questions))
+	for i, question := range quiz.Questions {
+		secrets[i] = cachedQuestionSecret{CorrectOption: question.CorrectOption, IsUsed: question.IsUsed}
+	}
+	return &cachedQuizWithQuestions{Quiz: quiz, Secrets: secrets}
+}
+
+func (c *cachedQuizWithQuestions) restore() (*entity.Quiz, bool) {
+	if c.Quiz == nil || len(c.Secrets) != len(c.Quiz.Questions) {
+		return nil, false
+	}
+	for i := range c.Quiz.Questions {
+		c.Quiz.Questions[i].CorrectOption = c.Secrets[i].CorrectOption
+		c.Quiz.Questions[i].IsUsed = c.Secrets[i].IsUsed
+	}
+	return c.Quiz, true
+}
+
+// cachedQuizWithQuestions возвращает викторину с вопросами из кеша или из БД.
+// Одновременные промахи по одной викторине выполняют один запрос к БД (single-flight).
+// Возвращаемая викторина общая для конкурентных вызовов и не должна изменяться.
+func (s *QuizService) cachedQuizWithQuestions(quizID uint) (*entity.Quiz, error) {
+	if s.cacheRepo == nil {
+		return s.quizRepo.GetWithQuestions(quizID)
+	}
+	key := fmt.Sprintf(quizWithQuestionsCacheKey, quizID)
+
+	var cached cachedQuizWithQuestions
+	if err := s.cacheRepo.GetJSON(key, &cached); err == nil {
+		if quiz, ok := cached.restore(); ok {
+			return quiz, nil
+		}
+	}
+
+	value, err, _ := s.loads.Do(key, func() (interface{}, error) {
+		quiz, err := s.quizRepo.GetWithQuestions(quizID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.cacheRepo.SetJSON(key, newCachedQuizWithQuestions(quiz), quizWithQuestionsCacheTTL); err != nil {
+			log.Printf("[QuizService] Ошибка кеширования викторины #%d: %v", quizID, err)
+		}
+		return quiz, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*entity.Quiz), nil
+}
+
+// cachedActiveQuiz возвращает активную викторину из кеша или из БД (single-flight).
+// Отсутствие активной викторины не кешируется.
+func (s *QuizService) cachedActiveQuiz() (*entity.Quiz, error) {
+	if s.cacheRepo == nil {
+		return s.quizRepo.GetActive()
+	}
+
+	var cached entity.Quiz
+	if err := s.cacheRepo.GetJSON(activeQuizCacheKey, &cached); err == nil && cached.ID != 0 {
+		return &cached, nil
+	}
+
+	value, err, _ := s.loads.Do(activeQuizCacheKey, func() (interface{}, error) {
+		quiz, err := s.quizRepo.GetActive()
+		if err != nil {
+			return nil, err
+		}
+		if err := s.cacheRepo.SetJSON(activeQuizCacheKey, quiz, activeQuizCacheTTL); err != nil {
+			log.Printf("[QuizService] Ошибка кеширования активной викторины: %v", err)
+		}
+		return quiz, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*entity.Quiz), nil
+}
+
+// InvalidateQuiz сбрасывает закешированные викторину с вопросами и активную викторину
+func (s *QuizService) InvalidateQuiz(quizID uint) {
+	invalidateQuizCache(s.cacheRepo, quizID)
+}
+
+// invalidateQuizCache удаляет кеш викторины quizID и активной викторины.
+// Активная сбрасывается всегда: изменённая викторина может быть активной.
+func invalidateQuizCache(cache repository.CacheRepository, quizID uint) {
+	if cache == nil {
+		return
+	}
+	for _, key := range []string{fmt.Sprintf(quizWithQuestionsCacheKey, quizID), activeQuizCacheKey} {
+		if err := cache.Delete(key); err != nil {
+			log.Printf("[QuizService] Ошибка сброса кеша %s: %v", key, err)
+		}
+	}
+}
@@ -4,12 +4,15 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math"
 	"strconv"
 	"sync"
 	"time"
 
 	"github.com/yourusername/trivia-api/internal/domain/entity"
 	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/pkg/httpcache"
 	"github.com/yourusername/trivia-api/internal/service/quizmanager"
 	"github.com/yourusername/trivia-api/internal/websocket"
 	"gorm.io/gorm"
@@ -21,12 +24,20 @@ type QuizManager struct {
 	scheduler       *quizmanager.Scheduler
 	questionManager *quizmanager.QuestionManager
 	answerProcessor *quizmanager.AnswerProcessor
+	simulator       *quizmanager.Simulator
+	validator       *quizmanager.ScheduleValidator
+	previewer       *quizmanager.Previewer
+	admission       *quizmanager.AdmissionController
+	delivery        *quizmanager.DeliveryTracker
+	config          *quizmanager.Config
 
 	// Репозитории для прямого доступа
 	quizRepo      repository.QuizRepository
 	resultService *ResultService
 	wsManager     *websocket.Manager
 	cacheRepo     repository.CacheRepository
+	httpCache     httpcache.Invalidator
+	webhooks      *WebhookService
 
 	// Состояние активной викторины
 	activeQuizState *quizmanager.ActiveQuizState
@@ -69,11 +80,29 @@ func NewQuizManager(
 	scheduler := quizmanager.NewScheduler(config, deps)
 	questionManager := quizmanager.NewQuestionManager(config, deps)
 	answerProcessor := quizmanager.NewAnswerProcessor(config, deps)
+	if resultService != nil {
+		questionManager.SetAnswerVoider(resultService)
+	}
+	admission := quizmanager.NewAdmissionController(config, deps)
+	answerProcessor.SetAdmission(admission)
+	questionManager.SetAdmission(admission)
+	delivery := quizmanager.NewDeliveryTracker(config, deps)
+	answerProcessor.SetDeliveryTracker(delivery)
+	questionManager.SetDeliveryTracker(delivery)
+	if wsManager != nil {
+		wsManager.SetQuizLeaveHandler(admission.HandleLeave)
+	}
 
 	qm := &QuizManager{
 		scheduler:       scheduler,
 		questionManager: questionManager,
 		answerProcessor: answerProcessor,
+		simulator:       quizmanager.NewSimulator(config, deps),
+		validator:       quizmanager.NewScheduleValidator(config, deps),
+		previewer:       quizmanager.NewPreviewer(config, deps),
+		admission:       admission,
+		delivery:        delivery,
+		config:          config,
 		quizRepo:        quizRepo,
 		resultService:   resultService,
 		wsManager:       wsManager,
@@ -89,6 +118,104 @@ func NewQuizManager(
 	return qm
 }
 
+// SetNotifier подключает отправитель push-напоминаний о предстоящих викторинах
+func (qm *QuizManager) SetNotifier(notifier quizmanager.QuizNotifier) {
+	qm.scheduler.SetNotifier(notifier)
+}
+
+// SetParticipantForecast подключает оценку аудитории викторин для резерва ёмкости WebSocket
+func (qm *QuizManager) SetParticipantForecast(forecast quizmanager.ParticipantForecast) {
+	qm.scheduler.SetParticipantForecast(forecast)
+}
+
+// SetTiming применяет новые тайминги викторин (при перечитывании конфигурации)
+func (qm *QuizManager) SetTiming(timing quizmanager.Timing) {
+	qm.config.SetTiming(timing)
+}
+
+// SetAdImpressionRepo подключает учёт показов рекламных пауз для аналитики
+func (qm *QuizManager) SetAdImpressionRepo(repo repository.AdImpressionRepository) {
+	qm.questionManager.SetAdImpressionRepo(repo)
+}
+
+// SetLocalizer подключает переводы вопросов для рассылки quiz:question
+func (qm *QuizManager) SetLocalizer(localizer quizmanager.QuestionLocalizer) {
+	qm.questionManager.SetLocalizer(localizer)
+}
+
+// SetQuestionMedia подключает ссылки на медиа вопросов: предзагрузка при обратном отсчёте и quiz:question
+func (qm *QuizManager) SetQuestionMedia(media quizmanager.QuestionMediaSigner) {
+	qm.scheduler.SetMedia(media)
+	qm.questionManager.SetMedia(media)
+}
+
+// SetHTTPCache подключает сброс закешированных списков викторин при смене статуса викторины
+func (qm *QuizManager) SetHTTPCache(invalidator httpcache.Invalidator) {
+	qm.httpCache = invalidator
+}
+
+// SetSchedulingPause подключает паузу запуска викторин (режим обслуживания)
+func (qm *QuizManager) SetSchedulingPause(pause quizmanager.SchedulingPause) {
+	qm.scheduler.SetPause(pause)
+}
+
+// SetAnswerWriter подключает буфер пакетной записи ответов пользователей
+func (qm *QuizManager) SetAnswerWriter(writer quizmanager.AnswerWriter) {
+	qm.answerProcessor.SetAnswerWriter(writer)
+	qm.questionManager.SetAnswerWriter(writer)
+}
+
+// SetFriendNotifier подключает уведомления подписчиков о входе игрока в зал ожидания
+func (qm *QuizManager) SetFriendNotifier(notifier quizmanager.FriendActivityNotifier) {
+	qm.answerProcessor.SetFriendNotifier(notifier)
+}
+
+// SetAnswerObserver подключает наблюдателя сохранённых ответов (выгрузка в хранилище аналитики)
+func (qm *QuizManager) SetAnswerObserver(observer quizmanager.AnswerObserver) {
+	qm.answerProcessor.SetAnswerObserver(observer)
+	qm.questionManager.SetAnswerObserver(observer)
+}
+
+// SetAnnouncer подключает объявления ведущего: до начала викторины, после вопросов и перед финалом
+func (qm *QuizManager) SetAnnouncer(announcer quizmanager.Announcer) {
+	qm.scheduler.SetAnnouncer(announcer)
+	qm.questionManager.SetAnnouncer(announcer)
+}
+
+// SetDeliveryIncidentHook подключает запись вопросов, снятых с эфира из-за сбоя рассылки
+func (qm *QuizManager) SetDeliveryIncidentHook(hook func(incident quizmanager.DeliveryIncident)) {
+	qm.questionManager.SetDeliveryIncidentHook(hook)
+}
+
+// SetSponsorship подключает брендинг спонсора в событии запуска викторины
+func (qm *QuizManager) SetSponsorship(sponsorship quizmanager.Sponsorship) {
+	qm.scheduler.SetSponsorship(sponsorship)
+}
+
+// SetWebhookService подключает вебхуки партнёров о запуске, планировании и завершении викторин
+func (qm *QuizManager) SetWebhookService(webhooks *WebhookService) {
+	qm.webhooks = webhooks
+}
+
+// publishWebhook отправляет событие викторины в вебхуки, если они подключены
+func (qm *QuizManager) publishWebhook(eventType string, quiz *entity.Quiz, extra map[string]interface{}) {
+	if qm.webhooks == nil {
+		return
+	}
+	data := quizWebhookData(quiz)
+	for key, value := range extra {
+		data[key] = value
+	}
+	qm.webhooks.Publish(eventType, data)
+}
+
+// invalidateQuizListings сбрасывает кеш списков викторин
+func (qm *QuizManager) invalidateQuizListings() {
+	if qm.httpCache != nil {
+		qm.httpCache.Invalidate(httpcache.NamespaceQuizzes)
+	}
+}
+
 // handleEvents обрабатывает события от компонентов
 func (qm *QuizManager) handleEvents() {
 	// Слушаем события запуска викторин
@@ -118,21 +245,53 @@ func (qm *QuizManager) handleEvents() {
 	}
 }
 
-// ScheduleQuiz планирует запуск викторины в указанное время
+// ScheduleQuiz планирует запуск викторины в указанное время и сообщает о нём в вебхуки
 func (qm *QuizManager) ScheduleQuiz(quizID uint, scheduledTime time.Time) error {
+	if err := qm.RestoreSchedule(quizID, scheduledTime); err != nil {
+		return err
+	}
+	if qm.webhooks != nil {
+		go func() {
+			quiz, err := qm.quizRepo.GetByID(quizID)
+			if err != nil {
+				log.Printf("[QuizManager] Ошибка получения викторины #%d для вебхука: %v", quizID, err)
+				return
+			}
+			qm.publishWebhook(entity.WebhookEventQuizScheduled, quiz, nil)
+		}()
+	}
+	return nil
+}
+
+// RestoreSchedule планирует запуск викторины без уведомления вебхуков
+// (восстановление расписания после перезапуска сервера)
+func (qm *QuizManager) RestoreSchedule(quizID uint, scheduledTime time.Time) error {
 	log.Printf("[QuizManager] Планирование викторины #%d на %v", quizID, scheduledTime)
-	return qm.scheduler.ScheduleQuiz(qm.ctx, quizID, scheduledTime)
+	if err := qm.scheduler.ScheduleQuiz(qm.ctx, quizID, scheduledTime); err != nil {
+		return err
+	}
+	invalidateQuizCache(qm.cacheRepo, quizID)
+	qm.invalidateQuizListings()
+	return nil
 }
 
 // CancelQuiz отменяет запланированную викторину
 func (qm *QuizManager) CancelQuiz(quizID uint) error {
 	log.Printf("[QuizManager] Отмена викторины #%d", quizID)
-	return qm.scheduler.CancelQuiz(quizID)
+	if err := qm.scheduler.CancelQuiz(quizID); err != nil {
+		return err
+	}
+	invalidateQuizCache(qm.cacheRepo, quizID)
+	qm.invalidateQuizListings()
+	return nil
 }
 
 // handleQuizStart обрабатывает запуск викторины
 func (qm *QuizManager) handleQuizStart(quizID uint) {
 	log.Printf("[QuizManager] Обработка запуска викторины #%d", quizID)
+	// Планировщик уже перевёл викторину в in_progress
+	invalidateQuizCache(qm.cacheRepo, quizID)
+	qm.invalidateQuizListings()
 
 	// Получаем викторину с вопросами
 	quiz, err := qm.quizRepo.GetWithQuestions(quizID)
@@ -159,6 +318,7 @@ func (qm *QuizManager) handleQuizStart(quizID uint) {
 	}
 	qm.activeQuizState = newState
 	qm.stateMutex.Unlock()
+	qm.publishWebhook(entity.WebhookEventQuizStarted, quiz, map[string]interface{}{"started_at": time.Now().UTC()})
 
 	// Запускаем процесс отправки вопросов
 	go func() {
@@ -199,6 +359,8 @@ func (qm *QuizManager) finishQuiz(quizID uint) {
 		log.Printf("[QuizManager] Ошибка при обновлении статуса викторины #%d: %v", quizID, err)
 		// Продолжаем несмотря на ошибку
 	}
+	invalidateQuizCache(qm.cacheRepo, quizID)
+	qm.invalidateQuizListings()
 
 	// Отправляем событие о завершении
 	finishEvent := map[string]interface{}{
@@ -219,6 +381,7 @@ func (qm *QuizManager) finishQuiz(quizID uint) {
 	if err := qm.wsManager.BroadcastEventToQuiz(quizID, fullEvent); err != nil {
 		log.Printf("[QuizManager] Ошибка при отправке события о завершении викторины #%d: %v", quizID, err)
 	}
+	qm.wsManager.ReleaseQuizCapacity(quizID)
 
 	// --- Расчет индивидуальных результатов для ВСЕХ участников ---
 	// FIX: Используем Redis Set вместо WebSocket sync.Map,
@@ -242,6 +405,11 @@ func (qm *QuizManager) finishQuiz(quizID uint) {
 		participantIDs = append(participantIDs, uint(userID))
 	}
 
+	// Ответы из буфера записи должны попасть в БД до подсчёта результатов
+	if err := qm.resultService.FlushAnswers(); err != nil {
+		log.Printf("[QuizManager] КРИТИЧЕСКАЯ ОШИБКА: Не удалось записать буферизованные ответы викторины #%d: %v", quizID, err)
+	}
+
 	log.Printf("[QuizManager] Расчет и сохранение итоговых результатов для %d участников викторины #%d...", len(participantIDs), quizID)
 	var calculationWg sync.WaitGroup
 	calculationWg.Add(len(participantIDs))
@@ -264,13 +432,18 @@ func (qm *QuizManager) finishQuiz(quizID uint) {
 	if err := qm.resultService.DetermineWinnersAndAllocatePrizes(qm.ctx, quizID); err != nil {
 		log.Printf("[QuizManager] Ошибка при определении победителей для викторины #%d: %v", quizID, err)
 	}
+	qm.publishWebhook(entity.WebhookEventQuizCompleted, quiz, map[string]interface{}{
+		"status":       entity.QuizStatusCompleted,
+		"completed_at": completedAt.UTC(),
+		"participants": len(participantIDs),
+	})
 	// Старый асинхронный вызов с задержкой удален
 	// activeQuizState уже сброшен на L192 под lock
 }
 
 // ProcessAnswer обрабатывает ответ пользователя, находя соответствующее состояние викторины
 // и делегируя обработку процессору ответов.
-func (qm *QuizManager) ProcessAnswer(userID, questionID uint, selectedOption int, timestamp int64) error {
+func (qm *QuizManager) ProcessAnswer(ctx context.Context, userID, questionID uint, selectedOption int, timestamp int64) error {
 	qm.stateMutex.RLock()
 	quizState := qm.activeQuizState
 	qm.stateMutex.RUnlock()
@@ -293,6 +466,11 @@ func (qm *QuizManager) ProcessAnswer(userID, questionID uint, selectedOption int
 		return fmt.Errorf("received answer for non-current question (expected %d, got %d)", question.ID, questionID)
 	}
 
+	// Ответы на снятый администратором вопрос не принимаются
+	if clock := quizState.QuestionClock(); clock != nil && clock.Voided() {
+		return fmt.Errorf("question %d was voided", questionID)
+	}
+
 	// ===>>> ИЗМЕНЕНИЕ: Получаем время старта вопроса ПЕРЕД вызовом <<<===
 	questionStartTimeMs := quizState.GetCurrentQuestionStartTime()
 	if questionStartTimeMs == 0 {
@@ -302,7 +480,6 @@ func (qm *QuizManager) ProcessAnswer(userID, questionID uint, selectedOption int
 	}
 
 	// Делегируем обработку процессору ответов, передавая все необходимые данные
-	ctx := context.Background() // Используем фоновый контекст или получаем из вызывающей функции
 	return qm.answerProcessor.ProcessAnswer(
 		ctx,
 		userID,
@@ -314,6 +491,35 @@ func (qm *QuizManager) ProcessAnswer(userID, questionID uint, selectedOption int
 	)
 }
 
+// AcknowledgeQuestion фиксирует, что текущий вопрос дошёл до игрока (user:question_received):
+// от этого момента, с ограниченной отсрочкой, отсчитывается его время на ответ
+func (qm *QuizManager) AcknowledgeQuestion(ctx context.Context, userID, questionID uint) error {
+	qm.stateMutex.RLock()
+	quizState := qm.activeQuizState
+	qm.stateMutex.RUnlock()
+	if quizState == nil {
+		return fmt.Errorf("active quiz state not found")
+	}
+
+	question, _ := quizState.GetCurrentQuestion()
+	if question == nil || question.ID != questionID {
+		return fmt.Errorf("question %d is not the current question", questionID)
+	}
+	startTimeMs := quizState.GetCurrentQuestionStartTime()
+	if startTimeMs == 0 {
+		return fmt.Errorf("question %d has not been sent yet", questionID)
+	}
+	return qm.answerProcessor.AcknowledgeQuestion(ctx, userID, question, quizState, startTimeMs)
+}
+
+// GetDeliveryStats возвращает задержку доставки вопросов идущей викторины по подтверждениям этого узла
+func (qm *QuizManager) GetDeliveryStats(quizID uint) (*quizmanager.DeliveryStats, error) {
+	if _, err := qm.liveQuizState(quizID); err != nil {
+		return nil, err
+	}
+	return qm.delivery.Stats(quizID), nil
+}
+
 // HandleReadyEvent обрабатывает событие готовности пользователя
 func (qm *QuizManager) HandleReadyEvent(userID uint, quizID uint) error {
 	return qm.answerProcessor.HandleReadyEvent(qm.ctx, userID, quizID)
@@ -331,6 +537,22 @@ func (qm *QuizManager) GetActiveQuiz() *entity.Quiz {
 	return qm.activeQuizState.Quiz
 }
 
+// GetLiveProgress возвращает номер текущего вопроса (0, если викторина не идёт на этом узле)
+// и количество игроков, подключённых к викторине
+func (qm *QuizManager) GetLiveProgress(quizID uint) (currentQuestion int, playerCount int) {
+	qm.stateMutex.RLock()
+	state := qm.activeQuizState
+	qm.stateMutex.RUnlock()
+
+	if state != nil && state.Quiz != nil && state.Quiz.ID == quizID {
+		_, currentQuestion = state.GetCurrentQuestion()
+	}
+	if qm.wsManager != nil {
+		playerCount = qm.wsManager.GetSubscriberCount(quizID)
+	}
+	return currentQuestion, playerCount
+}
+
 // QuizStateResponse представляет состояние викторины для resync
 type QuizStateResponse struct {
 	QuizID            uint           `json:"quiz_id"`
@@ -352,6 +574,7 @@ type QuestionState struct {
 	Text           string   `json:"text"`
 	Options        []Option `json:"options"`
 	TimeLimit      int      `json:"time_limit"`
+	Deadline       int64    `json:"deadline"` // unix ms окончания вопроса с учётом продлений
 }
 
 // Option представляет вариант ответа
@@ -422,8 +645,9 @@ func (qm *QuizManager) GetCurrentState(userID uint, quizID uint) (*QuizStateResp
 	// Если есть текущий вопрос
 	if question != nil {
 		// Рассчитываем оставшееся время
+		limitMs := state.QuestionTimeLimitMs(question, startTimeMs)
 		elapsedMs := time.Now().UnixMilli() - startTimeMs
-		remainingSec := question.TimeLimitSec - int(elapsedMs/1000)
+		remainingSec := int((limitMs - elapsedMs) / 1000)
 		if remainingSec < 0 {
 			remainingSec = 0
 		}
@@ -444,13 +668,143 @@ func (qm *QuizManager) GetCurrentState(userID uint, quizID uint) (*QuizStateResp
 			TotalQuestions: qm.getTotalQuestions(state.Quiz),
 			Text:           question.Text,
 			Options:        options,
-			TimeLimit:      question.TimeLimitSec,
+			TimeLimit:      int(math.Ceil(state.Quiz.Timing.QuestionTimeLimit(question.TimeLimitSec).Seconds())),
+			Deadline:       startTimeMs + limitMs,
 		}
 	}
 
 	return response, nil
 }
 
+// StartSimulation запускает воспроизведение завершённой викторины в песочнице.
+// speed — ускорение относительно реального времени, 0 — без пауз.
+func (qm *QuizManager) StartSimulation(quizID uint, speed float64) (*quizmanager.SimulationRun, error) {
+	return qm.simulator.Start(qm.ctx, quizID, speed)
+}
+
+// GetSimulation возвращает прогон симуляции викторины с отчётом, если он завершён
+func (qm *QuizManager) GetSimulation(quizID uint, runID string) (*quizmanager.SimulationRun, error) {
+	return qm.simulator.Get(quizID, runID)
+}
+
+// ValidateSchedule проверяет планирование викторины на scheduledTime без изменений:
+// вопросы, пересечения с другими викторинами, рекламные слоты и призовой фонд
+func (qm *QuizManager) ValidateSchedule(quizID uint, scheduledTime time.Time, timing *entity.QuizTiming) (*quizmanager.ScheduleValidationReport, error) {
+	return qm.validator.ValidateWithTiming(quizID, scheduledTime, timing)
+}
+
+// EstimateQuizEnd оценивает время окончания запланированной викторины (для календаря)
+func (qm *QuizManager) EstimateQuizEnd(quiz *entity.Quiz) (time.Time, error) {
+	return qm.validator.EstimateEnd(quiz)
+}
+
+// PreviewQuiz возвращает вероятный набор вопросов, кривую сложности, оценку длительности
+// и расстановку рекламы викторины. Пул вопросов не расходуется.
+func (qm *QuizManager) PreviewQuiz(quizID uint) (*quizmanager.QuizPreview, error) {
+	return qm.previewer.Preview(quizID)
+}
+
+// liveQuizState возвращает состояние викторины quizID, если она идёт на этом узле
+func (qm *QuizManager) liveQuizState(quizID uint) (*quizmanager.ActiveQuizState, error) {
+	qm.stateMutex.RLock()
+	state := qm.activeQuizState
+	qm.stateMutex.RUnlock()
+	if state == nil || state.Quiz == nil || state.Quiz.ID != quizID {
+		return nil, fmt.Errorf("%w: quiz #%d is not running", apperrors.ErrConflict, quizID)
+	}
+	return state, nil
+}
+
+// PauseQuestion ставит таймер текущего вопроса идущей викторины на паузу
+func (qm *QuizManager) PauseQuestion(quizID uint) (*quizmanager.LiveQuestionStatus, error) {
+	state, err := qm.liveQuizState(quizID)
+	if err != nil {
+		return nil, err
+	}
+	return qm.questionManager.PauseQuestion(qm.ctx, state)
+}
+
+// ResumeQuestion снимает таймер текущего вопроса с паузы
+func (qm *QuizManager) ResumeQuestion(quizID uint) (*quizmanager.LiveQuestionStatus, error) {
+	state, err := qm.liveQuizState(quizID)
+	if err != nil {
+		return nil, err
+	}
+	return qm.questionManager.ResumeQuestion(qm.ctx, state)
+}
+
+// ExtendQuestion продлевает текущий вопрос на seconds секунд
+func (qm *QuizManager) ExtendQuestion(quizID uint, seconds int) (*quizmanager.LiveQuestionStatus, error) {
+	state, err := qm.liveQuizState(quizID)
+	if err != nil {
+		return nil, err
+	}
+	return qm.questionManager.ExtendQuestion(qm.ctx, state, seconds)
+}
+
+// SkipQuestion снимает текущий вопрос с эфира: ответы на него аннулируются,
+// а его номер занимает следующий вопрос
+func (qm *QuizManager) SkipQuestion(quizID uint, reason string) (*quizmanager.LiveQuestionStatus, error) {
+	state, err := qm.liveQuizState(quizID)
+	if err != nil {
+		return nil, err
+	}
+	return qm.questionManager.SkipQuestion(state, reason)
+}
+
+// Announce рассылает участникам идущей викторины объявление администратора
+func (qm *QuizManager) Announce(quizID uint, message string) error {
+	state, err := qm.liveQuizState(quizID)
+	if err != nil {
+		return err
+	}
+	return qm.questionManager.Announce(qm.ctx, state, message)
+}
+
+// GetAdmissionStatus возвращает заполненность викторины и начало очереди допуска
+func (qm *QuizManager) GetAdmissionStatus(quizID uint) (*quizmanager.AdmissionStatus, error) {
+	quiz, err := qm.quizRepo.GetByID(quizID)
+	if err != nil {
+		return nil, err
+	}
+	return qm.admission.Status(quiz)
+}
+
+// SetQuizCapacity меняет вместимость викторины (0 — без ограничения). При увеличении
+// вместимости ждущие в очереди сразу допускаются на освободившиеся места.
+func (qm *QuizManager) SetQuizCapacity(quizID uint, maxPlayers int) (*quizmanager.AdmissionStatus, error) {
+	if maxPlayers < 0 {
+		return nil, fmt.Errorf("%w: max_players must not be negative", apperrors.ErrValidation)
+	}
+	quiz, err := qm.quizRepo.GetByID(quizID)
+	if err != nil {
+		return nil, err
+	}
+	if quiz.IsCompleted() || quiz.Status == entity.QuizStatusCancelled {
+		return nil, fmt.Errorf("%w: quiz #%d is already over", apperrors.ErrConflict, quizID)
+	}
+	if err := qm.quizRepo.UpdateMaxPlayers(quizID, maxPlayers); err != nil {
+		return nil, err
+	}
+	invalidateQuizCache(qm.cacheRepo, quizID)
+	qm.invalidateQuizListings()
+	log.Printf("[QuizManager] Вместимость викторины #%d: %d -> %d", quizID, quiz.MaxPlayers, maxPlayers)
+
+	if _, err := qm.admission.AdmitNext(quizID); err != nil {
+		log.Printf("[QuizManager] WARNING: Не удалось впустить игроков из очереди викторины #%d: %v", quizID, err)
+	}
+	quiz.MaxPlayers = maxPlayers
+	return qm.admission.Status(quiz)
+}
+
+// AdmitQueuedPlayer впускает игрока из очереди вне очереди и сверх вместимости
+func (qm *QuizManager) AdmitQueuedPlayer(quizID, userID uint) (*quizmanager.AdmissionStatus, error) {
+	if err := qm.admission.AdmitUser(quizID, userID); err != nil {
+		return nil, err
+	}
+	return qm.GetAdmissionStatus(quizID)
+}
+
 // getTotalQuestions возвращает количество вопросов с fallback на дефолт
 func (qm *QuizManager) getTotalQuestions(quiz *entity.Quiz) int {
 	if quiz.QuestionCount > 0 {
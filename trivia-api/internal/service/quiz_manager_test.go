@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -32,6 +33,14 @@ func (m *MockQuizRepository) GetByID(id uint) (*entity.Quiz, error) {
 	return args.Get(0).(*entity.Quiz), args.Error(1)
 }
 
+func (m *MockQuizRepository) GetByIDs(ids []uint) ([]entity.Quiz, error) {
+	args := m.Called(ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Quiz), args.Error(1)
+}
+
 func (m *MockQuizRepository) GetWithQuestions(id uint) (*entity.Quiz, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
@@ -60,6 +69,56 @@ func (m *MockQuizRepository) UpdateScheduleInfo(quizID uint, scheduledTime time.
 	return args.Error(0)
 }
 
+func (m *MockQuizRepository) UpdateSecondChance(quizID uint, mode string, cost int64, adAssetID *uint) error {
+	args := m.Called(quizID, mode, cost, adAssetID)
+	return args.Error(0)
+}
+
+func (m *MockQuizRepository) UpdateMaxPlayers(quizID uint, maxPlayers int) error {
+	args := m.Called(quizID, maxPlayers)
+	return args.Error(0)
+}
+
+func (m *MockQuizRepository) UpdateTiming(quizID uint, timing entity.QuizTiming) error {
+	args := m.Called(quizID, timing)
+	return args.Error(0)
+}
+
+func (m *MockQuizRepository) UpdateGameMode(quizID uint, mode string, lives int, suddenDeath bool) error {
+	args := m.Called(quizID, mode, lives, suddenDeath)
+	return args.Error(0)
+}
+
+func (m *MockQuizRepository) UpdateTieBreak(quizID uint, tieBreak string) error {
+	args := m.Called(quizID, tieBreak)
+	return args.Error(0)
+}
+
+func (m *MockQuizRepository) UpdateSponsor(quizID uint, sponsor entity.QuizSponsor) error {
+	args := m.Called(quizID, sponsor)
+	return args.Error(0)
+}
+
+func (m *MockQuizRepository) UpdateScoringStrategy(quizID uint, strategy string) error {
+	args := m.Called(quizID, strategy)
+	return args.Error(0)
+}
+
+func (m *MockQuizRepository) UpdateRequireVerifiedKK(quizID uint, require bool) error {
+	args := m.Called(quizID, require)
+	return args.Error(0)
+}
+
+func (m *MockQuizRepository) UpdatePrizeLadder(quizID uint, ladder entity.PrizeLadder) error {
+	args := m.Called(quizID, ladder)
+	return args.Error(0)
+}
+
+func (m *MockQuizRepository) UpdateAllowedCountries(quizID uint, countries entity.StringArray) error {
+	args := m.Called(quizID, countries)
+	return args.Error(0)
+}
+
 func (m *MockQuizRepository) AtomicStartQuiz(quizID uint) error {
 	args := m.Called(quizID)
 	return args.Error(0)
@@ -175,6 +234,39 @@ func (m *MockCacheRepository) SIsMember(key string, member interface{}) (bool, e
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockCacheRepository) SCard(key string) (int64, error) {
+	args := m.Called(key)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockCacheRepository) ZAddNX(key string, score float64, member interface{}) (bool, error) {
+	args := m.Called(key, score, member)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCacheRepository) ZRank(key string, member interface{}) (int64, error) {
+	args := m.Called(key, member)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockCacheRepository) ZRem(key string, members ...interface{}) error {
+	args := m.Called(key, members)
+	return args.Error(0)
+}
+
+func (m *MockCacheRepository) ZCard(key string) (int64, error) {
+	args := m.Called(key)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockCacheRepository) ZRange(key string, start, stop int64) ([]string, error) {
+	args := m.Called(key, start, stop)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
 func (m *MockCacheRepository) Expire(key string, expiration time.Duration) error {
 	args := m.Called(key, expiration)
 	return args.Error(0)
@@ -198,6 +290,23 @@ func (m *MockCacheRepository) GetJSON(key string, dest interface{}) error {
 	return args.Error(0)
 }
 
+func (m *MockCacheRepository) RPushTrim(key string, maxLen int64, expiration time.Duration, values ...interface{}) error {
+	args := m.Called(key, maxLen, expiration, values)
+	return args.Error(0)
+}
+
+func (m *MockCacheRepository) LRange(key string, start, stop int64) ([]string, error) {
+	args := m.Called(key, start, stop)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockCacheRepository) WithContext(ctx context.Context) repository.CacheRepository {
+	return m
+}
+
 // Мок для WebSocket Manager, который должен соответствовать типу *websocket.Manager
 type MockManager struct {
 	mock.Mock
@@ -283,8 +392,23 @@ func (m *MockResultRepository) SaveUserAnswer(answer *entity.UserAnswer) error {
 	return args.Error(0)
 }
 
-func (m *MockResultRepository) CalculateRanks(tx *gorm.DB, quizID uint) error {
-	args := m.Called(tx, quizID)
+func (m *MockResultRepository) SaveUserAnswersBatch(answers []entity.UserAnswer) (int64, error) {
+	args := m.Called(answers)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockResultRepository) VoidQuestionAnswers(quizID, questionID uint) (int64, error) {
+	args := m.Called(quizID, questionID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockResultRepository) ReviveEliminatedAnswer(quizID, userID uint) (int64, error) {
+	args := m.Called(quizID, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockResultRepository) CalculateRanks(tx *gorm.DB, quizID uint, tieBreak string) error {
+	args := m.Called(tx, quizID, tieBreak)
 	return args.Error(0)
 }
 
@@ -333,6 +457,27 @@ func (m *MockResultRepository) GetQuizUserAnswers(quizID uint) ([]entity.UserAns
 }
 
 // Добавляем недостающий метод GetAllQuizResults
+func (m *MockResultRepository) GetQuizResultsAfter(quizID uint, after *repository.QuizResultCursor, limit int) ([]entity.Result, error) {
+	args := m.Called(quizID, after, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Result), args.Error(1)
+}
+
+func (m *MockResultRepository) GetUserResultsAfter(userID uint, after *repository.UserResultCursor, limit int) ([]entity.Result, error) {
+	args := m.Called(userID, after, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Result), args.Error(1)
+}
+
+func (m *MockResultRepository) GetUserBestWinStreak(userID uint) (int, error) {
+	args := m.Called(userID)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *MockResultRepository) GetAllQuizResults(quizID uint) ([]entity.Result, error) {
 	args := m.Called(quizID)
 	if args.Get(0) == nil {
@@ -351,14 +496,18 @@ func (m *MockResultRepository) GetQuizWinners(quizID uint) ([]entity.Result, err
 }
 
 // Добавляем недостающий метод FindAndUpdateWinners
-func (m *MockResultRepository) FindAndUpdateWinners(tx *gorm.DB, quizID uint, questionCount int, totalPrizeFund int) ([]uint, int, error) {
-	args := m.Called(tx, quizID, questionCount, totalPrizeFund)
+func (m *MockResultRepository) FindAndUpdateWinners(tx *gorm.DB, quizID uint, criteria repository.WinnerCriteria, totalPrizeFund int) ([]uint, int, error) {
+	args := m.Called(tx, quizID, criteria, totalPrizeFund)
 	if args.Get(0) == nil {
 		return nil, 0, args.Error(2)
 	}
 	return args.Get(0).([]uint), args.Get(1).(int), args.Error(2)
 }
 
+func (m *MockResultRepository) WithContext(ctx context.Context) repository.ResultRepository {
+	return m
+}
+
 // Мок для вопросов
 type MockQuestionRepository struct {
 	mock.Mock
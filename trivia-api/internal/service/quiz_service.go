@@ -10,7 +10,10 @@ import (
 	"github.com/yourusername/trivia-api/internal/domain/entity"
 	"github.com/yourusername/trivia-api/internal/domain/repository"
 	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/pkg/geo"
+	"github.com/yourusername/trivia-api/internal/pkg/httpcache"
 	"github.com/yourusername/trivia-api/internal/service/quizmanager"
+	"golang.org/x/sync/singleflight"
 	"gorm.io/gorm"
 )
 
@@ -32,6 +35,8 @@ type QuizService struct {
 	cacheRepo    repository.CacheRepository
 	config       *quizmanager.Config
 	db           *gorm.DB
+	httpCache    httpcache.Invalidator
+	loads        singleflight.Group // объединяет одновременные промахи кеша викторин
 }
 
 // AskedQuizQuestion представляет фактически заданный вопрос в викторине
@@ -60,6 +65,18 @@ func NewQuizService(
 	}
 }
 
+// SetHTTPCache подключает сброс закешированных HTTP-ответов со списками викторин
+func (s *QuizService) SetHTTPCache(invalidator httpcache.Invalidator) {
+	s.httpCache = invalidator
+}
+
+// invalidateQuizListings сбрасывает кеш списков викторин после изменения викторины
+func (s *QuizService) invalidateQuizListings() {
+	if s.httpCache != nil {
+		s.httpCache.Invalidate(httpcache.NamespaceQuizzes)
+	}
+}
+
 // CreateQuiz создает новую викторину
 func (s *QuizService) CreateQuiz(title, description string, scheduledTime time.Time, prizeFund int, finishOnZeroPlayers bool, questionSourceMode string) (*entity.Quiz, error) {
 	// Проверяем, что время проведения в будущем
@@ -87,12 +104,14 @@ func (s *QuizService) CreateQuiz(title, description string, scheduledTime time.T
 		PrizeFund:           prizeFund,
 		FinishOnZeroPlayers: finishOnZeroPlayers,
 		QuestionSourceMode:  normalizedMode,
+		SecondChanceMode:    entity.SecondChanceOff,
 	}
 
 	// Сохраняем викторину в БД
 	if err := s.quizRepo.Create(quiz); err != nil {
 		return nil, fmt.Errorf("failed to create quiz: %w", err)
 	}
+	s.invalidateQuizListings()
 
 	return quiz, nil
 }
@@ -104,7 +123,7 @@ func (s *QuizService) GetQuizByID(quizID uint) (*entity.Quiz, error) {
 
 // GetActiveQuiz возвращает активную викторину
 func (s *QuizService) GetActiveQuiz() (*entity.Quiz, error) {
-	return s.quizRepo.GetActive()
+	return s.cachedActiveQuiz()
 }
 
 // GetScheduledQuizzes возвращает список запланированных викторин
@@ -112,6 +131,22 @@ func (s *QuizService) GetScheduledQuizzes() ([]entity.Quiz, error) {
 	return s.quizRepo.GetScheduled()
 }
 
+// GetRegistrationCounts возвращает число участников, отметивших готовность (user:ready),
+// для каждой викторины. Ошибка Redis по одной викторине не прерывает подсчёт: её счётчик
+// просто отсутствует в результате.
+func (s *QuizService) GetRegistrationCounts(quizIDs []uint) map[uint]int64 {
+	counts := make(map[uint]int64, len(quizIDs))
+	for _, quizID := range quizIDs {
+		count, err := s.cacheRepo.SCard(fmt.Sprintf("quiz:%d:participants", quizID))
+		if err != nil {
+			log.Printf("[QuizService] Не удалось посчитать участников викторины #%d: %v", quizID, err)
+			continue
+		}
+		counts[quizID] = count
+	}
+	return counts
+}
+
 // AddQuestions добавляет вопросы к викторине
 func (s *QuizService) AddQuestions(quizID uint, questions []entity.Question) error {
 	// Получаем викторину, чтобы убедиться, что она существует
@@ -138,9 +173,10 @@ func (s *QuizService) AddQuestions(quizID uint, questions []entity.Question) err
 		return fmt.Errorf("максимальное количество вопросов – %d", maxQuestions)
 	}
 
-	// Устанавливаем quizID для всех вопросов
+	// Устанавливаем quizID для всех вопросов; в эфир они попадут после проверки
 	for i := range questions {
 		questions[i].QuizID = &quizID
+		questions[i].ReviewStatus = entity.QuestionReviewDraft
 	}
 
 	// Сохраняем вопросы в БД
@@ -150,7 +186,12 @@ func (s *QuizService) AddQuestions(quizID uint, questions []entity.Question) err
 
 	// Обновляем количество вопросов в викторине
 	// FIX BUG-4: Атомарное увеличение question_count (без перетирания других полей)
-	return s.quizRepo.IncrementQuestionCount(quizID, len(questions))
+	if err := s.quizRepo.IncrementQuestionCount(quizID, len(questions)); err != nil {
+		return err
+	}
+	s.InvalidateQuiz(quizID)
+	s.invalidateQuizListings()
+	return nil
 }
 
 // ScheduleQuiz планирует время проведения викторины
@@ -172,12 +213,240 @@ func (s *QuizService) ScheduleQuiz(quizID uint, scheduledTime time.Time, finishO
 	}
 
 	// Точечное обновление scheduled_time и status (без full Save)
-	return s.quizRepo.UpdateScheduleInfo(quizID, scheduledTime, entity.QuizStatusScheduled, finishOnZeroPlayers)
+	if err := s.quizRepo.UpdateScheduleInfo(quizID, scheduledTime, entity.QuizStatusScheduled, finishOnZeroPlayers); err != nil {
+		return err
+	}
+	s.InvalidateQuiz(quizID)
+	s.invalidateQuizListings()
+	return nil
+}
+
+// Допустимые переопределения таймингов викторины
+const (
+	maxQuizQuestionDelayMs     = 10000
+	maxQuizAnswerRevealDelayMs = 10000
+	minQuizTimeLimitMultiplier = 0.5
+	maxQuizTimeLimitMultiplier = 3.0
+	minQuizAdBreakDurationSec  = 3
+	maxQuizAdBreakDurationSec  = 60
+)
+
+// ValidateQuizTiming проверяет переопределения таймингов викторины
+func ValidateQuizTiming(timing entity.QuizTiming) error {
+	if d := timing.QuestionDelayMs; d != nil && (*d < 0 || *d > maxQuizQuestionDelayMs) {
+		return fmt.Errorf("%w: question_delay_ms must be between 0 and %d", apperrors.ErrValidation, maxQuizQuestionDelayMs)
+	}
+	if d := timing.AnswerRevealDelayMs; d != nil && (*d < 0 || *d > maxQuizAnswerRevealDelayMs) {
+		return fmt.Errorf("%w: answer_reveal_delay_ms must be between 0 and %d", apperrors.ErrValidation, maxQuizAnswerRevealDelayMs)
+	}
+	if m := timing.TimeLimitMultiplier; m != nil && (*m < minQuizTimeLimitMultiplier || *m > maxQuizTimeLimitMultiplier) {
+		return fmt.Errorf("%w: time_limit_multiplier must be between %.1f and %.1f", apperrors.ErrValidation, minQuizTimeLimitMultiplier, maxQuizTimeLimitMultiplier)
+	}
+	if d := timing.AdBreakDurationSec; d != nil && (*d < minQuizAdBreakDurationSec || *d > maxQuizAdBreakDurationSec) {
+		return fmt.Errorf("%w: ad_break_duration_sec must be between %d and %d", apperrors.ErrValidation, minQuizAdBreakDurationSec, maxQuizAdBreakDurationSec)
+	}
+	return nil
+}
+
+// UpdateQuizTiming заменяет переопределения таймингов викторины. Идущая викторина читает
+// тайминги из загруженного при старте состояния, поэтому менять их можно только до старта.
+func (s *QuizService) UpdateQuizTiming(quizID uint, timing entity.QuizTiming) error {
+	if err := ValidateQuizTiming(timing); err != nil {
+		return err
+	}
+	quiz, err := s.quizRepo.GetByID(quizID)
+	if err != nil {
+		return err
+	}
+	if quiz.IsActive() || quiz.IsCompleted() {
+		return fmt.Errorf("%w: timing of quiz #%d cannot be changed after start", apperrors.ErrConflict, quizID)
+	}
+	if err := s.quizRepo.UpdateTiming(quizID, timing); err != nil {
+		return err
+	}
+	s.InvalidateQuiz(quizID)
+	s.invalidateQuizListings()
+	return nil
+}
+
+// maxQuizLives — сколько жизней можно дать игроку в режиме lives
+const maxQuizLives = 10
+
+// ValidateGameMode проверяет режим игры викторины: жизни задаются только для режима lives,
+// финал на выбывание нужен только там, где игроки выбывают
+func ValidateGameMode(mode string, lives int, suddenDeath bool) error {
+	switch mode {
+	case entity.GameModeElimination, entity.GameModePointsOnly:
+		if lives != 0 {
+			return fmt.Errorf("%w: lives are only allowed in %s mode", apperrors.ErrValidation, entity.GameModeLives)
+		}
+	case entity.GameModeLives:
+		if lives < 1 || lives > maxQuizLives {
+			return fmt.Errorf("%w: lives must be between 1 and %d", apperrors.ErrValidation, maxQuizLives)
+		}
+	default:
+		return fmt.Errorf("%w: unknown game mode %q", apperrors.ErrValidation, mode)
+	}
+	if suddenDeath && mode == entity.GameModePointsOnly {
+		return fmt.Errorf("%w: sudden death requires a mode with elimination", apperrors.ErrValidation)
+	}
+	return nil
+}
+
+// ConfigureGameMode задаёт режим игры викторины. Режим определяет выбывание с первого вопроса,
+// поэтому менять его можно только до старта.
+func (s *QuizService) ConfigureGameMode(quizID uint, mode string, lives int, suddenDeath bool) error {
+	if err := ValidateGameMode(mode, lives, suddenDeath); err != nil {
+		return err
+	}
+	quiz, err := s.quizRepo.GetByID(quizID)
+	if err != nil {
+		return err
+	}
+	if quiz.IsActive() || quiz.IsCompleted() {
+		return fmt.Errorf("%w: game mode of quiz #%d cannot be changed after start", apperrors.ErrConflict, quizID)
+	}
+	if err := s.quizRepo.UpdateGameMode(quizID, mode, lives, suddenDeath); err != nil {
+		return err
+	}
+	s.InvalidateQuiz(quizID)
+	s.invalidateQuizListings()
+	return nil
+}
+
+// ConfigureTieBreak задаёт способ разрешения ничьей между победителями. Победители определяются
+// при завершении викторины, поэтому менять способ можно до завершения.
+func (s *QuizService) ConfigureTieBreak(quizID uint, tieBreak string) error {
+	if tieBreak != entity.TieBreakNone && tieBreak != entity.TieBreakResponseTime {
+		return fmt.Errorf("%w: unknown tie-break %q", apperrors.ErrValidation, tieBreak)
+	}
+	quiz, err := s.quizRepo.GetByID(quizID)
+	if err != nil {
+		return err
+	}
+	if quiz.IsCompleted() {
+		return fmt.Errorf("%w: tie-break of quiz #%d cannot be changed after completion", apperrors.ErrConflict, quizID)
+	}
+	if err := s.quizRepo.UpdateTieBreak(quizID, tieBreak); err != nil {
+		return err
+	}
+	s.InvalidateQuiz(quizID)
+	s.invalidateQuizListings()
+	return nil
+}
+
+// ConfigurePrizeLadder заменяет лестницу призов викторины; пустая лестница возвращает деление
+// фонда поровну. Фонд распределяется при завершении, поэтому менять лестницу можно до завершения.
+func (s *QuizService) ConfigurePrizeLadder(quizID uint, ladder entity.PrizeLadder) error {
+	if err := ValidatePrizeLadder(ladder); err != nil {
+		return err
+	}
+	quiz, err := s.quizRepo.GetByID(quizID)
+	if err != nil {
+		return err
+	}
+	if quiz.IsCompleted() {
+		return fmt.Errorf("%w: prize ladder of quiz #%d cannot be changed after completion", apperrors.ErrConflict, quizID)
+	}
+	if err := s.quizRepo.UpdatePrizeLadder(quizID, ladder); err != nil {
+		return err
+	}
+	s.InvalidateQuiz(quizID)
+	s.invalidateQuizListings()
+	return nil
+}
+
+// maxAllowedCountries — ограничение длины списка разрешённых стран викторины
+const maxAllowedCountries = 50
+
+// ConfigureAllowedCountries задаёт страны, игроки из которых получают призы викторины; пустой
+// список снимает ограничение. Коды ISO 3166-1 alpha-2 приводятся к верхнему регистру, повторы
+// удаляются. Страна проверяется при подведении итогов, поэтому менять список можно до завершения.
+func (s *QuizService) ConfigureAllowedCountries(quizID uint, countries []string) (entity.StringArray, error) {
+	normalized := make(entity.StringArray, 0, len(countries))
+	seen := make(map[string]bool, len(countries))
+	for _, code := range countries {
+		country := geo.NormalizeCountry(code)
+		if country == "" {
+			return nil, fmt.Errorf("%w: invalid country code %q", apperrors.ErrValidation, code)
+		}
+		if !seen[country] {
+			seen[country] = true
+			normalized = append(normalized, country)
+		}
+	}
+	if len(normalized) > maxAllowedCountries {
+		return nil, fmt.Errorf("%w: at most %d countries are allowed", apperrors.ErrValidation, maxAllowedCountries)
+	}
+	quiz, err := s.quizRepo.GetByID(quizID)
+	if err != nil {
+		return nil, err
+	}
+	if quiz.IsCompleted() {
+		return nil, fmt.Errorf("%w: allowed countries of quiz #%d cannot be changed after completion", apperrors.ErrConflict, quizID)
+	}
+	if err := s.quizRepo.UpdateAllowedCountries(quizID, normalized); err != nil {
+		return nil, err
+	}
+	s.InvalidateQuiz(quizID)
+	s.invalidateQuizListings()
+	return normalized, nil
+}
+
+// ValidateScoringStrategy проверяет имя стратегии начисления очков
+func ValidateScoringStrategy(strategy string) error {
+	if _, ok := quizmanager.LookupScoringStrategy(strategy); !ok {
+		return fmt.Errorf("%w: unknown scoring strategy %q", apperrors.ErrValidation, strategy)
+	}
+	return nil
+}
+
+// ConfigureScoringStrategy задаёт стратегию начисления очков. Очки начисляются по ходу игры,
+// поэтому стратегию можно менять только до старта викторины.
+func (s *QuizService) ConfigureScoringStrategy(quizID uint, strategy string) error {
+	if err := ValidateScoringStrategy(strategy); err != nil {
+		return err
+	}
+	quiz, err := s.quizRepo.GetByID(quizID)
+	if err != nil {
+		return err
+	}
+	if quiz.IsActive() || quiz.IsCompleted() {
+		return fmt.Errorf("%w: scoring strategy of quiz #%d cannot be changed after start", apperrors.ErrConflict, quizID)
+	}
+	if err := s.quizRepo.UpdateScoringStrategy(quizID, strategy); err != nil {
+		return err
+	}
+	s.InvalidateQuiz(quizID)
+	s.invalidateQuizListings()
+	return nil
+}
+
+// ConfigureKazakhRequirement задаёт, нужен ли вопросам викторины проверенный казахский текст:
+// при require селектор не берёт вопросы без text_kk, в том числе с непроверенным машинным переводом.
+// Вопросы выбираются по ходу игры, поэтому требование можно менять только до старта викторины.
+func (s *QuizService) ConfigureKazakhRequirement(quizID uint, require bool) error {
+	quiz, err := s.quizRepo.GetByID(quizID)
+	if err != nil {
+		return err
+	}
+	if quiz.IsActive() || quiz.IsCompleted() {
+		return fmt.Errorf("%w: kk requirement of quiz #%d cannot be changed after start", apperrors.ErrConflict, quizID)
+	}
+	if quiz.RequireVerifiedKK == require {
+		return nil
+	}
+	if err := s.quizRepo.UpdateRequireVerifiedKK(quizID, require); err != nil {
+		return err
+	}
+	s.InvalidateQuiz(quizID)
+	s.invalidateQuizListings()
+	return nil
 }
 
 // GetQuizWithQuestions возвращает викторину с вопросами
 func (s *QuizService) GetQuizWithQuestions(quizID uint) (*entity.Quiz, error) {
-	return s.quizRepo.GetWithQuestions(quizID)
+	return s.cachedQuizWithQuestions(quizID)
 }
 
 // GetQuizAskedQuestions возвращает фактически заданные вопросы по истории проведения.
@@ -250,7 +519,12 @@ func (s *QuizService) DeleteQuiz(quizID uint) error {
 		return errors.New("cannot delete an active quiz")
 	}
 
-	return s.quizRepo.Delete(quizID)
+	if err := s.quizRepo.Delete(quizID); err != nil {
+		return err
+	}
+	s.InvalidateQuiz(quizID)
+	s.invalidateQuizListings()
+	return nil
 }
 
 // GetQuestionsByQuizID возвращает все вопросы для викторины
@@ -299,6 +573,20 @@ func (s *QuizService) DuplicateQuiz(originalQuizID uint, newScheduledTime time.T
 		PrizeFund:           originalQuiz.PrizeFund, // Копируем призовой фонд из оригинала
 		FinishOnZeroPlayers: originalQuiz.FinishOnZeroPlayers,
 		QuestionSourceMode:  originalQuiz.QuestionSourceMode,
+		SecondChanceMode:    originalQuiz.SecondChanceMode,
+		SecondChanceCost:    originalQuiz.SecondChanceCost,
+		SecondChanceAdID:    originalQuiz.SecondChanceAdID,
+		CategoryID:          originalQuiz.CategoryID,
+		Timing:              originalQuiz.Timing,
+		GameMode:            originalQuiz.GameMode,
+		Lives:               originalQuiz.Lives,
+		SuddenDeath:         originalQuiz.SuddenDeath,
+		TieBreak:            originalQuiz.TieBreak,
+		ScoringStrategy:     originalQuiz.ScoringStrategy,
+		RequireVerifiedKK:   originalQuiz.RequireVerifiedKK,
+		PrizeLadder:         originalQuiz.PrizeLadder,
+		AllowedCountries:    originalQuiz.AllowedCountries,
+		Sponsor:             originalQuiz.Sponsor,
 	}
 
 	// 5. Начать Транзакцию для атомарного создания викторины и вопросов
@@ -325,6 +613,13 @@ func (s *QuizService) DuplicateQuiz(originalQuizID uint, newScheduledTime time.T
 				Difficulty: origQuestion.Difficulty,
 				TextKK:     origQuestion.TextKK,
 				OptionsKK:  origQuestion.OptionsKK,
+				// Медиа разделяются с оригиналом: объект удаляется, когда на него не ссылается ни один вопрос
+				ImageKey: origQuestion.ImageKey,
+				AudioKey: origQuestion.AudioKey,
+				// Содержимое не меняется, поэтому результат проверки переносится в копию
+				ReviewStatus: origQuestion.ReviewStatus,
+				ReviewerID:   origQuestion.ReviewerID,
+				ReviewedAt:   origQuestion.ReviewedAt,
 				// IsUsed НЕ копируем — новый вопрос должен быть доступен для использования
 				// ID, CreatedAt, UpdatedAt будут установлены GORM
 			}
@@ -351,6 +646,7 @@ func (s *QuizService) DuplicateQuiz(originalQuizID uint, newScheduledTime time.T
 	}
 
 	log.Printf("[QuizService] Викторина ID=%d успешно дублирована с новым ID=%d на время %v", originalQuizID, newQuiz.ID, newScheduledTime)
+	s.invalidateQuizListings()
 	// Возвращаем новую викторину (без вопросов, т.к. GetWithQuestions не вызывался для нее)
 	return newQuiz, nil
 }
@@ -362,8 +658,9 @@ func (s *QuizService) BulkUploadQuestionPool(questions []entity.Question) error
 		return fmt.Errorf("%w: no questions provided", apperrors.ErrValidation)
 	}
 
-	// Проверяем все вопросы
+	// Проверяем все вопросы; в автовыбор они попадут после проверки
 	for i, q := range questions {
+		questions[i].ReviewStatus = entity.QuestionReviewDraft
 		if q.Difficulty < 1 || q.Difficulty > 5 {
 			return fmt.Errorf("%w: invalid difficulty %d for question #%d", apperrors.ErrValidation, q.Difficulty, i+1)
 		}
@@ -387,6 +684,11 @@ func (s *QuizService) GetPoolStats() (totalCount int64, availableCount int64, by
 	return s.questionRepo.GetPoolStats()
 }
 
+// GetCategoryPoolStats возвращает статистику вопросов пула одной категории
+func (s *QuizService) GetCategoryPoolStats(categoryID uint) (totalCount int64, availableCount int64, byDifficulty map[int]int64, err error) {
+	return s.questionRepo.GetCategoryPoolStats(categoryID)
+}
+
 // ResetPoolUsed сбрасывает флаг is_used для всех вопросов пула
 func (s *QuizService) ResetPoolUsed() (int64, error) {
 	count, err := s.questionRepo.ResetPoolUsed()
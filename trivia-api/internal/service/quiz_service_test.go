@@ -1,6 +1,7 @@
 package service
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -8,6 +9,8 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
 	"github.com/yourusername/trivia-api/internal/service/quizmanager"
 )
 
@@ -88,22 +91,43 @@ func (m *MockQuestionRepoForQuizService) CountByDifficulty(difficulty int) (int6
 }
 
 // Новые методы для гибридной адаптивной системы
-func (m *MockQuestionRepoForQuizService) GetQuizQuestionByDifficulty(quizID uint, difficulty int, excludeIDs []uint) (*entity.Question, error) {
-	args := m.Called(quizID, difficulty, excludeIDs)
+func (m *MockQuestionRepoForQuizService) GetQuizQuestionByDifficulty(quizID uint, difficulty int, excludeIDs []uint, verifiedKK bool) (*entity.Question, error) {
+	args := m.Called(quizID, difficulty, excludeIDs, verifiedKK)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*entity.Question), args.Error(1)
 }
 
-func (m *MockQuestionRepoForQuizService) GetPoolQuestionByDifficulty(difficulty int, excludeIDs []uint) (*entity.Question, error) {
-	args := m.Called(difficulty, excludeIDs)
+func (m *MockQuestionRepoForQuizService) GetPoolQuestionByDifficulty(difficulty int, excludeIDs []uint, verifiedKK bool) (*entity.Question, error) {
+	args := m.Called(difficulty, excludeIDs, verifiedKK)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*entity.Question), args.Error(1)
 }
 
+func (m *MockQuestionRepoForQuizService) GetCategoryPoolQuestionByDifficulty(categoryID uint, difficulty int, excludeIDs []uint, verifiedKK bool) (*entity.Question, error) {
+	args := m.Called(categoryID, difficulty, excludeIDs, verifiedKK)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Question), args.Error(1)
+}
+
+func (m *MockQuestionRepoForQuizService) GetPoolQuestionBySeed(difficulty int, seed uint64) (*entity.Question, error) {
+	args := m.Called(difficulty, seed)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Question), args.Error(1)
+}
+
+func (m *MockQuestionRepoForQuizService) GetCategoryPoolStats(categoryID uint) (int64, int64, map[int]int64, error) {
+	args := m.Called(categoryID)
+	return args.Get(0).(int64), args.Get(1).(int64), args.Get(2).(map[int]int64), args.Error(3)
+}
+
 func (m *MockQuestionRepoForQuizService) GetPoolStats() (int64, int64, map[int]int64, error) {
 	args := m.Called()
 	return args.Get(0).(int64), args.Get(1).(int64), args.Get(2).(map[int]int64), args.Error(3)
@@ -132,6 +156,19 @@ func (m *MockQuestionRepoForQuizService) GetQuizQuestionHistory(quizID uint) ([]
 	return args.Get(0).([]entity.QuizQuestionHistory), args.Error(1)
 }
 
+func (m *MockQuestionRepoForQuizService) CountMediaReferences(key string) (int64, error) {
+	args := m.Called(key)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQuestionRepoForQuizService) ExistingTextHashes(hashes []string) (map[string]bool, error) {
+	args := m.Called(hashes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]bool), args.Error(1)
+}
+
 // ============================================================================
 // createTestQuizService создаёт QuizService для тестирования
 // ============================================================================
@@ -363,3 +400,135 @@ func TestQuizService_DeleteQuiz_CannotDeleteActive(t *testing.T) {
 	assert.Contains(t, err.Error(), "active")
 	mockQuizRepo.AssertNotCalled(t, "Delete")
 }
+
+func TestQuizService_ConfigureTieBreak(t *testing.T) {
+	mockQuizRepo := new(MockQuizRepository)
+	mockQuizRepo.On("GetByID", uint(1)).Return(&entity.Quiz{ID: 1, Status: entity.QuizStatusInProgress}, nil)
+	mockQuizRepo.On("GetByID", uint(2)).Return(&entity.Quiz{ID: 2, Status: entity.QuizStatusCompleted}, nil)
+	mockQuizRepo.On("UpdateTieBreak", uint(1), entity.TieBreakResponseTime).Return(nil)
+	quizService := createTestQuizServiceWithMocks(mockQuizRepo, nil, getDefaultTestConfigForQuiz())
+
+	// Идущей викторине способ можно сменить: победители определяются при завершении
+	require.NoError(t, quizService.ConfigureTieBreak(1, entity.TieBreakResponseTime))
+
+	err := quizService.ConfigureTieBreak(2, entity.TieBreakResponseTime)
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+
+	err = quizService.ConfigureTieBreak(1, "coin_flip")
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	mockQuizRepo.AssertNumberOfCalls(t, "UpdateTieBreak", 1)
+}
+
+func TestQuizService_ConfigureScoringStrategy(t *testing.T) {
+	mockQuizRepo := new(MockQuizRepository)
+	mockQuizRepo.On("GetByID", uint(1)).Return(&entity.Quiz{ID: 1, Status: entity.QuizStatusScheduled}, nil)
+	mockQuizRepo.On("GetByID", uint(2)).Return(&entity.Quiz{ID: 2, Status: entity.QuizStatusInProgress}, nil)
+	mockQuizRepo.On("UpdateScoringStrategy", uint(1), entity.ScoringStreakMultiplier).Return(nil)
+	quizService := createTestQuizServiceWithMocks(mockQuizRepo, nil, getDefaultTestConfigForQuiz())
+
+	require.NoError(t, quizService.ConfigureScoringStrategy(1, entity.ScoringStreakMultiplier))
+
+	// Очки уже начисляются по ходу игры — сменить стратегию нельзя
+	err := quizService.ConfigureScoringStrategy(2, entity.ScoringStreakMultiplier)
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+
+	err = quizService.ConfigureScoringStrategy(1, "lucky_draw")
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	mockQuizRepo.AssertNumberOfCalls(t, "UpdateScoringStrategy", 1)
+}
+
+func TestQuizService_ConfigureKazakhRequirement(t *testing.T) {
+	mockQuizRepo := new(MockQuizRepository)
+	mockQuizRepo.On("GetByID", uint(1)).Return(&entity.Quiz{ID: 1, Status: entity.QuizStatusScheduled}, nil)
+	mockQuizRepo.On("GetByID", uint(2)).Return(&entity.Quiz{ID: 2, Status: entity.QuizStatusInProgress}, nil)
+	mockQuizRepo.On("GetByID", uint(3)).Return(&entity.Quiz{ID: 3, Status: entity.QuizStatusScheduled, RequireVerifiedKK: true}, nil)
+	mockQuizRepo.On("UpdateRequireVerifiedKK", uint(1), true).Return(nil)
+	quizService := createTestQuizServiceWithMocks(mockQuizRepo, nil, getDefaultTestConfigForQuiz())
+
+	require.NoError(t, quizService.ConfigureKazakhRequirement(1, true))
+	require.NoError(t, quizService.ConfigureKazakhRequirement(3, true), "без изменений — без записи")
+
+	// Вопросы уже выбираются по ходу игры
+	err := quizService.ConfigureKazakhRequirement(2, true)
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+	mockQuizRepo.AssertNumberOfCalls(t, "UpdateRequireVerifiedKK", 1)
+}
+
+func TestQuizService_ConfigureAllowedCountries(t *testing.T) {
+	mockQuizRepo := new(MockQuizRepository)
+	mockQuizRepo.On("GetByID", uint(1)).Return(&entity.Quiz{ID: 1, Status: entity.QuizStatusScheduled}, nil)
+	mockQuizRepo.On("GetByID", uint(2)).Return(&entity.Quiz{ID: 2, Status: entity.QuizStatusCompleted}, nil)
+	mockQuizRepo.On("UpdateAllowedCountries", uint(1), entity.StringArray{"KZ", "UZ"}).Return(nil)
+	quizService := createTestQuizServiceWithMocks(mockQuizRepo, nil, getDefaultTestConfigForQuiz())
+
+	countries, err := quizService.ConfigureAllowedCountries(1, []string{"kz", " UZ", "KZ"})
+	require.NoError(t, err)
+	assert.Equal(t, entity.StringArray{"KZ", "UZ"}, countries)
+
+	_, err = quizService.ConfigureAllowedCountries(1, []string{"Kazakhstan"})
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+
+	// Призы уже распределены по прежнему списку
+	_, err = quizService.ConfigureAllowedCountries(2, []string{"KZ"})
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+	mockQuizRepo.AssertNumberOfCalls(t, "UpdateAllowedCountries", 1)
+}
+
+// memoryQuizCache — CacheRepository в памяти для проверки кеша викторин
+type memoryQuizCache struct {
+	repository.CacheRepository
+	values map[string][]byte
+}
+
+func (m *memoryQuizCache) SetJSON(key string, value interface{}, expiration time.Duration) error {
+	data, err := json.Marshal(value)
+	m.values[key] = data
+	return err
+}
+
+func (m *memoryQuizCache) GetJSON(key string, dest interface{}) error {
+	data, ok := m.values[key]
+	if !ok {
+		return apperrors.ErrNotFound
+	}
+	return json.Unmarshal(data, dest)
+}
+
+func (m *memoryQuizCache) Delete(key string) error {
+	delete(m.values, key)
+	return nil
+}
+
+func TestQuizService_GetQuizWithQuestions_Cached(t *testing.T) {
+	mockQuizRepo := new(MockQuizRepository)
+	quizID := uint(7)
+	mockQuizRepo.On("GetWithQuestions", quizID).Return(&entity.Quiz{
+		ID:    quizID,
+		Title: "Кешируемая викторина",
+		Questions: []entity.Question{
+			{ID: 1, Text: "Q1", Options: entity.StringArray{"a", "b"}, CorrectOption: 1},
+			{ID: 2, Text: "Q2", Options: entity.StringArray{"a", "b", "c"}, CorrectOption: 2, IsUsed: true},
+		},
+	}, nil).Twice()
+
+	quizService := createTestQuizServiceWithMocks(mockQuizRepo, nil, getDefaultTestConfigForQuiz())
+	quizService.cacheRepo = &memoryQuizCache{values: map[string][]byte{}}
+
+	_, err := quizService.GetQuizWithQuestions(quizID)
+	require.NoError(t, err)
+
+	// Второе чтение — из кеша; поля с json:"-" восстанавливаются
+	quiz, err := quizService.GetQuizWithQuestions(quizID)
+	require.NoError(t, err)
+	require.Len(t, quiz.Questions, 2)
+	assert.Equal(t, 1, quiz.Questions[0].CorrectOption)
+	assert.Equal(t, 2, quiz.Questions[1].CorrectOption)
+	assert.True(t, quiz.Questions[1].IsUsed)
+	mockQuizRepo.AssertNumberOfCalls(t, "GetWithQuestions", 1)
+
+	// После инвалидации викторина читается из БД заново
+	quizService.InvalidateQuiz(quizID)
+	_, err = quizService.GetQuizWithQuestions(quizID)
+	require.NoError(t, err)
+	mockQuizRepo.AssertNumberOfCalls(t, "GetWithQuestions", 2)
+}
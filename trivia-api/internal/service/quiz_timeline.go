@@ -0,0 +1,248 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// quizTimelineCacheTTL — срок хранения хроники завершённой викторины в кеше: ответы после
+// завершения не меняются, поэтому хроника считается один раз
+const quizTimelineCacheTTL = 30 * 24 * time.Hour
+
+// QuizTimeline — хроника завершённой викторины для экрана «как прошла игра»
+type QuizTimeline struct {
+	QuizID          uint                   `json:"quiz_id"`
+	Title           string                 `json:"title"`
+	Participants    int                    `json:"participants"` // игроков, ответивших хотя бы на один вопрос
+	Questions       []QuizTimelineQuestion `json:"questions"`
+	HardestQuestion *int                   `json:"hardest_question,omitempty"` // номер вопроса с наименьшей долей верных ответов
+	FastestAnswer   *QuizTimelineAnswer    `json:"fastest_answer,omitempty"`   // самый быстрый верный ответ викторины
+	GeneratedAt     time.Time              `json:"generated_at"`
+}
+
+// QuizTimelineQuestion — агрегаты ответов на один заданный вопрос. Учитываются только игроки,
+// не выбывшие до этого вопроса; пропуск вопроса считается ответом без варианта.
+type QuizTimelineQuestion struct {
+	Number         int                 `json:"number"`
+	QuestionID     uint                `json:"question_id"`
+	Text           string              `json:"text"`
+	TextKK         string              `json:"text_kk,omitempty"`
+	Difficulty     int                 `json:"difficulty"`
+	AskedAt        *time.Time          `json:"asked_at,omitempty"`
+	Answered       int                 `json:"answered"`
+	Correct        int                 `json:"correct"`
+	PassRate       float64             `json:"pass_rate"` // доля верных ответов, 0–1
+	Eliminated     int                 `json:"eliminated"`
+	Survivors      int                 `json:"survivors"` // осталось в игре после вопроса
+	AvgResponseMs  float64             `json:"avg_response_ms"`
+	FastestCorrect *QuizTimelineAnswer `json:"fastest_correct,omitempty"`
+	Voided         bool                `json:"voided,omitempty"` // вопрос снят с эфира, ответы аннулированы
+}
+
+// QuizTimelineAnswer — верный ответ игрока с временем ответа
+type QuizTimelineAnswer struct {
+	QuestionNumber int    `json:"question_number,omitempty"`
+	UserID         uint   `json:"user_id"`
+	Username       string `json:"username"`
+	ResponseTimeMs int64  `json:"response_time_ms"`
+	Anonymous      bool   `json:"anonymous,omitempty"` // игрок скрыт из публичных результатов
+}
+
+// GetQuizTimeline возвращает хронику завершённой викторины по вопросам: сколько игроков ответило,
+// ответило верно, выбыло и осталось, самый быстрый верный ответ и самый трудный вопрос. Хроника
+// собирается из user_answers и истории заданных вопросов при первом запросе и кешируется;
+// настройки приватности игроков применяются при каждом чтении.
+func (s *ResultService) GetQuizTimeline(quizID uint) (*QuizTimeline, error) {
+	quiz, err := s.quizRepo.GetByID(quizID)
+	if err != nil {
+		return nil, err
+	}
+	if !quiz.IsCompleted() {
+		return nil, fmt.Errorf("%w: quiz timeline is available after the quiz is completed", apperrors.ErrConflict)
+	}
+
+	cacheKey := quizTimelineCacheKey(quizID)
+	if s.cacheRepo != nil {
+		var cached QuizTimeline
+		err := s.cacheRepo.GetJSON(cacheKey, &cached)
+		if err == nil {
+			return &cached, s.applyTimelinePrivacy(&cached)
+		}
+		if !errors.Is(err, apperrors.ErrNotFound) {
+			log.Printf("[ResultService] Ошибка чтения хроники викторины #%d из кеша: %v", quizID, err)
+		}
+	}
+
+	history, err := s.questionRepo.GetQuizQuestionHistory(quizID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quiz question history: %w", err)
+	}
+	answers, err := s.resultRepo.GetQuizUserAnswers(quizID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quiz answers: %w", err)
+	}
+
+	timeline := buildQuizTimeline(history, answers)
+	timeline.QuizID = quiz.ID
+	timeline.Title = quiz.Title
+	if err := s.describeTimeline(timeline); err != nil {
+		return nil, err
+	}
+
+	if s.cacheRepo != nil {
+		if err := s.cacheRepo.SetJSON(cacheKey, timeline, quizTimelineCacheTTL); err != nil {
+			log.Printf("[ResultService] Ошибка сохранения хроники викторины #%d в кеш: %v", quizID, err)
+		}
+	}
+	return timeline, s.applyTimelinePrivacy(timeline)
+}
+
+// describeTimeline дополняет хронику текстами вопросов и именами игроков
+func (s *ResultService) describeTimeline(timeline *QuizTimeline) error {
+	var userIDs []uint
+	for i := range timeline.Questions {
+		q := &timeline.Questions[i]
+		question, err := s.questionRepo.GetByID(q.QuestionID)
+		if err != nil {
+			log.Printf("[ResultService] WARNING: Не удалось загрузить вопрос #%d для хроники: %v", q.QuestionID, err)
+		} else if question != nil {
+			q.Text = question.Text
+			q.TextKK = question.TextKK
+			q.Difficulty = question.Difficulty
+		}
+		if q.FastestCorrect != nil {
+			userIDs = append(userIDs, q.FastestCorrect.UserID)
+		}
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	users, err := s.userRepo.GetByIDs(userIDs)
+	if err != nil {
+		return fmt.Errorf("failed to load timeline players: %w", err)
+	}
+	usernames := make(map[uint]string, len(users))
+	for _, u := range users {
+		usernames[u.ID] = u.Username
+	}
+	for i := range timeline.Questions {
+		if fastest := timeline.Questions[i].FastestCorrect; fastest != nil {
+			fastest.Username = usernames[fastest.UserID]
+		}
+	}
+	if timeline.FastestAnswer != nil {
+		timeline.FastestAnswer.Username = usernames[timeline.FastestAnswer.UserID]
+	}
+	return nil
+}
+
+// buildQuizTimeline считает агрегаты по вопросам в порядке их показа. Без истории показа
+// (старые викторины) порядок восстанавливается по времени первого ответа на вопрос.
+func buildQuizTimeline(history []entity.QuizQuestionHistory, answers []entity.UserAnswer) *QuizTimeline {
+	byQuestion := make(map[uint][]entity.UserAnswer)
+	firstAnswerAt := make(map[uint]time.Time)
+	for _, a := range answers {
+		byQuestion[a.QuestionID] = append(byQuestion[a.QuestionID], a)
+		if first, ok := firstAnswerAt[a.QuestionID]; !ok || a.CreatedAt.Before(first) {
+			firstAnswerAt[a.QuestionID] = a.CreatedAt
+		}
+	}
+
+	if len(history) == 0 {
+		for questionID := range byQuestion {
+			history = append(history, entity.QuizQuestionHistory{QuestionID: questionID})
+		}
+		sort.Slice(history, func(i, j int) bool {
+			a, b := history[i].QuestionID, history[j].QuestionID
+			if !firstAnswerAt[a].Equal(firstAnswerAt[b]) {
+				return firstAnswerAt[a].Before(firstAnswerAt[b])
+			}
+			return a < b
+		})
+		for i := range history {
+			history[i].QuestionOrder = i + 1
+		}
+	}
+
+	timeline := &QuizTimeline{
+		Questions:   make([]QuizTimelineQuestion, 0, len(history)),
+		GeneratedAt: time.Now().UTC(),
+	}
+	eliminated := make(map[uint]bool)
+	participants := make(map[uint]bool)
+	hardestRate := 2.0
+	for _, h := range history {
+		q := QuizTimelineQuestion{Number: h.QuestionOrder, QuestionID: h.QuestionID}
+		if !h.AskedAt.IsZero() {
+			askedAt := h.AskedAt
+			q.AskedAt = &askedAt
+		}
+
+		var totalResponseMs int64
+		var timed int
+		var newlyEliminated []uint
+		for _, a := range byQuestion[h.QuestionID] {
+			if eliminated[a.UserID] {
+				continue
+			}
+			participants[a.UserID] = true
+			q.Answered++
+			if a.EliminationReason == entity.AnswerReasonQuestionVoided {
+				q.Voided = true
+			}
+			if a.ResponseTimeMs > 0 {
+				totalResponseMs += a.ResponseTimeMs
+				timed++
+			}
+			if a.IsEliminated {
+				q.Eliminated++
+				newlyEliminated = append(newlyEliminated, a.UserID)
+			}
+			if !a.IsCorrect {
+				continue
+			}
+			q.Correct++
+			if q.FastestCorrect == nil || a.ResponseTimeMs < q.FastestCorrect.ResponseTimeMs ||
+				(a.ResponseTimeMs == q.FastestCorrect.ResponseTimeMs && a.UserID < q.FastestCorrect.UserID) {
+				q.FastestCorrect = &QuizTimelineAnswer{UserID: a.UserID, ResponseTimeMs: a.ResponseTimeMs}
+			}
+		}
+		for _, userID := range newlyEliminated {
+			eliminated[userID] = true
+		}
+		q.Survivors = q.Answered - q.Eliminated
+		if q.Answered > 0 {
+			q.PassRate = float64(q.Correct) / float64(q.Answered)
+		}
+		if timed > 0 {
+			q.AvgResponseMs = float64(totalResponseMs) / float64(timed)
+		}
+
+		// Аннулированный вопрос не участвует в выборе самого трудного и самого быстрого ответа
+		if !q.Voided && q.Answered > 0 && q.PassRate < hardestRate {
+			hardestRate = q.PassRate
+			number := q.Number
+			timeline.HardestQuestion = &number
+		}
+		if fastest := q.FastestCorrect; fastest != nil && !q.Voided &&
+			(timeline.FastestAnswer == nil || fastest.ResponseTimeMs < timeline.FastestAnswer.ResponseTimeMs) {
+			overall := *fastest
+			overall.QuestionNumber = q.Number
+			timeline.FastestAnswer = &overall
+		}
+		timeline.Questions = append(timeline.Questions, q)
+	}
+	timeline.Participants = len(participants)
+	return timeline
+}
+
+func quizTimelineCacheKey(quizID uint) string {
+	return fmt.Sprintf("quiz:%d:timeline", quizID)
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+func timelineAnswer(userID, questionID uint, correct bool, responseMs int64, eliminated bool) entity.UserAnswer {
+	a := entity.UserAnswer{UserID: userID, QuestionID: questionID, IsCorrect: correct, ResponseTimeMs: responseMs, IsEliminated: eliminated}
+	if eliminated {
+		a.EliminationReason = "incorrect_answer"
+	}
+	return a
+}
+
+func TestBuildQuizTimeline(t *testing.T) {
+	askedAt := time.Date(2026, 10, 16, 18, 0, 0, 0, time.UTC)
+	history := []entity.QuizQuestionHistory{
+		{QuestionID: 11, QuestionOrder: 1, AskedAt: askedAt},
+		{QuestionID: 12, QuestionOrder: 2},
+		{QuestionID: 13, QuestionOrder: 3},
+		{QuestionID: 14, QuestionOrder: 4},
+	}
+	answers := []entity.UserAnswer{
+		timelineAnswer(1, 11, true, 1500, false),
+		timelineAnswer(2, 11, true, 900, false),
+		timelineAnswer(3, 11, false, 4000, true),
+		timelineAnswer(4, 11, true, 900, false),
+		// Выбывший на первом вопросе игрок продолжает отвечать — не учитывается
+		timelineAnswer(3, 12, true, 300, false),
+		timelineAnswer(1, 12, true, 2000, false),
+		timelineAnswer(2, 12, false, 0, true),
+		timelineAnswer(4, 12, false, 2500, true),
+		{UserID: 1, QuestionID: 13, EliminationReason: entity.AnswerReasonQuestionVoided, ResponseTimeMs: 100},
+		timelineAnswer(1, 14, true, 700, false),
+	}
+
+	timeline := buildQuizTimeline(history, answers)
+	require.Len(t, timeline.Questions, 4)
+	assert.Equal(t, 4, timeline.Participants)
+
+	q1 := timeline.Questions[0]
+	assert.Equal(t, 4, q1.Answered)
+	assert.Equal(t, 3, q1.Correct)
+	assert.Equal(t, 1, q1.Eliminated)
+	assert.Equal(t, 3, q1.Survivors)
+	assert.InDelta(t, 0.75, q1.PassRate, 1e-9)
+	assert.InDelta(t, 1825.0, q1.AvgResponseMs, 1e-9)
+	require.NotNil(t, q1.FastestCorrect)
+	assert.Equal(t, uint(2), q1.FastestCorrect.UserID, "при равном времени — меньший ID")
+	require.NotNil(t, q1.AskedAt)
+	assert.Equal(t, askedAt, *q1.AskedAt)
+
+	q2 := timeline.Questions[1]
+	assert.Equal(t, 3, q2.Answered)
+	assert.Equal(t, 1, q2.Correct)
+	assert.Equal(t, 1, q2.Survivors)
+	assert.Equal(t, uint(1), q2.FastestCorrect.UserID)
+	assert.InDelta(t, 2250.0, q2.AvgResponseMs, 1e-9, "пропуск без времени ответа не учитывается")
+	assert.Nil(t, q2.AskedAt)
+
+	q3 := timeline.Questions[2]
+	assert.True(t, q3.Voided)
+	assert.Equal(t, 0, q3.Correct)
+	assert.Equal(t, 1, q3.Survivors)
+
+	// Аннулированный вопрос не считается самым трудным
+	require.NotNil(t, timeline.HardestQuestion)
+	assert.Equal(t, 2, *timeline.HardestQuestion)
+	require.NotNil(t, timeline.FastestAnswer)
+	assert.Equal(t, QuizTimelineAnswer{QuestionNumber: 4, UserID: 1, ResponseTimeMs: 700}, *timeline.FastestAnswer)
+}
+
+func TestBuildQuizTimeline_WithoutHistory(t *testing.T) {
+	start := time.Date(2026, 10, 16, 18, 0, 0, 0, time.UTC)
+	answers := []entity.UserAnswer{
+		{UserID: 1, QuestionID: 30, IsCorrect: true, ResponseTimeMs: 800, CreatedAt: start.Add(time.Minute)},
+		{UserID: 1, QuestionID: 40, IsCorrect: true, ResponseTimeMs: 600, CreatedAt: start},
+		{UserID: 2, QuestionID: 40, IsCorrect: true, ResponseTimeMs: 500, CreatedAt: start.Add(time.Second)},
+	}
+
+	timeline := buildQuizTimeline(nil, answers)
+	require.Len(t, timeline.Questions, 2)
+	assert.Equal(t, uint(40), timeline.Questions[0].QuestionID, "порядок — по первому ответу")
+	assert.Equal(t, 1, timeline.Questions[0].Number)
+	assert.Equal(t, uint(30), timeline.Questions[1].QuestionID)
+	assert.Equal(t, 2, timeline.Questions[1].Number)
+
+	empty := buildQuizTimeline(nil, nil)
+	assert.Empty(t, empty.Questions)
+	assert.Nil(t, empty.HardestQuestion)
+	assert.Nil(t, empty.FastestAnswer)
+}
@@ -28,12 +28,17 @@ func NewAdaptiveQuestionSelector(config *DifficultyConfig, deps *Dependencies) *
 // SelectNextQuestion выбирает следующий вопрос на основе статистики предыдущих
 // questionNumber — номер вопроса (1-indexed)
 // usedQuestionIDs — ID уже использованных вопросов в текущей викторине
+// categoryID — категория тематической викторины: вопросы пула этой категории берутся
+// на любой сложности раньше остального пула (nil — без предпочтения)
+// verifiedKK — брать только вопросы с проверенным казахским текстом, без непроверенного машинного перевода
 func (s *AdaptiveQuestionSelector) SelectNextQuestion(
 	ctx context.Context,
 	quizID uint,
 	questionNumber int,
 	usedQuestionIDs []uint,
 	allowPool bool,
+	categoryID *uint,
+	verifiedKK bool,
 ) (*entity.Question, error) {
 	// 1. Получаем actual pass rate предыдущего вопроса
 	actualPassRate := s.getActualPassRate(quizID, questionNumber-1)
@@ -44,15 +49,19 @@ func (s *AdaptiveQuestionSelector) SelectNextQuestion(
 	log.Printf("[AdaptiveSelector] Quiz #%d, Q%d: prev_pass_rate=%.2f, target_difficulty=%d",
 		quizID, questionNumber, actualPassRate, targetDifficulty)
 
-	// 3. Пытаемся найти вопрос нужной сложности (гибридная логика)
-	question, err := s.findQuestionByDifficultyHybrid(quizID, targetDifficulty, usedQuestionIDs, allowPool)
-	if err != nil {
-		log.Printf("[AdaptiveSelector] Error finding question at difficulty %d: %v", targetDifficulty, err)
+	// 3. Тематическая викторина: сначала вопросы викторины и пул её категории
+	var question *entity.Question
+	var err error
+	if allowPool && categoryID != nil {
+		question, _ = s.findQuestion(quizID, targetDifficulty, usedQuestionIDs, poolScope{allowed: true, categoryID: categoryID, verifiedKK: verifiedKK})
+		if question == nil {
+			log.Printf("[AdaptiveSelector] Category %d pool exhausted for quiz %d, using the whole pool", *categoryID, quizID)
+		}
 	}
 
-	// 4. Если не нашли — fallback на другие уровни
+	// 4. Пытаемся найти вопрос нужной сложности (гибридная логика) с fallback на другие уровни
 	if question == nil {
-		question, err = s.findQuestionWithFallbackHybrid(quizID, targetDifficulty, usedQuestionIDs, allowPool)
+		question, err = s.findQuestion(quizID, targetDifficulty, usedQuestionIDs, poolScope{allowed: allowPool, verifiedKK: verifiedKK})
 		if err != nil {
 			return nil, fmt.Errorf("failed to find question with fallback: %w", err)
 		}
@@ -69,6 +78,97 @@ func (s *AdaptiveQuestionSelector) SelectNextQuestion(
 	return question, nil
 }
 
+// PreviewQuestions выбирает вопросы для предпросмотра викторины тем же поиском, что и SelectNextQuestion,
+// но без статистики из Redis: pass rate каждого вопроса считается равным целевому, поэтому
+// сложность идёт по базовой схеме. Выбор случайный среди подходящих — это вероятный, а не точный набор.
+// Номерам, для которых вопрос не найден, соответствует nil.
+func (s *AdaptiveQuestionSelector) PreviewQuestions(quizID uint, totalQuestions int, allowPool bool, categoryID *uint, verifiedKK bool) []*entity.Question {
+	picks := make([]*entity.Question, 0, totalQuestions)
+	usedQuestionIDs := make([]uint, 0, totalQuestions)
+	for i := 1; i <= totalQuestions; i++ {
+		targetDifficulty := s.config.GetBaseDifficulty(i)
+		var question *entity.Question
+		if allowPool && categoryID != nil {
+			question, _ = s.findQuestion(quizID, targetDifficulty, usedQuestionIDs, poolScope{allowed: true, categoryID: categoryID, verifiedKK: verifiedKK})
+		}
+		if question == nil {
+			question, _ = s.findQuestion(quizID, targetDifficulty, usedQuestionIDs, poolScope{allowed: allowPool, verifiedKK: verifiedKK})
+		}
+		if question != nil {
+			usedQuestionIDs = append(usedQuestionIDs, question.ID)
+		}
+		picks = append(picks, question)
+	}
+	return picks
+}
+
+// SelectDailyQuestion выбирает вопрос дня из общего пула. Дни идут по кругу базовой схемы
+// сложности: day — номер дня, вопрос дня — вопрос номер day % len + 1 воображаемой викторины,
+// а previousPassRate (доля верных ответов на вопрос предыдущего дня, -1 — нет данных) сдвигает
+// сложность так же, как pass rate предыдущего вопроса в викторине. Вопрос выбирается по seed,
+// а не случайно, поэтому все инстансы выбирают один и тот же вопрос.
+func (s *AdaptiveQuestionSelector) SelectDailyQuestion(day int, seed uint64, previousPassRate float64) (*entity.Question, error) {
+	questionNumber := 1
+	if len(s.config.BaseDifficultyMap) > 0 {
+		questionNumber = day%len(s.config.BaseDifficultyMap) + 1
+	}
+	targetDifficulty := s.config.CalculateAdjustedDifficulty(questionNumber, previousPassRate)
+
+	for _, diff := range s.difficultySearchOrder(targetDifficulty) {
+		question, err := s.deps.QuestionRepo.GetPoolQuestionBySeed(diff, seed)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find daily question: %w", err)
+		}
+		if question != nil {
+			log.Printf("[AdaptiveSelector] Daily question ID=%d, difficulty=%d (target %d, prev_pass_rate=%.2f)",
+				question.ID, question.Difficulty, targetDifficulty, previousPassRate)
+			return question, nil
+		}
+	}
+	return nil, fmt.Errorf("no pool questions available for the daily challenge")
+}
+
+// difficultySearchOrder возвращает уровни сложности в порядке поиска: сначала целевой,
+// затем по FallbackToHigher в одну сторону и потом в другую
+func (s *AdaptiveQuestionSelector) difficultySearchOrder(targetDifficulty int) []int {
+	var searchOrder []int
+	if s.config.FallbackToHigher {
+		for diff := targetDifficulty; diff <= s.config.MaxDifficulty; diff++ {
+			searchOrder = append(searchOrder, diff)
+		}
+		for diff := targetDifficulty - 1; diff >= s.config.MinDifficulty; diff-- {
+			searchOrder = append(searchOrder, diff)
+		}
+	} else {
+		for diff := targetDifficulty; diff >= s.config.MinDifficulty; diff-- {
+			searchOrder = append(searchOrder, diff)
+		}
+		for diff := targetDifficulty + 1; diff <= s.config.MaxDifficulty; diff++ {
+			searchOrder = append(searchOrder, diff)
+		}
+	}
+	return searchOrder
+}
+
+// poolScope определяет, из какой части общего пула можно брать вопросы
+type poolScope struct {
+	allowed    bool  // false — только вопросы викторины (admin_only)
+	categoryID *uint // nil — весь пул, иначе только вопросы категории
+	verifiedKK bool  // только вопросы с проверенным казахским текстом (в т.ч. вопросы викторины)
+}
+
+// findQuestion ищет вопрос целевой сложности, а если его нет — на соседних уровнях
+func (s *AdaptiveQuestionSelector) findQuestion(quizID uint, targetDifficulty int, excludeIDs []uint, pool poolScope) (*entity.Question, error) {
+	question, err := s.findQuestionByDifficultyHybrid(quizID, targetDifficulty, excludeIDs, pool)
+	if err != nil {
+		log.Printf("[AdaptiveSelector] Error finding question at difficulty %d: %v", targetDifficulty, err)
+	}
+	if question != nil {
+		return question, nil
+	}
+	return s.findQuestionWithFallbackHybrid(quizID, targetDifficulty, excludeIDs, pool)
+}
+
 // getActualPassRate получает реальный pass rate из Redis.
 // Возвращает:
 //
@@ -114,20 +214,24 @@ func (s *AdaptiveQuestionSelector) getActualPassRate(quizID uint, questionNumber
 }
 
 // findQuestionByDifficultyHybrid ищет вопрос гибридно: сначала в викторине, затем в пуле
-func (s *AdaptiveQuestionSelector) findQuestionByDifficultyHybrid(quizID uint, difficulty int, excludeIDs []uint, allowPool bool) (*entity.Question, error) {
+func (s *AdaptiveQuestionSelector) findQuestionByDifficultyHybrid(quizID uint, difficulty int, excludeIDs []uint, pool poolScope) (*entity.Question, error) {
 	// 1. Сначала ищем вопрос, привязанный к данной викторине
-	question, err := s.deps.QuestionRepo.GetQuizQuestionByDifficulty(quizID, difficulty, excludeIDs)
+	question, err := s.deps.QuestionRepo.GetQuizQuestionByDifficulty(quizID, difficulty, excludeIDs, pool.verifiedKK)
 	if err == nil && question != nil {
 		log.Printf("[AdaptiveSelector] Found quiz-specific question ID=%d for quiz %d", question.ID, quizID)
 		return question, nil
 	}
 
-	if !allowPool {
+	if !pool.allowed {
 		return nil, nil
 	}
 
-	// 2. Если не нашли — ищем в общем пуле
-	question, err = s.deps.QuestionRepo.GetPoolQuestionByDifficulty(difficulty, excludeIDs)
+	// 2. Если не нашли — ищем в общем пуле (или в его части одной категории)
+	if pool.categoryID != nil {
+		question, err = s.deps.QuestionRepo.GetCategoryPoolQuestionByDifficulty(*pool.categoryID, difficulty, excludeIDs, pool.verifiedKK)
+	} else {
+		question, err = s.deps.QuestionRepo.GetPoolQuestionByDifficulty(difficulty, excludeIDs, pool.verifiedKK)
+	}
 	if err == nil && question != nil {
 		log.Printf("[AdaptiveSelector] Found pool question ID=%d (difficulty=%d)", question.ID, difficulty)
 		return question, nil
@@ -137,29 +241,9 @@ func (s *AdaptiveQuestionSelector) findQuestionByDifficultyHybrid(quizID uint, d
 }
 
 // findQuestionWithFallbackHybrid ищет вопрос с fallback на другие уровни (гибридная логика)
-func (s *AdaptiveQuestionSelector) findQuestionWithFallbackHybrid(quizID uint, targetDifficulty int, excludeIDs []uint, allowPool bool) (*entity.Question, error) {
-	var searchOrder []int
-
-	if s.config.FallbackToHigher {
-		// Сначала вверх (сложнее), потом вниз (легче)
-		for diff := targetDifficulty; diff <= s.config.MaxDifficulty; diff++ {
-			searchOrder = append(searchOrder, diff)
-		}
-		for diff := targetDifficulty - 1; diff >= s.config.MinDifficulty; diff-- {
-			searchOrder = append(searchOrder, diff)
-		}
-	} else {
-		// Сначала вниз (легче), потом вверх (сложнее)
-		for diff := targetDifficulty; diff >= s.config.MinDifficulty; diff-- {
-			searchOrder = append(searchOrder, diff)
-		}
-		for diff := targetDifficulty + 1; diff <= s.config.MaxDifficulty; diff++ {
-			searchOrder = append(searchOrder, diff)
-		}
-	}
-
-	for _, diff := range searchOrder {
-		q, err := s.findQuestionByDifficultyHybrid(quizID, diff, excludeIDs, allowPool)
+func (s *AdaptiveQuestionSelector) findQuestionWithFallbackHybrid(quizID uint, targetDifficulty int, excludeIDs []uint, pool poolScope) (*entity.Question, error) {
+	for _, diff := range s.difficultySearchOrder(targetDifficulty) {
+		q, err := s.findQuestionByDifficultyHybrid(quizID, diff, excludeIDs, pool)
 		if err == nil && q != nil {
 			if diff != targetDifficulty {
 				log.Printf("[AdaptiveSelector] Fallback: found question at difficulty=%d (target was %d)", diff, targetDifficulty)
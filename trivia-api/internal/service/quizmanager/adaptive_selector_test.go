@@ -1,9 +1,12 @@
 package quizmanager
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
 	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
 )
 
@@ -161,3 +164,102 @@ func TestCalculateAdjustedDifficulty_LowPassRate(t *testing.T) {
 	assert.Equal(t, expected, result,
 		"Слишком низкий pass rate должен уменьшить сложность на 1")
 }
+
+// ============================================================================
+// Тесты выбора вопросов тематической викторины (категория пула)
+// ============================================================================
+
+// TestSelectNextQuestion_PrefersCategoryPoolAcrossDifficulties — вопрос категории на соседней
+// сложности выбирается раньше вопроса общего пула на целевой
+func TestSelectNextQuestion_PrefersCategoryPoolAcrossDifficulties(t *testing.T) {
+	config := DefaultDifficultyConfig()
+	target := config.CalculateAdjustedDifficulty(1, 1.0)
+	other := target + 1
+	if other > config.MaxDifficulty {
+		other = target - 1
+	}
+	categoryID := uint(3)
+	themed := &entity.Question{ID: 42, Difficulty: other, CategoryID: &categoryID}
+
+	repo := new(MockQuestionRepoForScheduler)
+	repo.On("GetQuizQuestionByDifficulty", uint(7), mock.Anything, mock.Anything, false).Return(nil, nil)
+	repo.On("GetCategoryPoolQuestionByDifficulty", categoryID, other, mock.Anything, false).Return(themed, nil)
+	repo.On("GetCategoryPoolQuestionByDifficulty", categoryID, mock.Anything, mock.Anything, false).Return(nil, nil)
+
+	selector := NewAdaptiveQuestionSelector(config, &Dependencies{QuestionRepo: repo})
+	question, err := selector.SelectNextQuestion(context.Background(), 7, 1, nil, true, &categoryID, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(42), question.ID)
+	repo.AssertNotCalled(t, "GetPoolQuestionByDifficulty", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestSelectNextQuestion_CategoryExhaustedFallsBackToPool — без вопросов категории берётся общий пул
+func TestSelectNextQuestion_CategoryExhaustedFallsBackToPool(t *testing.T) {
+	config := DefaultDifficultyConfig()
+	target := config.CalculateAdjustedDifficulty(1, 1.0)
+	categoryID := uint(3)
+	general := &entity.Question{ID: 9, Difficulty: target}
+
+	repo := new(MockQuestionRepoForScheduler)
+	repo.On("GetQuizQuestionByDifficulty", uint(7), mock.Anything, mock.Anything, false).Return(nil, nil)
+	repo.On("GetCategoryPoolQuestionByDifficulty", categoryID, mock.Anything, mock.Anything, false).Return(nil, nil)
+	repo.On("GetPoolQuestionByDifficulty", target, mock.Anything, false).Return(general, nil)
+
+	selector := NewAdaptiveQuestionSelector(config, &Dependencies{QuestionRepo: repo})
+	question, err := selector.SelectNextQuestion(context.Background(), 7, 1, nil, true, &categoryID, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(9), question.ID)
+}
+
+// TestSelectNextQuestion_AdminOnlyIgnoresCategory — admin_only не обращается к пулу даже у тематической викторины
+func TestSelectNextQuestion_AdminOnlyIgnoresCategory(t *testing.T) {
+	categoryID := uint(3)
+	repo := new(MockQuestionRepoForScheduler)
+	repo.On("GetQuizQuestionByDifficulty", uint(7), mock.Anything, mock.Anything, false).Return(nil, nil)
+
+	selector := NewAdaptiveQuestionSelector(DefaultDifficultyConfig(), &Dependencies{QuestionRepo: repo})
+	_, err := selector.SelectNextQuestion(context.Background(), 7, 1, nil, false, &categoryID, false)
+
+	assert.Error(t, err)
+	repo.AssertNotCalled(t, "GetCategoryPoolQuestionByDifficulty", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "GetPoolQuestionByDifficulty", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestSelectNextQuestion_VerifiedKKFiltersEveryLookup — викторина с проверенным kk ищет только такие вопросы
+func TestSelectNextQuestion_VerifiedKKFiltersEveryLookup(t *testing.T) {
+	config := DefaultDifficultyConfig()
+	target := config.CalculateAdjustedDifficulty(1, 1.0)
+	verified := &entity.Question{ID: 11, Difficulty: target, TextKK: "Тексерілген сұрақ"}
+
+	repo := new(MockQuestionRepoForScheduler)
+	repo.On("GetQuizQuestionByDifficulty", uint(7), mock.Anything, mock.Anything, true).Return(nil, nil)
+	repo.On("GetPoolQuestionByDifficulty", target, mock.Anything, true).Return(verified, nil)
+
+	selector := NewAdaptiveQuestionSelector(config, &Dependencies{QuestionRepo: repo})
+	question, err := selector.SelectNextQuestion(context.Background(), 7, 1, nil, true, nil, true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(11), question.ID)
+	repo.AssertNotCalled(t, "GetPoolQuestionByDifficulty", mock.Anything, mock.Anything, false)
+}
+
+// TestSelectDailyQuestion_FollowsDayAndPassRate — сложность вопроса дня идёт по базовой схеме дня
+// и сдвигается по доле верных ответов вчера; без вопросов этой сложности берётся сложнее
+func TestSelectDailyQuestion_FollowsDayAndPassRate(t *testing.T) {
+	config := DefaultDifficultyConfig()
+	day := 3 // вопрос дня — 4-й вопрос схемы
+	target := config.CalculateAdjustedDifficulty(4, 1.0)
+	assert.Greater(t, target, config.GetBaseDifficulty(4), "все ответили верно — вопрос сложнее")
+
+	repo := new(MockQuestionRepoForScheduler)
+	repo.On("GetPoolQuestionBySeed", target, uint64(42)).Return(nil, nil)
+	repo.On("GetPoolQuestionBySeed", target+1, uint64(42)).Return(&entity.Question{ID: 5, Difficulty: target + 1}, nil)
+
+	selector := NewAdaptiveQuestionSelector(config, &Dependencies{QuestionRepo: repo})
+	question, err := selector.SelectDailyQuestion(day, 42, 1.0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(5), question.ID)
+}
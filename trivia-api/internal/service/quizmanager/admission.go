@@ -0,0 +1,403 @@
+package quizmanager
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+const (
+	// admissionKeyTTL — сколько живут ключи зала ожидания (как и participants Set)
+	admissionKeyTTL = 24 * time.Hour
+	// admissionPositionUpdates — скольким первым в очереди рассылается обновлённая позиция
+	admissionPositionUpdates = 1000
+	// admissionStatusPreview — сколько первых в очереди показывается администратору
+	admissionStatusPreview = 50
+)
+
+// AdmittedKey — Redis Set игроков, занимающих место в викторине с ограниченной вместимостью.
+// Выбывшие освобождают место; вернувшиеся по второму шансу занимают его снова.
+func AdmittedKey(quizID uint) string {
+	return fmt.Sprintf("quiz:%d:admitted", quizID)
+}
+
+// admissionQueueKey — Sorted Set очереди допуска; вес — время постановки в очередь (unix ns)
+func admissionQueueKey(quizID uint) string {
+	return fmt.Sprintf("quiz:%d:admission_queue", quizID)
+}
+
+// admissionLeftKey отмечает игрока, отключившегося из зала ожидания или очереди
+func admissionLeftKey(quizID, userID uint) string {
+	return fmt.Sprintf("quiz:%d:admission_left:%d", quizID, userID)
+}
+
+// AdmissionEvents отправляет игрокам события зала ожидания (реализуется websocket.Manager)
+type AdmissionEvents interface {
+	SendEventToUser(userID string, eventType string, data interface{}) error
+}
+
+// AdmissionResult — итог попытки занять место в викторине
+type AdmissionResult struct {
+	Admitted    bool
+	Position    int64 // позиция в очереди (с 1), если место не досталось
+	QueueLength int64
+}
+
+// AdmissionStatus — состояние зала ожидания для администратора
+type AdmissionStatus struct {
+	QuizID      uint   `json:"quiz_id"`
+	MaxPlayers  int    `json:"max_players"` // 0 — без ограничения
+	Admitted    int64  `json:"admitted"`
+	QueueLength int64  `json:"queue_length"`
+	Queue       []uint `json:"queue"` // первые в очереди, по порядку
+}
+
+// AdmissionController ограничивает число одновременных игроков викторины (Quiz.MaxPlayers).
+// Сверх вместимости игроки ждут в очереди и получают позицию по WS (quiz:admission_queue);
+// места освобождаются при уходе из зала ожидания и при выбывании, и первые в очереди
+// допускаются (quiz:admitted). Во время игры очередь продвигается между вопросами, чтобы
+// допущенный не выбыл за вопрос, который не успел увидеть.
+type AdmissionController struct {
+	config *Config
+	deps   *Dependencies
+	events AdmissionEvents
+	// Проверка вместимости и впуск сериализуются на узле; между узлами лимит
+	// может быть превышен на число одновременных user:ready
+	mu sync.Mutex
+}
+
+// NewAdmissionController создает контроллер зала ожидания
+func NewAdmissionController(config *Config, deps *Dependencies) *AdmissionController {
+	a := &AdmissionController{config: config, deps: deps}
+	if deps.WSManager != nil {
+		a.events = deps.WSManager
+	}
+	return a
+}
+
+// Admit занимает место для нового участника или ставит его в очередь, если викторина заполнена
+func (a *AdmissionController) Admit(quiz *entity.Quiz, userID uint) (*AdmissionResult, error) {
+	admittedKey := AdmittedKey(quiz.ID)
+	if !quiz.HasCapacityLimit() {
+		if err := a.occupy(quiz.ID, userID); err != nil {
+			return nil, err
+		}
+		return &AdmissionResult{Admitted: true}, nil
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	admitted, err := a.deps.CacheRepo.SCard(admittedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count admitted players: %w", err)
+	}
+	if admitted < int64(quiz.MaxPlayers) {
+		if err := a.occupy(quiz.ID, userID); err != nil {
+			return nil, err
+		}
+		return &AdmissionResult{Admitted: true}, nil
+	}
+
+	queueKey := admissionQueueKey(quiz.ID)
+	if _, err := a.deps.CacheRepo.ZAddNX(queueKey, float64(time.Now().UnixNano()), userID); err != nil {
+		return nil, fmt.Errorf("failed to enqueue player: %w", err)
+	}
+	if err := a.deps.CacheRepo.Expire(queueKey, admissionKeyTTL); err != nil {
+		log.Printf("[Admission] WARNING: Не удалось установить TTL очереди викторины #%d: %v", quiz.ID, err)
+	}
+	rank, err := a.deps.CacheRepo.ZRank(queueKey, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get queue position: %w", err)
+	}
+	queueLength, err := a.deps.CacheRepo.ZCard(queueKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get queue length: %w", err)
+	}
+	result := &AdmissionResult{Position: rank + 1, QueueLength: queueLength}
+	log.Printf("[Admission] Викторина #%d заполнена (%d/%d): пользователь #%d в очереди на позиции %d",
+		quiz.ID, admitted, quiz.MaxPlayers, userID, result.Position)
+	a.sendPosition(quiz, userID, result.Position, queueLength)
+	return result, nil
+}
+
+// Rejoin снимает отметку об уходе: игрок переподключился и сохраняет место или позицию в очереди
+func (a *AdmissionController) Rejoin(quizID, userID uint) {
+	if err := a.deps.CacheRepo.Delete(admissionLeftKey(quizID, userID)); err != nil {
+		log.Printf("[Admission] WARNING: Не удалось снять отметку ухода пользователя #%d из викторины #%d: %v", userID, quizID, err)
+	}
+}
+
+// HandleLeave вызывается, когда клиент отписывается от викторины (отключение или выход).
+// Если игрок не вернулся за AdmissionLeaveGrace, он покидает очередь, а допущенный — освобождает
+// место, пока викторина не началась: во время игры место освобождает только выбывание.
+func (a *AdmissionController) HandleLeave(userIDStr string, quizID uint) {
+	parsed, err := strconv.ParseUint(userIDStr, 10, 64)
+	if err != nil {
+		return
+	}
+	userID := uint(parsed)
+
+	queued, err := a.deps.CacheRepo.ZRank(admissionQueueKey(quizID), userID)
+	if err != nil {
+		log.Printf("[Admission] WARNING: Не удалось проверить очередь викторины #%d: %v", quizID, err)
+		return
+	}
+	if queued < 0 {
+		admitted, err := a.deps.CacheRepo.SIsMember(AdmittedKey(quizID), userID)
+		if err != nil || !admitted {
+			return
+		}
+	}
+	grace := a.config.AdmissionLeaveGrace
+	if err := a.deps.CacheRepo.Set(admissionLeftKey(quizID, userID), "1", 2*grace); err != nil {
+		log.Printf("[Admission] WARNING: Не удалось отметить уход пользователя #%d из викторины #%d: %v", userID, quizID, err)
+		return
+	}
+	time.AfterFunc(grace, func() { a.releaseIfGone(quizID, userID) })
+}
+
+// releaseIfGone убирает не вернувшегося игрока из очереди или освобождает его место
+// в зале ожидания и впускает следующего
+func (a *AdmissionController) releaseIfGone(quizID, userID uint) {
+	leftKey := admissionLeftKey(quizID, userID)
+	gone, err := a.deps.CacheRepo.Exists(leftKey)
+	if err != nil || !gone {
+		return
+	}
+	_ = a.deps.CacheRepo.Delete(leftKey)
+
+	queueKey := admissionQueueKey(quizID)
+	if rank, err := a.deps.CacheRepo.ZRank(queueKey, userID); err == nil && rank >= 0 {
+		if err := a.deps.CacheRepo.ZRem(queueKey, userID); err != nil {
+			log.Printf("[Admission] WARNING: Не удалось убрать пользователя #%d из очереди викторины #%d: %v", userID, quizID, err)
+		}
+		return
+	}
+
+	quiz, err := a.deps.QuizRepo.GetByID(quizID)
+	if err != nil || quiz == nil || !quiz.IsScheduled() {
+		return
+	}
+	if err := a.deps.CacheRepo.SRem(AdmittedKey(quizID), userID); err != nil {
+		log.Printf("[Admission] WARNING: Не удалось освободить место пользователя #%d в викторине #%d: %v", userID, quizID, err)
+		return
+	}
+	if err := a.deps.CacheRepo.SRem(fmt.Sprintf("quiz:%d:participants", quizID), userID); err != nil {
+		log.Printf("[Admission] WARNING: Не удалось снять регистрацию пользователя #%d в викторине #%d: %v", userID, quizID, err)
+	}
+	_ = a.deps.CacheRepo.Delete(fmt.Sprintf("quiz:%d:ready_users:%d", quizID, userID))
+	log.Printf("[Admission] Пользователь #%d покинул зал ожидания викторины #%d, место освобождено", userID, quizID)
+
+	if _, err := a.AdmitNext(quizID); err != nil {
+		log.Printf("[Admission] WARNING: Не удалось впустить следующего из очереди викторины #%d: %v", quizID, err)
+	}
+}
+
+// Release освобождает места выбывших игроков. Следующие в очереди допускаются через AdmitNext.
+func (a *AdmissionController) Release(quizID uint, userIDs ...uint) {
+	if len(userIDs) == 0 {
+		return
+	}
+	members := make([]interface{}, len(userIDs))
+	for i, id := range userIDs {
+		members[i] = id
+	}
+	if err := a.deps.CacheRepo.SRem(AdmittedKey(quizID), members...); err != nil {
+		log.Printf("[Admission] WARNING: Не удалось освободить %d мест в викторине #%d: %v", len(userIDs), quizID, err)
+	}
+}
+
+// AdmitNext впускает первых в очереди на свободные места и возвращает число допущенных.
+// Если ограничение вместимости снято, впускается вся очередь.
+func (a *AdmissionController) AdmitNext(quizID uint) (int, error) {
+	queueKey := admissionQueueKey(quizID)
+	queueLength, err := a.deps.CacheRepo.ZCard(queueKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get queue length: %w", err)
+	}
+	if queueLength == 0 {
+		return 0, nil
+	}
+	quiz, err := a.deps.QuizRepo.GetByID(quizID)
+	if err != nil {
+		return 0, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	free := queueLength
+	if quiz.HasCapacityLimit() {
+		admitted, err := a.deps.CacheRepo.SCard(AdmittedKey(quizID))
+		if err != nil {
+			return 0, fmt.Errorf("failed to count admitted players: %w", err)
+		}
+		free = int64(quiz.MaxPlayers) - admitted
+	}
+	if free <= 0 {
+		return 0, nil
+	}
+	next, err := a.deps.CacheRepo.ZRange(queueKey, 0, free-1)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read admission queue: %w", err)
+	}
+
+	count := 0
+	for _, member := range next {
+		userID, parseErr := strconv.ParseUint(member, 10, 64)
+		if parseErr != nil {
+			_ = a.deps.CacheRepo.ZRem(queueKey, member)
+			continue
+		}
+		if err := a.admitQueued(quiz, uint(userID)); err != nil {
+			log.Printf("[Admission] WARNING: Не удалось впустить пользователя #%d в викторину #%d: %v", userID, quizID, err)
+			continue
+		}
+		count++
+	}
+	if count > 0 {
+		log.Printf("[Admission] Викторина #%d: из очереди допущено %d игроков", quizID, count)
+		a.notifyPositions(quiz)
+	}
+	return count, nil
+}
+
+// AdmitUser впускает игрока из очереди вне очереди и сверх вместимости (решение администратора)
+func (a *AdmissionController) AdmitUser(quizID, userID uint) error {
+	quiz, err := a.deps.QuizRepo.GetByID(quizID)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	rank, err := a.deps.CacheRepo.ZRank(admissionQueueKey(quizID), userID)
+	if err != nil {
+		return fmt.Errorf("failed to check admission queue: %w", err)
+	}
+	if rank < 0 {
+		return fmt.Errorf("%w: user #%d is not in the admission queue of quiz #%d", apperrors.ErrNotFound, userID, quizID)
+	}
+	if err := a.admitQueued(quiz, userID); err != nil {
+		return err
+	}
+	log.Printf("[Admission] Пользователь #%d допущен в викторину #%d администратором", userID, quizID)
+	a.notifyPositions(quiz)
+	return nil
+}
+
+// Status возвращает заполненность викторины и начало очереди
+func (a *AdmissionController) Status(quiz *entity.Quiz) (*AdmissionStatus, error) {
+	admitted, err := a.deps.CacheRepo.SCard(AdmittedKey(quiz.ID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count admitted players: %w", err)
+	}
+	queueKey := admissionQueueKey(quiz.ID)
+	queueLength, err := a.deps.CacheRepo.ZCard(queueKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get queue length: %w", err)
+	}
+	status := &AdmissionStatus{
+		QuizID:      quiz.ID,
+		MaxPlayers:  quiz.MaxPlayers,
+		Admitted:    admitted,
+		QueueLength: queueLength,
+		Queue:       []uint{},
+	}
+	if queueLength == 0 {
+		return status, nil
+	}
+	members, err := a.deps.CacheRepo.ZRange(queueKey, 0, admissionStatusPreview-1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read admission queue: %w", err)
+	}
+	for _, member := range members {
+		if userID, parseErr := strconv.ParseUint(member, 10, 64); parseErr == nil {
+			status.Queue = append(status.Queue, uint(userID))
+		}
+	}
+	return status, nil
+}
+
+// occupy записывает игрока в занявшие место
+func (a *AdmissionController) occupy(quizID, userID uint) error {
+	admittedKey := AdmittedKey(quizID)
+	if err := a.deps.CacheRepo.SAdd(admittedKey, userID); err != nil {
+		return fmt.Errorf("failed to admit player: %w", err)
+	}
+	if err := a.deps.CacheRepo.Expire(admittedKey, admissionKeyTTL); err != nil {
+		log.Printf("[Admission] WARNING: Не удалось установить TTL допущенных викторины #%d: %v", quizID, err)
+	}
+	return nil
+}
+
+// admitQueued переводит игрока из очереди в участники и сообщает ему об этом.
+// Вызывается под mu.
+func (a *AdmissionController) admitQueued(quiz *entity.Quiz, userID uint) error {
+	if err := a.occupy(quiz.ID, userID); err != nil {
+		return err
+	}
+	participantsKey := fmt.Sprintf("quiz:%d:participants", quiz.ID)
+	if err := a.deps.CacheRepo.SAdd(participantsKey, userID); err != nil {
+		return fmt.Errorf("failed to register participant: %w", err)
+	}
+	if err := a.deps.CacheRepo.Expire(participantsKey, admissionKeyTTL); err != nil {
+		log.Printf("[Admission] WARNING: Не удалось установить TTL на participants Set: %v", err)
+	}
+	if err := a.deps.CacheRepo.Set(fmt.Sprintf("quiz:%d:ready_users:%d", quiz.ID, userID), "1", time.Hour); err != nil {
+		log.Printf("[Admission] WARNING: Не удалось сохранить готовность пользователя #%d: %v", userID, err)
+	}
+	if err := a.deps.CacheRepo.ZRem(admissionQueueKey(quiz.ID), userID); err != nil {
+		log.Printf("[Admission] WARNING: Не удалось убрать пользователя #%d из очереди викторины #%d: %v", userID, quiz.ID, err)
+	}
+	if a.events != nil {
+		event := map[string]interface{}{"quiz_id": quiz.ID, "status": quiz.Status}
+		if err := a.events.SendEventToUser(strconv.FormatUint(uint64(userID), 10), "quiz:admitted", event); err != nil {
+			log.Printf("[Admission] Ошибка отправки quiz:admitted пользователю #%d: %v", userID, err)
+		}
+	}
+	return nil
+}
+
+// notifyPositions рассылает первым в очереди их новые позиции
+func (a *AdmissionController) notifyPositions(quiz *entity.Quiz) {
+	if a.events == nil {
+		return
+	}
+	queueKey := admissionQueueKey(quiz.ID)
+	members, err := a.deps.CacheRepo.ZRange(queueKey, 0, admissionPositionUpdates-1)
+	if err != nil || len(members) == 0 {
+		return
+	}
+	queueLength, err := a.deps.CacheRepo.ZCard(queueKey)
+	if err != nil {
+		return
+	}
+	for i, member := range members {
+		if userID, parseErr := strconv.ParseUint(member, 10, 64); parseErr == nil {
+			a.sendPosition(quiz, uint(userID), int64(i)+1, queueLength)
+		}
+	}
+}
+
+func (a *AdmissionController) sendPosition(quiz *entity.Quiz, userID uint, position, queueLength int64) {
+	if a.events == nil {
+		return
+	}
+	event := map[string]interface{}{
+		"quiz_id":      quiz.ID,
+		"position":     position,
+		"queue_length": queueLength,
+		"max_players":  quiz.MaxPlayers,
+	}
+	if err := a.events.SendEventToUser(strconv.FormatUint(uint64(userID), 10), "quiz:admission_queue", event); err != nil {
+		log.Printf("[Admission] Ошибка отправки позиции в очереди пользователю #%d: %v", userID, err)
+	}
+}
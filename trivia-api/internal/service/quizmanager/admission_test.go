@@ -0,0 +1,276 @@
+package quizmanager
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// memoryAdmissionCache — в памяти только те операции кеша, которые использует зал ожидания
+type memoryAdmissionCache struct {
+	repository.CacheRepository
+	mu     sync.Mutex
+	values map[string]string
+	sets   map[string]map[string]bool
+	zsets  map[string]map[string]float64
+}
+
+func newMemoryAdmissionCache() *memoryAdmissionCache {
+	return &memoryAdmissionCache{
+		values: map[string]string{},
+		sets:   map[string]map[string]bool{},
+		zsets:  map[string]map[string]float64{},
+	}
+}
+
+func (c *memoryAdmissionCache) Set(key string, value interface{}, _ time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[key] = fmt.Sprint(value)
+	return nil
+}
+
+func (c *memoryAdmissionCache) Delete(key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.values, key)
+	return nil
+}
+
+func (c *memoryAdmissionCache) Exists(key string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.values[key]
+	return ok, nil
+}
+
+func (c *memoryAdmissionCache) Expire(string, time.Duration) error { return nil }
+
+func (c *memoryAdmissionCache) SAdd(key string, members ...interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.sets[key] == nil {
+		c.sets[key] = map[string]bool{}
+	}
+	for _, m := range members {
+		c.sets[key][fmt.Sprint(m)] = true
+	}
+	return nil
+}
+
+func (c *memoryAdmissionCache) SRem(key string, members ...interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, m := range members {
+		delete(c.sets[key], fmt.Sprint(m))
+	}
+	return nil
+}
+
+func (c *memoryAdmissionCache) SIsMember(key string, member interface{}) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sets[key][fmt.Sprint(member)], nil
+}
+
+func (c *memoryAdmissionCache) SCard(key string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return int64(len(c.sets[key])), nil
+}
+
+func (c *memoryAdmissionCache) ZAddNX(key string, score float64, member interface{}) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.zsets[key] == nil {
+		c.zsets[key] = map[string]float64{}
+	}
+	if _, ok := c.zsets[key][fmt.Sprint(member)]; ok {
+		return false, nil
+	}
+	c.zsets[key][fmt.Sprint(member)] = score
+	return true, nil
+}
+
+func (c *memoryAdmissionCache) ZRem(key string, members ...interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, m := range members {
+		delete(c.zsets[key], fmt.Sprint(m))
+	}
+	return nil
+}
+
+func (c *memoryAdmissionCache) ZCard(key string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return int64(len(c.zsets[key])), nil
+}
+
+func (c *memoryAdmissionCache) ZRank(key string, member interface{}) (int64, error) {
+	for i, m := range c.sorted(key) {
+		if m == fmt.Sprint(member) {
+			return int64(i), nil
+		}
+	}
+	return -1, nil
+}
+
+func (c *memoryAdmissionCache) ZRange(key string, start, stop int64) ([]string, error) {
+	members := c.sorted(key)
+	if stop < 0 || stop >= int64(len(members)) {
+		stop = int64(len(members)) - 1
+	}
+	if start > stop {
+		return []string{}, nil
+	}
+	return members[start : stop+1], nil
+}
+
+func (c *memoryAdmissionCache) sorted(key string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	members := make([]string, 0, len(c.zsets[key]))
+	for m := range c.zsets[key] {
+		members = append(members, m)
+	}
+	sort.Slice(members, func(i, j int) bool { return c.zsets[key][members[i]] < c.zsets[key][members[j]] })
+	return members
+}
+
+// admissionEventRecorder запоминает события, отправленные игрокам
+type admissionEventRecorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *admissionEventRecorder) SendEventToUser(userID string, eventType string, data interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if eventType == "quiz:admission_queue" {
+		eventType = fmt.Sprintf("%s#%d", eventType, data.(map[string]interface{})["position"])
+	}
+	r.events = append(r.events, userID+":"+eventType)
+	return nil
+}
+
+func (r *admissionEventRecorder) take() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	events := r.events
+	r.events = nil
+	return events
+}
+
+func newTestAdmission(quiz *entity.Quiz) (*AdmissionController, *memoryAdmissionCache, *admissionEventRecorder) {
+	cache := newMemoryAdmissionCache()
+	quizRepo := new(MockQuizRepoForScheduler)
+	quizRepo.On("GetByID", quiz.ID).Return(quiz, nil)
+	config := DefaultConfig()
+	config.AdmissionLeaveGrace = 10 * time.Millisecond
+	admission := NewAdmissionController(config, &Dependencies{QuizRepo: quizRepo, CacheRepo: cache})
+	events := &admissionEventRecorder{}
+	admission.events = events
+	return admission, cache, events
+}
+
+func TestAdmission_QueueBeyondCapacity(t *testing.T) {
+	quiz := &entity.Quiz{ID: 7, Status: entity.QuizStatusScheduled, MaxPlayers: 2}
+	admission, cache, events := newTestAdmission(quiz)
+
+	for userID := uint(1); userID <= 4; userID++ {
+		result, err := admission.Admit(quiz, userID)
+		require.NoError(t, err)
+		assert.Equal(t, userID <= 2, result.Admitted, "user #%d", userID)
+		time.Sleep(time.Millisecond) // порядок очереди задаётся временем постановки
+	}
+	assert.Equal(t, []string{"3:quiz:admission_queue#1", "4:quiz:admission_queue#2"}, events.take())
+
+	// Повторный ready не меняет позицию в очереди
+	result, err := admission.Admit(quiz, 3)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), result.Position)
+	events.take()
+
+	// Выбывший освобождает место; очередь продвигается
+	admission.Release(7, 1)
+	admitted, err := admission.AdmitNext(7)
+	require.NoError(t, err)
+	assert.Equal(t, 1, admitted)
+	assert.Equal(t, []string{"3:quiz:admitted", "4:quiz:admission_queue#1"}, events.take())
+	isParticipant, _ := cache.SIsMember("quiz:7:participants", uint(3))
+	assert.True(t, isParticipant)
+
+	status, err := admission.Status(quiz)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), status.Admitted)
+	assert.Equal(t, []uint{4}, status.Queue)
+
+	// Снятие ограничения впускает всю очередь
+	quiz.MaxPlayers = 0
+	admitted, err = admission.AdmitNext(7)
+	require.NoError(t, err)
+	assert.Equal(t, 1, admitted)
+}
+
+func TestAdmission_AdminOverride(t *testing.T) {
+	quiz := &entity.Quiz{ID: 7, Status: entity.QuizStatusScheduled, MaxPlayers: 1}
+	admission, cache, events := newTestAdmission(quiz)
+
+	_, err := admission.Admit(quiz, 1)
+	require.NoError(t, err)
+	_, err = admission.Admit(quiz, 2)
+	require.NoError(t, err)
+	events.take()
+
+	require.NoError(t, admission.AdmitUser(7, 2))
+	assert.Equal(t, []string{"2:quiz:admitted"}, events.take())
+	admitted, _ := cache.SCard(AdmittedKey(7))
+	assert.Equal(t, int64(2), admitted, "допуск администратором сверх вместимости")
+
+	assert.ErrorIs(t, admission.AdmitUser(7, 5), apperrors.ErrNotFound)
+}
+
+func TestAdmission_LeaveWaitingRoom(t *testing.T) {
+	quiz := &entity.Quiz{ID: 7, Status: entity.QuizStatusScheduled, MaxPlayers: 1}
+	admission, cache, events := newTestAdmission(quiz)
+
+	_, err := admission.Admit(quiz, 1)
+	require.NoError(t, err)
+	_, err = admission.Admit(quiz, 2)
+	require.NoError(t, err)
+	events.take()
+
+	// Переподключение в пределах отсрочки сохраняет место
+	admission.HandleLeave("1", 7)
+	admission.Rejoin(7, 1)
+	time.Sleep(30 * time.Millisecond)
+	admitted, _ := cache.SIsMember(AdmittedKey(7), uint(1))
+	assert.True(t, admitted)
+
+	// Ушедший насовсем освобождает место следующему в очереди
+	admission.HandleLeave("1", 7)
+	require.Eventually(t, func() bool {
+		admitted, _ := cache.SIsMember(AdmittedKey(7), uint(2))
+		return admitted
+	}, time.Second, 5*time.Millisecond)
+	admitted, _ = cache.SIsMember(AdmittedKey(7), uint(1))
+	assert.False(t, admitted)
+	assert.Equal(t, []string{"2:quiz:admitted"}, events.take())
+
+	// Во время игры место освобождает только выбывание
+	quiz.Status = entity.QuizStatusInProgress
+	admission.HandleLeave("2", 7)
+	time.Sleep(30 * time.Millisecond)
+	admitted, _ = cache.SIsMember(AdmittedKey(7), uint(2))
+	assert.True(t, admitted)
+}
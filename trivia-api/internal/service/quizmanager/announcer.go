@@ -0,0 +1,135 @@
+package quizmanager
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+// Объявления ведущего до начала викторины проверяются только в последний час перед стартом
+const (
+	MaxLobbyAnnouncementOffset = time.Hour
+	lobbyAnnouncementInterval  = 5 * time.Second
+)
+
+// AnnouncementMilestone — момент викторины, к которому привязаны объявления ведущего
+type AnnouncementMilestone struct {
+	Trigger        string // entity.AnnouncementTrigger*
+	SecondsToStart int    // До начала викторины (before_start)
+	QuestionNumber int    // Отыгранный вопрос (after_question) или финальный (before_finale)
+	TotalQuestions int
+	Participants   int // Игроков в эфире; до старта — подключённых к залу ожидания
+}
+
+// Announcer выдаёт объявления ведущего, наступившие к моменту викторины, с подставленными
+// значениями. Каждое объявление выдаётся один раз, даже если момент повторяется
+// (переигровка снятого вопроса, перезапуск планировщика).
+type Announcer interface {
+	DueAnnouncements(quiz *entity.Quiz, milestone AnnouncementMilestone) ([]string, error)
+}
+
+// SetAnnouncer подключает объявления ведущего перед началом викторины
+func (s *Scheduler) SetAnnouncer(announcer Announcer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.announcer = announcer
+}
+
+// runLobbyAnnouncements рассылает объявления before_start, пока викторина не началась.
+// Объявления можно добавить и после планирования, поэтому в последний час перед стартом
+// они проверяются периодически.
+func (s *Scheduler) runLobbyAnnouncements(ctx context.Context, quiz *entity.Quiz) {
+	s.mu.Lock()
+	announcer := s.announcer
+	s.mu.Unlock()
+	if announcer == nil {
+		return
+	}
+
+	if wait := time.Until(quiz.ScheduledTime.Add(-MaxLobbyAnnouncementOffset)); wait > 0 {
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return
+		}
+	}
+
+	ticker := time.NewTicker(lobbyAnnouncementInterval)
+	defer ticker.Stop()
+	for {
+		secondsToStart := int(time.Until(quiz.ScheduledTime).Seconds())
+		if secondsToStart <= 0 {
+			return
+		}
+		participants := 0
+		if s.deps.WSManager != nil {
+			participants = s.deps.WSManager.GetSubscriberCount(quiz.ID)
+		}
+		messages, err := announcer.DueAnnouncements(quiz, AnnouncementMilestone{
+			Trigger:        entity.AnnouncementTriggerBeforeStart,
+			SecondsToStart: secondsToStart,
+			Participants:   participants,
+		})
+		if err != nil {
+			log.Printf("[Scheduler] WARNING: Не удалось получить объявления викторины #%d: %v", quiz.ID, err)
+		}
+		for _, message := range messages {
+			s.deps.WSManager.BroadcastEventToQuiz(quiz.ID, map[string]interface{}{
+				"type": "quiz:announcement",
+				"data": announcementEvent(quiz.ID, entity.AnnouncementTriggerBeforeStart, message),
+			})
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// SetAnnouncer подключает объявления ведущего по ходу викторины
+func (qm *QuestionManager) SetAnnouncer(announcer Announcer) {
+	qm.mu.Lock()
+	defer qm.mu.Unlock()
+	qm.announcer = announcer
+}
+
+// announceMilestone рассылает объявления ведущего, привязанные к моменту викторины
+func (qm *QuestionManager) announceMilestone(ctx context.Context, quizState *ActiveQuizState, milestone AnnouncementMilestone) {
+	qm.mu.RLock()
+	announcer := qm.announcer
+	qm.mu.RUnlock()
+	if announcer == nil {
+		return
+	}
+
+	quizID := quizState.Quiz.ID
+	participants, err := qm.countActiveParticipants(quizID)
+	if err != nil {
+		log.Printf("[QuestionManager] WARNING: Не удалось посчитать участников для объявлений викторины #%d: %v", quizID, err)
+	}
+	milestone.Participants = participants
+
+	messages, err := announcer.DueAnnouncements(quizState.Quiz, milestone)
+	if err != nil {
+		log.Printf("[QuestionManager] WARNING: Не удалось получить объявления викторины #%d: %v", quizID, err)
+	}
+	for _, message := range messages {
+		if err := qm.sendEventWithRetry(ctx, quizID, "quiz:announcement", announcementEvent(quizID, milestone.Trigger, message)); err != nil {
+			log.Printf("[QuestionManager] WARNING: Не удалось отправить объявление викторины #%d: %v", quizID, err)
+		}
+	}
+}
+
+// announcementEvent — данные события quiz:announcement от ведущего
+func announcementEvent(quizID uint, trigger, message string) map[string]interface{} {
+	return map[string]interface{}{
+		"quiz_id":          quizID,
+		"message":          message,
+		"trigger":          trigger,
+		"server_timestamp": time.Now().UnixMilli(),
+	}
+}
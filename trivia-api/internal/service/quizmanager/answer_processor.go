@@ -10,6 +10,9 @@ import (
 
 	"github.com/lib/pq"
 	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	"github.com/yourusername/trivia-api/internal/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // AnswerProcessor отвечает за обработку ответов пользователей
@@ -19,6 +22,21 @@ type AnswerProcessor struct {
 
 	// Зависимости
 	deps *Dependencies
+
+	// Буфер пакетной записи ответов (опционально); без него ответ пишется в БД сразу
+	answerWriter AnswerWriter
+
+	// Вместимость викторины и очередь допуска (опционально)
+	admission *AdmissionController
+
+	// Уведомления подписчиков о входе игрока в зал ожидания (опционально)
+	friendNotifier FriendActivityNotifier
+
+	// Наблюдатель сохранённых ответов (опционально)
+	answerObserver AnswerObserver
+
+	// Персональные дедлайны по подтверждениям доставки вопроса (опционально)
+	delivery *DeliveryTracker
 }
 
 // NewAnswerProcessor создает новый процессор ответов
@@ -29,6 +47,36 @@ func NewAnswerProcessor(config *Config, deps *Dependencies) *AnswerProcessor {
 	}
 }
 
+// SetAdmission подключает ограничение вместимости и очередь допуска.
+// Вызывается при инициализации, до запуска викторин.
+func (ap *AnswerProcessor) SetAdmission(admission *AdmissionController) {
+	ap.admission = admission
+}
+
+// SetAnswerWriter подключает буфер пакетной записи ответов.
+// Вызывается при инициализации, до запуска викторин.
+func (ap *AnswerProcessor) SetAnswerWriter(writer AnswerWriter) {
+	ap.answerWriter = writer
+}
+
+// SetFriendNotifier подключает уведомления подписчиков о входе игрока в зал ожидания.
+// Вызывается при инициализации, до запуска викторин.
+func (ap *AnswerProcessor) SetFriendNotifier(notifier FriendActivityNotifier) {
+	ap.friendNotifier = notifier
+}
+
+// SetAnswerObserver подключает наблюдателя сохранённых ответов.
+// Вызывается при инициализации, до запуска викторин.
+func (ap *AnswerProcessor) SetAnswerObserver(observer AnswerObserver) {
+	ap.answerObserver = observer
+}
+
+// SetDeliveryTracker подключает персональные дедлайны по подтверждениям доставки вопроса.
+// Вызывается при инициализации, до запуска викторин.
+func (ap *AnswerProcessor) SetDeliveryTracker(delivery *DeliveryTracker) {
+	ap.delivery = delivery
+}
+
 // ProcessAnswer обрабатывает ответ пользователя
 func (ap *AnswerProcessor) ProcessAnswer(
 	ctx context.Context,
@@ -38,14 +86,24 @@ func (ap *AnswerProcessor) ProcessAnswer(
 	timestamp int64,
 	quizState *ActiveQuizState,
 	questionStartTimeMs int64,
-) error {
+) (err error) {
 	questionID := question.ID
 
+	ctx, span := tracing.StartSpan(ctx, "AnswerProcessor.ProcessAnswer",
+		attribute.Int64("enduser.id", int64(userID)),
+		attribute.Int64("quiz.question_id", int64(questionID)),
+	)
+	defer func() { tracing.EndSpan(span, err) }()
+
 	if quizState == nil || quizState.Quiz == nil {
 		log.Printf("[AnswerProcessor] Ошибка: нет активной викторины для ответа пользователя #%d", userID)
 		return fmt.Errorf("no active quiz")
 	}
 	quizID := quizState.Quiz.ID
+	span.SetAttributes(attribute.Int64("quiz.id", int64(quizID)))
+
+	// Репозиторий с контекстом запроса: команды Redis попадают в трейс ответа
+	cacheRepo := ap.deps.CacheRepo.WithContext(ctx)
 
 	log.Printf("[AnswerProcessor] Обработка ответа пользователя #%d на вопрос #%d (викторина #%d), выбранный вариант: %d",
 		userID, questionID, quizID, selectedOption)
@@ -54,7 +112,7 @@ func (ap *AnswerProcessor) ProcessAnswer(
 
 	// === 1. ПРОВЕРКА ВЫБЫВАНИЯ (ПЕРЕД ВСЕМ ОСТАЛЬНЫМ) ===
 	eliminationKey := fmt.Sprintf("quiz:%d:eliminated:%d", quizID, userID)
-	isEliminated, err := ap.deps.CacheRepo.Exists(eliminationKey)
+	isEliminated, err := cacheRepo.Exists(eliminationKey)
 	if err != nil {
 		// Ошибка Redis при проверке выбывания - критична, возвращаем ошибку
 		log.Printf("[AnswerProcessor] CRITICAL: Ошибка Redis при проверке ключа выбывания %s: %v", eliminationKey, err)
@@ -69,7 +127,7 @@ func (ap *AnswerProcessor) ProcessAnswer(
 	// === 1.1 ПРОВЕРКА УЧАСТНИКА ===
 	// В викторине могут отвечать только зарегистрированные участники (user:ready).
 	participantsKey := fmt.Sprintf("quiz:%d:participants", quizID)
-	isParticipant, err := ap.deps.CacheRepo.SIsMember(participantsKey, userID)
+	isParticipant, err := cacheRepo.SIsMember(participantsKey, userID)
 	if err != nil {
 		log.Printf("[AnswerProcessor] CRITICAL: Ошибка Redis при проверке участника %d в %s: %v", userID, participantsKey, err)
 		return fmt.Errorf("redis error checking participant status: %w", err)
@@ -89,7 +147,7 @@ func (ap *AnswerProcessor) ProcessAnswer(
 	if actualStartTimeMs == 0 {
 		// Пробуем получить из Redis
 		questionStartKey := fmt.Sprintf("question:%d:start_time", questionID)
-		startTimeStr, redisErr := ap.deps.CacheRepo.Get(questionStartKey)
+		startTimeStr, redisErr := cacheRepo.Get(questionStartKey)
 		if redisErr != nil {
 			log.Printf("[AnswerProcessor] CRITICAL: Время начала для вопроса #%d не найдено ни в state, ни в Redis для викторины #%d: %v", questionID, quizID, redisErr)
 			return fmt.Errorf("internal error: question start time not found")
@@ -105,18 +163,30 @@ func (ap *AnswerProcessor) ProcessAnswer(
 
 	// Фиксируем серверное время получения
 	serverReceiveTimeMs := time.Now().UnixNano() / int64(time.Millisecond)
-	// Рассчитываем время ответа - используем actualStartTimeMs (может быть из Redis)
-	responseTimeMs := serverReceiveTimeMs - actualStartTimeMs
+	// Игрок подтвердил получение вопроса: его отсчёт идёт с момента подтверждения (с ограниченной
+	// отсрочкой). Подтверждение и ответ проходят до сервера один путь, поэтому поправка на RTT не нужна.
+	userStartTimeMs := actualStartTimeMs
+	var latencyMs int64
+	if allowanceMs, acked := ap.deliveryAllowanceMs(cacheRepo, quizID, questionID, userID, actualStartTimeMs); acked {
+		userStartTimeMs += allowanceMs
+	} else {
+		// Вопрос идёт до игрока, а ответ обратно на сервер в сумме около RTT соединения.
+		// Вычитаем его, чтобы игроки с медленной сетью не теряли время на ответ.
+		latencyMs = ap.latencyCompensationMs(userID)
+	}
+	effectiveReceiveTimeMs := serverReceiveTimeMs - latencyMs
+	// Рассчитываем время ответа - от персонального начала отсчёта (actualStartTimeMs может быть из Redis)
+	responseTimeMs := effectiveReceiveTimeMs - userStartTimeMs
 	if responseTimeMs < 0 {
 		responseTimeMs = 0
 	}
 
-	// Проверяем лимит времени
-	timeLimitMs := int64(question.TimeLimitSec * 1000)
+	// Проверяем лимит времени (с учётом пауз и продлений администратора)
+	timeLimitMs := quizState.QuestionTimeLimitMs(question, actualStartTimeMs)
 	isTimeLimitExceeded := responseTimeMs > timeLimitMs
-	isReceivedTooLate := serverReceiveTimeMs > (actualStartTimeMs + timeLimitMs)
+	isReceivedTooLate := effectiveReceiveTimeMs > (userStartTimeMs + timeLimitMs)
 	if isReceivedTooLate {
-		log.Printf("[AnswerProcessor] Ответ от User #%d на Q #%d получен ПОСЛЕ дедлайна.", userID, questionID)
+		log.Printf("[AnswerProcessor] Ответ от User #%d на Q #%d получен ПОСЛЕ дедлайна (отсрочка %d мс, компенсация задержки %d мс).", userID, questionID, userStartTimeMs-actualStartTimeMs, latencyMs)
 		isTimeLimitExceeded = true // Гарантируем статус просроченного
 	}
 
@@ -126,12 +196,27 @@ func (ap *AnswerProcessor) ProcessAnswer(
 	isCorrectOption := question.IsCorrect(selectedOption)
 	isCorrect := isCorrectOption && !isTimeLimitExceeded
 	correctOption := question.CorrectOption
-	score := question.CalculatePoints(isCorrect, responseTimeMs)
-
-	// Определяем, должен ли пользователь выбыть СЕЙЧАС
-	userShouldBeEliminated := !isCorrect || isTimeLimitExceeded
+	scored := ScoredAnswer{Question: question, IsCorrect: isCorrect, ResponseTimeMs: responseTimeMs, TimeLimitMs: timeLimitMs}
+	if isCorrect {
+		scored.Streak = nextStreak(cacheRepo, quizState.Quiz, userID)
+	}
+	score := ScoringStrategyFor(quizState.Quiz).Points(scored)
+
+	// Определяем, должен ли пользователь выбыть СЕЙЧАС. Последствия ошибки зависят от режима игры;
+	// в финале на выбывание ошибка фиксируется сразу, а выбывание — по итогам раунда (QuestionManager)
+	suddenDeath := quizState.SuddenDeathRound() > 0
+	isMistake := !isCorrect || isTimeLimitExceeded
+	userShouldBeEliminated := false
+	var livesLeft *int
+	if isMistake && !suddenDeath {
+		outcome := registerMistake(cacheRepo, quizState.Quiz, userID, questionID)
+		userShouldBeEliminated = outcome.Eliminate
+		livesLeft = outcome.LivesLeft
+	}
 	eliminationReason := ""
-	if userShouldBeEliminated {
+	if suddenDeath && isMistake {
+		eliminationReason = entity.AnswerReasonSuddenDeath
+	} else if userShouldBeEliminated {
 		if isTimeLimitExceeded {
 			eliminationReason = "time_exceeded"
 		} else {
@@ -140,7 +225,7 @@ func (ap *AnswerProcessor) ProcessAnswer(
 		log.Printf("[AnswerProcessor] Пользователь #%d должен выбыть из викторины #%d. Причина: %s", userID, quizID, eliminationReason)
 	}
 
-	// === 3. СОХРАНЕНИЕ ОТВЕТА (DB First) ===
+	// === 3. СОХРАНЕНИЕ ОТВЕТА (DB First или через буфер записи) ===
 
 	// Создаем запись об ответе (ПОКА НЕ СОХРАНЯЕМ)
 	userAnswer := &entity.UserAnswer{
@@ -151,14 +236,35 @@ func (ap *AnswerProcessor) ProcessAnswer(
 		IsCorrect:         isCorrect,
 		ResponseTimeMs:    responseTimeMs,
 		Score:             score,
-		IsEliminated:      userShouldBeEliminated, // Записываем, должен ли он выбыть ПОСЛЕ этого ответа
+		IsEliminated:      userShouldBeEliminated || (suddenDeath && isMistake), // Записываем, должен ли он выбыть ПОСЛЕ этого ответа
 		EliminationReason: eliminationReason,
 		// CreatedAt будет установлен GORM
 	}
 
-	// Пытаемся сохранить ответ в БД
-	err = ap.deps.ResultRepo.SaveUserAnswer(userAnswer)
-	if err != nil {
+	answerKey := fmt.Sprintf("quiz:%d:user:%d:question:%d", quizID, userID, questionID)
+
+	if ap.answerWriter != nil {
+		// Буфер записывает ответ в БД позже, поэтому единственность ответа на вопрос
+		// обеспечивает атомарный захват флага ответа в Redis
+		claimed, errClaim := cacheRepo.SetNX(answerKey, "1", 1*time.Hour)
+		if errClaim != nil {
+			log.Printf("[AnswerProcessor] CRITICAL: Ошибка Redis при захвате флага ответа %s: %v", answerKey, errClaim)
+			return fmt.Errorf("redis error claiming answer: %w", errClaim)
+		}
+		if !claimed {
+			log.Printf("[AnswerProcessor] Пользователь #%d уже отвечал на вопрос #%d викторины #%d (определено по флагу ответа)", userID, questionID, quizID)
+			return fmt.Errorf("user already answered this question")
+		}
+		if err = ap.answerWriter.Enqueue(ctx, userAnswer); err != nil {
+			// Освобождаем флаг, чтобы пользователь мог повторить ответ
+			if errDel := cacheRepo.Delete(answerKey); errDel != nil {
+				log.Printf("[AnswerProcessor] WARNING: Не удалось снять флаг ответа %s: %v", answerKey, errDel)
+			}
+			log.Printf("[AnswerProcessor] CRITICAL: Ошибка при сохранении ответа пользователя #%d на вопрос #%d: %v",
+				userID, questionID, err)
+			return fmt.Errorf("failed to save user answer: %w", err)
+		}
+	} else if err = ap.deps.ResultRepo.WithContext(ctx).SaveUserAnswer(userAnswer); err != nil {
 		// Проверяем ошибку уникального ключа (дубликат ответа)
 		var pqErr *pq.Error
 		if errors.As(err, &pqErr) && pqErr.Code == "23505" { // 23505 - unique_violation
@@ -174,28 +280,50 @@ func (ap *AnswerProcessor) ProcessAnswer(
 
 	// === 4. ПОСТ-ОБРАБОТКА (ПОСЛЕ УСПЕШНОГО СОХРАНЕНИЯ В БД) ===
 
-	log.Printf("[AnswerProcessor] Ответ User #%d на Q #%d успешно сохранен в БД.", userID, questionID)
+	log.Printf("[AnswerProcessor] Ответ User #%d на Q #%d успешно принят.", userID, questionID)
+	if ap.answerObserver != nil {
+		ap.answerObserver.AnswerRecorded(userAnswer, quizState.CurrentQuestionNumber)
+	}
+
+	updateStreak(cacheRepo, quizState.Quiz, userID, isCorrect)
 
-	// Устанавливаем статус выбывшего в Redis, ЕСЛИ он должен выбыть
+	// Устанавливаем статус выбывшего в Redis, ЕСЛИ он должен выбыть.
+	// Значение — номер вопроса: по нему проверяется окно второго шанса.
 	if userShouldBeEliminated {
-		if errCache := ap.deps.CacheRepo.Set(eliminationKey, "1", 24*time.Hour); errCache != nil {
+		if errCache := cacheRepo.Set(eliminationKey, strconv.Itoa(quizState.CurrentQuestionNumber), 24*time.Hour); errCache != nil {
 			// Логируем ошибку Redis, но не возвращаем ее, т.к. ответ уже сохранен
 			log.Printf("[AnswerProcessor] WARNING: Не удалось установить статус выбывшего пользователя #%d в Redis: %v", userID, errCache)
 		}
 		// Отправляем уведомление о выбывании
 		ap.sendEliminationNotification(userID, quizID, eliminationReason)
+		offerSecondChance(ap.deps, quizState.Quiz, userID, quizState.CurrentQuestionNumber)
+		// Место выбывшего достаётся следующему в очереди между вопросами
+		if ap.admission != nil {
+			ap.admission.Release(quizID, userID)
+		}
 	}
 
-	// Опционально: Устанавливаем флаг, что ответ на этот вопрос дан (для QM)
-	answerKey := fmt.Sprintf("quiz:%d:user:%d:question:%d", quizID, userID, questionID)
-	if errCache := ap.deps.CacheRepo.Set(answerKey, "1", 1*time.Hour); errCache != nil {
-		log.Printf("[AnswerProcessor] WARNING: Не удалось установить флаг ответа в Redis для user #%d, question #%d: %v", userID, questionID, errCache)
+	// Верный ответ в финале на выбывание сохраняет игрока в игре по итогам раунда
+	if suddenDeath && !isMistake {
+		correctKey := suddenDeathCorrectKey(quizID, questionID)
+		if errCache := cacheRepo.SAdd(correctKey, userID); errCache != nil {
+			log.Printf("[AnswerProcessor] WARNING: Не удалось отметить верный ответ финала пользователя #%d: %v", userID, errCache)
+		} else if errCache := cacheRepo.Expire(correctKey, gameModeKeyTTL); errCache != nil {
+			log.Printf("[AnswerProcessor] WARNING: Не удалось установить TTL %s: %v", correctKey, errCache)
+		}
+	}
+
+	// Устанавливаем флаг, что ответ на этот вопрос дан (для QM); при буферизации он уже захвачен
+	if ap.answerWriter == nil {
+		if errCache := cacheRepo.Set(answerKey, "1", 1*time.Hour); errCache != nil {
+			log.Printf("[AnswerProcessor] WARNING: Не удалось установить флаг ответа в Redis для user #%d, question #%d: %v", userID, questionID, errCache)
+		}
 	}
 
 	// === ЗАПИСЫВАЕМ СТАТИСТИКУ ДЛЯ АДАПТИВНОЙ СИСТЕМЫ ===
 	// questionNumber передаётся через quizState.CurrentQuestionNumber
 	if quizState.CurrentQuestionNumber > 0 {
-		ap.recordAdaptiveStats(quizID, quizState.CurrentQuestionNumber, !userShouldBeEliminated)
+		ap.recordAdaptiveStats(quizID, quizState.CurrentQuestionNumber, !isMistake)
 	}
 
 	// Отправляем результат пользователю
@@ -210,6 +338,12 @@ func (ap *AnswerProcessor) ProcessAnswer(
 		"elimination_reason":  eliminationReason,
 		"time_limit_exceeded": isTimeLimitExceeded,
 	}
+	if livesLeft != nil {
+		answerResultEvent["lives_left"] = *livesLeft
+	}
+	if suddenDeath {
+		answerResultEvent["sudden_death"] = true
+	}
 	if errSend := ap.deps.WSManager.SendEventToUser(fmt.Sprintf("%d", userID), "quiz:answer_result", answerResultEvent); errSend != nil {
 		log.Printf("[AnswerProcessor] Ошибка при отправке результата ответа пользователю #%d: %v", userID, errSend)
 		// Не возвращаем ошибку, так как ответ уже сохранен
@@ -246,6 +380,20 @@ func (ap *AnswerProcessor) HandleReadyEvent(ctx context.Context, userID uint, qu
 				userID, quizID, quiz.Status)
 			return fmt.Errorf("quiz registration is closed")
 		}
+		// Сверх вместимости викторины игрок ждёт в очереди; позицию он получает событием quiz:admission_queue
+		if ap.admission != nil {
+			ap.admission.Rejoin(quizID, userID)
+			admission, err := ap.admission.Admit(quiz, userID)
+			if err != nil {
+				return fmt.Errorf("failed to admit player: %w", err)
+			}
+			if !admission.Admitted {
+				return nil
+			}
+		}
+	} else if ap.admission != nil {
+		// Переподключившийся участник сохраняет место в зале ожидания
+		ap.admission.Rejoin(quizID, userID)
 	}
 
 	// Создаем ключ для Redis и сохраняем информацию о готовности
@@ -270,6 +418,10 @@ func (ap *AnswerProcessor) HandleReadyEvent(ctx context.Context, userID uint, qu
 	} else {
 		log.Printf("[AnswerProcessor] Пользователь #%d добавлен в participants Set викторины #%d", userID, quizID)
 	}
+	// Подписчиков уведомляем один раз — при первом входе, а не при переподключении
+	if !alreadyParticipant && ap.friendNotifier != nil {
+		go ap.friendNotifier.NotifyFriendJoined(quizID, userID)
+	}
 	// Устанавливаем TTL на Set (24 часа) — обновляем при каждом добавлении
 	if err := ap.deps.CacheRepo.Expire(participantsKey, 24*time.Hour); err != nil {
 		log.Printf("[AnswerProcessor] WARNING: Не удалось установить TTL на participants Set: %v", err)
@@ -384,3 +536,40 @@ func (ap *AnswerProcessor) recordAdaptiveStats(quizID uint, questionNumber int,
 		}
 	}
 }
+
+// AcknowledgeQuestion фиксирует подтверждение доставки вопроса игроку (user:question_received)
+func (ap *AnswerProcessor) AcknowledgeQuestion(ctx context.Context, userID uint, question *entity.Question, quizState *ActiveQuizState, questionStartTimeMs int64) error {
+	if ap.delivery == nil {
+		return nil
+	}
+	if quizState == nil || quizState.Quiz == nil {
+		return fmt.Errorf("no active quiz")
+	}
+	deadline := time.UnixMilli(questionStartTimeMs + quizState.QuestionTimeLimitMs(question, questionStartTimeMs))
+	return ap.delivery.Acknowledge(ctx, quizState.Quiz.ID, question.ID, userID, questionStartTimeMs, deadline)
+}
+
+// deliveryAllowanceMs возвращает отсрочку игрока по подтверждению доставки вопроса; false — подтверждения нет
+func (ap *AnswerProcessor) deliveryAllowanceMs(cacheRepo repository.CacheRepository, quizID, questionID, userID uint, startTimeMs int64) (int64, bool) {
+	if ap.delivery == nil {
+		return 0, false
+	}
+	return ap.delivery.UserAllowanceMs(cacheRepo, quizID, questionID, userID, startTimeMs)
+}
+
+// latencyCompensationMs возвращает поправку на сетевую задержку игрока: RTT его соединения,
+// ограниченный MaxLatencyCompensationMs. Если игрок подключён к другому узлу или RTT
+// ещё не измерен, поправка не применяется.
+func (ap *AnswerProcessor) latencyCompensationMs(userID uint) int64 {
+	if ap.deps.WSManager == nil || ap.config.MaxLatencyCompensationMs <= 0 {
+		return 0
+	}
+	rtt, ok := ap.deps.WSManager.ClientRTT(strconv.FormatUint(uint64(userID), 10))
+	if !ok {
+		return 0
+	}
+	if latencyMs := rtt.Milliseconds(); latencyMs < ap.config.MaxLatencyCompensationMs {
+		return latencyMs
+	}
+	return ap.config.MaxLatencyCompensationMs
+}
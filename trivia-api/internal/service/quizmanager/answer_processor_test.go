@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
 	"gorm.io/gorm"
 )
 
@@ -90,6 +91,39 @@ func (m *MockCacheRepoForAnswerProcessor) SIsMember(key string, member interface
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockCacheRepoForAnswerProcessor) SCard(key string) (int64, error) {
+	args := m.Called(key)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockCacheRepoForAnswerProcessor) ZAddNX(key string, score float64, member interface{}) (bool, error) {
+	args := m.Called(key, score, member)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCacheRepoForAnswerProcessor) ZRank(key string, member interface{}) (int64, error) {
+	args := m.Called(key, member)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockCacheRepoForAnswerProcessor) ZRem(key string, members ...interface{}) error {
+	args := m.Called(key, members)
+	return args.Error(0)
+}
+
+func (m *MockCacheRepoForAnswerProcessor) ZCard(key string) (int64, error) {
+	args := m.Called(key)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockCacheRepoForAnswerProcessor) ZRange(key string, start, stop int64) ([]string, error) {
+	args := m.Called(key, start, stop)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
 func (m *MockCacheRepoForAnswerProcessor) Expire(key string, expiration time.Duration) error {
 	args := m.Called(key, expiration)
 	return args.Error(0)
@@ -100,6 +134,23 @@ func (m *MockCacheRepoForAnswerProcessor) ExistsBatch(keys []string) (map[string
 	return args.Get(0).(map[string]bool), args.Error(1)
 }
 
+func (m *MockCacheRepoForAnswerProcessor) RPushTrim(key string, maxLen int64, expiration time.Duration, values ...interface{}) error {
+	args := m.Called(key, maxLen, expiration, values)
+	return args.Error(0)
+}
+
+func (m *MockCacheRepoForAnswerProcessor) LRange(key string, start, stop int64) ([]string, error) {
+	args := m.Called(key, start, stop)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockCacheRepoForAnswerProcessor) WithContext(ctx context.Context) repository.CacheRepository {
+	return m
+}
+
 // MockResultRepoForAnswerProcessor реализует repository.ResultRepository (минимально)
 type MockResultRepoForAnswerProcessor struct {
 	mock.Mock
@@ -110,6 +161,21 @@ func (m *MockResultRepoForAnswerProcessor) SaveUserAnswer(answer *entity.UserAns
 	return args.Error(0)
 }
 
+func (m *MockResultRepoForAnswerProcessor) SaveUserAnswersBatch(answers []entity.UserAnswer) (int64, error) {
+	args := m.Called(answers)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockResultRepoForAnswerProcessor) VoidQuestionAnswers(quizID, questionID uint) (int64, error) {
+	args := m.Called(quizID, questionID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockResultRepoForAnswerProcessor) ReviveEliminatedAnswer(quizID, userID uint) (int64, error) {
+	args := m.Called(quizID, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 // Остальные методы не используются в ProcessAnswer, но нужны для интерфейса
 func (m *MockResultRepoForAnswerProcessor) GetUserAnswers(userID uint, quizID uint) ([]entity.UserAnswer, error) {
 	return nil, nil
@@ -121,6 +187,9 @@ func (m *MockResultRepoForAnswerProcessor) SaveResult(result *entity.Result) err
 func (m *MockResultRepoForAnswerProcessor) GetQuizResults(quizID uint, limit, offset int) ([]entity.Result, int64, error) {
 	return nil, 0, nil
 }
+func (m *MockResultRepoForAnswerProcessor) GetQuizResultsAfter(quizID uint, after *repository.QuizResultCursor, limit int) ([]entity.Result, error) {
+	return nil, nil
+}
 func (m *MockResultRepoForAnswerProcessor) GetAllQuizResults(quizID uint) ([]entity.Result, error) {
 	return nil, nil
 }
@@ -130,16 +199,26 @@ func (m *MockResultRepoForAnswerProcessor) GetUserResult(userID uint, quizID uin
 func (m *MockResultRepoForAnswerProcessor) GetUserResults(userID uint, limit, offset int) ([]entity.Result, int64, error) {
 	return nil, 0, nil
 }
-func (m *MockResultRepoForAnswerProcessor) CalculateRanks(tx *gorm.DB, quizID uint) error {
+func (m *MockResultRepoForAnswerProcessor) GetUserResultsAfter(userID uint, after *repository.UserResultCursor, limit int) ([]entity.Result, error) {
+	return nil, nil
+}
+func (m *MockResultRepoForAnswerProcessor) GetUserBestWinStreak(userID uint) (int, error) {
+	return 0, nil
+}
+func (m *MockResultRepoForAnswerProcessor) CalculateRanks(tx *gorm.DB, quizID uint, tieBreak string) error {
 	return nil
 }
 func (m *MockResultRepoForAnswerProcessor) GetQuizWinners(quizID uint) ([]entity.Result, error) {
 	return nil, nil
 }
-func (m *MockResultRepoForAnswerProcessor) FindAndUpdateWinners(tx *gorm.DB, quizID uint, questionCount int, totalPrizeFund int) ([]uint, int, error) {
+func (m *MockResultRepoForAnswerProcessor) FindAndUpdateWinners(tx *gorm.DB, quizID uint, criteria repository.WinnerCriteria, totalPrizeFund int) ([]uint, int, error) {
 	return nil, 0, nil
 }
 
+func (m *MockResultRepoForAnswerProcessor) WithContext(ctx context.Context) repository.ResultRepository {
+	return m
+}
+
 // MockWSManagerForAnswerProcessor реализует минимальный интерфейс для WS
 type MockWSManagerForAnswerProcessor struct {
 	mock.Mock
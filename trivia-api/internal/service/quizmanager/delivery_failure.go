@@ -0,0 +1,84 @@
+package quizmanager
+
+import (
+	"log"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/websocket"
+)
+
+// deliveryVoidReasonMessage — сообщение игрокам о вопросе, снятом из-за сбоя рассылки
+const deliveryVoidReasonMessage = "Вопрос дошёл не до всех игроков и снят с эфира. Ответы на него не засчитываются."
+
+// DeliveryIncident — рассылка вопроса, которая дошла до слишком малой доли подписчиков узла
+type DeliveryIncident struct {
+	QuizID         uint
+	QuestionID     uint
+	QuestionNumber int
+	Subscribers    int
+	Delivered      int
+	Ratio          float64
+	Threshold      float64
+	// Voided — вопрос снят с эфира; false — исчерпан лимит MaxDeliveryVoidsPerQuiz
+	Voided     bool
+	OccurredAt time.Time
+}
+
+// SetDeliveryIncidentHook подключает запись инцидентов доставки вопросов (например, в журнал аудита).
+// Хук вызывается в отдельной горутине и не задерживает ход викторины.
+func (qm *QuestionManager) SetDeliveryIncidentHook(hook func(incident DeliveryIncident)) {
+	qm.mu.Lock()
+	defer qm.mu.Unlock()
+	qm.deliveryIncidentHook = hook
+}
+
+// checkQuestionDelivery снимает вопрос с эфира, если рассылка quiz:question дошла меньше чем до
+// MinQuestionDeliveryRatio подписчиков: не получившие вопрос иначе выбыли бы за отсутствие ответа.
+// Снятый вопрос аннулирует RunQuizQuestions так же, как снятый администратором.
+// Возвращает true, если вопрос снят.
+func (qm *QuestionManager) checkQuestionDelivery(quizState *ActiveQuizState, question *entity.Question, questionNumber int,
+	clock *QuestionClock, delivery websocket.QuizDelivery, voidsSoFar int) bool {
+	threshold := qm.config.MinQuestionDeliveryRatio
+	if threshold <= 0 || delivery.Subscribers == 0 || delivery.Subscribers < qm.config.MinDeliverySubscribers {
+		return false
+	}
+	ratio := delivery.Ratio()
+	if ratio >= threshold {
+		return false
+	}
+
+	quizID := quizState.Quiz.ID
+	incident := DeliveryIncident{
+		QuizID:         quizID,
+		QuestionID:     question.ID,
+		QuestionNumber: questionNumber,
+		Subscribers:    delivery.Subscribers,
+		Delivered:      delivery.Delivered,
+		Ratio:          ratio,
+		Threshold:      threshold,
+		OccurredAt:     time.Now(),
+	}
+	if voidsSoFar < qm.config.MaxDeliveryVoidsPerQuiz {
+		if err := clock.Void(deliveryVoidReasonMessage); err != nil {
+			log.Printf("[QuestionManager] WARNING: Не удалось снять вопрос #%d викторины #%d после сбоя доставки: %v", question.ID, quizID, err)
+		} else {
+			incident.Voided = true
+		}
+	}
+	if incident.Voided {
+		log.Printf("[QuestionManager] ALERT: Вопрос #%d (номер %d) викторины #%d доставлен %d из %d подписчиков (%.1f%% < %.1f%%). Вопрос снят с эфира.",
+			question.ID, questionNumber, quizID, delivery.Delivered, delivery.Subscribers, ratio*100, threshold*100)
+	} else {
+		log.Printf("[QuestionManager] ALERT: Вопрос #%d (номер %d) викторины #%d доставлен %d из %d подписчиков (%.1f%% < %.1f%%), но лимит снятий (%d) исчерпан. Вопрос остаётся в эфире.",
+			question.ID, questionNumber, quizID, delivery.Delivered, delivery.Subscribers, ratio*100, threshold*100, qm.config.MaxDeliveryVoidsPerQuiz)
+	}
+
+	qm.mu.RLock()
+	hook := qm.deliveryIncidentHook
+	qm.mu.RUnlock()
+	if hook != nil {
+		go hook(incident)
+	}
+	return incident.Voided
+}
@@ -0,0 +1,73 @@
+package quizmanager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/websocket"
+)
+
+func newDeliveryCheckFixture(t *testing.T) (*QuestionManager, *ActiveQuizState, *entity.Question, chan DeliveryIncident) {
+	t.Helper()
+	config := DefaultConfig()
+	qm := NewQuestionManager(config, &Dependencies{Config: config})
+	incidents := make(chan DeliveryIncident, 4)
+	qm.SetDeliveryIncidentHook(func(incident DeliveryIncident) { incidents <- incident })
+	question := &entity.Question{ID: 21, TimeLimitSec: 10}
+	return qm, NewActiveQuizState(&entity.Quiz{ID: 11}), question, incidents
+}
+
+func receiveIncident(t *testing.T, incidents chan DeliveryIncident) DeliveryIncident {
+	t.Helper()
+	select {
+	case incident := <-incidents:
+		return incident
+	case <-time.After(time.Second):
+		t.Fatal("инцидент доставки не записан")
+		return DeliveryIncident{}
+	}
+}
+
+func TestCheckQuestionDelivery_VoidsOnMassFailure(t *testing.T) {
+	qm, state, question, incidents := newDeliveryCheckFixture(t)
+	clock := NewQuestionClock(10 * time.Second)
+
+	voided := qm.checkQuestionDelivery(state, question, 3, clock, websocket.QuizDelivery{Subscribers: 200, Delivered: 120}, 0)
+	require.True(t, voided)
+	assert.True(t, clock.Voided())
+	assert.Equal(t, deliveryVoidReasonMessage, clock.VoidReason())
+
+	incident := receiveIncident(t, incidents)
+	assert.Equal(t, uint(11), incident.QuizID)
+	assert.Equal(t, uint(21), incident.QuestionID)
+	assert.Equal(t, 3, incident.QuestionNumber)
+	assert.Equal(t, 200, incident.Subscribers)
+	assert.Equal(t, 120, incident.Delivered)
+	assert.InDelta(t, 0.6, incident.Ratio, 0.001)
+	assert.True(t, incident.Voided)
+}
+
+func TestCheckQuestionDelivery_KeepsQuestion(t *testing.T) {
+	qm, state, question, incidents := newDeliveryCheckFixture(t)
+
+	// Доставка выше порога
+	clock := NewQuestionClock(10 * time.Second)
+	assert.False(t, qm.checkQuestionDelivery(state, question, 1, clock, websocket.QuizDelivery{Subscribers: 200, Delivered: 190}, 0))
+	assert.False(t, clock.Voided())
+
+	// Слишком мало подписчиков, чтобы судить о сбое рассылки
+	assert.False(t, qm.checkQuestionDelivery(state, question, 1, clock, websocket.QuizDelivery{Subscribers: 5, Delivered: 1}, 0))
+	assert.False(t, clock.Voided())
+	assert.Empty(t, incidents)
+
+	// Лимит снятий исчерпан: вопрос остаётся в эфире, инцидент всё равно записывается
+	voided := qm.checkQuestionDelivery(state, question, 1, clock, websocket.QuizDelivery{Subscribers: 200, Delivered: 20}, qm.config.MaxDeliveryVoidsPerQuiz)
+	assert.False(t, voided)
+	assert.False(t, clock.Voided())
+	incident := receiveIncident(t, incidents)
+	assert.False(t, incident.Voided)
+}
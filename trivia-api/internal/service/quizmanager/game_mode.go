@@ -0,0 +1,230 @@
+package quizmanager
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+)
+
+// gameModeKeyTTL — сколько живут счётчики режима игры (как и ключи выбывания)
+const gameModeKeyTTL = 24 * time.Hour
+
+// mistakesKey — Redis Set вопросов, на которых игрок ошибся (режим lives). Set, а не счётчик:
+// повторная обработка того же вопроса не отнимает вторую жизнь.
+func mistakesKey(quizID, userID uint) string {
+	return fmt.Sprintf("quiz:%d:mistakes:%d", quizID, userID)
+}
+
+// suddenDeathCorrectKey — Redis Set игроков, верно ответивших на вопрос финала на выбывание
+func suddenDeathCorrectKey(quizID, questionID uint) string {
+	return fmt.Sprintf("quiz:%d:sudden_death:%d:correct", quizID, questionID)
+}
+
+// mistakeOutcome — последствия ошибки игрока в режиме игры викторины
+type mistakeOutcome struct {
+	Eliminate bool
+	LivesLeft *int // только в режиме lives
+}
+
+// registerMistake учитывает ошибку игрока (неверный, просроченный или пропущенный ответ
+// на вопрос questionID) по режиму игры викторины
+func registerMistake(cache repository.CacheRepository, quiz *entity.Quiz, userID, questionID uint) mistakeOutcome {
+	switch quiz.EffectiveGameMode() {
+	case entity.GameModePointsOnly:
+		return mistakeOutcome{}
+	case entity.GameModeLives:
+		key := mistakesKey(quiz.ID, userID)
+		if err := cache.SAdd(key, questionID); err != nil {
+			// Без учёта ошибки игрок не выбывает: жизни не должны сгорать из-за сбоя Redis
+			log.Printf("[GameMode] WARNING: Не удалось учесть ошибку пользователя #%d в викторине #%d: %v", userID, quiz.ID, err)
+			return mistakeOutcome{}
+		}
+		if err := cache.Expire(key, gameModeKeyTTL); err != nil {
+			log.Printf("[GameMode] WARNING: Не удалось установить TTL счётчика ошибок %s: %v", key, err)
+		}
+		mistakes, err := cache.SCard(key)
+		if err != nil {
+			log.Printf("[GameMode] WARNING: Не удалось посчитать ошибки пользователя #%d в викторине #%d: %v", userID, quiz.ID, err)
+			return mistakeOutcome{}
+		}
+		livesLeft := quiz.Lives - int(mistakes)
+		if livesLeft < 0 {
+			livesLeft = 0
+		}
+		return mistakeOutcome{Eliminate: livesLeft == 0, LivesLeft: &livesLeft}
+	default:
+		return mistakeOutcome{Eliminate: true}
+	}
+}
+
+// activeParticipants возвращает не выбывших участников викторины
+func (qm *QuestionManager) activeParticipants(quizID uint) ([]uint, error) {
+	participantStrings, err := qm.deps.CacheRepo.SMembers(fmt.Sprintf("quiz:%d:participants", quizID))
+	if err != nil {
+		return nil, err
+	}
+	userIDs := make([]uint, 0, len(participantStrings))
+	eliminationKeys := make([]string, 0, len(participantStrings))
+	for _, userIDStr := range participantStrings {
+		userID, parseErr := strconv.ParseUint(userIDStr, 10, 64)
+		if parseErr != nil {
+			continue
+		}
+		userIDs = append(userIDs, uint(userID))
+		eliminationKeys = append(eliminationKeys, fmt.Sprintf("quiz:%d:eliminated:%d", quizID, userID))
+	}
+	if len(userIDs) == 0 {
+		return userIDs, nil
+	}
+	eliminatedMap, err := qm.deps.CacheRepo.ExistsBatch(eliminationKeys)
+	if err != nil {
+		return nil, err
+	}
+	active := make([]uint, 0, len(userIDs))
+	for i, userID := range userIDs {
+		if !eliminatedMap[eliminationKeys[i]] {
+			active = append(active, userID)
+		}
+	}
+	return active, nil
+}
+
+// nextSuddenDeathRound решает, нужен ли ещё раунд финала на выбывание после round сыгранных:
+// финал включён в викторине с выбыванием, раунды не исчерпаны и в игре больше одного игрока
+func (qm *QuestionManager) nextSuddenDeathRound(ctx context.Context, quizState *ActiveQuizState, round int) bool {
+	quiz := quizState.Quiz
+	if !quiz.SuddenDeath || !quiz.EliminatesPlayers() || round >= qm.config.SuddenDeathMaxRounds {
+		return false
+	}
+	active, err := qm.activeParticipants(quiz.ID)
+	if err != nil {
+		log.Printf("[QuestionManager] WARNING: Не удалось получить оставшихся игроков викторины #%d для финала: %v", quiz.ID, err)
+		return false
+	}
+	if len(active) <= 1 {
+		return false
+	}
+	log.Printf("[QuestionManager] Викторина #%d: финал на выбывание, раунд %d, в игре %d игроков", quiz.ID, round+1, len(active))
+	if round == 0 {
+		event := map[string]interface{}{
+			"quiz_id":    quiz.ID,
+			"players":    len(active),
+			"max_rounds": qm.config.SuddenDeathMaxRounds,
+		}
+		if err := qm.sendEventWithRetry(ctx, quiz.ID, "quiz:sudden_death", event); err != nil {
+			log.Printf("[QuestionManager] WARNING: Не удалось отправить quiz:sudden_death для викторины #%d: %v", quiz.ID, err)
+		}
+	}
+	return true
+}
+
+// resolveSuddenDeathRound подводит итог раунда финала: ошибившиеся и не ответившие выбывают,
+// если хотя бы один игрок ответил верно. Если не ответил верно никто, раунд аннулируется
+// и в игре остаются все. Возвращает true, если раунд аннулирован.
+func (qm *QuestionManager) resolveSuddenDeathRound(ctx context.Context, quizState *ActiveQuizState, question *entity.Question, questionNumber int) bool {
+	quizID := quizState.Quiz.ID
+	correctKey := suddenDeathCorrectKey(quizID, question.ID)
+	defer func() {
+		if err := qm.deps.CacheRepo.Delete(correctKey); err != nil {
+			log.Printf("[QuestionManager] WARNING: Не удалось удалить %s: %v", correctKey, err)
+		}
+	}()
+
+	active, err := qm.activeParticipants(quizID)
+	if err != nil {
+		log.Printf("[QuestionManager] WARNING: Не удалось получить игроков финала викторины #%d: %v", quizID, err)
+		return false
+	}
+	correct := make(map[uint]bool)
+	for _, userID := range active {
+		ok, err := qm.deps.CacheRepo.SIsMember(correctKey, userID)
+		if err != nil {
+			// Презумпция невиновности: при сбое Redis игрок не выбывает
+			log.Printf("[QuestionManager] WARNING: Не удалось проверить ответ пользователя #%d в финале: %v", userID, err)
+			ok = true
+		}
+		if ok {
+			correct[userID] = true
+		}
+	}
+
+	if len(correct) == 0 {
+		log.Printf("[QuestionManager] Викторина #%d: в раунде финала (вопрос #%d) никто не ответил верно, раунд аннулирован", quizID, question.ID)
+		qm.mu.RLock()
+		voider := qm.answerVoider
+		qm.mu.RUnlock()
+		if voider != nil {
+			if _, err := voider.VoidQuestionAnswers(ctx, quizID, question.ID); err != nil {
+				log.Printf("[QuestionManager] ERROR: Не удалось аннулировать ответы раунда финала викторины #%d: %v", quizID, err)
+			}
+		}
+		qm.sendSuddenDeathResult(ctx, quizID, questionNumber, 0, len(active), true)
+		return true
+	}
+
+	eliminated := make([]uint, 0, len(active)-len(correct))
+	for _, userID := range active {
+		if correct[userID] {
+			continue
+		}
+		answerKey := fmt.Sprintf("quiz:%d:user:%d:question:%d", quizID, userID, question.ID)
+		answered, err := qm.deps.CacheRepo.Exists(answerKey)
+		if err == nil && !answered {
+			// Ответившие неверно уже записаны с выбыванием; не ответившим записываем пропуск
+			if err := qm.saveAnswer(&entity.UserAnswer{
+				UserID:            userID,
+				QuizID:            quizID,
+				QuestionID:        question.ID,
+				SelectedOption:    -1,
+				IsEliminated:      true,
+				EliminationReason: entity.AnswerReasonSuddenDeath,
+			}, questionNumber); err != nil {
+				log.Printf("[QuestionManager] WARNING: Не удалось сохранить пропуск финала пользователя #%d: %v", userID, err)
+			}
+		}
+		eliminationKey := fmt.Sprintf("quiz:%d:eliminated:%d", quizID, userID)
+		if err := qm.deps.CacheRepo.Set(eliminationKey, strconv.Itoa(questionNumber), gameModeKeyTTL); err != nil {
+			log.Printf("[QuestionManager] WARNING: Не удалось установить ключ выбывания %s: %v", eliminationKey, err)
+		}
+		qm.sendEliminationNotification(userID, quizID, entity.AnswerReasonSuddenDeath)
+		eliminated = append(eliminated, userID)
+	}
+	if admission := qm.getAdmission(); admission != nil {
+		admission.Release(quizID, eliminated...)
+	}
+	log.Printf("[QuestionManager] Викторина #%d: раунд финала (вопрос #%d) — выбыло %d, осталось %d",
+		quizID, question.ID, len(eliminated), len(correct))
+	qm.sendSuddenDeathResult(ctx, quizID, questionNumber, len(eliminated), len(correct), false)
+	return false
+}
+
+// sendSuddenDeathResult рассылает итог раунда финала на выбывание
+func (qm *QuestionManager) sendSuddenDeathResult(ctx context.Context, quizID uint, questionNumber, eliminated, remaining int, voided bool) {
+	event := map[string]interface{}{
+		"quiz_id":    quizID,
+		"number":     questionNumber,
+		"eliminated": eliminated,
+		"remaining":  remaining,
+		"voided":     voided,
+	}
+	if err := qm.sendEventWithRetry(ctx, quizID, "quiz:sudden_death_result", event); err != nil {
+		log.Printf("[QuestionManager] WARNING: Не удалось отправить quiz:sudden_death_result для викторины #%d: %v", quizID, err)
+	}
+}
+
+// sendLifeLost сообщает игроку режима lives, что пропуск вопроса стоил ему жизни
+func (qm *QuestionManager) sendLifeLost(userID, quizID, questionID uint, livesLeft int) {
+	event := map[string]interface{}{
+		"quiz_id":     quizID,
+		"question_id": questionID,
+		"lives_left":  livesLeft,
+	}
+	if err := qm.deps.WSManager.SendEventToUser(strconv.FormatUint(uint64(userID), 10), "quiz:life_lost", event); err != nil {
+		log.Printf("[QuestionManager] Ошибка при отправке quiz:life_lost пользователю #%d: %v", userID, err)
+	}
+}
@@ -0,0 +1,52 @@
+package quizmanager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+func TestRegisterMistake_ByGameMode(t *testing.T) {
+	cache := newMemoryAdmissionCache()
+
+	outcome := registerMistake(cache, &entity.Quiz{ID: 1}, 5, 10)
+	assert.True(t, outcome.Eliminate, "по умолчанию — выбывание с первой ошибки")
+	assert.Nil(t, outcome.LivesLeft)
+
+	outcome = registerMistake(cache, &entity.Quiz{ID: 2, GameMode: entity.GameModePointsOnly}, 5, 10)
+	assert.False(t, outcome.Eliminate)
+	assert.Nil(t, outcome.LivesLeft)
+}
+
+func TestRegisterMistake_Lives(t *testing.T) {
+	cache := newMemoryAdmissionCache()
+	quiz := &entity.Quiz{ID: 3, GameMode: entity.GameModeLives, Lives: 2}
+
+	outcome := registerMistake(cache, quiz, 5, 10)
+	require.NotNil(t, outcome.LivesLeft)
+	assert.False(t, outcome.Eliminate)
+	assert.Equal(t, 1, *outcome.LivesLeft)
+
+	// Повторная обработка того же вопроса не отнимает вторую жизнь
+	outcome = registerMistake(cache, quiz, 5, 10)
+	assert.False(t, outcome.Eliminate)
+	assert.Equal(t, 1, *outcome.LivesLeft)
+
+	// Жизни считаются для каждого игрока отдельно
+	outcome = registerMistake(cache, quiz, 6, 10)
+	assert.Equal(t, 1, *outcome.LivesLeft)
+
+	outcome = registerMistake(cache, quiz, 5, 11)
+	assert.True(t, outcome.Eliminate)
+	assert.Equal(t, 0, *outcome.LivesLeft)
+}
+
+func TestActiveQuizState_SuddenDeathRound(t *testing.T) {
+	state := NewActiveQuizState(&entity.Quiz{ID: 4, SuddenDeath: true})
+	assert.Equal(t, 0, state.SuddenDeathRound())
+	state.SetSuddenDeathRound(2)
+	assert.Equal(t, 2, state.SuddenDeathRound())
+}
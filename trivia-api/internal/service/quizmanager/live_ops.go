@@ -0,0 +1,445 @@
+package quizmanager
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// Ограничения команд администратора во время эфира
+const (
+	MaxQuestionExtensionSec  = 60  // Максимальное продление вопроса за одну команду
+	MaxAnnouncementLength    = 500 // Максимальная длина объявления в символах
+	MaxVoidReasonLength      = 200 // Максимальная длина причины снятия вопроса
+	defaultVoidReasonMessage = "Вопрос снят ведущим. Ответы на него не засчитываются."
+)
+
+// QuestionClock — таймер текущего вопроса, которым управляет администратор:
+// пауза, продление и снятие вопроса с эфира. Ожидание вопроса (Wait) и рассылка
+// quiz:timer читают остаток времени отсюда.
+type QuestionClock struct {
+	mu        sync.Mutex
+	deadline  time.Time     // Когда истекает время, если таймер идёт
+	remaining time.Duration // Остаток времени на момент паузы
+	extended  time.Duration // Суммарное продление
+	paused    bool
+	closed    bool // Время вышло или вопрос снят; команды больше не принимаются
+	voided    bool
+	reason    string
+	changed   chan struct{} // Закрывается при каждом изменении, чтобы разбудить Wait
+}
+
+// NewQuestionClock запускает таймер вопроса с лимитом limit
+func NewQuestionClock(limit time.Duration) *QuestionClock {
+	return newQuestionClockAt(time.Now(), limit)
+}
+
+// newQuestionClockAt запускает таймер, отсчитанный от момента отправки вопроса
+func newQuestionClockAt(start time.Time, limit time.Duration) *QuestionClock {
+	return &QuestionClock{
+		deadline: start.Add(limit),
+		changed:  make(chan struct{}),
+	}
+}
+
+// Wait ждёт окончания времени на вопрос с учётом пауз и продлений.
+// Возвращает true, если вопрос снят с эфира, и ошибку контекста при прерывании викторины.
+func (c *QuestionClock) Wait(ctx context.Context) (bool, error) {
+	for {
+		c.mu.Lock()
+		if c.voided {
+			c.mu.Unlock()
+			return true, nil
+		}
+		changed := c.changed
+		var expired <-chan time.Time
+		var timer *time.Timer
+		if !c.paused {
+			left := time.Until(c.deadline)
+			if left <= 0 {
+				c.closed = true
+				c.mu.Unlock()
+				return false, nil
+			}
+			timer = time.NewTimer(left)
+			expired = timer.C
+		}
+		c.mu.Unlock()
+
+		select {
+		case <-expired:
+		case <-changed:
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			return false, ctx.Err()
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// Remaining возвращает оставшееся время на вопрос
+func (c *QuestionClock) Remaining() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.remainingLocked()
+}
+
+// Deadline возвращает момент окончания времени на вопрос; на паузе он сдвигается вместе с текущим временем
+func (c *QuestionClock) Deadline() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.paused {
+		return time.Now().Add(c.remaining)
+	}
+	return c.deadline
+}
+
+// Paused сообщает, стоит ли таймер на паузе
+func (c *QuestionClock) Paused() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.paused
+}
+
+// Voided сообщает, снят ли вопрос с эфира
+func (c *QuestionClock) Voided() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.voided
+}
+
+// Pause останавливает отсчёт времени
+func (c *QuestionClock) Pause() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.checkOpenLocked(); err != nil {
+		return err
+	}
+	if c.paused {
+		return fmt.Errorf("%w: question timer is already paused", apperrors.ErrConflict)
+	}
+	c.remaining = c.remainingLocked()
+	c.paused = true
+	c.notifyLocked()
+	return nil
+}
+
+// Resume продолжает отсчёт с остатка, зафиксированного при паузе
+func (c *QuestionClock) Resume() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.checkOpenLocked(); err != nil {
+		return err
+	}
+	if !c.paused {
+		return fmt.Errorf("%w: question timer is not paused", apperrors.ErrConflict)
+	}
+	c.deadline = time.Now().Add(c.remaining)
+	c.paused = false
+	c.notifyLocked()
+	return nil
+}
+
+// Extend добавляет время к текущему вопросу (в том числе на паузе)
+func (c *QuestionClock) Extend(d time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.checkOpenLocked(); err != nil {
+		return err
+	}
+	if c.paused {
+		c.remaining += d
+	} else {
+		c.deadline = c.deadline.Add(d)
+	}
+	c.extended += d
+	c.notifyLocked()
+	return nil
+}
+
+// Void снимает вопрос с эфира: Wait сразу возвращает управление
+func (c *QuestionClock) Void(reason string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.checkOpenLocked(); err != nil {
+		return err
+	}
+	c.voided = true
+	c.closed = true
+	c.reason = reason
+	c.notifyLocked()
+	return nil
+}
+
+// VoidReason возвращает причину снятия вопроса, указанную администратором
+func (c *QuestionClock) VoidReason() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.reason
+}
+
+func (c *QuestionClock) remainingLocked() time.Duration {
+	switch {
+	case c.voided:
+		return 0
+	case c.paused:
+		return c.remaining
+	}
+	if left := time.Until(c.deadline); left > 0 {
+		return left
+	}
+	return 0
+}
+
+func (c *QuestionClock) checkOpenLocked() error {
+	if c.closed {
+		return fmt.Errorf("%w: question is already closed", apperrors.ErrConflict)
+	}
+	return nil
+}
+
+func (c *QuestionClock) notifyLocked() {
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+// LiveQuestionStatus — состояние текущего вопроса для консоли администратора
+type LiveQuestionStatus struct {
+	QuizID          uint  `json:"quiz_id"`
+	QuestionID      uint  `json:"question_id"`
+	Number          int   `json:"number"`
+	Paused          bool  `json:"paused"`
+	Voided          bool  `json:"voided"`
+	RemainingMs     int64 `json:"remaining_ms"`
+	ExtendedSeconds int   `json:"extended_seconds"`
+	Deadline        int64 `json:"deadline,omitempty"` // unix ms окончания вопроса; на паузе не задан
+}
+
+// liveQuestion возвращает текущий вопрос и его таймер; команды без запущенного вопроса отклоняются
+func liveQuestion(quizState *ActiveQuizState) (*entity.Question, int, *QuestionClock, error) {
+	question, number := quizState.GetCurrentQuestion()
+	clock := quizState.QuestionClock()
+	if question == nil || clock == nil {
+		return nil, 0, nil, fmt.Errorf("%w: no question is running in quiz #%d", apperrors.ErrConflict, quizState.Quiz.ID)
+	}
+	return question, number, clock, nil
+}
+
+func liveStatus(quizID uint, question *entity.Question, number int, clock *QuestionClock) *LiveQuestionStatus {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	status := &LiveQuestionStatus{
+		QuizID:          quizID,
+		QuestionID:      question.ID,
+		Number:          number,
+		Paused:          clock.paused,
+		Voided:          clock.voided,
+		RemainingMs:     clock.remainingLocked().Milliseconds(),
+		ExtendedSeconds: int(clock.extended / time.Second),
+	}
+	if !clock.paused && !clock.voided {
+		status.Deadline = clock.deadline.UnixMilli()
+	}
+	return status
+}
+
+// PauseQuestion ставит таймер текущего вопроса на паузу. Ответы принимаются и на паузе:
+// время, проведённое на паузе, добавляется к дедлайну.
+func (qm *QuestionManager) PauseQuestion(ctx context.Context, quizState *ActiveQuizState) (*LiveQuestionStatus, error) {
+	question, number, clock, err := liveQuestion(quizState)
+	if err != nil {
+		return nil, err
+	}
+	if err := clock.Pause(); err != nil {
+		return nil, err
+	}
+	status := liveStatus(quizState.Quiz.ID, question, number, clock)
+	log.Printf("[QuestionManager] Викторина #%d: таймер вопроса #%d поставлен на паузу (осталось %d мс)",
+		quizState.Quiz.ID, question.ID, status.RemainingMs)
+	qm.broadcastLiveEvent(ctx, quizState.Quiz.ID, "quiz:timer_paused", status, nil)
+	return status, nil
+}
+
+// ResumeQuestion снимает таймер текущего вопроса с паузы
+func (qm *QuestionManager) ResumeQuestion(ctx context.Context, quizState *ActiveQuizState) (*LiveQuestionStatus, error) {
+	question, number, clock, err := liveQuestion(quizState)
+	if err != nil {
+		return nil, err
+	}
+	if err := clock.Resume(); err != nil {
+		return nil, err
+	}
+	status := liveStatus(quizState.Quiz.ID, question, number, clock)
+	log.Printf("[QuestionManager] Викторина #%d: таймер вопроса #%d возобновлён (осталось %d мс)",
+		quizState.Quiz.ID, question.ID, status.RemainingMs)
+	qm.broadcastLiveEvent(ctx, quizState.Quiz.ID, "quiz:timer_resumed", status, nil)
+	return status, nil
+}
+
+// ExtendQuestion добавляет seconds секунд к текущему вопросу
+func (qm *QuestionManager) ExtendQuestion(ctx context.Context, quizState *ActiveQuizState, seconds int) (*LiveQuestionStatus, error) {
+	if seconds < 1 || seconds > MaxQuestionExtensionSec {
+		return nil, fmt.Errorf("%w: seconds must be between 1 and %d", apperrors.ErrValidation, MaxQuestionExtensionSec)
+	}
+	question, number, clock, err := liveQuestion(quizState)
+	if err != nil {
+		return nil, err
+	}
+	if err := clock.Extend(time.Duration(seconds) * time.Second); err != nil {
+		return nil, err
+	}
+	status := liveStatus(quizState.Quiz.ID, question, number, clock)
+	log.Printf("[QuestionManager] Викторина #%d: вопрос #%d продлён на %d с (осталось %d мс)",
+		quizState.Quiz.ID, question.ID, seconds, status.RemainingMs)
+	qm.broadcastLiveEvent(ctx, quizState.Quiz.ID, "quiz:time_extended", status, map[string]interface{}{
+		"added_seconds": seconds,
+	})
+	return status, nil
+}
+
+// SkipQuestion снимает текущий вопрос с эфира. Сам вопрос аннулирует RunQuizQuestions:
+// ответы на него не засчитываются, а его номер занимает следующий вопрос.
+func (qm *QuestionManager) SkipQuestion(quizState *ActiveQuizState, reason string) (*LiveQuestionStatus, error) {
+	reason = strings.TrimSpace(reason)
+	if utf8.RuneCountInString(reason) > MaxVoidReasonLength {
+		return nil, fmt.Errorf("%w: reason must be at most %d characters", apperrors.ErrValidation, MaxVoidReasonLength)
+	}
+	question, number, clock, err := liveQuestion(quizState)
+	if err != nil {
+		return nil, err
+	}
+	if err := clock.Void(reason); err != nil {
+		return nil, err
+	}
+	log.Printf("[QuestionManager] Викторина #%d: вопрос #%d (номер %d) снят с эфира администратором: %s",
+		quizState.Quiz.ID, question.ID, number, reason)
+	return liveStatus(quizState.Quiz.ID, question, number, clock), nil
+}
+
+// Announce рассылает участникам викторины объявление администратора
+func (qm *QuestionManager) Announce(ctx context.Context, quizState *ActiveQuizState, message string) error {
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return fmt.Errorf("%w: message is required", apperrors.ErrValidation)
+	}
+	if utf8.RuneCountInString(message) > MaxAnnouncementLength {
+		return fmt.Errorf("%w: message must be at most %d characters", apperrors.ErrValidation, MaxAnnouncementLength)
+	}
+	event := map[string]interface{}{
+		"quiz_id":          quizState.Quiz.ID,
+		"message":          message,
+		"server_timestamp": time.Now().UnixMilli(),
+	}
+	return qm.sendEventWithRetry(ctx, quizState.Quiz.ID, "quiz:announcement", event)
+}
+
+// broadcastLiveEvent рассылает участникам изменение таймера текущего вопроса
+func (qm *QuestionManager) broadcastLiveEvent(ctx context.Context, quizID uint, eventType string, status *LiveQuestionStatus, extra map[string]interface{}) {
+	event := map[string]interface{}{
+		"question_id":      status.QuestionID,
+		"remaining_ms":     status.RemainingMs,
+		"paused":           status.Paused,
+		"server_timestamp": time.Now().UnixMilli(),
+	}
+	if status.Deadline != 0 {
+		event["deadline"] = status.Deadline
+	}
+	for key, value := range extra {
+		event[key] = value
+	}
+	if err := qm.sendEventWithRetry(ctx, quizID, eventType, event); err != nil {
+		log.Printf("[QuestionManager] WARNING: Не удалось отправить %s для викторины #%d: %v", eventType, quizID, err)
+	}
+}
+
+// SetAnswerVoider подключает аннулирование ответов на снятые с эфира вопросы
+func (qm *QuestionManager) SetAnswerVoider(voider AnswerVoider) {
+	qm.mu.Lock()
+	defer qm.mu.Unlock()
+	qm.answerVoider = voider
+}
+
+// voidQuestion аннулирует снятый с эфира вопрос: ответы на него не приносят очков,
+// ответившие на него игроки возвращаются в игру, статистика адаптивной сложности по номеру сбрасывается
+func (qm *QuestionManager) voidQuestion(ctx context.Context, quizState *ActiveQuizState, question *entity.Question, questionNumber int, reason string) {
+	quizID := quizState.Quiz.ID
+
+	qm.mu.RLock()
+	voider := qm.answerVoider
+	qm.mu.RUnlock()
+	if voider != nil {
+		voided, err := voider.VoidQuestionAnswers(ctx, quizID, question.ID)
+		if err != nil {
+			log.Printf("[QuestionManager] ERROR: Не удалось аннулировать ответы на вопрос #%d викторины #%d: %v", question.ID, quizID, err)
+		} else {
+			log.Printf("[QuestionManager] Викторина #%d: аннулировано %d ответов на вопрос #%d", quizID, voided, question.ID)
+		}
+	}
+
+	// Ответить на вопрос могли только не выбывшие игроки, поэтому выбывание каждого ответившего
+	// произошло на этом вопросе и снимается вместе с ним
+	participantStrings, err := qm.deps.CacheRepo.SMembers(fmt.Sprintf("quiz:%d:participants", quizID))
+	if err != nil {
+		log.Printf("[QuestionManager] WARNING: Не удалось получить участников викторины #%d для аннулирования вопроса: %v", quizID, err)
+	}
+	answerKeys := make([]string, 0, len(participantStrings))
+	userIDs := make([]uint64, 0, len(participantStrings))
+	for _, userIDStr := range participantStrings {
+		userID, parseErr := strconv.ParseUint(userIDStr, 10, 64)
+		if parseErr != nil {
+			continue
+		}
+		userIDs = append(userIDs, userID)
+		answerKeys = append(answerKeys, fmt.Sprintf("quiz:%d:user:%d:question:%d", quizID, userID, question.ID))
+	}
+	if len(answerKeys) > 0 {
+		answeredMap, err := qm.deps.CacheRepo.ExistsBatch(answerKeys)
+		if err != nil {
+			log.Printf("[QuestionManager] WARNING: Не удалось проверить ответы на снятый вопрос #%d: %v", question.ID, err)
+		}
+		for i, key := range answerKeys {
+			if !answeredMap[key] {
+				continue
+			}
+			eliminationKey := fmt.Sprintf("quiz:%d:eliminated:%d", quizID, userIDs[i])
+			if err := qm.deps.CacheRepo.Delete(eliminationKey); err != nil {
+				log.Printf("[QuestionManager] WARNING: Не удалось снять выбывание %s: %v", eliminationKey, err)
+			}
+		}
+	}
+
+	for _, key := range []string{
+		fmt.Sprintf("quiz:%d:q%d:total", quizID, questionNumber),
+		fmt.Sprintf("quiz:%d:q%d:passed", quizID, questionNumber),
+	} {
+		if err := qm.deps.CacheRepo.Delete(key); err != nil {
+			log.Printf("[QuestionManager] WARNING: Не удалось сбросить статистику %s: %v", key, err)
+		}
+	}
+
+	message := reason
+	if message == "" {
+		message = defaultVoidReasonMessage
+	}
+	voidedEvent := map[string]interface{}{
+		"quiz_id":     quizID,
+		"question_id": question.ID,
+		"number":      questionNumber,
+		"message":     message,
+	}
+	if err := qm.sendEventWithRetry(ctx, quizID, "quiz:question_voided", voidedEvent); err != nil {
+		log.Printf("[QuestionManager] WARNING: Не удалось отправить quiz:question_voided для вопроса #%d: %v", question.ID, err)
+	}
+}
@@ -0,0 +1,87 @@
+package quizmanager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+func TestQuestionClock_PauseResumeExtend(t *testing.T) {
+	clock := NewQuestionClock(200 * time.Millisecond)
+
+	require.NoError(t, clock.Pause())
+	assert.ErrorIs(t, clock.Pause(), apperrors.ErrConflict)
+	paused := clock.Remaining()
+	time.Sleep(50 * time.Millisecond)
+	// На паузе остаток не уменьшается
+	assert.Equal(t, paused, clock.Remaining())
+
+	require.NoError(t, clock.Extend(time.Second))
+	assert.Equal(t, paused+time.Second, clock.Remaining())
+	require.NoError(t, clock.Resume())
+	assert.ErrorIs(t, clock.Resume(), apperrors.ErrConflict)
+
+	start := time.Now()
+	voided, err := clock.Wait(context.Background())
+	require.NoError(t, err)
+	assert.False(t, voided)
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+
+	// После окончания времени команды отклоняются
+	assert.ErrorIs(t, clock.Extend(time.Second), apperrors.ErrConflict)
+	assert.ErrorIs(t, clock.Void(""), apperrors.ErrConflict)
+}
+
+func TestQuestionClock_VoidWakesWait(t *testing.T) {
+	clock := NewQuestionClock(time.Minute)
+	require.NoError(t, clock.Pause())
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		_ = clock.Void("опечатка в вариантах")
+	}()
+	voided, err := clock.Wait(context.Background())
+	require.NoError(t, err)
+	assert.True(t, voided)
+	assert.Equal(t, "опечатка в вариантах", clock.VoidReason())
+	assert.Zero(t, clock.Remaining())
+}
+
+func TestActiveQuizState_QuestionTimeLimitFollowsClock(t *testing.T) {
+	question := &entity.Question{ID: 5, TimeLimitSec: 10}
+	state := NewActiveQuizState(&entity.Quiz{ID: 1})
+	state.SetCurrentQuestion(question, 1)
+	startMs := time.Now().UnixMilli()
+
+	// Без таймера действует лимит вопроса
+	assert.Equal(t, int64(10000), state.QuestionTimeLimitMs(question, startMs))
+
+	clock := NewQuestionClock(10 * time.Second)
+	state.SetQuestionClock(clock)
+	require.NoError(t, clock.Extend(5*time.Second))
+	assert.InDelta(t, 15000, state.QuestionTimeLimitMs(question, startMs), 100)
+
+	// Дедлайн в событиях совпадает с дедлайном приёма ответов; на паузе он не задан
+	status := liveStatus(1, question, 1, clock)
+	assert.Equal(t, clock.Deadline().UnixMilli(), status.Deadline)
+	require.NoError(t, clock.Pause())
+	assert.Zero(t, liveStatus(1, question, 1, clock).Deadline)
+	require.NoError(t, clock.Resume())
+
+	// Лимит чужого вопроса не зависит от таймера текущего
+	other := &entity.Question{ID: 6, TimeLimitSec: 7}
+	assert.Equal(t, int64(7000), state.QuestionTimeLimitMs(other, startMs))
+
+	// Следующий вопрос начинается без таймера предыдущего
+	state.SetCurrentQuestion(other, 2)
+	assert.Nil(t, state.QuestionClock())
+
+	_, err := (&QuestionManager{}).PauseQuestion(context.Background(), state)
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+}
@@ -0,0 +1,251 @@
+package quizmanager
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+// Источники вопросов в предпросмотре
+const (
+	PreviewSourceQuiz = "quiz"
+	PreviewSourcePool = "pool"
+)
+
+// QuizPreview — предпросмотр викторины до начала: вероятный набор вопросов, кривая сложности,
+// оценка длительности по текущим таймингам и расстановка рекламных пауз
+type QuizPreview struct {
+	QuizID             uint                   `json:"quiz_id"`
+	Mode               string                 `json:"mode"`
+	TotalQuestions     int                    `json:"total_questions"`
+	SelectedQuestions  int                    `json:"selected_questions"`
+	EstimatedDuration  int64                  `json:"estimated_duration_ms"`
+	ScheduledTime      *time.Time             `json:"scheduled_time,omitempty"`
+	EstimatedEnd       *time.Time             `json:"estimated_end,omitempty"`
+	Questions          []PreviewQuestion      `json:"questions"`
+	AdSlots            []PreviewAdSlot        `json:"ad_slots"`
+	PoolByDifficulty   map[int]int64          `json:"pool_by_difficulty,omitempty"`
+	DifficultyCurve    []PreviewDifficultyDot `json:"difficulty_curve"`
+	UnfilledQuestions  []int                  `json:"unfilled_questions,omitempty"`
+	DifficultyFallback int                    `json:"difficulty_fallback"`
+}
+
+// PreviewQuestion — вопрос, который вероятно будет задан под номером Number
+type PreviewQuestion struct {
+	Number           int    `json:"number"`
+	QuestionID       uint   `json:"question_id"`
+	Text             string `json:"text"`
+	Source           string `json:"source"`
+	Difficulty       int    `json:"difficulty"`
+	TargetDifficulty int    `json:"target_difficulty"`
+	TimeLimitSec     int    `json:"time_limit_sec"`
+	StartsAtMs       int64  `json:"starts_at_ms"` // смещение от начала викторины
+}
+
+// PreviewDifficultyDot — точка кривой сложности: целевые сложность и pass rate вопроса
+type PreviewDifficultyDot struct {
+	Number         int     `json:"number"`
+	Difficulty     int     `json:"difficulty"`
+	TargetPassRate float64 `json:"target_pass_rate"`
+}
+
+// PreviewAdSlot — рекламная пауза и её место в таймлайне
+type PreviewAdSlot struct {
+	SlotID        uint   `json:"slot_id"`
+	QuestionAfter int    `json:"question_after"`
+	DurationMs    int64  `json:"duration_ms"`
+	StartsAtMs    int64  `json:"starts_at_ms,omitempty"`
+	WillShow      bool   `json:"will_show"`
+	Reason        string `json:"reason,omitempty"`
+}
+
+// Previewer строит предпросмотр викторины. Вопросы выбирает AdaptiveQuestionSelector в режиме
+// предпросмотра: pass rate каждого вопроса считается равным целевому, поэтому сложность идёт по
+// базовой схеме. Ничего не пишется: история вопросов и пометки is_used не меняются.
+type Previewer struct {
+	config   *Config
+	deps     *Dependencies
+	selector *AdaptiveQuestionSelector
+}
+
+// NewPreviewer создает построитель предпросмотра
+func NewPreviewer(config *Config, deps *Dependencies) *Previewer {
+	return &Previewer{
+		config:   config,
+		deps:     deps,
+		selector: NewAdaptiveQuestionSelector(DefaultDifficultyConfig(), deps),
+	}
+}
+
+// Preview строит предпросмотр викторины quizID
+func (p *Previewer) Preview(quizID uint) (*QuizPreview, error) {
+	quiz, err := p.deps.QuizRepo.GetWithQuestions(quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	total := plannedQuestionCount(quiz, p.config)
+	preview := &QuizPreview{
+		QuizID:         quiz.ID,
+		Mode:           quiz.QuestionSourceMode,
+		TotalQuestions: total,
+		Questions:      []PreviewQuestion{},
+		AdSlots:        []PreviewAdSlot{},
+	}
+	if !quiz.IsAdminOnlyMode() {
+		_, _, byDifficulty, err := p.deps.QuestionRepo.GetPoolStats()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get question pool stats: %w", err)
+		}
+		preview.PoolByDifficulty = byDifficulty
+	}
+
+	difficulty := p.selector.config
+	picks := p.selector.PreviewQuestions(quiz.ID, total, !quiz.IsAdminOnlyMode(), quiz.CategoryID, quiz.RequireVerifiedKK)
+	for i, question := range picks {
+		number := i + 1
+		target := difficulty.GetBaseDifficulty(number)
+		preview.DifficultyCurve = append(preview.DifficultyCurve, PreviewDifficultyDot{
+			Number:         number,
+			Difficulty:     target,
+			TargetPassRate: difficulty.GetTargetPassRate(number),
+		})
+		if question == nil {
+			preview.UnfilledQuestions = append(preview.UnfilledQuestions, number)
+			continue
+		}
+		source := PreviewSourcePool
+		if question.QuizID != nil {
+			source = PreviewSourceQuiz
+		}
+		if question.Difficulty != target {
+			preview.DifficultyFallback++
+		}
+		preview.Questions = append(preview.Questions, PreviewQuestion{
+			Number:           number,
+			QuestionID:       question.ID,
+			Text:             question.Text,
+			Source:           source,
+			Difficulty:       question.Difficulty,
+			TargetDifficulty: target,
+			TimeLimitSec:     question.TimeLimitSec,
+		})
+	}
+	preview.SelectedQuestions = len(preview.Questions)
+
+	slots, err := p.listAdSlots(quiz.ID)
+	if err != nil {
+		return nil, err
+	}
+	p.buildTimeline(preview, slots, p.config.Timing().ForQuiz(quiz))
+
+	if quiz.IsScheduled() && !quiz.ScheduledTime.IsZero() {
+		start := quiz.ScheduledTime
+		end := start.Add(time.Duration(preview.EstimatedDuration) * time.Millisecond)
+		preview.ScheduledTime = &start
+		preview.EstimatedEnd = &end
+	}
+	return preview, nil
+}
+
+// listAdSlots возвращает рекламные слоты викторины
+func (p *Previewer) listAdSlots(quizID uint) ([]entity.QuizAdSlot, error) {
+	if p.deps.QuizAdSlotRepo == nil {
+		return nil, nil
+	}
+	slots, err := p.deps.QuizAdSlotRepo.ListByQuizID(quizID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ad slots: %w", err)
+	}
+	return slots, nil
+}
+
+// buildTimeline расставляет вопросы и рекламные паузы по времени так же, как RunQuizQuestions.
+// Вопросы без найденного кандидата считаются с лимитом по умолчанию.
+func (p *Previewer) buildTimeline(preview *QuizPreview, slots []entity.QuizAdSlot, timing Timing) {
+	byNumber := make(map[int]*PreviewQuestion, len(preview.Questions))
+	for i := range preview.Questions {
+		byNumber[preview.Questions[i].Number] = &preview.Questions[i]
+	}
+	breaks := make(map[int]int) // номер вопроса → индекс в preview.AdSlots
+	for i := range slots {
+		slot := &slots[i]
+		item := PreviewAdSlot{SlotID: slot.ID, QuestionAfter: slot.QuestionAfter}
+		duration := adBreakDuration(slot, timing)
+		item.DurationMs = duration.Milliseconds()
+		switch {
+		case !slot.IsActive:
+			item.Reason = "inactive"
+		case slot.AdAsset == nil:
+			item.Reason = "ad asset not found"
+		case slot.QuestionAfter < 1 || slot.QuestionAfter > preview.TotalQuestions:
+			item.Reason = fmt.Sprintf("quiz has %d questions", preview.TotalQuestions)
+		case duration <= 0:
+			item.Reason = "zero duration"
+		default:
+			item.WillShow = true
+		}
+		if item.WillShow {
+			breaks[slot.QuestionAfter] = len(preview.AdSlots)
+		}
+		preview.AdSlots = append(preview.AdSlots, item)
+	}
+
+	var elapsed time.Duration
+	for number := 1; number <= preview.TotalQuestions; number++ {
+		limit := defaultQuestionTimeLimitSec
+		if question, ok := byNumber[number]; ok {
+			question.StartsAtMs = (elapsed + time.Duration(timing.QuestionDelayMs)*time.Millisecond).Milliseconds()
+			if question.TimeLimitSec > 0 {
+				limit = question.TimeLimitSec
+			}
+		}
+		elapsed += questionDuration(timing, limit, number == preview.TotalQuestions)
+		if index, ok := breaks[number]; ok {
+			slot := &preview.AdSlots[index]
+			// Реклама идёт после раскрытия ответа, до паузы между вопросами
+			start := elapsed
+			if number < preview.TotalQuestions {
+				start -= time.Duration(timing.InterQuestionDelayMs) * time.Millisecond
+			}
+			slot.StartsAtMs = start.Milliseconds()
+			elapsed += time.Duration(slot.DurationMs) * time.Millisecond
+		}
+	}
+	preview.EstimatedDuration = elapsed.Milliseconds()
+}
+
+// plannedQuestionCount возвращает число вопросов, которое задаст RunQuizQuestions
+func plannedQuestionCount(quiz *entity.Quiz, config *Config) int {
+	if quiz.IsAdminOnlyMode() {
+		if approved := len(quiz.ApprovedQuestions()); approved > 0 {
+			return approved
+		}
+		return quiz.QuestionCount
+	}
+	if quiz.QuestionCount > 0 {
+		return quiz.QuestionCount
+	}
+	return config.MaxQuestionsPerQuiz
+}
+
+// questionDuration — время одного вопроса в RunQuizQuestions: задержка отправки, лимит ответа,
+// задержка раскрытия ответа и пауза перед следующим вопросом (после последнего её нет)
+func questionDuration(timing Timing, timeLimitSec int, last bool) time.Duration {
+	d := time.Duration(timing.QuestionDelayMs+timing.AnswerRevealDelayMs)*time.Millisecond +
+		timing.QuestionTimeLimit(timeLimitSec)
+	if !last {
+		d += time.Duration(timing.InterQuestionDelayMs) * time.Millisecond
+	}
+	return d
+}
+
+// adBreakDuration — длительность рекламной паузы слота в processAdBreak с запасом на буферизацию видео
+func adBreakDuration(slot *entity.QuizAdSlot, timing Timing) time.Duration {
+	playback := timing.AdBreakPlayback(slot)
+	if playback <= 0 || slot.AdAsset == nil || !slot.AdAsset.IsVideo() {
+		return playback
+	}
+	return playback + time.Duration(timing.AdVideoBufferMs)*time.Millisecond
+}
@@ -0,0 +1,96 @@
+package quizmanager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+)
+
+// previewQuestionRepo отдаёт первый неиспользованный одобренный вопрос викторины нужной сложности
+type previewQuestionRepo struct {
+	*MockQuestionRepoForScheduler
+	questions []entity.Question
+}
+
+func (r *previewQuestionRepo) GetQuizQuestionByDifficulty(quizID uint, difficulty int, excludeIDs []uint, verifiedKK bool) (*entity.Question, error) {
+	excluded := make(map[uint]bool, len(excludeIDs))
+	for _, id := range excludeIDs {
+		excluded[id] = true
+	}
+	for i := range r.questions {
+		if r.questions[i].Difficulty == difficulty && r.questions[i].IsApproved() && !excluded[r.questions[i].ID] {
+			return &r.questions[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// previewAdSlotRepo возвращает заданные слоты викторины
+type previewAdSlotRepo struct {
+	repository.QuizAdSlotRepository
+	slots []entity.QuizAdSlot
+}
+
+func (r *previewAdSlotRepo) ListByQuizID(quizID uint) ([]entity.QuizAdSlot, error) {
+	return r.slots, nil
+}
+
+func TestPreviewer_AdminOnlyTimeline(t *testing.T) {
+	quizID := uint(1)
+	questions := []entity.Question{
+		{ID: 10, QuizID: &quizID, Difficulty: 1, TimeLimitSec: 10, ReviewStatus: entity.QuestionReviewApproved},
+		{ID: 20, QuizID: &quizID, Difficulty: 2, TimeLimitSec: 10, ReviewStatus: entity.QuestionReviewApproved},
+		{ID: 30, QuizID: &quizID, Difficulty: 4, TimeLimitSec: 10, ReviewStatus: entity.QuestionReviewApproved},
+		// Черновик не попадает ни в число вопросов, ни в выборку
+		{ID: 40, QuizID: &quizID, Difficulty: 2, TimeLimitSec: 10, ReviewStatus: entity.QuestionReviewDraft},
+	}
+	quiz := &entity.Quiz{ID: quizID, Status: entity.QuizStatusScheduled, ScheduledTime: time.Now().Add(time.Hour), QuestionSourceMode: entity.QuizQuestionSourceAdminOnly, Questions: questions}
+
+	mockQuizRepo := new(MockQuizRepoForScheduler)
+	mockQuizRepo.On("GetWithQuestions", quizID).Return(quiz, nil)
+	duration := 15
+	slots := []entity.QuizAdSlot{
+		{ID: 1, QuizID: quizID, QuestionAfter: 2, IsActive: true, DurationSec: &duration, AdAsset: &entity.AdAsset{MediaType: "image", DurationSec: 5}},
+		{ID: 2, QuizID: quizID, QuestionAfter: 5, IsActive: true, AdAsset: &entity.AdAsset{MediaType: "image", DurationSec: 5}},
+	}
+
+	config := DefaultConfig()
+	config.SetTiming(Timing{QuestionDelayMs: 500, AnswerRevealDelayMs: 200, InterQuestionDelayMs: 500, AdVideoBufferMs: 1500})
+	previewer := NewPreviewer(config, &Dependencies{
+		QuizRepo:       mockQuizRepo,
+		QuestionRepo:   &previewQuestionRepo{MockQuestionRepoForScheduler: new(MockQuestionRepoForScheduler), questions: questions},
+		QuizAdSlotRepo: &previewAdSlotRepo{slots: slots},
+	})
+
+	preview, err := previewer.Preview(quizID)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, preview.TotalQuestions)
+	assert.Equal(t, 3, preview.SelectedQuestions)
+	assert.Empty(t, preview.UnfilledQuestions)
+	require.Len(t, preview.Questions, 3)
+	assert.Equal(t, []uint{10, 20, 30}, []uint{preview.Questions[0].QuestionID, preview.Questions[1].QuestionID, preview.Questions[2].QuestionID})
+	assert.Equal(t, PreviewSourceQuiz, preview.Questions[0].Source)
+	// Для третьего вопроса базовая сложность 2, но у викторины остался только вопрос сложности 4
+	assert.Equal(t, 2, preview.Questions[2].TargetDifficulty)
+	assert.Equal(t, 1, preview.DifficultyFallback)
+	assert.Len(t, preview.DifficultyCurve, 3)
+
+	// Вопрос: 500 + 10000 + 200 (+ 500 паузы, кроме последнего); реклама 15 с после второго
+	assert.Equal(t, int64(500), preview.Questions[0].StartsAtMs)
+	assert.Equal(t, int64(11700), preview.Questions[1].StartsAtMs)
+	assert.Equal(t, int64(37900), preview.Questions[2].StartsAtMs)
+	assert.Equal(t, int64(48100), preview.EstimatedDuration)
+
+	require.Len(t, preview.AdSlots, 2)
+	assert.True(t, preview.AdSlots[0].WillShow)
+	assert.Equal(t, int64(21900), preview.AdSlots[0].StartsAtMs)
+	assert.False(t, preview.AdSlots[1].WillShow)
+
+	require.NotNil(t, preview.EstimatedEnd)
+	assert.Equal(t, quiz.ScheduledTime.Add(48100*time.Millisecond), *preview.EstimatedEnd)
+}
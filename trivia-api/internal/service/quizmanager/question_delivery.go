@@ -0,0 +1,245 @@
+package quizmanager
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+const (
+	// questionDeliveryTTL — сколько живут подтверждения доставки (как и флаги ответов)
+	questionDeliveryTTL = time.Hour
+	// deliverySkewSampleLimit — сколько замеров задержки последнего вопроса хранится для перцентилей
+	deliverySkewSampleLimit = 50000
+)
+
+// questionDeliveryKey хранит момент (unix ms), когда игрок подтвердил получение вопроса
+func questionDeliveryKey(quizID, questionID, userID uint) string {
+	return fmt.Sprintf("quiz:%d:question:%d:delivered:%d", quizID, questionID, userID)
+}
+
+// questionDeliveriesKey — Sorted Set подтвердивших игроков; вес — выданная отсрочка (мс).
+// По последнему элементу определяется, сколько ждать ответов после общего дедлайна.
+func questionDeliveriesKey(quizID, questionID uint) string {
+	return fmt.Sprintf("quiz:%d:question:%d:deliveries", quizID, questionID)
+}
+
+// DeliveryEvents отправляет игроку его дедлайн (реализуется websocket.Manager)
+type DeliveryEvents interface {
+	SendEventToUser(userID string, eventType string, data interface{}) error
+}
+
+// DeliveryStats — задержка доставки вопросов игрокам по подтверждениям, принятым этим узлом
+type DeliveryStats struct {
+	QuizID     uint  `json:"quiz_id"`
+	QuestionID uint  `json:"question_id"` // последний вопрос, на который приходили подтверждения
+	Acks       int64 `json:"acks"`
+	// Capped — подтверждения с задержкой больше MaxDeliveryAllowanceMs: отсрочка ограничена
+	Capped           int64   `json:"capped"`
+	P50Ms            float64 `json:"p50_ms"`
+	P90Ms            float64 `json:"p90_ms"`
+	P99Ms            float64 `json:"p99_ms"`
+	MaxMs            float64 `json:"max_ms"`
+	MaxAllowanceMs   int64   `json:"max_allowance_ms"`
+	TotalAcks        int64   `json:"total_acks"` // с запуска узла
+	TotalCapped      int64   `json:"total_capped"`
+	AllowanceLimitMs int64   `json:"allowance_limit_ms"`
+}
+
+// DeliveryTracker ведёт персональные дедлайны вопросов. Игрок подтверждает получение вопроса
+// (user:question_received), и его отсчёт времени начинается с момента подтверждения, но не позже
+// MaxDeliveryAllowanceMs после отправки вопроса. Подтверждения хранятся в Redis, поэтому
+// ответ, принятый любым узлом, проверяется по тому же дедлайну.
+type DeliveryTracker struct {
+	config *Config
+	deps   *Dependencies
+	events DeliveryEvents
+
+	mu          sync.Mutex
+	quizID      uint
+	questionID  uint
+	skews       []time.Duration
+	acks        int64
+	capped      int64
+	totalAcks   int64
+	totalCapped int64
+}
+
+// NewDeliveryTracker создает учёт доставки вопросов
+func NewDeliveryTracker(config *Config, deps *Dependencies) *DeliveryTracker {
+	t := &DeliveryTracker{config: config, deps: deps}
+	if deps.WSManager != nil {
+		t.events = deps.WSManager
+	}
+	return t
+}
+
+// Acknowledge фиксирует подтверждение доставки вопроса игроку и отправляет ему персональный
+// дедлайн (quiz:question_deadline). Повторное подтверждение не сдвигает отсчёт.
+// deadline — общий дедлайн вопроса с учётом пауз и продлений.
+func (t *DeliveryTracker) Acknowledge(ctx context.Context, quizID, questionID, userID uint, startTimeMs int64, deadline time.Time) error {
+	cacheRepo := t.deps.CacheRepo.WithContext(ctx)
+
+	// Подтверждения выбывших и зрителей не влияют на дедлайны и не ждут их
+	isParticipant, err := cacheRepo.SIsMember(fmt.Sprintf("quiz:%d:participants", quizID), userID)
+	if err != nil {
+		return fmt.Errorf("redis error checking participant status: %w", err)
+	}
+	if !isParticipant {
+		return fmt.Errorf("%w: user is not a participant of this quiz", apperrors.ErrForbidden)
+	}
+	isEliminated, err := cacheRepo.Exists(fmt.Sprintf("quiz:%d:eliminated:%d", quizID, userID))
+	if err != nil {
+		return fmt.Errorf("redis error checking elimination status: %w", err)
+	}
+	if isEliminated {
+		return nil
+	}
+
+	nowMs := time.Now().UnixMilli()
+	claimed, err := cacheRepo.SetNX(questionDeliveryKey(quizID, questionID, userID), nowMs, questionDeliveryTTL)
+	if err != nil {
+		return fmt.Errorf("redis error recording question delivery: %w", err)
+	}
+	if !claimed {
+		return nil
+	}
+
+	skewMs := nowMs - startTimeMs
+	allowanceMs := t.allowanceMs(skewMs)
+	deliveriesKey := questionDeliveriesKey(quizID, questionID)
+	if _, err := cacheRepo.ZAddNX(deliveriesKey, float64(allowanceMs), userID); err != nil {
+		log.Printf("[DeliveryTracker] WARNING: Не удалось сохранить отсрочку пользователя #%d на вопрос #%d: %v", userID, questionID, err)
+	} else if err := cacheRepo.Expire(deliveriesKey, questionDeliveryTTL); err != nil {
+		log.Printf("[DeliveryTracker] WARNING: Не удалось установить TTL %s: %v", deliveriesKey, err)
+	}
+	t.record(quizID, questionID, time.Duration(skewMs)*time.Millisecond, allowanceMs > 0 && allowanceMs < skewMs)
+
+	if t.events != nil {
+		event := map[string]interface{}{
+			"question_id":  questionID,
+			"deadline":     deadline.UnixMilli() + allowanceMs,
+			"allowance_ms": allowanceMs,
+		}
+		if err := t.events.SendEventToUser(strconv.FormatUint(uint64(userID), 10), "quiz:question_deadline", event); err != nil {
+			log.Printf("[DeliveryTracker] Ошибка отправки персонального дедлайна пользователю #%d: %v", userID, err)
+		}
+	}
+	return nil
+}
+
+// UserAllowanceMs возвращает отсрочку игрока на вопрос; false — игрок не подтверждал получение
+func (t *DeliveryTracker) UserAllowanceMs(cacheRepo repository.CacheRepository, quizID, questionID, userID uint, startTimeMs int64) (int64, bool) {
+	raw, err := cacheRepo.Get(questionDeliveryKey(quizID, questionID, userID))
+	if err != nil {
+		return 0, false
+	}
+	deliveredMs, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		log.Printf("[DeliveryTracker] WARNING: Некорректное время доставки вопроса #%d пользователю #%d: %q", questionID, userID, raw)
+		return 0, false
+	}
+	return t.allowanceMs(deliveredMs - startTimeMs), true
+}
+
+// MaxAllowance возвращает наибольшую отсрочку, выданную на вопрос: столько после общего
+// дедлайна ещё принимаются ответы игроков, которым вопрос пришёл позже остальных
+func (t *DeliveryTracker) MaxAllowance(quizID, questionID uint, startTimeMs int64) time.Duration {
+	key := questionDeliveriesKey(quizID, questionID)
+	count, err := t.deps.CacheRepo.ZCard(key)
+	if err != nil || count == 0 {
+		return 0
+	}
+	latest, err := t.deps.CacheRepo.ZRange(key, count-1, count-1)
+	if err != nil || len(latest) == 0 {
+		return 0
+	}
+	userID, err := strconv.ParseUint(latest[0], 10, 64)
+	if err != nil {
+		return 0
+	}
+	allowanceMs, ok := t.UserAllowanceMs(t.deps.CacheRepo, quizID, questionID, uint(userID), startTimeMs)
+	if !ok {
+		return 0
+	}
+	return time.Duration(allowanceMs) * time.Millisecond
+}
+
+// Stats возвращает задержку доставки последнего вопроса викторины на этом узле
+func (t *DeliveryTracker) Stats(quizID uint) *DeliveryStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stats := &DeliveryStats{
+		QuizID:           quizID,
+		TotalAcks:        t.totalAcks,
+		TotalCapped:      t.totalCapped,
+		AllowanceLimitMs: t.config.MaxDeliveryAllowanceMs,
+	}
+	if t.quizID != quizID {
+		return stats
+	}
+	stats.QuestionID = t.questionID
+	stats.Acks = t.acks
+	stats.Capped = t.capped
+	if len(t.skews) == 0 {
+		return stats
+	}
+
+	samples := append([]time.Duration(nil), t.skews...)
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+	percentile := func(p float64) float64 {
+		rank := int(math.Ceil(p*float64(len(samples)))) - 1
+		if rank < 0 {
+			rank = 0
+		}
+		return float64(samples[rank]) / float64(time.Millisecond)
+	}
+	stats.P50Ms = percentile(0.50)
+	stats.P90Ms = percentile(0.90)
+	stats.P99Ms = percentile(0.99)
+	stats.MaxMs = float64(samples[len(samples)-1]) / float64(time.Millisecond)
+	stats.MaxAllowanceMs = t.allowanceMs(samples[len(samples)-1].Milliseconds())
+	return stats
+}
+
+// record учитывает задержку доставки; замеры сбрасываются с началом нового вопроса
+func (t *DeliveryTracker) record(quizID, questionID uint, skew time.Duration, capped bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.quizID != quizID || t.questionID != questionID {
+		t.quizID, t.questionID = quizID, questionID
+		t.skews = t.skews[:0]
+		t.acks, t.capped = 0, 0
+	}
+	if skew < 0 {
+		skew = 0
+	}
+	if len(t.skews) < deliverySkewSampleLimit {
+		t.skews = append(t.skews, skew)
+	}
+	t.acks++
+	t.totalAcks++
+	if capped {
+		t.capped++
+		t.totalCapped++
+	}
+}
+
+// allowanceMs ограничивает задержку доставки отрезком [0, MaxDeliveryAllowanceMs]
+func (t *DeliveryTracker) allowanceMs(skewMs int64) int64 {
+	switch {
+	case skewMs <= 0 || t.config.MaxDeliveryAllowanceMs <= 0:
+		return 0
+	case skewMs > t.config.MaxDeliveryAllowanceMs:
+		return t.config.MaxDeliveryAllowanceMs
+	}
+	return skewMs
+}
@@ -0,0 +1,142 @@
+package quizmanager
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/websocket"
+)
+
+// recordingAnswerWriter запоминает ответы, переданные на запись
+type recordingAnswerWriter struct {
+	mu      sync.Mutex
+	answers map[uint]*entity.UserAnswer
+}
+
+func (w *recordingAnswerWriter) Enqueue(_ context.Context, answer *entity.UserAnswer) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.answers[answer.UserID] = answer
+	return nil
+}
+
+func newDeliveryFixture(t *testing.T) (*DeliveryTracker, contractCache, *Config) {
+	t.Helper()
+	cache := contractCache{newMemoryAdmissionCache()}
+	config := DefaultConfig()
+	config.MaxDeliveryAllowanceMs = 1500
+	deps := &Dependencies{CacheRepo: cache, WSManager: websocket.NewManager(&contractHub{}), Config: config}
+	return NewDeliveryTracker(config, deps), cache, config
+}
+
+func TestDeliveryTracker_AllowanceIsCapped(t *testing.T) {
+	tracker, cache, _ := newDeliveryFixture(t)
+	ctx := context.Background()
+	require.NoError(t, cache.SAdd("quiz:1:participants", 1, 2, 3))
+
+	nowMs := time.Now().UnixMilli()
+	deadline := time.UnixMilli(nowMs + 10000)
+	// Вопрос ушёл 400 мс назад одному игроку и 5 с назад — другому
+	require.NoError(t, tracker.Acknowledge(ctx, 1, 7, 1, nowMs-400, deadline))
+	require.NoError(t, tracker.Acknowledge(ctx, 1, 7, 2, nowMs-5000, deadline))
+
+	allowance, ok := tracker.UserAllowanceMs(cache, 1, 7, 1, nowMs-400)
+	require.True(t, ok)
+	assert.InDelta(t, 400, allowance, 100)
+	allowance, ok = tracker.UserAllowanceMs(cache, 1, 7, 2, nowMs-5000)
+	require.True(t, ok)
+	assert.Equal(t, int64(1500), allowance, "отсрочка ограничена MaxDeliveryAllowanceMs")
+	_, ok = tracker.UserAllowanceMs(cache, 1, 7, 3, nowMs)
+	assert.False(t, ok, "игрок без подтверждения не получает отсрочку")
+
+	// Повторное подтверждение не сдвигает отсчёт
+	require.NoError(t, tracker.Acknowledge(ctx, 1, 7, 1, nowMs-3000, deadline))
+	allowance, _ = tracker.UserAllowanceMs(cache, 1, 7, 1, nowMs-400)
+	assert.InDelta(t, 400, allowance, 100)
+
+	stats := tracker.Stats(1)
+	assert.Equal(t, uint(7), stats.QuestionID)
+	assert.Equal(t, int64(2), stats.Acks)
+	assert.Equal(t, int64(1), stats.Capped)
+	assert.InDelta(t, 5000, stats.MaxMs, 100)
+	assert.Equal(t, int64(1500), stats.MaxAllowanceMs)
+	assert.Equal(t, int64(1500), stats.AllowanceLimitMs)
+
+	// Замеры сбрасываются с началом нового вопроса, общие счётчики — нет
+	require.NoError(t, tracker.Acknowledge(ctx, 1, 8, 1, time.Now().UnixMilli(), deadline))
+	stats = tracker.Stats(1)
+	assert.Equal(t, uint(8), stats.QuestionID)
+	assert.Equal(t, int64(1), stats.Acks)
+	assert.Equal(t, int64(3), stats.TotalAcks)
+	assert.Equal(t, int64(1), stats.TotalCapped)
+}
+
+func TestDeliveryTracker_MaxAllowance(t *testing.T) {
+	tracker, cache, _ := newDeliveryFixture(t)
+	ctx := context.Background()
+	require.NoError(t, cache.SAdd("quiz:1:participants", 1, 2))
+
+	startMs := time.Now().UnixMilli()
+	assert.Zero(t, tracker.MaxAllowance(1, 7, startMs), "без подтверждений ожидание не нужно")
+
+	require.NoError(t, tracker.Acknowledge(ctx, 1, 7, 1, startMs-200, time.Now()))
+	require.NoError(t, tracker.Acknowledge(ctx, 1, 7, 2, startMs-900, time.Now()))
+	assert.InDelta(t, 900*time.Millisecond, tracker.MaxAllowance(1, 7, startMs-900), float64(100*time.Millisecond))
+}
+
+func TestDeliveryTracker_RejectsNonParticipants(t *testing.T) {
+	tracker, cache, _ := newDeliveryFixture(t)
+	ctx := context.Background()
+
+	err := tracker.Acknowledge(ctx, 1, 7, 5, time.Now().UnixMilli(), time.Now())
+	assert.ErrorIs(t, err, apperrors.ErrForbidden)
+
+	// Подтверждение выбывшего игрока не учитывается
+	require.NoError(t, cache.SAdd("quiz:1:participants", 6))
+	require.NoError(t, cache.Set("quiz:1:eliminated:6", "1", time.Hour))
+	require.NoError(t, tracker.Acknowledge(ctx, 1, 7, 6, time.Now().UnixMilli()-500, time.Now()))
+	_, ok := tracker.UserAllowanceMs(cache, 1, 7, 6, time.Now().UnixMilli())
+	assert.False(t, ok)
+	assert.Zero(t, tracker.Stats(1).TotalAcks)
+}
+
+// Игрок, которому вопрос пришёл на секунду позже, успевает ответить в пределах своего дедлайна
+func TestAnswerProcessor_UsesDeliveryDeadline(t *testing.T) {
+	tracker, cache, config := newDeliveryFixture(t)
+	writer := &recordingAnswerWriter{answers: map[uint]*entity.UserAnswer{}}
+	quiz := &entity.Quiz{ID: 1, QuestionCount: 10}
+	question := &entity.Question{ID: 7, Options: entity.StringArray{"3", "4"}, CorrectOption: 1, TimeLimitSec: 10}
+
+	answers := NewAnswerProcessor(config, tracker.deps)
+	answers.SetDeliveryTracker(tracker)
+	answers.SetAnswerWriter(writer)
+	require.NoError(t, cache.SAdd(fmt.Sprintf("quiz:%d:participants", quiz.ID), 1, 2))
+
+	// Общий дедлайн прошёл 500 мс назад; первый игрок получил вопрос через секунду после отправки
+	startMs := time.Now().UnixMilli() - 10500
+	state := NewActiveQuizState(quiz)
+	state.SetCurrentQuestion(question, 1)
+	state.SetQuestionClock(newQuestionClockAt(time.UnixMilli(startMs), 10*time.Second))
+	require.NoError(t, cache.Set(questionDeliveryKey(quiz.ID, question.ID, 1), startMs+1000, time.Hour))
+
+	ctx := context.Background()
+	require.NoError(t, answers.ProcessAnswer(ctx, 1, question, 1, startMs, state, startMs))
+	require.NoError(t, answers.ProcessAnswer(ctx, 2, question, 1, startMs, state, startMs))
+
+	late := writer.answers[1]
+	require.NotNil(t, late)
+	assert.True(t, late.IsCorrect, "ответ принят по персональному дедлайну")
+	assert.Less(t, late.ResponseTimeMs, int64(10000))
+
+	unacked := writer.answers[2]
+	require.NotNil(t, unacked)
+	assert.False(t, unacked.IsCorrect, "без подтверждения действует общий дедлайн")
+}
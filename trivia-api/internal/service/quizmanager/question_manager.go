@@ -4,12 +4,15 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math"
 	"strconv"
 	"sync"
 	"time"
 
 	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
 	"github.com/yourusername/trivia-api/internal/handler/helper"
+	"github.com/yourusername/trivia-api/internal/websocket"
 )
 
 // QuestionManager отвечает за управление вопросами, их отправку и таймеры
@@ -25,6 +28,28 @@ type QuestionManager struct {
 
 	// Канал для сигнализации о завершении вопроса
 	questionDoneCh chan struct{}
+
+	// Учёт показов рекламы (опционально)
+	adImpressionRepo repository.AdImpressionRepository
+	// Переводы вопросов на дополнительные языки (опционально)
+	localizer QuestionLocalizer
+	// Ссылки на медиа вопросов (опционально)
+	media QuestionMediaSigner
+	// Буфер пакетной записи ответов (опционально)
+	answerWriter AnswerWriter
+	// Наблюдатель сохранённых ответов (опционально)
+	answerObserver AnswerObserver
+	// Аннулирование ответов на снятые с эфира вопросы (опционально)
+	answerVoider AnswerVoider
+	// Вместимость викторины и очередь допуска (опционально)
+	admission *AdmissionController
+	// Объявления ведущего по ходу викторины (опционально)
+	announcer Announcer
+	// Персональные дедлайны по подтверждениям доставки вопроса (опционально)
+	delivery *DeliveryTracker
+	// Запись вопросов, снятых из-за сбоя рассылки (опционально)
+	deliveryIncidentHook func(incident DeliveryIncident)
+	mu                   sync.RWMutex
 }
 
 // NewQuestionManager создает новый менеджер вопросов
@@ -51,9 +76,9 @@ func (qm *QuestionManager) QuestionDone() <-chan struct{} {
 func (qm *QuestionManager) RunQuizQuestions(ctx context.Context, quizState *ActiveQuizState) error {
 	totalQuestions := qm.config.MaxQuestionsPerQuiz
 	if quizState.Quiz.IsAdminOnlyMode() {
-		// В strict-режиме играем ровно по вопросам, добавленным админом.
-		if len(quizState.Quiz.Questions) > 0 {
-			totalQuestions = len(quizState.Quiz.Questions)
+		// В strict-режиме играем ровно по одобренным вопросам, добавленным админом.
+		if approved := len(quizState.Quiz.ApprovedQuestions()); approved > 0 {
+			totalQuestions = approved
 		} else if quizState.Quiz.QuestionCount > 0 {
 			totalQuestions = quizState.Quiz.QuestionCount
 		} else {
@@ -74,13 +99,27 @@ func (qm *QuestionManager) RunQuizQuestions(ctx context.Context, quizState *Acti
 	// WaitGroup для синхронизации всех таймеров вопросов
 	var timerWg sync.WaitGroup
 
-	// Список ID использованных вопросов в этой викторине
+	// Список ID использованных вопросов в этой викторине (включая снятые с эфира)
 	usedQuestionIDs := make([]uint, 0, totalQuestions)
+	// Количество вопросов, снятых администратором с эфира
+	voidedCount := 0
+	// Количество вопросов, снятых из-за сбоя рассылки (ограничено MaxDeliveryVoidsPerQuiz)
+	deliveryVoids := 0
 
 	// NOTE: quiz:start уже отправлен Scheduler.triggerQuizStart() перед вызовом QuestionManager.
 	// Здесь мы сразу начинаем отправку вопросов.
 
-	for i := 1; i <= totalQuestions; i++ {
+	for i := 1; ; i++ {
+		// После последнего вопроса — финал на выбывание, пока не останется один игрок.
+		// Вопрос, снятый администратором, переигрывается в том же раунде.
+		if i > totalQuestions && i-totalQuestions > quizState.SuddenDeathRound() {
+			if !qm.nextSuddenDeathRound(quizCtx, quizState, quizState.SuddenDeathRound()) {
+				break
+			}
+			quizState.SetSuddenDeathRound(i - totalQuestions)
+		}
+		suddenDeathRound := quizState.SuddenDeathRound()
+
 		// Опциональный режим: досрочно завершаем викторину, если активных участников больше нет.
 		if quizState.Quiz.FinishOnZeroPlayers {
 			activeParticipants, err := qm.countActiveParticipants(quizState.Quiz.ID)
@@ -94,9 +133,18 @@ func (qm *QuestionManager) RunQuizQuestions(ctx context.Context, quizState *Acti
 			}
 		}
 
+		// Перед финальным вопросом ведущий может подогреть зал
+		if i == totalQuestions && suddenDeathRound == 0 {
+			qm.announceMilestone(quizCtx, quizState, AnnouncementMilestone{
+				Trigger:        entity.AnnouncementTriggerBeforeFinale,
+				QuestionNumber: i,
+				TotalQuestions: totalQuestions,
+			})
+		}
+
 		// === АДАПТИВНЫЙ ВЫБОР ВОПРОСА ===
 		allowPool := !quizState.Quiz.IsAdminOnlyMode()
-		question, err := qm.adaptiveSelector.SelectNextQuestion(quizCtx, quizState.Quiz.ID, i, usedQuestionIDs, allowPool)
+		question, err := qm.adaptiveSelector.SelectNextQuestion(quizCtx, quizState.Quiz.ID, i, usedQuestionIDs, allowPool, quizState.Quiz.CategoryID, quizState.Quiz.RequireVerifiedKK)
 		if err != nil {
 			log.Printf("[QuestionManager] КРИТИЧЕСКАЯ ОШИБКА: Не удалось выбрать вопрос #%d для викторины #%d: %v. Завершаем викторину.",
 				i, quizState.Quiz.ID, err)
@@ -117,33 +165,32 @@ func (qm *QuestionManager) RunQuizQuestions(ctx context.Context, quizState *Acti
 		// Устанавливаем текущий вопрос в состоянии
 		quizState.SetCurrentQuestion(question, i)
 
+		// Тайминги читаются на каждом вопросе: глобальные меняются на лету, викторина может их переопределять
+		timing := qm.config.Timing().ForQuiz(quizState.Quiz)
+
 		// Добавляем задержку перед отправкой вопроса для синхронизации с фронтендом
-		time.Sleep(time.Duration(qm.config.QuestionDelayMs) * time.Millisecond)
+		time.Sleep(time.Duration(timing.QuestionDelayMs) * time.Millisecond)
 
-		// Получить точное время отправки вопроса
-		sendTimeMs := time.Now().UnixNano() / int64(time.Millisecond)
+		// Получить точное время отправки вопроса. Таймер отсчитывается от него же,
+		// чтобы дедлайн в quiz:question совпадал с дедлайном приёма ответов.
+		sendTime := time.Now()
+		sendTimeMs := sendTime.UnixMilli()
 		quizState.SetCurrentQuestionStartTime(sendTimeMs)
+		timeLimit := timing.QuestionTimeLimit(question.TimeLimitSec)
+		clock := newQuestionClockAt(sendTime, timeLimit)
+		quizState.SetQuestionClock(clock)
 
 		// Отправляем вопрос всем участникам
-		// Включаем оба языка — Frontend выбирает нужный по настройке пользователя
-		questionEvent := map[string]interface{}{
-			"question_id":      question.ID,
-			"quiz_id":          quizState.Quiz.ID,
-			"number":           i,
-			"text":             question.Text,
-			"text_kk":          question.TextKK, // Казахский текст (может быть пустым)
-			"options":          helper.ConvertOptionsToObjects(question.Options),
-			"options_kk":       helper.ConvertOptionsToObjects(question.OptionsKK), // Казахские варианты
-			"time_limit":       question.TimeLimitSec,
-			"total_questions":  totalQuestions,
-			"start_time":       sendTimeMs,
-			"server_timestamp": sendTimeMs,
-		}
+		questionEvent := qm.questionEvent(quizCtx, quizState.Quiz.ID, question, i, totalQuestions, timeLimit, clock, suddenDeathRound)
 
 		// Отправка с повторными попытками при ошибке
-		if err := qm.sendEventWithRetry(quizCtx, quizState.Quiz.ID, "quiz:question", questionEvent); err != nil {
+		delivery, counted, err := qm.broadcastWithRetry(quizCtx, quizState.Quiz.ID, "quiz:question", questionEvent)
+		if err != nil {
 			log.Printf("[QuestionManager] WARNING: Не удалось отправить вопрос #%d для викторины #%d: %v. Продолжаем викторину.",
 				question.ID, quizState.Quiz.ID, err)
+		} else if counted && qm.checkQuestionDelivery(quizState, question, i, clock, delivery, deliveryVoids) {
+			// Не получившие вопрос не должны выбыть за отсутствие ответа: вопрос аннулируется ниже
+			deliveryVoids++
 		}
 
 		// Сохраняем время начала вопроса для подсчета времени ответа
@@ -152,33 +199,55 @@ func (qm *QuestionManager) RunQuizQuestions(ctx context.Context, quizState *Acti
 			log.Printf("[QuestionManager] WARNING: Не удалось сохранить время начала вопроса #%d в Redis: %v", question.ID, err)
 		}
 
-		// Запускаем таймер для вопроса
-		timeLimit := time.Duration(question.TimeLimitSec) * time.Second
-		endTime := time.Now().Add(timeLimit)
+		// Запускаем рассылку таймера; администратор может поставить его на паузу,
+		// продлить или снять вопрос с эфира (см. live_ops.go)
 		timerWg.Add(1)
-		go qm.runQuestionTimer(quizCtx, quizState.Quiz, question, i, totalQuestions, endTime, &timerWg)
+		go qm.runQuestionTimer(quizCtx, quizState.Quiz, question, i, totalQuestions, clock, &timerWg)
 
 		// Ждем завершения времени на вопрос
 		log.Printf("[QuestionManager][DEBUG] Викторина #%d, Вопрос #%d: Ожидание завершения таймера (%v)...", quizState.Quiz.ID, question.ID, timeLimit)
-		select {
-		case <-time.After(timeLimit):
-			log.Printf("[QuestionManager] Викторина #%d, Вопрос #%d (%d из %d): Время истекло. Начинаем проверку не ответивших.",
-				quizState.Quiz.ID, question.ID, i, totalQuestions)
-		case <-quizCtx.Done():
+		voided, err := clock.Wait(quizCtx)
+		if err != nil {
 			log.Printf("[QuestionManager] Процесс викторины #%d был прерван на вопросе #%d",
 				quizState.Quiz.ID, i)
 			return nil
 		}
+		if voided {
+			// Снятый вопрос не выбивает не ответивших и не раскрывает ответ; его номер занимает следующий вопрос
+			qm.voidQuestion(quizCtx, quizState, question, i, clock.VoidReason())
+			voidedCount++
+			i--
+			select {
+			case <-time.After(time.Duration(timing.InterQuestionDelayMs) * time.Millisecond):
+			case <-quizCtx.Done():
+				return nil
+			}
+			continue
+		}
+		// Игроки, которым вопрос пришёл позже, отвечают до своего персонального дедлайна
+		if err := qm.waitDeliveryAllowance(quizCtx, quizState.Quiz.ID, question.ID, sendTimeMs, clock); err != nil {
+			return nil
+		}
+		log.Printf("[QuestionManager] Викторина #%d, Вопрос #%d (%d из %d): Время истекло. Начинаем проверку не ответивших.",
+			quizState.Quiz.ID, question.ID, i, totalQuestions)
 
 		// === ЛОГИКА ВЫБЫВАНИЯ ПРИ ОТСУТСТВИИ ОТВЕТА ===
-		qm.processNoAnswerEliminations(quizCtx, quizState, question, i)
+		if suddenDeathRound > 0 {
+			// Раунд, на который никто не ответил верно, не засчитывается: оставшиеся сохраняют
+			// ответы на все засчитанные вопросы
+			if qm.resolveSuddenDeathRound(quizCtx, quizState, question, i) {
+				voidedCount++
+			}
+		} else {
+			qm.processNoAnswerEliminations(quizCtx, quizState, question, i)
+		}
 
 		// === ОТПРАВКА REALTIME СТАТИСТИКИ АДАПТИВНОЙ СИСТЕМЫ ===
 		remainingPlayers := qm.deps.WSManager.GetSubscriberCount(quizState.Quiz.ID)
 		qm.sendAdaptiveQuestionStats(quizCtx, quizState.Quiz.ID, i, question.Difficulty, remainingPlayers)
 
 		// Добавляем задержку перед отправкой правильного ответа
-		time.Sleep(time.Duration(qm.config.AnswerRevealDelayMs) * time.Millisecond)
+		time.Sleep(time.Duration(timing.AnswerRevealDelayMs) * time.Millisecond)
 
 		// Отправляем правильный ответ всем оставшимся участникам
 		log.Printf("[QuestionManager][DEBUG] Викторина #%d, Вопрос #%d: Отправка события quiz:answer_reveal...", quizState.Quiz.ID, question.ID)
@@ -190,12 +259,25 @@ func (qm *QuestionManager) RunQuizQuestions(ctx context.Context, quizState *Acti
 			log.Printf("[QuestionManager] WARNING: Не удалось отправить ответ на вопрос #%d: %v", question.ID, err)
 		}
 
+		// === ОБЪЯВЛЕНИЯ ВЕДУЩЕГО ===
+		if suddenDeathRound == 0 {
+			qm.announceMilestone(quizCtx, quizState, AnnouncementMilestone{
+				Trigger:        entity.AnnouncementTriggerAfterQuestion,
+				QuestionNumber: i,
+				TotalQuestions: totalQuestions,
+			})
+		}
+
 		// === РЕКЛАМНЫЙ БЛОК ===
 		qm.processAdBreak(quizCtx, quizState, i, totalQuestions)
 
-		// Пауза между вопросами
-		if i < totalQuestions {
-			pauseTime := time.Duration(qm.config.InterQuestionDelayMs) * time.Millisecond
+		// Пауза между вопросами (и перед возможным раундом финала)
+		if i < totalQuestions || quizState.Quiz.SuddenDeath {
+			// Места выбывших занимают следующие в очереди: к следующему вопросу они уже участники
+			if i < totalQuestions {
+				qm.admitFromQueue(quizState.Quiz.ID)
+			}
+			pauseTime := time.Duration(timing.InterQuestionDelayMs) * time.Millisecond
 			log.Printf("[QuestionManager] Пауза %v между вопросами %d и %d", pauseTime, i, i+1)
 			select {
 			case <-time.After(pauseTime):
@@ -206,9 +288,12 @@ func (qm *QuestionManager) RunQuizQuestions(ctx context.Context, quizState *Acti
 		}
 	}
 
+	quizState.SetSuddenDeathRound(0)
+
 	// === FIX BUG-2: Фиксируем ФАКТИЧЕСКОЕ количество заданных вопросов ===
 	// Обновляем question_count ДО пометки вопросов. Даже если 0 (early break).
-	actualAsked := len(usedQuestionIDs)
+	// Снятые с эфира вопросы не считаются: победитель должен ответить на все засчитанные.
+	actualAsked := len(usedQuestionIDs) - voidedCount
 	if actualAsked != totalQuestions {
 		log.Printf("[QuestionManager] Викторина #%d: фактически задано %d/%d вопросов. Обновляем question_count.",
 			quizState.Quiz.ID, actualAsked, totalQuestions)
@@ -218,6 +303,7 @@ func (qm *QuestionManager) RunQuizQuestions(ctx context.Context, quizState *Acti
 	}
 
 	// === ПОМЕЧАЕМ ВОПРОСЫ КАК ИСПОЛЬЗОВАННЫЕ ===
+	// Снятые вопросы тоже помечаются, чтобы неисправный вопрос не попал в эфир повторно
 	if len(usedQuestionIDs) > 0 {
 		if err := qm.deps.QuestionRepo.MarkAsUsed(usedQuestionIDs); err != nil {
 			log.Printf("[QuestionManager] WARNING: Не удалось пометить вопросы как использованные: %v", err)
@@ -372,6 +458,7 @@ func (qm *QuestionManager) processNoAnswerEliminations(ctx context.Context, quiz
 	}
 
 	// Обрабатываем результаты
+	eliminated := make([]uint, 0)
 	for _, p := range participants {
 		answered := answeredMap[p.answerKey]
 		if answered {
@@ -385,9 +472,15 @@ func (qm *QuestionManager) processNoAnswerEliminations(ctx context.Context, quiz
 			continue
 		}
 
-		eliminationReason := "no_answer_timeout"
-		log.Printf("[QuestionManager] Пользователь #%d выбывает из викторины #%d. Причина: %s (Вопрос #%d).",
-			p.userID, quizState.Quiz.ID, eliminationReason, question.ID)
+		// Пропуск вопроса — ошибка; выбывает ли игрок, решает режим игры викторины
+		outcome := registerMistake(qm.deps.CacheRepo, quizState.Quiz, uint(p.userID), question.ID)
+		updateStreak(qm.deps.CacheRepo, quizState.Quiz, uint(p.userID), false)
+		eliminationReason := ""
+		if outcome.Eliminate {
+			eliminationReason = "no_answer_timeout"
+			log.Printf("[QuestionManager] Пользователь #%d выбывает из викторины #%d. Причина: %s (Вопрос #%d).",
+				p.userID, quizState.Quiz.ID, eliminationReason, question.ID)
+		}
 
 		// Сохраняем UserAnswer в БД для статистики
 		userAnswer := &entity.UserAnswer{
@@ -398,23 +491,253 @@ func (qm *QuestionManager) processNoAnswerEliminations(ctx context.Context, quiz
 			IsCorrect:         false,
 			ResponseTimeMs:    0,
 			Score:             0,
-			IsEliminated:      true,
+			IsEliminated:      outcome.Eliminate,
 			EliminationReason: eliminationReason,
 		}
-		if err := qm.deps.ResultRepo.SaveUserAnswer(userAnswer); err != nil {
+		if err := qm.saveAnswer(userAnswer, questionNumber); err != nil {
 			log.Printf("[QuestionManager] WARNING: Не удалось сохранить user_answer для таймаута User #%d: %v", p.userID, err)
 		}
 
-		// Устанавливаем статус выбывшего в Redis
-		if errSet := qm.deps.CacheRepo.Set(p.eliminationKey, "1", 24*time.Hour); errSet != nil {
+		// === ЗАПИСЫВАЕМ СТАТИСТИКУ ДЛЯ АДАПТАЦИИ ===
+		qm.adaptiveSelector.RecordQuestionResult(quizState.Quiz.ID, questionNumber, false)
+
+		if !outcome.Eliminate {
+			if outcome.LivesLeft != nil {
+				qm.sendLifeLost(uint(p.userID), quizState.Quiz.ID, question.ID, *outcome.LivesLeft)
+			}
+			continue
+		}
+
+		// Устанавливаем статус выбывшего в Redis (значение — номер вопроса, см. второй шанс)
+		if errSet := qm.deps.CacheRepo.Set(p.eliminationKey, strconv.Itoa(questionNumber), 24*time.Hour); errSet != nil {
 			log.Printf("[QuestionManager] WARNING: Не удалось установить ключ выбывания %s в Redis: %v", p.eliminationKey, errSet)
 		}
 
 		// Отправляем уведомление о выбывании
 		qm.sendEliminationNotification(uint(p.userID), quizState.Quiz.ID, eliminationReason)
+		offerSecondChance(qm.deps, quizState.Quiz, uint(p.userID), questionNumber)
+		eliminated = append(eliminated, uint(p.userID))
+	}
 
-		// === ЗАПИСЫВАЕМ СТАТИСТИКУ ДЛЯ АДАПТАЦИИ ===
-		qm.adaptiveSelector.RecordQuestionResult(quizState.Quiz.ID, questionNumber, false)
+	if admission := qm.getAdmission(); admission != nil {
+		admission.Release(quizState.Quiz.ID, eliminated...)
+	}
+}
+
+// SetAdImpressionRepo подключает учёт показов рекламных пауз
+func (qm *QuestionManager) SetAdImpressionRepo(repo repository.AdImpressionRepository) {
+	qm.mu.Lock()
+	defer qm.mu.Unlock()
+	qm.adImpressionRepo = repo
+}
+
+// SetLocalizer подключает переводы вопросов для рассылки quiz:question
+func (qm *QuestionManager) SetLocalizer(localizer QuestionLocalizer) {
+	qm.mu.Lock()
+	defer qm.mu.Unlock()
+	qm.localizer = localizer
+}
+
+// SetMedia подключает ссылки на медиа вопросов для рассылки quiz:question
+func (qm *QuestionManager) SetMedia(media QuestionMediaSigner) {
+	qm.mu.Lock()
+	defer qm.mu.Unlock()
+	qm.media = media
+}
+
+// questionMedia возвращает ссылки на медиа вопроса для события quiz:question.
+// nil — медиа нет или ссылки не удалось получить; вопрос отправляется без них.
+func (qm *QuestionManager) questionMedia(ctx context.Context, question *entity.Question) *QuestionMedia {
+	qm.mu.RLock()
+	signer := qm.media
+	qm.mu.RUnlock()
+	if signer == nil {
+		return nil
+	}
+	media, err := signer.QuestionMedia(ctx, question)
+	if err != nil {
+		log.Printf("[QuestionManager] WARNING: Не удалось получить ссылки на медиа вопроса %d: %v", question.ID, err)
+		return nil
+	}
+	return media
+}
+
+// SetDeliveryTracker подключает персональные дедлайны по подтверждениям доставки вопроса
+func (qm *QuestionManager) SetDeliveryTracker(delivery *DeliveryTracker) {
+	qm.mu.Lock()
+	defer qm.mu.Unlock()
+	qm.delivery = delivery
+}
+
+func (qm *QuestionManager) getDeliveryTracker() *DeliveryTracker {
+	qm.mu.RLock()
+	defer qm.mu.RUnlock()
+	return qm.delivery
+}
+
+// waitDeliveryAllowance ждёт после общего дедлайна наибольшую выданную на вопрос отсрочку,
+// чтобы не выбить не ответивших и не раскрыть ответ раньше персональных дедлайнов
+func (qm *QuestionManager) waitDeliveryAllowance(ctx context.Context, quizID, questionID uint, startTimeMs int64, clock *QuestionClock) error {
+	delivery := qm.getDeliveryTracker()
+	if delivery == nil {
+		return nil
+	}
+	allowance := delivery.MaxAllowance(quizID, questionID, startTimeMs)
+	wait := time.Until(clock.Deadline().Add(allowance))
+	stats := delivery.Stats(quizID)
+	if stats.QuestionID == questionID {
+		log.Printf("[QuestionManager] Викторина #%d, Вопрос #%d: доставка по %d подтверждениям узла: p50 %.0f мс, p90 %.0f мс, max %.0f мс, ограничено %d",
+			quizID, questionID, stats.Acks, stats.P50Ms, stats.P90Ms, stats.MaxMs, stats.Capped)
+	}
+	if allowance <= 0 || wait <= 0 {
+		return nil
+	}
+	log.Printf("[QuestionManager] Викторина #%d, Вопрос #%d: ожидание персональных дедлайнов (отсрочка до %v)", quizID, questionID, allowance)
+	select {
+	case <-time.After(wait):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// SetAdmission подключает ограничение вместимости и очередь допуска
+func (qm *QuestionManager) SetAdmission(admission *AdmissionController) {
+	qm.mu.Lock()
+	defer qm.mu.Unlock()
+	qm.admission = admission
+}
+
+func (qm *QuestionManager) getAdmission() *AdmissionController {
+	qm.mu.RLock()
+	defer qm.mu.RUnlock()
+	return qm.admission
+}
+
+// admitFromQueue впускает ждущих в очереди на места выбывших
+func (qm *QuestionManager) admitFromQueue(quizID uint) {
+	admission := qm.getAdmission()
+	if admission == nil {
+		return
+	}
+	if _, err := admission.AdmitNext(quizID); err != nil {
+		log.Printf("[QuestionManager] WARNING: Не удалось впустить игроков из очереди викторины #%d: %v", quizID, err)
+	}
+}
+
+// SetAnswerWriter подключает буфер пакетной записи ответов
+func (qm *QuestionManager) SetAnswerWriter(writer AnswerWriter) {
+	qm.mu.Lock()
+	defer qm.mu.Unlock()
+	qm.answerWriter = writer
+}
+
+// SetAnswerObserver подключает наблюдателя сохранённых ответов
+func (qm *QuestionManager) SetAnswerObserver(observer AnswerObserver) {
+	qm.mu.Lock()
+	defer qm.mu.Unlock()
+	qm.answerObserver = observer
+}
+
+// saveAnswer сохраняет ответ через буфер записи, если он подключён, иначе сразу в БД
+func (qm *QuestionManager) saveAnswer(answer *entity.UserAnswer, questionNumber int) error {
+	qm.mu.RLock()
+	writer := qm.answerWriter
+	observer := qm.answerObserver
+	qm.mu.RUnlock()
+	var err error
+	if writer != nil {
+		err = writer.Enqueue(context.Background(), answer)
+	} else {
+		err = qm.deps.ResultRepo.SaveUserAnswer(answer)
+	}
+	if err == nil && observer != nil {
+		observer.AnswerRecorded(answer, questionNumber)
+	}
+	return err
+}
+
+// questionEvent формирует данные quiz:question. Включает оба языка — Frontend выбирает нужный
+// по настройке пользователя; start_time — момент отправки, от которого отсчитывается таймер.
+func (qm *QuestionManager) questionEvent(ctx context.Context, quizID uint, question *entity.Question, number, totalQuestions int,
+	timeLimit time.Duration, clock *QuestionClock, suddenDeathRound int) map[string]interface{} {
+	startMs := clock.Deadline().Add(-timeLimit).UnixMilli()
+	event := map[string]interface{}{
+		"question_id":      question.ID,
+		"quiz_id":          quizID,
+		"number":           number,
+		"text":             question.Text,
+		"text_kk":          question.TextKK, // Казахский текст (может быть пустым)
+		"options":          helper.ConvertOptionsToObjects(question.Options),
+		"options_kk":       helper.ConvertOptionsToObjects(question.OptionsKK), // Казахские варианты
+		"time_limit":       int(math.Ceil(timeLimit.Seconds())),
+		"total_questions":  totalQuestions,
+		"start_time":       startMs,
+		"deadline":         clock.Deadline().UnixMilli(), // авторитетный дедлайн ответа (unix ms)
+		"server_timestamp": startMs,
+	}
+	// Переводы на все поддерживаемые языки: клиент выбирает язык по настройке пользователя,
+	// при отсутствии перевода — по цепочке запасных языков
+	if translations := qm.questionTranslations(question); translations != nil {
+		event["translations"] = translations
+	}
+	if media := qm.questionMedia(ctx, question); media != nil {
+		event["media"] = media
+	}
+	if suddenDeathRound > 0 {
+		event["sudden_death_round"] = suddenDeathRound
+	}
+	return event
+}
+
+// questionTranslations возвращает переводы вопроса для события quiz:question.
+// nil — переводы не подключены или недоступны; клиент использует text/text_kk.
+func (qm *QuestionManager) questionTranslations(question *entity.Question) map[string]interface{} {
+	qm.mu.RLock()
+	localizer := qm.localizer
+	qm.mu.RUnlock()
+	if localizer == nil {
+		return nil
+	}
+	translations, err := localizer.QuestionTranslations(question)
+	if err != nil {
+		log.Printf("[QuestionManager] WARNING: Не удалось получить переводы вопроса %d: %v", question.ID, err)
+		return nil
+	}
+	result := make(map[string]interface{}, len(translations))
+	for locale, localized := range translations {
+		result[locale] = map[string]interface{}{
+			"text":    localized.Text,
+			"options": helper.ConvertOptionsToObjects(localized.Options),
+		}
+	}
+	return result
+}
+
+// recordAdImpression сохраняет факт показа рекламы с числом подключённых зрителей
+func (qm *QuestionManager) recordAdImpression(quizID uint, adAssetID uint, questionNumber int) {
+	qm.mu.RLock()
+	repo := qm.adImpressionRepo
+	qm.mu.RUnlock()
+	if repo == nil {
+		return
+	}
+
+	viewers := 0
+	if qm.deps.WSManager != nil {
+		viewers = qm.deps.WSManager.GetSubscriberCount(quizID)
+	}
+
+	impression := &entity.AdImpression{
+		AdAssetID:     &adAssetID,
+		QuizID:        &quizID,
+		QuestionAfter: questionNumber,
+		Viewers:       viewers,
+		ShownAt:       time.Now(),
+		Placement:     entity.AdPlacementBreak,
+	}
+	if err := repo.Create(impression); err != nil {
+		log.Printf("[QuestionManager] WARNING: Не удалось сохранить показ рекламы #%d: %v", adAssetID, err)
 	}
 }
 
@@ -434,22 +757,36 @@ func (qm *QuestionManager) processAdBreak(ctx context.Context, quizState *Active
 		return
 	}
 
-	log.Printf("[QuestionManager] Показ рекламы #%d после вопроса %d (длительность: %d сек)",
-		slot.AdAsset.ID, questionNumber, slot.AdAsset.DurationSec)
+	// Длительность паузы: переопределение слота, длительность паузы викторины или длительность ролика
+	timing := qm.config.Timing().ForQuiz(quizState.Quiz)
+	playback := timing.AdBreakPlayback(slot)
+	if playback <= 0 {
+		return
+	}
+
+	log.Printf("[QuestionManager] Показ рекламы #%d после вопроса %d (длительность: %v)",
+		slot.AdAsset.ID, questionNumber, playback)
 
 	// Отправляем событие начала рекламы
 	adEvent := map[string]interface{}{
 		"quiz_id":      quizState.Quiz.ID,
 		"media_type":   slot.AdAsset.MediaType,
 		"media_url":    slot.AdAsset.URL,
-		"duration_sec": slot.AdAsset.DurationSec,
+		"duration_sec": int(math.Ceil(playback.Seconds())),
+		"duration_ms":  playback.Milliseconds(),
+	}
+	if slot.AdAsset.IsVideo() {
+		adEvent["mime_type"] = slot.AdAsset.MimeType
+		adEvent["thumbnail_url"] = slot.AdAsset.ThumbnailURL
 	}
 	if err := qm.sendEventWithRetry(ctx, quizState.Quiz.ID, "quiz:ad_break", adEvent); err != nil {
 		log.Printf("[QuestionManager] WARNING: Не удалось отправить quiz:ad_break: %v", err)
+	} else {
+		qm.recordAdImpression(quizState.Quiz.ID, slot.AdAsset.ID, questionNumber)
 	}
 
-	// Ждём заданное время показа рекламы
-	adDuration := time.Duration(slot.AdAsset.DurationSec) * time.Second
+	// Ждём заданное время показа рекламы; видео получает запас на буферизацию у клиентов
+	adDuration := adBreakDuration(slot, timing)
 	select {
 	case <-time.After(adDuration):
 		log.Printf("[QuestionManager] Реклама завершена, продолжаем викторину")
@@ -474,6 +811,9 @@ func (qm *QuestionManager) sendEliminationNotification(userID uint, quizID uint,
 		"reason":  reason,
 		"message": "Вы выбыли из викторины, так как не ответили вовремя.",
 	}
+	if reason == entity.AnswerReasonSuddenDeath {
+		eliminationEvent["message"] = "Вы выбыли в финале на выбывание."
+	}
 	// Убираем неиспользуемый fullEvent
 	// Используем WSManager напрямую
 	// Исправленный вызов: передаем тип и данные отдельно
@@ -490,7 +830,7 @@ func (qm *QuestionManager) runQuestionTimer(
 	question *entity.Question,
 	questionNumber int,
 	totalQuestions int,
-	endTime time.Time,
+	clock *QuestionClock,
 	wg *sync.WaitGroup,
 ) {
 	defer wg.Done()
@@ -506,7 +846,10 @@ func (qm *QuestionManager) runQuestionTimer(
 	for {
 		select {
 		case <-ticker.C:
-			remaining := int(time.Until(endTime).Seconds())
+			if clock.Voided() {
+				return
+			}
+			remaining := int(clock.Remaining().Seconds())
 			if remaining <= 0 {
 				// Время вышло
 				log.Printf("[QuestionManager] Время на вопрос #%d (%d из %d) викторины #%d истекло",
@@ -518,6 +861,7 @@ func (qm *QuestionManager) runQuestionTimer(
 			timerData := map[string]interface{}{
 				"question_id":       question.ID,
 				"remaining_seconds": remaining,
+				"paused":            clock.Paused(),
 				"server_timestamp":  time.Now().UnixNano() / int64(time.Millisecond),
 			}
 			timerFullEvent := map[string]interface{}{
@@ -588,6 +932,13 @@ func (qm *QuestionManager) sendAdaptiveQuestionStats(ctx context.Context, quizID
 // sendEventWithRetry пытается отправить событие через WSManager с заданным количеством попыток.
 // Возвращает ошибку, если все попытки неудачны.
 func (qm *QuestionManager) sendEventWithRetry(ctx context.Context, quizID uint, eventType string, data map[string]interface{}) error {
+	_, _, err := qm.broadcastWithRetry(ctx, quizID, eventType, data)
+	return err
+}
+
+// broadcastWithRetry отправляет событие, как sendEventWithRetry, и возвращает, скольким подписчикам
+// узла оно доставлено (counted=false — хаб не считает доставку)
+func (qm *QuestionManager) broadcastWithRetry(ctx context.Context, quizID uint, eventType string, data map[string]interface{}) (websocket.QuizDelivery, bool, error) {
 	var sendErr error
 
 	// Создаем полное событие для передачи
@@ -601,15 +952,17 @@ func (qm *QuestionManager) sendEventWithRetry(ctx context.Context, quizID uint,
 		select {
 		case <-ctx.Done():
 			log.Printf("[QuestionManager] Отправка события %s для викторины #%d отменена контекстом (попытка %d)", eventType, quizID, attempts+1)
-			return ctx.Err()
+			return websocket.QuizDelivery{}, false, ctx.Err()
 		default:
 		}
 
-		sendErr = qm.deps.WSManager.BroadcastEventToQuiz(quizID, fullEvent)
+		var delivery websocket.QuizDelivery
+		var counted bool
+		delivery, counted, sendErr = qm.deps.WSManager.BroadcastEventToQuizCounted(quizID, fullEvent)
 		if sendErr == nil {
 			log.Printf("[QuestionManager] Событие %s для викторины #%d успешно отправлено с %d попытки",
 				eventType, quizID, attempts+1)
-			return nil // Успешно отправлено
+			return delivery, counted, nil // Успешно отправлено
 		}
 		log.Printf("[QuestionManager] ОШИБКА при отправке события %s для викторины #%d (попытка %d): %v",
 			eventType, quizID, attempts+1, sendErr)
@@ -620,10 +973,10 @@ func (qm *QuestionManager) sendEventWithRetry(ctx context.Context, quizID uint,
 			// Продолжаем следующую попытку
 		case <-ctx.Done():
 			log.Printf("[QuestionManager] Ожидание перед ретраем отправки события %s для викторины #%d отменено контекстом", eventType, quizID)
-			return ctx.Err()
+			return websocket.QuizDelivery{}, false, ctx.Err()
 		}
 	}
 	// Если все попытки не удались
-	return fmt.Errorf("не удалось отправить событие %s для викторины #%d после %d попыток: %w",
+	return websocket.QuizDelivery{}, false, fmt.Errorf("не удалось отправить событие %s для викторины #%d после %d попыток: %w",
 		eventType, quizID, qm.config.MaxRetries, sendErr)
 }
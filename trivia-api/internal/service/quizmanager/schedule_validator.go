@@ -0,0 +1,409 @@
+package quizmanager
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// defaultQuestionTimeLimitSec — лимит вопроса по умолчанию (questions.time_limit_sec) для оценки
+// длительности викторины с вопросами из пула
+const defaultQuestionTimeLimitSec = 10
+
+// Статусы проверок расписания
+const (
+	ScheduleCheckOK       = "ok"
+	ScheduleCheckWarning  = "warning"
+	ScheduleCheckCritical = "critical"
+)
+
+// ScheduleCheck — результат одной проверки расписания
+type ScheduleCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// ScheduleValidationReport — отчёт о проверке времени проведения викторины.
+// Valid = false, если хотя бы одна проверка критическая.
+type ScheduleValidationReport struct {
+	QuizID        uint                   `json:"quiz_id"`
+	ScheduledTime time.Time              `json:"scheduled_time"`
+	EstimatedEnd  time.Time              `json:"estimated_end"`
+	Valid         bool                   `json:"valid"`
+	Checks        []ScheduleCheck        `json:"checks"`
+	Questions     ScheduleQuestionReport `json:"questions"`
+	Overlaps      []ScheduleOverlap      `json:"overlaps"`
+	AdSlots       []ScheduleAdSlot       `json:"ad_slots"`
+	PrizeFund     SchedulePrizeFund      `json:"prize_fund"`
+}
+
+// ScheduleQuestionReport — доступность вопросов для викторины
+type ScheduleQuestionReport struct {
+	Mode          string                   `json:"mode"`
+	Required      int                      `json:"required"`
+	QuizQuestions int                      `json:"quiz_questions"` // одобренные вопросы викторины
+	Unapproved    int                      `json:"unapproved"`     // вопросы викторины, не прошедшие проверку
+	PoolAvailable int64                    `json:"pool_available"`
+	ByDifficulty  []DifficultyAvailability `json:"by_difficulty"`
+}
+
+// DifficultyAvailability — сколько вопросов уровня сложности нужно по адаптивной схеме и сколько есть
+type DifficultyAvailability struct {
+	Difficulty    int   `json:"difficulty"`
+	Required      int   `json:"required"`
+	QuizQuestions int   `json:"quiz_questions"`
+	PoolAvailable int64 `json:"pool_available"`
+}
+
+// ScheduleOverlap — другая викторина, пересекающаяся по времени
+type ScheduleOverlap struct {
+	QuizID        uint      `json:"quiz_id"`
+	Title         string    `json:"title"`
+	Status        string    `json:"status"`
+	ScheduledTime time.Time `json:"scheduled_time"`
+	EstimatedEnd  time.Time `json:"estimated_end"`
+}
+
+// ScheduleAdSlot — рекламный слот викторины и будет ли он показан
+type ScheduleAdSlot struct {
+	SlotID        uint   `json:"slot_id"`
+	QuestionAfter int    `json:"question_after"`
+	DurationSec   int    `json:"duration_sec"`
+	WillShow      bool   `json:"will_show"`
+	Reason        string `json:"reason,omitempty"`
+}
+
+// SchedulePrizeFund — призовой фонд викторины и фонд, который будет разыгран
+type SchedulePrizeFund struct {
+	Configured int `json:"configured"`
+	Effective  int `json:"effective"`
+}
+
+// ScheduleValidator проверяет время проведения викторины до планирования: хватит ли вопросов,
+// не пересекается ли она с другими викторинами (одновременно идёт только одна), будут ли
+// показаны рекламные слоты и какой призовой фонд будет разыгран.
+type ScheduleValidator struct {
+	config     *Config
+	deps       *Dependencies
+	difficulty *DifficultyConfig
+}
+
+// NewScheduleValidator создает валидатор расписания
+func NewScheduleValidator(config *Config, deps *Dependencies) *ScheduleValidator {
+	return &ScheduleValidator{config: config, deps: deps, difficulty: DefaultDifficultyConfig()}
+}
+
+// Validate проверяет планирование викторины quizID на время scheduledTime
+func (v *ScheduleValidator) Validate(quizID uint, scheduledTime time.Time) (*ScheduleValidationReport, error) {
+	return v.ValidateWithTiming(quizID, scheduledTime, nil)
+}
+
+// ValidateWithTiming проверяет планирование так, как если бы викторине были заданы
+// тайминги timing (nil — сохранённые тайминги викторины)
+func (v *ScheduleValidator) ValidateWithTiming(quizID uint, scheduledTime time.Time, timing *entity.QuizTiming) (*ScheduleValidationReport, error) {
+	quiz, err := v.deps.QuizRepo.GetWithQuestions(quizID)
+	if err != nil {
+		return nil, err
+	}
+	if timing != nil {
+		withTiming := *quiz
+		withTiming.Timing = *timing
+		quiz = &withTiming
+	}
+
+	report := &ScheduleValidationReport{
+		QuizID:        quizID,
+		ScheduledTime: scheduledTime,
+		Overlaps:      []ScheduleOverlap{},
+		AdSlots:       []ScheduleAdSlot{},
+	}
+
+	v.checkTime(report, quiz, scheduledTime)
+	total, err := v.checkQuestions(report, quiz)
+	if err != nil {
+		return nil, err
+	}
+	adBreaks, err := v.checkAdSlots(report, quiz, total)
+	if err != nil {
+		return nil, err
+	}
+	report.EstimatedEnd = scheduledTime.Add(v.estimateDuration(quiz, total, adBreaks))
+	if err := v.checkOverlaps(report, quiz.ID); err != nil {
+		return nil, err
+	}
+	v.checkPrizeFund(report, quiz)
+
+	report.Valid = true
+	for _, check := range report.Checks {
+		if check.Status == ScheduleCheckCritical {
+			report.Valid = false
+		}
+	}
+	return report, nil
+}
+
+func (r *ScheduleValidationReport) add(name, status, message string) {
+	r.Checks = append(r.Checks, ScheduleCheck{Name: name, Status: status, Message: message})
+}
+
+// Failures возвращает критические проверки
+func (r *ScheduleValidationReport) Failures() []ScheduleCheck {
+	var failures []ScheduleCheck
+	for _, check := range r.Checks {
+		if check.Status == ScheduleCheckCritical {
+			failures = append(failures, check)
+		}
+	}
+	return failures
+}
+
+func (v *ScheduleValidator) checkTime(report *ScheduleValidationReport, quiz *entity.Quiz, scheduledTime time.Time) {
+	switch {
+	case !scheduledTime.After(time.Now()):
+		report.add("scheduled_time", ScheduleCheckCritical, "scheduled time must be in the future")
+	case quiz.IsCompleted():
+		report.add("scheduled_time", ScheduleCheckCritical, "completed quiz cannot be rescheduled")
+	case quiz.IsActive():
+		report.add("scheduled_time", ScheduleCheckCritical, "quiz is in progress")
+	default:
+		report.add("scheduled_time", ScheduleCheckOK, "")
+	}
+}
+
+// checkQuestions проверяет вопросы так же, как Scheduler.ScheduleQuiz, и дополнительно —
+// наличие вопросов каждого уровня сложности по базовой адаптивной схеме.
+// Учитываются только одобренные вопросы. Возвращает число вопросов, которое будет задано.
+func (v *ScheduleValidator) checkQuestions(report *ScheduleValidationReport, quiz *entity.Quiz) (int, error) {
+	approved := quiz.ApprovedQuestions()
+	quizByDifficulty := make(map[int]int)
+	for _, question := range approved {
+		quizByDifficulty[question.Difficulty]++
+	}
+	questions := &report.Questions
+	questions.Mode = quiz.QuestionSourceMode
+	questions.QuizQuestions = len(approved)
+	questions.Unapproved = len(quiz.Questions) - len(approved)
+
+	if quiz.IsAdminOnlyMode() {
+		questions.Required = len(approved)
+		switch {
+		case questions.Required == 0 && questions.Unapproved > 0:
+			report.add("questions", ScheduleCheckCritical, fmt.Sprintf("quiz in admin_only mode has no approved questions, %d awaiting review", questions.Unapproved))
+		case questions.Required == 0:
+			report.add("questions", ScheduleCheckCritical, "quiz in admin_only mode must contain at least 1 question")
+		case questions.Unapproved > 0:
+			report.add("questions", ScheduleCheckWarning, fmt.Sprintf("%d questions are not approved and will be skipped", questions.Unapproved))
+		default:
+			report.add("questions", ScheduleCheckOK, "")
+		}
+		for _, d := range sortedKeys(quizByDifficulty) {
+			questions.ByDifficulty = append(questions.ByDifficulty, DifficultyAvailability{
+				Difficulty: d, Required: quizByDifficulty[d], QuizQuestions: quizByDifficulty[d],
+			})
+		}
+		return questions.Required, nil
+	}
+
+	questions.Required = v.config.MaxQuestionsPerQuiz
+	_, available, poolByDifficulty, err := v.deps.QuestionRepo.GetPoolStats()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get question pool stats: %w", err)
+	}
+	questions.PoolAvailable = available
+
+	required := make(map[int]int)
+	for i := 1; i <= questions.Required; i++ {
+		required[v.difficulty.GetBaseDifficulty(i)]++
+	}
+	var short []int
+	for d := v.difficulty.MinDifficulty; d <= v.difficulty.MaxDifficulty; d++ {
+		item := DifficultyAvailability{
+			Difficulty:    d,
+			Required:      required[d],
+			QuizQuestions: quizByDifficulty[d],
+			PoolAvailable: poolByDifficulty[d],
+		}
+		questions.ByDifficulty = append(questions.ByDifficulty, item)
+		if int64(item.QuizQuestions)+item.PoolAvailable < int64(item.Required) {
+			short = append(short, d)
+		}
+	}
+
+	needed := questions.Required - questions.QuizQuestions
+	switch {
+	case needed > 0 && available < int64(needed):
+		report.add("questions", ScheduleCheckCritical, fmt.Sprintf("not enough questions: quiz has %d, pool has %d of %d needed", questions.QuizQuestions, available, needed))
+	case len(short) > 0:
+		report.add("questions", ScheduleCheckWarning, fmt.Sprintf("not enough questions of difficulty %v, neighbouring levels will be used", short))
+	case questions.Unapproved > 0:
+		report.add("questions", ScheduleCheckWarning, fmt.Sprintf("%d quiz questions are not approved, pool questions will be used instead", questions.Unapproved))
+	default:
+		report.add("questions", ScheduleCheckOK, "")
+	}
+	return questions.Required, nil
+}
+
+// checkAdSlots сообщает, какие рекламные слоты будут показаны, и возвращает их суммарную длительность
+func (v *ScheduleValidator) checkAdSlots(report *ScheduleValidationReport, quiz *entity.Quiz, totalQuestions int) (time.Duration, error) {
+	if v.deps.QuizAdSlotRepo == nil {
+		report.add("ad_slots", ScheduleCheckOK, "")
+		return 0, nil
+	}
+	slots, err := v.deps.QuizAdSlotRepo.ListByQuizID(quiz.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list ad slots: %w", err)
+	}
+
+	timing := v.config.Timing().ForQuiz(quiz)
+	var total time.Duration
+	var broken int
+	for i := range slots {
+		slot := &slots[i]
+		item := ScheduleAdSlot{SlotID: slot.ID, QuestionAfter: slot.QuestionAfter}
+		playback := adBreakDuration(slot, timing)
+		item.DurationSec = int(playback.Seconds())
+		switch {
+		case !slot.IsActive:
+			item.Reason = "inactive"
+		case slot.AdAsset == nil:
+			item.Reason = "ad asset not found"
+			broken++
+		case slot.QuestionAfter < 1 || slot.QuestionAfter > totalQuestions:
+			item.Reason = fmt.Sprintf("quiz has %d questions", totalQuestions)
+			broken++
+		case playback <= 0:
+			item.Reason = "zero duration"
+			broken++
+		default:
+			item.WillShow = true
+			total += playback
+		}
+		report.AdSlots = append(report.AdSlots, item)
+	}
+
+	if broken > 0 {
+		report.add("ad_slots", ScheduleCheckWarning, fmt.Sprintf("%d active ad slots will not be shown", broken))
+	} else {
+		report.add("ad_slots", ScheduleCheckOK, "")
+	}
+	return total, nil
+}
+
+// checkOverlaps ищет запланированные и идущую викторины, чьё время пересекается с этой
+func (v *ScheduleValidator) checkOverlaps(report *ScheduleValidationReport, quizID uint) error {
+	others, err := v.deps.QuizRepo.GetScheduled()
+	if err != nil {
+		return fmt.Errorf("failed to list scheduled quizzes: %w", err)
+	}
+	active, err := v.deps.QuizRepo.GetActive()
+	if err != nil && !errors.Is(err, apperrors.ErrNotFound) {
+		return fmt.Errorf("failed to get active quiz: %w", err)
+	}
+	if active != nil {
+		others = append(others, *active)
+	}
+
+	// Зал ожидания следующей викторины не должен открываться во время другой
+	lobby := time.Duration(v.config.Timing().WaitingRoomMinutes) * time.Minute
+	var nearby []uint
+	for i := range others {
+		other := &others[i]
+		if other.ID == quizID {
+			continue
+		}
+		end, err := v.EstimateEnd(other)
+		if err != nil {
+			return err
+		}
+		if report.ScheduledTime.Before(end) && other.ScheduledTime.Before(report.EstimatedEnd) {
+			report.Overlaps = append(report.Overlaps, ScheduleOverlap{
+				QuizID:        other.ID,
+				Title:         other.Title,
+				Status:        other.Status,
+				ScheduledTime: other.ScheduledTime,
+				EstimatedEnd:  end,
+			})
+		} else if report.ScheduledTime.Add(-lobby).Before(end) && other.ScheduledTime.Add(-lobby).Before(report.EstimatedEnd) {
+			nearby = append(nearby, other.ID)
+		}
+	}
+
+	switch {
+	case len(report.Overlaps) > 0:
+		report.add("overlap", ScheduleCheckCritical, fmt.Sprintf("overlaps with %d other quizzes", len(report.Overlaps)))
+	case len(nearby) > 0:
+		report.add("overlap", ScheduleCheckWarning, fmt.Sprintf("waiting room overlaps with quizzes %v", nearby))
+	default:
+		report.add("overlap", ScheduleCheckOK, "")
+	}
+	return nil
+}
+
+func (v *ScheduleValidator) checkPrizeFund(report *ScheduleValidationReport, quiz *entity.Quiz) {
+	report.PrizeFund = SchedulePrizeFund{Configured: quiz.PrizeFund, Effective: quiz.PrizeFund}
+	switch {
+	case quiz.PrizeFund < 0:
+		report.add("prize_fund", ScheduleCheckCritical, "prize fund must not be negative")
+	case quiz.PrizeFund == 0:
+		report.PrizeFund.Effective = v.config.TotalPrizeFund
+		report.add("prize_fund", ScheduleCheckWarning, fmt.Sprintf("prize fund is not set, default %d will be used", v.config.TotalPrizeFund))
+	default:
+		report.add("prize_fund", ScheduleCheckOK, "")
+	}
+}
+
+// EstimateEnd оценивает время окончания викторины по её вопросам и рекламным слотам
+func (v *ScheduleValidator) EstimateEnd(quiz *entity.Quiz) (time.Time, error) {
+	withQuestions, err := v.deps.QuizRepo.GetWithQuestions(quiz.ID)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to load quiz #%d: %w", quiz.ID, err)
+	}
+	total := v.config.MaxQuestionsPerQuiz
+	if withQuestions.IsAdminOnlyMode() {
+		total = len(withQuestions.ApprovedQuestions())
+	}
+	timing := v.config.Timing().ForQuiz(withQuestions)
+	var adBreaks time.Duration
+	if v.deps.QuizAdSlotRepo != nil {
+		slots, err := v.deps.QuizAdSlotRepo.ListByQuizID(quiz.ID)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to list ad slots: %w", err)
+		}
+		for i := range slots {
+			if slots[i].IsActive && slots[i].QuestionAfter >= 1 && slots[i].QuestionAfter <= total {
+				adBreaks += adBreakDuration(&slots[i], timing)
+			}
+		}
+	}
+	return quiz.ScheduledTime.Add(v.estimateDuration(withQuestions, total, adBreaks)), nil
+}
+
+// estimateDuration оценивает длительность викторины по таймингам QuestionManager.
+// Для вопросов из пула берётся лимит по умолчанию.
+func (v *ScheduleValidator) estimateDuration(quiz *entity.Quiz, totalQuestions int, adBreaks time.Duration) time.Duration {
+	timing := v.config.Timing().ForQuiz(quiz)
+	approved := quiz.ApprovedQuestions()
+	var duration time.Duration
+	for i := 0; i < totalQuestions; i++ {
+		limit := defaultQuestionTimeLimitSec
+		if i < len(approved) && approved[i].TimeLimitSec > 0 {
+			limit = approved[i].TimeLimitSec
+		}
+		duration += questionDuration(timing, limit, i == totalQuestions-1)
+	}
+	return duration + adBreaks
+}
+
+func sortedKeys(m map[int]int) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
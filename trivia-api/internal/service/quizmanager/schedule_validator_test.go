@@ -0,0 +1,132 @@
+package quizmanager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+func checkStatuses(report *ScheduleValidationReport) map[string]string {
+	statuses := make(map[string]string)
+	for _, check := range report.Checks {
+		statuses[check.Name] = check.Status
+	}
+	return statuses
+}
+
+func TestScheduleValidator_Valid(t *testing.T) {
+	mockQuizRepo := new(MockQuizRepoForScheduler)
+	mockQuestionRepo := new(MockQuestionRepoForScheduler)
+	config := DefaultConfig()
+
+	scheduledTime := time.Now().Add(time.Hour)
+	quiz := &entity.Quiz{ID: 1, Status: entity.QuizStatusScheduled, QuestionSourceMode: entity.QuizQuestionSourceHybrid, PrizeFund: 5000}
+	// Другая викторина через сутки не пересекается
+	other := entity.Quiz{ID: 2, Status: entity.QuizStatusScheduled, ScheduledTime: scheduledTime.Add(24 * time.Hour), QuestionSourceMode: entity.QuizQuestionSourceHybrid}
+
+	mockQuizRepo.On("GetWithQuestions", uint(1)).Return(quiz, nil)
+	mockQuizRepo.On("GetWithQuestions", uint(2)).Return(&other, nil)
+	mockQuizRepo.On("GetScheduled").Return([]entity.Quiz{other}, nil)
+	mockQuizRepo.On("GetActive").Return(nil, apperrors.ErrNotFound)
+	mockQuestionRepo.On("GetPoolStats").Return(int64(100), int64(100), map[int]int64{1: 20, 2: 20, 3: 20, 4: 20, 5: 20}, nil)
+
+	validator := NewScheduleValidator(config, &Dependencies{QuizRepo: mockQuizRepo, QuestionRepo: mockQuestionRepo})
+	report, err := validator.Validate(1, scheduledTime)
+	require.NoError(t, err)
+
+	assert.True(t, report.Valid)
+	assert.Empty(t, report.Failures())
+	assert.Empty(t, report.Overlaps)
+	assert.True(t, report.EstimatedEnd.After(scheduledTime))
+	assert.Equal(t, config.MaxQuestionsPerQuiz, report.Questions.Required)
+	assert.Equal(t, 5000, report.PrizeFund.Effective)
+	for name, status := range checkStatuses(report) {
+		assert.Equal(t, ScheduleCheckOK, status, name)
+	}
+}
+
+func TestScheduleValidator_CriticalFailures(t *testing.T) {
+	mockQuizRepo := new(MockQuizRepoForScheduler)
+	mockQuestionRepo := new(MockQuestionRepoForScheduler)
+	config := DefaultConfig()
+
+	scheduledTime := time.Now().Add(time.Hour)
+	quiz := &entity.Quiz{ID: 1, Status: entity.QuizStatusScheduled, QuestionSourceMode: entity.QuizQuestionSourceHybrid}
+	// Другая викторина начинается через минуту и ещё идёт
+	other := entity.Quiz{ID: 2, Title: "Evening", Status: entity.QuizStatusScheduled, ScheduledTime: scheduledTime.Add(time.Minute), QuestionSourceMode: entity.QuizQuestionSourceHybrid}
+
+	mockQuizRepo.On("GetWithQuestions", uint(1)).Return(quiz, nil)
+	mockQuizRepo.On("GetWithQuestions", uint(2)).Return(&other, nil)
+	mockQuizRepo.On("GetScheduled").Return([]entity.Quiz{other}, nil)
+	mockQuizRepo.On("GetActive").Return(nil, apperrors.ErrNotFound)
+	mockQuestionRepo.On("GetPoolStats").Return(int64(100), int64(3), map[int]int64{1: 3}, nil)
+
+	validator := NewScheduleValidator(config, &Dependencies{QuizRepo: mockQuizRepo, QuestionRepo: mockQuestionRepo})
+	report, err := validator.Validate(1, scheduledTime)
+	require.NoError(t, err)
+
+	assert.False(t, report.Valid)
+	statuses := checkStatuses(report)
+	assert.Equal(t, ScheduleCheckCritical, statuses["questions"])
+	assert.Equal(t, ScheduleCheckCritical, statuses["overlap"])
+	assert.Equal(t, ScheduleCheckWarning, statuses["prize_fund"])
+	assert.Equal(t, config.TotalPrizeFund, report.PrizeFund.Effective)
+	require.Len(t, report.Overlaps, 1)
+	assert.Equal(t, uint(2), report.Overlaps[0].QuizID)
+	assert.Len(t, report.Failures(), 2)
+}
+
+func TestScheduleValidator_AdminOnlyWithoutQuestions(t *testing.T) {
+	mockQuizRepo := new(MockQuizRepoForScheduler)
+	config := DefaultConfig()
+
+	quiz := &entity.Quiz{ID: 1, Status: entity.QuizStatusScheduled, QuestionSourceMode: entity.QuizQuestionSourceAdminOnly, PrizeFund: 1000}
+	mockQuizRepo.On("GetWithQuestions", uint(1)).Return(quiz, nil)
+	mockQuizRepo.On("GetScheduled").Return([]entity.Quiz{}, nil)
+	mockQuizRepo.On("GetActive").Return(nil, apperrors.ErrNotFound)
+
+	validator := NewScheduleValidator(config, &Dependencies{QuizRepo: mockQuizRepo})
+	report, err := validator.Validate(1, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+
+	assert.False(t, report.Valid)
+	statuses := checkStatuses(report)
+	assert.Equal(t, ScheduleCheckCritical, statuses["scheduled_time"])
+	assert.Equal(t, ScheduleCheckCritical, statuses["questions"])
+}
+
+func TestScheduleValidator_AdminOnlyUnapprovedQuestions(t *testing.T) {
+	mockQuizRepo := new(MockQuizRepoForScheduler)
+	config := DefaultConfig()
+
+	quizID := uint(1)
+	quiz := &entity.Quiz{ID: quizID, Status: entity.QuizStatusScheduled, QuestionSourceMode: entity.QuizQuestionSourceAdminOnly, PrizeFund: 1000,
+		Questions: []entity.Question{
+			{ID: 10, QuizID: &quizID, Difficulty: 1, ReviewStatus: entity.QuestionReviewPendingReview},
+			{ID: 20, QuizID: &quizID, Difficulty: 2, ReviewStatus: entity.QuestionReviewDraft},
+		}}
+	mockQuizRepo.On("GetWithQuestions", quizID).Return(quiz, nil)
+	mockQuizRepo.On("GetScheduled").Return([]entity.Quiz{}, nil)
+	mockQuizRepo.On("GetActive").Return(nil, apperrors.ErrNotFound)
+
+	validator := NewScheduleValidator(config, &Dependencies{QuizRepo: mockQuizRepo})
+	report, err := validator.Validate(quizID, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.False(t, report.Valid)
+	assert.Equal(t, ScheduleCheckCritical, checkStatuses(report)["questions"])
+	assert.Equal(t, 0, report.Questions.QuizQuestions)
+	assert.Equal(t, 2, report.Questions.Unapproved)
+
+	// Одобренный вопрос будет задан, непроверенный пропущен
+	quiz.Questions[0].ReviewStatus = entity.QuestionReviewApproved
+	report, err = validator.Validate(quizID, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.True(t, report.Valid)
+	assert.Equal(t, ScheduleCheckWarning, checkStatuses(report)["questions"])
+	assert.Equal(t, 1, report.Questions.Required)
+	assert.Equal(t, 1, report.Questions.Unapproved)
+}
@@ -33,8 +33,24 @@ type Scheduler struct {
 
 	// Канал для сигнализации о запуске викторины
 	quizStartCh chan uint
+
+	// Опциональный отправитель push-напоминаний (защищен mu)
+	notifier QuizNotifier
+	// Опциональная пауза запуска викторин, например режим обслуживания (защищена mu)
+	pause SchedulingPause
+	// Опциональные ссылки на медиа вопросов для предзагрузки (защищены mu)
+	media QuestionMediaSigner
+	// Опциональная оценка аудитории для резерва ёмкости WebSocket (защищена mu)
+	forecast ParticipantForecast
+	// Опциональные объявления ведущего до начала викторины (защищены mu)
+	announcer Announcer
+	// Опциональный брендинг спонсора в событии запуска (защищён mu)
+	sponsorship Sponsorship
 }
 
+// pausePollInterval — как часто проверять, снята ли пауза запуска викторин
+const pausePollInterval = 5 * time.Second
+
 // NewScheduler создает новый планировщик викторин
 func NewScheduler(config *Config, deps *Dependencies) *Scheduler {
 	return &Scheduler{
@@ -45,6 +61,61 @@ func NewScheduler(config *Config, deps *Dependencies) *Scheduler {
 	}
 }
 
+// SetNotifier устанавливает отправитель push-напоминаний о викторинах
+func (s *Scheduler) SetNotifier(notifier QuizNotifier) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.notifier = notifier
+}
+
+// SetPause подключает паузу запуска викторин: пока она активна, наступившие викторины ждут
+func (s *Scheduler) SetPause(pause SchedulingPause) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pause = pause
+}
+
+// SetMedia подключает ссылки на медиа вопросов: они рассылаются в начале обратного отсчёта
+func (s *Scheduler) SetMedia(media QuestionMediaSigner) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.media = media
+}
+
+// SetParticipantForecast подключает оценку аудитории: при открытии зала ожидания
+// шарды WebSocket заранее готовятся к ожидаемому числу участников
+func (s *Scheduler) SetParticipantForecast(forecast ParticipantForecast) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.forecast = forecast
+}
+
+// waitWhilePaused ждёт снятия паузы запуска. Возвращает false, если запуск отменён.
+func (s *Scheduler) waitWhilePaused(ctx context.Context, quizID uint) bool {
+	s.mu.Lock()
+	pause := s.pause
+	s.mu.Unlock()
+	if pause == nil || !pause.Active() {
+		return true
+	}
+
+	log.Printf("[Scheduler] Викторина #%d: запуск приостановлен до снятия паузы", quizID)
+	ticker := time.NewTicker(pausePollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if !pause.Active() {
+				log.Printf("[Scheduler] Викторина #%d: пауза снята, продолжаю запуск", quizID)
+				return true
+			}
+		case <-ctx.Done():
+			log.Printf("[Scheduler] Викторина #%d: запуск отменен во время паузы", quizID)
+			return false
+		}
+	}
+}
+
 // GetQuizStartChannel возвращает канал для уведомлений о запуске викторин
 func (s *Scheduler) GetQuizStartChannel() <-chan uint {
 	return s.quizStartCh
@@ -63,10 +134,11 @@ func (s *Scheduler) ScheduleQuiz(ctx context.Context, quizID uint, scheduledTime
 		return err
 	}
 
-	quizQCount := len(quiz.Questions)
+	// В эфир попадают только одобренные вопросы
+	quizQCount := len(quiz.ApprovedQuestions())
 	if quiz.IsAdminOnlyMode() {
 		if quizQCount == 0 {
-			return fmt.Errorf("quiz in admin_only mode must contain at least 1 question")
+			return fmt.Errorf("quiz in admin_only mode must contain at least 1 approved question")
 		}
 		log.Printf("[Scheduler] Quiz #%d scheduled in admin_only mode with %d quiz-specific questions", quizID, quizQCount)
 	} else {
@@ -160,6 +232,7 @@ func (s *Scheduler) CancelQuiz(quizID uint) error {
 
 	// Отправляем уведомление пользователям
 	if s.deps.WSManager != nil {
+		s.deps.WSManager.ReleaseQuizCapacity(quizID)
 		cancelEvent := map[string]interface{}{
 			"type": "quiz:cancelled",
 			"data": map[string]interface{}{
@@ -232,7 +305,11 @@ func (s *Scheduler) runQuizSequence(ctx context.Context, quiz *entity.Quiz, myTo
 	// Важно: перед каждым этапом обновляем quiz из БД, чтобы учитывать актуальный scheduled_time.
 	// Это защищает от рассинхрона времени в рамках длинной sequence.
 	quiz = s.refreshQuiz(quiz)
-	announcementTime := quiz.ScheduledTime.Add(-time.Duration(s.config.AnnouncementMinutes) * time.Minute)
+	go s.runLobbyAnnouncements(ctx, quiz)
+	// Напоминание ждёт своего срока отдельно: порядок ReminderMinutes относительно анонса
+	// и зала ожидания не задан, и ожидание напоминания не должно их задерживать
+	go s.runQuizReminder(ctx, quiz)
+	announcementTime := quiz.ScheduledTime.Add(-time.Duration(s.config.Timing().AnnouncementMinutes) * time.Minute)
 
 	// Планируем анонс, если время еще не наступило
 	if announcementTime.After(time.Now()) {
@@ -251,7 +328,7 @@ func (s *Scheduler) runQuizSequence(ctx context.Context, quiz *entity.Quiz, myTo
 
 	// Планируем открытие зала ожидания, если время еще не наступило
 	quiz = s.refreshQuiz(quiz)
-	waitingRoomTime := quiz.ScheduledTime.Add(-time.Duration(s.config.WaitingRoomMinutes) * time.Minute)
+	waitingRoomTime := quiz.ScheduledTime.Add(-time.Duration(s.config.Timing().WaitingRoomMinutes) * time.Minute)
 	if waitingRoomTime.After(time.Now()) {
 		timeToWaitingRoom := time.Until(waitingRoomTime)
 		log.Printf("[Scheduler] Викторина #%d: планирую открытие зала ожидания через %v", quiz.ID, timeToWaitingRoom)
@@ -268,7 +345,7 @@ func (s *Scheduler) runQuizSequence(ctx context.Context, quiz *entity.Quiz, myTo
 
 	// Планируем обратный отсчет, если время еще не наступило
 	quiz = s.refreshQuiz(quiz)
-	countdownTime := quiz.ScheduledTime.Add(-time.Duration(s.config.CountdownSeconds) * time.Second)
+	countdownTime := quiz.ScheduledTime.Add(-time.Duration(s.config.Timing().CountdownSeconds) * time.Second)
 	startTime := quiz.ScheduledTime
 	if countdownTime.After(time.Now()) {
 		timeToCountdown := time.Until(countdownTime)
@@ -287,6 +364,7 @@ func (s *Scheduler) runQuizSequence(ctx context.Context, quiz *entity.Quiz, myTo
 		// ждем точного времени начала
 		timeToStart := time.Until(startTime)
 		log.Printf("[Scheduler] Викторина #%d: слишком поздно для отсчета, ожидание начала (%v)", quiz.ID, timeToStart)
+		s.triggerMediaPreload(ctx, quiz)
 
 		select {
 		case <-time.After(timeToStart):
@@ -303,6 +381,30 @@ func (s *Scheduler) runQuizSequence(ctx context.Context, quiz *entity.Quiz, myTo
 	}
 }
 
+// runQuizReminder отправляет push-напоминание, если настроен отправитель и время еще не наступило
+func (s *Scheduler) runQuizReminder(ctx context.Context, quiz *entity.Quiz) {
+	s.mu.Lock()
+	notifier := s.notifier
+	s.mu.Unlock()
+	if notifier == nil || s.config.Timing().ReminderMinutes <= 0 {
+		return
+	}
+
+	reminderTime := quiz.ScheduledTime.Add(-time.Duration(s.config.Timing().ReminderMinutes) * time.Minute)
+	if !reminderTime.After(time.Now()) {
+		return
+	}
+	timeToReminder := time.Until(reminderTime)
+	log.Printf("[Scheduler] Викторина #%d: планирую push-напоминание через %v", quiz.ID, timeToReminder)
+
+	select {
+	case <-time.After(timeToReminder):
+		notifier.NotifyQuizStartingSoon(s.refreshQuiz(quiz))
+	case <-ctx.Done():
+		log.Printf("[Scheduler] Викторина #%d: push-напоминание отменено", quiz.ID)
+	}
+}
+
 // triggerAnnouncement отправляет анонс о предстоящей викторине
 func (s *Scheduler) triggerAnnouncement(ctx context.Context, quiz *entity.Quiz) {
 	quiz = s.refreshQuiz(quiz)
@@ -332,6 +434,7 @@ func (s *Scheduler) triggerAnnouncement(ctx context.Context, quiz *entity.Quiz)
 func (s *Scheduler) triggerWaitingRoom(ctx context.Context, quiz *entity.Quiz) {
 	quiz = s.refreshQuiz(quiz)
 	log.Printf("[Scheduler] Открытие зала ожидания для викторины #%d", quiz.ID)
+	s.reserveCapacity(quiz.ID)
 
 	// Рассчитываем оставшееся время до старта викторины
 	timeToStart := time.Until(quiz.ScheduledTime)
@@ -353,11 +456,29 @@ func (s *Scheduler) triggerWaitingRoom(ctx context.Context, quiz *entity.Quiz) {
 	s.deps.WSManager.BroadcastEventToQuiz(quiz.ID, fullEvent)
 }
 
+// reserveCapacity готовит шарды WebSocket к ожидаемой аудитории до того, как участники
+// начнут подключаться к залу ожидания
+func (s *Scheduler) reserveCapacity(quizID uint) {
+	s.mu.Lock()
+	forecast := s.forecast
+	s.mu.Unlock()
+	if forecast == nil || s.deps.WSManager == nil {
+		return
+	}
+	expected, err := forecast.ExpectedParticipants(quizID)
+	if err != nil {
+		log.Printf("[Scheduler] Викторина #%d: не удалось оценить аудиторию: %v", quizID, err)
+		return
+	}
+	s.deps.WSManager.ReserveQuizCapacity(quizID, expected)
+}
+
 // triggerCountdown запускает обратный отсчет для викторины
 func (s *Scheduler) triggerCountdown(ctx context.Context, quiz *entity.Quiz) {
 	quiz = s.refreshQuiz(quiz)
 	startTime := quiz.ScheduledTime
 	log.Printf("[Scheduler] Запуск обратного отсчета для викторины #%d", quiz.ID)
+	s.triggerMediaPreload(ctx, quiz)
 
 	ticker := time.NewTicker(1 * time.Second)
 	defer ticker.Stop()
@@ -392,8 +513,62 @@ func (s *Scheduler) triggerCountdown(ctx context.Context, quiz *entity.Quiz) {
 	}
 }
 
+// triggerMediaPreload рассылает ссылки на медиа вопросов викторины, чтобы клиенты загрузили их
+// во время обратного отсчёта. Вопросы из общего пула выбираются по ходу викторины и заранее
+// неизвестны — их медиа приходит только в quiz:question.
+func (s *Scheduler) triggerMediaPreload(ctx context.Context, quiz *entity.Quiz) {
+	s.mu.Lock()
+	media := s.media
+	s.mu.Unlock()
+	if media == nil {
+		return
+	}
+
+	withQuestions, err := s.deps.QuizRepo.GetWithQuestions(quiz.ID)
+	if err != nil {
+		log.Printf("[Scheduler] WARNING: Викторина #%d: не удалось загрузить вопросы для предзагрузки медиа: %v", quiz.ID, err)
+		return
+	}
+	items := make([]map[string]interface{}, 0)
+	var expiresAt int64
+	for i := range withQuestions.Questions {
+		question := &withQuestions.Questions[i]
+		links, err := media.QuestionMedia(ctx, question)
+		if err != nil {
+			log.Printf("[Scheduler] WARNING: Викторина #%d: не удалось получить медиа вопроса %d: %v", quiz.ID, question.ID, err)
+			continue
+		}
+		if links == nil {
+			continue
+		}
+		items = append(items, map[string]interface{}{
+			"question_id": question.ID,
+			"image_url":   links.ImageURL,
+			"audio_url":   links.AudioURL,
+		})
+		expiresAt = links.ExpiresAt
+	}
+	if len(items) == 0 {
+		return
+	}
+
+	log.Printf("[Scheduler] Викторина #%d: предзагрузка медиа %d вопросов", quiz.ID, len(items))
+	s.deps.WSManager.BroadcastEventToQuiz(quiz.ID, map[string]interface{}{
+		"type": "quiz:media_preload",
+		"data": map[string]interface{}{
+			"quiz_id":    quiz.ID,
+			"items":      items,
+			"expires_at": expiresAt,
+		},
+	})
+}
+
 // triggerQuizStart запускает викторину
 func (s *Scheduler) triggerQuizStart(ctx context.Context, quiz *entity.Quiz) {
+	if !s.waitWhilePaused(ctx, quiz.ID) {
+		return
+	}
+
 	// Перечитываем актуальные данные (title, description, prize могли измениться)
 	quiz = s.refreshQuiz(quiz)
 	log.Printf("[Scheduler] Запуск викторины #%d", quiz.ID)
@@ -431,12 +606,13 @@ func (s *Scheduler) triggerQuizStart(ctx context.Context, quiz *entity.Quiz) {
 	}
 
 	// 2. Фиксируем QuestionCount (точечно, без перезаписи остальных полей).
-	// admin_only: играем ровно N вопросов админа; hybrid: как и раньше, плановое значение из конфига.
+	// admin_only: играем ровно N одобренных вопросов админа (question_count учитывает и непроверенные);
+	// hybrid: как и раньше, плановое значение из конфига.
 	if quiz.IsAdminOnlyMode() {
-		if quiz.QuestionCount <= 0 {
-			quizWithQuestions, qErr := s.deps.QuizRepo.GetWithQuestions(quiz.ID)
-			if qErr == nil && len(quizWithQuestions.Questions) > 0 {
-				quiz.QuestionCount = len(quizWithQuestions.Questions)
+		quizWithQuestions, qErr := s.deps.QuizRepo.GetWithQuestions(quiz.ID)
+		if qErr == nil {
+			if approved := len(quizWithQuestions.ApprovedQuestions()); approved > 0 {
+				quiz.QuestionCount = approved
 			}
 		}
 		if quiz.QuestionCount <= 0 {
@@ -456,6 +632,9 @@ func (s *Scheduler) triggerQuizStart(ctx context.Context, quiz *entity.Quiz) {
 		"title":          quiz.Title,
 		"question_count": quiz.QuestionCount,
 	}
+	if sponsor := s.startSponsor(quiz); sponsor != nil {
+		startEvent["sponsor"] = sponsor
+	}
 	fullEvent := map[string]interface{}{
 		"type": "quiz:start",
 		"data": startEvent,
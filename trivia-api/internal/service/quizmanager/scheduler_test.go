@@ -2,6 +2,8 @@ package quizmanager
 
 import (
 	"context"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -10,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/yourusername/trivia-api/internal/domain/entity"
 	"github.com/yourusername/trivia-api/internal/domain/repository"
+	"github.com/yourusername/trivia-api/internal/websocket"
 )
 
 // ============================================================================
@@ -29,6 +32,14 @@ func (m *MockQuizRepoForScheduler) GetByID(id uint) (*entity.Quiz, error) {
 	return args.Get(0).(*entity.Quiz), args.Error(1)
 }
 
+func (m *MockQuizRepoForScheduler) GetByIDs(ids []uint) ([]entity.Quiz, error) {
+	args := m.Called(ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Quiz), args.Error(1)
+}
+
 func (m *MockQuizRepoForScheduler) GetWithQuestions(id uint) (*entity.Quiz, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
@@ -57,6 +68,56 @@ func (m *MockQuizRepoForScheduler) UpdateScheduleInfo(quizID uint, scheduledTime
 	return args.Error(0)
 }
 
+func (m *MockQuizRepoForScheduler) UpdateSecondChance(quizID uint, mode string, cost int64, adAssetID *uint) error {
+	args := m.Called(quizID, mode, cost, adAssetID)
+	return args.Error(0)
+}
+
+func (m *MockQuizRepoForScheduler) UpdateMaxPlayers(quizID uint, maxPlayers int) error {
+	args := m.Called(quizID, maxPlayers)
+	return args.Error(0)
+}
+
+func (m *MockQuizRepoForScheduler) UpdateTiming(quizID uint, timing entity.QuizTiming) error {
+	args := m.Called(quizID, timing)
+	return args.Error(0)
+}
+
+func (m *MockQuizRepoForScheduler) UpdateGameMode(quizID uint, mode string, lives int, suddenDeath bool) error {
+	args := m.Called(quizID, mode, lives, suddenDeath)
+	return args.Error(0)
+}
+
+func (m *MockQuizRepoForScheduler) UpdateTieBreak(quizID uint, tieBreak string) error {
+	args := m.Called(quizID, tieBreak)
+	return args.Error(0)
+}
+
+func (m *MockQuizRepoForScheduler) UpdateSponsor(quizID uint, sponsor entity.QuizSponsor) error {
+	args := m.Called(quizID, sponsor)
+	return args.Error(0)
+}
+
+func (m *MockQuizRepoForScheduler) UpdateScoringStrategy(quizID uint, strategy string) error {
+	args := m.Called(quizID, strategy)
+	return args.Error(0)
+}
+
+func (m *MockQuizRepoForScheduler) UpdateRequireVerifiedKK(quizID uint, require bool) error {
+	args := m.Called(quizID, require)
+	return args.Error(0)
+}
+
+func (m *MockQuizRepoForScheduler) UpdatePrizeLadder(quizID uint, ladder entity.PrizeLadder) error {
+	args := m.Called(quizID, ladder)
+	return args.Error(0)
+}
+
+func (m *MockQuizRepoForScheduler) UpdateAllowedCountries(quizID uint, countries entity.StringArray) error {
+	args := m.Called(quizID, countries)
+	return args.Error(0)
+}
+
 func (m *MockQuizRepoForScheduler) AtomicStartQuiz(quizID uint) error {
 	args := m.Called(quizID)
 	return args.Error(0)
@@ -171,22 +232,43 @@ func (m *MockQuestionRepoForScheduler) CountByDifficulty(difficulty int) (int64,
 	return args.Get(0).(int64), args.Error(1)
 }
 
-func (m *MockQuestionRepoForScheduler) GetQuizQuestionByDifficulty(quizID uint, difficulty int, excludeIDs []uint) (*entity.Question, error) {
-	args := m.Called(quizID, difficulty, excludeIDs)
+func (m *MockQuestionRepoForScheduler) GetQuizQuestionByDifficulty(quizID uint, difficulty int, excludeIDs []uint, verifiedKK bool) (*entity.Question, error) {
+	args := m.Called(quizID, difficulty, excludeIDs, verifiedKK)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*entity.Question), args.Error(1)
 }
 
-func (m *MockQuestionRepoForScheduler) GetPoolQuestionByDifficulty(difficulty int, excludeIDs []uint) (*entity.Question, error) {
-	args := m.Called(difficulty, excludeIDs)
+func (m *MockQuestionRepoForScheduler) GetPoolQuestionByDifficulty(difficulty int, excludeIDs []uint, verifiedKK bool) (*entity.Question, error) {
+	args := m.Called(difficulty, excludeIDs, verifiedKK)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*entity.Question), args.Error(1)
 }
 
+func (m *MockQuestionRepoForScheduler) GetCategoryPoolQuestionByDifficulty(categoryID uint, difficulty int, excludeIDs []uint, verifiedKK bool) (*entity.Question, error) {
+	args := m.Called(categoryID, difficulty, excludeIDs, verifiedKK)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Question), args.Error(1)
+}
+
+func (m *MockQuestionRepoForScheduler) GetPoolQuestionBySeed(difficulty int, seed uint64) (*entity.Question, error) {
+	args := m.Called(difficulty, seed)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Question), args.Error(1)
+}
+
+func (m *MockQuestionRepoForScheduler) GetCategoryPoolStats(categoryID uint) (total int64, available int64, byDifficulty map[int]int64, err error) {
+	args := m.Called(categoryID)
+	return args.Get(0).(int64), args.Get(1).(int64), args.Get(2).(map[int]int64), args.Error(3)
+}
+
 func (m *MockQuestionRepoForScheduler) GetPoolStats() (total int64, available int64, byDifficulty map[int]int64, err error) {
 	args := m.Called()
 	return args.Get(0).(int64), args.Get(1).(int64), args.Get(2).(map[int]int64), args.Error(3)
@@ -215,6 +297,19 @@ func (m *MockQuestionRepoForScheduler) GetQuizQuestionHistory(quizID uint) ([]en
 	return args.Get(0).([]entity.QuizQuestionHistory), args.Error(1)
 }
 
+func (m *MockQuestionRepoForScheduler) CountMediaReferences(key string) (int64, error) {
+	args := m.Called(key)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQuestionRepoForScheduler) ExistingTextHashes(hashes []string) (map[string]bool, error) {
+	args := m.Called(hashes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]bool), args.Error(1)
+}
+
 // MockWSManagerForScheduler реализует минимальный интерфейс WebSocket Manager
 type MockWSManagerForScheduler struct {
 	mock.Mock
@@ -443,6 +538,69 @@ func TestScheduler_Reschedule_NoDuplicateStart(t *testing.T) {
 	// Это подтверждается тем, что Map содержит только новый таймер
 }
 
+// recordingQuizNotifier запоминает викторины, о которых отправлено push-напоминание
+type recordingQuizNotifier struct {
+	mu       sync.Mutex
+	reminded []uint
+}
+
+func (n *recordingQuizNotifier) NotifyQuizStartingSoon(quiz *entity.Quiz) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.reminded = append(n.reminded, quiz.ID)
+}
+
+func (n *recordingQuizNotifier) count() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return len(n.reminded)
+}
+
+// Напоминание, назначенное позже открытия зала ожидания, не задерживает его
+func TestScheduler_RunQuizSequence_ReminderDoesNotDelayWaitingRoom(t *testing.T) {
+	config := DefaultConfig()
+	timing := config.Timing()
+	timing.AnnouncementMinutes = 3
+	timing.WaitingRoomMinutes = 2
+	timing.ReminderMinutes = 1
+	config.SetTiming(timing)
+
+	quiz := &entity.Quiz{ID: 1, Status: entity.QuizStatusScheduled, ScheduledTime: time.Now().Add(2*time.Minute + 100*time.Millisecond)}
+	mockQuizRepo := new(MockQuizRepoForScheduler)
+	mockQuizRepo.On("GetByID", uint(1)).Return(quiz, nil)
+	hub := &contractHub{}
+	scheduler := NewScheduler(config, &Dependencies{QuizRepo: mockQuizRepo, WSManager: websocket.NewManager(hub)})
+	notifier := &recordingQuizNotifier{}
+	scheduler.SetNotifier(notifier)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		scheduler.runQuizSequence(ctx, quiz, 0)
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool {
+		hub.mu.Lock()
+		defer hub.mu.Unlock()
+		for _, message := range hub.messages {
+			if strings.Contains(string(message), "quiz:waiting_room") {
+				return true
+			}
+		}
+		return false
+	}, 2*time.Second, 20*time.Millisecond, "зал ожидания открывается в срок")
+	assert.Zero(t, notifier.count(), "напоминание ещё не наступило")
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("последовательность не остановилась после отмены")
+	}
+	assert.Zero(t, notifier.count())
+}
+
 func TestScheduler_CancelQuiz_Success(t *testing.T) {
 	// Arrange
 	mockQuizRepo := new(MockQuizRepoForScheduler)
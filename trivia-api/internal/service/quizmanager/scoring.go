@@ -0,0 +1,166 @@
+package quizmanager
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+)
+
+// maxStreakMultiplier — предельный множитель серии верных ответов
+const maxStreakMultiplier = 5
+
+// ScoredAnswer — ответ, за который начисляются очки
+type ScoredAnswer struct {
+	Question       *entity.Question
+	IsCorrect      bool  // верный ответ, данный вовремя
+	ResponseTimeMs int64 // время ответа с учётом компенсации задержки
+	TimeLimitMs    int64 // лимит времени на вопрос с учётом пауз и продлений
+	Streak         int   // верных ответов подряд, включая этот (только для streak_multiplier)
+}
+
+// ScoringStrategy начисляет очки за ответ. Неверный или просроченный ответ очков не приносит.
+type ScoringStrategy interface {
+	Name() string
+	Points(answer ScoredAnswer) int
+}
+
+var scoringStrategies = map[string]ScoringStrategy{
+	entity.ScoringFlat:               flatScoring{},
+	entity.ScoringSpeedWeighted:      speedWeightedScoring{},
+	entity.ScoringStreakMultiplier:   streakMultiplierScoring{},
+	entity.ScoringDifficultyWeighted: difficultyWeightedScoring{},
+}
+
+// LookupScoringStrategy возвращает стратегию по имени; false — стратегия неизвестна
+func LookupScoringStrategy(name string) (ScoringStrategy, bool) {
+	strategy, ok := scoringStrategies[name]
+	return strategy, ok
+}
+
+// ScoringStrategyFor возвращает стратегию начисления очков викторины.
+// Неизвестное имя (например, из более новой версии сервера) считается flat.
+func ScoringStrategyFor(quiz *entity.Quiz) ScoringStrategy {
+	if strategy, ok := scoringStrategies[quiz.EffectiveScoringStrategy()]; ok {
+		return strategy
+	}
+	log.Printf("[Scoring] WARNING: Викторина #%d: неизвестная стратегия очков %q, используется flat", quiz.ID, quiz.ScoringStrategy)
+	return flatScoring{}
+}
+
+// basePoints — стоимость вопроса; вопросы без стоимости приносят 1 очко
+func basePoints(question *entity.Question) int {
+	if question.PointValue < 1 {
+		return 1
+	}
+	return question.PointValue
+}
+
+// flatScoring — 1 очко за верный ответ (исходная схема подсчёта)
+type flatScoring struct{}
+
+func (flatScoring) Name() string { return entity.ScoringFlat }
+
+func (flatScoring) Points(answer ScoredAnswer) int {
+	return answer.Question.CalculatePoints(answer.IsCorrect, answer.ResponseTimeMs)
+}
+
+// speedWeightedScoring — стоимость вопроса плюс бонус до той же величины пропорционально оставшемуся времени
+type speedWeightedScoring struct{}
+
+func (speedWeightedScoring) Name() string { return entity.ScoringSpeedWeighted }
+
+func (speedWeightedScoring) Points(answer ScoredAnswer) int {
+	if !answer.IsCorrect {
+		return 0
+	}
+	base := basePoints(answer.Question)
+	if answer.TimeLimitMs <= 0 {
+		return base
+	}
+	remaining := answer.TimeLimitMs - answer.ResponseTimeMs
+	if remaining < 0 {
+		remaining = 0
+	}
+	return base + int(int64(base)*remaining/answer.TimeLimitMs)
+}
+
+// streakMultiplierScoring — стоимость вопроса, умноженная на длину серии верных ответов (до maxStreakMultiplier)
+type streakMultiplierScoring struct{}
+
+func (streakMultiplierScoring) Name() string { return entity.ScoringStreakMultiplier }
+
+func (streakMultiplierScoring) Points(answer ScoredAnswer) int {
+	if !answer.IsCorrect {
+		return 0
+	}
+	multiplier := answer.Streak
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	if multiplier > maxStreakMultiplier {
+		multiplier = maxStreakMultiplier
+	}
+	return basePoints(answer.Question) * multiplier
+}
+
+// difficultyWeightedScoring — стоимость вопроса, умноженная на его сложность (1-5)
+type difficultyWeightedScoring struct{}
+
+func (difficultyWeightedScoring) Name() string { return entity.ScoringDifficultyWeighted }
+
+func (difficultyWeightedScoring) Points(answer ScoredAnswer) int {
+	if !answer.IsCorrect {
+		return 0
+	}
+	difficulty := answer.Question.Difficulty
+	if difficulty < 1 {
+		difficulty = 1
+	}
+	return basePoints(answer.Question) * difficulty
+}
+
+// streakKey — число верных ответов игрока подряд (для streak_multiplier)
+func streakKey(quizID, userID uint) string {
+	return fmt.Sprintf("quiz:%d:streak:%d", quizID, userID)
+}
+
+// nextStreak возвращает длину серии, которую продлит верный ответ игрока.
+// Серия ведётся только в викторинах со стратегией streak_multiplier.
+func nextStreak(cache repository.CacheRepository, quiz *entity.Quiz, userID uint) int {
+	if quiz.EffectiveScoringStrategy() != entity.ScoringStreakMultiplier {
+		return 0
+	}
+	value, err := cache.Get(streakKey(quiz.ID, userID))
+	if err != nil {
+		return 1 // серии ещё нет
+	}
+	streak, err := strconv.Atoi(value)
+	if err != nil {
+		return 1
+	}
+	return streak + 1
+}
+
+// updateStreak продлевает серию верных ответов игрока или обрывает её после ошибки или пропуска
+func updateStreak(cache repository.CacheRepository, quiz *entity.Quiz, userID uint, correct bool) {
+	if quiz.EffectiveScoringStrategy() != entity.ScoringStreakMultiplier {
+		return
+	}
+	key := streakKey(quiz.ID, userID)
+	if !correct {
+		if err := cache.Delete(key); err != nil {
+			log.Printf("[Scoring] WARNING: Не удалось сбросить серию пользователя #%d в викторине #%d: %v", userID, quiz.ID, err)
+		}
+		return
+	}
+	if _, err := cache.Increment(key); err != nil {
+		log.Printf("[Scoring] WARNING: Не удалось продлить серию пользователя #%d в викторине #%d: %v", userID, quiz.ID, err)
+		return
+	}
+	if err := cache.Expire(key, gameModeKeyTTL); err != nil {
+		log.Printf("[Scoring] WARNING: Не удалось установить TTL %s: %v", key, err)
+	}
+}
@@ -0,0 +1,73 @@
+package quizmanager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+func TestScoringStrategies_Points(t *testing.T) {
+	question := &entity.Question{PointValue: 10, Difficulty: 3}
+	unpriced := &entity.Question{}
+
+	cases := []struct {
+		strategy string
+		answer   ScoredAnswer
+		want     int
+	}{
+		{entity.ScoringFlat, ScoredAnswer{Question: question, IsCorrect: true}, 1},
+		{entity.ScoringFlat, ScoredAnswer{Question: question}, 0},
+		{entity.ScoringSpeedWeighted, ScoredAnswer{Question: question, IsCorrect: true, ResponseTimeMs: 2500, TimeLimitMs: 10000}, 17},
+		{entity.ScoringSpeedWeighted, ScoredAnswer{Question: question, IsCorrect: true, ResponseTimeMs: 12000, TimeLimitMs: 10000}, 10},
+		{entity.ScoringSpeedWeighted, ScoredAnswer{Question: unpriced, IsCorrect: true, ResponseTimeMs: 0, TimeLimitMs: 10000}, 2},
+		{entity.ScoringSpeedWeighted, ScoredAnswer{Question: question, ResponseTimeMs: 100, TimeLimitMs: 10000}, 0},
+		{entity.ScoringStreakMultiplier, ScoredAnswer{Question: question, IsCorrect: true, Streak: 3}, 30},
+		{entity.ScoringStreakMultiplier, ScoredAnswer{Question: question, IsCorrect: true, Streak: 9}, 50},
+		{entity.ScoringStreakMultiplier, ScoredAnswer{Question: unpriced, IsCorrect: true}, 1},
+		{entity.ScoringDifficultyWeighted, ScoredAnswer{Question: question, IsCorrect: true}, 30},
+		{entity.ScoringDifficultyWeighted, ScoredAnswer{Question: unpriced, IsCorrect: true}, 1},
+		{entity.ScoringDifficultyWeighted, ScoredAnswer{Question: question}, 0},
+	}
+	for _, c := range cases {
+		strategy, ok := LookupScoringStrategy(c.strategy)
+		if assert.True(t, ok, c.strategy) {
+			assert.Equal(t, c.strategy, strategy.Name())
+			assert.Equal(t, c.want, strategy.Points(c.answer), "%s %+v", c.strategy, c.answer)
+		}
+	}
+}
+
+func TestScoringStrategyFor(t *testing.T) {
+	assert.Equal(t, entity.ScoringFlat, ScoringStrategyFor(&entity.Quiz{ID: 1}).Name(), "по умолчанию — 1 очко за ответ")
+	assert.Equal(t, entity.ScoringSpeedWeighted, ScoringStrategyFor(&entity.Quiz{ID: 2, ScoringStrategy: entity.ScoringSpeedWeighted}).Name())
+	assert.Equal(t, entity.ScoringFlat, ScoringStrategyFor(&entity.Quiz{ID: 3, ScoringStrategy: "lucky_draw"}).Name())
+
+	_, ok := LookupScoringStrategy("lucky_draw")
+	assert.False(t, ok)
+}
+
+func TestStreak_ResetsOnMistake(t *testing.T) {
+	cache := contractCache{newMemoryAdmissionCache()}
+	quiz := &entity.Quiz{ID: 5, ScoringStrategy: entity.ScoringStreakMultiplier}
+
+	assert.Equal(t, 1, nextStreak(cache, quiz, 7))
+	updateStreak(cache, quiz, 7, true)
+	assert.Equal(t, 2, nextStreak(cache, quiz, 7))
+	updateStreak(cache, quiz, 7, true)
+	assert.Equal(t, 3, nextStreak(cache, quiz, 7))
+
+	// Серии игроков независимы
+	assert.Equal(t, 1, nextStreak(cache, quiz, 8))
+
+	updateStreak(cache, quiz, 7, false)
+	assert.Equal(t, 1, nextStreak(cache, quiz, 7))
+
+	// В остальных стратегиях серия не ведётся
+	flat := &entity.Quiz{ID: 6}
+	updateStreak(cache, flat, 7, true)
+	assert.Equal(t, 0, nextStreak(cache, flat, 7))
+	_, err := cache.Get(streakKey(flat.ID, 7))
+	assert.Error(t, err)
+}
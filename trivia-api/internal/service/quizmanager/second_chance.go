@@ -0,0 +1,42 @@
+package quizmanager
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+// SecondChanceRevivedKey — ключ Redis, отмечающий, что игрок уже использовал второй шанс в викторине
+func SecondChanceRevivedKey(quizID, userID uint) string {
+	return fmt.Sprintf("quiz:%d:revived:%d", quizID, userID)
+}
+
+// offerSecondChance предлагает выбывшему игроку второй шанс, если он включён в викторине
+// и ещё не использован. Вернуться можно до начала следующего вопроса.
+func offerSecondChance(deps *Dependencies, quiz *entity.Quiz, userID uint, questionNumber int) {
+	if quiz == nil || !quiz.SecondChanceEnabled() || deps.WSManager == nil {
+		return
+	}
+	used, err := deps.CacheRepo.Exists(SecondChanceRevivedKey(quiz.ID, userID))
+	if err != nil {
+		log.Printf("[QuizManager] WARNING: Не удалось проверить второй шанс пользователя #%d в викторине #%d: %v", userID, quiz.ID, err)
+		return
+	}
+	if used {
+		return
+	}
+
+	offer := map[string]interface{}{
+		"quiz_id":         quiz.ID,
+		"question_number": questionNumber,
+		"method":          quiz.SecondChanceMode,
+	}
+	if quiz.SecondChanceMode == entity.SecondChancePoints {
+		offer["cost"] = quiz.SecondChanceCost
+	}
+	if err := deps.WSManager.SendEventToUser(strconv.FormatUint(uint64(userID), 10), "quiz:second_chance_offer", offer); err != nil {
+		log.Printf("[QuizManager] Ошибка отправки предложения второго шанса пользователю #%d: %v", userID, err)
+	}
+}
@@ -0,0 +1,416 @@
+package quizmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+const (
+	// simulationTTL — сколько хранится прогон симуляции в Redis
+	simulationTTL = 24 * time.Hour
+
+	// MaxSimulationSpeed — максимальное ускорение воспроизведения относительно реального времени
+	MaxSimulationSpeed = 100
+
+	SimulationStatusRunning   = "running"
+	SimulationStatusCompleted = "completed"
+	SimulationStatusFailed    = "failed"
+)
+
+// SimulationRun — прогон симуляции завершённой викторины
+type SimulationRun struct {
+	ID         string            `json:"id"`
+	QuizID     uint              `json:"quiz_id"`
+	Status     string            `json:"status"`
+	Speed      float64           `json:"speed"`
+	Progress   int               `json:"progress"` // воспроизведено вопросов
+	Error      string            `json:"error,omitempty"`
+	StartedAt  time.Time         `json:"started_at"`
+	FinishedAt *time.Time        `json:"finished_at,omitempty"`
+	Report     *SimulationReport `json:"report,omitempty"`
+}
+
+// SimulationReport — итоги викторины, пересчитанные по записанным ответам.
+// Mismatches — число участников, у которых итог расходится с сохранённым в results.
+type SimulationReport struct {
+	TotalQuestions  int                   `json:"total_questions"`
+	Participants    int                   `json:"participants"`
+	WinnersCount    int                   `json:"winners_count"`
+	PrizePerWinner  int                   `json:"prize_per_winner"`
+	Mismatches      int                   `json:"mismatches"`
+	ScoringStrategy string                `json:"scoring_strategy"`
+	Questions       []SimulatedQuestion   `json:"questions"`
+	Results         []SimulatedUserResult `json:"results"`
+}
+
+// SimulatedQuestion — статистика одного воспроизведённого вопроса
+type SimulatedQuestion struct {
+	Number       int  `json:"number"`
+	QuestionID   uint `json:"question_id"`
+	TimeLimitSec int  `json:"time_limit_sec"`
+	Active       int  `json:"active"` // участников в игре к началу вопроса
+	Answered     int  `json:"answered"`
+	Passed       int  `json:"passed"`
+	Eliminated   int  `json:"eliminated"`
+}
+
+// SimulatedUserResult — итог участника в симуляции и сохранённый итог для сравнения
+type SimulatedUserResult struct {
+	UserID               uint   `json:"user_id"`
+	Username             string `json:"username,omitempty"`
+	Score                int    `json:"score"`
+	CorrectAnswers       int    `json:"correct_answers"`
+	Rank                 int    `json:"rank"`
+	IsEliminated         bool   `json:"is_eliminated"`
+	EliminatedOnQuestion int    `json:"eliminated_on_question,omitempty"`
+	EliminationReason    string `json:"elimination_reason,omitempty"`
+	IsWinner             bool   `json:"is_winner"`
+	PrizeFund            int    `json:"prize_fund"`
+
+	Recorded *RecordedUserResult `json:"recorded,omitempty"`
+	Mismatch bool                `json:"mismatch"`
+}
+
+// RecordedUserResult — итог участника, сохранённый при проведении викторины
+type RecordedUserResult struct {
+	Score          int  `json:"score"`
+	CorrectAnswers int  `json:"correct_answers"`
+	Rank           int  `json:"rank"`
+	IsEliminated   bool `json:"is_eliminated"`
+	IsWinner       bool `json:"is_winner"`
+	PrizeFund      int  `json:"prize_fund"`
+}
+
+// Simulator воспроизводит завершённые викторины: записанные ответы заново проходят проверку
+// времени, подсчёт очков и выбывание. Работает в песочнице — ничего не пишет в results
+// и user_answers и не отправляет событий WebSocket; прогоны хранятся в Redis.
+type Simulator struct {
+	config *Config
+	deps   *Dependencies
+}
+
+// NewSimulator создает симулятор викторин
+func NewSimulator(config *Config, deps *Dependencies) *Simulator {
+	return &Simulator{config: config, deps: deps}
+}
+
+// Start проверяет викторину, загружает её вопросы и ответы и запускает прогон в фоне.
+// speed — ускорение относительно реального времени (1..MaxSimulationSpeed), 0 — без пауз.
+func (s *Simulator) Start(ctx context.Context, quizID uint, speed float64) (*SimulationRun, error) {
+	if speed != 0 && (speed < 1 || speed > MaxSimulationSpeed) {
+		return nil, fmt.Errorf("%w: speed must be 0 or between 1 and %d", apperrors.ErrValidation, MaxSimulationSpeed)
+	}
+
+	quiz, err := s.deps.QuizRepo.GetByID(quizID)
+	if err != nil {
+		return nil, err
+	}
+	if !quiz.IsCompleted() {
+		return nil, fmt.Errorf("%w: only completed quizzes can be replayed", apperrors.ErrConflict)
+	}
+
+	questions, err := s.askedQuestions(quizID)
+	if err != nil {
+		return nil, err
+	}
+	if len(questions) == 0 {
+		return nil, fmt.Errorf("%w: quiz has no recorded questions", apperrors.ErrValidation)
+	}
+	answers, err := s.deps.ResultRepo.GetQuizUserAnswers(quizID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load quiz answers: %w", err)
+	}
+	recorded, err := s.deps.ResultRepo.GetAllQuizResults(quizID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load quiz results: %w", err)
+	}
+
+	run := &SimulationRun{
+		ID:        uuid.NewString(),
+		QuizID:    quizID,
+		Status:    SimulationStatusRunning,
+		Speed:     speed,
+		StartedAt: time.Now().UTC(),
+	}
+	if err := s.save(run); err != nil {
+		return nil, err
+	}
+
+	prizeFund := quiz.PrizeFund
+	if prizeFund <= 0 {
+		prizeFund = s.config.TotalPrizeFund
+	}
+	sim := newSimulation(quiz, questions, answers, recorded, prizeFund)
+	started := *run
+	go s.run(ctx, run, sim)
+	return &started, nil
+}
+
+// Get возвращает прогон симуляции викторины
+func (s *Simulator) Get(quizID uint, runID string) (*SimulationRun, error) {
+	var run SimulationRun
+	if err := s.deps.CacheRepo.GetJSON(simulationKey(quizID, runID), &run); err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil, fmt.Errorf("%w: simulation run not found", apperrors.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to load simulation run: %w", err)
+	}
+	return &run, nil
+}
+
+func (s *Simulator) run(ctx context.Context, run *SimulationRun, sim *simulation) {
+	for i := range sim.questions {
+		if run.Speed > 0 {
+			select {
+			case <-ctx.Done():
+				s.finish(run, nil, ctx.Err())
+				return
+			case <-time.After(s.questionDuration(sim.quiz, &sim.questions[i], run.Speed)):
+			}
+		}
+		sim.step(i)
+		run.Progress = i + 1
+		if run.Speed > 0 {
+			if err := s.save(run); err != nil {
+				log.Printf("[Simulator] %v", err)
+			}
+		}
+	}
+	s.finish(run, sim.report(), nil)
+}
+
+func (s *Simulator) finish(run *SimulationRun, report *SimulationReport, err error) {
+	now := time.Now().UTC()
+	run.FinishedAt = &now
+	run.Report = report
+	run.Status = SimulationStatusCompleted
+	if err != nil {
+		run.Status = SimulationStatusFailed
+		run.Error = err.Error()
+	}
+	if err := s.save(run); err != nil {
+		log.Printf("[Simulator] %v", err)
+		return
+	}
+	log.Printf("[Simulator] Симуляция %s викторины #%d: %s", run.ID, run.QuizID, run.Status)
+}
+
+func (s *Simulator) save(run *SimulationRun) error {
+	if err := s.deps.CacheRepo.SetJSON(simulationKey(run.QuizID, run.ID), run, simulationTTL); err != nil {
+		return fmt.Errorf("failed to store simulation run: %w", err)
+	}
+	return nil
+}
+
+// questionDuration — длительность вопроса в живой викторине, делённая на ускорение
+func (s *Simulator) questionDuration(quiz *entity.Quiz, question *entity.Question, speed float64) time.Duration {
+	timing := s.config.Timing().ForQuiz(quiz)
+	return time.Duration(float64(questionDuration(timing, question.TimeLimitSec, false)) / speed)
+}
+
+// askedQuestions возвращает заданные вопросы в порядке проведения. Для старых викторин
+// без истории — вопросы, привязанные к викторине.
+func (s *Simulator) askedQuestions(quizID uint) ([]entity.Question, error) {
+	history, err := s.deps.QuestionRepo.GetQuizQuestionHistory(quizID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load quiz question history: %w", err)
+	}
+	if len(history) == 0 {
+		return s.deps.QuestionRepo.GetByQuizID(quizID)
+	}
+
+	sort.Slice(history, func(i, j int) bool { return history[i].QuestionOrder < history[j].QuestionOrder })
+	questions := make([]entity.Question, 0, len(history))
+	for _, item := range history {
+		question, err := s.deps.QuestionRepo.GetByID(item.QuestionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load question #%d: %w", item.QuestionID, err)
+		}
+		questions = append(questions, *question)
+	}
+	return questions, nil
+}
+
+func simulationKey(quizID uint, runID string) string {
+	return fmt.Sprintf("quiz:%d:simulation:%s", quizID, runID)
+}
+
+// simulation — состояние одного прогона: участники проходят вопросы по порядку,
+// как в AnswerProcessor и QuestionManager
+type simulation struct {
+	quiz      *entity.Quiz
+	questions []entity.Question
+	answers   map[uint]map[uint]entity.UserAnswer // user_id → question_id → ответ
+	recorded  map[uint]entity.Result
+	prizeFund int
+	scoring   ScoringStrategy
+	streaks   map[uint]int // серии верных ответов подряд для streak_multiplier
+
+	players []*SimulatedUserResult
+	stats   []SimulatedQuestion
+}
+
+func newSimulation(quiz *entity.Quiz, questions []entity.Question, answers []entity.UserAnswer, recorded []entity.Result, prizeFund int) *simulation {
+	sim := &simulation{
+		quiz:      quiz,
+		questions: questions,
+		answers:   make(map[uint]map[uint]entity.UserAnswer),
+		recorded:  make(map[uint]entity.Result, len(recorded)),
+		prizeFund: prizeFund,
+		scoring:   ScoringStrategyFor(quiz),
+		streaks:   make(map[uint]int),
+	}
+
+	seen := make(map[uint]bool)
+	addPlayer := func(userID uint) {
+		if !seen[userID] {
+			seen[userID] = true
+			sim.players = append(sim.players, &SimulatedUserResult{UserID: userID})
+		}
+	}
+	for _, answer := range answers {
+		if sim.answers[answer.UserID] == nil {
+			sim.answers[answer.UserID] = make(map[uint]entity.UserAnswer)
+		}
+		sim.answers[answer.UserID][answer.QuestionID] = answer
+		addPlayer(answer.UserID)
+	}
+	for _, result := range recorded {
+		sim.recorded[result.UserID] = result
+		addPlayer(result.UserID)
+	}
+	sort.Slice(sim.players, func(i, j int) bool { return sim.players[i].UserID < sim.players[j].UserID })
+	return sim
+}
+
+// step воспроизводит вопрос i для всех участников, ещё не выбывших из игры
+func (sim *simulation) step(i int) {
+	question := &sim.questions[i]
+	stat := SimulatedQuestion{Number: i + 1, QuestionID: question.ID, TimeLimitSec: question.TimeLimitSec}
+	timeLimitMs := sim.quiz.Timing.QuestionTimeLimit(question.TimeLimitSec).Milliseconds()
+
+	for _, player := range sim.players {
+		if player.IsEliminated {
+			continue
+		}
+		stat.Active++
+
+		answer, ok := sim.answers[player.UserID][question.ID]
+		if !ok || answer.SelectedOption < 0 {
+			player.eliminate(i+1, "no_answer_timeout")
+			stat.Eliminated++
+			continue
+		}
+		stat.Answered++
+
+		timeLimitExceeded := answer.ResponseTimeMs > timeLimitMs
+		isCorrect := question.IsCorrect(answer.SelectedOption) && !timeLimitExceeded
+		scored := ScoredAnswer{Question: question, IsCorrect: isCorrect, ResponseTimeMs: answer.ResponseTimeMs, TimeLimitMs: timeLimitMs}
+		if isCorrect {
+			sim.streaks[player.UserID]++
+			scored.Streak = sim.streaks[player.UserID]
+		} else {
+			delete(sim.streaks, player.UserID)
+		}
+		player.Score += sim.scoring.Points(scored)
+		switch {
+		case timeLimitExceeded:
+			player.eliminate(i+1, "time_exceeded")
+			stat.Eliminated++
+		case !isCorrect:
+			player.eliminate(i+1, "incorrect_answer")
+			stat.Eliminated++
+		default:
+			player.CorrectAnswers++
+			stat.Passed++
+		}
+	}
+	sim.stats = append(sim.stats, stat)
+}
+
+// report считает ранги и победителей как ResultService и сравнивает итоги с сохранёнными
+func (sim *simulation) report() *SimulationReport {
+	totalQuestions := sim.quiz.QuestionCount
+	if totalQuestions <= 0 {
+		totalQuestions = len(sim.questions)
+	}
+
+	report := &SimulationReport{
+		TotalQuestions:  totalQuestions,
+		Participants:    len(sim.players),
+		ScoringStrategy: sim.scoring.Name(),
+		Questions:       sim.stats,
+		Results:         make([]SimulatedUserResult, 0, len(sim.players)),
+	}
+
+	var winners []*SimulatedUserResult
+	for _, player := range sim.players {
+		if !player.IsEliminated && player.CorrectAnswers == totalQuestions {
+			winners = append(winners, player)
+		}
+	}
+	report.WinnersCount = len(winners)
+	if len(winners) > 0 && sim.prizeFund > 0 {
+		report.PrizePerWinner = sim.prizeFund / len(winners)
+	}
+	for _, winner := range winners {
+		winner.IsWinner = true
+		winner.PrizeFund = report.PrizePerWinner
+	}
+
+	// RANK() OVER (ORDER BY score DESC, correct_answers DESC)
+	sort.SliceStable(sim.players, func(i, j int) bool {
+		a, b := sim.players[i], sim.players[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.CorrectAnswers > b.CorrectAnswers
+	})
+	for i, player := range sim.players {
+		player.Rank = i + 1
+		if i > 0 {
+			prev := sim.players[i-1]
+			if prev.Score == player.Score && prev.CorrectAnswers == player.CorrectAnswers {
+				player.Rank = prev.Rank
+			}
+		}
+
+		if result, ok := sim.recorded[player.UserID]; ok {
+			player.Username = result.Username
+			player.Recorded = &RecordedUserResult{
+				Score:          result.Score,
+				CorrectAnswers: result.CorrectAnswers,
+				Rank:           result.Rank,
+				IsEliminated:   result.IsEliminated,
+				IsWinner:       result.IsWinner,
+				PrizeFund:      result.PrizeFund,
+			}
+		}
+		player.Mismatch = player.Recorded == nil ||
+			player.Recorded.Score != player.Score ||
+			player.Recorded.CorrectAnswers != player.CorrectAnswers ||
+			player.Recorded.IsEliminated != player.IsEliminated ||
+			player.Recorded.IsWinner != player.IsWinner ||
+			player.Recorded.PrizeFund != player.PrizeFund
+		if player.Mismatch {
+			report.Mismatches++
+		}
+		report.Results = append(report.Results, *player)
+	}
+	return report
+}
+
+func (r *SimulatedUserResult) eliminate(questionNumber int, reason string) {
+	r.IsEliminated = true
+	r.EliminatedOnQuestion = questionNumber
+	r.EliminationReason = reason
+}
@@ -0,0 +1,73 @@
+package quizmanager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+func TestSimulation_ReplaysScoringAndElimination(t *testing.T) {
+	quiz := &entity.Quiz{ID: 1, QuestionCount: 2, Status: entity.QuizStatusCompleted}
+	questions := []entity.Question{
+		{ID: 10, CorrectOption: 1, TimeLimitSec: 10},
+		{ID: 20, CorrectOption: 2, TimeLimitSec: 10},
+	}
+	answers := []entity.UserAnswer{
+		// Победитель: оба ответа правильные и в срок
+		{UserID: 1, QuestionID: 10, SelectedOption: 1, ResponseTimeMs: 2000},
+		{UserID: 1, QuestionID: 20, SelectedOption: 2, ResponseTimeMs: 3000},
+		// Неправильный ответ на первый вопрос
+		{UserID: 2, QuestionID: 10, SelectedOption: 0, ResponseTimeMs: 1000},
+		// Правильный, но просроченный ответ на второй вопрос
+		{UserID: 3, QuestionID: 10, SelectedOption: 1, ResponseTimeMs: 4000},
+		{UserID: 3, QuestionID: 20, SelectedOption: 2, ResponseTimeMs: 12000},
+		// Не ответил на второй вопрос (запись таймаута)
+		{UserID: 4, QuestionID: 10, SelectedOption: 1, ResponseTimeMs: 5000},
+		{UserID: 4, QuestionID: 20, SelectedOption: -1},
+	}
+	recorded := []entity.Result{
+		{UserID: 1, Username: "winner", Score: 2, CorrectAnswers: 2, Rank: 1, IsWinner: true, PrizeFund: 1000},
+		// Сохранённый итог ошибочно засчитал просроченный ответ
+		{UserID: 3, Username: "late", Score: 2, CorrectAnswers: 2, Rank: 1, IsWinner: true, PrizeFund: 500},
+	}
+
+	sim := newSimulation(quiz, questions, answers, recorded, 1000)
+	for i := range questions {
+		sim.step(i)
+	}
+	report := sim.report()
+
+	assert.Equal(t, 4, report.Participants)
+	assert.Equal(t, 1, report.WinnersCount)
+	assert.Equal(t, 1000, report.PrizePerWinner)
+	assert.Equal(t, []SimulatedQuestion{
+		{Number: 1, QuestionID: 10, TimeLimitSec: 10, Active: 4, Answered: 4, Passed: 3, Eliminated: 1},
+		{Number: 2, QuestionID: 20, TimeLimitSec: 10, Active: 3, Answered: 2, Passed: 1, Eliminated: 2},
+	}, report.Questions)
+
+	byUser := make(map[uint]SimulatedUserResult)
+	for _, result := range report.Results {
+		byUser[result.UserID] = result
+	}
+	require.Len(t, byUser, 4)
+
+	assert.True(t, byUser[1].IsWinner)
+	assert.Equal(t, 1, byUser[1].Rank)
+	assert.False(t, byUser[1].Mismatch)
+
+	assert.Equal(t, "incorrect_answer", byUser[2].EliminationReason)
+	assert.Equal(t, 1, byUser[2].EliminatedOnQuestion)
+
+	assert.Equal(t, "time_exceeded", byUser[3].EliminationReason)
+	assert.Equal(t, 2, byUser[3].EliminatedOnQuestion)
+	assert.True(t, byUser[3].Mismatch)
+
+	assert.Equal(t, "no_answer_timeout", byUser[4].EliminationReason)
+	assert.Equal(t, byUser[3].Rank, byUser[4].Rank, "equal score and correct answers share a rank")
+
+	// Участники 2 и 4 отсутствуют в results, участник 3 расходится с сохранённым итогом
+	assert.Equal(t, 3, report.Mismatches)
+}
@@ -0,0 +1,29 @@
+package quizmanager
+
+import (
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+// Sponsorship выдаёт брендинг спонсора викторины для WS-события и учитывает его показ
+// зрителям вместе с рекламными паузами. Nil — у викторины нет спонсора.
+type Sponsorship interface {
+	SponsorImpression(quiz *entity.Quiz, placement string, viewers int) *entity.SponsorBranding
+}
+
+// SetSponsorship подключает брендинг спонсора в событии quiz:start
+func (s *Scheduler) SetSponsorship(sponsorship Sponsorship) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sponsorship = sponsorship
+}
+
+// startSponsor возвращает брендинг спонсора для события quiz:start и учитывает показ
+func (s *Scheduler) startSponsor(quiz *entity.Quiz) *entity.SponsorBranding {
+	s.mu.Lock()
+	sponsorship := s.sponsorship
+	s.mu.Unlock()
+	if sponsorship == nil || quiz.Sponsor.IsEmpty() {
+		return nil
+	}
+	return sponsorship.SponsorImpression(quiz, entity.AdPlacementSponsorStart, s.deps.WSManager.GetSubscriberCount(quiz.ID))
+}
@@ -0,0 +1,66 @@
+package quizmanager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestTiming_ForQuiz(t *testing.T) {
+	global := DefaultTiming()
+	multiplier := 1.5
+	quiz := &entity.Quiz{ID: 1, Timing: entity.QuizTiming{
+		AnswerRevealDelayMs: intPtr(3000),
+		TimeLimitMultiplier: &multiplier,
+		AdBreakDurationSec:  intPtr(20),
+	}}
+
+	timing := global.ForQuiz(quiz)
+	assert.Equal(t, global.QuestionDelayMs, timing.QuestionDelayMs, "незаданное поле берётся из конфигурации")
+	assert.Equal(t, 3000, timing.AnswerRevealDelayMs)
+	assert.Equal(t, 15*time.Second, timing.QuestionTimeLimit(10))
+	assert.Equal(t, 10*time.Second, global.QuestionTimeLimit(10), "глобальные тайминги без множителя")
+
+	// Длительность паузы викторины заменяет длительность ролика, но не переопределение слота
+	asset := &entity.AdAsset{MediaType: "image", DurationSec: 5}
+	assert.Equal(t, 20*time.Second, timing.AdBreakPlayback(&entity.QuizAdSlot{AdAsset: asset}))
+	assert.Equal(t, 8*time.Second, timing.AdBreakPlayback(&entity.QuizAdSlot{AdAsset: asset, DurationSec: intPtr(8)}))
+	assert.Equal(t, asset.PlaybackDuration(), global.AdBreakPlayback(&entity.QuizAdSlot{AdAsset: asset}))
+
+	// Лимит вопроса, который не идёт сейчас, тоже масштабируется
+	state := NewActiveQuizState(quiz)
+	assert.Equal(t, int64(15000), state.QuestionTimeLimitMs(&entity.Question{ID: 3, TimeLimitSec: 10}, time.Now().UnixMilli()))
+}
+
+func TestScheduleValidator_QuizTimingOverrides(t *testing.T) {
+	mockQuizRepo := new(MockQuizRepoForScheduler)
+	mockQuestionRepo := new(MockQuestionRepoForScheduler)
+	config := DefaultConfig()
+
+	scheduledTime := time.Now().Add(time.Hour)
+	quiz := &entity.Quiz{ID: 1, Status: entity.QuizStatusScheduled, QuestionSourceMode: entity.QuizQuestionSourceHybrid}
+	mockQuizRepo.On("GetWithQuestions", uint(1)).Return(quiz, nil)
+	mockQuizRepo.On("GetScheduled").Return([]entity.Quiz{}, nil)
+	mockQuizRepo.On("GetActive").Return(nil, apperrors.ErrNotFound)
+	mockQuestionRepo.On("GetPoolStats").Return(int64(100), int64(100), map[int]int64{1: 20, 2: 20, 3: 20, 4: 20, 5: 20}, nil)
+	validator := NewScheduleValidator(config, &Dependencies{QuizRepo: mockQuizRepo, QuestionRepo: mockQuestionRepo})
+
+	base, err := validator.Validate(1, scheduledTime)
+	require.NoError(t, err)
+
+	double := 2.0
+	slower, err := validator.ValidateWithTiming(1, scheduledTime, &entity.QuizTiming{TimeLimitMultiplier: &double})
+	require.NoError(t, err)
+
+	// Каждый вопрос идёт вдвое дольше: оценка окончания сдвигается на сумму лимитов
+	shift := time.Duration(config.MaxQuestionsPerQuiz*defaultQuestionTimeLimitSec) * time.Second
+	assert.Equal(t, base.EstimatedEnd.Add(shift), slower.EstimatedEnd)
+	assert.Nil(t, quiz.Timing.TimeLimitMultiplier, "проверка не меняет загруженную викторину")
+}
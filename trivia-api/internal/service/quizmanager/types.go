@@ -16,16 +16,79 @@ const (
 	DefaultTotalPrizeFund   = 1000000 // Пример призового фонда
 )
 
+// Timing содержит тайминги проведения викторины. Их можно менять на лету (hot reload
+// конфигурации): компоненты читают актуальные значения через Config.Timing().
+type Timing struct {
+	AnnouncementMinutes  int // За сколько минут отправлять анонс викторины
+	ReminderMinutes      int // За сколько минут отправлять push-напоминание
+	WaitingRoomMinutes   int // За сколько минут открывать зал ожидания
+	CountdownSeconds     int // Продолжительность обратного отсчета в секундах
+	QuestionDelayMs      int // Задержка перед отправкой вопроса
+	AnswerRevealDelayMs  int // Задержка перед отправкой правильного ответа
+	InterQuestionDelayMs int // Задержка между вопросами
+	AdVideoBufferMs      int // Запас времени на буферизацию видео-рекламы на клиенте
+
+	// Задаются только викториной (см. ForQuiz); в глобальной конфигурации нулевые
+	TimeLimitMultiplier float64 // Множитель лимита времени вопросов (0 — без изменения)
+	AdBreakDurationSec  int     // Длительность рекламной паузы для слотов без своей длительности (0 — по ролику)
+}
+
+// DefaultTiming возвращает тайминги по умолчанию
+func DefaultTiming() Timing {
+	return Timing{
+		AnnouncementMinutes:  30,
+		ReminderMinutes:      10,
+		WaitingRoomMinutes:   5,
+		CountdownSeconds:     60,
+		QuestionDelayMs:      500,
+		AnswerRevealDelayMs:  200,
+		InterQuestionDelayMs: 500,
+		AdVideoBufferMs:      1500,
+	}
+}
+
+// ForQuiz возвращает тайминги с переопределениями викторины (entity.QuizTiming)
+func (t Timing) ForQuiz(quiz *entity.Quiz) Timing {
+	if quiz == nil {
+		return t
+	}
+	overrides := quiz.Timing
+	if overrides.QuestionDelayMs != nil {
+		t.QuestionDelayMs = *overrides.QuestionDelayMs
+	}
+	if overrides.AnswerRevealDelayMs != nil {
+		t.AnswerRevealDelayMs = *overrides.AnswerRevealDelayMs
+	}
+	if overrides.TimeLimitMultiplier != nil {
+		t.TimeLimitMultiplier = *overrides.TimeLimitMultiplier
+	}
+	if overrides.AdBreakDurationSec != nil {
+		t.AdBreakDurationSec = *overrides.AdBreakDurationSec
+	}
+	return t
+}
+
+// QuestionTimeLimit возвращает лимит времени вопроса с учётом множителя
+func (t Timing) QuestionTimeLimit(timeLimitSec int) time.Duration {
+	return entity.ScaleTimeLimit(timeLimitSec, t.TimeLimitMultiplier)
+}
+
+// AdBreakPlayback возвращает длительность показа рекламы слота: переопределение слота,
+// затем длительность паузы викторины, затем длительность ролика
+func (t Timing) AdBreakPlayback(slot *entity.QuizAdSlot) time.Duration {
+	if (slot.DurationSec == nil || *slot.DurationSec <= 0) && t.AdBreakDurationSec > 0 && slot.AdAsset != nil {
+		return time.Duration(t.AdBreakDurationSec) * time.Second
+	}
+	return slot.PlaybackDuration()
+}
+
 // Config содержит настройки для всех компонентов QuizManager
 type Config struct {
-	// Таймауты и интервалы
-	AnnouncementMinutes  int           // За сколько минут отправлять анонс викторины
-	WaitingRoomMinutes   int           // За сколько минут открывать зал ожидания
-	CountdownSeconds     int           // Продолжительность обратного отсчета в секундах
-	QuestionDelayMs      int           // Задержка перед отправкой вопроса
-	AnswerRevealDelayMs  int           // Задержка перед отправкой правильного ответа
-	InterQuestionDelayMs int           // Задержка между вопросами
-	RetryInterval        time.Duration // Интервал между повторными попытками отправки
+	// Тайминги викторины (изменяемые на лету, доступ через Timing/SetTiming)
+	timing   Timing
+	timingMu sync.RWMutex
+
+	RetryInterval time.Duration // Интервал между повторными попытками отправки
 
 	// Настройки автозаполнения вопросов
 	AutoFillThreshold   int // За сколько минут до начала выполнять автозаполнение
@@ -35,9 +98,28 @@ type Config struct {
 	MaxResponseTimeMs int64 // Максимальное время ответа в мс
 	EliminationTimeMs int64 // Время ответа, после которого пользователь выбывает
 
+	// Максимальная компенсация сетевой задержки (RTT соединения) при проверке времени ответа, мс
+	MaxLatencyCompensationMs int64
+
+	// Максимальная отсрочка персонального дедлайна игроку, которому вопрос пришёл позже отправки, мс
+	MaxDeliveryAllowanceMs int64
+
+	// Вопрос, который дошёл меньше чем до этой доли подписчиков (переполнены очереди отправки),
+	// снимается с эфира; проверка выполняется, только если подписчиков не меньше MinDeliverySubscribers
+	MinQuestionDeliveryRatio float64
+	MinDeliverySubscribers   int
+	// Сколько вопросов викторины можно снять из-за сбоя доставки; дальше вопросы идут как обычно
+	MaxDeliveryVoidsPerQuiz int
+
 	// Максимальное количество попыток отправки сообщений
 	MaxRetries int
 
+	// Сколько ждать переподключения игрока, прежде чем освободить его место в зале ожидания
+	AdmissionLeaveGrace time.Duration
+
+	// Сколько раундов финала на выбывание играется, прежде чем оставшиеся делят победу
+	SuddenDeathMaxRounds int
+
 	// Настройки призового фонда
 	TotalPrizeFund int // Общий призовой фонд
 }
@@ -45,22 +127,39 @@ type Config struct {
 // DefaultConfig возвращает конфигурацию по умолчанию
 func DefaultConfig() *Config {
 	return &Config{
-		AnnouncementMinutes:  30,
-		WaitingRoomMinutes:   5,
-		CountdownSeconds:     60,
-		QuestionDelayMs:      500,
-		AnswerRevealDelayMs:  200,
-		InterQuestionDelayMs: 500,
-		RetryInterval:        500 * time.Millisecond,
-		AutoFillThreshold:    2,
-		MaxQuestionsPerQuiz:  DefaultMaxQuizQuestions, // Используем константу
-		MaxResponseTimeMs:    30000,                   // 30 секунд
-		EliminationTimeMs:    10000,                   // 10 секунд
-		MaxRetries:           3,
-		TotalPrizeFund:       DefaultTotalPrizeFund, // Используем константу
+		timing:                   DefaultTiming(),
+		RetryInterval:            500 * time.Millisecond,
+		AutoFillThreshold:        2,
+		MaxQuestionsPerQuiz:      DefaultMaxQuizQuestions, // Используем константу
+		MaxResponseTimeMs:        30000,                   // 30 секунд
+		EliminationTimeMs:        10000,                   // 10 секунд
+		MaxLatencyCompensationMs: 1000,
+		MaxDeliveryAllowanceMs:   1500,
+		MinQuestionDeliveryRatio: 0.9,
+		MinDeliverySubscribers:   10,
+		MaxDeliveryVoidsPerQuiz:  3,
+		MaxRetries:               3,
+		AdmissionLeaveGrace:      15 * time.Second,
+		SuddenDeathMaxRounds:     5,
+		TotalPrizeFund:           DefaultTotalPrizeFund, // Используем константу
 	}
 }
 
+// Timing возвращает текущие тайминги викторины
+func (c *Config) Timing() Timing {
+	c.timingMu.RLock()
+	defer c.timingMu.RUnlock()
+	return c.timing
+}
+
+// SetTiming заменяет тайминги викторины. Новые значения применяются со следующего этапа
+// расписания или вопроса; уже начатое ожидание не пересчитывается.
+func (c *Config) SetTiming(t Timing) {
+	c.timingMu.Lock()
+	defer c.timingMu.Unlock()
+	c.timing = t
+}
+
 // ResultService определяет интерфейс для методов сервиса результатов,
 // необходимых QuizManager.
 type ResultService interface {
@@ -68,6 +167,69 @@ type ResultService interface {
 	// Добавьте другие методы ResultService, если они вызываются из QuizManager
 }
 
+// AnswerWriter принимает ответы пользователей на отложенную пакетную запись в БД
+type AnswerWriter interface {
+	Enqueue(ctx context.Context, answer *entity.UserAnswer) error
+}
+
+// AnswerObserver получает каждый сохранённый ответ (включая пропуски по таймауту) с номером вопроса
+// в викторине. Вызывается синхронно на пути ответа, поэтому не должен блокироваться.
+type AnswerObserver interface {
+	AnswerRecorded(answer *entity.UserAnswer, questionNumber int)
+}
+
+// AnswerVoider аннулирует ответы на вопрос, снятый администратором с эфира
+type AnswerVoider interface {
+	VoidQuestionAnswers(ctx context.Context, quizID, questionID uint) (int64, error)
+}
+
+// SchedulingPause приостанавливает запуск викторин (режим обслуживания)
+type SchedulingPause interface {
+	Active() bool
+}
+
+// QuizNotifier рассылает внешние уведомления (push) о предстоящих викторинах
+type QuizNotifier interface {
+	NotifyQuizStartingSoon(quiz *entity.Quiz)
+}
+
+// QuizNotifiers рассылает напоминание через несколько отправителей по очереди
+type QuizNotifiers []QuizNotifier
+
+// NotifyQuizStartingSoon передаёт напоминание каждому отправителю
+func (n QuizNotifiers) NotifyQuizStartingSoon(quiz *entity.Quiz) {
+	for _, notifier := range n {
+		notifier.NotifyQuizStartingSoon(quiz)
+	}
+}
+
+// FriendActivityNotifier сообщает подписчикам игрока, что он вошёл в зал ожидания викторины
+type FriendActivityNotifier interface {
+	NotifyFriendJoined(quizID, userID uint)
+}
+
+// ParticipantForecast оценивает ожидаемую аудиторию викторины (например, по предварительной записи)
+type ParticipantForecast interface {
+	ExpectedParticipants(quizID uint) (int, error)
+}
+
+// QuestionLocalizer возвращает вопрос на всех языках, для которых есть перевод (по коду языка)
+type QuestionLocalizer interface {
+	QuestionTranslations(question *entity.Question) (map[string]entity.LocalizedQuestion, error)
+}
+
+// QuestionMedia — временные ссылки на медиа вопроса для клиентов
+type QuestionMedia struct {
+	ImageURL  string `json:"image_url,omitempty"`
+	AudioURL  string `json:"audio_url,omitempty"`
+	ExpiresAt int64  `json:"expires_at"` // Unix ms, до которого действуют ссылки
+}
+
+// QuestionMediaSigner выдаёт ссылки на медиа вопроса; nil — у вопроса нет медиа
+type QuestionMediaSigner interface {
+	QuestionMedia(ctx context.Context, question *entity.Question) (*QuestionMedia, error)
+}
+
 // Dependencies содержит зависимости для QuizManager
 type Dependencies struct {
 	QuizRepo       repository.QuizRepository
@@ -85,7 +247,9 @@ type ActiveQuizState struct {
 	Quiz                       *entity.Quiz
 	CurrentQuestion            *entity.Question
 	CurrentQuestionNumber      int
-	CurrentQuestionStartTimeMs int64 // Добавляем время старта текущего вопроса (Unix ms)
+	CurrentQuestionStartTimeMs int64          // Добавляем время старта текущего вопроса (Unix ms)
+	questionClock              *QuestionClock // Таймер текущего вопроса, которым управляет администратор
+	suddenDeathRound           int            // Раунд финала на выбывание (0 — основная часть викторины)
 	Mu                         sync.RWMutex
 }
 
@@ -102,6 +266,7 @@ func (s *ActiveQuizState) SetCurrentQuestion(question *entity.Question, number i
 	defer s.Mu.Unlock()
 	s.CurrentQuestion = question
 	s.CurrentQuestionNumber = number
+	s.questionClock = nil
 }
 
 // GetCurrentQuestion возвращает текущий вопрос
@@ -132,4 +297,49 @@ func (s *ActiveQuizState) ClearCurrentQuestion() {
 	s.CurrentQuestion = nil
 	s.CurrentQuestionNumber = 0
 	s.CurrentQuestionStartTimeMs = 0
+	s.questionClock = nil
+}
+
+// SetSuddenDeathRound отмечает начало раунда финала на выбывание
+func (s *ActiveQuizState) SetSuddenDeathRound(round int) {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	s.suddenDeathRound = round
+}
+
+// SuddenDeathRound возвращает текущий раунд финала на выбывание (0 — основная часть викторины)
+func (s *ActiveQuizState) SuddenDeathRound() int {
+	s.Mu.RLock()
+	defer s.Mu.RUnlock()
+	return s.suddenDeathRound
+}
+
+// SetQuestionClock устанавливает таймер текущего вопроса
+func (s *ActiveQuizState) SetQuestionClock(clock *QuestionClock) {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	s.questionClock = clock
+}
+
+// QuestionClock возвращает таймер текущего вопроса (nil — вопрос ещё не запущен)
+func (s *ActiveQuizState) QuestionClock() *QuestionClock {
+	s.Mu.RLock()
+	defer s.Mu.RUnlock()
+	return s.questionClock
+}
+
+// QuestionTimeLimitMs возвращает фактический лимит времени на вопрос, отсчитанный от startTimeMs:
+// с учётом пауз и продлений администратора, если вопрос текущий, иначе исходный лимит вопроса
+// с множителем викторины
+func (s *ActiveQuizState) QuestionTimeLimitMs(question *entity.Question, startTimeMs int64) int64 {
+	s.Mu.RLock()
+	clock, current := s.questionClock, s.CurrentQuestion
+	s.Mu.RUnlock()
+	if clock == nil || current == nil || current.ID != question.ID {
+		if s.Quiz == nil {
+			return int64(question.TimeLimitSec * 1000)
+		}
+		return s.Quiz.Timing.QuestionTimeLimit(question.TimeLimitSec).Milliseconds()
+	}
+	return clock.Deadline().UnixMilli() - startTimeMs
 }
@@ -0,0 +1,229 @@
+package quizmanager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	"github.com/yourusername/trivia-api/internal/websocket"
+	"github.com/yourusername/trivia-api/internal/websocket/contract"
+)
+
+// contractHub записывает сообщения, которые менеджеры викторины отправляют через websocket.Manager
+type contractHub struct {
+	mu       sync.Mutex
+	messages [][]byte
+}
+
+func (h *contractHub) record(message []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.messages = append(h.messages, message)
+}
+
+func (h *contractHub) recordJSON(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	h.record(data)
+	return nil
+}
+
+func (h *contractHub) BroadcastJSON(v interface{}) error            { return h.recordJSON(v) }
+func (h *contractHub) SendJSONToUser(_ string, v interface{}) error { return h.recordJSON(v) }
+func (h *contractHub) SendToUser(_ string, message []byte) bool     { h.record(message); return true }
+func (h *contractHub) BroadcastToQuiz(_ uint, message []byte)       { h.record(message) }
+func (h *contractHub) GetMetrics() map[string]interface{}           { return nil }
+func (h *contractHub) ClientCount() int                             { return 0 }
+func (h *contractHub) GetActiveSubscribers(_ uint) ([]uint, error)  { return nil, nil }
+func (h *contractHub) GetSubscriberCount(_ uint) int                { return 3 }
+
+// contractCache дополняет кеш зала ожидания операциями, которые использует обработка ответов
+type contractCache struct {
+	*memoryAdmissionCache
+}
+
+func (c contractCache) WithContext(context.Context) repository.CacheRepository { return c }
+
+func (c contractCache) Get(key string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	value, ok := c.values[key]
+	if !ok {
+		return "", fmt.Errorf("key %s not found", key)
+	}
+	return value, nil
+}
+
+func (c contractCache) SetNX(key string, value interface{}, _ time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.values[key]; ok {
+		return false, nil
+	}
+	c.values[key] = fmt.Sprint(value)
+	return true, nil
+}
+
+func (c contractCache) Increment(key string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n, _ := strconv.ParseInt(c.values[key], 10, 64)
+	n++
+	c.values[key] = strconv.FormatInt(n, 10)
+	return n, nil
+}
+
+type contractAnswerWriter struct{}
+
+func (contractAnswerWriter) Enqueue(context.Context, *entity.UserAnswer) error { return nil }
+
+type contractMedia struct{}
+
+func (contractMedia) QuestionMedia(_ context.Context, question *entity.Question) (*QuestionMedia, error) {
+	return &QuestionMedia{ImageURL: fmt.Sprintf("https://cdn.example.com/q/%d.png", question.ID), ExpiresAt: 1760630400000}, nil
+}
+
+// Все события, которые отправляют планировщик, менеджер вопросов, обработчик ответов
+// и зал ожидания, должны соответствовать каталогу протокола
+func TestWSContract_QuizManagerEvents(t *testing.T) {
+	catalog, err := contract.Load()
+	require.NoError(t, err)
+
+	hub := &contractHub{}
+	cache := contractCache{newMemoryAdmissionCache()}
+	quiz := &entity.Quiz{
+		ID:               11,
+		Title:            "Вечерняя викторина",
+		Description:      "Десять вопросов",
+		ScheduledTime:    time.Now().Add(10 * time.Minute),
+		Status:           entity.QuizStatusScheduled,
+		QuestionCount:    10,
+		MaxPlayers:       1,
+		GameMode:         entity.GameModeLives,
+		Lives:            2,
+		SecondChanceMode: entity.SecondChancePoints,
+		SecondChanceCost: 50,
+	}
+	question := &entity.Question{
+		ID:            21,
+		Text:          "Столица Казахстана?",
+		TextKK:        "Қазақстанның астанасы?",
+		Options:       entity.StringArray{"Алматы", "Астана", "Шымкент"},
+		OptionsKK:     entity.StringArray{"Алматы", "Астана", "Шымкент"},
+		CorrectOption: 1,
+		TimeLimitSec:  10,
+		Difficulty:    2,
+	}
+	quizRepo := new(MockQuizRepoForScheduler)
+	quizRepo.On("GetByID", quiz.ID).Return(quiz, nil)
+	quizRepo.On("UpdateStatus", quiz.ID, entity.QuizStatusCancelled).Return(nil)
+	quizRepo.On("GetWithQuestions", quiz.ID).Return(&entity.Quiz{ID: quiz.ID, Questions: []entity.Question{*question}}, nil)
+
+	config := DefaultConfig()
+	deps := &Dependencies{
+		QuizRepo:  quizRepo,
+		CacheRepo: cache,
+		WSManager: websocket.NewManager(hub),
+		Config:    config,
+	}
+	ctx := context.Background()
+
+	// Планировщик
+	scheduler := NewScheduler(config, deps)
+	scheduler.SetMedia(contractMedia{})
+	scheduler.triggerAnnouncement(ctx, quiz)
+	scheduler.triggerWaitingRoom(ctx, quiz)
+	scheduler.triggerMediaPreload(ctx, quiz)
+
+	// Зал ожидания: второй игрок сверх вместимости встаёт в очередь
+	admission := NewAdmissionController(config, deps)
+	answers := NewAnswerProcessor(config, deps)
+	answers.SetAdmission(admission)
+	answers.SetAnswerWriter(contractAnswerWriter{})
+	require.NoError(t, answers.HandleReadyEvent(ctx, 1, quiz.ID))
+	require.NoError(t, answers.HandleReadyEvent(ctx, 2, quiz.ID))
+	admission.Release(quiz.ID, 1)
+	admitted, err := admission.AdmitNext(quiz.ID)
+	require.NoError(t, err)
+	require.Equal(t, 1, admitted)
+
+	// Вопрос и управление им в эфире
+	qm := NewQuestionManager(config, deps)
+	qm.SetMedia(contractMedia{})
+	state := NewActiveQuizState(quiz)
+	state.SetCurrentQuestion(question, 1)
+	clock := NewQuestionClock(10 * time.Second)
+	state.SetQuestionClock(clock)
+	require.NoError(t, catalogEvent(t, catalog, "quiz:question").ValidateData(qm.questionEvent(ctx, quiz.ID, question, 1, 10, 10*time.Second, clock, 0)))
+	require.NoError(t, catalogEvent(t, catalog, "quiz:question").ValidateData(qm.questionEvent(ctx, quiz.ID, question, 11, 10, 10*time.Second, clock, 1)))
+	_, err = qm.PauseQuestion(ctx, state)
+	require.NoError(t, err)
+	_, err = qm.ResumeQuestion(ctx, state)
+	require.NoError(t, err)
+	_, err = qm.ExtendQuestion(ctx, state, 5)
+	require.NoError(t, err)
+	require.NoError(t, qm.Announce(ctx, state, "Следующий вопрос — последний"))
+
+	var timers sync.WaitGroup
+	timers.Add(1)
+	timerCtx, stopTimer := context.WithTimeout(ctx, 1500*time.Millisecond)
+	qm.runQuestionTimer(timerCtx, quiz, question, 1, 10, clock, &timers)
+	stopTimer()
+
+	// Ответы: верный, ошибка со списанием жизни, выбывание со вторым шансом
+	startMs := time.Now().UnixMilli()
+	require.NoError(t, cache.SAdd(fmt.Sprintf("quiz:%d:participants", quiz.ID), 3))
+	answers.SetDeliveryTracker(NewDeliveryTracker(config, deps))
+	require.NoError(t, answers.AcknowledgeQuestion(ctx, 1, question, state, startMs))
+	require.NoError(t, answers.ProcessAnswer(ctx, 1, question, 1, startMs, state, startMs))
+	require.NoError(t, answers.ProcessAnswer(ctx, 3, question, 0, startMs, state, startMs))
+	next := &entity.Question{ID: 22, Text: "2+2?", Options: entity.StringArray{"3", "4"}, CorrectOption: 1, TimeLimitSec: 10}
+	require.NoError(t, answers.ProcessAnswer(ctx, 3, next, 0, startMs, state, startMs))
+	assert.Error(t, answers.ProcessAnswer(ctx, 3, next, 1, startMs, state, startMs))
+
+	qm.sendAdaptiveQuestionStats(ctx, quiz.ID, 1, 2, 2)
+	qm.sendEliminationNotification(4, quiz.ID, "no_answer_timeout")
+	qm.sendEliminationNotification(5, quiz.ID, entity.AnswerReasonSuddenDeath)
+	qm.sendLifeLost(6, quiz.ID, question.ID, 1)
+	qm.sendSuddenDeathResult(ctx, quiz.ID, 11, 2, 1, false)
+
+	// Отмена администратором
+	require.NoError(t, scheduler.CancelQuiz(quiz.ID))
+
+	seen := make(map[string]bool)
+	for _, message := range hub.messages {
+		assert.NoError(t, catalog.Validate(message), string(message))
+		var envelope struct {
+			Type string `json:"type"`
+		}
+		require.NoError(t, json.Unmarshal(message, &envelope))
+		seen[envelope.Type] = true
+	}
+	for _, name := range []string{
+		"quiz:announcement", "quiz:waiting_room", "quiz:media_preload", "quiz:user_ready",
+		"quiz:admission_queue", "quiz:admitted", "quiz:timer_paused", "quiz:timer_resumed",
+		"quiz:time_extended", "quiz:timer", "quiz:answer_result", "quiz:elimination",
+		"quiz:second_chance_offer", "adaptive:question_stats", "quiz:life_lost",
+		"quiz:sudden_death_result", "quiz:question_deadline", "quiz:cancelled",
+	} {
+		assert.True(t, seen[name], "%s was not sent", name)
+	}
+}
+
+func catalogEvent(t *testing.T, catalog *contract.Catalog, name string) *contract.Event {
+	t.Helper()
+	event, ok := catalog.Event(name)
+	require.True(t, ok, "%s is not in the catalog", name)
+	return event
+}
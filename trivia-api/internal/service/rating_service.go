@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	"github.com/yourusername/trivia-api/internal/handler/dto"
+	"github.com/yourusername/trivia-api/internal/pkg/httpcache"
+	"github.com/yourusername/trivia-api/internal/pkg/rating"
+)
+
+// RatingConfig содержит настройки рейтинга мастерства
+type RatingConfig struct {
+	Algorithm  string        // имя алгоритма (rating.New)
+	Params     rating.Config // параметры алгоритма
+	MinPlayers int           // викторины с меньшим числом участников не меняют рейтинг
+}
+
+// RatingService ведёт рейтинг мастерства игроков: после завершения викторины место каждого
+// участника сравнивается с ожидаемым при силе соперников, и рейтинг сдвигается на разницу.
+// Пересчёт идёт по событию quiz.completed и идемпотентен: учтённая викторина не пересчитывается.
+type RatingService struct {
+	repo       repository.RatingRepository
+	resultRepo repository.ResultRepository
+	algorithm  rating.Algorithm
+	cfg        RatingConfig
+	httpCache  httpcache.Invalidator
+}
+
+// NewRatingService создает сервис рейтинга; неизвестный алгоритм — ошибка конфигурации
+func NewRatingService(repo repository.RatingRepository, resultRepo repository.ResultRepository, cfg RatingConfig) (*RatingService, error) {
+	algorithm, err := rating.New(cfg.Algorithm, cfg.Params)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.MinPlayers < 2 {
+		cfg.MinPlayers = 2
+	}
+	return &RatingService{repo: repo, resultRepo: resultRepo, algorithm: algorithm, cfg: cfg}, nil
+}
+
+// SetHTTPCache подключает сброс кэша лидерборда после пересчёта рейтинга
+func (s *RatingService) SetHTTPCache(invalidator httpcache.Invalidator) {
+	s.httpCache = invalidator
+}
+
+// SubscribeTo подписывает пересчёт рейтинга на завершение викторин
+func (s *RatingService) SubscribeTo(bus *EventBus) {
+	bus.Subscribe("rating", s.handleQuizCompleted, entity.EventQuizCompleted)
+}
+
+func (s *RatingService) handleQuizCompleted(ctx context.Context, event *entity.OutboxEvent) error {
+	var payload entity.QuizCompletedPayload
+	if err := event.Decode(&payload); err != nil {
+		return err
+	}
+	_, err := s.RateQuiz(payload.QuizID)
+	return err
+}
+
+// RateQuiz пересчитывает рейтинги участников викторины по их местам. Возвращает false, если
+// викторина уже учтена или в ней меньше MinPlayers участников.
+func (s *RatingService) RateQuiz(quizID uint) (bool, error) {
+	results, err := s.resultRepo.GetAllQuizResults(quizID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get quiz results: %w", err)
+	}
+	if len(results) < s.cfg.MinPlayers {
+		return false, nil
+	}
+
+	userIDs := make([]uint, len(results))
+	for i, result := range results {
+		userIDs[i] = result.UserID
+	}
+	applied, err := s.repo.ApplyQuiz(quizID, userIDs, func(current map[uint]entity.UserRating) ([]entity.UserRating, []entity.RatingHistory) {
+		return s.rate(quizID, results, current)
+	})
+	if err != nil {
+		return false, err
+	}
+	if applied {
+		log.Printf("[RatingService] Рейтинг пересчитан по викторине %d: %d участников", quizID, len(results))
+		if s.httpCache != nil {
+			s.httpCache.Invalidate(httpcache.NamespaceLeaderboard)
+		}
+	}
+	return applied, nil
+}
+
+// rate вычисляет новые рейтинги участников и записи истории
+func (s *RatingService) rate(quizID uint, results []entity.Result, current map[uint]entity.UserRating) ([]entity.UserRating, []entity.RatingHistory) {
+	players := make([]rating.Player, len(results))
+	for i, result := range results {
+		players[i] = rating.Player{
+			UserID: result.UserID,
+			Rating: s.effectiveRating(current[result.UserID]),
+			Games:  current[result.UserID].Games,
+			Rank:   result.Rank,
+		}
+	}
+	updated := s.algorithm.Update(players)
+
+	ratings := make([]entity.UserRating, len(players))
+	history := make([]entity.RatingHistory, len(players))
+	for i, player := range players {
+		previous := current[player.UserID]
+		ratings[i] = entity.UserRating{
+			UserID:     player.UserID,
+			Rating:     updated[i],
+			Games:      previous.Games + 1,
+			PeakRating: max(s.effectivePeak(previous), updated[i]),
+		}
+		history[i] = entity.RatingHistory{
+			UserID:       player.UserID,
+			QuizID:       quizID,
+			Algorithm:    s.algorithm.Name(),
+			RatingBefore: player.Rating,
+			RatingAfter:  updated[i],
+			Delta:        updated[i] - player.Rating,
+			Rank:         player.Rank,
+			Players:      len(players),
+		}
+	}
+	return ratings, history
+}
+
+// effectiveRating — рейтинг игрока; до первой викторины — начальный
+func (s *RatingService) effectiveRating(r entity.UserRating) int {
+	if r.Games == 0 {
+		return s.algorithm.Initial()
+	}
+	return r.Rating
+}
+
+func (s *RatingService) effectivePeak(r entity.UserRating) int {
+	if r.Games == 0 {
+		return s.algorithm.Initial()
+	}
+	return r.PeakRating
+}
+
+// GetUserRating возвращает рейтинг пользователя; до первой викторины — начальный
+func (s *RatingService) GetUserRating(userID uint) (*entity.UserRating, error) {
+	r, err := s.repo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	r.Rating = s.effectiveRating(*r)
+	r.PeakRating = s.effectivePeak(*r)
+	r.Provisional = r.Games < s.cfg.Params.ProvisionalGames
+	return r, nil
+}
+
+// GetLeaderboard возвращает страницу лидерборда по рейтингу
+func (s *RatingService) GetLeaderboard(page, pageSize int) (*dto.PaginatedRatingLeaderboardResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	} else if pageSize > 100 {
+		pageSize = 100
+	}
+	offset := (page - 1) * pageSize
+
+	entries, total, err := s.repo.GetLeaderboard(pageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+	users := make([]*dto.RatingLeaderboardUserDTO, len(entries))
+	for i, entry := range entries {
+		identity := publicLeaderboardIdentity(entry.UserID, entry.Username, entry.ProfilePicture, entry.ShowOnLeaderboards)
+		users[i] = &dto.RatingLeaderboardUserDTO{
+			Rank:           offset + i + 1,
+			UserID:         identity.UserID,
+			Username:       identity.Username,
+			ProfilePicture: identity.ProfilePicture,
+			Rating:         entry.Rating,
+			Games:          entry.Games,
+			Provisional:    entry.Games < s.cfg.Params.ProvisionalGames,
+			Anonymous:      identity.Anonymous,
+		}
+	}
+	return &dto.PaginatedRatingLeaderboardResponse{
+		Users:   users,
+		Total:   total,
+		Page:    page,
+		PerPage: pageSize,
+	}, nil
+}
+
+// GetHistory возвращает изменения рейтинга пользователя, новые первыми
+func (s *RatingService) GetHistory(userID uint, page, pageSize int) ([]entity.RatingHistory, int64, error) {
+	page, pageSize = normalizeWalletPage(page, pageSize)
+	return s.repo.ListHistory(userID, pageSize, (page-1)*pageSize)
+}
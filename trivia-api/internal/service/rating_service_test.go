@@ -0,0 +1,111 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	"github.com/yourusername/trivia-api/internal/pkg/rating"
+)
+
+// fakeRatingRepo хранит рейтинги и историю в памяти
+type fakeRatingRepo struct {
+	ratings map[uint]entity.UserRating
+	history []entity.RatingHistory
+}
+
+func newFakeRatingRepo() *fakeRatingRepo {
+	return &fakeRatingRepo{ratings: make(map[uint]entity.UserRating)}
+}
+
+func (r *fakeRatingRepo) GetByUserID(userID uint) (*entity.UserRating, error) {
+	rating := r.ratings[userID]
+	rating.UserID = userID
+	return &rating, nil
+}
+
+func (r *fakeRatingRepo) ApplyQuiz(quizID uint, userIDs []uint, update repository.RatingUpdate) (bool, error) {
+	for _, h := range r.history {
+		if h.QuizID == quizID {
+			return false, nil
+		}
+	}
+	current := make(map[uint]entity.UserRating, len(userIDs))
+	for _, userID := range userIDs {
+		current[userID] = r.ratings[userID]
+	}
+	ratings, history := update(current)
+	for _, rating := range ratings {
+		r.ratings[rating.UserID] = rating
+	}
+	r.history = append(r.history, history...)
+	return true, nil
+}
+
+func (r *fakeRatingRepo) GetLeaderboard(limit, offset int) ([]repository.RatingLeaderboardEntry, int64, error) {
+	return nil, 0, nil
+}
+
+func (r *fakeRatingRepo) ListHistory(userID uint, limit, offset int) ([]entity.RatingHistory, int64, error) {
+	return nil, 0, nil
+}
+
+func TestRatingService_RateQuiz(t *testing.T) {
+	repo := newFakeRatingRepo()
+	resultRepo := new(MockResultRepository)
+	svc, err := NewRatingService(repo, resultRepo, RatingConfig{
+		Algorithm:  rating.AlgorithmElo,
+		Params:     rating.Config{Initial: 1500, KFactor: 32, ProvisionalGames: 1},
+		MinPlayers: 3,
+	})
+	require.NoError(t, err)
+
+	// Слишком мало участников — рейтинг не меняется
+	resultRepo.On("GetAllQuizResults", uint(1)).Return([]entity.Result{{UserID: 1, Rank: 1}, {UserID: 2, Rank: 2}}, nil)
+	applied, err := svc.RateQuiz(1)
+	require.NoError(t, err)
+	assert.False(t, applied)
+
+	resultRepo.On("GetAllQuizResults", uint(2)).Return([]entity.Result{
+		{UserID: 1, Rank: 1}, {UserID: 2, Rank: 2}, {UserID: 3, Rank: 3},
+	}, nil)
+	applied, err = svc.RateQuiz(2)
+	require.NoError(t, err)
+	assert.True(t, applied)
+
+	first, err := svc.GetUserRating(1)
+	require.NoError(t, err)
+	assert.Equal(t, 1516, first.Rating)
+	assert.Equal(t, 1516, first.PeakRating)
+	assert.Equal(t, 1, first.Games)
+	assert.False(t, first.Provisional)
+
+	last, err := svc.GetUserRating(3)
+	require.NoError(t, err)
+	assert.Equal(t, 1484, last.Rating)
+	assert.Equal(t, 1500, last.PeakRating) // пик не ниже начального рейтинга
+	require.Len(t, repo.history, 3)
+	assert.Equal(t, entity.RatingHistory{
+		UserID: 3, QuizID: 2, Algorithm: rating.AlgorithmElo,
+		RatingBefore: 1500, RatingAfter: 1484, Delta: -16, Rank: 3, Players: 3,
+	}, repo.history[2])
+
+	// Повторное событие той же викторины ничего не меняет
+	applied, err = svc.RateQuiz(2)
+	require.NoError(t, err)
+	assert.False(t, applied)
+	assert.Len(t, repo.history, 3)
+
+	// Новичок без викторин получает начальный предварительный рейтинг
+	newcomer, err := svc.GetUserRating(4)
+	require.NoError(t, err)
+	assert.Equal(t, 1500, newcomer.Rating)
+	assert.True(t, newcomer.Provisional)
+}
+
+func TestNewRatingService_UnknownAlgorithm(t *testing.T) {
+	_, err := NewRatingService(newFakeRatingRepo(), new(MockResultRepository), RatingConfig{Algorithm: "glicko"})
+	assert.Error(t, err)
+}
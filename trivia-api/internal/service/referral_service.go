@@ -0,0 +1,305 @@
+package service
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// ErrInvalidReferralCode возвращается, если переданный при регистрации код не существует
+var ErrInvalidReferralCode = errors.New("invalid_referral_code")
+
+const (
+	referralCodeLength   = 8
+	referralCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789" // без 0/O и 1/I
+	referralCodeAttempts = 5
+)
+
+// ReferralAttachInput содержит данные регистрации, нужные для привязки реферала
+type ReferralAttachInput struct {
+	RefereeID uint
+	Code      string
+	IP        string
+	DeviceID  string
+}
+
+// ReferralInfo содержит сводку реферальной программы для текущего пользователя
+type ReferralInfo struct {
+	Code              string            `json:"code"`
+	BonusPoints       int               `json:"bonus_points"`
+	TotalReferrals    int64             `json:"total_referrals"`
+	CreditedReferrals int64             `json:"credited_referrals"`
+	Referrals         []entity.Referral `json:"referrals"`
+}
+
+// ReferralService управляет кодами приглашений и начислением бонусов пригласившим
+type ReferralService struct {
+	referralRepo     repository.ReferralRepository
+	userRepo         repository.UserRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	bonusPoints      int
+	maxPerIP         int
+}
+
+// NewReferralService создает новый сервис реферальной программы
+func NewReferralService(
+	referralRepo repository.ReferralRepository,
+	userRepo repository.UserRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	bonusPoints int,
+	maxPerIP int,
+) (*ReferralService, error) {
+	if referralRepo == nil {
+		return nil, fmt.Errorf("referral repository is required")
+	}
+	if userRepo == nil {
+		return nil, fmt.Errorf("user repository is required")
+	}
+	if bonusPoints < 0 {
+		bonusPoints = 0
+	}
+	if maxPerIP <= 0 {
+		maxPerIP = 3
+	}
+
+	return &ReferralService{
+		referralRepo:     referralRepo,
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		bonusPoints:      bonusPoints,
+		maxPerIP:         maxPerIP,
+	}, nil
+}
+
+// NormalizeReferralCode приводит код к каноническому виду
+func NormalizeReferralCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// GetOrCreateCode возвращает код пользователя, создавая его при первом обращении
+func (s *ReferralService) GetOrCreateCode(userID uint) (*entity.ReferralCode, error) {
+	existing, err := s.referralRepo.GetCodeByUserID(userID)
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, apperrors.ErrNotFound) {
+		return nil, err
+	}
+
+	var lastErr error
+	for i := 0; i < referralCodeAttempts; i++ {
+		code, genErr := generateReferralCode()
+		if genErr != nil {
+			return nil, genErr
+		}
+		rc := &entity.ReferralCode{UserID: userID, Code: code, CreatedAt: time.Now()}
+		if lastErr = s.referralRepo.CreateCode(rc); lastErr == nil {
+			return rc, nil
+		}
+		// Параллельный запрос мог уже создать код для этого пользователя
+		if existing, err := s.referralRepo.GetCodeByUserID(userID); err == nil {
+			return existing, nil
+		}
+	}
+	return nil, fmt.Errorf("failed to allocate referral code: %w", lastErr)
+}
+
+// ResolveCode проверяет существование кода до создания пользователя
+func (s *ReferralService) ResolveCode(code string) (*entity.ReferralCode, error) {
+	code = NormalizeReferralCode(code)
+	if code == "" {
+		return nil, ErrInvalidReferralCode
+	}
+	rc, err := s.referralRepo.GetCodeByCode(code)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil, ErrInvalidReferralCode
+		}
+		return nil, err
+	}
+	return rc, nil
+}
+
+// AttachReferral связывает нового пользователя с пригласившим.
+// Подозрительные регистрации сохраняются со статусом rejected, чтобы они были видны в отчётах,
+// но регистрацию не прерывают.
+func (s *ReferralService) AttachReferral(input ReferralAttachInput) (*entity.Referral, error) {
+	rc, err := s.ResolveCode(input.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	referral := &entity.Referral{
+		ReferrerID:      rc.UserID,
+		RefereeID:       input.RefereeID,
+		Code:            rc.Code,
+		Status:          entity.ReferralStatusPending,
+		RefereeIP:       strings.TrimSpace(input.IP),
+		RefereeDeviceID: strings.TrimSpace(input.DeviceID),
+		CreatedAt:       time.Now(),
+	}
+
+	if reason := s.detectFraud(referral); reason != "" {
+		referral.Status = entity.ReferralStatusRejected
+		referral.RejectReason = reason
+		log.Printf("[ReferralService] Реферал отклонён: referrer=%d referee=%d reason=%s", referral.ReferrerID, referral.RefereeID, reason)
+	}
+
+	if err := s.referralRepo.Create(referral); err != nil {
+		return nil, err
+	}
+	return referral, nil
+}
+
+// detectFraud возвращает причину отклонения или пустую строку
+func (s *ReferralService) detectFraud(referral *entity.Referral) string {
+	if referral.ReferrerID == referral.RefereeID {
+		return entity.ReferralRejectSelfReferral
+	}
+
+	// Сравниваем IP и устройство приглашённого с активными сессиями пригласившего
+	if s.refreshTokenRepo != nil {
+		sessions, err := s.refreshTokenRepo.GetActiveTokensForUser(referral.ReferrerID)
+		if err != nil {
+			log.Printf("[ReferralService] Не удалось получить сессии пригласившего ID=%d: %v", referral.ReferrerID, err)
+		}
+		for _, session := range sessions {
+			if referral.RefereeDeviceID != "" && session.DeviceID == referral.RefereeDeviceID {
+				return entity.ReferralRejectSameDevice
+			}
+			if referral.RefereeIP != "" && session.IPAddress == referral.RefereeIP {
+				return entity.ReferralRejectSameIP
+			}
+		}
+	}
+
+	if referral.RefereeDeviceID != "" {
+		exists, err := s.referralRepo.ExistsForDevice(referral.ReferrerID, referral.RefereeDeviceID)
+		if err != nil {
+			log.Printf("[ReferralService] Ошибка проверки устройства для referrer=%d: %v", referral.ReferrerID, err)
+		} else if exists {
+			return entity.ReferralRejectSameDevice
+		}
+	}
+
+	if referral.RefereeIP != "" {
+		count, err := s.referralRepo.CountByRefereeIP(referral.ReferrerID, referral.RefereeIP)
+		if err != nil {
+			log.Printf("[ReferralService] Ошибка подсчёта рефералов по IP для referrer=%d: %v", referral.ReferrerID, err)
+		} else if count >= int64(s.maxPerIP) {
+			return entity.ReferralRejectIPLimit
+		}
+	}
+
+	return ""
+}
+
+// HandleFirstQuizCompleted начисляет бонус пригласившему после первой завершённой викторины приглашённого
+func (s *ReferralService) HandleFirstQuizCompleted(refereeID uint) error {
+	referral, err := s.referralRepo.GetByRefereeID(refereeID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if referral.Status != entity.ReferralStatusPending {
+		return nil
+	}
+
+	credited, err := s.referralRepo.MarkCredited(referral.ID, s.bonusPoints, time.Now())
+	if err != nil {
+		return err
+	}
+	if credited {
+		log.Printf("[ReferralService] Начислено %d бонусных очков пользователю ID=%d за приглашение ID=%d", s.bonusPoints, referral.ReferrerID, refereeID)
+	}
+	return nil
+}
+
+// GetMyReferralInfo возвращает код пользователя и список его приглашений
+func (s *ReferralService) GetMyReferralInfo(userID uint, page, pageSize int) (*ReferralInfo, error) {
+	page, pageSize = normalizeReferralPage(page, pageSize)
+
+	rc, err := s.GetOrCreateCode(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	referrals, total, err := s.referralRepo.ListByReferrer(userID, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	credited, err := s.referralRepo.CountByReferrerAndStatus(userID, entity.ReferralStatusCredited)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReferralInfo{
+		Code:              rc.Code,
+		BonusPoints:       s.bonusPoints,
+		TotalReferrals:    total,
+		CreditedReferrals: credited,
+		Referrals:         referrals,
+	}, nil
+}
+
+// GetStats возвращает статистику конверсии за период
+func (s *ReferralService) GetStats(from, to time.Time) (*repository.ReferralStats, error) {
+	return s.referralRepo.GetStats(from, to)
+}
+
+// GetTopReferrers возвращает самых активных пригласивших
+func (s *ReferralService) GetTopReferrers(limit int) ([]repository.ReferrerSummary, error) {
+	if limit < 1 {
+		limit = 10
+	} else if limit > 100 {
+		limit = 100
+	}
+	return s.referralRepo.GetTopReferrers(limit)
+}
+
+// ListReferrals возвращает реферальные записи для админки
+func (s *ReferralService) ListReferrals(status string, page, pageSize int) ([]entity.Referral, int64, error) {
+	switch status {
+	case "", entity.ReferralStatusPending, entity.ReferralStatusCredited, entity.ReferralStatusRejected:
+	default:
+		return nil, 0, fmt.Errorf("%w: unknown referral status %q", apperrors.ErrValidation, status)
+	}
+	page, pageSize = normalizeReferralPage(page, pageSize)
+	return s.referralRepo.List(status, pageSize, (page-1)*pageSize)
+}
+
+func normalizeReferralPage(page, pageSize int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 20
+	} else if pageSize > 100 {
+		pageSize = 100
+	}
+	return page, pageSize
+}
+
+func generateReferralCode() (string, error) {
+	var sb strings.Builder
+	max := big.NewInt(int64(len(referralCodeAlphabet)))
+	for i := 0; i < referralCodeLength; i++ {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate referral code: %w", err)
+		}
+		sb.WriteByte(referralCodeAlphabet[n.Int64()])
+	}
+	return sb.String(), nil
+}
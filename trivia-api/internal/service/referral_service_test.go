@@ -0,0 +1,218 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// ============================================================================
+// Мок для ReferralService
+// ============================================================================
+
+// MockReferralRepository реализует repository.ReferralRepository
+type MockReferralRepository struct {
+	mock.Mock
+}
+
+func (m *MockReferralRepository) CreateCode(code *entity.ReferralCode) error {
+	args := m.Called(code)
+	return args.Error(0)
+}
+
+func (m *MockReferralRepository) GetCodeByUserID(userID uint) (*entity.ReferralCode, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ReferralCode), args.Error(1)
+}
+
+func (m *MockReferralRepository) GetCodeByCode(code string) (*entity.ReferralCode, error) {
+	args := m.Called(code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ReferralCode), args.Error(1)
+}
+
+func (m *MockReferralRepository) Create(referral *entity.Referral) error {
+	args := m.Called(referral)
+	return args.Error(0)
+}
+
+func (m *MockReferralRepository) GetByRefereeID(refereeID uint) (*entity.Referral, error) {
+	args := m.Called(refereeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Referral), args.Error(1)
+}
+
+func (m *MockReferralRepository) ListByReferrer(referrerID uint, limit, offset int) ([]entity.Referral, int64, error) {
+	args := m.Called(referrerID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]entity.Referral), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockReferralRepository) CountByReferrerAndStatus(referrerID uint, status string) (int64, error) {
+	args := m.Called(referrerID, status)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockReferralRepository) CountByRefereeIP(referrerID uint, ip string) (int64, error) {
+	args := m.Called(referrerID, ip)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockReferralRepository) ExistsForDevice(referrerID uint, deviceID string) (bool, error) {
+	args := m.Called(referrerID, deviceID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockReferralRepository) MarkCredited(referralID uint, bonusPoints int, creditedAt time.Time) (bool, error) {
+	args := m.Called(referralID, bonusPoints, creditedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockReferralRepository) GetStats(from, to time.Time) (*repository.ReferralStats, error) {
+	args := m.Called(from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.ReferralStats), args.Error(1)
+}
+
+func (m *MockReferralRepository) GetTopReferrers(limit int) ([]repository.ReferrerSummary, error) {
+	args := m.Called(limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.ReferrerSummary), args.Error(1)
+}
+
+func (m *MockReferralRepository) List(status string, limit, offset int) ([]entity.Referral, int64, error) {
+	args := m.Called(status, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]entity.Referral), args.Get(1).(int64), args.Error(2)
+}
+
+func createTestReferralService(referralRepo *MockReferralRepository, refreshTokenRepo *MockRefreshTokenRepository) *ReferralService {
+	svc := &ReferralService{
+		referralRepo: referralRepo,
+		userRepo:     new(MockUserRepository),
+		bonusPoints:  100,
+		maxPerIP:     3,
+	}
+	if refreshTokenRepo != nil {
+		svc.refreshTokenRepo = refreshTokenRepo
+	}
+	return svc
+}
+
+// ============================================================================
+// Тесты для ReferralService
+// ============================================================================
+
+func TestReferralService_AttachReferral_Pending(t *testing.T) {
+	mockRepo := new(MockReferralRepository)
+	mockTokens := new(MockRefreshTokenRepository)
+
+	mockRepo.On("GetCodeByCode", "ABCD2345").Return(&entity.ReferralCode{UserID: 1, Code: "ABCD2345"}, nil)
+	mockTokens.On("GetActiveTokensForUser", uint(1)).Return([]*entity.RefreshToken{
+		{UserID: 1, DeviceID: "device-a", IPAddress: "10.0.0.1"},
+	}, nil)
+	mockRepo.On("ExistsForDevice", uint(1), "device-b").Return(false, nil)
+	mockRepo.On("CountByRefereeIP", uint(1), "10.0.0.2").Return(int64(0), nil)
+	mockRepo.On("Create", mock.AnythingOfType("*entity.Referral")).Return(nil)
+
+	svc := createTestReferralService(mockRepo, mockTokens)
+
+	referral, err := svc.AttachReferral(ReferralAttachInput{RefereeID: 2, Code: " abcd2345 ", IP: "10.0.0.2", DeviceID: "device-b"})
+
+	require.NoError(t, err)
+	assert.Equal(t, entity.ReferralStatusPending, referral.Status)
+	assert.Equal(t, uint(1), referral.ReferrerID)
+	assert.Empty(t, referral.RejectReason)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestReferralService_AttachReferral_RejectsSameDevice(t *testing.T) {
+	mockRepo := new(MockReferralRepository)
+	mockTokens := new(MockRefreshTokenRepository)
+
+	mockRepo.On("GetCodeByCode", "ABCD2345").Return(&entity.ReferralCode{UserID: 1, Code: "ABCD2345"}, nil)
+	mockTokens.On("GetActiveTokensForUser", uint(1)).Return([]*entity.RefreshToken{
+		{UserID: 1, DeviceID: "device-a", IPAddress: "10.0.0.1"},
+	}, nil)
+	mockRepo.On("Create", mock.AnythingOfType("*entity.Referral")).Return(nil)
+
+	svc := createTestReferralService(mockRepo, mockTokens)
+
+	referral, err := svc.AttachReferral(ReferralAttachInput{RefereeID: 2, Code: "ABCD2345", IP: "10.0.0.2", DeviceID: "device-a"})
+
+	require.NoError(t, err, "Подозрительный реферал сохраняется, а не прерывает регистрацию")
+	assert.Equal(t, entity.ReferralStatusRejected, referral.Status)
+	assert.Equal(t, entity.ReferralRejectSameDevice, referral.RejectReason)
+}
+
+func TestReferralService_AttachReferral_RejectsIPLimit(t *testing.T) {
+	mockRepo := new(MockReferralRepository)
+
+	mockRepo.On("GetCodeByCode", "ABCD2345").Return(&entity.ReferralCode{UserID: 1, Code: "ABCD2345"}, nil)
+	mockRepo.On("CountByRefereeIP", uint(1), "10.0.0.2").Return(int64(3), nil)
+	mockRepo.On("Create", mock.AnythingOfType("*entity.Referral")).Return(nil)
+
+	svc := createTestReferralService(mockRepo, nil)
+
+	referral, err := svc.AttachReferral(ReferralAttachInput{RefereeID: 2, Code: "ABCD2345", IP: "10.0.0.2"})
+
+	require.NoError(t, err)
+	assert.Equal(t, entity.ReferralRejectIPLimit, referral.RejectReason)
+}
+
+func TestReferralService_ResolveCode_Unknown(t *testing.T) {
+	mockRepo := new(MockReferralRepository)
+	mockRepo.On("GetCodeByCode", "NOPE").Return(nil, apperrors.ErrNotFound)
+
+	svc := createTestReferralService(mockRepo, nil)
+
+	_, err := svc.ResolveCode("nope")
+
+	assert.ErrorIs(t, err, ErrInvalidReferralCode)
+}
+
+func TestReferralService_HandleFirstQuizCompleted_CreditsPending(t *testing.T) {
+	mockRepo := new(MockReferralRepository)
+	mockRepo.On("GetByRefereeID", uint(2)).Return(&entity.Referral{ID: 7, ReferrerID: 1, RefereeID: 2, Status: entity.ReferralStatusPending}, nil)
+	mockRepo.On("MarkCredited", uint(7), 100, mock.AnythingOfType("time.Time")).Return(true, nil)
+
+	svc := createTestReferralService(mockRepo, nil)
+
+	err := svc.HandleFirstQuizCompleted(2)
+
+	require.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestReferralService_HandleFirstQuizCompleted_SkipsRejected(t *testing.T) {
+	mockRepo := new(MockReferralRepository)
+	mockRepo.On("GetByRefereeID", uint(2)).Return(&entity.Referral{ID: 7, ReferrerID: 1, RefereeID: 2, Status: entity.ReferralStatusRejected}, nil)
+
+	svc := createTestReferralService(mockRepo, nil)
+
+	err := svc.HandleFirstQuizCompleted(2)
+
+	require.NoError(t, err)
+	mockRepo.AssertNotCalled(t, "MarkCredited", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"log"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	"github.com/yourusername/trivia-api/internal/pkg/pagination"
+)
+
+// GetQuizResultsByCursor возвращает страницу результатов викторины после курсора cursor
+// (пустой курсор — первая страница) и курсор следующей страницы (пустой, если страница последняя).
+// Без fullAccess (администратор) применяются настройки приватности игроков.
+func (s *ResultService) GetQuizResultsByCursor(quizID uint, cursor string, pageSize int, fullAccess bool) ([]entity.Result, string, error) {
+	if pageSize < 1 {
+		pageSize = 10
+	} else if pageSize > 100 {
+		pageSize = 100
+	}
+
+	var after repository.QuizResultCursor
+	ok, err := pagination.DecodeCursor(cursor, &after)
+	if err != nil {
+		return nil, "", err
+	}
+	var afterPtr *repository.QuizResultCursor
+	if ok {
+		afterPtr = &after
+	}
+
+	// Запрашиваем на одну запись больше, чтобы понять, есть ли следующая страница
+	results, err := s.resultRepo.GetQuizResultsAfter(quizID, afterPtr, pageSize+1)
+	if err != nil {
+		log.Printf("[ResultService] Ошибка при получении результатов викторины %d по курсору: %v", quizID, err)
+		return nil, "", err
+	}
+	next := ""
+	if len(results) > pageSize {
+		results = results[:pageSize]
+		last := results[len(results)-1]
+		if next, err = pagination.EncodeCursor(repository.QuizResultCursor{Rank: last.Rank, Score: last.Score, ID: last.ID}); err != nil {
+			return nil, "", err
+		}
+	}
+	if !fullAccess {
+		if err := s.applyResultsPrivacy(results); err != nil {
+			return nil, "", err
+		}
+	}
+	return results, next, nil
+}
+
+// GetUserResultsByCursor возвращает страницу истории игр пользователя после курсора cursor
+// и курсор следующей страницы.
+func (s *ResultService) GetUserResultsByCursor(userID uint, cursor string, pageSize int) ([]entity.Result, string, error) {
+	var after repository.UserResultCursor
+	ok, err := pagination.DecodeCursor(cursor, &after)
+	if err != nil {
+		return nil, "", err
+	}
+	var afterPtr *repository.UserResultCursor
+	if ok {
+		afterPtr = &after
+	}
+
+	results, err := s.resultRepo.GetUserResultsAfter(userID, afterPtr, pageSize+1)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(results) <= pageSize {
+		return results, "", nil
+	}
+	results = results[:pageSize]
+	last := results[len(results)-1]
+	next, err := pagination.EncodeCursor(repository.UserResultCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	return results, next, err
+}
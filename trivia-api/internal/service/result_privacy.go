@@ -0,0 +1,118 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+// AnonymousPlayerName — имя, под которым в публичных результатах и лидербордах показывается
+// игрок, отключивший show_on_leaderboards
+const AnonymousPlayerName = "Игрок"
+
+// applyResultsPrivacy применяет к результатам викторины настройки приватности игроков:
+// скрывшие себя заменяются заглушкой без ID и аватара, разрешившие — дополняются именем и фамилией.
+func (s *ResultService) applyResultsPrivacy(results []entity.Result) error {
+	if len(results) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(results))
+	for i, r := range results {
+		ids[i] = r.UserID
+	}
+	users, err := s.resultUsers(ids)
+	if err != nil {
+		return err
+	}
+	for i := range results {
+		user, ok := users[results[i].UserID]
+		if !ok {
+			continue
+		}
+		if !user.ShowOnLeaderboards {
+			anonymizeResult(&results[i])
+			continue
+		}
+		if user.ShowFullName {
+			results[i].FullName = userFullName(user)
+		}
+	}
+	return nil
+}
+
+// applyTimelinePrivacy заменяет заглушкой игроков хроники, скрывших себя из публичных результатов.
+// Применяется при каждом чтении, поэтому изменение настроек не ждёт истечения кеша хроники.
+func (s *ResultService) applyTimelinePrivacy(timeline *QuizTimeline) error {
+	var answers []*QuizTimelineAnswer
+	var ids []uint
+	for i := range timeline.Questions {
+		if fastest := timeline.Questions[i].FastestCorrect; fastest != nil {
+			answers = append(answers, fastest)
+			ids = append(ids, fastest.UserID)
+		}
+	}
+	if timeline.FastestAnswer != nil {
+		answers = append(answers, timeline.FastestAnswer)
+		ids = append(ids, timeline.FastestAnswer.UserID)
+	}
+	if len(answers) == 0 {
+		return nil
+	}
+
+	users, err := s.resultUsers(ids)
+	if err != nil {
+		return err
+	}
+	for _, a := range answers {
+		if user, ok := users[a.UserID]; ok && !user.ShowOnLeaderboards {
+			a.UserID = 0
+			a.Username = AnonymousPlayerName
+			a.Anonymous = true
+		}
+	}
+	return nil
+}
+
+// resultUsers загружает игроков результатов одним запросом
+func (s *ResultService) resultUsers(ids []uint) (map[uint]entity.User, error) {
+	list, err := s.userRepo.GetByIDs(ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load result players: %w", err)
+	}
+	users := make(map[uint]entity.User, len(list))
+	for _, u := range list {
+		users[u.ID] = u
+	}
+	return users, nil
+}
+
+// leaderboardIdentity — под каким ID, именем и аватаром игрок показывается в лидерборде
+type leaderboardIdentity struct {
+	UserID         uint
+	Username       string
+	ProfilePicture string
+	Anonymous      bool
+}
+
+// publicLeaderboardIdentity возвращает подпись игрока в публичном лидерборде: скрывший себя
+// (show_on_leaderboards) показывается заглушкой без ID и аватара. Через неё строятся строки
+// всех лидербордов, чтобы новый лидерборд не раскрыл скрывшихся игроков.
+func publicLeaderboardIdentity(userID uint, username, profilePicture string, showOnLeaderboards bool) leaderboardIdentity {
+	if !showOnLeaderboards {
+		return leaderboardIdentity{Username: AnonymousPlayerName, Anonymous: true}
+	}
+	return leaderboardIdentity{UserID: userID, Username: username, ProfilePicture: profilePicture}
+}
+
+func anonymizeResult(r *entity.Result) {
+	r.UserID = 0
+	r.Username = AnonymousPlayerName
+	r.ProfilePicture = ""
+	r.FullName = ""
+	r.Anonymous = true
+}
+
+func userFullName(user entity.User) string {
+	return strings.TrimSpace(strings.TrimSpace(user.FirstName) + " " + strings.TrimSpace(user.LastName))
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	"github.com/yourusername/trivia-api/internal/pkg/httpcache"
+)
+
+func TestResultService_GetQuizResults_AppliesPrivacy(t *testing.T) {
+	resultRepo := new(MockResultRepoForResultService)
+	userRepo := new(MockUserRepository)
+	resultService := createTestResultService(resultRepo)
+	resultService.userRepo = userRepo
+
+	page := func() []entity.Result {
+		return []entity.Result{
+			{ID: 1, UserID: 1, QuizID: 1, Username: "hidden", ProfilePicture: "a.png", Rank: 1},
+			{ID: 2, UserID: 2, QuizID: 1, Username: "named", Rank: 2},
+			{ID: 3, UserID: 3, QuizID: 1, Username: "plain", Rank: 3},
+		}
+	}
+	resultRepo.On("GetQuizResults", uint(1), 10, 0).Return(page(), int64(3), nil).Once()
+	userRepo.On("GetByIDs", []uint{1, 2, 3}).Return([]entity.User{
+		{ID: 1, ShowOnLeaderboards: false, ShowFullName: true, FirstName: "Скрытый", LastName: "Игрок"},
+		{ID: 2, ShowOnLeaderboards: true, ShowFullName: true, FirstName: "Айгерим", LastName: "Сейткали"},
+		{ID: 3, ShowOnLeaderboards: true, FirstName: "Иван", LastName: "Петров"},
+	}, nil).Once()
+
+	results, _, err := resultService.GetQuizResults(1, 1, 10, false)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	assert.True(t, results[0].Anonymous)
+	assert.Equal(t, AnonymousPlayerName, results[0].Username)
+	assert.Zero(t, results[0].UserID, "ID скрытого игрока не раскрывается")
+	assert.Empty(t, results[0].ProfilePicture)
+	assert.Empty(t, results[0].FullName)
+	assert.Equal(t, 1, results[0].Rank, "место сохраняется")
+
+	assert.Equal(t, "Айгерим Сейткали", results[1].FullName)
+	assert.Equal(t, "named", results[1].Username)
+	assert.Empty(t, results[2].FullName, "имя показывается только с разрешения")
+
+	// Администратор видит результаты без изменений и без загрузки игроков
+	resultRepo.On("GetQuizResults", uint(1), 10, 0).Return(page(), int64(3), nil).Once()
+	results, _, err = resultService.GetQuizResults(1, 1, 10, true)
+	require.NoError(t, err)
+	assert.Equal(t, "hidden", results[0].Username)
+	assert.Equal(t, uint(1), results[0].UserID)
+	assert.False(t, results[0].Anonymous)
+
+	resultRepo.AssertExpectations(t)
+	userRepo.AssertExpectations(t)
+}
+
+func TestResultService_ApplyTimelinePrivacy(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	resultService := createTestResultService(new(MockResultRepoForResultService))
+	resultService.userRepo = userRepo
+
+	timeline := &QuizTimeline{
+		Questions: []QuizTimelineQuestion{
+			{Number: 1, FastestCorrect: &QuizTimelineAnswer{UserID: 5, Username: "fast", ResponseTimeMs: 900}},
+			{Number: 2, FastestCorrect: &QuizTimelineAnswer{UserID: 6, Username: "public", ResponseTimeMs: 1200}},
+		},
+		FastestAnswer: &QuizTimelineAnswer{QuestionNumber: 1, UserID: 5, Username: "fast", ResponseTimeMs: 900},
+	}
+	userRepo.On("GetByIDs", []uint{5, 6, 5}).Return([]entity.User{
+		{ID: 5, ShowOnLeaderboards: false},
+		{ID: 6, ShowOnLeaderboards: true},
+	}, nil).Once()
+
+	require.NoError(t, resultService.applyTimelinePrivacy(timeline))
+	assert.True(t, timeline.Questions[0].FastestCorrect.Anonymous)
+	assert.Equal(t, AnonymousPlayerName, timeline.FastestAnswer.Username)
+	assert.Zero(t, timeline.FastestAnswer.UserID)
+	assert.Equal(t, int64(900), timeline.FastestAnswer.ResponseTimeMs)
+	assert.Equal(t, "public", timeline.Questions[1].FastestCorrect.Username)
+	userRepo.AssertExpectations(t)
+}
+
+func TestNewLeaderboardUserDTO_HiddenPlayer(t *testing.T) {
+	hidden := newLeaderboardUserDTO(3, entity.User{ID: 7, Username: "shy", ProfilePicture: "p.png", WinsCount: 4})
+	assert.True(t, hidden.Anonymous)
+	assert.Equal(t, AnonymousPlayerName, hidden.Username)
+	assert.Zero(t, hidden.UserID)
+	assert.Empty(t, hidden.ProfilePicture)
+	assert.Equal(t, int64(4), hidden.WinsCount)
+	assert.Equal(t, 3, hidden.Rank)
+
+	visible := newLeaderboardUserDTO(1, entity.User{ID: 8, Username: "bold", ShowOnLeaderboards: true})
+	assert.False(t, visible.Anonymous)
+	assert.Equal(t, uint(8), visible.UserID)
+	assert.Equal(t, "bold", visible.Username)
+}
+
+// Скрывшие себя игроки показываются заглушкой во всех лидербордах
+func TestLeaderboards_AnonymizeHiddenPlayers(t *testing.T) {
+	assertHidden := func(t *testing.T, userID uint, username, profilePicture string, anonymous bool) {
+		t.Helper()
+		assert.Zero(t, userID, "ID скрытого игрока не раскрывается")
+		assert.Equal(t, AnonymousPlayerName, username)
+		assert.Empty(t, profilePicture)
+		assert.True(t, anonymous)
+	}
+
+	t.Run("category", func(t *testing.T) {
+		repo := newFakeTaxonomyRepo()
+		svc := NewTaxonomyService(repo)
+		_, err := svc.CreateCategory(CategoryInput{Slug: "music", Name: "Музыка"})
+		require.NoError(t, err)
+		repo.entries = []entity.CategoryLeaderboardEntry{
+			{UserID: 1, Username: "hidden", ProfilePicture: "a.png", WinsCount: 3},
+			{UserID: 2, Username: "shown", ProfilePicture: "b.png", WinsCount: 2, ShowOnLeaderboards: true},
+		}
+
+		leaderboard, err := svc.GetCategoryLeaderboard("music", 1, 10)
+		require.NoError(t, err)
+		require.Len(t, leaderboard.Users, 2)
+		hidden := leaderboard.Users[0]
+		assertHidden(t, hidden.UserID, hidden.Username, hidden.ProfilePicture, hidden.Anonymous)
+		assert.Equal(t, int64(3), hidden.WinsCount, "статистика и место сохраняются")
+		assert.Equal(t, 1, hidden.Rank)
+		assert.Equal(t, "shown", leaderboard.Users[1].Username)
+		assert.Equal(t, uint(2), leaderboard.Users[1].UserID)
+		assert.False(t, leaderboard.Users[1].Anonymous)
+	})
+
+	t.Run("daily challenge", func(t *testing.T) {
+		repo := newFakeDailyChallengeRepo()
+		repo.leaderboard = []repository.DailyLeaderboardEntry{
+			{UserID: 1, Username: "hidden", ProfilePicture: "a.png", ResponseTimeMs: 900},
+			{UserID: 2, Username: "shown", ResponseTimeMs: 1200, ShowOnLeaderboards: true},
+		}
+		svc := NewDailyChallengeService(repo, nil, nil, DailyChallengeConfig{Grace: time.Second})
+
+		users, total, err := svc.GetLeaderboard("2026-10-16", 1, 10)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		require.Len(t, users, 2)
+		assertHidden(t, users[0].UserID, users[0].Username, users[0].ProfilePicture, users[0].Anonymous)
+		assert.Equal(t, int64(900), users[0].ResponseTimeMs)
+		assert.Equal(t, uint(2), users[1].UserID)
+		assert.False(t, users[1].Anonymous)
+	})
+
+	t.Run("friends", func(t *testing.T) {
+		repo := &fakeFollowRepo{leaderboard: []entity.User{
+			{ID: 1, Username: "me", WinsCount: 5},
+			{ID: 2, Username: "hidden", ProfilePicture: "a.png", WinsCount: 4},
+			{ID: 3, Username: "shown", WinsCount: 3, ShowOnLeaderboards: true},
+		}}
+		svc := NewFollowService(repo, newFollowTestUsers())
+
+		page, err := svc.GetFriendsLeaderboard(1, 1, 10)
+		require.NoError(t, err)
+		require.Len(t, page.Users, 3)
+		assert.Equal(t, "me", page.Users[0].Username, "свою строку пользователь видит всегда")
+		assert.False(t, page.Users[0].Anonymous)
+		assertHidden(t, page.Users[1].UserID, page.Users[1].Username, page.Users[1].ProfilePicture, page.Users[1].Anonymous)
+		assert.Equal(t, "shown", page.Users[2].Username)
+	})
+}
+
+// recordingHTTPCache запоминает сброшенные пространства имён HTTP-кеша
+type recordingHTTPCache struct {
+	namespaces []string
+}
+
+func (r *recordingHTTPCache) Invalidate(namespaces ...string) {
+	r.namespaces = append(r.namespaces, namespaces...)
+}
+
+func TestUserService_UpdatePrivacy_InvalidatesLeaderboardCache(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	userRepo.On("UpdateProfile", uint(7), map[string]interface{}{"show_on_leaderboards": false}).Return(nil).Once()
+	userRepo.On("UpdateProfile", uint(7), map[string]interface{}{"show_full_name": true}).Return(nil).Once()
+	userRepo.On("GetByID", uint(7)).Return(&entity.User{ID: 7}, nil)
+	cache := &recordingHTTPCache{}
+	svc := NewUserService(userRepo, new(MockResultRepository))
+	svc.SetHTTPCache(cache)
+
+	hidden := false
+	_, err := svc.UpdatePrivacy(7, UpdatePrivacyInput{ShowOnLeaderboards: &hidden})
+	require.NoError(t, err)
+	assert.Equal(t, []string{httpcache.NamespaceLeaderboard}, cache.namespaces)
+
+	// Настройки, не влияющие на лидерборды, кеш не сбрасывают
+	shown := true
+	_, err = svc.UpdatePrivacy(7, UpdatePrivacyInput{ShowFullName: &shown})
+	require.NoError(t, err)
+	assert.Len(t, cache.namespaces, 1)
+	userRepo.AssertExpectations(t)
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/yourusername/trivia-api/internal/domain/entity"
 	"github.com/yourusername/trivia-api/internal/domain/repository"
+	"github.com/yourusername/trivia-api/internal/pkg/httpcache"
 	"github.com/yourusername/trivia-api/internal/service/quizmanager"
 	"github.com/yourusername/trivia-api/internal/websocket"
 )
@@ -20,11 +21,25 @@ type ResultService struct {
 	userRepo     repository.UserRepository
 	quizRepo     repository.QuizRepository
 	questionRepo repository.QuestionRepository
+	statsRepo    repository.QuizAnswerStatsRepository
 	cacheRepo    repository.CacheRepository
 	db           *gorm.DB
 	wsManager    *websocket.Manager
 	config       *quizmanager.Config
 	requireVerifiedForPrizes bool
+	referralService          *ReferralService
+	pushService              *PushNotificationService
+	notificationService      *NotificationService
+	walletService            *WalletService
+	prizeClaimService        *PrizeClaimService
+	httpCache                httpcache.Invalidator
+	answerBuffer             *AnswerBuffer
+	sponsorService           *SponsorService
+	eventBus                 *EventBus
+	geoRestricted            bool // призы викторин с allowed_countries — только игрокам из этих стран
+	geoAllowUnknown          bool
+	ipRiskThreshold          int    // 0 — победители с рискованных адресов не проверяются
+	ipRiskAction             string // entity.PrizeRiskActionFlag или entity.PrizeRiskActionExclude
 }
 
 // NewResultService СЃРѕР·РґР°РµС‚ РЅРѕРІС‹Р№ СЃРµСЂРІРёСЃ СЂРµР·СѓР»СЊС‚Р°С‚РѕРІ
@@ -33,6 +48,7 @@ func NewResultService(
 	userRepo repository.UserRepository,
 	quizRepo repository.QuizRepository,
 	questionRepo repository.QuestionRepository,
+	statsRepo repository.QuizAnswerStatsRepository,
 	cacheRepo repository.CacheRepository,
 	db *gorm.DB,
 	wsManager *websocket.Manager,
@@ -43,6 +59,7 @@ func NewResultService(
 		userRepo:     userRepo,
 		quizRepo:     quizRepo,
 		questionRepo: questionRepo,
+		statsRepo:    statsRepo,
 		cacheRepo:    cacheRepo,
 		db:           db,
 		wsManager:    wsManager,
@@ -54,6 +71,110 @@ func (s *ResultService) SetEmailVerificationGate(enabled bool) {
 	s.requireVerifiedForPrizes = enabled
 }
 
+// SetEventBus включает доменные события quiz.completed и prize.awarded: они записываются
+// в транзакции подведения итогов, а in-app уведомления о результатах отправляются подписчиком шины
+func (s *ResultService) SetEventBus(bus *EventBus) {
+	s.eventBus = bus
+	bus.Subscribe("result_notifications", s.handleQuizCompleted, entity.EventQuizCompleted)
+}
+
+// SetReferralService включает начисление реферальных бонусов после первой викторины пользователя
+func (s *ResultService) SetReferralService(svc *ReferralService) {
+	s.referralService = svc
+}
+
+// SetPushNotificationService включает push-уведомления победителям викторин
+func (s *ResultService) SetPushNotificationService(svc *PushNotificationService) {
+	s.pushService = svc
+}
+
+// SetNotificationService включает in-app уведомления о результатах и выигрышах
+func (s *ResultService) SetNotificationService(svc *NotificationService) {
+	s.notificationService = svc
+}
+
+// SetWalletService включает зачисление призов в кошельки победителей
+func (s *ResultService) SetWalletService(svc *WalletService) {
+	s.walletService = svc
+}
+
+// SetPrizeClaimService включает заявки на призы: вместо прямого зачисления победители
+// получают токен заявки и получают приз после проверки администратором
+func (s *ResultService) SetPrizeClaimService(svc *PrizeClaimService) {
+	s.prizeClaimService = svc
+}
+
+// SetHTTPCache подключает сброс закешированных лидерборда и списков викторин после финализации
+func (s *ResultService) SetHTTPCache(invalidator httpcache.Invalidator) {
+	s.httpCache = invalidator
+}
+
+// SetAnswerBuffer подключает буфер пакетной записи ответов, который нужно сбросить перед подсчётом результатов
+func (s *ResultService) SetAnswerBuffer(buffer *AnswerBuffer) {
+	s.answerBuffer = buffer
+}
+
+// SetSponsorService подключает брендинг спонсора в событии quiz:results_available
+func (s *ResultService) SetSponsorService(svc *SponsorService) {
+	s.sponsorService = svc
+}
+
+// FlushAnswers записывает в БД ответы, ещё находящиеся в буфере записи
+func (s *ResultService) FlushAnswers() error {
+	if s.answerBuffer == nil {
+		return nil
+	}
+	return s.answerBuffer.Flush()
+}
+
+// VoidQuestionAnswers аннулирует ответы на снятый с эфира вопрос. Буфер записи сбрасывается
+// заранее, чтобы аннулирование застало все уже принятые ответы.
+func (s *ResultService) VoidQuestionAnswers(ctx context.Context, quizID, questionID uint) (int64, error) {
+	if err := s.FlushAnswers(); err != nil {
+		return 0, fmt.Errorf("flush buffered answers: %w", err)
+	}
+	voided, err := s.resultRepo.WithContext(ctx).VoidQuestionAnswers(quizID, questionID)
+	if err != nil {
+		return 0, err
+	}
+	s.emitAnswersChanged(ctx, entity.EventQuestionVoided, entity.QuestionVoidedPayload{
+		QuizID: quizID, QuestionID: questionID, VoidedAt: time.Now().UTC(),
+	})
+	return voided, nil
+}
+
+// ReviveEliminatedAnswer возвращает выбывшего игрока в викторину (второй шанс): выбивший его
+// ответ перестаёт считаться выбыванием. Буфер записи сбрасывается, чтобы ответ уже был в БД.
+func (s *ResultService) ReviveEliminatedAnswer(ctx context.Context, quizID, userID uint) (int64, error) {
+	if err := s.FlushAnswers(); err != nil {
+		return 0, fmt.Errorf("flush buffered answers: %w", err)
+	}
+	revived, err := s.resultRepo.WithContext(ctx).ReviveEliminatedAnswer(quizID, userID)
+	if err != nil {
+		return 0, err
+	}
+	if revived > 0 {
+		s.emitAnswersChanged(ctx, entity.EventEliminationRevoked, entity.EliminationRevokedPayload{
+			QuizID: quizID, UserID: userID, RevokedAt: time.Now().UTC(),
+		})
+	}
+	return revived, nil
+}
+
+// emitAnswersChanged сообщает подписчикам шины (хранилищу аналитики) об исправлении уже записанных ответов.
+// Ответы к этому моменту обновлены, поэтому ошибка записи события только логируется: пропущенное
+// исправление восстанавливает backfill хранилища.
+func (s *ResultService) emitAnswersChanged(ctx context.Context, eventType string, payload interface{}) {
+	if s.eventBus == nil {
+		return
+	}
+	if err := s.eventBus.Emit(s.db.WithContext(ctx), eventType, payload); err != nil {
+		log.Printf("[ResultService] WARNING: Не удалось записать событие %s: %v", eventType, err)
+		return
+	}
+	s.eventBus.Notify()
+}
+
 // CalculateQuizResult РїРѕРґСЃС‡РёС‚С‹РІР°РµС‚ РёС‚РѕРіРѕРІС‹Р№ СЂРµР·СѓР»СЊС‚Р°С‚ РїРѕР»СЊР·РѕРІР°С‚РµР»СЏ РІ РІРёРєС‚РѕСЂРёРЅРµ
 func (s *ResultService) CalculateQuizResult(userID, quizID uint) (*entity.Result, error) {
 	// РџРѕР»СѓС‡Р°РµРј РёРЅС„РѕСЂРјР°С†РёСЋ Рѕ РїРѕР»СЊР·РѕРІР°С‚РµР»Рµ
@@ -84,10 +205,23 @@ func (s *ResultService) CalculateQuizResult(userID, quizID uint) (*entity.Result
 	correctAnswers := 0
 	var eliminatedOnQuestion *int
 	var eliminationReason *string
+	revived := false
+	mistakes := 0
+	var totalResponseTimeMs int64
 	for i, answer := range userAnswers {
 		totalScore += answer.Score
 		if answer.IsCorrect {
 			correctAnswers++
+			totalResponseTimeMs += answer.ResponseTimeMs
+		} else if answer.EliminationReason != entity.AnswerReasonQuestionVoided &&
+			answer.EliminationReason != entity.AnswerReasonSecondChance &&
+			answer.EliminationReason != entity.AnswerReasonSuddenDeath {
+			mistakes++
+		}
+		// Ответ, прощённый вторым шансом, засчитывается, иначе вернувшийся игрок не сможет победить
+		if answer.EliminationReason == entity.AnswerReasonSecondChance {
+			correctAnswers++
+			revived = true
 		}
 		// РС‰РµРј РїРµСЂРІС‹Р№ РѕС‚РІРµС‚ СЃ РІС‹Р±С‹С‚РёРµРј
 		if answer.IsEliminated && eliminatedOnQuestion == nil {
@@ -112,8 +246,19 @@ func (s *ResultService) CalculateQuizResult(userID, quizID uint) (*entity.Result
 		IsEliminated:         isEliminated,
 		EliminatedOnQuestion: eliminatedOnQuestion,
 		EliminationReason:    eliminationReason,
+		Revived:              revived,
+		GameMode:             quiz.EffectiveGameMode(),
+		TotalResponseTimeMs:  totalResponseTimeMs,
+		ScoringStrategy:      quiz.EffectiveScoringStrategy(),
 		CompletedAt:          time.Now(),
 	}
+	if quiz.EffectiveGameMode() == entity.GameModeLives {
+		livesLeft := quiz.Lives - mistakes
+		if livesLeft < 0 {
+			livesLeft = 0
+		}
+		result.LivesLeft = &livesLeft
+	}
 
 	// --- РќР°С‡Р°Р»Рѕ С‚СЂР°РЅР·Р°РєС†РёРё ---
 	tx := s.db.Begin()
@@ -156,12 +301,26 @@ func (s *ResultService) CalculateQuizResult(userID, quizID uint) (*entity.Result
 		return nil, fmt.Errorf("failed to increment games played: %w", err)
 	}
 
+	// Серии игрока (викторины подряд, верные ответы, без выбывания) — в той же транзакции
+	if err := s.updateStreaks(tx, quiz, userID, userAnswers, isEliminated); err != nil {
+		tx.Rollback()
+		log.Printf("Error updating user streaks in transaction: %v", err)
+		return nil, err
+	}
+
 	// --- РљРѕРјРјРёС‚ С‚СЂР°РЅР·Р°РєС†РёРё ---
 	if err := tx.Commit().Error; err != nil {
 		log.Printf("Error committing transaction in CalculateQuizResult: %v", err)
 		return nil, err
 	}
 
+	// Первая завершённая викторина приглашённого пользователя засчитывает реферал
+	if s.referralService != nil && user.GamesPlayed == 0 {
+		if err := s.referralService.HandleFirstQuizCompleted(userID); err != nil {
+			log.Printf("[ResultService] Ошибка начисления реферального бонуса для пользователя #%d: %v", userID, err)
+		}
+	}
+
 	log.Printf("[ResultService] РЈСЃРїРµС€РЅРѕ СЂР°СЃСЃС‡РёС‚Р°РЅ Рё СЃРѕС…СЂР°РЅРµРЅ СЂРµР·СѓР»СЊС‚Р°С‚ РґР»СЏ РїРѕР»СЊР·РѕРІР°С‚РµР»СЏ #%d РІ РІРёРєС‚РѕСЂРёРЅРµ #%d", userID, quizID)
 	return result, nil
 }
@@ -169,7 +328,9 @@ func (s *ResultService) CalculateQuizResult(userID, quizID uint) (*entity.Result
 // GetQuizResults РІРѕР·РІСЂР°С‰Р°РµС‚ РїР°РіРёРЅРёСЂРѕРІР°РЅРЅС‹Р№ СЃРїРёСЃРѕРє СЂРµР·СѓР»СЊС‚Р°С‚РѕРІ РґР»СЏ РІРёРєС‚РѕСЂРёРЅС‹
 // Р’РќРРњРђРќРР•: Р­С‚Р° С„СѓРЅРєС†РёСЏ Р±РѕР»СЊС€Рµ РќР• РІС‹Р·С‹РІР°РµС‚ CalculateRanks РЅР°РїСЂСЏРјСѓСЋ.
 // CalculateRanks С‚РµРїРµСЂСЊ РІС‹Р·С‹РІР°РµС‚СЃСЏ РІ DetermineWinnersAndAllocatePrizes.
-func (s *ResultService) GetQuizResults(quizID uint, page, pageSize int) ([]entity.Result, int64, error) {
+//
+// Без fullAccess (администратор) применяются настройки приватности игроков (applyResultsPrivacy).
+func (s *ResultService) GetQuizResults(quizID uint, page, pageSize int, fullAccess bool) ([]entity.Result, int64, error) {
 	// Р’Р°Р»РёРґР°С†РёСЏ РїР°СЂР°РјРµС‚СЂРѕРІ РїР°РіРёРЅР°С†РёРё (РѕРїС†РёРѕРЅР°Р»СЊРЅРѕ, РЅРѕ СЂРµРєРѕРјРµРЅРґСѓРµС‚СЃСЏ)
 	if page < 1 {
 		page = 1
@@ -190,6 +351,12 @@ func (s *ResultService) GetQuizResults(quizID uint, page, pageSize int) ([]entit
 		return nil, 0, err // РџСЂРѕСЃС‚Рѕ РїСЂРѕР±СЂР°СЃС‹РІР°РµРј РѕС€РёР±РєСѓ РІС‹С€Рµ
 	}
 
+	if !fullAccess {
+		if err := s.applyResultsPrivacy(results); err != nil {
+			return nil, 0, err
+		}
+	}
+
 	return results, total, nil
 }
 
@@ -260,87 +427,125 @@ func (s *ResultService) DetermineWinnersAndAllocatePrizes(ctx context.Context, q
 	}
 
 	// 1Р°. Р Р°СЃСЃС‡РёС‚С‹РІР°РµРј Рё СЃРѕС…СЂР°РЅСЏРµРј СЂР°РЅРіРё Р’РќРЈРўР Р С‚СЂР°РЅР·Р°РєС†РёРё
-	if err = s.resultRepo.CalculateRanks(tx, quizID); err != nil {
+	if err = s.resultRepo.CalculateRanks(tx, quizID, quiz.EffectiveTieBreak()); err != nil {
 		log.Printf("[ResultService] РћС€РёР±РєР° РїСЂРё СЂР°СЃС‡РµС‚Рµ СЂР°РЅРіРѕРІ РґР»СЏ РІРёРєС‚РѕСЂРёРЅС‹ #%d РІ С‚СЂР°РЅР·Р°РєС†РёРё: %v", quizID, err)
 		tx.Rollback()
 		return fmt.Errorf("РѕС€РёР±РєР° СЂР°СЃС‡РµС‚Р° СЂР°РЅРіРѕРІ: %w", err)
 	}
 	log.Printf("[ResultService] Р Р°РЅРіРё РґР»СЏ РІРёРєС‚РѕСЂРёРЅС‹ #%d СѓСЃРїРµС€РЅРѕ СЂР°СЃСЃС‡РёС‚Р°РЅС‹ Рё СЃРѕС…СЂР°РЅРµРЅС‹ РІ С‚СЂР°РЅР·Р°РєС†РёРё.", quizID)
 
-	// 1Р±. РћРїСЂРµРґРµР»СЏРµРј РїРѕР±РµРґРёС‚РµР»РµР№, СЂР°СЃСЃС‡РёС‚С‹РІР°РµРј РїСЂРёР·С‹ Рё РѕР±РЅРѕРІР»СЏРµРј СЃС‚Р°С‚СѓСЃ РІ Р‘Р” Р’РќРЈРўР Р С‚СЂР°РЅР·Р°РєС†РёРё
-	winnerIDs, prizePerWinner, err = s.resultRepo.FindAndUpdateWinners(tx, quizID, totalQuestions, totalPrizeFund)
-	if err != nil {
-		log.Printf("[ResultService] РћС€РёР±РєР° РїСЂРё РѕРїСЂРµРґРµР»РµРЅРёРё/РѕР±РЅРѕРІР»РµРЅРёРё РїРѕР±РµРґРёС‚РµР»РµР№ РґР»СЏ РІРёРєС‚РѕСЂРёРЅС‹ #%d РІ С‚СЂР°РЅР·Р°РєС†РёРё: %v", quizID, err)
-		tx.Rollback()
-		return fmt.Errorf("РѕС€РёР±РєР° РѕРїСЂРµРґРµР»РµРЅРёСЏ РїРѕР±РµРґРёС‚РµР»РµР№: %w", err)
-	}
-	winnersCount := len(winnerIDs)
-	log.Printf("[ResultService] РќР°Р№РґРµРЅРѕ Рё РѕР±РЅРѕРІР»РµРЅРѕ %d РїРѕР±РµРґРёС‚РµР»РµР№ РґР»СЏ РІРёРєС‚РѕСЂРёРЅС‹ #%d РІ С‚СЂР°РЅР·Р°РєС†РёРё. РџСЂРёР· РЅР° РїРѕР±РµРґРёС‚РµР»СЏ: %d.", winnersCount, quizID, prizePerWinner)
-	if s.requireVerifiedForPrizes && winnersCount > 0 {
-		var verifiedWinnerIDs []uint
-		if err = tx.Model(&entity.User{}).
-			Where("id IN ? AND email_verified_at IS NOT NULL", winnerIDs).
-			Pluck("id", &verifiedWinnerIDs).Error; err != nil {
+	var winnersCount int
+	var prizes map[uint]int
+	if len(quiz.PrizeLadder) > 0 {
+		// 1б. Лестница призов: фонд распределяется по местам финишировавших, а не поровну
+		winnerIDs, prizes, err = s.allocateLadderPrizes(tx, quiz, totalPrizeFund)
+		if err != nil {
+			log.Printf("[ResultService] Ошибка распределения фонда викторины #%d по лестнице призов: %v", quizID, err)
 			tx.Rollback()
-			return fmt.Errorf("failed to apply verified-email gate to winners: %w", err)
+			return fmt.Errorf("failed to allocate prize ladder: %w", err)
 		}
-
-		verifiedSet := make(map[uint]struct{}, len(verifiedWinnerIDs))
-		for _, id := range verifiedWinnerIDs {
-			verifiedSet[id] = struct{}{}
+		winnersCount = len(winnerIDs)
+		log.Printf("[ResultService] Викторина #%d: фонд %d распределён по лестнице призов между %d игроками", quizID, totalPrizeFund, winnersCount)
+	} else {
+		// 1Р±. РћРїСЂРµРґРµР»СЏРµРј РїРѕР±РµРґРёС‚РµР»РµР№, СЂР°СЃСЃС‡РёС‚С‹РІР°РµРј РїСЂРёР·С‹ Рё РѕР±РЅРѕРІР»СЏРµРј СЃС‚Р°С‚СѓСЃ РІ Р‘Р” Р’РќРЈРўР Р С‚СЂР°РЅР·Р°РєС†РёРё
+		criteria := repository.WinnerCriteria{
+			GameMode:      quiz.EffectiveGameMode(),
+			QuestionCount: totalQuestions,
+			TieBreak:      quiz.EffectiveTieBreak(),
 		}
-		ineligibleIDs := make([]uint, 0)
-		for _, id := range winnerIDs {
-			if _, ok := verifiedSet[id]; !ok {
-				ineligibleIDs = append(ineligibleIDs, id)
-			}
+		winnerIDs, prizePerWinner, err = s.resultRepo.FindAndUpdateWinners(tx, quizID, criteria, totalPrizeFund)
+		if err != nil {
+			log.Printf("[ResultService] РћС€РёР±РєР° РїСЂРё РѕРїСЂРµРґРµР»РµРЅРёРё/РѕР±РЅРѕРІР»РµРЅРёРё РїРѕР±РµРґРёС‚РµР»РµР№ РґР»СЏ РІРёРєС‚РѕСЂРёРЅС‹ #%d РІ С‚СЂР°РЅР·Р°РєС†РёРё: %v", quizID, err)
+			tx.Rollback()
+			return fmt.Errorf("РѕС€РёР±РєР° РѕРїСЂРµРґРµР»РµРЅРёСЏ РїРѕР±РµРґРёС‚РµР»РµР№: %w", err)
 		}
-
-		if len(ineligibleIDs) > 0 {
-			if err = tx.Model(&entity.Result{}).
-				Where("quiz_id = ? AND user_id IN ?", quizID, ineligibleIDs).
-				Updates(map[string]interface{}{"is_winner": false, "prize_fund": 0}).Error; err != nil {
+		winnersCount = len(winnerIDs)
+		log.Printf("[ResultService] РќР°Р№РґРµРЅРѕ Рё РѕР±РЅРѕРІР»РµРЅРѕ %d РїРѕР±РµРґРёС‚РµР»РµР№ РґР»СЏ РІРёРєС‚РѕСЂРёРЅС‹ #%d РІ С‚СЂР°РЅР·Р°РєС†РёРё. РџСЂРёР· РЅР° РїРѕР±РµРґРёС‚РµР»СЏ: %d.", winnersCount, quizID, prizePerWinner)
+		// Аккаунты с теневым баном, без подтверждённого email (если это требуется) и из стран
+		// вне allowed_countries викторины не получают приз
+		if winnersCount > 0 {
+			var eligibleWinnerIDs []uint
+			if err = s.prizeEligibleUsers(tx, quiz, winnerIDs).Pluck("id", &eligibleWinnerIDs).Error; err != nil {
 				tx.Rollback()
-				return fmt.Errorf("failed to exclude unverified winners: %w", err)
+				return fmt.Errorf("failed to apply prize eligibility gate to winners: %w", err)
 			}
-		}
 
-		if len(verifiedWinnerIDs) == 0 {
-			winnerIDs = []uint{}
-			prizePerWinner = 0
-			winnersCount = 0
-		} else {
-			recalculatedPrize := 0
-			if totalPrizeFund > 0 {
-				recalculatedPrize = totalPrizeFund / len(verifiedWinnerIDs)
+			eligibleSet := make(map[uint]struct{}, len(eligibleWinnerIDs))
+			for _, id := range eligibleWinnerIDs {
+				eligibleSet[id] = struct{}{}
 			}
-			if err = tx.Model(&entity.Result{}).
-				Where("quiz_id = ? AND user_id IN ?", quizID, verifiedWinnerIDs).
-				Updates(map[string]interface{}{"is_winner": true, "prize_fund": recalculatedPrize}).Error; err != nil {
-				tx.Rollback()
-				return fmt.Errorf("failed to update verified winners prize: %w", err)
+			ineligibleIDs := make([]uint, 0)
+			for _, id := range winnerIDs {
+				if _, ok := eligibleSet[id]; !ok {
+					ineligibleIDs = append(ineligibleIDs, id)
+				}
 			}
 
-			winnerIDs = verifiedWinnerIDs
-			prizePerWinner = recalculatedPrize
-			winnersCount = len(winnerIDs)
-		}
+			if len(ineligibleIDs) > 0 {
+				if err = tx.Model(&entity.Result{}).
+					Where("quiz_id = ? AND user_id IN ?", quizID, ineligibleIDs).
+					Updates(map[string]interface{}{"is_winner": false, "prize_fund": 0}).Error; err != nil {
+					tx.Rollback()
+					return fmt.Errorf("failed to exclude ineligible winners: %w", err)
+				}
 
-		log.Printf("[ResultService] Email verification gate applied for quiz #%d. Eligible winners: %d, prize per winner: %d", quizID, winnersCount, prizePerWinner)
+				if len(eligibleWinnerIDs) == 0 {
+					winnerIDs = []uint{}
+					prizePerWinner = 0
+					winnersCount = 0
+				} else {
+					recalculatedPrize := 0
+					if totalPrizeFund > 0 {
+						recalculatedPrize = totalPrizeFund / len(eligibleWinnerIDs)
+					}
+					if err = tx.Model(&entity.Result{}).
+						Where("quiz_id = ? AND user_id IN ?", quizID, eligibleWinnerIDs).
+						Updates(map[string]interface{}{"is_winner": true, "prize_fund": recalculatedPrize}).Error; err != nil {
+						tx.Rollback()
+						return fmt.Errorf("failed to update eligible winners prize: %w", err)
+					}
+
+					winnerIDs = eligibleWinnerIDs
+					prizePerWinner = recalculatedPrize
+					winnersCount = len(winnerIDs)
+				}
+
+				log.Printf("[ResultService] Prize eligibility gate applied for quiz #%d. Excluded: %d, eligible winners: %d, prize per winner: %d", quizID, len(ineligibleIDs), winnersCount, prizePerWinner)
+			}
+		}
+		prizes = equalPrizes(winnerIDs, prizePerWinner)
 	}
 	// 1РІ. РћР±РЅРѕРІР»СЏРµРј СЃС‚Р°С‚РёСЃС‚РёРєСѓ РїРѕР»СЊР·РѕРІР°С‚РµР»РµР№-РїРѕР±РµРґРёС‚РµР»РµР№ Р’РќРЈРўР Р С‚СЂР°РЅР·Р°РєС†РёРё (РµСЃР»Рё РµСЃС‚СЊ РїРѕР±РµРґРёС‚РµР»Рё)
 	if winnersCount > 0 && prizePerWinner >= 0 { // Р”РѕР±Р°РІРёРј РїСЂРѕРІРµСЂРєСѓ РЅР° РЅРµРѕС‚СЂРёС†Р°С‚РµР»СЊРЅС‹Р№ РїСЂРёР·
-		if err = tx.Model(&entity.User{}).Where("id IN ?", winnerIDs).Updates(map[string]interface{}{
-			"wins_count":      gorm.Expr("wins_count + ?", 1),
-			"total_prize_won": gorm.Expr("total_prize_won + ?", prizePerWinner),
-		}).Error; err != nil {
-			log.Printf("[ResultService] РћС€РёР±РєР° РїСЂРё РѕР±РЅРѕРІР»РµРЅРёРё СЃС‚Р°С‚РёСЃС‚РёРєРё РїРѕР±РµРґРёС‚РµР»РµР№ (wins_count, total_prize_won) РґР»СЏ РІРёРєС‚РѕСЂРёРЅС‹ #%d РІ С‚СЂР°РЅР·Р°РєС†РёРё: %v", quizID, err)
-			tx.Rollback()
-			return fmt.Errorf("РѕС€РёР±РєР° РѕР±РЅРѕРІР»РµРЅРёСЏ СЃС‚Р°С‚РёСЃС‚РёРєРё РїРѕР±РµРґРёС‚РµР»РµР№: %w", err)
+		// При лестнице призов суммы различаются: обновляем победителей группами с одинаковым призом
+		for _, group := range groupPrizes(winnerIDs, prizes) {
+			if err = tx.Model(&entity.User{}).Where("id IN ?", group.UserIDs).Updates(map[string]interface{}{
+				"wins_count":      gorm.Expr("wins_count + ?", 1),
+				"total_prize_won": gorm.Expr("total_prize_won + ?", group.Amount),
+			}).Error; err != nil {
+				log.Printf("[ResultService] РћС€РёР±РєР° РїСЂРё РѕР±РЅРѕРІР»РµРЅРёРё СЃС‚Р°С‚РёСЃС‚РёРєРё РїРѕР±РµРґРёС‚РµР»РµР№ (wins_count, total_prize_won) РґР»СЏ РІРёРєС‚РѕСЂРёРЅС‹ #%d РІ С‚СЂР°РЅР·Р°РєС†РёРё: %v", quizID, err)
+				tx.Rollback()
+				return fmt.Errorf("РѕС€РёР±РєР° РѕР±РЅРѕРІР»РµРЅРёСЏ СЃС‚Р°С‚РёСЃС‚РёРєРё РїРѕР±РµРґРёС‚РµР»РµР№: %w", err)
+			}
 		}
 		log.Printf("[ResultService] РЎС‚Р°С‚РёСЃС‚РёРєР° РґР»СЏ %d РїРѕР±РµРґРёС‚РµР»РµР№ РІРёРєС‚РѕСЂРёРЅС‹ #%d СѓСЃРїРµС€РЅРѕ РѕР±РЅРѕРІР»РµРЅР° РІ С‚СЂР°РЅР·Р°РєС†РёРё.", winnersCount, quizID)
 	}
 
+	// Победители, подключавшиеся через VPN, прокси или из дата-центров, уходят на ручную проверку
+	heldIDs, err := s.reviewHighRiskWinners(tx, quizID, winnerIDs, prizes)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Доменные события фиксируются вместе с итогами викторины
+	if s.eventBus != nil {
+		if err = s.emitQuizCompleted(tx, quiz, winnerIDs, prizePerWinner, prizes); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
 	// === РљРѕРјРјРёС‚ С‚СЂР°РЅР·Р°РєС†РёРё ===
 	if err = tx.Commit().Error; err != nil {
 		log.Printf("[ResultService] РћС€РёР±РєР° РєРѕРјРјРёС‚Р° С‚СЂР°РЅР·Р°РєС†РёРё РґР»СЏ РІРёРєС‚РѕСЂРёРЅС‹ #%d: %v", quizID, err)
@@ -350,16 +555,116 @@ func (s *ResultService) DetermineWinnersAndAllocatePrizes(ctx context.Context, q
 	// 2. РћС‚РїСЂР°РІР»СЏРµРј WebSocket-СЃРѕРѕР±С‰РµРЅРёРµ Рѕ РґРѕСЃС‚СѓРїРЅРѕСЃС‚Рё СЂРµР·СѓР»СЊС‚Р°С‚РѕРІ (РџРћРЎР›Р• РєРѕРјРјРёС‚Р°)
 	s.sendResultsAvailableNotification(quizID)
 
+	// Статистика победителей изменилась: закешированный лидерборд устарел
+	if s.httpCache != nil {
+		s.httpCache.Invalidate(httpcache.NamespaceLeaderboard, httpcache.NamespaceQuizzes)
+	}
+
+	// Заявки на призы либо прямое зачисление в кошельки (идемпотентно по викторине и пользователю).
+	// Выплата победителям на проверке задерживается до решения администратора (IPRiskService).
+	for _, group := range groupPrizes(winnerIDs, prizes) {
+		if payable := withoutUsers(group.UserIDs, heldIDs); len(payable) > 0 {
+			if s.prizeClaimService != nil {
+				s.prizeClaimService.CreateForWinners(quizID, quiz.Title, payable, group.Amount)
+			} else if s.walletService != nil {
+				s.walletService.CreditPrizes(quizID, payable, group.Amount)
+			}
+		}
+
+		// Push-уведомления победителям (асинхронно, через очередь сервиса)
+		if s.pushService != nil {
+			s.pushService.NotifyQuizWinners(quizID, quiz.Title, group.UserIDs, group.Amount)
+		}
+	}
+
+	// In-app уведомления участникам и победителям (асинхронно, чтобы не задерживать финализацию).
+	// С шиной событий их отправляет подписчик quiz.completed.
+	if s.eventBus != nil {
+		s.eventBus.Notify()
+	} else if s.notificationService != nil {
+		go func() {
+			if err := s.createResultNotifications(quizID, quiz.Title, winnerIDs, prizes); err != nil {
+				log.Printf("[ResultService] %v", err)
+			}
+		}()
+	}
+
 	log.Printf("[ResultService] Р¤РёРЅР°Р»РёР·Р°С†РёСЏ СЂРµР·СѓР»СЊС‚Р°С‚РѕРІ РґР»СЏ РІРёРєС‚РѕСЂРёРЅС‹ #%d СѓСЃРїРµС€РЅРѕ Р·Р°РІРµСЂС€РµРЅР°.", quizID)
 	return nil
 }
 
+// emitQuizCompleted записывает в outbox quiz.completed и prize.awarded для каждого победителя
+func (s *ResultService) emitQuizCompleted(tx *gorm.DB, quiz *entity.Quiz, winnerIDs []uint, prizePerWinner int, prizes map[uint]int) error {
+	payload := entity.QuizCompletedPayload{
+		QuizID:         quiz.ID,
+		Title:          quiz.Title,
+		ScheduledTime:  quiz.ScheduledTime,
+		PrizeFund:      quiz.PrizeFund,
+		WinnerIDs:      winnerIDs,
+		PrizePerWinner: prizePerWinner,
+	}
+	if len(quiz.PrizeLadder) > 0 {
+		payload.Prizes = prizes
+	}
+	if err := s.eventBus.Emit(tx, entity.EventQuizCompleted, payload); err != nil {
+		return fmt.Errorf("failed to emit quiz completed event: %w", err)
+	}
+	for _, userID := range winnerIDs {
+		if err := s.eventBus.Emit(tx, entity.EventPrizeAwarded, entity.PrizeAwardedPayload{
+			QuizID: quiz.ID,
+			UserID: userID,
+			Amount: prizes[userID],
+		}); err != nil {
+			return fmt.Errorf("failed to emit prize awarded event: %w", err)
+		}
+	}
+	return nil
+}
+
+// handleQuizCompleted — подписчик quiz.completed: in-app уведомления о результатах
+func (s *ResultService) handleQuizCompleted(ctx context.Context, event *entity.OutboxEvent) error {
+	if s.notificationService == nil {
+		return nil
+	}
+	var payload entity.QuizCompletedPayload
+	if err := event.Decode(&payload); err != nil {
+		return err
+	}
+	prizes := payload.Prizes
+	if prizes == nil {
+		prizes = equalPrizes(payload.WinnerIDs, payload.PrizePerWinner)
+	}
+	return s.createResultNotifications(payload.QuizID, payload.Title, payload.WinnerIDs, prizes)
+}
+
+// createResultNotifications создает in-app уведомления о результатах для всех участников и о выигрыше для победителей
+func (s *ResultService) createResultNotifications(quizID uint, quizTitle string, winnerIDs []uint, prizes map[uint]int) error {
+	results, err := s.resultRepo.GetAllQuizResults(quizID)
+	if err != nil {
+		return fmt.Errorf("failed to load participants of quiz #%d for notifications: %w", quizID, err)
+	}
+
+	participantIDs := make([]uint, 0, len(results))
+	for _, r := range results {
+		participantIDs = append(participantIDs, r.UserID)
+	}
+	s.notificationService.NotifyResultsAvailable(quizID, quizTitle, participantIDs)
+
+	for _, group := range groupPrizes(winnerIDs, prizes) {
+		s.notificationService.NotifyPrizeWon(quizID, quizTitle, group.UserIDs, group.Amount)
+	}
+	return nil
+}
+
 // sendResultsAvailableNotification - РІСЃРїРѕРјРѕРіР°С‚РµР»СЊРЅР°СЏ С„СѓРЅРєС†РёСЏ РґР»СЏ РѕС‚РїСЂР°РІРєРё WS СѓРІРµРґРѕРјР»РµРЅРёСЏ
 func (s *ResultService) sendResultsAvailableNotification(quizID uint) {
 	if s.wsManager != nil {
 		resultsAvailableEvent := map[string]interface{}{
 			"quiz_id": quizID,
 		}
+		if sponsor := s.resultsSponsor(quizID); sponsor != nil {
+			resultsAvailableEvent["sponsor"] = sponsor
+		}
 		fullEvent := map[string]interface{}{ // РСЃРїРѕР»СЊР·СѓРµРј СЃС‚Р°РЅРґР°СЂС‚РЅСѓСЋ СЃС‚СЂСѓРєС‚СѓСЂСѓ СЃРѕР±С‹С‚РёСЏ
 			"type": "quiz:results_available",
 			"data": resultsAvailableEvent,
@@ -375,6 +680,19 @@ func (s *ResultService) sendResultsAvailableNotification(quizID uint) {
 	}
 }
 
+// resultsSponsor возвращает брендинг спонсора для события quiz:results_available и учитывает показ
+func (s *ResultService) resultsSponsor(quizID uint) *entity.SponsorBranding {
+	if s.sponsorService == nil {
+		return nil
+	}
+	quiz, err := s.quizRepo.GetByID(quizID)
+	if err != nil {
+		log.Printf("[ResultService] WARNING: Не удалось загрузить викторину #%d для брендинга спонсора: %v", quizID, err)
+		return nil
+	}
+	return s.sponsorService.SponsorImpression(quiz, entity.AdPlacementSponsorResults, s.wsManager.GetSubscriberCount(quizID))
+}
+
 // GetQuizWinners РІРѕР·РІСЂР°С‰Р°РµС‚ СЃРїРёСЃРѕРє РїРѕР±РµРґРёС‚РµР»РµР№ РІРёРєС‚РѕСЂРёРЅС‹
 func (s *ResultService) GetQuizWinners(quizID uint) ([]entity.Result, error) {
 	return s.resultRepo.GetQuizWinners(quizID)
@@ -386,6 +704,11 @@ type QuizStatistics struct {
 	TotalParticipants      int                    `json:"total_participants"`
 	TotalWinners           int                    `json:"total_winners"`
 	TotalEliminated        int                    `json:"total_eliminated"`
+	RevivedCount           int                    `json:"revived_count"`                      // игроки, вернувшиеся по второму шансу
+	TieBreak               string                 `json:"tie_break"`                          // способ разрешения ничьей между победителями
+	ScoringStrategy        string                 `json:"scoring_strategy"`                   // стратегия начисления очков
+	TieBreakCandidates     int                    `json:"tie_break_candidates,omitempty"`     // сколько игроков делили первое место до разрешения ничьей
+	WinningResponseTimeMs  *int64                 `json:"winning_response_time_ms,omitempty"` // суммарное время верных ответов победителей
 	AvgResponseTimeMs      float64                `json:"avg_response_time_ms"`
 	AvgCorrectAnswers      float64                `json:"avg_correct_answers"`
 	EliminationsByQ        []QuestionElimination  `json:"eliminations_by_question"`
@@ -434,7 +757,9 @@ func (s *ResultService) CalculateQuizStatistics(quizID uint) (*QuizStatistics, e
 	}
 
 	stats := &QuizStatistics{
-		QuizID: quizID,
+		QuizID:          quizID,
+		TieBreak:        quiz.EffectiveTieBreak(),
+		ScoringStrategy: quiz.EffectiveScoringStrategy(),
 	}
 
 	// 1. РџРѕР»СѓС‡Р°РµРј РѕР±С‰РµРµ РєРѕР»РёС‡РµСЃС‚РІРѕ СѓС‡Р°СЃС‚РЅРёРєРѕРІ Рё РїРѕР±РµРґРёС‚РµР»РµР№ РёР· results
@@ -442,12 +767,14 @@ func (s *ResultService) CalculateQuizStatistics(quizID uint) (*QuizStatistics, e
 		Total      int
 		Winners    int
 		Eliminated int
+		Revived    int
 	}
 	s.db.Table("results").
 		Select(`
 			COUNT(*) as total,
 			COUNT(*) FILTER (WHERE is_winner = true) as winners,
-			COUNT(*) FILTER (WHERE is_eliminated = true) as eliminated
+			COUNT(*) FILTER (WHERE is_eliminated = true) as eliminated,
+			COUNT(*) FILTER (WHERE revived = true) as revived
 		`).
 		Where("quiz_id = ?", quizID).
 		Scan(&participantStats)
@@ -455,6 +782,28 @@ func (s *ResultService) CalculateQuizStatistics(quizID uint) (*QuizStatistics, e
 	stats.TotalParticipants = participantStats.Total
 	stats.TotalWinners = participantStats.Winners
 	stats.TotalEliminated = participantStats.Eliminated
+	stats.RevivedCount = participantStats.Revived
+
+	// Разрешение ничьей: время победителей и число игроков с тем же числом верных ответов и очками
+	if stats.TieBreak == entity.TieBreakResponseTime && stats.TotalWinners > 0 {
+		var winning struct {
+			CorrectAnswers      int
+			Score               int
+			TotalResponseTimeMs int64
+		}
+		s.db.Table("results").
+			Select("correct_answers, score, total_response_time_ms").
+			Where("quiz_id = ? AND is_winner = true", quizID).
+			Order("total_response_time_ms ASC").
+			Limit(1).
+			Scan(&winning)
+		stats.WinningResponseTimeMs = &winning.TotalResponseTimeMs
+		var candidates int64
+		s.db.Table("results").
+			Where("quiz_id = ? AND is_eliminated = false AND correct_answers = ? AND score = ?", quizID, winning.CorrectAnswers, winning.Score).
+			Count(&candidates)
+		stats.TieBreakCandidates = int(candidates)
+	}
 
 	// 2. РЎСЂРµРґРЅРµРµ РІСЂРµРјСЏ РѕС‚РІРµС‚Р° Рё РїСЂР°РІРёР»СЊРЅС‹С… РѕС‚РІРµС‚РѕРІ
 	var avgStats struct {
@@ -469,41 +818,20 @@ func (s *ResultService) CalculateQuizStatistics(quizID uint) (*QuizStatistics, e
 		Scan(&avgStats)
 	stats.AvgCorrectAnswers = avgStats.AvgCorrect
 
-	// РЎСЂРµРґРЅРµРµ РІСЂРµРјСЏ РёР· user_answers
-	s.db.Table("user_answers").
-		Select("AVG(response_time_ms)").
-		Where("quiz_id = ? AND response_time_ms > 0", quizID).
-		Scan(&stats.AvgResponseTimeMs)
-
-	// 3. Р’С‹Р±С‹С‚РёСЏ РїРѕ РІРѕРїСЂРѕСЃР°Рј СЃ GROUP BY (СЂР°СЃС€РёСЂРµРЅРЅР°СЏ СЃС‚Р°С‚РёСЃС‚РёРєР°)
-	type elimByQ struct {
-		QuestionID      uint
-		EliminatedCount int
-		ByTimeout       int
-		ByWrongAnswer   int
-		AvgRespMs       float64
-		TotalAnswers    int // NEW: РІСЃРµРіРѕ РѕС‚РІРµС‚РѕРІ
-		PassedCount     int // NEW: РїСЂРѕС€РµРґС€РёС… (РїСЂР°РІРёР»СЊРЅРѕ + РІРѕРІСЂРµРјСЏ)
-	}
-	var eliminations []elimByQ
+	// Среднее время ответа и агрегаты по вопросам — из источника статистики (PostgreSQL или хранилище аналитики)
+	stats.AvgResponseTimeMs, err = s.statsRepo.QuizAvgResponseTime(quizID)
+	if err != nil {
+		return nil, err
+	}
 
-	s.db.Table("user_answers").
-		Select(`
-			question_id,
-			COUNT(*) FILTER (WHERE is_eliminated = true) as eliminated_count,
-			COUNT(*) FILTER (WHERE elimination_reason IN ('time_exceeded', 'no_answer_timeout')) as by_timeout,
-			COUNT(*) FILTER (WHERE elimination_reason = 'incorrect_answer') as by_wrong_answer,
-			AVG(response_time_ms) FILTER (WHERE response_time_ms > 0) as avg_resp_ms,
-			COUNT(*) as total_answers,
-			COUNT(*) FILTER (WHERE is_correct = true AND is_eliminated = false) as passed_count
-		`).
-		Where("quiz_id = ?", quizID).
-		Group("question_id").
-		Order("question_id").
-		Scan(&eliminations)
+	// 3. Выбытия по вопросам (расширенная статистика)
+	eliminations, err := s.statsRepo.QuizQuestionStats(quizID)
+	if err != nil {
+		return nil, err
+	}
 
-	// РљР°СЂС‚Р° Р°РіСЂРµРіР°С‚РѕРІ РїРѕ РІРѕРїСЂРѕСЃСѓ (РїРѕ РѕС‚РІРµС‚Р°Рј РїРѕР»СЊР·РѕРІР°С‚РµР»РµР№)
-	aggregatesByQuestion := make(map[uint]elimByQ, len(eliminations))
+	// Карта агрегатов по вопросу (по ответам пользователей)
+	aggregatesByQuestion := make(map[uint]repository.QuestionAnswerStats, len(eliminations))
 	for _, e := range eliminations {
 		aggregatesByQuestion[e.QuestionID] = e
 	}
@@ -568,7 +896,7 @@ func (s *ResultService) CalculateQuizStatistics(quizID uint) (*QuizStatistics, e
 				EliminatedCount: agg.EliminatedCount,
 				ByTimeout:       agg.ByTimeout,
 				ByWrongAnswer:   agg.ByWrongAnswer,
-				AvgResponseMs:   agg.AvgRespMs,
+				AvgResponseMs:   agg.AvgResponseMs,
 				Difficulty:      difficulty,
 				PassRate:        passRate,
 				TotalAnswers:    agg.TotalAnswers,
@@ -647,7 +975,7 @@ func (s *ResultService) CalculateQuizStatistics(quizID uint) (*QuizStatistics, e
 				EliminatedCount: e.EliminatedCount,
 				ByTimeout:       e.ByTimeout,
 				ByWrongAnswer:   e.ByWrongAnswer,
-				AvgResponseMs:   e.AvgRespMs,
+				AvgResponseMs:   e.AvgResponseMs,
 				Difficulty:      questionDifficulty[e.QuestionID],
 				PassRate:        passRate,
 				TotalAnswers:    e.TotalAnswers,
@@ -1,12 +1,15 @@
 package service
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
 	"gorm.io/gorm"
 )
 
@@ -24,6 +27,21 @@ func (m *MockResultRepoForResultService) SaveUserAnswer(answer *entity.UserAnswe
 	return args.Error(0)
 }
 
+func (m *MockResultRepoForResultService) SaveUserAnswersBatch(answers []entity.UserAnswer) (int64, error) {
+	args := m.Called(answers)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockResultRepoForResultService) VoidQuestionAnswers(quizID, questionID uint) (int64, error) {
+	args := m.Called(quizID, questionID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockResultRepoForResultService) ReviveEliminatedAnswer(quizID, userID uint) (int64, error) {
+	args := m.Called(quizID, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockResultRepoForResultService) GetUserAnswers(userID uint, quizID uint) ([]entity.UserAnswer, error) {
 	args := m.Called(userID, quizID)
 	if args.Get(0) == nil {
@@ -53,6 +71,14 @@ func (m *MockResultRepoForResultService) GetQuizResults(quizID uint, limit, offs
 	return args.Get(0).([]entity.Result), args.Get(1).(int64), args.Error(2)
 }
 
+func (m *MockResultRepoForResultService) GetQuizResultsAfter(quizID uint, after *repository.QuizResultCursor, limit int) ([]entity.Result, error) {
+	args := m.Called(quizID, after, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Result), args.Error(1)
+}
+
 func (m *MockResultRepoForResultService) GetAllQuizResults(quizID uint) ([]entity.Result, error) {
 	args := m.Called(quizID)
 	if args.Get(0) == nil {
@@ -77,8 +103,21 @@ func (m *MockResultRepoForResultService) GetUserResults(userID uint, limit, offs
 	return args.Get(0).([]entity.Result), args.Get(1).(int64), args.Error(2)
 }
 
-func (m *MockResultRepoForResultService) CalculateRanks(tx *gorm.DB, quizID uint) error {
-	args := m.Called(tx, quizID)
+func (m *MockResultRepoForResultService) GetUserResultsAfter(userID uint, after *repository.UserResultCursor, limit int) ([]entity.Result, error) {
+	args := m.Called(userID, after, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Result), args.Error(1)
+}
+
+func (m *MockResultRepoForResultService) GetUserBestWinStreak(userID uint) (int, error) {
+	args := m.Called(userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockResultRepoForResultService) CalculateRanks(tx *gorm.DB, quizID uint, tieBreak string) error {
+	args := m.Called(tx, quizID, tieBreak)
 	return args.Error(0)
 }
 
@@ -90,14 +129,18 @@ func (m *MockResultRepoForResultService) GetQuizWinners(quizID uint) ([]entity.R
 	return args.Get(0).([]entity.Result), args.Error(1)
 }
 
-func (m *MockResultRepoForResultService) FindAndUpdateWinners(tx *gorm.DB, quizID uint, questionCount int, totalPrizeFund int) ([]uint, int, error) {
-	args := m.Called(tx, quizID, questionCount, totalPrizeFund)
+func (m *MockResultRepoForResultService) FindAndUpdateWinners(tx *gorm.DB, quizID uint, criteria repository.WinnerCriteria, totalPrizeFund int) ([]uint, int, error) {
+	args := m.Called(tx, quizID, criteria, totalPrizeFund)
 	if args.Get(0) == nil {
 		return nil, 0, args.Error(2)
 	}
 	return args.Get(0).([]uint), args.Int(1), args.Error(2)
 }
 
+func (m *MockResultRepoForResultService) WithContext(ctx context.Context) repository.ResultRepository {
+	return m
+}
+
 // ============================================================================
 // createTestResultService создаёт ResultService для тестирования
 // ============================================================================
@@ -138,7 +181,7 @@ func TestResultService_GetQuizResults_Pagination(t *testing.T) {
 	resultService := createTestResultService(mockResultRepo)
 
 	// Act
-	results, total, err := resultService.GetQuizResults(1, 1, 3)
+	results, total, err := resultService.GetQuizResults(1, 1, 3, true)
 
 	// Assert
 	require.NoError(t, err, "Получение результатов должно быть успешным")
@@ -163,7 +206,7 @@ func TestResultService_GetQuizResults_PageValidation(t *testing.T) {
 	resultService := createTestResultService(mockResultRepo)
 
 	// Act: передаём невалидные параметры
-	results, _, err := resultService.GetQuizResults(1, 0, 0)
+	results, _, err := resultService.GetQuizResults(1, 0, 0, true)
 
 	// Assert
 	require.NoError(t, err)
@@ -185,7 +228,7 @@ func TestResultService_GetQuizResults_MaxPageSize(t *testing.T) {
 	resultService := createTestResultService(mockResultRepo)
 
 	// Act: передаём слишком большой pageSize
-	results, _, err := resultService.GetQuizResults(1, 1, 500)
+	results, _, err := resultService.GetQuizResults(1, 1, 500, true)
 
 	// Assert
 	require.NoError(t, err)
@@ -254,3 +297,38 @@ func TestResultService_GetUserResults_Pagination(t *testing.T) {
 // транзакции gorm.DB напрямую. Рекомендуется использовать testcontainers или
 // in-memory SQLite для таких тестов.
 // ============================================================================
+
+func TestResultService_GetQuizResultsByCursor(t *testing.T) {
+	mockResultRepo := new(MockResultRepoForResultService)
+	resultService := createTestResultService(mockResultRepo)
+
+	// Первая страница: запрашивается pageSize+1 записей, лишняя означает наличие следующей страницы
+	firstPage := []entity.Result{
+		{ID: 5, QuizID: 1, Score: 100, Rank: 1},
+		{ID: 7, QuizID: 1, Score: 80, Rank: 2},
+		{ID: 9, QuizID: 1, Score: 80, Rank: 2},
+	}
+	mockResultRepo.On("GetQuizResultsAfter", uint(1), (*repository.QuizResultCursor)(nil), 3).Return(firstPage, nil).Once()
+
+	results, next, err := resultService.GetQuizResultsByCursor(1, "", 2, true)
+	require.NoError(t, err)
+	assert.Len(t, results, 2)
+	require.NotEmpty(t, next, "Должен быть курсор следующей страницы")
+
+	// Вторая страница продолжается после последней записи первой
+	expectedAfter := &repository.QuizResultCursor{Rank: 2, Score: 80, ID: 7}
+	mockResultRepo.On("GetQuizResultsAfter", uint(1), expectedAfter, 3).Return(firstPage[2:], nil).Once()
+
+	results, next, err = resultService.GetQuizResultsByCursor(1, next, 2, true)
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Empty(t, next, "На последней странице курсор пуст")
+	mockResultRepo.AssertExpectations(t)
+}
+
+func TestResultService_GetQuizResultsByCursor_InvalidCursor(t *testing.T) {
+	resultService := createTestResultService(new(MockResultRepoForResultService))
+
+	_, _, err := resultService.GetQuizResultsByCursor(1, "not a cursor", 10, true)
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// QuizReminderNotifier напоминает записавшимся о скором начале викторины (реализуется NotificationService)
+type QuizReminderNotifier interface {
+	NotifyQuizReminder(quizID uint, quizTitle string, userIDs []uint, startsAt time.Time)
+}
+
+// RSVPStatus — запись пользователя на викторину и общее число записавшихся
+type RSVPStatus struct {
+	QuizID    uint  `json:"quiz_id"`
+	RSVPed    bool  `json:"rsvped"`
+	RSVPCount int64 `json:"rsvp_count"`
+}
+
+// RSVPService ведёт предварительную запись на запланированные викторины: напоминает записавшимся
+// перед стартом и оценивает ожидаемую аудиторию для QuizManager
+type RSVPService struct {
+	repo     repository.QuizRSVPRepository
+	quizRepo repository.QuizRepository
+	notifier QuizReminderNotifier
+}
+
+// NewRSVPService создает сервис предварительной записи
+func NewRSVPService(repo repository.QuizRSVPRepository, quizRepo repository.QuizRepository) *RSVPService {
+	return &RSVPService{repo: repo, quizRepo: quizRepo}
+}
+
+// SetNotifier подключает напоминания записавшимся
+func (s *RSVPService) SetNotifier(notifier QuizReminderNotifier) {
+	s.notifier = notifier
+}
+
+// RSVP записывает пользователя на викторину. Записаться можно только до старта
+// запланированной викторины; повторная запись ничего не меняет.
+func (s *RSVPService) RSVP(userID, quizID uint) (*RSVPStatus, error) {
+	if err := s.checkOpen(quizID); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.Create(&entity.QuizRSVP{QuizID: quizID, UserID: userID}); err != nil {
+		return nil, err
+	}
+	return s.status(quizID, true)
+}
+
+// CancelRSVP отменяет запись пользователя до старта викторины
+func (s *RSVPService) CancelRSVP(userID, quizID uint) (*RSVPStatus, error) {
+	if err := s.checkOpen(quizID); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.Delete(quizID, userID); err != nil {
+		return nil, err
+	}
+	return s.status(quizID, false)
+}
+
+// GetStatus возвращает, записан ли пользователь, и число записавшихся
+func (s *RSVPService) GetStatus(userID, quizID uint) (*RSVPStatus, error) {
+	if _, err := s.quizRepo.GetByID(quizID); err != nil {
+		return nil, err
+	}
+	rsvped, err := s.repo.Exists(quizID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.status(quizID, rsvped)
+}
+
+// Counts возвращает число записавшихся по викторинам (для списков)
+func (s *RSVPService) Counts(quizIDs []uint) (map[uint]int64, error) {
+	return s.repo.CountByQuizIDs(quizIDs)
+}
+
+// ExpectedParticipants оценивает аудиторию викторины по числу записавшихся
+func (s *RSVPService) ExpectedParticipants(quizID uint) (int, error) {
+	counts, err := s.repo.CountByQuizIDs([]uint{quizID})
+	if err != nil {
+		return 0, err
+	}
+	return int(counts[quizID]), nil
+}
+
+// NotifyQuizStartingSoon напоминает записавшимся о скором старте (вызывается планировщиком)
+func (s *RSVPService) NotifyQuizStartingSoon(quiz *entity.Quiz) {
+	if s.notifier == nil {
+		return
+	}
+	userIDs, err := s.repo.ListUserIDs(quiz.ID)
+	if err != nil {
+		log.Printf("[RSVPService] Не удалось получить записавшихся на викторину #%d: %v", quiz.ID, err)
+		return
+	}
+	if len(userIDs) == 0 {
+		return
+	}
+	log.Printf("[RSVPService] Напоминание о викторине #%d для %d записавшихся", quiz.ID, len(userIDs))
+	s.notifier.NotifyQuizReminder(quiz.ID, quiz.Title, userIDs, quiz.ScheduledTime)
+}
+
+// checkOpen проверяет, что запись на викторину ещё открыта
+func (s *RSVPService) checkOpen(quizID uint) error {
+	quiz, err := s.quizRepo.GetByID(quizID)
+	if err != nil {
+		return err
+	}
+	if !quiz.IsScheduled() || !quiz.ScheduledTime.After(time.Now()) {
+		return fmt.Errorf("%w: rsvp is closed for quiz #%d", apperrors.ErrConflict, quizID)
+	}
+	return nil
+}
+
+func (s *RSVPService) status(quizID uint, rsvped bool) (*RSVPStatus, error) {
+	counts, err := s.repo.CountByQuizIDs([]uint{quizID})
+	if err != nil {
+		return nil, err
+	}
+	return &RSVPStatus{QuizID: quizID, RSVPed: rsvped, RSVPCount: counts[quizID]}, nil
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// fakeRSVPRepo — QuizRSVPRepository в памяти
+type fakeRSVPRepo struct {
+	rsvps map[uint][]uint // quizID -> пользователи в порядке записи
+}
+
+func newFakeRSVPRepo() *fakeRSVPRepo {
+	return &fakeRSVPRepo{rsvps: map[uint][]uint{}}
+}
+
+func (f *fakeRSVPRepo) Create(rsvp *entity.QuizRSVP) (bool, error) {
+	if exists, _ := f.Exists(rsvp.QuizID, rsvp.UserID); exists {
+		return false, nil
+	}
+	f.rsvps[rsvp.QuizID] = append(f.rsvps[rsvp.QuizID], rsvp.UserID)
+	return true, nil
+}
+
+func (f *fakeRSVPRepo) Delete(quizID, userID uint) (bool, error) {
+	users := f.rsvps[quizID]
+	for i, id := range users {
+		if id == userID {
+			f.rsvps[quizID] = append(users[:i], users[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeRSVPRepo) Exists(quizID, userID uint) (bool, error) {
+	for _, id := range f.rsvps[quizID] {
+		if id == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeRSVPRepo) CountByQuizIDs(quizIDs []uint) (map[uint]int64, error) {
+	counts := map[uint]int64{}
+	for _, quizID := range quizIDs {
+		if n := len(f.rsvps[quizID]); n > 0 {
+			counts[quizID] = int64(n)
+		}
+	}
+	return counts, nil
+}
+
+func (f *fakeRSVPRepo) ListUserIDs(quizID uint) ([]uint, error) {
+	return f.rsvps[quizID], nil
+}
+
+// recordingReminder запоминает, кому ушло напоминание
+type recordingReminder struct {
+	quizID  uint
+	userIDs []uint
+}
+
+func (r *recordingReminder) NotifyQuizReminder(quizID uint, quizTitle string, userIDs []uint, startsAt time.Time) {
+	r.quizID = quizID
+	r.userIDs = userIDs
+}
+
+func TestRSVPService_RSVPAndCancel(t *testing.T) {
+	quizRepo := new(MockQuizRepository)
+	quiz := &entity.Quiz{ID: 7, Status: entity.QuizStatusScheduled, ScheduledTime: time.Now().Add(time.Hour)}
+	quizRepo.On("GetByID", uint(7)).Return(quiz, nil)
+	svc := NewRSVPService(newFakeRSVPRepo(), quizRepo)
+
+	status, err := svc.RSVP(1, 7)
+	require.NoError(t, err)
+	assert.True(t, status.RSVPed)
+	assert.Equal(t, int64(1), status.RSVPCount)
+
+	// Повторная запись не увеличивает счётчик
+	_, err = svc.RSVP(1, 7)
+	require.NoError(t, err)
+	status, err = svc.RSVP(2, 7)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), status.RSVPCount)
+
+	status, err = svc.CancelRSVP(1, 7)
+	require.NoError(t, err)
+	assert.False(t, status.RSVPed)
+	assert.Equal(t, int64(1), status.RSVPCount)
+
+	expected, err := svc.ExpectedParticipants(7)
+	require.NoError(t, err)
+	assert.Equal(t, 1, expected)
+}
+
+func TestRSVPService_ClosedAfterStart(t *testing.T) {
+	quizRepo := new(MockQuizRepository)
+	quizRepo.On("GetByID", uint(8)).Return(&entity.Quiz{ID: 8, Status: entity.QuizStatusInProgress, ScheduledTime: time.Now().Add(-time.Minute)}, nil)
+	quizRepo.On("GetByID", uint(9)).Return(nil, apperrors.ErrNotFound)
+	svc := NewRSVPService(newFakeRSVPRepo(), quizRepo)
+
+	_, err := svc.RSVP(1, 8)
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+	_, err = svc.CancelRSVP(1, 8)
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+	_, err = svc.RSVP(1, 9)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
+
+func TestRSVPService_NotifyQuizStartingSoon(t *testing.T) {
+	repo := newFakeRSVPRepo()
+	repo.rsvps[7] = []uint{3, 5}
+	svc := NewRSVPService(repo, new(MockQuizRepository))
+	reminder := &recordingReminder{}
+	svc.SetNotifier(reminder)
+
+	svc.NotifyQuizStartingSoon(&entity.Quiz{ID: 7, Title: "Вечерняя"})
+	assert.Equal(t, uint(7), reminder.quizID)
+	assert.Equal(t, []uint{3, 5}, reminder.userIDs)
+
+	// Без записавшихся напоминание не отправляется
+	reminder.userIDs = nil
+	svc.NotifyQuizStartingSoon(&entity.Quiz{ID: 8})
+	assert.Nil(t, reminder.userIDs)
+}
@@ -0,0 +1,319 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/service/quizmanager"
+)
+
+const (
+	// secondChanceAdTTL — сколько живёт отметка о начале просмотра рекламы
+	secondChanceAdTTL = 10 * time.Minute
+	// secondChanceRevivedTTL — сколько хранится отметка об использованном втором шансе
+	secondChanceRevivedTTL = 24 * time.Hour
+)
+
+// SecondChanceLiveQuiz — ход идущей викторины (реализуется QuizManager)
+type SecondChanceLiveQuiz interface {
+	GetLiveProgress(quizID uint) (currentQuestion int, playerCount int)
+}
+
+// SecondChanceResults возвращает выбывшего игрока в результаты (реализуется ResultService)
+type SecondChanceResults interface {
+	ReviveEliminatedAnswer(ctx context.Context, quizID, userID uint) (int64, error)
+}
+
+// UserEventSender отправляет WS-событие одному пользователю
+type UserEventSender interface {
+	SendEventToUser(userID string, eventType string, data interface{}) error
+}
+
+// SecondChanceConfig — настройки второго шанса викторины
+type SecondChanceConfig struct {
+	Mode      string `json:"mode"`
+	Cost      int64  `json:"cost"`
+	AdAssetID *uint  `json:"ad_asset_id,omitempty"`
+}
+
+// SecondChanceOffer — условия второго шанса, выданные игроку перед возвращением
+type SecondChanceOffer struct {
+	QuizID         uint   `json:"quiz_id"`
+	QuestionNumber int    `json:"question_number"`
+	Method         string `json:"method"`
+	Cost           int64  `json:"cost,omitempty"`
+	AdMediaType    string `json:"ad_media_type,omitempty"`
+	AdMediaURL     string `json:"ad_media_url,omitempty"`
+	AdDurationMs   int    `json:"ad_duration_ms,omitempty"`
+	ReadyAt        int64  `json:"ready_at,omitempty"` // unix ms, после которого можно вернуться
+}
+
+// SecondChanceService возвращает выбывших игроков в викторину один раз за игру:
+// за просмотр рекламы или за очки кошелька. Вернуться можно до начала следующего вопроса.
+type SecondChanceService struct {
+	quizRepo      repository.QuizRepository
+	adAssetRepo   repository.AdAssetRepository
+	cacheRepo     repository.CacheRepository
+	liveQuiz      SecondChanceLiveQuiz
+	results       SecondChanceResults
+	events        UserEventSender
+	walletService *WalletService
+	quizCache     QuizCacheInvalidator
+}
+
+// NewSecondChanceService создает сервис второго шанса
+func NewSecondChanceService(
+	quizRepo repository.QuizRepository,
+	adAssetRepo repository.AdAssetRepository,
+	cacheRepo repository.CacheRepository,
+	liveQuiz SecondChanceLiveQuiz,
+	results SecondChanceResults,
+	events UserEventSender,
+) *SecondChanceService {
+	return &SecondChanceService{
+		quizRepo:    quizRepo,
+		adAssetRepo: adAssetRepo,
+		cacheRepo:   cacheRepo,
+		liveQuiz:    liveQuiz,
+		results:     results,
+		events:      events,
+	}
+}
+
+// SetWalletService подключает кошелёк для второго шанса за очки
+func (s *SecondChanceService) SetWalletService(walletService *WalletService) {
+	s.walletService = walletService
+}
+
+// SetQuizCache подключает сброс кеша викторины при смене настроек
+func (s *SecondChanceService) SetQuizCache(invalidator QuizCacheInvalidator) {
+	s.quizCache = invalidator
+}
+
+// Configure задаёт режим второго шанса викторины. Менять его во время и после игры нельзя.
+func (s *SecondChanceService) Configure(quizID uint, cfg SecondChanceConfig) (*entity.Quiz, error) {
+	switch cfg.Mode {
+	case entity.SecondChanceOff:
+		cfg.Cost, cfg.AdAssetID = 0, nil
+	case entity.SecondChancePoints:
+		if cfg.Cost <= 0 {
+			return nil, fmt.Errorf("%w: cost must be positive for points mode", apperrors.ErrValidation)
+		}
+		if s.walletService == nil {
+			return nil, fmt.Errorf("%w: points mode requires the wallet", apperrors.ErrValidation)
+		}
+		cfg.AdAssetID = nil
+	case entity.SecondChanceAd:
+		if cfg.AdAssetID == nil {
+			return nil, fmt.Errorf("%w: ad_asset_id is required for ad mode", apperrors.ErrValidation)
+		}
+		if _, err := s.adAssetRepo.GetByID(*cfg.AdAssetID); err != nil {
+			return nil, fmt.Errorf("%w: ad asset %d not found", apperrors.ErrValidation, *cfg.AdAssetID)
+		}
+		cfg.Cost = 0
+	default:
+		return nil, fmt.Errorf("%w: unknown second chance mode %q", apperrors.ErrValidation, cfg.Mode)
+	}
+
+	quiz, err := s.quizRepo.GetByID(quizID)
+	if err != nil {
+		return nil, err
+	}
+	if quiz.IsActive() || quiz.IsCompleted() {
+		return nil, fmt.Errorf("%w: second chance can't be changed once the quiz has started", apperrors.ErrConflict)
+	}
+	if err := s.quizRepo.UpdateSecondChance(quizID, cfg.Mode, cfg.Cost, cfg.AdAssetID); err != nil {
+		return nil, err
+	}
+	if s.quizCache != nil {
+		s.quizCache.InvalidateQuiz(quizID)
+	}
+
+	quiz.SecondChanceMode, quiz.SecondChanceCost, quiz.SecondChanceAdID = cfg.Mode, cfg.Cost, cfg.AdAssetID
+	log.Printf("[SecondChanceService] Викторина #%d: второй шанс %s (стоимость %d)", quizID, cfg.Mode, cfg.Cost)
+	return quiz, nil
+}
+
+// Start открывает возвращение: для рекламного режима отмечает начало просмотра и возвращает ролик,
+// для режима очков — стоимость.
+func (s *SecondChanceService) Start(userID, quizID uint) (*SecondChanceOffer, error) {
+	quiz, questionNumber, err := s.eligible(userID, quizID)
+	if err != nil {
+		return nil, err
+	}
+	offer := &SecondChanceOffer{QuizID: quizID, QuestionNumber: questionNumber, Method: quiz.SecondChanceMode}
+
+	if quiz.SecondChanceMode == entity.SecondChancePoints {
+		offer.Cost = quiz.SecondChanceCost
+		return offer, nil
+	}
+
+	asset, err := s.adAsset(quiz)
+	if err != nil {
+		return nil, err
+	}
+	startedAt := time.Now()
+	if err := s.cacheRepo.Set(secondChanceAdKey(quizID, userID), startedAt.UnixMilli(), secondChanceAdTTL); err != nil {
+		return nil, fmt.Errorf("store ad start: %w", err)
+	}
+	offer.AdMediaType = asset.MediaType
+	offer.AdMediaURL = asset.URL
+	offer.AdDurationMs = adDurationMs(asset)
+	offer.ReadyAt = startedAt.Add(time.Duration(offer.AdDurationMs) * time.Millisecond).UnixMilli()
+	return offer, nil
+}
+
+// Revive возвращает выбывшего игрока в викторину: снимает ключ выбывания в Redis
+// и исправляет его ответ в результатах. Второй шанс даётся один раз за викторину.
+func (s *SecondChanceService) Revive(ctx context.Context, userID, quizID uint) error {
+	quiz, questionNumber, err := s.eligible(userID, quizID)
+	if err != nil {
+		return err
+	}
+	if quiz.SecondChanceMode == entity.SecondChanceAd {
+		if err := s.checkAdWatched(quiz, userID); err != nil {
+			return err
+		}
+	}
+
+	revivedKey := quizmanager.SecondChanceRevivedKey(quizID, userID)
+	claimed, err := s.cacheRepo.SetNX(revivedKey, questionNumber, secondChanceRevivedTTL)
+	if err != nil {
+		return fmt.Errorf("claim second chance: %w", err)
+	}
+	if !claimed {
+		return fmt.Errorf("%w: second chance already used in this quiz", apperrors.ErrConflict)
+	}
+
+	if err := s.revive(ctx, quiz, userID); err != nil {
+		if errDel := s.cacheRepo.Delete(revivedKey); errDel != nil {
+			log.Printf("[SecondChanceService] WARNING: Не удалось снять отметку второго шанса %s: %v", revivedKey, errDel)
+		}
+		return err
+	}
+
+	_ = s.cacheRepo.Delete(secondChanceAdKey(quizID, userID))
+	log.Printf("[SecondChanceService] Пользователь #%d вернулся в викторину #%d на вопросе %d (%s)",
+		userID, quizID, questionNumber, quiz.SecondChanceMode)
+	if s.events != nil {
+		if err := s.events.SendEventToUser(strconv.FormatUint(uint64(userID), 10), "quiz:revived", map[string]interface{}{
+			"quiz_id":         quizID,
+			"question_number": questionNumber,
+			"method":          quiz.SecondChanceMode,
+		}); err != nil {
+			log.Printf("[SecondChanceService] Ошибка отправки quiz:revived пользователю #%d: %v", userID, err)
+		}
+	}
+	return nil
+}
+
+// revive списывает очки и снимает выбывание
+func (s *SecondChanceService) revive(ctx context.Context, quiz *entity.Quiz, userID uint) error {
+	if quiz.SecondChanceMode == entity.SecondChancePoints {
+		if s.walletService == nil {
+			return fmt.Errorf("%w: wallet is not available", apperrors.ErrConflict)
+		}
+		if err := s.walletService.ChargeSecondChance(userID, quiz.ID, quiz.SecondChanceCost); err != nil {
+			return err
+		}
+	}
+	if _, err := s.results.ReviveEliminatedAnswer(ctx, quiz.ID, userID); err != nil {
+		return fmt.Errorf("revive answer: %w", err)
+	}
+	if err := s.cacheRepo.Delete(eliminationKey(quiz.ID, userID)); err != nil {
+		return err
+	}
+	// Вернувшийся снова занимает место в викторине с ограниченной вместимостью
+	if quiz.HasCapacityLimit() {
+		return s.cacheRepo.SAdd(quizmanager.AdmittedKey(quiz.ID), userID)
+	}
+	return nil
+}
+
+// eligible проверяет, что игрок выбыл на текущем вопросе идущей викторины и ещё не возвращался
+func (s *SecondChanceService) eligible(userID, quizID uint) (*entity.Quiz, int, error) {
+	quiz, err := s.quizRepo.GetByID(quizID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !quiz.SecondChanceEnabled() {
+		return nil, 0, fmt.Errorf("%w: second chance is not available in this quiz", apperrors.ErrConflict)
+	}
+	currentQuestion, _ := s.liveQuiz.GetLiveProgress(quizID)
+	if currentQuestion == 0 {
+		return nil, 0, fmt.Errorf("%w: quiz is not running", apperrors.ErrConflict)
+	}
+
+	eliminatedOn, err := s.cacheRepo.Get(eliminationKey(quizID, userID))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil, 0, fmt.Errorf("%w: user is not eliminated", apperrors.ErrConflict)
+		}
+		return nil, 0, err
+	}
+	if eliminatedOn != strconv.Itoa(currentQuestion) {
+		return nil, 0, fmt.Errorf("%w: second chance window has closed", apperrors.ErrConflict)
+	}
+
+	used, err := s.cacheRepo.Exists(quizmanager.SecondChanceRevivedKey(quizID, userID))
+	if err != nil {
+		return nil, 0, err
+	}
+	if used {
+		return nil, 0, fmt.Errorf("%w: second chance already used in this quiz", apperrors.ErrConflict)
+	}
+	return quiz, currentQuestion, nil
+}
+
+// checkAdWatched проверяет, что с начала просмотра прошла длительность ролика
+func (s *SecondChanceService) checkAdWatched(quiz *entity.Quiz, userID uint) error {
+	startedRaw, err := s.cacheRepo.Get(secondChanceAdKey(quiz.ID, userID))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return fmt.Errorf("%w: ad has not been started", apperrors.ErrConflict)
+		}
+		return err
+	}
+	startedMs, err := strconv.ParseInt(startedRaw, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: ad has not been started", apperrors.ErrConflict)
+	}
+	asset, err := s.adAsset(quiz)
+	if err != nil {
+		return err
+	}
+	if time.Now().UnixMilli() < startedMs+int64(adDurationMs(asset)) {
+		return fmt.Errorf("%w: ad has not finished yet", apperrors.ErrConflict)
+	}
+	return nil
+}
+
+func (s *SecondChanceService) adAsset(quiz *entity.Quiz) (*entity.AdAsset, error) {
+	if quiz.SecondChanceAdID == nil {
+		return nil, fmt.Errorf("%w: second chance ad is not configured", apperrors.ErrConflict)
+	}
+	return s.adAssetRepo.GetByID(*quiz.SecondChanceAdID)
+}
+
+// adDurationMs — длительность ролика: фактическая, если известна, иначе заданная в ресурсе
+func adDurationMs(asset *entity.AdAsset) int {
+	if asset.VideoDurationMs > 0 {
+		return asset.VideoDurationMs
+	}
+	return asset.DurationSec * 1000
+}
+
+func eliminationKey(quizID, userID uint) string {
+	return fmt.Sprintf("quiz:%d:eliminated:%d", quizID, userID)
+}
+
+func secondChanceAdKey(quizID, userID uint) string {
+	return fmt.Sprintf("quiz:%d:second_chance:%d:ad_started", quizID, userID)
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/service/quizmanager"
+)
+
+// memorySecondChanceCache — строковые ключи Redis в памяти
+type memorySecondChanceCache struct {
+	repository.CacheRepository
+	values map[string]string
+}
+
+func (m *memorySecondChanceCache) Set(key string, value interface{}, expiration time.Duration) error {
+	m.values[key] = fmt.Sprint(value)
+	return nil
+}
+
+func (m *memorySecondChanceCache) Get(key string) (string, error) {
+	value, ok := m.values[key]
+	if !ok {
+		return "", apperrors.ErrNotFound
+	}
+	return value, nil
+}
+
+func (m *memorySecondChanceCache) Exists(key string) (bool, error) {
+	_, ok := m.values[key]
+	return ok, nil
+}
+
+func (m *memorySecondChanceCache) SetNX(key string, value interface{}, expiration time.Duration) (bool, error) {
+	if _, ok := m.values[key]; ok {
+		return false, nil
+	}
+	m.values[key] = fmt.Sprint(value)
+	return true, nil
+}
+
+func (m *memorySecondChanceCache) Delete(key string) error {
+	delete(m.values, key)
+	return nil
+}
+
+// stubLiveQuiz — номер текущего вопроса идущей викторины
+type stubLiveQuiz struct {
+	question int
+}
+
+func (s *stubLiveQuiz) GetLiveProgress(uint) (int, int) { return s.question, 10 }
+
+// reviveRecorder запоминает возвращённых игроков
+type reviveRecorder struct {
+	revived []uint
+}
+
+func (r *reviveRecorder) ReviveEliminatedAnswer(ctx context.Context, quizID, userID uint) (int64, error) {
+	r.revived = append(r.revived, userID)
+	return 1, nil
+}
+
+// userEventRecorder запоминает отправленные пользователям события
+type userEventRecorder struct {
+	events []string
+}
+
+func (r *userEventRecorder) SendEventToUser(userID string, eventType string, data interface{}) error {
+	r.events = append(r.events, userID+":"+eventType)
+	return nil
+}
+
+// secondChanceAdRepo отдаёт один рекламный ролик
+type secondChanceAdRepo struct {
+	stubAdAssetRepo
+	asset *entity.AdAsset
+}
+
+func (r *secondChanceAdRepo) GetByID(id uint) (*entity.AdAsset, error) {
+	if r.asset == nil || r.asset.ID != id {
+		return nil, apperrors.ErrNotFound
+	}
+	return r.asset, nil
+}
+
+type secondChanceFixture struct {
+	svc     *SecondChanceService
+	cache   *memorySecondChanceCache
+	live    *stubLiveQuiz
+	results *reviveRecorder
+	events  *userEventRecorder
+}
+
+func newSecondChanceFixture(quiz *entity.Quiz) *secondChanceFixture {
+	quizRepo := new(MockQuizRepository)
+	quizRepo.On("GetByID", quiz.ID).Return(quiz, nil)
+	quizRepo.On("UpdateSecondChance", quiz.ID, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	f := &secondChanceFixture{
+		cache:   &memorySecondChanceCache{values: make(map[string]string)},
+		live:    &stubLiveQuiz{question: 3},
+		results: &reviveRecorder{},
+		events:  &userEventRecorder{},
+	}
+	adRepo := &secondChanceAdRepo{asset: &entity.AdAsset{ID: 9, MediaType: "video", URL: "https://cdn.example/ad.mp4", DurationSec: 15}}
+	f.svc = NewSecondChanceService(quizRepo, adRepo, f.cache, f.live, f.results, f.events)
+	return f
+}
+
+func (f *secondChanceFixture) eliminate(quizID, userID uint, questionNumber int) {
+	f.cache.values[eliminationKey(quizID, userID)] = strconv.Itoa(questionNumber)
+}
+
+func TestSecondChance_AdRevive(t *testing.T) {
+	adID := uint(9)
+	f := newSecondChanceFixture(&entity.Quiz{ID: 1, Status: entity.QuizStatusInProgress, SecondChanceMode: entity.SecondChanceAd, SecondChanceAdID: &adID})
+	ctx := context.Background()
+
+	// Не выбывший игрок вернуться не может
+	assert.ErrorIs(t, f.svc.Revive(ctx, 5, 1), apperrors.ErrConflict)
+
+	f.eliminate(1, 5, 3)
+	// Без просмотра рекламы возвращения нет
+	assert.ErrorIs(t, f.svc.Revive(ctx, 5, 1), apperrors.ErrConflict)
+
+	offer, err := f.svc.Start(5, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 3, offer.QuestionNumber)
+	assert.Equal(t, 15000, offer.AdDurationMs)
+	// Ролик ещё не досмотрен
+	assert.ErrorIs(t, f.svc.Revive(ctx, 5, 1), apperrors.ErrConflict)
+
+	f.cache.values[secondChanceAdKey(1, 5)] = strconv.FormatInt(time.Now().Add(-16*time.Second).UnixMilli(), 10)
+	require.NoError(t, f.svc.Revive(ctx, 5, 1))
+	assert.Equal(t, []uint{5}, f.results.revived)
+	assert.Equal(t, []string{"5:quiz:revived"}, f.events.events)
+	_, stillEliminated := f.cache.values[eliminationKey(1, 5)]
+	assert.False(t, stillEliminated)
+
+	// Второй шанс даётся один раз за викторину
+	f.live.question = 4
+	f.eliminate(1, 5, 4)
+	_, err = f.svc.Start(5, 1)
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+	assert.Len(t, f.results.revived, 1)
+}
+
+func TestSecondChance_WindowClosesWithNextQuestion(t *testing.T) {
+	f := newSecondChanceFixture(&entity.Quiz{ID: 1, Status: entity.QuizStatusInProgress, SecondChanceMode: entity.SecondChancePoints, SecondChanceCost: 50})
+	f.eliminate(1, 5, 2)
+
+	_, err := f.svc.Start(5, 1)
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+
+	// Викторина закончилась — возвращаться некуда
+	f.live.question = 0
+	f.eliminate(1, 5, 0)
+	_, err = f.svc.Start(5, 1)
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+}
+
+func TestSecondChance_PointsChargesWallet(t *testing.T) {
+	f := newSecondChanceFixture(&entity.Quiz{ID: 1, Status: entity.QuizStatusInProgress, SecondChanceMode: entity.SecondChancePoints, SecondChanceCost: 50})
+	wallet, walletRepo := createTestWalletService(t)
+	f.svc.SetWalletService(wallet)
+	code := entity.SystemAccountSecondChance
+	walletRepo.On("GetSystemAccount", entity.SystemAccountSecondChance).Return(&entity.WalletAccount{ID: 4, Type: entity.WalletAccountTypeSystem, Code: &code}, nil)
+	f.eliminate(1, 5, 3)
+	f.eliminate(1, 6, 3)
+
+	// Не хватает очков — отметка второго шанса снимается, попытку можно повторить
+	walletRepo.On("GetOrCreateUserAccount", uint(5)).Return(&entity.WalletAccount{ID: 10, Balance: 20}, nil)
+	assert.ErrorIs(t, f.svc.Revive(context.Background(), 5, 1), ErrInsufficientFunds)
+	_, used := f.cache.values[quizmanager.SecondChanceRevivedKey(1, 5)]
+	assert.False(t, used)
+
+	walletRepo.On("GetOrCreateUserAccount", uint(6)).Return(&entity.WalletAccount{ID: 11, Balance: 100}, nil)
+	walletRepo.On("PostTransaction", mock.MatchedBy(func(txn *entity.LedgerTransaction) bool {
+		return txn.Type == entity.LedgerTxSecondChance && txn.Reference == "quiz:1:user:6" &&
+			txn.Entries[0].AccountID == 11 && txn.Entries[0].Amount == -50 && txn.Entries[1].Amount == 50
+	})).Return(nil).Once()
+	offer, err := f.svc.Start(6, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(50), offer.Cost)
+	require.NoError(t, f.svc.Revive(context.Background(), 6, 1))
+	assert.Equal(t, []uint{6}, f.results.revived)
+	walletRepo.AssertExpectations(t)
+}
+
+func TestSecondChance_Configure(t *testing.T) {
+	f := newSecondChanceFixture(&entity.Quiz{ID: 1, Status: entity.QuizStatusScheduled})
+
+	_, err := f.svc.Configure(1, SecondChanceConfig{Mode: "coins"})
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	_, err = f.svc.Configure(1, SecondChanceConfig{Mode: entity.SecondChancePoints, Cost: 0})
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	missing := uint(99)
+	_, err = f.svc.Configure(1, SecondChanceConfig{Mode: entity.SecondChanceAd, AdAssetID: &missing})
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+
+	adID := uint(9)
+	quiz, err := f.svc.Configure(1, SecondChanceConfig{Mode: entity.SecondChanceAd, Cost: 30, AdAssetID: &adID})
+	require.NoError(t, err)
+	assert.Equal(t, entity.SecondChanceAd, quiz.SecondChanceMode)
+	assert.Zero(t, quiz.SecondChanceCost)
+
+	// Во время игры правила не меняются
+	live := newSecondChanceFixture(&entity.Quiz{ID: 2, Status: entity.QuizStatusInProgress})
+	_, err = live.svc.Configure(2, SecondChanceConfig{Mode: entity.SecondChanceOff})
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/yourusername/trivia-api/internal/config"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/pkg/sharecard"
+	"github.com/yourusername/trivia-api/internal/pkg/storage"
+)
+
+const shareCardStoragePrefix = "share/"
+
+// shareCardTokenPattern — формат адреса карточки: 128 бит HMAC в hex
+var shareCardTokenPattern = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// ShareCard — ссылки на карточку результата для соцсетей
+type ShareCard struct {
+	PageURL   string             `json:"page_url"`  // страница с метаданными OpenGraph — ею делятся в соцсетях
+	ImageURL  string             `json:"image_url"` // PNG 1200×630
+	SVGURL    string             `json:"svg_url"`
+	OpenGraph ShareCardOpenGraph `json:"og"`
+}
+
+// ShareCardOpenGraph — метаданные превью ссылки на карточку
+type ShareCardOpenGraph struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Image       string `json:"image"`
+}
+
+// ShareCardService рисует карточки результатов викторины (PNG, SVG и страницу OpenGraph) и хранит
+// их в хранилище загрузок. Адрес карточки — HMAC от данных результата: его нельзя подобрать, а при
+// изменении результата (пересчёт рангов, призов) карточка рисуется заново.
+type ShareCardService struct {
+	resultRepo repository.ResultRepository
+	quizRepo   repository.QuizRepository
+	storage    storage.Storage
+	baseURL    string
+	siteName   string
+	secret     []byte
+}
+
+// NewShareCardService создает сервис карточек результатов
+func NewShareCardService(
+	resultRepo repository.ResultRepository,
+	quizRepo repository.QuizRepository,
+	store storage.Storage,
+	cfg config.ShareCardsConfig,
+) (*ShareCardService, error) {
+	if resultRepo == nil {
+		return nil, fmt.Errorf("result repository is required")
+	}
+	if quizRepo == nil {
+		return nil, fmt.Errorf("quiz repository is required")
+	}
+	if store == nil {
+		return nil, fmt.Errorf("storage is required")
+	}
+	secret := []byte(cfg.Secret)
+	if len(secret) == 0 {
+		// Без постоянного ключа адреса новых карточек меняются при перезапуске; старые ссылки продолжают работать
+		log.Printf("[ShareCardService] WARNING: shareCards.secret не задан, используется случайный ключ процесса")
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("failed to generate share card secret: %w", err)
+		}
+	}
+	siteName := cfg.SiteName
+	if siteName == "" {
+		siteName = "Trivia"
+	}
+	return &ShareCardService{
+		resultRepo: resultRepo,
+		quizRepo:   quizRepo,
+		storage:    store,
+		baseURL:    strings.TrimRight(cfg.BaseURL, "/"),
+		siteName:   siteName,
+		secret:     secret,
+	}, nil
+}
+
+// GetMyResultCard возвращает карточку результата пользователя в викторине, рисуя её при первом запросе
+func (s *ShareCardService) GetMyResultCard(ctx context.Context, userID, quizID uint) (*ShareCard, error) {
+	result, err := s.resultRepo.GetUserResult(userID, quizID)
+	if err != nil {
+		return nil, err
+	}
+	quiz, err := s.quizRepo.GetByID(quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	card := sharecard.Card{
+		SiteName:       s.siteName,
+		QuizTitle:      quiz.Title,
+		Username:       result.Username,
+		Rank:           result.Rank,
+		Score:          result.Score,
+		CorrectAnswers: result.CorrectAnswers,
+		TotalQuestions: result.TotalQuestions,
+		IsWinner:       result.IsWinner,
+		Prize:          result.PrizeFund,
+	}
+	token := s.cardToken(result, quiz)
+	pageURL := s.absoluteURL("/share/" + token)
+	imageURL := s.absoluteURL(s.storage.PublicURL(shareCardStoragePrefix + token + ".png"))
+
+	exists, err := s.cardExists(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		if err := s.renderCard(ctx, token, card, pageURL, imageURL); err != nil {
+			return nil, err
+		}
+	}
+
+	return &ShareCard{
+		PageURL:  pageURL,
+		ImageURL: imageURL,
+		SVGURL:   s.absoluteURL(s.storage.PublicURL(shareCardStoragePrefix + token + ".svg")),
+		OpenGraph: ShareCardOpenGraph{
+			Title:       fmt.Sprintf("%s — %s", card.Username, card.QuizTitle),
+			Description: card.Description(),
+			Image:       imageURL,
+		},
+	}, nil
+}
+
+// GetPage возвращает HTML-страницу карточки с метаданными OpenGraph
+func (s *ShareCardService) GetPage(ctx context.Context, token string) ([]byte, error) {
+	if !shareCardTokenPattern.MatchString(token) {
+		return nil, apperrors.ErrNotFound
+	}
+	body, err := s.storage.Get(ctx, shareCardStoragePrefix+token+".html")
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	page, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read share page: %w", err)
+	}
+	return page, nil
+}
+
+// cardToken — адрес карточки: HMAC от результата и названия викторины
+func (s *ShareCardService) cardToken(result *entity.Result, quiz *entity.Quiz) string {
+	mac := hmac.New(sha256.New, s.secret)
+	fmt.Fprintf(mac, "%d|%d|%d|%d|%d|%d|%t|%d|%s|%s",
+		result.QuizID, result.UserID, result.Rank, result.Score, result.CorrectAnswers, result.TotalQuestions,
+		result.IsWinner, result.PrizeFund, result.Username, quiz.Title)
+	return hex.EncodeToString(mac.Sum(nil))[:32]
+}
+
+// cardExists проверяет, нарисована ли карточка: страница сохраняется последней
+func (s *ShareCardService) cardExists(ctx context.Context, token string) (bool, error) {
+	body, err := s.storage.Get(ctx, shareCardStoragePrefix+token+".html")
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check share card: %w", err)
+	}
+	body.Close()
+	return true, nil
+}
+
+// renderCard рисует PNG, SVG и страницу карточки и сохраняет их в хранилище
+func (s *ShareCardService) renderCard(ctx context.Context, token string, card sharecard.Card, pageURL, imageURL string) error {
+	pngCard, err := sharecard.RenderPNG(card)
+	if err != nil {
+		return err
+	}
+	svgCard, err := sharecard.RenderSVG(card)
+	if err != nil {
+		return err
+	}
+	page, err := sharecard.RenderPage(card, sharecard.Page{PageURL: pageURL, ImageURL: imageURL})
+	if err != nil {
+		return err
+	}
+	objects := []struct {
+		ext         string
+		body        []byte
+		contentType string
+	}{
+		{".png", pngCard, "image/png"},
+		{".svg", svgCard, "image/svg+xml"},
+		{".html", page, "text/html; charset=utf-8"},
+	}
+	for _, object := range objects {
+		key := shareCardStoragePrefix + token + object.ext
+		if err := s.storage.Store(ctx, key, bytes.NewReader(object.body), int64(len(object.body)), object.contentType); err != nil {
+			return fmt.Errorf("failed to store share card %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// absoluteURL дополняет относительную ссылку публичным адресом сервера: превью в соцсетях
+// загружаются только по абсолютным ссылкам
+func (s *ShareCardService) absoluteURL(path string) string {
+	if s.baseURL == "" || strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	return s.baseURL + "/" + strings.TrimLeft(path, "/")
+}
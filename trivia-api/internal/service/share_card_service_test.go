@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/config"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/pkg/storage"
+)
+
+func newShareCardTestService(t *testing.T, result *entity.Result) (*ShareCardService, string) {
+	t.Helper()
+	dir := t.TempDir()
+	store, err := storage.NewLocalStorage(dir, "/uploads")
+	require.NoError(t, err)
+
+	resultRepo := new(MockResultRepository)
+	resultRepo.On("GetUserResult", uint(7), uint(3)).Return(result, nil)
+	quizRepo := new(MockQuizRepository)
+	quizRepo.On("GetByID", uint(3)).Return(&entity.Quiz{ID: 3, Title: "Кино"}, nil)
+
+	svc, err := NewShareCardService(resultRepo, quizRepo, store, config.ShareCardsConfig{
+		BaseURL: "https://example.com/", SiteName: "Trivia", Secret: "test-secret",
+	})
+	require.NoError(t, err)
+	return svc, dir
+}
+
+func TestShareCardService_RendersOnceAndServesPage(t *testing.T) {
+	result := &entity.Result{QuizID: 3, UserID: 7, Username: "player", Rank: 1, Score: 9, IsWinner: true, PrizeFund: 500}
+	svc, dir := newShareCardTestService(t, result)
+	ctx := context.Background()
+
+	card, err := svc.GetMyResultCard(ctx, 7, 3)
+	require.NoError(t, err)
+
+	token := filepath.Base(card.PageURL)
+	assert.Regexp(t, `^[0-9a-f]{32}$`, token)
+	assert.Equal(t, "https://example.com/share/"+token, card.PageURL)
+	assert.Equal(t, "https://example.com/uploads/share/"+token+".png", card.ImageURL)
+	assert.Equal(t, card.ImageURL, card.OpenGraph.Image)
+	for _, ext := range []string{".png", ".svg", ".html"} {
+		assert.FileExists(t, filepath.Join(dir, "share", token+ext))
+	}
+
+	page, err := svc.GetPage(ctx, token)
+	require.NoError(t, err)
+	assert.Contains(t, string(page), card.ImageURL)
+
+	// Повторный запрос берёт карточку из хранилища
+	pngPath := filepath.Join(dir, "share", token+".png")
+	require.NoError(t, os.WriteFile(pngPath, []byte("cached"), 0644))
+	again, err := svc.GetMyResultCard(ctx, 7, 3)
+	require.NoError(t, err)
+	assert.Equal(t, card.PageURL, again.PageURL)
+	cached, err := os.ReadFile(pngPath)
+	require.NoError(t, err)
+	assert.Equal(t, "cached", string(cached))
+
+	// Изменившийся результат получает новый адрес
+	result.Rank = 2
+	result.IsWinner = false
+	changed, err := svc.GetMyResultCard(ctx, 7, 3)
+	require.NoError(t, err)
+	assert.NotEqual(t, card.PageURL, changed.PageURL)
+}
+
+func TestShareCardService_GetPage_RejectsBadTokens(t *testing.T) {
+	svc, _ := newShareCardTestService(t, &entity.Result{QuizID: 3, UserID: 7})
+
+	for _, token := range []string{"", "../../etc/passwd", "ABCDEF0123456789abcdef0123456789", "0123456789abcdef0123456789abcdef"} {
+		_, err := svc.GetPage(context.Background(), token)
+		assert.ErrorIs(t, err, apperrors.ErrNotFound, token)
+	}
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// Ограничения брендинга спонсора (совпадают с размерами колонок quizzes.sponsor_*)
+const (
+	maxSponsorNameLength     = 100
+	maxSponsorClickURLLength = 500
+)
+
+var sponsorColorPattern = regexp.MustCompile(`^#[0-9a-f]{6}$`)
+
+// SponsorService ведёт брендинг спонсора викторины: имя, логотип из рекламных материалов,
+// цвета оформления и ссылку перехода. Брендинг отдаётся в карточке викторины и в событиях
+// quiz:start и quiz:results_available; показы в событиях учитываются в ad_impressions
+// вместе с рекламными паузами.
+type SponsorService struct {
+	quizRepo       repository.QuizRepository
+	adAssetRepo    repository.AdAssetRepository
+	impressionRepo repository.AdImpressionRepository
+	quizCache      QuizCacheInvalidator
+	now            func() time.Time
+}
+
+// NewSponsorService создает сервис брендинга спонсоров
+func NewSponsorService(quizRepo repository.QuizRepository, adAssetRepo repository.AdAssetRepository, impressionRepo repository.AdImpressionRepository) *SponsorService {
+	return &SponsorService{
+		quizRepo:       quizRepo,
+		adAssetRepo:    adAssetRepo,
+		impressionRepo: impressionRepo,
+		now:            time.Now,
+	}
+}
+
+// SetQuizCache подключает сброс кеша викторины при смене спонсора
+func (s *SponsorService) SetQuizCache(invalidator QuizCacheInvalidator) {
+	s.quizCache = invalidator
+}
+
+// Configure заменяет брендинг спонсора викторины; пустое имя убирает спонсора. Брендинг
+// показывается и в итогах, поэтому менять его можно до завершения викторины.
+func (s *SponsorService) Configure(quizID uint, sponsor entity.QuizSponsor) (*entity.Quiz, error) {
+	sponsor, err := s.normalize(sponsor)
+	if err != nil {
+		return nil, err
+	}
+	quiz, err := s.quizRepo.GetByID(quizID)
+	if err != nil {
+		return nil, err
+	}
+	if quiz.IsCompleted() || quiz.Status == entity.QuizStatusCancelled {
+		return nil, fmt.Errorf("%w: sponsor of quiz #%d cannot be changed after it is over", apperrors.ErrConflict, quizID)
+	}
+	if err := s.quizRepo.UpdateSponsor(quizID, sponsor); err != nil {
+		return nil, err
+	}
+	if s.quizCache != nil {
+		s.quizCache.InvalidateQuiz(quizID)
+	}
+
+	quiz.Sponsor = sponsor
+	log.Printf("[SponsorService] Викторина #%d: спонсор %q", quizID, sponsor.Name)
+	return quiz, nil
+}
+
+// normalize проверяет брендинг и приводит цвета к нижнему регистру
+func (s *SponsorService) normalize(sponsor entity.QuizSponsor) (entity.QuizSponsor, error) {
+	sponsor.Name = strings.TrimSpace(sponsor.Name)
+	if sponsor.Name == "" {
+		return entity.QuizSponsor{}, nil
+	}
+	if utf8.RuneCountInString(sponsor.Name) > maxSponsorNameLength {
+		return sponsor, fmt.Errorf("%w: sponsor name must be at most %d characters", apperrors.ErrValidation, maxSponsorNameLength)
+	}
+
+	sponsor.PrimaryColor = strings.ToLower(strings.TrimSpace(sponsor.PrimaryColor))
+	sponsor.SecondaryColor = strings.ToLower(strings.TrimSpace(sponsor.SecondaryColor))
+	for _, color := range []string{sponsor.PrimaryColor, sponsor.SecondaryColor} {
+		if color != "" && !sponsorColorPattern.MatchString(color) {
+			return sponsor, fmt.Errorf("%w: color %q must be in #rrggbb format", apperrors.ErrValidation, color)
+		}
+	}
+
+	sponsor.ClickURL = strings.TrimSpace(sponsor.ClickURL)
+	if sponsor.ClickURL != "" {
+		if len(sponsor.ClickURL) > maxSponsorClickURLLength {
+			return sponsor, fmt.Errorf("%w: click_url must be at most %d characters", apperrors.ErrValidation, maxSponsorClickURLLength)
+		}
+		u, err := url.Parse(sponsor.ClickURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return sponsor, fmt.Errorf("%w: click_url must be an absolute http(s) URL", apperrors.ErrValidation)
+		}
+	}
+
+	if sponsor.LogoAssetID != nil {
+		asset, err := s.adAssetRepo.GetByID(*sponsor.LogoAssetID)
+		if err != nil {
+			return sponsor, fmt.Errorf("%w: ad asset %d not found", apperrors.ErrValidation, *sponsor.LogoAssetID)
+		}
+		if !asset.IsImage() {
+			return sponsor, fmt.Errorf("%w: sponsor logo must be an image", apperrors.ErrValidation)
+		}
+	}
+	return sponsor, nil
+}
+
+// Branding возвращает брендинг спонсора викторины с адресом логотипа; nil — спонсора нет.
+// Если логотип недоступен, брендинг отдаётся без него.
+func (s *SponsorService) Branding(quiz *entity.Quiz) *entity.SponsorBranding {
+	if quiz == nil || quiz.Sponsor.IsEmpty() {
+		return nil
+	}
+	branding := &entity.SponsorBranding{
+		Name:           quiz.Sponsor.Name,
+		LogoAssetID:    quiz.Sponsor.LogoAssetID,
+		PrimaryColor:   quiz.Sponsor.PrimaryColor,
+		SecondaryColor: quiz.Sponsor.SecondaryColor,
+		ClickURL:       quiz.Sponsor.ClickURL,
+	}
+	if quiz.Sponsor.LogoAssetID != nil {
+		asset, err := s.adAssetRepo.GetByID(*quiz.Sponsor.LogoAssetID)
+		if err != nil {
+			log.Printf("[SponsorService] WARNING: Логотип спонсора #%d викторины #%d недоступен: %v", *quiz.Sponsor.LogoAssetID, quiz.ID, err)
+		} else {
+			branding.LogoURL = asset.URL
+		}
+	}
+	return branding
+}
+
+// SponsorImpression возвращает брендинг для WS-события и записывает показ спонсора
+// (реализует quizmanager.Sponsorship). Ошибка записи показа не мешает отправке события.
+func (s *SponsorService) SponsorImpression(quiz *entity.Quiz, placement string, viewers int) *entity.SponsorBranding {
+	branding := s.Branding(quiz)
+	if branding == nil {
+		return nil
+	}
+
+	questionAfter := 0
+	if placement == entity.AdPlacementSponsorResults {
+		questionAfter = quiz.QuestionCount
+	}
+	quizID := quiz.ID
+	impression := &entity.AdImpression{
+		AdAssetID:     quiz.Sponsor.LogoAssetID,
+		QuizID:        &quizID,
+		QuestionAfter: questionAfter,
+		Viewers:       viewers,
+		ShownAt:       s.now(),
+		Placement:     placement,
+	}
+	if err := s.impressionRepo.Create(impression); err != nil {
+		log.Printf("[SponsorService] WARNING: Не удалось сохранить показ спонсора викторины #%d: %v", quiz.ID, err)
+	}
+	return branding
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// impressionRecorder запоминает записанные показы рекламы
+type impressionRecorder struct {
+	items []entity.AdImpression
+	err   error
+}
+
+func (r *impressionRecorder) Create(impression *entity.AdImpression) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.items = append(r.items, *impression)
+	return nil
+}
+
+func newTestSponsorService(quiz *entity.Quiz, logo *entity.AdAsset) (*SponsorService, *MockQuizRepository, *impressionRecorder) {
+	quizRepo := new(MockQuizRepository)
+	quizRepo.On("GetByID", quiz.ID).Return(quiz, nil)
+	quizRepo.On("UpdateSponsor", quiz.ID, mock.Anything).Return(nil).Maybe()
+	impressions := &impressionRecorder{}
+	return NewSponsorService(quizRepo, &secondChanceAdRepo{asset: logo}, impressions), quizRepo, impressions
+}
+
+func TestSponsorService_Configure(t *testing.T) {
+	logoID, videoID := uint(4), uint(5)
+	quiz := &entity.Quiz{ID: 1, Status: entity.QuizStatusScheduled}
+	svc, quizRepo, _ := newTestSponsorService(quiz, &entity.AdAsset{ID: logoID, MediaType: "image", URL: "https://cdn.example/logo.png"})
+
+	cases := []entity.QuizSponsor{
+		{Name: "Kaspi", PrimaryColor: "red"},
+		{Name: "Kaspi", SecondaryColor: "#12345"},
+		{Name: "Kaspi", ClickURL: "javascript:alert(1)"},
+		{Name: "Kaspi", ClickURL: "/promo"},
+		{Name: "Kaspi", LogoAssetID: &videoID},
+	}
+	for _, sponsor := range cases {
+		_, err := svc.Configure(quiz.ID, sponsor)
+		assert.ErrorIs(t, err, apperrors.ErrValidation, "%+v", sponsor)
+	}
+
+	updated, err := svc.Configure(quiz.ID, entity.QuizSponsor{
+		Name:         " Kaspi ",
+		LogoAssetID:  &logoID,
+		PrimaryColor: "#F14635",
+		ClickURL:     "https://kaspi.kz/promo",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, entity.QuizSponsor{Name: "Kaspi", LogoAssetID: &logoID, PrimaryColor: "#f14635", ClickURL: "https://kaspi.kz/promo"}, updated.Sponsor)
+	quizRepo.AssertCalled(t, "UpdateSponsor", quiz.ID, updated.Sponsor)
+
+	// Пустое имя убирает спонсора вместе с оформлением
+	updated, err = svc.Configure(quiz.ID, entity.QuizSponsor{PrimaryColor: "#ffffff", LogoAssetID: &logoID})
+	require.NoError(t, err)
+	assert.True(t, updated.Sponsor.IsEmpty())
+	assert.Nil(t, updated.Sponsor.LogoAssetID)
+
+	// Завершённой викторине спонсор не меняется
+	quiz.Status = entity.QuizStatusCompleted
+	_, err = svc.Configure(quiz.ID, entity.QuizSponsor{Name: "Kaspi"})
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+}
+
+func TestSponsorService_SponsorImpression(t *testing.T) {
+	logoID := uint(4)
+	quiz := &entity.Quiz{ID: 3, QuestionCount: 12, Sponsor: entity.QuizSponsor{
+		Name: "Kaspi", LogoAssetID: &logoID, PrimaryColor: "#f14635", ClickURL: "https://kaspi.kz/promo",
+	}}
+	svc, _, impressions := newTestSponsorService(quiz, &entity.AdAsset{ID: logoID, MediaType: "image", URL: "https://cdn.example/logo.png"})
+
+	branding := svc.SponsorImpression(quiz, entity.AdPlacementSponsorStart, 250)
+	require.NotNil(t, branding)
+	assert.Equal(t, "https://cdn.example/logo.png", branding.LogoURL)
+	assert.Equal(t, "#f14635", branding.PrimaryColor)
+
+	svc.SponsorImpression(quiz, entity.AdPlacementSponsorResults, 40)
+	require.Len(t, impressions.items, 2)
+	assert.Equal(t, entity.AdPlacementSponsorStart, impressions.items[0].Placement)
+	assert.Equal(t, 250, impressions.items[0].Viewers)
+	assert.Equal(t, 0, impressions.items[0].QuestionAfter)
+	assert.Equal(t, &logoID, impressions.items[0].AdAssetID)
+	assert.Equal(t, entity.AdPlacementSponsorResults, impressions.items[1].Placement)
+	assert.Equal(t, 12, impressions.items[1].QuestionAfter)
+
+	// Ошибка записи показа не убирает брендинг из события
+	impressions.err = errors.New("db down")
+	assert.NotNil(t, svc.SponsorImpression(quiz, entity.AdPlacementSponsorResults, 40))
+
+	// Без спонсора брендинга и показа нет; удалённый логотип не мешает остальному брендингу
+	assert.Nil(t, svc.SponsorImpression(&entity.Quiz{ID: 4}, entity.AdPlacementSponsorStart, 10))
+	missingLogo := uint(99)
+	quiz.Sponsor.LogoAssetID = &missingLogo
+	branding = svc.Branding(quiz)
+	require.NotNil(t, branding)
+	assert.Empty(t, branding.LogoURL)
+	assert.Equal(t, "Kaspi", branding.Name)
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// updateStreaks продлевает или обрывает серии игрока в транзакции tx подсчёта его результата.
+// Серия викторин продолжается, если игрок сыграл предыдущую завершённую викторину по расписанию.
+func (s *ResultService) updateStreaks(tx *gorm.DB, quiz *entity.Quiz, userID uint, answers []entity.UserAnswer, eliminated bool) error {
+	streak := entity.UserStreak{UserID: userID}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ?", userID).Limit(1).Find(&streak).Error; err != nil {
+		return fmt.Errorf("failed to load user streaks: %w", err)
+	}
+
+	var previousQuizID uint
+	if err := tx.Model(&entity.Quiz{}).
+		Select("id").
+		Where("id <> ? AND status = ? AND scheduled_time < ?", quiz.ID, entity.QuizStatusCompleted, quiz.ScheduledTime).
+		Order("scheduled_time DESC, id DESC").
+		Limit(1).
+		Scan(&previousQuizID).Error; err != nil {
+		return fmt.Errorf("failed to find previous quiz: %w", err)
+	}
+
+	if !advanceStreaks(&streak, quiz.ID, previousQuizID, answers, eliminated) {
+		return nil
+	}
+	if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&streak).Error; err != nil {
+		return fmt.Errorf("failed to save user streaks: %w", err)
+	}
+	return nil
+}
+
+// advanceStreaks применяет к сериям игрока сыгранную викторину quizID (previousQuizID — предыдущая
+// завершённая викторина, 0 — нет). answers — ответы игрока в порядке вопросов: аннулированные
+// не обрывают и не продлевают серию верных ответов. Возвращает false, если викторина уже учтена.
+func advanceStreaks(streak *entity.UserStreak, quizID, previousQuizID uint, answers []entity.UserAnswer, eliminated bool) bool {
+	if streak.LastQuizID != nil && *streak.LastQuizID == quizID {
+		return false
+	}
+
+	if streak.LastQuizID != nil && previousQuizID != 0 && *streak.LastQuizID == previousQuizID {
+		streak.QuizStreak++
+	} else {
+		streak.QuizStreak = 1
+	}
+	streak.BestQuizStreak = max(streak.BestQuizStreak, streak.QuizStreak)
+
+	for _, answer := range answers {
+		if answer.EliminationReason == entity.AnswerReasonQuestionVoided {
+			continue
+		}
+		if answer.IsCorrect {
+			streak.CorrectStreak++
+			streak.BestCorrectStreak = max(streak.BestCorrectStreak, streak.CorrectStreak)
+		} else {
+			streak.CorrectStreak = 0
+		}
+	}
+
+	if eliminated {
+		streak.SurvivalStreak = 0
+	} else {
+		streak.SurvivalStreak++
+		streak.BestSurvivalStreak = max(streak.BestSurvivalStreak, streak.SurvivalStreak)
+	}
+
+	lastQuizID := quizID
+	streak.LastQuizID = &lastQuizID
+	return true
+}
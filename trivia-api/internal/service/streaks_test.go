@@ -0,0 +1,55 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+func streakAnswers(correct ...bool) []entity.UserAnswer {
+	answers := make([]entity.UserAnswer, len(correct))
+	for i, c := range correct {
+		answers[i] = entity.UserAnswer{IsCorrect: c}
+	}
+	return answers
+}
+
+func TestAdvanceStreaks(t *testing.T) {
+	var streak entity.UserStreak
+
+	// Первая викторина: серии начинаются
+	assert.True(t, advanceStreaks(&streak, 10, 9, streakAnswers(true, false, true, true), false))
+	assert.Equal(t, 1, streak.QuizStreak)
+	assert.Equal(t, 2, streak.CorrectStreak)
+	assert.Equal(t, 2, streak.BestCorrectStreak)
+	assert.Equal(t, 1, streak.SurvivalStreak)
+
+	// Следующая по расписанию: серия верных ответов продолжается через игры,
+	// аннулированный вопрос её не обрывает
+	answers := streakAnswers(true, false, true)
+	answers[1].EliminationReason = entity.AnswerReasonQuestionVoided
+	assert.True(t, advanceStreaks(&streak, 11, 10, answers, false))
+	assert.Equal(t, 2, streak.QuizStreak)
+	assert.Equal(t, 4, streak.CorrectStreak)
+	assert.Equal(t, 4, streak.BestCorrectStreak)
+	assert.Equal(t, 2, streak.SurvivalStreak)
+
+	// Повторный подсчёт той же викторины ничего не меняет
+	assert.False(t, advanceStreaks(&streak, 11, 10, answers, true))
+	assert.Equal(t, 2, streak.SurvivalStreak)
+
+	// Выбывание обрывает серию игр без выбывания, лучшая серия сохраняется
+	assert.True(t, advanceStreaks(&streak, 12, 11, streakAnswers(false), true))
+	assert.Equal(t, 3, streak.QuizStreak)
+	assert.Equal(t, 0, streak.CorrectStreak)
+	assert.Equal(t, 0, streak.SurvivalStreak)
+	assert.Equal(t, 2, streak.BestSurvivalStreak)
+
+	// Пропущенная викторина 13: серия викторин начинается заново
+	assert.True(t, advanceStreaks(&streak, 14, 13, streakAnswers(true), false))
+	assert.Equal(t, 1, streak.QuizStreak)
+	assert.Equal(t, 3, streak.BestQuizStreak)
+	assert.Equal(t, 4, streak.BestCorrectStreak)
+	assert.Equal(t, uint(14), *streak.LastQuizID)
+}
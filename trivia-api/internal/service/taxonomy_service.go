@@ -0,0 +1,336 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/pkg/httpcache"
+)
+
+// taxonomySlugPattern — slug категории или тега: латиница в нижнем регистре, цифры и дефисы
+var taxonomySlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+const (
+	maxTaxonomySlugLength = 64
+	maxTaxonomyNameLength = 100
+	// maxTagsPerItem ограничивает число тегов у одной викторины или вопроса
+	maxTagsPerItem = 20
+)
+
+// CategoryInput — редактируемые поля категории
+type CategoryInput struct {
+	Slug        string
+	Name        string
+	Description string
+}
+
+// TagInput — редактируемые поля тега
+type TagInput struct {
+	Slug string
+	Name string
+}
+
+// TaxonomyAssignment — категория и теги викторины или вопроса после изменения
+type TaxonomyAssignment struct {
+	CategoryID *uint        `json:"category_id"`
+	Tags       []entity.Tag `json:"tags"`
+}
+
+// CategoryLeaderboard — страница лидерборда одной категории
+type CategoryLeaderboard struct {
+	Category *entity.Category          `json:"category"`
+	Users    []CategoryLeaderboardUser `json:"users"`
+	Total    int64                     `json:"total"`
+	Page     int                       `json:"page"`
+	PerPage  int                       `json:"per_page"`
+}
+
+// CategoryLeaderboardUser — участник лидерборда категории с местом
+type CategoryLeaderboardUser struct {
+	Rank int `json:"rank"`
+	entity.CategoryLeaderboardEntry
+}
+
+// TaxonomyService управляет категориями и тегами викторин и вопросов пула
+type TaxonomyService struct {
+	repo      repository.TaxonomyRepository
+	quizCache QuizCacheInvalidator
+	httpCache httpcache.Invalidator
+}
+
+// NewTaxonomyService создает сервис категорий и тегов
+func NewTaxonomyService(repo repository.TaxonomyRepository) *TaxonomyService {
+	return &TaxonomyService{repo: repo}
+}
+
+// SetQuizCache подключает сброс кеша викторины после смены её категории или тегов
+func (s *TaxonomyService) SetQuizCache(invalidator QuizCacheInvalidator) {
+	s.quizCache = invalidator
+}
+
+// SetHTTPCache подключает сброс закешированных списков викторин и лидербордов
+func (s *TaxonomyService) SetHTTPCache(invalidator httpcache.Invalidator) {
+	s.httpCache = invalidator
+}
+
+// ListCategories возвращает все категории
+func (s *TaxonomyService) ListCategories() ([]entity.Category, error) {
+	return s.repo.ListCategories()
+}
+
+// GetCategory возвращает категорию по ID
+func (s *TaxonomyService) GetCategory(id uint) (*entity.Category, error) {
+	return s.repo.GetCategoryByID(id)
+}
+
+// GetCategoryBySlug возвращает категорию по slug
+func (s *TaxonomyService) GetCategoryBySlug(slug string) (*entity.Category, error) {
+	return s.repo.GetCategoryBySlug(slug)
+}
+
+// CreateCategory создаёт категорию
+func (s *TaxonomyService) CreateCategory(input CategoryInput) (*entity.Category, error) {
+	category := &entity.Category{}
+	if err := applyCategoryInput(category, input); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateCategory(category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// UpdateCategory заменяет поля категории. Смена slug меняет адреса фильтров и лидерборда.
+func (s *TaxonomyService) UpdateCategory(id uint, input CategoryInput) (*entity.Category, error) {
+	category, err := s.repo.GetCategoryByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyCategoryInput(category, input); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateCategory(category); err != nil {
+		return nil, err
+	}
+	s.invalidateQuizListings()
+	return category, nil
+}
+
+// DeleteCategory удаляет категорию; её викторины и вопросы остаются без категории
+func (s *TaxonomyService) DeleteCategory(id uint) error {
+	if err := s.repo.DeleteCategory(id); err != nil {
+		return err
+	}
+	s.invalidateQuizListings()
+	return nil
+}
+
+// ListTags возвращает все теги
+func (s *TaxonomyService) ListTags() ([]entity.Tag, error) {
+	return s.repo.ListTags()
+}
+
+// GetTag возвращает тег по ID
+func (s *TaxonomyService) GetTag(id uint) (*entity.Tag, error) {
+	return s.repo.GetTagByID(id)
+}
+
+// CreateTag создаёт тег
+func (s *TaxonomyService) CreateTag(input TagInput) (*entity.Tag, error) {
+	tag := &entity.Tag{}
+	if err := applyTagInput(tag, input); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateTag(tag); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+// UpdateTag заменяет поля тега
+func (s *TaxonomyService) UpdateTag(id uint, input TagInput) (*entity.Tag, error) {
+	tag, err := s.repo.GetTagByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyTagInput(tag, input); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateTag(tag); err != nil {
+		return nil, err
+	}
+	s.invalidateQuizListings()
+	return tag, nil
+}
+
+// DeleteTag удаляет тег и снимает его со всех викторин и вопросов
+func (s *TaxonomyService) DeleteTag(id uint) error {
+	if err := s.repo.DeleteTag(id); err != nil {
+		return err
+	}
+	s.invalidateQuizListings()
+	return nil
+}
+
+// SetQuizTaxonomy заменяет категорию и теги викторины. categoryID == nil снимает категорию,
+// пустой tagSlugs — все теги.
+func (s *TaxonomyService) SetQuizTaxonomy(quizID uint, categoryID *uint, tagSlugs []string) (*TaxonomyAssignment, error) {
+	tags, err := s.resolveAssignment(categoryID, tagSlugs)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetQuizTaxonomy(quizID, categoryID, tags); err != nil {
+		return nil, err
+	}
+	if s.quizCache != nil {
+		s.quizCache.InvalidateQuiz(quizID)
+	}
+	s.invalidateQuizListings()
+	return &TaxonomyAssignment{CategoryID: categoryID, Tags: tags}, nil
+}
+
+// SetQuestionTaxonomy заменяет категорию и теги вопроса
+func (s *TaxonomyService) SetQuestionTaxonomy(questionID uint, categoryID *uint, tagSlugs []string) (*TaxonomyAssignment, error) {
+	tags, err := s.resolveAssignment(categoryID, tagSlugs)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetQuestionTaxonomy(questionID, categoryID, tags); err != nil {
+		return nil, err
+	}
+	return &TaxonomyAssignment{CategoryID: categoryID, Tags: tags}, nil
+}
+
+// GetCategoryLeaderboard возвращает лидерборд категории по её slug
+func (s *TaxonomyService) GetCategoryLeaderboard(slug string, page, pageSize int) (*CategoryLeaderboard, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	} else if pageSize > 100 {
+		pageSize = 100
+	}
+
+	category, err := s.repo.GetCategoryBySlug(slug)
+	if err != nil {
+		return nil, err
+	}
+	offset := (page - 1) * pageSize
+	entries, total, err := s.repo.GetCategoryLeaderboard(category.ID, pageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]CategoryLeaderboardUser, len(entries))
+	for i, entry := range entries {
+		identity := publicLeaderboardIdentity(entry.UserID, entry.Username, entry.ProfilePicture, entry.ShowOnLeaderboards)
+		entry.UserID, entry.Username, entry.ProfilePicture, entry.Anonymous = identity.UserID, identity.Username, identity.ProfilePicture, identity.Anonymous
+		users[i] = CategoryLeaderboardUser{Rank: offset + i + 1, CategoryLeaderboardEntry: entry}
+	}
+	return &CategoryLeaderboard{
+		Category: category,
+		Users:    users,
+		Total:    total,
+		Page:     page,
+		PerPage:  pageSize,
+	}, nil
+}
+
+// resolveAssignment проверяет категорию и находит теги по slug; неизвестный slug — ошибка валидации
+func (s *TaxonomyService) resolveAssignment(categoryID *uint, tagSlugs []string) ([]entity.Tag, error) {
+	if categoryID != nil {
+		if _, err := s.repo.GetCategoryByID(*categoryID); err != nil {
+			if errors.Is(err, apperrors.ErrNotFound) {
+				return nil, fmt.Errorf("%w: category %d not found", apperrors.ErrValidation, *categoryID)
+			}
+			return nil, err
+		}
+	}
+
+	slugs := uniqueSlugs(tagSlugs)
+	if len(slugs) > maxTagsPerItem {
+		return nil, fmt.Errorf("%w: at most %d tags are allowed", apperrors.ErrValidation, maxTagsPerItem)
+	}
+	tags, err := s.repo.GetTagsBySlugs(slugs)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) != len(slugs) {
+		found := make(map[string]bool, len(tags))
+		for _, tag := range tags {
+			found[tag.Slug] = true
+		}
+		var missing []string
+		for _, slug := range slugs {
+			if !found[slug] {
+				missing = append(missing, slug)
+			}
+		}
+		return nil, fmt.Errorf("%w: unknown tags: %s", apperrors.ErrValidation, strings.Join(missing, ", "))
+	}
+	return tags, nil
+}
+
+// invalidateQuizListings сбрасывает кеш списков викторин и лидербордов: в них выводятся
+// категории и теги, а лидерборд категории зависит от состава её викторин
+func (s *TaxonomyService) invalidateQuizListings() {
+	if s.httpCache != nil {
+		s.httpCache.Invalidate(httpcache.NamespaceQuizzes, httpcache.NamespaceLeaderboard)
+	}
+}
+
+func applyCategoryInput(category *entity.Category, input CategoryInput) error {
+	slug, name, err := normalizeTaxonomyFields(input.Slug, input.Name)
+	if err != nil {
+		return err
+	}
+	category.Slug = slug
+	category.Name = name
+	category.Description = strings.TrimSpace(input.Description)
+	return nil
+}
+
+func applyTagInput(tag *entity.Tag, input TagInput) error {
+	slug, name, err := normalizeTaxonomyFields(input.Slug, input.Name)
+	if err != nil {
+		return err
+	}
+	tag.Slug = slug
+	tag.Name = name
+	return nil
+}
+
+func normalizeTaxonomyFields(slug, name string) (string, string, error) {
+	slug = strings.ToLower(strings.TrimSpace(slug))
+	name = strings.TrimSpace(name)
+	if len(slug) > maxTaxonomySlugLength || !taxonomySlugPattern.MatchString(slug) {
+		return "", "", fmt.Errorf("%w: slug must match %s and be at most %d characters",
+			apperrors.ErrValidation, taxonomySlugPattern, maxTaxonomySlugLength)
+	}
+	if name == "" || len([]rune(name)) > maxTaxonomyNameLength {
+		return "", "", fmt.Errorf("%w: name is required and must be at most %d characters",
+			apperrors.ErrValidation, maxTaxonomyNameLength)
+	}
+	return slug, name, nil
+}
+
+// uniqueSlugs нормализует slug тегов и убирает повторы, сохраняя порядок
+func uniqueSlugs(slugs []string) []string {
+	seen := make(map[string]bool, len(slugs))
+	result := make([]string, 0, len(slugs))
+	for _, slug := range slugs {
+		slug = strings.ToLower(strings.TrimSpace(slug))
+		if slug == "" || seen[slug] {
+			continue
+		}
+		seen[slug] = true
+		result = append(result, slug)
+	}
+	return result
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// fakeTaxonomyRepo — TaxonomyRepository в памяти
+type fakeTaxonomyRepo struct {
+	categories map[uint]entity.Category
+	tags       map[uint]entity.Tag
+	quizzes    map[uint]TaxonomyAssignment
+	entries    []entity.CategoryLeaderboardEntry
+	nextID     uint
+}
+
+func newFakeTaxonomyRepo() *fakeTaxonomyRepo {
+	return &fakeTaxonomyRepo{
+		categories: map[uint]entity.Category{},
+		tags:       map[uint]entity.Tag{},
+		quizzes:    map[uint]TaxonomyAssignment{},
+	}
+}
+
+func (f *fakeTaxonomyRepo) CreateCategory(category *entity.Category) error {
+	for _, c := range f.categories {
+		if c.Slug == category.Slug {
+			return apperrors.ErrConflict
+		}
+	}
+	f.nextID++
+	category.ID = f.nextID
+	f.categories[category.ID] = *category
+	return nil
+}
+
+func (f *fakeTaxonomyRepo) UpdateCategory(category *entity.Category) error {
+	f.categories[category.ID] = *category
+	return nil
+}
+
+func (f *fakeTaxonomyRepo) DeleteCategory(id uint) error {
+	if _, ok := f.categories[id]; !ok {
+		return apperrors.ErrNotFound
+	}
+	delete(f.categories, id)
+	return nil
+}
+
+func (f *fakeTaxonomyRepo) GetCategoryByID(id uint) (*entity.Category, error) {
+	category, ok := f.categories[id]
+	if !ok {
+		return nil, apperrors.ErrNotFound
+	}
+	return &category, nil
+}
+
+func (f *fakeTaxonomyRepo) GetCategoryBySlug(slug string) (*entity.Category, error) {
+	for _, category := range f.categories {
+		if category.Slug == slug {
+			return &category, nil
+		}
+	}
+	return nil, apperrors.ErrNotFound
+}
+
+func (f *fakeTaxonomyRepo) ListCategories() ([]entity.Category, error) {
+	categories := make([]entity.Category, 0, len(f.categories))
+	for _, category := range f.categories {
+		categories = append(categories, category)
+	}
+	return categories, nil
+}
+
+func (f *fakeTaxonomyRepo) CreateTag(tag *entity.Tag) error {
+	f.nextID++
+	tag.ID = f.nextID
+	f.tags[tag.ID] = *tag
+	return nil
+}
+
+func (f *fakeTaxonomyRepo) UpdateTag(tag *entity.Tag) error {
+	f.tags[tag.ID] = *tag
+	return nil
+}
+
+func (f *fakeTaxonomyRepo) DeleteTag(id uint) error {
+	delete(f.tags, id)
+	return nil
+}
+
+func (f *fakeTaxonomyRepo) GetTagByID(id uint) (*entity.Tag, error) {
+	tag, ok := f.tags[id]
+	if !ok {
+		return nil, apperrors.ErrNotFound
+	}
+	return &tag, nil
+}
+
+func (f *fakeTaxonomyRepo) ListTags() ([]entity.Tag, error) {
+	tags := make([]entity.Tag, 0, len(f.tags))
+	for _, tag := range f.tags {
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+func (f *fakeTaxonomyRepo) GetTagsBySlugs(slugs []string) ([]entity.Tag, error) {
+	var tags []entity.Tag
+	for _, slug := range slugs {
+		for _, tag := range f.tags {
+			if tag.Slug == slug {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return tags, nil
+}
+
+func (f *fakeTaxonomyRepo) SetQuizTaxonomy(quizID uint, categoryID *uint, tags []entity.Tag) error {
+	f.quizzes[quizID] = TaxonomyAssignment{CategoryID: categoryID, Tags: tags}
+	return nil
+}
+
+func (f *fakeTaxonomyRepo) SetQuestionTaxonomy(questionID uint, categoryID *uint, tags []entity.Tag) error {
+	return nil
+}
+
+func (f *fakeTaxonomyRepo) GetCategoryLeaderboard(categoryID uint, limit, offset int) ([]entity.CategoryLeaderboardEntry, int64, error) {
+	end := offset + limit
+	if end > len(f.entries) {
+		end = len(f.entries)
+	}
+	if offset > end {
+		offset = end
+	}
+	return f.entries[offset:end], int64(len(f.entries)), nil
+}
+
+// recordingQuizCache запоминает викторины, чей кеш был сброшен
+type recordingQuizCache struct {
+	invalidated []uint
+}
+
+func (r *recordingQuizCache) InvalidateQuiz(quizID uint) {
+	r.invalidated = append(r.invalidated, quizID)
+}
+
+func TestTaxonomyService_CreateCategoryValidatesSlug(t *testing.T) {
+	svc := NewTaxonomyService(newFakeTaxonomyRepo())
+
+	_, err := svc.CreateCategory(CategoryInput{Slug: "Кино и ТВ", Name: "Кино"})
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+
+	category, err := svc.CreateCategory(CategoryInput{Slug: " Movies-90s ", Name: " Кино 90-х "})
+	require.NoError(t, err)
+	assert.Equal(t, "movies-90s", category.Slug)
+	assert.Equal(t, "Кино 90-х", category.Name)
+
+	_, err = svc.CreateCategory(CategoryInput{Slug: "movies-90s", Name: "Дубль"})
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+}
+
+func TestTaxonomyService_SetQuizTaxonomy(t *testing.T) {
+	repo := newFakeTaxonomyRepo()
+	svc := NewTaxonomyService(repo)
+	cache := &recordingQuizCache{}
+	svc.SetQuizCache(cache)
+
+	category, err := svc.CreateCategory(CategoryInput{Slug: "history", Name: "История"})
+	require.NoError(t, err)
+	_, err = svc.CreateTag(TagInput{Slug: "new-year", Name: "Новогодняя"})
+	require.NoError(t, err)
+
+	assignment, err := svc.SetQuizTaxonomy(5, &category.ID, []string{"New-Year", "new-year", ""})
+	require.NoError(t, err)
+	require.Len(t, assignment.Tags, 1)
+	assert.Equal(t, "new-year", assignment.Tags[0].Slug)
+	assert.Equal(t, &category.ID, repo.quizzes[5].CategoryID)
+	assert.Equal(t, []uint{5}, cache.invalidated)
+
+	// Неизвестный тег и несуществующая категория — ошибки валидации, викторина не меняется
+	_, err = svc.SetQuizTaxonomy(6, nil, []string{"new-year", "missing"})
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	assert.Contains(t, err.Error(), "missing")
+	unknownCategory := uint(999)
+	_, err = svc.SetQuizTaxonomy(6, &unknownCategory, nil)
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	assert.NotContains(t, repo.quizzes, uint(6))
+}
+
+func TestTaxonomyService_GetCategoryLeaderboardRanks(t *testing.T) {
+	repo := newFakeTaxonomyRepo()
+	svc := NewTaxonomyService(repo)
+	_, err := svc.CreateCategory(CategoryInput{Slug: "music", Name: "Музыка"})
+	require.NoError(t, err)
+	repo.entries = []entity.CategoryLeaderboardEntry{
+		{UserID: 1, WinsCount: 3, ShowOnLeaderboards: true},
+		{UserID: 2, WinsCount: 2, ShowOnLeaderboards: true},
+		{UserID: 3, WinsCount: 1, ShowOnLeaderboards: true},
+	}
+
+	leaderboard, err := svc.GetCategoryLeaderboard("music", 2, 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), leaderboard.Total)
+	require.Len(t, leaderboard.Users, 1)
+	assert.Equal(t, 3, leaderboard.Users[0].Rank)
+	assert.Equal(t, uint(3), leaderboard.Users[0].UserID)
+
+	_, err = svc.GetCategoryLeaderboard("sport", 1, 10)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// translationAssistLocale — язык, на который предзаполняются переводы
+const translationAssistLocale = "kk"
+
+// TranslationAssistRun — итоги прохода машинного перевода
+type TranslationAssistRun struct {
+	StartedAt   time.Time  `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"` // nil — проход ещё идёт
+	Translated  int        `json:"translated"`             // сохранено машинных переводов
+	Skipped     int        `json:"skipped"`                // перевод появился раньше, чем закончился машинный
+	Failed      int        `json:"failed"`                 // сервис вернул непригодный перевод
+	Error       string     `json:"error,omitempty"`        // сервис перевода недоступен — проход прерван
+}
+
+// TranslationAssistStatus — состояние предзаполнения для админ-панели
+type TranslationAssistStatus struct {
+	Provider string                `json:"provider"`
+	Locale   string                `json:"locale"`
+	Running  bool                  `json:"running"`
+	LastRun  *TranslationAssistRun `json:"last_run,omitempty"`
+}
+
+// TranslationAssistService предзаполняет казахские переводы вопросов, у которых их нет,
+// сервисом машинного перевода. Переводы сохраняются с флагом machine_translated и ждут
+// проверки администратором (TranslationService.AcceptMachineTranslation).
+type TranslationAssistService struct {
+	translationRepo repository.QuestionTranslationRepository
+	translator      MachineTranslator
+	sourceLocale    string
+	batchSize       int
+	timeout         time.Duration
+
+	mu      sync.Mutex
+	running bool
+	cursor  uint // ID последнего обработанного вопроса: проходы идут по пулу по кругу
+	lastRun *TranslationAssistRun
+}
+
+// NewTranslationAssistService создает сервис предзаполнения переводов.
+// sourceLocale — язык основных полей вопроса, timeout — на перевод одного вопроса.
+func NewTranslationAssistService(
+	translationRepo repository.QuestionTranslationRepository,
+	translator MachineTranslator,
+	sourceLocale string,
+	batchSize int,
+	timeout time.Duration,
+) *TranslationAssistService {
+	return &TranslationAssistService{
+		translationRepo: translationRepo,
+		translator:      translator,
+		sourceLocale:    sourceLocale,
+		batchSize:       batchSize,
+		timeout:         timeout,
+	}
+}
+
+// Status возвращает провайдера, язык и итоги последнего прохода
+func (s *TranslationAssistService) Status() TranslationAssistStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := TranslationAssistStatus{Provider: s.translator.Name(), Locale: translationAssistLocale, Running: s.running}
+	if s.lastRun != nil {
+		run := *s.lastRun
+		status.LastRun = &run
+	}
+	return status
+}
+
+// Trigger запускает проход в фоне (запуск из админ-панели);
+// apperrors.ErrConflict, если проход уже идёт
+func (s *TranslationAssistService) Trigger() error {
+	if !s.begin() {
+		return fmt.Errorf("%w: machine translation is already running", apperrors.ErrConflict)
+	}
+	go s.run(context.Background())
+	return nil
+}
+
+// Run выполняет один проход синхронно; apperrors.ErrConflict, если проход уже идёт
+func (s *TranslationAssistService) Run(ctx context.Context) (*TranslationAssistRun, error) {
+	if !s.begin() {
+		return nil, fmt.Errorf("%w: machine translation is already running", apperrors.ErrConflict)
+	}
+	return s.run(ctx), nil
+}
+
+// Start запускает проходы по расписанию до отмены контекста
+func (s *TranslationAssistService) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := s.Run(ctx); err != nil {
+					log.Printf("[TranslationAssist] Проход по расписанию пропущен: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+func (s *TranslationAssistService) begin() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running {
+		return false
+	}
+	s.running = true
+	s.lastRun = &TranslationAssistRun{StartedAt: time.Now()}
+	return true
+}
+
+// run переводит следующую порцию вопросов без перевода и публикует итоги
+func (s *TranslationAssistService) run(ctx context.Context) *TranslationAssistRun {
+	s.mu.Lock()
+	cursor := s.cursor
+	run := *s.lastRun
+	s.mu.Unlock()
+
+	cursor, err := s.translateBatch(ctx, cursor, &run)
+	if err != nil {
+		run.Error = err.Error()
+		log.Printf("[TranslationAssist] Проход прерван: %v", err)
+	}
+	completedAt := time.Now()
+	run.CompletedAt = &completedAt
+	log.Printf("[TranslationAssist] Проход завершён: переведено %d, пропущено %d, с ошибкой %d",
+		run.Translated, run.Skipped, run.Failed)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cursor = cursor
+	s.lastRun = &run
+	s.running = false
+	result := run
+	return &result
+}
+
+// translateBatch переводит до batchSize вопросов после cursor и возвращает новый курсор.
+// Непригодный перевод одного вопроса пропускается; ошибка сервиса прерывает проход,
+// и вопрос будет переведён в следующий раз.
+func (s *TranslationAssistService) translateBatch(ctx context.Context, cursor uint, run *TranslationAssistRun) (uint, error) {
+	questions, err := s.translationRepo.ListUntranslated(translationAssistLocale, cursor, s.batchSize)
+	if err != nil {
+		return cursor, err
+	}
+	for i := range questions {
+		question := &questions[i]
+		translation, err := s.translate(ctx, question)
+		if err != nil {
+			return cursor, fmt.Errorf("question %d: %w", question.ID, err)
+		}
+		cursor = question.ID
+		if translation == nil {
+			run.Failed++
+			continue
+		}
+		created, err := s.translationRepo.CreateIfMissing(translation)
+		if err != nil {
+			return cursor, err
+		}
+		if created {
+			run.Translated++
+		} else {
+			run.Skipped++
+		}
+	}
+	if len(questions) < s.batchSize {
+		cursor = 0 // пул пройден: следующий проход начнёт сначала
+	}
+	return cursor, nil
+}
+
+// translate переводит текст и варианты вопроса одним запросом. Возвращает nil без ошибки,
+// если перевод непригоден: другое число строк, пустая строка или слишком длинный текст.
+func (s *TranslationAssistService) translate(ctx context.Context, question *entity.Question) (*entity.QuestionTranslation, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	texts := append([]string{question.Text}, question.Options...)
+	translated, err := s.translator.Translate(ctx, s.sourceLocale, translationAssistLocale, texts)
+	if err != nil {
+		return nil, err
+	}
+	if len(translated) != len(texts) {
+		log.Printf("[TranslationAssist] Вопрос %d: ожидалось %d строк перевода, получено %d", question.ID, len(texts), len(translated))
+		return nil, nil
+	}
+	for i := range translated {
+		if translated[i] = strings.TrimSpace(translated[i]); translated[i] == "" {
+			log.Printf("[TranslationAssist] Вопрос %d: пустая строка %d в переводе", question.ID, i)
+			return nil, nil
+		}
+	}
+	if utf8.RuneCountInString(translated[0]) > maxQuestionTextLength {
+		log.Printf("[TranslationAssist] Вопрос %d: перевод длиннее %d символов", question.ID, maxQuestionTextLength)
+		return nil, nil
+	}
+	return &entity.QuestionTranslation{
+		QuestionID:        question.ID,
+		Locale:            translationAssistLocale,
+		Text:              translated[0],
+		Options:           entity.StringArray(translated[1:]),
+		MachineTranslated: true,
+		MTProvider:        s.translator.Name(),
+	}, nil
+}
@@ -0,0 +1,305 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/pkg/i18n"
+)
+
+// fakeTranslationRepo — QuestionTranslationRepository в памяти поверх списка вопросов
+type fakeTranslationRepo struct {
+	questions    []entity.Question
+	translations map[uint]entity.QuestionTranslation // по ID вопроса, только kk
+}
+
+func newFakeTranslationRepo(questions ...entity.Question) *fakeTranslationRepo {
+	return &fakeTranslationRepo{questions: questions, translations: make(map[uint]entity.QuestionTranslation)}
+}
+
+func (r *fakeTranslationRepo) ListByQuestionIDs(questionIDs []uint) ([]entity.QuestionTranslation, error) {
+	var result []entity.QuestionTranslation
+	for _, id := range questionIDs {
+		if t, ok := r.translations[id]; ok {
+			result = append(result, t)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeTranslationRepo) Get(questionID uint, locale string) (*entity.QuestionTranslation, error) {
+	t, ok := r.translations[questionID]
+	if !ok || t.Locale != locale {
+		return nil, apperrors.ErrNotFound
+	}
+	return &t, nil
+}
+
+func (r *fakeTranslationRepo) Upsert(translation *entity.QuestionTranslation) error {
+	r.translations[translation.QuestionID] = *translation
+	return nil
+}
+
+func (r *fakeTranslationRepo) CreateIfMissing(translation *entity.QuestionTranslation) (bool, error) {
+	if _, ok := r.translations[translation.QuestionID]; ok {
+		return false, nil
+	}
+	r.translations[translation.QuestionID] = *translation
+	return true, nil
+}
+
+func (r *fakeTranslationRepo) Delete(questionID uint, locale string) error {
+	delete(r.translations, questionID)
+	return nil
+}
+
+func (r *fakeTranslationRepo) ListMachineTranslated(locale string, limit, offset int) ([]entity.QuestionTranslation, int64, error) {
+	var result []entity.QuestionTranslation
+	for _, t := range r.translations {
+		if t.MachineTranslated {
+			result = append(result, t)
+		}
+	}
+	return result, int64(len(result)), nil
+}
+
+func (r *fakeTranslationRepo) ListUntranslated(locale string, afterID uint, limit int) ([]entity.Question, error) {
+	sort.Slice(r.questions, func(i, j int) bool { return r.questions[i].ID < r.questions[j].ID })
+	var result []entity.Question
+	for _, q := range r.questions {
+		if _, ok := r.translations[q.ID]; ok || q.ID <= afterID || q.TextKK != "" {
+			continue
+		}
+		if len(result) == limit {
+			break
+		}
+		result = append(result, q)
+	}
+	return result, nil
+}
+
+// fakeMachineTranslator помечает строки префиксом языка; replies подменяет ответ по исходному тексту вопроса
+type fakeMachineTranslator struct {
+	replies map[string][]string
+	err     error
+	calls   int
+}
+
+func (t *fakeMachineTranslator) Name() string { return "fake" }
+
+func (t *fakeMachineTranslator) Translate(ctx context.Context, source, target string, texts []string) ([]string, error) {
+	t.calls++
+	if t.err != nil {
+		return nil, t.err
+	}
+	if reply, ok := t.replies[texts[0]]; ok {
+		return reply, nil
+	}
+	translated := make([]string, len(texts))
+	for i, text := range texts {
+		translated[i] = target + ": " + text
+	}
+	return translated, nil
+}
+
+// translationQuestionRepo хранит вопросы для TranslationService
+type translationQuestionRepo struct {
+	repository.QuestionRepository
+	questions map[uint]*entity.Question
+}
+
+func (r *translationQuestionRepo) GetByID(id uint) (*entity.Question, error) {
+	q, ok := r.questions[id]
+	if !ok {
+		return nil, apperrors.ErrNotFound
+	}
+	copied := *q
+	return &copied, nil
+}
+
+func (r *translationQuestionRepo) Update(question *entity.Question) error {
+	copied := *question
+	r.questions[question.ID] = &copied
+	return nil
+}
+
+func assistQuestion(id uint, text string) entity.Question {
+	return entity.Question{ID: id, Text: text, Options: entity.StringArray{"Да", "Нет"}}
+}
+
+func TestTranslationAssistService_Run(t *testing.T) {
+	repo := newFakeTranslationRepo(
+		assistQuestion(1, "Столица Казахстана?"),
+		assistQuestion(2, "Сломанный ответ"),
+		entity.Question{ID: 3, Text: "Уже переведён", Options: entity.StringArray{"А", "Б"}, TextKK: "Аударылған"},
+		assistQuestion(4, "Самая длинная река?"),
+	)
+	translator := &fakeMachineTranslator{replies: map[string][]string{"Сломанный ответ": {"только текст"}}}
+	svc := NewTranslationAssistService(repo, translator, "ru", 10, time.Second)
+
+	run, err := svc.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, run.Translated)
+	assert.Equal(t, 1, run.Failed, "число строк перевода не совпало с вопросом")
+	assert.NotNil(t, run.CompletedAt)
+	assert.Empty(t, run.Error)
+
+	translation := repo.translations[1]
+	assert.Equal(t, "kk", translation.Locale)
+	assert.Equal(t, "kk: Столица Казахстана?", translation.Text)
+	assert.Equal(t, entity.StringArray{"kk: Да", "kk: Нет"}, translation.Options)
+	assert.True(t, translation.MachineTranslated)
+	assert.Equal(t, "fake", translation.MTProvider)
+	assert.NotContains(t, repo.translations, uint(3), "вопрос с text_kk уже переведён")
+
+	status := svc.Status()
+	assert.False(t, status.Running)
+	assert.Equal(t, "kk", status.Locale)
+	require.NotNil(t, status.LastRun)
+	assert.Equal(t, 2, status.LastRun.Translated)
+
+	// Пул пройден: следующий проход снова начинает с начала и повторяет непереведённый вопрос
+	translator.replies = nil
+	run, err = svc.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, run.Translated)
+	assert.Contains(t, repo.translations, uint(2))
+}
+
+func TestTranslationAssistService_BatchCursor(t *testing.T) {
+	repo := newFakeTranslationRepo(assistQuestion(1, "Один"), assistQuestion(2, "Два"), assistQuestion(3, "Три"))
+	translator := &fakeMachineTranslator{replies: map[string][]string{"Один": {"", "Иә", "Жоқ"}}}
+	svc := NewTranslationAssistService(repo, translator, "ru", 2, time.Second)
+
+	run, err := svc.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, run.Translated)
+	assert.Equal(t, 1, run.Failed, "пустой текст перевода")
+
+	// Второй проход продолжает после вопроса 2, а не застревает на непригодном переводе
+	run, err = svc.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, run.Translated)
+	assert.Contains(t, repo.translations, uint(3))
+	assert.NotContains(t, repo.translations, uint(1))
+}
+
+func TestTranslationAssistService_ProviderFailure(t *testing.T) {
+	repo := newFakeTranslationRepo(assistQuestion(1, "Один"), assistQuestion(2, "Два"))
+	translator := &fakeMachineTranslator{err: errors.New("HTTP 403: API key not valid")}
+	svc := NewTranslationAssistService(repo, translator, "ru", 10, time.Second)
+
+	run, err := svc.Run(context.Background())
+	require.NoError(t, err)
+	assert.Contains(t, run.Error, "API key not valid")
+	assert.Equal(t, 1, translator.calls, "ошибка сервиса прерывает проход")
+	assert.Empty(t, repo.translations)
+
+	// Вопрос не пропускается: после восстановления сервиса он переводится первым
+	translator.err = nil
+	run, err = svc.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, run.Translated)
+}
+
+func TestTranslationAssistService_TriggerConflict(t *testing.T) {
+	svc := NewTranslationAssistService(newFakeTranslationRepo(), &fakeMachineTranslator{}, "ru", 10, time.Second)
+	require.True(t, svc.begin())
+
+	assert.ErrorIs(t, svc.Trigger(), apperrors.ErrConflict)
+	_, err := svc.Run(context.Background())
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+	assert.True(t, svc.Status().Running)
+}
+
+func TestTranslationService_AcceptMachineTranslation(t *testing.T) {
+	question := assistQuestion(5, "Столица Казахстана?")
+	questions := &translationQuestionRepo{questions: map[uint]*entity.Question{5: &question}}
+	repo := newFakeTranslationRepo()
+	repo.translations[5] = entity.QuestionTranslation{
+		QuestionID: 5, Locale: "kk", Text: "Қазақстанның астанасы?", Options: entity.StringArray{"Иә", "Жок"},
+		MachineTranslated: true, MTProvider: "google",
+	}
+	svc := NewTranslationService(repo, questions, nil, i18n.NewLocales("ru", []string{"ru", "kk", "en"}))
+
+	// Пока перевод не проверен, text_kk пуст — викторины с require_verified_kk вопрос не получат
+	assert.Empty(t, questions.questions[5].TextKK)
+
+	accepted, err := svc.AcceptMachineTranslation(5, "kk", 7, "", []string{"Иә", "Жоқ"})
+	require.NoError(t, err)
+	assert.False(t, accepted.MachineTranslated)
+	assert.Equal(t, "google", accepted.MTProvider)
+	require.NotNil(t, accepted.ReviewedBy)
+	assert.Equal(t, uint(7), *accepted.ReviewedBy)
+	assert.NotNil(t, accepted.ReviewedAt)
+	assert.Equal(t, "Қазақстанның астанасы?", accepted.Text, "без правки остаётся машинный текст")
+	assert.Equal(t, entity.StringArray{"Иә", "Жоқ"}, repo.translations[5].Options)
+	assert.Equal(t, "Қазақстанның астанасы?", questions.questions[5].TextKK, "проверенный перевод попадает в text_kk")
+
+	_, err = svc.AcceptMachineTranslation(5, "kk", 7, "", nil)
+	assert.ErrorIs(t, err, apperrors.ErrConflict, "перевод уже проверен")
+	_, err = svc.AcceptMachineTranslation(6, "kk", 7, "", nil)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	// Ручной перевод снимает отметку машинного
+	repo.translations[5] = entity.QuestionTranslation{QuestionID: 5, Locale: "kk", Text: "x", Options: entity.StringArray{"a", "b"}, MachineTranslated: true}
+	_, err = svc.UpsertTranslation(5, "kk", "Астана?", []string{"Иә", "Жоқ"})
+	require.NoError(t, err)
+	assert.False(t, repo.translations[5].MachineTranslated)
+}
+
+func TestQuestion_LocalizePrefersVerifiedKazakh(t *testing.T) {
+	question := entity.Question{ID: 1, Text: "Вопрос", Options: entity.StringArray{"А", "Б"}, TextKK: "Тексерілген"}
+	machine := []entity.QuestionTranslation{{QuestionID: 1, Locale: "kk", Text: "Машиналық", Options: entity.StringArray{"А", "Б"}, MachineTranslated: true}}
+
+	assert.Equal(t, "Тексерілген", question.Localize(machine, []string{"kk"}, "ru").Text)
+	question.TextKK = ""
+	assert.Equal(t, "Машиналық", question.Localize(machine, []string{"kk"}, "ru").Text, "без проверенного текста — машинный перевод")
+}
+
+func TestGoogleTranslator(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/language/translate/v2", r.URL.Path)
+		if r.URL.Query().Get("key") != "secret" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error": {"code": 403, "message": "API key not valid"}}`))
+			return
+		}
+		var body struct {
+			Q              []string
+			Source, Target string
+			Format         string
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, []string{"Да", "Нет"}, body.Q)
+		assert.Equal(t, "ru", body.Source)
+		assert.Equal(t, "kk", body.Target)
+		assert.Equal(t, "text", body.Format)
+		_, _ = w.Write([]byte(`{"data": {"translations": [{"translatedText": "Иә"}, {"translatedText": "Жоқ"}]}}`))
+	}))
+	defer server.Close()
+
+	translator, err := NewGoogleTranslator(server.URL, "secret", time.Second)
+	require.NoError(t, err)
+	translated, err := translator.Translate(context.Background(), "ru", "kk", []string{"Да", "Нет"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Иә", "Жоқ"}, translated)
+
+	translator, err = NewGoogleTranslator(server.URL, "wrong", time.Second)
+	require.NoError(t, err)
+	_, err = translator.Translate(context.Background(), "ru", "kk", []string{"Да"})
+	assert.ErrorContains(t, err, "API key not valid")
+	assert.NotContains(t, err.Error(), "wrong", "ключ API не попадает в ошибку")
+}
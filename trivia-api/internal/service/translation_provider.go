@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/config"
+)
+
+// Сервисы машинного перевода
+const (
+	MachineTranslatorGoogle = "google"
+	MachineTranslatorLLM    = "llm"
+)
+
+// MachineTranslator переводит тексты сервисом машинного перевода
+type MachineTranslator interface {
+	Name() string
+	// Translate переводит texts с языка source на target; результат — в том же порядке
+	Translate(ctx context.Context, source, target string, texts []string) ([]string, error)
+}
+
+// NewMachineTranslatorFromConfig создает переводчик по секции translationAssist.
+// Провайдер llm использует модель из секции aiQuestions.
+func NewMachineTranslatorFromConfig(cfg config.TranslationAssistConfig, aiCfg config.AIQuestionsConfig) (MachineTranslator, error) {
+	switch cfg.Provider {
+	case MachineTranslatorGoogle:
+		return NewGoogleTranslator(cfg.Google.BaseURL, cfg.Google.APIKey, time.Duration(cfg.TimeoutSec)*time.Second)
+	case MachineTranslatorLLM:
+		provider, err := NewLLMProviderFromConfig(aiCfg)
+		if err != nil {
+			return nil, err
+		}
+		return NewLLMTranslator(provider, aiCfg.MaxTokens), nil
+	default:
+		return nil, fmt.Errorf("unknown machine translation provider %q", cfg.Provider)
+	}
+}
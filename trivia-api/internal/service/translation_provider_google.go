@@ -0,0 +1,92 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// GoogleTranslator обращается к Google Cloud Translation API (v2, ключ API)
+type GoogleTranslator struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+// NewGoogleTranslator создает переводчик; пустой baseURL — https://translation.googleapis.com
+func NewGoogleTranslator(baseURL, apiKey string, timeout time.Duration) (*GoogleTranslator, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("google translation api key is required")
+	}
+	if baseURL == "" {
+		baseURL = "https://translation.googleapis.com"
+	}
+	return &GoogleTranslator{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		client:  &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func (t *GoogleTranslator) Name() string { return MachineTranslatorGoogle }
+
+func (t *GoogleTranslator) Translate(ctx context.Context, source, target string, texts []string) ([]string, error) {
+	payload, err := json.Marshal(map[string]interface{}{
+		"q":      texts,
+		"source": source,
+		"target": target,
+		"format": "text", // без HTML-экранирования кавычек и амперсандов
+	})
+	if err != nil {
+		return nil, err
+	}
+	endpoint := t.baseURL + "/language/translate/v2?key=" + url.QueryEscape(t.apiKey)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		// Ошибка клиента содержит URL с ключом
+		return nil, fmt.Errorf("google translate request failed: %w", errors.Unwrap(err))
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read google translate response: %w", err)
+	}
+
+	var result struct {
+		Data struct {
+			Translations []struct {
+				TranslatedText string `json:"translatedText"`
+			} `json:"translations"`
+		} `json:"data"`
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil && resp.StatusCode == http.StatusOK {
+		return nil, fmt.Errorf("failed to decode google translate response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if result.Error.Message != "" {
+			return nil, fmt.Errorf("google translate returned HTTP %d: %s", resp.StatusCode, result.Error.Message)
+		}
+		return nil, fmt.Errorf("google translate returned HTTP %d", resp.StatusCode)
+	}
+	translated := make([]string, len(result.Data.Translations))
+	for i, translation := range result.Data.Translations {
+		translated[i] = translation.TranslatedText
+	}
+	return translated, nil
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// llmTranslationSystem — инструкции модели для перевода вопросов викторины
+const llmTranslationSystem = `You translate trivia questions and answer options. ` +
+	`The user sends a JSON array of strings; reply with a JSON array of their translations ` +
+	`in the same order and of the same length, and nothing else. Keep answers short, ` +
+	`do not add explanations, quotes or numbering.`
+
+// llmLanguageNames — названия языков контента для инструкции модели
+var llmLanguageNames = map[string]string{"ru": "Russian", "kk": "Kazakh", "en": "English"}
+
+// LLMTranslator переводит тексты языковой моделью (секция aiQuestions)
+type LLMTranslator struct {
+	provider  LLMProvider
+	maxTokens int
+}
+
+// NewLLMTranslator создает переводчик поверх провайдера языковой модели
+func NewLLMTranslator(provider LLMProvider, maxTokens int) *LLMTranslator {
+	return &LLMTranslator{provider: provider, maxTokens: maxTokens}
+}
+
+func (t *LLMTranslator) Name() string { return MachineTranslatorLLM }
+
+func (t *LLMTranslator) Translate(ctx context.Context, source, target string, texts []string) ([]string, error) {
+	payload, err := json.Marshal(texts)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.provider.Complete(ctx, LLMRequest{
+		System:    llmTranslationSystem,
+		Prompt:    fmt.Sprintf("Translate from %s to %s:\n%s", languageName(source), languageName(target), payload),
+		MaxTokens: t.maxTokens,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var translated []string
+	if err := decodeModelJSONArray(resp.Text, &translated); err != nil {
+		return nil, err
+	}
+	return translated, nil
+}
+
+func languageName(locale string) string {
+	if name, ok := llmLanguageNames[locale]; ok {
+		return name
+	}
+	return locale
+}
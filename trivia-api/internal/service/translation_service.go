@@ -0,0 +1,277 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/pkg/i18n"
+)
+
+// maxQuestionTextLength — предел длины текста вопроса (questions.text VARCHAR(500))
+const maxQuestionTextLength = 500
+
+// TranslationService управляет переводами вопросов и выбором языка контента
+type TranslationService struct {
+	translationRepo repository.QuestionTranslationRepository
+	questionRepo    repository.QuestionRepository
+	userRepo        repository.UserRepository
+	locales         *i18n.Locales
+	quizCache       QuizCacheInvalidator
+}
+
+// NewTranslationService создает новый сервис переводов
+func NewTranslationService(
+	translationRepo repository.QuestionTranslationRepository,
+	questionRepo repository.QuestionRepository,
+	userRepo repository.UserRepository,
+	locales *i18n.Locales,
+) *TranslationService {
+	return &TranslationService{
+		translationRepo: translationRepo,
+		questionRepo:    questionRepo,
+		userRepo:        userRepo,
+		locales:         locales,
+	}
+}
+
+// SetQuizCache подключает сброс кеша викторины при изменении полей text_kk/options_kk её вопросов
+func (s *TranslationService) SetQuizCache(invalidator QuizCacheInvalidator) {
+	s.quizCache = invalidator
+}
+
+// Locales возвращает поддерживаемые языки контента
+func (s *TranslationService) Locales() *i18n.Locales {
+	return s.locales
+}
+
+// NegotiateLocale выбирает язык ответа: явный выбор клиента, язык из профиля
+// пользователя (userID 0 — аноним), Accept-Language, язык по умолчанию
+func (s *TranslationService) NegotiateLocale(explicit string, userID uint, acceptLanguage string) i18n.Resolution {
+	userPreference := ""
+	if userID != 0 && explicit == "" {
+		user, err := s.userRepo.GetByID(userID)
+		if err != nil {
+			log.Printf("[TranslationService] Не удалось получить язык пользователя %d: %v", userID, err)
+		} else {
+			userPreference = user.Language
+		}
+	}
+	return s.locales.Negotiate(explicit, userPreference, acceptLanguage)
+}
+
+// ListTranslations возвращает все переводы вопроса
+func (s *TranslationService) ListTranslations(questionID uint) ([]entity.QuestionTranslation, error) {
+	if _, err := s.questionRepo.GetByID(questionID); err != nil {
+		return nil, err
+	}
+	return s.translationRepo.ListByQuestionIDs([]uint{questionID})
+}
+
+// UpsertTranslation создаёт или заменяет перевод вопроса на язык locale.
+// Число вариантов должно совпадать с основными: индекс правильного ответа общий.
+func (s *TranslationService) UpsertTranslation(questionID uint, locale, text string, options []string) (*entity.QuestionTranslation, error) {
+	locale, err := s.translatableLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+	question, err := s.questionRepo.GetByID(questionID)
+	if err != nil {
+		return nil, err
+	}
+	text, trimmed, err := normalizeTranslation(question, text, options)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ручной перевод заменяет машинный целиком, включая отметку о нём
+	translation := &entity.QuestionTranslation{QuestionID: questionID, Locale: locale, Text: text, Options: trimmed}
+	if err := s.translationRepo.Upsert(translation); err != nil {
+		return nil, err
+	}
+	if locale == "kk" {
+		s.syncLegacyKazakh(question, text, trimmed)
+	}
+	return translation, nil
+}
+
+// AcceptMachineTranslation отмечает машинный перевод проверенным администратором adminID.
+// Непустые text/options заменяют машинный текст правкой администратора.
+// Для казахского проверенный текст попадает в text_kk — такие вопросы получают и викторины
+// с require_verified_kk.
+func (s *TranslationService) AcceptMachineTranslation(questionID uint, locale string, adminID uint, text string, options []string) (*entity.QuestionTranslation, error) {
+	locale, err := s.translatableLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+	question, err := s.questionRepo.GetByID(questionID)
+	if err != nil {
+		return nil, err
+	}
+	translation, err := s.translationRepo.Get(questionID, locale)
+	if err != nil {
+		return nil, err
+	}
+	if !translation.MachineTranslated {
+		return nil, fmt.Errorf("%w: translation is not machine-translated or already reviewed", apperrors.ErrConflict)
+	}
+
+	if text == "" {
+		text = translation.Text
+	}
+	if options == nil {
+		options = translation.Options
+	}
+	text, trimmed, err := normalizeTranslation(question, text, options)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	accepted := &entity.QuestionTranslation{
+		QuestionID: questionID,
+		Locale:     locale,
+		Text:       text,
+		Options:    trimmed,
+		MTProvider: translation.MTProvider,
+		ReviewedBy: &adminID,
+		ReviewedAt: &now,
+	}
+	if err := s.translationRepo.Upsert(accepted); err != nil {
+		return nil, err
+	}
+	if locale == "kk" {
+		s.syncLegacyKazakh(question, text, trimmed)
+	}
+	return accepted, nil
+}
+
+// ListMachineTranslated возвращает непроверенные машинные переводы на язык locale
+func (s *TranslationService) ListMachineTranslated(locale string, page, pageSize int) ([]entity.QuestionTranslation, int64, error) {
+	locale, err := s.translatableLocale(locale)
+	if err != nil {
+		return nil, 0, err
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return s.translationRepo.ListMachineTranslated(locale, pageSize, (page-1)*pageSize)
+}
+
+// DeleteTranslation удаляет перевод вопроса на язык locale
+func (s *TranslationService) DeleteTranslation(questionID uint, locale string) error {
+	locale, err := s.translatableLocale(locale)
+	if err != nil {
+		return err
+	}
+	question, err := s.questionRepo.GetByID(questionID)
+	if err != nil {
+		return err
+	}
+	if err := s.translationRepo.Delete(questionID, locale); err != nil {
+		// Казахский текст мог остаться только в устаревших полях вопроса
+		if !(locale == "kk" && errors.Is(err, apperrors.ErrNotFound) && question.TextKK != "") {
+			return err
+		}
+	}
+	if locale == "kk" {
+		s.syncLegacyKazakh(question, "", nil)
+	}
+	return nil
+}
+
+// LocalizeQuestions возвращает тексты вопросов на выбранном языке (по ID вопроса)
+func (s *TranslationService) LocalizeQuestions(questions []entity.Question, resolution i18n.Resolution) (map[uint]entity.LocalizedQuestion, error) {
+	ids := make([]uint, len(questions))
+	for i := range questions {
+		ids[i] = questions[i].ID
+	}
+	translations, err := s.translationRepo.ListByQuestionIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byQuestion := make(map[uint][]entity.QuestionTranslation, len(questions))
+	for _, t := range translations {
+		byQuestion[t.QuestionID] = append(byQuestion[t.QuestionID], t)
+	}
+	chain := resolution.Chain()
+	localized := make(map[uint]entity.LocalizedQuestion, len(questions))
+	for i := range questions {
+		q := &questions[i]
+		localized[q.ID] = q.Localize(byQuestion[q.ID], chain, s.locales.Default())
+	}
+	return localized, nil
+}
+
+// QuestionTranslations возвращает вопрос на всех поддерживаемых языках, для которых есть
+// перевод (по коду языка). Используется в рассылке вопроса, где клиент сам выбирает язык.
+func (s *TranslationService) QuestionTranslations(question *entity.Question) (map[string]entity.LocalizedQuestion, error) {
+	translations, err := s.translationRepo.ListByQuestionIDs([]uint{question.ID})
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]entity.LocalizedQuestion, len(s.locales.Supported()))
+	for _, locale := range s.locales.Supported() {
+		localized := question.Localize(translations, []string{locale}, s.locales.Default())
+		if localized.Locale == locale {
+			result[locale] = localized
+		}
+	}
+	return result, nil
+}
+
+// normalizeTranslation проверяет текст и варианты перевода вопроса и обрезает пробелы.
+// Число вариантов должно совпадать с основными: индекс правильного ответа общий.
+func normalizeTranslation(question *entity.Question, text string, options []string) (string, entity.StringArray, error) {
+	text = strings.TrimSpace(text)
+	if text == "" || utf8.RuneCountInString(text) > maxQuestionTextLength {
+		return "", nil, fmt.Errorf("%w: text must be between 1 and %d characters", apperrors.ErrValidation, maxQuestionTextLength)
+	}
+	if len(options) != len(question.Options) {
+		return "", nil, fmt.Errorf("%w: expected %d options, got %d", apperrors.ErrValidation, len(question.Options), len(options))
+	}
+	trimmed := make(entity.StringArray, len(options))
+	for i, option := range options {
+		if trimmed[i] = strings.TrimSpace(option); trimmed[i] == "" {
+			return "", nil, fmt.Errorf("%w: option %d must not be empty", apperrors.ErrValidation, i)
+		}
+	}
+	return text, trimmed, nil
+}
+
+// translatableLocale нормализует язык перевода и проверяет, что он поддерживается
+// и не совпадает с языком основных полей вопроса
+func (s *TranslationService) translatableLocale(locale string) (string, error) {
+	locale = i18n.Normalize(locale)
+	if !s.locales.IsSupported(locale) {
+		return "", fmt.Errorf("%w: unsupported locale %q, allowed: %s", apperrors.ErrValidation, locale, strings.Join(s.locales.Supported(), ", "))
+	}
+	if locale == s.locales.Default() {
+		return "", fmt.Errorf("%w: %q is the default locale, edit the question itself", apperrors.ErrValidation, locale)
+	}
+	return locale, nil
+}
+
+// syncLegacyKazakh копирует казахский перевод в поля text_kk/options_kk, которые ещё
+// читают старые клиенты. Ошибка не отменяет сохранённый перевод.
+func (s *TranslationService) syncLegacyKazakh(question *entity.Question, text string, options entity.StringArray) {
+	question.TextKK = text
+	question.OptionsKK = options
+	if err := s.questionRepo.Update(question); err != nil {
+		log.Printf("[TranslationService] Не удалось обновить text_kk вопроса %d: %v", question.ID, err)
+		return
+	}
+	if s.quizCache != nil && question.QuizID != nil {
+		s.quizCache.InvalidateQuiz(*question.QuizID)
+	}
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+const (
+	// DefaultUserAnalyticsDays — период личной статистики по умолчанию
+	DefaultUserAnalyticsDays = 90
+	// MaxUserAnalyticsDays — максимальный период личной статистики
+	MaxUserAnalyticsDays = 365
+
+	userAnalyticsCacheTTL = 10 * time.Minute
+	// userAnalyticsMinCategoryAnswers — сколько ответов нужно в теме, чтобы она попала в сильные или слабые
+	userAnalyticsMinCategoryAnswers = 5
+	userAnalyticsTopCategories      = 3
+)
+
+// AccuracyStats — доля верных ответов
+type AccuracyStats struct {
+	Answered int64   `json:"answered"`
+	Correct  int64   `json:"correct"`
+	Accuracy float64 `json:"accuracy"` // проценты, один знак после запятой
+}
+
+// DifficultyAccuracyStats — точность ответов на вопросы одной сложности (1–5)
+type DifficultyAccuracyStats struct {
+	Difficulty int `json:"difficulty"`
+	AccuracyStats
+}
+
+// CategoryAccuracyStats — точность ответов на вопросы одной темы
+type CategoryAccuracyStats struct {
+	CategoryID uint   `json:"category_id"`
+	Slug       string `json:"slug"`
+	Name       string `json:"name"`
+	AccuracyStats
+}
+
+// EliminationHistogram — на каких вопросах игрок выбывал
+type EliminationHistogram struct {
+	QuizzesPlayed int64                         `json:"quizzes_played"`
+	Survived      int64                         `json:"survived"` // викторины, пройденные без выбывания
+	Stages        []repository.EliminationStage `json:"stages"`
+}
+
+// UserAnalytics — личная статистика игрока для экрана «Ваша статистика»
+type UserAnalytics struct {
+	Days                 int                             `json:"days"`
+	From                 time.Time                       `json:"from"`
+	GeneratedAt          time.Time                       `json:"generated_at"`
+	Overall              AccuracyStats                   `json:"overall"`
+	AccuracyByDifficulty []DifficultyAccuracyStats       `json:"accuracy_by_difficulty"`
+	ResponseTime         []repository.WeeklyResponseTime `json:"response_time"`
+	Eliminations         EliminationHistogram            `json:"eliminations"`
+	Categories           []CategoryAccuracyStats         `json:"categories"`
+	Strengths            []CategoryAccuracyStats         `json:"strengths"`
+	Weaknesses           []CategoryAccuracyStats         `json:"weaknesses"`
+}
+
+// UserAnalyticsService собирает личную статистику игрока по истории ответов
+type UserAnalyticsService struct {
+	repo      repository.UserAnalyticsRepository
+	cacheRepo repository.CacheRepository
+}
+
+// NewUserAnalyticsService создает сервис личной статистики
+func NewUserAnalyticsService(repo repository.UserAnalyticsRepository, cacheRepo repository.CacheRepository) (*UserAnalyticsService, error) {
+	if repo == nil {
+		return nil, fmt.Errorf("user analytics repository is required")
+	}
+	return &UserAnalyticsService{repo: repo, cacheRepo: cacheRepo}, nil
+}
+
+// GetUserAnalytics возвращает статистику игрока за последние days дней. Результат кешируется на 10 минут:
+// статистика меняется только после викторин.
+func (s *UserAnalyticsService) GetUserAnalytics(userID uint, days int) (*UserAnalytics, error) {
+	if days < 1 || days > MaxUserAnalyticsDays {
+		return nil, fmt.Errorf("%w: days must be between 1 and %d", apperrors.ErrValidation, MaxUserAnalyticsDays)
+	}
+
+	cacheKey := fmt.Sprintf("analytics:user:%d:%d", userID, days)
+	if s.cacheRepo != nil {
+		var cached UserAnalytics
+		err := s.cacheRepo.GetJSON(cacheKey, &cached)
+		if err == nil {
+			return &cached, nil
+		}
+		if !errors.Is(err, apperrors.ErrNotFound) {
+			log.Printf("[UserAnalyticsService] Ошибка чтения кеша статистики пользователя #%d: %v", userID, err)
+		}
+	}
+
+	analytics, err := s.buildUserAnalytics(userID, days)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.cacheRepo != nil {
+		if err := s.cacheRepo.SetJSON(cacheKey, analytics, userAnalyticsCacheTTL); err != nil {
+			log.Printf("[UserAnalyticsService] Ошибка сохранения статистики пользователя #%d в кеш: %v", userID, err)
+		}
+	}
+	return analytics, nil
+}
+
+func (s *UserAnalyticsService) buildUserAnalytics(userID uint, days int) (*UserAnalytics, error) {
+	now := time.Now().UTC()
+	from := now.Truncate(24*time.Hour).AddDate(0, 0, -(days - 1))
+
+	byDifficulty, err := s.repo.UserAccuracyByDifficulty(userID, from)
+	if err != nil {
+		return nil, err
+	}
+	responseTime, err := s.repo.UserWeeklyResponseTime(userID, from)
+	if err != nil {
+		return nil, err
+	}
+	stages, err := s.repo.UserEliminationStages(userID, from)
+	if err != nil {
+		return nil, err
+	}
+	categoryRows, err := s.repo.UserCategoryAccuracy(userID, from)
+	if err != nil {
+		return nil, err
+	}
+	played, err := s.repo.CountUserQuizzes(userID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	analytics := &UserAnalytics{
+		Days:                 days,
+		From:                 from,
+		GeneratedAt:          now,
+		AccuracyByDifficulty: []DifficultyAccuracyStats{},
+		ResponseTime:         responseTime,
+		Eliminations:         EliminationHistogram{QuizzesPlayed: played, Stages: stages},
+		Categories:           make([]CategoryAccuracyStats, 0, len(categoryRows)),
+	}
+	if analytics.ResponseTime == nil {
+		analytics.ResponseTime = []repository.WeeklyResponseTime{}
+	}
+	if analytics.Eliminations.Stages == nil {
+		analytics.Eliminations.Stages = []repository.EliminationStage{}
+	}
+
+	for _, row := range byDifficulty {
+		stats := newAccuracyStats(row.Answered, row.Correct)
+		if row.Difficulty == 0 {
+			analytics.Overall = stats
+			continue
+		}
+		analytics.AccuracyByDifficulty = append(analytics.AccuracyByDifficulty, DifficultyAccuracyStats{Difficulty: row.Difficulty, AccuracyStats: stats})
+	}
+
+	var eliminated int64
+	for _, stage := range stages {
+		eliminated += stage.Count
+	}
+	if survived := played - eliminated; survived > 0 {
+		analytics.Eliminations.Survived = survived
+	}
+
+	for _, row := range categoryRows {
+		analytics.Categories = append(analytics.Categories, CategoryAccuracyStats{
+			CategoryID:    row.CategoryID,
+			Slug:          row.Slug,
+			Name:          row.Name,
+			AccuracyStats: newAccuracyStats(row.Answered, row.Correct),
+		})
+	}
+	analytics.Strengths, analytics.Weaknesses = rankCategories(analytics.Categories)
+	return analytics, nil
+}
+
+// rankCategories выбирает сильные и слабые темы среди тем с достаточным числом ответов.
+// Тема с равной точностью ранжируется по числу ответов; одна тема не попадает в оба списка.
+func rankCategories(categories []CategoryAccuracyStats) (strengths, weaknesses []CategoryAccuracyStats) {
+	ranked := make([]CategoryAccuracyStats, 0, len(categories))
+	for _, c := range categories {
+		if c.Answered >= userAnalyticsMinCategoryAnswers {
+			ranked = append(ranked, c)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Accuracy != ranked[j].Accuracy {
+			return ranked[i].Accuracy > ranked[j].Accuracy
+		}
+		return ranked[i].Answered > ranked[j].Answered
+	})
+
+	top := userAnalyticsTopCategories
+	if len(ranked) < 2*top {
+		top = len(ranked) / 2
+	}
+	strengths = append([]CategoryAccuracyStats{}, ranked[:top]...)
+	weaknesses = make([]CategoryAccuracyStats, 0, top)
+	for i := len(ranked) - 1; i >= len(ranked)-top; i-- {
+		weaknesses = append(weaknesses, ranked[i])
+	}
+	return strengths, weaknesses
+}
+
+func newAccuracyStats(answered, correct int64) AccuracyStats {
+	stats := AccuracyStats{Answered: answered, Correct: correct}
+	if answered > 0 {
+		stats.Accuracy = math.Round(float64(correct)*1000/float64(answered)) / 10
+	}
+	return stats
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// fakeUserAnalyticsRepo возвращает заранее заданные агрегаты
+type fakeUserAnalyticsRepo struct {
+	difficulty []repository.DifficultyAccuracy
+	stages     []repository.EliminationStage
+	categories []repository.CategoryAccuracy
+	quizzes    int64
+	calls      int
+}
+
+func (f *fakeUserAnalyticsRepo) UserAccuracyByDifficulty(userID uint, from time.Time) ([]repository.DifficultyAccuracy, error) {
+	f.calls++
+	return f.difficulty, nil
+}
+
+func (f *fakeUserAnalyticsRepo) UserWeeklyResponseTime(userID uint, from time.Time) ([]repository.WeeklyResponseTime, error) {
+	return nil, nil
+}
+
+func (f *fakeUserAnalyticsRepo) UserEliminationStages(userID uint, from time.Time) ([]repository.EliminationStage, error) {
+	return f.stages, nil
+}
+
+func (f *fakeUserAnalyticsRepo) UserCategoryAccuracy(userID uint, from time.Time) ([]repository.CategoryAccuracy, error) {
+	return f.categories, nil
+}
+
+func (f *fakeUserAnalyticsRepo) CountUserQuizzes(userID uint, from time.Time) (int64, error) {
+	return f.quizzes, nil
+}
+
+func TestUserAnalyticsService_GetUserAnalytics(t *testing.T) {
+	repo := &fakeUserAnalyticsRepo{
+		difficulty: []repository.DifficultyAccuracy{
+			{Difficulty: 0, Answered: 30, Correct: 20},
+			{Difficulty: 1, Answered: 10, Correct: 9},
+			{Difficulty: 5, Answered: 20, Correct: 11},
+		},
+		stages: []repository.EliminationStage{{QuestionNumber: 3, Count: 2}, {QuestionNumber: 7, Count: 1}},
+		categories: []repository.CategoryAccuracy{
+			{CategoryID: 1, Slug: "kino", Answered: 10, Correct: 9},
+			{CategoryID: 2, Slug: "sport", Answered: 10, Correct: 2},
+			{CategoryID: 3, Slug: "history", Answered: 4, Correct: 4}, // мало ответов для рейтинга
+			{CategoryID: 4, Slug: "music", Answered: 6, Correct: 3},
+		},
+		quizzes: 5,
+	}
+	svc, err := NewUserAnalyticsService(repo, nil)
+	require.NoError(t, err)
+
+	analytics, err := svc.GetUserAnalytics(7, 30)
+	require.NoError(t, err)
+
+	assert.Equal(t, AccuracyStats{Answered: 30, Correct: 20, Accuracy: 66.7}, analytics.Overall)
+	require.Len(t, analytics.AccuracyByDifficulty, 2, "итоговая строка ROLLUP не попадает в разбивку")
+	assert.Equal(t, 5, analytics.AccuracyByDifficulty[1].Difficulty)
+	assert.Equal(t, 55.0, analytics.AccuracyByDifficulty[1].Accuracy)
+
+	assert.Equal(t, int64(5), analytics.Eliminations.QuizzesPlayed)
+	assert.Equal(t, int64(2), analytics.Eliminations.Survived)
+	assert.NotNil(t, analytics.ResponseTime)
+
+	require.Len(t, analytics.Categories, 4)
+	require.Len(t, analytics.Strengths, 1)
+	assert.Equal(t, "kino", analytics.Strengths[0].Slug)
+	require.Len(t, analytics.Weaknesses, 1)
+	assert.Equal(t, "sport", analytics.Weaknesses[0].Slug)
+}
+
+func TestUserAnalyticsService_GetUserAnalytics_RejectsInvalidDays(t *testing.T) {
+	svc, err := NewUserAnalyticsService(&fakeUserAnalyticsRepo{}, nil)
+	require.NoError(t, err)
+
+	for _, days := range []int{0, -1, MaxUserAnalyticsDays + 1} {
+		_, err := svc.GetUserAnalytics(1, days)
+		assert.ErrorIs(t, err, apperrors.ErrValidation, "days=%d", days)
+	}
+}
+
+func TestRankCategories(t *testing.T) {
+	categories := make([]CategoryAccuracyStats, 0, 8)
+	for i := 0; i < 8; i++ {
+		categories = append(categories, CategoryAccuracyStats{
+			CategoryID:    uint(i + 1),
+			AccuracyStats: newAccuracyStats(10, int64(i+1)),
+		})
+	}
+
+	strengths, weaknesses := rankCategories(categories)
+
+	require.Len(t, strengths, userAnalyticsTopCategories)
+	require.Len(t, weaknesses, userAnalyticsTopCategories)
+	assert.Equal(t, uint(8), strengths[0].CategoryID)
+	assert.Equal(t, uint(1), weaknesses[0].CategoryID, "слабые темы — от худшей")
+}
@@ -1,23 +1,66 @@
 package service
 
 import (
+	"fmt"
 	"log"
 
+	"github.com/yourusername/trivia-api/internal/domain/entity"
 	"github.com/yourusername/trivia-api/internal/domain/repository"
 	"github.com/yourusername/trivia-api/internal/handler/dto"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/internal/pkg/httpcache"
+	"github.com/yourusername/trivia-api/internal/pkg/pagination"
 )
 
+// publicProfileRecentResults — сколько последних игр показывается в публичном профиле
+const publicProfileRecentResults = 5
+
 // UserService предоставляет методы для работы с пользователями
 type UserService struct {
-	userRepo repository.UserRepository
-	// Добавьте другие зависимости, если они понадобятся
+	userRepo   repository.UserRepository
+	resultRepo repository.ResultRepository
+	streakRepo repository.UserStreakRepository
+	rating     *RatingService
+	httpCache  httpcache.Invalidator
 }
 
 // NewUserService создает новый сервис пользователей
-func NewUserService(userRepo repository.UserRepository) *UserService {
+func NewUserService(userRepo repository.UserRepository, resultRepo repository.ResultRepository) *UserService {
 	return &UserService{
-		userRepo: userRepo,
+		userRepo:   userRepo,
+		resultRepo: resultRepo,
+	}
+}
+
+// SetHTTPCache подключает сброс кэша лидербордов, когда игрок скрывает себя из них или возвращается
+func (s *UserService) SetHTTPCache(invalidator httpcache.Invalidator) {
+	s.httpCache = invalidator
+}
+
+// SetStreakRepository подключает серии игроков к профилям
+func (s *UserService) SetStreakRepository(repo repository.UserStreakRepository) {
+	s.streakRepo = repo
+}
+
+// GetStreaks возвращает серии пользователя; без подключённого хранилища — nil
+func (s *UserService) GetStreaks(userID uint) (*entity.UserStreak, error) {
+	if s.streakRepo == nil {
+		return nil, nil
 	}
+	return s.streakRepo.GetByUserID(userID)
+}
+
+// SetRatingService подключает рейтинг мастерства к профилям
+func (s *UserService) SetRatingService(ratingService *RatingService) {
+	s.rating = ratingService
+}
+
+// GetRating возвращает рейтинг мастерства пользователя; рейтинг выключен — nil
+func (s *UserService) GetRating(userID uint) (*entity.UserRating, error) {
+	if s.rating == nil {
+		return nil, nil
+	}
+	return s.rating.GetUserRating(userID)
 }
 
 // GetLeaderboard возвращает пагинированный список пользователей для лидерборда.
@@ -44,14 +87,7 @@ func (s *UserService) GetLeaderboard(page, pageSize int) (*dto.PaginatedLeaderbo
 	// Преобразуем пользователей в DTO
 	userDTOs := make([]*dto.LeaderboardUserDTO, len(users))
 	for i, user := range users {
-		userDTOs[i] = &dto.LeaderboardUserDTO{
-			Rank:           offset + i + 1, // Рассчитываем ранг на основе смещения и индекса
-			UserID:         user.ID,
-			Username:       user.Username,
-			ProfilePicture: user.ProfilePicture,
-			WinsCount:      user.WinsCount,
-			TotalPrizeWon:  user.TotalPrizeWon,
-		}
+		userDTOs[i] = newLeaderboardUserDTO(offset+i+1, user) // Рассчитываем ранг на основе смещения и индекса
 	}
 
 	// Формируем пагинированный ответ
@@ -64,3 +100,171 @@ func (s *UserService) GetLeaderboard(page, pageSize int) (*dto.PaginatedLeaderbo
 
 	return response, nil
 }
+
+// GetLeaderboardByCursor возвращает страницу лидерборда после курсора cursor (пустой курсор —
+// первая страница). Места продолжают нумерацию предыдущей страницы: позиция хранится в курсоре.
+func (s *UserService) GetLeaderboardByCursor(cursor string, pageSize int) (*dto.CursorLeaderboardResponse, error) {
+	if pageSize < 1 {
+		pageSize = 10
+	} else if pageSize > 100 {
+		pageSize = 100
+	}
+
+	var after repository.LeaderboardCursor
+	ok, err := pagination.DecodeCursor(cursor, &after)
+	if err != nil {
+		return nil, err
+	}
+	var afterPtr *repository.LeaderboardCursor
+	if ok {
+		if after.Position < 0 {
+			after.Position = 0
+		}
+		afterPtr = &after
+	}
+
+	// Запрашиваем на одного пользователя больше, чтобы понять, есть ли следующая страница
+	users, err := s.userRepo.GetLeaderboardAfter(afterPtr, pageSize+1)
+	if err != nil {
+		log.Printf("[UserService] Ошибка при получении лидерборда по курсору: %v", err)
+		return nil, err
+	}
+	hasMore := len(users) > pageSize
+	if hasMore {
+		users = users[:pageSize]
+	}
+
+	userDTOs := make([]*dto.LeaderboardUserDTO, len(users))
+	for i, user := range users {
+		userDTOs[i] = newLeaderboardUserDTO(after.Position+i+1, user)
+	}
+
+	result := &dto.CursorLeaderboardResponse{Users: userDTOs, PerPage: pageSize}
+	if hasMore {
+		last := users[len(users)-1]
+		result.NextCursor, err = pagination.EncodeCursor(repository.LeaderboardCursor{
+			WinsCount:     last.WinsCount,
+			TotalPrizeWon: last.TotalPrizeWon,
+			ID:            last.ID,
+			Position:      after.Position + len(users),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// newLeaderboardUserDTO формирует строку публичного лидерборда; игрок, скрывший себя
+// из лидербордов (show_on_leaderboards), показывается заглушкой без ID и аватара
+func newLeaderboardUserDTO(rank int, user entity.User) *dto.LeaderboardUserDTO {
+	identity := publicLeaderboardIdentity(user.ID, user.Username, user.ProfilePicture, user.ShowOnLeaderboards)
+	return &dto.LeaderboardUserDTO{
+		Rank:           rank,
+		UserID:         identity.UserID,
+		Username:       identity.Username,
+		ProfilePicture: identity.ProfilePicture,
+		WinsCount:      user.WinsCount,
+		TotalPrizeWon:  user.TotalPrizeWon,
+		Anonymous:      identity.Anonymous,
+	}
+}
+
+// UpdatePrivacyInput содержит изменяемые настройки приватности профиля (nil — без изменений)
+type UpdatePrivacyInput struct {
+	ProfilePublic      *bool
+	ShowRecentResults  *bool
+	ShowFullName       *bool
+	ShowOnLeaderboards *bool
+}
+
+// GetPublicProfile возвращает публичный профиль пользователя userID глазами viewerID.
+// Владелец и администратор видят профиль целиком независимо от настроек приватности;
+// остальным скрытый профиль отдаётся без статистики, а последние игры — только если владелец их не скрыл.
+func (s *UserService) GetPublicProfile(userID, viewerID uint, viewerIsAdmin bool) (*dto.PublicProfileDTO, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.DeletedAt != nil {
+		return nil, apperrors.ErrNotFound
+	}
+
+	profile := &dto.PublicProfileDTO{
+		UserID:         user.ID,
+		Username:       user.Username,
+		ProfilePicture: user.ProfilePicture,
+		Private:        !user.ProfilePublic,
+	}
+	fullAccess := viewerIsAdmin || viewerID == user.ID
+	if !user.ProfilePublic && !fullAccess {
+		return profile, nil
+	}
+
+	bestStreak, err := s.resultRepo.GetUserBestWinStreak(user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get best win streak: %w", err)
+	}
+	profile.Stats = &dto.PublicProfileStatsDTO{
+		GamesPlayed:   user.GamesPlayed,
+		WinsCount:     user.WinsCount,
+		TotalPrizeWon: user.TotalPrizeWon,
+		HighestScore:  user.HighestScore,
+		BestWinStreak: bestStreak,
+	}
+	if profile.Stats.Streaks, err = s.GetStreaks(user.ID); err != nil {
+		return nil, err
+	}
+	if profile.Stats.Rating, err = s.GetRating(user.ID); err != nil {
+		return nil, err
+	}
+
+	if !user.ShowRecentResults && !fullAccess {
+		return profile, nil
+	}
+	results, _, err := s.resultRepo.GetUserResults(user.ID, publicProfileRecentResults, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent results: %w", err)
+	}
+	profile.RecentResults = make([]*dto.PublicProfileResultDTO, len(results))
+	for i, result := range results {
+		profile.RecentResults[i] = &dto.PublicProfileResultDTO{
+			QuizID:         result.QuizID,
+			Score:          result.Score,
+			CorrectAnswers: result.CorrectAnswers,
+			TotalQuestions: result.TotalQuestions,
+			Rank:           result.Rank,
+			IsWinner:       result.IsWinner,
+			PrizeFund:      result.PrizeFund,
+			CompletedAt:    result.CompletedAt,
+		}
+	}
+	return profile, nil
+}
+
+// UpdatePrivacy изменяет настройки приватности публичного профиля, результатов и лидербордов пользователя
+func (s *UserService) UpdatePrivacy(userID uint, input UpdatePrivacyInput) (*entity.User, error) {
+	updates := make(map[string]interface{})
+	if input.ProfilePublic != nil {
+		updates["profile_public"] = *input.ProfilePublic
+	}
+	if input.ShowRecentResults != nil {
+		updates["show_recent_results"] = *input.ShowRecentResults
+	}
+	if input.ShowFullName != nil {
+		updates["show_full_name"] = *input.ShowFullName
+	}
+	if input.ShowOnLeaderboards != nil {
+		updates["show_on_leaderboards"] = *input.ShowOnLeaderboards
+	}
+	if len(updates) > 0 {
+		if err := s.userRepo.UpdateProfile(userID, updates); err != nil {
+			return nil, fmt.Errorf("failed to update privacy settings: %w", err)
+		}
+	}
+	// Закешированные лидерборды показывают игрока по прежней настройке до истечения TTL
+	if input.ShowOnLeaderboards != nil && s.httpCache != nil {
+		s.httpCache.Invalidate(httpcache.NamespaceLeaderboard)
+	}
+	return s.userRepo.GetByID(userID)
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+func TestUserService_GetPublicProfile_Public(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	resultRepo := new(MockResultRepository)
+	userRepo.On("GetByID", uint(7)).Return(&entity.User{
+		ID: 7, Username: "player", GamesPlayed: 12, WinsCount: 4, TotalPrizeWon: 900,
+		ProfilePublic: true, ShowRecentResults: true,
+	}, nil)
+	resultRepo.On("GetUserBestWinStreak", uint(7)).Return(3, nil)
+	completedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	resultRepo.On("GetUserResults", uint(7), publicProfileRecentResults, 0).Return([]entity.Result{
+		{QuizID: 11, Score: 8, CorrectAnswers: 8, TotalQuestions: 10, Rank: 1, IsWinner: true, PrizeFund: 300, CompletedAt: completedAt},
+	}, int64(12), nil)
+
+	profile, err := NewUserService(userRepo, resultRepo).GetPublicProfile(7, 9, false)
+
+	require.NoError(t, err)
+	assert.False(t, profile.Private)
+	require.NotNil(t, profile.Stats)
+	assert.Equal(t, int64(12), profile.Stats.GamesPlayed)
+	assert.Equal(t, int64(4), profile.Stats.WinsCount)
+	assert.Equal(t, 3, profile.Stats.BestWinStreak)
+	require.Len(t, profile.RecentResults, 1)
+	assert.Equal(t, uint(11), profile.RecentResults[0].QuizID)
+	assert.True(t, profile.RecentResults[0].IsWinner)
+}
+
+func TestUserService_GetPublicProfile_PrivacySettings(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	resultRepo := new(MockResultRepository)
+	userRepo.On("GetByID", uint(7)).Return(&entity.User{ID: 7, Username: "hidden", ProfilePublic: false}, nil)
+	userRepo.On("GetByID", uint(8)).Return(&entity.User{ID: 8, Username: "quiet", ProfilePublic: true}, nil)
+	resultRepo.On("GetUserBestWinStreak", mock.Anything).Return(0, nil)
+	resultRepo.On("GetUserResults", mock.Anything, publicProfileRecentResults, 0).Return([]entity.Result{}, int64(0), nil)
+	svc := NewUserService(userRepo, resultRepo)
+
+	// Скрытый профиль другим игрокам виден без статистики
+	profile, err := svc.GetPublicProfile(7, 9, false)
+	require.NoError(t, err)
+	assert.True(t, profile.Private)
+	assert.Equal(t, "hidden", profile.Username)
+	assert.Nil(t, profile.Stats)
+	assert.Nil(t, profile.RecentResults)
+
+	// Скрытые последние игры: статистика есть, игр нет
+	profile, err = svc.GetPublicProfile(8, 9, false)
+	require.NoError(t, err)
+	assert.NotNil(t, profile.Stats)
+	assert.Nil(t, profile.RecentResults)
+	resultRepo.AssertNotCalled(t, "GetUserResults", uint(8), publicProfileRecentResults, 0)
+
+	// Владелец и администратор видят профиль целиком
+	for _, viewer := range []struct {
+		id    uint
+		admin bool
+	}{{7, false}, {1, true}} {
+		profile, err = svc.GetPublicProfile(7, viewer.id, viewer.admin)
+		require.NoError(t, err)
+		assert.True(t, profile.Private)
+		assert.NotNil(t, profile.Stats)
+		assert.NotNil(t, profile.RecentResults)
+	}
+}
+
+func TestUserService_GetPublicProfile_DeletedUser(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	deletedAt := time.Now()
+	userRepo.On("GetByID", uint(7)).Return(&entity.User{ID: 7, ProfilePublic: true, DeletedAt: &deletedAt}, nil)
+
+	_, err := NewUserService(userRepo, new(MockResultRepository)).GetPublicProfile(7, 9, false)
+
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
+
+func TestUserService_UpdatePrivacy(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	userRepo.On("UpdateProfile", uint(7), map[string]interface{}{"show_recent_results": false}).Return(nil)
+	userRepo.On("GetByID", uint(7)).Return(&entity.User{ID: 7, ProfilePublic: true}, nil)
+
+	hidden := false
+	user, err := NewUserService(userRepo, new(MockResultRepository)).UpdatePrivacy(7, UpdatePrivacyInput{ShowRecentResults: &hidden})
+
+	require.NoError(t, err)
+	assert.True(t, user.ProfilePublic)
+	assert.False(t, user.ShowRecentResults)
+	userRepo.AssertExpectations(t)
+}
@@ -0,0 +1,425 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// ErrInsufficientFunds возвращается, если на балансе кошелька недостаточно средств для выплаты
+var ErrInsufficientFunds = errors.New("insufficient_funds")
+
+// Поддерживаемые способы выплаты
+var payoutMethods = map[string]struct{}{
+	"bank_card":     {},
+	"bank_transfer": {},
+	"kaspi":         {},
+}
+
+// RequestPayoutInput содержит данные заявки на выплату
+type RequestPayoutInput struct {
+	Amount      int64
+	Method      string
+	Destination string
+}
+
+// WalletSummary содержит баланс кошелька пользователя
+type WalletSummary struct {
+	Balance        int64 `json:"balance"`         // доступно к выводу
+	PendingPayouts int64 `json:"pending_payouts"` // зарезервировано под заявки на выплату
+	TotalPrizeWon  int64 `json:"total_prize_won"`
+	MinPayout      int64 `json:"min_payout"`
+}
+
+// WalletService ведёт кошельки пользователей в двойной записи: призы зачисляются
+// со счёта призовых расходов, выплаты резервируются на счёте ожидающих выплат
+// и списываются на счёт выплаченных средств после подтверждения администратором.
+type WalletService struct {
+	walletRepo repository.WalletRepository
+	userRepo   repository.UserRepository
+	minPayout  int64
+}
+
+// NewWalletService создает сервис кошельков
+func NewWalletService(walletRepo repository.WalletRepository, userRepo repository.UserRepository, minPayout int64) (*WalletService, error) {
+	if walletRepo == nil {
+		return nil, fmt.Errorf("wallet repository is required")
+	}
+	if userRepo == nil {
+		return nil, fmt.Errorf("user repository is required")
+	}
+	if minPayout <= 0 {
+		minPayout = 1
+	}
+	return &WalletService{
+		walletRepo: walletRepo,
+		userRepo:   userRepo,
+		minPayout:  minPayout,
+	}, nil
+}
+
+// CreditPrizes зачисляет призы победителям викторины. Повторный вызов для той же викторины безопасен.
+func (s *WalletService) CreditPrizes(quizID uint, winnerIDs []uint, prize int) {
+	if prize <= 0 || len(winnerIDs) == 0 {
+		return
+	}
+
+	expense, err := s.walletRepo.GetSystemAccount(entity.SystemAccountPrizeExpense)
+	if err != nil {
+		log.Printf("[WalletService] Ошибка получения счёта призовых расходов: %v", err)
+		return
+	}
+
+	credited := 0
+	for _, userID := range winnerIDs {
+		account, err := s.walletRepo.GetOrCreateUserAccount(userID)
+		if err != nil {
+			log.Printf("[WalletService] Ошибка получения кошелька пользователя ID=%d: %v", userID, err)
+			continue
+		}
+
+		txn := &entity.LedgerTransaction{
+			Type:        entity.LedgerTxPrizeCredit,
+			Reference:   fmt.Sprintf("quiz:%d:user:%d", quizID, userID),
+			Description: fmt.Sprintf("Prize for quiz #%d", quizID),
+			Entries: []entity.LedgerEntry{
+				{AccountID: account.ID, Amount: int64(prize)},
+				{AccountID: expense.ID, Amount: -int64(prize)},
+			},
+		}
+		if err := s.walletRepo.PostTransaction(txn); err != nil {
+			if errors.Is(err, repository.ErrLedgerTransactionExists) {
+				continue
+			}
+			log.Printf("[WalletService] Ошибка зачисления приза пользователю ID=%d за викторину #%d: %v", userID, quizID, err)
+			continue
+		}
+		credited++
+	}
+	log.Printf("[WalletService] Викторина #%d: зачислено призов %d из %d по %d", quizID, credited, len(winnerIDs), prize)
+}
+
+// CreditPrizeShare зачисляет победителю долю невостребованного приза другой заявки.
+// Повторное зачисление той же доли не выполняется.
+func (s *WalletService) CreditPrizeShare(quizID, userID, fromClaimID uint, amount int64) error {
+	if amount <= 0 {
+		return nil
+	}
+	account, err := s.walletRepo.GetOrCreateUserAccount(userID)
+	if err != nil {
+		return err
+	}
+	expense, err := s.walletRepo.GetSystemAccount(entity.SystemAccountPrizeExpense)
+	if err != nil {
+		return err
+	}
+
+	txn := &entity.LedgerTransaction{
+		Type:        entity.LedgerTxPrizeShare,
+		Reference:   fmt.Sprintf("quiz:%d:user:%d:claim:%d", quizID, userID, fromClaimID),
+		Description: fmt.Sprintf("Unclaimed prize share for quiz #%d", quizID),
+		Entries: []entity.LedgerEntry{
+			{AccountID: account.ID, Amount: amount},
+			{AccountID: expense.ID, Amount: -amount},
+		},
+	}
+	if err := s.walletRepo.PostTransaction(txn); err != nil && !errors.Is(err, repository.ErrLedgerTransactionExists) {
+		return err
+	}
+	return nil
+}
+
+// ChargeSecondChance списывает с кошелька пользователя стоимость второго шанса в викторине.
+// Повторное списание за ту же викторину не выполняется.
+func (s *WalletService) ChargeSecondChance(userID, quizID uint, amount int64) error {
+	if amount <= 0 {
+		return nil
+	}
+	account, err := s.walletRepo.GetOrCreateUserAccount(userID)
+	if err != nil {
+		return err
+	}
+	if account.Balance < amount {
+		return ErrInsufficientFunds
+	}
+	revenue, err := s.walletRepo.GetSystemAccount(entity.SystemAccountSecondChance)
+	if err != nil {
+		return err
+	}
+
+	txn := &entity.LedgerTransaction{
+		Type:        entity.LedgerTxSecondChance,
+		Reference:   fmt.Sprintf("quiz:%d:user:%d", quizID, userID),
+		Description: fmt.Sprintf("Second chance in quiz #%d", quizID),
+		Entries: []entity.LedgerEntry{
+			{AccountID: account.ID, Amount: -amount},
+			{AccountID: revenue.ID, Amount: amount},
+		},
+	}
+	if err := s.walletRepo.PostTransaction(txn); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrLedgerTransactionExists):
+			return nil
+		case errors.Is(err, repository.ErrInsufficientFunds):
+			return ErrInsufficientFunds
+		}
+		return err
+	}
+	log.Printf("[WalletService] Пользователь ID=%d оплатил второй шанс в викторине #%d: %d", userID, quizID, amount)
+	return nil
+}
+
+// GetWallet возвращает сводку по кошельку пользователя
+func (s *WalletService) GetWallet(userID uint) (*WalletSummary, error) {
+	account, err := s.walletRepo.GetOrCreateUserAccount(userID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := s.walletRepo.SumPayouts(userID, []string{entity.PayoutStatusRequested, entity.PayoutStatusApproved})
+	if err != nil {
+		return nil, err
+	}
+
+	return &WalletSummary{
+		Balance:        account.Balance,
+		PendingPayouts: pending,
+		TotalPrizeWon:  user.TotalPrizeWon,
+		MinPayout:      s.minPayout,
+	}, nil
+}
+
+// GetTransactions возвращает историю операций по кошельку пользователя
+func (s *WalletService) GetTransactions(userID uint, page, pageSize int) ([]entity.LedgerEntry, int64, error) {
+	page, pageSize = normalizeWalletPage(page, pageSize)
+	account, err := s.walletRepo.GetOrCreateUserAccount(userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.walletRepo.ListEntriesByAccount(account.ID, pageSize, (page-1)*pageSize)
+}
+
+// RequestPayout создаёт заявку на выплату и резервирует сумму на балансе
+func (s *WalletService) RequestPayout(userID uint, input RequestPayoutInput) (*entity.PayoutRequest, error) {
+	method := strings.ToLower(strings.TrimSpace(input.Method))
+	destination := strings.TrimSpace(input.Destination)
+	if _, ok := payoutMethods[method]; !ok {
+		return nil, fmt.Errorf("%w: unsupported payout method", apperrors.ErrValidation)
+	}
+	if destination == "" {
+		return nil, fmt.Errorf("%w: destination is required", apperrors.ErrValidation)
+	}
+	if input.Amount < s.minPayout {
+		return nil, fmt.Errorf("%w: minimum payout amount is %d", apperrors.ErrValidation, s.minPayout)
+	}
+
+	account, err := s.walletRepo.GetOrCreateUserAccount(userID)
+	if err != nil {
+		return nil, err
+	}
+	if account.Balance < input.Amount {
+		return nil, ErrInsufficientFunds
+	}
+	pending, err := s.walletRepo.GetSystemAccount(entity.SystemAccountPayoutsPending)
+	if err != nil {
+		return nil, err
+	}
+
+	payout := &entity.PayoutRequest{
+		UserID:      userID,
+		Amount:      input.Amount,
+		Status:      entity.PayoutStatusRequested,
+		Method:      method,
+		Destination: destination,
+	}
+	hold := &entity.LedgerTransaction{
+		Type:        entity.LedgerTxPayoutHold,
+		Description: "Payout request hold",
+		Entries: []entity.LedgerEntry{
+			{AccountID: account.ID, Amount: -input.Amount},
+			{AccountID: pending.ID, Amount: input.Amount},
+		},
+	}
+	if err := s.walletRepo.CreatePayoutRequest(payout, hold); err != nil {
+		if errors.Is(err, repository.ErrInsufficientFunds) {
+			return nil, ErrInsufficientFunds
+		}
+		return nil, err
+	}
+
+	log.Printf("[WalletService] Пользователь ID=%d создал заявку на выплату #%d на сумму %d", userID, payout.ID, payout.Amount)
+	return payout, nil
+}
+
+// ListMyPayouts возвращает заявки на выплату пользователя
+func (s *WalletService) ListMyPayouts(userID uint, page, pageSize int) ([]entity.PayoutRequest, int64, error) {
+	page, pageSize = normalizeWalletPage(page, pageSize)
+	return s.walletRepo.ListPayoutRequests(repository.PayoutFilters{UserID: &userID}, pageSize, (page-1)*pageSize)
+}
+
+// CancelPayout отменяет собственную заявку пользователя, пока она не одобрена
+func (s *WalletService) CancelPayout(userID, payoutID uint) (*entity.PayoutRequest, error) {
+	payout, err := s.walletRepo.GetPayoutRequest(payoutID)
+	if err != nil {
+		return nil, err
+	}
+	if payout.UserID != userID {
+		return nil, apperrors.ErrNotFound
+	}
+	if payout.Status != entity.PayoutStatusRequested {
+		return nil, fmt.Errorf("%w: only requested payouts can be cancelled", apperrors.ErrConflict)
+	}
+
+	release, err := s.releasePosting(payout)
+	if err != nil {
+		return nil, err
+	}
+	payout.Status = entity.PayoutStatusCancelled
+	if err := s.walletRepo.TransitionPayout(payout, entity.PayoutStatusRequested, release); err != nil {
+		return nil, err
+	}
+	return payout, nil
+}
+
+// ListPayouts возвращает заявки на выплату для администратора
+func (s *WalletService) ListPayouts(status string, page, pageSize int) ([]entity.PayoutRequest, int64, error) {
+	page, pageSize = normalizeWalletPage(page, pageSize)
+	return s.walletRepo.ListPayoutRequests(repository.PayoutFilters{Status: status}, pageSize, (page-1)*pageSize)
+}
+
+// ApprovePayout одобряет заявку; средства остаются зарезервированными до выплаты
+func (s *WalletService) ApprovePayout(adminID, payoutID uint) (*entity.PayoutRequest, error) {
+	payout, err := s.walletRepo.GetPayoutRequest(payoutID)
+	if err != nil {
+		return nil, err
+	}
+	if payout.Status != entity.PayoutStatusRequested {
+		return nil, fmt.Errorf("%w: only requested payouts can be approved", apperrors.ErrConflict)
+	}
+
+	now := time.Now()
+	payout.Status = entity.PayoutStatusApproved
+	payout.AdminID = &adminID
+	payout.ApprovedAt = &now
+	if err := s.walletRepo.TransitionPayout(payout, entity.PayoutStatusRequested, nil); err != nil {
+		return nil, err
+	}
+	log.Printf("[WalletService] Администратор ID=%d одобрил заявку на выплату #%d", adminID, payoutID)
+	return payout, nil
+}
+
+// MarkPayoutPaid отмечает одобренную заявку выплаченной и списывает резерв на счёт выплат
+func (s *WalletService) MarkPayoutPaid(adminID, payoutID uint, externalReference string) (*entity.PayoutRequest, error) {
+	payout, err := s.walletRepo.GetPayoutRequest(payoutID)
+	if err != nil {
+		return nil, err
+	}
+	if payout.Status != entity.PayoutStatusApproved {
+		return nil, fmt.Errorf("%w: only approved payouts can be marked as paid", apperrors.ErrConflict)
+	}
+
+	pending, err := s.walletRepo.GetSystemAccount(entity.SystemAccountPayoutsPending)
+	if err != nil {
+		return nil, err
+	}
+	settled, err := s.walletRepo.GetSystemAccount(entity.SystemAccountPayoutsSettled)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	payout.Status = entity.PayoutStatusPaid
+	payout.AdminID = &adminID
+	payout.PaidAt = &now
+	payout.ExternalReference = strings.TrimSpace(externalReference)
+	settle := &entity.LedgerTransaction{
+		Type:        entity.LedgerTxPayoutSettle,
+		Reference:   fmt.Sprintf("payout:%d", payout.ID),
+		Description: "Payout settled",
+		Entries: []entity.LedgerEntry{
+			{AccountID: pending.ID, Amount: -payout.Amount},
+			{AccountID: settled.ID, Amount: payout.Amount},
+		},
+	}
+	if err := s.walletRepo.TransitionPayout(payout, entity.PayoutStatusApproved, settle); err != nil {
+		return nil, err
+	}
+	log.Printf("[WalletService] Администратор ID=%d отметил выплату #%d как выполненную", adminID, payoutID)
+	return payout, nil
+}
+
+// RejectPayout отклоняет заявку и возвращает зарезервированную сумму на баланс пользователя
+func (s *WalletService) RejectPayout(adminID, payoutID uint, reason string) (*entity.PayoutRequest, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("%w: reject reason is required", apperrors.ErrValidation)
+	}
+
+	payout, err := s.walletRepo.GetPayoutRequest(payoutID)
+	if err != nil {
+		return nil, err
+	}
+	fromStatus := payout.Status
+	if fromStatus != entity.PayoutStatusRequested && fromStatus != entity.PayoutStatusApproved {
+		return nil, fmt.Errorf("%w: payout can no longer be rejected", apperrors.ErrConflict)
+	}
+
+	release, err := s.releasePosting(payout)
+	if err != nil {
+		return nil, err
+	}
+	payout.Status = entity.PayoutStatusRejected
+	payout.AdminID = &adminID
+	payout.RejectReason = reason
+	if err := s.walletRepo.TransitionPayout(payout, fromStatus, release); err != nil {
+		return nil, err
+	}
+	log.Printf("[WalletService] Администратор ID=%d отклонил заявку на выплату #%d: %s", adminID, payoutID, reason)
+	return payout, nil
+}
+
+// Reconcile выполняет сверку журнала проводок
+func (s *WalletService) Reconcile() (*repository.LedgerReconciliation, error) {
+	return s.walletRepo.Reconcile()
+}
+
+// releasePosting формирует проводку возврата резерва заявки на кошелёк пользователя
+func (s *WalletService) releasePosting(payout *entity.PayoutRequest) (*entity.LedgerTransaction, error) {
+	account, err := s.walletRepo.GetOrCreateUserAccount(payout.UserID)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := s.walletRepo.GetSystemAccount(entity.SystemAccountPayoutsPending)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.LedgerTransaction{
+		Type:        entity.LedgerTxPayoutRelease,
+		Reference:   fmt.Sprintf("payout:%d", payout.ID),
+		Description: "Payout hold released",
+		Entries: []entity.LedgerEntry{
+			{AccountID: pending.ID, Amount: -payout.Amount},
+			{AccountID: account.ID, Amount: payout.Amount},
+		},
+	}, nil
+}
+
+func normalizeWalletPage(page, pageSize int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return page, pageSize
+}
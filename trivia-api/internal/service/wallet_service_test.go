@@ -0,0 +1,176 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// ============================================================================
+// Мок для WalletService
+// ============================================================================
+
+// MockWalletRepository реализует repository.WalletRepository
+type MockWalletRepository struct {
+	mock.Mock
+}
+
+func (m *MockWalletRepository) GetOrCreateUserAccount(userID uint) (*entity.WalletAccount, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.WalletAccount), args.Error(1)
+}
+
+func (m *MockWalletRepository) GetSystemAccount(code string) (*entity.WalletAccount, error) {
+	args := m.Called(code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.WalletAccount), args.Error(1)
+}
+
+func (m *MockWalletRepository) PostTransaction(txn *entity.LedgerTransaction) error {
+	args := m.Called(txn)
+	return args.Error(0)
+}
+
+func (m *MockWalletRepository) ListEntriesByAccount(accountID uint, limit, offset int) ([]entity.LedgerEntry, int64, error) {
+	args := m.Called(accountID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]entity.LedgerEntry), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockWalletRepository) CreatePayoutRequest(payout *entity.PayoutRequest, hold *entity.LedgerTransaction) error {
+	args := m.Called(payout, hold)
+	return args.Error(0)
+}
+
+func (m *MockWalletRepository) GetPayoutRequest(id uint) (*entity.PayoutRequest, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.PayoutRequest), args.Error(1)
+}
+
+func (m *MockWalletRepository) ListPayoutRequests(filters repository.PayoutFilters, limit, offset int) ([]entity.PayoutRequest, int64, error) {
+	args := m.Called(filters, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]entity.PayoutRequest), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockWalletRepository) SumPayouts(userID uint, statuses []string) (int64, error) {
+	args := m.Called(userID, statuses)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockWalletRepository) TransitionPayout(payout *entity.PayoutRequest, fromStatus string, posting *entity.LedgerTransaction) error {
+	args := m.Called(payout, fromStatus, posting)
+	return args.Error(0)
+}
+
+func (m *MockWalletRepository) Reconcile() (*repository.LedgerReconciliation, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.LedgerReconciliation), args.Error(1)
+}
+
+func createTestWalletService(t *testing.T) (*WalletService, *MockWalletRepository) {
+	walletRepo := new(MockWalletRepository)
+	svc, err := NewWalletService(walletRepo, new(MockUserRepository), 100)
+	require.NoError(t, err)
+
+	code := func(c string) *string { return &c }
+	walletRepo.On("GetSystemAccount", entity.SystemAccountPrizeExpense).Return(&entity.WalletAccount{ID: 1, Type: entity.WalletAccountTypeSystem, Code: code(entity.SystemAccountPrizeExpense)}, nil).Maybe()
+	walletRepo.On("GetSystemAccount", entity.SystemAccountPayoutsPending).Return(&entity.WalletAccount{ID: 2, Type: entity.WalletAccountTypeSystem, Code: code(entity.SystemAccountPayoutsPending)}, nil).Maybe()
+	walletRepo.On("GetSystemAccount", entity.SystemAccountPayoutsSettled).Return(&entity.WalletAccount{ID: 3, Type: entity.WalletAccountTypeSystem, Code: code(entity.SystemAccountPayoutsSettled)}, nil).Maybe()
+	return svc, walletRepo
+}
+
+// ============================================================================
+// Тесты
+// ============================================================================
+
+func TestWalletCreditPrizes_PostsBalancedIdempotentTransactions(t *testing.T) {
+	svc, walletRepo := createTestWalletService(t)
+	userID := uint(7)
+	walletRepo.On("GetOrCreateUserAccount", userID).Return(&entity.WalletAccount{ID: 10, UserID: &userID, Type: entity.WalletAccountTypeUser}, nil)
+	walletRepo.On("PostTransaction", mock.MatchedBy(func(txn *entity.LedgerTransaction) bool {
+		return txn.Type == entity.LedgerTxPrizeCredit && txn.Reference == "quiz:3:user:7" && txn.IsBalanced() &&
+			txn.Entries[0].AccountID == 10 && txn.Entries[0].Amount == 500
+	})).Return(repository.ErrLedgerTransactionExists)
+
+	// Повторное зачисление не должно приводить к ошибке или панике
+	svc.CreditPrizes(3, []uint{userID}, 500)
+
+	walletRepo.AssertNumberOfCalls(t, "PostTransaction", 1)
+}
+
+func TestWalletRequestPayout_Validation(t *testing.T) {
+	svc, walletRepo := createTestWalletService(t)
+	userID := uint(7)
+	walletRepo.On("GetOrCreateUserAccount", userID).Return(&entity.WalletAccount{ID: 10, UserID: &userID, Type: entity.WalletAccountTypeUser, Balance: 150}, nil)
+
+	_, err := svc.RequestPayout(userID, RequestPayoutInput{Amount: 50, Method: "kaspi", Destination: "+77001234567"})
+	assert.ErrorIs(t, err, apperrors.ErrValidation, "сумма ниже минимальной")
+
+	_, err = svc.RequestPayout(userID, RequestPayoutInput{Amount: 100, Method: "crypto", Destination: "x"})
+	assert.ErrorIs(t, err, apperrors.ErrValidation, "неподдерживаемый способ")
+
+	_, err = svc.RequestPayout(userID, RequestPayoutInput{Amount: 200, Method: "kaspi", Destination: "+77001234567"})
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+}
+
+func TestWalletRequestPayout_HoldsFunds(t *testing.T) {
+	svc, walletRepo := createTestWalletService(t)
+	userID := uint(7)
+	walletRepo.On("GetOrCreateUserAccount", userID).Return(&entity.WalletAccount{ID: 10, UserID: &userID, Type: entity.WalletAccountTypeUser, Balance: 1000}, nil)
+	walletRepo.On("CreatePayoutRequest", mock.AnythingOfType("*entity.PayoutRequest"), mock.MatchedBy(func(hold *entity.LedgerTransaction) bool {
+		return hold.Type == entity.LedgerTxPayoutHold && hold.IsBalanced() &&
+			hold.Entries[0].AccountID == 10 && hold.Entries[0].Amount == -300 &&
+			hold.Entries[1].AccountID == 2 && hold.Entries[1].Amount == 300
+	})).Return(nil)
+
+	payout, err := svc.RequestPayout(userID, RequestPayoutInput{Amount: 300, Method: "Kaspi", Destination: "+77001234567"})
+	require.NoError(t, err)
+	assert.Equal(t, entity.PayoutStatusRequested, payout.Status)
+	assert.Equal(t, "kaspi", payout.Method)
+}
+
+func TestWalletRejectPayout_ReleasesHold(t *testing.T) {
+	svc, walletRepo := createTestWalletService(t)
+	userID := uint(7)
+	walletRepo.On("GetPayoutRequest", uint(5)).Return(&entity.PayoutRequest{ID: 5, UserID: userID, Amount: 300, Status: entity.PayoutStatusApproved}, nil)
+	walletRepo.On("GetOrCreateUserAccount", userID).Return(&entity.WalletAccount{ID: 10, UserID: &userID, Type: entity.WalletAccountTypeUser}, nil)
+	walletRepo.On("TransitionPayout", mock.AnythingOfType("*entity.PayoutRequest"), entity.PayoutStatusApproved, mock.MatchedBy(func(txn *entity.LedgerTransaction) bool {
+		return txn.Type == entity.LedgerTxPayoutRelease && txn.Reference == "payout:5" && txn.IsBalanced() &&
+			txn.Entries[1].AccountID == 10 && txn.Entries[1].Amount == 300
+	})).Return(nil)
+
+	payout, err := svc.RejectPayout(1, 5, "invalid destination")
+	require.NoError(t, err)
+	assert.Equal(t, entity.PayoutStatusRejected, payout.Status)
+	assert.Equal(t, "invalid destination", payout.RejectReason)
+}
+
+func TestWalletMarkPayoutPaid_RequiresApproval(t *testing.T) {
+	svc, walletRepo := createTestWalletService(t)
+	walletRepo.On("GetPayoutRequest", uint(5)).Return(&entity.PayoutRequest{ID: 5, UserID: 7, Amount: 300, Status: entity.PayoutStatusRequested}, nil)
+
+	_, err := svc.MarkPayoutPaid(1, 5, "")
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+	walletRepo.AssertNotCalled(t, "TransitionPayout", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,484 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	"github.com/yourusername/trivia-api/internal/pkg/warehouse"
+	ws "github.com/yourusername/trivia-api/internal/websocket"
+)
+
+const (
+	// warehouseWriteAttempts — попыток отправки пачки живых событий, затем пачка отбрасывается
+	warehouseWriteAttempts = 3
+	warehouseRetryBase     = time.Second
+	warehouseWriteTimeout  = 30 * time.Second
+)
+
+// WarehouseConfig содержит настройки выгрузки событий в хранилище аналитики
+type WarehouseConfig struct {
+	BatchSize     int           // событий в одной пачке
+	FlushInterval time.Duration // максимальная задержка отправки события
+	BufferSize    int           // ёмкость очереди живых событий; при переполнении события отбрасываются
+	InstanceID    string        // ID инстанса в событиях соединений
+}
+
+// WarehouseExporter отправляет нормализованные события игры в приёмник хранилища аналитики.
+//
+// quiz.completed приходит из шины событий (outbox) и отправляется синхронно: при ошибке
+// приёмника шина повторит событие. Ответы, выбывания и соединения слишком частые для outbox,
+// поэтому копятся в очереди в памяти и уходят пачками; пачка, которую не удалось отправить
+// за warehouseWriteAttempts попыток, отбрасывается. Потерянные ответы, выбывания и итоги
+// викторин восстанавливаются BackfillWarehouse (cmd/warehouse-backfill), дубликаты
+// убираются в хранилище по event_id.
+type WarehouseExporter struct {
+	sink warehouse.Sink
+	cfg  WarehouseConfig
+
+	events  chan warehouse.Event
+	dropped atomic.Int64
+
+	closeOnce sync.Once
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// NewWarehouseExporter создает экспортёр и запускает фоновую отправку
+func NewWarehouseExporter(sink warehouse.Sink, cfg WarehouseConfig) *WarehouseExporter {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 500
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = 2 * time.Second
+	}
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = 20000
+	}
+	if cfg.BufferSize < cfg.BatchSize {
+		cfg.BufferSize = cfg.BatchSize
+	}
+	e := &WarehouseExporter{
+		sink:   sink,
+		cfg:    cfg,
+		events: make(chan warehouse.Event, cfg.BufferSize),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go e.run()
+	return e
+}
+
+// SubscribeTo подписывает экспортёр на доменные события шины
+func (e *WarehouseExporter) SubscribeTo(bus *EventBus) {
+	bus.Subscribe("warehouse", e.handleQuizCompleted, entity.EventQuizCompleted)
+	bus.Subscribe("warehouse_corrections", e.handleAnswersChanged, entity.EventQuestionVoided, entity.EventEliminationRevoked)
+}
+
+func (e *WarehouseExporter) handleQuizCompleted(ctx context.Context, event *entity.OutboxEvent) error {
+	var payload entity.QuizCompletedPayload
+	if err := event.Decode(&payload); err != nil {
+		return err
+	}
+	winners := payload.WinnerIDs
+	if winners == nil {
+		winners = []uint{}
+	}
+	wEvent, err := warehouse.NewEvent(warehouse.EventQuizCompleted, warehouse.QuizCompletedEventID(payload.QuizID),
+		warehouse.SourceLive, event.CreatedAt, payload.QuizID, 0, warehouse.QuizCompletedData{
+			Title:         payload.Title,
+			ScheduledTime: payload.ScheduledTime,
+			PrizeFund:     payload.PrizeFund,
+			WinnerIDs:     winners,
+		})
+	if err != nil {
+		return err
+	}
+	return e.writeNow(ctx, wEvent)
+}
+
+// handleAnswersChanged отправляет исправления уже выгруженных ответов: снятие вопроса и второй шанс
+func (e *WarehouseExporter) handleAnswersChanged(ctx context.Context, event *entity.OutboxEvent) error {
+	var wEvent warehouse.Event
+	var err error
+	switch event.EventType {
+	case entity.EventQuestionVoided:
+		var payload entity.QuestionVoidedPayload
+		if err := event.Decode(&payload); err != nil {
+			return err
+		}
+		wEvent, err = questionVoidedEvent(warehouse.SourceLive, payload.QuizID, payload.QuestionID, payload.VoidedAt)
+	case entity.EventEliminationRevoked:
+		var payload entity.EliminationRevokedPayload
+		if err := event.Decode(&payload); err != nil {
+			return err
+		}
+		wEvent, err = eliminationRevokedEvent(warehouse.SourceLive, payload.QuizID, payload.UserID, payload.RevokedAt)
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return e.writeNow(ctx, wEvent)
+}
+
+// writeNow отправляет редкое событие из шины сразу, минуя очередь: ошибка возвращается шине для повтора
+func (e *WarehouseExporter) writeNow(ctx context.Context, event warehouse.Event) error {
+	ctx, cancel := context.WithTimeout(ctx, warehouseWriteTimeout)
+	defer cancel()
+	return e.sink.Write(ctx, []warehouse.Event{event})
+}
+
+// AnswerRecorded ставит в очередь answer_submitted и, если ответ выбил игрока, elimination.
+// Реализует quizmanager.AnswerObserver.
+func (e *WarehouseExporter) AnswerRecorded(answer *entity.UserAnswer, questionNumber int) {
+	events, err := answerWarehouseEvents(warehouse.SourceLive, answer.QuizID, answer.UserID, answer.QuestionID,
+		questionNumber, answer.SelectedOption, answer.IsCorrect, answer.ResponseTimeMs, answer.Score,
+		answer.IsEliminated, answer.EliminationReason, answer.CreatedAt)
+	if err != nil {
+		log.Printf("[Warehouse] Ошибка формирования события ответа: %v", err)
+		return
+	}
+	for _, event := range events {
+		e.Track(event)
+	}
+}
+
+// HandleConnection ставит в очередь событие открытия или закрытия WebSocket-соединения
+func (e *WarehouseExporter) HandleConnection(event ws.ConnectionEvent) {
+	userID, err := strconv.ParseUint(event.UserID, 10, 64)
+	if err != nil {
+		return
+	}
+	eventType := warehouse.EventConnectionOpened
+	data := warehouse.ConnectionData{ConnectionID: event.ConnectionID, InstanceID: e.cfg.InstanceID}
+	if event.Type == ws.ConnectionClosed {
+		eventType = warehouse.EventConnectionClosed
+		data.DurationMs = event.Duration.Milliseconds()
+		data.Reason = "closed"
+		if event.Replaced {
+			data.Reason = "replaced"
+		}
+	}
+	wEvent, err := warehouse.NewEvent(eventType, eventType+":"+event.ConnectionID, warehouse.SourceLive,
+		event.At, event.QuizID, uint(userID), data)
+	if err != nil {
+		log.Printf("[Warehouse] Ошибка формирования события соединения: %v", err)
+		return
+	}
+	e.Track(wEvent)
+}
+
+// Track ставит событие в очередь отправки, не блокируясь: при переполнении очереди событие отбрасывается
+func (e *WarehouseExporter) Track(event warehouse.Event) {
+	select {
+	case <-e.stop:
+		e.drop(1)
+		return
+	default:
+	}
+	select {
+	case e.events <- event:
+	default:
+		e.drop(1)
+	}
+}
+
+// Dropped возвращает число отброшенных событий с момента запуска
+func (e *WarehouseExporter) Dropped() int64 {
+	return e.dropped.Load()
+}
+
+func (e *WarehouseExporter) drop(n int) {
+	total := e.dropped.Add(int64(n))
+	// Не засоряем лог при длительной недоступности приёмника: пишем примерно раз на тысячу событий
+	if total/1000 != (total-int64(n))/1000 || total == int64(n) {
+		log.Printf("[Warehouse] Отброшено событий с момента запуска: %d", total)
+	}
+}
+
+// Close останавливает фоновую отправку, отправляет накопленные события и закрывает приёмник
+func (e *WarehouseExporter) Close() {
+	e.closeOnce.Do(func() {
+		close(e.stop)
+		<-e.done
+		if err := e.sink.Close(); err != nil {
+			log.Printf("[Warehouse] Ошибка закрытия приёмника: %v", err)
+		}
+	})
+}
+
+func (e *WarehouseExporter) run() {
+	defer close(e.done)
+
+	ticker := time.NewTicker(e.cfg.FlushInterval)
+	defer ticker.Stop()
+	batch := make([]warehouse.Event, 0, e.cfg.BatchSize)
+
+	for {
+		select {
+		case event := <-e.events:
+			batch = append(batch, event)
+			if len(batch) < e.cfg.BatchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		case <-e.stop:
+			// Дописываем то, что уже в очереди
+			for drained := false; !drained; {
+				select {
+				case event := <-e.events:
+					batch = append(batch, event)
+					if len(batch) >= e.cfg.BatchSize {
+						e.flush(batch)
+						batch = batch[:0]
+					}
+				default:
+					drained = true
+				}
+			}
+			if len(batch) > 0 {
+				e.flush(batch)
+			}
+			return
+		}
+		e.flush(batch)
+		batch = batch[:0]
+	}
+}
+
+// flush отправляет пачку с повторами; после последней неудачи пачка отбрасывается
+func (e *WarehouseExporter) flush(batch []warehouse.Event) {
+	delay := warehouseRetryBase
+	for attempt := 1; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), warehouseWriteTimeout)
+		err := e.sink.Write(ctx, batch)
+		cancel()
+		if err == nil {
+			return
+		}
+		if attempt >= warehouseWriteAttempts {
+			log.Printf("[Warehouse] Пачка из %d событий не отправлена после %d попыток: %v", len(batch), attempt, err)
+			e.drop(len(batch))
+			return
+		}
+		log.Printf("[Warehouse] Ошибка отправки пачки из %d событий, повтор через %s: %v", len(batch), delay, err)
+		select {
+		case <-time.After(delay):
+		case <-e.stop:
+			// При остановке не ждём паузы: делаем оставшиеся попытки сразу
+		}
+		delay *= 2
+	}
+}
+
+// answerWarehouseEvents строит answer_submitted и, для выбивающего ответа, elimination.
+// Живые события и backfill используют её же, чтобы строки не различались ничем, кроме source.
+func answerWarehouseEvents(source string, quizID, userID, questionID uint, questionNumber, selectedOption int,
+	isCorrect bool, responseTimeMs int64, score int, isEliminated bool, eliminationReason string, at time.Time) ([]warehouse.Event, error) {
+	if at.IsZero() {
+		at = time.Now()
+	}
+	answer, err := warehouse.NewEvent(warehouse.EventAnswerSubmitted, warehouse.AnswerEventID(quizID, userID, questionID),
+		source, at, quizID, userID, warehouse.AnswerSubmittedData{
+			QuestionID:     questionID,
+			QuestionNumber: questionNumber,
+			SelectedOption: selectedOption,
+			IsCorrect:      isCorrect,
+			ResponseTimeMs: responseTimeMs,
+			Score:          score,
+		})
+	if err != nil {
+		return nil, err
+	}
+	if !isEliminated {
+		return []warehouse.Event{answer}, nil
+	}
+	elimination, err := warehouse.NewEvent(warehouse.EventElimination, warehouse.EliminationEventID(quizID, userID, questionID),
+		source, at, quizID, userID, warehouse.EliminationData{
+			QuestionID:     questionID,
+			QuestionNumber: questionNumber,
+			Reason:         eliminationReason,
+		})
+	if err != nil {
+		return nil, err
+	}
+	return []warehouse.Event{answer, elimination}, nil
+}
+
+func questionVoidedEvent(source string, quizID, questionID uint, at time.Time) (warehouse.Event, error) {
+	return warehouse.NewEvent(warehouse.EventQuestionVoided, warehouse.QuestionVoidedEventID(quizID, questionID),
+		source, at, quizID, 0, warehouse.QuestionVoidedData{QuestionID: questionID})
+}
+
+func eliminationRevokedEvent(source string, quizID, userID uint, at time.Time) (warehouse.Event, error) {
+	return warehouse.NewEvent(warehouse.EventEliminationRevoked, warehouse.EliminationRevokedEventID(quizID, userID),
+		source, at, quizID, userID, warehouse.EliminationRevokedData{Reason: entity.AnswerReasonSecondChance})
+}
+
+// WarehouseBackfillOptions задаёт викторины для восстановления событий
+type WarehouseBackfillOptions struct {
+	QuizIDs   []uint     // конкретные викторины; пусто — все завершённые в [From, To]
+	From      *time.Time // начало периода по времени проведения
+	To        *time.Time // конец периода по времени проведения
+	BatchSize int        // строк за запрос к БД и событий в пачке приёмника
+}
+
+// WarehouseBackfillReport — итог восстановления
+type WarehouseBackfillReport struct {
+	Quizzes int
+	Events  int
+}
+
+// BackfillWarehouse восстанавливает из БД события answer_submitted, elimination, question_voided,
+// elimination_revoked и quiz_completed завершённых викторин и отправляет их в sink с source=backfill. События соединений в БД не
+// хранятся и не восстанавливаются. Повторный запуск безопасен: event_id совпадают с живыми.
+func BackfillWarehouse(ctx context.Context, sink warehouse.Sink, quizRepo repository.QuizRepository,
+	dataRepo repository.ExportJobRepository, opts WarehouseBackfillOptions) (*WarehouseBackfillReport, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 1000
+	}
+	quizzes, err := backfillQuizzes(quizRepo, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &WarehouseBackfillReport{}
+	for i := range quizzes {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		quiz := &quizzes[i]
+		if quiz.Status != entity.QuizStatusCompleted {
+			log.Printf("[Warehouse] Викторина #%d не завершена (%s), пропускаем", quiz.ID, quiz.Status)
+			continue
+		}
+		written, err := backfillQuiz(ctx, sink, dataRepo, quiz, opts.BatchSize)
+		report.Events += written
+		if err != nil {
+			return report, fmt.Errorf("quiz %d: %w", quiz.ID, err)
+		}
+		report.Quizzes++
+		log.Printf("[Warehouse] Викторина #%d: отправлено %d событий", quiz.ID, written)
+	}
+	return report, nil
+}
+
+// backfillQuizzes возвращает викторины из opts.QuizIDs или все завершённые за период
+func backfillQuizzes(quizRepo repository.QuizRepository, opts WarehouseBackfillOptions) ([]entity.Quiz, error) {
+	if len(opts.QuizIDs) > 0 {
+		return quizRepo.GetByIDs(opts.QuizIDs)
+	}
+	filters := repository.QuizFilters{Status: entity.QuizStatusCompleted, DateFrom: opts.From, DateTo: opts.To}
+	var quizzes []entity.Quiz
+	for offset := 0; ; offset += 100 {
+		page, _, err := quizRepo.ListWithFilters(filters, 100, offset)
+		if err != nil {
+			return nil, err
+		}
+		quizzes = append(quizzes, page...)
+		if len(page) < 100 {
+			return quizzes, nil
+		}
+	}
+}
+
+func backfillQuiz(ctx context.Context, sink warehouse.Sink, dataRepo repository.ExportJobRepository, quiz *entity.Quiz, batchSize int) (int, error) {
+	written := 0
+	afterID := uint(0)
+	voidedQuestions := make(map[uint]bool)
+	for {
+		answers, err := dataRepo.ListQuizAnswersAfter(quiz.ID, afterID, batchSize)
+		if err != nil {
+			return written, err
+		}
+		if len(answers) == 0 {
+			break
+		}
+		events := make([]warehouse.Event, 0, len(answers))
+		for _, a := range answers {
+			questionNumber := 0
+			if a.QuestionNumber != nil {
+				questionNumber = *a.QuestionNumber
+			}
+			answerEvents, err := answerWarehouseEvents(warehouse.SourceBackfill, quiz.ID, a.UserID, a.QuestionID, questionNumber,
+				a.SelectedOption, a.IsCorrect, a.ResponseTimeMs, a.Score, a.IsEliminated, a.EliminationReason, a.CreatedAt)
+			if err != nil {
+				return written, err
+			}
+			events = append(events, answerEvents...)
+
+			// Исправления: ответ на снятый вопрос и ответ, выбывание на котором отменено вторым шансом.
+			// Время отмены — время самого ответа: хранилище отменяет выбывания не позже него.
+			var correction warehouse.Event
+			switch {
+			case a.EliminationReason == entity.AnswerReasonQuestionVoided && !voidedQuestions[a.QuestionID]:
+				voidedQuestions[a.QuestionID] = true
+				correction, err = questionVoidedEvent(warehouse.SourceBackfill, quiz.ID, a.QuestionID, a.CreatedAt)
+			case a.EliminationReason == entity.AnswerReasonSecondChance:
+				correction, err = eliminationRevokedEvent(warehouse.SourceBackfill, quiz.ID, a.UserID, a.CreatedAt)
+			default:
+				continue
+			}
+			if err != nil {
+				return written, err
+			}
+			events = append(events, correction)
+		}
+		if err := sink.Write(ctx, events); err != nil {
+			return written, err
+		}
+		written += len(events)
+		afterID = answers[len(answers)-1].ID
+		if len(answers) < batchSize {
+			break
+		}
+	}
+
+	winners := []uint{}
+	completedAt := quiz.ScheduledTime
+	afterRank, afterResultID := -1, uint(0)
+	for {
+		results, err := dataRepo.ListQuizResultsAfter(quiz.ID, afterRank, afterResultID, batchSize)
+		if err != nil {
+			return written, err
+		}
+		for _, r := range results {
+			if r.IsWinner {
+				winners = append(winners, r.UserID)
+			}
+			if r.CompletedAt.After(completedAt) {
+				completedAt = r.CompletedAt
+			}
+		}
+		if len(results) < batchSize {
+			break
+		}
+		last := results[len(results)-1]
+		afterRank, afterResultID = last.Rank, last.ID
+	}
+	completed, err := warehouse.NewEvent(warehouse.EventQuizCompleted, warehouse.QuizCompletedEventID(quiz.ID),
+		warehouse.SourceBackfill, completedAt, quiz.ID, 0, warehouse.QuizCompletedData{
+			Title:         quiz.Title,
+			ScheduledTime: quiz.ScheduledTime,
+			PrizeFund:     quiz.PrizeFund,
+			WinnerIDs:     winners,
+		})
+	if err != nil {
+		return written, err
+	}
+	if err := sink.Write(ctx, []warehouse.Event{completed}); err != nil {
+		return written, err
+	}
+	return written + 1, nil
+}
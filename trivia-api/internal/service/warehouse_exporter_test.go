@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	"github.com/yourusername/trivia-api/internal/pkg/warehouse"
+	ws "github.com/yourusername/trivia-api/internal/websocket"
+)
+
+// recordingSink запоминает отправленные пачки; fail — сколько первых вызовов завершить ошибкой
+type recordingSink struct {
+	mu      sync.Mutex
+	batches [][]warehouse.Event
+	fail    int
+	calls   int
+}
+
+func (s *recordingSink) Write(ctx context.Context, events []warehouse.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if s.calls <= s.fail {
+		return errors.New("sink unavailable")
+	}
+	s.batches = append(s.batches, append([]warehouse.Event(nil), events...))
+	return nil
+}
+
+func (s *recordingSink) Close() error { return nil }
+
+func (s *recordingSink) events() []warehouse.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var all []warehouse.Event
+	for _, batch := range s.batches {
+		all = append(all, batch...)
+	}
+	return all
+}
+
+func TestWarehouseExporter_AnswersAndConnections(t *testing.T) {
+	sink := &recordingSink{}
+	exporter := NewWarehouseExporter(sink, WarehouseConfig{BatchSize: 2, FlushInterval: time.Hour, InstanceID: "api-1"})
+
+	exporter.AnswerRecorded(&entity.UserAnswer{UserID: 7, QuizID: 3, QuestionID: 11, SelectedOption: 2, IsCorrect: true, ResponseTimeMs: 1500, Score: 1}, 1)
+	exporter.AnswerRecorded(&entity.UserAnswer{UserID: 8, QuizID: 3, QuestionID: 11, SelectedOption: -1, IsEliminated: true, EliminationReason: "no_answer_timeout"}, 1)
+	exporter.HandleConnection(ws.ConnectionEvent{Type: ws.ConnectionClosed, UserID: "8", ConnectionID: "c-1", QuizID: 3, At: time.Now(), Duration: 90 * time.Second, Replaced: true})
+	exporter.HandleConnection(ws.ConnectionEvent{Type: ws.ConnectionOpened, UserID: "not-a-user", ConnectionID: "c-2", At: time.Now()})
+	exporter.Close()
+
+	events := sink.events()
+	require.Len(t, events, 4)
+	assert.Len(t, sink.batches, 2, "пачки по BatchSize, остаток — при Close")
+
+	assert.Equal(t, warehouse.EventAnswerSubmitted, events[0].EventType)
+	assert.Equal(t, "answer:3:7:11", events[0].EventID)
+	assert.Equal(t, warehouse.SourceLive, events[0].Source)
+
+	assert.Equal(t, warehouse.EventElimination, events[2].EventType)
+	var elimination warehouse.EliminationData
+	require.NoError(t, json.Unmarshal(events[2].Data, &elimination))
+	assert.Equal(t, warehouse.EliminationData{QuestionID: 11, QuestionNumber: 1, Reason: "no_answer_timeout"}, elimination)
+
+	assert.Equal(t, warehouse.EventConnectionClosed, events[3].EventType)
+	var connection warehouse.ConnectionData
+	require.NoError(t, json.Unmarshal(events[3].Data, &connection))
+	assert.Equal(t, warehouse.ConnectionData{ConnectionID: "c-1", InstanceID: "api-1", DurationMs: 90000, Reason: "replaced"}, connection)
+	assert.Zero(t, exporter.Dropped())
+}
+
+func TestWarehouseExporter_DropsBatchAfterRetries(t *testing.T) {
+	sink := &recordingSink{fail: warehouseWriteAttempts}
+	exporter := NewWarehouseExporter(sink, WarehouseConfig{BatchSize: 10, FlushInterval: time.Hour})
+
+	exporter.AnswerRecorded(&entity.UserAnswer{UserID: 7, QuizID: 3, QuestionID: 11}, 1)
+	exporter.Close() // при остановке повторы идут без пауз
+
+	assert.Empty(t, sink.events())
+	assert.Equal(t, warehouseWriteAttempts, sink.calls)
+	assert.Equal(t, int64(1), exporter.Dropped())
+
+	exporter.AnswerRecorded(&entity.UserAnswer{UserID: 7, QuizID: 3, QuestionID: 12}, 2)
+	assert.Equal(t, int64(2), exporter.Dropped(), "после Close события не принимаются")
+}
+
+func TestWarehouseExporter_QuizCompletedFromEventBus(t *testing.T) {
+	sink := &recordingSink{fail: 1}
+	exporter := NewWarehouseExporter(sink, WarehouseConfig{})
+	defer exporter.Close()
+
+	outbox, err := entity.NewOutboxEvent(entity.EventQuizCompleted, entity.QuizCompletedPayload{QuizID: 3, Title: "Кино", PrizeFund: 1000, WinnerIDs: []uint{7, 9}})
+	require.NoError(t, err)
+
+	assert.Error(t, exporter.handleQuizCompleted(context.Background(), &outbox), "ошибка приёмника возвращается шине для повтора")
+	require.NoError(t, exporter.handleQuizCompleted(context.Background(), &outbox))
+
+	events := sink.events()
+	require.Len(t, events, 1)
+	assert.Equal(t, "quiz_completed:3", events[0].EventID)
+	var data warehouse.QuizCompletedData
+	require.NoError(t, json.Unmarshal(events[0].Data, &data))
+	assert.Equal(t, []uint{7, 9}, data.WinnerIDs)
+}
+
+func TestWarehouseExporter_AnswerCorrections(t *testing.T) {
+	sink := &recordingSink{}
+	exporter := NewWarehouseExporter(sink, WarehouseConfig{})
+	defer exporter.Close()
+	revokedAt := time.Date(2026, 10, 16, 19, 4, 0, 0, time.UTC)
+
+	voided, err := entity.NewOutboxEvent(entity.EventQuestionVoided, entity.QuestionVoidedPayload{QuizID: 3, QuestionID: 11, VoidedAt: revokedAt})
+	require.NoError(t, err)
+	revoked, err := entity.NewOutboxEvent(entity.EventEliminationRevoked, entity.EliminationRevokedPayload{QuizID: 3, UserID: 8, RevokedAt: revokedAt})
+	require.NoError(t, err)
+	require.NoError(t, exporter.handleAnswersChanged(context.Background(), &voided))
+	require.NoError(t, exporter.handleAnswersChanged(context.Background(), &revoked))
+
+	events := sink.events()
+	require.Len(t, events, 2)
+	assert.Equal(t, "question_voided:3:11", events[0].EventID)
+	assert.JSONEq(t, `{"question_id":11}`, string(events[0].Data))
+	assert.Equal(t, "elimination_revoked:3:8", events[1].EventID)
+	assert.Equal(t, uint(8), events[1].UserID)
+	assert.Equal(t, revokedAt, events[1].OccurredAt)
+}
+
+func TestBackfillWarehouse(t *testing.T) {
+	question := 4
+	finished := time.Date(2026, 10, 1, 20, 15, 0, 0, time.UTC)
+	dataRepo := &fakeExportJobRepo{
+		answers: []repository.AnswerExportRow{
+			{ID: 1, UserID: 7, QuestionID: 11, QuestionNumber: &question, SelectedOption: 0, IsCorrect: true},
+			{ID: 2, UserID: 8, QuestionID: 11, QuestionNumber: &question, SelectedOption: 2, IsEliminated: true, EliminationReason: "incorrect_answer"},
+			{ID: 3, UserID: 7, QuestionID: 12, SelectedOption: 1, IsCorrect: true},
+		},
+		results: []entity.Result{
+			{ID: 20, UserID: 7, Rank: 1, IsWinner: true, CompletedAt: finished},
+			{ID: 21, UserID: 8, Rank: 2, CompletedAt: finished},
+		},
+	}
+	quizRepo := new(MockQuizRepository)
+	quizRepo.On("GetByIDs", []uint{3, 4}).Return([]entity.Quiz{
+		{ID: 3, Title: "Кино", Status: entity.QuizStatusCompleted, ScheduledTime: finished.Add(-15 * time.Minute)},
+		{ID: 4, Status: entity.QuizStatusScheduled},
+	}, nil)
+	sink := &recordingSink{}
+
+	report, err := BackfillWarehouse(context.Background(), sink, quizRepo, dataRepo, WarehouseBackfillOptions{QuizIDs: []uint{3, 4}, BatchSize: 2})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, report.Quizzes, "незавершённая викторина пропускается")
+	assert.Equal(t, 5, report.Events)
+	events := sink.events()
+	require.Len(t, events, 5)
+	for _, event := range events {
+		assert.Equal(t, warehouse.SourceBackfill, event.Source)
+	}
+	assert.Equal(t, "answer:3:8:11", events[1].EventID, "ID совпадает с живым событием")
+	assert.Equal(t, "elimination:3:8:11", events[2].EventID)
+
+	last := events[4]
+	assert.Equal(t, warehouse.EventQuizCompleted, last.EventType)
+	assert.Equal(t, finished, last.OccurredAt)
+	var data warehouse.QuizCompletedData
+	require.NoError(t, json.Unmarshal(last.Data, &data))
+	assert.Equal(t, []uint{7}, data.WinnerIDs)
+}
+
+func TestBackfillWarehouse_Corrections(t *testing.T) {
+	answeredAt := time.Date(2026, 10, 1, 20, 3, 0, 0, time.UTC)
+	dataRepo := &fakeExportJobRepo{
+		answers: []repository.AnswerExportRow{
+			{ID: 1, UserID: 7, QuestionID: 11, EliminationReason: entity.AnswerReasonQuestionVoided},
+			{ID: 2, UserID: 8, QuestionID: 11, EliminationReason: entity.AnswerReasonQuestionVoided},
+			{ID: 3, UserID: 9, QuestionID: 12, SelectedOption: 2, EliminationReason: entity.AnswerReasonSecondChance, CreatedAt: answeredAt},
+		},
+	}
+	quizRepo := new(MockQuizRepository)
+	quizRepo.On("GetByIDs", []uint{3}).Return([]entity.Quiz{{ID: 3, Status: entity.QuizStatusCompleted}}, nil)
+	sink := &recordingSink{}
+
+	report, err := BackfillWarehouse(context.Background(), sink, quizRepo, dataRepo, WarehouseBackfillOptions{QuizIDs: []uint{3}})
+	require.NoError(t, err)
+
+	var ids []string
+	for _, event := range sink.events() {
+		ids = append(ids, event.EventID)
+	}
+	assert.Equal(t, []string{
+		"answer:3:7:11", "question_voided:3:11", "answer:3:8:11",
+		"answer:3:9:12", "elimination_revoked:3:9",
+		"quiz_completed:3",
+	}, ids, "снятие вопроса — одно событие на вопрос")
+	assert.Equal(t, 6, report.Events)
+	assert.Equal(t, answeredAt, sink.events()[4].OccurredAt, "отмена датируется выбившим ответом")
+}
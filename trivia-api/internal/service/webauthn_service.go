@@ -0,0 +1,347 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+
+	"github.com/yourusername/trivia-api/internal/config"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+const (
+	webAuthnSessionKeyPrefix = "webauthn:session:"
+	webAuthnMaxCredentials   = 10
+
+	webAuthnCeremonyRegister = "register"
+	webAuthnCeremonyLogin    = "login"
+)
+
+// webAuthnSession — состояние начатой регистрации или входа до её завершения
+type webAuthnSession struct {
+	Ceremony string               `json:"ceremony"`
+	UserID   uint                 `json:"user_id,omitempty"`
+	Data     webauthn.SessionData `json:"data"`
+}
+
+// webAuthnUser связывает пользователя с его ключами для библиотеки WebAuthn
+type webAuthnUser struct {
+	user        *entity.User
+	handle      []byte
+	credentials []webauthn.Credential
+}
+
+func (u *webAuthnUser) WebAuthnID() []byte                         { return u.handle }
+func (u *webAuthnUser) WebAuthnName() string                       { return u.user.Email }
+func (u *webAuthnUser) WebAuthnDisplayName() string                { return u.user.Username }
+func (u *webAuthnUser) WebAuthnCredentials() []webauthn.Credential { return u.credentials }
+
+// WebAuthnService регистрирует ключи доступа (passkeys) и проверяет вход по ним. Вход
+// выполняется без ввода email: браузер предлагает сохранённые для сайта ключи
+// (discoverable credentials), поэтому ключи создаются как резидентные.
+type WebAuthnService struct {
+	userRepo         repository.UserRepository
+	credentialRepo   repository.WebAuthnCredentialRepository
+	cache            repository.CacheRepository
+	webAuthn         *webauthn.WebAuthn
+	ceremonyTimeout  time.Duration
+	requireForAdmins bool
+	now              func() time.Time
+}
+
+// NewWebAuthnService создает сервис ключей доступа
+func NewWebAuthnService(
+	userRepo repository.UserRepository,
+	credentialRepo repository.WebAuthnCredentialRepository,
+	cache repository.CacheRepository,
+	cfg config.WebAuthnConfig,
+) (*WebAuthnService, error) {
+	if userRepo == nil {
+		return nil, fmt.Errorf("user repository is required")
+	}
+	if credentialRepo == nil {
+		return nil, fmt.Errorf("webauthn credential repository is required")
+	}
+	if cache == nil {
+		return nil, fmt.Errorf("cache repository is required")
+	}
+	if cfg.CeremonyTimeout <= 0 {
+		cfg.CeremonyTimeout = 5 * time.Minute
+	}
+	webAuthn, err := webauthn.New(&webauthn.Config{
+		RPID:          cfg.RPID,
+		RPDisplayName: cfg.RPDisplayName,
+		RPOrigins:     cfg.RPOrigins,
+		Timeouts: webauthn.TimeoutsConfig{
+			Login:        webauthn.TimeoutConfig{Enforce: true, Timeout: cfg.CeremonyTimeout, TimeoutUVD: cfg.CeremonyTimeout},
+			Registration: webauthn.TimeoutConfig{Enforce: true, Timeout: cfg.CeremonyTimeout, TimeoutUVD: cfg.CeremonyTimeout},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid webauthn config: %w", err)
+	}
+	return &WebAuthnService{
+		userRepo:         userRepo,
+		credentialRepo:   credentialRepo,
+		cache:            cache,
+		webAuthn:         webAuthn,
+		ceremonyTimeout:  cfg.CeremonyTimeout,
+		requireForAdmins: cfg.RequireForAdmins,
+		now:              time.Now,
+	}, nil
+}
+
+// BeginRegistration начинает регистрацию ключа и возвращает параметры для
+// navigator.credentials.create и идентификатор сессии для завершения
+func (s *WebAuthnService) BeginRegistration(ctx context.Context, userID uint) (*protocol.CredentialCreation, string, error) {
+	user, err := s.loadUser(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(user.credentials) >= webAuthnMaxCredentials {
+		return nil, "", fmt.Errorf("%w: at most %d passkeys per account", apperrors.ErrConflict, webAuthnMaxCredentials)
+	}
+
+	creation, data, err := s.webAuthn.BeginRegistration(user,
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementRequired),
+		webauthn.WithExclusions(webauthn.Credentials(user.credentials).CredentialDescriptors()),
+	)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to begin passkey registration: %w", err)
+	}
+	sessionID, err := s.saveSession(webAuthnSession{Ceremony: webAuthnCeremonyRegister, UserID: userID, Data: *data})
+	if err != nil {
+		return nil, "", err
+	}
+	return creation, sessionID, nil
+}
+
+// FinishRegistration проверяет ответ браузера и сохраняет ключ
+func (s *WebAuthnService) FinishRegistration(ctx context.Context, userID uint, sessionID, name string, response []byte) (*entity.WebAuthnCredential, error) {
+	session, err := s.takeSession(sessionID, webAuthnCeremonyRegister)
+	if err != nil {
+		return nil, err
+	}
+	if session.UserID != userID {
+		return nil, ErrWebAuthnCeremonyInvalid
+	}
+	user, err := s.loadUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(user.credentials) == 0 {
+		// Первый ключ: идентификатор, сгенерированный в BeginRegistration, хранится в сессии
+		user.handle = session.Data.UserID
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(response)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrWebAuthnVerificationFailed, err)
+	}
+	credential, err := s.webAuthn.CreateCredential(user, session.Data, parsed)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrWebAuthnVerificationFailed, err)
+	}
+	payload, err := json.Marshal(credential)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode passkey: %w", err)
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "Passkey"
+	}
+	record := &entity.WebAuthnCredential{
+		UserID:       userID,
+		UserHandle:   user.handle,
+		CredentialID: credential.ID,
+		Credential:   payload,
+		Name:         truncateRunes(name, 100),
+	}
+	if err := s.credentialRepo.Create(record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// BeginLogin начинает вход по ключу и возвращает параметры для navigator.credentials.get
+func (s *WebAuthnService) BeginLogin() (*protocol.CredentialAssertion, string, error) {
+	assertion, data, err := s.webAuthn.BeginDiscoverableLogin(webauthn.WithUserVerification(protocol.VerificationPreferred))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to begin passkey login: %w", err)
+	}
+	sessionID, err := s.saveSession(webAuthnSession{Ceremony: webAuthnCeremonyLogin, Data: *data})
+	if err != nil {
+		return nil, "", err
+	}
+	return assertion, sessionID, nil
+}
+
+// FinishLogin проверяет подпись ключа и возвращает его владельца. Ключ, у которого счётчик
+// подписей не вырос (признак копии ключа), отклоняется.
+func (s *WebAuthnService) FinishLogin(ctx context.Context, sessionID string, response []byte) (*entity.User, error) {
+	session, err := s.takeSession(sessionID, webAuthnCeremonyLogin)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(response)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrWebAuthnVerificationFailed, err)
+	}
+
+	var record *entity.WebAuthnCredential
+	handler := func(rawID, userHandle []byte) (webauthn.User, error) {
+		found, err := s.credentialRepo.GetByCredentialID(rawID)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(found.UserHandle, userHandle) {
+			return nil, fmt.Errorf("user handle does not match the passkey")
+		}
+		record = found
+		return s.loadUser(ctx, found.UserID)
+	}
+	owner, credential, err := s.webAuthn.ValidatePasskeyLogin(handler, session.Data, parsed)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrWebAuthnVerificationFailed, err)
+	}
+	if credential.Authenticator.CloneWarning {
+		log.Printf("[WebAuthnService] Счётчик подписей ключа ID=%d пользователя ID=%d не вырос — возможна копия ключа", record.ID, record.UserID)
+		return nil, fmt.Errorf("%w: passkey signature counter did not increase", ErrWebAuthnVerificationFailed)
+	}
+
+	payload, err := json.Marshal(credential)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode passkey: %w", err)
+	}
+	if err := s.credentialRepo.UpdateAfterLogin(record.ID, payload, s.now()); err != nil {
+		return nil, fmt.Errorf("failed to update passkey: %w", err)
+	}
+	user := owner.(*webAuthnUser).user
+	if user.DeletedAt != nil {
+		return nil, fmt.Errorf("%w: account is deleted", apperrors.ErrUnauthorized)
+	}
+	return user, nil
+}
+
+// ListCredentials возвращает ключи пользователя
+func (s *WebAuthnService) ListCredentials(userID uint) ([]entity.WebAuthnCredential, error) {
+	return s.credentialRepo.ListByUserID(userID)
+}
+
+// DeleteCredential удаляет ключ пользователя. Если для администраторов ключ обязателен,
+// последний ключ администратора удалить нельзя: иначе снова откроется вход по паролю.
+func (s *WebAuthnService) DeleteCredential(ctx context.Context, userID, credentialID uint) error {
+	if s.requireForAdmins {
+		user, err := s.userRepo.WithContext(ctx).GetByID(userID)
+		if err != nil {
+			return err
+		}
+		if user.Role == "admin" {
+			count, err := s.credentialRepo.CountByUserID(userID)
+			if err != nil {
+				return err
+			}
+			if count <= 1 {
+				return fmt.Errorf("%w: administrators must keep at least one passkey", apperrors.ErrConflict)
+			}
+		}
+	}
+	return s.credentialRepo.Delete(userID, credentialID)
+}
+
+// DeleteAllCredentials удаляет все ключи пользователя (при удалении аккаунта)
+func (s *WebAuthnService) DeleteAllCredentials(userID uint) error {
+	return s.credentialRepo.DeleteByUserID(userID)
+}
+
+// CheckLoginMethod запрещает вход без ключа (пароль, ссылка, Google) администраторам,
+// у которых есть ключ, если включено webauthn.requireForAdmins
+func (s *WebAuthnService) CheckLoginMethod(user *entity.User) error {
+	if !s.requireForAdmins || user == nil || user.Role != "admin" {
+		return nil
+	}
+	count, err := s.credentialRepo.CountByUserID(user.ID)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrPasskeyRequired
+	}
+	return nil
+}
+
+// loadUser загружает пользователя с ключами. Новому пользователю назначается случайный
+// идентификатор WebAuthn; он сохраняется вместе с первым ключом.
+func (s *WebAuthnService) loadUser(ctx context.Context, userID uint) (*webAuthnUser, error) {
+	user, err := s.userRepo.WithContext(ctx).GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	records, err := s.credentialRepo.ListByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	result := &webAuthnUser{user: user}
+	for _, record := range records {
+		var credential webauthn.Credential
+		if err := json.Unmarshal(record.Credential, &credential); err != nil {
+			return nil, fmt.Errorf("failed to decode passkey %d: %w", record.ID, err)
+		}
+		result.credentials = append(result.credentials, credential)
+		result.handle = record.UserHandle
+	}
+	if len(result.handle) == 0 {
+		result.handle = make([]byte, 32)
+		if _, err := rand.Read(result.handle); err != nil {
+			return nil, fmt.Errorf("failed to generate webauthn user handle: %w", err)
+		}
+	}
+	return result, nil
+}
+
+func (s *WebAuthnService) saveSession(session webAuthnSession) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate webauthn session id: %w", err)
+	}
+	sessionID := hex.EncodeToString(buf)
+	if err := s.cache.SetJSON(webAuthnSessionKeyPrefix+sessionID, session, s.ceremonyTimeout); err != nil {
+		return "", fmt.Errorf("failed to store webauthn session: %w", err)
+	}
+	return sessionID, nil
+}
+
+// takeSession возвращает и удаляет сессию: каждую можно завершить только один раз
+func (s *WebAuthnService) takeSession(sessionID, ceremony string) (*webAuthnSession, error) {
+	if sessionID == "" {
+		return nil, ErrWebAuthnCeremonyInvalid
+	}
+	key := webAuthnSessionKeyPrefix + sessionID
+	var session webAuthnSession
+	if err := s.cache.GetJSON(key, &session); err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil, ErrWebAuthnCeremonyInvalid
+		}
+		return nil, err
+	}
+	if err := s.cache.Delete(key); err != nil {
+		return nil, err
+	}
+	if session.Ceremony != ceremony {
+		return nil, ErrWebAuthnCeremonyInvalid
+	}
+	return &session, nil
+}
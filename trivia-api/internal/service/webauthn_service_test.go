@@ -0,0 +1,359 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/config"
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+const testWebAuthnOrigin = "https://quiz.example.com"
+
+// fakeWebAuthnCredentialRepo — WebAuthnCredentialRepository в памяти
+type fakeWebAuthnCredentialRepo struct {
+	credentials []*entity.WebAuthnCredential
+}
+
+func (f *fakeWebAuthnCredentialRepo) Create(credential *entity.WebAuthnCredential) error {
+	for _, existing := range f.credentials {
+		if bytes.Equal(existing.CredentialID, credential.CredentialID) {
+			return apperrors.ErrConflict
+		}
+	}
+	credential.ID = uint(len(f.credentials) + 1)
+	f.credentials = append(f.credentials, credential)
+	return nil
+}
+
+func (f *fakeWebAuthnCredentialRepo) GetByCredentialID(credentialID []byte) (*entity.WebAuthnCredential, error) {
+	for _, credential := range f.credentials {
+		if bytes.Equal(credential.CredentialID, credentialID) {
+			copied := *credential
+			return &copied, nil
+		}
+	}
+	return nil, apperrors.ErrNotFound
+}
+
+func (f *fakeWebAuthnCredentialRepo) ListByUserID(userID uint) ([]entity.WebAuthnCredential, error) {
+	var result []entity.WebAuthnCredential
+	for _, credential := range f.credentials {
+		if credential.UserID == userID {
+			result = append(result, *credential)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeWebAuthnCredentialRepo) CountByUserID(userID uint) (int64, error) {
+	list, _ := f.ListByUserID(userID)
+	return int64(len(list)), nil
+}
+
+func (f *fakeWebAuthnCredentialRepo) UpdateAfterLogin(id uint, credential entity.JSONPayload, usedAt time.Time) error {
+	for _, existing := range f.credentials {
+		if existing.ID == id {
+			existing.Credential = credential
+			existing.LastUsedAt = &usedAt
+		}
+	}
+	return nil
+}
+
+func (f *fakeWebAuthnCredentialRepo) Delete(userID, id uint) error {
+	for i, credential := range f.credentials {
+		if credential.ID == id && credential.UserID == userID {
+			f.credentials = append(f.credentials[:i], f.credentials[i+1:]...)
+			return nil
+		}
+	}
+	return apperrors.ErrNotFound
+}
+
+func (f *fakeWebAuthnCredentialRepo) DeleteByUserID(userID uint) error {
+	return nil
+}
+
+// memoryJSONCache — JSON-значения Redis в памяти
+type memoryJSONCache struct {
+	repository.CacheRepository
+	values map[string][]byte
+}
+
+func (m *memoryJSONCache) SetJSON(key string, value interface{}, expiration time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	m.values[key] = data
+	return nil
+}
+
+func (m *memoryJSONCache) GetJSON(key string, dest interface{}) error {
+	data, ok := m.values[key]
+	if !ok {
+		return apperrors.ErrNotFound
+	}
+	return json.Unmarshal(data, dest)
+}
+
+func (m *memoryJSONCache) Delete(key string) error {
+	delete(m.values, key)
+	return nil
+}
+
+// testAuthenticator — программный ключ доступа: ES256, аттестация "none"
+type testAuthenticator struct {
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	userHandle   []byte
+	counter      uint32
+}
+
+func newTestAuthenticator(t *testing.T) *testAuthenticator {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	credentialID := make([]byte, 16)
+	_, err = rand.Read(credentialID)
+	require.NoError(t, err)
+	return &testAuthenticator{key: key, credentialID: credentialID}
+}
+
+func b64(data []byte) string { return base64.RawURLEncoding.EncodeToString(data) }
+
+// cborBytes кодирует байтовую строку CBOR (длина до 65535)
+func cborBytes(data []byte) []byte {
+	switch {
+	case len(data) < 24:
+		return append([]byte{0x40 | byte(len(data))}, data...)
+	case len(data) < 256:
+		return append([]byte{0x58, byte(len(data))}, data...)
+	default:
+		return append([]byte{0x59, byte(len(data) >> 8), byte(len(data))}, data...)
+	}
+}
+
+func cborText(value string) []byte {
+	return append([]byte{0x60 | byte(len(value))}, value...)
+}
+
+func (a *testAuthenticator) authData(flags byte) []byte {
+	rpIDHash := sha256.Sum256([]byte("quiz.example.com"))
+	data := append(rpIDHash[:], flags)
+	return binary.BigEndian.AppendUint32(data, a.counter)
+}
+
+func clientDataJSON(t *testing.T, ceremony string, challenge interface{}) []byte {
+	t.Helper()
+	data, err := json.Marshal(map[string]interface{}{"type": ceremony, "challenge": challenge, "origin": testWebAuthnOrigin})
+	require.NoError(t, err)
+	return data
+}
+
+// create возвращает ответ navigator.credentials.create для параметров регистрации
+func (a *testAuthenticator) create(t *testing.T, options interface{}) []byte {
+	t.Helper()
+	var parsed struct {
+		PublicKey struct {
+			Challenge string `json:"challenge"`
+			User      struct {
+				ID string `json:"id"`
+			} `json:"user"`
+		} `json:"publicKey"`
+	}
+	raw, err := json.Marshal(options)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(raw, &parsed))
+	a.userHandle, err = base64.RawURLEncoding.DecodeString(parsed.PublicKey.User.ID)
+	require.NoError(t, err)
+
+	// COSE-ключ EC2: {1: 2, 3: -7, -1: 1, -2: x, -3: y}
+	x, y := a.key.PublicKey.X.FillBytes(make([]byte, 32)), a.key.PublicKey.Y.FillBytes(make([]byte, 32))
+	coseKey := []byte{0xa5, 0x01, 0x02, 0x03, 0x26, 0x20, 0x01, 0x21}
+	coseKey = append(append(coseKey, cborBytes(x)...), 0x22)
+	coseKey = append(coseKey, cborBytes(y)...)
+
+	authData := a.authData(0x45) // UP | UV | AT
+	authData = append(authData, make([]byte, 16)...)
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(a.credentialID)))
+	authData = append(append(authData, a.credentialID...), coseKey...)
+
+	attestation := []byte{0xa3}
+	attestation = append(append(attestation, cborText("fmt")...), cborText("none")...)
+	attestation = append(append(attestation, cborText("attStmt")...), 0xa0)
+	attestation = append(append(attestation, cborText("authData")...), cborBytes(authData)...)
+
+	response, err := json.Marshal(map[string]interface{}{
+		"id":    b64(a.credentialID),
+		"rawId": b64(a.credentialID),
+		"type":  "public-key",
+		"response": map[string]string{
+			"clientDataJSON":    b64(clientDataJSON(t, "webauthn.create", parsed.PublicKey.Challenge)),
+			"attestationObject": b64(attestation),
+		},
+	})
+	require.NoError(t, err)
+	return response
+}
+
+// get возвращает ответ navigator.credentials.get для параметров входа
+func (a *testAuthenticator) get(t *testing.T, options interface{}) []byte {
+	t.Helper()
+	var parsed struct {
+		PublicKey struct {
+			Challenge string `json:"challenge"`
+		} `json:"publicKey"`
+	}
+	raw, err := json.Marshal(options)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(raw, &parsed))
+
+	authData := a.authData(0x05) // UP | UV
+	clientData := clientDataJSON(t, "webauthn.get", parsed.PublicKey.Challenge)
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	require.NoError(t, err)
+
+	response, err := json.Marshal(map[string]interface{}{
+		"id":    b64(a.credentialID),
+		"rawId": b64(a.credentialID),
+		"type":  "public-key",
+		"response": map[string]string{
+			"clientDataJSON":    b64(clientData),
+			"authenticatorData": b64(authData),
+			"signature":         b64(signature),
+			"userHandle":        b64(a.userHandle),
+		},
+	})
+	require.NoError(t, err)
+	return response
+}
+
+func newTestWebAuthnService(t *testing.T, users *MockUserRepository, requireForAdmins bool) (*WebAuthnService, *fakeWebAuthnCredentialRepo, *memoryJSONCache) {
+	t.Helper()
+	repo := &fakeWebAuthnCredentialRepo{}
+	cache := &memoryJSONCache{values: map[string][]byte{}}
+	svc, err := NewWebAuthnService(users, repo, cache, config.WebAuthnConfig{
+		RPID:             "quiz.example.com",
+		RPDisplayName:    "Trivia",
+		RPOrigins:        []string{testWebAuthnOrigin},
+		CeremonyTimeout:  5 * time.Minute,
+		RequireForAdmins: requireForAdmins,
+	})
+	require.NoError(t, err)
+	return svc, repo, cache
+}
+
+func registerTestPasskey(t *testing.T, svc *WebAuthnService, userID uint, name string) *testAuthenticator {
+	t.Helper()
+	authenticator := newTestAuthenticator(t)
+	options, sessionID, err := svc.BeginRegistration(context.Background(), userID)
+	require.NoError(t, err)
+	_, err = svc.FinishRegistration(context.Background(), userID, sessionID, name, authenticator.create(t, options))
+	require.NoError(t, err)
+	return authenticator
+}
+
+func TestWebAuthn_RegisterAndLogin(t *testing.T) {
+	users := new(MockUserRepository)
+	user := &entity.User{ID: 7, Email: "player@example.com", Username: "player"}
+	users.On("GetByID", uint(7)).Return(user, nil)
+	svc, repo, cache := newTestWebAuthnService(t, users, false)
+
+	first := registerTestPasskey(t, svc, 7, " Laptop ")
+	second := registerTestPasskey(t, svc, 7, "")
+	require.Len(t, repo.credentials, 2)
+	assert.Equal(t, "Laptop", repo.credentials[0].Name)
+	assert.Equal(t, "Passkey", repo.credentials[1].Name)
+	assert.Equal(t, first.userHandle, second.userHandle, "у всех ключей пользователя общий user handle")
+	assert.Empty(t, cache.values, "сессии регистрации удаляются после завершения")
+
+	second.counter = 3
+	options, sessionID, err := svc.BeginLogin()
+	require.NoError(t, err)
+	response := second.get(t, options)
+	loggedIn, err := svc.FinishLogin(context.Background(), sessionID, response)
+	require.NoError(t, err)
+	assert.Equal(t, uint(7), loggedIn.ID)
+	assert.NotNil(t, repo.credentials[1].LastUsedAt)
+
+	// Сессия одноразовая: повтор того же ответа отклоняется
+	_, err = svc.FinishLogin(context.Background(), sessionID, response)
+	assert.ErrorIs(t, err, ErrWebAuthnCeremonyInvalid)
+
+	// Счётчик подписей не вырос — похоже на копию ключа
+	options, sessionID, err = svc.BeginLogin()
+	require.NoError(t, err)
+	_, err = svc.FinishLogin(context.Background(), sessionID, second.get(t, options))
+	assert.ErrorIs(t, err, ErrWebAuthnVerificationFailed)
+
+	// Сессию входа нельзя использовать для регистрации
+	_, sessionID, err = svc.BeginLogin()
+	require.NoError(t, err)
+	_, err = svc.FinishRegistration(context.Background(), 7, sessionID, "", []byte(`{}`))
+	assert.ErrorIs(t, err, ErrWebAuthnCeremonyInvalid)
+}
+
+func TestWebAuthn_RejectsForeignSessionAndDuplicateKey(t *testing.T) {
+	users := new(MockUserRepository)
+	users.On("GetByID", uint(7)).Return(&entity.User{ID: 7, Email: "a@example.com", Username: "a"}, nil)
+	users.On("GetByID", uint(8)).Return(&entity.User{ID: 8, Email: "b@example.com", Username: "b"}, nil)
+	svc, _, _ := newTestWebAuthnService(t, users, false)
+
+	authenticator := newTestAuthenticator(t)
+	options, sessionID, err := svc.BeginRegistration(context.Background(), 7)
+	require.NoError(t, err)
+	_, err = svc.FinishRegistration(context.Background(), 8, sessionID, "", authenticator.create(t, options))
+	assert.ErrorIs(t, err, ErrWebAuthnCeremonyInvalid, "сессию регистрации завершает только тот, кто её начал")
+
+	options, sessionID, err = svc.BeginRegistration(context.Background(), 7)
+	require.NoError(t, err)
+	_, err = svc.FinishRegistration(context.Background(), 7, sessionID, "", authenticator.create(t, options))
+	require.NoError(t, err)
+
+	options, sessionID, err = svc.BeginRegistration(context.Background(), 8)
+	require.NoError(t, err)
+	_, err = svc.FinishRegistration(context.Background(), 8, sessionID, "", authenticator.create(t, options))
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+}
+
+func TestWebAuthn_RequireForAdmins(t *testing.T) {
+	users := new(MockUserRepository)
+	admin := &entity.User{ID: 1, Email: "admin@example.com", Username: "admin", Role: "admin"}
+	player := &entity.User{ID: 2, Email: "player@example.com", Username: "player", Role: "user"}
+	users.On("GetByID", uint(1)).Return(admin, nil)
+	users.On("GetByID", uint(2)).Return(player, nil)
+	svc, repo, _ := newTestWebAuthnService(t, users, true)
+
+	// Пока у администратора нет ключа, вход по паролю открыт — иначе ключ не зарегистрировать
+	assert.NoError(t, svc.CheckLoginMethod(admin))
+
+	registerTestPasskey(t, svc, 1, "YubiKey")
+	registerTestPasskey(t, svc, 2, "Phone")
+	assert.ErrorIs(t, svc.CheckLoginMethod(admin), ErrPasskeyRequired)
+	assert.NoError(t, svc.CheckLoginMethod(player))
+
+	assert.ErrorIs(t, svc.DeleteCredential(context.Background(), 1, repo.credentials[0].ID), apperrors.ErrConflict)
+	assert.NoError(t, svc.DeleteCredential(context.Background(), 2, repo.credentials[1].ID))
+
+	registerTestPasskey(t, svc, 1, "Backup")
+	assert.NoError(t, svc.DeleteCredential(context.Background(), 1, repo.credentials[0].ID))
+	assert.ErrorIs(t, svc.DeleteCredential(context.Background(), 2, repo.credentials[0].ID), apperrors.ErrNotFound)
+}
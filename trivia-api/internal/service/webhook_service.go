@@ -0,0 +1,457 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+const (
+	// Заголовки запроса доставки
+	WebhookSignatureHeader = "X-Trivia-Signature"
+	WebhookEventHeader     = "X-Trivia-Event"
+	WebhookEventIDHeader   = "X-Trivia-Event-Id"
+	WebhookDeliveryHeader  = "X-Trivia-Delivery"
+
+	webhookRetryBase = 30 * time.Second
+	webhookRetryMax  = 6 * time.Hour
+	// webhookClaimLease — на сколько откладывается доставка, забранная на отправку
+	webhookClaimLease = 2 * time.Minute
+	// webhookConcurrency — сколько доставок отправляется одновременно
+	webhookConcurrency = 8
+)
+
+// WebhookConfig содержит настройки доставки вебхуков
+type WebhookConfig struct {
+	MaxAttempts int           // попыток на доставку, включая первую
+	Timeout     time.Duration // таймаут одного запроса
+	BatchSize   int           // доставок за один проход
+	AllowHTTP   bool          // разрешить адреса http:// (для разработки)
+	Retention   time.Duration // срок хранения журнала доставки, 0 — бессрочно
+}
+
+// WebhookEndpointInput — настраиваемые поля адреса. Пустой Secret при создании
+// генерируется, при изменении — оставляет прежний.
+type WebhookEndpointInput struct {
+	URL         string
+	Secret      string
+	Events      []string
+	Description string
+	Active      bool
+}
+
+// WebhookEvent — тело запроса доставки
+type WebhookEvent struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// WebhookService отправляет партнёрам события викторин. Publish записывает доставки в БД,
+// фоновый обработчик отправляет их с подписью HMAC-SHA256 и повторяет неудачные
+// с экспоненциальной задержкой.
+type WebhookService struct {
+	repo       repository.WebhookRepository
+	config     WebhookConfig
+	httpClient *http.Client
+	kick       chan struct{}
+}
+
+// NewWebhookService создает сервис вебхуков
+func NewWebhookService(repo repository.WebhookRepository, config WebhookConfig) *WebhookService {
+	return &WebhookService{
+		repo:       repo,
+		config:     config,
+		httpClient: &http.Client{Timeout: config.Timeout},
+		kick:       make(chan struct{}, 1),
+	}
+}
+
+// CreateEndpoint регистрирует адрес. Возвращает секрет подписи: он показывается только при создании.
+func (s *WebhookService) CreateEndpoint(input WebhookEndpointInput) (*entity.WebhookEndpoint, string, error) {
+	if err := s.validateEndpoint(input); err != nil {
+		return nil, "", err
+	}
+	secret := input.Secret
+	if secret == "" {
+		generated, err := generateWebhookSecret()
+		if err != nil {
+			return nil, "", err
+		}
+		secret = generated
+	}
+
+	endpoint := &entity.WebhookEndpoint{
+		URL:         input.URL,
+		Secret:      secret,
+		Events:      entity.StringArray(input.Events),
+		Description: input.Description,
+		Active:      input.Active,
+	}
+	if err := s.repo.CreateEndpoint(endpoint); err != nil {
+		return nil, "", err
+	}
+	return endpoint, secret, nil
+}
+
+// UpdateEndpoint заменяет настройки адреса
+func (s *WebhookService) UpdateEndpoint(id uint, input WebhookEndpointInput) (*entity.WebhookEndpoint, error) {
+	if err := s.validateEndpoint(input); err != nil {
+		return nil, err
+	}
+	endpoint, err := s.repo.GetEndpoint(id)
+	if err != nil {
+		return nil, err
+	}
+	endpoint.URL = input.URL
+	endpoint.Events = entity.StringArray(input.Events)
+	endpoint.Description = input.Description
+	endpoint.Active = input.Active
+	if input.Secret != "" {
+		endpoint.Secret = input.Secret
+	}
+	if err := s.repo.UpdateEndpoint(endpoint); err != nil {
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+// GetEndpoint возвращает адрес по ID
+func (s *WebhookService) GetEndpoint(id uint) (*entity.WebhookEndpoint, error) {
+	return s.repo.GetEndpoint(id)
+}
+
+// ListEndpoints возвращает все адреса
+func (s *WebhookService) ListEndpoints() ([]entity.WebhookEndpoint, error) {
+	return s.repo.ListEndpoints(false)
+}
+
+// DeleteEndpoint удаляет адрес вместе с журналом доставки
+func (s *WebhookService) DeleteEndpoint(id uint) error {
+	return s.repo.DeleteEndpoint(id)
+}
+
+// ListDeliveries возвращает журнал доставки адреса постранично
+func (s *WebhookService) ListDeliveries(endpointID uint, status string, page, pageSize int) ([]entity.WebhookDelivery, int64, error) {
+	if _, err := s.repo.GetEndpoint(endpointID); err != nil {
+		return nil, 0, err
+	}
+	switch status {
+	case "", entity.WebhookDeliveryPending, entity.WebhookDeliverySucceeded, entity.WebhookDeliveryFailed:
+	default:
+		return nil, 0, fmt.Errorf("%w: unknown delivery status %q", apperrors.ErrValidation, status)
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 50
+	}
+	return s.repo.ListDeliveries(endpointID, status, pageSize, (page-1)*pageSize)
+}
+
+// Publish ставит событие в очередь доставки всем включённым адресам, подписанным на него.
+// Ошибки только логируются: вебхуки не должны прерывать ход викторины.
+func (s *WebhookService) Publish(eventType string, data interface{}) {
+	if err := s.publish(uuid.NewString(), eventType, data); err != nil {
+		log.Printf("[WebhookService] Ошибка постановки события %s в очередь: %v", eventType, err)
+	}
+}
+
+// SubscribeTo подписывает вебхуки на доменные события шины: quiz.completed с победителями
+// превращается в quiz:winners. ID события вебхука выводится из ID события outbox,
+// поэтому повторная обработка даёт получателю тот же id.
+func (s *WebhookService) SubscribeTo(bus *EventBus) {
+	bus.Subscribe("webhooks", s.handleQuizCompleted, entity.EventQuizCompleted)
+}
+
+func (s *WebhookService) handleQuizCompleted(ctx context.Context, event *entity.OutboxEvent) error {
+	var payload entity.QuizCompletedPayload
+	if err := event.Decode(&payload); err != nil {
+		return err
+	}
+	if len(payload.WinnerIDs) == 0 {
+		return nil
+	}
+	quiz := &entity.Quiz{
+		ID:            payload.QuizID,
+		Title:         payload.Title,
+		ScheduledTime: payload.ScheduledTime,
+		Status:        entity.QuizStatusCompleted,
+		PrizeFund:     payload.PrizeFund,
+	}
+	data := quizWebhookData(quiz)
+	data["winner_ids"] = payload.WinnerIDs
+	data["winners_count"] = len(payload.WinnerIDs)
+	data["prize_per_winner"] = payload.PrizePerWinner
+	if len(payload.Prizes) > 0 {
+		data["prizes"] = payload.Prizes
+	}
+	return s.publish(fmt.Sprintf("outbox-%d", event.ID), entity.WebhookEventQuizWinners, data)
+}
+
+func (s *WebhookService) publish(eventID, eventType string, data interface{}) error {
+	endpoints, err := s.repo.ListEndpoints(true)
+	if err != nil {
+		return err
+	}
+
+	event := WebhookEvent{ID: eventID, Type: eventType, CreatedAt: time.Now().UTC(), Data: data}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook event: %w", err)
+	}
+
+	var deliveries []entity.WebhookDelivery
+	for _, endpoint := range endpoints {
+		if !endpoint.Subscribed(eventType) {
+			continue
+		}
+		deliveries = append(deliveries, entity.WebhookDelivery{
+			EndpointID:    endpoint.ID,
+			EventID:       event.ID,
+			EventType:     eventType,
+			Payload:       payload,
+			Status:        entity.WebhookDeliveryPending,
+			NextAttemptAt: event.CreatedAt,
+		})
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	if err := s.repo.CreateDeliveries(deliveries); err != nil {
+		return err
+	}
+
+	select {
+	case s.kick <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Start запускает отправку доставок: каждые interval и сразу после Publish.
+// Раз в час удаляет журнал доставки старше Retention. Работает до отмены ctx.
+func (s *WebhookService) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		cleanup := time.NewTicker(time.Hour)
+		defer cleanup.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-s.kick:
+			case <-cleanup.C:
+				s.cleanup()
+				continue
+			}
+			if _, err := s.DeliverDue(ctx); err != nil {
+				log.Printf("[WebhookService] Ошибка отправки вебхуков: %v", err)
+			}
+		}
+	}()
+}
+
+// DeliverDue отправляет доставки, срок которых наступил, и возвращает их число
+func (s *WebhookService) DeliverDue(ctx context.Context) (int, error) {
+	deliveries, err := s.repo.ClaimDueDeliveries(time.Now().UTC(), webhookClaimLease, s.config.BatchSize)
+	if err != nil || len(deliveries) == 0 {
+		return 0, err
+	}
+	endpoints, err := s.repo.ListEndpoints(false)
+	if err != nil {
+		return 0, err
+	}
+	byID := make(map[uint]entity.WebhookEndpoint, len(endpoints))
+	for _, endpoint := range endpoints {
+		byID[endpoint.ID] = endpoint
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, webhookConcurrency)
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		endpoint, ok := byID[delivery.EndpointID]
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if !ok || !endpoint.Active {
+				s.finish(delivery, entity.WebhookDeliveryFailed, 0, "endpoint is disabled")
+				return
+			}
+			s.attempt(ctx, &endpoint, delivery)
+		}()
+	}
+	wg.Wait()
+	return len(deliveries), nil
+}
+
+// attempt выполняет одну попытку доставки и сохраняет её результат
+func (s *WebhookService) attempt(ctx context.Context, endpoint *entity.WebhookEndpoint, delivery *entity.WebhookDelivery) {
+	delivery.Attempts++
+	statusCode, err := s.send(ctx, endpoint, delivery)
+	if err == nil {
+		s.finish(delivery, entity.WebhookDeliverySucceeded, statusCode, "")
+		return
+	}
+	if delivery.Attempts >= s.config.MaxAttempts {
+		log.Printf("[WebhookService] Доставка #%d на адрес #%d не удалась после %d попыток: %v", delivery.ID, endpoint.ID, delivery.Attempts, err)
+		s.finish(delivery, entity.WebhookDeliveryFailed, statusCode, err.Error())
+		return
+	}
+	delivery.NextAttemptAt = time.Now().UTC().Add(WebhookRetryDelay(delivery.Attempts))
+	s.finish(delivery, entity.WebhookDeliveryPending, statusCode, err.Error())
+}
+
+func (s *WebhookService) send(ctx context.Context, endpoint *entity.WebhookEndpoint, delivery *entity.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "trivia-api-webhooks/1")
+	req.Header.Set(WebhookEventHeader, delivery.EventType)
+	req.Header.Set(WebhookEventIDHeader, delivery.EventID)
+	req.Header.Set(WebhookDeliveryHeader, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(endpoint.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func (s *WebhookService) finish(delivery *entity.WebhookDelivery, status string, statusCode int, lastError string) {
+	delivery.Status = status
+	delivery.LastStatusCode = statusCode
+	if len(lastError) > 500 {
+		lastError = lastError[:500]
+	}
+	delivery.LastError = lastError
+	if status == entity.WebhookDeliverySucceeded {
+		now := time.Now().UTC()
+		delivery.DeliveredAt = &now
+	}
+	if err := s.repo.UpdateDelivery(delivery); err != nil {
+		log.Printf("[WebhookService] Ошибка сохранения доставки #%d: %v", delivery.ID, err)
+	}
+}
+
+func (s *WebhookService) cleanup() {
+	if s.config.Retention <= 0 {
+		return
+	}
+	deleted, err := s.repo.DeleteDeliveriesBefore(time.Now().UTC().Add(-s.config.Retention))
+	if err != nil {
+		log.Printf("[WebhookService] Ошибка очистки журнала доставки: %v", err)
+		return
+	}
+	if deleted > 0 {
+		log.Printf("[WebhookService] Удалено %d старых записей журнала доставки", deleted)
+	}
+}
+
+func (s *WebhookService) validateEndpoint(input WebhookEndpointInput) error {
+	parsed, err := url.Parse(input.URL)
+	if err != nil || parsed.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute URL", apperrors.ErrValidation)
+	}
+	if parsed.Scheme != "https" && !(s.config.AllowHTTP && parsed.Scheme == "http") {
+		return fmt.Errorf("%w: url must use https", apperrors.ErrValidation)
+	}
+	if input.Secret != "" && len(input.Secret) < 16 {
+		return fmt.Errorf("%w: secret must be at least 16 characters", apperrors.ErrValidation)
+	}
+	if len(input.Events) == 0 {
+		return fmt.Errorf("%w: at least one event is required", apperrors.ErrValidation)
+	}
+	for _, event := range input.Events {
+		known := false
+		for _, candidate := range entity.WebhookEvents {
+			if event == candidate {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("%w: unknown event %q (supported: %s)", apperrors.ErrValidation, event, strings.Join(entity.WebhookEvents, ", "))
+		}
+	}
+	return nil
+}
+
+// WebhookRetryDelay — задержка перед повтором после attempts неудачных попыток:
+// 30с, 1м, 2м, ... не больше 6 часов
+func WebhookRetryDelay(attempts int) time.Duration {
+	delay := webhookRetryBase
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= webhookRetryMax {
+			return webhookRetryMax
+		}
+	}
+	return delay
+}
+
+// SignWebhookPayload возвращает значение заголовка подписи: t=<unix-время>,v1=<hex HMAC-SHA256
+// от "<unix-время>.<тело>">. Время в подписи защищает получателя от повтора старых запросов.
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// quizWebhookData — общие поля викторины в событиях вебхуков
+func quizWebhookData(quiz *entity.Quiz) map[string]interface{} {
+	return map[string]interface{}{
+		"quiz_id":        quiz.ID,
+		"title":          quiz.Title,
+		"scheduled_time": quiz.ScheduledTime.UTC(),
+		"status":         quiz.Status,
+		"prize_fund":     quiz.PrizeFund,
+	}
+}
+
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
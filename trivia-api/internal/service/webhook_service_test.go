@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// fakeWebhookRepo — WebhookRepository в памяти
+type fakeWebhookRepo struct {
+	endpoints  []entity.WebhookEndpoint
+	deliveries []entity.WebhookDelivery
+}
+
+func (f *fakeWebhookRepo) CreateEndpoint(endpoint *entity.WebhookEndpoint) error {
+	endpoint.ID = uint(len(f.endpoints) + 1)
+	f.endpoints = append(f.endpoints, *endpoint)
+	return nil
+}
+
+func (f *fakeWebhookRepo) GetEndpoint(id uint) (*entity.WebhookEndpoint, error) {
+	for _, endpoint := range f.endpoints {
+		if endpoint.ID == id {
+			return &endpoint, nil
+		}
+	}
+	return nil, apperrors.ErrNotFound
+}
+
+func (f *fakeWebhookRepo) ListEndpoints(activeOnly bool) ([]entity.WebhookEndpoint, error) {
+	var endpoints []entity.WebhookEndpoint
+	for _, endpoint := range f.endpoints {
+		if !activeOnly || endpoint.Active {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	return endpoints, nil
+}
+
+func (f *fakeWebhookRepo) UpdateEndpoint(endpoint *entity.WebhookEndpoint) error {
+	f.endpoints[endpoint.ID-1] = *endpoint
+	return nil
+}
+
+func (f *fakeWebhookRepo) DeleteEndpoint(id uint) error { return nil }
+
+func (f *fakeWebhookRepo) CreateDeliveries(deliveries []entity.WebhookDelivery) error {
+	for _, delivery := range deliveries {
+		delivery.ID = uint(len(f.deliveries) + 1)
+		f.deliveries = append(f.deliveries, delivery)
+	}
+	return nil
+}
+
+func (f *fakeWebhookRepo) ClaimDueDeliveries(now time.Time, lease time.Duration, limit int) ([]entity.WebhookDelivery, error) {
+	var due []entity.WebhookDelivery
+	for i := range f.deliveries {
+		d := &f.deliveries[i]
+		if d.Status == entity.WebhookDeliveryPending && !d.NextAttemptAt.After(now) && len(due) < limit {
+			d.NextAttemptAt = now.Add(lease)
+			due = append(due, *d)
+		}
+	}
+	return due, nil
+}
+
+func (f *fakeWebhookRepo) UpdateDelivery(delivery *entity.WebhookDelivery) error {
+	f.deliveries[delivery.ID-1] = *delivery
+	return nil
+}
+
+func (f *fakeWebhookRepo) ListDeliveries(endpointID uint, status string, limit, offset int) ([]entity.WebhookDelivery, int64, error) {
+	return f.deliveries, int64(len(f.deliveries)), nil
+}
+
+func (f *fakeWebhookRepo) DeleteDeliveriesBefore(before time.Time) (int64, error) { return 0, nil }
+
+// makeDue делает все ожидающие доставки готовыми к отправке
+func (f *fakeWebhookRepo) makeDue() {
+	for i := range f.deliveries {
+		f.deliveries[i].NextAttemptAt = time.Now().Add(-time.Second)
+	}
+}
+
+func TestWebhookService_PublishAndDeliverSigned(t *testing.T) {
+	var gotSignature, gotEvent string
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSignature = r.Header.Get(WebhookSignatureHeader)
+		gotEvent = r.Header.Get(WebhookEventHeader)
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	repo := &fakeWebhookRepo{}
+	svc := NewWebhookService(repo, WebhookConfig{MaxAttempts: 3, Timeout: time.Second, BatchSize: 10, AllowHTTP: true})
+
+	endpoint, secret, err := svc.CreateEndpoint(WebhookEndpointInput{
+		URL: server.URL, Events: []string{entity.WebhookEventQuizStarted}, Active: true,
+	})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(secret, "whsec_"))
+	_, _, err = svc.CreateEndpoint(WebhookEndpointInput{
+		URL: server.URL, Events: []string{entity.WebhookEventQuizWinners}, Active: true,
+	})
+	require.NoError(t, err)
+
+	svc.Publish(entity.WebhookEventQuizStarted, map[string]interface{}{"quiz_id": 5})
+	require.Len(t, repo.deliveries, 1, "only subscribed endpoints get a delivery")
+	assert.Equal(t, endpoint.ID, repo.deliveries[0].EndpointID)
+
+	sent, err := svc.DeliverDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+
+	delivery := repo.deliveries[0]
+	assert.Equal(t, entity.WebhookDeliverySucceeded, delivery.Status)
+	assert.Equal(t, http.StatusNoContent, delivery.LastStatusCode)
+	assert.NotNil(t, delivery.DeliveredAt)
+
+	assert.Equal(t, entity.WebhookEventQuizStarted, gotEvent)
+	parts := strings.SplitN(strings.TrimPrefix(gotSignature, "t="), ",", 2)
+	require.Len(t, parts, 2)
+	timestamp, err := strconv.ParseInt(parts[0], 10, 64)
+	require.NoError(t, err)
+	assert.Equal(t, SignWebhookPayload(secret, timestamp, gotBody), gotSignature)
+}
+
+func TestWebhookService_RetriesWithBackoffThenFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	repo := &fakeWebhookRepo{}
+	svc := NewWebhookService(repo, WebhookConfig{MaxAttempts: 2, Timeout: time.Second, BatchSize: 10, AllowHTTP: true})
+	_, _, err := svc.CreateEndpoint(WebhookEndpointInput{
+		URL: server.URL, Events: []string{entity.WebhookEventQuizCompleted}, Active: true,
+	})
+	require.NoError(t, err)
+	svc.Publish(entity.WebhookEventQuizCompleted, map[string]interface{}{"quiz_id": 5})
+
+	_, err = svc.DeliverDue(context.Background())
+	require.NoError(t, err)
+	delivery := repo.deliveries[0]
+	assert.Equal(t, entity.WebhookDeliveryPending, delivery.Status)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, http.StatusBadGateway, delivery.LastStatusCode)
+	assert.WithinDuration(t, time.Now().Add(30*time.Second), delivery.NextAttemptAt, 5*time.Second)
+
+	repo.makeDue()
+	_, err = svc.DeliverDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, entity.WebhookDeliveryFailed, repo.deliveries[0].Status)
+	assert.Equal(t, 2, repo.deliveries[0].Attempts)
+}
+
+func TestWebhookService_ValidateEndpoint(t *testing.T) {
+	svc := NewWebhookService(&fakeWebhookRepo{}, WebhookConfig{MaxAttempts: 1, Timeout: time.Second, BatchSize: 1})
+
+	_, _, err := svc.CreateEndpoint(WebhookEndpointInput{URL: "http://partner.example/hook", Events: []string{entity.WebhookEventQuizStarted}})
+	assert.ErrorIs(t, err, apperrors.ErrValidation, "plain http is rejected unless allowed")
+	_, _, err = svc.CreateEndpoint(WebhookEndpointInput{URL: "https://partner.example/hook", Events: []string{"quiz:unknown"}})
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	assert.Equal(t, 30*time.Second, WebhookRetryDelay(1))
+	assert.Equal(t, 2*time.Minute, WebhookRetryDelay(3))
+	assert.Equal(t, 6*time.Hour, WebhookRetryDelay(20))
+}
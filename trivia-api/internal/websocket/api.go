@@ -328,6 +328,14 @@ func renderPrometheusMetrics(w http.ResponseWriter, metrics map[string]interface
 				fmt.Fprintf(w, "websocket_shard_load_percentage{shard_id=\"%v\"} %f %d\n",
 					shardID, loadPercentage, timestamp)
 			}
+			if rtt, ok := shard["rtt_ms"].(map[string]interface{}); ok {
+				for _, quantile := range []struct{ key, label string }{{"p50", "0.5"}, {"p90", "0.9"}, {"p99", "0.99"}} {
+					if value, ok := rtt[quantile.key].(float64); ok {
+						fmt.Fprintf(w, "websocket_shard_rtt_ms{shard_id=\"%v\",quantile=\"%s\"} %f %d\n",
+							shardID, quantile.label, value, timestamp)
+					}
+				}
+			}
 
 			// Добавляем метрики отключений для каждого шарда
 			if disconnectionStats, ok := shard["disconnection_stats"].(map[string]interface{}); ok {
@@ -0,0 +1,251 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+)
+
+// Сообщения обновления авторизации соединения без переподключения
+const (
+	// TOKEN_EXPIRING — авторизация соединения отозвана; клиент должен прислать новый тикет до refresh_by
+	TOKEN_EXPIRING = "token_expiring"
+	// AUTH_REFRESH — клиент присылает новый тикет, полученный через POST /api/auth/ws-ticket
+	AUTH_REFRESH = "auth:refresh"
+	// AUTH_REFRESHED — тикет принят, соединение продолжает работу
+	AUTH_REFRESHED = "auth:refreshed"
+)
+
+// Причины отзыва авторизации соединения
+const (
+	SessionRevokedInvalidated = "invalidated" // токены пользователя отозваны (выход со всех устройств, блокировка)
+	SessionRevokedKeyRotated  = "key_rotated" // ключ подписи тикета выведен из оборота
+)
+
+// ClientSession — авторизация соединения: пользователь и тикет, которым он подключился
+type ClientSession struct {
+	UserID   uint
+	KeyID    string    // ключ подписи тикета (kid)
+	IssuedAt time.Time // время выдачи тикета
+}
+
+// SessionAuthenticator проверяет тикеты соединений и то, что их авторизация ещё действует
+// (реализуется auth.JWTService)
+type SessionAuthenticator interface {
+	// ParseSessionTicket проверяет тикет и возвращает авторизацию соединения
+	ParseSessionTicket(ctx context.Context, ticket string) (ClientSession, error)
+	// RevokedSessions возвращает для каждой авторизации причину отзыва; "" — авторизация действует
+	RevokedSessions(ctx context.Context, sessions []ClientSession) ([]string, error)
+}
+
+// authState — авторизация соединения и срок, до которого её нужно обновить
+type authState struct {
+	mu              sync.Mutex
+	session         ClientSession
+	refreshDeadline time.Time // нулевой — обновление не запрошено
+}
+
+// SetSession задаёт авторизацию соединения и снимает запрос на её обновление
+func (c *Client) SetSession(session ClientSession) {
+	c.auth.mu.Lock()
+	defer c.auth.mu.Unlock()
+	c.auth.session = session
+	c.auth.refreshDeadline = time.Time{}
+}
+
+// Session возвращает авторизацию соединения
+func (c *Client) Session() ClientSession {
+	c.auth.mu.Lock()
+	defer c.auth.mu.Unlock()
+	return c.auth.session
+}
+
+// RefreshDeadline возвращает срок, до которого клиент должен обновить авторизацию; нулевой — не запрошено
+func (c *Client) RefreshDeadline() time.Time {
+	c.auth.mu.Lock()
+	defer c.auth.mu.Unlock()
+	return c.auth.refreshDeadline
+}
+
+// requestRefresh запрашивает обновление авторизации, если оно ещё не запрошено.
+// Сессия сравнивается с проверенной: если клиент успел её обновить, запрос не нужен.
+func (c *Client) requestRefresh(checked ClientSession, deadline time.Time) bool {
+	c.auth.mu.Lock()
+	defer c.auth.mu.Unlock()
+	if c.auth.session != checked || !c.auth.refreshDeadline.IsZero() {
+		return false
+	}
+	c.auth.refreshDeadline = deadline
+	return true
+}
+
+// AuthRefresher следит за авторизацией подключённых клиентов. Если токены пользователя
+// отозваны или ключ подписи тикета выведен из оборота, клиент получает token_expiring и
+// Grace на то, чтобы прислать новый тикет в auth:refresh; соединение и подписка на викторину
+// при этом сохраняются. Не обновившего авторизацию клиента отключает TOKEN_EXPIRED.
+type AuthRefresher struct {
+	manager *Manager
+	auth    SessionAuthenticator
+	grace   time.Duration // сколько ждать нового тикета, прежде чем закрыть соединение
+	now     func() time.Time
+}
+
+// NewAuthRefresher создает проверку авторизации соединений и регистрирует обработчик auth:refresh
+func NewAuthRefresher(manager *Manager, auth SessionAuthenticator, grace time.Duration) *AuthRefresher {
+	r := &AuthRefresher{
+		manager: manager,
+		auth:    auth,
+		grace:   grace,
+		now:     time.Now,
+	}
+	manager.RegisterHandler(AUTH_REFRESH, r.handleRefresh)
+	return r
+}
+
+// Start запускает периодическую проверку авторизации подключённых клиентов
+func (r *AuthRefresher) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.Check(ctx)
+			}
+		}
+	}()
+}
+
+// Check отключает клиентов, не обновивших авторизацию в срок, и запрашивает обновление
+// у клиентов с отозванной авторизацией
+func (r *AuthRefresher) Check(ctx context.Context) {
+	now := r.now()
+	var clients []*Client
+	var sessions []ClientSession
+	r.manager.ForEachClient(func(client *Client) {
+		session := client.Session()
+		if session.UserID == 0 {
+			return
+		}
+		if deadline := client.RefreshDeadline(); !deadline.IsZero() {
+			if now.After(deadline) {
+				r.expire(client)
+			}
+			return
+		}
+		clients = append(clients, client)
+		sessions = append(sessions, session)
+	})
+	if len(sessions) == 0 {
+		return
+	}
+
+	reasons, err := r.auth.RevokedSessions(ctx, sessions)
+	if err != nil {
+		// Не удалось проверить — клиентов не трогаем до следующей проверки
+		log.Printf("[AuthRefresher] Ошибка проверки авторизации соединений: %v", err)
+		return
+	}
+	deadline := now.Add(r.grace)
+	for i, reason := range reasons {
+		if reason == "" || !clients[i].requestRefresh(sessions[i], deadline) {
+			continue
+		}
+		log.Printf("[AuthRefresher] Авторизация соединения пользователя %s отозвана (%s), запрошено обновление", clients[i].UserID, reason)
+		sendToClient(clients[i], TOKEN_EXPIRING, map[string]interface{}{
+			"reason":     reason,
+			"refresh_by": deadline.UnixMilli(),
+			"grace_sec":  int(r.grace / time.Second),
+		})
+	}
+}
+
+// expire отключает клиента, не обновившего авторизацию в срок
+func (r *AuthRefresher) expire(client *Client) {
+	log.Printf("[AuthRefresher] Пользователь %s не обновил авторизацию соединения, соединение закрывается", client.UserID)
+	expireClient(client, "")
+}
+
+// DisconnectUser закрывает соединения пользователя на этом инстансе, авторизованные тикетом,
+// выданным не позже invalidatedAt: клиент получает TOKEN_EXPIRED с reason "invalidated" и должен
+// войти заново. Возвращает число закрытых соединений.
+func (m *Manager) DisconnectUser(userID uint, invalidatedAt time.Time) int {
+	closed := 0
+	m.ForEachClient(func(client *Client) {
+		session := client.Session()
+		if session.UserID != userID || session.IssuedAt.After(invalidatedAt) || client.IsSendClosed() {
+			return
+		}
+		expireClient(client, SessionRevokedInvalidated)
+		closed++
+	})
+	if closed > 0 {
+		log.Printf("[WebSocketManager] Токены пользователя %d отозваны, закрыто соединений: %d", userID, closed)
+	}
+	return closed
+}
+
+// expireClient отправляет клиенту TOKEN_EXPIRED и закрывает соединение
+func expireClient(client *Client, reason string) {
+	data := map[string]interface{}{
+		"message": "Срок действия токена истек. Необходимо выполнить повторный вход.",
+	}
+	if reason != "" {
+		data["reason"] = reason
+	}
+	sendToClient(client, TOKEN_EXPIRED, data)
+	client.CloseSend()
+}
+
+// handleRefresh обрабатывает auth:refresh: {"ticket": "..."}. Отклонённый тикет не закрывает
+// соединение: клиент может повторить попытку до срока из token_expiring.
+func (r *AuthRefresher) handleRefresh(data json.RawMessage, client *Client) error {
+	var req struct {
+		Ticket string `json:"ticket"`
+	}
+	if err := json.Unmarshal(data, &req); err != nil || req.Ticket == "" {
+		r.manager.SendErrorToClient(client, "invalid_format", "auth:refresh requires a ticket")
+		return nil
+	}
+
+	ctx := context.Background()
+	session, err := r.auth.ParseSessionTicket(ctx, req.Ticket)
+	if err != nil {
+		log.Printf("[AuthRefresher] Пользователь %s прислал недействительный тикет: %v", client.UserID, err)
+		r.manager.SendErrorToClient(client, "auth_refresh_failed", "Invalid or expired ticket")
+		return nil
+	}
+	if session.UserID != client.GetUserIDUint() {
+		log.Printf("[AuthRefresher] Пользователь %s прислал тикет пользователя %d", client.UserID, session.UserID)
+		r.manager.SendErrorToClient(client, "auth_refresh_failed", "Ticket belongs to another user")
+		return nil
+	}
+	reasons, err := r.auth.RevokedSessions(ctx, []ClientSession{session})
+	if err != nil || reasons[0] != "" {
+		r.manager.SendErrorToClient(client, "auth_refresh_failed", "Ticket has been revoked")
+		return nil
+	}
+
+	client.SetSession(session)
+	log.Printf("[AuthRefresher] Пользователь %s обновил авторизацию соединения", client.UserID)
+	sendToClient(client, AUTH_REFRESHED, map[string]interface{}{
+		"user_id":          session.UserID,
+		"server_timestamp": r.now().UnixMilli(),
+	})
+	return nil
+}
+
+// sendToClient ставит событие в очередь именно этого соединения
+func sendToClient(client *Client, eventType string, data interface{}) {
+	message, err := json.Marshal(Event{Type: eventType, Data: data})
+	if err != nil {
+		log.Printf("[WebSocketManager] Ошибка сериализации %s: %v", eventType, err)
+		return
+	}
+	client.enqueue(newOutboundMessage(message))
+}
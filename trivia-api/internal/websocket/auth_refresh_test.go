@@ -0,0 +1,164 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAuthenticator отзывает авторизации по пользователю и принимает тикеты из tickets
+type fakeAuthenticator struct {
+	revoked map[uint]string
+	tickets map[string]ClientSession
+	err     error
+}
+
+func (a *fakeAuthenticator) ParseSessionTicket(_ context.Context, ticket string) (ClientSession, error) {
+	session, ok := a.tickets[ticket]
+	if !ok {
+		return ClientSession{}, errors.New("invalid ticket")
+	}
+	return session, nil
+}
+
+func (a *fakeAuthenticator) RevokedSessions(_ context.Context, sessions []ClientSession) ([]string, error) {
+	if a.err != nil {
+		return nil, a.err
+	}
+	reasons := make([]string, len(sessions))
+	for i, session := range sessions {
+		if session.IssuedAt.Before(time.Unix(1000, 0)) {
+			reasons[i] = a.revoked[session.UserID]
+		}
+	}
+	return reasons, nil
+}
+
+func newAuthRefreshHub(clients ...*Client) *ShardedHub {
+	h := &ShardedHub{shardCount: 1, shards: []*Shard{NewShard(0, nil, 10, 0, 0, nil)}}
+	for _, client := range clients {
+		h.shards[0].clients.Store(client, true)
+	}
+	return h
+}
+
+func queuedTypes(t *testing.T, client *Client) []string {
+	t.Helper()
+	var types []string
+	for {
+		message, ok := client.send.pop()
+		if !ok {
+			return types
+		}
+		types = append(types, message.Type())
+	}
+}
+
+func TestAuthRefresher_RequestsRefreshAndExpiresAfterGrace(t *testing.T) {
+	oldTicket := time.Unix(900, 0)
+	revoked := NewClient(nil, nil, "1")
+	revoked.SetSession(ClientSession{UserID: 1, KeyID: "k1", IssuedAt: oldTicket})
+	valid := NewClient(nil, nil, "2")
+	valid.SetSession(ClientSession{UserID: 2, KeyID: "k1", IssuedAt: oldTicket})
+	anonymous := NewClient(nil, nil, "3")
+
+	auth := &fakeAuthenticator{revoked: map[uint]string{1: SessionRevokedKeyRotated, 3: SessionRevokedInvalidated}}
+	r := NewAuthRefresher(NewManager(newAuthRefreshHub(revoked, valid, anonymous)), auth, time.Minute)
+	now := time.Unix(2000, 0)
+	r.now = func() time.Time { return now }
+
+	r.Check(context.Background())
+	message, ok := revoked.send.pop()
+	require.True(t, ok)
+	assert.JSONEq(t, `{"type":"token_expiring","data":{"reason":"key_rotated","refresh_by":2060000,"grace_sec":60}}`, string(message.json))
+	assert.Equal(t, now.Add(time.Minute), revoked.RefreshDeadline())
+	assert.Empty(t, queuedTypes(t, valid))
+	assert.Empty(t, queuedTypes(t, anonymous))
+
+	// Повторная проверка до срока не шлёт token_expiring ещё раз и не сдвигает срок
+	now = now.Add(30 * time.Second)
+	r.Check(context.Background())
+	assert.Empty(t, queuedTypes(t, revoked))
+	assert.Equal(t, time.Unix(2060, 0), revoked.RefreshDeadline())
+
+	// После срока соединение закрывается с TOKEN_EXPIRED
+	now = now.Add(31 * time.Second)
+	r.Check(context.Background())
+	assert.Equal(t, []string{TOKEN_EXPIRED}, queuedTypes(t, revoked))
+	assert.True(t, revoked.IsSendClosed())
+	assert.False(t, valid.IsSendClosed())
+}
+
+func TestAuthRefresher_CheckErrorKeepsClients(t *testing.T) {
+	client := NewClient(nil, nil, "1")
+	client.SetSession(ClientSession{UserID: 1, IssuedAt: time.Unix(900, 0)})
+	auth := &fakeAuthenticator{revoked: map[uint]string{1: SessionRevokedInvalidated}, err: errors.New("db down")}
+	r := NewAuthRefresher(NewManager(newAuthRefreshHub(client)), auth, time.Minute)
+
+	r.Check(context.Background())
+	assert.Empty(t, queuedTypes(t, client))
+	assert.True(t, client.RefreshDeadline().IsZero())
+}
+
+func TestAuthRefresher_HandleRefresh(t *testing.T) {
+	client := NewClient(nil, nil, "1")
+	client.SetSession(ClientSession{UserID: 1, KeyID: "k1", IssuedAt: time.Unix(900, 0)})
+	fresh := ClientSession{UserID: 1, KeyID: "k2", IssuedAt: time.Unix(2010, 0)}
+	auth := &fakeAuthenticator{
+		revoked: map[uint]string{1: SessionRevokedInvalidated},
+		tickets: map[string]ClientSession{
+			"fresh":   fresh,
+			"other":   {UserID: 2, KeyID: "k2", IssuedAt: time.Unix(2010, 0)},
+			"revoked": {UserID: 1, KeyID: "k2", IssuedAt: time.Unix(950, 0)},
+		},
+	}
+	r := NewAuthRefresher(NewManager(newAuthRefreshHub(client)), auth, time.Minute)
+	r.now = func() time.Time { return time.Unix(2000, 0) }
+	r.Check(context.Background())
+	assert.Equal(t, []string{TOKEN_EXPIRING}, queuedTypes(t, client))
+
+	// Чужой, отозванный или неизвестный тикет не заменяет авторизацию и не закрывает соединение
+	for _, ticket := range []string{"other", "revoked", "unknown", ""} {
+		data, _ := json.Marshal(map[string]string{"ticket": ticket})
+		require.NoError(t, r.handleRefresh(data, client))
+		assert.Equal(t, time.Unix(900, 0), client.Session().IssuedAt, ticket)
+		assert.False(t, client.RefreshDeadline().IsZero(), ticket)
+	}
+	assert.False(t, client.IsSendClosed())
+
+	require.NoError(t, r.handleRefresh(json.RawMessage(`{"ticket":"fresh"}`), client))
+	assert.Equal(t, fresh, client.Session())
+	assert.True(t, client.RefreshDeadline().IsZero())
+	assert.Contains(t, queuedTypes(t, client), AUTH_REFRESHED)
+
+	// С новой авторизацией проверка больше не запрашивает обновление
+	r.Check(context.Background())
+	assert.NotContains(t, queuedTypes(t, client), TOKEN_EXPIRING)
+}
+
+func TestManager_DisconnectUser(t *testing.T) {
+	invalidatedAt := time.Unix(1000, 0)
+	revoked := NewClient(nil, nil, "1")
+	revoked.SetSession(ClientSession{UserID: 1, IssuedAt: time.Unix(900, 0)})
+	reconnected := NewClient(nil, nil, "1")
+	reconnected.SetSession(ClientSession{UserID: 1, IssuedAt: time.Unix(1001, 0)})
+	other := NewClient(nil, nil, "2")
+	other.SetSession(ClientSession{UserID: 2, IssuedAt: time.Unix(900, 0)})
+	m := NewManager(newAuthRefreshHub(revoked, reconnected, other))
+
+	assert.Equal(t, 1, m.DisconnectUser(1, invalidatedAt))
+	message, ok := revoked.send.pop()
+	require.True(t, ok)
+	assert.JSONEq(t, `{"type":"TOKEN_EXPIRED","data":{"message":"Срок действия токена истек. Необходимо выполнить повторный вход.","reason":"invalidated"}}`, string(message.json))
+	assert.True(t, revoked.IsSendClosed())
+	// Соединение с тикетом, выданным после отзыва, и соединения других пользователей не закрываются
+	assert.False(t, reconnected.IsSendClosed())
+	assert.False(t, other.IsSendClosed())
+
+	assert.Equal(t, 0, m.DisconnectUser(1, invalidatedAt), "закрытое соединение не считается повторно")
+}
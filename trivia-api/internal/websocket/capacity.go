@@ -0,0 +1,85 @@
+package websocket
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sync"
+)
+
+// capacityHeadroom — запас к ожидаемой аудитории: приходят и те, кто не записывался заранее
+const capacityHeadroom = 1.25
+
+// ReserveQuizCapacity готовит шарды к викторине с ожидаемым числом клиентов expectedClients
+// (например, по RSVP): поднимает рекомендуемую ёмкость шардов до ожидаемой нагрузки с запасом
+// и заранее создаёт индексы подписчиков викторины. Если ожидаемая нагрузка превышает
+// настроенную ёмкость шарда, отправляет алерт AlertCapacityForecast — узлы стоит добавить до старта.
+func (h *ShardedHub) ReserveQuizCapacity(quizID uint, expectedClients int) {
+	if expectedClients < 0 {
+		expectedClients = 0
+	}
+
+	h.reservationsMu.Lock()
+	if h.reservations == nil {
+		h.reservations = make(map[uint]int)
+	}
+	h.reservations[quizID] = expectedClients
+	perShard := h.applyReservationsLocked()
+	h.reservationsMu.Unlock()
+
+	h.shardsMu.RLock()
+	for _, shard := range h.shards {
+		shard.quizSubscriptions.LoadOrStore(quizID, &sync.Map{})
+	}
+	h.shardsMu.RUnlock()
+
+	log.Printf("[ShardedHub] Викторина #%d: ожидается %d клиентов, ожидаемая нагрузка на шард %d", quizID, expectedClients, perShard)
+	if perShard > h.maxClientsPerShard {
+		h.SendAlert(AlertCapacityForecast, AlertWarning,
+			fmt.Sprintf("Ожидаемая аудитория викторины #%d превышает ёмкость шардов: %d клиентов на шард при лимите %d",
+				quizID, perShard, h.maxClientsPerShard),
+			map[string]interface{}{
+				"quiz_id":               quizID,
+				"expected_clients":      expectedClients,
+				"expected_per_shard":    perShard,
+				"max_clients_per_shard": h.maxClientsPerShard,
+			})
+	}
+}
+
+// ReleaseQuizCapacity снимает резерв викторины; ёмкость шардов возвращается к настроенной
+// (или к резервам других викторин)
+func (h *ShardedHub) ReleaseQuizCapacity(quizID uint) {
+	h.reservationsMu.Lock()
+	defer h.reservationsMu.Unlock()
+	if _, ok := h.reservations[quizID]; !ok {
+		return
+	}
+	delete(h.reservations, quizID)
+	h.applyReservationsLocked()
+}
+
+// applyReservationsLocked выставляет шардам ёмкость под сумму резервов и возвращает ожидаемую
+// нагрузку на шард. Вызывается под reservationsMu.
+func (h *ShardedHub) applyReservationsLocked() int {
+	total := 0
+	for _, expected := range h.reservations {
+		total += expected
+	}
+
+	h.shardsMu.RLock()
+	defer h.shardsMu.RUnlock()
+	if len(h.shards) == 0 {
+		return 0
+	}
+	// Клиенты распределяются по шардам хешем UserID, поэтому нагрузка близка к равномерной
+	perShard := int(math.Ceil(float64(total) * capacityHeadroom / float64(len(h.shards))))
+	capacity := h.maxClientsPerShard
+	if perShard > capacity {
+		capacity = perShard
+	}
+	for _, shard := range h.shards {
+		shard.maxClients.Store(int64(capacity))
+	}
+	return perShard
+}
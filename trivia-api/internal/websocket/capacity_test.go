@@ -0,0 +1,45 @@
+package websocket
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCapacityHub() *ShardedHub {
+	return &ShardedHub{
+		shardCount:         2,
+		maxClientsPerShard: 100,
+		shards:             []*Shard{NewShard(0, nil, 100, 0, 0, nil), NewShard(1, nil, 100, 0, 0, nil)},
+		alertChan:          make(chan AlertMessage, 4),
+	}
+}
+
+func TestShardedHub_ReserveQuizCapacity(t *testing.T) {
+	hub := newCapacityHub()
+
+	// 400 ожидаемых клиентов с запасом 25% — 250 на шард, больше настроенных 100
+	hub.ReserveQuizCapacity(7, 400)
+	for _, shard := range hub.shards {
+		assert.Equal(t, int64(250), shard.maxClients.Load())
+		_, ok := shard.quizSubscriptions.Load(uint(7))
+		assert.True(t, ok, "индекс подписчиков создан заранее")
+	}
+	require.Len(t, hub.alertChan, 1)
+	alert := <-hub.alertChan
+	assert.Equal(t, AlertCapacityForecast, alert.Type)
+	assert.Equal(t, 250, alert.Metadata["expected_per_shard"])
+
+	// Резервы суммируются, снятие одного возвращает ёмкость под оставшийся
+	hub.ReserveQuizCapacity(8, 80)
+	assert.Equal(t, int64(300), hub.shards[0].maxClients.Load())
+	assert.Len(t, hub.alertChan, 1, "суммарный резерв всё ещё превышает ёмкость")
+	<-hub.alertChan
+	hub.ReleaseQuizCapacity(7)
+	assert.Equal(t, int64(100), hub.shards[1].maxClients.Load(), "не ниже настроенной ёмкости")
+
+	// Небольшая аудитория не вызывает алерт
+	hub.ReserveQuizCapacity(9, 10)
+	assert.Len(t, hub.alertChan, 0)
+}
@@ -2,7 +2,6 @@ package websocket
 
 import (
 	"bytes"
-	"encoding/json"
 	"fmt"
 	"log"
 	"runtime/debug"
@@ -28,26 +27,34 @@ const (
 	// Максимальный размер сообщения
 	maxMessageSize = 512
 
-	// Размер буфера по умолчанию для каналов отправки сообщений клиенту
+	// Размер очереди отправки сообщений клиенту по умолчанию
 	// Увеличено с 64 до 128 для большей устойчивости к пикам
 	defaultClientBufferSize = 128
 
-	// Максимальное количество предупреждений о переполнении буфера до отключения
-	maxBufferWarnings = 3
+	// Время, которое writePump пишет накопившиеся сообщения подряд, прежде чем вернуться к пингам
+	defaultWriteBudget = 50 * time.Millisecond
+
+	// Максимальное количество сообщений, забираемых из очереди за один проход writePump
+	defaultMaxBatch = 64
 )
 
 var (
 	newline = []byte{'\n'}
 	space   = []byte{' '}
 
-	// debugLogging включает подробное логирование для отладки
-	// В production должно быть false
-	debugLogging = false
+	// debugLogging включает подробное логирование по каждому клиенту и сообщению.
+	// В production должно быть выключено: при рассылке на десятки тысяч клиентов логи становятся узким местом.
+	debugLogging atomic.Bool
 )
 
+// SetDebugLogging включает или выключает подробное логирование рассылки
+func SetDebugLogging(enabled bool) {
+	debugLogging.Store(enabled)
+}
+
 // ClientConfig содержит настройки для клиента
 type ClientConfig struct {
-	// BufferSize определяет размер буфера канала отправки сообщений
+	// BufferSize определяет ёмкость очереди отправки сообщений
 	BufferSize int
 
 	// PingInterval определяет интервал между ping-сообщениями
@@ -61,6 +68,12 @@ type ClientConfig struct {
 
 	// MaxMessageSize определяет максимальный размер сообщения
 	MaxMessageSize int64
+
+	// WriteBudget ограничивает время записи пакета накопившихся сообщений
+	WriteBudget time.Duration
+
+	// MaxBatch ограничивает количество сообщений в пакете записи
+	MaxBatch int
 }
 
 // DefaultClientConfig возвращает конфигурацию клиента по умолчанию
@@ -71,6 +84,8 @@ func DefaultClientConfig() ClientConfig {
 		PongWait:       pongWait,
 		WriteWait:      writeWait,
 		MaxMessageSize: maxMessageSize,
+		WriteBudget:    defaultWriteBudget,
+		MaxBatch:       defaultMaxBatch,
 	}
 }
 
@@ -88,14 +103,14 @@ type Client struct {
 	// WebSocket соединение
 	conn *websocket.Conn
 
-	// Буферизованный канал для исходящих сообщений
-	send chan []byte
+	// Очередь исходящих сообщений
+	send *sendQueue
 
 	// Конфигурация клиента (лимиты, таймауты)
 	config ClientConfig
 
-	// Флаг, указывающий что канал send закрыт (для предотвращения panic)
-	sendClosed atomic.Bool
+	// Время регистрации в шарде; пишется один раз при регистрации, дальше только читается
+	connectedAt time.Time
 
 	// Время последней активности клиента (защищено мьютексом)
 	lastActivity time.Time
@@ -110,6 +125,9 @@ type Client struct {
 	// Мьютекс для синхронизации доступа к подпискам
 	subMutex sync.RWMutex
 
+	// IP-адрес клиента при подключении; задаётся до запуска обработки сообщений
+	remoteIP string
+
 	// Роли клиента (например, "admin", "player", "spectator")
 	roles map[string]bool
 
@@ -117,9 +135,23 @@ type Client struct {
 	// Используем атомарный тип для потокобезопасности
 	currentQuizID atomic.Uint32
 
-	// Счетчик предупреждений о переполнении буфера
-	bufferWarningCount int32
-	bufferWarningMutex sync.Mutex // Мьютекс для защиты счетчика
+	// Номер последнего события журнала викторины, поставленного в очередь
+	seqMu   sync.Mutex
+	quizSeq int64
+
+	// Оценка RTT соединения по ping/pong
+	rtt rttEstimate
+
+	// Авторизация соединения и запрос на её обновление
+	auth authState
+
+	// Согласованная версия протокола, возможности и сериализатор исходящих сообщений
+	// (nil — сообщения отправляются в текущем формате)
+	protocolMu      sync.RWMutex
+	protocolVersion int
+	capabilities    []string
+	binary          bool // сообщения передаются в MessagePack
+	encoder         func(*outboundMessage) (*outboundMessage, bool)
 }
 
 // UpdateLastActivity обновляет время последней активности (thread-safe)
@@ -162,23 +194,45 @@ func NewClientWithConfig(hub interface{}, conn *websocket.Conn, userID string, c
 	if config.MaxMessageSize <= 0 {
 		config.MaxMessageSize = defaultConfig.MaxMessageSize
 	}
+	if config.WriteBudget <= 0 {
+		config.WriteBudget = defaultConfig.WriteBudget
+	}
+	if config.MaxBatch <= 0 {
+		config.MaxBatch = defaultConfig.MaxBatch
+	}
 
 	return &Client{
 		hub:                  hub,
 		conn:                 conn,
-		send:                 make(chan []byte, config.BufferSize),
+		send:                 newSendQueue(config.BufferSize),
 		config:               config, // Сохраняем конфигурацию для использования в pumps
 		UserID:               userID,
 		ConnectionID:         connectionID,
 		lastActivity:         time.Now(),
 		registrationComplete: make(chan struct{}, 1),
 		roles:                make(map[string]bool),
+		protocolVersion:      ProtocolV1,
 	}
 }
 
+// SetRemoteIP запоминает IP-адрес клиента (с учётом доверенных прокси); вызывается до StartPumps
+func (c *Client) SetRemoteIP(ip string) {
+	c.remoteIP = ip
+}
+
+// RemoteIP возвращает IP-адрес клиента при подключении
+func (c *Client) RemoteIP() string {
+	return c.remoteIP
+}
+
 // SetQuizID устанавливает ID текущей викторины для клиента
 func (c *Client) SetQuizID(quizID uint) {
-	c.currentQuizID.Store(uint32(quizID))
+	if previous := c.currentQuizID.Swap(uint32(quizID)); previous != uint32(quizID) {
+		// Номера событий у каждой викторины свои
+		c.seqMu.Lock()
+		c.quizSeq = 0
+		c.seqMu.Unlock()
+	}
 	log.Printf("Client %s (Conn: %s) set QuizID to %d", c.UserID, c.ConnectionID, quizID)
 }
 
@@ -214,16 +268,17 @@ func (c *Client) readPump(messageHandler func(message []byte, client *Client) er
 	// Настройка чтения сообщений - используем значения из конфигурации клиента
 	c.conn.SetReadLimit(c.config.MaxMessageSize)
 	c.conn.SetReadDeadline(time.Now().Add(c.config.PongWait))
-	c.conn.SetPongHandler(func(string) error {
+	c.conn.SetPongHandler(func(appData string) error {
 		c.conn.SetReadDeadline(time.Now().Add(c.config.PongWait))
 		c.UpdateLastActivity() // FIX: thread-safe обновление времени активности
+		c.handlePongRTT(appData, time.Now())
 		return nil
 	})
 
 	log.Printf("WebSocket Client Read Pump STARTED for UserID: %s, ConnID: %s", c.UserID, c.ConnectionID)
 
 	for {
-		_, message, err := c.conn.ReadMessage()
+		frameType, message, err := c.conn.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure, websocket.CloseNormalClosure) {
 				log.Printf("WebSocket Client Read Error (UserID: %s, ConnID: %s): %v", c.UserID, c.ConnectionID, err)
@@ -240,15 +295,20 @@ func (c *Client) readPump(messageHandler func(message []byte, client *Client) er
 		// Обновляем время активности при получении сообщения
 		c.UpdateLastActivity() // FIX: thread-safe обновление
 
+		// Бинарные фреймы — MessagePack; обработчики работают с JSON
+		if frameType == websocket.BinaryMessage {
+			if message, err = messagePackToJSON(message); err != nil {
+				log.Printf("WebSocket Client Invalid MessagePack (UserID: %s, ConnID: %s): %v", c.UserID, c.ConnectionID, err)
+				continue
+			}
+		}
+
 		// Безопасный вызов обработчика с recover
 		if handlerErr := safeHandleMessage(message, c, messageHandler); handlerErr != nil {
 			// Если обработчик вернул ошибку, считаем ее фатальной для соединения
 			log.Printf("WebSocket Client Handler Error (UserID: %s, ConnID: %s): %v. Closing connection.", c.UserID, c.ConnectionID, handlerErr)
 			break // Закрываем соединение
 		}
-
-		// Сбрасываем счетчик предупреждений при получении любого сообщения от клиента
-		c.resetBufferWarningCount()
 	}
 }
 
@@ -275,7 +335,7 @@ func safeHandleMessage(message []byte, client *Client, messageHandler func(messa
 	return err // Возвращаем ошибку (или nil)
 }
 
-// writePump отправляет сообщения клиенту из канала send
+// writePump отправляет сообщения клиенту из очереди send
 func (c *Client) writePump() {
 	ticker := time.NewTicker(c.config.PingInterval)
 	defer func() {
@@ -289,46 +349,40 @@ func (c *Client) writePump() {
 
 	for {
 		select {
-		case message, ok := <-c.send:
-			// Debug логирование (отключено в production для производительности)
-			if debugLogging {
-				log.Printf("[Client %s][Conn %s] Dequeued message. Type: %s. Buffer len: %d", c.UserID, c.ConnectionID, messageTypeFromBytes(message), len(c.send))
-			}
-
-			// Устанавливаем таймаут для записи - используем значения из конфигурации
+		case <-c.send.ready:
+			// Устанавливаем таймаут для записи - один на весь пакет сообщений
 			if err := c.conn.SetWriteDeadline(time.Now().Add(c.config.WriteWait)); err != nil {
 				log.Printf("WebSocket Client SetWriteDeadline Error (UserID: %s, ConnID: %s): %v", c.UserID, c.ConnectionID, err)
 				return // Завершаем горутину записи
 			}
 
-			if !ok {
-				// Канал send закрыт (хаб или шард закрыли канал клиента)
-				log.Printf("WebSocket Client Send Channel Closed (UserID: %s, ConnID: %s)", c.UserID, c.ConnectionID)
-				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
-				return // Завершаем горутину записи
+			// Сообщаем клиенту о сообщениях, вытесненных из переполненной очереди
+			if dropped := c.send.takeDropped(); dropped > 0 && !c.writeMessage(bufferWarning(dropped)) {
+				return
 			}
 
-			// Получаем writer для отправки сообщения
-			w, err := c.conn.NextWriter(websocket.TextMessage)
-			if err != nil {
-				log.Printf("WebSocket Client NextWriter Error (UserID: %s, ConnID: %s): %v", c.UserID, c.ConnectionID, err)
-				return // Завершаем горутину записи
+			// Пишем накопившиеся сообщения, пока не исчерпан бюджет времени или размер пакета.
+			// После этого возвращаемся в select, чтобы не задерживать пинги.
+			budgetEnd := time.Now().Add(c.config.WriteBudget)
+			for written := 0; written < c.config.MaxBatch && time.Now().Before(budgetEnd); written++ {
+				message, ok := c.send.pop()
+				if !ok {
+					break
+				}
+				if !c.writeMessage(message) {
+					return // Завершаем горутину записи
+				}
 			}
 
-			// Пишем сообщение
-			if _, err := w.Write(message); err != nil {
-				log.Printf("WebSocket Client Write Error (UserID: %s, ConnID: %s): %v", c.UserID, c.ConnectionID, err)
-			}
-
-			// Закрываем writer, чтобы отправить сообщение
-			if err := w.Close(); err != nil {
-				log.Printf("WebSocket Client Writer Close Error (UserID: %s, ConnID: %s): %v", c.UserID, c.ConnectionID, err)
+			if c.send.drained() {
+				// Очередь закрыта хабом или шардом и все сообщения отправлены
+				log.Printf("WebSocket Client Send Queue Closed (UserID: %s, ConnID: %s)", c.UserID, c.ConnectionID)
+				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
 				return // Завершаем горутину записи
 			}
-
-			// Debug лог после успешной записи
-			if debugLogging {
-				log.Printf("[Client %s][Conn %s] Wrote message. Type: %s", c.UserID, c.ConnectionID, messageTypeFromBytes(message))
+			if c.send.Len() > 0 {
+				// Пакет ограничен бюджетом — продолжим после проверки тикера
+				c.send.signal()
 			}
 
 		case <-ticker.C:
@@ -337,7 +391,8 @@ func (c *Client) writePump() {
 				log.Printf("WebSocket Client SetWriteDeadline (Ping) Error (UserID: %s, ConnID: %s): %v", c.UserID, c.ConnectionID, err)
 				return // Завершаем горутину записи
 			}
-			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+			// В ping передаём время отправки — по pong измеряется RTT
+			if err := c.conn.WriteMessage(websocket.PingMessage, pingPayload(time.Now())); err != nil {
 				log.Printf("WebSocket Client Ping Error (UserID: %s, ConnID: %s): %v", c.UserID, c.ConnectionID, err)
 				return // Завершаем горутину записи при ошибке пинга
 			}
@@ -347,6 +402,33 @@ func (c *Client) writePump() {
 	}
 }
 
+// writeMessage приводит сообщение к протоколу клиента и пишет подготовленный фрейм.
+// Возвращает false, если соединение нужно закрыть.
+func (c *Client) writeMessage(message *outboundMessage) bool {
+	if debugLogging.Load() {
+		log.Printf("[Client %s][Conn %s] Dequeued message. Type: %s. Buffer len: %d", c.UserID, c.ConnectionID, message.Type(), c.send.Len())
+	}
+
+	frame, binary, deliver := c.encodeOutgoing(message)
+	if !deliver {
+		return true
+	}
+	prepared, err := frame.prepared(binary)
+	if err != nil {
+		log.Printf("WebSocket Client Prepare Error (UserID: %s, ConnID: %s): %v", c.UserID, c.ConnectionID, err)
+		return true // Сообщение пропускаем, соединение исправно
+	}
+	if err := c.conn.WritePreparedMessage(prepared); err != nil {
+		log.Printf("WebSocket Client Write Error (UserID: %s, ConnID: %s): %v", c.UserID, c.ConnectionID, err)
+		return false
+	}
+
+	if debugLogging.Load() {
+		log.Printf("[Client %s][Conn %s] Wrote message. Type: %s", c.UserID, c.ConnectionID, message.Type())
+	}
+	return true
+}
+
 // StartPumps запускает горутины для чтения и записи сообщений
 func (c *Client) StartPumps(messageHandler func(message []byte, client *Client) error) {
 	if c.UserID == "" {
@@ -487,68 +569,98 @@ func (c *Client) RemoveRole(role string) {
 	log.Printf("WebSocket: у клиента %s удалена роль %s", c.UserID, role)
 }
 
-// --- Новые методы для управления счетчиком предупреждений ---
+// ProtocolVersion возвращает согласованную версию протокола
+func (c *Client) ProtocolVersion() int {
+	c.protocolMu.RLock()
+	defer c.protocolMu.RUnlock()
+	return c.protocolVersion
+}
 
-// incrementBufferWarningCount увеличивает счетчик предупреждений и возвращает новое значение
-func (c *Client) incrementBufferWarningCount() int32 {
-	c.bufferWarningMutex.Lock()
-	defer c.bufferWarningMutex.Unlock()
-	c.bufferWarningCount++
-	return c.bufferWarningCount
+// Capabilities возвращает возможности протокола, включённые для соединения
+func (c *Client) Capabilities() []string {
+	c.protocolMu.RLock()
+	defer c.protocolMu.RUnlock()
+	return append([]string(nil), c.capabilities...)
 }
 
-// resetBufferWarningCount сбрасывает счетчик предупреждений
-func (c *Client) resetBufferWarningCount() {
-	c.bufferWarningMutex.Lock()
-	defer c.bufferWarningMutex.Unlock()
-	if c.bufferWarningCount > 0 {
-		c.bufferWarningCount = 0
-		log.Printf("[Client %s][Conn %s] Buffer warning count reset.", c.UserID, c.ConnectionID)
+// HasCapability проверяет, включена ли для соединения возможность протокола
+func (c *Client) HasCapability(name string) bool {
+	c.protocolMu.RLock()
+	defer c.protocolMu.RUnlock()
+	for _, capability := range c.capabilities {
+		if capability == name {
+			return true
+		}
 	}
+	return false
 }
 
-// GetBufferWarningCount возвращает текущее значение счетчика предупреждений
-func (c *Client) getBufferWarningCount() int32 {
-	c.bufferWarningMutex.Lock()
-	defer c.bufferWarningMutex.Unlock()
-	return c.bufferWarningCount
+func (c *Client) setProtocol(version int, capabilities []string, encoder func(*outboundMessage) (*outboundMessage, bool)) {
+	c.protocolMu.Lock()
+	defer c.protocolMu.Unlock()
+	c.protocolVersion = version
+	c.capabilities = capabilities
+	c.binary = false
+	for _, capability := range capabilities {
+		if capability == CapabilityMessagePack {
+			c.binary = true
+		}
+	}
+	c.encoder = encoder
 }
 
-// CloseSend безопасно закрывает канал send (только один раз)
-// Использует atomic CompareAndSwap для предотвращения panic при повторном закрытии
-// Возвращает true, если канал был закрыт этим вызовом, false если уже был закрыт
-func (c *Client) CloseSend() bool {
-	if c.sendClosed.CompareAndSwap(false, true) {
-		close(c.send)
-		return true
+// encodeOutgoing применяет сериализатор версии протокола. Возвращает сообщение для записи
+// и признак бинарного формата; false — сообщение клиенту этой версии не отправляется.
+func (c *Client) encodeOutgoing(message *outboundMessage) (*outboundMessage, bool, bool) {
+	c.protocolMu.RLock()
+	encoder, binary := c.encoder, c.binary
+	c.protocolMu.RUnlock()
+
+	if encoder != nil {
+		var ok bool
+		if message, ok = encoder(message); !ok {
+			return nil, false, false
+		}
 	}
-	return false // Канал уже был закрыт ранее
+	return message, binary, true
 }
 
-// IsSendClosed проверяет, закрыт ли канал send
-func (c *Client) IsSendClosed() bool {
-	return c.sendClosed.Load()
+// CloseSend закрывает очередь отправки (только один раз). writePump отправит
+// уже поставленные сообщения и закроет соединение.
+// Возвращает true, если очередь была закрыта этим вызовом, false если уже была закрыта
+func (c *Client) CloseSend() bool {
+	return c.send.close()
 }
 
-// --- Конец новых методов ---
+// IsSendClosed проверяет, закрыта ли очередь отправки
+func (c *Client) IsSendClosed() bool {
+	return c.send.isClosed()
+}
 
-// --- Вспомогательные функции ---
+// enqueue ставит сообщение в очередь отправки клиента. События журнала викторины
+// ставятся строго по возрастанию номера, чтобы повтор после переподключения
+// не продублировал и не обогнал уже полученные события.
+func (c *Client) enqueue(message *outboundMessage) enqueueResult {
+	if message.seq == 0 {
+		return c.send.push(message)
+	}
 
-// messageTypeFromBytes пытается извлечь тип сообщения из JSON байтов
-func messageTypeFromBytes(message []byte) string {
-	// Добавляем импорт encoding/json локально, если его нет вверху файла
-	// import "encoding/json"
-	var event struct {
-		Type string `json:"type"`
+	c.seqMu.Lock()
+	defer c.seqMu.Unlock()
+	if message.seq <= c.quizSeq {
+		return enqueueSkipped
 	}
-	// Используем json.Unmarshal вместо json.NewDecoder().Decode(), чтобы не требовать io.Reader
-	if json.Unmarshal(message, &event) == nil && event.Type != "" {
-		return event.Type
+	result := c.send.push(message)
+	if result == enqueueOK || result == enqueueEvicted {
+		c.quizSeq = message.seq
 	}
-	// Возвращаем строку, указывающую на возможный бинарный формат или ошибку парсинга
-	return "unknown/binary"
+	return result
 }
 
+// --- Конец новых методов ---
+
+// --- Вспомогательные функции ---
+
 // GetUserIDUint преобразует строковый UserID в uint.
 // Возвращает 0 при ошибке преобразования.
 func (c *Client) GetUserIDUint() uint {
@@ -0,0 +1,201 @@
+// Package contract описывает протокол WebSocket в машиночитаемом виде: каталог catalog.json
+// перечисляет события сервера и сообщения клиента с версией протокола, в которой они появились,
+// и JSON Schema их поля data. По каталогу тесты проверяют всё, что отправляют хаб и менеджеры
+// викторины, а cmd/ws-contract генерирует TypeScript-типы для фронтенда (shared/src/ws-contract.gen.ts).
+//
+// Новое событие сначала описывается в каталоге: без этого контрактные тесты не пропустят его отправку.
+package contract
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+//go:embed catalog.json
+var catalogJSON []byte
+
+// Направления сообщений протокола
+const (
+	DirectionServer = "server" // сервер → клиент
+	DirectionClient = "client" // клиент → сервер
+)
+
+var (
+	// ErrUnknownEvent — тип сообщения отсутствует в каталоге
+	ErrUnknownEvent = errors.New("event is not in the contract catalog")
+	// ErrInvalidMessage — сообщение не соответствует контракту
+	ErrInvalidMessage = errors.New("message does not match the contract")
+)
+
+// Event — событие или сообщение протокола
+type Event struct {
+	Name        string            `json:"-"`
+	Direction   string            `json:"direction,omitempty"`
+	Since       int               `json:"since,omitempty"` // версия протокола, с которой клиент получает событие
+	Description string            `json:"description"`
+	Schema      json.RawMessage   `json:"schema"`
+	Examples    []json.RawMessage `json:"examples"`
+
+	compiled *gojsonschema.Schema
+}
+
+// Catalog — каталог протокола WebSocket.
+// Events — сообщения в конверте {type, data}; Notices — служебные сообщения без конверта,
+// которые различаются полем event (уведомления об отзыве сессий).
+type Catalog struct {
+	ProtocolVersion int                        `json:"protocol_version"`
+	Definitions     map[string]json.RawMessage `json:"definitions"`
+	Events          map[string]*Event          `json:"events"`
+	Notices         map[string]*Event          `json:"notices"`
+}
+
+// Load разбирает встроенный каталог и компилирует схемы
+func Load() (*Catalog, error) {
+	var c Catalog
+	if err := json.Unmarshal(catalogJSON, &c); err != nil {
+		return nil, fmt.Errorf("parse contract catalog: %w", err)
+	}
+	for name, event := range c.Events {
+		if event.Direction != DirectionServer && event.Direction != DirectionClient {
+			return nil, fmt.Errorf("contract event %s: unknown direction %q", name, event.Direction)
+		}
+		if event.Since < 1 || event.Since > c.ProtocolVersion {
+			return nil, fmt.Errorf("contract event %s: since %d is outside protocol versions 1-%d", name, event.Since, c.ProtocolVersion)
+		}
+	}
+	for _, events := range []map[string]*Event{c.Events, c.Notices} {
+		for name, event := range events {
+			event.Name = name
+			schema, err := c.compile(event.Schema)
+			if err != nil {
+				return nil, fmt.Errorf("contract event %s: %w", name, err)
+			}
+			event.compiled = schema
+		}
+	}
+	return &c, nil
+}
+
+// compile компилирует схему события вместе с общими определениями каталога
+func (c *Catalog) compile(raw json.RawMessage) (*gojsonschema.Schema, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("parse schema: %w", err)
+	}
+	definitions := make(map[string]interface{}, len(c.Definitions))
+	for name, def := range c.Definitions {
+		var v interface{}
+		if err := json.Unmarshal(def, &v); err != nil {
+			return nil, fmt.Errorf("parse definition %s: %w", name, err)
+		}
+		definitions[name] = v
+	}
+	doc["definitions"] = definitions
+	return gojsonschema.NewSchema(gojsonschema.NewGoLoader(doc))
+}
+
+// Event возвращает событие каталога по типу
+func (c *Catalog) Event(name string) (*Event, bool) {
+	event, ok := c.Events[name]
+	return event, ok
+}
+
+// Names возвращает отсортированные типы событий указанного направления
+func (c *Catalog) Names(direction string) []string {
+	names := make([]string, 0, len(c.Events))
+	for name, event := range c.Events {
+		if event.Direction == direction {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate проверяет сообщение сервера клиенту: конверт {type, data[, seq]} и data по схеме события
+// либо служебное сообщение без конверта по схеме из Notices
+func (c *Catalog) Validate(message []byte) error {
+	return c.validate(DirectionServer, message)
+}
+
+// ValidateClient проверяет сообщение клиента серверу
+func (c *Catalog) ValidateClient(message []byte) error {
+	return c.validate(DirectionClient, message)
+}
+
+func (c *Catalog) validate(direction string, message []byte) error {
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(message, &envelope); err != nil {
+		return fmt.Errorf("%w: not a JSON object: %v", ErrInvalidMessage, err)
+	}
+
+	rawType, ok := envelope["type"]
+	if !ok {
+		var notice string
+		if direction == DirectionServer && json.Unmarshal(envelope["event"], &notice) == nil && notice != "" {
+			event, ok := c.Notices[notice]
+			if !ok {
+				return fmt.Errorf("%w: notice %q", ErrUnknownEvent, notice)
+			}
+			return event.validate(message)
+		}
+		return fmt.Errorf("%w: missing type", ErrInvalidMessage)
+	}
+	var eventType string
+	if err := json.Unmarshal(rawType, &eventType); err != nil || eventType == "" {
+		return fmt.Errorf("%w: type must be a non-empty string", ErrInvalidMessage)
+	}
+	for key := range envelope {
+		switch key {
+		case "type", "data":
+		case "seq":
+			// Номер события в журнале викторины (см. websocket/replay.go) есть только у сообщений сервера
+			var seq int64
+			if direction != DirectionServer || json.Unmarshal(envelope[key], &seq) != nil || seq < 1 {
+				return fmt.Errorf("%w: %s: invalid seq", ErrInvalidMessage, eventType)
+			}
+		default:
+			return fmt.Errorf("%w: %s: unexpected envelope field %q", ErrInvalidMessage, eventType, key)
+		}
+	}
+
+	event, ok := c.Events[eventType]
+	if !ok || event.Direction != direction {
+		return fmt.Errorf("%w: %s message %q", ErrUnknownEvent, direction, eventType)
+	}
+	data, ok := envelope["data"]
+	if !ok {
+		return fmt.Errorf("%w: %s: missing data", ErrInvalidMessage, eventType)
+	}
+	return event.validate(data)
+}
+
+// ValidateData проверяет данные события по его схеме
+func (e *Event) ValidateData(data interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidMessage, e.Name, err)
+	}
+	return e.validate(raw)
+}
+
+func (e *Event) validate(raw []byte) error {
+	result, err := e.compiled.Validate(gojsonschema.NewBytesLoader(raw))
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidMessage, e.Name, err)
+	}
+	if result.Valid() {
+		return nil
+	}
+	problems := make([]string, 0, len(result.Errors()))
+	for _, desc := range result.Errors() {
+		problems = append(problems, desc.String())
+	}
+	return fmt.Errorf("%w: %s: %s", ErrInvalidMessage, e.Name, strings.Join(problems, "; "))
+}
@@ -0,0 +1,95 @@
+package contract
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadCatalog(t *testing.T) *Catalog {
+	t.Helper()
+	catalog, err := Load()
+	require.NoError(t, err)
+	return catalog
+}
+
+func TestCatalog_ExamplesMatchSchemas(t *testing.T) {
+	catalog := loadCatalog(t)
+	require.NotEmpty(t, catalog.Names(DirectionServer))
+	require.NotEmpty(t, catalog.Names(DirectionClient))
+
+	for _, events := range []map[string]*Event{catalog.Events, catalog.Notices} {
+		for name, event := range events {
+			assert.NotEmpty(t, event.Description, name)
+			if assert.NotEmpty(t, event.Examples, "%s has no examples", name) {
+				for i, example := range event.Examples {
+					assert.NoError(t, event.validate(example), "%s example %d", name, i)
+				}
+			}
+		}
+	}
+}
+
+func TestCatalog_Validate(t *testing.T) {
+	catalog := loadCatalog(t)
+
+	valid := []string{
+		`{"type":"quiz:timer","data":{"question_id":7,"remaining_seconds":5,"paused":false,"server_timestamp":1700000000000}}`,
+		`{"type":"quiz:countdown","data":{"quiz_id":1,"seconds_left":3},"seq":12}`,
+		`{"event":"logout_all_devices","user_id":7,"timestamp":"2026-10-16T19:00:00Z","reason":"user_logout_all"}`,
+	}
+	for _, message := range valid {
+		assert.NoError(t, catalog.Validate([]byte(message)), message)
+	}
+
+	invalid := map[string]error{
+		`{"type":"quiz:unknown","data":{}}`:                                                 ErrUnknownEvent,
+		`{"type":"user:answer","data":{"question_id":1,"selected_option":1,"timestamp":1}}`: ErrUnknownEvent,
+		`{"event":"unknown_notice"}`:                                                        ErrUnknownEvent,
+		`{"type":"quiz:countdown","data":{"quiz_id":1}}`:                                    ErrInvalidMessage,
+		`{"type":"quiz:countdown","data":{"quiz_id":1,"seconds_left":"3"}}`:                 ErrInvalidMessage,
+		`{"type":"quiz:countdown","data":{"quiz_id":1,"seconds_left":3,"extra":true}}`:      ErrInvalidMessage,
+		`{"type":"quiz:countdown","data":{"quiz_id":1,"seconds_left":3},"seq":0}`:           ErrInvalidMessage,
+		`{"type":"quiz:countdown","data":{"quiz_id":1,"seconds_left":3},"id":"x"}`:          ErrInvalidMessage,
+		`{"type":"quiz:countdown"}`:                                                         ErrInvalidMessage,
+		`{"data":{}}`:                                                                       ErrInvalidMessage,
+		`[1,2]`:                                                                             ErrInvalidMessage,
+	}
+	for message, want := range invalid {
+		err := catalog.Validate([]byte(message))
+		assert.True(t, errors.Is(err, want), "%s: got %v, want %v", message, err, want)
+	}
+}
+
+func TestCatalog_ValidateClient(t *testing.T) {
+	catalog := loadCatalog(t)
+
+	assert.NoError(t, catalog.ValidateClient([]byte(`{"type":"user:answer","data":{"question_id":1,"selected_option":2,"timestamp":1700000000000}}`)))
+	assert.NoError(t, catalog.ValidateClient([]byte(`{"type":"client:hello","data":{"version":2,"capabilities":["msgpack"]}}`)))
+
+	// Сообщения сервера клиент не отправляет, seq у клиента не бывает
+	err := catalog.ValidateClient([]byte(`{"type":"quiz:timer","data":{}}`))
+	assert.True(t, errors.Is(err, ErrUnknownEvent), "%v", err)
+	err = catalog.ValidateClient([]byte(`{"type":"user:heartbeat","data":{},"seq":1}`))
+	assert.True(t, errors.Is(err, ErrInvalidMessage), "%v", err)
+}
+
+// Сгенерированные типы фронтенда должны соответствовать каталогу: после изменения
+// catalog.json нужно выполнить go run ./cmd/ws-contract
+func TestCatalog_TypeScriptUpToDate(t *testing.T) {
+	catalog := loadCatalog(t)
+	generated, err := catalog.TypeScript()
+	require.NoError(t, err)
+	assert.Contains(t, generated, "export interface QuizAnswerResultData {")
+	assert.Contains(t, generated, "'server:hello': 2,")
+
+	current, err := os.ReadFile("../../../../shared/src/ws-contract.gen.ts")
+	if os.IsNotExist(err) {
+		t.Skip("shared/src is not checked out next to trivia-api")
+	}
+	require.NoError(t, err)
+	assert.Equal(t, generated, string(current), "shared/src/ws-contract.gen.ts is out of date, run go run ./cmd/ws-contract")
+}
@@ -0,0 +1,362 @@
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TypeScriptHeader — первая строка сгенерированного файла
+const TypeScriptHeader = "// Code generated by cmd/ws-contract from internal/websocket/contract/catalog.json. DO NOT EDIT."
+
+// schemaNode — подмножество JSON Schema, которое используется в каталоге
+type schemaNode struct {
+	Ref                  string            `json:"$ref"`
+	Type                 json.RawMessage   `json:"type"`
+	Description          string            `json:"description"`
+	Properties           json.RawMessage   `json:"properties"`
+	Required             []string          `json:"required"`
+	AdditionalProperties json.RawMessage   `json:"additionalProperties"`
+	Items                json.RawMessage   `json:"items"`
+	Enum                 []json.RawMessage `json:"enum"`
+	Const                json.RawMessage   `json:"const"`
+	OneOf                []json.RawMessage `json:"oneOf"`
+	AnyOf                []json.RawMessage `json:"anyOf"`
+}
+
+// TypeScript генерирует типы протокола для фронтенда: интерфейсы данных каждого события,
+// карты «тип → данные» и размеченные объединения сообщений сервера и клиента
+func (c *Catalog) TypeScript() (string, error) {
+	g := &tsGenerator{}
+	g.line(TypeScriptHeader)
+	g.line("// Контракт протокола WebSocket: события сервера, сообщения клиента и их данные.")
+	g.line("")
+	g.line("/** Текущая версия протокола сервера */")
+	g.line("export const WS_PROTOCOL_VERSION = %d;", c.ProtocolVersion)
+
+	names := make([]string, 0, len(c.Definitions))
+	for name := range c.Definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := g.declare(name, "", c.Definitions[name]); err != nil {
+			return "", fmt.Errorf("definition %s: %w", name, err)
+		}
+	}
+
+	for _, direction := range []string{DirectionServer, DirectionClient} {
+		for _, name := range c.Names(direction) {
+			event := c.Events[name]
+			if err := g.declare(dataTypeName(name), name+" — "+event.Description, event.Schema); err != nil {
+				return "", fmt.Errorf("event %s: %w", name, err)
+			}
+		}
+	}
+	noticeNames := make([]string, 0, len(c.Notices))
+	for name := range c.Notices {
+		noticeNames = append(noticeNames, name)
+	}
+	sort.Strings(noticeNames)
+	for _, name := range noticeNames {
+		notice := c.Notices[name]
+		if err := g.declare(typeName(name)+"Notice", notice.Description, notice.Schema); err != nil {
+			return "", fmt.Errorf("notice %s: %w", name, err)
+		}
+	}
+
+	g.line("")
+	g.line("/** Данные событий сервера по типу */")
+	g.line("export interface WSServerEventDataMap {")
+	for _, name := range c.Names(DirectionServer) {
+		g.line("    %s: %s;", quote(name), dataTypeName(name))
+	}
+	g.line("}")
+	g.line("")
+	g.line("export type WSServerEventName = keyof WSServerEventDataMap;")
+	g.line("")
+	g.line("/** Сообщение сервера; seq — номер события в журнале викторины для досылки после переподключения */")
+	g.line("export type WSServerEvent = {")
+	g.line("    [K in WSServerEventName]: { type: K; data: WSServerEventDataMap[K]; seq?: number };")
+	g.line("}[WSServerEventName];")
+	g.line("")
+	g.line("/** Версия протокола, с которой клиент получает событие */")
+	g.line("export const WS_SERVER_EVENT_SINCE: Record<WSServerEventName, number> = {")
+	for _, name := range c.Names(DirectionServer) {
+		g.line("    %s: %d,", quote(name), c.Events[name].Since)
+	}
+	g.line("};")
+	g.line("")
+	g.line("/** Данные сообщений клиента по типу */")
+	g.line("export interface WSClientMessageDataMap {")
+	for _, name := range c.Names(DirectionClient) {
+		g.line("    %s: %s;", quote(name), dataTypeName(name))
+	}
+	g.line("}")
+	g.line("")
+	g.line("export type WSClientMessageName = keyof WSClientMessageDataMap;")
+	g.line("")
+	g.line("export type WSClientMessage = {")
+	g.line("    [K in WSClientMessageName]: { type: K; data: WSClientMessageDataMap[K] };")
+	g.line("}[WSClientMessageName];")
+	g.line("")
+	g.line("/** Служебные сообщения сервера без конверта {type, data} */")
+	notices := make([]string, 0, len(noticeNames))
+	for _, name := range noticeNames {
+		notices = append(notices, typeName(name)+"Notice")
+	}
+	if len(notices) == 0 {
+		notices = append(notices, "never")
+	}
+	g.line("export type WSNotice = %s;", strings.Join(notices, " | "))
+	return g.buf.String(), nil
+}
+
+type tsGenerator struct {
+	buf bytes.Buffer
+}
+
+func (g *tsGenerator) line(format string, args ...interface{}) {
+	if len(args) > 0 {
+		format = fmt.Sprintf(format, args...)
+	}
+	g.buf.WriteString(format)
+	g.buf.WriteByte('\n')
+}
+
+// declare объявляет именованный тип: объект с полями — интерфейсом, остальное — псевдонимом
+func (g *tsGenerator) declare(name, doc string, raw json.RawMessage) error {
+	var node schemaNode
+	if err := json.Unmarshal(raw, &node); err != nil {
+		return err
+	}
+	if doc == "" {
+		doc = node.Description
+	}
+	g.line("")
+	if doc != "" {
+		g.line("/** %s */", doc)
+	}
+	if node.Properties != nil {
+		body, err := objectBody(node, "")
+		if err != nil {
+			return err
+		}
+		g.line("export interface %s %s", name, body)
+		return nil
+	}
+	expr, err := tsType(raw, "")
+	if err != nil {
+		return err
+	}
+	g.line("export type %s = %s;", name, expr)
+	return nil
+}
+
+// tsType переводит схему в выражение типа TypeScript
+func tsType(raw json.RawMessage, indent string) (string, error) {
+	var node schemaNode
+	if err := json.Unmarshal(raw, &node); err != nil {
+		return "", err
+	}
+	switch {
+	case node.Ref != "":
+		return strings.TrimPrefix(node.Ref, "#/definitions/"), nil
+	case len(node.OneOf) > 0 || len(node.AnyOf) > 0:
+		return union(append(node.OneOf, node.AnyOf...), indent)
+	case node.Const != nil:
+		return literal(node.Const)
+	case len(node.Enum) > 0:
+		values := make([]string, 0, len(node.Enum))
+		for _, v := range node.Enum {
+			lit, err := literal(v)
+			if err != nil {
+				return "", err
+			}
+			values = append(values, lit)
+		}
+		return strings.Join(values, " | "), nil
+	}
+
+	types, err := schemaTypes(node.Type)
+	if err != nil {
+		return "", err
+	}
+	if len(types) == 0 {
+		return "unknown", nil
+	}
+	exprs := make([]string, 0, len(types))
+	for _, t := range types {
+		var expr string
+		switch t {
+		case "string":
+			expr = "string"
+		case "integer", "number":
+			expr = "number"
+		case "boolean":
+			expr = "boolean"
+		case "null":
+			expr = "null"
+		case "array":
+			expr = "unknown[]"
+			if node.Items != nil {
+				item, err := tsType(node.Items, indent)
+				if err != nil {
+					return "", err
+				}
+				if strings.Contains(item, " | ") {
+					item = "(" + item + ")"
+				}
+				expr = item + "[]"
+			}
+		case "object":
+			if expr, err = objectType(node, indent); err != nil {
+				return "", err
+			}
+		default:
+			return "", fmt.Errorf("unsupported schema type %q", t)
+		}
+		exprs = append(exprs, expr)
+	}
+	return strings.Join(exprs, " | "), nil
+}
+
+func union(variants []json.RawMessage, indent string) (string, error) {
+	exprs := make([]string, 0, len(variants))
+	for _, variant := range variants {
+		expr, err := tsType(variant, indent)
+		if err != nil {
+			return "", err
+		}
+		exprs = append(exprs, expr)
+	}
+	return strings.Join(exprs, " | "), nil
+}
+
+func objectType(node schemaNode, indent string) (string, error) {
+	if node.Properties != nil {
+		return objectBody(node, indent)
+	}
+	var additional bool
+	if node.AdditionalProperties != nil && json.Unmarshal(node.AdditionalProperties, &additional) != nil {
+		value, err := tsType(node.AdditionalProperties, indent)
+		if err != nil {
+			return "", err
+		}
+		return "Record<string, " + value + ">", nil
+	}
+	return "Record<string, unknown>", nil
+}
+
+// objectBody формирует тело объекта с полями в порядке каталога
+func objectBody(node schemaNode, indent string) (string, error) {
+	keys, err := orderedKeys(node.Properties)
+	if err != nil {
+		return "", err
+	}
+	var properties map[string]json.RawMessage
+	if err := json.Unmarshal(node.Properties, &properties); err != nil {
+		return "", err
+	}
+	required := make(map[string]bool, len(node.Required))
+	for _, name := range node.Required {
+		required[name] = true
+	}
+
+	inner := indent + "    "
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, key := range keys {
+		var prop schemaNode
+		if err := json.Unmarshal(properties[key], &prop); err != nil {
+			return "", err
+		}
+		expr, err := tsType(properties[key], inner)
+		if err != nil {
+			return "", fmt.Errorf("property %s: %w", key, err)
+		}
+		if prop.Description != "" && prop.Ref == "" {
+			fmt.Fprintf(&b, "%s/** %s */\n", inner, prop.Description)
+		}
+		optional := ""
+		if !required[key] {
+			optional = "?"
+		}
+		fmt.Fprintf(&b, "%s%s%s: %s;\n", inner, key, optional, expr)
+	}
+	b.WriteString(indent + "}")
+	return b.String(), nil
+}
+
+// orderedKeys возвращает ключи JSON-объекта в порядке записи
+func orderedKeys(raw json.RawMessage) ([]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	var keys []string
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected token %v", tok)
+		}
+		var skip json.RawMessage
+		if err := dec.Decode(&skip); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func schemaTypes(raw json.RawMessage) ([]string, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		return []string{single}, nil
+	}
+	var many []string
+	if err := json.Unmarshal(raw, &many); err != nil {
+		return nil, fmt.Errorf("invalid schema type %s", raw)
+	}
+	return many, nil
+}
+
+func literal(raw json.RawMessage) (string, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return quote(s), nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
+
+// quote записывает строку литералом в одинарных кавычках, как принято в shared/src
+func quote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+// dataTypeName — имя типа данных события: quiz:answer_result → QuizAnswerResultData
+func dataTypeName(event string) string {
+	return typeName(event) + "Data"
+}
+
+func typeName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return r == ':' || r == '_' || r == '-' }) {
+		part = strings.ToLower(part)
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
@@ -0,0 +1,108 @@
+package websocket
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/websocket/contract"
+)
+
+// recordingHub запоминает всё, что Manager отправляет через хаб
+type recordingHub struct {
+	mu       sync.Mutex
+	messages [][]byte
+}
+
+func (h *recordingHub) record(message []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.messages = append(h.messages, message)
+}
+
+func (h *recordingHub) recordJSON(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	h.record(data)
+	return nil
+}
+
+func (h *recordingHub) BroadcastJSON(v interface{}) error            { return h.recordJSON(v) }
+func (h *recordingHub) SendJSONToUser(_ string, v interface{}) error { return h.recordJSON(v) }
+func (h *recordingHub) SendToUser(_ string, message []byte) bool     { h.record(message); return true }
+func (h *recordingHub) BroadcastToQuiz(_ uint, message []byte)       { h.record(message) }
+func (h *recordingHub) GetMetrics() map[string]interface{}           { return nil }
+func (h *recordingHub) ClientCount() int                             { return 0 }
+func (h *recordingHub) GetActiveSubscribers(_ uint) ([]uint, error)  { return nil, nil }
+func (h *recordingHub) GetSubscriberCount(_ uint) int                { return 0 }
+
+func TestContract_HelloMatchesCatalog(t *testing.T) {
+	catalog, err := contract.Load()
+	require.NoError(t, err)
+	assert.Equal(t, CurrentProtocolVersion, catalog.ProtocolVersion)
+
+	m := NewManager(nil)
+	current := NewClient(nil, nil, "1")
+	m.SetClientProtocol(current, CurrentProtocolVersion, nil)
+	hello := m.Hello(current)
+
+	for _, name := range hello.Events {
+		event, ok := catalog.Event(name)
+		if assert.True(t, ok, "server event %s is not in the catalog", name) {
+			assert.Equal(t, contract.DirectionServer, event.Direction, name)
+			assert.Equal(t, defaultServerEvents[name], event.Since, "since of %s", name)
+		}
+	}
+	for _, name := range hello.Messages {
+		event, ok := catalog.Event(name)
+		if assert.True(t, ok, "client message %s is not in the catalog", name) {
+			assert.Equal(t, contract.DirectionClient, event.Direction, name)
+		}
+	}
+
+	// Клиенты версии 1 не получают событий, появившихся позже
+	legacy := NewClient(nil, nil, "2")
+	m.SetClientProtocol(legacy, ProtocolV1, nil)
+	legacyEvents := make(map[string]bool)
+	for _, name := range m.Hello(legacy).Events {
+		legacyEvents[name] = true
+	}
+	for _, name := range hello.Events {
+		event, _ := catalog.Event(name)
+		assert.Equal(t, event.Since == ProtocolV1, legacyEvents[name], "%s in protocol v1 hello", name)
+	}
+}
+
+func TestContract_ManagerEvents(t *testing.T) {
+	catalog, err := contract.Load()
+	require.NoError(t, err)
+
+	hub := &recordingHub{}
+	m := NewManager(hub)
+	client := NewClient(nil, nil, "7")
+
+	m.SendErrorToClient(client, "invalid_format", "Failed to parse user:ready event")
+	m.SendTokenExpirationWarning(client.UserID, 300)
+	m.SendTokenExpiredNotification(client.UserID)
+	require.NoError(t, m.HandleMessage([]byte(`{"type":"client:hello","data":{"version":2,"capabilities":[]}}`), client))
+	require.NoError(t, m.BroadcastEventToQuiz(1, Event{Type: "quiz:countdown", Data: map[string]interface{}{"quiz_id": 1, "seconds_left": 3}}))
+	hub.record(bufferWarning(4).json)
+
+	seen := make(map[string]bool)
+	for _, message := range hub.messages {
+		assert.NoError(t, catalog.Validate(message), string(message))
+		var envelope struct {
+			Type string `json:"type"`
+		}
+		require.NoError(t, json.Unmarshal(message, &envelope))
+		seen[envelope.Type] = true
+	}
+	for _, name := range []string{"server:error", TOKEN_EXPIRE_SOON, TOKEN_EXPIRED, SERVER_HELLO, "quiz:countdown", "server:buffer_warning"} {
+		assert.True(t, seen[name], "%s was not sent", name)
+	}
+}
@@ -0,0 +1,143 @@
+package websocket
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// CapabilityMessagePack — возможность протокола: сервер отправляет события бинарными
+// фреймами MessagePack и принимает сообщения клиента в том же формате
+const CapabilityMessagePack = "msgpack"
+
+// outboundMessage — сообщение в очереди отправки клиента. Один экземпляр разделяется всеми
+// получателями рассылки, поэтому тип, каждый формат и готовый фрейм WebSocket
+// вычисляются один раз на рассылку, а не на клиента.
+type outboundMessage struct {
+	json []byte
+	seq  int64 // номер события в журнале викторины (0 — событие не журналируется)
+
+	typeOnce sync.Once
+	msgType  string
+
+	msgpackOnce sync.Once
+	msgpack     []byte
+	msgpackErr  error
+
+	textOnce     sync.Once
+	textFrame    *websocket.PreparedMessage
+	textErr      error
+	binaryOnce   sync.Once
+	binaryFrame  *websocket.PreparedMessage
+	binaryFailed bool
+}
+
+// newOutboundMessage оборачивает сериализованное в JSON событие для постановки в очереди клиентов
+func newOutboundMessage(data []byte) *outboundMessage {
+	return &outboundMessage{json: data}
+}
+
+// Type возвращает тип события или пустую строку, если сообщение не является событием {type, data}
+func (m *outboundMessage) Type() string {
+	m.typeOnce.Do(func() {
+		var event struct {
+			Type string `json:"type"`
+		}
+		if json.Unmarshal(m.json, &event) == nil {
+			m.msgType = event.Type
+		}
+	})
+	return m.msgType
+}
+
+// prepared возвращает фрейм WebSocket в нужном формате. PreparedMessage кэширует и результат
+// permessage-deflate, поэтому сжатие тоже выполняется один раз на рассылку. Если событие
+// не удалось закодировать в MessagePack, клиенту уходит текстовый JSON-фрейм.
+func (m *outboundMessage) prepared(binary bool) (*websocket.PreparedMessage, error) {
+	if binary {
+		m.binaryOnce.Do(func() {
+			data, err := m.MessagePack()
+			if err == nil {
+				m.binaryFrame, err = websocket.NewPreparedMessage(websocket.BinaryMessage, data)
+			}
+			if err != nil {
+				log.Printf("[WebSocket] Событие %s не закодировано в MessagePack, отправляется JSON: %v", m.Type(), err)
+				m.binaryFailed = true
+			}
+		})
+		if !m.binaryFailed {
+			return m.binaryFrame, nil
+		}
+	}
+	m.textOnce.Do(func() {
+		m.textFrame, m.textErr = websocket.NewPreparedMessage(websocket.TextMessage, m.json)
+	})
+	return m.textFrame, m.textErr
+}
+
+// MessagePack возвращает событие в формате MessagePack, кодируя его при первом обращении
+func (m *outboundMessage) MessagePack() ([]byte, error) {
+	m.msgpackOnce.Do(func() {
+		m.msgpack, m.msgpackErr = jsonToMessagePack(m.json)
+	})
+	return m.msgpack, m.msgpackErr
+}
+
+// jsonToMessagePack перекодирует JSON в MessagePack. Кодируется уже готовый JSON, а не исходная
+// структура, чтобы форма событий (теги json, MarshalJSON) в обоих форматах совпадала.
+func jsonToMessagePack(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("decode json: %w", err)
+	}
+
+	var buf bytes.Buffer
+	encoder := msgpack.NewEncoder(&buf)
+	encoder.UseCompactInts(true)
+	encoder.UseCompactFloats(true)
+	if err := encoder.Encode(normalizeJSONNumbers(value)); err != nil {
+		return nil, fmt.Errorf("encode msgpack: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// normalizeJSONNumbers заменяет json.Number целыми или вещественными числами,
+// иначе MessagePack закодировал бы их строками
+func normalizeJSONNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = normalizeJSONNumbers(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeJSONNumbers(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// messagePackToJSON перекодирует сообщение клиента из MessagePack в JSON для обработчиков Manager
+func messagePackToJSON(data []byte) ([]byte, error) {
+	var value interface{}
+	if err := msgpack.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("decode msgpack: %w", err)
+	}
+	return json.Marshal(value)
+}
@@ -0,0 +1,69 @@
+package websocket
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func TestOutboundMessage_MessagePackEncodedOnce(t *testing.T) {
+	frame := newOutboundMessage([]byte(`{"type":"quiz:question","data":{"question_id":42,"time_limit":10.5,"options":["a","b"]}}`))
+
+	first, err := frame.MessagePack()
+	require.NoError(t, err)
+	second, err := frame.MessagePack()
+	require.NoError(t, err)
+	assert.Same(t, &first[0], &second[0])
+
+	var decoded map[string]interface{}
+	require.NoError(t, msgpack.Unmarshal(first, &decoded))
+	assert.Equal(t, "quiz:question", decoded["type"])
+	data := decoded["data"].(map[string]interface{})
+	// Целые числа остаются целыми, а не строками или float
+	assert.EqualValues(t, 42, data["question_id"])
+	assert.IsType(t, int8(0), data["question_id"])
+	assert.EqualValues(t, 10.5, data["time_limit"])
+}
+
+func TestClient_EncodesByNegotiatedFormat(t *testing.T) {
+	m := NewManager(nil)
+	frame := newOutboundMessage([]byte(`{"type":"quiz:timer","data":{"remaining_seconds":5}}`))
+
+	textClient := NewClient(nil, nil, "1")
+	m.SetClientProtocol(textClient, ProtocolV2, nil)
+	out, binary, ok := textClient.encodeOutgoing(frame)
+	require.True(t, ok)
+	assert.False(t, binary)
+	assert.Same(t, frame, out)
+
+	binaryClient := NewClient(nil, nil, "2")
+	m.SetClientProtocol(binaryClient, ProtocolV2, []string{CapabilityMessagePack})
+	_, binary, ok = binaryClient.encodeOutgoing(frame)
+	require.True(t, ok)
+	assert.True(t, binary)
+
+	// Подготовленный фрейм создаётся один раз на формат и разделяется всеми клиентами
+	text, err := frame.prepared(false)
+	require.NoError(t, err)
+	again, err := frame.prepared(false)
+	require.NoError(t, err)
+	assert.Same(t, text, again)
+	bin, err := frame.prepared(true)
+	require.NoError(t, err)
+	assert.NotSame(t, text, bin)
+	assert.Equal(t, "quiz:timer", frame.Type())
+}
+
+func TestMessagePackToJSON(t *testing.T) {
+	in, err := msgpack.Marshal(map[string]interface{}{
+		"type": "user:answer",
+		"data": map[string]interface{}{"question_id": 7, "selected_option": 2},
+	})
+	require.NoError(t, err)
+
+	out, err := messagePackToJSON(in)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"user:answer","data":{"question_id":7,"selected_option":2}}`, string(out))
+}
@@ -0,0 +1,220 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+)
+
+// streamableEvents — события викторины, которые доступны через поток только для чтения (SSE).
+// События с данными конкретных пользователей (quiz:user_ready) в поток не попадают.
+var streamableEvents = map[string]bool{
+	"quiz:announcement":      true,
+	"quiz:waiting_room":      true,
+	"quiz:countdown":         true,
+	"quiz:start":             true,
+	"quiz:question":          true,
+	"quiz:timer":             true,
+	"quiz:answer_reveal":     true,
+	"quiz:ad_break":          true,
+	"quiz:ad_break_end":      true,
+	"quiz:player_count":      true,
+	"quiz:finish":            true,
+	"quiz:results_available": true,
+	"quiz:cancelled":         true,
+}
+
+// defaultStreamBuffer — размер очереди подписчика, если в конфигурации он не задан
+const defaultStreamBuffer = 64
+
+// StreamEvent — событие викторины для потоковой доставки
+type StreamEvent struct {
+	Seq  int64           // номер события в журнале викторины (0 — событие не журналируется)
+	Type string          // тип события, например quiz:question
+	Data json.RawMessage // поле data события
+}
+
+// QuizStream — подписка на события одной викторины. Events закрывается, когда подписка
+// завершена: вызван Close или подписчик не успевал забирать события.
+type QuizStream struct {
+	quizID  uint
+	events  chan StreamEvent
+	streams *quizStreams
+
+	mu      sync.Mutex
+	lastSeq int64
+	closed  bool
+}
+
+// Events возвращает канал событий подписки
+func (s *QuizStream) Events() <-chan StreamEvent {
+	return s.events
+}
+
+// Close отменяет подписку. Повторный вызов безопасен.
+func (s *QuizStream) Close() {
+	s.streams.remove(s)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closeLocked()
+}
+
+func (s *QuizStream) closeLocked() {
+	if !s.closed {
+		s.closed = true
+		close(s.events)
+	}
+}
+
+// push ставит событие в очередь подписчика. Журналируемые события, которые подписчик
+// уже получил при повторе, пропускаются. Возвращает false, если очередь переполнена.
+func (s *QuizStream) push(event StreamEvent) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pushLocked(event)
+}
+
+func (s *QuizStream) pushLocked(event StreamEvent) bool {
+	if s.closed {
+		return true
+	}
+	if event.Seq > 0 {
+		if event.Seq <= s.lastSeq {
+			return true
+		}
+		s.lastSeq = event.Seq
+	}
+	select {
+	case s.events <- event:
+		return true
+	default:
+		// Медленный подписчик: закрываем поток, клиент переподключится с Last-Event-ID
+		s.closeLocked()
+		return false
+	}
+}
+
+// quizStreams — реестр потоковых подписок по викторинам
+type quizStreams struct {
+	bufferSize int
+
+	mu   sync.RWMutex
+	subs map[uint]map[*QuizStream]struct{}
+}
+
+func newQuizStreams(bufferSize int) *quizStreams {
+	if bufferSize <= 0 {
+		bufferSize = defaultStreamBuffer
+	}
+	return &quizStreams{bufferSize: bufferSize, subs: make(map[uint]map[*QuizStream]struct{})}
+}
+
+func (r *quizStreams) add(stream *QuizStream) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.subs[stream.quizID] == nil {
+		r.subs[stream.quizID] = make(map[*QuizStream]struct{})
+	}
+	r.subs[stream.quizID][stream] = struct{}{}
+}
+
+func (r *quizStreams) remove(stream *QuizStream) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if subs, ok := r.subs[stream.quizID]; ok {
+		delete(subs, stream)
+		if len(subs) == 0 {
+			delete(r.subs, stream.quizID)
+		}
+	}
+}
+
+// count возвращает общее количество открытых подписок
+func (r *quizStreams) count() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	total := 0
+	for _, subs := range r.subs {
+		total += len(subs)
+	}
+	return total
+}
+
+// publish рассылает событие подписчикам викторины; переполненные подписки отключаются
+func (r *quizStreams) publish(quizID uint, message *outboundMessage) {
+	r.mu.RLock()
+	subs := make([]*QuizStream, 0, len(r.subs[quizID]))
+	for stream := range r.subs[quizID] {
+		subs = append(subs, stream)
+	}
+	r.mu.RUnlock()
+	if len(subs) == 0 || !streamableEvents[message.Type()] {
+		return
+	}
+
+	event, ok := toStreamEvent(message)
+	if !ok {
+		return
+	}
+	for _, stream := range subs {
+		if !stream.push(event) {
+			r.remove(stream)
+			log.Printf("[QuizStream] Подписчик викторины %d не успевал получать события, поток закрыт", quizID)
+		}
+	}
+}
+
+// toStreamEvent извлекает тип и данные события из сообщения рассылки
+func toStreamEvent(message *outboundMessage) (StreamEvent, bool) {
+	var event struct {
+		Type string          `json:"type"`
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(message.json, &event); err != nil || event.Type == "" {
+		return StreamEvent{}, false
+	}
+	if len(event.Data) == 0 {
+		event.Data = json.RawMessage("null")
+	}
+	return StreamEvent{Seq: message.seq, Type: event.Type, Data: event.Data}, true
+}
+
+// SubscribeQuizStream открывает поток событий викторины только для чтения.
+// lastSeq — номер последнего полученного события: пропущенные события повторяются
+// из журнала до живых, без дублей (0 — повтор с последнего вопроса, как для WebSocket).
+func (h *ShardedHub) SubscribeQuizStream(quizID uint, lastSeq int64) (*QuizStream, error) {
+	if h.streams == nil {
+		return nil, fmt.Errorf("quiz event streams are disabled")
+	}
+	stream := &QuizStream{
+		quizID:  quizID,
+		events:  make(chan StreamEvent, h.streams.bufferSize),
+		streams: h.streams,
+		lastSeq: lastSeq,
+	}
+
+	// Подписка регистрируется до чтения журнала, а живые события ждут окончания повтора
+	// на мьютексе потока: так события не теряются между журналом и рассылкой и не
+	// переставляются местами
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+	h.streams.add(stream)
+
+	if h.replay != nil {
+		events, err := h.replay.since(quizID, lastSeq)
+		if err != nil {
+			log.Printf("[ShardedHub] Ошибка чтения журнала событий викторины %d для потока: %v", quizID, err)
+		}
+		for _, message := range events {
+			if !streamableEvents[message.Type()] {
+				continue
+			}
+			if event, ok := toStreamEvent(message); ok && !stream.pushLocked(event) {
+				h.streams.remove(stream)
+				break
+			}
+		}
+	}
+	return stream, nil
+}
@@ -0,0 +1,70 @@
+package websocket
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func drainStream(stream *QuizStream) []StreamEvent {
+	var events []StreamEvent
+	for {
+		select {
+		case event, ok := <-stream.Events():
+			if !ok {
+				return events
+			}
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func TestQuizStream_ReplaysThenDeliversLiveWithoutDuplicates(t *testing.T) {
+	h := newReplayHub(t)
+	h.streams = newQuizStreams(8)
+	recordEvent(h, "quiz:question")
+	missed := recordEvent(h, "quiz:answer_reveal")
+
+	stream, err := h.SubscribeQuizStream(7, 1)
+	require.NoError(t, err)
+	defer stream.Close()
+
+	// Событие уже пришло повтором — повторная рассылка его не дублирует
+	h.streams.publish(7, missed)
+	h.streams.publish(7, recordEvent(h, "quiz:finish"))
+	// Персональные события и события других викторин в поток не попадают
+	h.streams.publish(7, newOutboundMessage([]byte(`{"type":"quiz:user_ready","data":{"user_id":1}}`)))
+	h.streams.publish(8, newOutboundMessage([]byte(`{"type":"quiz:timer","data":{}}`)))
+
+	events := drainStream(stream)
+	require.Len(t, events, 2)
+	assert.Equal(t, StreamEvent{Seq: 2, Type: "quiz:answer_reveal", Data: []byte(`{}`)}, events[0])
+	assert.Equal(t, int64(3), events[1].Seq)
+	assert.Equal(t, "quiz:finish", events[1].Type)
+}
+
+func TestQuizStream_SlowSubscriberIsClosed(t *testing.T) {
+	h := newReplayHub(t)
+	h.streams = newQuizStreams(1)
+
+	stream, err := h.SubscribeQuizStream(7, 0)
+	require.NoError(t, err)
+	timer := newOutboundMessage([]byte(`{"type":"quiz:timer","data":{"remaining":5}}`))
+	h.streams.publish(7, timer)
+	h.streams.publish(7, timer)
+
+	assert.Len(t, drainStream(stream), 1)
+	_, open := <-stream.Events()
+	assert.False(t, open)
+	assert.Equal(t, 0, h.streams.count())
+	stream.Close() // повторное закрытие безопасно
+}
+
+func TestShardedHub_SubscribeQuizStreamDisabled(t *testing.T) {
+	h := newReplayHub(t)
+	_, err := h.SubscribeQuizStream(7, 0)
+	assert.Error(t, err)
+}
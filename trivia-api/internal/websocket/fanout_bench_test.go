@@ -0,0 +1,166 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"testing"
+)
+
+const (
+	benchClientsPerNode = 50000
+	benchShards         = 16
+	benchQuizID         = 1
+)
+
+var benchEvent = []byte(`{"type":"quiz:question","data":{"question_id":17,"quiz_id":1,"number":3,"total_questions":10,"text":"Какая река самая длинная в Казахстане?","options":[{"id":1,"text":"Иртыш"},{"id":2,"text":"Сырдарья"},{"id":3,"text":"Или"},{"id":4,"text":"Урал"}],"time_limit_sec":10,"start_time":1760000000000,"server_timestamp":1760000000000}}`)
+
+// benchNode — шарды узла с подписанными на викторину клиентами без реальных соединений
+type benchNode struct {
+	shards  []*Shard
+	clients [][]*Client // клиенты по шардам
+}
+
+// newBenchNode подписывает clients клиентов на викторину; каждый msgpackEvery-й клиент
+// получает бинарные фреймы (0 — только JSON)
+func newBenchNode(b *testing.B, clients, msgpackEvery int) *benchNode {
+	b.Helper()
+	node := &benchNode{
+		shards:  make([]*Shard, benchShards),
+		clients: make([][]*Client, benchShards),
+	}
+	for i := range node.shards {
+		node.shards[i] = NewShard(i, nil, clients, 0, 0, nil)
+	}
+	for i := 0; i < clients; i++ {
+		client := NewClient(nil, nil, fmt.Sprintf("user-%d", i))
+		if msgpackEvery > 0 && i%msgpackEvery == 0 {
+			client.setProtocol(CurrentProtocolVersion, []string{CapabilityMessagePack}, nil)
+		}
+		shard := node.shards[i%benchShards]
+		quizMap, _ := shard.quizSubscriptions.LoadOrStore(uint(benchQuizID), &sync.Map{})
+		quizMap.(*sync.Map).Store(client, struct{}{})
+		shard.clients.Store(client, true)
+		node.clients[i%benchShards] = append(node.clients[i%benchShards], client)
+	}
+	return node
+}
+
+// broadcast рассылает кадр по всем шардам и забирает его из очередей клиентов так же,
+// как это делает writePump: кодирование и подготовка фрейма без записи в сокет
+func (n *benchNode) broadcast(b *testing.B, frame *outboundMessage) {
+	var wg sync.WaitGroup
+	wg.Add(len(n.shards))
+	for i, shard := range n.shards {
+		go func(shard *Shard, clients []*Client) {
+			defer wg.Done()
+			shard.broadcastToQuiz(benchQuizID, frame)
+			for _, client := range clients {
+				queued, ok := client.send.pop()
+				if !ok {
+					b.Errorf("client %s: message not queued", client.UserID)
+					return
+				}
+				message, binary, ok := client.encodeOutgoing(queued)
+				if !ok {
+					continue
+				}
+				if _, err := message.prepared(binary); err != nil {
+					b.Error(err)
+					return
+				}
+			}
+		}(shard, n.clients[i])
+	}
+	wg.Wait()
+}
+
+func silenceLogs(b *testing.B) {
+	b.Helper()
+	previous := log.Writer()
+	log.SetOutput(io.Discard)
+	b.Cleanup(func() { log.SetOutput(previous) })
+}
+
+func reportDeliveries(b *testing.B, clients int) {
+	b.ReportMetric(float64(clients)*float64(b.N)/b.Elapsed().Seconds(), "msgs/s")
+}
+
+// BenchmarkQuizFanout_50kClients — рассылка события викторины 50 000 клиентам узла
+func BenchmarkQuizFanout_50kClients(b *testing.B) {
+	silenceLogs(b)
+	node := newBenchNode(b, benchClientsPerNode, 0)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		node.broadcast(b, newOutboundMessage(benchEvent))
+	}
+	reportDeliveries(b, benchClientsPerNode)
+}
+
+// BenchmarkQuizFanout_50kClientsMixedEncoding — каждый четвёртый клиент согласовал MessagePack
+func BenchmarkQuizFanout_50kClientsMixedEncoding(b *testing.B) {
+	silenceLogs(b)
+	node := newBenchNode(b, benchClientsPerNode, 4)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		node.broadcast(b, newOutboundMessage(benchEvent))
+	}
+	reportDeliveries(b, benchClientsPerNode)
+}
+
+// BenchmarkQuizFanout_50kClientsDebugLogging — те же 50 000 клиентов с включёнными
+// логами по каждому клиенту (вывод отбрасывается, измеряется только форматирование)
+func BenchmarkQuizFanout_50kClientsDebugLogging(b *testing.B) {
+	silenceLogs(b)
+	SetDebugLogging(true)
+	b.Cleanup(func() { SetDebugLogging(false) })
+	node := newBenchNode(b, benchClientsPerNode, 0)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		node.broadcast(b, newOutboundMessage(benchEvent))
+	}
+	reportDeliveries(b, benchClientsPerNode)
+}
+
+// BenchmarkFramePreparation сравнивает подготовку фрейма один раз на рассылку
+// с прежней схемой, где каждый клиент заново разбирал и кодировал сообщение
+func BenchmarkFramePreparation(b *testing.B) {
+	const recipients = 1000
+
+	b.Run("shared", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			frame := newOutboundMessage(benchEvent)
+			for r := 0; r < recipients; r++ {
+				frame.Type()
+				if _, err := frame.prepared(false); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+		reportDeliveries(b, recipients)
+	})
+
+	b.Run("per_client", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for r := 0; r < recipients; r++ {
+				var event struct {
+					Type string `json:"type"`
+				}
+				if err := json.Unmarshal(benchEvent, &event); err != nil {
+					b.Fatal(err)
+				}
+				frame := newOutboundMessage(benchEvent)
+				if _, err := frame.prepared(false); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+		reportDeliveries(b, recipients)
+	})
+}
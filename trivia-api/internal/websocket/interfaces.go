@@ -2,6 +2,7 @@ package websocket
 
 import (
 	"net/http"
+	"time"
 )
 
 // MetricsProvider определяет метод для получения метрик хаба.
@@ -46,6 +47,74 @@ type HubInterface interface {
 	// UnregisterClient(client *Client) // Пример
 }
 
+// QuizBroadcaster — хаб, который рассылает сообщения подписчикам одной викторины
+type QuizBroadcaster interface {
+	BroadcastToQuiz(quizID uint, message []byte)
+}
+
+// QuizDelivery — итог рассылки сообщения подписчикам викторины на одном инстансе
+type QuizDelivery struct {
+	Subscribers int // Подписчики викторины на момент рассылки
+	Delivered   int // Получили сообщение в очередь отправки (или уже получили его повтором)
+}
+
+// Failed возвращает число подписчиков, которым сообщение не доставлено: их очередь
+// переполнена или соединение закрывается
+func (d QuizDelivery) Failed() int {
+	return d.Subscribers - d.Delivered
+}
+
+// Ratio возвращает долю подписчиков, получивших сообщение; без подписчиков — 1
+func (d QuizDelivery) Ratio() float64 {
+	if d.Subscribers == 0 {
+		return 1
+	}
+	return float64(d.Delivered) / float64(d.Subscribers)
+}
+
+// QuizDeliveryBroadcaster — хаб, который сообщает, скольким подписчикам викторины доставлена рассылка
+type QuizDeliveryBroadcaster interface {
+	BroadcastToQuizCounted(quizID uint, message []byte) QuizDelivery
+}
+
+// CapacityPlanner — хаб, который заранее готовит ёмкость под ожидаемую аудиторию викторины
+type CapacityPlanner interface {
+	ReserveQuizCapacity(quizID uint, expectedClients int)
+	ReleaseQuizCapacity(quizID uint)
+}
+
+// ClientIterator — хаб, который перебирает подключенные к инстансу клиенты
+type ClientIterator interface {
+	ForEachClient(fn func(client *Client))
+}
+
+// QuizLeaveNotifier — хаб, который сообщает об отписке клиентов от викторины
+type QuizLeaveNotifier interface {
+	SetQuizLeaveHandler(handler func(userID string, quizID uint))
+}
+
+// Типы событий жизненного цикла соединения
+const (
+	ConnectionOpened = "opened"
+	ConnectionClosed = "closed"
+)
+
+// ConnectionEvent — открытие или закрытие WebSocket-соединения
+type ConnectionEvent struct {
+	Type         string
+	UserID       string
+	ConnectionID string
+	QuizID       uint // викторина, на которую клиент был подписан при закрытии
+	At           time.Time
+	Duration     time.Duration // длительность соединения (только при закрытии)
+	Replaced     bool          // соединение вытеснено новым соединением того же пользователя
+}
+
+// ConnectionNotifier — хаб, который сообщает об открытии и закрытии соединений
+type ConnectionNotifier interface {
+	SetConnectionHandler(handler func(event ConnectionEvent))
+}
+
 // HttpHandlerProvider определяет метод для предоставления HTTP обработчиков.
 type HttpHandlerProvider interface {
 	GetHttpHandlers() map[string]http.HandlerFunc
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"sync"
+	"time"
 )
 
 // Event представляет структуру WebSocket-сообщения
@@ -16,6 +18,12 @@ type Event struct {
 type Manager struct {
 	hub            HubInterface
 	messageHandler map[string]func(data json.RawMessage, client *Client) error
+
+	// Версии протокола: события сервера, сериализаторы для старых версий, возможности
+	protocolMu   sync.RWMutex
+	serverEvents map[string]int
+	serializers  map[int]map[string]EventSerializer
+	capabilities map[string]bool
 }
 
 // NewManager создает новый менеджер WebSocket
@@ -23,7 +31,14 @@ func NewManager(hub HubInterface) *Manager {
 	m := &Manager{
 		hub:            hub,
 		messageHandler: make(map[string]func(data json.RawMessage, client *Client) error),
+		serverEvents:   make(map[string]int, len(defaultServerEvents)),
+		serializers:    make(map[int]map[string]EventSerializer),
+		capabilities:   map[string]bool{CapabilityMessagePack: true},
+	}
+	for eventType, since := range defaultServerEvents {
+		m.serverEvents[eventType] = since
 	}
+	m.messageHandler[CLIENT_HELLO] = m.handleClientHello
 	return m
 }
 
@@ -161,19 +176,41 @@ func (m *Manager) BroadcastEventToQuiz(quizID uint, event interface{}) error {
 		return fmt.Errorf("failed to marshal event for quiz %d: %w", quizID, err)
 	}
 
-	// Проверяем, является ли хаб шардированным
-	if shardedHub, ok := m.hub.(*ShardedHub); ok {
+	// Проверяем, умеет ли хаб рассылать по викторинам (ShardedHub)
+	if broadcaster, ok := m.hub.(QuizBroadcaster); ok {
 		// Если да, используем его метод для отправки в конкретный квиз
-		shardedHub.BroadcastToQuiz(quizID, jsonBytes)
+		broadcaster.BroadcastToQuiz(quizID, jsonBytes)
 		return nil
 	} else {
-		// Если это не ShardedHub, то специфичная для квиза рассылка не поддерживается.
+		// Если хаб не рассылает по викторинам, то специфичная для квиза рассылка не поддерживается.
 		// НЕЛЬЗЯ просто вызывать m.hub.BroadcastJSON(event), т.к. это отправит ВСЕМ.
 		log.Printf("Warning: BroadcastEventToQuiz called on a non-sharded hub type %T. Quiz-specific broadcast is not supported. Event dropped for quiz %d.", m.hub, quizID)
 		return nil // Возвращаем nil, т.к. это ограничение типа, а не ошибка выполнения.
 	}
 }
 
+// BroadcastEventToQuizCounted отправляет событие подписчикам викторины, как BroadcastEventToQuiz,
+// и сообщает, скольким из них оно доставлено. counted=false — хаб не считает доставку.
+func (m *Manager) BroadcastEventToQuizCounted(quizID uint, event interface{}) (delivery QuizDelivery, counted bool, err error) {
+	broadcaster, ok := m.hub.(QuizDeliveryBroadcaster)
+	if !ok {
+		return QuizDelivery{}, false, m.BroadcastEventToQuiz(quizID, event)
+	}
+	jsonBytes, err := json.Marshal(event)
+	if err != nil {
+		return QuizDelivery{}, false, fmt.Errorf("failed to marshal event for quiz %d: %w", quizID, err)
+	}
+	return broadcaster.BroadcastToQuizCounted(quizID, jsonBytes), true, nil
+}
+
+// ForEachClient вызывает fn для каждого клиента, подключенного к этому инстансу.
+// Для хабов без перебора клиентов ничего не делает.
+func (m *Manager) ForEachClient(fn func(client *Client)) {
+	if iterator, ok := m.hub.(ClientIterator); ok {
+		iterator.ForEachClient(fn)
+	}
+}
+
 // SubscribeClientToTypes подписывает клиента на указанные типы сообщений
 func (m *Manager) SubscribeClientToTypes(client *Client, messageTypes []string) {
 	for _, msgType := range messageTypes {
@@ -204,6 +241,36 @@ func (m *Manager) SubscribeClientToQuiz(client *Client, quizID uint) error {
 	return nil
 }
 
+// ClientRTT возвращает оценку RTT соединения пользователя. false — пользователь не подключён
+// к этому узлу или замеров ещё не было.
+func (m *Manager) ClientRTT(userID string) (time.Duration, bool) {
+	shardedHub, ok := m.hub.(*ShardedHub)
+	if !ok {
+		return 0, false
+	}
+	return shardedHub.ClientRTT(userID)
+}
+
+// ReplayQuizEvents досылает клиенту события викторины, пропущенные до подписки.
+// lastSeq — номер последнего события, полученного клиентом (0, если неизвестен).
+func (m *Manager) ReplayQuizEvents(client *Client, quizID uint, lastSeq int64) int {
+	shardedHub, ok := m.hub.(*ShardedHub)
+	if !ok {
+		return 0
+	}
+	return shardedHub.ReplayQuizEvents(client, quizID, lastSeq)
+}
+
+// SubscribeQuizStream открывает поток событий викторины только для чтения (для SSE).
+// lastSeq — номер последнего полученного события (0, если неизвестен).
+func (m *Manager) SubscribeQuizStream(quizID uint, lastSeq int64) (*QuizStream, error) {
+	shardedHub, ok := m.hub.(*ShardedHub)
+	if !ok {
+		return nil, fmt.Errorf("тип хаба %T не поддерживает потоки событий викторин", m.hub)
+	}
+	return shardedHub.SubscribeQuizStream(quizID, lastSeq)
+}
+
 // UnsubscribeClientFromTypes отменяет подписку клиента на указанные типы сообщений
 func (m *Manager) UnsubscribeClientFromTypes(client *Client, messageTypes []string) {
 	for _, msgType := range messageTypes {
@@ -298,3 +365,31 @@ func (m *Manager) GetSubscriberCount(quizID uint) int {
 	}
 	return m.hub.GetSubscriberCount(quizID)
 }
+
+// ReserveQuizCapacity готовит хаб к ожидаемому числу участников викторины, если хаб это умеет
+func (m *Manager) ReserveQuizCapacity(quizID uint, expectedClients int) {
+	if planner, ok := m.hub.(CapacityPlanner); ok {
+		planner.ReserveQuizCapacity(quizID, expectedClients)
+	}
+}
+
+// ReleaseQuizCapacity снимает резерв ёмкости викторины
+func (m *Manager) ReleaseQuizCapacity(quizID uint) {
+	if planner, ok := m.hub.(CapacityPlanner); ok {
+		planner.ReleaseQuizCapacity(quizID)
+	}
+}
+
+// SetConnectionHandler подписывает handler на открытие и закрытие соединений, если хаб это умеет
+func (m *Manager) SetConnectionHandler(handler func(event ConnectionEvent)) {
+	if notifier, ok := m.hub.(ConnectionNotifier); ok {
+		notifier.SetConnectionHandler(handler)
+	}
+}
+
+// SetQuizLeaveHandler подписывает handler на отписку клиентов от викторин, если хаб это умеет
+func (m *Manager) SetQuizLeaveHandler(handler func(userID string, quizID uint)) {
+	if notifier, ok := m.hub.(QuizLeaveNotifier); ok {
+		notifier.SetQuizLeaveHandler(handler)
+	}
+}
@@ -0,0 +1,249 @@
+package websocket
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+)
+
+// Версии протокола WebSocket.
+// Клиент указывает версию параметром ?v= при подключении или сообщением client:hello;
+// клиенты, не указавшие версию, считаются клиентами версии 1.
+const (
+	// ProtocolV1 — исходный протокол без согласования версии (старые мобильные клиенты)
+	ProtocolV1 = 1
+	// ProtocolV2 — согласование версии и возможностей (client:hello / server:hello)
+	ProtocolV2 = 2
+
+	// MinProtocolVersion — минимальная поддерживаемая версия
+	MinProtocolVersion = ProtocolV1
+	// CurrentProtocolVersion — версия, в формате которой сервер формирует события
+	CurrentProtocolVersion = ProtocolV2
+)
+
+// Сообщения согласования протокола
+const (
+	// CLIENT_HELLO — клиент сообщает версию протокола и поддерживаемые возможности
+	CLIENT_HELLO = "client:hello"
+	// SERVER_HELLO — ответ сервера с согласованной версией и списком событий
+	SERVER_HELLO = "server:hello"
+)
+
+// ErrUnsupportedProtocolVersion возвращается, если версия клиента ниже минимальной
+var ErrUnsupportedProtocolVersion = errors.New("unsupported protocol version")
+
+// defaultServerEvents — события сервера и версия протокола, в которой каждое появилось.
+// Новое событие регистрируется с текущей версией, чтобы старые клиенты его не получали.
+var defaultServerEvents = map[string]int{
+	"quiz:announcement":      ProtocolV1,
+	"quiz:waiting_room":      ProtocolV1,
+	"quiz:countdown":         ProtocolV1,
+	"quiz:start":             ProtocolV1,
+	"quiz:question":          ProtocolV1,
+	"quiz:timer":             ProtocolV1,
+	"quiz:answer_result":     ProtocolV1,
+	"quiz:elimination":       ProtocolV1,
+	"quiz:user_ready":        ProtocolV1,
+	"quiz:player_count":      ProtocolV1,
+	"quiz:finish":            ProtocolV1,
+	"quiz:results_available": ProtocolV1,
+	"quiz:cancelled":         ProtocolV1,
+	"quiz:error":             ProtocolV1,
+	"quiz:state":             ProtocolV1,
+	"notification:new":       ProtocolV1,
+	"server:heartbeat":       ProtocolV1,
+	"server:time_sync":       ProtocolV1,
+	"server:error":           ProtocolV1,
+	"server:buffer_warning":  ProtocolV1,
+	TOKEN_EXPIRE_SOON:        ProtocolV1,
+	TOKEN_EXPIRED:            ProtocolV1,
+	SERVER_HELLO:             ProtocolV2,
+	TOKEN_EXPIRING:           ProtocolV2,
+	AUTH_REFRESHED:           ProtocolV2,
+	"quiz:question_deadline": ProtocolV2,
+}
+
+// EventSerializer приводит data события из формата версии v+1 к формату версии v,
+// для которой он зарегистрирован. ok=false означает, что клиентам этой версии событие не отправляется.
+type EventSerializer func(data json.RawMessage) (out json.RawMessage, ok bool)
+
+// ServerHello — ответ на согласование протокола
+type ServerHello struct {
+	ProtocolVersion int      `json:"protocol_version"`
+	MinVersion      int      `json:"min_version"`
+	MaxVersion      int      `json:"max_version"`
+	Events          []string `json:"events"`       // события сервера, доступные в согласованной версии
+	Messages        []string `json:"messages"`     // типы сообщений, которые принимает сервер
+	Capabilities    []string `json:"capabilities"` // возможности, включённые для соединения
+}
+
+// NegotiateProtocolVersion выбирает версию протокола для клиента: 0 (не указана) — версия 1,
+// версия новее серверной понижается до текущей, версия ниже минимальной не поддерживается.
+func NegotiateProtocolVersion(requested int) (int, error) {
+	switch {
+	case requested == 0:
+		return ProtocolV1, nil
+	case requested < MinProtocolVersion:
+		return 0, fmt.Errorf("%w: %d (supported %d-%d)", ErrUnsupportedProtocolVersion,
+			requested, MinProtocolVersion, CurrentProtocolVersion)
+	case requested > CurrentProtocolVersion:
+		return CurrentProtocolVersion, nil
+	default:
+		return requested, nil
+	}
+}
+
+// RegisterServerEvent регистрирует событие сервера, появившееся в указанной версии протокола
+func (m *Manager) RegisterServerEvent(eventType string, sinceVersion int) {
+	m.protocolMu.Lock()
+	defer m.protocolMu.Unlock()
+	m.serverEvents[eventType] = sinceVersion
+}
+
+// RegisterSerializer регистрирует преобразование события eventType для клиентов версии version.
+// Преобразования применяются цепочкой от текущей версии вниз до версии клиента.
+func (m *Manager) RegisterSerializer(version int, eventType string, serializer EventSerializer) {
+	m.protocolMu.Lock()
+	defer m.protocolMu.Unlock()
+	if m.serializers[version] == nil {
+		m.serializers[version] = make(map[string]EventSerializer)
+	}
+	m.serializers[version][eventType] = serializer
+	log.Printf("[WebSocketManager] Зарегистрирован сериализатор %s для протокола v%d", eventType, version)
+}
+
+// RegisterCapability объявляет необязательную возможность протокола, которую клиент может запросить
+func (m *Manager) RegisterCapability(name string) {
+	m.protocolMu.Lock()
+	defer m.protocolMu.Unlock()
+	m.capabilities[name] = true
+}
+
+// SetClientProtocol применяет согласованную версию и возможности к соединению.
+// Возможности, неизвестные серверу, отбрасываются.
+func (m *Manager) SetClientProtocol(client *Client, version int, requested []string) {
+	m.protocolMu.RLock()
+	var accepted []string
+	for _, name := range requested {
+		if m.capabilities[name] {
+			accepted = append(accepted, name)
+		}
+	}
+	m.protocolMu.RUnlock()
+
+	var encoder func(*outboundMessage) (*outboundMessage, bool)
+	if version < CurrentProtocolVersion {
+		encoder = func(message *outboundMessage) (*outboundMessage, bool) {
+			return m.encodeForVersion(version, message)
+		}
+	}
+	client.setProtocol(version, accepted, encoder)
+}
+
+// Hello формирует описание протокола для клиента указанной версии
+func (m *Manager) Hello(client *Client) ServerHello {
+	version := client.ProtocolVersion()
+
+	m.protocolMu.RLock()
+	events := make([]string, 0, len(m.serverEvents))
+	for eventType, since := range m.serverEvents {
+		if since <= version {
+			events = append(events, eventType)
+		}
+	}
+	m.protocolMu.RUnlock()
+
+	messages := make([]string, 0, len(m.messageHandler))
+	for eventType := range m.messageHandler {
+		messages = append(messages, eventType)
+	}
+	sort.Strings(events)
+	sort.Strings(messages)
+
+	capabilities := client.Capabilities()
+	if capabilities == nil {
+		capabilities = []string{}
+	}
+	return ServerHello{
+		ProtocolVersion: version,
+		MinVersion:      MinProtocolVersion,
+		MaxVersion:      CurrentProtocolVersion,
+		Events:          events,
+		Messages:        messages,
+		Capabilities:    capabilities,
+	}
+}
+
+// SendHello отправляет клиенту server:hello
+func (m *Manager) SendHello(client *Client) {
+	if err := m.hub.SendJSONToUser(client.UserID, Event{Type: SERVER_HELLO, Data: m.Hello(client)}); err != nil {
+		log.Printf("[WebSocketManager] Ошибка отправки server:hello клиенту %s: %v", client.UserID, err)
+	}
+}
+
+// handleClientHello обрабатывает client:hello: {"version": 2, "capabilities": [...]}
+func (m *Manager) handleClientHello(data json.RawMessage, client *Client) error {
+	var hello struct {
+		Version      int      `json:"version"`
+		Capabilities []string `json:"capabilities"`
+	}
+	if err := json.Unmarshal(data, &hello); err != nil || hello.Version == 0 {
+		m.SendErrorToClient(client, "invalid_format", "client:hello requires a protocol version")
+		return nil
+	}
+
+	version, err := NegotiateProtocolVersion(hello.Version)
+	if err != nil {
+		m.SendErrorToClient(client, "unsupported_protocol_version", err.Error())
+		return err // Клиент не сможет разобрать события сервера — закрываем соединение
+	}
+	m.SetClientProtocol(client, version, hello.Capabilities)
+	log.Printf("[WebSocketManager] Клиент %s согласовал протокол v%d (запрошена v%d)", client.UserID, version, hello.Version)
+	m.SendHello(client)
+	return nil
+}
+
+// encodeForVersion приводит сообщение текущего формата к формату версии version.
+// Неизменённое сообщение возвращается как есть и продолжает разделяться с другими клиентами;
+// сообщения, не являющиеся событиями {type, data}, отправляются без изменений.
+func (m *Manager) encodeForVersion(version int, message *outboundMessage) (*outboundMessage, bool) {
+	var event struct {
+		Type string          `json:"type"`
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(message.json, &event); err != nil || event.Type == "" {
+		return message, true
+	}
+
+	m.protocolMu.RLock()
+	defer m.protocolMu.RUnlock()
+
+	if since, known := m.serverEvents[event.Type]; known && since > version {
+		return nil, false
+	}
+
+	data, changed := event.Data, false
+	for v := CurrentProtocolVersion - 1; v >= version; v-- {
+		serializer := m.serializers[v][event.Type]
+		if serializer == nil {
+			continue
+		}
+		var ok bool
+		if data, ok = serializer(data); !ok {
+			return nil, false
+		}
+		changed = true
+	}
+	if !changed {
+		return message, true
+	}
+
+	encoded, err := json.Marshal(Event{Type: event.Type, Data: data})
+	if err != nil {
+		log.Printf("[WebSocketManager] Ошибка сериализации %s для протокола v%d: %v", event.Type, version, err)
+		return nil, false
+	}
+	return newOutboundMessage(encoded), true
+}
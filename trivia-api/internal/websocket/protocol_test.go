@@ -0,0 +1,83 @@
+package websocket
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiateProtocolVersion(t *testing.T) {
+	version, err := NegotiateProtocolVersion(0)
+	require.NoError(t, err)
+	assert.Equal(t, ProtocolV1, version)
+
+	version, err = NegotiateProtocolVersion(CurrentProtocolVersion + 5)
+	require.NoError(t, err)
+	assert.Equal(t, CurrentProtocolVersion, version)
+
+	_, err = NegotiateProtocolVersion(-1)
+	assert.True(t, errors.Is(err, ErrUnsupportedProtocolVersion))
+}
+
+func TestManager_EncodesEventsForOldProtocol(t *testing.T) {
+	m := NewManager(nil)
+	// В текущей версии поле переименовано: remaining_seconds → remaining_ms
+	m.RegisterSerializer(ProtocolV1, "quiz:timer", func(data json.RawMessage) (json.RawMessage, bool) {
+		var v2 struct {
+			RemainingMs int `json:"remaining_ms"`
+		}
+		if err := json.Unmarshal(data, &v2); err != nil {
+			return nil, false
+		}
+		out, _ := json.Marshal(map[string]int{"remaining_seconds": v2.RemainingMs / 1000})
+		return out, true
+	})
+
+	legacy := NewClient(nil, nil, "1")
+	m.SetClientProtocol(legacy, ProtocolV1, nil)
+	current := NewClient(nil, nil, "2")
+	m.SetClientProtocol(current, CurrentProtocolVersion, nil)
+
+	timer := []byte(`{"type":"quiz:timer","data":{"remaining_ms":5000}}`)
+	out, _, ok := legacy.encodeOutgoing(newOutboundMessage(timer))
+	require.True(t, ok)
+	assert.JSONEq(t, `{"type":"quiz:timer","data":{"remaining_seconds":5}}`, string(out.json))
+
+	shared := newOutboundMessage(timer)
+	out, _, ok = current.encodeOutgoing(shared)
+	require.True(t, ok)
+	assert.Same(t, shared, out)
+
+	// События новых версий старым клиентам не отправляются
+	_, _, ok = legacy.encodeOutgoing(newOutboundMessage([]byte(`{"type":"server:hello","data":{}}`)))
+	assert.False(t, ok)
+
+	// Сообщения без типа передаются как есть
+	raw := newOutboundMessage([]byte(`{"event":"session_revoked"}`))
+	out, _, ok = legacy.encodeOutgoing(raw)
+	require.True(t, ok)
+	assert.Same(t, raw, out)
+}
+
+func TestManager_HelloListsVersionEventsAndCapabilities(t *testing.T) {
+	m := NewManager(nil)
+	m.RegisterCapability("batching")
+	m.RegisterHandler("user:ready", func(json.RawMessage, *Client) error { return nil })
+
+	client := NewClient(nil, nil, "1")
+	m.SetClientProtocol(client, ProtocolV2, []string{"batching", "unknown"})
+
+	hello := m.Hello(client)
+	assert.Equal(t, ProtocolV2, hello.ProtocolVersion)
+	assert.Contains(t, hello.Events, SERVER_HELLO)
+	assert.Contains(t, hello.Events, "quiz:question")
+	assert.Equal(t, []string{CLIENT_HELLO, "user:ready"}, hello.Messages)
+	assert.Equal(t, []string{"batching"}, hello.Capabilities)
+	assert.True(t, client.HasCapability("batching"))
+
+	m.SetClientProtocol(client, ProtocolV1, nil)
+	assert.NotContains(t, m.Hello(client).Events, SERVER_HELLO)
+}
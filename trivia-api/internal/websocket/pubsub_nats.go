@@ -0,0 +1,144 @@
+package websocket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/yourusername/trivia-api/internal/config"
+)
+
+// natsSubscriptionBuffer — размер буфера входящих сообщений одной подписки NATS
+const natsSubscriptionBuffer = 1024
+
+// NATSPubSub реализует PubSubProvider с использованием NATS. Каналы кластера
+// (broadcastChannel, directChannel, metricsChannel) используются как subject'ы NATS.
+type NATSPubSub struct {
+	conn      *nats.Conn
+	ctx       context.Context
+	cancel    context.CancelFunc
+	closeOnce sync.Once
+}
+
+// NewNATSPubSub подключается к серверам NATS из конфигурации и создаёт провайдер
+func NewNATSPubSub(cfg config.NATSConfig) (*NATSPubSub, error) {
+	if strings.TrimSpace(cfg.URL) == "" {
+		return nil, errors.New("nats url cannot be empty for NATSPubSub")
+	}
+
+	name := cfg.Name
+	if name == "" {
+		name = "trivia-api"
+	}
+	opts := []nats.Option{
+		nats.Name(name),
+		nats.MaxReconnects(cfg.MaxReconnects),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil {
+				log.Printf("NATSPubSub: Disconnected: %v", err)
+			}
+		}),
+		nats.ReconnectHandler(func(nc *nats.Conn) {
+			log.Printf("NATSPubSub: Reconnected to %s", nc.ConnectedUrlRedacted())
+		}),
+	}
+	if cfg.ReconnectWait > 0 {
+		opts = append(opts, nats.ReconnectWait(time.Duration(cfg.ReconnectWait)*time.Second))
+	}
+	if cfg.ConnectTimeout > 0 {
+		opts = append(opts, nats.Timeout(time.Duration(cfg.ConnectTimeout)*time.Second))
+	}
+	if cfg.Token != "" {
+		opts = append(opts, nats.Token(cfg.Token))
+	}
+	if cfg.Username != "" {
+		opts = append(opts, nats.UserInfo(cfg.Username, cfg.Password))
+	}
+
+	conn, err := nats.Connect(cfg.URL, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	log.Printf("NATSPubSub provider connected to %s", conn.ConnectedUrlRedacted())
+	return &NATSPubSub{conn: conn, ctx: ctx, cancel: cancel}, nil
+}
+
+// Publish публикует сообщение в subject NATS
+func (p *NATSPubSub) Publish(channel string, message []byte) error {
+	if err := p.conn.Publish(channel, message); err != nil {
+		log.Printf("NATSPubSub: Error publishing to subject '%s': %v", channel, err)
+		return fmt.Errorf("failed to publish to NATS subject %s: %w", channel, err)
+	}
+	return nil
+}
+
+// Subscribe подписывается на subject NATS. В отличие от Redis, каждый вызов создаёт
+// отдельную подписку на сервере, поэтому повторная подписка на тот же канал безопасна.
+func (p *NATSPubSub) Subscribe(ctx context.Context, channel string) (<-chan []byte, error) {
+	natsCh := make(chan *nats.Msg, natsSubscriptionBuffer)
+	sub, err := p.conn.ChanSubscribe(channel, natsCh)
+	if err != nil {
+		log.Printf("NATSPubSub: Error subscribing to subject '%s': %v", channel, err)
+		return nil, fmt.Errorf("failed to subscribe to NATS subject %s: %w", channel, err)
+	}
+	// Подписка должна быть зарегистрирована на сервере до возврата, иначе первые
+	// сообщения, опубликованные сразу после Subscribe, могут быть потеряны
+	if err := p.conn.Flush(); err != nil {
+		_ = sub.Unsubscribe()
+		return nil, fmt.Errorf("failed to confirm NATS subscription %s: %w", channel, err)
+	}
+	log.Printf("NATSPubSub: Successfully subscribed to subject '%s'", channel)
+
+	msgCh := make(chan []byte, 100)
+	go func() {
+		defer func() {
+			if sub.IsValid() {
+				_ = sub.Unsubscribe()
+			}
+			close(msgCh)
+			log.Printf("NATSPubSub: Unsubscribed and closed subject '%s'", channel)
+		}()
+
+		for {
+			select {
+			case msg := <-natsCh:
+				select {
+				case msgCh <- msg.Data:
+				case <-p.ctx.Done():
+					return
+				case <-ctx.Done():
+					return
+				}
+			case <-p.ctx.Done():
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return msgCh, nil
+}
+
+// Close отписывается от всех subject'ов и закрывает соединение, дождавшись
+// отправки уже опубликованных сообщений
+func (p *NATSPubSub) Close() error {
+	var err error
+	p.closeOnce.Do(func() {
+		log.Println("NATSPubSub: Closing NATS connection and all subscriptions...")
+		p.cancel()
+		if err = p.conn.Drain(); err != nil {
+			log.Printf("NATSPubSub: Error draining connection: %v", err)
+			p.conn.Close()
+		}
+		log.Println("NATSPubSub: Closed.")
+	})
+	return err
+}
@@ -0,0 +1,48 @@
+package websocket
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func subscribeTestClient(shard *Shard, quizID uint, userID string) *Client {
+	client := NewClient(nil, nil, userID)
+	quizMap, _ := shard.quizSubscriptions.LoadOrStore(quizID, &sync.Map{})
+	quizMap.(*sync.Map).Store(client, struct{}{})
+	shard.clients.Store(client, true)
+	return client
+}
+
+func TestShardedHub_BroadcastToQuizCountsDelivery(t *testing.T) {
+	pool := NewWorkerPool(2)
+	defer pool.Stop()
+	h := &ShardedHub{
+		shardCount: 2,
+		shards:     []*Shard{NewShard(0, nil, 10, 0, 0, nil), NewShard(1, nil, 10, 0, 0, nil)},
+		workerPool: pool,
+	}
+	var clients []*Client
+	for i := 0; i < 4; i++ {
+		clients = append(clients, subscribeTestClient(h.shards[i%2], 7, fmt.Sprintf("user-%d", i)))
+	}
+	// Соединение закрывается: сообщение ему не доставляется
+	clients[3].CloseSend()
+	// Подписчик другой викторины не учитывается
+	subscribeTestClient(h.shards[0], 8, "user-other")
+
+	delivery := h.BroadcastToQuizCounted(7, []byte(`{"type":"quiz:question","data":{}}`))
+	assert.Equal(t, QuizDelivery{Subscribers: 4, Delivered: 3}, delivery)
+	assert.Equal(t, 1, delivery.Failed())
+	assert.InDelta(t, 0.75, delivery.Ratio(), 0.001)
+	for _, client := range clients[:3] {
+		_, ok := client.send.pop()
+		require.True(t, ok, "client %s: message not queued", client.UserID)
+	}
+
+	assert.Equal(t, QuizDelivery{}, h.BroadcastToQuizCounted(9, []byte(`{"type":"quiz:question","data":{}}`)))
+	assert.Equal(t, 1.0, QuizDelivery{}.Ratio(), "без подписчиков рассылка считается успешной")
+}
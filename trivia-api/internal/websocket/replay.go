@@ -0,0 +1,148 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/yourusername/trivia-api/internal/config"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+)
+
+// replayableEvents — события викторины, которые сохраняются в журнале и повторяются
+// клиенту, переподключившемуся посреди игры
+var replayableEvents = map[string]bool{
+	"quiz:start":              true,
+	"quiz:question":           true,
+	"quiz:answer_reveal":      true,
+	"adaptive:question_stats": true,
+	"quiz:ad_break":           true,
+	"quiz:ad_break_end":       true,
+	"quiz:finish":             true,
+	"quiz:results_available":  true,
+	"quiz:cancelled":          true,
+}
+
+// replayAnchorEvents — с последнего из этих событий начинается повтор для клиента,
+// который не сообщил номер последнего полученного события
+var replayAnchorEvents = map[string]bool{
+	"quiz:start":    true,
+	"quiz:question": true,
+}
+
+// quizEventLog — короткоживущий журнал событий викторины в Redis. Каждое событие
+// получает порядковый номер seq, который передаётся клиентам в поле "seq" сообщения.
+type quizEventLog struct {
+	cache     repository.CacheRepository
+	maxEvents int64
+	ttl       time.Duration
+}
+
+// quizEventSeqTTL — время жизни счётчика номеров событий викторины
+const quizEventSeqTTL = 24 * time.Hour
+
+func newQuizEventLog(cache repository.CacheRepository, cfg config.ReplayConfig) *quizEventLog {
+	maxEvents, ttl := int64(cfg.MaxEvents), time.Duration(cfg.TTLSeconds)*time.Second
+	if maxEvents <= 0 {
+		maxEvents = 50
+	}
+	if ttl <= 0 {
+		ttl = 10 * time.Minute
+	}
+	return &quizEventLog{cache: cache, maxEvents: maxEvents, ttl: ttl}
+}
+
+func quizEventLogKey(quizID uint) string {
+	return fmt.Sprintf("ws:quiz:%d:events", quizID)
+}
+
+func quizEventSeqKey(quizID uint) string {
+	return fmt.Sprintf("ws:quiz:%d:events:seq", quizID)
+}
+
+// record присваивает событию следующий номер и сохраняет его в журнал. При ошибке Redis
+// событие рассылается без номера — повтор для него будет недоступен, но рассылка не страдает.
+func (l *quizEventLog) record(quizID uint, message *outboundMessage) *outboundMessage {
+	seq, err := l.cache.Increment(quizEventSeqKey(quizID))
+	if err != nil {
+		log.Printf("[ReplayLog] Ошибка получения номера события %s викторины %d: %v", message.Type(), quizID, err)
+		return message
+	}
+	// Счётчик живёт дольше журнала: если он обнулится, пока клиенты подключены,
+	// новые события с меньшими номерами будут считаться уже полученными
+	if err := l.cache.Expire(quizEventSeqKey(quizID), quizEventSeqTTL); err != nil {
+		log.Printf("[ReplayLog] Ошибка установки TTL счётчика событий викторины %d: %v", quizID, err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(message.json, &fields); err != nil {
+		return message
+	}
+	fields["seq"] = json.RawMessage(fmt.Sprintf("%d", seq))
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return message
+	}
+
+	if err := l.cache.RPushTrim(quizEventLogKey(quizID), l.maxEvents, l.ttl, data); err != nil {
+		log.Printf("[ReplayLog] Ошибка записи события %s викторины %d в журнал: %v", message.Type(), quizID, err)
+	}
+	recorded := newOutboundMessage(data)
+	recorded.seq = seq
+	return recorded
+}
+
+// since возвращает события журнала с номером больше lastSeq. Если lastSeq не указан (0),
+// возвращаются события начиная с последнего вопроса или старта викторины.
+func (l *quizEventLog) since(quizID uint, lastSeq int64) ([]*outboundMessage, error) {
+	entries, err := l.cache.LRange(quizEventLogKey(quizID), 0, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*outboundMessage, 0, len(entries))
+	anchor := 0
+	for _, entry := range entries {
+		var header struct {
+			Type string `json:"type"`
+			Seq  int64  `json:"seq"`
+		}
+		if err := json.Unmarshal([]byte(entry), &header); err != nil || header.Seq <= lastSeq {
+			continue
+		}
+		if lastSeq == 0 && replayAnchorEvents[header.Type] {
+			anchor = len(events)
+		}
+		message := newOutboundMessage([]byte(entry))
+		message.seq = header.Seq
+		events = append(events, message)
+	}
+	return events[anchor:], nil
+}
+
+// ReplayQuizEvents ставит в очередь клиента события викторины, пропущенные им до подписки.
+// lastSeq — номер последнего события, полученного клиентом (0, если неизвестен).
+// Возвращает количество повторённых событий.
+func (h *ShardedHub) ReplayQuizEvents(client *Client, quizID uint, lastSeq int64) int {
+	if h.replay == nil {
+		return 0
+	}
+	events, err := h.replay.since(quizID, lastSeq)
+	if err != nil {
+		log.Printf("[ShardedHub] Ошибка чтения журнала событий викторины %d: %v", quizID, err)
+		return 0
+	}
+
+	shard := h.getShard(client.UserID)
+	replayed := 0
+	for _, event := range events {
+		if shard.deliver(client, event) {
+			replayed++
+		}
+	}
+	if replayed > 0 {
+		log.Printf("[ShardedHub] Клиенту %s повторено %d событий викторины %d (после seq %d)", client.UserID, replayed, quizID, lastSeq)
+	}
+	return replayed
+}
@@ -0,0 +1,145 @@
+package websocket
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/trivia-api/internal/config"
+	"github.com/yourusername/trivia-api/internal/domain/repository"
+)
+
+// memoryEventCache — в памяти только те операции кеша, которые использует журнал событий
+type memoryEventCache struct {
+	repository.CacheRepository
+	counters map[string]int64
+	lists    map[string][]string
+}
+
+func newMemoryEventCache() *memoryEventCache {
+	return &memoryEventCache{counters: map[string]int64{}, lists: map[string][]string{}}
+}
+
+func (c *memoryEventCache) Increment(key string) (int64, error) {
+	c.counters[key]++
+	return c.counters[key], nil
+}
+
+func (c *memoryEventCache) Expire(string, time.Duration) error { return nil }
+
+func (c *memoryEventCache) RPushTrim(key string, maxLen int64, _ time.Duration, values ...interface{}) error {
+	for _, v := range values {
+		c.lists[key] = append(c.lists[key], string(v.([]byte)))
+	}
+	if extra := int64(len(c.lists[key])) - maxLen; extra > 0 {
+		c.lists[key] = c.lists[key][extra:]
+	}
+	return nil
+}
+
+func (c *memoryEventCache) LRange(key string, _, _ int64) ([]string, error) {
+	return c.lists[key], nil
+}
+
+func newReplayHub(t *testing.T) *ShardedHub {
+	t.Helper()
+	return &ShardedHub{
+		shardCount: 1,
+		shards:     []*Shard{NewShard(0, nil, 10, 0, 0, nil)},
+		replay:     newQuizEventLog(newMemoryEventCache(), config.ReplayConfig{MaxEvents: 4}),
+	}
+}
+
+func recordEvent(h *ShardedHub, eventType string) *outboundMessage {
+	return h.replay.record(7, newOutboundMessage([]byte(`{"type":"`+eventType+`","data":{}}`)))
+}
+
+func queuedSeqs(t *testing.T, client *Client) []int64 {
+	t.Helper()
+	var seqs []int64
+	for {
+		message, ok := client.send.pop()
+		if !ok {
+			return seqs
+		}
+		var header struct {
+			Seq int64 `json:"seq"`
+		}
+		require.NoError(t, json.Unmarshal(message.json, &header))
+		seqs = append(seqs, header.Seq)
+	}
+}
+
+func TestQuizEventLog_RecordAddsSequenceAndTrims(t *testing.T) {
+	h := newReplayHub(t)
+	for _, eventType := range []string{"quiz:start", "quiz:question", "quiz:answer_reveal", "quiz:question", "quiz:answer_reveal"} {
+		recordEvent(h, eventType)
+	}
+
+	recorded := recordEvent(h, "quiz:finish")
+	assert.Equal(t, int64(6), recorded.seq)
+	assert.JSONEq(t, `{"type":"quiz:finish","data":{},"seq":6}`, string(recorded.json))
+
+	// Журнал хранит только последние MaxEvents событий
+	events, err := h.replay.since(7, 1)
+	require.NoError(t, err)
+	require.Len(t, events, 4)
+	assert.Equal(t, int64(3), events[0].seq)
+}
+
+func TestShardedHub_ReplayFromCurrentQuestion(t *testing.T) {
+	h := newReplayHub(t)
+	for _, eventType := range []string{"quiz:question", "quiz:answer_reveal", "quiz:question", "quiz:answer_reveal"} {
+		recordEvent(h, eventType)
+	}
+
+	// Клиент без номера последнего события получает текущий вопрос и всё после него
+	client := NewClient(nil, nil, "1")
+	assert.Equal(t, 2, h.ReplayQuizEvents(client, 7, 0))
+	assert.Equal(t, []int64{3, 4}, queuedSeqs(t, client))
+
+	// Клиент, сообщивший номер, получает только пропущенное
+	client = NewClient(nil, nil, "2")
+	assert.Equal(t, 3, h.ReplayQuizEvents(client, 7, 1))
+	assert.Equal(t, []int64{2, 3, 4}, queuedSeqs(t, client))
+}
+
+func TestShardedHub_BroadcastRecordsReplayableEvents(t *testing.T) {
+	h := newReplayHub(t)
+	h.workerPool = NewWorkerPool(1)
+	defer h.workerPool.Stop()
+	for _, eventType := range []string{"quiz:question", "quiz:answer_reveal", "adaptive:question_stats", "quiz:timer"} {
+		h.BroadcastToQuiz(7, []byte(`{"type":"`+eventType+`","data":{}}`))
+	}
+
+	// Переподключившийся клиент получает вопрос, ответ и статистику вопроса; таймер не повторяется
+	client := NewClient(nil, nil, "1")
+	assert.Equal(t, 3, h.ReplayQuizEvents(client, 7, 0))
+	var types []string
+	for {
+		message, ok := client.send.pop()
+		if !ok {
+			break
+		}
+		types = append(types, message.Type())
+	}
+	assert.Equal(t, []string{"quiz:question", "quiz:answer_reveal", "adaptive:question_stats"}, types)
+}
+
+func TestShardedHub_ReplayDoesNotOvertakeLiveEvents(t *testing.T) {
+	h := newReplayHub(t)
+	recordEvent(h, "quiz:question")
+	live := recordEvent(h, "quiz:answer_reveal")
+
+	// Событие рассылки попало в очередь раньше повтора — повтор не дублирует и не обгоняет его
+	client := NewClient(nil, nil, "1")
+	require.True(t, h.shards[0].deliver(client, live))
+	assert.Equal(t, 0, h.ReplayQuizEvents(client, 7, 0))
+	assert.Equal(t, []int64{2}, queuedSeqs(t, client))
+
+	// При смене викторины номера начинаются заново
+	client.SetQuizID(8)
+	assert.True(t, h.shards[0].deliver(client, live))
+}
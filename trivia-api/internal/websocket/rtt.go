@@ -0,0 +1,117 @@
+package websocket
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// rttSmoothing — вес нового замера в сглаженной оценке RTT (как SRTT в TCP, RFC 6298):
+// один медленный pong не должен резко менять оценку
+const rttSmoothing = 0.125
+
+// rttEstimate — оценка времени кругового пути (RTT) соединения по ping/pong
+type rttEstimate struct {
+	mu       sync.Mutex
+	smoothed time.Duration
+	samples  int
+}
+
+// pingPayload кодирует время отправки ping. Клиент обязан вернуть те же данные в pong
+// (RFC 6455, 5.5.3), поэтому RTT измеряется без участия клиентского кода и его часов.
+func pingPayload(sentAt time.Time) []byte {
+	return strconv.AppendInt(nil, sentAt.UnixNano(), 10)
+}
+
+// parsePingPayload возвращает время отправки ping из данных pong
+func parsePingPayload(data string) (time.Time, bool) {
+	nanos, err := strconv.ParseInt(data, 10, 64)
+	if err != nil || nanos <= 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(0, nanos), true
+}
+
+// handlePongRTT учитывает замер RTT из pong. Замеры вне (0, PongWait] отбрасываются:
+// pong на чужой ping или с изменёнными данными не должен искажать оценку.
+func (c *Client) handlePongRTT(appData string, now time.Time) {
+	sentAt, ok := parsePingPayload(appData)
+	if !ok {
+		return
+	}
+	sample := now.Sub(sentAt)
+	if sample <= 0 || sample > c.config.PongWait {
+		return
+	}
+	c.recordRTT(sample)
+}
+
+// recordRTT добавляет замер RTT в сглаженную оценку
+func (c *Client) recordRTT(sample time.Duration) {
+	c.rtt.mu.Lock()
+	defer c.rtt.mu.Unlock()
+	if c.rtt.samples == 0 {
+		c.rtt.smoothed = sample
+	} else {
+		c.rtt.smoothed += time.Duration(rttSmoothing * float64(sample-c.rtt.smoothed))
+	}
+	c.rtt.samples++
+}
+
+// RTT возвращает сглаженную оценку RTT соединения; false — замеров ещё не было
+func (c *Client) RTT() (time.Duration, bool) {
+	c.rtt.mu.Lock()
+	defer c.rtt.mu.Unlock()
+	return c.rtt.smoothed, c.rtt.samples > 0
+}
+
+// latencyMetrics возвращает медиану и перцентили RTT клиентов шарда в миллисекундах
+func (s *Shard) latencyMetrics() map[string]interface{} {
+	var samples []time.Duration
+	s.clients.Range(func(key, value interface{}) bool {
+		if client, ok := key.(*Client); ok {
+			if rtt, measured := client.RTT(); measured {
+				samples = append(samples, rtt)
+			}
+		}
+		return true
+	})
+	return latencyPercentiles(samples)
+}
+
+// latencyPercentiles считает перцентили по отсортированным замерам (метод ближайшего ранга)
+func latencyPercentiles(samples []time.Duration) map[string]interface{} {
+	metrics := map[string]interface{}{"clients": len(samples)}
+	if len(samples) == 0 {
+		return metrics
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+
+	percentile := func(p float64) float64 {
+		rank := int(math.Ceil(p*float64(len(samples)))) - 1
+		if rank < 0 {
+			rank = 0
+		}
+		return float64(samples[rank]) / float64(time.Millisecond)
+	}
+	metrics["p50"] = percentile(0.50)
+	metrics["p90"] = percentile(0.90)
+	metrics["p99"] = percentile(0.99)
+	metrics["max"] = float64(samples[len(samples)-1]) / float64(time.Millisecond)
+	return metrics
+}
+
+// ClientRTT возвращает оценку RTT подключённого к этому узлу пользователя
+func (h *ShardedHub) ClientRTT(userID string) (time.Duration, bool) {
+	value, ok := h.getShard(userID).userMap.Load(userID)
+	if !ok {
+		return 0, false
+	}
+	client, ok := value.(*Client)
+	if !ok {
+		return 0, false
+	}
+	return client.RTT()
+}
@@ -0,0 +1,47 @@
+package websocket
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_PongUpdatesSmoothedRTT(t *testing.T) {
+	client := NewClient(nil, nil, "1")
+	_, measured := client.RTT()
+	assert.False(t, measured)
+
+	sentAt := time.Now()
+	client.handlePongRTT(string(pingPayload(sentAt)), sentAt.Add(80*time.Millisecond))
+	rtt, measured := client.RTT()
+	require.True(t, measured)
+	assert.Equal(t, 80*time.Millisecond, rtt)
+
+	// Одиночный выброс сдвигает оценку на 1/8 разницы
+	client.handlePongRTT(string(pingPayload(sentAt)), sentAt.Add(880*time.Millisecond))
+	rtt, _ = client.RTT()
+	assert.Equal(t, 180*time.Millisecond, rtt)
+
+	// Pong без метки времени или с меткой из будущего игнорируется
+	client.handlePongRTT("", sentAt)
+	client.handlePongRTT(string(pingPayload(sentAt.Add(time.Second))), sentAt)
+	rtt, _ = client.RTT()
+	assert.Equal(t, 180*time.Millisecond, rtt)
+}
+
+func TestLatencyPercentiles(t *testing.T) {
+	assert.Equal(t, map[string]interface{}{"clients": 0}, latencyPercentiles(nil))
+
+	samples := make([]time.Duration, 0, 100)
+	for i := 100; i >= 1; i-- {
+		samples = append(samples, time.Duration(i)*time.Millisecond)
+	}
+	metrics := latencyPercentiles(samples)
+	assert.Equal(t, 100, metrics["clients"])
+	assert.Equal(t, 50.0, metrics["p50"])
+	assert.Equal(t, 90.0, metrics["p90"])
+	assert.Equal(t, 99.0, metrics["p99"])
+	assert.Equal(t, 100.0, metrics["max"])
+}
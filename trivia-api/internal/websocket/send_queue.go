@@ -0,0 +1,267 @@
+package websocket
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// highPriorityEvents — события, которые отправляются раньше остальных и не вытесняются
+// при переполнении очереди: игрок должен узнать о выбывании и результатах,
+// даже если не успевает получать вопросы и таймер
+var highPriorityEvents = map[string]bool{
+	"quiz:answer_result":     true,
+	"quiz:elimination":       true,
+	"quiz:finish":            true,
+	"quiz:results_available": true,
+	"quiz:cancelled":         true,
+	TOKEN_EXPIRED:            true,
+	TOKEN_EXPIRING:           true,
+}
+
+// supersededEvents — для события перечислены типы ещё не отправленных событий,
+// которые оно делает устаревшими: новый вопрос отменяет предыдущий вопрос и его таймер,
+// новое значение таймера или счётчика — предыдущее
+var supersededEvents = map[string][]string{
+	"quiz:question":     {"quiz:question", "quiz:timer"},
+	"quiz:timer":        {"quiz:timer"},
+	"quiz:countdown":    {"quiz:countdown"},
+	"quiz:player_count": {"quiz:player_count"},
+}
+
+// enqueueResult — результат постановки сообщения в очередь отправки клиента
+type enqueueResult int
+
+const (
+	// enqueueOK — сообщение поставлено в очередь
+	enqueueOK enqueueResult = iota
+	// enqueueEvicted — сообщение поставлено, но ради него вытеснено самое старое обычное сообщение
+	enqueueEvicted
+	// enqueueOverflow — очередь заполнена важными сообщениями, клиент не успевает их получать
+	enqueueOverflow
+	// enqueueClosed — очередь закрыта, клиент отключается
+	enqueueClosed
+	// enqueueSkipped — событие журнала викторины уже было поставлено клиенту
+	enqueueSkipped
+)
+
+// sendQueue — очередь исходящих сообщений клиента с приоритетами и схлопыванием
+// устаревших событий. Заменяет буферизованный канал: при переполнении теряются
+// самые старые обычные сообщения, а клиент узнаёт, сколько сообщений пропустил.
+type sendQueue struct {
+	mu       sync.Mutex
+	high     messageRing
+	normal   messageRing
+	capacity int
+	closed   bool
+
+	// ready получает сигнал, когда в очереди появились сообщения или она закрыта
+	ready chan struct{}
+
+	dropped        int64 // вытеснено с момента последнего уведомления клиента
+	droppedTotal   int64
+	coalescedTotal int64
+}
+
+// sendQueueStats — состояние очереди для метрик шарда
+type sendQueueStats struct {
+	Depth     int
+	Dropped   int64
+	Coalesced int64
+}
+
+// bufferWarning — уведомление клиенту о сообщениях, потерянных из-за переполнения очереди
+func bufferWarning(dropped int64) *outboundMessage {
+	data, _ := json.Marshal(Event{
+		Type: "server:buffer_warning",
+		Data: map[string]interface{}{
+			"dropped_messages": dropped,
+			"message":          "Your connection is slow, some messages were skipped.",
+		},
+	})
+	return newOutboundMessage(data)
+}
+
+func newSendQueue(capacity int) *sendQueue {
+	return &sendQueue{
+		capacity: capacity,
+		ready:    make(chan struct{}, 1),
+	}
+}
+
+// push ставит сообщение в очередь. Неотправленные события, которые сообщение делает
+// устаревшими, удаляются; при переполнении вытесняется самое старое обычное сообщение.
+func (q *sendQueue) push(message *outboundMessage) enqueueResult {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return enqueueClosed
+	}
+
+	msgType := message.Type()
+	if stale := supersededEvents[msgType]; len(stale) > 0 {
+		q.coalescedTotal += int64(q.normal.removeFunc(func(queued *outboundMessage) bool {
+			queuedType := queued.Type()
+			for _, t := range stale {
+				if queuedType == t {
+					return true
+				}
+			}
+			return false
+		}))
+	}
+
+	result := enqueueOK
+	if q.high.size+q.normal.size >= q.capacity {
+		if q.normal.size == 0 {
+			return enqueueOverflow
+		}
+		q.normal.pop()
+		q.dropped++
+		q.droppedTotal++
+		result = enqueueEvicted
+	}
+
+	if highPriorityEvents[msgType] {
+		q.high.push(message, q.capacity)
+	} else {
+		q.normal.push(message, q.capacity)
+	}
+	q.signal()
+	return result
+}
+
+// pop возвращает следующее сообщение: сначала важные, затем обычные в порядке поступления
+func (q *sendQueue) pop() (*outboundMessage, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if message, ok := q.high.pop(); ok {
+		return message, true
+	}
+	return q.normal.pop()
+}
+
+// takeDropped возвращает количество вытесненных сообщений с прошлого вызова и сбрасывает его
+func (q *sendQueue) takeDropped() int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	dropped := q.dropped
+	q.dropped = 0
+	return dropped
+}
+
+// close закрывает очередь; уже поставленные сообщения ещё могут быть отправлены.
+// Возвращает true, если очередь закрыта этим вызовом.
+func (q *sendQueue) close() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return false
+	}
+	q.closed = true
+	q.signal()
+	return true
+}
+
+func (q *sendQueue) isClosed() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.closed
+}
+
+// drained сообщает, что очередь закрыта и все сообщения из неё забраны
+func (q *sendQueue) drained() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.closed && q.high.size+q.normal.size == 0
+}
+
+// Len возвращает количество сообщений, ожидающих отправки
+func (q *sendQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.high.size + q.normal.size
+}
+
+func (q *sendQueue) stats() sendQueueStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return sendQueueStats{
+		Depth:     q.high.size + q.normal.size,
+		Dropped:   q.droppedTotal,
+		Coalesced: q.coalescedTotal,
+	}
+}
+
+// signal будит writePump
+func (q *sendQueue) signal() {
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// messageRing — кольцевой буфер сообщений. Растёт по мере необходимости до ёмкости очереди,
+// чтобы десятки тысяч простаивающих клиентов не держали полные буферы.
+type messageRing struct {
+	buf  []*outboundMessage
+	head int
+	size int
+}
+
+const minRingSize = 8
+
+func (r *messageRing) push(message *outboundMessage, limit int) {
+	if r.size == len(r.buf) {
+		r.grow(limit)
+	}
+	r.buf[(r.head+r.size)%len(r.buf)] = message
+	r.size++
+}
+
+func (r *messageRing) pop() (*outboundMessage, bool) {
+	if r.size == 0 {
+		return nil, false
+	}
+	message := r.buf[r.head]
+	r.buf[r.head] = nil
+	r.head = (r.head + 1) % len(r.buf)
+	r.size--
+	return message, true
+}
+
+// removeFunc удаляет сообщения, для которых match вернул true, сохраняя порядок остальных
+func (r *messageRing) removeFunc(match func(*outboundMessage) bool) int {
+	kept := 0
+	for i := 0; i < r.size; i++ {
+		message := r.buf[(r.head+i)%len(r.buf)]
+		if match(message) {
+			continue
+		}
+		r.buf[(r.head+kept)%len(r.buf)] = message
+		kept++
+	}
+	for i := kept; i < r.size; i++ {
+		r.buf[(r.head+i)%len(r.buf)] = nil
+	}
+	removed := r.size - kept
+	r.size = kept
+	return removed
+}
+
+func (r *messageRing) grow(limit int) {
+	size := len(r.buf) * 2
+	if size < minRingSize {
+		size = minRingSize
+	}
+	if size > limit {
+		size = limit
+	}
+	buf := make([]*outboundMessage, size)
+	for i := 0; i < r.size; i++ {
+		buf[i] = r.buf[(r.head+i)%len(r.buf)]
+	}
+	r.buf = buf
+	r.head = 0
+}
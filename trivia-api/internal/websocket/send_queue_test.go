@@ -0,0 +1,89 @@
+package websocket
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func event(eventType string, n int) *outboundMessage {
+	return newOutboundMessage([]byte(fmt.Sprintf(`{"type":%q,"data":{"n":%d}}`, eventType, n)))
+}
+
+func drainTypes(q *sendQueue) []string {
+	var types []string
+	for {
+		message, ok := q.pop()
+		if !ok {
+			return types
+		}
+		types = append(types, fmt.Sprintf("%s#%s", message.Type(), string(message.json[len(message.json)-3:len(message.json)-2])))
+	}
+}
+
+func TestSendQueue_CoalescesStaleQuestionEvents(t *testing.T) {
+	q := newSendQueue(16)
+	q.push(event("quiz:question", 1))
+	q.push(event("quiz:timer", 1))
+	q.push(event("quiz:player_count", 1))
+	q.push(event("quiz:timer", 2))
+	q.push(event("quiz:question", 2))
+	q.push(event("quiz:player_count", 2))
+
+	assert.Equal(t, []string{"quiz:question#2", "quiz:player_count#2"}, drainTypes(q))
+	assert.Equal(t, int64(4), q.stats().Coalesced)
+}
+
+func TestSendQueue_PrioritizesEliminationAndResults(t *testing.T) {
+	q := newSendQueue(16)
+	q.push(event("quiz:player_count", 1))
+	q.push(event("quiz:elimination", 1))
+	q.push(event("quiz:user_ready", 1))
+	q.push(event("quiz:results_available", 1))
+
+	assert.Equal(t, []string{"quiz:elimination#1", "quiz:results_available#1", "quiz:player_count#1", "quiz:user_ready#1"}, drainTypes(q))
+}
+
+func TestSendQueue_EvictsOldestNormalMessageAndReportsDrops(t *testing.T) {
+	q := newSendQueue(3)
+	for i := 1; i <= 3; i++ {
+		require.Equal(t, enqueueOK, q.push(event("quiz:user_ready", i)))
+	}
+	assert.Equal(t, enqueueEvicted, q.push(event("quiz:elimination", 1)))
+	assert.Equal(t, enqueueEvicted, q.push(event("quiz:user_ready", 4)))
+
+	assert.Equal(t, int64(2), q.takeDropped())
+	assert.Equal(t, int64(0), q.takeDropped())
+	assert.Equal(t, []string{"quiz:elimination#1", "quiz:user_ready#3", "quiz:user_ready#4"}, drainTypes(q))
+	assert.Equal(t, int64(2), q.stats().Dropped)
+}
+
+func TestSendQueue_OverflowWhenFullOfImportantMessages(t *testing.T) {
+	q := newSendQueue(2)
+	q.push(event("quiz:answer_result", 1))
+	q.push(event("quiz:elimination", 1))
+
+	assert.Equal(t, enqueueOverflow, q.push(event("quiz:finish", 1)))
+	assert.Equal(t, enqueueOverflow, q.push(event("quiz:timer", 1)))
+	assert.Equal(t, 2, q.Len())
+}
+
+func TestSendQueue_RingWrapsAndCloseDrains(t *testing.T) {
+	q := newSendQueue(10)
+	for i := 0; i < 50; i++ {
+		q.push(event("quiz:user_ready", i%10))
+		if i%3 == 0 {
+			q.pop()
+		}
+	}
+	assert.Equal(t, 10, q.Len())
+
+	assert.True(t, q.close())
+	assert.False(t, q.close())
+	assert.Equal(t, enqueueClosed, q.push(event("quiz:user_ready", 1)))
+	assert.False(t, q.drained())
+	drainTypes(q)
+	assert.True(t, q.drained())
+}
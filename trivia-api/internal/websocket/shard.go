@@ -4,26 +4,31 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/yourusername/trivia-api/internal/domain/repository"
 )
 
+// maxLaggingClientsReported — сколько клиентов с самой длинной очередью отправки попадает в метрики шарда
+const maxLaggingClientsReported = 10
+
 // Shard представляет подмножество клиентов Hub
 // Каждый шард обрабатывает свою группу клиентов независимо,
 // что значительно улучшает производительность при большом числе соединений
 type Shard struct {
-	id         int           // Уникальный ID шарда
-	clients    sync.Map      // Ключ: *Client, Значение: bool (или struct{})
-	userMap    sync.Map      // Карта UserID -> *Client
-	broadcast  chan []byte   // Канал для широковещательных сообщений шарда
-	register   chan *Client  // Канал для регистрации клиентов в шарде
-	unregister chan *Client  // Канал для отмены регистрации клиентов из шарда
-	done       chan struct{} // Сигнал для завершения работы шарда
-	metrics    *ShardMetrics // Метрики производительности шарда
-	parent     interface{}   // Ссылка на родительский хаб (ShardedHub)
-	maxClients int           // Максимальное рекомендуемое количество клиентов в шарде
+	id         int                   // Уникальный ID шарда
+	clients    sync.Map              // Ключ: *Client, Значение: bool (или struct{})
+	userMap    sync.Map              // Карта UserID -> *Client
+	broadcast  chan *outboundMessage // Канал для широковещательных сообщений шарда
+	register   chan *Client          // Канал для регистрации клиентов в шарде
+	unregister chan *Client          // Канал для отмены регистрации клиентов из шарда
+	done       chan struct{}         // Сигнал для завершения работы шарда
+	metrics    *ShardMetrics         // Метрики производительности шарда
+	parent     interface{}           // Ссылка на родительский хаб (ShardedHub)
+	maxClients atomic.Int64          // Рекомендуемое количество клиентов в шарде; повышается под ожидаемую викторину
 
 	// Настройки для очистки
 	cleanupInterval   time.Duration
@@ -57,7 +62,7 @@ func NewShard(id int, parent interface{}, maxClients int, cleanupInterval time.D
 
 	shard := &Shard{
 		id:         id,
-		broadcast:  make(chan []byte, 256),
+		broadcast:  make(chan *outboundMessage, 256),
 		register:   make(chan *Client, 100),
 		unregister: make(chan *Client, 100),
 		done:       make(chan struct{}),
@@ -65,14 +70,15 @@ func NewShard(id int, parent interface{}, maxClients int, cleanupInterval time.D
 			id:              id,
 			lastCleanupTime: time.Now(),
 		},
-		parent:     parent,
-		maxClients: maxClients,
+		parent: parent,
 		// Сохраняем настройки очистки и репозиторий кэша
 		cleanupInterval:   cleanupInterval,
 		inactivityTimeout: inactivityTimeout,
 		cacheRepo:         cacheRepo, // Сохраняем репозиторий кэша
 	}
 
+	shard.maxClients.Store(int64(maxClients))
+
 	// Запускаем горутину для периодической очистки
 	go shard.runCleanupTicker()
 
@@ -120,6 +126,8 @@ func (s *Shard) handleRegister(client *Client) {
 				s.metrics.mu.Lock()
 				s.metrics.activeConnections--
 				s.metrics.mu.Unlock()
+
+				s.notifyClosed(oldClient, oldClient.GetQuizID(), true)
 			}()
 		}
 	}
@@ -127,6 +135,7 @@ func (s *Shard) handleRegister(client *Client) {
 	// Регистрируем нового клиента
 	s.clients.Store(client, true)
 	client.UpdateLastActivity() // FIX: thread-safe обновление времени активности
+	client.connectedAt = time.Now()
 
 	// Обновляем метрики
 	s.metrics.mu.Lock()
@@ -134,6 +143,14 @@ func (s *Shard) handleRegister(client *Client) {
 	s.metrics.mu.Unlock()
 
 	log.Printf("Shard %d: client %s registered", s.id, client.UserID)
+	if hub, ok := s.parent.(*ShardedHub); ok {
+		hub.notifyConnection(ConnectionEvent{
+			Type:         ConnectionOpened,
+			UserID:       client.UserID,
+			ConnectionID: client.ConnectionID,
+			At:           client.connectedAt,
+		})
+	}
 
 	// Сигнал о завершении регистрации
 	if client.registrationComplete != nil {
@@ -150,6 +167,7 @@ func (s *Shard) handleUnregister(client *Client) {
 	log.Printf("[Shard %d][User %s][Conn %s] handleUnregister called", s.id, client.UserID, client.ConnectionID)
 
 	// Отписываем клиента от викторины перед удалением
+	quizID := client.GetQuizID()
 	s.UnsubscribeFromQuiz(client)
 
 	if _, ok := s.clients.LoadAndDelete(client); ok {
@@ -173,25 +191,37 @@ func (s *Shard) handleUnregister(client *Client) {
 		s.metrics.mu.Unlock()
 
 		log.Printf("Shard %d: client %s unregistered", s.id, client.UserID)
+		s.notifyClosed(client, quizID, false)
 	}
 }
 
+// notifyClosed сообщает хабу о закрытии соединения клиента
+func (s *Shard) notifyClosed(client *Client, quizID uint, replaced bool) {
+	hub, ok := s.parent.(*ShardedHub)
+	if !ok {
+		return
+	}
+	now := time.Now()
+	event := ConnectionEvent{
+		Type:         ConnectionClosed,
+		UserID:       client.UserID,
+		ConnectionID: client.ConnectionID,
+		QuizID:       quizID,
+		At:           now,
+		Replaced:     replaced,
+	}
+	if !client.connectedAt.IsZero() {
+		event.Duration = now.Sub(client.connectedAt)
+	}
+	hub.notifyConnection(event)
+}
+
 // handleBroadcast отправляет сообщение всем клиентам в шарде
-func (s *Shard) handleBroadcast(message []byte) {
+func (s *Shard) handleBroadcast(message *outboundMessage) {
 	var clientCount int
 
 	// Проверяем, есть ли в сообщении тип для фильтрации по подпискам
-	var messageType string
-	if len(message) > 2 { // Минимальная длина для JSON с полем type
-		// Пытаемся распарсить JSON, чтобы получить тип сообщения
-		var event struct {
-			Type string `json:"type"`
-		}
-		// Используем UnmarshalJSON, который не модифицирует исходное сообщение
-		if err := json.Unmarshal(message, &event); err == nil {
-			messageType = event.Type
-		}
-	}
+	messageType := message.Type()
 
 	// Флаг для проверки, является ли сообщение системным (отправляется всем)
 	isSystemMessage := messageType == "system" || messageType == TOKEN_EXPIRED
@@ -209,63 +239,8 @@ func (s *Shard) handleBroadcast(message []byte) {
 			return true // Клиент не подписан, пропускаем
 		}
 
-		clientCount++
-		select {
-		case client.send <- message:
-			// Сообщение успешно отправлено в буфер клиента
-			// При успешной отправке сбрасываем счетчик предупреждений
-			client.resetBufferWarningCount()
-		default:
-			// Буфер клиента переполнен
-			log.Printf("[Shard %d] Client %s (Conn: %s) buffer full during broadcast. Current warning count: %d", s.id, client.UserID, client.ConnectionID, client.getBufferWarningCount())
-
-			// Увеличиваем счетчик и проверяем порог
-			newCount := client.incrementBufferWarningCount()
-
-			if newCount >= maxBufferWarnings {
-				log.Printf("[Shard %d] Client %s (Conn: %s) exceeded max buffer warnings (%d). Unregistering.", s.id, client.UserID, client.ConnectionID, maxBufferWarnings)
-				// Отключаем клиента, если превышен порог
-				s.clients.Delete(client)
-
-				if existingClient, loaded := s.userMap.Load(client.UserID); loaded && existingClient == client {
-					s.userMap.Delete(client.UserID)
-				}
-
-				// Отписываем от викторины перед закрытием
-				s.UnsubscribeFromQuiz(client)
-
-				if client.conn != nil {
-					client.conn.Close()
-				}
-				client.CloseSend() // Безопасное закрытие канала
-
-				// Обновляем метрики
-				s.metrics.mu.Lock()
-				if s.metrics.activeConnections > 0 {
-					s.metrics.activeConnections--
-				}
-				s.metrics.connectionErrors++
-				s.metrics.mu.Unlock()
-			} else {
-				// Отправляем предупреждение клиенту
-				log.Printf("[Shard %d] Sending buffer warning %d/%d to client %s (Conn: %s)", s.id, newCount, maxBufferWarnings, client.UserID, client.ConnectionID)
-				warningMsg := map[string]interface{}{
-					"type": "server:buffer_warning",
-					"data": map[string]interface{}{
-						"warning_count": newCount,
-						"max_warnings":  maxBufferWarnings,
-						"message":       "Your connection is slow or buffer is full. You may be disconnected soon.",
-					},
-				}
-				jsonWarning, _ := json.Marshal(warningMsg)
-				// Попытка отправить предупреждение неблокирующим способом
-				// Если и это не удается, ничего страшного, основная логика - счетчик
-				select {
-				case client.send <- jsonWarning:
-				default:
-					log.Printf("[Shard %d] Failed to send buffer warning message to client %s (Conn: %s) - buffer still full.", s.id, client.UserID, client.ConnectionID)
-				}
-			}
+		if s.deliver(client, message) {
+			clientCount++
 		}
 		return true
 	})
@@ -386,6 +361,7 @@ func (s *Shard) unsubscribeInternal(client *Client, quizID uint) {
 		if hub, ok := s.parent.(*ShardedHub); ok {
 			// Используем горутину чтобы не блокировать unsubscribe
 			go hub.BroadcastPlayerCountUpdate(quizID)
+			hub.notifyQuizLeave(client.UserID, quizID)
 		}
 
 		// Опционально: можно удалить карту викторины из s.quizSubscriptions, если она стала пустой
@@ -399,14 +375,25 @@ func (s *Shard) unsubscribeInternal(client *Client, quizID uint) {
 // BroadcastToQuiz отправляет сообщение только тем клиентам шарда,
 // которые подписаны на указанную викторину.
 func (s *Shard) BroadcastToQuiz(quizID uint, message []byte) {
-	// НОВЫЙ ЛОГ
-	log.Printf("[Shard %d][Quiz %d] BroadcastToQuiz called. Message type: %s", s.id, quizID, messageTypeFromBytes(message))
+	s.broadcastToQuiz(quizID, newOutboundMessage(message))
+}
+
+// broadcastToQuiz ставит одно и то же сообщение в очереди всех подписчиков викторины
+// и возвращает, скольким из них оно доставлено
+func (s *Shard) broadcastToQuiz(quizID uint, message *outboundMessage) QuizDelivery {
+	// Подробные логи по каждому клиенту только в режиме отладки: при десятках тысяч
+	// подписчиков они занимают больше времени, чем сама рассылка
+	verbose := debugLogging.Load()
+	if verbose {
+		log.Printf("[Shard %d][Quiz %d] BroadcastToQuiz called. Message type: %s", s.id, quizID, message.Type())
+	}
 	clientCount := 0
+	var delivery QuizDelivery
 	if quizMapUntyped, ok := s.quizSubscriptions.Load(quizID); ok {
 		quizMap, ok := quizMapUntyped.(*sync.Map)
 		if !ok {
 			log.Printf("CRITICAL: Shard %d: Invalid type stored in quizSubscriptions for quiz %d during broadcast", s.id, quizID)
-			return
+			return delivery
 		}
 		quizMap.Range(func(key, value interface{}) bool {
 			client, ok := key.(*Client)
@@ -415,43 +402,17 @@ func (s *Shard) BroadcastToQuiz(quizID uint, message []byte) {
 				return true // Пропускаем некорректные записи
 			}
 
-			// НОВЫЙ ЛОГ
-			log.Printf("[Shard %d][Quiz %d][Range] Iterating over client: User %s, Conn %s", s.id, quizID, client.UserID, client.ConnectionID)
-
-			// Перед select
-			log.Printf("[Shard %d][Quiz %d][User %s][Conn %s] Attempting to queue message type: %s", s.id, quizID, client.UserID, client.ConnectionID, messageTypeFromBytes(message))
-
-			select {
-			case client.send <- message:
+			delivery.Subscribers++
+			switch s.deliverResult(client, message) {
+			case enqueueOK, enqueueEvicted:
 				clientCount++
-				log.Printf("[Shard %d][Quiz %d][User %s][Conn %s] Successfully queued message type: %s. Buffer len: %d", s.id, quizID, client.UserID, client.ConnectionID, messageTypeFromBytes(message), len(client.send))
-			default:
-				// Буфер клиента переполнен, отключаем клиента (копипаста из handleBroadcast)
-				// Добавляем лог перед существующим логом об ошибке
-				log.Printf("[Shard %d][Quiz %d][User %s][Conn %s] FAILED to queue message type: %s (BUFFER FULL/CLOSED). Buffer len: %d. Initiating unregister.", s.id, quizID, client.UserID, client.ConnectionID, messageTypeFromBytes(message), len(client.send))
-				log.Printf("Shard %d: client %s buffer full during quiz broadcast, unregistering", s.id, client.UserID)
-				s.clients.Delete(client)
-				quizMap.Delete(client) // Удаляем из карты викторины
-
-				if existingClient, loaded := s.userMap.Load(client.UserID); loaded && existingClient == client {
-					s.userMap.Delete(client.UserID)
-				}
-
-				if client.conn != nil {
-					client.conn.Close()
-				}
-				// Не закрываем client.send здесь, он закроется в handleUnregister
-				// Вызываем handleUnregister асинхронно, чтобы не блокировать рассылку
-				// handleUnregister сам отпишет от викторины, но мы уже удалили из quizMap
-				go s.handleUnregister(client)
-
-				// Обновляем метрики
-				s.metrics.mu.Lock()
-				if s.metrics.activeConnections > 0 { // Предотвращаем отрицательные значения
-					s.metrics.activeConnections--
+				delivery.Delivered++
+				if verbose {
+					log.Printf("[Shard %d][Quiz %d][User %s][Conn %s] Queued message type: %s. Queue len: %d", s.id, quizID, client.UserID, client.ConnectionID, message.Type(), client.send.Len())
 				}
-				s.metrics.connectionErrors++
-				s.metrics.mu.Unlock()
+			case enqueueSkipped:
+				// Событие уже поставлено клиенту повтором после переподключения
+				delivery.Delivered++
 			}
 			return true
 		})
@@ -467,6 +428,7 @@ func (s *Shard) BroadcastToQuiz(quizID uint, message []byte) {
 		// Можно добавить лог, если для викторины нет подписчиков в этом шарде
 		// log.Printf("Shard %d: No clients found for Quiz %d broadcast", s.id, quizID)
 	}
+	return delivery
 }
 
 // runCleanupTicker запускает тикер для периодической очистки
@@ -529,6 +491,16 @@ func (s *Shard) cleanupInactiveClients(timeout time.Duration) {
 	// Фактическое обновление метрик произойдет в handleUnregister
 }
 
+// forEachClient вызывает fn для каждого клиента шарда
+func (s *Shard) forEachClient(fn func(client *Client)) {
+	s.clients.Range(func(key, value interface{}) bool {
+		if client, ok := key.(*Client); ok {
+			fn(client)
+		}
+		return true
+	})
+}
+
 // cleanupAllClients закрывает все соединения перед остановкой шарда
 func (s *Shard) cleanupAllClients() {
 	s.clients.Range(func(key, value interface{}) bool {
@@ -561,72 +533,57 @@ func (s *Shard) SendToUser(userID string, message []byte) bool {
 		return false
 	}
 
-	select {
-	case client.send <- message:
-		// Обновляем метрики
-		s.metrics.mu.Lock()
-		s.metrics.messagesSent++
-		s.metrics.mu.Unlock()
-		// Сбрасываем счетчик при успешной прямой отправке
-		client.resetBufferWarningCount()
-		return true
-	default:
-		// Буфер клиента переполнен
-		log.Printf("[Shard %d] Client %s (Conn: %s) buffer full on direct message. Current warning count: %d", s.id, userID, client.ConnectionID, client.getBufferWarningCount())
-
-		newCount := client.incrementBufferWarningCount()
+	if !s.deliver(client, newOutboundMessage(message)) {
+		return false
+	}
+	s.metrics.mu.Lock()
+	s.metrics.messagesSent++
+	s.metrics.mu.Unlock()
+	return true
+}
 
-		if newCount >= maxBufferWarnings {
-			log.Printf("[Shard %d] Client %s (Conn: %s) exceeded max buffer warnings (%d) on direct message. Unregistering.", s.id, client.UserID, client.ConnectionID, maxBufferWarnings)
-			// Отключаем клиента, если превышен порог
-			s.clients.Delete(client)
+// deliver ставит сообщение в очередь клиента. Переполненная очередь теряет самые старые
+// обычные сообщения; если она заполнена важными сообщениями, клиент не успевает
+// их получать и отключается. Возвращает true, если сообщение поставлено в очередь.
+func (s *Shard) deliver(client *Client, message *outboundMessage) bool {
+	result := s.deliverResult(client, message)
+	return result == enqueueOK || result == enqueueEvicted
+}
 
-			if existingClient, loaded := s.userMap.Load(client.UserID); loaded && existingClient == client {
-				s.userMap.Delete(client.UserID)
-			}
+// deliverResult ставит сообщение в очередь клиента, как deliver, и возвращает результат постановки
+func (s *Shard) deliverResult(client *Client, message *outboundMessage) enqueueResult {
+	result := client.enqueue(message)
+	switch result {
+	case enqueueEvicted:
+		if debugLogging.Load() {
+			log.Printf("[Shard %d] Client %s (Conn: %s) send queue full, oldest message dropped", s.id, client.UserID, client.ConnectionID)
+		}
+	case enqueueOverflow:
+		s.dropSlowClient(client, message)
+	}
+	return result
+}
 
-			// Отписываем от викторины перед закрытием
-			s.UnsubscribeFromQuiz(client)
+// dropSlowClient отключает клиента, очередь которого заполнена важными сообщениями
+func (s *Shard) dropSlowClient(client *Client, message *outboundMessage) {
+	log.Printf("[Shard %d] Client %s (Conn: %s) send queue overflow on %s (%d queued). Unregistering.",
+		s.id, client.UserID, client.ConnectionID, message.Type(), client.send.Len())
 
-			if client.conn != nil {
-				client.conn.Close()
-			}
-			client.CloseSend() // Безопасное закрытие канала
+	s.metrics.mu.Lock()
+	s.metrics.connectionErrors++
+	s.metrics.mu.Unlock()
 
-			// Обновляем метрики
-			s.metrics.mu.Lock()
-			if s.metrics.activeConnections > 0 {
-				s.metrics.activeConnections--
-			}
-			s.metrics.connectionErrors++
-			s.metrics.mu.Unlock()
-			return false // Сообщение не доставлено, клиент отключен
-		} else {
-			// Отправляем предупреждение
-			log.Printf("[Shard %d] Sending buffer warning %d/%d to client %s (Conn: %s) on direct message", s.id, newCount, maxBufferWarnings, client.UserID, client.ConnectionID)
-			warningMsg := map[string]interface{}{
-				"type": "server:buffer_warning",
-				"data": map[string]interface{}{
-					"warning_count": newCount,
-					"max_warnings":  maxBufferWarnings,
-					"message":       "Your connection is slow or buffer is full. You may be disconnected soon.",
-				},
-			}
-			jsonWarning, _ := json.Marshal(warningMsg)
-			select {
-			case client.send <- jsonWarning:
-			default:
-				log.Printf("[Shard %d] Failed to send buffer warning message to client %s (Conn: %s) - buffer still full.", s.id, client.UserID, client.ConnectionID)
-			}
-			return false // Сообщение не доставлено, но клиент пока не отключен
-		}
+	if client.conn != nil {
+		client.conn.Close()
 	}
+	// Вызываем handleUnregister асинхронно, чтобы не блокировать рассылку
+	go s.handleUnregister(client)
 }
 
 // BroadcastBytes рассылает байтовое сообщение всем клиентам в шарде
 func (s *Shard) BroadcastBytes(message []byte) {
 	select {
-	case s.broadcast <- message:
+	case s.broadcast <- newOutboundMessage(message):
 		// Сообщение успешно отправлено в канал рассылки
 	default:
 		log.Printf("Shard %d: broadcast channel full, message dropped", s.id)
@@ -650,18 +607,69 @@ func (s *Shard) GetMetrics() map[string]interface{} {
 	defer s.metrics.mu.RUnlock()
 
 	clientCount := s.GetClientCount()
-	loadPercentage := float64(clientCount) / float64(s.maxClients) * 100
+	maxClients := s.maxClients.Load()
+	loadPercentage := float64(clientCount) / float64(maxClients) * 100
+	queues := s.queueMetrics()
 
 	return map[string]interface{}{
 		"shard_id":           s.id,
 		"active_connections": clientCount,
-		"max_clients":        s.maxClients,
+		"max_clients":        maxClients,
 		"messages_sent":      s.metrics.messagesSent,
 		"messages_received":  s.metrics.messagesReceived,
 		"connection_errors":  s.metrics.connectionErrors,
 		"load_percentage":    loadPercentage,
 		"last_cleanup":       s.metrics.lastCleanupTime.Format(time.RFC3339),
 		"inactive_removed":   s.metrics.inactiveClientsRemoved,
+		"send_queues":        queues,
+		"rtt_ms":             s.latencyMetrics(),
+	}
+}
+
+// queueMetrics собирает состояние очередей отправки клиентов шарда: суммарную
+// и максимальную глубину, потерянные и схлопнутые сообщения, а также самых отстающих клиентов
+func (s *Shard) queueMetrics() map[string]interface{} {
+	var (
+		totalDepth, maxDepth int
+		dropped, coalesced   int64
+		lagging              []map[string]interface{}
+	)
+	s.clients.Range(func(key, value interface{}) bool {
+		client, ok := key.(*Client)
+		if !ok {
+			return true
+		}
+		stats := client.send.stats()
+		totalDepth += stats.Depth
+		if stats.Depth > maxDepth {
+			maxDepth = stats.Depth
+		}
+		dropped += stats.Dropped
+		coalesced += stats.Coalesced
+		if stats.Depth > 0 {
+			lagging = append(lagging, map[string]interface{}{
+				"user_id":       client.UserID,
+				"connection_id": client.ConnectionID,
+				"queue_depth":   stats.Depth,
+				"dropped":       stats.Dropped,
+			})
+		}
+		return true
+	})
+
+	sort.Slice(lagging, func(i, j int) bool {
+		return lagging[i]["queue_depth"].(int) > lagging[j]["queue_depth"].(int)
+	})
+	if len(lagging) > maxLaggingClientsReported {
+		lagging = lagging[:maxLaggingClientsReported]
+	}
+
+	return map[string]interface{}{
+		"total_depth":        totalDepth,
+		"max_depth":          maxDepth,
+		"messages_dropped":   dropped,
+		"messages_coalesced": coalesced,
+		"lagging_clients":    lagging,
 	}
 }
 
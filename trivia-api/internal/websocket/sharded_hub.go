@@ -139,15 +139,31 @@ type ShardedHub struct {
 	// Функция для обработки алертов (может быть заменена пользователем)
 	alertHandler func(AlertMessage)
 
-	// Мьютекс для безопасной работы с alertHandler
+	// Мьютекс для безопасной работы с alertHandler, quizLeaveHandler и connectionHandler
 	alertMu sync.RWMutex
 
+	// Вызывается, когда клиент отписывается от викторины (см. SetQuizLeaveHandler)
+	quizLeaveHandler func(userID string, quizID uint)
+
+	// Вызывается при открытии и закрытии соединений (см. SetConnectionHandler)
+	connectionHandler func(event ConnectionEvent)
+
 	// Добавляем хранилище для информации о других узлах кластера
 	clusterPeers sync.Map // Ключ: InstanceID, Значение: map[string]interface{} (распарсенные метрики)
 
 	// Добавляем зависимость для проверки Redis
 	cacheRepo repository.CacheRepository
 
+	// Журнал событий викторин для переподключившихся клиентов (nil — повтор отключён)
+	replay *quizEventLog
+
+	// Потоки событий викторин только для чтения (SSE); nil — потоки отключены
+	streams *quizStreams
+
+	// Ожидаемое число клиентов запланированных викторин (quizID -> клиенты), см. ReserveQuizCapacity
+	reservations   map[uint]int
+	reservationsMu sync.Mutex
+
 	// Мьютекс для защиты доступа к срезу shards
 	shardsMu sync.RWMutex
 }
@@ -167,6 +183,9 @@ const (
 
 	// AlertHighLatency сигнализирует о высокой задержке обработки сообщений
 	AlertHighLatency AlertType = "high_latency"
+
+	// AlertCapacityForecast предупреждает, что ожидаемая аудитория викторины превышает ёмкость шардов
+	AlertCapacityForecast AlertType = "capacity_forecast"
 )
 
 // AlertSeverity определяет уровень серьезности алерта
@@ -243,11 +262,19 @@ func NewShardedHub(wsConfig config.WebSocketConfig, provider PubSubProvider, cac
 		workerPool:         workerPool,
 		alertChan:          make(chan AlertMessage, 1000),
 		cacheRepo:          cacheRepo,
+		reservations:       make(map[uint]int),
 	}
 
 	// Инициализируем обработчик алертов по умолчанию
 	hub.alertHandler = hub.defaultAlertHandler
 
+	if wsConfig.Replay.Enabled && cacheRepo != nil {
+		hub.replay = newQuizEventLog(cacheRepo, wsConfig.Replay)
+	}
+	if wsConfig.SSE.Enabled {
+		hub.streams = newQuizStreams(wsConfig.SSE.BufferSize)
+	}
+
 	// Создаем шарды
 	hub.shards = make([]*Shard, shardCount)
 	for i := 0; i < shardCount; i++ {
@@ -302,6 +329,41 @@ func (h *ShardedHub) SetAlertHandler(handler func(AlertMessage)) {
 	h.alertHandler = handler
 }
 
+// SetQuizLeaveHandler устанавливает обработчик отписки клиента от викторины
+// (отключение, выход или переход в другую викторину)
+func (h *ShardedHub) SetQuizLeaveHandler(handler func(userID string, quizID uint)) {
+	h.alertMu.Lock()
+	defer h.alertMu.Unlock()
+	h.quizLeaveHandler = handler
+}
+
+// notifyQuizLeave сообщает обработчику об отписке клиента, не блокируя шард
+func (h *ShardedHub) notifyQuizLeave(userID string, quizID uint) {
+	h.alertMu.RLock()
+	handler := h.quizLeaveHandler
+	h.alertMu.RUnlock()
+	if handler != nil {
+		go handler(userID, quizID)
+	}
+}
+
+// SetConnectionHandler устанавливает обработчик открытия и закрытия соединений
+func (h *ShardedHub) SetConnectionHandler(handler func(event ConnectionEvent)) {
+	h.alertMu.Lock()
+	defer h.alertMu.Unlock()
+	h.connectionHandler = handler
+}
+
+// notifyConnection сообщает обработчику о соединении, не блокируя шард
+func (h *ShardedHub) notifyConnection(event ConnectionEvent) {
+	h.alertMu.RLock()
+	handler := h.connectionHandler
+	h.alertMu.RUnlock()
+	if handler != nil {
+		go handler(event)
+	}
+}
+
 // SendAlert отправляет алерт
 func (h *ShardedHub) SendAlert(alertType AlertType, severity AlertSeverity, message string, metadata map[string]interface{}) {
 	alert := AlertMessage{
@@ -418,6 +480,8 @@ func (h *ShardedHub) BroadcastBytes(message []byte) {
 // BroadcastBytesLocal отправляет байтовое сообщение всем локальным шардам через worker pool.
 // Этот метод используется для внутренней локальной рассылки.
 func (h *ShardedHub) BroadcastBytesLocal(message []byte) {
+	// Одно сообщение на все шарды: каждый формат кодируется один раз на рассылку
+	frame := newOutboundMessage(message)
 	// Используем пул воркеров для асинхронной отправки сообщения каждому шарду
 	for _, shard := range h.shards {
 		// Захватываем переменную shard для замыкания
@@ -425,7 +489,7 @@ func (h *ShardedHub) BroadcastBytesLocal(message []byte) {
 		success := h.workerPool.Submit(func() {
 			// Отправляем сообщение в канал broadcast конкретного шарда
 			// Shard.Run() обработает это сообщение и разошлет клиентам
-			currentShard.broadcast <- message
+			currentShard.broadcast <- frame
 		})
 		if !success {
 			// КРИТИЧЕСКАЯ ОШИБКА: Пул воркеров переполнен, broadcast сообщение не может быть доставлено шарду.
@@ -490,28 +554,51 @@ func (h *ShardedHub) SendJSONToUser(userID string, v interface{}) error {
 
 // BroadcastToQuiz отправляет сообщение всем клиентам указанной викторины во всех шардах.
 func (h *ShardedHub) BroadcastToQuiz(quizID uint, message []byte) {
+	h.BroadcastToQuizCounted(quizID, message)
+}
+
+// BroadcastToQuizCounted отправляет сообщение всем клиентам викторины, как BroadcastToQuiz,
+// и возвращает, скольким подписчикам этого инстанса оно доставлено.
+func (h *ShardedHub) BroadcastToQuizCounted(quizID uint, message []byte) QuizDelivery {
 	log.Printf("ShardedHub: Broadcasting message to Quiz %d across all shards", quizID)
+	// Одно сообщение на все шарды: каждый формат кодируется один раз на рассылку
+	frame := newOutboundMessage(message)
+	if h.replay != nil && replayableEvents[frame.Type()] {
+		frame = h.replay.record(quizID, frame)
+	}
+	if h.streams != nil {
+		h.streams.publish(quizID, frame)
+	}
 	// Используем пул воркеров для параллельной рассылки по шардам
 	var wg sync.WaitGroup
 	wg.Add(h.shardCount)
+	// Итог каждого шарда пишется в свою ячейку, поэтому блокировка не нужна
+	results := make([]QuizDelivery, len(h.shards))
 
-	for _, shard := range h.shards {
+	for i, shard := range h.shards {
 		// Запускаем рассылку для каждого шарда в отдельной горутине из пула
 		currentShard := shard // Захватываем переменную для горутины
+		slot := i
 		success := h.workerPool.Submit(func() {
 			defer wg.Done()
-			currentShard.BroadcastToQuiz(quizID, message)
+			results[slot] = currentShard.broadcastToQuiz(quizID, frame)
 		})
 		if !success {
 			// Если пул переполнен, выполняем синхронно и логируем
 			log.Printf("ShardedHub: Worker pool full, broadcasting to quiz %d in shard %d synchronously", quizID, currentShard.id)
 			wg.Done() // Уменьшаем счетчик, так как горутина не будет запущена
-			currentShard.BroadcastToQuiz(quizID, message)
+			results[slot] = currentShard.broadcastToQuiz(quizID, frame)
 		}
 	}
 
 	wg.Wait() // Ожидаем завершения рассылки по всем шардам
-	log.Printf("ShardedHub: Finished broadcasting to Quiz %d", quizID)
+	var delivery QuizDelivery
+	for _, result := range results {
+		delivery.Subscribers += result.Subscribers
+		delivery.Delivered += result.Delivered
+	}
+	log.Printf("ShardedHub: Finished broadcasting to Quiz %d (delivered %d of %d)", quizID, delivery.Delivered, delivery.Subscribers)
+	return delivery
 }
 
 // ClientCount возвращает общее количество подключенных клиентов
@@ -524,6 +611,13 @@ func (h *ShardedHub) ClientCount() int {
 	return count
 }
 
+// ForEachClient вызывает fn для каждого клиента, подключенного к этому инстансу
+func (h *ShardedHub) ForEachClient(fn func(client *Client)) {
+	for _, shard := range h.shards {
+		shard.forEachClient(fn)
+	}
+}
+
 // GetMetrics возвращает основные метрики хаба
 func (h *ShardedHub) GetMetrics() map[string]interface{} {
 	return h.metrics.GetBasicMetrics()
@@ -550,6 +644,9 @@ func (h *ShardedHub) GetDetailedMetrics() map[string]interface{} {
 
 	// Перезаписываем active_connections актуальным значением из шардов
 	allMetrics["active_connections"] = totalActiveConnections
+	if h.streams != nil {
+		allMetrics["event_streams"] = h.streams.count()
+	}
 
 	// Добавляем информацию о пирах кластера
 	peerMetrics := make(map[string]interface{})
@@ -692,86 +789,6 @@ func (h *ShardedHub) Close() {
 	log.Println("ShardedHub: все ресурсы освобождены")
 }
 
-// BroadcastPrioritized отправляет высокоприоритетное сообщение всем клиентам
-// с дополнительными гарантиями доставки
-func (h *ShardedHub) BroadcastPrioritized(message []byte) error {
-	log.Printf("ShardedHub: рассылка высокоприоритетного сообщения")
-
-	// Создаем WaitGroup для ожидания завершения отправки во все шарды
-	var wg sync.WaitGroup
-	wg.Add(len(h.shards))
-
-	// Увеличенные буферы для высокоприоритетных сообщений
-	// чтобы гарантировать, что они не будут отброшены
-	for _, shard := range h.shards {
-		// Используем пул воркеров для распределения нагрузки
-		currentShard := shard // Создаем локальную копию для замыкания
-		if !h.workerPool.Submit(func() {
-			defer wg.Done()
-
-			// Для высокоприоритетных сообщений блокируем отправку,
-			// чтобы гарантировать доставку
-			select {
-			case currentShard.broadcast <- message:
-				// Сообщение успешно отправлено в канал рассылки
-			case <-time.After(100 * time.Millisecond): // Уменьшаем таймаут
-				// Если канал полный, обрабатываем сообщение напрямую
-				log.Printf("Shard %d: приоритетная отправка через прямую рассылку (канал broadcast переполнен)", currentShard.id)
-
-				// Подсчитываем клиентов для метрик
-				var clientCount int
-
-				// Выполняем прямую рассылку клиентам
-				currentShard.clients.Range(func(key, value interface{}) bool {
-					client, ok := key.(*Client)
-					if !ok {
-						return true // Пропускаем некорректные записи
-					}
-
-					// Блокирующая отправка с таймаутом
-					select {
-					case client.send <- message:
-						clientCount++
-					case <-time.After(100 * time.Millisecond): // Уменьшаем таймаут
-						// Если буфер клиента переполнен и не освобождается, обрабатываем ошибку
-						log.Printf("Shard %d: не удалось отправить приоритетное сообщение клиенту %s (таймаут отправки)",
-							currentShard.id, client.UserID)
-					}
-
-					return true
-				})
-
-				// Обновляем метрики
-				if clientCount > 0 {
-					currentShard.metrics.mu.Lock()
-					currentShard.metrics.messagesSent += int64(clientCount)
-					currentShard.metrics.mu.Unlock()
-
-					log.Printf("Shard %d: приоритетное сообщение отправлено %d клиентам напрямую",
-						currentShard.id, clientCount)
-				}
-			}
-		}) {
-			// Если пул воркеров переполнен, выполняем задачу напрямую
-			log.Printf("ShardedHub: пул воркеров переполнен, выполняем задачу напрямую для шарда %d", shard.id)
-			go func(s *Shard) {
-				defer wg.Done()
-				s.BroadcastBytes(message)
-			}(shard)
-		}
-	}
-
-	// Ожидаем завершения отправки во все шарды
-	wg.Wait()
-
-	// Если включен кластерный режим, отправляем сообщение в другие экземпляры
-	if h.cluster != nil {
-		go h.cluster.BroadcastToCluster(message)
-	}
-
-	return nil
-}
-
 // handleAlerts обрабатывает алерты
 func (h *ShardedHub) handleAlerts() {
 	for {
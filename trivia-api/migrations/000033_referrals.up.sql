@@ -0,0 +1,25 @@
+-- Referral program: per-user invite codes and referral tracking
+CREATE TABLE IF NOT EXISTS referral_codes (
+  id SERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+  code VARCHAR(16) NOT NULL UNIQUE,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS referrals (
+  id SERIAL PRIMARY KEY,
+  referrer_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  referee_id INTEGER NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+  code VARCHAR(16) NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'pending',
+  reject_reason VARCHAR(50) NOT NULL DEFAULT '',
+  referee_ip VARCHAR(50) NOT NULL DEFAULT '',
+  referee_device_id VARCHAR(255) NOT NULL DEFAULT '',
+  bonus_points INTEGER NOT NULL DEFAULT 0,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  credited_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_referrals_referrer ON referrals(referrer_id);
+CREATE INDEX IF NOT EXISTS idx_referrals_status ON referrals(status);
+CREATE INDEX IF NOT EXISTS idx_referrals_referee_ip ON referrals(referee_ip);
@@ -0,0 +1,22 @@
+-- Push notifications: device tokens (FCM/APNs) and per-user notification preferences
+CREATE TABLE IF NOT EXISTS push_devices (
+  id SERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  platform VARCHAR(10) NOT NULL,
+  provider VARCHAR(10) NOT NULL,
+  token VARCHAR(512) NOT NULL UNIQUE,
+  device_id VARCHAR(255) NOT NULL DEFAULT '',
+  language VARCHAR(5) NOT NULL DEFAULT 'ru',
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_push_devices_user ON push_devices(user_id);
+
+CREATE TABLE IF NOT EXISTS notification_preferences (
+  user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  quiz_reminders BOOLEAN NOT NULL DEFAULT TRUE,
+  winner_announcements BOOLEAN NOT NULL DEFAULT TRUE,
+  security_alerts BOOLEAN NOT NULL DEFAULT TRUE,
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,6 @@
+ALTER TABLE notification_preferences
+  DROP COLUMN IF EXISTS websocket_enabled,
+  DROP COLUMN IF EXISTS push_enabled,
+  DROP COLUMN IF EXISTS email_enabled;
+
+DROP TABLE IF EXISTS notifications;
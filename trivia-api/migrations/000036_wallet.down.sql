@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS payout_requests;
+DROP TABLE IF EXISTS ledger_entries;
+DROP TABLE IF EXISTS ledger_transactions;
+DROP TABLE IF EXISTS wallet_accounts;
@@ -0,0 +1,92 @@
+-- Wallet: double-entry ledger for prize credits and payouts.
+-- Every ledger transaction has entries that sum to zero; account balances are
+-- a materialized sum of their entries, so SUM(balance) over all accounts is always 0.
+CREATE TABLE IF NOT EXISTS wallet_accounts (
+  id SERIAL PRIMARY KEY,
+  user_id INTEGER NULL REFERENCES users(id) ON DELETE RESTRICT,
+  type VARCHAR(20) NOT NULL,
+  code VARCHAR(50) NULL UNIQUE,
+  balance BIGINT NOT NULL DEFAULT 0,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  CONSTRAINT chk_wallet_accounts_owner CHECK (
+    (type = 'user' AND user_id IS NOT NULL AND code IS NULL) OR
+    (type = 'system' AND user_id IS NULL AND code IS NOT NULL)
+  ),
+  CONSTRAINT chk_wallet_accounts_user_balance CHECK (type <> 'user' OR balance >= 0)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_wallet_accounts_user ON wallet_accounts(user_id) WHERE user_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS ledger_transactions (
+  id SERIAL PRIMARY KEY,
+  type VARCHAR(30) NOT NULL,
+  reference VARCHAR(100) NOT NULL,
+  description VARCHAR(255) NOT NULL DEFAULT '',
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  CONSTRAINT uq_ledger_transactions_type_reference UNIQUE (type, reference)
+);
+
+CREATE TABLE IF NOT EXISTS ledger_entries (
+  id SERIAL PRIMARY KEY,
+  transaction_id INTEGER NOT NULL REFERENCES ledger_transactions(id) ON DELETE RESTRICT,
+  account_id INTEGER NOT NULL REFERENCES wallet_accounts(id) ON DELETE RESTRICT,
+  amount BIGINT NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  CONSTRAINT chk_ledger_entries_amount CHECK (amount <> 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_account ON ledger_entries(account_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_transaction ON ledger_entries(transaction_id);
+
+CREATE TABLE IF NOT EXISTS payout_requests (
+  id SERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+  amount BIGINT NOT NULL CHECK (amount > 0),
+  status VARCHAR(20) NOT NULL DEFAULT 'requested',
+  method VARCHAR(30) NOT NULL,
+  destination VARCHAR(255) NOT NULL,
+  admin_id INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+  reject_reason VARCHAR(255) NOT NULL DEFAULT '',
+  external_reference VARCHAR(255) NOT NULL DEFAULT '',
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  approved_at TIMESTAMP NULL,
+  paid_at TIMESTAMP NULL,
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_payout_requests_user ON payout_requests(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_payout_requests_status ON payout_requests(status, created_at);
+
+-- System accounts
+INSERT INTO wallet_accounts (type, code) VALUES
+  ('system', 'prize_expense'),
+  ('system', 'payouts_pending'),
+  ('system', 'payouts_settled')
+ON CONFLICT (code) DO NOTHING;
+
+-- Opening balances from prizes won before the wallet existed
+INSERT INTO wallet_accounts (user_id, type, balance)
+SELECT id, 'user', total_prize_won FROM users WHERE total_prize_won > 0
+ON CONFLICT DO NOTHING;
+
+INSERT INTO ledger_transactions (type, reference, description)
+SELECT 'opening_balance', 'user:' || user_id, 'Opening balance from total_prize_won'
+FROM wallet_accounts WHERE type = 'user' AND balance > 0
+ON CONFLICT (type, reference) DO NOTHING;
+
+INSERT INTO ledger_entries (transaction_id, account_id, amount)
+SELECT t.id, a.id, a.balance
+FROM ledger_transactions t
+JOIN wallet_accounts a ON a.type = 'user' AND t.reference = 'user:' || a.user_id
+WHERE t.type = 'opening_balance';
+
+INSERT INTO ledger_entries (transaction_id, account_id, amount)
+SELECT t.id, (SELECT id FROM wallet_accounts WHERE code = 'prize_expense'), -a.balance
+FROM ledger_transactions t
+JOIN wallet_accounts a ON a.type = 'user' AND t.reference = 'user:' || a.user_id
+WHERE t.type = 'opening_balance';
+
+UPDATE wallet_accounts
+SET balance = -(SELECT COALESCE(SUM(balance), 0) FROM wallet_accounts WHERE type = 'user')
+WHERE code = 'prize_expense';
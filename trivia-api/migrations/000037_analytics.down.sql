@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS ad_impressions;
+DROP TABLE IF EXISTS ws_connection_samples;
+DROP INDEX IF EXISTS idx_refresh_tokens_created_at;
+DROP INDEX IF EXISTS idx_results_created_at;
+DROP INDEX IF EXISTS idx_users_created_at;
@@ -0,0 +1,28 @@
+-- Admin analytics: indexes for date-range rollups, WS connection samples and ad impressions
+CREATE INDEX IF NOT EXISTS idx_users_created_at ON users(created_at);
+CREATE INDEX IF NOT EXISTS idx_results_created_at ON results(created_at);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_created_at ON refresh_tokens(created_at);
+
+-- Per-instance samples of connected WebSocket clients (one row per instance per sampling interval)
+CREATE TABLE IF NOT EXISTS ws_connection_samples (
+  id BIGSERIAL PRIMARY KEY,
+  instance_id VARCHAR(100) NOT NULL,
+  connections INTEGER NOT NULL,
+  sampled_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ws_connection_samples_sampled_at ON ws_connection_samples(sampled_at);
+
+-- One row per ad break shown; viewers is the number of quiz subscribers at the time of the break.
+-- References are nullable so impression history survives deletion of the ad asset or quiz.
+CREATE TABLE IF NOT EXISTS ad_impressions (
+  id BIGSERIAL PRIMARY KEY,
+  ad_asset_id INTEGER NULL REFERENCES ad_assets(id) ON DELETE SET NULL,
+  quiz_id INTEGER NULL REFERENCES quizzes(id) ON DELETE SET NULL,
+  question_after INTEGER NOT NULL,
+  viewers INTEGER NOT NULL DEFAULT 0,
+  shown_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ad_impressions_shown_at ON ad_impressions(shown_at);
+CREATE INDEX IF NOT EXISTS idx_ad_impressions_asset ON ad_impressions(ad_asset_id);
@@ -0,0 +1,10 @@
+BEGIN;
+
+ALTER TABLE quiz_ad_slots DROP COLUMN IF EXISTS duration_sec;
+
+ALTER TABLE ad_assets DROP COLUMN IF EXISTS height;
+ALTER TABLE ad_assets DROP COLUMN IF EXISTS width;
+ALTER TABLE ad_assets DROP COLUMN IF EXISTS video_duration_ms;
+ALTER TABLE ad_assets DROP COLUMN IF EXISTS mime_type;
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+-- Метаданные видео-рекламы: MIME-тип и фактическая длительность ролика
+ALTER TABLE ad_assets ADD COLUMN IF NOT EXISTS mime_type VARCHAR(64);
+ALTER TABLE ad_assets ADD COLUMN IF NOT EXISTS video_duration_ms INT NOT NULL DEFAULT 0 CHECK (video_duration_ms >= 0);
+ALTER TABLE ad_assets ADD COLUMN IF NOT EXISTS width INT;
+ALTER TABLE ad_assets ADD COLUMN IF NOT EXISTS height INT;
+
+-- Переопределение длительности показа для конкретного слота (NULL — длительность ресурса)
+ALTER TABLE quiz_ad_slots ADD COLUMN IF NOT EXISTS duration_sec INT CHECK (duration_sec IS NULL OR (duration_sec >= 3 AND duration_sec <= 60));
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE ad_assets DROP COLUMN IF EXISTS thumbnail_storage_key;
+ALTER TABLE ad_assets DROP COLUMN IF EXISTS storage_key;
+
+COMMIT;
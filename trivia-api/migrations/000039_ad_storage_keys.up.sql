@@ -0,0 +1,14 @@
+BEGIN;
+
+-- Ключи объектов в хранилище загрузок (локальный диск или S3)
+ALTER TABLE ad_assets ADD COLUMN IF NOT EXISTS storage_key VARCHAR(512);
+ALTER TABLE ad_assets ADD COLUMN IF NOT EXISTS thumbnail_storage_key VARCHAR(512);
+
+-- Существующие файлы лежат в ./uploads/ads и раздаются по /uploads/ads/<имя>
+UPDATE ad_assets SET storage_key = 'ads/' || regexp_replace(url, '^.*/', '')
+WHERE storage_key IS NULL AND url LIKE '/uploads/ads/%';
+
+UPDATE ad_assets SET thumbnail_storage_key = 'ads/' || regexp_replace(thumbnail_url, '^.*/', '')
+WHERE thumbnail_storage_key IS NULL AND thumbnail_url LIKE '/uploads/ads/%';
+
+COMMIT;
@@ -0,0 +1,2 @@
+-- Ключ загруженного аватара в хранилище (пусто — аватар задан внешним URL или отсутствует)
+ALTER TABLE users ADD COLUMN IF NOT EXISTS avatar_storage_key VARCHAR(255) NOT NULL DEFAULT '';
@@ -0,0 +1,25 @@
+-- Question translations keyed by locale. questions.text/options stay in the default locale.
+CREATE TABLE IF NOT EXISTS question_translations (
+  id SERIAL PRIMARY KEY,
+  question_id INTEGER NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+  locale VARCHAR(5) NOT NULL,
+  text VARCHAR(500) NOT NULL,
+  options JSONB NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_question_translations_question_locale
+  ON question_translations(question_id, locale);
+
+-- Backfill Kazakh texts. text_kk/options_kk are kept for older clients.
+INSERT INTO question_translations (question_id, locale, text, options)
+SELECT id, 'kk', text_kk,
+  CASE
+    WHEN jsonb_typeof(options_kk) = 'array' AND jsonb_array_length(options_kk) = jsonb_array_length(options)
+      THEN options_kk
+    ELSE options
+  END
+FROM questions
+WHERE text_kk IS NOT NULL AND text_kk <> ''
+ON CONFLICT (question_id, locale) DO NOTHING;
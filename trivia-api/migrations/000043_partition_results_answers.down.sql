@@ -0,0 +1,68 @@
+-- Возврат к несекционированным user_answers и results. Архивные секции (схема archive) не возвращаются.
+DROP TRIGGER IF EXISTS trg_quizzes_ensure_partitions ON quizzes;
+DROP FUNCTION IF EXISTS quizzes_ensure_partitions();
+
+ALTER TABLE user_answers RENAME TO user_answers_partitioned;
+ALTER TABLE results RENAME TO results_partitioned;
+ALTER TABLE user_answers_partitioned RENAME CONSTRAINT user_answers_pkey TO user_answers_partitioned_pkey;
+ALTER TABLE results_partitioned RENAME CONSTRAINT results_pkey TO results_partitioned_pkey;
+
+CREATE TABLE user_answers (
+  id INTEGER NOT NULL DEFAULT nextval('user_answers_id_seq') PRIMARY KEY,
+  user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  quiz_id BIGINT NOT NULL REFERENCES quizzes (id) ON DELETE CASCADE,
+  question_id BIGINT NOT NULL REFERENCES questions (id) ON DELETE CASCADE,
+  selected_option BIGINT NOT NULL,
+  is_correct BOOLEAN NOT NULL,
+  response_time_ms BIGINT NOT NULL,
+  score BIGINT NOT NULL DEFAULT 0,
+  created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+  is_eliminated BOOLEAN NOT NULL DEFAULT FALSE,
+  elimination_reason TEXT
+);
+
+CREATE TABLE results (
+  id INTEGER NOT NULL DEFAULT nextval('results_id_seq') PRIMARY KEY,
+  user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  quiz_id BIGINT NOT NULL REFERENCES quizzes (id) ON DELETE CASCADE,
+  username VARCHAR(50) NOT NULL,
+  profile_picture VARCHAR(255) NOT NULL DEFAULT '',
+  score BIGINT NOT NULL,
+  correct_answers BIGINT NOT NULL DEFAULT 0,
+  total_questions BIGINT NOT NULL DEFAULT 0,
+  rank BIGINT NOT NULL DEFAULT 0,
+  completed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+  is_winner BOOLEAN NOT NULL DEFAULT FALSE,
+  prize_fund BIGINT NOT NULL DEFAULT 0,
+  is_eliminated BOOLEAN NOT NULL DEFAULT FALSE,
+  updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+  eliminated_on_question INTEGER DEFAULT NULL,
+  elimination_reason VARCHAR(50) DEFAULT NULL
+);
+
+INSERT INTO user_answers SELECT id, user_id, quiz_id, question_id, selected_option, is_correct, response_time_ms,
+       score, created_at, is_eliminated, elimination_reason
+FROM user_answers_partitioned;
+
+INSERT INTO results SELECT id, user_id, quiz_id, username, profile_picture, score, correct_answers, total_questions,
+       rank, completed_at, created_at, is_winner, prize_fund, is_eliminated, updated_at,
+       eliminated_on_question, elimination_reason
+FROM results_partitioned;
+
+ALTER SEQUENCE user_answers_id_seq OWNED BY user_answers.id;
+ALTER SEQUENCE results_id_seq OWNED BY results.id;
+
+DROP TABLE user_answers_partitioned;
+DROP TABLE results_partitioned;
+DROP FUNCTION IF EXISTS ensure_quiz_partitions(BIGINT);
+
+CREATE INDEX idx_user_answers_user_id ON user_answers (user_id);
+CREATE INDEX idx_user_answers_quiz_id ON user_answers (quiz_id);
+CREATE INDEX idx_user_answers_question_id ON user_answers (question_id);
+CREATE UNIQUE INDEX uidx_user_answers_user_quiz_question ON user_answers (user_id, quiz_id, question_id);
+
+ALTER TABLE results ADD CONSTRAINT results_user_id_quiz_id_key UNIQUE (user_id, quiz_id);
+CREATE INDEX idx_results_quiz_id ON results (quiz_id);
+CREATE INDEX idx_results_user_id ON results (user_id);
+CREATE INDEX idx_results_created_at ON results (created_at);
@@ -0,0 +1,118 @@
+-- Секционирование user_answers и results по quiz_id (RANGE, 100 викторин на секцию).
+-- quiz_id входит во все уникальные индексы, поэтому (user_id, quiz_id, question_id) и (user_id, quiz_id)
+-- остаются уникальными, а запросы с quiz_id читают одну секцию.
+-- Секции называются <таблица>_p<нижняя граница, 10 цифр>, например user_answers_p0000000100.
+
+-- Схема для отсоединённых (архивных) секций
+CREATE SCHEMA IF NOT EXISTS archive;
+
+-- ensure_quiz_partitions создаёт секции user_answers и results для диапазона, в который входит p_quiz_id
+CREATE OR REPLACE FUNCTION ensure_quiz_partitions(p_quiz_id BIGINT) RETURNS VOID AS $$
+DECLARE
+  width CONSTANT BIGINT := 100;
+  lower_bound BIGINT := (p_quiz_id / width) * width;
+  tbl TEXT;
+BEGIN
+  -- Сериализуем создание секций между параллельными транзакциями
+  PERFORM pg_advisory_xact_lock(hashtext('ensure_quiz_partitions'));
+  FOREACH tbl IN ARRAY ARRAY['user_answers', 'results'] LOOP
+    EXECUTE format(
+      'CREATE TABLE IF NOT EXISTS %I PARTITION OF %I FOR VALUES FROM (%s) TO (%s)',
+      tbl || '_p' || lpad(lower_bound::TEXT, 10, '0'), tbl, lower_bound, lower_bound + width
+    );
+  END LOOP;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Переносим данные в секционированные таблицы. Последовательности id переходят к новым таблицам.
+ALTER TABLE user_answers RENAME TO user_answers_legacy;
+ALTER TABLE results RENAME TO results_legacy;
+-- Имена индексов уникальны в схеме: освобождаем имена первичных ключей для новых таблиц
+ALTER TABLE user_answers_legacy RENAME CONSTRAINT user_answers_pkey TO user_answers_legacy_pkey;
+ALTER TABLE results_legacy RENAME CONSTRAINT results_pkey TO results_legacy_pkey;
+
+CREATE TABLE user_answers (
+  id INTEGER NOT NULL DEFAULT nextval('user_answers_id_seq'),
+  user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  quiz_id BIGINT NOT NULL REFERENCES quizzes (id) ON DELETE CASCADE,
+  question_id BIGINT NOT NULL REFERENCES questions (id) ON DELETE CASCADE,
+  selected_option BIGINT NOT NULL,
+  is_correct BOOLEAN NOT NULL,
+  response_time_ms BIGINT NOT NULL,
+  score BIGINT NOT NULL DEFAULT 0,
+  created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+  is_eliminated BOOLEAN NOT NULL DEFAULT FALSE,
+  elimination_reason TEXT,
+  PRIMARY KEY (id, quiz_id)
+) PARTITION BY RANGE (quiz_id);
+
+CREATE TABLE results (
+  id INTEGER NOT NULL DEFAULT nextval('results_id_seq'),
+  user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  quiz_id BIGINT NOT NULL REFERENCES quizzes (id) ON DELETE CASCADE,
+  username VARCHAR(50) NOT NULL,
+  profile_picture VARCHAR(255) NOT NULL DEFAULT '',
+  score BIGINT NOT NULL,
+  correct_answers BIGINT NOT NULL DEFAULT 0,
+  total_questions BIGINT NOT NULL DEFAULT 0,
+  rank BIGINT NOT NULL DEFAULT 0,
+  completed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+  is_winner BOOLEAN NOT NULL DEFAULT FALSE,
+  prize_fund BIGINT NOT NULL DEFAULT 0,
+  is_eliminated BOOLEAN NOT NULL DEFAULT FALSE,
+  updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+  eliminated_on_question INTEGER DEFAULT NULL,
+  elimination_reason VARCHAR(50) DEFAULT NULL,
+  PRIMARY KEY (id, quiz_id)
+) PARTITION BY RANGE (quiz_id);
+
+-- Секции для всех существующих викторин (ответы и результаты без викторины невозможны из-за FK)
+SELECT ensure_quiz_partitions(id) FROM quizzes;
+
+INSERT INTO user_answers (id, user_id, quiz_id, question_id, selected_option, is_correct, response_time_ms,
+                          score, created_at, is_eliminated, elimination_reason)
+SELECT id, user_id, quiz_id, question_id, selected_option, is_correct, response_time_ms,
+       score, created_at, is_eliminated, elimination_reason
+FROM user_answers_legacy;
+
+INSERT INTO results (id, user_id, quiz_id, username, profile_picture, score, correct_answers, total_questions,
+                     rank, completed_at, created_at, is_winner, prize_fund, is_eliminated, updated_at,
+                     eliminated_on_question, elimination_reason)
+SELECT id, user_id, quiz_id, username, profile_picture, score, correct_answers, total_questions,
+       rank, completed_at, created_at, is_winner, prize_fund, is_eliminated, updated_at,
+       eliminated_on_question, elimination_reason
+FROM results_legacy;
+
+ALTER SEQUENCE user_answers_id_seq OWNED BY user_answers.id;
+ALTER SEQUENCE results_id_seq OWNED BY results.id;
+
+DROP TABLE user_answers_legacy;
+DROP TABLE results_legacy;
+
+-- Индексы создаются на родительских таблицах и наследуются секциями
+CREATE INDEX idx_user_answers_user_id ON user_answers (user_id);
+CREATE INDEX idx_user_answers_quiz_id ON user_answers (quiz_id);
+CREATE INDEX idx_user_answers_question_id ON user_answers (question_id);
+CREATE UNIQUE INDEX uidx_user_answers_user_quiz_question ON user_answers (user_id, quiz_id, question_id);
+
+ALTER TABLE results ADD CONSTRAINT results_user_id_quiz_id_key UNIQUE (user_id, quiz_id);
+CREATE INDEX idx_results_quiz_id ON results (quiz_id);
+CREATE INDEX idx_results_user_id ON results (user_id);
+CREATE INDEX idx_results_created_at ON results (created_at);
+
+-- Секции новых викторин создаются при вставке викторины. Секция следующего диапазона
+-- создаётся заранее, чтобы блокировка родительских таблиц не совпала с идущей викториной.
+CREATE OR REPLACE FUNCTION quizzes_ensure_partitions() RETURNS TRIGGER AS $$
+BEGIN
+  PERFORM ensure_quiz_partitions(NEW.id);
+  PERFORM ensure_quiz_partitions(NEW.id + 100);
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_quizzes_ensure_partitions
+  AFTER INSERT ON quizzes
+  FOR EACH ROW EXECUTE FUNCTION quizzes_ensure_partitions();
+
+SELECT ensure_quiz_partitions(COALESCE(MAX(id), 0) + 100) FROM quizzes;
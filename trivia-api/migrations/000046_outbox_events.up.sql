@@ -0,0 +1,20 @@
+-- Transactional outbox for domain events. Rows are written in the transaction that
+-- produced the event and dispatched to in-process consumers with at-least-once delivery.
+CREATE TABLE IF NOT EXISTS outbox_events (
+  id BIGSERIAL PRIMARY KEY,
+  event_type VARCHAR(64) NOT NULL,
+  payload JSONB NOT NULL,
+  status VARCHAR(16) NOT NULL DEFAULT 'pending',
+  attempts INTEGER NOT NULL DEFAULT 0,
+  next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  done_consumers JSONB NOT NULL DEFAULT '[]',
+  last_error VARCHAR(500) NOT NULL DEFAULT '',
+  processed_at TIMESTAMP NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- The dispatcher polls only pending rows
+CREATE INDEX IF NOT EXISTS idx_outbox_events_due
+  ON outbox_events(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_outbox_events_processed_at
+  ON outbox_events(processed_at) WHERE status = 'processed';
@@ -0,0 +1,2 @@
+ALTER TABLE questions DROP COLUMN IF EXISTS audio_key;
+ALTER TABLE questions DROP COLUMN IF EXISTS image_key;
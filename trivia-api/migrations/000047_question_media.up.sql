@@ -0,0 +1,4 @@
+-- Optional media attachments of questions: storage keys of an image and an audio clip.
+-- Duplicated quizzes share objects, so a key may be referenced by several questions.
+ALTER TABLE questions ADD COLUMN IF NOT EXISTS image_key VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE questions ADD COLUMN IF NOT EXISTS audio_key VARCHAR(255) NOT NULL DEFAULT '';
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS question_review_comments;
+DROP INDEX IF EXISTS idx_questions_review_status;
+ALTER TABLE questions DROP COLUMN IF EXISTS reviewed_at;
+ALTER TABLE questions DROP COLUMN IF EXISTS reviewer_id;
+ALTER TABLE questions DROP COLUMN IF EXISTS review_status;
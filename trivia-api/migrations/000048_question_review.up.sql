@@ -0,0 +1,24 @@
+-- Question review workflow: draft -> pending_review -> approved/rejected.
+-- Questions that already exist have been played or prepared for a show, so they start approved;
+-- new questions default to draft and reach the adaptive selector only after approval.
+ALTER TABLE questions ADD COLUMN IF NOT EXISTS review_status VARCHAR(20) NOT NULL DEFAULT 'approved';
+ALTER TABLE questions ALTER COLUMN review_status SET DEFAULT 'draft';
+ALTER TABLE questions ADD COLUMN IF NOT EXISTS reviewer_id INTEGER NULL REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE questions ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMP NULL;
+
+CREATE INDEX IF NOT EXISTS idx_questions_review_status ON questions(review_status);
+
+-- Review history: reviewer comments and status transitions
+CREATE TABLE IF NOT EXISTS question_review_comments (
+  id SERIAL PRIMARY KEY,
+  question_id INTEGER NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+  author_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  action VARCHAR(20) NOT NULL,
+  from_status VARCHAR(20) NOT NULL DEFAULT '',
+  to_status VARCHAR(20) NOT NULL DEFAULT '',
+  body VARCHAR(1000) NOT NULL DEFAULT '',
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_question_review_comments_question
+  ON question_review_comments(question_id, created_at);
@@ -0,0 +1,7 @@
+ALTER TABLE results DROP COLUMN IF EXISTS revived;
+
+ALTER TABLE quizzes DROP COLUMN IF EXISTS second_chance_ad_asset_id;
+ALTER TABLE quizzes DROP COLUMN IF EXISTS second_chance_cost;
+ALTER TABLE quizzes DROP COLUMN IF EXISTS second_chance_mode;
+
+-- The revenue account is kept: its ledger entries still reference it
@@ -0,0 +1,12 @@
+-- Second chance: an eliminated player may be revived once per quiz, either by watching
+-- an ad or by spending wallet balance. Disabled ('off') for existing quizzes.
+ALTER TABLE quizzes ADD COLUMN IF NOT EXISTS second_chance_mode VARCHAR(20) NOT NULL DEFAULT 'off';
+ALTER TABLE quizzes ADD COLUMN IF NOT EXISTS second_chance_cost BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE quizzes ADD COLUMN IF NOT EXISTS second_chance_ad_asset_id INTEGER NULL REFERENCES ad_assets(id) ON DELETE SET NULL;
+
+ALTER TABLE results ADD COLUMN IF NOT EXISTS revived BOOLEAN NOT NULL DEFAULT false;
+
+-- Wallet balance spent on revives is credited to this system account
+INSERT INTO wallet_accounts (type, code) VALUES
+  ('system', 'second_chance_revenue')
+ON CONFLICT (code) DO NOTHING;
@@ -0,0 +1,29 @@
+-- Prize claims: a winner receives a claim token, submits payout and contact details
+-- before the deadline, and an administrator verifies the winner's identity.
+-- Claims left pending after the deadline are redistributed among the other
+-- claimants of the quiz or forfeited.
+CREATE TABLE IF NOT EXISTS prize_claims (
+  id SERIAL PRIMARY KEY,
+  quiz_id INTEGER NOT NULL REFERENCES quizzes(id) ON DELETE CASCADE,
+  user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  amount BIGINT NOT NULL CHECK (amount >= 0),
+  status VARCHAR(20) NOT NULL DEFAULT 'pending',
+  token_hash VARCHAR(64) NOT NULL,
+  payout_method VARCHAR(30) NOT NULL DEFAULT '',
+  payout_destination VARCHAR(255) NOT NULL DEFAULT '',
+  full_name VARCHAR(150) NOT NULL DEFAULT '',
+  contact_email VARCHAR(255) NOT NULL DEFAULT '',
+  contact_phone VARCHAR(32) NOT NULL DEFAULT '',
+  reject_reason VARCHAR(255) NOT NULL DEFAULT '',
+  reviewer_id INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+  claim_deadline TIMESTAMP NOT NULL,
+  submitted_at TIMESTAMP NULL,
+  verified_at TIMESTAMP NULL,
+  resolved_at TIMESTAMP NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  CONSTRAINT uq_prize_claims_quiz_user UNIQUE (quiz_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_prize_claims_status_deadline ON prize_claims(status, claim_deadline);
+CREATE INDEX IF NOT EXISTS idx_prize_claims_user ON prize_claims(user_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_users_shadow_banned;
+DROP INDEX IF EXISTS idx_users_registration_device;
+ALTER TABLE users DROP COLUMN IF EXISTS shadow_ban_reason;
+ALTER TABLE users DROP COLUMN IF EXISTS shadow_banned_at;
+ALTER TABLE users DROP COLUMN IF EXISTS registration_device_hash;
@@ -0,0 +1,8 @@
+-- Anti-abuse: the device a user registered from (SHA-256 of the client device_id) is stored
+-- to cap accounts per device. Shadow-banned accounts keep playing but are never winners.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS registration_device_hash VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS shadow_banned_at TIMESTAMP NULL;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS shadow_ban_reason VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_users_registration_device ON users(registration_device_hash) WHERE registration_device_hash <> '';
+CREATE INDEX IF NOT EXISTS idx_users_shadow_banned ON users(shadow_banned_at) WHERE shadow_banned_at IS NOT NULL;
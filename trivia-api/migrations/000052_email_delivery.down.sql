@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS email_dead_letters;
+DROP TABLE IF EXISTS email_delivery_events;
+DROP TABLE IF EXISTS email_messages;
@@ -0,0 +1,59 @@
+-- Outgoing email log: one row per accepted message, updated by provider delivery callbacks.
+CREATE TABLE IF NOT EXISTS email_messages (
+  id BIGSERIAL PRIMARY KEY,
+  provider VARCHAR(16) NOT NULL,
+  provider_message_id VARCHAR(255) NOT NULL,
+  kind VARCHAR(32) NOT NULL,
+  recipient VARCHAR(255) NOT NULL,
+  subject VARCHAR(255) NOT NULL DEFAULT '',
+  idempotency_key VARCHAR(255) NOT NULL DEFAULT '',
+  status VARCHAR(16) NOT NULL DEFAULT 'sent',
+  attempts INTEGER NOT NULL DEFAULT 1,
+  last_event_at TIMESTAMP NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_email_messages_provider_message
+  ON email_messages(provider, provider_message_id);
+CREATE INDEX IF NOT EXISTS idx_email_messages_recipient_created
+  ON email_messages(recipient, created_at DESC);
+
+-- Delivery status callbacks; external_id deduplicates callbacks redelivered by the provider
+CREATE TABLE IF NOT EXISTS email_delivery_events (
+  id BIGSERIAL PRIMARY KEY,
+  message_id BIGINT NULL REFERENCES email_messages(id) ON DELETE CASCADE,
+  provider VARCHAR(16) NOT NULL,
+  provider_message_id VARCHAR(255) NOT NULL,
+  external_id VARCHAR(255) NOT NULL DEFAULT '',
+  event_type VARCHAR(32) NOT NULL,
+  detail VARCHAR(500) NOT NULL DEFAULT '',
+  occurred_at TIMESTAMP NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_email_delivery_events_external
+  ON email_delivery_events(provider, external_id) WHERE external_id <> '';
+CREATE INDEX IF NOT EXISTS idx_email_delivery_events_message
+  ON email_delivery_events(message_id, occurred_at);
+
+-- Messages every provider permanently failed to accept
+CREATE TABLE IF NOT EXISTS email_dead_letters (
+  id BIGSERIAL PRIMARY KEY,
+  kind VARCHAR(32) NOT NULL,
+  recipient VARCHAR(255) NOT NULL,
+  subject VARCHAR(255) NOT NULL DEFAULT '',
+  text_body TEXT NOT NULL DEFAULT '',
+  html_body TEXT NOT NULL DEFAULT '',
+  idempotency_key VARCHAR(255) NOT NULL DEFAULT '',
+  provider VARCHAR(16) NOT NULL DEFAULT '',
+  attempts INTEGER NOT NULL DEFAULT 0,
+  last_error VARCHAR(500) NOT NULL DEFAULT '',
+  retried_at TIMESTAMP NULL,
+  retry_message_id BIGINT NULL REFERENCES email_messages(id) ON DELETE SET NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_dead_letters_pending
+  ON email_dead_letters(created_at DESC) WHERE retried_at IS NULL;
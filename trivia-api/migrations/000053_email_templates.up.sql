@@ -0,0 +1,19 @@
+-- Versioned email template overrides edited by admins. Templates without an active row
+-- are rendered from the files shipped with the server (or email.templatesDir).
+CREATE TABLE IF NOT EXISTS email_templates (
+  id BIGSERIAL PRIMARY KEY,
+  key VARCHAR(64) NOT NULL,
+  locale VARCHAR(5) NOT NULL,
+  version INTEGER NOT NULL,
+  subject VARCHAR(255) NOT NULL,
+  text_body TEXT NOT NULL,
+  html_body TEXT NOT NULL DEFAULT '',
+  is_active BOOLEAN NOT NULL DEFAULT FALSE,
+  created_by BIGINT NULL REFERENCES users(id) ON DELETE SET NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_email_templates_version
+  ON email_templates(key, locale, version);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_email_templates_active
+  ON email_templates(key, locale) WHERE is_active;
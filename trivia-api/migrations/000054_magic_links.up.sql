@@ -0,0 +1,17 @@
+-- Single-use passwordless login links. Only the HMAC of the token is stored; device_hash
+-- binds the link to the browser or app that requested it.
+CREATE TABLE IF NOT EXISTS magic_links (
+  id BIGSERIAL PRIMARY KEY,
+  user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  token_hash VARCHAR(64) NOT NULL,
+  device_hash VARCHAR(64) NOT NULL,
+  ip_address VARCHAR(45) NOT NULL DEFAULT '',
+  expires_at TIMESTAMP NOT NULL,
+  consumed_at TIMESTAMP NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_magic_links_token_hash
+  ON magic_links(token_hash);
+CREATE INDEX IF NOT EXISTS idx_magic_links_user_created
+  ON magic_links(user_id, created_at DESC);
@@ -0,0 +1,18 @@
+-- Passkeys (WebAuthn credentials). credential holds the library's credential record
+-- (public key, sign counter, flags); user_handle is the opaque WebAuthn user id shared
+-- by all passkeys of a user.
+CREATE TABLE IF NOT EXISTS webauthn_credentials (
+  id BIGSERIAL PRIMARY KEY,
+  user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  user_handle BYTEA NOT NULL,
+  credential_id BYTEA NOT NULL,
+  credential JSONB NOT NULL,
+  name VARCHAR(100) NOT NULL DEFAULT '',
+  last_used_at TIMESTAMP NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_webauthn_credentials_credential_id
+  ON webauthn_credentials(credential_id);
+CREATE INDEX IF NOT EXISTS idx_webauthn_credentials_user
+  ON webauthn_credentials(user_id);
@@ -0,0 +1,12 @@
+-- Session lifetime policies: the client type selects the idle/absolute limits, and
+-- session_started_at (login time) is carried over to every rotated refresh token.
+ALTER TABLE refresh_tokens
+  ADD COLUMN IF NOT EXISTS client VARCHAR(16) NOT NULL DEFAULT '',
+  ADD COLUMN IF NOT EXISTS session_started_at TIMESTAMP WITH TIME ZONE NULL;
+
+-- Existing sessions cannot be traced back to their login; count them from the current token.
+UPDATE refresh_tokens SET session_started_at = COALESCE(created_at, NOW()) WHERE session_started_at IS NULL;
+
+ALTER TABLE refresh_tokens
+  ALTER COLUMN session_started_at SET DEFAULT NOW(),
+  ALTER COLUMN session_started_at SET NOT NULL;
@@ -0,0 +1,27 @@
+-- API keys for partner server-to-server access and their daily usage counters.
+CREATE TABLE IF NOT EXISTS api_keys (
+  id SERIAL PRIMARY KEY,
+  name VARCHAR(100) NOT NULL,
+  prefix VARCHAR(16) NOT NULL,
+  key_hash VARCHAR(64) NOT NULL,
+  scopes JSONB NOT NULL DEFAULT '[]',
+  rate_limit INTEGER NOT NULL DEFAULT 0,
+  created_by INTEGER NOT NULL DEFAULT 0,
+  expires_at TIMESTAMP NULL,
+  revoked_at TIMESTAMP NULL,
+  last_used_at TIMESTAMP NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Keys are looked up by hash on every partner request
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_keys_key_hash ON api_keys(key_hash);
+
+CREATE TABLE IF NOT EXISTS api_key_usage (
+  key_id INTEGER NOT NULL REFERENCES api_keys(id) ON DELETE CASCADE,
+  day DATE NOT NULL,
+  requests BIGINT NOT NULL DEFAULT 0,
+  rate_limited BIGINT NOT NULL DEFAULT 0,
+  errors BIGINT NOT NULL DEFAULT 0,
+  PRIMARY KEY (key_id, day)
+);
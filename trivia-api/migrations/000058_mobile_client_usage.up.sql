@@ -0,0 +1,9 @@
+-- Daily request counters of mobile clients by API version and app version (from User-Agent).
+CREATE TABLE IF NOT EXISTS mobile_client_usage (
+  day DATE NOT NULL,
+  api_version VARCHAR(8) NOT NULL,
+  platform VARCHAR(16) NOT NULL,
+  app_version VARCHAR(32) NOT NULL,
+  requests BIGINT NOT NULL DEFAULT 0,
+  PRIMARY KEY (day, api_version, platform, app_version)
+);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_refresh_tokens_user_device_key;
+
+ALTER TABLE refresh_tokens
+  DROP COLUMN IF EXISTS device_key_thumbprint,
+  DROP COLUMN IF EXISTS device_key;
@@ -0,0 +1,9 @@
+-- Device-bound mobile sessions: the public key (JWK) registered by the app at login.
+-- When set, refreshing the session requires a proof signed by the device's private key.
+ALTER TABLE refresh_tokens
+  ADD COLUMN IF NOT EXISTS device_key TEXT NOT NULL DEFAULT '',
+  ADD COLUMN IF NOT EXISTS device_key_thumbprint VARCHAR(64) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_device_key
+  ON refresh_tokens (user_id, device_key_thumbprint)
+  WHERE device_key_thumbprint <> '';
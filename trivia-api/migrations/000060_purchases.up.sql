@@ -0,0 +1,33 @@
+-- In-app purchases (App Store, Google Play) and the entitlements they grant.
+-- transaction_id is stable across subscription renewals: originalTransactionId for the
+-- App Store, purchaseToken for Google Play.
+CREATE TABLE IF NOT EXISTS purchases (
+  id SERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  store VARCHAR(16) NOT NULL,
+  transaction_id TEXT NOT NULL,
+  product_id VARCHAR(255) NOT NULL,
+  environment VARCHAR(16) NOT NULL DEFAULT 'production',
+  status VARCHAR(16) NOT NULL,
+  auto_renew BOOLEAN NOT NULL DEFAULT FALSE,
+  purchased_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  expires_at TIMESTAMP WITH TIME ZONE NULL,
+  revoked_at TIMESTAMP WITH TIME ZONE NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_purchases_store_transaction ON purchases(store, transaction_id);
+CREATE INDEX IF NOT EXISTS idx_purchases_user_id ON purchases(user_id);
+
+-- Recomputed from purchases whenever a user's purchases change; expired rows are filtered on read.
+CREATE TABLE IF NOT EXISTS user_entitlements (
+  user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  entitlement VARCHAR(50) NOT NULL,
+  purchase_id INTEGER NOT NULL REFERENCES purchases(id) ON DELETE CASCADE,
+  store VARCHAR(16) NOT NULL,
+  product_id VARCHAR(255) NOT NULL,
+  expires_at TIMESTAMP WITH TIME ZONE NULL,
+  updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (user_id, entitlement)
+);
@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS question_tags;
+DROP TABLE IF EXISTS quiz_tags;
+
+DROP INDEX IF EXISTS idx_questions_pool_category;
+DROP INDEX IF EXISTS idx_quizzes_category_id;
+
+ALTER TABLE questions DROP COLUMN IF EXISTS category_id;
+ALTER TABLE quizzes DROP COLUMN IF EXISTS category_id;
+
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS categories;
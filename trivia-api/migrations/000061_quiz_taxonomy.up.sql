@@ -0,0 +1,45 @@
+-- Quiz/question taxonomy: one category per quiz or question plus any number of tags.
+-- Deleting a category or tag detaches it from quizzes and questions instead of deleting them.
+CREATE TABLE IF NOT EXISTS categories (
+  id SERIAL PRIMARY KEY,
+  slug VARCHAR(64) NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  description VARCHAR(500) NOT NULL DEFAULT '',
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_slug ON categories(slug);
+
+CREATE TABLE IF NOT EXISTS tags (
+  id SERIAL PRIMARY KEY,
+  slug VARCHAR(64) NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_slug ON tags(slug);
+
+ALTER TABLE quizzes ADD COLUMN IF NOT EXISTS category_id INTEGER NULL REFERENCES categories(id) ON DELETE SET NULL;
+ALTER TABLE questions ADD COLUMN IF NOT EXISTS category_id INTEGER NULL REFERENCES categories(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_quizzes_category_id ON quizzes(category_id);
+-- Pool selection for themed quizzes looks up unused approved pool questions of one category
+CREATE INDEX IF NOT EXISTS idx_questions_pool_category ON questions(category_id, difficulty)
+  WHERE quiz_id IS NULL;
+
+CREATE TABLE IF NOT EXISTS quiz_tags (
+  quiz_id INTEGER NOT NULL REFERENCES quizzes(id) ON DELETE CASCADE,
+  tag_id INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+  PRIMARY KEY (quiz_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_quiz_tags_tag_id ON quiz_tags(tag_id);
+
+CREATE TABLE IF NOT EXISTS question_tags (
+  question_id INTEGER NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+  tag_id INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+  PRIMARY KEY (question_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_question_tags_tag_id ON question_tags(tag_id);
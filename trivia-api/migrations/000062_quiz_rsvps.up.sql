@@ -0,0 +1,9 @@
+-- Pre-registration (RSVP) for scheduled quizzes: reminders before start and audience forecasts.
+CREATE TABLE IF NOT EXISTS quiz_rsvps (
+  quiz_id INTEGER NOT NULL REFERENCES quizzes(id) ON DELETE CASCADE,
+  user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (quiz_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_quiz_rsvps_user_id ON quiz_rsvps(user_id);
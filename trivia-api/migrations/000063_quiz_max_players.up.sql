@@ -0,0 +1,2 @@
+-- Waiting room capacity: players beyond max_players wait in an admission queue (0 = unlimited)
+ALTER TABLE quizzes ADD COLUMN IF NOT EXISTS max_players INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+ALTER TABLE quizzes DROP COLUMN IF EXISTS ad_break_duration_sec;
+ALTER TABLE quizzes DROP COLUMN IF EXISTS time_limit_multiplier;
+ALTER TABLE quizzes DROP COLUMN IF EXISTS answer_reveal_delay_ms;
+ALTER TABLE quizzes DROP COLUMN IF EXISTS question_delay_ms;
@@ -0,0 +1,5 @@
+-- Per-quiz timing overrides; NULL means the global QuizManager timing is used
+ALTER TABLE quizzes ADD COLUMN IF NOT EXISTS question_delay_ms INTEGER;
+ALTER TABLE quizzes ADD COLUMN IF NOT EXISTS answer_reveal_delay_ms INTEGER;
+ALTER TABLE quizzes ADD COLUMN IF NOT EXISTS time_limit_multiplier DOUBLE PRECISION;
+ALTER TABLE quizzes ADD COLUMN IF NOT EXISTS ad_break_duration_sec INTEGER;
@@ -0,0 +1,6 @@
+ALTER TABLE results DROP COLUMN IF EXISTS lives_left;
+ALTER TABLE results DROP COLUMN IF EXISTS game_mode;
+
+ALTER TABLE quizzes DROP COLUMN IF EXISTS sudden_death;
+ALTER TABLE quizzes DROP COLUMN IF EXISTS lives;
+ALTER TABLE quizzes DROP COLUMN IF EXISTS game_mode;
@@ -0,0 +1,8 @@
+-- Game modes: elimination (default), points_only (no elimination), lives (eliminated after N mistakes)
+ALTER TABLE quizzes ADD COLUMN IF NOT EXISTS game_mode VARCHAR(20) NOT NULL DEFAULT 'elimination';
+ALTER TABLE quizzes ADD COLUMN IF NOT EXISTS lives INTEGER NOT NULL DEFAULT 0;
+-- Sudden-death finals after the last question while several players survive
+ALTER TABLE quizzes ADD COLUMN IF NOT EXISTS sudden_death BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE results ADD COLUMN IF NOT EXISTS game_mode VARCHAR(20) NOT NULL DEFAULT 'elimination';
+ALTER TABLE results ADD COLUMN IF NOT EXISTS lives_left INTEGER;
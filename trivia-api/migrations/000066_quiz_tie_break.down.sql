@@ -0,0 +1,3 @@
+ALTER TABLE results DROP COLUMN IF EXISTS total_response_time_ms;
+
+ALTER TABLE quizzes DROP COLUMN IF EXISTS tie_break;
@@ -0,0 +1,4 @@
+-- Tie-break between winners: none (split the prize) or response_time (fastest cumulative correct answers)
+ALTER TABLE quizzes ADD COLUMN IF NOT EXISTS tie_break VARCHAR(20) NOT NULL DEFAULT 'none';
+
+ALTER TABLE results ADD COLUMN IF NOT EXISTS total_response_time_ms BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+-- Prize ladder: tiers of the prize fund by finishing place, e.g. [{"top":1,"percent":50},{"top":10,"percent":30},{"percent":20}]
+ALTER TABLE quizzes ADD COLUMN IF NOT EXISTS prize_ladder JSONB;
@@ -0,0 +1,2 @@
+ALTER TABLE users DROP COLUMN IF EXISTS show_recent_results;
+ALTER TABLE users DROP COLUMN IF EXISTS profile_public;
@@ -0,0 +1,3 @@
+-- Public profile privacy: players can hide their stats and recent results from other players
+ALTER TABLE users ADD COLUMN IF NOT EXISTS profile_public BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS show_recent_results BOOLEAN NOT NULL DEFAULT TRUE;
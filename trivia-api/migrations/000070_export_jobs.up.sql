@@ -0,0 +1,27 @@
+-- Background exports of quiz results and answers: a worker writes the file into upload storage
+-- in batches, admins poll progress and download it through an expiring signed URL.
+CREATE TABLE IF NOT EXISTS export_jobs (
+  id SERIAL PRIMARY KEY,
+  quiz_id INTEGER NOT NULL REFERENCES quizzes(id) ON DELETE CASCADE,
+  kind VARCHAR(20) NOT NULL,
+  format VARCHAR(10) NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'pending',
+  requested_by INTEGER NOT NULL,
+  rows_total BIGINT NOT NULL DEFAULT 0,
+  rows_done BIGINT NOT NULL DEFAULT 0,
+  storage_key VARCHAR(255) NOT NULL DEFAULT '',
+  file_name VARCHAR(255) NOT NULL DEFAULT '',
+  size_bytes BIGINT NOT NULL DEFAULT 0,
+  error VARCHAR(500) NOT NULL DEFAULT '',
+  attempts INTEGER NOT NULL DEFAULT 0,
+  lease_until TIMESTAMP WITH TIME ZONE,
+  started_at TIMESTAMP WITH TIME ZONE,
+  completed_at TIMESTAMP WITH TIME ZONE,
+  expires_at TIMESTAMP WITH TIME ZONE,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_export_jobs_quiz_id ON export_jobs(quiz_id);
+-- Worker claims: pending jobs and running jobs whose lease expired
+CREATE INDEX IF NOT EXISTS idx_export_jobs_status ON export_jobs(status) WHERE status IN ('pending', 'running');
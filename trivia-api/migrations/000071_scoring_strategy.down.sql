@@ -0,0 +1,3 @@
+ALTER TABLE results DROP COLUMN IF EXISTS scoring_strategy;
+
+ALTER TABLE quizzes DROP COLUMN IF EXISTS scoring_strategy;
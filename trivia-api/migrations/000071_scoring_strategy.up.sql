@@ -0,0 +1,5 @@
+-- Scoring strategy per quiz: flat (1 point per correct answer), speed_weighted, streak_multiplier, difficulty_weighted.
+-- Results record the strategy their score was computed with.
+ALTER TABLE quizzes ADD COLUMN IF NOT EXISTS scoring_strategy VARCHAR(30) NOT NULL DEFAULT 'flat';
+
+ALTER TABLE results ADD COLUMN IF NOT EXISTS scoring_strategy VARCHAR(30) NOT NULL DEFAULT 'flat';
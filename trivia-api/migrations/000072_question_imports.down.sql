@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_questions_import_id;
+DROP INDEX IF EXISTS idx_questions_text_hash;
+
+ALTER TABLE questions DROP COLUMN IF EXISTS text_hash;
+ALTER TABLE questions DROP COLUMN IF EXISTS import_id;
+ALTER TABLE questions DROP COLUMN IF EXISTS attribution;
+ALTER TABLE questions DROP COLUMN IF EXISTS license;
+ALTER TABLE questions DROP COLUMN IF EXISTS source_ref;
+ALTER TABLE questions DROP COLUMN IF EXISTS source;
+
+DROP TABLE IF EXISTS question_imports;
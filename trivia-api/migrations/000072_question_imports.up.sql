@@ -0,0 +1,34 @@
+-- Question bank import from external trivia sources (Open Trivia DB): each run is recorded,
+-- imported questions keep source/license metadata and land in the review queue.
+CREATE TABLE IF NOT EXISTS question_imports (
+  id SERIAL PRIMARY KEY,
+  source VARCHAR(30) NOT NULL,
+  external_category VARCHAR(100) NOT NULL DEFAULT '',
+  difficulty VARCHAR(20) NOT NULL DEFAULT '',
+  requested INTEGER NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'running',
+  triggered_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+  fetched INTEGER NOT NULL DEFAULT 0,
+  imported INTEGER NOT NULL DEFAULT 0,
+  duplicates INTEGER NOT NULL DEFAULT 0,
+  skipped INTEGER NOT NULL DEFAULT 0,
+  uncategorized INTEGER NOT NULL DEFAULT 0,
+  error VARCHAR(500) NOT NULL DEFAULT '',
+  completed_at TIMESTAMP WITH TIME ZONE,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE questions ADD COLUMN IF NOT EXISTS source VARCHAR(30) NOT NULL DEFAULT '';
+ALTER TABLE questions ADD COLUMN IF NOT EXISTS source_ref VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE questions ADD COLUMN IF NOT EXISTS license VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE questions ADD COLUMN IF NOT EXISTS attribution VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE questions ADD COLUMN IF NOT EXISTS import_id INTEGER REFERENCES question_imports(id) ON DELETE SET NULL;
+ALTER TABLE questions ADD COLUMN IF NOT EXISTS text_hash VARCHAR(64) NOT NULL DEFAULT '';
+
+-- Same normalization as entity.QuestionTextHash: letters and digits only, lower case
+UPDATE questions
+SET text_hash = encode(sha256(convert_to(lower(regexp_replace(text, '[^[:alnum:]]+', '', 'g')), 'UTF8')), 'hex')
+WHERE text_hash = '';
+
+CREATE INDEX IF NOT EXISTS idx_questions_text_hash ON questions(text_hash);
+CREATE INDEX IF NOT EXISTS idx_questions_import_id ON questions(import_id) WHERE import_id IS NOT NULL;
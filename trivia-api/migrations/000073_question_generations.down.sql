@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_questions_generation_id;
+ALTER TABLE questions DROP COLUMN IF EXISTS generation_id;
+
+DROP TABLE IF EXISTS question_generations;
@@ -0,0 +1,31 @@
+-- AI-assisted question generation: each admin request is recorded with token usage and cost
+-- (for per-admin daily quotas); generated questions land in the review queue.
+CREATE TABLE IF NOT EXISTS question_generations (
+  id SERIAL PRIMARY KEY,
+  admin_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  provider VARCHAR(30) NOT NULL,
+  model VARCHAR(100) NOT NULL,
+  topic VARCHAR(200) NOT NULL,
+  language VARCHAR(10) NOT NULL,
+  template VARCHAR(50) NOT NULL,
+  category_id INTEGER REFERENCES categories(id) ON DELETE SET NULL,
+  spread JSONB NOT NULL DEFAULT '{}',
+  requested INTEGER NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'running',
+  generated INTEGER NOT NULL DEFAULT 0,
+  imported INTEGER NOT NULL DEFAULT 0,
+  duplicates INTEGER NOT NULL DEFAULT 0,
+  skipped INTEGER NOT NULL DEFAULT 0,
+  prompt_tokens INTEGER NOT NULL DEFAULT 0,
+  completion_tokens INTEGER NOT NULL DEFAULT 0,
+  cost_usd NUMERIC(12, 6) NOT NULL DEFAULT 0,
+  error VARCHAR(500) NOT NULL DEFAULT '',
+  completed_at TIMESTAMP WITH TIME ZONE,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Daily quota lookups: one admin, since the start of the UTC day
+CREATE INDEX IF NOT EXISTS idx_question_generations_admin_created ON question_generations(admin_id, created_at);
+
+ALTER TABLE questions ADD COLUMN IF NOT EXISTS generation_id INTEGER REFERENCES question_generations(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_questions_generation_id ON questions(generation_id) WHERE generation_id IS NOT NULL;
@@ -0,0 +1,8 @@
+ALTER TABLE quizzes DROP COLUMN IF EXISTS require_verified_kk;
+
+DROP INDEX IF EXISTS idx_question_translations_machine;
+
+ALTER TABLE question_translations DROP COLUMN IF EXISTS reviewed_at;
+ALTER TABLE question_translations DROP COLUMN IF EXISTS reviewed_by;
+ALTER TABLE question_translations DROP COLUMN IF EXISTS mt_provider;
+ALTER TABLE question_translations DROP COLUMN IF EXISTS machine_translated;
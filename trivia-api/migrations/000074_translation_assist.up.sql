@@ -0,0 +1,11 @@
+-- Machine translation assistance for the kk locale: pre-filled translations are flagged until an admin
+-- accepts them; quizzes can require verified Kazakh content (questions with text_kk).
+ALTER TABLE question_translations ADD COLUMN IF NOT EXISTS machine_translated BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE question_translations ADD COLUMN IF NOT EXISTS mt_provider VARCHAR(30) NOT NULL DEFAULT '';
+ALTER TABLE question_translations ADD COLUMN IF NOT EXISTS reviewed_by INTEGER REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE question_translations ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMP WITH TIME ZONE;
+
+-- Review queue of machine translations
+CREATE INDEX IF NOT EXISTS idx_question_translations_machine ON question_translations(locale, id) WHERE machine_translated;
+
+ALTER TABLE quizzes ADD COLUMN IF NOT EXISTS require_verified_kk BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,18 @@
+-- Moderation queue for borderline user-generated text (usernames, profile fields, chat):
+-- the text is accepted and an admin approves it or rejects it (the field is reset).
+CREATE TABLE IF NOT EXISTS moderation_cases (
+  id SERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  field VARCHAR(30) NOT NULL,
+  content VARCHAR(1000) NOT NULL,
+  matches JSONB NOT NULL DEFAULT '[]',
+  status VARCHAR(20) NOT NULL DEFAULT 'pending',
+  reviewed_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+  reviewed_at TIMESTAMP WITH TIME ZONE,
+  note VARCHAR(500) NOT NULL DEFAULT '',
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Queue listing and superseding the pending case of a user's field
+CREATE INDEX IF NOT EXISTS idx_moderation_cases_status ON moderation_cases(status, id);
+CREATE INDEX IF NOT EXISTS idx_moderation_cases_user_field ON moderation_cases(user_id, field) WHERE status = 'pending';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS quiz_geo_checks;
+ALTER TABLE users DROP COLUMN IF EXISTS last_login_country;
+ALTER TABLE quizzes DROP COLUMN IF EXISTS allowed_countries;
@@ -0,0 +1,19 @@
+-- Regional restrictions of prize quizzes: allowed countries per quiz, the country of every
+-- quiz join (checked when winners are determined) and the country of the last login.
+ALTER TABLE quizzes ADD COLUMN IF NOT EXISTS allowed_countries JSONB NOT NULL DEFAULT '[]';
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS last_login_country VARCHAR(2) NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS quiz_geo_checks (
+  quiz_id INTEGER NOT NULL REFERENCES quizzes(id) ON DELETE CASCADE,
+  user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  country VARCHAR(2) NOT NULL,
+  ip_address VARCHAR(45) NOT NULL DEFAULT '',
+  attempts INTEGER NOT NULL DEFAULT 1,
+  first_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (quiz_id, user_id, country)
+);
+
+-- Blocked attempts report, newest first
+CREATE INDEX IF NOT EXISTS idx_quiz_geo_checks_last_seen ON quiz_geo_checks(last_seen_at DESC);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS prize_risk_reviews;
+DROP TABLE IF EXISTS quiz_session_risks;
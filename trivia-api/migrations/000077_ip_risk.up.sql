@@ -0,0 +1,36 @@
+-- VPN/proxy risk of quiz sessions: the risk score (0-100) of every address a player joined a quiz
+-- from, and the review queue of winners whose sessions scored at or above ipRisk.threshold.
+CREATE TABLE IF NOT EXISTS quiz_session_risks (
+  quiz_id INTEGER NOT NULL REFERENCES quizzes(id) ON DELETE CASCADE,
+  user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  ip_address VARCHAR(45) NOT NULL,
+  score INTEGER NOT NULL DEFAULT 0,
+  reasons JSONB NOT NULL DEFAULT '[]',
+  attempts INTEGER NOT NULL DEFAULT 1,
+  first_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (quiz_id, user_id, ip_address)
+);
+
+-- Admin report of risky sessions, newest first
+CREATE INDEX IF NOT EXISTS idx_quiz_session_risks_score ON quiz_session_risks(score, last_seen_at DESC);
+
+CREATE TABLE IF NOT EXISTS prize_risk_reviews (
+  id SERIAL PRIMARY KEY,
+  quiz_id INTEGER NOT NULL REFERENCES quizzes(id) ON DELETE CASCADE,
+  user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  score INTEGER NOT NULL,
+  reasons JSONB NOT NULL DEFAULT '[]',
+  ip_addresses JSONB NOT NULL DEFAULT '[]',
+  amount INTEGER NOT NULL DEFAULT 0,
+  action VARCHAR(20) NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'pending',
+  reviewed_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+  reviewed_at TIMESTAMP WITH TIME ZONE,
+  note VARCHAR(500) NOT NULL DEFAULT '',
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- One review per winner: re-running winner determination does not duplicate it
+CREATE UNIQUE INDEX IF NOT EXISTS idx_prize_risk_reviews_quiz_user ON prize_risk_reviews(quiz_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_prize_risk_reviews_status ON prize_risk_reviews(status, id);
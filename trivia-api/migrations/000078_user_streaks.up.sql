@@ -0,0 +1,14 @@
+-- Player streaks across quizzes: consecutive quizzes played, consecutive correct answers
+-- across games and consecutive quizzes survived. Maintained in the result calculation
+-- transaction; counting starts with the first quiz completed after this migration.
+CREATE TABLE IF NOT EXISTS user_streaks (
+  user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  quiz_streak INTEGER NOT NULL DEFAULT 0,
+  best_quiz_streak INTEGER NOT NULL DEFAULT 0,
+  correct_streak INTEGER NOT NULL DEFAULT 0,
+  best_correct_streak INTEGER NOT NULL DEFAULT 0,
+  survival_streak INTEGER NOT NULL DEFAULT 0,
+  best_survival_streak INTEGER NOT NULL DEFAULT 0,
+  last_quiz_id INTEGER REFERENCES quizzes(id) ON DELETE SET NULL,
+  updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
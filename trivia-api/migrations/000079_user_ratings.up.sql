@@ -0,0 +1,27 @@
+-- Skill rating per player and its change after every rated quiz. Ratings start from the
+-- first quiz completed after this migration; rating_history makes the update idempotent.
+CREATE TABLE IF NOT EXISTS user_ratings (
+  user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  rating INTEGER NOT NULL,
+  games INTEGER NOT NULL DEFAULT 0,
+  peak_rating INTEGER NOT NULL,
+  updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_ratings_leaderboard ON user_ratings (rating DESC, user_id);
+
+CREATE TABLE IF NOT EXISTS rating_history (
+  id SERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  quiz_id INTEGER NOT NULL REFERENCES quizzes(id) ON DELETE CASCADE,
+  algorithm VARCHAR(20) NOT NULL,
+  rating_before INTEGER NOT NULL,
+  rating_after INTEGER NOT NULL,
+  delta INTEGER NOT NULL,
+  rank INTEGER NOT NULL,
+  players INTEGER NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_rating_history_user_quiz ON rating_history (user_id, quiz_id);
+CREATE INDEX IF NOT EXISTS idx_rating_history_quiz ON rating_history (quiz_id);
@@ -0,0 +1,7 @@
+ALTER TABLE user_streaks
+  DROP COLUMN IF EXISTS last_daily_day,
+  DROP COLUMN IF EXISTS best_daily_streak,
+  DROP COLUMN IF EXISTS daily_streak;
+
+DROP TABLE IF EXISTS daily_challenge_answers;
+DROP TABLE IF EXISTS daily_challenges;
@@ -0,0 +1,29 @@
+-- Daily challenge: one pool question per UTC day for everyone, one answer per player per day,
+-- and a daily streak next to the other player streaks.
+CREATE TABLE IF NOT EXISTS daily_challenges (
+  day DATE PRIMARY KEY,
+  question_id INTEGER NOT NULL REFERENCES questions(id) ON DELETE RESTRICT,
+  difficulty INTEGER NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS daily_challenge_answers (
+  id SERIAL PRIMARY KEY,
+  day DATE NOT NULL REFERENCES daily_challenges(day) ON DELETE CASCADE,
+  user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  question_id INTEGER NOT NULL REFERENCES questions(id) ON DELETE RESTRICT,
+  selected_option INTEGER NOT NULL,
+  is_correct BOOLEAN NOT NULL,
+  timed_out BOOLEAN NOT NULL DEFAULT FALSE,
+  response_time_ms BIGINT NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_daily_answer_day_user ON daily_challenge_answers (day, user_id);
+-- Daily leaderboard: correct answers of the day, fastest first
+CREATE INDEX IF NOT EXISTS idx_daily_answer_leaderboard ON daily_challenge_answers (day, response_time_ms, id) WHERE is_correct;
+
+ALTER TABLE user_streaks
+  ADD COLUMN IF NOT EXISTS daily_streak INTEGER NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS best_daily_streak INTEGER NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS last_daily_day DATE;
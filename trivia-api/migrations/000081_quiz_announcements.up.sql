@@ -0,0 +1,17 @@
+-- Host announcements scheduled relative to quiz milestones; sent once as quiz:announcement.
+CREATE TABLE IF NOT EXISTS quiz_announcements (
+  id SERIAL PRIMARY KEY,
+  quiz_id INTEGER NOT NULL REFERENCES quizzes(id) ON DELETE CASCADE,
+  trigger VARCHAR(20) NOT NULL,
+  offset_sec INTEGER NOT NULL DEFAULT 0,
+  question_number INTEGER NOT NULL DEFAULT 0,
+  message VARCHAR(500) NOT NULL,
+  sent_at TIMESTAMP WITH TIME ZONE,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  CONSTRAINT quiz_announcements_trigger_check
+    CHECK (trigger IN ('before_start', 'after_question', 'before_finale'))
+);
+
+-- Pending announcements of a quiz, polled before start and after every question
+CREATE INDEX IF NOT EXISTS idx_quiz_announcements_pending ON quiz_announcements (quiz_id) WHERE sent_at IS NULL;
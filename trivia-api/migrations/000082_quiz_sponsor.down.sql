@@ -0,0 +1,7 @@
+ALTER TABLE ad_impressions DROP COLUMN IF EXISTS placement;
+
+ALTER TABLE quizzes DROP COLUMN IF EXISTS sponsor_click_url;
+ALTER TABLE quizzes DROP COLUMN IF EXISTS sponsor_secondary_color;
+ALTER TABLE quizzes DROP COLUMN IF EXISTS sponsor_primary_color;
+ALTER TABLE quizzes DROP COLUMN IF EXISTS sponsor_logo_asset_id;
+ALTER TABLE quizzes DROP COLUMN IF EXISTS sponsor_name;
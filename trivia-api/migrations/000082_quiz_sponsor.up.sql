@@ -0,0 +1,10 @@
+-- Persistent sponsor branding per quiz, shown in quiz detail and in the quiz:start and
+-- quiz:results_available events. An empty sponsor_name means the quiz has no sponsor.
+ALTER TABLE quizzes ADD COLUMN IF NOT EXISTS sponsor_name VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE quizzes ADD COLUMN IF NOT EXISTS sponsor_logo_asset_id INTEGER NULL REFERENCES ad_assets(id) ON DELETE SET NULL;
+ALTER TABLE quizzes ADD COLUMN IF NOT EXISTS sponsor_primary_color VARCHAR(7) NOT NULL DEFAULT '';
+ALTER TABLE quizzes ADD COLUMN IF NOT EXISTS sponsor_secondary_color VARCHAR(7) NOT NULL DEFAULT '';
+ALTER TABLE quizzes ADD COLUMN IF NOT EXISTS sponsor_click_url VARCHAR(500) NOT NULL DEFAULT '';
+
+-- Sponsor branding impressions share the ad impressions table with ad breaks
+ALTER TABLE ad_impressions ADD COLUMN IF NOT EXISTS placement VARCHAR(20) NOT NULL DEFAULT 'ad_break';
@@ -0,0 +1,6 @@
+-- Secrets written with envelope encryption cannot be decrypted without their wrapped data key:
+-- after rolling back, remove such rows so the API generates a new signing key (users sign in again).
+DELETE FROM jwt_keys WHERE wrapped_data_key <> '';
+DROP INDEX IF EXISTS idx_jwt_keys_encryption_key_version;
+ALTER TABLE jwt_keys DROP COLUMN IF EXISTS encryption_key_version;
+ALTER TABLE jwt_keys DROP COLUMN IF EXISTS wrapped_data_key;
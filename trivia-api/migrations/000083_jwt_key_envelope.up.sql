@@ -0,0 +1,7 @@
+-- Envelope encryption for JWT signing keys: each secret is encrypted with its own data key,
+-- and the data key is wrapped by the master key of encryption_key_version. Existing rows keep
+-- an empty wrapped_data_key (secret encrypted directly by master key version 1) until
+-- `manage jwt-keys reencrypt` rewraps them.
+ALTER TABLE jwt_keys ADD COLUMN IF NOT EXISTS wrapped_data_key TEXT NOT NULL DEFAULT '';
+ALTER TABLE jwt_keys ADD COLUMN IF NOT EXISTS encryption_key_version INTEGER NOT NULL DEFAULT 1;
+CREATE INDEX IF NOT EXISTS idx_jwt_keys_encryption_key_version ON jwt_keys (encryption_key_version);
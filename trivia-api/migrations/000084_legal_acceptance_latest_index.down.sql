@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_user_legal_acceptances_user_accepted;
@@ -0,0 +1,3 @@
+-- Latest acceptance per user: checked on authenticated requests until the user accepts the
+-- current legal versions, and used by the acceptance coverage report
+CREATE INDEX IF NOT EXISTS idx_user_legal_acceptances_user_accepted ON user_legal_acceptances (user_id, accepted_at DESC, id DESC);
@@ -0,0 +1,4 @@
+-- Results privacy: players can hide themselves from public quiz results and leaderboards
+-- and choose whether their full name is shown next to their results
+ALTER TABLE users ADD COLUMN IF NOT EXISTS show_full_name BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS show_on_leaderboards BOOLEAN NOT NULL DEFAULT TRUE;
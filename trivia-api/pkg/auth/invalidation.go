@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+)
+
+// InvalidationChannel — канал Pub/Sub, через который инстансы сообщают друг другу об отзыве токенов
+// пользователя (auth:invalidate), чтобы отзыв действовал во всём кластере сразу
+const InvalidationChannel = "auth:invalidate"
+
+// LegacyInvalidationChannel — канал событий инвалидации предыдущего релиза. На время одного релиза
+// инстансы публикуют и слушают оба канала, чтобы отзыв токенов работал при поэтапном обновлении
+// кластера. Удалить вместе с legacyInvalidationEvent в следующем релизе.
+const LegacyInvalidationChannel = "jwt_invalidation_events"
+
+// invalidationEvent — сообщение auth:invalidate
+type invalidationEvent struct {
+	UserID        uint   `json:"user_id"`
+	InvalidatedAt int64  `json:"invalidated_at"` // Unix ms
+	Origin        string `json:"origin"`         // инстанс, отозвавший токены
+}
+
+// legacyInvalidationEvent — сообщение jwt_invalidation_events. Инстансы предыдущего релиза
+// не заполняют Origin; новые заполняют, чтобы получатели могли отличить дубль auth:invalidate.
+type legacyInvalidationEvent struct {
+	UserID           uint   `json:"user_id"`
+	InvalidationTime int64  `json:"invalidation_time"` // Unix seconds
+	Origin           string `json:"origin,omitempty"`
+}
+
+// SetInvalidationHandler задаёт обработчик отзыва токенов пользователя — на этом инстансе и на
+// остальных инстансах кластера (например, закрытие WebSocket-соединений пользователя)
+func (s *JWTService) SetInvalidationHandler(handler func(userID uint, invalidatedAt time.Time)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onInvalidated = handler
+}
+
+// notifyInvalidated вызывает обработчик отзыва токенов, если он задан
+func (s *JWTService) notifyInvalidated(userID uint, invalidatedAt time.Time) {
+	s.mu.RLock()
+	handler := s.onInvalidated
+	s.mu.RUnlock()
+	if handler != nil {
+		handler(userID, invalidatedAt)
+	}
+}
+
+// publishInvalidation сообщает остальным инстансам об отзыве токенов пользователя.
+// Событие дублируется в LegacyInvalidationChannel для инстансов предыдущего релиза.
+func (s *JWTService) publishInvalidation(userID uint, invalidatedAt time.Time) error {
+	eventBytes, err := json.Marshal(invalidationEvent{UserID: userID, InvalidatedAt: invalidatedAt.UnixMilli(), Origin: s.nodeID})
+	if err != nil {
+		return fmt.Errorf("failed to marshal invalidation event: %w", err)
+	}
+	if err := s.pubSubProvider.Publish(InvalidationChannel, eventBytes); err != nil {
+		return err
+	}
+
+	legacyBytes, err := json.Marshal(legacyInvalidationEvent{UserID: userID, InvalidationTime: invalidatedAt.Unix(), Origin: s.nodeID})
+	if err != nil {
+		return fmt.Errorf("failed to marshal legacy invalidation event: %w", err)
+	}
+	if err := s.pubSubProvider.Publish(LegacyInvalidationChannel, legacyBytes); err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", LegacyInvalidationChannel, err)
+	}
+	return nil
+}
+
+// handleInvalidationEvent применяет auth:invalidate другого инстанса к локальному кешу.
+// Собственные события (их доставляют Redis и NATS) пропускаются: они уже применены.
+func (s *JWTService) handleInvalidationEvent(msgBytes []byte) {
+	var event invalidationEvent
+	if err := json.Unmarshal(msgBytes, &event); err != nil {
+		log.Printf("[JWTService] Ошибка десериализации сообщения из Pub/Sub: %v. Сообщение: %s", err, string(msgBytes))
+		return
+	}
+	if event.Origin == s.nodeID {
+		return
+	}
+	s.applyInvalidation(event.UserID, time.UnixMilli(event.InvalidatedAt))
+}
+
+// handleLegacyInvalidationEvent применяет jwt_invalidation_events инстанса предыдущего релиза.
+// Событие с Origin опубликовал инстанс текущего релиза: оно дублирует auth:invalidate и пропускается.
+func (s *JWTService) handleLegacyInvalidationEvent(msgBytes []byte) {
+	var event legacyInvalidationEvent
+	if err := json.Unmarshal(msgBytes, &event); err != nil {
+		log.Printf("[JWTService] Ошибка десериализации сообщения из Pub/Sub: %v. Сообщение: %s", err, string(msgBytes))
+		return
+	}
+	if event.Origin != "" {
+		return
+	}
+	s.applyInvalidation(event.UserID, time.Unix(event.InvalidationTime, 0))
+}
+
+// applyInvalidation обновляет локальный кеш инвалидации по событию другого инстанса
+func (s *JWTService) applyInvalidation(userID uint, invalidatedAt time.Time) {
+	if userID == 0 {
+		log.Printf("[JWTService] Получено событие инвалидации с некорректным UserID: %d", userID)
+		return
+	}
+
+	s.mu.Lock()
+	// Более позднюю инвалидацию не откатываем
+	if current, exists := s.invalidatedUsers[userID]; exists && current.After(invalidatedAt) {
+		s.mu.Unlock()
+		return
+	}
+	s.invalidatedUsers[userID] = invalidatedAt
+	s.mu.Unlock()
+	log.Printf("[JWTService] Локальный кэш инвалидации обновлен для UserID %d из Pub/Sub, время: %v", userID, invalidatedAt)
+
+	s.notifyInvalidated(userID, invalidatedAt)
+}
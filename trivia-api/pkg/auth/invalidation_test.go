@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingPubSub запоминает опубликованные сообщения
+type recordingPubSub struct {
+	published map[string][][]byte
+}
+
+func (p *recordingPubSub) Publish(channel string, message []byte) error {
+	if p.published == nil {
+		p.published = make(map[string][][]byte)
+	}
+	p.published[channel] = append(p.published[channel], message)
+	return nil
+}
+
+func (p *recordingPubSub) Subscribe(context.Context, string) (<-chan []byte, error) {
+	return make(chan []byte), nil
+}
+
+func (p *recordingPubSub) Close() error { return nil }
+
+func newInvalidationNode(nodeID string, pubSub *recordingPubSub) (*JWTService, *[]uint) {
+	s := &JWTService{invalidatedUsers: make(map[uint]time.Time), pubSubProvider: pubSub, nodeID: nodeID}
+	var handled []uint
+	s.SetInvalidationHandler(func(userID uint, _ time.Time) { handled = append(handled, userID) })
+	return s, &handled
+}
+
+func TestJWTService_InvalidationPropagatesToOtherNodes(t *testing.T) {
+	pubSub := &recordingPubSub{}
+	nodeA, handledA := newInvalidationNode("a", pubSub)
+	nodeB, handledB := newInvalidationNode("b", pubSub)
+
+	require.NoError(t, nodeA.InvalidateTokensForUser(context.Background(), 7))
+	assert.Equal(t, []uint{7}, *handledA, "сокеты закрываются и на инстансе, отозвавшем токены")
+	require.Len(t, pubSub.published[InvalidationChannel], 1)
+	message := pubSub.published[InvalidationChannel][0]
+
+	var event invalidationEvent
+	require.NoError(t, json.Unmarshal(message, &event))
+	assert.Equal(t, "a", event.Origin)
+	assert.Equal(t, nodeA.invalidatedUsers[7].UnixMilli(), event.InvalidatedAt)
+
+	// Все инстансы получают событие, включая отправителя
+	nodeA.handleInvalidationEvent(message)
+	nodeB.handleInvalidationEvent(message)
+	assert.Equal(t, []uint{7}, *handledA, "собственное событие не применяется повторно")
+	assert.Equal(t, []uint{7}, *handledB)
+	assert.Equal(t, nodeA.invalidatedUsers[7].UnixMilli(), nodeB.invalidatedUsers[7].UnixMilli())
+
+	// Запоздавшее событие не откатывает более позднюю инвалидацию
+	later := time.Now().Add(time.Minute)
+	nodeB.invalidatedUsers[7] = later
+	nodeB.handleInvalidationEvent(message)
+	assert.Equal(t, later, nodeB.invalidatedUsers[7])
+	assert.Len(t, *handledB, 1)
+}
+
+// Пока кластер обновляется поэтапно, отзыв токенов доходит и до инстансов предыдущего релиза, и от них
+func TestJWTService_LegacyInvalidationChannel(t *testing.T) {
+	pubSub := &recordingPubSub{}
+	nodeA, _ := newInvalidationNode("a", pubSub)
+	nodeB, handledB := newInvalidationNode("b", pubSub)
+
+	require.NoError(t, nodeA.InvalidateTokensForUser(context.Background(), 7))
+	require.Len(t, pubSub.published[LegacyInvalidationChannel], 1)
+	var legacy struct {
+		UserID           uint  `json:"user_id"`
+		InvalidationTime int64 `json:"invalidation_time"`
+	}
+	require.NoError(t, json.Unmarshal(pubSub.published[LegacyInvalidationChannel][0], &legacy))
+	assert.Equal(t, uint(7), legacy.UserID)
+	assert.Equal(t, nodeA.invalidatedUsers[7].Unix(), legacy.InvalidationTime, "старый формат — секунды")
+
+	// Дубль события текущего релиза применяется только из auth:invalidate
+	nodeB.handleLegacyInvalidationEvent(pubSub.published[LegacyInvalidationChannel][0])
+	assert.Empty(t, *handledB)
+	nodeB.handleInvalidationEvent(pubSub.published[InvalidationChannel][0])
+	assert.Equal(t, []uint{7}, *handledB)
+
+	// Событие инстанса предыдущего релиза: без origin, время в секундах
+	invalidatedAt := time.Now().Add(time.Minute).Truncate(time.Second)
+	message, err := json.Marshal(map[string]interface{}{"user_id": 9, "invalidation_time": invalidatedAt.Unix()})
+	require.NoError(t, err)
+	nodeB.handleLegacyInvalidationEvent(message)
+	assert.Equal(t, []uint{7, 9}, *handledB)
+	assert.True(t, invalidatedAt.Equal(nodeB.invalidatedUsers[9]))
+}
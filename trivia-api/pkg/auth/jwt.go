@@ -9,9 +9,8 @@ import (
 	"sync"
 	"time"
 
-	"encoding/json"
-
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 	"github.com/yourusername/trivia-api/internal/domain/entity"
 	"github.com/yourusername/trivia-api/internal/domain/repository"
 	"github.com/yourusername/trivia-api/internal/websocket"
@@ -53,6 +52,12 @@ type JWTService struct {
 	keyProvider     KeyProvider // Добавлено: зависимость от провайдера ключей
 	pubSubProvider  websocket.PubSubProvider
 	appCtx          context.Context
+	// Счётчики подписей и проверок по ключам
+	keyUsage keyUsageCounters
+	// Идентификатор инстанса в событиях auth:invalidate
+	nodeID string
+	// Обработчик отзыва токенов пользователя (см. SetInvalidationHandler)
+	onInvalidated func(userID uint, invalidatedAt time.Time)
 }
 
 // NewJWTService создает новый сервис JWT и возвращает ошибку при проблемах
@@ -101,6 +106,7 @@ func NewJWTService(
 		keyProvider:      keyProvider, // Сохраняем keyProvider
 		pubSubProvider:   pubSubProvider,
 		appCtx:           appCtx,
+		nodeID:           uuid.NewString(),
 	}
 
 	// Создаем контекст для загрузки из БД при старте
@@ -198,6 +204,7 @@ func (s *JWTService) GenerateTokenWithKey(user *entity.User, csrfSecret string,
 		log.Printf("[JWT] Ошибка генерации токена для пользователя ID=%d с ключом ID=%s: %v", user.ID, signingKey.ID, err)
 		return "", err
 	}
+	s.keyUsage.record(signingKey.ID, true)
 
 	// Не логируем CSRFSecret
 	log.Printf("[JWT] Токен доступа успешно сгенерирован для пользователя ID=%d с ключом ID=%s",
@@ -295,6 +302,9 @@ func (s *JWTService) ParseToken(ctx context.Context, tokenString string) (*JWTCu
 		log.Printf("[JWT] Токен недействителен")
 		return nil, errors.New("invalid token")
 	}
+	if kid, ok := token.Header["kid"].(string); ok {
+		s.keyUsage.record(kid, false)
+	}
 
 	// Проверяем, является ли токен WS-тикетом
 	if claims.Usage == "websocket_auth" {
@@ -351,20 +361,14 @@ func (s *JWTService) InvalidateTokensForUser(ctx context.Context, userID uint) e
 		}
 	}
 
-	// Публикуем событие инвалидации в Pub/Sub
-	invalidationEvent := map[string]interface{}{"user_id": userID, "invalidation_time": now.Unix()}
-	eventBytes, err := json.Marshal(invalidationEvent)
-	if err != nil {
-		log.Printf("[JWT] Ошибка сериализации события инвалидации для userID %d: %v", userID, err)
+	// Публикуем событие инвалидации в Pub/Sub, чтобы остальные инстансы применили его сразу
+	if pubErr := s.publishInvalidation(userID, now); pubErr != nil {
+		log.Printf("[JWT] Ошибка публикации события инвалидации для userID %d в Pub/Sub: %v", userID, pubErr)
 		// Не возвращаем ошибку, чтобы не прерывать основной процесс, но логируем
 	} else {
-		if pubErr := s.pubSubProvider.Publish("jwt_invalidation_events", eventBytes); pubErr != nil {
-			log.Printf("[JWT] Ошибка публикации события инвалидации для userID %d в Pub/Sub: %v", userID, pubErr)
-			// Также не возвращаем ошибку, но логируем
-		} else {
-			log.Printf("[JWT] Событие инвалидации для userID %d опубликовано в Pub/Sub", userID)
-		}
+		log.Printf("[JWT] Событие инвалидации для userID %d опубликовано в Pub/Sub", userID)
 	}
+	s.notifyInvalidated(userID, now)
 
 	log.Printf("[JWT] Токены инвалидированы для пользователя ID=%d в %v", userID, now)
 	return nil
@@ -501,6 +505,50 @@ func (s *JWTService) DebugToken(tokenString string) map[string]interface{} {
 // ParseWSTicket проверяет JWT, используемый как WS тикет
 // Обновлено: использует Keyfunc для проверки подписи
 func (s *JWTService) ParseWSTicket(ctx context.Context, ticketString string) (*JWTCustomClaims, error) {
+	claims, _, err := s.parseWSTicket(ctx, ticketString)
+	return claims, err
+}
+
+// ParseSessionTicket проверяет WS тикет и возвращает авторизацию соединения
+// (пользователь, ключ подписи и время выдачи тикета)
+func (s *JWTService) ParseSessionTicket(ctx context.Context, ticketString string) (websocket.ClientSession, error) {
+	claims, kid, err := s.parseWSTicket(ctx, ticketString)
+	if err != nil {
+		return websocket.ClientSession{}, err
+	}
+	session := websocket.ClientSession{UserID: claims.UserID, KeyID: kid}
+	if claims.IssuedAt != nil {
+		session.IssuedAt = claims.IssuedAt.Time
+	}
+	return session, nil
+}
+
+// RevokedSessions проверяет, действует ли авторизация открытых соединений: токены пользователя
+// не отозваны после выдачи тикета, а ключ подписи тикета ещё используется для проверки.
+// Для каждой авторизации возвращает причину отзыва или "".
+func (s *JWTService) RevokedSessions(ctx context.Context, sessions []websocket.ClientSession) ([]string, error) {
+	validationKeys, err := s.keyProvider.GetKeysForValidation(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get validation keys: %w", err)
+	}
+
+	reasons := make([]string, len(sessions))
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for i, session := range sessions {
+		if invTime, exists := s.invalidatedUsers[session.UserID]; exists && !session.IssuedAt.After(invTime) {
+			reasons[i] = websocket.SessionRevokedInvalidated
+			continue
+		}
+		if _, found := validationKeys[session.KeyID]; !found {
+			reasons[i] = websocket.SessionRevokedKeyRotated
+		}
+	}
+	return reasons, nil
+}
+
+// parseWSTicket проверяет WS тикет и возвращает его claims и ключ подписи (kid)
+func (s *JWTService) parseWSTicket(ctx context.Context, ticketString string) (*JWTCustomClaims, string, error) {
 	claims := &JWTCustomClaims{}
 
 	// Используем ту же Keyfunc, что и в ParseToken
@@ -528,26 +576,28 @@ func (s *JWTService) ParseWSTicket(ctx context.Context, ticketString string) (*J
 	if err != nil {
 		if ve, ok := err.(*jwt.ValidationError); ok {
 			if ve.Errors&jwt.ValidationErrorExpired != 0 {
-				return nil, errors.New("ticket is expired")
+				return nil, "", errors.New("ticket is expired")
 			}
 		}
-		return nil, fmt.Errorf("invalid ticket: %w", err)
+		return nil, "", fmt.Errorf("invalid ticket: %w", err)
 	}
 
 	if !token.Valid {
-		return nil, errors.New("invalid ticket")
+		return nil, "", errors.New("invalid ticket")
 	}
 
 	if claims.Usage != "websocket_auth" {
-		return nil, errors.New("invalid ticket usage")
+		return nil, "", errors.New("invalid ticket usage")
 	}
 
 	if claims.CSRFSecret != "" {
 		log.Printf("[JWT] Ошибка: WS-тикет для пользователя ID=%d содержит CSRF секрет", claims.UserID)
-		return nil, errors.New("WS ticket should not contain CSRF secret")
+		return nil, "", errors.New("WS ticket should not contain CSRF secret")
 	}
 
-	return claims, nil
+	kid, _ := token.Header["kid"].(string)
+	s.keyUsage.record(kid, false)
+	return claims, kid, nil
 }
 
 // GenerateWSTicket создает короткоживущий JWT для аутентификации WebSocket
@@ -585,6 +635,7 @@ func (s *JWTService) GenerateWSTicket(ctx context.Context, userID uint, email st
 		log.Printf("[JWT] Ошибка генерации WS-тикета для пользователя ID=%d с ключом ID=%s: %v", userID, signingKey.ID, err)
 		return "", err
 	}
+	s.keyUsage.record(signingKey.ID, true)
 
 	log.Printf("[JWT] WS-тикет успешно сгенерирован для пользователя ID=%d с ключом ID=%s, истекает через %v",
 		userID, signingKey.ID, s.wsTicketExpiry)
@@ -600,13 +651,20 @@ func (s *JWTService) listenForInvalidationEvents() {
 	}
 
 	// Подписываемся на канал, используя s.appCtx для управления жизненным циклом подписки
-	messages, err := s.pubSubProvider.Subscribe(s.appCtx, "jwt_invalidation_events")
+	messages, err := s.pubSubProvider.Subscribe(s.appCtx, InvalidationChannel)
 	if err != nil {
-		log.Printf("[JWTService] Ошибка подписки на канал jwt_invalidation_events: %v", err)
+		log.Printf("[JWTService] Ошибка подписки на канал %s: %v", InvalidationChannel, err)
 		return // Если не удалось подписаться, нет смысла продолжать
 	}
 
-	log.Println("[JWTService] Успешно подписан на канал jwt_invalidation_events для синхронизации кэша.")
+	log.Printf("[JWTService] Успешно подписан на канал %s для синхронизации кэша.", InvalidationChannel)
+
+	// События инстансов предыдущего релиза; без подписки работаем только с основным каналом
+	legacyMessages, err := s.pubSubProvider.Subscribe(s.appCtx, LegacyInvalidationChannel)
+	if err != nil {
+		log.Printf("[JWTService] Ошибка подписки на канал %s: %v", LegacyInvalidationChannel, err)
+		legacyMessages = nil
+	}
 
 	for {
 		select {
@@ -616,33 +674,17 @@ func (s *JWTService) listenForInvalidationEvents() {
 			return
 		case msgBytes, ok := <-messages:
 			if !ok {
-				log.Println("[JWTService] Канал сообщений Pub/Sub jwt_invalidation_events был закрыт.")
+				log.Printf("[JWTService] Канал сообщений Pub/Sub %s был закрыт.", InvalidationChannel)
 				return // Канал закрыт, выходим
 			}
-
-			var eventData struct {
-				UserID           uint  `json:"user_id"`
-				InvalidationTime int64 `json:"invalidation_time"` // Ожидаем Unix timestamp
-			}
-
-			if err := json.Unmarshal(msgBytes, &eventData); err != nil {
-				log.Printf("[JWTService] Ошибка десериализации сообщения из Pub/Sub: %v. Сообщение: %s", err, string(msgBytes))
-				continue // Пропускаем некорректное сообщение
-			}
-
-			if eventData.UserID == 0 {
-				log.Printf("[JWTService] Получено событие инвалидации с некорректным UserID: %d", eventData.UserID)
+			s.handleInvalidationEvent(msgBytes)
+		case msgBytes, ok := <-legacyMessages:
+			if !ok {
+				log.Printf("[JWTService] Канал сообщений Pub/Sub %s был закрыт.", LegacyInvalidationChannel)
+				legacyMessages = nil // Чтение из nil-канала блокируется: продолжаем слушать основной
 				continue
 			}
-
-			// Обновляем локальный кеш
-			s.mu.Lock()
-			// Если уже есть более свежая запись об инвалидации, не перезаписываем
-			// или всегда перезаписываем временем из события. Для простоты - перезаписываем.
-			invalidationTime := time.Unix(eventData.InvalidationTime, 0)
-			s.invalidatedUsers[eventData.UserID] = invalidationTime
-			s.mu.Unlock()
-			log.Printf("[JWTService] Локальный кэш инвалидации обновлен для UserID %d из Pub/Sub, время: %v", eventData.UserID, invalidationTime)
+			s.handleLegacyInvalidationEvent(msgBytes)
 		}
 	}
 }
@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+// KeyUsage — использование ключа подписи на этом инстансе с момента запуска
+type KeyUsage struct {
+	Signed     int64     `json:"signed"`   // выдано токенов и WS-тикетов
+	Verified   int64     `json:"verified"` // успешно проверено токенов и WS-тикетов
+	LastUsedAt time.Time `json:"last_used_at"`
+}
+
+// keyUsageCounters считает подписи и проверки по ключам (kid)
+type keyUsageCounters struct {
+	mu    sync.Mutex
+	byKey map[string]*KeyUsage
+}
+
+func (c *keyUsageCounters) record(kid string, signed bool) {
+	if kid == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.byKey == nil {
+		c.byKey = make(map[string]*KeyUsage)
+	}
+	usage, ok := c.byKey[kid]
+	if !ok {
+		usage = &KeyUsage{}
+		c.byKey[kid] = usage
+	}
+	if signed {
+		usage.Signed++
+	} else {
+		usage.Verified++
+	}
+	usage.LastUsedAt = time.Now()
+}
+
+// KeyUsage возвращает использование ключей подписи на этом инстансе по kid
+func (s *JWTService) KeyUsage() map[string]KeyUsage {
+	s.keyUsage.mu.Lock()
+	defer s.keyUsage.mu.Unlock()
+	result := make(map[string]KeyUsage, len(s.keyUsage.byKey))
+	for kid, usage := range s.keyUsage.byKey {
+		result[kid] = *usage
+	}
+	return result
+}
@@ -0,0 +1,142 @@
+package manager
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/url"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+const (
+	// DeviceProofHeader — заголовок с доказательством владения ключом устройства (по образцу DPoP, RFC 9449)
+	DeviceProofHeader = "DPoP"
+	// DeviceProofType — значение typ в заголовке JWS доказательства
+	DeviceProofType = "dpop+jwt"
+	// DeviceProofMaxAge — насколько iat доказательства может отличаться от времени сервера
+	DeviceProofMaxAge = 2 * time.Minute
+)
+
+// DeviceKey — открытый ключ устройства (EC P-256), которым мобильный клиент подписывает
+// доказательства при обновлении токенов
+type DeviceKey struct {
+	JWK        string // каноническое представление JWK (RFC 7638), хранится в refresh_tokens.device_key
+	Thumbprint string // SHA-256 отпечаток JWK в base64url
+
+	publicKey *ecdsa.PublicKey
+}
+
+// deviceJWK — поля JWK, участвующие в отпечатке EC-ключа; порядок полей лексикографический (RFC 7638)
+type deviceJWK struct {
+	Crv string `json:"crv"`
+	Kty string `json:"kty"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// ParseDeviceKey разбирает открытый ключ устройства в формате JWK ({"kty":"EC","crv":"P-256","x":...,"y":...}).
+// Закрытая часть ключа (d) не принимается.
+func ParseDeviceKey(raw []byte) (*DeviceKey, error) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, fmt.Errorf("%w: device_key должен быть JWK", apperrors.ErrValidation)
+	}
+	if _, ok := fields["d"]; ok {
+		return nil, fmt.Errorf("%w: device_key содержит закрытый ключ", apperrors.ErrValidation)
+	}
+
+	var jwk deviceJWK
+	if err := json.Unmarshal(raw, &jwk); err != nil || jwk.Kty != "EC" || jwk.Crv != "P-256" {
+		return nil, fmt.Errorf("%w: поддерживаются только ключи EC P-256", apperrors.ErrValidation)
+	}
+	x, errX := base64.RawURLEncoding.DecodeString(jwk.X)
+	y, errY := base64.RawURLEncoding.DecodeString(jwk.Y)
+	if errX != nil || errY != nil || len(x) != 32 || len(y) != 32 {
+		return nil, fmt.Errorf("%w: некорректные координаты ключа устройства", apperrors.ErrValidation)
+	}
+	publicKey := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+	if !publicKey.Curve.IsOnCurve(publicKey.X, publicKey.Y) {
+		return nil, fmt.Errorf("%w: точка ключа устройства не лежит на кривой P-256", apperrors.ErrValidation)
+	}
+
+	canonical, err := json.Marshal(jwk)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(canonical)
+	return &DeviceKey{
+		JWK:        string(canonical),
+		Thumbprint: base64.RawURLEncoding.EncodeToString(sum[:]),
+		publicKey:  publicKey,
+	}, nil
+}
+
+// DeviceProof — доказательство владения ключом устройства из запроса: JWT из заголовка DPoP
+// и запрос, для которого оно выпущено
+type DeviceProof struct {
+	Token  string
+	Method string
+	Path   string
+}
+
+// deviceProofClaims — утверждения доказательства: метод и URL запроса, время выпуска,
+// уникальный идентификатор и хеш refresh-токена (ath)
+type deviceProofClaims struct {
+	HTM string `json:"htm"`
+	HTU string `json:"htu"`
+	IAT int64  `json:"iat"`
+	JTI string `json:"jti"`
+	ATH string `json:"ath"`
+}
+
+func (c deviceProofClaims) Valid() error { return nil }
+
+// verifyDeviceProof проверяет, что доказательство подписано ключом сессии и выпущено для этого
+// запроса и этого refresh-токена. Повтор доказательства бесполезен: ath привязывает его
+// к refresh-токену, который после успешной проверки сразу заменяется новым.
+func verifyDeviceProof(key *DeviceKey, proof *DeviceProof, refreshToken string, now time.Time) error {
+	if proof == nil || proof.Token == "" {
+		return NewTokenError(InvalidDeviceProof, "сессия привязана к ключу устройства, требуется заголовок "+DeviceProofHeader, nil)
+	}
+
+	var claims deviceProofClaims
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{jwt.SigningMethodES256.Alg()}))
+	token, err := parser.ParseWithClaims(proof.Token, &claims, func(t *jwt.Token) (interface{}, error) {
+		if typ, _ := t.Header["typ"].(string); typ != DeviceProofType {
+			return nil, fmt.Errorf("typ %q вместо %s", typ, DeviceProofType)
+		}
+		return key.publicKey, nil
+	})
+	if err != nil || !token.Valid {
+		return NewTokenError(InvalidDeviceProof, "подпись доказательства не соответствует ключу устройства", err)
+	}
+
+	htu, err := url.Parse(claims.HTU)
+	switch {
+	case claims.HTM != proof.Method || err != nil || htu.Path != proof.Path:
+		return NewTokenError(InvalidDeviceProof, "доказательство выпущено для другого запроса", nil)
+	case claims.JTI == "":
+		return NewTokenError(InvalidDeviceProof, "в доказательстве нет jti", nil)
+	case claims.ATH != deviceProofTokenHash(refreshToken):
+		return NewTokenError(InvalidDeviceProof, "доказательство выпущено для другого refresh токена", nil)
+	}
+	issuedAt := time.Unix(claims.IAT, 0)
+	if issuedAt.Before(now.Add(-DeviceProofMaxAge)) || issuedAt.After(now.Add(DeviceProofMaxAge)) {
+		return NewTokenError(InvalidDeviceProof, "доказательство устарело или выпущено в будущем", nil)
+	}
+	return nil
+}
+
+// deviceProofTokenHash — значение ath: SHA-256 refresh-токена в base64url
+func deviceProofTokenHash(refreshToken string) string {
+	sum := sha256.Sum256([]byte(refreshToken))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
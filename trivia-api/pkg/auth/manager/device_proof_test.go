@@ -0,0 +1,113 @@
+package manager
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+func newDeviceKeyPair(t *testing.T) (*ecdsa.PrivateKey, *DeviceKey) {
+	t.Helper()
+	private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	raw, err := json.Marshal(map[string]string{
+		"kty": "EC",
+		"crv": "P-256",
+		"x":   base64.RawURLEncoding.EncodeToString(private.X.FillBytes(make([]byte, 32))),
+		"y":   base64.RawURLEncoding.EncodeToString(private.Y.FillBytes(make([]byte, 32))),
+		"use": "sig",
+	})
+	require.NoError(t, err)
+	key, err := ParseDeviceKey(raw)
+	require.NoError(t, err)
+	return private, key
+}
+
+func signDeviceProof(t *testing.T, private *ecdsa.PrivateKey, claims deviceProofClaims, typ string) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["typ"] = typ
+	signed, err := token.SignedString(private)
+	require.NoError(t, err)
+	return signed
+}
+
+func TestParseDeviceKey(t *testing.T) {
+	_, key := newDeviceKeyPair(t)
+	assert.NotContains(t, key.JWK, "use", "в каноническом JWK только поля отпечатка")
+	assert.Len(t, key.Thumbprint, 43)
+
+	again, err := ParseDeviceKey([]byte(key.JWK))
+	require.NoError(t, err)
+	assert.Equal(t, key.Thumbprint, again.Thumbprint)
+
+	for name, raw := range map[string]string{
+		"not json":    `"key"`,
+		"rsa":         `{"kty":"RSA","n":"AQAB","e":"AQAB"}`,
+		"other curve": `{"kty":"EC","crv":"P-384","x":"AA","y":"AA"}`,
+		"private key": `{"kty":"EC","crv":"P-256","x":"AA","y":"AA","d":"AA"}`,
+		"off curve":   `{"kty":"EC","crv":"P-256","x":"` + base64.RawURLEncoding.EncodeToString(make([]byte, 32)) + `","y":"` + base64.RawURLEncoding.EncodeToString(make([]byte, 32)) + `"}`,
+	} {
+		_, err := ParseDeviceKey([]byte(raw))
+		assert.True(t, errors.Is(err, apperrors.ErrValidation), name)
+	}
+}
+
+func TestVerifyDeviceProof(t *testing.T) {
+	private, key := newDeviceKeyPair(t)
+	otherPrivate, _ := newDeviceKeyPair(t)
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	const refreshToken = "refresh-token"
+	const path = "/api/mobile/v2/auth/refresh"
+
+	valid := deviceProofClaims{
+		HTM: "POST",
+		HTU: "https://api.example.com" + path,
+		IAT: now.Unix(),
+		JTI: "c0a4e8f1",
+		ATH: deviceProofTokenHash(refreshToken),
+	}
+	proof := func(token string) *DeviceProof {
+		return &DeviceProof{Token: token, Method: "POST", Path: path}
+	}
+
+	require.NoError(t, verifyDeviceProof(key, proof(signDeviceProof(t, private, valid, DeviceProofType)), refreshToken, now))
+
+	modified := func(change func(*deviceProofClaims)) string {
+		claims := valid
+		change(&claims)
+		return signDeviceProof(t, private, claims, DeviceProofType)
+	}
+	cases := map[string]*DeviceProof{
+		"missing":      nil,
+		"empty":        proof(""),
+		"other key":    proof(signDeviceProof(t, otherPrivate, valid, DeviceProofType)),
+		"wrong typ":    proof(signDeviceProof(t, private, valid, "JWT")),
+		"other method": proof(modified(func(c *deviceProofClaims) { c.HTM = "GET" })),
+		"other path":   proof(modified(func(c *deviceProofClaims) { c.HTU = "https://api.example.com/api/mobile/auth/refresh" })),
+		"no jti":       proof(modified(func(c *deviceProofClaims) { c.JTI = "" })),
+		"other token":  proof(modified(func(c *deviceProofClaims) { c.ATH = deviceProofTokenHash("stolen") })),
+		"stale":        proof(modified(func(c *deviceProofClaims) { c.IAT = now.Add(-DeviceProofMaxAge - time.Second).Unix() })),
+		"issued later": proof(modified(func(c *deviceProofClaims) { c.IAT = now.Add(DeviceProofMaxAge + time.Second).Unix() })),
+		"not a jws":    proof("not.a.jws"),
+		"truncated":    proof(signDeviceProof(t, private, valid, DeviceProofType)[:40]),
+	}
+	for name, p := range cases {
+		err := verifyDeviceProof(key, p, refreshToken, now)
+		var tokenErr *TokenError
+		if assert.True(t, errors.As(err, &tokenErr), name) {
+			assert.Equal(t, InvalidDeviceProof, tokenErr.Type, name)
+		}
+	}
+}
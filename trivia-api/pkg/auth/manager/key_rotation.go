@@ -0,0 +1,185 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+	"github.com/yourusername/trivia-api/pkg/auth"
+)
+
+// Причины ротации ключа подписи
+const (
+	KeyRotationScheduled = "scheduled" // ключ старше MaxAge
+	KeyRotationManual    = "manual"    // POST /api/auth/admin/rotate-keys
+)
+
+// KeyRotationPolicy задаёт автоматическую ротацию ключей подписи JWT
+type KeyRotationPolicy struct {
+	MaxAge    time.Duration // возраст активного ключа, после которого он ротируется; 0 — автоматической ротации нет
+	Retention time.Duration // сколько ротированный ключ ещё принимается при проверке подписи
+}
+
+// KeyRotation описывает выполненную ротацию ключа подписи
+type KeyRotation struct {
+	PreviousKeyID string
+	NewKeyID      string
+	Trigger       string // KeyRotationScheduled | KeyRotationManual
+	At            time.Time
+}
+
+// JWTKeyStatus — состояние ключа подписи для админ-панели и мониторинга
+type JWTKeyStatus struct {
+	ID                   string     `json:"id"`
+	Algorithm            string     `json:"algorithm"`
+	IsActive             bool       `json:"is_active"`
+	Validating           bool       `json:"validating"` // ключ принимается при проверке подписи
+	CreatedAt            time.Time  `json:"created_at"`
+	ExpiresAt            time.Time  `json:"expires_at"`
+	RotatedAt            *time.Time `json:"rotated_at,omitempty"`
+	AgeSeconds           int64      `json:"age_seconds"`
+	EncryptionKeyVersion int        `json:"encryption_key_version"` // версия мастер-ключа, которым зашифрован секрет в БД
+	// Использование на этом инстансе с момента запуска
+	Signed     int64      `json:"signed"`
+	Verified   int64      `json:"verified"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// SetKeyRotationPolicy задаёт автоматическую ротацию ключей подписи
+func (m *TokenManager) SetKeyRotationPolicy(policy KeyRotationPolicy) {
+	m.keyRotation = policy
+}
+
+// SetKeyRotationHook задаёт обработчик выполненных ротаций (например, запись в журнал аудита).
+// Вызывается только для автоматических ротаций: ручную фиксирует обработчик запроса.
+func (m *TokenManager) SetKeyRotationHook(hook func(rotation KeyRotation)) {
+	m.onKeyRotated = hook
+}
+
+// keyLifetime — срок жизни нового ключа: ключ должен дожить до ротации и пройти период проверки после неё
+func (m *TokenManager) keyLifetime() time.Duration {
+	if lifetime := m.keyRotation.MaxAge + m.keyRotation.Retention; lifetime > DefaultJWTKeyLifetime {
+		return lifetime
+	}
+	return DefaultJWTKeyLifetime
+}
+
+// RotateJWTKeysManually ротирует ключ подписи по запросу администратора
+func (m *TokenManager) RotateJWTKeysManually(ctx context.Context) (*KeyRotation, error) {
+	previousKeyID, newKeyID, err := m.rotateJWTKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	m.pruneRotatedKeys(ctx)
+	return &KeyRotation{PreviousKeyID: previousKeyID, NewKeyID: newKeyID, Trigger: KeyRotationManual, At: time.Now()}, nil
+}
+
+// RotateJWTKeysIfDue ротирует активный ключ, если он старше MaxAge. Возвращает nil, если ротация
+// не нужна или её уже выполнил другой инстанс.
+func (m *TokenManager) RotateJWTKeysIfDue(ctx context.Context, now time.Time) (*KeyRotation, error) {
+	if m.keyRotation.MaxAge <= 0 {
+		return nil, nil
+	}
+	activeKey, err := m.jwtKeyRepo.GetActiveKey(ctx)
+	if err != nil && !errors.Is(err, apperrors.ErrNotFound) {
+		return nil, fmt.Errorf("failed to get active JWT key: %w", err)
+	}
+	if activeKey != nil && now.Sub(activeKey.CreatedAt) < m.keyRotation.MaxAge {
+		return nil, nil
+	}
+
+	previousKeyID, newKeyID, err := m.rotateJWTKeys(ctx)
+	if errors.Is(err, apperrors.ErrConflict) {
+		log.Printf("[TokenManager] JWT key rotation skipped: %v", err)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	m.pruneRotatedKeys(ctx)
+
+	rotation := &KeyRotation{PreviousKeyID: previousKeyID, NewKeyID: newKeyID, Trigger: KeyRotationScheduled, At: now}
+	if m.onKeyRotated != nil {
+		m.onKeyRotated(*rotation)
+	}
+	return rotation, nil
+}
+
+// pruneRotatedKeys удаляет истекшие ключи, период проверки которых закончился
+func (m *TokenManager) pruneRotatedKeys(ctx context.Context) {
+	pruned, err := m.jwtKeyRepo.PruneExpiredKeys(ctx, m.keyRotation.Retention)
+	if err != nil {
+		log.Printf("[TokenManager] Failed to prune expired JWT keys: %v", err)
+		return
+	}
+	if pruned > 0 {
+		log.Printf("[TokenManager] Pruned %d expired JWT keys", pruned)
+	}
+}
+
+// StartKeyRotation периодически проверяет возраст активного ключа и ротирует его. Работает до отмены ctx.
+func (m *TokenManager) StartKeyRotation(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				if _, err := m.RotateJWTKeysIfDue(ctx, now); err != nil {
+					log.Printf("[TokenManager] Scheduled JWT key rotation failed: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// JWTKeyStatuses возвращает состояние ключей подписи: возраст, участие в проверке подписи
+// и использование на этом инстансе
+func (m *TokenManager) JWTKeyStatuses(ctx context.Context) ([]JWTKeyStatus, error) {
+	keys, err := m.jwtKeyRepo.ListAllKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	validationKeys, err := m.jwtKeyRepo.GetValidationKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	validating := make(map[string]bool, len(validationKeys))
+	for _, key := range validationKeys {
+		validating[key.ID] = true
+	}
+
+	var usage map[string]auth.KeyUsage
+	if m.jwtService != nil {
+		usage = m.jwtService.KeyUsage()
+	}
+
+	now := time.Now()
+	statuses := make([]JWTKeyStatus, 0, len(keys))
+	for _, key := range keys {
+		status := JWTKeyStatus{
+			ID:                   key.ID,
+			Algorithm:            key.Algorithm,
+			IsActive:             key.IsActive,
+			Validating:           validating[key.ID],
+			CreatedAt:            key.CreatedAt,
+			ExpiresAt:            key.ExpiresAt,
+			RotatedAt:            key.RotatedAt,
+			AgeSeconds:           int64(now.Sub(key.CreatedAt) / time.Second),
+			EncryptionKeyVersion: key.EncryptionKeyVersion,
+		}
+		if u, ok := usage[key.ID]; ok {
+			status.Signed, status.Verified = u.Signed, u.Verified
+			lastUsedAt := u.LastUsedAt
+			status.LastUsedAt = &lastUsedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
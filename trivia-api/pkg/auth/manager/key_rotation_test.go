@@ -0,0 +1,174 @@
+package manager
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+	apperrors "github.com/yourusername/trivia-api/internal/pkg/errors"
+)
+
+// memoryJWTKeyRepo хранит ключи в памяти; staleActive имитирует инстанс, прочитавший
+// активный ключ до того, как его ротировал другой инстанс
+type memoryJWTKeyRepo struct {
+	keys        map[string]*entity.JWTKey
+	staleActive *entity.JWTKey
+	pruned      []time.Duration
+}
+
+func newMemoryJWTKeyRepo(keys ...*entity.JWTKey) *memoryJWTKeyRepo {
+	r := &memoryJWTKeyRepo{keys: make(map[string]*entity.JWTKey)}
+	for _, key := range keys {
+		r.keys[key.ID] = key
+	}
+	return r
+}
+
+func (r *memoryJWTKeyRepo) CreateKey(_ context.Context, key *entity.JWTKey) error {
+	copied := *key
+	r.keys[key.ID] = &copied
+	return nil
+}
+
+func (r *memoryJWTKeyRepo) GetKeyByID(_ context.Context, id string) (*entity.JWTKey, error) {
+	if key, ok := r.keys[id]; ok {
+		return key, nil
+	}
+	return nil, apperrors.ErrNotFound
+}
+
+func (r *memoryJWTKeyRepo) GetActiveKey(_ context.Context) (*entity.JWTKey, error) {
+	if r.staleActive != nil {
+		return r.staleActive, nil
+	}
+	for _, key := range r.keys {
+		if key.IsActive {
+			return key, nil
+		}
+	}
+	return nil, apperrors.ErrNotFound
+}
+
+func (r *memoryJWTKeyRepo) GetValidationKeys(_ context.Context) ([]*entity.JWTKey, error) {
+	var keys []*entity.JWTKey
+	for _, key := range r.keys {
+		if key.IsActive || key.RotatedAt != nil {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (r *memoryJWTKeyRepo) DeactivateKey(_ context.Context, id string, rotatedAt time.Time) error {
+	key, ok := r.keys[id]
+	if !ok || !key.IsActive {
+		return apperrors.ErrNotFound
+	}
+	key.IsActive = false
+	key.RotatedAt = &rotatedAt
+	return nil
+}
+
+func (r *memoryJWTKeyRepo) ListAllKeys(_ context.Context) ([]*entity.JWTKey, error) {
+	keys := make([]*entity.JWTKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.After(keys[j].CreatedAt) })
+	return keys, nil
+}
+
+func (r *memoryJWTKeyRepo) PruneExpiredKeys(_ context.Context, gracePeriod time.Duration) (int64, error) {
+	r.pruned = append(r.pruned, gracePeriod)
+	return 0, nil
+}
+
+func (r *memoryJWTKeyRepo) activeKeys() []string {
+	var ids []string
+	for id, key := range r.keys {
+		if key.IsActive {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func TestTokenManager_RotateJWTKeysIfDue(t *testing.T) {
+	now := time.Now()
+	repo := newMemoryJWTKeyRepo(&entity.JWTKey{ID: "old", Algorithm: "HS256", IsActive: true, CreatedAt: now.Add(-20 * 24 * time.Hour)})
+	m := &TokenManager{jwtKeyRepo: repo}
+	var hooked []KeyRotation
+	m.SetKeyRotationHook(func(rotation KeyRotation) { hooked = append(hooked, rotation) })
+
+	// Без MaxAge автоматической ротации нет
+	rotation, err := m.RotateJWTKeysIfDue(context.Background(), now)
+	require.NoError(t, err)
+	assert.Nil(t, rotation)
+
+	m.SetKeyRotationPolicy(KeyRotationPolicy{MaxAge: 30 * 24 * time.Hour, Retention: 7 * 24 * time.Hour})
+	rotation, err = m.RotateJWTKeysIfDue(context.Background(), now)
+	require.NoError(t, err)
+	assert.Nil(t, rotation, "ключ моложе maxKeyAge")
+
+	rotation, err = m.RotateJWTKeysIfDue(context.Background(), now.Add(11*24*time.Hour))
+	require.NoError(t, err)
+	require.NotNil(t, rotation)
+	assert.Equal(t, "old", rotation.PreviousKeyID)
+	assert.Equal(t, KeyRotationScheduled, rotation.Trigger)
+	assert.Equal(t, []string{rotation.NewKeyID}, repo.activeKeys())
+	assert.NotNil(t, repo.keys["old"].RotatedAt)
+	assert.Equal(t, []KeyRotation{*rotation}, hooked)
+	assert.Equal(t, []time.Duration{7 * 24 * time.Hour}, repo.pruned)
+
+	// Новый ключ живёт не меньше maxKeyAge + retention
+	newKey := repo.keys[rotation.NewKeyID]
+	assert.WithinDuration(t, time.Now().Add(DefaultJWTKeyLifetime), newKey.ExpiresAt, time.Minute)
+	m.SetKeyRotationPolicy(KeyRotationPolicy{MaxAge: 90 * 24 * time.Hour, Retention: 7 * 24 * time.Hour})
+	assert.Equal(t, 97*24*time.Hour, m.keyLifetime())
+}
+
+func TestTokenManager_RotateJWTKeysIfDueSkipsConcurrentRotation(t *testing.T) {
+	now := time.Now()
+	stale := &entity.JWTKey{ID: "old", Algorithm: "HS256", CreatedAt: now.Add(-40 * 24 * time.Hour)}
+	repo := newMemoryJWTKeyRepo(stale, &entity.JWTKey{ID: "fresh", Algorithm: "HS256", IsActive: true, CreatedAt: now})
+	repo.staleActive = stale
+	m := &TokenManager{jwtKeyRepo: repo}
+	m.SetKeyRotationPolicy(KeyRotationPolicy{MaxAge: 30 * 24 * time.Hour})
+	m.SetKeyRotationHook(func(KeyRotation) { t.Fatal("hook must not run for a skipped rotation") })
+
+	// Другой инстанс уже ротировал ключ: второй активный ключ не создаётся
+	rotation, err := m.RotateJWTKeysIfDue(context.Background(), now)
+	require.NoError(t, err)
+	assert.Nil(t, rotation)
+	assert.Equal(t, []string{"fresh"}, repo.activeKeys())
+
+	_, err = m.RotateJWTKeysManually(context.Background())
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+}
+
+func TestTokenManager_JWTKeyStatuses(t *testing.T) {
+	now := time.Now()
+	rotatedAt := now.Add(-time.Hour)
+	repo := newMemoryJWTKeyRepo(
+		&entity.JWTKey{ID: "current", Algorithm: "HS256", IsActive: true, CreatedAt: now.Add(-2 * time.Hour)},
+		&entity.JWTKey{ID: "previous", Algorithm: "HS256", CreatedAt: now.Add(-48 * time.Hour), RotatedAt: &rotatedAt},
+		&entity.JWTKey{ID: "retired", Algorithm: "HS256", CreatedAt: now.Add(-96 * time.Hour)},
+	)
+	m := &TokenManager{jwtKeyRepo: repo}
+
+	statuses, err := m.JWTKeyStatuses(context.Background())
+	require.NoError(t, err)
+	require.Len(t, statuses, 3)
+	assert.Equal(t, "current", statuses[0].ID)
+	assert.True(t, statuses[0].IsActive)
+	assert.True(t, statuses[0].Validating)
+	assert.InDelta(t, 2*3600, statuses[0].AgeSeconds, 5)
+	assert.True(t, statuses[1].Validating)
+	assert.False(t, statuses[2].Validating)
+	assert.Nil(t, statuses[0].LastUsedAt, "без JWTService использование не известно")
+}
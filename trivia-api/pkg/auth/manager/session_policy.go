@@ -0,0 +1,107 @@
+package manager
+
+import (
+	"context"
+	"time"
+)
+
+// ClientType — тип клиента, которому выдана сессия
+type ClientType string
+
+const (
+	// ClientWeb — браузер: refresh-токен в HttpOnly cookie
+	ClientWeb ClientType = "web"
+	// ClientMobile — мобильное приложение: refresh-токен в теле запроса
+	ClientMobile ClientType = "mobile"
+)
+
+type clientContextKey struct{}
+
+// WithClient помечает контекст запроса типом клиента. Мобильные обработчики вызывают его,
+// чтобы сессии, созданные через общие методы AuthService, получали мобильную политику.
+func WithClient(ctx context.Context, client ClientType) context.Context {
+	return context.WithValue(ctx, clientContextKey{}, client)
+}
+
+// ClientFromContext возвращает тип клиента из контекста (по умолчанию — веб)
+func ClientFromContext(ctx context.Context) ClientType {
+	if ctx != nil {
+		if client, ok := ctx.Value(clientContextKey{}).(ClientType); ok && client != "" {
+			return client
+		}
+	}
+	return ClientWeb
+}
+
+// SessionPolicy ограничивает время жизни сессии — цепочки refresh-токенов от входа до выхода.
+// Нулевое значение поля — без ограничения.
+type SessionPolicy struct {
+	// IdleTimeout — сессия завершается, если refresh-токен не использовался дольше этого времени
+	IdleTimeout time.Duration
+	// AbsoluteLifetime — сессия завершается через это время после входа, даже при активном использовании
+	AbsoluteLifetime time.Duration
+}
+
+// merge возвращает политику, в которой заданные (ненулевые) поля override заменяют поля p
+func (p SessionPolicy) merge(override SessionPolicy) SessionPolicy {
+	if override.IdleTimeout > 0 {
+		p.IdleTimeout = override.IdleTimeout
+	}
+	if override.AbsoluteLifetime > 0 {
+		p.AbsoluteLifetime = override.AbsoluteLifetime
+	}
+	return p
+}
+
+// ClientSessionPolicies — политики для веб- и мобильных клиентов
+type ClientSessionPolicies struct {
+	Web    SessionPolicy
+	Mobile SessionPolicy
+}
+
+func (p ClientSessionPolicies) forClient(client ClientType) SessionPolicy {
+	if client == ClientMobile {
+		return p.Mobile
+	}
+	return p.Web
+}
+
+// SessionPolicies — политики по умолчанию и переопределения для ролей пользователей
+type SessionPolicies struct {
+	ClientSessionPolicies
+	// Roles переопределяет политику для роли (например, короче для admin); незаданные поля наследуются
+	Roles map[string]ClientSessionPolicies
+}
+
+// Resolve возвращает политику для роли пользователя и типа клиента
+func (p SessionPolicies) Resolve(role string, client ClientType) SessionPolicy {
+	policy := p.forClient(client)
+	if override, ok := p.Roles[role]; ok {
+		policy = policy.merge(override.forClient(client))
+	}
+	return policy
+}
+
+// sessionEndReason проверяет сессию, refresh-токен которой выдан в issuedAt, по политике.
+// Возвращает причину завершения или пустую строку, если сессия ещё действует.
+func (p SessionPolicy) sessionEndReason(sessionStartedAt, issuedAt, now time.Time) string {
+	if p.AbsoluteLifetime > 0 && !sessionStartedAt.IsZero() && now.After(sessionStartedAt.Add(p.AbsoluteLifetime)) {
+		return "session_lifetime_exceeded"
+	}
+	if p.IdleTimeout > 0 && now.After(issuedAt.Add(p.IdleTimeout)) {
+		return "session_idle_timeout"
+	}
+	return ""
+}
+
+// expiresAt ограничивает срок действия нового refresh-токена политикой сессии
+func (p SessionPolicy) expiresAt(sessionStartedAt, now time.Time, tokenLifetime time.Duration) time.Time {
+	expiresAt := now.Add(tokenLifetime)
+	if p.IdleTimeout > 0 && now.Add(p.IdleTimeout).Before(expiresAt) {
+		expiresAt = now.Add(p.IdleTimeout)
+	}
+	if p.AbsoluteLifetime > 0 && sessionStartedAt.Add(p.AbsoluteLifetime).Before(expiresAt) {
+		expiresAt = sessionStartedAt.Add(p.AbsoluteLifetime)
+	}
+	return expiresAt
+}
@@ -0,0 +1,50 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionPolicies_ResolveMergesRoleOverride(t *testing.T) {
+	policies := SessionPolicies{
+		ClientSessionPolicies: ClientSessionPolicies{
+			Web:    SessionPolicy{IdleTimeout: 7 * 24 * time.Hour, AbsoluteLifetime: 30 * 24 * time.Hour},
+			Mobile: SessionPolicy{IdleTimeout: 30 * 24 * time.Hour, AbsoluteLifetime: 180 * 24 * time.Hour},
+		},
+		Roles: map[string]ClientSessionPolicies{
+			"admin": {Web: SessionPolicy{IdleTimeout: 12 * time.Hour}},
+		},
+	}
+
+	assert.Equal(t, policies.Web, policies.Resolve("user", ClientWeb))
+	assert.Equal(t, policies.Mobile, policies.Resolve("user", ClientMobile))
+	assert.Equal(t, SessionPolicy{IdleTimeout: 12 * time.Hour, AbsoluteLifetime: 30 * 24 * time.Hour}, policies.Resolve("admin", ClientWeb))
+	assert.Equal(t, policies.Mobile, policies.Resolve("admin", ClientMobile), "незаданная политика роли наследуется")
+}
+
+func TestSessionPolicy_IdleAndAbsoluteLimits(t *testing.T) {
+	policy := SessionPolicy{IdleTimeout: 24 * time.Hour, AbsoluteLifetime: 72 * time.Hour}
+	login := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+
+	assert.Empty(t, policy.sessionEndReason(login, login, login.Add(23*time.Hour)))
+	assert.Equal(t, "session_idle_timeout", policy.sessionEndReason(login, login, login.Add(25*time.Hour)))
+
+	// Активная сессия всё равно завершается через absoluteLifetime после входа
+	lastRefresh := login.Add(71 * time.Hour)
+	assert.Empty(t, policy.sessionEndReason(login, lastRefresh, login.Add(71*time.Hour+time.Minute)))
+	assert.Equal(t, "session_lifetime_exceeded", policy.sessionEndReason(login, lastRefresh, login.Add(73*time.Hour)))
+	assert.Empty(t, SessionPolicy{}.sessionEndReason(login, login, login.Add(365*24*time.Hour)), "нулевая политика не ограничивает")
+
+	// Срок refresh-токена не выходит за простой и за конец сессии
+	assert.Equal(t, login.Add(24*time.Hour), policy.expiresAt(login, login, 30*24*time.Hour))
+	assert.Equal(t, login.Add(72*time.Hour), policy.expiresAt(login, login.Add(60*time.Hour), 30*24*time.Hour))
+	assert.Equal(t, login.Add(30*24*time.Hour), SessionPolicy{}.expiresAt(login, login, 30*24*time.Hour))
+}
+
+func TestClientFromContext(t *testing.T) {
+	assert.Equal(t, ClientWeb, ClientFromContext(context.Background()))
+	assert.Equal(t, ClientMobile, ClientFromContext(WithClient(context.Background(), ClientMobile)))
+}
@@ -35,6 +35,12 @@ const (
 	CSRFHeader = "X-CSRF-Token"
 	// Имя cookie для CSRF секрета (HttpOnly, Secure)
 	CSRFSecretCookie = "__Host-csrf-secret" // Используем __Host- префикс для безопасности
+	// Имя cookie CSRF-сессии админ-панели (режим synchronizer token, HttpOnly)
+	AdminCSRFSessionCookie = "__Host-admin-csrf"
+	// Имя cookie с идентификатором браузера для лимита аккаунтов на устройство
+	DeviceIDCookie = "device_id"
+	// Время жизни cookie идентификатора браузера (2 года)
+	DeviceIDCookieLifetime = 2 * 365 * 24 * time.Hour
 
 	// Время жизни ключа JWT по умолчанию
 	DefaultJWTKeyLifetime = 90 * 24 * time.Hour // 90 дней
@@ -55,6 +61,7 @@ const (
 	InvalidCSRFToken    TokenErrorType = "INVALID_CSRF_TOKEN"
 	UserNotFound        TokenErrorType = "USER_NOT_FOUND"
 	InactiveUser        TokenErrorType = "INACTIVE_USER"
+	InvalidDeviceProof  TokenErrorType = "INVALID_DEVICE_PROOF"
 
 	// Ошибки базы данных или репозитория
 	DatabaseError TokenErrorType = "DATABASE_ERROR"
@@ -124,6 +131,10 @@ type TokenManager struct {
 	accessTokenExpiry       time.Duration
 	refreshTokenExpiry      time.Duration
 	maxRefreshTokensPerUser int // Добавлено: настраиваемый лимит сессий
+	sessionPolicies         SessionPolicies
+	onLogin                 func(userID uint, ipAddress string)
+	keyRotation             KeyRotationPolicy
+	onKeyRotated            func(rotation KeyRotation)
 	// Настройки для Cookie
 	cookiePath       string
 	cookieDomain     string
@@ -242,6 +253,19 @@ func (m *TokenManager) SetMaxRefreshTokensPerUser(limit int) {
 	}
 }
 
+// SetSessionPolicies задаёт ограничения времени жизни сессий для веб- и мобильных клиентов
+func (m *TokenManager) SetSessionPolicies(policies SessionPolicies) {
+	m.sessionPolicies = policies
+	log.Printf("[TokenManager] Session policies set: web=%+v, mobile=%+v, role overrides=%d",
+		policies.Web, policies.Mobile, len(policies.Roles))
+}
+
+// SetLoginHook задаёт функцию, вызываемую после выдачи пары токенов при входе любым способом
+// (и при регистрации); обновление токенов входом не считается. Функция не должна блокировать.
+func (m *TokenManager) SetLoginHook(hook func(userID uint, ipAddress string)) {
+	m.onLogin = hook
+}
+
 // GetMaxRefreshTokensPerUser возвращает текущее максимальное количество активных refresh-токенов на пользователя.
 func (m *TokenManager) GetMaxRefreshTokensPerUser() int {
 	return m.maxRefreshTokensPerUser
@@ -250,6 +274,11 @@ func (m *TokenManager) GetMaxRefreshTokensPerUser() int {
 // GenerateTokenPair создает новую пару токенов (access и refresh)
 // Эта функция теперь использует jwtService напрямую, а не через tokenService
 func (m *TokenManager) GenerateTokenPair(userID uint, deviceID, ipAddress, userAgent string) (*TokenResponse, error) {
+	return m.GenerateTokenPairForClient(ClientWeb, userID, deviceID, ipAddress, userAgent)
+}
+
+// GenerateTokenPairForClient создает пару токенов для новой сессии клиента указанного типа
+func (m *TokenManager) GenerateTokenPairForClient(client ClientType, userID uint, deviceID, ipAddress, userAgent string) (*TokenResponse, error) {
 	if m.jwtService == nil {
 		log.Println("CRITICAL: [TokenManager] JWTService not set in TokenManager. Cannot generate tokens.")
 		return nil, NewTokenError(TokenGenerationFailed, "JWTService not configured", nil)
@@ -281,8 +310,11 @@ func (m *TokenManager) GenerateTokenPair(userID uint, deviceID, ipAddress, userA
 	// Генерируем CSRF токен (хеш)
 	csrfTokenHash := HashCSRFSecret(csrfSecret)
 
-	// Генерируем refresh-токен
-	refreshTokenString, err := m.generateRefreshToken(userID, deviceID, ipAddress, userAgent)
+	// Генерируем refresh-токен; сессия начинается сейчас
+	now := time.Now()
+	policy := m.sessionPolicies.Resolve(user.Role, client)
+	expiresAt := policy.expiresAt(now, now, m.refreshTokenExpiry)
+	refreshTokenString, err := m.generateRefreshToken(userID, deviceID, ipAddress, userAgent, client, now, expiresAt, nil)
 	if err != nil {
 		log.Printf("[TokenManager] Ошибка генерации refresh-токена для пользователя ID=%d: %v", userID, err)
 		return nil, NewTokenError(TokenGenerationFailed, "ошибка генерации refresh токена", err)
@@ -295,6 +327,9 @@ func (m *TokenManager) GenerateTokenPair(userID uint, deviceID, ipAddress, userA
 	}
 
 	log.Printf("[TokenManager] Сгенерирована пара токенов для пользователя ID=%d, JWT Key ID: %s", userID, signingKey.ID)
+	if m.onLogin != nil {
+		m.onLogin(userID, ipAddress)
+	}
 
 	return &TokenResponse{
 		AccessToken:  accessToken,
@@ -310,6 +345,34 @@ func (m *TokenManager) GenerateTokenPair(userID uint, deviceID, ipAddress, userA
 // RefreshTokens обновляет пару токенов, используя refresh токен
 // Эта функция теперь использует jwtService напрямую
 func (m *TokenManager) RefreshTokens(refreshToken, csrfTokenHeader, deviceID, ipAddress, userAgent string) (*TokenResponse, error) {
+	return m.RefreshTokensForClient(ClientWeb, refreshToken, csrfTokenHeader, deviceID, ipAddress, userAgent)
+}
+
+// RefreshTokensForClient обновляет пару токенов сессии клиента указанного типа. Сессия
+// завершается, если она простаивала или длится дольше, чем разрешает политика для роли
+// пользователя и типа клиента, а также если токен предъявлен клиентом другого типа.
+func (m *TokenManager) RefreshTokensForClient(client ClientType, refreshToken, csrfTokenHeader, deviceID, ipAddress, userAgent string) (*TokenResponse, error) {
+	return m.refreshTokens(client, refreshToken, nil, nil, deviceID, ipAddress, userAgent)
+}
+
+// RefreshTokensWithProof обновляет пару токенов, как RefreshTokensForClient. Если сессия
+// привязана к ключу устройства, proof должно быть подписано этим ключом; иначе proof не нужно.
+func (m *TokenManager) RefreshTokensWithProof(client ClientType, refreshToken string, proof *DeviceProof, deviceID, ipAddress, userAgent string) (*TokenResponse, error) {
+	return m.refreshTokens(client, refreshToken, proof, nil, deviceID, ipAddress, userAgent)
+}
+
+// RotateDeviceKey обновляет пару токенов и привязывает новую пару к ключу newKey. Сессию,
+// уже привязанную к ключу, можно перепривязать только с доказательством, подписанным текущим
+// ключом. Старый refresh-токен при этом отзывается, поэтому доказательство нельзя повторить.
+func (m *TokenManager) RotateDeviceKey(client ClientType, refreshToken string, proof *DeviceProof, newKey *DeviceKey, deviceID, ipAddress, userAgent string) (*TokenResponse, error) {
+	if newKey == nil {
+		return nil, fmt.Errorf("%w: не указан новый ключ устройства", apperrors.ErrValidation)
+	}
+	return m.refreshTokens(client, refreshToken, proof, newKey, deviceID, ipAddress, userAgent)
+}
+
+// refreshTokens — общая часть обновления токенов. newKey, если задан, заменяет ключ устройства сессии.
+func (m *TokenManager) refreshTokens(client ClientType, refreshToken string, proof *DeviceProof, newKey *DeviceKey, deviceID, ipAddress, userAgent string) (*TokenResponse, error) {
 	if m.jwtService == nil {
 		log.Println("CRITICAL: [TokenManager] JWTService not set in TokenManager. Cannot refresh tokens.")
 		return nil, NewTokenError(TokenGenerationFailed, "JWTService not configured", nil)
@@ -334,6 +397,43 @@ func (m *TokenManager) RefreshTokens(refreshToken, csrfTokenHeader, deviceID, ip
 		return nil, NewTokenError(UserNotFound, "пользователь не найден", err)
 	}
 
+	// Проверяем политику времени жизни сессии. Сессии, созданные до её появления, не имеют
+	// типа клиента и принимают тип первого обновления.
+	now := time.Now()
+	if tokenEntity.Client != "" && ClientType(tokenEntity.Client) != client {
+		log.Printf("[TokenManager] Refresh-токен сессии %s (ID: %d) предъявлен клиентом %s — сессия завершена", tokenEntity.Client, tokenEntity.ID, client)
+		_ = m.refreshTokenRepo.MarkTokenAsExpiredByHash(tokenHash)
+		return nil, NewTokenError(InvalidRefreshToken, "refresh токен выдан клиенту другого типа", nil)
+	}
+	sessionStartedAt := tokenEntity.SessionStartedAt
+	if sessionStartedAt.IsZero() {
+		sessionStartedAt = tokenEntity.CreatedAt
+	}
+	policy := m.sessionPolicies.Resolve(user.Role, client)
+	if reason := policy.sessionEndReason(sessionStartedAt, tokenEntity.CreatedAt, now); reason != "" {
+		log.Printf("[TokenManager] Сессия пользователя ID=%d (токен ID: %d) завершена: %s", user.ID, tokenEntity.ID, reason)
+		_ = m.refreshTokenRepo.MarkTokenAsExpiredByHash(tokenHash)
+		return nil, NewTokenError(ExpiredRefreshToken, "сессия завершена: "+reason, nil)
+	}
+
+	// Сессия, привязанная к ключу устройства, обновляется только с доказательством владения ключом.
+	// Токен без доказательства не отзывается: его мог предъявить укравший токен, а не владелец.
+	deviceKey := newKey
+	if tokenEntity.DeviceKey != "" {
+		currentKey, keyErr := ParseDeviceKey([]byte(tokenEntity.DeviceKey))
+		if keyErr != nil {
+			log.Printf("[TokenManager] Некорректный ключ устройства у refresh-токена ID=%d: %v", tokenEntity.ID, keyErr)
+			return nil, NewTokenError(InvalidDeviceProof, "ключ устройства сессии повреждён", keyErr)
+		}
+		if err := verifyDeviceProof(currentKey, proof, refreshToken, now); err != nil {
+			log.Printf("[TokenManager] Отклонено обновление сессии пользователя ID=%d (токен ID: %d): %v", user.ID, tokenEntity.ID, err)
+			return nil, err
+		}
+		if deviceKey == nil {
+			deviceKey = currentKey
+		}
+	}
+
 	// Помечаем старый refresh токен как истекший по hash
 	if err := m.refreshTokenRepo.MarkTokenAsExpiredByHash(tokenHash); err != nil {
 		log.Printf("[TokenManager] Ошибка при маркировке старого refresh-токена как истекшего (ID: %d): %v", tokenEntity.ID, err)
@@ -358,7 +458,8 @@ func (m *TokenManager) RefreshTokens(refreshToken, csrfTokenHeader, deviceID, ip
 	}
 
 	// Генерируем новый refresh токен
-	newRefreshTokenString, err := m.generateRefreshToken(user.ID, deviceID, ipAddress, userAgent)
+	expiresAt := policy.expiresAt(sessionStartedAt, now, m.refreshTokenExpiry)
+	newRefreshTokenString, err := m.generateRefreshToken(user.ID, deviceID, ipAddress, userAgent, client, sessionStartedAt, expiresAt, deviceKey)
 	if err != nil {
 		log.Printf("[TokenManager] Ошибка генерации нового refresh-токена для пользователя ID=%d: %v", user.ID, err)
 		return nil, NewTokenError(TokenGenerationFailed, "ошибка генерации нового refresh токена", err)
@@ -386,6 +487,39 @@ func (m *TokenManager) RefreshTokens(refreshToken, csrfTokenHeader, deviceID, ip
 	}, nil
 }
 
+// BindDeviceKey привязывает только что выданную сессию к ключу устройства. Привязанную сессию
+// этим методом перепривязать нельзя — для этого есть RotateDeviceKey.
+func (m *TokenManager) BindDeviceKey(refreshToken string, key *DeviceKey) error {
+	tokenEntity, err := m.refreshTokenRepo.GetTokenByHash(hashToken(refreshToken))
+	if err != nil {
+		return NewTokenError(InvalidRefreshToken, "недействительный или истекший refresh токен", err)
+	}
+	if tokenEntity.DeviceKey != "" {
+		return NewTokenError(InvalidDeviceProof, "сессия уже привязана к ключу устройства", nil)
+	}
+	if err := m.refreshTokenRepo.SetDeviceKey(tokenEntity.ID, key.JWK, key.Thumbprint); err != nil {
+		log.Printf("[TokenManager] Ошибка привязки ключа устройства к refresh-токену ID=%d: %v", tokenEntity.ID, err)
+		return NewTokenError(DatabaseError, "ошибка привязки ключа устройства", err)
+	}
+	log.Printf("[TokenManager] Сессия пользователя ID=%d привязана к ключу устройства %s", tokenEntity.UserID, key.Thumbprint)
+	return nil
+}
+
+// RevokeDeviceKey завершает все сессии пользователя, привязанные к ключу устройства с отпечатком
+// thumbprint (ключ утерян или скомпрометирован), и возвращает их количество
+func (m *TokenManager) RevokeDeviceKey(userID uint, thumbprint string) (int64, error) {
+	revoked, err := m.refreshTokenRepo.MarkAsExpiredByDeviceKey(userID, thumbprint)
+	if err != nil {
+		log.Printf("[TokenManager] Ошибка отзыва сессий пользователя ID=%d по ключу устройства: %v", userID, err)
+		return 0, NewTokenError(DatabaseError, "ошибка отзыва сессий по ключу устройства", err)
+	}
+	if revoked == 0 {
+		return 0, fmt.Errorf("%w: активных сессий с этим ключом устройства нет", apperrors.ErrNotFound)
+	}
+	log.Printf("[TokenManager] Отозваны сессии пользователя ID=%d по ключу устройства %s: %d", userID, thumbprint, revoked)
+	return revoked, nil
+}
+
 // GetTokenInfo возвращает информацию о сроках действия текущих токенов
 func (m *TokenManager) GetTokenInfo(refreshToken string) (*TokenInfo, error) {
 	// Вычисляем hash и находим refresh-токен в БД
@@ -528,6 +662,60 @@ func (m *TokenManager) SetCSRFSecretCookie(w http.ResponseWriter, csrfSecret str
 	log.Printf("[TokenManager] Установлена CSRF secret cookie (%s) с Secure=%v, MaxAge: %d секунд", cookieName, m.cookieSecure, maxAge)
 }
 
+// EnsureAdminCSRFSession возвращает идентификатор CSRF-сессии админ-панели из куки,
+// а если куки нет — создаёт новый и устанавливает куку на срок жизни refresh-токена
+func (m *TokenManager) EnsureAdminCSRFSession(w http.ResponseWriter, r *http.Request) string {
+	if sessionID, err := m.GetAdminCSRFSessionFromCookie(r); err == nil {
+		return sessionID
+	}
+	sessionID := generateRandomString(64)
+	http.SetCookie(w, &http.Cookie{
+		Name:     m.adminCSRFSessionCookieName(),
+		Value:    sessionID,
+		Path:     m.cookiePath,
+		Domain:   m.cookieDomain,
+		HttpOnly: true,
+		Secure:   m.cookieSecure,
+		SameSite: m.cookieSameSite,
+		MaxAge:   int(m.refreshTokenExpiry.Seconds()),
+	})
+	return sessionID
+}
+
+// GetAdminCSRFSessionFromCookie получает идентификатор CSRF-сессии админ-панели из куки
+func (m *TokenManager) GetAdminCSRFSessionFromCookie(r *http.Request) (string, error) {
+	cookie, err := r.Cookie(m.adminCSRFSessionCookieName())
+	if err != nil {
+		return "", NewTokenError(InvalidCSRFToken, "кука CSRF-сессии не найдена", err)
+	}
+	if cookie.Value == "" {
+		return "", NewTokenError(InvalidCSRFToken, "пустая кука CSRF-сессии", nil)
+	}
+	return cookie.Value, nil
+}
+
+// adminCSRFSessionCookieName — без префикса __Host-, если куки не Secure (разработка по HTTP)
+func (m *TokenManager) adminCSRFSessionCookieName() string {
+	if !m.cookieSecure {
+		return strings.TrimPrefix(AdminCSRFSessionCookie, "__Host-")
+	}
+	return AdminCSRFSessionCookie
+}
+
+// SetDeviceIDCookie устанавливает долгоживущую куку с идентификатором браузера
+func (m *TokenManager) SetDeviceIDCookie(w http.ResponseWriter, deviceID string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     DeviceIDCookie,
+		Value:    deviceID,
+		Path:     m.cookiePath,
+		Domain:   m.cookieDomain,
+		HttpOnly: true,
+		Secure:   m.cookieSecure,
+		SameSite: m.cookieSameSite,
+		MaxAge:   int(DeviceIDCookieLifetime.Seconds()),
+	})
+}
+
 // GetRefreshTokenFromCookie получает refresh-токен из куки
 func (m *TokenManager) GetRefreshTokenFromCookie(r *http.Request) (string, error) {
 	cookie, err := r.Cookie(RefreshTokenCookie)
@@ -633,6 +821,17 @@ func (m *TokenManager) ClearCSRFSecretCookie(w http.ResponseWriter) {
 		SameSite: m.cookieSameSite,
 		MaxAge:   -1, // Удаление куки
 	})
+	// Вместе с секретом завершается и CSRF-сессия админ-панели
+	http.SetCookie(w, &http.Cookie{
+		Name:     m.adminCSRFSessionCookieName(),
+		Value:    "",
+		Path:     m.cookiePath,
+		Domain:   m.cookieDomain,
+		HttpOnly: true,
+		Secure:   m.cookieSecure,
+		SameSite: m.cookieSameSite,
+		MaxAge:   -1,
+	})
 }
 
 // CleanupExpiredTokens удаляет все истекшие refresh-токены
@@ -668,29 +867,40 @@ func (m *TokenManager) CleanupExpiredTokens() error {
 
 // RotateJWTKeys выполняет ротацию ключей подписи JWT, используя репозиторий.
 func (m *TokenManager) RotateJWTKeys(ctx context.Context) (string, error) {
+	_, newKeyID, err := m.rotateJWTKeys(ctx)
+	return newKeyID, err
+}
+
+// rotateJWTKeys деактивирует текущий ключ и создает новый. Возвращает ID прежнего (может быть пустым)
+// и нового ключа. Если текущий ключ уже ротировал другой инстанс, возвращает ErrConflict.
+func (m *TokenManager) rotateJWTKeys(ctx context.Context) (string, string, error) {
 	log.Println("[TokenManager] Initiating JWT key rotation...")
 
 	// 1. Получаем текущий активный ключ
 	currentActiveKey, err := m.jwtKeyRepo.GetActiveKey(ctx)
 	if err != nil && !errors.Is(err, apperrors.ErrNotFound) {
-		return "", fmt.Errorf("failed to get current active key for rotation: %w", err)
+		return "", "", fmt.Errorf("failed to get current active key for rotation: %w", err)
 	}
 
-	// 2. Деактивируем текущий ключ (если он есть)
+	// 2. Деактивируем текущий ключ (если он есть). Новый ключ создается только после успешной
+	// деактивации, чтобы одновременная ротация на нескольких инстансах не оставила два активных ключа.
+	previousKeyID := ""
 	if currentActiveKey != nil {
-		now := time.Now()
-		if err := m.jwtKeyRepo.DeactivateKey(ctx, currentActiveKey.ID, now); err != nil {
-			log.Printf("WARN: Failed to deactivate previous JWT key ID %s during rotation: %v", currentActiveKey.ID, err)
-		} else {
-			log.Printf("[TokenManager] Deactivated previous JWT key ID: %s", currentActiveKey.ID)
+		previousKeyID = currentActiveKey.ID
+		if err := m.jwtKeyRepo.DeactivateKey(ctx, currentActiveKey.ID, time.Now()); err != nil {
+			if errors.Is(err, apperrors.ErrNotFound) {
+				return "", "", fmt.Errorf("JWT key %s is already rotated: %w", currentActiveKey.ID, apperrors.ErrConflict)
+			}
+			return "", "", fmt.Errorf("failed to deactivate previous JWT key ID %s during rotation: %w", currentActiveKey.ID, err)
 		}
+		log.Printf("[TokenManager] Deactivated previous JWT key ID: %s", currentActiveKey.ID)
 	}
 
 	// 3. Генерируем новый ключ
 	newKeyID := generateRandomString(16)
 	newSecret := generateRandomString(64)
 	now := time.Now()
-	expiry := now.Add(DefaultJWTKeyLifetime)
+	expiry := now.Add(m.keyLifetime())
 
 	newKey := &entity.JWTKey{
 		ID:        newKeyID,
@@ -703,11 +913,11 @@ func (m *TokenManager) RotateJWTKeys(ctx context.Context) (string, error) {
 
 	// 4. Сохраняем новый ключ
 	if err := m.jwtKeyRepo.CreateKey(ctx, newKey); err != nil {
-		return "", fmt.Errorf("failed to create new JWT key during rotation: %w", err)
+		return "", "", fmt.Errorf("failed to create new JWT key during rotation: %w", err)
 	}
 
 	log.Printf("[TokenManager] Successfully rotated JWT key. New active key ID: %s", newKeyID)
-	return newKeyID, nil
+	return previousKeyID, newKeyID, nil
 }
 
 // GetCurrentSigningKey возвращает текущий активный ключ для подписи JWT из репозитория.
@@ -775,8 +985,7 @@ func (m *TokenManager) initializeAndEnsureKeys(ctx context.Context) error {
 		newKeyID := generateRandomString(16)
 		newSecret := generateRandomString(64) // Генерируем 32 байта секрета в hex виде (64 символов)
 		now := time.Now()
-		// Используем DefaultJWTKeyLifetime для срока жизни ключа
-		expiry := now.Add(DefaultJWTKeyLifetime)
+		expiry := now.Add(m.keyLifetime())
 
 		initialKey := &entity.JWTKey{
 			ID:        newKeyID,
@@ -800,8 +1009,10 @@ func (m *TokenManager) initializeAndEnsureKeys(ctx context.Context) error {
 // Служебные функции
 
 // generateRefreshToken генерирует новый refresh-токен, вычисляет SHA-256 hash, и сохраняет hash в БД.
+// sessionStartedAt — время входа, с которого начался отсчёт сессии; deviceKey (может быть nil) —
+// ключ устройства, к которому привязана сессия.
 // Возвращает RAW (unhashed) строку токена — только она отправляется клиенту.
-func (m *TokenManager) generateRefreshToken(userID uint, deviceID, ipAddress, userAgent string) (string, error) {
+func (m *TokenManager) generateRefreshToken(userID uint, deviceID, ipAddress, userAgent string, client ClientType, sessionStartedAt, expiresAt time.Time, deviceKey *DeviceKey) (string, error) {
 	// Генерируем случайный токен (32 байта = 64 hex символов)
 	randomBytes := make([]byte, 32)
 	if _, err := rand.Read(randomBytes); err != nil {
@@ -812,11 +1023,14 @@ func (m *TokenManager) generateRefreshToken(userID uint, deviceID, ipAddress, us
 	// Вычисляем SHA-256 hash для хранения в БД
 	tokenHash := hashToken(rawToken)
 
-	// Время истечения
-	expiresAt := time.Now().Add(m.refreshTokenExpiry)
-
 	// Сохраняем только hash токена (raw token не хранится в БД).
 	token := entity.NewRefreshToken(userID, tokenHash, deviceID, ipAddress, userAgent, expiresAt)
+	token.Client = string(client)
+	token.SessionStartedAt = sessionStartedAt
+	if deviceKey != nil {
+		token.DeviceKey = deviceKey.JWK
+		token.DeviceKeyThumbprint = deviceKey.Thumbprint
+	}
 
 	// Сохраняем в БД
 	_, err := m.refreshTokenRepo.CreateToken(token)
@@ -5,43 +5,67 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"os"
+	"path/filepath"
 	"time"
 
 	migrateV4 "github.com/golang-migrate/migrate/v4"
 	migratePostgres "github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/yourusername/trivia-api/internal/config"
 	gormPostgres "gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
-// NewPostgresDB создает новое подключение к PostgreSQL
-func NewPostgresDB(dsn string) (*gorm.DB, error) {
-	db, err := gorm.Open(gormPostgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+// NewPostgresDB создает новое подключение к PostgreSQL с настройками пула из cfg
+// и подключает логирование медленных запросов (см. SlowQueryLogger)
+func NewPostgresDB(cfg config.DatabaseConfig) (*gorm.DB, error) {
+	db, err := gorm.Open(gormPostgres.Open(cfg.PostgresConnectionString()), &gorm.Config{
+		Logger: logger.New(log.New(os.Stdout, "\r\n", log.LstdFlags), logger.Config{
+			SlowThreshold:             0, // медленные запросы логирует SlowQueryLogger
+			LogLevel:                  gormLogLevel(cfg.LogLevel),
+			IgnoreRecordNotFoundError: false,
+			Colorful:                  true,
+		}),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	if cfg.SlowQueryMs > 0 {
+		if err := db.Use(NewSlowQueryLogger(time.Duration(cfg.SlowQueryMs) * time.Millisecond)); err != nil {
+			return nil, fmt.Errorf("failed to register slow query logger: %w", err)
+		}
+	}
+
 	// Настройка пула соединений
 	sqlDB, err := db.DB()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get sql.DB: %w", err)
 	}
-
-	// Максимальное число открытых соединений
-	sqlDB.SetMaxOpenConns(25)
-
-	// Максимальное число простаивающих соединений
-	sqlDB.SetMaxIdleConns(10)
-
-	// Максимальное время жизни соединения
-	sqlDB.SetConnMaxLifetime(time.Hour)
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetimeMinutes) * time.Minute)
+	sqlDB.SetConnMaxIdleTime(time.Duration(cfg.ConnMaxIdleTimeMinutes) * time.Minute)
 
 	return db, nil
 }
 
+// gormLogLevel переводит уровень из конфигурации в уровень логгера GORM
+func gormLogLevel(level string) logger.LogLevel {
+	switch level {
+	case "silent":
+		return logger.Silent
+	case "error":
+		return logger.Error
+	case "warn":
+		return logger.Warn
+	default:
+		return logger.Info
+	}
+}
+
 // MigrateDB применяет SQL-миграции из папки 'migrations'
 func MigrateDB(db *gorm.DB) error {
 	log.Println("Запуск применения миграций базы данных...")
@@ -56,22 +80,9 @@ func MigrateDB(db *gorm.DB) error {
 		return fmt.Errorf("не удалось проверить подключение к БД перед миграцией: %w", err)
 	}
 
-	// Создаем драйвер базы данных для migrate
-	driver, err := migratePostgres.WithInstance(sqlDB, &migratePostgres.Config{})
-	if err != nil {
-		return fmt.Errorf("не удалось создать драйвер postgres для migrate: %w", err)
-	}
-
-	// Создаем источник миграций (из папки migrations)
-	// Путь "file://migrations" указывает на папку migrations
-	// в рабочем каталоге приложения (в Docker это будет /root/migrations)
-	m, err := migrateV4.NewWithDatabaseInstance(
-		"file://migrations",
-		"postgres", // Имя базы данных (для логирования в migrate)
-		driver,
-	)
+	m, err := NewMigrator(db, "migrations")
 	if err != nil {
-		return fmt.Errorf("не удалось создать экземпляр migrate: %w", err)
+		return err
 	}
 
 	// Применяем миграции "вверх"
@@ -91,6 +102,24 @@ func MigrateDB(db *gorm.DB) error {
 	return nil // Возвращаем nil, если все прошло успешно или не было изменений
 }
 
+// NewMigrator создаёт экземпляр migrate для SQL-миграций из каталога dir.
+// Относительный путь считается от рабочего каталога (в Docker это /root/migrations).
+func NewMigrator(db *gorm.DB, dir string) (*migrateV4.Migrate, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("не удалось получить *sql.DB из *gorm.DB: %w", err)
+	}
+	driver, err := migratePostgres.WithInstance(sqlDB, &migratePostgres.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("не удалось создать драйвер postgres для migrate: %w", err)
+	}
+	m, err := migrateV4.NewWithDatabaseInstance("file://"+filepath.ToSlash(dir), "postgres", driver)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось создать экземпляр migrate: %w", err)
+	}
+	return m, nil
+}
+
 // GetSQLDB возвращает базовый *sql.DB из *gorm.DB
 func GetSQLDB(gormDB *gorm.DB) (*sql.DB, error) {
 	sqlDB, err := gormDB.DB()
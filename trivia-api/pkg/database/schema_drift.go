@@ -0,0 +1,95 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// SchemaIssue — расхождение схемы БД с моделями GORM
+type SchemaIssue struct {
+	Table   string
+	Column  string // пусто, если отсутствует вся таблица
+	Problem string
+}
+
+func (i SchemaIssue) String() string {
+	if i.Column == "" {
+		return fmt.Sprintf("%s: %s", i.Table, i.Problem)
+	}
+	return fmt.Sprintf("%s.%s: %s", i.Table, i.Column, i.Problem)
+}
+
+// ModelColumns возвращает колонки каждой таблицы моделей по тегам GORM (без связей и полей "-")
+func ModelColumns(models []interface{}) (map[string][]string, error) {
+	cache := &sync.Map{}
+	tables := make(map[string][]string, len(models))
+	for _, model := range models {
+		s, err := schema.Parse(model, cache, schema.NamingStrategy{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse model %T: %w", model, err)
+		}
+		for _, field := range s.Fields {
+			if field.DBName == "" || field.IgnoreMigration {
+				continue
+			}
+			tables[s.Table] = append(tables[s.Table], field.DBName)
+		}
+	}
+	return tables, nil
+}
+
+// FindSchemaDrift сравнивает колонки моделей с колонками БД. actual содержит только существующие таблицы.
+// Лишние колонки и таблицы БД не считаются расхождением: их может добавлять миграция до изменения кода.
+func FindSchemaDrift(expected, actual map[string][]string) []SchemaIssue {
+	var issues []SchemaIssue
+	for table, columns := range expected {
+		existing, ok := actual[table]
+		if !ok {
+			issues = append(issues, SchemaIssue{Table: table, Problem: "table is missing"})
+			continue
+		}
+		present := make(map[string]bool, len(existing))
+		for _, column := range existing {
+			present[column] = true
+		}
+		for _, column := range columns {
+			if !present[column] {
+				issues = append(issues, SchemaIssue{Table: table, Column: column, Problem: "column is missing"})
+			}
+		}
+	}
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].Table != issues[j].Table {
+			return issues[i].Table < issues[j].Table
+		}
+		return issues[i].Column < issues[j].Column
+	})
+	return issues
+}
+
+// CheckSchema сверяет таблицы и колонки моделей со схемой БД (information_schema текущей схемы)
+func CheckSchema(db *gorm.DB, models []interface{}) ([]SchemaIssue, error) {
+	expected, err := ModelColumns(models)
+	if err != nil {
+		return nil, err
+	}
+	var rows []struct {
+		TableName  string
+		ColumnName string
+	}
+	if err := db.Raw(`
+		SELECT table_name, column_name
+		FROM information_schema.columns
+		WHERE table_schema = current_schema()`).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to read database columns: %w", err)
+	}
+	actual := make(map[string][]string)
+	for _, row := range rows {
+		actual[row.TableName] = append(actual[row.TableName], row.ColumnName)
+	}
+	return FindSchemaDrift(expected, actual), nil
+}
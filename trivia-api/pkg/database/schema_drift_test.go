@@ -0,0 +1,41 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/trivia-api/internal/domain/entity"
+)
+
+func TestModelColumns(t *testing.T) {
+	tables, err := ModelColumns(entity.PersistentModels())
+	require.NoError(t, err)
+
+	assert.Len(t, tables, len(entity.PersistentModels()), "у каждой модели своя таблица")
+	quizzes := tables["quizzes"]
+	assert.Contains(t, quizzes, "question_delay_ms", "колонки встроенной структуры")
+	assert.Contains(t, quizzes, "second_chance_ad_asset_id", "имя колонки из тега")
+	assert.NotContains(t, quizzes, "questions", "связи не колонки")
+	assert.NotContains(t, quizzes, "tags")
+}
+
+func TestFindSchemaDrift(t *testing.T) {
+	expected := map[string][]string{
+		"users":     {"id", "username", "email"},
+		"quizzes":   {"id", "title"},
+		"referrals": {"id"},
+	}
+	actual := map[string][]string{
+		"users":             {"id", "email", "legacy_flag"},
+		"quizzes":           {"id", "title"},
+		"schema_migrations": {"version", "dirty"},
+	}
+
+	issues := FindSchemaDrift(expected, actual)
+
+	require.Len(t, issues, 2, "лишние колонки и таблицы БД не считаются расхождением")
+	assert.Equal(t, "referrals: table is missing", issues[0].String())
+	assert.Equal(t, "users.username: column is missing", issues[1].String())
+}
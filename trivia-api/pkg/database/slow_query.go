@@ -0,0 +1,95 @@
+package database
+
+import (
+	"errors"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	slowQueryPluginName = "slow_query_logger"
+	slowQueryStartKey   = "slow_query_logger:start"
+)
+
+// SlowQueryLogger — плагин GORM, который логирует запросы дольше порога.
+// В лог попадает SQL с плейсхолдерами ($1, $2, ...): значения параметров не пишутся,
+// чтобы в логи не утекали email, хеши паролей и токены.
+type SlowQueryLogger struct {
+	threshold time.Duration
+	count     atomic.Int64
+}
+
+// NewSlowQueryLogger создает плагин с порогом threshold
+func NewSlowQueryLogger(threshold time.Duration) *SlowQueryLogger {
+	return &SlowQueryLogger{threshold: threshold}
+}
+
+// Name возвращает имя плагина GORM
+func (p *SlowQueryLogger) Name() string {
+	return slowQueryPluginName
+}
+
+// Initialize регистрирует замер времени вокруг запросов всех типов
+func (p *SlowQueryLogger) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	before, after := slowQueryPluginName+":before", slowQueryPluginName+":after"
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register(before, p.before),
+		cb.Create().After("gorm:create").Register(after, p.after),
+		cb.Query().Before("gorm:query").Register(before, p.before),
+		cb.Query().After("gorm:query").Register(after, p.after),
+		cb.Update().Before("gorm:update").Register(before, p.before),
+		cb.Update().After("gorm:update").Register(after, p.after),
+		cb.Delete().Before("gorm:delete").Register(before, p.before),
+		cb.Delete().After("gorm:delete").Register(after, p.after),
+		cb.Row().Before("gorm:row").Register(before, p.before),
+		cb.Row().After("gorm:row").Register(after, p.after),
+		cb.Raw().Before("gorm:raw").Register(before, p.before),
+		cb.Raw().After("gorm:raw").Register(after, p.after),
+	)
+}
+
+// Threshold возвращает порог медленного запроса
+func (p *SlowQueryLogger) Threshold() time.Duration {
+	return p.threshold
+}
+
+// SlowQueries возвращает число медленных запросов с запуска процесса
+func (p *SlowQueryLogger) SlowQueries() int64 {
+	return p.count.Load()
+}
+
+func (p *SlowQueryLogger) before(db *gorm.DB) {
+	db.InstanceSet(slowQueryStartKey, time.Now())
+}
+
+func (p *SlowQueryLogger) after(db *gorm.DB) {
+	value, ok := db.InstanceGet(slowQueryStartKey)
+	if !ok {
+		return
+	}
+	start, ok := value.(time.Time)
+	if !ok {
+		return
+	}
+	elapsed := time.Since(start)
+	if elapsed < p.threshold {
+		return
+	}
+	p.count.Add(1)
+	log.Printf("[DB] Медленный запрос (%s, строк: %d, параметров: %d): %s",
+		elapsed.Round(time.Millisecond), db.Statement.RowsAffected, len(db.Statement.Vars), db.Statement.SQL.String())
+}
+
+// SlowQueryPlugin возвращает плагин медленных запросов, подключённый в NewPostgresDB
+func SlowQueryPlugin(db *gorm.DB) (*SlowQueryLogger, bool) {
+	plugin, ok := db.Config.Plugins[slowQueryPluginName]
+	if !ok {
+		return nil, false
+	}
+	p, ok := plugin.(*SlowQueryLogger)
+	return p, ok
+}
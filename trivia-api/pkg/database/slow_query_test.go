@@ -0,0 +1,31 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestSlowQueryLogger_CountsOnlySlowQueries(t *testing.T) {
+	plugin := NewSlowQueryLogger(10 * time.Millisecond)
+	db := &gorm.DB{Statement: &gorm.Statement{}}
+	db.Statement.DB = db
+
+	// Быстрый запрос не считается
+	db.InstanceSet(slowQueryStartKey, time.Now())
+	plugin.after(db)
+	assert.Equal(t, int64(0), plugin.SlowQueries())
+
+	// Запрос дольше порога считается
+	db.InstanceSet(slowQueryStartKey, time.Now().Add(-50*time.Millisecond))
+	plugin.after(db)
+	assert.Equal(t, int64(1), plugin.SlowQueries())
+
+	// Без отметки начала (плагин подключён посреди запроса) ничего не происходит
+	fresh := &gorm.DB{Statement: &gorm.Statement{}}
+	fresh.Statement.DB = fresh
+	plugin.after(fresh)
+	assert.Equal(t, int64(1), plugin.SlowQueries())
+}
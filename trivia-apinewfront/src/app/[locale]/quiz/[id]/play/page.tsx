@@ -68,6 +68,8 @@ export default function QuizPlayPage() {
         switch (msg.type) {
             case 'quiz:question': {
                 const question = msg.data as unknown as QuizQuestionEvent;
+                // Acknowledge delivery: the answer window starts when the question arrives
+                send('user:question_received', { question_id: question.question_id });
                 setQuizState(prev => ({
                     ...prev,
                     status: 'question',
@@ -208,7 +210,7 @@ export default function QuizPlayPage() {
                 break;
             }
         }
-    }, [quizId, router, t]);
+    }, [quizId, router, send, t]);
 
     useEffect(() => {
         const unsubscribe = subscribe(handleMessage);